@@ -0,0 +1,79 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AppServerSettings are properties of an app server of a group that the
+// operator keeps set through the Manage API, so they are not tuned by hand in
+// the Admin UI and lost when the cluster is rebuilt. Properties left out are
+// not changed.
+type AppServerSettings struct {
+	// Name of the app server, for example App-Services.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// RequestTimeout is the seconds the app server waits for a request
+	// from the client, request-timeout.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	RequestTimeout *int32 `json:"requestTimeout,omitempty"`
+	// KeepAliveTimeout is the seconds a keep-alive connection waits for the
+	// next request, keep-alive-timeout.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	KeepAliveTimeout *int32 `json:"keepAliveTimeout,omitempty"`
+	// SessionTimeout is the seconds a session lives without a request,
+	// session-timeout.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	SessionTimeout *int32 `json:"sessionTimeout,omitempty"`
+	// DefaultTimeLimit is the seconds a request may run unless it sets its
+	// own limit, default-time-limit.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	DefaultTimeLimit *int32 `json:"defaultTimeLimit,omitempty"`
+	// MaxTimeLimit is the most seconds a request may set as its limit,
+	// max-time-limit.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxTimeLimit *int32 `json:"maxTimeLimit,omitempty"`
+	// MaxInferenceSize is the most megabytes of memory a request may use
+	// for semantic inference, max-inference-size.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxInferenceSize *int32 `json:"maxInferenceSize,omitempty"`
+	// ConcurrentRequestLimit is the most requests a user may run at the
+	// same time on the app server, concurrent-request-limit. 0 is unlimited.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	ConcurrentRequestLimit *int32 `json:"concurrentRequestLimit,omitempty"`
+	// Threads is the most requests the app server runs at the same time on
+	// a host, threads.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=256
+	// +optional
+	Threads *int32 `json:"threads,omitempty"`
+	// URLRewriter is the module that rewrites the URLs of requests,
+	// url-rewriter. An empty value removes the rewriter.
+	// +optional
+	URLRewriter *string `json:"urlRewriter,omitempty"`
+	// ErrorHandler is the module that handles the errors of requests,
+	// error-handler.
+	// +optional
+	ErrorHandler *string `json:"errorHandler,omitempty"`
+}
+
+// AppServerSettingsStatus reports the settings the operator applied to an
+// app server.
+type AppServerSettingsStatus struct {
+	Group string `json:"group"`
+	Name  string `json:"name"`
+	// SpecHash identifies the settings last applied.
+	SpecHash  string       `json:"specHash,omitempty"`
+	AppliedAt *metav1.Time `json:"appliedAt,omitempty"`
+	// Message is why the settings of the spec could not be applied yet.
+	Message string `json:"message,omitempty"`
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// GroupAutoscaling configures the autoscalers of a group.
+type GroupAutoscaling struct {
+	// Vertical creates a VerticalPodAutoscaler for the MarkLogic container
+	// of the group. Requires the VPA components in the Kubernetes cluster.
+	// +optional
+	Vertical *VerticalAutoscaling `json:"vertical,omitempty"`
+	// LoadMetrics exports the request rate and queue depth of the group for
+	// an HPA or KEDA. Only groups without forests (e-nodes) can scale on
+	// load, so it is not allowed on the bootstrap group or groups with
+	// forests.
+	// +optional
+	LoadMetrics *LoadMetrics `json:"loadMetrics,omitempty"`
+	// Keda creates a KEDA ScaledObject that scales the MarklogicGroup of the
+	// group. Only dynamic groups can use it, so hosts are removed from the
+	// MarkLogic cluster before their pods stop.
+	// +optional
+	Keda *KedaAutoscaling `json:"keda,omitempty"`
+}
+
+// KedaAutoscaling configures the KEDA ScaledObject of a group.
+type KedaAutoscaling struct {
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+	// +kubebuilder:validation:Minimum=1
+	MaxReplicas int32 `json:"maxReplicas"`
+	// PollingInterval is how often KEDA checks the triggers, in seconds.
+	// +optional
+	PollingInterval *int32 `json:"pollingInterval,omitempty"`
+	// CooldownPeriod is how long KEDA waits after the last active trigger
+	// before scaling down, in seconds.
+	// +optional
+	CooldownPeriod *int32 `json:"cooldownPeriod,omitempty"`
+	// +kubebuilder:validation:MinItems=1
+	// +listType=atomic
+	Triggers []KedaTrigger `json:"triggers"`
+}
+
+// KedaTriggerType is the kind of a KEDA trigger.
+// +kubebuilder:validation:Enum=prometheus;marklogic
+type KedaTriggerType string
+
+const (
+	// KedaTriggerPrometheus scales on a PromQL query.
+	KedaTriggerPrometheus KedaTriggerType = "prometheus"
+	// KedaTriggerMarkLogic scales on a load metric the operator reads from
+	// the Manage API, see LoadMetrics. It is queried from Prometheus, which
+	// must scrape the operator.
+	KedaTriggerMarkLogic KedaTriggerType = "marklogic"
+)
+
+// KedaTrigger is a trigger of the ScaledObject of a group.
+// +kubebuilder:validation:XValidation:rule="self.type != 'prometheus' || (has(self.query) && size(self.query) > 0)",message="query is required for prometheus triggers"
+// +kubebuilder:validation:XValidation:rule="self.type != 'marklogic' || has(self.metric)",message="metric is required for marklogic triggers"
+type KedaTrigger struct {
+	Type KedaTriggerType `json:"type"`
+	// ServerAddress is the address of the Prometheus server.
+	// +kubebuilder:validation:MinLength=1
+	ServerAddress string `json:"serverAddress"`
+	// Query is the PromQL query of a prometheus trigger.
+	// +optional
+	Query string `json:"query,omitempty"`
+	// Metric is the load metric of a marklogic trigger.
+	// +kubebuilder:validation:Enum=requestRate;activeRequests;queueDepth
+	// +optional
+	Metric string `json:"metric,omitempty"`
+	// Threshold is the value per replica KEDA scales to.
+	// +kubebuilder:validation:MinLength=1
+	Threshold string `json:"threshold"`
+}
+
+// LoadMetrics configures the load metrics of a group.
+type LoadMetrics struct {
+	Enabled bool `json:"enabled,omitempty"`
+}
+
+// VerticalAutoscalingMode is how the VPA recommendations of a group apply.
+// +kubebuilder:validation:Enum=Off;Initial;Auto
+type VerticalAutoscalingMode string
+
+const (
+	// VerticalAutoscalingOff only computes recommendations.
+	VerticalAutoscalingOff VerticalAutoscalingMode = "Off"
+	// VerticalAutoscalingInitial applies recommendations to pods as they are
+	// created and never restarts pods for them.
+	VerticalAutoscalingInitial VerticalAutoscalingMode = "Initial"
+	// VerticalAutoscalingAuto also restarts pods whose requests are outside
+	// the recommended range. The operator restarts them one at a time behind
+	// the upgrade health gates rather than the VPA updater evicting them.
+	VerticalAutoscalingAuto VerticalAutoscalingMode = "Auto"
+)
+
+// VerticalAutoscaling configures the VerticalPodAutoscaler of a group.
+type VerticalAutoscaling struct {
+	// +kubebuilder:default=Off
+	// +optional
+	Mode VerticalAutoscalingMode `json:"mode,omitempty"`
+	// MinAllowed and MaxAllowed bound the recommended requests.
+	// +optional
+	MinAllowed corev1.ResourceList `json:"minAllowed,omitempty"`
+	// +optional
+	MaxAllowed corev1.ResourceList `json:"maxAllowed,omitempty"`
+	// ControlledValues is RequestsOnly or RequestsAndLimits (VPA default),
+	// which scales the limits in proportion to the requests.
+	// +kubebuilder:validation:Enum=RequestsOnly;RequestsAndLimits
+	// +optional
+	ControlledValues string `json:"controlledValues,omitempty"`
+}
@@ -0,0 +1,230 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// BackupStorageProvider identifies the object store that holds database backups.
+type BackupStorageProvider string
+
+const (
+	BackupStorageProviderS3    BackupStorageProvider = "s3"
+	BackupStorageProviderAzure BackupStorageProvider = "azure"
+	BackupStorageProviderGCS   BackupStorageProvider = "gcs"
+)
+
+// BackupStorageAuthMode defines how MarkLogic authenticates against the backup object store.
+type BackupStorageAuthMode string
+
+const (
+	// BackupStorageAuthModeSecret reads static credentials from a Kubernetes Secret
+	// and registers them with MarkLogic through the Manage API.
+	BackupStorageAuthModeSecret BackupStorageAuthMode = "secret"
+	// BackupStorageAuthModeSAS uses an Azure shared access signature token.
+	BackupStorageAuthModeSAS BackupStorageAuthMode = "sas"
+	// BackupStorageAuthModeWorkloadIdentity relies on the cloud provider identity
+	// bound to the MarkLogic service account (IRSA or Azure Managed Identity).
+	// No static credentials are stored. GCS is reached through its S3
+	// interoperability endpoint, which only accepts HMAC keys, so it does not
+	// support this mode.
+	BackupStorageAuthModeWorkloadIdentity BackupStorageAuthMode = "workloadIdentity"
+)
+
+// +kubebuilder:validation:XValidation:rule="self.provider != 's3' || self.authMode != 'sas'",message="sas authMode is only supported for the azure provider"
+// +kubebuilder:validation:XValidation:rule="self.provider != 'gcs' || self.authMode != 'workloadIdentity'",message="workloadIdentity authMode is not supported for the gcs provider, which requires HMAC keys"
+// +kubebuilder:validation:XValidation:rule="self.authMode == 'workloadIdentity' || (has(self.credentialsSecretName) && size(self.credentialsSecretName) > 0)",message="credentialsSecretName is required unless authMode is workloadIdentity"
+type BackupStorage struct {
+	// +kubebuilder:validation:Enum=s3;azure;gcs
+	// +kubebuilder:validation:Required
+	Provider BackupStorageProvider `json:"provider"`
+	// Bucket is the S3/GCS bucket or the Azure Blob container name.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Bucket string `json:"bucket"`
+	Prefix string `json:"prefix,omitempty"`
+	// +kubebuilder:validation:Enum=secret;sas;workloadIdentity
+	// +kubebuilder:default:=secret
+	AuthMode BackupStorageAuthMode `json:"authMode,omitempty"`
+	// CredentialsSecretName references a Secret with provider specific keys:
+	// s3 and gcs require access-key-id and secret-access-key, azure requires
+	// storage-account plus storage-key (secret) or sas-token (sas).
+	CredentialsSecretName string              `json:"credentialsSecretName,omitempty"`
+	S3                    *S3BackupStorage    `json:"s3,omitempty"`
+	Azure                 *AzureBackupStorage `json:"azure,omitempty"`
+	Retry                 *BackupStorageRetry `json:"retry,omitempty"`
+}
+
+type S3BackupStorage struct {
+	Region string `json:"region,omitempty"`
+	// Endpoint overrides the S3 domain used by MarkLogic, e.g. for S3 compatible stores.
+	Endpoint string `json:"endpoint,omitempty"`
+	// RoleARN is bound to the MarkLogic service account when authMode is workloadIdentity.
+	RoleARN string `json:"roleArn,omitempty"`
+}
+
+type AzureBackupStorage struct {
+	StorageAccount string `json:"storageAccount,omitempty"`
+	// ClientID of the user assigned managed identity used when authMode is workloadIdentity.
+	ClientID string `json:"clientId,omitempty"`
+}
+
+type BackupStorageRetry struct {
+	// +kubebuilder:default:=5
+	// +kubebuilder:validation:Minimum=1
+	InitialDelaySeconds int32 `json:"initialDelaySeconds,omitempty"`
+	// +kubebuilder:default:=300
+	// +kubebuilder:validation:Minimum=1
+	MaxDelaySeconds int32 `json:"maxDelaySeconds,omitempty"`
+}
+
+// BackupSchedule declares a scheduled backup that MarkLogic runs itself for a database.
+// +kubebuilder:validation:XValidation:rule="self.frequency != 'weekly' || (has(self.daysOfWeek) && size(self.daysOfWeek) > 0)",message="daysOfWeek is required for weekly schedules"
+// +kubebuilder:validation:XValidation:rule="self.frequency != 'monthly' || (has(self.dayOfMonth) && self.dayOfMonth > 0)",message="dayOfMonth is required for monthly schedules"
+type BackupSchedule struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Database string `json:"database"`
+	// +kubebuilder:validation:Enum=minutely;hourly;daily;weekly;monthly
+	// +kubebuilder:default:=daily
+	Frequency string `json:"frequency,omitempty"`
+	// Period is the number of frequency units between backups, e.g. every 2 days.
+	// +kubebuilder:default:=1
+	// +kubebuilder:validation:Minimum=1
+	Period int32 `json:"period,omitempty"`
+	// StartTime is the time of day (HH:MM or HH:MM:SS) the backup starts. Ignored for minutely schedules.
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9](:[0-5][0-9])?$`
+	StartTime string `json:"startTime,omitempty"`
+	// +kubebuilder:validation:items:Enum=monday;tuesday;wednesday;thursday;friday;saturday;sunday
+	DaysOfWeek []string `json:"daysOfWeek,omitempty"`
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=31
+	DayOfMonth int32 `json:"dayOfMonth,omitempty"`
+	// Directory overrides the backup directory. Defaults to <storage directory>/<database>/.
+	Directory string `json:"directory,omitempty"`
+	// +kubebuilder:default:=2
+	// +kubebuilder:validation:Minimum=0
+	MaxBackups int32 `json:"maxBackups,omitempty"`
+	// +kubebuilder:default:=false
+	Incremental bool `json:"incremental,omitempty"`
+	// JournalArchiving keeps journals alongside the backup so the database can be
+	// restored to any point in time after the backup completed.
+	// +kubebuilder:default:=false
+	JournalArchiving bool `json:"journalArchiving,omitempty"`
+	// JournalArchivePath defaults to the backup directory.
+	JournalArchivePath string `json:"journalArchivePath,omitempty"`
+	// JournalArchiveLagLimitSeconds is how far journal archiving may fall behind before MarkLogic throttles updates.
+	// +kubebuilder:default:=15
+	// +kubebuilder:validation:Minimum=1
+	JournalArchiveLagLimitSeconds int32 `json:"journalArchiveLagLimitSeconds,omitempty"`
+}
+
+// BackupRestore requests a one-shot restore of a database. Changing restoreId
+// starts a new restore; the operator never re-runs a completed restore.
+type BackupRestore struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=63
+	RestoreID string `json:"restoreId"`
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Database string `json:"database"`
+	// Directory holding the backup. Defaults to the directory of the database schedule or <storage directory>/<database>/.
+	Directory string `json:"directory,omitempty"`
+	// RestoreToTime replays archived journals up to the given timestamp. Requires journal archiving on the database schedule.
+	RestoreToTime *metav1.Time `json:"restoreToTime,omitempty"`
+	// +kubebuilder:default:=true
+	IncludeReplicas bool `json:"includeReplicas,omitempty"`
+}
+
+// VeleroHooks adds Velero backup hook annotations to MarkLogic pods. The pre hook
+// puts the forests of the host into flash-backup mode so PVC snapshots are
+// consistent; the post hook restores normal updates.
+type VeleroHooks struct {
+	// +kubebuilder:default:=false
+	Enabled bool `json:"enabled,omitempty"`
+	// +kubebuilder:default:=300
+	// +kubebuilder:validation:Minimum=1
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+	// +kubebuilder:validation:Enum=Fail;Continue
+	// +kubebuilder:default:=Fail
+	OnError string `json:"onError,omitempty"`
+}
+
+// +kubebuilder:validation:XValidation:rule="!has(self.schedules) || has(self.storage) || self.schedules.all(s, has(s.directory) && size(s.directory) > 0)",message="schedules require backup storage or an explicit directory"
+type Backup struct {
+	// +kubebuilder:default:=false
+	Enabled bool           `json:"enabled,omitempty"`
+	Storage *BackupStorage `json:"storage,omitempty"`
+	// Schedules are pushed into MarkLogic as database scheduled backups.
+	// +kubebuilder:validation:MaxItems=100
+	Schedules []BackupSchedule `json:"schedules,omitempty"`
+	Restore   *BackupRestore   `json:"restore,omitempty"`
+	// Velero hooks are applied independently of enabled, which only controls operator managed backups.
+	Velero *VeleroHooks `json:"velero,omitempty"`
+}
+
+type BackupStorageStatus struct {
+	Provider           BackupStorageProvider `json:"provider,omitempty"`
+	Directory          string                `json:"directory,omitempty"`
+	Ready              bool                  `json:"ready,omitempty"`
+	Message            string                `json:"message,omitempty"`
+	RetryCount         int32                 `json:"retryCount,omitempty"`
+	NextRetryTime      *metav1.Time          `json:"nextRetryTime,omitempty"`
+	LastTransitionTime *metav1.Time          `json:"lastTransitionTime,omitempty"`
+	// AppliedHash identifies the credentials and the s3 domain last
+	// registered with MarkLogic. They are registered again once it changes.
+	AppliedHash string `json:"appliedHash,omitempty"`
+}
+
+type ScheduledBackupStatus struct {
+	Database                  string `json:"database"`
+	Configured                bool   `json:"configured,omitempty"`
+	LastBackupTime            string `json:"lastBackupTime,omitempty"`
+	LastIncrementalBackupTime string `json:"lastIncrementalBackupTime,omitempty"`
+	Message                   string `json:"message,omitempty"`
+	// LastSyncTime is when the schedules were last pushed to MarkLogic.
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+	// SpecHash identifies the schedules last pushed to MarkLogic.
+	SpecHash string `json:"specHash,omitempty"`
+	// LastRefreshTime is when the last run times were last read from MarkLogic.
+	LastRefreshTime *metav1.Time `json:"lastRefreshTime,omitempty"`
+}
+
+type RestorePhase string
+
+const (
+	RestorePhaseRunning   RestorePhase = "Running"
+	RestorePhaseCompleted RestorePhase = "Completed"
+	RestorePhaseFailed    RestorePhase = "Failed"
+)
+
+type ForestRestoreStatus struct {
+	Name   string `json:"name"`
+	Status string `json:"status,omitempty"`
+}
+
+type RestoreStatus struct {
+	RestoreID      string                `json:"restoreId,omitempty"`
+	Database       string                `json:"database,omitempty"`
+	Phase          RestorePhase          `json:"phase,omitempty"`
+	JobID          string                `json:"jobId,omitempty"`
+	Directory      string                `json:"directory,omitempty"`
+	RestoreToTime  *metav1.Time          `json:"restoreToTime,omitempty"`
+	Forests        []ForestRestoreStatus `json:"forests,omitempty"`
+	Message        string                `json:"message,omitempty"`
+	StartTime      *metav1.Time          `json:"startTime,omitempty"`
+	CompletionTime *metav1.Time          `json:"completionTime,omitempty"`
+}
+
+type BackupStatus struct {
+	Storage   *BackupStorageStatus    `json:"storage,omitempty"`
+	Schedules []ScheduledBackupStatus `json:"schedules,omitempty"`
+	Restore   *RestoreStatus          `json:"restore,omitempty"`
+}
+
+const (
+	BackupStorageReady     MarkLogicConditionType = "BackupStorageReady"
+	BackupSchedulesApplied MarkLogicConditionType = "BackupSchedulesApplied"
+)
@@ -0,0 +1,101 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Bootstrap holds what the operator does once the cluster is first ready.
+type Bootstrap struct {
+	// DataImport seeds a database of the cluster.
+	// +optional
+	DataImport *DataImport `json:"dataImport,omitempty"`
+}
+
+// DataImportTool is the tool a data import runs.
+// +kubebuilder:validation:Enum=mlcp;ml-gradle
+type DataImportTool string
+
+const (
+	// DataImportMLCP runs mlcp.sh import.
+	DataImportMLCP DataImportTool = "mlcp"
+	// DataImportMLGradle runs the mlLoadData task of an ml-gradle project.
+	DataImportMLGradle DataImportTool = "ml-gradle"
+)
+
+// DataImport is a Job the operator runs once all groups are ready, loading
+// seed data into a database with MLCP or ml-gradle. The Job connects to the
+// bootstrap host with the admin credentials of the cluster.
+type DataImport struct {
+	// Tool is mlcp or ml-gradle. Defaults to mlcp.
+	// +kubebuilder:default:=mlcp
+	// +optional
+	Tool DataImportTool `json:"tool,omitempty"`
+	// Image of the Job. For mlcp it has mlcp.sh on the PATH, for ml-gradle
+	// its working directory is an ml-gradle project and it has gradle on the
+	// PATH.
+	// +kubebuilder:validation:MinLength=1
+	Image string `json:"image"`
+	// +optional
+	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
+	// SourceURI is where the data is read from: a path in the image, or a URI
+	// the tool supports, such as s3:// for MLCP.
+	// +kubebuilder:validation:MinLength=1
+	SourceURI string `json:"sourceURI"`
+	// Database the data is loaded into.
+	// +kubebuilder:validation:MinLength=1
+	Database string `json:"database"`
+	// Args are passed to the tool after the arguments the operator sets.
+	// +optional
+	Args []string `json:"args,omitempty"`
+	// EnvSecretName is a Secret whose keys are set as environment variables
+	// of the Job, such as the credentials of the source.
+	// +optional
+	EnvSecretName string `json:"envSecretName,omitempty"`
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+	// BackoffLimit is how often a failed import is retried. Defaults to 3.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	BackoffLimit *int32 `json:"backoffLimit,omitempty"`
+}
+
+// DataImportPhase is where the data import stands.
+// +kubebuilder:validation:Enum=Pending;Running;Succeeded;Failed
+type DataImportPhase string
+
+const (
+	// DataImportPending waits for the groups of the cluster to be ready.
+	DataImportPending DataImportPhase = "Pending"
+	// DataImportRunning runs the Job.
+	DataImportRunning DataImportPhase = "Running"
+	// DataImportSucceeded loaded the data; the import is not run again.
+	DataImportSucceeded DataImportPhase = "Succeeded"
+	// DataImportFailed ran out of retries; the import is not run again
+	// unless its spec changes.
+	DataImportFailed DataImportPhase = "Failed"
+)
+
+// DataImportStatus reports the data import of the cluster.
+type DataImportStatus struct {
+	// Phase is Pending, Running, Succeeded or Failed.
+	Phase DataImportPhase `json:"phase"`
+	// SpecHash identifies the spec the import ran for. Changing the spec
+	// runs a new import.
+	SpecHash string `json:"specHash,omitempty"`
+	// JobName is the Job of the import.
+	JobName        string       `json:"jobName,omitempty"`
+	StartTime      *metav1.Time `json:"startTime,omitempty"`
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// Message reports what the import waits for or why it failed.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// BootstrapStatus reports what the operator did once the cluster was ready.
+type BootstrapStatus struct {
+	// +optional
+	DataImport *DataImportStatus `json:"dataImport,omitempty"`
+}
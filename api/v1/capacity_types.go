@@ -0,0 +1,43 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package v1
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ResourceCapacity sums the CPU, memory and storage of a set of pods.
+type ResourceCapacity struct {
+	// Pods is the number of pods the other fields sum up.
+	Pods int32 `json:"pods"`
+	// CPURequests and MemoryRequests sum the requests of the containers.
+	CPURequests    resource.Quantity `json:"cpuRequests"`
+	MemoryRequests resource.Quantity `json:"memoryRequests"`
+	// CPUUsage and MemoryUsage sum the usage reported by the metrics API.
+	// They are unset when the Kubernetes cluster runs no metrics-server.
+	// +optional
+	CPUUsage *resource.Quantity `json:"cpuUsage,omitempty"`
+	// +optional
+	MemoryUsage *resource.Quantity `json:"memoryUsage,omitempty"`
+	// StorageRequests and StorageCapacity sum the requested and the
+	// provisioned size of the persistent volume claims of the pods.
+	StorageRequests resource.Quantity `json:"storageRequests"`
+	StorageCapacity resource.Quantity `json:"storageCapacity"`
+}
+
+// GroupCapacity is the capacity of the pods of a group.
+type GroupCapacity struct {
+	Group            string `json:"group"`
+	ResourceCapacity `json:",inline"`
+}
+
+// CapacityStatus reports the capacity of the cluster for chargeback and
+// right-sizing. It is refreshed every few minutes.
+type CapacityStatus struct {
+	// Total sums the capacity of the groups.
+	Total ResourceCapacity `json:"total"`
+	// +listType=atomic
+	Groups         []GroupCapacity `json:"groups,omitempty"`
+	LastUpdateTime *metav1.Time    `json:"lastUpdateTime,omitempty"`
+}
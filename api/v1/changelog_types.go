@@ -0,0 +1,46 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MaxChangeLogEntries bounds status.changeLog.entries; older entries are dropped.
+const MaxChangeLogEntries = 50
+
+// SpecChangeEntry records a change of an audited spec field.
+type SpecChangeEntry struct {
+	Time metav1.Time `json:"time"`
+	// Generation is the generation of the cluster with the change.
+	Generation int64 `json:"generation,omitempty"`
+	// Field is the changed field, for example spec.image or
+	// spec.markLogicGroups[dnode].replicas.
+	Field string `json:"field"`
+	// Old and New are the values before and after the change. They are empty
+	// when the field was unset.
+	Old string `json:"old,omitempty"`
+	New string `json:"new,omitempty"`
+	// Manager is the field manager that last wrote the field, or
+	// "marklogic-operator" when it cannot be determined.
+	Manager string `json:"manager,omitempty"`
+}
+
+// ChangeLogStatus is the audit trail of the significant spec changes the
+// operator acted on: images, replicas and authentication.
+type ChangeLogStatus struct {
+	// ObservedGeneration is the generation of the cluster last audited.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Observed holds the audited fields of ObservedGeneration.
+	Observed map[string]string `json:"observed,omitempty"`
+	// +listType=atomic
+	Entries []SpecChangeEntry `json:"entries,omitempty"`
+}
+
+// Record appends the entries, dropping the oldest beyond MaxChangeLogEntries.
+func (c *ChangeLogStatus) Record(entries ...SpecChangeEntry) {
+	c.Entries = append(c.Entries, entries...)
+	if len(c.Entries) > MaxChangeLogEntries {
+		c.Entries = c.Entries[len(c.Entries)-MaxChangeLogEntries:]
+	}
+}
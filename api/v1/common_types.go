@@ -5,9 +5,12 @@ package v1
 import (
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// ContainerProbe configures a probe of the MarkLogic container. Timings left
+// unset use the defaults of the operator configuration.
 type ContainerProbe struct {
 	Enabled bool `json:"enabled,omitempty"`
 	// +kubebuilder:validation:Minimum=0
@@ -22,6 +25,96 @@ type ContainerProbe struct {
 	FailureThreshold int32 `json:"failureThreshold,omitempty"`
 }
 
+// StartupProbe holds off the liveness and readiness probes of the MarkLogic
+// container while the host boots, so hosts that take long to mount large
+// forests are not restarted by the liveness probe.
+type StartupProbe struct {
+	Enabled bool `json:"enabled,omitempty"`
+	// BootTimeout is how long a host may take to start, and with
+	// waitForForests to open its forests, before its pod is restarted.
+	// Defaults to 30m.
+	// +optional
+	BootTimeout *metav1.Duration `json:"bootTimeout,omitempty"`
+	// PeriodSeconds is how often the probe runs. Defaults to 10.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	PeriodSeconds int32 `json:"periodSeconds,omitempty"`
+	// WaitForForests keeps the probe failing until every forest of a host
+	// that is already in the cluster is open.
+	// +optional
+	WaitForForests bool `json:"waitForForests,omitempty"`
+}
+
+// ShutdownDrain drains a MarkLogic host in the preStop hook of its pod before
+// MarkLogic stops: the pod reports not ready, so Services and HAProxy stop
+// routing new requests to it, and the hook waits for the active requests on
+// the host to finish. terminationGracePeriodSeconds must leave room for the
+// delay, the timeout and the shutdown of MarkLogic.
+type ShutdownDrain struct {
+	Enabled bool `json:"enabled,omitempty"`
+	// Delay between reporting not ready and waiting for the active requests,
+	// so endpoints and load balancers catch up. Defaults to 5s.
+	// +optional
+	Delay *metav1.Duration `json:"delay,omitempty"`
+	// Timeout is how long to wait for the active requests before MarkLogic
+	// is stopped anyway. Defaults to 30s.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// HostJoin controls how the hosts of a group join the cluster. The hosts join
+// in parallel, at most maxConcurrent at a time, and retry a failed join with
+// an exponential backoff so hosts that failed together do not retry together.
+type HostJoin struct {
+	// MaxConcurrent is how many hosts of the group join at the same time.
+	// Each host waits for the host maxConcurrent ordinals before it to join.
+	// 0, the default, lets every host join at once.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxConcurrent int32 `json:"maxConcurrent,omitempty"`
+	// Retries is how many times a failed join request is retried before the
+	// pod restarts. Defaults to 10.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Retries int32 `json:"retries,omitempty"`
+	// Backoff is the delay before the first retry, doubled for every
+	// following retry. Defaults to 5s.
+	// +optional
+	Backoff *metav1.Duration `json:"backoff,omitempty"`
+	// MaxBackoff caps the delay between retries. Defaults to 1m.
+	// +optional
+	MaxBackoff *metav1.Duration `json:"maxBackoff,omitempty"`
+}
+
+// PodRemediationPolicy is what the operator does with a stuck pod.
+// +kubebuilder:validation:Enum=none;restart;recreate-with-alert
+type PodRemediationPolicy string
+
+const (
+	// PodRemediationNone reports stuck pods and leaves them alone.
+	PodRemediationNone PodRemediationPolicy = "none"
+	// PodRemediationRestart deletes a stuck pod so its StatefulSet starts it
+	// again.
+	PodRemediationRestart PodRemediationPolicy = "restart"
+	// PodRemediationRecreateWithAlert force deletes a stuck pod, without
+	// waiting for it to shut down, and records a Warning event to alert on.
+	PodRemediationRecreateWithAlert PodRemediationPolicy = "recreate-with-alert"
+)
+
+// PodRemediation remediates MarkLogic pods stuck in CrashLoopBackOff or not
+// ready for longer than a threshold. The end of the ErrorLog of a stuck pod
+// is recorded in an event before the operator acts.
+type PodRemediation struct {
+	// Policy is what the operator does with a stuck pod. Defaults to none.
+	// +kubebuilder:default:=none
+	// +optional
+	Policy PodRemediationPolicy `json:"policy,omitempty"`
+	// Threshold is how long a pod crash-loops or is not ready before it is
+	// stuck. Defaults to 10m.
+	// +optional
+	Threshold *metav1.Duration `json:"threshold,omitempty"`
+}
+
 // VolumeResizeStrategy defines how PVC resize requests are submitted.
 type VolumeResizeStrategy string
 
@@ -37,26 +130,138 @@ type DynamicGroupConfig struct {
 	TokenDuration string `json:"tokenDuration,omitempty"`
 }
 
+// ForestProvisioning creates forests for the listed databases on every host
+// of a group once it joins the cluster, so hosts added by scaling up store
+// data without manual forest setup.
+type ForestProvisioning struct {
+	// ForestsPerHost is the number of forests created on each host for every
+	// database.
+	// +kubebuilder:default:=1
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=64
+	ForestsPerHost int32 `json:"forestsPerHost,omitempty"`
+	// DataDirectory is the directory the forests are created in. The default
+	// data directory of the host is used when empty.
+	// +optional
+	DataDirectory string `json:"dataDirectory,omitempty"`
+	// FastDataDirectory holds the journals and the most recent stands of the
+	// forests, usually on a fast storage tier of the group.
+	// +optional
+	FastDataDirectory string `json:"fastDataDirectory,omitempty"`
+	// LargeDataDirectory holds the large binary documents of the forests.
+	// +optional
+	LargeDataDirectory string `json:"largeDataDirectory,omitempty"`
+	// Databases the forests are attached to.
+	// +kubebuilder:validation:MinItems=1
+	Databases []string `json:"databases"`
+}
+
+// PVCRetentionPolicyType is what happens to a data volume once its pod is
+// removed.
+// +kubebuilder:validation:Enum=Retain;Delete
+type PVCRetentionPolicyType string
+
+const (
+	PVCRetentionPolicyRetain PVCRetentionPolicyType = "Retain"
+	PVCRetentionPolicyDelete PVCRetentionPolicyType = "Delete"
+)
+
+// PVCRetentionPolicy is the persistentVolumeClaimRetentionPolicy of the
+// StatefulSet of a group. On Kubernetes versions without it, the operator
+// deletes the volumes itself.
+type PVCRetentionPolicy struct {
+	// WhenDeleted applies to the volumes of a deleted group, including the
+	// groups of a deleted cluster.
+	// +kubebuilder:default:=Retain
+	WhenDeleted PVCRetentionPolicyType `json:"whenDeleted,omitempty"`
+	// WhenScaled applies to the volumes of the pods removed by a scale-down.
+	// Scaling a group to zero, as stopping the cluster does, keeps them.
+	// +kubebuilder:default:=Retain
+	WhenScaled PVCRetentionPolicyType `json:"whenScaled,omitempty"`
+}
+
 // Storage is the inteface to add pvc and pv support in marklogic
+// +kubebuilder:validation:XValidation:rule="has(self.tiers) == has(oldSelf.tiers) && (!has(self.tiers) || self.tiers == oldSelf.tiers)", message="persistence.tiers cannot change, the volume claim templates of a StatefulSet are immutable"
 type Persistence struct {
 	Enabled bool `json:"enabled,omitempty"`
 	// +kubebuilder:validation:Required
 	Size string `json:"size,omitempty"`
 	// +kubebuilder:validation:Enum=parallel;sequential
 	// +kubebuilder:default:=parallel
-	ResizeStrategy   VolumeResizeStrategy `json:"resizeStrategy,omitempty"`
-	StorageClassName string               `json:"storageClassName,omitempty"`
+	ResizeStrategy VolumeResizeStrategy `json:"resizeStrategy,omitempty"`
+	// RetentionPolicy controls whether the data volumes are kept or deleted
+	// when the group is deleted or scaled down. They are kept by default.
+	// +optional
+	RetentionPolicy *PVCRetentionPolicy `json:"retentionPolicy,omitempty"`
+	// StorageClassName of the data volumes. Defaults to the
+	// defaultStorageClass of the operator configuration when the
+	// StatefulSet is created.
+	StorageClassName string `json:"storageClassName,omitempty"`
+	// Tiers are volumes of other storage classes, mounted at
+	// /var/opt/MarkLogic/tiers/<name>, for the data directories of forests
+	// and the partitions spec.tieredStorage migrates.
+	// +kubebuilder:validation:MaxItems=4
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Tiers []StorageTier `json:"tiers,omitempty"`
 	// +kubebuilder:default:={ReadWriteOnce}
 	AccessModes []corev1.PersistentVolumeAccessMode `json:"accessModes,omitempty"`
 	Annotations map[string]string                   `json:"annotations,omitempty"`
 }
 
+// StorageTier is a volume of every pod of a group for a tier of forest
+// storage, such as fast local disks or cheap archive storage.
+type StorageTier struct {
+	// Name of the tier. The volume claims are named tier-<name>-<pod>.
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=20
+	Name string `json:"name"`
+	// StorageClassName of the volumes. The default storage class is used
+	// when empty.
+	// +optional
+	StorageClassName string            `json:"storageClassName,omitempty"`
+	Size             resource.Quantity `json:"size"`
+}
+
 type HugePages struct {
 	Enabled bool `json:"enabled,omitempty"`
 	// +kubebuilder:default:="/dev/hugepages"
 	MountPath string `json:"mountPath,omitempty"`
 }
 
+// ReadOnlyRootFilesystem runs the MarkLogic and fluent-bit containers with a
+// read-only root filesystem. /var/opt/MarkLogic stays on the data volume.
+// +kubebuilder:validation:XValidation:rule="!self.enabled || (has(self.writablePaths) && '/tmp' in self.writablePaths)", message="writablePaths must include /tmp, the scripts of the MarkLogic container write there"
+type ReadOnlyRootFilesystem struct {
+	Enabled bool `json:"enabled,omitempty"`
+	// WritablePaths of the MarkLogic container each get an emptyDir volume.
+	// /tmp holds the output of the scripts and the MarkLogic PID file.
+	// +kubebuilder:default:={"/tmp"}
+	// +kubebuilder:validation:MaxItems=20
+	// +kubebuilder:validation:XValidation:rule="self.all(p, p.startsWith('/') && p != '/' && p != '/var/opt/MarkLogic' && !p.startsWith('/var/opt/MarkLogic/'))", message="writablePaths must be absolute and outside /var/opt/MarkLogic"
+	// +listType=set
+	WritablePaths []string `json:"writablePaths,omitempty"`
+	// SizeLimit of each emptyDir volume.
+	// +optional
+	SizeLimit *resource.Quantity `json:"sizeLimit,omitempty"`
+}
+
+// PodManagementPolicy controls the order in which the pods of a group start.
+type PodManagementPolicy string
+
+const (
+	// PodManagementParallel starts every pod of the group at once.
+	PodManagementParallel PodManagementPolicy = "Parallel"
+	// PodManagementOrderedReady starts the pods one at a time, each once the
+	// previous one is ready.
+	PodManagementOrderedReady PodManagementPolicy = "OrderedReady"
+	// PodManagementBootstrapFirst starts pod 0 first and the other pods in
+	// parallel once it is ready, so the first host forms or joins the
+	// cluster before the others join it.
+	PodManagementBootstrapFirst PodManagementPolicy = "BootstrapFirst"
+)
+
 type Service struct {
 	// +kubebuilder:default:= ClusterIP
 	Type            corev1.ServiceType   `json:"type,omitempty"`
@@ -70,16 +275,44 @@ type VolumeMountWrapper struct {
 }
 
 type AdminAuth struct {
-	SecretName     *string `json:"secretName,omitempty"`
-	AdminUsername  *string `json:"adminUsername,omitempty"`
-	AdminPassword  *string `json:"adminPassword,omitempty"`
+	SecretName    *string `json:"secretName,omitempty"`
+	AdminUsername *string `json:"adminUsername,omitempty"`
+	AdminPassword *string `json:"adminPassword,omitempty"`
+	// WalletPassword protects the wallet of the cluster, which stores its
+	// keys and certificates. It is written to the wallet-password key of the
+	// generated admin Secret. With secretName, set that key of the Secret
+	// instead. Only applied when the security of the cluster is initialized.
+	// +optional
 	WalletPassword *string `json:"walletPassword,omitempty"`
+	// Realm is the realm used for digest authentication, written to the realm
+	// key of the generated admin Secret. With secretName, set that key of the
+	// Secret instead. Defaults to public. Only applied when the security of
+	// the cluster is initialized.
+	// +kubebuilder:validation:MinLength=1
+	// +optional
+	Realm *string `json:"realm,omitempty"`
+	// OperatorUser makes the operator create a dedicated MarkLogic user with
+	// the manage-admin role once the cluster is bootstrapped, store it in the
+	// <cluster>-manage-admin Secret and use it for its Manage API calls. The
+	// admin credentials are then only used to create that user and for calls
+	// that need the admin role, such as the Security database upgrade.
+	// Defaults to true.
+	// +optional
+	OperatorUser *bool `json:"operatorUser,omitempty"`
 }
 
+// +kubebuilder:validation:XValidation:rule="!has(self.engine) || self.engine == 'fluent-bit' || (!has(self.buffer) && !has(self.healthCheck) && !has(self.sampling) && !has(self.severityRoutes) && (!has(self.credentialsSecretName) || size(self.credentialsSecretName) == 0))",message="buffer, healthCheck, sampling, severityRoutes and credentialsSecretName are only supported by the fluent-bit engine"
 type LogCollection struct {
 	// +kubebuilder:default:=false
 	Enabled bool `json:"enabled,omitempty"`
-	// +kubebuilder:default:="fluent/fluent-bit:4.1.1"
+	// Engine is the log collector that runs next to MarkLogic. The inputs,
+	// filters, outputs and parsers are written in the YAML configuration of
+	// the engine.
+	// +kubebuilder:default:=fluent-bit
+	// +optional
+	Engine LogCollectionEngine `json:"engine,omitempty"`
+	// Image is the image of the engine. Defaults to the logCollectionImage,
+	// fluentdImage or vectorImage of the operator configuration.
 	Image            string                        `json:"image,omitempty"`
 	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
 	SecurityContext  *corev1.SecurityContext       `json:"securityContext,omitempty"`
@@ -91,6 +324,132 @@ type LogCollection struct {
 	Filters string         `json:"filters,omitempty"`
 	Inputs  string         `json:"inputs,omitempty"`
 	Parsers string         `json:"parsers,omitempty"`
+	// CredentialsSecretName is a Secret whose keys are available to the
+	// outputs as ${KEY} variables. When it changes, fluent-bit reloads its
+	// configuration without restarting the pods.
+	// +optional
+	CredentialsSecretName string `json:"credentialsSecretName,omitempty"`
+	// Buffer bounds what fluent-bit buffers while its outputs fall behind,
+	// such as under a burst of AccessLog entries.
+	// +optional
+	Buffer *LogCollectionBuffer `json:"buffer,omitempty"`
+	// HealthCheck sets when fluent-bit reports itself unhealthy to its
+	// liveness probe, which restarts it.
+	// +optional
+	HealthCheck *LogCollectionHealthCheck `json:"healthCheck,omitempty"`
+	// Sampling ships only a share of the ErrorLog records below a severity,
+	// such as the Debug and Info records of a busy cluster.
+	// +optional
+	Sampling *LogSampling `json:"sampling,omitempty"`
+	// SeverityRoutes send the ErrorLog records of a severity and above to
+	// outputs of their own. A record takes the first route, by descending
+	// minSeverity, it qualifies for.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	SeverityRoutes []LogSeverityRoute `json:"severityRoutes,omitempty"`
+}
+
+// LogSeverity is the level of a MarkLogic ErrorLog record.
+// +kubebuilder:validation:Enum=Finest;Finer;Fine;Debug;Config;Info;Notice;Warning;Error;Critical;Alert;Emergency
+type LogSeverity string
+
+// LogSampling ships every record at or above MinSeverity and Percent of the
+// records below it. Records without a severity, such as AccessLog entries,
+// are always shipped.
+type LogSampling struct {
+	// Percent of the records below MinSeverity that are shipped.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	Percent int32 `json:"percent"`
+	// MinSeverity is the lowest severity that is always shipped.
+	// +kubebuilder:default:=Warning
+	// +optional
+	MinSeverity LogSeverity `json:"minSeverity,omitempty"`
+}
+
+// LogSeverityRoute sends the ErrorLog records of MinSeverity and above to
+// Outputs.
+type LogSeverityRoute struct {
+	// Name of the route. Its records are tagged marklogic.severity.<name>.
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`
+	Name string `json:"name"`
+	// MinSeverity is the lowest severity of the route.
+	MinSeverity LogSeverity `json:"minSeverity"`
+	// Outputs are the fluent-bit outputs of the route. Outputs without a
+	// match receive the records of the route.
+	Outputs string `json:"outputs"`
+}
+
+// LogCollectionEngine is a log collector the operator can run as the sidecar
+// of the MarkLogic pods.
+// +kubebuilder:validation:Enum=fluent-bit;fluentd;vector
+type LogCollectionEngine string
+
+const (
+	LogCollectionEngineFluentBit LogCollectionEngine = "fluent-bit"
+	LogCollectionEngineFluentd   LogCollectionEngine = "fluentd"
+	LogCollectionEngineVector    LogCollectionEngine = "vector"
+)
+
+// LogCollectionHealthCheck is the health check of fluent-bit. fluent-bit is
+// unhealthy once its outputs failed more often than either count within the
+// period.
+type LogCollectionHealthCheck struct {
+	// ErrorsCount is how many failed flushes of the outputs make fluent-bit
+	// unhealthy. Defaults to 5.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ErrorsCount int32 `json:"errorsCount,omitempty"`
+	// RetryFailureCount is how many records the outputs dropped after their
+	// retries make fluent-bit unhealthy. Defaults to 5.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	RetryFailureCount int32 `json:"retryFailureCount,omitempty"`
+	// PeriodSeconds is the period the failures are counted in. Defaults to
+	// 60.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	PeriodSeconds int32 `json:"periodSeconds,omitempty"`
+}
+
+// LogCollectionStorageType is where fluent-bit buffers records it has not
+// sent yet.
+// +kubebuilder:validation:Enum=memory;filesystem
+type LogCollectionStorageType string
+
+const (
+	LogCollectionStorageMemory     LogCollectionStorageType = "memory"
+	LogCollectionStorageFilesystem LogCollectionStorageType = "filesystem"
+)
+
+// LogCollectionBuffer configures the buffers and retries of fluent-bit, so a
+// high volume of logs neither gets the sidecar OOM killed nor drops records
+// while an output is unavailable.
+type LogCollectionBuffer struct {
+	// MemBufLimit is the memory each input buffers, such as 4MB. An input at
+	// its limit pauses reading its files with memory storage, and buffers
+	// further records on the filesystem with filesystem storage.
+	// +kubebuilder:default:="4MB"
+	// +kubebuilder:validation:Pattern=`^[0-9]+[KMG]?B?$`
+	// +optional
+	MemBufLimit string `json:"memBufLimit,omitempty"`
+	// StorageType is memory or filesystem. With filesystem, the operator
+	// mounts an emptyDir for the buffered records, which keeps them when
+	// fluent-bit restarts.
+	// +kubebuilder:default:=memory
+	// +optional
+	StorageType LogCollectionStorageType `json:"storageType,omitempty"`
+	// StorageLimit is the most each output buffers on the filesystem before
+	// it drops its oldest records. Defaults to 1Gi.
+	// +optional
+	StorageLimit *resource.Quantity `json:"storageLimit,omitempty"`
+	// RetryLimit is how often the outputs retry to send records before
+	// dropping them, 0 to retry without limit. Defaults to fluent-bit's
+	// single retry.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	RetryLimit *int32 `json:"retryLimit,omitempty"`
 }
 
 type LogFilesConfig struct {
@@ -99,6 +458,11 @@ type LogFilesConfig struct {
 	RequestLogs bool `json:"requestLogs,omitempty"`
 	CrashLogs   bool `json:"crashLogs,omitempty"`
 	AuditLogs   bool `json:"auditLogs,omitempty"`
+	// StructuredAccessLogs splits the AccessLog entries into the method, path,
+	// protocol, status, size, referer, user_agent and response_time fields,
+	// instead of the request and request_info fields.
+	// +optional
+	StructuredAccessLogs bool `json:"structuredAccessLogs,omitempty"`
 }
 
 type NetworkPolicy struct {
@@ -134,6 +498,23 @@ type HAProxy struct {
 	Affinity     *corev1.Affinity            `json:"affinity,omitempty"`
 	NodeSelector map[string]string           `json:"nodeSelector,omitempty"`
 	Ingress      Ingress                     `json:"ingress,omitempty"`
+	// RolloutCoordination takes a host out of the backends before the
+	// operator restarts its pod and puts it back once the health gates
+	// passed.
+	// +optional
+	RolloutCoordination *HAProxyRolloutCoordination `json:"rolloutCoordination,omitempty"`
+}
+
+// HAProxyRolloutCoordination configures the HAProxy runtime API the operator
+// uses to put the servers of a restarting host into maintenance. The API
+// listens on every HAProxy pod without authentication, so restrict access to
+// the port with a NetworkPolicy.
+type HAProxyRolloutCoordination struct {
+	Enabled bool `json:"enabled,omitempty"`
+	// +kubebuilder:default:=9999
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	RuntimeAPIPort int32 `json:"runtimeAPIPort,omitempty"`
 }
 
 // HAProxyGroup represents group-level HAProxy configuration that can override cluster settings
@@ -198,3 +579,29 @@ type Ingress struct {
 	TLS              []networkingv1.IngressTLS  `json:"tls,omitempty"`
 	AdditionalHosts  []networkingv1.IngressRule `json:"additionalHosts,omitempty"`
 }
+
+// NetworkAccess controls whether the App-Services (8000), Admin (8001) and
+// Manage (8002) ports are reachable from outside the Kubernetes cluster. By
+// default they stay on ClusterIP services only, even when a group service is
+// of type NodePort or LoadBalancer.
+// +kubebuilder:validation:XValidation:rule="(has(self.exposeAdmin) && self.exposeAdmin) || !has(self.adminIngress)", message="adminIngress can only be set when exposeAdmin is true"
+// +kubebuilder:validation:XValidation:rule="!has(self.exposeAdmin) || !self.exposeAdmin || has(self.adminIngress)", message="exposeAdmin requires adminIngress with TLS"
+type NetworkAccess struct {
+	// +kubebuilder:default:=false
+	ExposeAdmin  bool          `json:"exposeAdmin,omitempty"`
+	AdminIngress *AdminIngress `json:"adminIngress,omitempty"`
+}
+
+// AdminIngress exposes the admin ports through an Ingress with one host per
+// port. TLS is mandatory. With spec.ocpCompatible Routes are created instead.
+// +kubebuilder:validation:XValidation:rule="has(self.appServicesHost) || has(self.adminHost) || has(self.manageHost)", message="at least one of appServicesHost, adminHost or manageHost must be set"
+type AdminIngress struct {
+	IngressClassName string            `json:"ingressClassName,omitempty"`
+	Annotations      map[string]string `json:"annotations,omitempty"`
+	AppServicesHost  string            `json:"appServicesHost,omitempty"`
+	AdminHost        string            `json:"adminHost,omitempty"`
+	ManageHost       string            `json:"manageHost,omitempty"`
+	// +kubebuilder:validation:MinLength=1
+	// Name of the kubernetes.io/tls Secret holding the certificate for all hosts.
+	TLSSecretName string `json:"tlsSecretName"`
+}
@@ -0,0 +1,49 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Diagnostics turns on MarkLogic trace events and a more verbose file log
+// level on the hosts of a group for debugging. With expiresAfter the
+// operator removes them again, so they do not linger in production.
+type Diagnostics struct {
+	// TraceEvents are the MarkLogic trace events to activate, for example
+	// "Merge" or "SSL Handshake".
+	// +listType=set
+	// +optional
+	TraceEvents []string `json:"traceEvents,omitempty"`
+	// FileLogLevel is the level of ErrorLog.txt while the diagnostics apply.
+	// +kubebuilder:validation:Enum=finest;finer;fine;debug;config;info;notice;warning;error;critical;alert;emergency
+	// +optional
+	FileLogLevel string `json:"fileLogLevel,omitempty"`
+	// ExpiresAfter is how long the diagnostics apply after the operator
+	// applied them. Without it they apply until removed from the spec.
+	// +optional
+	ExpiresAfter *metav1.Duration `json:"expiresAfter,omitempty"`
+}
+
+// DiagnosticSettings are the trace event and log level settings of a
+// MarkLogic group.
+type DiagnosticSettings struct {
+	EventsActivated bool `json:"eventsActivated,omitempty"`
+	// +listType=atomic
+	TraceEvents  []string `json:"traceEvents,omitempty"`
+	FileLogLevel string   `json:"fileLogLevel,omitempty"`
+}
+
+// DiagnosticsStatus reports the diagnostics applied to a group.
+type DiagnosticsStatus struct {
+	Group string `json:"group"`
+	// Applied are the diagnostics of the spec the operator applied.
+	Applied Diagnostics `json:"applied"`
+	// Previous are the settings of the group before, which are restored
+	// when the diagnostics expire or are removed from the spec.
+	Previous  DiagnosticSettings `json:"previous"`
+	AppliedAt metav1.Time        `json:"appliedAt"`
+	ExpiresAt *metav1.Time       `json:"expiresAt,omitempty"`
+	// Expired is set once the previous settings were restored at ExpiresAt.
+	Expired bool `json:"expired,omitempty"`
+}
@@ -0,0 +1,20 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ExportStatus reports the last export of the cluster into a bundle for
+// recreating it in another Kubernetes cluster.
+type ExportStatus struct {
+	// RequestID is the value of the marklogic.progress.com/export annotation
+	// the export was made for.
+	RequestID string `json:"requestID,omitempty"`
+	// ConfigMapName is the ConfigMap holding the bundle.
+	ConfigMapName string       `json:"configMapName,omitempty"`
+	ExportTime    *metav1.Time `json:"exportTime,omitempty"`
+	// Message reports why the export failed.
+	Message string `json:"message,omitempty"`
+}
@@ -0,0 +1,33 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// FIPSState is the FIPS compliance of a cluster with spec.fipsMode.
+// +kubebuilder:validation:Enum=Pending;Compliant;NonCompliant
+type FIPSState string
+
+const (
+	// FIPSStatePending is a cluster whose MarkLogic settings could not be
+	// read yet, for example while the bootstrap host starts.
+	FIPSStatePending   FIPSState = "Pending"
+	FIPSStateCompliant FIPSState = "Compliant"
+	// FIPSStateNonCompliant is a cluster running an image without FIPS
+	// support, or whose MarkLogic settings could not be applied.
+	FIPSStateNonCompliant FIPSState = "NonCompliant"
+)
+
+// FIPSStatus reports the FIPS compliance of the cluster.
+type FIPSStatus struct {
+	State FIPSState `json:"state,omitempty"`
+	// SSLFIPSEnabled is the ssl-fips-enabled property of the MarkLogic
+	// cluster.
+	SSLFIPSEnabled bool   `json:"sslFipsEnabled,omitempty"`
+	Message        string `json:"message,omitempty"`
+	// ObservedGeneration is the generation of the cluster last checked.
+	ObservedGeneration int64        `json:"observedGeneration,omitempty"`
+	LastCheckTime      *metav1.Time `json:"lastCheckTime,omitempty"`
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HealthReportSchedule is how often the health report of a cluster is
+// produced.
+// +kubebuilder:validation:Enum=Daily;Weekly
+type HealthReportSchedule string
+
+const (
+	HealthReportDaily  HealthReportSchedule = "Daily"
+	HealthReportWeekly HealthReportSchedule = "Weekly"
+)
+
+// HealthReport produces a periodic health report of the cluster: the state
+// of its forests, the storage left on its hosts, the expiry of its license and
+// the upgrades it has pending. The report is written to the
+// <cluster>-health-report ConfigMap and optionally posted to a notification
+// webhook.
+type HealthReport struct {
+	// Schedule is Daily or Weekly.
+	// +kubebuilder:default:=Daily
+	// +optional
+	Schedule HealthReportSchedule `json:"schedule,omitempty"`
+	// LicenseExpiryWarningDays is how many days before its expiry the license
+	// is reported.
+	// +kubebuilder:default:=30
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	LicenseExpiryWarningDays int32 `json:"licenseExpiryWarningDays,omitempty"`
+	// StorageWarningPercent is the usage of the forest storage of a host
+	// above which the host is reported.
+	// +kubebuilder:default:=85
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	StorageWarningPercent int32 `json:"storageWarningPercent,omitempty"`
+	// WebhookURLSecret holds the URL of a notification webhook the report is
+	// posted to as JSON. Its text field summarizes the report, so Slack and
+	// Microsoft Teams incoming webhooks display it.
+	// +optional
+	WebhookURLSecret *corev1.SecretKeySelector `json:"webhookURLSecret,omitempty"`
+}
+
+// HealthReportResult sums up a health report.
+type HealthReportResult string
+
+const (
+	HealthReportHealthy  HealthReportResult = "Healthy"
+	HealthReportWarning  HealthReportResult = "Warning"
+	HealthReportCritical HealthReportResult = "Critical"
+)
+
+// HealthReportStatus reports the last health report of the cluster.
+type HealthReportStatus struct {
+	// ConfigMapName is the ConfigMap holding the report.
+	ConfigMapName string       `json:"configMapName,omitempty"`
+	ReportTime    *metav1.Time `json:"reportTime,omitempty"`
+	// Result is Healthy, Warning or Critical.
+	Result HealthReportResult `json:"result,omitempty"`
+	// Message reports why the report could not be written or posted.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
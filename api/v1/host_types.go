@@ -0,0 +1,92 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HostStatus is the MarkLogic host of a pod of a group.
+type HostStatus struct {
+	Pod string `json:"pod"`
+	// Host is the name of the MarkLogic host, empty while the pod has not
+	// joined the cluster.
+	// +optional
+	Host   string `json:"host,omitempty"`
+	Online bool   `json:"online"`
+	// +optional
+	Version string `json:"version,omitempty"`
+	// Forests is the number of forests on the host.
+	Forests int32 `json:"forests"`
+	// Restarts counts the restarts of the MarkLogic container of the pod.
+	Restarts int32 `json:"restarts"`
+	// LastRestartTime is when the MarkLogic container last started.
+	// +optional
+	LastRestartTime *metav1.Time `json:"lastRestartTime,omitempty"`
+}
+
+// GroupHostsStatus sums up the hosts of a group.
+type GroupHostsStatus struct {
+	Group       string `json:"group"`
+	Hosts       int32  `json:"hosts"`
+	OnlineHosts int32  `json:"onlineHosts"`
+	Forests     int32  `json:"forests"`
+	// Versions are the MarkLogic versions the hosts run, more than one
+	// while an upgrade rolls out.
+	// +listType=set
+	// +optional
+	Versions []string `json:"versions,omitempty"`
+}
+
+// ClusterHostsStatus rolls up the hosts of the groups. The hosts themselves
+// are in the status of the MarklogicGroups. It is refreshed every minute.
+type ClusterHostsStatus struct {
+	// +listType=atomic
+	Groups         []GroupHostsStatus `json:"groups,omitempty"`
+	LastUpdateTime *metav1.Time       `json:"lastUpdateTime,omitempty"`
+}
+
+// HostRecoveryStatus tracks a pod that is ready while its MarkLogic host is
+// offline in the cluster or did not join it, for example after the pod was
+// rescheduled from a failed node.
+type HostRecoveryStatus struct {
+	Pod string `json:"pod"`
+	// Host is the name the cluster knows the host of the pod by, empty
+	// while the cluster does not know it.
+	// +optional
+	Host string `json:"host,omitempty"`
+	// Attempts counts the renames and restarts that tried to recover the
+	// host.
+	Attempts int32 `json:"attempts"`
+	// LastAttemptTime is when the host was last renamed or its pod
+	// restarted, or when the attempts ran out.
+	// +optional
+	LastAttemptTime *metav1.Time `json:"lastAttemptTime,omitempty"`
+	// Failed is true once the attempts ran out. The host is no longer
+	// recovered until its pod is recreated.
+	// +optional
+	Failed bool `json:"failed,omitempty"`
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// QuorumStatus reports how many MarkLogic hosts of the cluster are online.
+// The cluster keeps its quorum while more than half of its hosts are.
+type QuorumStatus struct {
+	// OnlineHosts is how many hosts the cluster sees online.
+	OnlineHosts int32 `json:"onlineHosts"`
+	// TotalHosts is how many hosts the cluster has.
+	TotalHosts int32 `json:"totalHosts"`
+	// LostSince is when the cluster was first seen without quorum, empty
+	// while it has quorum.
+	// +optional
+	LostSince *metav1.Time `json:"lostSince,omitempty"`
+	// LastCheckTime is when the hosts were last checked.
+	// +optional
+	LastCheckTime *metav1.Time `json:"lastCheckTime,omitempty"`
+	// AcknowledgedRequestID is the last request ID of the
+	// marklogic.progress.com/acknowledge-quorum-loss annotation the operator
+	// processed.
+	// +optional
+	AcknowledgedRequestID string `json:"acknowledgedRequestID,omitempty"`
+}
@@ -29,6 +29,8 @@ import (
 
 // +kubebuilder:validation:XValidation:rule="!has(self.haproxy) || !(self.haproxy.enabled == true && self.haproxy.pathBasedRouting == true) || self.image.split(':')[1].matches('.*latest.*') || int(self.image.split(':')[1].split('.')[0] + self.image.split(':')[1].split('.')[1]) >= 111", message="HAProxy and Pathbased Routing is enabled. PathBasedRouting is only supported for MarkLogic 11.1 and above"
 // +kubebuilder:validation:XValidation:rule="!has(self.markLogicGroups) || !self.markLogicGroups.exists(g, g.isDynamic && (!has(g.image) || size(g.image) == 0)) || self.image.matches('^.+:(latest.*|((1[2-9]|[2-9][0-9])[.][0-9]+[.][0-9]+.*))$')", message="dynamic hosts require image tag latest or MarkLogic major version 12+"
+// +kubebuilder:validation:XValidation:rule="!has(self.restrictedPodSecurity) || !self.restrictedPodSecurity || (self.image.contains('rootless') && !self.markLogicGroups.exists(g, has(g.image) && size(g.image) > 0 && !g.image.contains('rootless')))", message="restrictedPodSecurity requires rootless MarkLogic images"
+// +kubebuilder:validation:XValidation:rule="has(self.fullnameOverride) == has(oldSelf.fullnameOverride)", message="fullnameOverride can not be added or removed after the cluster is created"
 type MarklogicClusterSpec struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
@@ -36,6 +38,25 @@ type MarklogicClusterSpec struct {
 	// +kubebuilder:default:="cluster.local"
 	ClusterDomain string `json:"clusterDomain,omitempty"`
 
+	// FullnameOverride replaces the cluster name in the names of the
+	// resources generated for the whole cluster: the HAProxy resources, the
+	// Ingresses, the NetworkPolicy and the OpenShift Routes. Group resources
+	// are named after their group.
+	// +kubebuilder:validation:MaxLength=40
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf", message="fullnameOverride can not be changed"
+	// +optional
+	FullnameOverride string `json:"fullnameOverride,omitempty"`
+	// AdditionalLabels are added to every resource generated for the cluster
+	// and to its pods, for tagging policies and cost attribution.
+	// +kubebuilder:validation:XValidation:rule="!self.exists(k, k in ['app.kubernetes.io/name', 'app.kubernetes.io/instance', 'app.kubernetes.io/managed-by', 'app.kubernetes.io/component', 'marklogic.progress.com/cluster'])", message="additionalLabels cannot set the labels managed by the operator"
+	// +optional
+	AdditionalLabels map[string]string `json:"additionalLabels,omitempty"`
+	// AdditionalAnnotations are added to every resource generated for the
+	// cluster and to its pods.
+	// +optional
+	AdditionalAnnotations map[string]string `json:"additionalAnnotations,omitempty"`
+
 	// +kubebuilder:default:="progressofficial/marklogic-db:12.0.3-ubi9-rootless-2.2.6"
 	// +kubebuilder:validation:MaxLength=256
 	Image string `json:"image"`
@@ -54,6 +75,25 @@ type MarklogicClusterSpec struct {
 	Persistence                   *Persistence                 `json:"persistence,omitempty"`
 	Resources                     *corev1.ResourceRequirements `json:"resources,omitempty"`
 	TerminationGracePeriodSeconds *int64                       `json:"terminationGracePeriodSeconds,omitempty"`
+	// Drain drains each host before its pod stops. Groups can override it.
+	// +optional
+	Drain *ShutdownDrain `json:"drain,omitempty"`
+	// HostJoin controls how the hosts join the cluster. Groups can override it.
+	// +optional
+	HostJoin *HostJoin `json:"hostJoin,omitempty"`
+	// AutoscalerDisruption tells Karpenter and the cluster autoscaler whether
+	// they may evict the pods, Never or Coordinated with the failover of their
+	// forests. Groups can override it.
+	// +optional
+	AutoscalerDisruption AutoscalerDisruption `json:"autoscalerDisruption,omitempty"`
+	// PodRemediation remediates pods stuck in CrashLoopBackOff or not
+	// ready. Groups can override it.
+	// +optional
+	PodRemediation *PodRemediation `json:"podRemediation,omitempty"`
+	// StartupProbe gives the hosts time to boot before the liveness probe
+	// applies. Groups can override it.
+	// +optional
+	StartupProbe *StartupProbe `json:"startupProbe,omitempty"`
 	// +kubebuilder:validation:Enum=OnDelete;RollingUpdate
 	// +kubebuilder:default:="OnDelete"
 	UpdateStrategy            appsv1.StatefulSetUpdateStrategyType `json:"updateStrategy,omitempty"`
@@ -68,13 +108,104 @@ type MarklogicClusterSpec struct {
 	EnableConverters          bool                                 `json:"enableConverters,omitempty"`
 	// +kubebuilder:default:={enabled: false, mountPath: "/dev/hugepages"}
 	HugePages *HugePages `json:"hugePages,omitempty"`
-	// +kubebuilder:default:={enabled: false, image: "fluent/fluent-bit:4.1.1", resources: {requests: {cpu: "100m", memory: "200Mi"}, limits: {cpu: "200m", memory: "500Mi"}}, files: {errorLogs: true, accessLogs: true, requestLogs: true}, outputs: "stdout"}
+	// ReadOnlyRootFilesystem runs the MarkLogic and fluent-bit containers with
+	// a read-only root filesystem. Groups can override it.
+	// +optional
+	ReadOnlyRootFilesystem *ReadOnlyRootFilesystem `json:"readOnlyRootFilesystem,omitempty"`
+	// Architecture schedules the MarkLogic pods on nodes of this CPU
+	// architecture unless the affinity or nodeSelector already select one.
+	// Groups can override it.
+	// +kubebuilder:validation:Enum=amd64;arm64
+	// +optional
+	Architecture string `json:"architecture,omitempty"`
+	// PodManagementPolicy is the order in which the pods of the groups start:
+	// Parallel, the default, OrderedReady or BootstrapFirst. Groups can
+	// override it.
+	// +kubebuilder:validation:Enum=Parallel;OrderedReady;BootstrapFirst
+	// +optional
+	PodManagementPolicy PodManagementPolicy `json:"podManagementPolicy,omitempty"`
+	// +kubebuilder:default:={enabled: false, resources: {requests: {cpu: "100m", memory: "200Mi"}, limits: {cpu: "200m", memory: "500Mi"}}, files: {errorLogs: true, accessLogs: true, requestLogs: true}, outputs: "stdout"}
 	LogCollection                  *LogCollection                  `json:"logCollection,omitempty"`
 	HAProxy                        *HAProxy                        `json:"haproxy,omitempty"`
 	Tls                            *Tls                            `json:"tls,omitempty"`
 	AdditionalVolumes              *[]corev1.Volume                `json:"additionalVolumes,omitempty"`
 	AdditionalVolumeMounts         *[]corev1.VolumeMount           `json:"additionalVolumeMounts,omitempty"`
 	AdditionalVolumeClaimTemplates *[]corev1.PersistentVolumeClaim `json:"additionalVolumeClaimTemplates,omitempty"`
+	Backup                         *Backup                         `json:"backup,omitempty"`
+	// TieredStorage migrates older partitions of databases to other storage
+	// tiers.
+	// +optional
+	TieredStorage *TieredStorage `json:"tieredStorage,omitempty"`
+	// Replication checks the database replication to the replica clusters
+	// and reports it in the ReplicationDegraded condition.
+	// +optional
+	Replication *ReplicationCheck `json:"replication,omitempty"`
+	// HealthReport writes a daily or weekly health report of the cluster to
+	// a ConfigMap and optionally posts it to a notification webhook.
+	// +optional
+	HealthReport *HealthReport `json:"healthReport,omitempty"`
+	// NodeDrain fails over the forests of pods on cordoned nodes before they
+	// are evicted, and keeps a PodDisruptionBudget for every group.
+	// +optional
+	NodeDrain *NodeDrain `json:"nodeDrain,omitempty"`
+	// Bootstrap runs a data import once all groups are first ready.
+	// +optional
+	Bootstrap *Bootstrap `json:"bootstrap,omitempty"`
+	// Modules declares a modules database and loads the application
+	// modules of a ConfigMap or OCI artifact into it when they change.
+	// +optional
+	Modules *Modules `json:"modules,omitempty"`
+	// +kubebuilder:default:={exposeAdmin: false}
+	NetworkAccess *NetworkAccess `json:"networkAccess,omitempty"`
+	Upgrade       *UpgradeSpec   `json:"upgrade,omitempty"`
+	// Stopped stops the cluster: HAProxy and then the groups scale to 0, the
+	// bootstrap group last. Setting it back to false starts the bootstrap
+	// group first and then the other groups. Volumes are kept.
+	// +optional
+	Stopped bool `json:"stopped,omitempty"`
+	// Hibernation stops and starts the cluster on a schedule. spec.stopped
+	// keeps the cluster stopped regardless of the schedule.
+	// +optional
+	Hibernation *Hibernation `json:"hibernation,omitempty"`
+	// FIPSMode runs the cluster as a FIPS 140 deployment: MarkLogic restricts
+	// its TLS to the FIPS validated module, HAProxy frontends only offer FIPS
+	// approved ciphers and the images must support FIPS. The compliance is
+	// reported in status.fips.
+	// +optional
+	FIPSMode bool `json:"fipsMode,omitempty"`
+	// OCPCompatible adapts the generated resources to OpenShift: user and
+	// group IDs outside the ranges the SCC of the namespace allows are left to
+	// the SCC, pods run with the RuntimeDefault seccomp profile and the SELinux
+	// level of the namespace, and Routes are created instead of Ingresses.
+	// +optional
+	OCPCompatible bool `json:"ocpCompatible,omitempty"`
+	// RestrictedPodSecurity generates pods that meet the restricted Pod
+	// Security Standard: they run as non-root with the RuntimeDefault seccomp
+	// profile, without privilege escalation and with all capabilities dropped.
+	// The MarkLogic images must be rootless.
+	// +optional
+	RestrictedPodSecurity bool `json:"restrictedPodSecurity,omitempty"`
+	// SupportBundle is where the support bundles requested with the
+	// marklogic.progress.com/support-bundle annotation are stored.
+	// +optional
+	SupportBundle *SupportBundle `json:"supportBundle,omitempty"`
+	// HostRenames is set on clusters cloned from the data volumes of another
+	// cluster. Before MarkLogic starts, the host names of the groups of the
+	// source cluster in its configuration are rewritten to those of the
+	// groups that took their place.
+	// +listType=atomic
+	// +optional
+	HostRenames []HostRename `json:"hostRenames,omitempty"`
+	// Operations requests the operations of the control annotations from
+	// the spec, for clusters managed with GitOps.
+	// +optional
+	Operations *Operations `json:"operations,omitempty"`
+	// Profile fills an empty markLogicGroups with the groups of a common
+	// topology when the cluster is created through the defaulting webhook:
+	// single-node, three-node-ha or enode-dnode. Explicit groups are kept.
+	// +kubebuilder:validation:Enum=single-node;three-node-ha;enode-dnode
+	// +optional
+	Profile ClusterProfile `json:"profile,omitempty"`
 
 	// +kubebuilder:validation:MaxItems=100
 	// +kubebuilder:validation:MinItems=1
@@ -88,12 +219,42 @@ type MarklogicClusterSpec struct {
 	// +kubebuilder:validation:XValidation:rule="size(self) >= 4 && size(oldSelf) >= 4 ? self[3].name == oldSelf[3].name : true", message="Name of MarkLogikGroup must not be changed"
 	// +kubebuilder:validation:XValidation:rule="size(self) >= 5 && size(oldSelf) >= 5 ? self[4].name == oldSelf[4].name : true", message="Name of MarkLogikGroup must not be changed"
 	// +kubebuilder:validation:XValidation:rule="size(self.filter(x, x.isBootstrap == true)) == 1", message="Exactly one MarkLogicGroup must have isBootstrap set to true"
+	// +kubebuilder:validation:XValidation:rule="!self.exists(x, has(x.forests) && x.isDynamic == true)", message="forests can not be provisioned on dynamic MarkLogicGroups"
+	// +kubebuilder:validation:XValidation:rule="!self.exists(x, has(x.scaleUp) && !has(x.forests) && (!has(x.scaleUp.databases) || size(x.scaleUp.databases) == 0))", message="scaleUp requires databases when forests is not set"
 	MarkLogicGroups []*MarklogicGroups `json:"markLogicGroups,omitempty"`
 }
 
+// ClusterProfile is a common topology the groups of a cluster are created
+// from.
+type ClusterProfile string
+
+const (
+	// ClusterProfileSingleNode is a single host, for development and tests.
+	ClusterProfileSingleNode ClusterProfile = "single-node"
+	// ClusterProfileThreeNodeHA is three hosts in one group, the smallest
+	// cluster whose forests can fail over.
+	ClusterProfileThreeNodeHA ClusterProfile = "three-node-ha"
+	// ClusterProfileENodeDNode is three data hosts and two evaluator hosts
+	// with the dnode and enode group profiles.
+	ClusterProfileENodeDNode ClusterProfile = "enode-dnode"
+)
+
+// GroupProfile is a preset of defaults for a role in a two-tier topology.
+type GroupProfile string
+
+const (
+	// GroupProfileENode is for evaluator hosts, which run queries but hold no forests.
+	GroupProfileENode GroupProfile = "enode"
+	// GroupProfileDNode is for data hosts, which hold the forests.
+	GroupProfileDNode GroupProfile = "dnode"
+)
+
 // +kubebuilder:validation:XValidation:rule="!has(self.dynamic) || self.isDynamic == true", message="dynamic can only be set when isDynamic is true"
 // +kubebuilder:validation:XValidation:rule="!(self.isDynamic == true && self.isBootstrap == true)", message="isDynamic cannot be set when isBootstrap is true"
 // +kubebuilder:validation:XValidation:rule="!self.isDynamic || !has(self.image) || size(self.image) == 0 || self.image.matches('^.+:(latest.*|((1[2-9]|[2-9][0-9])[.][0-9]+[.][0-9]+.*))$')", message="dynamic host group image override must use tag latest or MarkLogic major version 12+"
+// +kubebuilder:validation:XValidation:rule="!has(self.autoscaling) || !has(self.autoscaling.loadMetrics) || !self.autoscaling.loadMetrics.enabled || (self.isBootstrap != true && !has(self.forests))", message="autoscaling.loadMetrics is only allowed on groups without forests"
+// +kubebuilder:validation:XValidation:rule="!has(self.autoscaling) || !has(self.autoscaling.keda) || self.isDynamic == true", message="autoscaling.keda is only allowed on dynamic groups"
+// +kubebuilder:validation:XValidation:rule="!has(self.allowDisruptibleNodes) || !self.allowDisruptibleNodes || (self.isBootstrap != true && !has(self.forests) && !has(self.scaleUp))", message="allowDisruptibleNodes is only allowed on groups without forests"
 type MarklogicGroups struct {
 	// +kubebuilder:default:=1
 	Replicas *int32 `json:"replicas,omitempty"`
@@ -115,12 +276,76 @@ type MarklogicGroups struct {
 	NodeSelector              map[string]string                 `json:"nodeSelector,omitempty"`
 	PriorityClassName         string                            `json:"priorityClassName,omitempty"`
 	HugePages                 *HugePages                        `json:"hugePages,omitempty"`
-	// +kubebuilder:default:={enabled: true, initialDelaySeconds: 30, timeoutSeconds: 5, periodSeconds: 30, successThreshold: 1, failureThreshold: 3}
+	// ReadOnlyRootFilesystem overrides the cluster setting for this group.
+	// +optional
+	ReadOnlyRootFilesystem *ReadOnlyRootFilesystem `json:"readOnlyRootFilesystem,omitempty"`
+	// +kubebuilder:default:={enabled: true}
 	LivenessProbe ContainerProbe `json:"livenessProbe,omitempty"`
-	// +kubebuilder:default:={enabled: true, initialDelaySeconds: 10, timeoutSeconds: 5, periodSeconds: 30, successThreshold: 1, failureThreshold: 3}
+	// +kubebuilder:default:={enabled: true}
 	ReadinessProbe ContainerProbe `json:"readinessProbe,omitempty"`
 	LogCollection  *LogCollection `json:"logCollection,omitempty"`
 	HAProxy        *HAProxyGroup  `json:"haproxy,omitempty"`
+	// Drain overrides the cluster drain settings for this group.
+	// +optional
+	Drain *ShutdownDrain `json:"drain,omitempty"`
+	// HostJoin overrides the cluster host join settings for this group.
+	// +optional
+	HostJoin *HostJoin `json:"hostJoin,omitempty"`
+	// AutoscalerDisruption overrides the cluster autoscaler disruption for
+	// this group.
+	// +optional
+	AutoscalerDisruption AutoscalerDisruption `json:"autoscalerDisruption,omitempty"`
+	// AllowDisruptibleNodes lets the group run on spot and preemptible nodes.
+	// It is meant for e-node groups, which hold no forests: the pods tolerate
+	// the spot taints of the clouds and stop within the notice of a
+	// preemption, and the PodDisruptionBudget of the group lets half of them
+	// be evicted at once.
+	// +optional
+	AllowDisruptibleNodes bool `json:"allowDisruptibleNodes,omitempty"`
+	// PodRemediation overrides the cluster pod remediation for this group.
+	// +optional
+	PodRemediation *PodRemediation `json:"podRemediation,omitempty"`
+	// StartupProbe overrides the cluster startup probe for this group.
+	// +optional
+	StartupProbe *StartupProbe `json:"startupProbe,omitempty"`
+	// Architecture overrides the cluster architecture for this group.
+	// +kubebuilder:validation:Enum=amd64;arm64
+	// +optional
+	Architecture string `json:"architecture,omitempty"`
+	// PodManagementPolicy overrides the cluster pod management policy for
+	// this group.
+	// +kubebuilder:validation:Enum=Parallel;OrderedReady;BootstrapFirst
+	// +optional
+	PodManagementPolicy PodManagementPolicy `json:"podManagementPolicy,omitempty"`
+	// Profile applies defaults for evaluator (enode) or data (dnode) hosts.
+	// They replace the cluster-wide values; fields set on the group win.
+	// +kubebuilder:validation:Enum=enode;dnode
+	// +optional
+	Profile GroupProfile `json:"profile,omitempty"`
+	// Forests are created on every host of the group as it joins the cluster.
+	// Dynamic hosts cannot hold forests.
+	// +optional
+	Forests *ForestProvisioning `json:"forests,omitempty"`
+	// ScaleUp throttles and monitors rebalancing after the group scales up.
+	// +optional
+	ScaleUp *ScaleUpPolicy `json:"scaleUp,omitempty"`
+	// ZoneAwareness sets the zone of the hosts of the group in MarkLogic to
+	// the zone of their nodes.
+	// +optional
+	ZoneAwareness *ZoneAwareness `json:"zoneAwareness,omitempty"`
+	// Autoscaling configures the autoscalers of the group.
+	// +optional
+	Autoscaling *GroupAutoscaling `json:"autoscaling,omitempty"`
+	// Diagnostics turns on trace events and a file log level on the hosts
+	// of the group, optionally for a limited time.
+	// +optional
+	Diagnostics *Diagnostics `json:"diagnostics,omitempty"`
+	// AppServerSettings are properties of the app servers of the group the
+	// operator keeps set in MarkLogic.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	AppServerSettings []AppServerSettings `json:"appServerSettings,omitempty"`
 	// +kubebuilder:default:=false
 	IsBootstrap bool `json:"isBootstrap,omitempty"`
 	// +kubebuilder:default:=false
@@ -147,11 +372,114 @@ type MarklogicClusterStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// ObservedGeneration is the generation of the spec the Ready,
+	// Reconciling and Stalled conditions were computed for.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Phase summarizes the conditions: Provisioning, Joining, Ready,
+	// Upgrading, Degraded, Stopped or Terminating.
+	// +optional
+	Phase   ClusterPhase   `json:"phase,omitempty"`
+	Backup  *BackupStatus  `json:"backup,omitempty"`
+	Upgrade *UpgradeStatus `json:"upgrade,omitempty"`
+	// ResourceRollout tracks the restarts that apply changed group resources.
+	ResourceRollout *ResourceRolloutStatus `json:"resourceRollout,omitempty"`
+	// HostRestart tracks the last restart of hosts requested with the
+	// marklogic.progress.com/restart-hosts annotation.
+	HostRestart *HostRestartStatus `json:"hostRestart,omitempty"`
+	// WarmUp tracks the groups with a scaleUp policy.
+	// +listType=atomic
+	WarmUp []GroupWarmUpStatus `json:"warmUp,omitempty"`
+	// Run tracks stopping and starting the cluster with spec.stopped.
+	Run *ClusterRunStatus `json:"run,omitempty"`
+	// Hibernation reports the hibernation schedule.
+	Hibernation *HibernationStatus `json:"hibernation,omitempty"`
+	// Capacity reports the requested and used resources of the groups.
+	Capacity *CapacityStatus `json:"capacity,omitempty"`
+	// Hosts rolls up the MarkLogic hosts of the groups.
+	Hosts *ClusterHostsStatus `json:"hosts,omitempty"`
+	// ServerVersion is the oldest MarkLogic version the hosts run, as
+	// reported by the Manage API.
+	// +optional
+	ServerVersion string `json:"serverVersion,omitempty"`
+	// HostZones are the zones the operator set on the hosts of the groups
+	// with zoneAwareness.
+	// +listType=map
+	// +listMapKey=host
+	HostZones []HostZone `json:"hostZones,omitempty"`
+	// HostRecovery lists the pods whose MarkLogic host did not rejoin the
+	// cluster, while the operator recovers them.
+	// +listType=map
+	// +listMapKey=pod
+	HostRecovery []HostRecoveryStatus `json:"hostRecovery,omitempty"`
+	// Quorum reports the hosts the cluster sees online, see the QuorumLost
+	// condition.
+	Quorum *QuorumStatus `json:"quorum,omitempty"`
+	// Diagnostics are the diagnostics the operator applied to the groups.
+	// +listType=map
+	// +listMapKey=group
+	Diagnostics []DiagnosticsStatus `json:"diagnostics,omitempty"`
+	// AppServerSettings are the app server settings the operator applied to
+	// the groups.
+	// +listType=map
+	// +listMapKey=group
+	// +listMapKey=name
+	AppServerSettings []AppServerSettingsStatus `json:"appServerSettings,omitempty"`
+	// FIPS reports the compliance of a cluster with spec.fipsMode.
+	FIPS *FIPSStatus `json:"fips,omitempty"`
+	// ChangeLog records who changed the images, replicas and authentication
+	// of the cluster.
+	ChangeLog *ChangeLogStatus `json:"changeLog,omitempty"`
+	// TieredStorage reports the partitions migrated by spec.tieredStorage.
+	TieredStorage *TieredStorageStatus `json:"tieredStorage,omitempty"`
+	// Replication reports the last check of spec.replication.
+	Replication *ReplicationStatus `json:"replication,omitempty"`
+	// HealthReport reports the last health report of spec.healthReport.
+	HealthReport *HealthReportStatus `json:"healthReport,omitempty"`
+	// NodeDrain reports the pods on cordoned nodes, see spec.nodeDrain.
+	NodeDrain *NodeDrainStatus `json:"nodeDrain,omitempty"`
+	// Bootstrap reports the data import of spec.bootstrap.
+	Bootstrap *BootstrapStatus `json:"bootstrap,omitempty"`
+	// Modules reports the modules loaded for spec.modules.
+	Modules *ModulesStatus `json:"modules,omitempty"`
+	// Export reports the last export requested with the
+	// marklogic.progress.com/export annotation.
+	Export *ExportStatus `json:"export,omitempty"`
+	// SupportBundle reports the last support bundle requested with the
+	// marklogic.progress.com/support-bundle annotation.
+	SupportBundle *SupportBundleStatus `json:"supportBundle,omitempty"`
+}
+
+func (status *MarklogicClusterStatus) SetCondition(condition metav1.Condition) {
+	for i := range status.Conditions {
+		if status.Conditions[i].Type == condition.Type {
+			status.Conditions[i] = condition
+			return
+		}
+	}
+	status.Conditions = append(status.Conditions, condition)
+}
+
+func (status *MarklogicClusterStatus) GetConditionStatus(conditionType string) metav1.ConditionStatus {
+	for _, condition := range status.Conditions {
+		if condition.Type == conditionType {
+			return condition.Status
+		}
+	}
+	return metav1.ConditionUnknown
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:metadata:annotations="helm.sh/resource-policy=keep"
 //+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Image",type=string,JSONPath=`.spec.image`
+//+kubebuilder:printcolumn:name="Upgrade",type=string,JSONPath=`.status.upgrade.state`
+//+kubebuilder:printcolumn:name="Upgrade Message",type=string,JSONPath=`.status.upgrade.message`,priority=1
+//+kubebuilder:printcolumn:name="State",type=string,JSONPath=`.status.run.state`
+//+kubebuilder:printcolumn:name="Version",type=string,JSONPath=`.status.serverVersion`,priority=1
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // MarklogicCluster is the Schema for the marklogicclusters API
 type MarklogicCluster struct {
@@ -177,10 +505,82 @@ func init() {
 
 // Observed State for MarkLogic Cluster
 const (
+	// ClusterReady is True while the cluster runs as specified: every group
+	// is ready and no workflow is in progress or failed. Together with
+	// ClusterReconciling and ClusterStalled it follows the conventions the
+	// health checks of GitOps tools read.
 	ClusterReady        MarkLogicConditionType = "Ready"
 	ClusterInitialized  MarkLogicConditionType = "Initialized"
 	ClusterScalingUp    MarkLogicConditionType = "Stopped"
 	ClusterScalingDown  MarkLogicConditionType = "Resuming"
 	ClusterDecommission MarkLogicConditionType = "Decommission"
 	ClusterUpdating     MarkLogicConditionType = "Updating"
+	OperatorUserReady   MarkLogicConditionType = "OperatorUserReady"
+	ForestsProvisioned  MarkLogicConditionType = "ForestsProvisioned"
+	// RolloutLocked is True while a workflow restarts pods, with the
+	// workflow as the reason. No other workflow restarts pods meanwhile.
+	RolloutLocked MarkLogicConditionType = "RolloutLocked"
+	// LogCollectionDegraded is True while the fluent-bit sidecar of a pod
+	// crash-loops or restarted recently.
+	LogCollectionDegraded MarkLogicConditionType = "LogCollectionDegraded"
+	// PreflightFailed is True while the environment of a new cluster cannot
+	// run it, for example a missing storage class. No resources are created
+	// until it is False.
+	PreflightFailed MarkLogicConditionType = "PreflightFailed"
+	// ServerVersionMismatch is True while a host runs another MarkLogic
+	// version than the tag of the image of its pod, for example after a
+	// patch was installed in place.
+	ServerVersionMismatch MarkLogicConditionType = "ServerVersionMismatch"
+	// ClusterReconciling is True while the cluster makes progress towards
+	// its spec, such as pods starting or an upgrade rolling out.
+	ClusterReconciling MarkLogicConditionType = "Reconciling"
+	// ClusterStalled is True while the cluster cannot make progress without
+	// intervention, such as after a failed upgrade.
+	ClusterStalled MarkLogicConditionType = "Stalled"
+	// UpgradePendingApproval is True while an upgrade waits for its
+	// approval, see status.upgrade.pendingApproval.
+	UpgradePendingApproval MarkLogicConditionType = "UpgradePendingApproval"
+	// UpgradeChangesHeld is True while an active upgrade holds back changes
+	// of the replicas, storage or images of groups and new groups, until
+	// the upgrade ends or the
+	// marklogic.progress.com/force-change-during-upgrade annotation is set.
+	UpgradeChangesHeld MarkLogicConditionType = "UpgradeChangesHeld"
+	// PodsStuck is True while pods crash-loop or are not ready for longer
+	// than the threshold of spec.podRemediation.
+	PodsStuck MarkLogicConditionType = "PodsStuck"
+	// QuorumLost is True once the cluster lost its quorum, and stays True
+	// until the loss is acknowledged with the
+	// marklogic.progress.com/acknowledge-quorum-loss annotation. The operator
+	// restarts and upgrades no pods while it is True.
+	QuorumLost MarkLogicConditionType = "QuorumLost"
+	// ReplicationDegraded is True while a foreign cluster of spec.replication
+	// is not connected or a database lags behind its foreign replicas.
+	ReplicationDegraded MarkLogicConditionType = "ReplicationDegraded"
+)
+
+// ClusterPhase is a coarse summary of the lifecycle of a cluster, computed
+// from the Ready, Reconciling and Stalled conditions.
+// +kubebuilder:validation:Enum=Provisioning;Joining;Ready;Upgrading;Degraded;Stopped;Terminating
+type ClusterPhase string
+
+const (
+	// ClusterPhaseProvisioning is the phase of a new cluster until its
+	// bootstrap group is ready.
+	ClusterPhaseProvisioning ClusterPhase = "Provisioning"
+	// ClusterPhaseJoining is the phase while hosts start and join the
+	// cluster, after it was provisioned.
+	ClusterPhaseJoining ClusterPhase = "Joining"
+	// ClusterPhaseReady is the phase of a cluster that runs as specified.
+	ClusterPhaseReady ClusterPhase = "Ready"
+	// ClusterPhaseUpgrading is the phase while an upgrade, a resource
+	// rollout or a host restart is in progress or waits for its approval.
+	ClusterPhaseUpgrading ClusterPhase = "Upgrading"
+	// ClusterPhaseDegraded is the phase of a cluster that needs
+	// intervention, as told by the Stalled condition.
+	ClusterPhaseDegraded ClusterPhase = "Degraded"
+	// ClusterPhaseStopped is the phase of a cluster stopped with
+	// spec.stopped or hibernated.
+	ClusterPhaseStopped ClusterPhase = "Stopped"
+	// ClusterPhaseTerminating is the phase of a cluster being deleted.
+	ClusterPhaseTerminating ClusterPhase = "Terminating"
 )
@@ -0,0 +1,140 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CloneMethod is how the data of the source cluster gets into the clone.
+// +kubebuilder:validation:Enum=Backup;VolumeSnapshot
+type CloneMethod string
+
+const (
+	// CloneMethodBackup initializes a new cluster and restores the
+	// databases from the backups of the source cluster.
+	CloneMethodBackup CloneMethod = "Backup"
+	// CloneMethodVolumeSnapshot snapshots the data volumes of the source
+	// cluster and provisions the data volumes of the clone from them.
+	CloneMethodVolumeSnapshot CloneMethod = "VolumeSnapshot"
+)
+
+// ClonePhase is the progress of a MarklogicClusterClone.
+type ClonePhase string
+
+const (
+	ClonePhaseSnapshotting ClonePhase = "Snapshotting"
+	ClonePhaseProvisioning ClonePhase = "Provisioning"
+	ClonePhaseRestoring    ClonePhase = "Restoring"
+	ClonePhaseReady        ClonePhase = "Ready"
+	ClonePhaseFailed       ClonePhase = "Failed"
+)
+
+// CloneGroup overrides the replicas of a group of the source cluster in the
+// clone.
+type CloneGroup struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// +kubebuilder:validation:Minimum=1
+	Replicas int32 `json:"replicas"`
+}
+
+// HostRename rewrites the host names of a group in the MarkLogic
+// configuration of data volumes cloned from another cluster.
+type HostRename struct {
+	// From is the group of the source cluster.
+	From string `json:"from"`
+	// To is the group that took its place.
+	To string `json:"to"`
+}
+
+// MarklogicClusterCloneSpec defines the desired state of MarklogicClusterClone
+// +kubebuilder:validation:XValidation:rule="self == oldSelf", message="the spec of a clone can not be changed, create another clone instead"
+// +kubebuilder:validation:XValidation:rule="!has(self.method) || self.method != 'Backup' || (has(self.databases) && size(self.databases) > 0)", message="databases are required for Backup clones"
+// +kubebuilder:validation:XValidation:rule="!has(self.method) || self.method != 'VolumeSnapshot' || !has(self.groups)", message="the replicas of VolumeSnapshot clones can not be changed"
+type MarklogicClusterCloneSpec struct {
+	// SourceCluster is the MarklogicCluster to clone, in the namespace of
+	// the clone.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	SourceCluster string `json:"sourceCluster"`
+	// ClusterName is the name of the MarklogicCluster created for the
+	// clone. Its groups are named <clusterName>-<group>. Defaults to the
+	// name of the clone.
+	// +kubebuilder:validation:MaxLength=40
+	// +optional
+	ClusterName string `json:"clusterName,omitempty"`
+	// +kubebuilder:default:=Backup
+	Method CloneMethod `json:"method,omitempty"`
+	// Databases are restored, in order, by Backup clones from the backup
+	// directory of their schedule on the source cluster or
+	// <storage directory>/<database>/.
+	// +listType=atomic
+	// +optional
+	Databases []string `json:"databases,omitempty"`
+	// VolumeSnapshotClassName is the class of the snapshots of VolumeSnapshot
+	// clones. The default class of the CSI driver is used when empty.
+	// +optional
+	VolumeSnapshotClassName string `json:"volumeSnapshotClassName,omitempty"`
+	// Groups reduce the replicas of groups of Backup clones. The other
+	// groups keep the replicas of the source cluster.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Groups []CloneGroup `json:"groups,omitempty"`
+	// KeepCredentials copies the admin credentials of the source cluster.
+	// By default the clone gets a new admin password, so the credentials of
+	// the source cluster never reach the clone.
+	// +kubebuilder:default:=false
+	KeepCredentials bool `json:"keepCredentials,omitempty"`
+}
+
+// MarklogicClusterCloneStatus defines the observed state of MarklogicClusterClone
+type MarklogicClusterCloneStatus struct {
+	Phase ClonePhase `json:"phase,omitempty"`
+	// ClusterName is the MarklogicCluster created for the clone.
+	ClusterName string `json:"clusterName,omitempty"`
+	// Snapshots are the VolumeSnapshots taken of the source cluster.
+	// +listType=atomic
+	Snapshots []string `json:"snapshots,omitempty"`
+	// RestoredDatabases are the databases restored into the clone so far.
+	// +listType=atomic
+	RestoredDatabases []string     `json:"restoredDatabases,omitempty"`
+	Message           string       `json:"message,omitempty"`
+	StartTime         *metav1.Time `json:"startTime,omitempty"`
+	CompletionTime    *metav1.Time `json:"completionTime,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:metadata:annotations="helm.sh/resource-policy=keep"
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Source",type=string,JSONPath=`.spec.sourceCluster`
+//+kubebuilder:printcolumn:name="Method",type=string,JSONPath=`.spec.method`
+//+kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+//+kubebuilder:printcolumn:name="Message",type=string,JSONPath=`.status.message`,priority=1
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// MarklogicClusterClone creates a MarklogicCluster from the backups or the
+// data volumes of another one, for example a staging environment from
+// production. Deleting the clone deletes the cluster it created.
+type MarklogicClusterClone struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MarklogicClusterCloneSpec   `json:"spec,omitempty"`
+	Status MarklogicClusterCloneStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// MarklogicClusterCloneList contains a list of MarklogicClusterClone
+type MarklogicClusterCloneList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MarklogicClusterClone `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MarklogicClusterClone{}, &MarklogicClusterCloneList{})
+}
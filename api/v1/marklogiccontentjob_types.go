@@ -0,0 +1,162 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ContentJobOperation is the MLCP command a MarklogicContentJob runs.
+// +kubebuilder:validation:Enum=Import;Export;Copy
+type ContentJobOperation string
+
+const (
+	// ContentJobImport loads the files under spec.inputPath into the
+	// database.
+	ContentJobImport ContentJobOperation = "Import"
+	// ContentJobExport writes the documents of the database under
+	// spec.outputPath.
+	ContentJobExport ContentJobOperation = "Export"
+	// ContentJobCopy copies the documents of the database into the database
+	// of spec.target.
+	ContentJobCopy ContentJobOperation = "Copy"
+)
+
+// ContentJobPhase is the progress of a MarklogicContentJob.
+type ContentJobPhase string
+
+const (
+	ContentJobPending   ContentJobPhase = "Pending"
+	ContentJobRunning   ContentJobPhase = "Running"
+	ContentJobSucceeded ContentJobPhase = "Succeeded"
+	ContentJobFailed    ContentJobPhase = "Failed"
+)
+
+// ContentJobTarget is the database a Copy writes to.
+type ContentJobTarget struct {
+	// Cluster is the MarklogicCluster to copy to, in the namespace of the
+	// job. Defaults to spec.cluster.
+	// +optional
+	Cluster string `json:"cluster,omitempty"`
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Database string `json:"database"`
+}
+
+// ContentJobRetry is how often and how long the MLCP run is retried.
+type ContentJobRetry struct {
+	// BackoffLimit is the number of retries of the run before the job
+	// fails. Defaults to 3.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	BackoffLimit *int32 `json:"backoffLimit,omitempty"`
+	// ActiveDeadlineSeconds fails the job, with all its retries, once it
+	// ran that long.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ActiveDeadlineSeconds *int64 `json:"activeDeadlineSeconds,omitempty"`
+}
+
+// MarklogicContentJobSpec defines the desired state of MarklogicContentJob
+// +kubebuilder:validation:XValidation:rule="self == oldSelf", message="the spec of a content job can not be changed, create another job instead"
+// +kubebuilder:validation:XValidation:rule="self.operation != 'Import' || (has(self.inputPath) && size(self.inputPath) > 0)", message="inputPath is required for Import"
+// +kubebuilder:validation:XValidation:rule="self.operation != 'Export' || (has(self.outputPath) && size(self.outputPath) > 0)", message="outputPath is required for Export"
+// +kubebuilder:validation:XValidation:rule="self.operation != 'Copy' || has(self.target)", message="target is required for Copy"
+type MarklogicContentJobSpec struct {
+	// Cluster is the MarklogicCluster the job connects to, in the namespace
+	// of the job. The host, port, TLS and admin credentials of MLCP are
+	// taken from it.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Cluster string `json:"cluster"`
+	// +kubebuilder:validation:Required
+	Operation ContentJobOperation `json:"operation"`
+	// Image has MLCP installed, with mlcp.sh on the PATH.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Image string `json:"image"`
+	// +optional
+	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
+	// Database is imported into, exported or copied from.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Database string `json:"database"`
+	// Port is the app server MLCP connects to.
+	// +kubebuilder:default:=8000
+	// +optional
+	Port int32 `json:"port,omitempty"`
+	// InputPath is the path Import loads the files from.
+	// +optional
+	InputPath string `json:"inputPath,omitempty"`
+	// OutputPath is the directory Export writes the documents to.
+	// +optional
+	OutputPath string `json:"outputPath,omitempty"`
+	// Target is the database Copy writes to.
+	// +optional
+	Target *ContentJobTarget `json:"target,omitempty"`
+	// VolumeClaimName is a PersistentVolumeClaim mounted at /data, for the
+	// files of Import and Export.
+	// +optional
+	VolumeClaimName string `json:"volumeClaimName,omitempty"`
+	// Args are appended to the MLCP command, for example -collection_filter.
+	// +listType=atomic
+	// +optional
+	Args []string `json:"args,omitempty"`
+	// EnvSecretName is a Secret whose keys are set as environment variables
+	// of MLCP, for example the credentials of an object store.
+	// +optional
+	EnvSecretName string `json:"envSecretName,omitempty"`
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+	// +optional
+	Retry *ContentJobRetry `json:"retry,omitempty"`
+}
+
+// MarklogicContentJobStatus defines the observed state of MarklogicContentJob
+type MarklogicContentJobStatus struct {
+	Phase ContentJobPhase `json:"phase,omitempty"`
+	// JobName is the Job running MLCP.
+	JobName string `json:"jobName,omitempty"`
+	// Attempts is the number of MLCP runs started so far.
+	Attempts int32 `json:"attempts,omitempty"`
+	// FailedAttempts is the number of MLCP runs that failed so far.
+	FailedAttempts int32        `json:"failedAttempts,omitempty"`
+	Message        string       `json:"message,omitempty"`
+	StartTime      *metav1.Time `json:"startTime,omitempty"`
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:metadata:annotations="helm.sh/resource-policy=keep"
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Cluster",type=string,JSONPath=`.spec.cluster`
+//+kubebuilder:printcolumn:name="Operation",type=string,JSONPath=`.spec.operation`
+//+kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+//+kubebuilder:printcolumn:name="Attempts",type=integer,JSONPath=`.status.attempts`
+//+kubebuilder:printcolumn:name="Message",type=string,JSONPath=`.status.message`,priority=1
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// MarklogicContentJob runs an MLCP import, export or copy against a
+// MarklogicCluster as a Kubernetes Job. Deleting the content job deletes
+// its Job.
+type MarklogicContentJob struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MarklogicContentJobSpec   `json:"spec,omitempty"`
+	Status MarklogicContentJobStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// MarklogicContentJobList contains a list of MarklogicContentJob
+type MarklogicContentJobList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MarklogicContentJob `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MarklogicContentJob{}, &MarklogicContentJobList{})
+}
@@ -34,6 +34,12 @@ type MarklogicGroupSpec struct {
 	Name        string            `json:"name,omitempty"`
 	Labels      map[string]string `json:"labels,omitempty"`
 	Annotations map[string]string `json:"annotations,omitempty"`
+	// AdditionalLabels and AdditionalAnnotations of the cluster, added to
+	// every resource generated for the group.
+	// +optional
+	AdditionalLabels map[string]string `json:"additionalLabels,omitempty"`
+	// +optional
+	AdditionalAnnotations map[string]string `json:"additionalAnnotations,omitempty"`
 	// +kubebuilder:default:="cluster.local"
 	ClusterDomain string `json:"clusterDomain,omitempty"`
 	// +kubebuilder:default:="progressofficial/marklogic-db:12.0.3-ubi9-rootless-2.2.6"
@@ -49,6 +55,24 @@ type MarklogicGroupSpec struct {
 	Persistence                   *Persistence                 `json:"persistence,omitempty"`
 	Resources                     *corev1.ResourceRequirements `json:"resources,omitempty"`
 	TerminationGracePeriodSeconds *int64                       `json:"terminationGracePeriodSeconds,omitempty"`
+	// Drain drains the host in the preStop hook before MarkLogic stops.
+	// +optional
+	Drain *ShutdownDrain `json:"drain,omitempty"`
+	// HostJoin controls how the hosts join the cluster.
+	// +optional
+	HostJoin *HostJoin `json:"hostJoin,omitempty"`
+	// AutoscalerDisruption annotates the pods for Karpenter and the cluster
+	// autoscaler. It is set from the MarklogicCluster.
+	// +optional
+	AutoscalerDisruption AutoscalerDisruption `json:"autoscalerDisruption,omitempty"`
+	// AllowDisruptibleNodes makes the pods tolerate spot and preemptible
+	// nodes. It is set from the MarklogicCluster.
+	// +optional
+	AllowDisruptibleNodes bool `json:"allowDisruptibleNodes,omitempty"`
+	// StartupProbe gives the hosts time to boot before the liveness probe
+	// applies.
+	// +optional
+	StartupProbe *StartupProbe `json:"startupProbe,omitempty"`
 	// +kubebuilder:validation:Enum=OnDelete;RollingUpdate
 	// +kubebuilder:default:="OnDelete"
 	UpdateStrategy appsv1.StatefulSetUpdateStrategyType `json:"updateStrategy,omitempty"`
@@ -61,13 +85,28 @@ type MarklogicGroupSpec struct {
 	NodeSelector              map[string]string                 `json:"nodeSelector,omitempty"`
 	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
 	PriorityClassName         string                            `json:"priorityClassName,omitempty"`
+	// Architecture schedules the pods on nodes of this CPU architecture
+	// unless the affinity or nodeSelector already select one.
+	// +kubebuilder:validation:Enum=amd64;arm64
+	// +optional
+	Architecture string `json:"architecture,omitempty"`
+	// PodManagementPolicy is the order in which the pods start. The
+	// StatefulSet is recreated, keeping its pods, when it changes between
+	// OrderedReady and the parallel policies.
+	// +kubebuilder:validation:Enum=Parallel;OrderedReady;BootstrapFirst
+	// +optional
+	PodManagementPolicy PodManagementPolicy `json:"podManagementPolicy,omitempty"`
 	// +kubebuilder:default:={enabled: false, mountPath: "/dev/hugepages"}
 	HugePages *HugePages `json:"hugePages,omitempty"`
-	// +kubebuilder:default:={enabled: true, initialDelaySeconds: 30, timeoutSeconds: 5, periodSeconds: 30, successThreshold: 1, failureThreshold: 3}
+	// ReadOnlyRootFilesystem runs the MarkLogic and fluent-bit containers with
+	// a read-only root filesystem.
+	// +optional
+	ReadOnlyRootFilesystem *ReadOnlyRootFilesystem `json:"readOnlyRootFilesystem,omitempty"`
+	// +kubebuilder:default:={enabled: true}
 	LivenessProbe ContainerProbe `json:"livenessProbe,omitempty"`
-	// +kubebuilder:default:={enabled: true, initialDelaySeconds: 10, timeoutSeconds: 5, periodSeconds: 30, successThreshold: 1, failureThreshold: 3}
+	// +kubebuilder:default:={enabled: true}
 	ReadinessProbe ContainerProbe `json:"readinessProbe,omitempty"`
-	// +kubebuilder:default:={enabled: false, image: "fluent/fluent-bit:4.1.1", resources: {requests: {cpu: "100m", memory: "200Mi"}, limits: {cpu: "200m", memory: "500Mi"}}, files: {errorLogs: true, accessLogs: true, requestLogs: true}, outputs: "stdout"}
+	// +kubebuilder:default:={enabled: false, resources: {requests: {cpu: "100m", memory: "200Mi"}, limits: {cpu: "200m", memory: "500Mi"}}, files: {errorLogs: true, accessLogs: true, requestLogs: true}, outputs: "stdout"}
 	LogCollection *LogCollection `json:"logCollection,omitempty"`
 	// +kubebuilder:default:={name: "Default", enableXdqpSsl: true}
 	GroupConfig *GroupConfig `json:"groupConfig,omitempty"`
@@ -85,8 +124,22 @@ type MarklogicGroupSpec struct {
 	AdditionalVolumes              *[]corev1.Volume                `json:"additionalVolumes,omitempty"`
 	AdditionalVolumeMounts         *[]corev1.VolumeMount           `json:"additionalVolumeMounts,omitempty"`
 	AdditionalVolumeClaimTemplates *[]corev1.PersistentVolumeClaim `json:"additionalVolumeClaimTemplates,omitempty"`
+	VeleroHooks                    *VeleroHooks                    `json:"veleroHooks,omitempty"`
 	SecretName                     string                          `json:"secretName,omitempty"`
 	Tls                            *Tls                            `json:"tls,omitempty"`
+	// When false, ports 8000-8002 are left off the group service unless it is a ClusterIP service.
+	// +kubebuilder:default:=false
+	ExposeAdmin bool `json:"exposeAdmin,omitempty"`
+	// RestrictedPodSecurity generates pods that meet the restricted Pod
+	// Security Standard. It is set from the MarklogicCluster.
+	// +optional
+	RestrictedPodSecurity bool `json:"restrictedPodSecurity,omitempty"`
+	// HostRenames rewrites the host names in the MarkLogic configuration of
+	// cloned data volumes before MarkLogic starts. It is set from the
+	// MarklogicCluster.
+	// +listType=atomic
+	// +optional
+	HostRenames []HostRename `json:"hostRenames,omitempty"`
 }
 
 // InternalState defines the observed state of MarklogicGroup
@@ -212,6 +265,105 @@ type MarklogicGroupStatus struct {
 	MarklogicGroupStatus InternalState `json:"markLogicGroupStatus,omitempty"`
 	// +optional
 	Dynamic *DynamicGroupStatus `json:"dynamic,omitempty"`
+	// +optional
+	SecretRotation *SecretRotationStatus `json:"secretRotation,omitempty"`
+	// Replicas and Selector back the scale subresource, through which
+	// autoscalers such as KEDA scale the group.
+	// +optional
+	Replicas int32 `json:"replicas,omitempty"`
+	// +optional
+	Selector string `json:"selector,omitempty"`
+	// Hosts reports the MarkLogic host of every pod of the group, as read
+	// by the cluster controller from the Manage API.
+	// +listType=map
+	// +listMapKey=pod
+	// +optional
+	Hosts []HostStatus `json:"hosts,omitempty"`
+	// +optional
+	HostsUpdateTime *metav1.Time `json:"hostsUpdateTime,omitempty"`
+	// VolumeBindings lists the volumes of the group that are only reachable
+	// from one node, such as local PersistentVolumes. Their pods cannot be
+	// rescheduled to another node.
+	// +listType=map
+	// +listMapKey=persistentVolumeClaim
+	// +optional
+	VolumeBindings []VolumeNodeBinding `json:"volumeBindings,omitempty"`
+	// +optional
+	StorageMigration *StorageMigrationStatus `json:"storageMigration,omitempty"`
+}
+
+// StorageMigrationPhase is the phase of the migration of the data volumes of
+// a group to another storage class.
+type StorageMigrationPhase string
+
+const (
+	StorageMigrationPhasePending   StorageMigrationPhase = "Pending"
+	StorageMigrationPhaseRunning   StorageMigrationPhase = "Running"
+	StorageMigrationPhaseCompleted StorageMigrationPhase = "Completed"
+	StorageMigrationPhaseFailed    StorageMigrationPhase = "Failed"
+)
+
+// StorageMigrationStep is the step of the migration of the pod in progress.
+type StorageMigrationStep string
+
+const (
+	StorageMigrationStepStopping StorageMigrationStep = "Stopping"
+	StorageMigrationStepCopying  StorageMigrationStep = "Copying"
+	StorageMigrationStepSwapping StorageMigrationStep = "Swapping"
+)
+
+// StorageMigrationStatus tracks the migration of the data volumes of a group
+// to the storage class of its spec, one pod at a time.
+type StorageMigrationStatus struct {
+	Phase              StorageMigrationPhase `json:"phase"`
+	SourceStorageClass string                `json:"sourceStorageClass,omitempty"`
+	TargetStorageClass string                `json:"targetStorageClass"`
+	Message            string                `json:"message,omitempty"`
+	// Pod is the pod whose data is being copied.
+	// +optional
+	Pod string `json:"pod,omitempty"`
+	// +optional
+	Step StorageMigrationStep `json:"step,omitempty"`
+	// NewVolume is the PersistentVolume the data of the pod was copied to.
+	// +optional
+	NewVolume string `json:"newVolume,omitempty"`
+	// MigratedPods lists the pods that run on the new volumes.
+	// +optional
+	MigratedPods []string `json:"migratedPods,omitempty"`
+	// RetainedVolumes lists the PersistentVolumes of the previous storage
+	// class. They are kept with the Retain reclaim policy until deleted by
+	// hand.
+	// +optional
+	RetainedVolumes []string `json:"retainedVolumes,omitempty"`
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// SecretRotationStatus tracks the changes of the Secrets referenced by the
+// group that are still being applied to its pods.
+type SecretRotationStatus struct {
+	// TLSChecksum is the checksum of the TLS Secrets the pods are restarted for.
+	TLSChecksum string `json:"tlsChecksum,omitempty"`
+	// TLSRestartPending lists the pods still running with the previous certificates.
+	TLSRestartPending []string `json:"tlsRestartPending,omitempty"`
+	// LogCredentialsChecksum is the checksum of the fluent-bit credentials Secret.
+	LogCredentialsChecksum string `json:"logCredentialsChecksum,omitempty"`
+	// LogCredentialsUpdateTime is when the fluent-bit credentials were last rendered.
+	LogCredentialsUpdateTime *metav1.Time `json:"logCredentialsUpdateTime,omitempty"`
+	// LogReloadPending lists the pods whose fluent-bit has not reloaded the credentials yet.
+	LogReloadPending []string `json:"logReloadPending,omitempty"`
+}
+
+// VolumeNodeBinding is a PersistentVolumeClaim of the group bound to a
+// volume whose node affinity pins it to a single node.
+type VolumeNodeBinding struct {
+	PersistentVolumeClaim string `json:"persistentVolumeClaim"`
+	Pod                   string `json:"pod"`
+	PersistentVolume      string `json:"persistentVolume"`
+	// Node is the value of the kubernetes.io/hostname label of the node.
+	Node string `json:"node"`
 }
 
 type DynamicGroupStatus struct {
@@ -283,6 +435,7 @@ type License struct {
 //+kubebuilder:object:root=true
 //+kubebuilder:metadata:annotations="helm.sh/resource-policy=keep"
 //+kubebuilder:subresource:status
+//+kubebuilder:subresource:scale:specpath=.spec.replicas,statuspath=.status.replicas,selectorpath=.status.selector
 
 // MarklogicGroup is the Schema for the marklogicgroup API
 type MarklogicGroup struct {
@@ -316,6 +469,12 @@ const (
 	ServerResuming     MarkLogicConditionType = "Resuming"
 	ServerDecommission MarkLogicConditionType = "Decommission"
 	ServerUpdating     MarkLogicConditionType = "Updating"
+	// VolumeTopologyConflict is True while a pod cannot run on the node its
+	// local volume is bound to.
+	VolumeTopologyConflict MarkLogicConditionType = "VolumeTopologyConflict"
+	// ScaleUpBlocked is True while the ResourceQuotas or LimitRanges of the
+	// namespace would reject the pods a scale-up of the group adds.
+	ScaleUpBlocked MarkLogicConditionType = "ScaleUpBlocked"
 )
 
 // Internal State for MarkLogic Server
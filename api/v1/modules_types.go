@@ -0,0 +1,107 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Modules declares a modules database and the application modules the
+// operator loads into it through the REST API whenever their content
+// changes, so an application is deployed with the cluster.
+// +kubebuilder:validation:XValidation:rule="has(self.configMap) != has(self.oci)",message="exactly one of configMap or oci must be set"
+type Modules struct {
+	// Database is the modules database. The operator creates it with a
+	// forest on the bootstrap host when it does not exist.
+	// +kubebuilder:validation:MinLength=1
+	Database string `json:"database"`
+	// AppServers use the modules database, their modules-database is set
+	// once the database exists.
+	// +optional
+	AppServers []ModulesAppServer `json:"appServers,omitempty"`
+	// ConfigMap holds the modules, one per key. The key is the name of the
+	// module below Root. The format of a module follows the extension of
+	// its key.
+	// +optional
+	ConfigMap string `json:"configMap,omitempty"`
+	// OCI is an OCI artifact holding the modules, pulled by the Job that
+	// loads them.
+	// +optional
+	OCI *ModulesOCIArtifact `json:"oci,omitempty"`
+	// Root is the URI prefix of the modules in the database. It starts and
+	// ends with /.
+	// +kubebuilder:default:="/"
+	// +kubebuilder:validation:Pattern=`^/(.*/)?$`
+	// +optional
+	Root string `json:"root,omitempty"`
+	// Image of the Job that loads the modules. It has sh, find and curl.
+	// Defaults to the MarkLogic image of the cluster.
+	// +optional
+	Image string `json:"image,omitempty"`
+	// +optional
+	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
+	// BackoffLimit is how often a failed load is retried. Defaults to 3.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	BackoffLimit *int32 `json:"backoffLimit,omitempty"`
+}
+
+// ModulesAppServer is an app server of a MarkLogic group.
+type ModulesAppServer struct {
+	// Group is the MarkLogic group of the app server.
+	// +kubebuilder:default:=Default
+	// +optional
+	Group string `json:"group,omitempty"`
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// ModulesOCIArtifact is an OCI artifact of modules. Its files are loaded
+// with their paths in the artifact below Root.
+type ModulesOCIArtifact struct {
+	// Reference of the artifact, such as
+	// registry.example.com/orders/modules@sha256:... The modules are loaded
+	// again when the reference changes, so pin a digest or a unique tag.
+	// +kubebuilder:validation:MinLength=1
+	Reference string `json:"reference"`
+	// Image with the oras CLI that pulls the artifact.
+	// +kubebuilder:default:="ghcr.io/oras-project/oras:v1.2.0"
+	// +optional
+	Image string `json:"image,omitempty"`
+	// PullSecretName is a Secret of type kubernetes.io/dockerconfigjson
+	// with the credentials of the registry.
+	// +optional
+	PullSecretName string `json:"pullSecretName,omitempty"`
+}
+
+// ModulesPhase is where the loading of the modules stands.
+// +kubebuilder:validation:Enum=Pending;Loading;Loaded;Failed
+type ModulesPhase string
+
+const (
+	// ModulesPending waits for the groups of the cluster to be ready.
+	ModulesPending ModulesPhase = "Pending"
+	// ModulesLoading runs the Job that loads the modules.
+	ModulesLoading ModulesPhase = "Loading"
+	// ModulesLoaded loaded the modules of the content hash.
+	ModulesLoaded ModulesPhase = "Loaded"
+	// ModulesFailed ran out of retries; the modules are not loaded again
+	// until their content changes.
+	ModulesFailed ModulesPhase = "Failed"
+)
+
+// ModulesStatus reports the modules of the cluster.
+type ModulesStatus struct {
+	// Phase is Pending, Loading, Loaded or Failed.
+	Phase ModulesPhase `json:"phase"`
+	// ContentHash identifies the spec and the content of the modules. A
+	// change loads the modules again.
+	ContentHash string `json:"contentHash,omitempty"`
+	// JobName is the Job that loads the modules.
+	JobName  string       `json:"jobName,omitempty"`
+	LoadedAt *metav1.Time `json:"loadedAt,omitempty"`
+	// Message reports what the load waits for or why it failed.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
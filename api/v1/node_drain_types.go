@@ -0,0 +1,86 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NodeDrain coordinates the drain of nodes with the failover of MarkLogic.
+// The operator keeps a PodDisruptionBudget for every group, so evictions take
+// down one pod of a group at a time. Once a node the pods of the cluster run
+// on is cordoned, the operator fails over the forests those pods hold to their
+// replicas, and the budgets allow no eviction until the failover completed.
+type NodeDrain struct {
+	// Enabled creates the PodDisruptionBudgets of the groups and coordinates
+	// the drain of cordoned nodes.
+	Enabled bool `json:"enabled"`
+	// FailoverTimeout is how long the evictions of a pod wait for its forests
+	// to fail over before they are allowed anyway. Defaults to 10m.
+	// +optional
+	FailoverTimeout *metav1.Duration `json:"failoverTimeout,omitempty"`
+}
+
+// AutoscalerDisruption tells node autoscalers, Karpenter and the Kubernetes
+// cluster autoscaler, whether they may evict the MarkLogic pods to
+// consolidate or scale down their nodes.
+// +kubebuilder:validation:Enum=Never;Coordinated
+type AutoscalerDisruption string
+
+const (
+	// AutoscalerDisruptionNever annotates the pods with
+	// karpenter.sh/do-not-disrupt "true" and
+	// cluster-autoscaler.kubernetes.io/safe-to-evict "false", so autoscalers
+	// leave their nodes alone.
+	AutoscalerDisruptionNever AutoscalerDisruption = "Never"
+	// AutoscalerDisruptionCoordinated annotates the pods with
+	// cluster-autoscaler.kubernetes.io/safe-to-evict "true" and coordinates
+	// the node drain, so autoscalers evict the pods once their forests
+	// failed over.
+	AutoscalerDisruptionCoordinated AutoscalerDisruption = "Coordinated"
+)
+
+// NodeDrainState is where the drain of a pod stands.
+// +kubebuilder:validation:Enum=FailingOver;Ready;TimedOut
+type NodeDrainState string
+
+const (
+	// NodeDrainFailingOver holds the evictions of the pod while its forests
+	// fail over.
+	NodeDrainFailingOver NodeDrainState = "FailingOver"
+	// NodeDrainReady allows the evictions of the pod, its forests failed
+	// over or have no replica to fail over to.
+	NodeDrainReady NodeDrainState = "Ready"
+	// NodeDrainTimedOut allows the evictions of the pod although its forests
+	// did not fail over within the failover timeout.
+	NodeDrainTimedOut NodeDrainState = "TimedOut"
+)
+
+// DrainingPodStatus is a pod on a node that is cordoned or that an
+// autoscaler disrupts.
+type DrainingPodStatus struct {
+	Pod   string `json:"pod"`
+	Group string `json:"group"`
+	Node  string `json:"node"`
+	// State is FailingOver, Ready or TimedOut.
+	State NodeDrainState `json:"state"`
+	// StartTime is when the cordon of the node was first seen.
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// Forests are the forests the pod holds that have not failed over yet.
+	// +optional
+	// +listType=set
+	Forests []string `json:"forests,omitempty"`
+	// UnprotectedForests are the forests of the pod without a replica, which
+	// are unavailable while the pod is evicted.
+	// +optional
+	// +listType=set
+	UnprotectedForests []string `json:"unprotectedForests,omitempty"`
+}
+
+// NodeDrainStatus reports the pods of the cluster on nodes that are cordoned
+// or that an autoscaler disrupts.
+type NodeDrainStatus struct {
+	// +listType=map
+	// +listMapKey=pod
+	Pods []DrainingPodStatus `json:"pods,omitempty"`
+}
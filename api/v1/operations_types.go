@@ -0,0 +1,50 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package v1
+
+// Operations requests the operations of the control annotations of a
+// MarklogicCluster from its spec, so GitOps tools reconcile them like the
+// rest of the spec instead of reverting annotations they do not manage. Each
+// field works like the annotation of the same name, which takes precedence
+// when it is set. The operator only reports the progress of an operation in
+// the status and never writes these fields or the annotations back, so the
+// same value can stay in Git without repeating the operation.
+type Operations struct {
+	// ApproveUpgrade approves the upgrade to this image, like
+	// marklogic.progress.com/approve-upgrade.
+	// +optional
+	ApproveUpgrade string `json:"approveUpgrade,omitempty"`
+	// PauseUpgrade holds the rollout of an upgrade before it restarts the
+	// next pod, like marklogic.progress.com/pause-upgrade.
+	// +optional
+	PauseUpgrade bool `json:"pauseUpgrade,omitempty"`
+	// RetryUpgrade retries a failed upgrade once for every new request ID,
+	// like marklogic.progress.com/retry-upgrade.
+	// +optional
+	RetryUpgrade string `json:"retryUpgrade,omitempty"`
+	// RestartHosts lists the pods to restart, separated by commas, like
+	// marklogic.progress.com/restart-hosts.
+	// +optional
+	RestartHosts string `json:"restartHosts,omitempty"`
+	// SupportBundle requests a support bundle with this request ID, like
+	// marklogic.progress.com/support-bundle.
+	// +optional
+	SupportBundle string `json:"supportBundle,omitempty"`
+	// Export requests an export with this request ID, like
+	// marklogic.progress.com/export.
+	// +optional
+	Export string `json:"export,omitempty"`
+	// ForceChangeDuringUpgrade applies changes of the groups while an
+	// upgrade is active, like
+	// marklogic.progress.com/force-change-during-upgrade.
+	// +optional
+	ForceChangeDuringUpgrade bool `json:"forceChangeDuringUpgrade,omitempty"`
+	// SkipPreflight creates the cluster without its preflight checks, like
+	// marklogic.progress.com/skip-preflight.
+	// +optional
+	SkipPreflight bool `json:"skipPreflight,omitempty"`
+	// AcknowledgeQuorumLoss acknowledges a quorum loss once for every new
+	// request ID, like marklogic.progress.com/acknowledge-quorum-loss.
+	// +optional
+	AcknowledgeQuorumLoss string `json:"acknowledgeQuorumLoss,omitempty"`
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ReplicationCheck validates the database replication of the cluster to its
+// replica clusters for disaster recovery. The operator does not set up the
+// replication; it checks that the foreign clusters the cluster is coupled
+// with are connected and that the databases do not lag behind their foreign
+// replicas.
+type ReplicationCheck struct {
+	// Databases are the master databases whose replication lag is checked.
+	// +kubebuilder:validation:MinItems=1
+	// +listType=set
+	Databases []string `json:"databases"`
+	// MaxLag is how far a database may lag behind its foreign replicas
+	// before the replication is degraded. Defaults to 60s.
+	// +optional
+	MaxLag *metav1.Duration `json:"maxLag,omitempty"`
+	// Interval is how often the replication is checked. Defaults to 1m.
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+}
+
+// ForeignClusterReplicationStatus is a foreign cluster the cluster is coupled
+// with.
+type ForeignClusterReplicationStatus struct {
+	Name string `json:"name"`
+	// ConnectedHosts is how many hosts of the foreign cluster the cluster is
+	// connected to.
+	ConnectedHosts int32 `json:"connectedHosts"`
+	// Hosts is how many hosts of the foreign cluster the cluster knows.
+	Hosts int32 `json:"hosts"`
+}
+
+// DatabaseReplicationStatus is the replication lag of a database.
+type DatabaseReplicationStatus struct {
+	Database string `json:"database"`
+	// Lag is how far the foreign replicas of the database are behind it,
+	// empty when it could not be read.
+	// +optional
+	Lag *metav1.Duration `json:"lag,omitempty"`
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// ReplicationStatus reports the last check of spec.replication.
+type ReplicationStatus struct {
+	// +listType=map
+	// +listMapKey=name
+	ForeignClusters []ForeignClusterReplicationStatus `json:"foreignClusters,omitempty"`
+	// +listType=map
+	// +listMapKey=database
+	Databases     []DatabaseReplicationStatus `json:"databases,omitempty"`
+	LastCheckTime *metav1.Time                `json:"lastCheckTime,omitempty"`
+}
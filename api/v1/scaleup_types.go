@@ -0,0 +1,61 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ScaleUpPolicy controls how the databases of a group rebalance after the
+// group scales up.
+type ScaleUpPolicy struct {
+	// WarmUpWindow is how long the operator monitors rebalancing after the
+	// replicas of the group increase. Defaults to 30m.
+	// +optional
+	WarmUpWindow *metav1.Duration `json:"warmUpWindow,omitempty"`
+	// RebalancerThrottle is set on the databases for the warm-up window, from
+	// 1 (least resources) to 10. The previous throttle is restored when the
+	// window ends. The throttle is left unchanged when unset.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=10
+	// +optional
+	RebalancerThrottle *int32 `json:"rebalancerThrottle,omitempty"`
+	// Databases whose rebalancing is monitored and throttled. Defaults to the
+	// databases of forests.
+	// +optional
+	Databases []string `json:"databases,omitempty"`
+}
+
+// WarmUpPhase is the phase of the warm-up window of a group.
+// +kubebuilder:validation:Enum=WarmingUp;Completed
+type WarmUpPhase string
+
+const (
+	WarmUpPhaseWarmingUp WarmUpPhase = "WarmingUp"
+	WarmUpPhaseCompleted WarmUpPhase = "Completed"
+)
+
+// DatabaseRebalanceStatus is the rebalancing progress of a database.
+type DatabaseRebalanceStatus struct {
+	Database  string `json:"database"`
+	Forests   int32  `json:"forests,omitempty"`
+	Documents int64  `json:"documents,omitempty"`
+	// BalancePercent is the document count of the emptiest forest relative
+	// to the average forest, 100 once the documents are spread evenly.
+	BalancePercent int32 `json:"balancePercent,omitempty"`
+	// PreviousThrottle is the rebalancer throttle restored after the window.
+	PreviousThrottle int32 `json:"previousThrottle,omitempty"`
+}
+
+// GroupWarmUpStatus tracks the warm-up window of a group after a scale-up.
+type GroupWarmUpStatus struct {
+	Group string `json:"group"`
+	// Replicas is the replica count the last scale-up was detected against.
+	Replicas       int32        `json:"replicas"`
+	Phase          WarmUpPhase  `json:"phase,omitempty"`
+	StartTime      *metav1.Time `json:"startTime,omitempty"`
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// +listType=atomic
+	Databases []DatabaseRebalanceStatus `json:"databases,omitempty"`
+	Message   string                    `json:"message,omitempty"`
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClusterRunState is the state of a cluster that is stopped with spec.stopped
+// and started again.
+// +kubebuilder:validation:Enum=Running;Stopping;Stopped;Hibernated;Starting
+type ClusterRunState string
+
+const (
+	ClusterRunStateRunning  ClusterRunState = "Running"
+	ClusterRunStateStopping ClusterRunState = "Stopping"
+	ClusterRunStateStopped  ClusterRunState = "Stopped"
+	// ClusterRunStateHibernated is a cluster stopped by its hibernation
+	// schedule rather than by spec.stopped.
+	ClusterRunStateHibernated ClusterRunState = "Hibernated"
+	ClusterRunStateStarting   ClusterRunState = "Starting"
+)
+
+// ClusterRunStep is the step of a cluster that is stopping or starting.
+// +kubebuilder:validation:Enum=Quiescing;NonBootstrapGroups;BootstrapGroup
+type ClusterRunStep string
+
+const (
+	// ClusterRunStepQuiescing scales HAProxy to 0 so clients stop sending
+	// requests before the hosts stop.
+	ClusterRunStepQuiescing ClusterRunStep = "Quiescing"
+	// ClusterRunStepNonBootstrapGroups stops or starts every group but the
+	// bootstrap group.
+	ClusterRunStepNonBootstrapGroups ClusterRunStep = "NonBootstrapGroups"
+	// ClusterRunStepBootstrapGroup stops or starts the bootstrap group, last
+	// when stopping and first when starting.
+	ClusterRunStepBootstrapGroup ClusterRunStep = "BootstrapGroup"
+)
+
+// ClusterRunStatus tracks stopping and starting the cluster.
+type ClusterRunStatus struct {
+	State              ClusterRunState `json:"state,omitempty"`
+	Step               ClusterRunStep  `json:"step,omitempty"`
+	Message            string          `json:"message,omitempty"`
+	LastTransitionTime *metav1.Time    `json:"lastTransitionTime,omitempty"`
+}
+
+// Hibernation stops and starts the cluster on a schedule, for example to
+// scale development clusters to zero overnight and on weekends.
+type Hibernation struct {
+	Schedule HibernationSchedule `json:"schedule"`
+	// TimeZone of the schedule as an IANA name, such as Europe/Berlin.
+	// Defaults to UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// HibernationSchedule holds the cron expressions, "minute hour day-of-month
+// month day-of-week", that stop and start the cluster. The cluster is
+// hibernated when the last stop time is later than the last start time.
+type HibernationSchedule struct {
+	// +kubebuilder:validation:MinLength=1
+	Stop string `json:"stop"`
+	// +kubebuilder:validation:MinLength=1
+	Start string `json:"start"`
+}
+
+// HibernationStatus reports the state of the hibernation schedule.
+type HibernationStatus struct {
+	// Hibernated is set while the schedule keeps the cluster stopped.
+	Hibernated    bool         `json:"hibernated,omitempty"`
+	NextStopTime  *metav1.Time `json:"nextStopTime,omitempty"`
+	NextStartTime *metav1.Time `json:"nextStartTime,omitempty"`
+	Message       string       `json:"message,omitempty"`
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SupportBundle is where the support bundles requested with the
+// marklogic.progress.com/support-bundle annotation are stored.
+// +kubebuilder:validation:XValidation:rule="has(self.persistentVolumeClaim) != has(self.uploadURLSecret)", message="exactly one of persistentVolumeClaim and uploadURLSecret must be set"
+type SupportBundle struct {
+	// PersistentVolumeClaim stores the bundles in a claim of the namespace of
+	// the cluster.
+	// +optional
+	PersistentVolumeClaim string `json:"persistentVolumeClaim,omitempty"`
+	// UploadURLSecret holds a pre-signed URL of an object storage bucket the
+	// bundle is uploaded to with an HTTP PUT, such as an S3 or GCS signed URL
+	// or an Azure SAS URL.
+	// +optional
+	UploadURLSecret *corev1.SecretKeySelector `json:"uploadURLSecret,omitempty"`
+	// LogLimitBytes is how much of the end of every MarkLogic error log and of
+	// the operator log is collected.
+	// +kubebuilder:default:=10485760
+	// +kubebuilder:validation:Minimum=1024
+	// +optional
+	LogLimitBytes int64 `json:"logLimitBytes,omitempty"`
+}
+
+type SupportBundlePhase string
+
+const (
+	SupportBundlePending   SupportBundlePhase = "Pending"
+	SupportBundleCompleted SupportBundlePhase = "Completed"
+	SupportBundleFailed    SupportBundlePhase = "Failed"
+)
+
+// SupportBundleStatus reports the last support bundle of the cluster.
+type SupportBundleStatus struct {
+	// RequestID is the value of the marklogic.progress.com/support-bundle
+	// annotation the bundle was collected for.
+	RequestID string             `json:"requestID,omitempty"`
+	Phase     SupportBundlePhase `json:"phase,omitempty"`
+	// Location is the file in the claim, or the URL without its query,
+	// the bundle was stored at.
+	Location       string       `json:"location,omitempty"`
+	SizeBytes      int64        `json:"sizeBytes,omitempty"`
+	CollectionTime *metav1.Time `json:"collectionTime,omitempty"`
+	// Message reports what could not be collected, or why the bundle failed.
+	Message string `json:"message,omitempty"`
+}
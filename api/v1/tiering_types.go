@@ -0,0 +1,50 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TieredStorage migrates the range partitions of databases to other data
+// directories as their data ages, so older data moves to cheaper storage.
+type TieredStorage struct {
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=20
+	// +listType=atomic
+	Policies []PartitionMigrationPolicy `json:"policies"`
+}
+
+// PartitionMigrationPolicy migrates the partitions of a database whose range
+// ended more than olderThan ago. The database must use the range assignment
+// policy on an xs:dateTime or xs:date partition key. When several policies of
+// a database match a partition, the one with the largest olderThan applies.
+type PartitionMigrationPolicy struct {
+	Database string `json:"database"`
+	// OlderThan is compared to the range-upper-bound of the partitions.
+	OlderThan metav1.Duration `json:"olderThan"`
+	// DataDirectory the partitions are migrated to, for example the
+	// /var/opt/MarkLogic/tiers/archive mount of a storage tier. It must exist
+	// on the hosts of the partitions.
+	// +kubebuilder:validation:MinLength=1
+	DataDirectory string `json:"dataDirectory"`
+}
+
+// PartitionTierStatus is a partition the operator migrated.
+type PartitionTierStatus struct {
+	Database      string       `json:"database"`
+	Partition     string       `json:"partition"`
+	DataDirectory string       `json:"dataDirectory"`
+	MigrationTime *metav1.Time `json:"migrationTime,omitempty"`
+}
+
+// TieredStorageStatus reports the partitions migrated by spec.tieredStorage.
+// The partitions are checked every few minutes.
+type TieredStorageStatus struct {
+	// +listType=atomic
+	Partitions []PartitionTierStatus `json:"partitions,omitempty"`
+	// Message reports the databases whose partitions could not be read or
+	// migrated at the last check.
+	Message       string       `json:"message,omitempty"`
+	LastCheckTime *metav1.Time `json:"lastCheckTime,omitempty"`
+}
@@ -0,0 +1,462 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package v1
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// UpgradeState is the state of the upgrade workflow of a MarklogicCluster.
+// +kubebuilder:validation:Enum=Idle;Precheck;WaitingForUserApproval;InProgress;Completed;Failed
+type UpgradeState string
+
+const (
+	UpgradeStateIdle                   UpgradeState = "Idle"
+	UpgradeStatePrecheck               UpgradeState = "Precheck"
+	UpgradeStateWaitingForUserApproval UpgradeState = "WaitingForUserApproval"
+	UpgradeStateInProgress             UpgradeState = "InProgress"
+	UpgradeStateCompleted              UpgradeState = "Completed"
+	UpgradeStateFailed                 UpgradeState = "Failed"
+)
+
+// UpgradeStep is the stage of an upgrade that rolls out the bootstrap group
+// before the other groups, as required for a new MarkLogic major version.
+// +kubebuilder:validation:Enum=BootstrapGroup;SecurityDatabase;RemainingGroups
+type UpgradeStep string
+
+const (
+	// UpgradeStepBootstrapGroup rolls out the bootstrap group only.
+	UpgradeStepBootstrapGroup UpgradeStep = "BootstrapGroup"
+	// UpgradeStepSecurityDatabase waits for the Security database upgrade on the bootstrap host.
+	UpgradeStepSecurityDatabase UpgradeStep = "SecurityDatabase"
+	// UpgradeStepRemainingGroups rolls out the remaining groups.
+	UpgradeStepRemainingGroups UpgradeStep = "RemainingGroups"
+)
+
+// PrecheckStatus is the outcome of a single upgrade precheck.
+// +kubebuilder:validation:Enum=Passed;Warning;Failed;Skipped
+type PrecheckStatus string
+
+const (
+	PrecheckPassed  PrecheckStatus = "Passed"
+	PrecheckWarning PrecheckStatus = "Warning"
+	PrecheckFailed  PrecheckStatus = "Failed"
+	PrecheckSkipped PrecheckStatus = "Skipped"
+)
+
+// UpgradeSpec configures how a change of spec.image is rolled out.
+type UpgradeSpec struct {
+	// Prechecks overrides individual prechecks by name. Prechecks that are not
+	// listed run with their defaults.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Prechecks []PrecheckSpec `json:"prechecks,omitempty"`
+	// HealthGate lists the checks that must pass after each pod restart before
+	// the next pod is restarted. Defaults to a HostOnline and a ForestsMounted
+	// gate. Only enforced for groups with the OnDelete update strategy, where
+	// the operator restarts the pods.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	HealthGate []HealthGate `json:"healthGate,omitempty"`
+	// BackgroundJobs holds back pod restarts while the forests of the pod's
+	// host merge or reindex. Defaults to the Wait policy.
+	// +optional
+	BackgroundJobs *BackgroundJobProtection `json:"backgroundJobs,omitempty"`
+	// RestartBatching has the operator also restart the pods of OnDelete
+	// groups whose pod template changed other than in its image, such as for
+	// TLS or huge pages, and coalesces the changes made within the settle
+	// window into a single rolling restart.
+	// +optional
+	RestartBatching *RestartBatching `json:"restartBatching,omitempty"`
+	// AutoApprove approves upgrades without the approval annotation of the
+	// InteractiveUpgrade feature gate when their prechecks allow it. Failed
+	// prechecks still block the upgrade.
+	// +optional
+	AutoApprove *AutoApprove `json:"autoApprove,omitempty"`
+}
+
+// AutoApprove configures when the operator approves an upgrade itself.
+type AutoApprove struct {
+	// MaxWarnings is how many prechecks may end in Warning for the upgrade
+	// to be approved. Defaults to 0.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxWarnings *int32 `json:"maxWarnings,omitempty"`
+	// MaintenanceWindow limits the approval to a recurring window. Upgrades
+	// are approved at any time without it.
+	// +optional
+	MaintenanceWindow *MaintenanceWindow `json:"maintenanceWindow,omitempty"`
+}
+
+// MaintenanceWindow is a recurring period of time.
+type MaintenanceWindow struct {
+	// Start is the cron expression, "minute hour day-of-month month
+	// day-of-week", of the times the window opens.
+	// +kubebuilder:validation:MinLength=1
+	Start string `json:"start"`
+	// Duration is how long the window stays open.
+	Duration metav1.Duration `json:"duration"`
+	// TimeZone of the schedule as an IANA name, such as Europe/Berlin.
+	// Defaults to UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// RestartBatching configures the restarts that apply configuration changes.
+type RestartBatching struct {
+	// SettleWindow is how long no further change of a pod template must
+	// arrive before the pods are restarted, so changes applied one after the
+	// other restart each pod once. Defaults to 1m.
+	// +optional
+	SettleWindow *metav1.Duration `json:"settleWindow,omitempty"`
+}
+
+// BackgroundJobPolicy decides how a pod restart treats merges and
+// reindexing in progress on the host of the pod.
+// +kubebuilder:validation:Enum=Wait;RequireForce;Ignore
+type BackgroundJobPolicy string
+
+const (
+	// BackgroundJobWait holds the restart back until the jobs finish or the
+	// timeout expires.
+	BackgroundJobWait BackgroundJobPolicy = "Wait"
+	// BackgroundJobRequireForce holds the restart back until the jobs finish
+	// or the pod is annotated with marklogic.progress.com/force-restart=true.
+	BackgroundJobRequireForce BackgroundJobPolicy = "RequireForce"
+	// BackgroundJobIgnore restarts pods regardless of background jobs.
+	BackgroundJobIgnore BackgroundJobPolicy = "Ignore"
+)
+
+// BackgroundJobProtection configures the check for merges and reindexing
+// before the operator restarts a pod for an upgrade or a resource change.
+type BackgroundJobProtection struct {
+	// +kubebuilder:default=Wait
+	// +optional
+	Policy BackgroundJobPolicy `json:"policy,omitempty"`
+	// Timeout is how long the Wait policy holds a restart back. Defaults to 1h.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// HealthGateType is the kind of check performed by a health gate.
+// +kubebuilder:validation:Enum=HostOnline;ForestsMounted;AppServer;HTTP
+type HealthGateType string
+
+const (
+	// HealthGateHostOnline waits for the MarkLogic host of the pod to be online.
+	HealthGateHostOnline HealthGateType = "HostOnline"
+	// HealthGateForestsMounted waits for the forests of the host to be open or replicating.
+	HealthGateForestsMounted HealthGateType = "ForestsMounted"
+	// HealthGateAppServer waits for an app server of the host to respond.
+	HealthGateAppServer HealthGateType = "AppServer"
+	// HealthGateHTTP waits for a custom HTTP endpoint of the pod to return a 2xx or 3xx status.
+	HealthGateHTTP HealthGateType = "HTTP"
+)
+
+// HealthGateFailurePolicy decides what happens when a health gate times out.
+// +kubebuilder:validation:Enum=Fail;Ignore
+type HealthGateFailurePolicy string
+
+const (
+	// HealthGateFail stops restarting pods and marks the upgrade Failed.
+	HealthGateFail HealthGateFailurePolicy = "Fail"
+	// HealthGateIgnore records a warning event and continues with the next pod.
+	HealthGateIgnore HealthGateFailurePolicy = "Ignore"
+)
+
+// HealthGate is a check that must pass for a restarted pod before the next pod is restarted.
+// +kubebuilder:validation:XValidation:rule="self.type != 'HTTP' || has(self.http)", message="http is required for HTTP health gates"
+type HealthGate struct {
+	// +kubebuilder:validation:MinLength=1
+	Name string         `json:"name"`
+	Type HealthGateType `json:"type"`
+	// Timeout is how long after the restart the gate may keep failing. Defaults to 10m.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+	// +kubebuilder:default=Fail
+	// +optional
+	FailurePolicy HealthGateFailurePolicy `json:"failurePolicy,omitempty"`
+	// Port of the app server checked by AppServer gates. Defaults to 8000.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port int32 `json:"port,omitempty"`
+	// +optional
+	HTTP *HTTPHealthGate `json:"http,omitempty"`
+}
+
+// HTTPHealthGate is a custom HTTP endpoint served by the restarted pod.
+type HTTPHealthGate struct {
+	// +kubebuilder:default="/"
+	// +optional
+	Path string `json:"path,omitempty"`
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port"`
+	// +kubebuilder:validation:Enum=HTTP;HTTPS
+	// +kubebuilder:default=HTTP
+	// +optional
+	Scheme string `json:"scheme,omitempty"`
+}
+
+// PrecheckSpec enables, disables or configures one upgrade precheck.
+type PrecheckSpec struct {
+	// Name of the precheck, e.g. cluster-health, forest-health, storage-headroom,
+	// license or backup-freshness.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// Enabled defaults to true.
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+	// Parameters are passed to the precheck; the supported keys depend on the check.
+	// +optional
+	Parameters map[string]string `json:"parameters,omitempty"`
+}
+
+// UpgradePodRestart tracks a pod restarted by the operator during an upgrade.
+type UpgradePodRestart struct {
+	Pod string `json:"pod"`
+	// PodUID is the UID of the deleted pod. The health gates only check the
+	// pod of this name once it has a new UID.
+	// +optional
+	PodUID    types.UID   `json:"podUID,omitempty"`
+	StartTime metav1.Time `json:"startTime"`
+}
+
+// UpgradePodHold tracks a pod restart held back by merges or reindexing on
+// the host of the pod.
+type UpgradePodHold struct {
+	Pod   string      `json:"pod"`
+	Since metav1.Time `json:"since"`
+	// Jobs describes the background jobs the restart waits for.
+	Jobs string `json:"jobs,omitempty"`
+}
+
+// PrecheckResult is the outcome of one precheck run.
+type PrecheckResult struct {
+	Name    string         `json:"name"`
+	Status  PrecheckStatus `json:"status"`
+	Message string         `json:"message,omitempty"`
+}
+
+// MaxUpgradeTimelineEntries bounds status.upgrade.timeline; older entries are dropped.
+const MaxUpgradeTimelineEntries = 50
+
+// MaxPodStartHistory bounds status.upgrade.podStartHistory; older entries are dropped.
+const MaxPodStartHistory = 20
+
+// UpgradeImpact estimates the blast radius of an upgrade, so approvers can
+// judge it before approving.
+type UpgradeImpact struct {
+	// Restarts is the number of pods the upgrade restarts, one at a time.
+	Restarts int32 `json:"restarts"`
+	// PodDowntime is the expected time from the restart of a pod until its
+	// health gates pass, the median of podStartHistory. It is not set before
+	// the first upgrade restarted a pod.
+	// +optional
+	PodDowntime *metav1.Duration `json:"podDowntime,omitempty"`
+	// Duration is the expected time until every pod is restarted.
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+	// Databases are the databases with forests on the restarted hosts.
+	// +listType=set
+	// +optional
+	Databases []string `json:"databases,omitempty"`
+	Message   string   `json:"message,omitempty"`
+}
+
+// UpgradeTimelineEntry records one state transition of the upgrade workflow.
+type UpgradeTimelineEntry struct {
+	State UpgradeState `json:"state"`
+	Time  metav1.Time  `json:"time"`
+	// Actor is the user or field manager that caused the transition, or
+	// "marklogic-operator" for transitions made by the operator itself.
+	Actor   string `json:"actor,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// UpgradeStatus tracks the rollout of spec.image across the groups of the cluster.
+type UpgradeStatus struct {
+	State UpgradeState `json:"state,omitempty"`
+	// CurrentImage is the image the cluster last finished rolling out.
+	CurrentImage string `json:"currentImage,omitempty"`
+	// TargetImage is the image being rolled out.
+	TargetImage    string       `json:"targetImage,omitempty"`
+	StartTime      *metav1.Time `json:"startTime,omitempty"`
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// RolloutStarted is set once the prechecks passed and TargetImage was
+	// handed to the groups.
+	RolloutStarted bool `json:"rolloutStarted,omitempty"`
+	// PodRestart is the pod the operator restarted last and whose health
+	// gates have not passed yet.
+	PodRestart *UpgradePodRestart `json:"podRestart,omitempty"`
+	// PodHold is the pod whose restart waits for background jobs.
+	PodHold *UpgradePodHold `json:"podHold,omitempty"`
+	// Paused is set while spec.operations.pauseUpgrade or the pause-upgrade
+	// annotation holds the rollout before the next pod restart.
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+	// RetryRequestID is the request ID of the last retry of a failed upgrade.
+	// +optional
+	RetryRequestID string `json:"retryRequestID,omitempty"`
+	// Step is set for major version upgrades, which upgrade the bootstrap
+	// group and the Security database before the other groups.
+	Step UpgradeStep `json:"step,omitempty"`
+	// UpdatedPods and TotalPods count the pods running TargetImage.
+	UpdatedPods int32  `json:"updatedPods,omitempty"`
+	TotalPods   int32  `json:"totalPods,omitempty"`
+	Message     string `json:"message,omitempty"`
+	// Prechecks holds the results of the last precheck run for TargetImage.
+	// +listType=atomic
+	Prechecks []PrecheckResult `json:"prechecks,omitempty"`
+	// Impact is estimated with every precheck run for TargetImage.
+	// +optional
+	Impact *UpgradeImpact `json:"impact,omitempty"`
+	// PodStartHistory are the times the pods restarted by the last upgrades
+	// took until their health gates passed, oldest first.
+	// +listType=atomic
+	// +optional
+	PodStartHistory []metav1.Duration `json:"podStartHistory,omitempty"`
+	// PendingApproval is set while the upgrade waits in
+	// WaitingForUserApproval, for pipelines that change spec.image and
+	// approve the rollout.
+	// +optional
+	PendingApproval *UpgradeApproval `json:"pendingApproval,omitempty"`
+	// +listType=atomic
+	Timeline []UpgradeTimelineEntry `json:"timeline,omitempty"`
+}
+
+// UpgradeApproval describes the approval an upgrade waits for.
+type UpgradeApproval struct {
+	// Image is the image to approve.
+	Image string `json:"image"`
+	// Since is when the upgrade started to wait.
+	Since metav1.Time `json:"since"`
+	// RequestedBy is the field manager that set spec.image, such as the
+	// kustomize-controller of Flux applying a commit of its image
+	// automation.
+	// +optional
+	RequestedBy string `json:"requestedBy,omitempty"`
+	// ImagePolicy is the image policy that selected the image, from the
+	// marklogic.progress.com/image-policy annotation of the cluster.
+	// +optional
+	ImagePolicy string `json:"imagePolicy,omitempty"`
+	// Annotation approves the upgrade when the cluster is annotated with it
+	// and Image.
+	Annotation string `json:"annotation"`
+	// Field approves the upgrade when set to Image.
+	Field string `json:"field"`
+	// Warnings counts the prechecks that ended in Warning.
+	// +optional
+	Warnings int32 `json:"warnings,omitempty"`
+	// AutoApproval is why spec.upgrade.autoApprove did not approve the
+	// upgrade.
+	// +optional
+	AutoApproval string `json:"autoApproval,omitempty"`
+}
+
+// RecordTransition moves the upgrade to state and appends a timeline entry.
+func (u *UpgradeStatus) RecordTransition(state UpgradeState, actor, message string, now metav1.Time) {
+	u.State = state
+	u.Message = message
+	u.Timeline = append(u.Timeline, UpgradeTimelineEntry{State: state, Time: now, Actor: actor, Message: message})
+	if len(u.Timeline) > MaxUpgradeTimelineEntries {
+		u.Timeline = u.Timeline[len(u.Timeline)-MaxUpgradeTimelineEntries:]
+	}
+}
+
+// RecordPodStart appends the time a restarted pod took until its health
+// gates passed to the history.
+func (u *UpgradeStatus) RecordPodStart(d time.Duration) {
+	u.PodStartHistory = append(u.PodStartHistory, metav1.Duration{Duration: d})
+	if len(u.PodStartHistory) > MaxPodStartHistory {
+		u.PodStartHistory = u.PodStartHistory[len(u.PodStartHistory)-MaxPodStartHistory:]
+	}
+}
+
+// Active reports whether the upgrade is running its prechecks, waiting for
+// approval or rolling out TargetImage.
+func (u *UpgradeStatus) Active() bool {
+	if u == nil {
+		return false
+	}
+	return u.State == UpgradeStatePrecheck || u.State == UpgradeStateWaitingForUserApproval || u.State == UpgradeStateInProgress
+}
+
+// ResourceRolloutState is the state of a rollout of changed group resources.
+// +kubebuilder:validation:Enum=InProgress;Completed;Failed
+type ResourceRolloutState string
+
+const (
+	ResourceRolloutInProgress ResourceRolloutState = "InProgress"
+	ResourceRolloutCompleted  ResourceRolloutState = "Completed"
+	ResourceRolloutFailed     ResourceRolloutState = "Failed"
+)
+
+// ResourceRolloutStatus tracks the restart of pods whose CPU or memory no
+// longer match their group. The pods are restarted one at a time behind the
+// upgrade health gates.
+type ResourceRolloutStatus struct {
+	State          ResourceRolloutState `json:"state,omitempty"`
+	StartTime      *metav1.Time         `json:"startTime,omitempty"`
+	CompletionTime *metav1.Time         `json:"completionTime,omitempty"`
+	// PodRestart is the pod restarted last whose health gates have not
+	// passed yet.
+	PodRestart *UpgradePodRestart `json:"podRestart,omitempty"`
+	// PodHold is the pod whose restart waits for background jobs.
+	PodHold *UpgradePodHold `json:"podHold,omitempty"`
+	// OutdatedPods counts the pods still running with the previous resources,
+	// or the previous pod template with spec.upgrade.restartBatching.
+	OutdatedPods int32 `json:"outdatedPods,omitempty"`
+	// Revisions are the update revisions of the StatefulSets of the groups,
+	// as group=revision, seen last with spec.upgrade.restartBatching.
+	// +listType=atomic
+	Revisions []string `json:"revisions,omitempty"`
+	// SettlingUntil is when the pods are restarted unless another change of
+	// a pod template arrives before.
+	SettlingUntil *metav1.Time `json:"settlingUntil,omitempty"`
+	// ObservedGeneration is the cluster generation a failed rollout stopped
+	// at. The rollout is retried once the spec changes.
+	ObservedGeneration int64  `json:"observedGeneration,omitempty"`
+	Message            string `json:"message,omitempty"`
+}
+
+// HostRestartState is the state of a restart of hosts requested with the
+// marklogic.progress.com/restart-hosts annotation.
+// +kubebuilder:validation:Enum=InProgress;Completed;Failed;Cancelled
+type HostRestartState string
+
+const (
+	HostRestartInProgress HostRestartState = "InProgress"
+	HostRestartCompleted  HostRestartState = "Completed"
+	HostRestartFailed     HostRestartState = "Failed"
+	HostRestartCancelled  HostRestartState = "Cancelled"
+)
+
+// HostRestartStatus tracks the restart of the pods named in the
+// marklogic.progress.com/restart-hosts annotation. The pods are restarted one
+// at a time behind the upgrade health gates.
+type HostRestartStatus struct {
+	// Request is the value of the annotation the restart was started for.
+	Request        string           `json:"request,omitempty"`
+	State          HostRestartState `json:"state,omitempty"`
+	StartTime      *metav1.Time     `json:"startTime,omitempty"`
+	CompletionTime *metav1.Time     `json:"completionTime,omitempty"`
+	// Pending are the pods left to restart, in the order they are restarted.
+	// +listType=atomic
+	Pending []string `json:"pending,omitempty"`
+	// Restarted are the pods restarted whose health gates passed.
+	// +listType=atomic
+	Restarted []string `json:"restarted,omitempty"`
+	// PodRestart is the pod restarted last whose health gates have not
+	// passed yet.
+	PodRestart *UpgradePodRestart `json:"podRestart,omitempty"`
+	// PodHold is the pod whose restart waits for background jobs.
+	PodHold *UpgradePodHold `json:"podHold,omitempty"`
+	Message string          `json:"message,omitempty"`
+}
@@ -0,0 +1,19 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package v1
+
+// ZoneAwareness sets the zone of every MarkLogic host of a group to the zone
+// of the node its pod runs on, so MarkLogic places forest replicas in another
+// failure domain than their master forests.
+type ZoneAwareness struct {
+	// TopologyKey is the node label that holds the zone.
+	// +kubebuilder:default:="topology.kubernetes.io/zone"
+	// +kubebuilder:validation:MinLength=1
+	TopologyKey string `json:"topologyKey,omitempty"`
+}
+
+// HostZone is the zone the operator set on a MarkLogic host.
+type HostZone struct {
+	Host string `json:"host"`
+	Zone string `json:"zone"`
+}
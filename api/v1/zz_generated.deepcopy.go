@@ -50,6 +50,16 @@ func (in *AdminAuth) DeepCopyInto(out *AdminAuth) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.Realm != nil {
+		in, out := &in.Realm, &out.Realm
+		*out = new(string)
+		**out = **in
+	}
+	if in.OperatorUser != nil {
+		in, out := &in.OperatorUser, &out.OperatorUser
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdminAuth.
@@ -63,644 +73,3202 @@ func (in *AdminAuth) DeepCopy() *AdminAuth {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AppServers) DeepCopyInto(out *AppServers) {
+func (in *AdminIngress) DeepCopyInto(out *AdminIngress) {
 	*out = *in
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppServers.
-func (in *AppServers) DeepCopy() *AppServers {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdminIngress.
+func (in *AdminIngress) DeepCopy() *AdminIngress {
 	if in == nil {
 		return nil
 	}
-	out := new(AppServers)
+	out := new(AdminIngress)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ContainerProbe) DeepCopyInto(out *ContainerProbe) {
+func (in *AppServerSettings) DeepCopyInto(out *AppServerSettings) {
 	*out = *in
+	if in.RequestTimeout != nil {
+		in, out := &in.RequestTimeout, &out.RequestTimeout
+		*out = new(int32)
+		**out = **in
+	}
+	if in.KeepAliveTimeout != nil {
+		in, out := &in.KeepAliveTimeout, &out.KeepAliveTimeout
+		*out = new(int32)
+		**out = **in
+	}
+	if in.SessionTimeout != nil {
+		in, out := &in.SessionTimeout, &out.SessionTimeout
+		*out = new(int32)
+		**out = **in
+	}
+	if in.DefaultTimeLimit != nil {
+		in, out := &in.DefaultTimeLimit, &out.DefaultTimeLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxTimeLimit != nil {
+		in, out := &in.MaxTimeLimit, &out.MaxTimeLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxInferenceSize != nil {
+		in, out := &in.MaxInferenceSize, &out.MaxInferenceSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ConcurrentRequestLimit != nil {
+		in, out := &in.ConcurrentRequestLimit, &out.ConcurrentRequestLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Threads != nil {
+		in, out := &in.Threads, &out.Threads
+		*out = new(int32)
+		**out = **in
+	}
+	if in.URLRewriter != nil {
+		in, out := &in.URLRewriter, &out.URLRewriter
+		*out = new(string)
+		**out = **in
+	}
+	if in.ErrorHandler != nil {
+		in, out := &in.ErrorHandler, &out.ErrorHandler
+		*out = new(string)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerProbe.
-func (in *ContainerProbe) DeepCopy() *ContainerProbe {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppServerSettings.
+func (in *AppServerSettings) DeepCopy() *AppServerSettings {
 	if in == nil {
 		return nil
 	}
-	out := new(ContainerProbe)
+	out := new(AppServerSettings)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DynamicGroupConfig) DeepCopyInto(out *DynamicGroupConfig) {
+func (in *AppServerSettingsStatus) DeepCopyInto(out *AppServerSettingsStatus) {
 	*out = *in
+	if in.AppliedAt != nil {
+		in, out := &in.AppliedAt, &out.AppliedAt
+		*out = (*in).DeepCopy()
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DynamicGroupConfig.
-func (in *DynamicGroupConfig) DeepCopy() *DynamicGroupConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppServerSettingsStatus.
+func (in *AppServerSettingsStatus) DeepCopy() *AppServerSettingsStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(DynamicGroupConfig)
+	out := new(AppServerSettingsStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DynamicGroupStatus) DeepCopyInto(out *DynamicGroupStatus) {
+func (in *AppServers) DeepCopyInto(out *AppServers) {
 	*out = *in
-	if in.LastTransitionTime != nil {
-		in, out := &in.LastTransitionTime, &out.LastTransitionTime
-		*out = (*in).DeepCopy()
-	}
-	if in.Hosts != nil {
-		in, out := &in.Hosts, &out.Hosts
-		*out = make([]DynamicHostStatus, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DynamicGroupStatus.
-func (in *DynamicGroupStatus) DeepCopy() *DynamicGroupStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppServers.
+func (in *AppServers) DeepCopy() *AppServers {
 	if in == nil {
 		return nil
 	}
-	out := new(DynamicGroupStatus)
+	out := new(AppServers)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DynamicHostStatus) DeepCopyInto(out *DynamicHostStatus) {
+func (in *AutoApprove) DeepCopyInto(out *AutoApprove) {
 	*out = *in
-	if in.LastUpdated != nil {
-		in, out := &in.LastUpdated, &out.LastUpdated
-		*out = (*in).DeepCopy()
+	if in.MaxWarnings != nil {
+		in, out := &in.MaxWarnings, &out.MaxWarnings
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaintenanceWindow != nil {
+		in, out := &in.MaintenanceWindow, &out.MaintenanceWindow
+		*out = new(MaintenanceWindow)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DynamicHostStatus.
-func (in *DynamicHostStatus) DeepCopy() *DynamicHostStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoApprove.
+func (in *AutoApprove) DeepCopy() *AutoApprove {
 	if in == nil {
 		return nil
 	}
-	out := new(DynamicHostStatus)
+	out := new(AutoApprove)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FailedPVCStatus) DeepCopyInto(out *FailedPVCStatus) {
+func (in *AzureBackupStorage) DeepCopyInto(out *AzureBackupStorage) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailedPVCStatus.
-func (in *FailedPVCStatus) DeepCopy() *FailedPVCStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureBackupStorage.
+func (in *AzureBackupStorage) DeepCopy() *AzureBackupStorage {
 	if in == nil {
 		return nil
 	}
-	out := new(FailedPVCStatus)
+	out := new(AzureBackupStorage)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GroupConfig) DeepCopyInto(out *GroupConfig) {
+func (in *BackgroundJobProtection) DeepCopyInto(out *BackgroundJobProtection) {
 	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroupConfig.
-func (in *GroupConfig) DeepCopy() *GroupConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackgroundJobProtection.
+func (in *BackgroundJobProtection) DeepCopy() *BackgroundJobProtection {
 	if in == nil {
 		return nil
 	}
-	out := new(GroupConfig)
+	out := new(BackgroundJobProtection)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HAProxy) DeepCopyInto(out *HAProxy) {
+func (in *Backup) DeepCopyInto(out *Backup) {
 	*out = *in
-	if in.ImagePullSecrets != nil {
-		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
-		*out = make([]corev1.LocalObjectReference, len(*in))
-		copy(*out, *in)
-	}
-	if in.PodSecurityContext != nil {
-		in, out := &in.PodSecurityContext, &out.PodSecurityContext
-		*out = new(corev1.PodSecurityContext)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.ContainerSecurityContext != nil {
-		in, out := &in.ContainerSecurityContext, &out.ContainerSecurityContext
-		*out = new(corev1.SecurityContext)
+	if in.Storage != nil {
+		in, out := &in.Storage, &out.Storage
+		*out = new(BackupStorage)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.AppServers != nil {
-		in, out := &in.AppServers, &out.AppServers
-		*out = make([]AppServers, len(*in))
-		copy(*out, *in)
-	}
-	if in.PathBasedRouting != nil {
-		in, out := &in.PathBasedRouting, &out.PathBasedRouting
-		*out = new(bool)
-		**out = **in
-	}
-	if in.Service != nil {
-		in, out := &in.Service, &out.Service
-		*out = new(corev1.ServiceType)
-		**out = **in
+	if in.Schedules != nil {
+		in, out := &in.Schedules, &out.Schedules
+		*out = make([]BackupSchedule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	if in.TcpPorts != nil {
-		in, out := &in.TcpPorts, &out.TcpPorts
-		*out = new(Tcpports)
+	if in.Restore != nil {
+		in, out := &in.Restore, &out.Restore
+		*out = new(BackupRestore)
 		(*in).DeepCopyInto(*out)
 	}
-	out.Timeout = in.Timeout
-	if in.Tls != nil {
-		in, out := &in.Tls, &out.Tls
-		*out = new(TlsForHAProxy)
+	if in.Velero != nil {
+		in, out := &in.Velero, &out.Velero
+		*out = new(VeleroHooks)
 		**out = **in
 	}
-	out.Stats = in.Stats
-	in.Resources.DeepCopyInto(&out.Resources)
-	if in.Affinity != nil {
-		in, out := &in.Affinity, &out.Affinity
-		*out = new(corev1.Affinity)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.NodeSelector != nil {
-		in, out := &in.NodeSelector, &out.NodeSelector
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	in.Ingress.DeepCopyInto(&out.Ingress)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HAProxy.
-func (in *HAProxy) DeepCopy() *HAProxy {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Backup.
+func (in *Backup) DeepCopy() *Backup {
 	if in == nil {
 		return nil
 	}
-	out := new(HAProxy)
+	out := new(Backup)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HAProxyGroup) DeepCopyInto(out *HAProxyGroup) {
+func (in *BackupRestore) DeepCopyInto(out *BackupRestore) {
 	*out = *in
-	if in.AppServers != nil {
-		in, out := &in.AppServers, &out.AppServers
-		*out = make([]AppServers, len(*in))
-		copy(*out, *in)
-	}
-	if in.PathBasedRouting != nil {
-		in, out := &in.PathBasedRouting, &out.PathBasedRouting
-		*out = new(bool)
-		**out = **in
-	}
-	if in.TcpPorts != nil {
-		in, out := &in.TcpPorts, &out.TcpPorts
-		*out = new(Tcpports)
-		(*in).DeepCopyInto(*out)
+	if in.RestoreToTime != nil {
+		in, out := &in.RestoreToTime, &out.RestoreToTime
+		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HAProxyGroup.
-func (in *HAProxyGroup) DeepCopy() *HAProxyGroup {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupRestore.
+func (in *BackupRestore) DeepCopy() *BackupRestore {
 	if in == nil {
 		return nil
 	}
-	out := new(HAProxyGroup)
+	out := new(BackupRestore)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HugePages) DeepCopyInto(out *HugePages) {
+func (in *BackupSchedule) DeepCopyInto(out *BackupSchedule) {
 	*out = *in
+	if in.DaysOfWeek != nil {
+		in, out := &in.DaysOfWeek, &out.DaysOfWeek
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HugePages.
-func (in *HugePages) DeepCopy() *HugePages {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupSchedule.
+func (in *BackupSchedule) DeepCopy() *BackupSchedule {
 	if in == nil {
 		return nil
 	}
-	out := new(HugePages)
+	out := new(BackupSchedule)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Ingress) DeepCopyInto(out *Ingress) {
+func (in *BackupStatus) DeepCopyInto(out *BackupStatus) {
 	*out = *in
-	if in.Labels != nil {
-		in, out := &in.Labels, &out.Labels
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.Annotations != nil {
-		in, out := &in.Annotations, &out.Annotations
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.Storage != nil {
+		in, out := &in.Storage, &out.Storage
+		*out = new(BackupStorageStatus)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.TLS != nil {
-		in, out := &in.TLS, &out.TLS
-		*out = make([]networkingv1.IngressTLS, len(*in))
+	if in.Schedules != nil {
+		in, out := &in.Schedules, &out.Schedules
+		*out = make([]ScheduledBackupStatus, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.AdditionalHosts != nil {
-		in, out := &in.AdditionalHosts, &out.AdditionalHosts
-		*out = make([]networkingv1.IngressRule, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.Restore != nil {
+		in, out := &in.Restore, &out.Restore
+		*out = new(RestoreStatus)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Ingress.
-func (in *Ingress) DeepCopy() *Ingress {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupStatus.
+func (in *BackupStatus) DeepCopy() *BackupStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(Ingress)
+	out := new(BackupStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *License) DeepCopyInto(out *License) {
+func (in *BackupStorage) DeepCopyInto(out *BackupStorage) {
 	*out = *in
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(S3BackupStorage)
+		**out = **in
+	}
+	if in.Azure != nil {
+		in, out := &in.Azure, &out.Azure
+		*out = new(AzureBackupStorage)
+		**out = **in
+	}
+	if in.Retry != nil {
+		in, out := &in.Retry, &out.Retry
+		*out = new(BackupStorageRetry)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new License.
-func (in *License) DeepCopy() *License {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupStorage.
+func (in *BackupStorage) DeepCopy() *BackupStorage {
 	if in == nil {
 		return nil
 	}
-	out := new(License)
+	out := new(BackupStorage)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LogCollection) DeepCopyInto(out *LogCollection) {
+func (in *BackupStorageRetry) DeepCopyInto(out *BackupStorageRetry) {
 	*out = *in
-	if in.ImagePullSecrets != nil {
-		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
-		*out = make([]corev1.LocalObjectReference, len(*in))
-		copy(*out, *in)
-	}
-	if in.SecurityContext != nil {
-		in, out := &in.SecurityContext, &out.SecurityContext
-		*out = new(corev1.SecurityContext)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Resources != nil {
-		in, out := &in.Resources, &out.Resources
-		*out = new(corev1.ResourceRequirements)
-		(*in).DeepCopyInto(*out)
-	}
-	out.Files = in.Files
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogCollection.
-func (in *LogCollection) DeepCopy() *LogCollection {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupStorageRetry.
+func (in *BackupStorageRetry) DeepCopy() *BackupStorageRetry {
 	if in == nil {
 		return nil
 	}
-	out := new(LogCollection)
+	out := new(BackupStorageRetry)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LogFilesConfig) DeepCopyInto(out *LogFilesConfig) {
+func (in *BackupStorageStatus) DeepCopyInto(out *BackupStorageStatus) {
 	*out = *in
+	if in.NextRetryTime != nil {
+		in, out := &in.NextRetryTime, &out.NextRetryTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastTransitionTime != nil {
+		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+		*out = (*in).DeepCopy()
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogFilesConfig.
-func (in *LogFilesConfig) DeepCopy() *LogFilesConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupStorageStatus.
+func (in *BackupStorageStatus) DeepCopy() *BackupStorageStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(LogFilesConfig)
+	out := new(BackupStorageStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MarklogicCluster) DeepCopyInto(out *MarklogicCluster) {
+func (in *Bootstrap) DeepCopyInto(out *Bootstrap) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	if in.DataImport != nil {
+		in, out := &in.DataImport, &out.DataImport
+		*out = new(DataImport)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MarklogicCluster.
-func (in *MarklogicCluster) DeepCopy() *MarklogicCluster {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Bootstrap.
+func (in *Bootstrap) DeepCopy() *Bootstrap {
 	if in == nil {
 		return nil
 	}
-	out := new(MarklogicCluster)
+	out := new(Bootstrap)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *MarklogicCluster) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BootstrapStatus) DeepCopyInto(out *BootstrapStatus) {
+	*out = *in
+	if in.DataImport != nil {
+		in, out := &in.DataImport, &out.DataImport
+		*out = new(DataImportStatus)
+		(*in).DeepCopyInto(*out)
 	}
-	return nil
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BootstrapStatus.
+func (in *BootstrapStatus) DeepCopy() *BootstrapStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BootstrapStatus)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MarklogicClusterList) DeepCopyInto(out *MarklogicClusterList) {
+func (in *CapacityStatus) DeepCopyInto(out *CapacityStatus) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]MarklogicCluster, len(*in))
+	in.Total.DeepCopyInto(&out.Total)
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]GroupCapacity, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.LastUpdateTime != nil {
+		in, out := &in.LastUpdateTime, &out.LastUpdateTime
+		*out = (*in).DeepCopy()
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MarklogicClusterList.
-func (in *MarklogicClusterList) DeepCopy() *MarklogicClusterList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CapacityStatus.
+func (in *CapacityStatus) DeepCopy() *CapacityStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(MarklogicClusterList)
+	out := new(CapacityStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *MarklogicClusterList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MarklogicClusterSpec) DeepCopyInto(out *MarklogicClusterSpec) {
+func (in *ChangeLogStatus) DeepCopyInto(out *ChangeLogStatus) {
 	*out = *in
-	if in.ImagePullSecrets != nil {
-		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
-		*out = make([]corev1.LocalObjectReference, len(*in))
-		copy(*out, *in)
-	}
-	if in.Auth != nil {
-		in, out := &in.Auth, &out.Auth
-		*out = new(AdminAuth)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.AutomountServiceAccountToken != nil {
-		in, out := &in.AutomountServiceAccountToken, &out.AutomountServiceAccountToken
-		*out = new(bool)
-		**out = **in
-	}
-	if in.Persistence != nil {
-		in, out := &in.Persistence, &out.Persistence
-		*out = new(Persistence)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Resources != nil {
-		in, out := &in.Resources, &out.Resources
-		*out = new(corev1.ResourceRequirements)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.TerminationGracePeriodSeconds != nil {
-		in, out := &in.TerminationGracePeriodSeconds, &out.TerminationGracePeriodSeconds
-		*out = new(int64)
-		**out = **in
-	}
-	in.NetworkPolicy.DeepCopyInto(&out.NetworkPolicy)
-	if in.PodSecurityContext != nil {
-		in, out := &in.PodSecurityContext, &out.PodSecurityContext
-		*out = new(corev1.PodSecurityContext)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.ContainerSecurityContext != nil {
-		in, out := &in.ContainerSecurityContext, &out.ContainerSecurityContext
-		*out = new(corev1.SecurityContext)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Affinity != nil {
-		in, out := &in.Affinity, &out.Affinity
-		*out = new(corev1.Affinity)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.NodeSelector != nil {
-		in, out := &in.NodeSelector, &out.NodeSelector
+	if in.Observed != nil {
+		in, out := &in.Observed, &out.Observed
 		*out = make(map[string]string, len(*in))
 		for key, val := range *in {
 			(*out)[key] = val
 		}
 	}
-	if in.TopologySpreadConstraints != nil {
-		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
-		*out = make([]corev1.TopologySpreadConstraint, len(*in))
+	if in.Entries != nil {
+		in, out := &in.Entries, &out.Entries
+		*out = make([]SpecChangeEntry, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.License != nil {
-		in, out := &in.License, &out.License
-		*out = new(License)
-		**out = **in
-	}
-	if in.HugePages != nil {
-		in, out := &in.HugePages, &out.HugePages
-		*out = new(HugePages)
-		**out = **in
-	}
-	if in.LogCollection != nil {
-		in, out := &in.LogCollection, &out.LogCollection
-		*out = new(LogCollection)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.HAProxy != nil {
-		in, out := &in.HAProxy, &out.HAProxy
-		*out = new(HAProxy)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Tls != nil {
-		in, out := &in.Tls, &out.Tls
-		*out = new(Tls)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.AdditionalVolumes != nil {
-		in, out := &in.AdditionalVolumes, &out.AdditionalVolumes
-		*out = new([]corev1.Volume)
-		if **in != nil {
-			in, out := *in, *out
-			*out = make([]corev1.Volume, len(*in))
-			for i := range *in {
-				(*in)[i].DeepCopyInto(&(*out)[i])
-			}
-		}
-	}
-	if in.AdditionalVolumeMounts != nil {
-		in, out := &in.AdditionalVolumeMounts, &out.AdditionalVolumeMounts
-		*out = new([]corev1.VolumeMount)
-		if **in != nil {
-			in, out := *in, *out
-			*out = make([]corev1.VolumeMount, len(*in))
-			for i := range *in {
-				(*in)[i].DeepCopyInto(&(*out)[i])
-			}
-		}
-	}
-	if in.AdditionalVolumeClaimTemplates != nil {
-		in, out := &in.AdditionalVolumeClaimTemplates, &out.AdditionalVolumeClaimTemplates
-		*out = new([]corev1.PersistentVolumeClaim)
-		if **in != nil {
-			in, out := *in, *out
-			*out = make([]corev1.PersistentVolumeClaim, len(*in))
-			for i := range *in {
-				(*in)[i].DeepCopyInto(&(*out)[i])
-			}
-		}
-	}
-	if in.MarkLogicGroups != nil {
-		in, out := &in.MarkLogicGroups, &out.MarkLogicGroups
-		*out = make([]*MarklogicGroups, len(*in))
-		for i := range *in {
-			if (*in)[i] != nil {
-				in, out := &(*in)[i], &(*out)[i]
-				*out = new(MarklogicGroups)
-				(*in).DeepCopyInto(*out)
-			}
-		}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChangeLogStatus.
+func (in *ChangeLogStatus) DeepCopy() *ChangeLogStatus {
+	if in == nil {
+		return nil
 	}
+	out := new(ChangeLogStatus)
+	in.DeepCopyInto(out)
+	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MarklogicClusterSpec.
-func (in *MarklogicClusterSpec) DeepCopy() *MarklogicClusterSpec {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloneGroup) DeepCopyInto(out *CloneGroup) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloneGroup.
+func (in *CloneGroup) DeepCopy() *CloneGroup {
 	if in == nil {
 		return nil
 	}
-	out := new(MarklogicClusterSpec)
+	out := new(CloneGroup)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MarklogicClusterStatus) DeepCopyInto(out *MarklogicClusterStatus) {
+func (in *ClusterHostsStatus) DeepCopyInto(out *ClusterHostsStatus) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]metav1.Condition, len(*in))
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]GroupHostsStatus, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.LastUpdateTime != nil {
+		in, out := &in.LastUpdateTime, &out.LastUpdateTime
+		*out = (*in).DeepCopy()
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MarklogicClusterStatus.
-func (in *MarklogicClusterStatus) DeepCopy() *MarklogicClusterStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterHostsStatus.
+func (in *ClusterHostsStatus) DeepCopy() *ClusterHostsStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(MarklogicClusterStatus)
+	out := new(ClusterHostsStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MarklogicGroup) DeepCopyInto(out *MarklogicGroup) {
+func (in *ClusterRunStatus) DeepCopyInto(out *ClusterRunStatus) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	if in.LastTransitionTime != nil {
+		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+		*out = (*in).DeepCopy()
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MarklogicGroup.
-func (in *MarklogicGroup) DeepCopy() *MarklogicGroup {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterRunStatus.
+func (in *ClusterRunStatus) DeepCopy() *ClusterRunStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(MarklogicGroup)
+	out := new(ClusterRunStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *MarklogicGroup) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerProbe) DeepCopyInto(out *ContainerProbe) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerProbe.
+func (in *ContainerProbe) DeepCopy() *ContainerProbe {
+	if in == nil {
+		return nil
 	}
-	return nil
+	out := new(ContainerProbe)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MarklogicGroupList) DeepCopyInto(out *MarklogicGroupList) {
+func (in *ContentJobRetry) DeepCopyInto(out *ContentJobRetry) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]MarklogicGroup, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.BackoffLimit != nil {
+		in, out := &in.BackoffLimit, &out.BackoffLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ActiveDeadlineSeconds != nil {
+		in, out := &in.ActiveDeadlineSeconds, &out.ActiveDeadlineSeconds
+		*out = new(int64)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MarklogicGroupList.
-func (in *MarklogicGroupList) DeepCopy() *MarklogicGroupList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContentJobRetry.
+func (in *ContentJobRetry) DeepCopy() *ContentJobRetry {
 	if in == nil {
 		return nil
 	}
-	out := new(MarklogicGroupList)
+	out := new(ContentJobRetry)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *MarklogicGroupList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContentJobTarget) DeepCopyInto(out *ContentJobTarget) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContentJobTarget.
+func (in *ContentJobTarget) DeepCopy() *ContentJobTarget {
+	if in == nil {
+		return nil
 	}
-	return nil
+	out := new(ContentJobTarget)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MarklogicGroupSpec) DeepCopyInto(out *MarklogicGroupSpec) {
+func (in *DataImport) DeepCopyInto(out *DataImport) {
 	*out = *in
-	if in.Replicas != nil {
-		in, out := &in.Replicas, &out.Replicas
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BackoffLimit != nil {
+		in, out := &in.BackoffLimit, &out.BackoffLimit
 		*out = new(int32)
 		**out = **in
 	}
-	if in.Labels != nil {
-		in, out := &in.Labels, &out.Labels
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataImport.
+func (in *DataImport) DeepCopy() *DataImport {
+	if in == nil {
+		return nil
+	}
+	out := new(DataImport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataImportStatus) DeepCopyInto(out *DataImportStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataImportStatus.
+func (in *DataImportStatus) DeepCopy() *DataImportStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DataImportStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseRebalanceStatus) DeepCopyInto(out *DatabaseRebalanceStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseRebalanceStatus.
+func (in *DatabaseRebalanceStatus) DeepCopy() *DatabaseRebalanceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseRebalanceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseReplicationStatus) DeepCopyInto(out *DatabaseReplicationStatus) {
+	*out = *in
+	if in.Lag != nil {
+		in, out := &in.Lag, &out.Lag
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseReplicationStatus.
+func (in *DatabaseReplicationStatus) DeepCopy() *DatabaseReplicationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseReplicationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiagnosticSettings) DeepCopyInto(out *DiagnosticSettings) {
+	*out = *in
+	if in.TraceEvents != nil {
+		in, out := &in.TraceEvents, &out.TraceEvents
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiagnosticSettings.
+func (in *DiagnosticSettings) DeepCopy() *DiagnosticSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(DiagnosticSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Diagnostics) DeepCopyInto(out *Diagnostics) {
+	*out = *in
+	if in.TraceEvents != nil {
+		in, out := &in.TraceEvents, &out.TraceEvents
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExpiresAfter != nil {
+		in, out := &in.ExpiresAfter, &out.ExpiresAfter
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Diagnostics.
+func (in *Diagnostics) DeepCopy() *Diagnostics {
+	if in == nil {
+		return nil
+	}
+	out := new(Diagnostics)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiagnosticsStatus) DeepCopyInto(out *DiagnosticsStatus) {
+	*out = *in
+	in.Applied.DeepCopyInto(&out.Applied)
+	in.Previous.DeepCopyInto(&out.Previous)
+	in.AppliedAt.DeepCopyInto(&out.AppliedAt)
+	if in.ExpiresAt != nil {
+		in, out := &in.ExpiresAt, &out.ExpiresAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiagnosticsStatus.
+func (in *DiagnosticsStatus) DeepCopy() *DiagnosticsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DiagnosticsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DrainingPodStatus) DeepCopyInto(out *DrainingPodStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Forests != nil {
+		in, out := &in.Forests, &out.Forests
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.UnprotectedForests != nil {
+		in, out := &in.UnprotectedForests, &out.UnprotectedForests
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DrainingPodStatus.
+func (in *DrainingPodStatus) DeepCopy() *DrainingPodStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DrainingPodStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DynamicGroupConfig) DeepCopyInto(out *DynamicGroupConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DynamicGroupConfig.
+func (in *DynamicGroupConfig) DeepCopy() *DynamicGroupConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(DynamicGroupConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DynamicGroupStatus) DeepCopyInto(out *DynamicGroupStatus) {
+	*out = *in
+	if in.LastTransitionTime != nil {
+		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]DynamicHostStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DynamicGroupStatus.
+func (in *DynamicGroupStatus) DeepCopy() *DynamicGroupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DynamicGroupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DynamicHostStatus) DeepCopyInto(out *DynamicHostStatus) {
+	*out = *in
+	if in.LastUpdated != nil {
+		in, out := &in.LastUpdated, &out.LastUpdated
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DynamicHostStatus.
+func (in *DynamicHostStatus) DeepCopy() *DynamicHostStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DynamicHostStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExportStatus) DeepCopyInto(out *ExportStatus) {
+	*out = *in
+	if in.ExportTime != nil {
+		in, out := &in.ExportTime, &out.ExportTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExportStatus.
+func (in *ExportStatus) DeepCopy() *ExportStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ExportStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FIPSStatus) DeepCopyInto(out *FIPSStatus) {
+	*out = *in
+	if in.LastCheckTime != nil {
+		in, out := &in.LastCheckTime, &out.LastCheckTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FIPSStatus.
+func (in *FIPSStatus) DeepCopy() *FIPSStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FIPSStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FailedPVCStatus) DeepCopyInto(out *FailedPVCStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailedPVCStatus.
+func (in *FailedPVCStatus) DeepCopy() *FailedPVCStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FailedPVCStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ForeignClusterReplicationStatus) DeepCopyInto(out *ForeignClusterReplicationStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ForeignClusterReplicationStatus.
+func (in *ForeignClusterReplicationStatus) DeepCopy() *ForeignClusterReplicationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ForeignClusterReplicationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ForestProvisioning) DeepCopyInto(out *ForestProvisioning) {
+	*out = *in
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ForestProvisioning.
+func (in *ForestProvisioning) DeepCopy() *ForestProvisioning {
+	if in == nil {
+		return nil
+	}
+	out := new(ForestProvisioning)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ForestRestoreStatus) DeepCopyInto(out *ForestRestoreStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ForestRestoreStatus.
+func (in *ForestRestoreStatus) DeepCopy() *ForestRestoreStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ForestRestoreStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GroupAutoscaling) DeepCopyInto(out *GroupAutoscaling) {
+	*out = *in
+	if in.Vertical != nil {
+		in, out := &in.Vertical, &out.Vertical
+		*out = new(VerticalAutoscaling)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LoadMetrics != nil {
+		in, out := &in.LoadMetrics, &out.LoadMetrics
+		*out = new(LoadMetrics)
+		**out = **in
+	}
+	if in.Keda != nil {
+		in, out := &in.Keda, &out.Keda
+		*out = new(KedaAutoscaling)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroupAutoscaling.
+func (in *GroupAutoscaling) DeepCopy() *GroupAutoscaling {
+	if in == nil {
+		return nil
+	}
+	out := new(GroupAutoscaling)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GroupCapacity) DeepCopyInto(out *GroupCapacity) {
+	*out = *in
+	in.ResourceCapacity.DeepCopyInto(&out.ResourceCapacity)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroupCapacity.
+func (in *GroupCapacity) DeepCopy() *GroupCapacity {
+	if in == nil {
+		return nil
+	}
+	out := new(GroupCapacity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GroupConfig) DeepCopyInto(out *GroupConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroupConfig.
+func (in *GroupConfig) DeepCopy() *GroupConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(GroupConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GroupHostsStatus) DeepCopyInto(out *GroupHostsStatus) {
+	*out = *in
+	if in.Versions != nil {
+		in, out := &in.Versions, &out.Versions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroupHostsStatus.
+func (in *GroupHostsStatus) DeepCopy() *GroupHostsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GroupHostsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GroupWarmUpStatus) DeepCopyInto(out *GroupWarmUpStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]DatabaseRebalanceStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroupWarmUpStatus.
+func (in *GroupWarmUpStatus) DeepCopy() *GroupWarmUpStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GroupWarmUpStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HAProxy) DeepCopyInto(out *HAProxy) {
+	*out = *in
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]corev1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.PodSecurityContext != nil {
+		in, out := &in.PodSecurityContext, &out.PodSecurityContext
+		*out = new(corev1.PodSecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ContainerSecurityContext != nil {
+		in, out := &in.ContainerSecurityContext, &out.ContainerSecurityContext
+		*out = new(corev1.SecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AppServers != nil {
+		in, out := &in.AppServers, &out.AppServers
+		*out = make([]AppServers, len(*in))
+		copy(*out, *in)
+	}
+	if in.PathBasedRouting != nil {
+		in, out := &in.PathBasedRouting, &out.PathBasedRouting
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Service != nil {
+		in, out := &in.Service, &out.Service
+		*out = new(corev1.ServiceType)
+		**out = **in
+	}
+	if in.TcpPorts != nil {
+		in, out := &in.TcpPorts, &out.TcpPorts
+		*out = new(Tcpports)
+		(*in).DeepCopyInto(*out)
+	}
+	out.Timeout = in.Timeout
+	if in.Tls != nil {
+		in, out := &in.Tls, &out.Tls
+		*out = new(TlsForHAProxy)
+		**out = **in
+	}
+	out.Stats = in.Stats
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(corev1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.Ingress.DeepCopyInto(&out.Ingress)
+	if in.RolloutCoordination != nil {
+		in, out := &in.RolloutCoordination, &out.RolloutCoordination
+		*out = new(HAProxyRolloutCoordination)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HAProxy.
+func (in *HAProxy) DeepCopy() *HAProxy {
+	if in == nil {
+		return nil
+	}
+	out := new(HAProxy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HAProxyGroup) DeepCopyInto(out *HAProxyGroup) {
+	*out = *in
+	if in.AppServers != nil {
+		in, out := &in.AppServers, &out.AppServers
+		*out = make([]AppServers, len(*in))
+		copy(*out, *in)
+	}
+	if in.PathBasedRouting != nil {
+		in, out := &in.PathBasedRouting, &out.PathBasedRouting
+		*out = new(bool)
+		**out = **in
+	}
+	if in.TcpPorts != nil {
+		in, out := &in.TcpPorts, &out.TcpPorts
+		*out = new(Tcpports)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HAProxyGroup.
+func (in *HAProxyGroup) DeepCopy() *HAProxyGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(HAProxyGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HAProxyRolloutCoordination) DeepCopyInto(out *HAProxyRolloutCoordination) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HAProxyRolloutCoordination.
+func (in *HAProxyRolloutCoordination) DeepCopy() *HAProxyRolloutCoordination {
+	if in == nil {
+		return nil
+	}
+	out := new(HAProxyRolloutCoordination)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPHealthGate) DeepCopyInto(out *HTTPHealthGate) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPHealthGate.
+func (in *HTTPHealthGate) DeepCopy() *HTTPHealthGate {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPHealthGate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthGate) DeepCopyInto(out *HealthGate) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(HTTPHealthGate)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthGate.
+func (in *HealthGate) DeepCopy() *HealthGate {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthGate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthReport) DeepCopyInto(out *HealthReport) {
+	*out = *in
+	if in.WebhookURLSecret != nil {
+		in, out := &in.WebhookURLSecret, &out.WebhookURLSecret
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthReport.
+func (in *HealthReport) DeepCopy() *HealthReport {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthReport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthReportStatus) DeepCopyInto(out *HealthReportStatus) {
+	*out = *in
+	if in.ReportTime != nil {
+		in, out := &in.ReportTime, &out.ReportTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthReportStatus.
+func (in *HealthReportStatus) DeepCopy() *HealthReportStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthReportStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Hibernation) DeepCopyInto(out *Hibernation) {
+	*out = *in
+	out.Schedule = in.Schedule
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Hibernation.
+func (in *Hibernation) DeepCopy() *Hibernation {
+	if in == nil {
+		return nil
+	}
+	out := new(Hibernation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HibernationSchedule) DeepCopyInto(out *HibernationSchedule) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HibernationSchedule.
+func (in *HibernationSchedule) DeepCopy() *HibernationSchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(HibernationSchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HibernationStatus) DeepCopyInto(out *HibernationStatus) {
+	*out = *in
+	if in.NextStopTime != nil {
+		in, out := &in.NextStopTime, &out.NextStopTime
+		*out = (*in).DeepCopy()
+	}
+	if in.NextStartTime != nil {
+		in, out := &in.NextStartTime, &out.NextStartTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HibernationStatus.
+func (in *HibernationStatus) DeepCopy() *HibernationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HibernationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostJoin) DeepCopyInto(out *HostJoin) {
+	*out = *in
+	if in.Backoff != nil {
+		in, out := &in.Backoff, &out.Backoff
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MaxBackoff != nil {
+		in, out := &in.MaxBackoff, &out.MaxBackoff
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostJoin.
+func (in *HostJoin) DeepCopy() *HostJoin {
+	if in == nil {
+		return nil
+	}
+	out := new(HostJoin)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostRecoveryStatus) DeepCopyInto(out *HostRecoveryStatus) {
+	*out = *in
+	if in.LastAttemptTime != nil {
+		in, out := &in.LastAttemptTime, &out.LastAttemptTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostRecoveryStatus.
+func (in *HostRecoveryStatus) DeepCopy() *HostRecoveryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HostRecoveryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostRename) DeepCopyInto(out *HostRename) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostRename.
+func (in *HostRename) DeepCopy() *HostRename {
+	if in == nil {
+		return nil
+	}
+	out := new(HostRename)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostRestartStatus) DeepCopyInto(out *HostRestartStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Pending != nil {
+		in, out := &in.Pending, &out.Pending
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Restarted != nil {
+		in, out := &in.Restarted, &out.Restarted
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PodRestart != nil {
+		in, out := &in.PodRestart, &out.PodRestart
+		*out = new(UpgradePodRestart)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodHold != nil {
+		in, out := &in.PodHold, &out.PodHold
+		*out = new(UpgradePodHold)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostRestartStatus.
+func (in *HostRestartStatus) DeepCopy() *HostRestartStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HostRestartStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostStatus) DeepCopyInto(out *HostStatus) {
+	*out = *in
+	if in.LastRestartTime != nil {
+		in, out := &in.LastRestartTime, &out.LastRestartTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostStatus.
+func (in *HostStatus) DeepCopy() *HostStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HostStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostZone) DeepCopyInto(out *HostZone) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostZone.
+func (in *HostZone) DeepCopy() *HostZone {
+	if in == nil {
+		return nil
+	}
+	out := new(HostZone)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HugePages) DeepCopyInto(out *HugePages) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HugePages.
+func (in *HugePages) DeepCopy() *HugePages {
+	if in == nil {
+		return nil
+	}
+	out := new(HugePages)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Ingress) DeepCopyInto(out *Ingress) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = make([]networkingv1.IngressTLS, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AdditionalHosts != nil {
+		in, out := &in.AdditionalHosts, &out.AdditionalHosts
+		*out = make([]networkingv1.IngressRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Ingress.
+func (in *Ingress) DeepCopy() *Ingress {
+	if in == nil {
+		return nil
+	}
+	out := new(Ingress)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KedaAutoscaling) DeepCopyInto(out *KedaAutoscaling) {
+	*out = *in
+	if in.MinReplicas != nil {
+		in, out := &in.MinReplicas, &out.MinReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PollingInterval != nil {
+		in, out := &in.PollingInterval, &out.PollingInterval
+		*out = new(int32)
+		**out = **in
+	}
+	if in.CooldownPeriod != nil {
+		in, out := &in.CooldownPeriod, &out.CooldownPeriod
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Triggers != nil {
+		in, out := &in.Triggers, &out.Triggers
+		*out = make([]KedaTrigger, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KedaAutoscaling.
+func (in *KedaAutoscaling) DeepCopy() *KedaAutoscaling {
+	if in == nil {
+		return nil
+	}
+	out := new(KedaAutoscaling)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KedaTrigger) DeepCopyInto(out *KedaTrigger) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KedaTrigger.
+func (in *KedaTrigger) DeepCopy() *KedaTrigger {
+	if in == nil {
+		return nil
+	}
+	out := new(KedaTrigger)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *License) DeepCopyInto(out *License) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new License.
+func (in *License) DeepCopy() *License {
+	if in == nil {
+		return nil
+	}
+	out := new(License)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadMetrics) DeepCopyInto(out *LoadMetrics) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadMetrics.
+func (in *LoadMetrics) DeepCopy() *LoadMetrics {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadMetrics)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogCollection) DeepCopyInto(out *LogCollection) {
+	*out = *in
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]corev1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.SecurityContext != nil {
+		in, out := &in.SecurityContext, &out.SecurityContext
+		*out = new(corev1.SecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	out.Files = in.Files
+	if in.Buffer != nil {
+		in, out := &in.Buffer, &out.Buffer
+		*out = new(LogCollectionBuffer)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HealthCheck != nil {
+		in, out := &in.HealthCheck, &out.HealthCheck
+		*out = new(LogCollectionHealthCheck)
+		**out = **in
+	}
+	if in.Sampling != nil {
+		in, out := &in.Sampling, &out.Sampling
+		*out = new(LogSampling)
+		**out = **in
+	}
+	if in.SeverityRoutes != nil {
+		in, out := &in.SeverityRoutes, &out.SeverityRoutes
+		*out = make([]LogSeverityRoute, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogCollection.
+func (in *LogCollection) DeepCopy() *LogCollection {
+	if in == nil {
+		return nil
+	}
+	out := new(LogCollection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogCollectionBuffer) DeepCopyInto(out *LogCollectionBuffer) {
+	*out = *in
+	if in.StorageLimit != nil {
+		in, out := &in.StorageLimit, &out.StorageLimit
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.RetryLimit != nil {
+		in, out := &in.RetryLimit, &out.RetryLimit
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogCollectionBuffer.
+func (in *LogCollectionBuffer) DeepCopy() *LogCollectionBuffer {
+	if in == nil {
+		return nil
+	}
+	out := new(LogCollectionBuffer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogCollectionHealthCheck) DeepCopyInto(out *LogCollectionHealthCheck) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogCollectionHealthCheck.
+func (in *LogCollectionHealthCheck) DeepCopy() *LogCollectionHealthCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(LogCollectionHealthCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogFilesConfig) DeepCopyInto(out *LogFilesConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogFilesConfig.
+func (in *LogFilesConfig) DeepCopy() *LogFilesConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(LogFilesConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogSampling) DeepCopyInto(out *LogSampling) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogSampling.
+func (in *LogSampling) DeepCopy() *LogSampling {
+	if in == nil {
+		return nil
+	}
+	out := new(LogSampling)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogSeverityRoute) DeepCopyInto(out *LogSeverityRoute) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogSeverityRoute.
+func (in *LogSeverityRoute) DeepCopy() *LogSeverityRoute {
+	if in == nil {
+		return nil
+	}
+	out := new(LogSeverityRoute)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MarklogicCluster) DeepCopyInto(out *MarklogicCluster) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MarklogicCluster.
+func (in *MarklogicCluster) DeepCopy() *MarklogicCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(MarklogicCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MarklogicCluster) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MarklogicClusterClone) DeepCopyInto(out *MarklogicClusterClone) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MarklogicClusterClone.
+func (in *MarklogicClusterClone) DeepCopy() *MarklogicClusterClone {
+	if in == nil {
+		return nil
+	}
+	out := new(MarklogicClusterClone)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MarklogicClusterClone) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MarklogicClusterCloneList) DeepCopyInto(out *MarklogicClusterCloneList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]MarklogicClusterClone, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MarklogicClusterCloneList.
+func (in *MarklogicClusterCloneList) DeepCopy() *MarklogicClusterCloneList {
+	if in == nil {
+		return nil
+	}
+	out := new(MarklogicClusterCloneList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MarklogicClusterCloneList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MarklogicClusterCloneSpec) DeepCopyInto(out *MarklogicClusterCloneSpec) {
+	*out = *in
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]CloneGroup, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MarklogicClusterCloneSpec.
+func (in *MarklogicClusterCloneSpec) DeepCopy() *MarklogicClusterCloneSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MarklogicClusterCloneSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MarklogicClusterCloneStatus) DeepCopyInto(out *MarklogicClusterCloneStatus) {
+	*out = *in
+	if in.Snapshots != nil {
+		in, out := &in.Snapshots, &out.Snapshots
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RestoredDatabases != nil {
+		in, out := &in.RestoredDatabases, &out.RestoredDatabases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MarklogicClusterCloneStatus.
+func (in *MarklogicClusterCloneStatus) DeepCopy() *MarklogicClusterCloneStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MarklogicClusterCloneStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MarklogicClusterList) DeepCopyInto(out *MarklogicClusterList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]MarklogicCluster, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MarklogicClusterList.
+func (in *MarklogicClusterList) DeepCopy() *MarklogicClusterList {
+	if in == nil {
+		return nil
+	}
+	out := new(MarklogicClusterList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MarklogicClusterList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MarklogicClusterSpec) DeepCopyInto(out *MarklogicClusterSpec) {
+	*out = *in
+	if in.AdditionalLabels != nil {
+		in, out := &in.AdditionalLabels, &out.AdditionalLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.AdditionalAnnotations != nil {
+		in, out := &in.AdditionalAnnotations, &out.AdditionalAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]corev1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.Auth != nil {
+		in, out := &in.Auth, &out.Auth
+		*out = new(AdminAuth)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AutomountServiceAccountToken != nil {
+		in, out := &in.AutomountServiceAccountToken, &out.AutomountServiceAccountToken
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Persistence != nil {
+		in, out := &in.Persistence, &out.Persistence
+		*out = new(Persistence)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TerminationGracePeriodSeconds != nil {
+		in, out := &in.TerminationGracePeriodSeconds, &out.TerminationGracePeriodSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Drain != nil {
+		in, out := &in.Drain, &out.Drain
+		*out = new(ShutdownDrain)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HostJoin != nil {
+		in, out := &in.HostJoin, &out.HostJoin
+		*out = new(HostJoin)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodRemediation != nil {
+		in, out := &in.PodRemediation, &out.PodRemediation
+		*out = new(PodRemediation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StartupProbe != nil {
+		in, out := &in.StartupProbe, &out.StartupProbe
+		*out = new(StartupProbe)
+		(*in).DeepCopyInto(*out)
+	}
+	in.NetworkPolicy.DeepCopyInto(&out.NetworkPolicy)
+	if in.PodSecurityContext != nil {
+		in, out := &in.PodSecurityContext, &out.PodSecurityContext
+		*out = new(corev1.PodSecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ContainerSecurityContext != nil {
+		in, out := &in.ContainerSecurityContext, &out.ContainerSecurityContext
+		*out = new(corev1.SecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(corev1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.TopologySpreadConstraints != nil {
+		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
+		*out = make([]corev1.TopologySpreadConstraint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.License != nil {
+		in, out := &in.License, &out.License
+		*out = new(License)
+		**out = **in
+	}
+	if in.HugePages != nil {
+		in, out := &in.HugePages, &out.HugePages
+		*out = new(HugePages)
+		**out = **in
+	}
+	if in.ReadOnlyRootFilesystem != nil {
+		in, out := &in.ReadOnlyRootFilesystem, &out.ReadOnlyRootFilesystem
+		*out = new(ReadOnlyRootFilesystem)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LogCollection != nil {
+		in, out := &in.LogCollection, &out.LogCollection
+		*out = new(LogCollection)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HAProxy != nil {
+		in, out := &in.HAProxy, &out.HAProxy
+		*out = new(HAProxy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tls != nil {
+		in, out := &in.Tls, &out.Tls
+		*out = new(Tls)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AdditionalVolumes != nil {
+		in, out := &in.AdditionalVolumes, &out.AdditionalVolumes
+		*out = new([]corev1.Volume)
+		if **in != nil {
+			in, out := *in, *out
+			*out = make([]corev1.Volume, len(*in))
+			for i := range *in {
+				(*in)[i].DeepCopyInto(&(*out)[i])
+			}
+		}
+	}
+	if in.AdditionalVolumeMounts != nil {
+		in, out := &in.AdditionalVolumeMounts, &out.AdditionalVolumeMounts
+		*out = new([]corev1.VolumeMount)
+		if **in != nil {
+			in, out := *in, *out
+			*out = make([]corev1.VolumeMount, len(*in))
+			for i := range *in {
+				(*in)[i].DeepCopyInto(&(*out)[i])
+			}
+		}
+	}
+	if in.AdditionalVolumeClaimTemplates != nil {
+		in, out := &in.AdditionalVolumeClaimTemplates, &out.AdditionalVolumeClaimTemplates
+		*out = new([]corev1.PersistentVolumeClaim)
+		if **in != nil {
+			in, out := *in, *out
+			*out = make([]corev1.PersistentVolumeClaim, len(*in))
+			for i := range *in {
+				(*in)[i].DeepCopyInto(&(*out)[i])
+			}
+		}
+	}
+	if in.Backup != nil {
+		in, out := &in.Backup, &out.Backup
+		*out = new(Backup)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TieredStorage != nil {
+		in, out := &in.TieredStorage, &out.TieredStorage
+		*out = new(TieredStorage)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Replication != nil {
+		in, out := &in.Replication, &out.Replication
+		*out = new(ReplicationCheck)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HealthReport != nil {
+		in, out := &in.HealthReport, &out.HealthReport
+		*out = new(HealthReport)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeDrain != nil {
+		in, out := &in.NodeDrain, &out.NodeDrain
+		*out = new(NodeDrain)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Bootstrap != nil {
+		in, out := &in.Bootstrap, &out.Bootstrap
+		*out = new(Bootstrap)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Modules != nil {
+		in, out := &in.Modules, &out.Modules
+		*out = new(Modules)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NetworkAccess != nil {
+		in, out := &in.NetworkAccess, &out.NetworkAccess
+		*out = new(NetworkAccess)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Upgrade != nil {
+		in, out := &in.Upgrade, &out.Upgrade
+		*out = new(UpgradeSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Hibernation != nil {
+		in, out := &in.Hibernation, &out.Hibernation
+		*out = new(Hibernation)
+		**out = **in
+	}
+	if in.SupportBundle != nil {
+		in, out := &in.SupportBundle, &out.SupportBundle
+		*out = new(SupportBundle)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HostRenames != nil {
+		in, out := &in.HostRenames, &out.HostRenames
+		*out = make([]HostRename, len(*in))
+		copy(*out, *in)
+	}
+	if in.Operations != nil {
+		in, out := &in.Operations, &out.Operations
+		*out = new(Operations)
+		**out = **in
+	}
+	if in.MarkLogicGroups != nil {
+		in, out := &in.MarkLogicGroups, &out.MarkLogicGroups
+		*out = make([]*MarklogicGroups, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(MarklogicGroups)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MarklogicClusterSpec.
+func (in *MarklogicClusterSpec) DeepCopy() *MarklogicClusterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MarklogicClusterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MarklogicClusterStatus) DeepCopyInto(out *MarklogicClusterStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Backup != nil {
+		in, out := &in.Backup, &out.Backup
+		*out = new(BackupStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Upgrade != nil {
+		in, out := &in.Upgrade, &out.Upgrade
+		*out = new(UpgradeStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ResourceRollout != nil {
+		in, out := &in.ResourceRollout, &out.ResourceRollout
+		*out = new(ResourceRolloutStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HostRestart != nil {
+		in, out := &in.HostRestart, &out.HostRestart
+		*out = new(HostRestartStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.WarmUp != nil {
+		in, out := &in.WarmUp, &out.WarmUp
+		*out = make([]GroupWarmUpStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Run != nil {
+		in, out := &in.Run, &out.Run
+		*out = new(ClusterRunStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Hibernation != nil {
+		in, out := &in.Hibernation, &out.Hibernation
+		*out = new(HibernationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Capacity != nil {
+		in, out := &in.Capacity, &out.Capacity
+		*out = new(CapacityStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = new(ClusterHostsStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HostZones != nil {
+		in, out := &in.HostZones, &out.HostZones
+		*out = make([]HostZone, len(*in))
+		copy(*out, *in)
+	}
+	if in.HostRecovery != nil {
+		in, out := &in.HostRecovery, &out.HostRecovery
+		*out = make([]HostRecoveryStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Quorum != nil {
+		in, out := &in.Quorum, &out.Quorum
+		*out = new(QuorumStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Diagnostics != nil {
+		in, out := &in.Diagnostics, &out.Diagnostics
+		*out = make([]DiagnosticsStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AppServerSettings != nil {
+		in, out := &in.AppServerSettings, &out.AppServerSettings
+		*out = make([]AppServerSettingsStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.FIPS != nil {
+		in, out := &in.FIPS, &out.FIPS
+		*out = new(FIPSStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ChangeLog != nil {
+		in, out := &in.ChangeLog, &out.ChangeLog
+		*out = new(ChangeLogStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TieredStorage != nil {
+		in, out := &in.TieredStorage, &out.TieredStorage
+		*out = new(TieredStorageStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Replication != nil {
+		in, out := &in.Replication, &out.Replication
+		*out = new(ReplicationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HealthReport != nil {
+		in, out := &in.HealthReport, &out.HealthReport
+		*out = new(HealthReportStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeDrain != nil {
+		in, out := &in.NodeDrain, &out.NodeDrain
+		*out = new(NodeDrainStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Bootstrap != nil {
+		in, out := &in.Bootstrap, &out.Bootstrap
+		*out = new(BootstrapStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Modules != nil {
+		in, out := &in.Modules, &out.Modules
+		*out = new(ModulesStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Export != nil {
+		in, out := &in.Export, &out.Export
+		*out = new(ExportStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SupportBundle != nil {
+		in, out := &in.SupportBundle, &out.SupportBundle
+		*out = new(SupportBundleStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MarklogicClusterStatus.
+func (in *MarklogicClusterStatus) DeepCopy() *MarklogicClusterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MarklogicClusterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MarklogicContentJob) DeepCopyInto(out *MarklogicContentJob) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MarklogicContentJob.
+func (in *MarklogicContentJob) DeepCopy() *MarklogicContentJob {
+	if in == nil {
+		return nil
+	}
+	out := new(MarklogicContentJob)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MarklogicContentJob) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MarklogicContentJobList) DeepCopyInto(out *MarklogicContentJobList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]MarklogicContentJob, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MarklogicContentJobList.
+func (in *MarklogicContentJobList) DeepCopy() *MarklogicContentJobList {
+	if in == nil {
+		return nil
+	}
+	out := new(MarklogicContentJobList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MarklogicContentJobList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MarklogicContentJobSpec) DeepCopyInto(out *MarklogicContentJobSpec) {
+	*out = *in
+	if in.Target != nil {
+		in, out := &in.Target, &out.Target
+		*out = new(ContentJobTarget)
+		**out = **in
+	}
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Retry != nil {
+		in, out := &in.Retry, &out.Retry
+		*out = new(ContentJobRetry)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MarklogicContentJobSpec.
+func (in *MarklogicContentJobSpec) DeepCopy() *MarklogicContentJobSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MarklogicContentJobSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MarklogicContentJobStatus) DeepCopyInto(out *MarklogicContentJobStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MarklogicContentJobStatus.
+func (in *MarklogicContentJobStatus) DeepCopy() *MarklogicContentJobStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MarklogicContentJobStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MarklogicGroup) DeepCopyInto(out *MarklogicGroup) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MarklogicGroup.
+func (in *MarklogicGroup) DeepCopy() *MarklogicGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(MarklogicGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MarklogicGroup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MarklogicGroupList) DeepCopyInto(out *MarklogicGroupList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]MarklogicGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MarklogicGroupList.
+func (in *MarklogicGroupList) DeepCopy() *MarklogicGroupList {
+	if in == nil {
+		return nil
+	}
+	out := new(MarklogicGroupList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MarklogicGroupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MarklogicGroupSpec) DeepCopyInto(out *MarklogicGroupSpec) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.AdditionalLabels != nil {
+		in, out := &in.AdditionalLabels, &out.AdditionalLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.AdditionalAnnotations != nil {
+		in, out := &in.AdditionalAnnotations, &out.AdditionalAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]corev1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.Auth != nil {
+		in, out := &in.Auth, &out.Auth
+		*out = new(AdminAuth)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AutomountServiceAccountToken != nil {
+		in, out := &in.AutomountServiceAccountToken, &out.AutomountServiceAccountToken
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Persistence != nil {
+		in, out := &in.Persistence, &out.Persistence
+		*out = new(Persistence)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TerminationGracePeriodSeconds != nil {
+		in, out := &in.TerminationGracePeriodSeconds, &out.TerminationGracePeriodSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Drain != nil {
+		in, out := &in.Drain, &out.Drain
+		*out = new(ShutdownDrain)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HostJoin != nil {
+		in, out := &in.HostJoin, &out.HostJoin
+		*out = new(HostJoin)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StartupProbe != nil {
+		in, out := &in.StartupProbe, &out.StartupProbe
+		*out = new(StartupProbe)
+		(*in).DeepCopyInto(*out)
+	}
+	in.NetworkPolicy.DeepCopyInto(&out.NetworkPolicy)
+	if in.PodSecurityContext != nil {
+		in, out := &in.PodSecurityContext, &out.PodSecurityContext
+		*out = new(corev1.PodSecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ContainerSecurityContext != nil {
+		in, out := &in.ContainerSecurityContext, &out.ContainerSecurityContext
+		*out = new(corev1.SecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(corev1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.TopologySpreadConstraints != nil {
+		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
+		*out = make([]corev1.TopologySpreadConstraint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.HugePages != nil {
+		in, out := &in.HugePages, &out.HugePages
+		*out = new(HugePages)
+		**out = **in
+	}
+	if in.ReadOnlyRootFilesystem != nil {
+		in, out := &in.ReadOnlyRootFilesystem, &out.ReadOnlyRootFilesystem
+		*out = new(ReadOnlyRootFilesystem)
+		(*in).DeepCopyInto(*out)
+	}
+	out.LivenessProbe = in.LivenessProbe
+	out.ReadinessProbe = in.ReadinessProbe
+	if in.LogCollection != nil {
+		in, out := &in.LogCollection, &out.LogCollection
+		*out = new(LogCollection)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GroupConfig != nil {
+		in, out := &in.GroupConfig, &out.GroupConfig
+		*out = new(GroupConfig)
+		**out = **in
+	}
+	if in.Dynamic != nil {
+		in, out := &in.Dynamic, &out.Dynamic
+		*out = new(DynamicGroupConfig)
+		**out = **in
+	}
+	if in.License != nil {
+		in, out := &in.License, &out.License
+		*out = new(License)
+		**out = **in
+	}
+	if in.DoNotDelete != nil {
+		in, out := &in.DoNotDelete, &out.DoNotDelete
+		*out = new(bool)
+		**out = **in
+	}
+	in.Service.DeepCopyInto(&out.Service)
+	if in.AdditionalVolumes != nil {
+		in, out := &in.AdditionalVolumes, &out.AdditionalVolumes
+		*out = new([]corev1.Volume)
+		if **in != nil {
+			in, out := *in, *out
+			*out = make([]corev1.Volume, len(*in))
+			for i := range *in {
+				(*in)[i].DeepCopyInto(&(*out)[i])
+			}
+		}
+	}
+	if in.AdditionalVolumeMounts != nil {
+		in, out := &in.AdditionalVolumeMounts, &out.AdditionalVolumeMounts
+		*out = new([]corev1.VolumeMount)
+		if **in != nil {
+			in, out := *in, *out
+			*out = make([]corev1.VolumeMount, len(*in))
+			for i := range *in {
+				(*in)[i].DeepCopyInto(&(*out)[i])
+			}
+		}
+	}
+	if in.AdditionalVolumeClaimTemplates != nil {
+		in, out := &in.AdditionalVolumeClaimTemplates, &out.AdditionalVolumeClaimTemplates
+		*out = new([]corev1.PersistentVolumeClaim)
+		if **in != nil {
+			in, out := *in, *out
+			*out = make([]corev1.PersistentVolumeClaim, len(*in))
+			for i := range *in {
+				(*in)[i].DeepCopyInto(&(*out)[i])
+			}
+		}
+	}
+	if in.VeleroHooks != nil {
+		in, out := &in.VeleroHooks, &out.VeleroHooks
+		*out = new(VeleroHooks)
+		**out = **in
+	}
+	if in.Tls != nil {
+		in, out := &in.Tls, &out.Tls
+		*out = new(Tls)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HostRenames != nil {
+		in, out := &in.HostRenames, &out.HostRenames
+		*out = make([]HostRename, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MarklogicGroupSpec.
+func (in *MarklogicGroupSpec) DeepCopy() *MarklogicGroupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MarklogicGroupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MarklogicGroupStatus) DeepCopyInto(out *MarklogicGroupStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.MarkLogicPods != nil {
+		in, out := &in.MarkLogicPods, &out.MarkLogicPods
+		*out = make([]corev1.ObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.VolumeResizeStatus != nil {
+		in, out := &in.VolumeResizeStatus, &out.VolumeResizeStatus
+		*out = new(VolumeResizeStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Dynamic != nil {
+		in, out := &in.Dynamic, &out.Dynamic
+		*out = new(DynamicGroupStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretRotation != nil {
+		in, out := &in.SecretRotation, &out.SecretRotation
+		*out = new(SecretRotationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]HostStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.HostsUpdateTime != nil {
+		in, out := &in.HostsUpdateTime, &out.HostsUpdateTime
+		*out = (*in).DeepCopy()
+	}
+	if in.VolumeBindings != nil {
+		in, out := &in.VolumeBindings, &out.VolumeBindings
+		*out = make([]VolumeNodeBinding, len(*in))
+		copy(*out, *in)
+	}
+	if in.StorageMigration != nil {
+		in, out := &in.StorageMigration, &out.StorageMigration
+		*out = new(StorageMigrationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MarklogicGroupStatus.
+func (in *MarklogicGroupStatus) DeepCopy() *MarklogicGroupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MarklogicGroupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MarklogicGroups) DeepCopyInto(out *MarklogicGroups) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.GroupConfig != nil {
+		in, out := &in.GroupConfig, &out.GroupConfig
+		*out = new(GroupConfig)
+		**out = **in
+	}
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]corev1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.Persistence != nil {
+		in, out := &in.Persistence, &out.Persistence
+		*out = new(Persistence)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Service.DeepCopyInto(&out.Service)
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(corev1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TopologySpreadConstraints != nil {
+		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
+		*out = make([]corev1.TopologySpreadConstraint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.HugePages != nil {
+		in, out := &in.HugePages, &out.HugePages
+		*out = new(HugePages)
+		**out = **in
+	}
+	if in.ReadOnlyRootFilesystem != nil {
+		in, out := &in.ReadOnlyRootFilesystem, &out.ReadOnlyRootFilesystem
+		*out = new(ReadOnlyRootFilesystem)
+		(*in).DeepCopyInto(*out)
+	}
+	out.LivenessProbe = in.LivenessProbe
+	out.ReadinessProbe = in.ReadinessProbe
+	if in.LogCollection != nil {
+		in, out := &in.LogCollection, &out.LogCollection
+		*out = new(LogCollection)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HAProxy != nil {
+		in, out := &in.HAProxy, &out.HAProxy
+		*out = new(HAProxyGroup)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Drain != nil {
+		in, out := &in.Drain, &out.Drain
+		*out = new(ShutdownDrain)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HostJoin != nil {
+		in, out := &in.HostJoin, &out.HostJoin
+		*out = new(HostJoin)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodRemediation != nil {
+		in, out := &in.PodRemediation, &out.PodRemediation
+		*out = new(PodRemediation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StartupProbe != nil {
+		in, out := &in.StartupProbe, &out.StartupProbe
+		*out = new(StartupProbe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Forests != nil {
+		in, out := &in.Forests, &out.Forests
+		*out = new(ForestProvisioning)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ScaleUp != nil {
+		in, out := &in.ScaleUp, &out.ScaleUp
+		*out = new(ScaleUpPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ZoneAwareness != nil {
+		in, out := &in.ZoneAwareness, &out.ZoneAwareness
+		*out = new(ZoneAwareness)
+		**out = **in
+	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(GroupAutoscaling)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Diagnostics != nil {
+		in, out := &in.Diagnostics, &out.Diagnostics
+		*out = new(Diagnostics)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AppServerSettings != nil {
+		in, out := &in.AppServerSettings, &out.AppServerSettings
+		*out = make([]AppServerSettings, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Dynamic != nil {
+		in, out := &in.Dynamic, &out.Dynamic
+		*out = new(DynamicGroupConfig)
+		**out = **in
+	}
+	if in.Tls != nil {
+		in, out := &in.Tls, &out.Tls
+		*out = new(Tls)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AdditionalVolumes != nil {
+		in, out := &in.AdditionalVolumes, &out.AdditionalVolumes
+		*out = new([]corev1.Volume)
+		if **in != nil {
+			in, out := *in, *out
+			*out = make([]corev1.Volume, len(*in))
+			for i := range *in {
+				(*in)[i].DeepCopyInto(&(*out)[i])
+			}
+		}
+	}
+	if in.AdditionalVolumeMounts != nil {
+		in, out := &in.AdditionalVolumeMounts, &out.AdditionalVolumeMounts
+		*out = new([]corev1.VolumeMount)
+		if **in != nil {
+			in, out := *in, *out
+			*out = make([]corev1.VolumeMount, len(*in))
+			for i := range *in {
+				(*in)[i].DeepCopyInto(&(*out)[i])
+			}
+		}
+	}
+	if in.AdditionalVolumeClaimTemplates != nil {
+		in, out := &in.AdditionalVolumeClaimTemplates, &out.AdditionalVolumeClaimTemplates
+		*out = new([]corev1.PersistentVolumeClaim)
+		if **in != nil {
+			in, out := *in, *out
+			*out = make([]corev1.PersistentVolumeClaim, len(*in))
+			for i := range *in {
+				(*in)[i].DeepCopyInto(&(*out)[i])
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MarklogicGroups.
+func (in *MarklogicGroups) DeepCopy() *MarklogicGroups {
+	if in == nil {
+		return nil
+	}
+	out := new(MarklogicGroups)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Modules) DeepCopyInto(out *Modules) {
+	*out = *in
+	if in.AppServers != nil {
+		in, out := &in.AppServers, &out.AppServers
+		*out = make([]ModulesAppServer, len(*in))
+		copy(*out, *in)
+	}
+	if in.OCI != nil {
+		in, out := &in.OCI, &out.OCI
+		*out = new(ModulesOCIArtifact)
+		**out = **in
+	}
+	if in.BackoffLimit != nil {
+		in, out := &in.BackoffLimit, &out.BackoffLimit
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Modules.
+func (in *Modules) DeepCopy() *Modules {
+	if in == nil {
+		return nil
+	}
+	out := new(Modules)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModulesAppServer) DeepCopyInto(out *ModulesAppServer) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModulesAppServer.
+func (in *ModulesAppServer) DeepCopy() *ModulesAppServer {
+	if in == nil {
+		return nil
+	}
+	out := new(ModulesAppServer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModulesOCIArtifact) DeepCopyInto(out *ModulesOCIArtifact) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModulesOCIArtifact.
+func (in *ModulesOCIArtifact) DeepCopy() *ModulesOCIArtifact {
+	if in == nil {
+		return nil
+	}
+	out := new(ModulesOCIArtifact)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModulesStatus) DeepCopyInto(out *ModulesStatus) {
+	*out = *in
+	if in.LoadedAt != nil {
+		in, out := &in.LoadedAt, &out.LoadedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModulesStatus.
+func (in *ModulesStatus) DeepCopy() *ModulesStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ModulesStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkAccess) DeepCopyInto(out *NetworkAccess) {
+	*out = *in
+	if in.AdminIngress != nil {
+		in, out := &in.AdminIngress, &out.AdminIngress
+		*out = new(AdminIngress)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkAccess.
+func (in *NetworkAccess) DeepCopy() *NetworkAccess {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkAccess)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkPolicy) DeepCopyInto(out *NetworkPolicy) {
+	*out = *in
+	if in.PolicyTypes != nil {
+		in, out := &in.PolicyTypes, &out.PolicyTypes
+		*out = make([]networkingv1.PolicyType, len(*in))
+		copy(*out, *in)
+	}
+	in.PodSelector.DeepCopyInto(&out.PodSelector)
+	if in.Ingress != nil {
+		in, out := &in.Ingress, &out.Ingress
+		*out = make([]networkingv1.NetworkPolicyIngressRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Egress != nil {
+		in, out := &in.Egress, &out.Egress
+		*out = make([]networkingv1.NetworkPolicyEgressRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPolicy.
+func (in *NetworkPolicy) DeepCopy() *NetworkPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeDrain) DeepCopyInto(out *NodeDrain) {
+	*out = *in
+	if in.FailoverTimeout != nil {
+		in, out := &in.FailoverTimeout, &out.FailoverTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeDrain.
+func (in *NodeDrain) DeepCopy() *NodeDrain {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeDrain)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeDrainStatus) DeepCopyInto(out *NodeDrainStatus) {
+	*out = *in
+	if in.Pods != nil {
+		in, out := &in.Pods, &out.Pods
+		*out = make([]DrainingPodStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeDrainStatus.
+func (in *NodeDrainStatus) DeepCopy() *NodeDrainStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeDrainStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Operations) DeepCopyInto(out *Operations) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Operations.
+func (in *Operations) DeepCopy() *Operations {
+	if in == nil {
+		return nil
+	}
+	out := new(Operations)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PVCResizeStatus) DeepCopyInto(out *PVCResizeStatus) {
+	*out = *in
+	if in.LastTransitionTime != nil {
+		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PVCResizeStatus.
+func (in *PVCResizeStatus) DeepCopy() *PVCResizeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PVCResizeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PVCRetentionPolicy) DeepCopyInto(out *PVCRetentionPolicy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PVCRetentionPolicy.
+func (in *PVCRetentionPolicy) DeepCopy() *PVCRetentionPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(PVCRetentionPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PartitionMigrationPolicy) DeepCopyInto(out *PartitionMigrationPolicy) {
+	*out = *in
+	out.OlderThan = in.OlderThan
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PartitionMigrationPolicy.
+func (in *PartitionMigrationPolicy) DeepCopy() *PartitionMigrationPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(PartitionMigrationPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PartitionTierStatus) DeepCopyInto(out *PartitionTierStatus) {
+	*out = *in
+	if in.MigrationTime != nil {
+		in, out := &in.MigrationTime, &out.MigrationTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PartitionTierStatus.
+func (in *PartitionTierStatus) DeepCopy() *PartitionTierStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PartitionTierStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Persistence) DeepCopyInto(out *Persistence) {
+	*out = *in
+	if in.RetentionPolicy != nil {
+		in, out := &in.RetentionPolicy, &out.RetentionPolicy
+		*out = new(PVCRetentionPolicy)
+		**out = **in
+	}
+	if in.Tiers != nil {
+		in, out := &in.Tiers, &out.Tiers
+		*out = make([]StorageTier, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AccessModes != nil {
+		in, out := &in.AccessModes, &out.AccessModes
+		*out = make([]corev1.PersistentVolumeAccessMode, len(*in))
+		copy(*out, *in)
+	}
 	if in.Annotations != nil {
 		in, out := &in.Annotations, &out.Annotations
 		*out = make(map[string]string, len(*in))
@@ -708,199 +3276,417 @@ func (in *MarklogicGroupSpec) DeepCopyInto(out *MarklogicGroupSpec) {
 			(*out)[key] = val
 		}
 	}
-	if in.ImagePullSecrets != nil {
-		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
-		*out = make([]corev1.LocalObjectReference, len(*in))
-		copy(*out, *in)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Persistence.
+func (in *Persistence) DeepCopy() *Persistence {
+	if in == nil {
+		return nil
+	}
+	out := new(Persistence)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodRemediation) DeepCopyInto(out *PodRemediation) {
+	*out = *in
+	if in.Threshold != nil {
+		in, out := &in.Threshold, &out.Threshold
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodRemediation.
+func (in *PodRemediation) DeepCopy() *PodRemediation {
+	if in == nil {
+		return nil
+	}
+	out := new(PodRemediation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrecheckResult) DeepCopyInto(out *PrecheckResult) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrecheckResult.
+func (in *PrecheckResult) DeepCopy() *PrecheckResult {
+	if in == nil {
+		return nil
+	}
+	out := new(PrecheckResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrecheckSpec) DeepCopyInto(out *PrecheckSpec) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrecheckSpec.
+func (in *PrecheckSpec) DeepCopy() *PrecheckSpec {
+	if in == nil {
+		return nil
 	}
-	if in.Auth != nil {
-		in, out := &in.Auth, &out.Auth
-		*out = new(AdminAuth)
-		(*in).DeepCopyInto(*out)
+	out := new(PrecheckSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QuorumStatus) DeepCopyInto(out *QuorumStatus) {
+	*out = *in
+	if in.LostSince != nil {
+		in, out := &in.LostSince, &out.LostSince
+		*out = (*in).DeepCopy()
 	}
-	if in.AutomountServiceAccountToken != nil {
-		in, out := &in.AutomountServiceAccountToken, &out.AutomountServiceAccountToken
-		*out = new(bool)
-		**out = **in
+	if in.LastCheckTime != nil {
+		in, out := &in.LastCheckTime, &out.LastCheckTime
+		*out = (*in).DeepCopy()
 	}
-	if in.Persistence != nil {
-		in, out := &in.Persistence, &out.Persistence
-		*out = new(Persistence)
-		(*in).DeepCopyInto(*out)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuorumStatus.
+func (in *QuorumStatus) DeepCopy() *QuorumStatus {
+	if in == nil {
+		return nil
 	}
-	if in.Resources != nil {
-		in, out := &in.Resources, &out.Resources
-		*out = new(corev1.ResourceRequirements)
-		(*in).DeepCopyInto(*out)
+	out := new(QuorumStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReadOnlyRootFilesystem) DeepCopyInto(out *ReadOnlyRootFilesystem) {
+	*out = *in
+	if in.WritablePaths != nil {
+		in, out := &in.WritablePaths, &out.WritablePaths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.TerminationGracePeriodSeconds != nil {
-		in, out := &in.TerminationGracePeriodSeconds, &out.TerminationGracePeriodSeconds
-		*out = new(int64)
-		**out = **in
+	if in.SizeLimit != nil {
+		in, out := &in.SizeLimit, &out.SizeLimit
+		x := (*in).DeepCopy()
+		*out = &x
 	}
-	in.NetworkPolicy.DeepCopyInto(&out.NetworkPolicy)
-	if in.PodSecurityContext != nil {
-		in, out := &in.PodSecurityContext, &out.PodSecurityContext
-		*out = new(corev1.PodSecurityContext)
-		(*in).DeepCopyInto(*out)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReadOnlyRootFilesystem.
+func (in *ReadOnlyRootFilesystem) DeepCopy() *ReadOnlyRootFilesystem {
+	if in == nil {
+		return nil
 	}
-	if in.ContainerSecurityContext != nil {
-		in, out := &in.ContainerSecurityContext, &out.ContainerSecurityContext
-		*out = new(corev1.SecurityContext)
-		(*in).DeepCopyInto(*out)
+	out := new(ReadOnlyRootFilesystem)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicationCheck) DeepCopyInto(out *ReplicationCheck) {
+	*out = *in
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.Affinity != nil {
-		in, out := &in.Affinity, &out.Affinity
-		*out = new(corev1.Affinity)
-		(*in).DeepCopyInto(*out)
+	if in.MaxLag != nil {
+		in, out := &in.MaxLag, &out.MaxLag
+		*out = new(metav1.Duration)
+		**out = **in
 	}
-	if in.NodeSelector != nil {
-		in, out := &in.NodeSelector, &out.NodeSelector
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(metav1.Duration)
+		**out = **in
 	}
-	if in.TopologySpreadConstraints != nil {
-		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
-		*out = make([]corev1.TopologySpreadConstraint, len(*in))
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicationCheck.
+func (in *ReplicationCheck) DeepCopy() *ReplicationCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicationCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicationStatus) DeepCopyInto(out *ReplicationStatus) {
+	*out = *in
+	if in.ForeignClusters != nil {
+		in, out := &in.ForeignClusters, &out.ForeignClusters
+		*out = make([]ForeignClusterReplicationStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]DatabaseReplicationStatus, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.HugePages != nil {
-		in, out := &in.HugePages, &out.HugePages
-		*out = new(HugePages)
-		**out = **in
+	if in.LastCheckTime != nil {
+		in, out := &in.LastCheckTime, &out.LastCheckTime
+		*out = (*in).DeepCopy()
 	}
-	out.LivenessProbe = in.LivenessProbe
-	out.ReadinessProbe = in.ReadinessProbe
-	if in.LogCollection != nil {
-		in, out := &in.LogCollection, &out.LogCollection
-		*out = new(LogCollection)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicationStatus.
+func (in *ReplicationStatus) DeepCopy() *ReplicationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceCapacity) DeepCopyInto(out *ResourceCapacity) {
+	*out = *in
+	out.CPURequests = in.CPURequests.DeepCopy()
+	out.MemoryRequests = in.MemoryRequests.DeepCopy()
+	if in.CPUUsage != nil {
+		in, out := &in.CPUUsage, &out.CPUUsage
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.MemoryUsage != nil {
+		in, out := &in.MemoryUsage, &out.MemoryUsage
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	out.StorageRequests = in.StorageRequests.DeepCopy()
+	out.StorageCapacity = in.StorageCapacity.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceCapacity.
+func (in *ResourceCapacity) DeepCopy() *ResourceCapacity {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceCapacity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceRolloutStatus) DeepCopyInto(out *ResourceRolloutStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.PodRestart != nil {
+		in, out := &in.PodRestart, &out.PodRestart
+		*out = new(UpgradePodRestart)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.GroupConfig != nil {
-		in, out := &in.GroupConfig, &out.GroupConfig
-		*out = new(GroupConfig)
-		**out = **in
+	if in.PodHold != nil {
+		in, out := &in.PodHold, &out.PodHold
+		*out = new(UpgradePodHold)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.Dynamic != nil {
-		in, out := &in.Dynamic, &out.Dynamic
-		*out = new(DynamicGroupConfig)
-		**out = **in
+	if in.Revisions != nil {
+		in, out := &in.Revisions, &out.Revisions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.License != nil {
-		in, out := &in.License, &out.License
-		*out = new(License)
-		**out = **in
+	if in.SettlingUntil != nil {
+		in, out := &in.SettlingUntil, &out.SettlingUntil
+		*out = (*in).DeepCopy()
 	}
-	if in.DoNotDelete != nil {
-		in, out := &in.DoNotDelete, &out.DoNotDelete
-		*out = new(bool)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceRolloutStatus.
+func (in *ResourceRolloutStatus) DeepCopy() *ResourceRolloutStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceRolloutStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RestartBatching) DeepCopyInto(out *RestartBatching) {
+	*out = *in
+	if in.SettleWindow != nil {
+		in, out := &in.SettleWindow, &out.SettleWindow
+		*out = new(metav1.Duration)
 		**out = **in
 	}
-	in.Service.DeepCopyInto(&out.Service)
-	if in.AdditionalVolumes != nil {
-		in, out := &in.AdditionalVolumes, &out.AdditionalVolumes
-		*out = new([]corev1.Volume)
-		if **in != nil {
-			in, out := *in, *out
-			*out = make([]corev1.Volume, len(*in))
-			for i := range *in {
-				(*in)[i].DeepCopyInto(&(*out)[i])
-			}
-		}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestartBatching.
+func (in *RestartBatching) DeepCopy() *RestartBatching {
+	if in == nil {
+		return nil
 	}
-	if in.AdditionalVolumeMounts != nil {
-		in, out := &in.AdditionalVolumeMounts, &out.AdditionalVolumeMounts
-		*out = new([]corev1.VolumeMount)
-		if **in != nil {
-			in, out := *in, *out
-			*out = make([]corev1.VolumeMount, len(*in))
-			for i := range *in {
-				(*in)[i].DeepCopyInto(&(*out)[i])
-			}
-		}
+	out := new(RestartBatching)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RestoreStatus) DeepCopyInto(out *RestoreStatus) {
+	*out = *in
+	if in.RestoreToTime != nil {
+		in, out := &in.RestoreToTime, &out.RestoreToTime
+		*out = (*in).DeepCopy()
 	}
-	if in.AdditionalVolumeClaimTemplates != nil {
-		in, out := &in.AdditionalVolumeClaimTemplates, &out.AdditionalVolumeClaimTemplates
-		*out = new([]corev1.PersistentVolumeClaim)
-		if **in != nil {
-			in, out := *in, *out
-			*out = make([]corev1.PersistentVolumeClaim, len(*in))
-			for i := range *in {
-				(*in)[i].DeepCopyInto(&(*out)[i])
-			}
-		}
+	if in.Forests != nil {
+		in, out := &in.Forests, &out.Forests
+		*out = make([]ForestRestoreStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestoreStatus.
+func (in *RestoreStatus) DeepCopy() *RestoreStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RestoreStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *S3BackupStorage) DeepCopyInto(out *S3BackupStorage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3BackupStorage.
+func (in *S3BackupStorage) DeepCopy() *S3BackupStorage {
+	if in == nil {
+		return nil
+	}
+	out := new(S3BackupStorage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScaleUpPolicy) DeepCopyInto(out *ScaleUpPolicy) {
+	*out = *in
+	if in.WarmUpWindow != nil {
+		in, out := &in.WarmUpWindow, &out.WarmUpWindow
+		*out = new(metav1.Duration)
+		**out = **in
 	}
-	if in.Tls != nil {
-		in, out := &in.Tls, &out.Tls
-		*out = new(Tls)
-		(*in).DeepCopyInto(*out)
+	if in.RebalancerThrottle != nil {
+		in, out := &in.RebalancerThrottle, &out.RebalancerThrottle
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MarklogicGroupSpec.
-func (in *MarklogicGroupSpec) DeepCopy() *MarklogicGroupSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScaleUpPolicy.
+func (in *ScaleUpPolicy) DeepCopy() *ScaleUpPolicy {
 	if in == nil {
 		return nil
 	}
-	out := new(MarklogicGroupSpec)
+	out := new(ScaleUpPolicy)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MarklogicGroupStatus) DeepCopyInto(out *MarklogicGroupStatus) {
+func (in *ScheduledBackupStatus) DeepCopyInto(out *ScheduledBackupStatus) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]metav1.Condition, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
 	}
-	if in.MarkLogicPods != nil {
-		in, out := &in.MarkLogicPods, &out.MarkLogicPods
-		*out = make([]corev1.ObjectReference, len(*in))
+	if in.LastRefreshTime != nil {
+		in, out := &in.LastRefreshTime, &out.LastRefreshTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledBackupStatus.
+func (in *ScheduledBackupStatus) DeepCopy() *ScheduledBackupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduledBackupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretRotationStatus) DeepCopyInto(out *SecretRotationStatus) {
+	*out = *in
+	if in.TLSRestartPending != nil {
+		in, out := &in.TLSRestartPending, &out.TLSRestartPending
+		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.VolumeResizeStatus != nil {
-		in, out := &in.VolumeResizeStatus, &out.VolumeResizeStatus
-		*out = new(VolumeResizeStatus)
-		(*in).DeepCopyInto(*out)
+	if in.LogCredentialsUpdateTime != nil {
+		in, out := &in.LogCredentialsUpdateTime, &out.LogCredentialsUpdateTime
+		*out = (*in).DeepCopy()
 	}
-	if in.Dynamic != nil {
-		in, out := &in.Dynamic, &out.Dynamic
-		*out = new(DynamicGroupStatus)
-		(*in).DeepCopyInto(*out)
+	if in.LogReloadPending != nil {
+		in, out := &in.LogReloadPending, &out.LogReloadPending
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MarklogicGroupStatus.
-func (in *MarklogicGroupStatus) DeepCopy() *MarklogicGroupStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretRotationStatus.
+func (in *SecretRotationStatus) DeepCopy() *SecretRotationStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(MarklogicGroupStatus)
+	out := new(SecretRotationStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MarklogicGroups) DeepCopyInto(out *MarklogicGroups) {
+func (in *Service) DeepCopyInto(out *Service) {
 	*out = *in
-	if in.Replicas != nil {
-		in, out := &in.Replicas, &out.Replicas
-		*out = new(int32)
-		**out = **in
-	}
-	if in.Labels != nil {
-		in, out := &in.Labels, &out.Labels
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
+	if in.AdditionalPorts != nil {
+		in, out := &in.AdditionalPorts, &out.AdditionalPorts
+		*out = make([]corev1.ServicePort, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 	if in.Annotations != nil {
@@ -910,340 +3696,567 @@ func (in *MarklogicGroups) DeepCopyInto(out *MarklogicGroups) {
 			(*out)[key] = val
 		}
 	}
-	if in.GroupConfig != nil {
-		in, out := &in.GroupConfig, &out.GroupConfig
-		*out = new(GroupConfig)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Service.
+func (in *Service) DeepCopy() *Service {
+	if in == nil {
+		return nil
+	}
+	out := new(Service)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ShutdownDrain) DeepCopyInto(out *ShutdownDrain) {
+	*out = *in
+	if in.Delay != nil {
+		in, out := &in.Delay, &out.Delay
+		*out = new(metav1.Duration)
 		**out = **in
 	}
-	if in.ImagePullSecrets != nil {
-		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
-		*out = make([]corev1.LocalObjectReference, len(*in))
-		copy(*out, *in)
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
 	}
-	if in.Persistence != nil {
-		in, out := &in.Persistence, &out.Persistence
-		*out = new(Persistence)
-		(*in).DeepCopyInto(*out)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ShutdownDrain.
+func (in *ShutdownDrain) DeepCopy() *ShutdownDrain {
+	if in == nil {
+		return nil
 	}
-	in.Service.DeepCopyInto(&out.Service)
-	if in.Resources != nil {
-		in, out := &in.Resources, &out.Resources
-		*out = new(corev1.ResourceRequirements)
-		(*in).DeepCopyInto(*out)
+	out := new(ShutdownDrain)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpecChangeEntry) DeepCopyInto(out *SpecChangeEntry) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpecChangeEntry.
+func (in *SpecChangeEntry) DeepCopy() *SpecChangeEntry {
+	if in == nil {
+		return nil
 	}
-	if in.Affinity != nil {
-		in, out := &in.Affinity, &out.Affinity
-		*out = new(corev1.Affinity)
-		(*in).DeepCopyInto(*out)
+	out := new(SpecChangeEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StartupProbe) DeepCopyInto(out *StartupProbe) {
+	*out = *in
+	if in.BootTimeout != nil {
+		in, out := &in.BootTimeout, &out.BootTimeout
+		*out = new(metav1.Duration)
+		**out = **in
 	}
-	if in.TopologySpreadConstraints != nil {
-		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
-		*out = make([]corev1.TopologySpreadConstraint, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StartupProbe.
+func (in *StartupProbe) DeepCopy() *StartupProbe {
+	if in == nil {
+		return nil
 	}
-	if in.NodeSelector != nil {
-		in, out := &in.NodeSelector, &out.NodeSelector
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	out := new(StartupProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Stats) DeepCopyInto(out *Stats) {
+	*out = *in
+	out.Auth = in.Auth
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Stats.
+func (in *Stats) DeepCopy() *Stats {
+	if in == nil {
+		return nil
 	}
-	if in.HugePages != nil {
-		in, out := &in.HugePages, &out.HugePages
-		*out = new(HugePages)
-		**out = **in
+	out := new(Stats)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StatsAuth) DeepCopyInto(out *StatsAuth) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StatsAuth.
+func (in *StatsAuth) DeepCopy() *StatsAuth {
+	if in == nil {
+		return nil
 	}
-	out.LivenessProbe = in.LivenessProbe
-	out.ReadinessProbe = in.ReadinessProbe
-	if in.LogCollection != nil {
-		in, out := &in.LogCollection, &out.LogCollection
-		*out = new(LogCollection)
-		(*in).DeepCopyInto(*out)
+	out := new(StatsAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageMigrationStatus) DeepCopyInto(out *StorageMigrationStatus) {
+	*out = *in
+	if in.MigratedPods != nil {
+		in, out := &in.MigratedPods, &out.MigratedPods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.HAProxy != nil {
-		in, out := &in.HAProxy, &out.HAProxy
-		*out = new(HAProxyGroup)
-		(*in).DeepCopyInto(*out)
+	if in.RetainedVolumes != nil {
+		in, out := &in.RetainedVolumes, &out.RetainedVolumes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.Dynamic != nil {
-		in, out := &in.Dynamic, &out.Dynamic
-		*out = new(DynamicGroupConfig)
-		**out = **in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
 	}
-	if in.Tls != nil {
-		in, out := &in.Tls, &out.Tls
-		*out = new(Tls)
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageMigrationStatus.
+func (in *StorageMigrationStatus) DeepCopy() *StorageMigrationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageMigrationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageTier) DeepCopyInto(out *StorageTier) {
+	*out = *in
+	out.Size = in.Size.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageTier.
+func (in *StorageTier) DeepCopy() *StorageTier {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageTier)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SupportBundle) DeepCopyInto(out *SupportBundle) {
+	*out = *in
+	if in.UploadURLSecret != nil {
+		in, out := &in.UploadURLSecret, &out.UploadURLSecret
+		*out = new(corev1.SecretKeySelector)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.AdditionalVolumes != nil {
-		in, out := &in.AdditionalVolumes, &out.AdditionalVolumes
-		*out = new([]corev1.Volume)
-		if **in != nil {
-			in, out := *in, *out
-			*out = make([]corev1.Volume, len(*in))
-			for i := range *in {
-				(*in)[i].DeepCopyInto(&(*out)[i])
-			}
-		}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SupportBundle.
+func (in *SupportBundle) DeepCopy() *SupportBundle {
+	if in == nil {
+		return nil
 	}
-	if in.AdditionalVolumeMounts != nil {
-		in, out := &in.AdditionalVolumeMounts, &out.AdditionalVolumeMounts
-		*out = new([]corev1.VolumeMount)
-		if **in != nil {
-			in, out := *in, *out
-			*out = make([]corev1.VolumeMount, len(*in))
-			for i := range *in {
-				(*in)[i].DeepCopyInto(&(*out)[i])
-			}
-		}
+	out := new(SupportBundle)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SupportBundleStatus) DeepCopyInto(out *SupportBundleStatus) {
+	*out = *in
+	if in.CollectionTime != nil {
+		in, out := &in.CollectionTime, &out.CollectionTime
+		*out = (*in).DeepCopy()
 	}
-	if in.AdditionalVolumeClaimTemplates != nil {
-		in, out := &in.AdditionalVolumeClaimTemplates, &out.AdditionalVolumeClaimTemplates
-		*out = new([]corev1.PersistentVolumeClaim)
-		if **in != nil {
-			in, out := *in, *out
-			*out = make([]corev1.PersistentVolumeClaim, len(*in))
-			for i := range *in {
-				(*in)[i].DeepCopyInto(&(*out)[i])
-			}
-		}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SupportBundleStatus.
+func (in *SupportBundleStatus) DeepCopy() *SupportBundleStatus {
+	if in == nil {
+		return nil
 	}
+	out := new(SupportBundleStatus)
+	in.DeepCopyInto(out)
+	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MarklogicGroups.
-func (in *MarklogicGroups) DeepCopy() *MarklogicGroups {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TcpPort) DeepCopyInto(out *TcpPort) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TcpPort.
+func (in *TcpPort) DeepCopy() *TcpPort {
 	if in == nil {
 		return nil
 	}
-	out := new(MarklogicGroups)
+	out := new(TcpPort)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NetworkPolicy) DeepCopyInto(out *NetworkPolicy) {
+func (in *Tcpports) DeepCopyInto(out *Tcpports) {
 	*out = *in
-	if in.PolicyTypes != nil {
-		in, out := &in.PolicyTypes, &out.PolicyTypes
-		*out = make([]networkingv1.PolicyType, len(*in))
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]TcpPort, len(*in))
 		copy(*out, *in)
 	}
-	in.PodSelector.DeepCopyInto(&out.PodSelector)
-	if in.Ingress != nil {
-		in, out := &in.Ingress, &out.Ingress
-		*out = make([]networkingv1.NetworkPolicyIngressRule, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Tcpports.
+func (in *Tcpports) DeepCopy() *Tcpports {
+	if in == nil {
+		return nil
 	}
-	if in.Egress != nil {
-		in, out := &in.Egress, &out.Egress
-		*out = make([]networkingv1.NetworkPolicyEgressRule, len(*in))
+	out := new(Tcpports)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TieredStorage) DeepCopyInto(out *TieredStorage) {
+	*out = *in
+	if in.Policies != nil {
+		in, out := &in.Policies, &out.Policies
+		*out = make([]PartitionMigrationPolicy, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TieredStorage.
+func (in *TieredStorage) DeepCopy() *TieredStorage {
+	if in == nil {
+		return nil
+	}
+	out := new(TieredStorage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TieredStorageStatus) DeepCopyInto(out *TieredStorageStatus) {
+	*out = *in
+	if in.Partitions != nil {
+		in, out := &in.Partitions, &out.Partitions
+		*out = make([]PartitionTierStatus, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.LastCheckTime != nil {
+		in, out := &in.LastCheckTime, &out.LastCheckTime
+		*out = (*in).DeepCopy()
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPolicy.
-func (in *NetworkPolicy) DeepCopy() *NetworkPolicy {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TieredStorageStatus.
+func (in *TieredStorageStatus) DeepCopy() *TieredStorageStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(NetworkPolicy)
+	out := new(TieredStorageStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PVCResizeStatus) DeepCopyInto(out *PVCResizeStatus) {
+func (in *Timeout) DeepCopyInto(out *Timeout) {
 	*out = *in
-	if in.LastTransitionTime != nil {
-		in, out := &in.LastTransitionTime, &out.LastTransitionTime
-		*out = (*in).DeepCopy()
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PVCResizeStatus.
-func (in *PVCResizeStatus) DeepCopy() *PVCResizeStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Timeout.
+func (in *Timeout) DeepCopy() *Timeout {
 	if in == nil {
 		return nil
 	}
-	out := new(PVCResizeStatus)
+	out := new(Timeout)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Persistence) DeepCopyInto(out *Persistence) {
+func (in *Tls) DeepCopyInto(out *Tls) {
 	*out = *in
-	if in.AccessModes != nil {
-		in, out := &in.AccessModes, &out.AccessModes
-		*out = make([]corev1.PersistentVolumeAccessMode, len(*in))
+	if in.CertSecretNames != nil {
+		in, out := &in.CertSecretNames, &out.CertSecretNames
+		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.Annotations != nil {
-		in, out := &in.Annotations, &out.Annotations
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Tls.
+func (in *Tls) DeepCopy() *Tls {
+	if in == nil {
+		return nil
 	}
+	out := new(Tls)
+	in.DeepCopyInto(out)
+	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Persistence.
-func (in *Persistence) DeepCopy() *Persistence {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TlsForHAProxy) DeepCopyInto(out *TlsForHAProxy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TlsForHAProxy.
+func (in *TlsForHAProxy) DeepCopy() *TlsForHAProxy {
 	if in == nil {
 		return nil
 	}
-	out := new(Persistence)
+	out := new(TlsForHAProxy)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Service) DeepCopyInto(out *Service) {
+func (in *UpgradeApproval) DeepCopyInto(out *UpgradeApproval) {
 	*out = *in
-	if in.AdditionalPorts != nil {
-		in, out := &in.AdditionalPorts, &out.AdditionalPorts
-		*out = make([]corev1.ServicePort, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	in.Since.DeepCopyInto(&out.Since)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeApproval.
+func (in *UpgradeApproval) DeepCopy() *UpgradeApproval {
+	if in == nil {
+		return nil
 	}
-	if in.Annotations != nil {
-		in, out := &in.Annotations, &out.Annotations
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	out := new(UpgradeApproval)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradeImpact) DeepCopyInto(out *UpgradeImpact) {
+	*out = *in
+	if in.PodDowntime != nil {
+		in, out := &in.PodDowntime, &out.PodDowntime
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Service.
-func (in *Service) DeepCopy() *Service {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeImpact.
+func (in *UpgradeImpact) DeepCopy() *UpgradeImpact {
 	if in == nil {
 		return nil
 	}
-	out := new(Service)
+	out := new(UpgradeImpact)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Stats) DeepCopyInto(out *Stats) {
+func (in *UpgradePodHold) DeepCopyInto(out *UpgradePodHold) {
 	*out = *in
-	out.Auth = in.Auth
+	in.Since.DeepCopyInto(&out.Since)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Stats.
-func (in *Stats) DeepCopy() *Stats {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradePodHold.
+func (in *UpgradePodHold) DeepCopy() *UpgradePodHold {
 	if in == nil {
 		return nil
 	}
-	out := new(Stats)
+	out := new(UpgradePodHold)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *StatsAuth) DeepCopyInto(out *StatsAuth) {
+func (in *UpgradePodRestart) DeepCopyInto(out *UpgradePodRestart) {
 	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StatsAuth.
-func (in *StatsAuth) DeepCopy() *StatsAuth {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradePodRestart.
+func (in *UpgradePodRestart) DeepCopy() *UpgradePodRestart {
 	if in == nil {
 		return nil
 	}
-	out := new(StatsAuth)
+	out := new(UpgradePodRestart)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TcpPort) DeepCopyInto(out *TcpPort) {
+func (in *UpgradeSpec) DeepCopyInto(out *UpgradeSpec) {
 	*out = *in
+	if in.Prechecks != nil {
+		in, out := &in.Prechecks, &out.Prechecks
+		*out = make([]PrecheckSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.HealthGate != nil {
+		in, out := &in.HealthGate, &out.HealthGate
+		*out = make([]HealthGate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.BackgroundJobs != nil {
+		in, out := &in.BackgroundJobs, &out.BackgroundJobs
+		*out = new(BackgroundJobProtection)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RestartBatching != nil {
+		in, out := &in.RestartBatching, &out.RestartBatching
+		*out = new(RestartBatching)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AutoApprove != nil {
+		in, out := &in.AutoApprove, &out.AutoApprove
+		*out = new(AutoApprove)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TcpPort.
-func (in *TcpPort) DeepCopy() *TcpPort {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeSpec.
+func (in *UpgradeSpec) DeepCopy() *UpgradeSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(TcpPort)
+	out := new(UpgradeSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Tcpports) DeepCopyInto(out *Tcpports) {
+func (in *UpgradeStatus) DeepCopyInto(out *UpgradeStatus) {
 	*out = *in
-	if in.Ports != nil {
-		in, out := &in.Ports, &out.Ports
-		*out = make([]TcpPort, len(*in))
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.PodRestart != nil {
+		in, out := &in.PodRestart, &out.PodRestart
+		*out = new(UpgradePodRestart)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodHold != nil {
+		in, out := &in.PodHold, &out.PodHold
+		*out = new(UpgradePodHold)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Prechecks != nil {
+		in, out := &in.Prechecks, &out.Prechecks
+		*out = make([]PrecheckResult, len(*in))
+		copy(*out, *in)
+	}
+	if in.Impact != nil {
+		in, out := &in.Impact, &out.Impact
+		*out = new(UpgradeImpact)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodStartHistory != nil {
+		in, out := &in.PodStartHistory, &out.PodStartHistory
+		*out = make([]metav1.Duration, len(*in))
 		copy(*out, *in)
 	}
+	if in.PendingApproval != nil {
+		in, out := &in.PendingApproval, &out.PendingApproval
+		*out = new(UpgradeApproval)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Timeline != nil {
+		in, out := &in.Timeline, &out.Timeline
+		*out = make([]UpgradeTimelineEntry, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Tcpports.
-func (in *Tcpports) DeepCopy() *Tcpports {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeStatus.
+func (in *UpgradeStatus) DeepCopy() *UpgradeStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(Tcpports)
+	out := new(UpgradeStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Timeout) DeepCopyInto(out *Timeout) {
+func (in *UpgradeTimelineEntry) DeepCopyInto(out *UpgradeTimelineEntry) {
 	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Timeout.
-func (in *Timeout) DeepCopy() *Timeout {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeTimelineEntry.
+func (in *UpgradeTimelineEntry) DeepCopy() *UpgradeTimelineEntry {
 	if in == nil {
 		return nil
 	}
-	out := new(Timeout)
+	out := new(UpgradeTimelineEntry)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Tls) DeepCopyInto(out *Tls) {
+func (in *VeleroHooks) DeepCopyInto(out *VeleroHooks) {
 	*out = *in
-	if in.CertSecretNames != nil {
-		in, out := &in.CertSecretNames, &out.CertSecretNames
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Tls.
-func (in *Tls) DeepCopy() *Tls {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VeleroHooks.
+func (in *VeleroHooks) DeepCopy() *VeleroHooks {
 	if in == nil {
 		return nil
 	}
-	out := new(Tls)
+	out := new(VeleroHooks)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TlsForHAProxy) DeepCopyInto(out *TlsForHAProxy) {
+func (in *VerticalAutoscaling) DeepCopyInto(out *VerticalAutoscaling) {
 	*out = *in
+	if in.MinAllowed != nil {
+		in, out := &in.MinAllowed, &out.MinAllowed
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.MaxAllowed != nil {
+		in, out := &in.MaxAllowed, &out.MaxAllowed
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TlsForHAProxy.
-func (in *TlsForHAProxy) DeepCopy() *TlsForHAProxy {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VerticalAutoscaling.
+func (in *VerticalAutoscaling) DeepCopy() *VerticalAutoscaling {
 	if in == nil {
 		return nil
 	}
-	out := new(TlsForHAProxy)
+	out := new(VerticalAutoscaling)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -1277,6 +4290,21 @@ func (in *VolumeMountWrapper) DeepCopy() *VolumeMountWrapper {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeNodeBinding) DeepCopyInto(out *VolumeNodeBinding) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeNodeBinding.
+func (in *VolumeNodeBinding) DeepCopy() *VolumeNodeBinding {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeNodeBinding)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VolumeResizeStatus) DeepCopyInto(out *VolumeResizeStatus) {
 	*out = *in
@@ -1329,3 +4357,18 @@ func (in *VolumeResizeStatus) DeepCopy() *VolumeResizeStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ZoneAwareness) DeepCopyInto(out *ZoneAwareness) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ZoneAwareness.
+func (in *ZoneAwareness) DeepCopy() *ZoneAwareness {
+	if in == nil {
+		return nil
+	}
+	out := new(ZoneAwareness)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,292 @@
+/*
+Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// kubectl-marklogic is a kubectl plugin for the MarkLogic operator. Install
+// it on the PATH and run it as `kubectl marklogic <command>`.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
+	_ "k8s.io/client-go/plugin/pkg/client/auth"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/k8sutil"
+)
+
+// requestPollInterval is how often the status of a request to the operator is
+// read.
+var requestPollInterval = 2 * time.Second
+
+const usage = `Usage: kubectl marklogic <command> [flags]
+
+Commands:
+  export <cluster>           Export a MarklogicCluster into a bundle for
+                             recreating it in another Kubernetes cluster.
+  support-bundle <cluster>   Collect a support bundle of a MarklogicCluster
+                             into the storage of spec.supportBundle.
+  upgrade <command> <cluster>
+                             Print, approve, pause, resume, retry or cancel
+                             the upgrade of a MarklogicCluster.
+`
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+// run runs the command of args and returns the exit code: 2 for a missing or
+// unknown command, 1 when the command fails.
+func run(args []string) int {
+	if len(args) < 1 {
+		fmt.Fprint(os.Stderr, usage)
+		return 2
+	}
+	var err error
+	switch args[0] {
+	case "export":
+		err = runExport(args[1:])
+	case "support-bundle":
+		err = runSupportBundle(args[1:])
+	case "upgrade":
+		err = runUpgrade(args[1:])
+	case "-h", "--help", "help":
+		fmt.Print(usage)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", args[0], usage)
+		return 2
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// runExport asks the operator for an export of the cluster with the
+// marklogic.progress.com/export annotation, waits for it and writes the
+// bundle to a directory.
+func runExport(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	namespace := flags.String("namespace", "", "Namespace of the cluster. Defaults to the namespace of the current context.")
+	flags.StringVar(namespace, "n", "", "Shorthand for --namespace.")
+	kubeconfig := flags.String("kubeconfig", "", "Path to the kubeconfig file.")
+	kubeContext := flags.String("context", "", "The kubeconfig context to use.")
+	outputDir := flags.String("output-dir", "", "Directory the bundle is written to. Defaults to <cluster>-export.")
+	timeout := flags.Duration("timeout", 2*time.Minute, "How long to wait for the operator to export the cluster.")
+	if err := flags.Parse(reorderFlags(args)); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("export takes the name of the MarklogicCluster")
+	}
+	name := flags.Arg(0)
+
+	c, err := newClient(*kubeconfig, *kubeContext, namespace)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	key := types.NamespacedName{Name: name, Namespace: *namespace}
+	cluster := &marklogicv1.MarklogicCluster{}
+	requestID, err := annotateCluster(ctx, c, key, cluster, k8sutil.ExportAnnotation)
+	if err != nil {
+		return fmt.Errorf("failed to request the export: %w", err)
+	}
+
+	var status *marklogicv1.ExportStatus
+	err = wait.PollUntilContextCancel(ctx, requestPollInterval, true, func(ctx context.Context) (bool, error) {
+		if err := c.Get(ctx, key, cluster); err != nil {
+			return false, err
+		}
+		status = cluster.Status.Export
+		return status != nil && status.RequestID == requestID, nil
+	})
+	if err != nil {
+		return fmt.Errorf("the operator did not export the cluster: %w", err)
+	}
+	if status.Message != "" {
+		return fmt.Errorf("the operator failed to export the cluster: %s", status.Message)
+	}
+
+	bundle := &corev1.ConfigMap{}
+	if err := c.Get(ctx, types.NamespacedName{Name: status.ConfigMapName, Namespace: *namespace}, bundle); err != nil {
+		return err
+	}
+	dir := *outputDir
+	if dir == "" {
+		dir = name + "-export"
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return err
+	}
+	keys := make([]string, 0, len(bundle.Data))
+	for key := range bundle.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if err := os.WriteFile(filepath.Join(dir, key), []byte(bundle.Data[key]), 0o600); err != nil {
+			return err
+		}
+	}
+	fmt.Printf("Exported MarklogicCluster %s/%s to %s\n", *namespace, name, dir)
+
+	secrets := []k8sutil.ExportedSecret{}
+	if err := yaml.Unmarshal([]byte(bundle.Data[k8sutil.ExportSecretsKey]), &secrets); err != nil {
+		return err
+	}
+	for _, secret := range secrets {
+		switch {
+		case secret.Missing:
+			fmt.Printf("Secret %s is referenced by the cluster but does not exist\n", secret.Name)
+		case secret.Generated:
+			fmt.Printf("Copy Secret %s to keep the credentials generated by the operator\n", secret.Name)
+		default:
+			fmt.Printf("Copy Secret %s before applying %s\n", secret.Name, k8sutil.ExportClusterKey)
+		}
+	}
+	return nil
+}
+
+// runSupportBundle asks the operator for a support bundle of the cluster with
+// the marklogic.progress.com/support-bundle annotation and waits for it.
+func runSupportBundle(args []string) error {
+	flags := flag.NewFlagSet("support-bundle", flag.ExitOnError)
+	namespace := flags.String("namespace", "", "Namespace of the cluster. Defaults to the namespace of the current context.")
+	flags.StringVar(namespace, "n", "", "Shorthand for --namespace.")
+	kubeconfig := flags.String("kubeconfig", "", "Path to the kubeconfig file.")
+	kubeContext := flags.String("context", "", "The kubeconfig context to use.")
+	timeout := flags.Duration("timeout", 10*time.Minute, "How long to wait for the operator to collect the bundle.")
+	if err := flags.Parse(reorderFlags(args)); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("support-bundle takes the name of the MarklogicCluster")
+	}
+	name := flags.Arg(0)
+	c, err := newClient(*kubeconfig, *kubeContext, namespace)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	key := types.NamespacedName{Name: name, Namespace: *namespace}
+	cluster := &marklogicv1.MarklogicCluster{}
+	requestID, err := annotateCluster(ctx, c, key, cluster, k8sutil.SupportBundleAnnotation)
+	if err != nil {
+		return fmt.Errorf("failed to request the support bundle: %w", err)
+	}
+	var status *marklogicv1.SupportBundleStatus
+	err = wait.PollUntilContextCancel(ctx, requestPollInterval, true, func(ctx context.Context) (bool, error) {
+		if err := c.Get(ctx, key, cluster); err != nil {
+			return false, err
+		}
+		status = cluster.Status.SupportBundle
+		return status != nil && status.RequestID == requestID && status.Phase != marklogicv1.SupportBundlePending, nil
+	})
+	if err != nil {
+		return fmt.Errorf("the operator did not collect the support bundle: %w", err)
+	}
+	if status.Phase == marklogicv1.SupportBundleFailed {
+		return fmt.Errorf("the operator failed to collect the support bundle: %s", status.Message)
+	}
+	fmt.Printf("Stored the support bundle of MarklogicCluster %s/%s at %s (%d bytes)\n", *namespace, name, status.Location, status.SizeBytes)
+	if status.Message != "" {
+		fmt.Println(status.Message)
+	}
+	return nil
+}
+
+// newClient connects to the Kubernetes cluster of the kubeconfig and sets
+// namespace to the namespace of the context when it is empty. Tests replace
+// it with a fake client.
+var newClient = func(kubeconfig, kubeContext string, namespace *string) (client.Client, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = kubeconfig
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{CurrentContext: kubeContext})
+	if *namespace == "" {
+		ns, _, err := clientConfig.Namespace()
+		if err != nil {
+			return nil, err
+		}
+		*namespace = ns
+	}
+	restConfig, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(marklogicv1.AddToScheme(scheme))
+	return client.New(restConfig, client.Options{Scheme: scheme})
+}
+
+// annotateCluster sets the annotation of a request to the operator on the
+// cluster to a new request ID, which it returns.
+func annotateCluster(ctx context.Context, c client.Client, key types.NamespacedName, cluster *marklogicv1.MarklogicCluster, annotation string) (string, error) {
+	if err := c.Get(ctx, key, cluster); err != nil {
+		return "", err
+	}
+	requestID := time.Now().UTC().Format("20060102T150405Z")
+	patchBase := client.MergeFrom(cluster.DeepCopy())
+	if cluster.Annotations == nil {
+		cluster.Annotations = map[string]string{}
+	}
+	cluster.Annotations[annotation] = requestID
+	return requestID, c.Patch(ctx, cluster, patchBase)
+}
+
+// reorderFlags moves the positional arguments after the flags, so the name of
+// the cluster can come first like in other kubectl commands. All flags of the
+// plugin take a value.
+func reorderFlags(args []string) []string {
+	flags, positional := []string{}, []string{}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if len(arg) < 2 || arg[0] != '-' {
+			positional = append(positional, arg)
+			continue
+		}
+		flags = append(flags, arg)
+		if !strings.Contains(arg, "=") && i+1 < len(args) {
+			i++
+			flags = append(flags, args[i])
+		}
+	}
+	return append(flags, positional...)
+}
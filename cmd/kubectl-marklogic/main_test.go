@@ -0,0 +1,192 @@
+/*
+Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/k8sutil"
+)
+
+// useFakeClient makes the commands use a fake client holding objects. After
+// a command patched a cluster, operator stands in for the operator: it
+// changes the cluster from the second read on, so the first read still sees
+// the status from before the request.
+func useFakeClient(t *testing.T, operator func(ctx context.Context, c client.Client, cluster *marklogicv1.MarklogicCluster), objects ...client.Object) client.Client {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	for _, add := range []func(*runtime.Scheme) error{clientgoscheme.AddToScheme, marklogicv1.AddToScheme} {
+		if err := add(scheme); err != nil {
+			t.Fatalf("failed to build scheme: %v", err)
+		}
+	}
+	readsAfterPatch := -1
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).WithInterceptorFuncs(interceptor.Funcs{
+		Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			if err := c.Patch(ctx, obj, patch, opts...); err != nil {
+				return err
+			}
+			readsAfterPatch = 0
+			return nil
+		},
+		Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			if err := c.Get(ctx, key, obj, opts...); err != nil {
+				return err
+			}
+			cluster, ok := obj.(*marklogicv1.MarklogicCluster)
+			if !ok || operator == nil || readsAfterPatch < 0 {
+				return nil
+			}
+			if readsAfterPatch++; readsAfterPatch >= 2 {
+				operator(ctx, c, cluster)
+			}
+			return nil
+		},
+	}).Build()
+
+	originalClient, originalRequestInterval, originalUpgradeInterval := newClient, requestPollInterval, upgradePollInterval
+	newClient = func(_, _ string, namespace *string) (client.Client, error) {
+		if *namespace == "" {
+			*namespace = "default"
+		}
+		return c, nil
+	}
+	requestPollInterval, upgradePollInterval = time.Millisecond, time.Millisecond
+	t.Cleanup(func() {
+		newClient, requestPollInterval, upgradePollInterval = originalClient, originalRequestInterval, originalUpgradeInterval
+	})
+	return c
+}
+
+func TestReorderFlags(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{"cluster first", []string{"ml", "-n", "prod"}, []string{"-n", "prod", "ml"}},
+		{"flags first", []string{"--namespace", "prod", "ml"}, []string{"--namespace", "prod", "ml"}},
+		{"flag with value", []string{"ml", "--wait=5m", "-o", "json"}, []string{"--wait=5m", "-o", "json", "ml"}},
+		{"flag without value at the end", []string{"ml", "--wait"}, []string{"--wait", "ml"}},
+		{"dash is positional", []string{"-", "ml"}, []string{"-", "ml"}},
+		{"no arguments", nil, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := reorderFlags(tt.args); !slices.Equal(got, tt.want) {
+				t.Fatalf("reorderFlags(%q) = %q, want %q", tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunExitCodes(t *testing.T) {
+	cluster := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+		Status: marklogicv1.MarklogicClusterStatus{Upgrade: &marklogicv1.UpgradeStatus{
+			State:     marklogicv1.UpgradeStatePrecheck,
+			Prechecks: []marklogicv1.PrecheckResult{{Name: "disk", Status: marklogicv1.PrecheckFailed}},
+		}},
+	}
+	useFakeClient(t, nil, cluster)
+
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{"no command", nil, 2},
+		{"unknown command", []string{"frobnicate"}, 2},
+		{"help", []string{"help"}, 0},
+		{"upgrade help", []string{"upgrade"}, 0},
+		{"status", []string{"upgrade", "status", "ml"}, 0},
+		{"failed precheck", []string{"upgrade", "prechecks", "ml"}, 1},
+		{"missing cluster", []string{"upgrade", "status", "other"}, 1},
+		{"missing cluster name", []string{"export"}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := run(tt.args); got != tt.want {
+				t.Fatalf("run(%q) = %d, want %d", tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunExport(t *testing.T) {
+	bundle := map[string]string{
+		k8sutil.ExportClusterKey: "kind: MarklogicCluster\n",
+		k8sutil.ExportSecretsKey: "- name: ml-admin\n  generated: true\n",
+	}
+	tests := []struct {
+		name    string
+		message string
+		wantErr string
+	}{
+		{name: "writes the bundle"},
+		{name: "reports a failed export", message: "secret ml-admin is forbidden", wantErr: "the operator failed to export the cluster: secret ml-admin is forbidden"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := &marklogicv1.MarklogicCluster{ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"}}
+			useFakeClient(t, func(ctx context.Context, c client.Client, cluster *marklogicv1.MarklogicCluster) {
+				configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "ml-export", Namespace: "default"}, Data: bundle}
+				if err := c.Create(ctx, configMap); err != nil && !apierrors.IsAlreadyExists(err) {
+					t.Errorf("failed to create the bundle: %v", err)
+				}
+				cluster.Status.Export = &marklogicv1.ExportStatus{
+					RequestID:     cluster.Annotations[k8sutil.ExportAnnotation],
+					ConfigMapName: configMap.Name,
+					Message:       tt.message,
+				}
+			}, cluster)
+
+			dir := filepath.Join(t.TempDir(), "bundle")
+			err := runExport([]string{"ml", "--output-dir", dir, "--timeout=10s"})
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("expected %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("runExport: %v", err)
+			}
+			for key, want := range bundle {
+				got, err := os.ReadFile(filepath.Join(dir, key))
+				if err != nil || string(got) != want {
+					t.Fatalf("expected %s to hold %q, got %q, %v", key, want, got, err)
+				}
+			}
+		})
+	}
+}
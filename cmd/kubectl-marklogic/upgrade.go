@@ -0,0 +1,267 @@
+/*
+Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/k8sutil"
+)
+
+const upgradeUsage = `Usage: kubectl marklogic upgrade <command> <cluster> [flags]
+
+Commands:
+  status      Print the state of the upgrade.
+  prechecks   Print the results of the last precheck run. Exits with 1 when a
+              precheck failed.
+  approve     Approve the upgrade that waits for approval.
+  pause       Hold the rollout before it restarts the next pod.
+  resume      Resume a paused rollout.
+  retry       Retry a failed upgrade right away.
+  cancel      Revert spec.image to the image the cluster runs.
+`
+
+// upgradePollInterval is how often --wait reads status.upgrade.
+var upgradePollInterval = 5 * time.Second
+
+// runUpgrade drives the upgrade workflow of a cluster through the control
+// annotations and status.upgrade, so pipelines do not need to know either.
+func runUpgrade(args []string) error {
+	if len(args) < 1 || args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		fmt.Print(upgradeUsage)
+		return nil
+	}
+	command := args[0]
+	flags := flag.NewFlagSet("upgrade "+command, flag.ExitOnError)
+	namespace := flags.String("namespace", "", "Namespace of the cluster. Defaults to the namespace of the current context.")
+	flags.StringVar(namespace, "n", "", "Shorthand for --namespace.")
+	kubeconfig := flags.String("kubeconfig", "", "Path to the kubeconfig file.")
+	kubeContext := flags.String("context", "", "The kubeconfig context to use.")
+	output := flags.String("output", "", "Output format of status and prechecks. One of: json. Defaults to text.")
+	flags.StringVar(output, "o", "", "Shorthand for --output.")
+	waitFor := flags.Duration("wait", 0, "How long approve, resume and retry wait for the upgrade to complete. Returns right away when 0.")
+	if err := flags.Parse(reorderFlags(args[1:])); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("upgrade %s takes the name of the MarklogicCluster", command)
+	}
+	if *output != "" && *output != "json" {
+		return fmt.Errorf("unsupported output format %q", *output)
+	}
+	name := flags.Arg(0)
+	c, err := newClient(*kubeconfig, *kubeContext, namespace)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute+*waitFor)
+	defer cancel()
+	key := types.NamespacedName{Name: name, Namespace: *namespace}
+	cluster := &marklogicv1.MarklogicCluster{}
+	if err := c.Get(ctx, key, cluster); err != nil {
+		return err
+	}
+	upgrade := cluster.Status.Upgrade
+	if upgrade == nil {
+		upgrade = &marklogicv1.UpgradeStatus{}
+	}
+	// picked tells when the operator picked up the request of the command.
+	var picked func(*marklogicv1.UpgradeStatus) bool
+	switch command {
+	case "status":
+		return printUpgradeStatus(upgrade, *output)
+	case "prechecks":
+		return printPrechecks(upgrade, *output)
+	case "approve":
+		if upgrade.PendingApproval == nil {
+			return fmt.Errorf("no upgrade of MarklogicCluster %s/%s waits for approval", key.Namespace, name)
+		}
+		image := upgrade.PendingApproval.Image
+		if err := setClusterAnnotation(ctx, c, cluster, k8sutil.UpgradeApprovalAnnotation, image); err != nil {
+			return fmt.Errorf("failed to approve the upgrade: %w", err)
+		}
+		fmt.Printf("Approved the upgrade of MarklogicCluster %s/%s to %s\n", key.Namespace, name, image)
+		picked = func(u *marklogicv1.UpgradeStatus) bool {
+			return u.State != marklogicv1.UpgradeStateWaitingForUserApproval
+		}
+	case "pause":
+		if !upgrade.Active() && upgrade.State != marklogicv1.UpgradeStateFailed {
+			return fmt.Errorf("MarklogicCluster %s/%s is not upgrading", key.Namespace, name)
+		}
+		if err := setClusterAnnotation(ctx, c, cluster, k8sutil.PauseUpgradeAnnotation, "true"); err != nil {
+			return fmt.Errorf("failed to pause the upgrade: %w", err)
+		}
+		fmt.Printf("Paused the upgrade of MarklogicCluster %s/%s before the next pod restart\n", key.Namespace, name)
+		return nil
+	case "resume":
+		if ops := cluster.Spec.Operations; ops != nil && ops.PauseUpgrade {
+			return errors.New("spec.operations.pauseUpgrade pauses the upgrade, unset it where the cluster is managed")
+		}
+		if err := setClusterAnnotation(ctx, c, cluster, k8sutil.PauseUpgradeAnnotation, ""); err != nil {
+			return fmt.Errorf("failed to resume the upgrade: %w", err)
+		}
+		fmt.Printf("Resumed the upgrade of MarklogicCluster %s/%s\n", key.Namespace, name)
+		picked = func(u *marklogicv1.UpgradeStatus) bool { return !u.Paused }
+	case "retry":
+		if upgrade.State != marklogicv1.UpgradeStateFailed {
+			return fmt.Errorf("the upgrade of MarklogicCluster %s/%s did not fail", key.Namespace, name)
+		}
+		requestID, err := annotateCluster(ctx, c, key, cluster, k8sutil.RetryUpgradeAnnotation)
+		if err != nil {
+			return fmt.Errorf("failed to retry the upgrade: %w", err)
+		}
+		fmt.Printf("Retrying the upgrade of MarklogicCluster %s/%s (request %s)\n", key.Namespace, name, requestID)
+		picked = func(u *marklogicv1.UpgradeStatus) bool { return u.RetryRequestID == requestID }
+	case "cancel":
+		if !upgrade.Active() && upgrade.State != marklogicv1.UpgradeStateFailed {
+			return fmt.Errorf("MarklogicCluster %s/%s is not upgrading", key.Namespace, name)
+		}
+		patchBase := client.MergeFrom(cluster.DeepCopy())
+		cluster.Spec.Image = upgrade.CurrentImage
+		if err := c.Patch(ctx, cluster, patchBase); err != nil {
+			return fmt.Errorf("failed to cancel the upgrade: %w", err)
+		}
+		fmt.Printf("Reverted spec.image of MarklogicCluster %s/%s to %s\n", key.Namespace, name, upgrade.CurrentImage)
+		return nil
+	default:
+		return fmt.Errorf("unknown upgrade command %q\n\n%s", command, upgradeUsage)
+	}
+	if *waitFor == 0 {
+		return nil
+	}
+	return waitForUpgrade(ctx, c, key, *waitFor, picked)
+}
+
+// waitForUpgrade waits until the operator picked up the request and the
+// upgrade completed, failed, was cancelled, paused or waits for approval.
+func waitForUpgrade(ctx context.Context, c client.Client, key types.NamespacedName, timeout time.Duration, picked func(*marklogicv1.UpgradeStatus) bool) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	cluster := &marklogicv1.MarklogicCluster{}
+	var upgrade *marklogicv1.UpgradeStatus
+	err := wait.PollUntilContextCancel(ctx, upgradePollInterval, true, func(ctx context.Context) (bool, error) {
+		if err := c.Get(ctx, key, cluster); err != nil {
+			return false, err
+		}
+		upgrade = cluster.Status.Upgrade
+		if upgrade == nil {
+			return true, nil
+		}
+		return picked(upgrade) && (!upgrade.Active() || upgrade.Paused || upgrade.State == marklogicv1.UpgradeStateWaitingForUserApproval), nil
+	})
+	if err != nil {
+		return fmt.Errorf("the upgrade did not complete: %w", err)
+	}
+	if upgrade == nil || upgrade.State != marklogicv1.UpgradeStateCompleted {
+		return fmt.Errorf("the upgrade did not complete: %s", upgradeMessage(upgrade))
+	}
+	fmt.Printf("Upgraded MarklogicCluster %s/%s to %s\n", key.Namespace, key.Name, upgrade.CurrentImage)
+	return nil
+}
+
+// printUpgradeStatus prints status.upgrade.
+func printUpgradeStatus(upgrade *marklogicv1.UpgradeStatus, output string) error {
+	if output == "json" {
+		return printJSON(upgrade)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "State:\t%s\n", upgrade.State)
+	fmt.Fprintf(w, "Current image:\t%s\n", upgrade.CurrentImage)
+	if upgrade.TargetImage != "" {
+		fmt.Fprintf(w, "Target image:\t%s\n", upgrade.TargetImage)
+	}
+	if upgrade.TotalPods > 0 {
+		fmt.Fprintf(w, "Pods updated:\t%d/%d\n", upgrade.UpdatedPods, upgrade.TotalPods)
+	}
+	if upgrade.Paused {
+		fmt.Fprintf(w, "Paused:\ttrue\n")
+	}
+	if approval := upgrade.PendingApproval; approval != nil {
+		fmt.Fprintf(w, "Waiting for approval of:\t%s since %s\n", approval.Image, approval.Since.UTC().Format(time.RFC3339))
+	}
+	fmt.Fprintf(w, "Message:\t%s\n", upgradeMessage(upgrade))
+	return w.Flush()
+}
+
+// printPrechecks prints the results of the last precheck run and fails when
+// one of them failed.
+func printPrechecks(upgrade *marklogicv1.UpgradeStatus, output string) error {
+	if output == "json" {
+		if err := printJSON(upgrade.Prechecks); err != nil {
+			return err
+		}
+	} else {
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tSTATUS\tMESSAGE")
+		for _, res := range upgrade.Prechecks {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", res.Name, res.Status, res.Message)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+	for _, res := range upgrade.Prechecks {
+		if res.Status == marklogicv1.PrecheckFailed {
+			return fmt.Errorf("precheck %s failed", res.Name)
+		}
+	}
+	return nil
+}
+
+func printJSON(v any) error {
+	out, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(out))
+	return nil
+}
+
+func upgradeMessage(upgrade *marklogicv1.UpgradeStatus) string {
+	if upgrade == nil || upgrade.Message == "" {
+		return "none"
+	}
+	return upgrade.Message
+}
+
+// setClusterAnnotation sets an annotation of the cluster, or removes it when
+// value is empty.
+func setClusterAnnotation(ctx context.Context, c client.Client, cluster *marklogicv1.MarklogicCluster, annotation, value string) error {
+	patchBase := client.MergeFrom(cluster.DeepCopy())
+	if value == "" {
+		delete(cluster.Annotations, annotation)
+	} else {
+		if cluster.Annotations == nil {
+			cluster.Annotations = map[string]string{}
+		}
+		cluster.Annotations[annotation] = value
+	}
+	return c.Patch(ctx, cluster, patchBase)
+}
@@ -0,0 +1,189 @@
+/*
+Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/k8sutil"
+)
+
+const (
+	upgradeTestOldImage = "progressofficial/marklogic-db:11.3.1-ubi-rootless"
+	upgradeTestNewImage = "progressofficial/marklogic-db:12.0.1-ubi-rootless"
+)
+
+func TestRunUpgrade(t *testing.T) {
+	completed := func(_ context.Context, _ client.Client, cluster *marklogicv1.MarklogicCluster) {
+		upgrade := cluster.Status.Upgrade
+		upgrade.State, upgrade.CurrentImage, upgrade.Paused, upgrade.PendingApproval = marklogicv1.UpgradeStateCompleted, upgradeTestNewImage, false, nil
+		upgrade.RetryRequestID = cluster.Annotations[k8sutil.RetryUpgradeAnnotation]
+	}
+	tests := []struct {
+		name        string
+		args        []string
+		annotations map[string]string
+		upgrade     marklogicv1.UpgradeStatus
+		// operator changes the cluster once the command patched it.
+		operator func(ctx context.Context, c client.Client, cluster *marklogicv1.MarklogicCluster)
+		wantErr  string
+		check    func(t *testing.T, cluster *marklogicv1.MarklogicCluster)
+	}{
+		{
+			name: "approve waits until the operator leaves the approval",
+			args: []string{"approve", "ml", "--wait", "1m"},
+			upgrade: marklogicv1.UpgradeStatus{
+				State:           marklogicv1.UpgradeStateWaitingForUserApproval,
+				PendingApproval: &marklogicv1.UpgradeApproval{Image: upgradeTestNewImage},
+			},
+			operator: completed,
+			check: func(t *testing.T, cluster *marklogicv1.MarklogicCluster) {
+				if cluster.Annotations[k8sutil.UpgradeApprovalAnnotation] != upgradeTestNewImage {
+					t.Fatalf("expected the approval of %s, got %v", upgradeTestNewImage, cluster.Annotations)
+				}
+			},
+		},
+		{
+			name:    "approve requires a pending approval",
+			args:    []string{"approve", "ml"},
+			upgrade: marklogicv1.UpgradeStatus{State: marklogicv1.UpgradeStateInProgress},
+			wantErr: "no upgrade of MarklogicCluster default/ml waits for approval",
+		},
+		{
+			name:        "resume waits until the rollout is no longer paused",
+			args:        []string{"resume", "--wait=1m", "ml"},
+			annotations: map[string]string{k8sutil.PauseUpgradeAnnotation: "true"},
+			upgrade:     marklogicv1.UpgradeStatus{State: marklogicv1.UpgradeStateInProgress, Paused: true},
+			operator:    completed,
+			check: func(t *testing.T, cluster *marklogicv1.MarklogicCluster) {
+				if _, ok := cluster.Annotations[k8sutil.PauseUpgradeAnnotation]; ok {
+					t.Fatalf("expected the pause annotation to be removed, got %v", cluster.Annotations)
+				}
+			},
+		},
+		{
+			name:     "retry waits until the operator picked up the request",
+			args:     []string{"retry", "ml", "-n", "default", "--wait", "1m"},
+			upgrade:  marklogicv1.UpgradeStatus{State: marklogicv1.UpgradeStateFailed, RetryRequestID: "20260101T000000Z"},
+			operator: completed,
+		},
+		{
+			name:    "retry reports an upgrade that failed again",
+			args:    []string{"retry", "ml", "--wait", "1m"},
+			upgrade: marklogicv1.UpgradeStatus{State: marklogicv1.UpgradeStateFailed},
+			operator: func(_ context.Context, _ client.Client, cluster *marklogicv1.MarklogicCluster) {
+				cluster.Status.Upgrade.RetryRequestID = cluster.Annotations[k8sutil.RetryUpgradeAnnotation]
+				cluster.Status.Upgrade.Message = "health gate failed"
+			},
+			wantErr: "the upgrade did not complete: health gate failed",
+		},
+		{
+			name:    "retry requires a failed upgrade",
+			args:    []string{"retry", "ml"},
+			upgrade: marklogicv1.UpgradeStatus{State: marklogicv1.UpgradeStateInProgress},
+			wantErr: "the upgrade of MarklogicCluster default/ml did not fail",
+		},
+		{
+			name:    "pause annotates the cluster",
+			args:    []string{"pause", "ml"},
+			upgrade: marklogicv1.UpgradeStatus{State: marklogicv1.UpgradeStateInProgress},
+			check: func(t *testing.T, cluster *marklogicv1.MarklogicCluster) {
+				if cluster.Annotations[k8sutil.PauseUpgradeAnnotation] != "true" {
+					t.Fatalf("expected the pause annotation, got %v", cluster.Annotations)
+				}
+			},
+		},
+		{
+			name:    "cancel reverts the image",
+			args:    []string{"cancel", "ml"},
+			upgrade: marklogicv1.UpgradeStatus{State: marklogicv1.UpgradeStateInProgress},
+			check: func(t *testing.T, cluster *marklogicv1.MarklogicCluster) {
+				if cluster.Spec.Image != upgradeTestOldImage {
+					t.Fatalf("expected spec.image %s, got %s", upgradeTestOldImage, cluster.Spec.Image)
+				}
+			},
+		},
+		{
+			name:    "cancel requires an upgrade",
+			args:    []string{"cancel", "ml"},
+			upgrade: marklogicv1.UpgradeStatus{State: marklogicv1.UpgradeStateCompleted},
+			wantErr: "MarklogicCluster default/ml is not upgrading",
+		},
+		{
+			name: "prechecks fail with a failed precheck",
+			args: []string{"prechecks", "ml", "-o", "json"},
+			upgrade: marklogicv1.UpgradeStatus{Prechecks: []marklogicv1.PrecheckResult{
+				{Name: "hosts", Status: marklogicv1.PrecheckPassed},
+				{Name: "disk", Status: marklogicv1.PrecheckFailed, Message: "90% used"},
+			}},
+			wantErr: "precheck disk failed",
+		},
+		{
+			name:    "unsupported output",
+			args:    []string{"status", "ml", "-o", "yaml"},
+			wantErr: `unsupported output format "yaml"`,
+		},
+		{
+			name:    "missing cluster name",
+			args:    []string{"status"},
+			wantErr: "upgrade status takes the name of the MarklogicCluster",
+		},
+		{
+			name:    "unknown command",
+			args:    []string{"rollback", "ml"},
+			wantErr: `unknown upgrade command "rollback"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			upgrade := tt.upgrade
+			if upgrade.CurrentImage == "" {
+				upgrade.CurrentImage = upgradeTestOldImage
+			}
+			cluster := &marklogicv1.MarklogicCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default", Annotations: tt.annotations},
+				Spec:       marklogicv1.MarklogicClusterSpec{Image: upgradeTestNewImage},
+				Status:     marklogicv1.MarklogicClusterStatus{Upgrade: &upgrade},
+			}
+			c := useFakeClient(t, tt.operator, cluster)
+
+			err := runUpgrade(tt.args)
+			if tt.wantErr != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) {
+					t.Fatalf("expected %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("runUpgrade(%q): %v", tt.args, err)
+			}
+			if tt.check != nil {
+				if err := c.Get(context.Background(), types.NamespacedName{Name: "ml", Namespace: "default"}, cluster); err != nil {
+					t.Fatal(err)
+				}
+				tt.check(t, cluster)
+			}
+		})
+	}
+}
@@ -17,7 +17,10 @@ limitations under the License.
 package main
 
 import (
+	"context"
+	"crypto/fips140"
 	"crypto/tls"
+	"errors"
 	"flag"
 	"os"
 	"sort"
@@ -29,9 +32,11 @@ import (
 
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/discovery"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
@@ -40,12 +45,25 @@ import (
 
 	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
 	"github.com/marklogic/marklogic-operator-kubernetes/internal/controller"
+	webhookv1 "github.com/marklogic/marklogic-operator-kubernetes/internal/webhook/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/faultinject"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/features"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/k8sutil"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/operatorconfig"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/telemetry"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/webhookcert"
 	//+kubebuilder:scaffold:imports
 )
 
 var (
 	scheme   = runtime.NewScheme()
 	setupLog = ctrl.Log.WithName("setup")
+
+	// version is the operator version, set at build time with
+	// -ldflags "-X main.version=<version>".
+	version = "dev"
 )
 
 func init() {
@@ -62,6 +80,17 @@ func main() {
 	var secureMetrics bool
 	var enableHTTP2 bool
 	var watchNamespace string
+	var faultInjection string
+	var enableWebhooks bool
+	var webhookCertMode string
+	var webhookCertDir string
+	var webhookServiceName string
+	var webhookSecretName string
+	var configFile string
+	var featureGates string
+	var offline bool
+	var tenantRBAC bool
+	var fipsTLS bool
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080",
 		"The address the metrics endpoint binds to. Use :8443 when --metrics-secure is true.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
@@ -77,6 +106,36 @@ func main() {
 		"Namespace(s) to watch for resources. If empty, watches all namespaces (cluster-scoped). "+
 			"Can be a single namespace or comma-separated list of namespaces. "+
 			"Can be set via WATCH_NAMESPACE environment variable.")
+	flag.StringVar(&faultInjection, "fault-injection", "",
+		"Enable fault injection for resilience testing. Never use in production. "+
+			"Comma-separated key=value list: manage-latency, manage-error-rate, pod-kill-rate, "+
+			"pod-kill-interval, pod-kill-scope (upgrade|always), seed.")
+	flag.BoolVar(&enableWebhooks, "enable-webhooks", false,
+		"Serve the admission webhooks. Requires the webhook Service and webhook configurations to be installed.")
+	flag.StringVar(&webhookCertMode, "webhook-cert-mode", webhookcert.ModeSelfSigned,
+		"How webhook serving certificates are provided: \"self-signed\" (generated and rotated by the operator) "+
+			"or \"cert-manager\" (mounted into --webhook-cert-dir from a cert-manager Certificate).")
+	flag.StringVar(&webhookCertDir, "webhook-cert-dir", "/tmp/k8s-webhook-server/serving-certs",
+		"Directory holding tls.crt and tls.key for the webhook server.")
+	flag.StringVar(&webhookServiceName, "webhook-service-name", "marklogic-operator-webhook-service",
+		"Name of the Service in front of the webhook server, used for the self-signed certificate DNS names.")
+	flag.StringVar(&webhookSecretName, "webhook-secret-name", "marklogic-operator-webhook-server-cert",
+		"Secret in the operator namespace that stores the self-signed webhook certificates.")
+	flag.StringVar(&featureGates, "feature-gates", "",
+		"Comma-separated Feature=true|false pairs that enable or disable operator features. "+
+			"Known features: "+features.Usage()+".")
+	flag.BoolVar(&fipsTLS, "fips-tls", false,
+		"Restrict the metrics and webhook servers to TLS 1.2 and later with FIPS approved ciphers and curves. "+
+			"Run with GODEBUG=fips140=on to also use the FIPS 140-3 Go cryptographic module.")
+	flag.BoolVar(&offline, "offline", false,
+		"Run in a disconnected environment: no call is made outside the Kubernetes cluster and the MarkLogic hosts. "+
+			"Image registries are not read by the upgrade prechecks and telemetry is disabled.")
+	flag.BoolVar(&tenantRBAC, "tenant-rbac", false,
+		"Generate a Role and RoleBinding in namespaces with the "+k8sutil.TenantGroupsAnnotation+" annotation that let "+
+			"the listed groups view MarkLogic clusters and approve their upgrades.")
+	flag.StringVar(&configFile, "config", "",
+		"Path to the operator configuration file with the defaults for all clusters: log collection image, "+
+			"probe timings, requeue intervals, default storage class, event verbosity and the opt-in telemetry.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -126,6 +185,10 @@ func main() {
 	if !enableHTTP2 {
 		tlsOpts = append(tlsOpts, disableHTTP2)
 	}
+	if fipsTLS {
+		setupLog.Info("restricting the metrics and webhook servers to FIPS approved TLS", "fips140Module", fips140.Enabled())
+		tlsOpts = append(tlsOpts, restrictToFIPSTLS)
+	}
 
 	// FilterProvider is wired only when secure mode is requested.
 	// It validates every scrape via TokenReview (authn) and
@@ -141,8 +204,39 @@ func main() {
 		metricsOpts.FilterProvider = filters.WithAuthenticationAndAuthorization
 	}
 
+	gates, err := features.Parse(featureGates)
+	if err != nil {
+		setupLog.Error(err, "invalid --feature-gates value")
+		os.Exit(1)
+	}
+	features.Default = gates
+	setupLog.Info("feature gates", "gates", gates.String())
+
+	if configFile != "" {
+		operatorConfig, err := operatorconfig.Load(configFile)
+		if err != nil {
+			setupLog.Error(err, "unable to load the operator configuration", "file", configFile)
+			os.Exit(1)
+		}
+		setupLog.Info("loaded operator configuration", "file", configFile, "config", operatorConfig)
+		k8sutil.OperatorConfig = operatorConfig
+		result.DurationFunc = operatorConfig.RequeueDuration
+	}
+
+	k8sutil.ManagementStatusCache = mlmanage.NewStatusCache(k8sutil.OperatorConfig.StatusCacheTTL())
+
+	k8sutil.Offline = offline
+	if offline {
+		setupLog.Info("offline mode: registry lookups and telemetry are disabled")
+	}
+
+	if webhookCertMode != webhookcert.ModeSelfSigned && webhookCertMode != webhookcert.ModeCertManager {
+		setupLog.Info("invalid --webhook-cert-mode, must be self-signed or cert-manager", "mode", webhookCertMode)
+		os.Exit(1)
+	}
 	webhookServer := webhook.NewServer(webhook.Options{
 		TLSOpts: tlsOpts,
+		CertDir: webhookCertDir,
 	})
 
 	// Build cache options: when watchNamespaces is non-empty (set via --watch-namespace flag
@@ -154,17 +248,9 @@ func main() {
 			nsMap[ns] = cache.Config{}
 		}
 		// Always include the operator's own namespace so leader-election works.
-		podNS := strings.TrimSpace(os.Getenv("POD_NAMESPACE"))
-		if podNS == "" {
-			nsBytes, readErr := os.ReadFile("/var/run/secrets/kubernetes.io/serviceaccount/namespace")
-			if readErr != nil {
-				setupLog.Error(readErr, "unable to determine pod namespace from POD_NAMESPACE or serviceaccount namespace file")
-				os.Exit(1)
-			}
-			podNS = strings.TrimSpace(string(nsBytes))
-		}
-		if podNS == "" {
-			setupLog.Info("unable to determine pod namespace; namespace-scoped mode requires POD_NAMESPACE or the serviceaccount namespace file")
+		podNS, err := operatorNamespace()
+		if err != nil {
+			setupLog.Error(err, "unable to determine pod namespace; namespace-scoped mode requires POD_NAMESPACE or the serviceaccount namespace file")
 			os.Exit(1)
 		}
 		nsMap[podNS] = cache.Config{}
@@ -213,11 +299,44 @@ func main() {
 		os.Exit(1)
 	}
 
+	if discoveryClient, err := discovery.NewDiscoveryClientForConfig(mgr.GetConfig()); err != nil {
+		setupLog.Error(err, "unable to create a discovery client, the StatefulSets apply the volume retention policy")
+	} else if serverVersion, err := discoveryClient.ServerVersion(); err != nil {
+		setupLog.Error(err, "unable to read the Kubernetes version, the StatefulSets apply the volume retention policy")
+	} else if !k8sutil.SupportsStatefulSetPVCRetention(serverVersion) {
+		setupLog.Info("the StatefulSets of this Kubernetes version ignore persistentVolumeClaimRetentionPolicy, the operator applies the volume retention policy",
+			"version", serverVersion.GitVersion)
+		k8sutil.StatefulSetPVCRetention = false
+	}
+
+	if enableWebhooks && webhookCertMode == webhookcert.ModeSelfSigned {
+		if err := setupWebhookCertRotation(mgr, webhookCertDir, webhookServiceName, webhookSecretName); err != nil {
+			setupLog.Error(err, "unable to set up webhook certificate rotation")
+			os.Exit(1)
+		}
+	}
+
+	var faultInjector *faultinject.Injector
+	if faultInjection != "" {
+		faultConfig, err := faultinject.ParseConfig(faultInjection)
+		if err != nil {
+			setupLog.Error(err, "invalid --fault-injection value")
+			os.Exit(1)
+		}
+		setupLog.Info("FAULT INJECTION ENABLED, do not use in production", "config", faultConfig)
+		faultInjector = faultinject.NewInjector(faultConfig)
+		k8sutil.NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+			opts.WrapTransport = faultInjector.WrapTransport
+			return mlmanage.NewClient(opts)
+		}
+	}
+
 	if err = (&controller.MarklogicGroupReconciler{
-		Client:   mgr.GetClient(),
-		Log:      ctrl.Log.WithName("controllers").WithName("MarklogicGroup"),
-		Scheme:   mgr.GetScheme(),
-		Recorder: mgr.GetEventRecorderFor("marklogicgroup-controller"),
+		Client:        mgr.GetClient(),
+		Log:           ctrl.Log.WithName("controllers").WithName("MarklogicGroup"),
+		Scheme:        mgr.GetScheme(),
+		Recorder:      k8sutil.OperatorConfig.EventRecorder(mgr.GetEventRecorderFor("marklogicgroup-controller")),
+		FaultInjector: faultInjector,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "MarklogicGroup")
 		os.Exit(1)
@@ -226,13 +345,59 @@ func main() {
 		Client:   mgr.GetClient(),
 		Scheme:   mgr.GetScheme(),
 		Log:      ctrl.Log.WithName("controllers").WithName("MarklogicCluster"),
-		Recorder: mgr.GetEventRecorderFor("marklogiccluster-controller"),
+		Recorder: k8sutil.OperatorConfig.EventRecorder(mgr.GetEventRecorderFor("marklogiccluster-controller")),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "MarklogicCluster")
 		os.Exit(1)
 	}
+	if err = (&controller.MarklogicClusterCloneReconciler{
+		Client:   mgr.GetClient(),
+		Log:      ctrl.Log.WithName("controllers").WithName("MarklogicClusterClone"),
+		Recorder: k8sutil.OperatorConfig.EventRecorder(mgr.GetEventRecorderFor("marklogicclusterclone-controller")),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "MarklogicClusterClone")
+		os.Exit(1)
+	}
+	if err = (&controller.MarklogicContentJobReconciler{
+		Client:   mgr.GetClient(),
+		Log:      ctrl.Log.WithName("controllers").WithName("MarklogicContentJob"),
+		Recorder: k8sutil.OperatorConfig.EventRecorder(mgr.GetEventRecorderFor("marklogiccontentjob-controller")),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "MarklogicContentJob")
+		os.Exit(1)
+	}
+	if tenantRBAC {
+		if err = (&controller.TenantRBACReconciler{
+			Client:          mgr.GetClient(),
+			Log:             ctrl.Log.WithName("controllers").WithName("TenantRBAC"),
+			WatchNamespaces: watchNamespaces,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "TenantRBAC")
+			os.Exit(1)
+		}
+	}
+	if enableWebhooks {
+		if err = webhookv1.SetupMarklogicClusterWebhookWithManager(mgr, tenantRBAC); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "MarklogicCluster")
+			os.Exit(1)
+		}
+	}
 	//+kubebuilder:scaffold:builder
 
+	if telemetryConfig := k8sutil.OperatorConfig.Telemetry; telemetryConfig.Enabled && offline {
+		setupLog.Info("telemetry is configured but disabled in offline mode")
+	} else if telemetryConfig.Enabled {
+		setupLog.Info("telemetry enabled", "endpoint", telemetryConfig.Endpoint, "interval", telemetryConfig.Interval.Duration)
+		if err := mgr.Add(telemetry.NewReporter(mgr.GetClient(), telemetry.Options{
+			Endpoint:        telemetryConfig.Endpoint,
+			Interval:        telemetryConfig.Interval.Duration,
+			OperatorVersion: version,
+		}, ctrl.Log.WithName("telemetry"))); err != nil {
+			setupLog.Error(err, "unable to set up telemetry")
+			os.Exit(1)
+		}
+	}
+
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
@@ -242,9 +407,72 @@ func main() {
 		os.Exit(1)
 	}
 
-	setupLog.Info("starting manager")
+	setupLog.Info("starting manager", "version", version)
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
 }
+
+// restrictToFIPSTLS limits a TLS server to TLS 1.2 and later with FIPS
+// approved cipher suites and curves. The TLS 1.3 cipher suites of Go are not
+// configurable; GODEBUG=fips140=on restricts them as well.
+func restrictToFIPSTLS(c *tls.Config) {
+	c.MinVersion = tls.VersionTLS12
+	c.CipherSuites = []uint16{
+		tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+		tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+		tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+		tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+	}
+	c.CurvePreferences = []tls.CurveID{tls.CurveP256, tls.CurveP384}
+}
+
+// operatorNamespace returns the namespace the operator runs in, from POD_NAMESPACE
+// or the service account namespace file.
+func operatorNamespace() (string, error) {
+	podNS := strings.TrimSpace(os.Getenv("POD_NAMESPACE"))
+	if podNS != "" {
+		return podNS, nil
+	}
+	nsBytes, err := os.ReadFile("/var/run/secrets/kubernetes.io/serviceaccount/namespace")
+	if err != nil {
+		return "", err
+	}
+	podNS = strings.TrimSpace(string(nsBytes))
+	if podNS == "" {
+		return "", errors.New("serviceaccount namespace file is empty")
+	}
+	return podNS, nil
+}
+
+// setupWebhookCertRotation issues the self-signed webhook certificate before the
+// webhook server starts and registers the rotator with the manager.
+func setupWebhookCertRotation(mgr ctrl.Manager, certDir, serviceName, secretName string) error {
+	namespace, err := operatorNamespace()
+	if err != nil {
+		return err
+	}
+	// The manager cache is not running yet and may not cover the operator namespace.
+	directClient, err := client.New(mgr.GetConfig(), client.Options{Scheme: mgr.GetScheme()})
+	if err != nil {
+		return err
+	}
+	clusterDomain := os.Getenv("KUBERNETES_CLUSTER_DOMAIN")
+	if clusterDomain == "" {
+		clusterDomain = "cluster.local"
+	}
+	rotator := webhookcert.NewRotator(directClient, webhookcert.Options{
+		Namespace:                       namespace,
+		SecretName:                      secretName,
+		ServiceName:                     serviceName,
+		ClusterDomain:                   clusterDomain,
+		CertDir:                         certDir,
+		ValidatingWebhookConfigurations: []string{"marklogic-operator-validating-webhook-configuration"},
+		MutatingWebhookConfigurations:   []string{"marklogic-operator-mutating-webhook-configuration"},
+	}, ctrl.Log.WithName("webhook-cert"))
+	if err := rotator.Ensure(context.Background()); err != nil {
+		return err
+	}
+	return mgr.Add(rotator)
+}
@@ -0,0 +1,140 @@
+/*
+Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// samples writes example MarklogicClusters for the cluster profiles and
+// common features, after validating them against the CRDs of the operator
+// like the API server does. Run it with `make samples`.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+)
+
+const header = `# Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+#
+# Generated by cmd/samples, do not edit. Run make samples to update.
+`
+
+func main() {
+	crdDir := flag.String("crd-dir", "config/crd/bases", "Directory of the CRDs the samples are validated against.")
+	outputDir := flag.String("output-dir", "config/samples/generated", "Directory the samples are written to.")
+	flag.Parse()
+	if err := run(*crdDir, *outputDir); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(crdDir, outputDir string) error {
+	files, err := renderSamples(crdDir)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(outputDir, 0o750); err != nil {
+		return err
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(outputDir, name), data, 0o600); err != nil {
+			return err
+		}
+	}
+	fmt.Printf("Wrote %d samples to %s\n", len(files), outputDir)
+	return nil
+}
+
+// renderSamples validates the samples against the CRDs and returns their
+// files by name. It fails on the first sample the API server would reject.
+func renderSamples(crdDir string) (map[string][]byte, error) {
+	schemas, err := loadCRDSchemas(crdDir)
+	if err != nil {
+		return nil, err
+	}
+	all, err := samples()
+	if err != nil {
+		return nil, err
+	}
+	files := map[string][]byte{}
+	for _, s := range all {
+		gvk := s.cluster.GroupVersionKind()
+		crd, ok := schemas[gvk]
+		if !ok {
+			return nil, fmt.Errorf("sample %s: no CRD for %s in %s", s.name, gvk, crdDir)
+		}
+		if errs := crd.validate(s.cluster); len(errs) > 0 {
+			return nil, fmt.Errorf("sample %s is invalid: %w", s.name, errs.ToAggregate())
+		}
+		data, err := sampleYAML(s.cluster)
+		if err != nil {
+			return nil, fmt.Errorf("sample %s: %w", s.name, err)
+		}
+		var buf bytes.Buffer
+		buf.WriteString(header)
+		buf.WriteString("#\n# " + s.description + "\n")
+		buf.Write(data)
+		files[s.name+".yaml"] = buf.Bytes()
+	}
+	return files, nil
+}
+
+// sampleYAML marshals an object without its status, the metadata fields the
+// API server sets and the empty objects of fields without omitempty.
+func sampleYAML(obj runtime.Object) ([]byte, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	delete(content, "status")
+	metadata, ok := content["metadata"].(map[string]interface{})
+	if !ok {
+		return nil, errors.New("object has no metadata")
+	}
+	delete(metadata, "creationTimestamp")
+	dropEmptyObjects(content)
+	data, err := yaml.Marshal(content)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(strings.TrimRight(string(data), "\n") + "\n"), nil
+}
+
+// dropEmptyObjects removes the fields of content whose value is an object
+// that is empty once its own empty objects are removed.
+func dropEmptyObjects(content map[string]interface{}) {
+	for key, value := range content {
+		switch value := value.(type) {
+		case map[string]interface{}:
+			dropEmptyObjects(value)
+			if len(value) == 0 {
+				delete(content, key)
+			}
+		case []interface{}:
+			for _, item := range value {
+				if item, ok := item.(map[string]interface{}); ok {
+					dropEmptyObjects(item)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,107 @@
+/*
+Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+)
+
+const (
+	testCRDDir     = "../../config/crd/bases"
+	testSamplesDir = "../../config/samples/generated"
+)
+
+// TestGeneratedSamplesUpToDate fails when a sample no longer passes the CRD
+// validation or the files in config/samples/generated are out of date.
+func TestGeneratedSamplesUpToDate(t *testing.T) {
+	files, err := renderSamples(testCRDDir)
+	if err != nil {
+		t.Fatalf("failed to render the samples: %v", err)
+	}
+	for name, want := range files {
+		got, err := os.ReadFile(filepath.Join(testSamplesDir, name))
+		if err != nil || string(got) != string(want) {
+			t.Errorf("%s is out of date, run make samples", name)
+		}
+	}
+	existing, err := filepath.Glob(filepath.Join(testSamplesDir, "*.yaml"))
+	if err != nil {
+		t.Fatalf("failed to list the samples: %v", err)
+	}
+	for _, path := range existing {
+		if _, ok := files[filepath.Base(path)]; !ok {
+			t.Errorf("%s is no longer generated, run make samples", filepath.Base(path))
+		}
+	}
+}
+
+func TestValidateRejectsInvalidCluster(t *testing.T) {
+	schemas, err := loadCRDSchemas(testCRDDir)
+	if err != nil {
+		t.Fatalf("failed to load the CRDs: %v", err)
+	}
+	cluster := &marklogicv1.MarklogicCluster{
+		TypeMeta:   metav1.TypeMeta{APIVersion: marklogicv1.GroupVersion.String(), Kind: "MarklogicCluster"},
+		ObjectMeta: metav1.ObjectMeta{Name: "invalid"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			Image:   sampleImage,
+			Profile: "four-node",
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{
+				{Name: "dnode", IsBootstrap: true, GroupConfig: &marklogicv1.GroupConfig{Name: "dnode"}},
+				{Name: "enode", IsBootstrap: true, GroupConfig: &marklogicv1.GroupConfig{Name: "enode"}},
+			},
+		},
+	}
+	errs := schemas[cluster.GroupVersionKind()].validate(cluster)
+	message := errs.ToAggregate().Error()
+	if !strings.Contains(message, "spec.profile") || !strings.Contains(message, "Exactly one MarkLogicGroup must have isBootstrap set to true") {
+		t.Fatalf("expected the enum and the CEL rule to reject the cluster, got %v", errs)
+	}
+}
+
+func TestValidateRejectsGCSWorkloadIdentityBackups(t *testing.T) {
+	schemas, err := loadCRDSchemas(testCRDDir)
+	if err != nil {
+		t.Fatalf("failed to load the CRDs: %v", err)
+	}
+	cluster := &marklogicv1.MarklogicCluster{
+		TypeMeta:   metav1.TypeMeta{APIVersion: marklogicv1.GroupVersion.String(), Kind: "MarklogicCluster"},
+		ObjectMeta: metav1.ObjectMeta{Name: "gcs"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			Image: sampleImage,
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{
+				{Name: "dnode", IsBootstrap: true, GroupConfig: &marklogicv1.GroupConfig{Name: "dnode"}},
+			},
+			Backup: &marklogicv1.Backup{Enabled: true, Storage: &marklogicv1.BackupStorage{
+				Provider: marklogicv1.BackupStorageProviderGCS,
+				Bucket:   "ml-backups",
+				AuthMode: marklogicv1.BackupStorageAuthModeWorkloadIdentity,
+			}},
+		},
+	}
+	errs := schemas[cluster.GroupVersionKind()].validate(cluster)
+	if !strings.Contains(errs.ToAggregate().Error(), "workloadIdentity authMode is not supported for the gcs provider") {
+		t.Fatalf("expected GCS with workload identity to be rejected, got %v", errs)
+	}
+}
@@ -0,0 +1,127 @@
+/*
+Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	webhookv1 "github.com/marklogic/marklogic-operator-kubernetes/internal/webhook/v1"
+)
+
+// sampleImage is the MarkLogic image of the samples, the default of spec.image.
+const sampleImage = "progressofficial/marklogic-db:12.0.3-ubi9-rootless-2.2.6"
+
+// sample is an example MarklogicCluster written to <name>.yaml.
+type sample struct {
+	name string
+	// description is written as a comment above the sample.
+	description string
+	cluster     *marklogicv1.MarklogicCluster
+}
+
+// samples returns an example for every cluster profile and for the features
+// new users ask for first.
+func samples() ([]sample, error) {
+	singleNode, err := profileCluster("single-node", marklogicv1.ClusterProfileSingleNode)
+	if err != nil {
+		return nil, err
+	}
+	threeNodeHA, err := profileCluster("three-node-ha", marklogicv1.ClusterProfileThreeNodeHA)
+	if err != nil {
+		return nil, err
+	}
+	enodeDnode, err := profileCluster("enode-dnode", marklogicv1.ClusterProfileENodeDNode)
+	if err != nil {
+		return nil, err
+	}
+
+	tls, err := profileCluster("ml-tls", marklogicv1.ClusterProfileThreeNodeHA)
+	if err != nil {
+		return nil, err
+	}
+	tls.Spec.Tls = &marklogicv1.Tls{EnableOnDefaultAppServers: true}
+
+	loki, err := profileCluster("ml-loki", marklogicv1.ClusterProfileThreeNodeHA)
+	if err != nil {
+		return nil, err
+	}
+	loki.Spec.LogCollection = &marklogicv1.LogCollection{
+		Enabled:               true,
+		Files:                 marklogicv1.LogFilesConfig{ErrorLogs: true, AccessLogs: true, RequestLogs: true, CrashLogs: true, AuditLogs: true},
+		CredentialsSecretName: "ml-loki-credentials",
+		Outputs: `- name: loki
+  match: "*"
+  host: loki-gateway.loki.svc.cluster.local
+  port: 80
+  labels: job=marklogic, cluster=ml-loki
+  http_user: ${LOKI_USER}
+  http_passwd: ${LOKI_PASSWORD}
+`,
+	}
+
+	backup, err := profileCluster("ml-backup-schedule", marklogicv1.ClusterProfileThreeNodeHA)
+	if err != nil {
+		return nil, err
+	}
+	backup.Spec.Backup = &marklogicv1.Backup{
+		Enabled: true,
+		Storage: &marklogicv1.BackupStorage{
+			Provider: marklogicv1.BackupStorageProviderS3,
+			Bucket:   "marklogic-backups",
+			Prefix:   "ml-backup-schedule",
+			AuthMode: marklogicv1.BackupStorageAuthModeWorkloadIdentity,
+			S3: &marklogicv1.S3BackupStorage{
+				Region:  "us-east-1",
+				RoleARN: "arn:aws:iam::123456789012:role/marklogic-backup",
+			},
+		},
+		Schedules: []marklogicv1.BackupSchedule{
+			{Database: "Documents", Frequency: "daily", Period: 1, StartTime: "01:00", MaxBackups: 7, JournalArchiving: true},
+			{Database: "Documents", Frequency: "hourly", Period: 4, MaxBackups: 2, Incremental: true},
+		},
+	}
+
+	return []sample{
+		{"single-node", "A single host for development and tests, created from the single-node profile.", singleNode},
+		{"three-node-ha", "Three hosts in one group, created from the three-node-ha profile.", threeNodeHA},
+		{"enode-dnode", "Three data hosts and two evaluator hosts, created from the enode-dnode profile.", enodeDnode},
+		{"tls", "TLS on the default app servers with certificates generated by MarkLogic.", tls},
+		{"log-collection-loki", "Log collection to Grafana Loki. The ml-loki-credentials Secret holds LOKI_USER and LOKI_PASSWORD.", loki},
+		{"backup-schedule", "Daily full and four-hourly incremental backups of Documents to S3 with IRSA.", backup},
+	}, nil
+}
+
+// profileCluster returns a cluster of a profile with the groups the
+// defaulting webhook expands it into.
+func profileCluster(name string, profile marklogicv1.ClusterProfile) (*marklogicv1.MarklogicCluster, error) {
+	cluster := &marklogicv1.MarklogicCluster{
+		TypeMeta:   metav1.TypeMeta{APIVersion: marklogicv1.GroupVersion.String(), Kind: "MarklogicCluster"},
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			Image:   sampleImage,
+			Profile: profile,
+		},
+	}
+	if err := (&webhookv1.MarklogicClusterCustomDefaulter{}).Default(context.Background(), cluster); err != nil {
+		return nil, fmt.Errorf("failed to expand profile %s: %w", profile, err)
+	}
+	return cluster, nil
+}
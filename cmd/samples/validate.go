@@ -0,0 +1,106 @@
+/*
+Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	structuralschema "k8s.io/apiextensions-apiserver/pkg/apiserver/schema"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/schema/cel"
+	structuraldefaulting "k8s.io/apiextensions-apiserver/pkg/apiserver/schema/defaulting"
+	structuralpruning "k8s.io/apiextensions-apiserver/pkg/apiserver/schema/pruning"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/validation"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	celconfig "k8s.io/apiserver/pkg/apis/cel"
+	"sigs.k8s.io/yaml"
+)
+
+// crdSchema validates objects of one version of a CRD like the API server
+// does on create: defaults are applied, unknown fields are reported and the
+// OpenAPI schema and x-kubernetes-validations rules are evaluated.
+type crdSchema struct {
+	structural *structuralschema.Structural
+	validator  validation.SchemaValidator
+	cel        *cel.Validator
+}
+
+// loadCRDSchemas reads the CRDs of a directory and returns the schema of
+// every served version by its group, version and kind.
+func loadCRDSchemas(dir string) (map[schema.GroupVersionKind]*crdSchema, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	schemas := map[schema.GroupVersionKind]*crdSchema{}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		crd := &apiextensionsv1.CustomResourceDefinition{}
+		if err := yaml.Unmarshal(data, crd); err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		for _, version := range crd.Spec.Versions {
+			if !version.Served || version.Schema == nil || version.Schema.OpenAPIV3Schema == nil {
+				continue
+			}
+			props := &apiextensions.JSONSchemaProps{}
+			if err := apiextensionsv1.Convert_v1_JSONSchemaProps_To_apiextensions_JSONSchemaProps(version.Schema.OpenAPIV3Schema, props, nil); err != nil {
+				return nil, fmt.Errorf("%s: %w", file, err)
+			}
+			structural, err := structuralschema.NewStructural(props)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", file, err)
+			}
+			validator, _, err := validation.NewSchemaValidator(props)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", file, err)
+			}
+			gvk := schema.GroupVersionKind{Group: crd.Spec.Group, Version: version.Name, Kind: crd.Spec.Names.Kind}
+			schemas[gvk] = &crdSchema{
+				structural: structural,
+				validator:  validator,
+				cel:        cel.NewValidator(structural, true, celconfig.PerCallLimit),
+			}
+		}
+	}
+	return schemas, nil
+}
+
+// validate returns the errors the API server would reject the object with.
+func (s *crdSchema) validate(obj runtime.Object) field.ErrorList {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return field.ErrorList{field.InternalError(nil, err)}
+	}
+	structuraldefaulting.Default(content, s.structural)
+	errs := field.ErrorList{}
+	for _, path := range structuralpruning.PruneWithOptions(runtime.DeepCopyJSON(content), s.structural, true, structuralschema.UnknownFieldPathOptions{TrackUnknownFieldPaths: true}) {
+		errs = append(errs, field.Invalid(field.NewPath(path), nil, "unknown field"))
+	}
+	errs = append(errs, validation.ValidateCustomResource(nil, content, s.validator)...)
+	celErrs, _ := s.cel.Validate(context.Background(), nil, s.structural, content, nil, celconfig.RuntimeCELCostBudget)
+	return append(errs, celErrs...)
+}
@@ -33,8 +33,13 @@ import (
 	"github.com/go-logr/logr"
 	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
 	"github.com/marklogic/marklogic-operator-kubernetes/pkg/k8sutil"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 // MarklogicClusterReconciler reconciles a MarklogicCluster object
@@ -49,6 +54,16 @@ type MarklogicClusterReconciler struct {
 //+kubebuilder:rbac:groups=marklogic.progress.com,resources=marklogicclusters/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=marklogic.progress.com,resources=marklogicclusters/finalizers,verbs=update
 //+kubebuilder:rbac:groups=core,resources=serviceaccounts,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=core,resources=pods/exec,verbs=create
+//+kubebuilder:rbac:groups=core,resources=pods/log,verbs=get
+//+kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
+//+kubebuilder:rbac:groups=metrics.k8s.io,resources=pods,verbs=get;list
+//+kubebuilder:rbac:groups=autoscaling.k8s.io,resources=verticalpodautoscalers,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=keda.sh,resources=scaledobjects,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=route.openshift.io,resources=routes,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=route.openshift.io,resources=routes/custom-host,verbs=create
+//+kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch
+//+kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -68,6 +83,7 @@ func (r *MarklogicClusterReconciler) Reconcile(ctx context.Context, req ctrl.Req
 	if err != nil {
 		if errors.IsNotFound(err) {
 			logger.Info("MarkLogicCluster resource not found. Exiting reconcile loop since there is nothing to do")
+			k8sutil.DeleteClusterMetrics(req.Namespace, req.Name)
 			return ctrl.Result{}, nil
 		}
 
@@ -88,19 +104,16 @@ func (r *MarklogicClusterReconciler) Reconcile(ctx context.Context, req ctrl.Req
 func markLogicClusterCreateUpdateDeletePredicate() predicate.Predicate {
 	return predicate.Funcs{
 		CreateFunc: func(e event.CreateEvent) bool {
+			if node, ok := e.Object.(*corev1.Node); ok {
+				return k8sutil.NodeDraining(node) // Reconcile for nodes drained before the operator started
+			}
 			return true // Reconcile on create
 		},
 		UpdateFunc: func(e event.UpdateEvent) bool {
 			switch e.ObjectNew.(type) {
 			case *marklogicv1.MarklogicCluster:
-				oldAnnotations := e.ObjectOld.GetAnnotations()
-				newAnnotations := e.ObjectNew.GetAnnotations()
-				delete(newAnnotations, "banzaicloud.com/last-applied")
-				delete(oldAnnotations, "banzaicloud.com/last-applied")
-				delete(newAnnotations, "kubectl.kubernetes.io/last-applied-configuration")
-				delete(oldAnnotations, "kubectl.kubernetes.io/last-applied-configuration")
-				if !reflect.DeepEqual(oldAnnotations, newAnnotations) {
-					return true // Reconcile if annotations have changed
+				if operatorAnnotationsChanged(e.ObjectOld.GetAnnotations(), e.ObjectNew.GetAnnotations()) {
+					return true // Reconcile if an annotation of the operator has changed, not on annotation churn of other controllers
 				}
 				oldLables := e.ObjectOld.GetLabels()
 				newLabels := e.ObjectNew.GetLabels()
@@ -114,6 +127,24 @@ func markLogicClusterCreateUpdateDeletePredicate() predicate.Predicate {
 				if !reflect.DeepEqual(oldObj.Spec, newObj.Spec) {
 					return true // Reconcile if spec has changed
 				}
+			case *corev1.Secret:
+				oldObj := e.ObjectOld.(*corev1.Secret)
+				newObj := e.ObjectNew.(*corev1.Secret)
+				return !reflect.DeepEqual(oldObj.Data, newObj.Data) // Reconcile if referenced or owned credentials changed
+			case *corev1.ConfigMap:
+				oldObj := e.ObjectOld.(*corev1.ConfigMap)
+				newObj := e.ObjectNew.(*corev1.ConfigMap)
+				return !reflect.DeepEqual(oldObj.Data, newObj.Data) || !reflect.DeepEqual(oldObj.BinaryData, newObj.BinaryData) // Reconcile if referenced modules or the HAProxy configuration changed
+			case *corev1.Service:
+				oldObj := e.ObjectOld.(*corev1.Service)
+				newObj := e.ObjectNew.(*corev1.Service)
+				return !reflect.DeepEqual(oldObj.Spec, newObj.Spec) || !reflect.DeepEqual(oldObj.GetLabels(), newObj.GetLabels()) // Reconcile if the HAProxy Service drifted
+			case *appsv1.Deployment:
+				return childMetadataChanged(e) // Reconcile if the HAProxy Deployment drifted, not on its status updates
+			case *corev1.Node:
+				oldObj := e.ObjectOld.(*corev1.Node)
+				newObj := e.ObjectNew.(*corev1.Node)
+				return k8sutil.NodeDraining(oldObj) != k8sutil.NodeDraining(newObj) // Reconcile if the node was cordoned, uncordoned or tainted by an autoscaler
 			default:
 				return false // Ignore updates for other types
 			}
@@ -121,7 +152,8 @@ func markLogicClusterCreateUpdateDeletePredicate() predicate.Predicate {
 
 		},
 		DeleteFunc: func(e event.DeleteEvent) bool {
-			return true // Reconcile on delete
+			_, isNode := e.Object.(*corev1.Node)
+			return !isNode // Reconcile on delete
 		},
 		GenericFunc: func(e event.GenericEvent) bool {
 			return false // Ignore generic events (optional)
@@ -135,5 +167,70 @@ func (r *MarklogicClusterReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		For(&marklogicv1.MarklogicCluster{}).
 		WithEventFilter(markLogicClusterCreateUpdateDeletePredicate()).
 		Owns(&marklogicv1.MarklogicGroup{}).
+		Owns(&appsv1.Deployment{}).
+		Owns(&corev1.Service{}).
+		Owns(&corev1.ConfigMap{}).
+		Owns(&corev1.Secret{}).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.secretToMarklogicClusters)).
+		Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(r.configMapToMarklogicClusters)).
+		Watches(&corev1.Node{}, handler.EnqueueRequestsFromMapFunc(r.nodeToMarklogicClusters)).
 		Complete(r)
 }
+
+// secretToMarklogicClusters maps an admin credentials Secret to the clusters
+// using it, so a rotated password is applied without a spec change.
+func (r *MarklogicClusterReconciler) secretToMarklogicClusters(ctx context.Context, obj client.Object) []reconcile.Request {
+	clusters := &marklogicv1.MarklogicClusterList{}
+	if err := r.List(ctx, clusters, client.InNamespace(obj.GetNamespace())); err != nil {
+		return nil
+	}
+	requests := []reconcile.Request{}
+	for i := range clusters.Items {
+		if k8sutil.AdminSecretName(&clusters.Items[i]) == obj.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+				Name:      clusters.Items[i].Name,
+				Namespace: obj.GetNamespace(),
+			}})
+		}
+	}
+	return requests
+}
+
+// configMapToMarklogicClusters maps a ConfigMap of modules to the clusters
+// loading them, so changed modules are loaded without a spec change.
+func (r *MarklogicClusterReconciler) configMapToMarklogicClusters(ctx context.Context, obj client.Object) []reconcile.Request {
+	clusters := &marklogicv1.MarklogicClusterList{}
+	if err := r.List(ctx, clusters, client.InNamespace(obj.GetNamespace())); err != nil {
+		return nil
+	}
+	requests := []reconcile.Request{}
+	for i := range clusters.Items {
+		if modules := clusters.Items[i].Spec.Modules; modules != nil && modules.ConfigMap == obj.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+				Name:      clusters.Items[i].Name,
+				Namespace: obj.GetNamespace(),
+			}})
+		}
+	}
+	return requests
+}
+
+// nodeToMarklogicClusters maps a node that was cordoned, uncordoned or
+// tainted by an autoscaler to the clusters that coordinate the node drain, so
+// their pods on the node fail over before the node is drained.
+func (r *MarklogicClusterReconciler) nodeToMarklogicClusters(ctx context.Context, obj client.Object) []reconcile.Request {
+	clusters := &marklogicv1.MarklogicClusterList{}
+	if err := r.List(ctx, clusters); err != nil {
+		return nil
+	}
+	requests := []reconcile.Request{}
+	for i := range clusters.Items {
+		if k8sutil.NodeDrainEnabled(&clusters.Items[i]) {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+				Name:      clusters.Items[i].Name,
+				Namespace: clusters.Items[i].Namespace,
+			}})
+		}
+	}
+	return requests
+}
@@ -29,8 +29,10 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 
 	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/k8sutil"
 )
 
 var clusterName = "marklogic-cluster-test"
@@ -88,6 +90,8 @@ var _ = Describe("MarklogicCluster Controller", func() {
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      clusterName,
 					Namespace: clusterNS,
+					// envtest has no nodes or storage classes to pass the pre-flight checks.
+					Annotations: map[string]string{k8sutil.SkipPreflightAnnotation: "true"},
 				},
 				Spec: marklogicv1.MarklogicClusterSpec{
 					Image:            imageName,
@@ -211,6 +215,8 @@ var _ = Describe("MarklogicCluster Controller", func() {
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      dynamicClusterName,
 					Namespace: dynamicClusterNS,
+					// envtest has no nodes or storage classes to pass the pre-flight checks.
+					Annotations: map[string]string{k8sutil.SkipPreflightAnnotation: "true"},
 				},
 				Spec: marklogicv1.MarklogicClusterSpec{
 					Image: imageName,
@@ -295,3 +301,53 @@ var _ = Describe("MarklogicCluster Controller", func() {
 		})
 	})
 })
+
+var _ = Describe("MarklogicCluster watch predicates", func() {
+	It("Should reconcile on drift of child resources but not on their status updates", func() {
+		clusterPredicate := markLogicClusterCreateUpdateDeletePredicate()
+		deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "marklogic-haproxy", Generation: 1}}
+		available := deployment.DeepCopy()
+		available.Status.AvailableReplicas = 1
+		Expect(clusterPredicate.Update(event.UpdateEvent{ObjectOld: deployment, ObjectNew: available})).Should(BeFalse())
+		edited := available.DeepCopy()
+		edited.Generation = 2
+		Expect(clusterPredicate.Update(event.UpdateEvent{ObjectOld: available, ObjectNew: edited})).Should(BeTrue())
+
+		service := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "marklogic-haproxy"}, Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP}}
+		loadBalanced := service.DeepCopy()
+		loadBalanced.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "10.0.0.1"}}
+		Expect(clusterPredicate.Update(event.UpdateEvent{ObjectOld: service, ObjectNew: loadBalanced})).Should(BeFalse())
+		retyped := service.DeepCopy()
+		retyped.Spec.Type = corev1.ServiceTypeNodePort
+		Expect(clusterPredicate.Update(event.UpdateEvent{ObjectOld: service, ObjectNew: retyped})).Should(BeTrue())
+
+		groupPredicate := markLogicGroupCreateUpdateDeletePredicate()
+		statefulSet := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "dnode", Generation: 1}}
+		observed := statefulSet.DeepCopy()
+		observed.Status.ObservedGeneration = 1
+		Expect(groupPredicate.Update(event.UpdateEvent{ObjectOld: statefulSet, ObjectNew: observed})).Should(BeFalse())
+		ready := observed.DeepCopy()
+		ready.Status.ReadyReplicas = 1
+		Expect(groupPredicate.Update(event.UpdateEvent{ObjectOld: observed, ObjectNew: ready})).Should(BeTrue())
+	})
+
+	It("Should ignore status writes and the annotations of other controllers", func() {
+		clusterPredicate := markLogicClusterCreateUpdateDeletePredicate()
+		cluster := &marklogicv1.MarklogicCluster{ObjectMeta: metav1.ObjectMeta{Name: "marklogic", Generation: 1}}
+		reported := cluster.DeepCopy()
+		reported.Status.Phase = "Ready"
+		Expect(clusterPredicate.Update(event.UpdateEvent{ObjectOld: cluster, ObjectNew: reported})).Should(BeFalse())
+		tracked := cluster.DeepCopy()
+		tracked.Annotations = map[string]string{
+			"argocd.argoproj.io/tracking-id":                   "marklogic:marklogic.progress.com/MarklogicCluster:default/marklogic",
+			"kubectl.kubernetes.io/last-applied-configuration": "{}",
+		}
+		Expect(clusterPredicate.Update(event.UpdateEvent{ObjectOld: cluster, ObjectNew: tracked})).Should(BeFalse())
+		exported := tracked.DeepCopy()
+		exported.Annotations[k8sutil.ExportAnnotation] = "true"
+		Expect(clusterPredicate.Update(event.UpdateEvent{ObjectOld: tracked, ObjectNew: exported})).Should(BeTrue())
+		kicked := tracked.DeepCopy()
+		kicked.Annotations["e2e.marklogic.progress.com/reconcile-kick"] = "1"
+		Expect(clusterPredicate.Update(event.UpdateEvent{ObjectOld: tracked, ObjectNew: kicked})).Should(BeTrue())
+	})
+})
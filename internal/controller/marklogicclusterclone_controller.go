@@ -0,0 +1,64 @@
+/*
+Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/k8sutil"
+)
+
+// MarklogicClusterCloneReconciler reconciles a MarklogicClusterClone object
+type MarklogicClusterCloneReconciler struct {
+	client.Client
+	Log      logr.Logger
+	Recorder record.EventRecorder
+}
+
+//+kubebuilder:rbac:groups=marklogic.progress.com,resources=marklogicclusterclones,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=marklogic.progress.com,resources=marklogicclusterclones/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=marklogic.progress.com,resources=marklogicclusterclones/finalizers,verbs=update
+//+kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshots,verbs=get;list;watch;create;delete
+
+// Reconcile creates the MarklogicCluster of a clone and fills it with the
+// data of the source cluster.
+func (r *MarklogicClusterCloneReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	cl, err := k8sutil.CreateCloneContext(ctx, &req, r.Client, r.Recorder)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		r.Log.Error(err, "Failed to get MarklogicClusterClone resource", "clone", req.NamespacedName)
+		return ctrl.Result{}, err
+	}
+	return cl.ReconcileClusterClone().Output()
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *MarklogicClusterCloneReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&marklogicv1.MarklogicClusterClone{}).
+		Owns(&marklogicv1.MarklogicCluster{}).
+		Complete(r)
+}
@@ -22,6 +22,7 @@ import (
 
 	"github.com/go-logr/logr"
 	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/faultinject"
 	"github.com/marklogic/marklogic-operator-kubernetes/pkg/k8sutil"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -44,6 +45,8 @@ type MarklogicGroupReconciler struct {
 	Scheme   *runtime.Scheme
 	Log      logr.Logger
 	Recorder record.EventRecorder
+	// FaultInjector is only set when the operator runs with --fault-injection.
+	FaultInjector *faultinject.Injector
 }
 
 const (
@@ -57,8 +60,11 @@ const (
 //+kubebuilder:rbac:groups=marklogic.progress.com,resources=marklogicgroups/finalizers,verbs=update
 //+kubebuilder:rbac:groups=apps,resources=statefulsets;replicasets;deployments;daemonsets,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=core,resources=pods;services;secrets;configmaps,verbs=get;list;watch;create;update;patch;delete
-//+kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;watch;patch;update
+//+kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;watch;create;patch;update;delete
+//+kubebuilder:rbac:groups=core,resources=persistentvolumes,verbs=get;list;watch;patch
+//+kubebuilder:rbac:groups=core,resources=resourcequotas;limitranges,verbs=get;list;watch
 //+kubebuilder:rbac:groups=core,resources=persistentvolumeclaims/status,verbs=get
+//+kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;delete
 //+kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get;list;watch
 //+kubebuilder:rbac:groups=core;events.k8s.io,resources=events,verbs=create;patch;update
 //+kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;watch;create;update;patch;delete
@@ -94,6 +100,16 @@ func (r *MarklogicGroupReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		return ctrl.Result{}, err
 	}
 
+	if r.FaultInjector != nil {
+		killed, err := r.FaultInjector.MaybeKillPod(ctx, r.Client, req.Namespace, oc.MarklogicGroup.Spec.Name)
+		if err != nil {
+			logger.Error(err, "Fault injection failed to delete pod")
+		} else if killed != "" {
+			logger.Info("Fault injection deleted pod", "pod", killed)
+			r.Recorder.Event(oc.MarklogicGroup, "Warning", "FaultInjected", "fault injection deleted pod "+killed)
+		}
+	}
+
 	result, err := oc.ReconsileMarklogicGroupHandler()
 	if err != nil {
 		logger.Error(err, "Error reconciling statefulset")
@@ -111,14 +127,8 @@ func markLogicGroupCreateUpdateDeletePredicate() predicate.Predicate {
 		UpdateFunc: func(e event.UpdateEvent) bool {
 			switch e.ObjectNew.(type) {
 			case *marklogicv1.MarklogicGroup:
-				oldAnnotations := e.ObjectOld.GetAnnotations()
-				newAnnotations := e.ObjectNew.GetAnnotations()
-				delete(newAnnotations, "banzaicloud.com/last-applied")
-				delete(oldAnnotations, "banzaicloud.com/last-applied")
-				delete(newAnnotations, "kubectl.kubernetes.io/last-applied-configuration")
-				delete(oldAnnotations, "kubectl.kubernetes.io/last-applied-configuration")
-				if !reflect.DeepEqual(oldAnnotations, newAnnotations) {
-					return true // Reconcile if annotations have changed
+				if operatorAnnotationsChanged(e.ObjectOld.GetAnnotations(), e.ObjectNew.GetAnnotations()) {
+					return true // Reconcile if an annotation of the operator has changed, not on annotation churn of other controllers
 				}
 				oldLables := e.ObjectOld.GetLabels()
 				newLabels := e.ObjectNew.GetLabels()
@@ -132,16 +142,21 @@ func markLogicGroupCreateUpdateDeletePredicate() predicate.Predicate {
 				}
 				return false
 			case *appsv1.StatefulSet:
-				return true // Reconcile on update of StatefulSet
+				return statefulSetChanged(e) // Reconcile on drift of the StatefulSet or a change of its ready replicas
 			case *corev1.Service:
 				oldObj := e.ObjectOld.(*corev1.Service)
 				newObj := e.ObjectNew.(*corev1.Service)
-				if !reflect.DeepEqual(oldObj.Spec, newObj.Spec) {
-					return true // Reconcile if the spec has changed
-				}
-				return false // Reconcile on update of Service
+				return !reflect.DeepEqual(oldObj.Spec, newObj.Spec) || !reflect.DeepEqual(oldObj.GetLabels(), newObj.GetLabels()) // Reconcile if the Service drifted
 			case *corev1.Pod:
 				return true // Reconcile on pod updates for dynamic host finalizer lifecycle
+			case *corev1.Secret:
+				oldObj := e.ObjectOld.(*corev1.Secret)
+				newObj := e.ObjectNew.(*corev1.Secret)
+				return !reflect.DeepEqual(oldObj.Data, newObj.Data) // Reconcile if referenced credentials or certificates changed
+			case *corev1.ConfigMap:
+				oldObj := e.ObjectOld.(*corev1.ConfigMap)
+				newObj := e.ObjectNew.(*corev1.ConfigMap)
+				return !reflect.DeepEqual(oldObj.Data, newObj.Data) || !reflect.DeepEqual(oldObj.GetLabels(), newObj.GetLabels()) // Reconcile if a scripts or log collection ConfigMap drifted
 			default:
 				return false // Ignore updates for other types
 			}
@@ -164,11 +179,36 @@ func (r *MarklogicGroupReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		WithEventFilter(markLogicGroupCreateUpdateDeletePredicate()).
 		Owns(&appsv1.StatefulSet{}).
 		Owns(&corev1.Service{}).
-		Watches(&corev1.Pod{}, handler.EnqueueRequestsFromMapFunc(r.podToMarklogicGroup))
+		Owns(&corev1.ConfigMap{}).
+		Owns(&corev1.Secret{}).
+		Watches(&corev1.Pod{}, handler.EnqueueRequestsFromMapFunc(r.podToMarklogicGroup)).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.secretToMarklogicGroups))
 
 	return builder.Complete(r)
 }
 
+// secretToMarklogicGroups maps a Secret to the groups that reference it for
+// admin credentials, TLS certificates or log output credentials.
+func (r *MarklogicGroupReconciler) secretToMarklogicGroups(ctx context.Context, obj client.Object) []reconcile.Request {
+	groups := &marklogicv1.MarklogicGroupList{}
+	if err := r.List(ctx, groups, client.InNamespace(obj.GetNamespace())); err != nil {
+		return nil
+	}
+	requests := []reconcile.Request{}
+	for i := range groups.Items {
+		for _, name := range k8sutil.ReferencedSecretNames(&groups.Items[i]) {
+			if name == obj.GetName() {
+				requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+					Name:      groups.Items[i].Name,
+					Namespace: obj.GetNamespace(),
+				}})
+				break
+			}
+		}
+	}
+	return requests
+}
+
 // podToMarklogicGroup maps a Pod to its owning MarklogicGroup by traversing
 // the ownership chain: Pod -> StatefulSet -> MarklogicGroup.
 func (r *MarklogicGroupReconciler) podToMarklogicGroup(ctx context.Context, obj client.Object) []reconcile.Request {
@@ -2827,3 +2827,163 @@ func hasOrderedSubsequence(calls []string, expected []string) bool {
 	}
 	return false
 }
+
+func (f *fakeDynamicManagementClient) SetCloudCredentials(ctx context.Context, creds mlmanage.CloudCredentials) error {
+	f.record("SetCloudCredentials")
+	return nil
+}
+
+func (f *fakeDynamicManagementClient) SetGroupS3Domain(ctx context.Context, groupName, domain string) error {
+	f.record("SetGroupS3Domain")
+	return nil
+}
+
+func (f *fakeDynamicManagementClient) SetDatabaseBackups(ctx context.Context, database string, schedules []mlmanage.DatabaseBackupSchedule) error {
+	f.record("SetDatabaseBackups")
+	return nil
+}
+
+func (f *fakeDynamicManagementClient) GetDatabaseBackupStatus(ctx context.Context, database string) (mlmanage.DatabaseBackupStatus, error) {
+	f.record("GetDatabaseBackupStatus")
+	return mlmanage.DatabaseBackupStatus{}, nil
+}
+
+func (f *fakeDynamicManagementClient) ListDatabaseForests(ctx context.Context, database string) ([]string, error) {
+	f.record("ListDatabaseForests")
+	return nil, nil
+}
+
+func (f *fakeDynamicManagementClient) CreateForest(ctx context.Context, forest mlmanage.ForestSpec) (bool, error) {
+	f.record("CreateForest")
+	return false, nil
+}
+
+func (f *fakeDynamicManagementClient) ListDatabasePartitions(ctx context.Context, database string) ([]mlmanage.Partition, error) {
+	f.record("ListDatabasePartitions")
+	return nil, nil
+}
+
+func (f *fakeDynamicManagementClient) MigratePartition(ctx context.Context, database, partition, dataDirectory string) error {
+	f.record("MigratePartition")
+	return nil
+}
+
+func (f *fakeDynamicManagementClient) GetDatabaseProperties(ctx context.Context, database string) (map[string]any, error) {
+	f.record("GetDatabaseProperties")
+	return map[string]any{}, nil
+}
+
+func (f *fakeDynamicManagementClient) CreateDatabase(ctx context.Context, properties map[string]any) (bool, error) {
+	f.record("CreateDatabase")
+	return false, nil
+}
+
+func (f *fakeDynamicManagementClient) GetDatabaseReplicationLag(ctx context.Context, database string) (time.Duration, error) {
+	f.record("GetDatabaseReplicationLag")
+	return 0, nil
+}
+
+func (f *fakeDynamicManagementClient) GetForestDocumentCount(ctx context.Context, forest string) (int64, error) {
+	f.record("GetForestDocumentCount")
+	return 0, nil
+}
+
+func (f *fakeDynamicManagementClient) GetDatabaseRebalancerThrottle(ctx context.Context, database string) (int32, error) {
+	f.record("GetDatabaseRebalancerThrottle")
+	return 5, nil
+}
+
+func (f *fakeDynamicManagementClient) SetDatabaseRebalancerThrottle(ctx context.Context, database string, throttle int32) error {
+	f.record("SetDatabaseRebalancerThrottle")
+	return nil
+}
+
+func (f *fakeDynamicManagementClient) StartDatabaseRestore(ctx context.Context, database string, req mlmanage.DatabaseRestoreRequest) (string, error) {
+	f.record("StartDatabaseRestore")
+	return "", nil
+}
+
+func (f *fakeDynamicManagementClient) GetDatabaseRestoreStatus(ctx context.Context, database, jobID string) (mlmanage.DatabaseRestoreStatus, error) {
+	f.record("GetDatabaseRestoreStatus")
+	return mlmanage.DatabaseRestoreStatus{}, nil
+}
+
+func (f *fakeDynamicManagementClient) ListForestsStatus(ctx context.Context) ([]mlmanage.ForestStatus, error) {
+	f.record("ListForestsStatus")
+	return nil, nil
+}
+
+func (f *fakeDynamicManagementClient) ListForestReplicas(ctx context.Context, forest string) ([]string, error) {
+	f.record("ListForestReplicas")
+	return nil, nil
+}
+
+func (f *fakeDynamicManagementClient) RestartForest(ctx context.Context, forest string) error {
+	f.record("RestartForest")
+	return nil
+}
+
+func (f *fakeDynamicManagementClient) GetHostLicense(ctx context.Context, hostName string) (mlmanage.HostLicense, error) {
+	f.record("GetHostLicense")
+	return mlmanage.HostLicense{}, nil
+}
+
+func (f *fakeDynamicManagementClient) ListForeignClustersStatus(ctx context.Context, clusterName string) ([]mlmanage.ForeignClusterStatus, error) {
+	f.record("ListForeignClustersStatus")
+	return nil, nil
+}
+
+func (f *fakeDynamicManagementClient) SetHostZone(ctx context.Context, hostName, zone string) error {
+	f.record("SetHostZone")
+	return nil
+}
+
+func (f *fakeDynamicManagementClient) SetHostName(ctx context.Context, hostName, newName string) error {
+	f.record("SetHostName")
+	return nil
+}
+
+func (f *fakeDynamicManagementClient) GetStatusView(ctx context.Context, resource string) ([]byte, error) {
+	f.record("GetStatusView")
+	return []byte("{}"), nil
+}
+
+func (f *fakeDynamicManagementClient) GetGroupDiagnostics(ctx context.Context, groupName string) (mlmanage.GroupDiagnostics, error) {
+	f.record("GetGroupDiagnostics")
+	return mlmanage.GroupDiagnostics{}, nil
+}
+
+func (f *fakeDynamicManagementClient) SetGroupDiagnostics(ctx context.Context, groupName string, diagnostics mlmanage.GroupDiagnostics) error {
+	f.record("SetGroupDiagnostics")
+	return nil
+}
+
+func (f *fakeDynamicManagementClient) SetAppServerProperties(ctx context.Context, groupName, serverName string, properties map[string]any) error {
+	f.record("SetAppServerProperties")
+	return nil
+}
+
+func (f *fakeDynamicManagementClient) GetGroupLoad(ctx context.Context, groupName string) (mlmanage.GroupLoad, error) {
+	f.record("GetGroupLoad")
+	return mlmanage.GroupLoad{}, nil
+}
+
+func (f *fakeDynamicManagementClient) UpgradeSecurityDatabase(ctx context.Context) (bool, error) {
+	f.record("UpgradeSecurityDatabase")
+	return true, nil
+}
+
+func (f *fakeDynamicManagementClient) GetSSLFIPSEnabled(ctx context.Context) (bool, error) {
+	f.record("GetSSLFIPSEnabled")
+	return true, nil
+}
+
+func (f *fakeDynamicManagementClient) SetSSLFIPSEnabled(ctx context.Context, enabled bool) error {
+	f.record("SetSSLFIPSEnabled")
+	return nil
+}
+
+func (f *fakeDynamicManagementClient) SetUserPassword(ctx context.Context, username, password string) error {
+	f.record("SetUserPassword")
+	return nil
+}
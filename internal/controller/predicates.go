@@ -0,0 +1,73 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package controller
+
+import (
+	"maps"
+	"reflect"
+	"strings"
+
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/k8sutil"
+	appsv1 "k8s.io/api/apps/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+// childMetadataChanged reports whether an update of a child resource changed
+// its spec, which bumps the generation, or its labels or annotations. Updates
+// of only the status of a child, such as the availability of a Deployment,
+// leave all three alone and do not trigger a reconcile.
+func childMetadataChanged(e event.UpdateEvent) bool {
+	return e.ObjectOld.GetGeneration() != e.ObjectNew.GetGeneration() ||
+		!reflect.DeepEqual(e.ObjectOld.GetLabels(), e.ObjectNew.GetLabels()) ||
+		!reflect.DeepEqual(withoutSpecTracking(e.ObjectOld.GetAnnotations()), withoutSpecTracking(e.ObjectNew.GetAnnotations()))
+}
+
+// withoutSpecTracking drops the spec hash and observed generation the
+// operator records on the resources it writes, so recording them does not
+// trigger another reconcile.
+func withoutSpecTracking(annotations map[string]string) map[string]string {
+	filtered := maps.Clone(annotations)
+	delete(filtered, k8sutil.SpecHashAnnotation)
+	delete(filtered, k8sutil.ObservedGenerationAnnotation)
+	return filtered
+}
+
+// statefulSetChanged reports whether an update of a StatefulSet of a group
+// changed its spec or metadata, or the number of its replicas that are
+// ready. Other status updates, such as the observed generation or the
+// revisions while pods roll, do not trigger a reconcile; the pods of the
+// group are watched on their own.
+func statefulSetChanged(e event.UpdateEvent) bool {
+	if childMetadataChanged(e) {
+		return true
+	}
+	oldObj := e.ObjectOld.(*appsv1.StatefulSet)
+	newObj := e.ObjectNew.(*appsv1.StatefulSet)
+	return oldObj.Status.Replicas != newObj.Status.Replicas || oldObj.Status.ReadyReplicas != newObj.Status.ReadyReplicas
+}
+
+// operatorAnnotationDomain is the domain of the annotations that request
+// something of the operator, such as marklogic.progress.com/export.
+const operatorAnnotationDomain = "marklogic.progress.com"
+
+// operatorAnnotationsChanged reports whether an update changed an annotation
+// of the operator's domain or one of its subdomains. Other annotations are
+// churned by other controllers, such as the tracking annotations of GitOps
+// tools and last-applied configurations, and do not trigger a reconcile.
+func operatorAnnotationsChanged(oldAnnotations, newAnnotations map[string]string) bool {
+	return !reflect.DeepEqual(operatorAnnotations(oldAnnotations), operatorAnnotations(newAnnotations))
+}
+
+func operatorAnnotations(annotations map[string]string) map[string]string {
+	filtered := map[string]string{}
+	for key, value := range annotations {
+		domain, _, found := strings.Cut(key, "/")
+		if key == k8sutil.SpecHashAnnotation || key == k8sutil.ObservedGenerationAnnotation {
+			continue
+		}
+		if found && (domain == operatorAnnotationDomain || strings.HasSuffix(domain, "."+operatorAnnotationDomain)) {
+			filtered[key] = value
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,98 @@
+/*
+Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"slices"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/k8sutil"
+)
+
+// TenantRBACReconciler generates the Role and RoleBinding of the app teams
+// listed in the tenant-groups annotation of a namespace.
+type TenantRBACReconciler struct {
+	client.Client
+	Log logr.Logger
+	// WatchNamespaces limits the namespaces RBAC is generated in to those
+	// of a namespace-scoped operator. Empty means all namespaces.
+	WatchNamespaces []string
+}
+
+//+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles;rolebindings,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile applies or removes the tenant RBAC of a namespace.
+func (r *TenantRBACReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ns := &corev1.Namespace{}
+	if err := r.Get(ctx, req.NamespacedName, ns); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	if err := k8sutil.ReconcileTenantRBAC(ctx, r.Client, ns); err != nil {
+		r.Log.Error(err, "Failed to reconcile tenant RBAC", "namespace", ns.Name)
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// tenantNamespacePredicate passes the namespaces that have or had the
+// tenant-groups annotation, so the other namespaces are never reconciled.
+func (r *TenantRBACReconciler) tenantNamespacePredicate() predicate.Predicate {
+	watched := func(obj client.Object) bool {
+		return len(r.WatchNamespaces) == 0 || slices.Contains(r.WatchNamespaces, obj.GetName())
+	}
+	annotated := func(obj client.Object) bool {
+		_, ok := obj.GetAnnotations()[k8sutil.TenantGroupsAnnotation]
+		return ok
+	}
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return watched(e.Object) && annotated(e.Object)
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if !watched(e.ObjectNew) || (!annotated(e.ObjectOld) && !annotated(e.ObjectNew)) {
+				return false
+			}
+			return e.ObjectOld.GetAnnotations()[k8sutil.TenantGroupsAnnotation] != e.ObjectNew.GetAnnotations()[k8sutil.TenantGroupsAnnotation]
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return false // the Role and RoleBinding go with the namespace
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
+		},
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *TenantRBACReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("tenantrbac").
+		For(&corev1.Namespace{}, builder.WithPredicates(r.tenantNamespacePredicate())).
+		Complete(r)
+}
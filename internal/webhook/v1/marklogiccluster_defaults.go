@@ -0,0 +1,73 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package v1
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+)
+
+//+kubebuilder:webhook:path=/mutate-marklogic-progress-com-v1-marklogiccluster,mutating=true,failurePolicy=fail,sideEffects=None,groups=marklogic.progress.com,resources=marklogicclusters,verbs=create,versions=v1,name=mmarklogiccluster-v1.marklogic.progress.com,admissionReviewVersions=v1
+
+// MarklogicClusterCustomDefaulter expands spec.profile into the groups of its
+// topology when a cluster is created without spec.markLogicGroups. Clusters
+// that list their groups keep them, and the groups are stored in the spec so
+// later changes of the profile do not touch a running cluster.
+type MarklogicClusterCustomDefaulter struct{}
+
+var _ webhook.CustomDefaulter = &MarklogicClusterCustomDefaulter{}
+
+// Default implements webhook.CustomDefaulter.
+func (d *MarklogicClusterCustomDefaulter) Default(_ context.Context, obj runtime.Object) error {
+	cluster, ok := obj.(*marklogicv1.MarklogicCluster)
+	if !ok {
+		return fmt.Errorf("expected a MarklogicCluster object but got %T", obj)
+	}
+	if cluster.Spec.Profile == "" || len(cluster.Spec.MarkLogicGroups) > 0 {
+		return nil
+	}
+	cluster.Spec.MarkLogicGroups = profileGroups(cluster.Spec.Profile)
+	marklogicclusterlog.Info("Expanded the cluster profile", "name", cluster.Name, "namespace", cluster.Namespace, "profile", cluster.Spec.Profile)
+	return nil
+}
+
+// profileGroups returns the groups of a cluster profile. In profiles with
+// more than one group, every group names its MarkLogic group after itself,
+// since MarkLogic group names must be unique. The groups do not enable huge
+// pages, which only schedule on nodes with huge pages pre-allocated.
+func profileGroups(profile marklogicv1.ClusterProfile) []*marklogicv1.MarklogicGroups {
+	replicas := func(n int32) *int32 { return &n }
+	noHugePages := func() *marklogicv1.HugePages {
+		return &marklogicv1.HugePages{Enabled: false, MountPath: "/dev/hugepages"}
+	}
+	switch profile {
+	case marklogicv1.ClusterProfileSingleNode:
+		return []*marklogicv1.MarklogicGroups{{Name: "node", Replicas: replicas(1), IsBootstrap: true}}
+	case marklogicv1.ClusterProfileThreeNodeHA:
+		return []*marklogicv1.MarklogicGroups{{Name: "node", Replicas: replicas(3), IsBootstrap: true}}
+	case marklogicv1.ClusterProfileENodeDNode:
+		return []*marklogicv1.MarklogicGroups{
+			{
+				Name:        "dnode",
+				Replicas:    replicas(3),
+				IsBootstrap: true,
+				Profile:     marklogicv1.GroupProfileDNode,
+				GroupConfig: &marklogicv1.GroupConfig{Name: "dnode", EnableXdqpSsl: true},
+				HugePages:   noHugePages(),
+			},
+			{
+				Name:        "enode",
+				Replicas:    replicas(2),
+				Profile:     marklogicv1.GroupProfileENode,
+				GroupConfig: &marklogicv1.GroupConfig{Name: "enode", EnableXdqpSsl: true},
+				HugePages:   noHugePages(),
+			},
+		}
+	}
+	return nil
+}
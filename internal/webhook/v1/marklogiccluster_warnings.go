@@ -0,0 +1,106 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package v1
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+)
+
+// productionNamespaceLabels are the namespace labels whose value prod or
+// production marks the namespace as production.
+var productionNamespaceLabels = []string{"environment", "env"}
+
+// riskyConfigWarnings returns a warning for every setting of a cluster that
+// is allowed but risky, so users learn about it without being blocked.
+func (v *MarklogicClusterCustomValidator) riskyConfigWarnings(ctx context.Context, cluster *marklogicv1.MarklogicCluster) admission.Warnings {
+	warnings := admission.Warnings{}
+	spec := cluster.Spec
+
+	hosts := int32(0)
+	var bootstrap *marklogicv1.MarklogicGroups
+	for _, group := range spec.MarkLogicGroups {
+		if group == nil || group.IsDynamic {
+			continue
+		}
+		hosts += groupReplicas(group)
+		if group.IsBootstrap {
+			bootstrap = group
+		}
+	}
+	if bootstrap != nil && groupReplicas(bootstrap) == 1 && hosts == 1 {
+		warnings = append(warnings, fmt.Sprintf("spec.markLogicGroups: the bootstrap group %s is the only host of the cluster, so its forests have no replicas and losing the host or its volume loses the cluster", bootstrap.Name))
+	}
+
+	if v.productionNamespace(ctx, cluster.Namespace) {
+		for _, group := range spec.MarkLogicGroups {
+			if group == nil || group.IsDynamic {
+				continue
+			}
+			persistence := spec.Persistence
+			if group.Persistence != nil {
+				persistence = group.Persistence
+			}
+			if persistence == nil || !persistence.Enabled {
+				warnings = append(warnings, fmt.Sprintf("spec.markLogicGroups: group %s stores its data on ephemeral storage in the production namespace %s, the data is lost when a pod is rescheduled", group.Name, cluster.Namespace))
+			}
+		}
+	}
+
+	disabled, unparsed := []string{}, []string{}
+	for _, group := range spec.MarkLogicGroups {
+		if group == nil {
+			continue
+		}
+		logCollection := spec.LogCollection
+		if group.LogCollection != nil {
+			logCollection = group.LogCollection
+		}
+		if logCollection == nil || !logCollection.Enabled {
+			disabled = append(disabled, group.Name)
+			continue
+		}
+		if logCollection.Engine != "" && logCollection.Engine != marklogicv1.LogCollectionEngineFluentBit && strings.TrimSpace(logCollection.Parsers) != "" {
+			unparsed = append(unparsed, group.Name)
+		}
+	}
+	if len(disabled) > 0 {
+		warnings = append(warnings, fmt.Sprintf("spec.logCollection: log collection is disabled for %s, the MarkLogic logs are lost with their pods", strings.Join(disabled, ", ")))
+	}
+	if len(unparsed) > 0 {
+		warnings = append(warnings, fmt.Sprintf("spec.logCollection: parsers are only read by the fluent-bit engine and are ignored for %s, parse the logs in the inputs or filters of the engine instead", strings.Join(unparsed, ", ")))
+	}
+
+	if len(warnings) == 0 {
+		return nil
+	}
+	return warnings
+}
+
+// productionNamespace reports whether a namespace is labelled as production.
+// Without a Namespaces reader, or when the namespace cannot be read, it is
+// not.
+func (v *MarklogicClusterCustomValidator) productionNamespace(ctx context.Context, name string) bool {
+	if v.Namespaces == nil || name == "" {
+		return false
+	}
+	namespace := &corev1.Namespace{}
+	if err := v.Namespaces.Get(ctx, types.NamespacedName{Name: name}, namespace); err != nil {
+		marklogicclusterlog.V(1).Info("Failed to read the namespace for warnings", "namespace", name, "error", err.Error())
+		return false
+	}
+	for _, label := range productionNamespaceLabels {
+		switch strings.ToLower(namespace.Labels[label]) {
+		case "prod", "production":
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,286 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package v1
+
+import (
+	"context"
+	"fmt"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/k8sutil"
+)
+
+// ForceChangeDuringUpgradeAnnotation lets a MarklogicCluster change replicas,
+// storage or images while an upgrade is active when set to "true".
+const ForceChangeDuringUpgradeAnnotation = k8sutil.ForceChangeDuringUpgradeAnnotation
+
+var marklogicclusterlog = logf.Log.WithName("marklogiccluster-resource")
+
+// SetupMarklogicClusterWebhookWithManager registers the webhook for MarklogicCluster in the manager.
+// With tenantRBAC, users who cannot update MarklogicClusters may only approve upgrades.
+func SetupMarklogicClusterWebhookWithManager(mgr ctrl.Manager, tenantRBAC bool) error {
+	validator := &MarklogicClusterCustomValidator{Namespaces: mgr.GetAPIReader(), Groups: mgr.GetAPIReader()}
+	if tenantRBAC {
+		validator.Client = mgr.GetClient()
+	}
+	return ctrl.NewWebhookManagedBy(mgr).For(&marklogicv1.MarklogicCluster{}).
+		WithDefaulter(&MarklogicClusterCustomDefaulter{}).
+		WithValidator(validator).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-marklogic-progress-com-v1-marklogiccluster,mutating=false,failurePolicy=fail,sideEffects=None,groups=marklogic.progress.com,resources=marklogicclusters,verbs=create;update,versions=v1,name=vmarklogiccluster-v1.marklogic.progress.com,admissionReviewVersions=v1
+
+// MarklogicClusterCustomValidator rejects changes of replicas, storage and
+// images while the upgrade workflow runs its prechecks, waits for approval or
+// rolls out a new image, so scaling and resizing never interleave with the
+// pod restarts of the upgrade. Reverting spec.image to the current image,
+// which cancels the upgrade, is always allowed.
+//
+// When Client is set, a user that may patch but not update MarklogicClusters,
+// such as the app teams bound by the tenant RBAC of the operator, may change
+// nothing but the approve-upgrade annotation.
+//
+// Settings that are allowed but risky, such as ephemeral storage in a
+// production namespace, are admitted with a warning on create and update.
+// Namespaces reads the labels of the namespace of a cluster for them.
+//
+// When Groups is set, a cluster may not add a group named like a
+// MarklogicGroup of another cluster in its namespace, as the resources of a
+// group are named after the group alone.
+type MarklogicClusterCustomValidator struct {
+	Client     client.Client
+	Namespaces client.Reader
+	Groups     client.Reader
+}
+
+//+kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
+
+var _ webhook.CustomValidator = &MarklogicClusterCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator.
+func (v *MarklogicClusterCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	cluster, ok := obj.(*marklogicv1.MarklogicCluster)
+	if !ok {
+		return nil, fmt.Errorf("expected a MarklogicCluster object but got %T", obj)
+	}
+	if errs := v.groupNameConflicts(ctx, nil, cluster); len(errs) > 0 {
+		return nil, apierrors.NewInvalid(marklogicv1.GroupVersion.WithKind("MarklogicCluster").GroupKind(), cluster.Name, errs)
+	}
+	return v.riskyConfigWarnings(ctx, cluster), nil
+}
+
+// ValidateUpdate implements webhook.CustomValidator.
+func (v *MarklogicClusterCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	oldCluster, ok := oldObj.(*marklogicv1.MarklogicCluster)
+	if !ok {
+		return nil, fmt.Errorf("expected a MarklogicCluster object for the oldObj but got %T", oldObj)
+	}
+	cluster, ok := newObj.(*marklogicv1.MarklogicCluster)
+	if !ok {
+		return nil, fmt.Errorf("expected a MarklogicCluster object for the newObj but got %T", newObj)
+	}
+	if err := v.validateApprover(ctx, oldCluster, cluster); err != nil {
+		return nil, err
+	}
+	if errs := v.groupNameConflicts(ctx, oldCluster, cluster); len(errs) > 0 {
+		return nil, apierrors.NewInvalid(marklogicv1.GroupVersion.WithKind("MarklogicCluster").GroupKind(), cluster.Name, errs)
+	}
+	warnings := v.riskyConfigWarnings(ctx, cluster)
+	upgrade := oldCluster.Status.Upgrade
+	if !upgrade.Active() {
+		return warnings, nil
+	}
+	errs := changesDuringUpgrade(oldCluster, cluster)
+	if len(errs) == 0 {
+		return warnings, nil
+	}
+	if k8sutil.ClusterOperation(cluster, ForceChangeDuringUpgradeAnnotation) == "true" {
+		marklogicclusterlog.Info("Allowing changes during an active upgrade", "name", cluster.Name, "namespace", cluster.Namespace, "state", upgrade.State)
+		return append(warnings, fmt.Sprintf("changes applied during an upgrade in state %s because of the %s annotation", upgrade.State, ForceChangeDuringUpgradeAnnotation)), nil
+	}
+	return nil, apierrors.NewInvalid(marklogicv1.GroupVersion.WithKind("MarklogicCluster").GroupKind(), cluster.Name, errs)
+}
+
+// ValidateDelete implements webhook.CustomValidator.
+func (v *MarklogicClusterCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// groupNameConflicts returns the groups added to the cluster that are named
+// like a MarklogicGroup of another cluster in the namespace. Groups the old
+// cluster already had are left to the GroupNameConflict event of the
+// operator, so the cluster can still be changed.
+func (v *MarklogicClusterCustomValidator) groupNameConflicts(ctx context.Context, oldCluster, cluster *marklogicv1.MarklogicCluster) field.ErrorList {
+	if v.Groups == nil {
+		return nil
+	}
+	existing := map[string]bool{}
+	if oldCluster != nil {
+		for _, group := range oldCluster.Spec.MarkLogicGroups {
+			if group != nil {
+				existing[group.Name] = true
+			}
+		}
+	}
+	var errs field.ErrorList
+	groupsPath := field.NewPath("spec", "markLogicGroups")
+	for i, group := range cluster.Spec.MarkLogicGroups {
+		if group == nil || existing[group.Name] {
+			continue
+		}
+		mlg := &marklogicv1.MarklogicGroup{}
+		if err := v.Groups.Get(ctx, types.NamespacedName{Name: group.Name, Namespace: cluster.Namespace}, mlg); err != nil {
+			if !apierrors.IsNotFound(err) {
+				marklogicclusterlog.Error(err, "Failed to read the MarklogicGroup to check its owner", "group", group.Name, "namespace", cluster.Namespace)
+			}
+			continue
+		}
+		if owner, conflict := k8sutil.GroupOwnedByOtherCluster(cluster, mlg); conflict {
+			errs = append(errs, field.Duplicate(groupsPath.Index(i).Child("name"),
+				fmt.Sprintf("%s, already a group of MarklogicCluster %s in this namespace", group.Name, owner)))
+		}
+	}
+	return errs
+}
+
+// validateApprover rejects an update that changes more than the
+// approve-upgrade annotation when the requesting user cannot update
+// MarklogicClusters, which a SubjectAccessReview tells.
+func (v *MarklogicClusterCustomValidator) validateApprover(ctx context.Context, oldCluster, cluster *marklogicv1.MarklogicCluster) error {
+	if v.Client == nil || approvalOnlyChange(oldCluster, cluster) {
+		return nil
+	}
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return nil
+	}
+	extra := map[string]authorizationv1.ExtraValue{}
+	for k, values := range req.UserInfo.Extra {
+		extra[k] = authorizationv1.ExtraValue(values)
+	}
+	review := &authorizationv1.SubjectAccessReview{Spec: authorizationv1.SubjectAccessReviewSpec{
+		User:   req.UserInfo.Username,
+		UID:    req.UserInfo.UID,
+		Groups: req.UserInfo.Groups,
+		Extra:  extra,
+		ResourceAttributes: &authorizationv1.ResourceAttributes{
+			Namespace: cluster.Namespace,
+			Verb:      "update",
+			Group:     marklogicv1.GroupVersion.Group,
+			Resource:  "marklogicclusters",
+			Name:      cluster.Name,
+		},
+	}}
+	if err := v.Client.Create(ctx, review); err != nil {
+		return fmt.Errorf("failed to review the access of %s: %w", req.UserInfo.Username, err)
+	}
+	if review.Status.Allowed {
+		return nil
+	}
+	return apierrors.NewForbidden(marklogicv1.GroupVersion.WithResource("marklogicclusters").GroupResource(), cluster.Name,
+		fmt.Errorf("%s may only change the %s annotation", req.UserInfo.Username, k8sutil.UpgradeApprovalAnnotation))
+}
+
+// approvalOnlyChange reports whether an update leaves everything but the
+// approve-upgrade annotation and spec.operations.approveUpgrade as it was.
+func approvalOnlyChange(oldCluster, cluster *marklogicv1.MarklogicCluster) bool {
+	withoutApproval := func(annotations map[string]string) map[string]string {
+		kept := map[string]string{}
+		for k, v := range annotations {
+			if k != k8sutil.UpgradeApprovalAnnotation {
+				kept[k] = v
+			}
+		}
+		return kept
+	}
+	withoutApprovalSpec := func(spec marklogicv1.MarklogicClusterSpec) marklogicv1.MarklogicClusterSpec {
+		if spec.Operations != nil {
+			spec.Operations = spec.Operations.DeepCopy()
+			spec.Operations.ApproveUpgrade = ""
+			if *spec.Operations == (marklogicv1.Operations{}) {
+				spec.Operations = nil
+			}
+		}
+		return spec
+	}
+	return equality.Semantic.DeepEqual(withoutApprovalSpec(oldCluster.Spec), withoutApprovalSpec(cluster.Spec)) &&
+		equality.Semantic.DeepEqual(withoutApproval(oldCluster.Annotations), withoutApproval(cluster.Annotations)) &&
+		equality.Semantic.DeepEqual(oldCluster.Labels, cluster.Labels) &&
+		equality.Semantic.DeepEqual(oldCluster.Finalizers, cluster.Finalizers) &&
+		equality.Semantic.DeepEqual(oldCluster.OwnerReferences, cluster.OwnerReferences)
+}
+
+// changesDuringUpgrade returns the replica, storage and image changes between
+// the old and new spec.
+func changesDuringUpgrade(oldCluster, cluster *marklogicv1.MarklogicCluster) field.ErrorList {
+	var errs field.ErrorList
+	spec := field.NewPath("spec")
+	during := fmt.Sprintf("while the upgrade is in state %s, set the %s annotation to \"true\" to force the change",
+		oldCluster.Status.Upgrade.State, ForceChangeDuringUpgradeAnnotation)
+	detail := "cannot be changed " + during
+
+	if cluster.Spec.Image != oldCluster.Spec.Image && cluster.Spec.Image != oldCluster.Status.Upgrade.CurrentImage {
+		errs = append(errs, field.Forbidden(spec.Child("image"), detail))
+	}
+	if !equality.Semantic.DeepEqual(cluster.Spec.Persistence, oldCluster.Spec.Persistence) {
+		errs = append(errs, field.Forbidden(spec.Child("persistence"), detail))
+	}
+
+	oldGroups := map[string]*marklogicv1.MarklogicGroups{}
+	for _, group := range oldCluster.Spec.MarkLogicGroups {
+		if group != nil {
+			oldGroups[group.Name] = group
+		}
+	}
+	groupsPath := spec.Child("markLogicGroups")
+	for i, group := range cluster.Spec.MarkLogicGroups {
+		if group == nil {
+			continue
+		}
+		path := groupsPath.Index(i)
+		oldGroup, ok := oldGroups[group.Name]
+		if !ok {
+			errs = append(errs, field.Forbidden(path, fmt.Sprintf("group %s cannot be added %s", group.Name, during)))
+			continue
+		}
+		delete(oldGroups, group.Name)
+		if groupReplicas(group) != groupReplicas(oldGroup) {
+			errs = append(errs, field.Forbidden(path.Child("replicas"), detail))
+		}
+		if !equality.Semantic.DeepEqual(group.Persistence, oldGroup.Persistence) {
+			errs = append(errs, field.Forbidden(path.Child("persistence"), detail))
+		}
+		if group.Image != oldGroup.Image {
+			errs = append(errs, field.Forbidden(path.Child("image"), detail))
+		}
+	}
+	for _, group := range oldCluster.Spec.MarkLogicGroups {
+		if group == nil {
+			continue
+		}
+		if _, removed := oldGroups[group.Name]; removed {
+			errs = append(errs, field.Forbidden(groupsPath, fmt.Sprintf("group %s cannot be removed %s", group.Name, during)))
+		}
+	}
+	return errs
+}
+
+func groupReplicas(group *marklogicv1.MarklogicGroups) int32 {
+	if group.Replicas == nil {
+		return 1
+	}
+	return *group.Replicas
+}
@@ -0,0 +1,253 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package v1
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/k8sutil"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestValidateUpdateRejectsScalingDuringUpgrade(t *testing.T) {
+	replicas := int32(3)
+	oldCluster := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			Image:           "marklogic:12.0.1",
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{{Name: "dnode", Replicas: &replicas}},
+		},
+		Status: marklogicv1.MarklogicClusterStatus{Upgrade: &marklogicv1.UpgradeStatus{
+			State:        marklogicv1.UpgradeStateInProgress,
+			CurrentImage: "marklogic:12.0.0",
+			TargetImage:  "marklogic:12.0.1",
+		}},
+	}
+	validator := &MarklogicClusterCustomValidator{}
+	ctx := context.Background()
+
+	scaled := oldCluster.DeepCopy()
+	more := int32(5)
+	scaled.Spec.MarkLogicGroups[0].Replicas = &more
+	if _, err := validator.ValidateUpdate(ctx, oldCluster, scaled); err == nil {
+		t.Fatalf("expected a replica change during the upgrade to be rejected")
+	}
+
+	cancelled := oldCluster.DeepCopy()
+	cancelled.Spec.Image = "marklogic:12.0.0"
+	if _, err := validator.ValidateUpdate(ctx, oldCluster, cancelled); err != nil {
+		t.Fatalf("expected reverting the image to cancel the upgrade, got %v", err)
+	}
+
+	scaled.Annotations = map[string]string{ForceChangeDuringUpgradeAnnotation: "true"}
+	warnings, err := validator.ValidateUpdate(ctx, oldCluster, scaled)
+	if err != nil || len(warnings) == 0 {
+		t.Fatalf("expected the forced change to be admitted with a warning, got %v, %v", warnings, err)
+	}
+
+	oldCluster.Status.Upgrade.State = marklogicv1.UpgradeStateCompleted
+	scaled.Annotations = nil
+	if _, err := validator.ValidateUpdate(ctx, oldCluster, scaled); err != nil {
+		t.Fatalf("expected changes after the upgrade to be admitted, got %v", err)
+	}
+}
+
+func TestValidateUpdateLimitsApproversToTheApprovalAnnotation(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	editors := map[string]bool{"alice": true}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			review, ok := obj.(*authorizationv1.SubjectAccessReview)
+			if !ok {
+				return c.Create(ctx, obj, opts...)
+			}
+			attributes := review.Spec.ResourceAttributes
+			review.Status.Allowed = editors[review.Spec.User] && attributes.Verb == "update" && attributes.Resource == "marklogicclusters"
+			return nil
+		},
+	}).Build()
+	validator := &MarklogicClusterCustomValidator{Client: c}
+	asUser := func(user string) context.Context {
+		return admission.NewContextWithRequest(context.Background(), admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			UserInfo: authenticationv1.UserInfo{Username: user, Groups: []string{"team-a-devs"}},
+		}})
+	}
+	oldCluster := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "team-a"},
+		Spec:       marklogicv1.MarklogicClusterSpec{Image: "marklogic:12.0.1"},
+	}
+
+	approved := oldCluster.DeepCopy()
+	approved.Annotations = map[string]string{k8sutil.UpgradeApprovalAnnotation: "marklogic:12.0.1"}
+	if _, err := validator.ValidateUpdate(asUser("bob"), oldCluster, approved); err != nil {
+		t.Fatalf("expected an approver to set the approval annotation, got %v", err)
+	}
+	approvedInSpec := oldCluster.DeepCopy()
+	approvedInSpec.Spec.Operations = &marklogicv1.Operations{ApproveUpgrade: "marklogic:12.0.1"}
+	if _, err := validator.ValidateUpdate(asUser("bob"), oldCluster, approvedInSpec); err != nil {
+		t.Fatalf("expected an approver to set spec.operations.approveUpgrade, got %v", err)
+	}
+	approvedInSpec.Spec.Operations.Export = "1"
+	if _, err := validator.ValidateUpdate(asUser("bob"), oldCluster, approvedInSpec); !apierrors.IsForbidden(err) {
+		t.Fatalf("expected an approver to be forbidden to request other operations, got %v", err)
+	}
+
+	changed := approved.DeepCopy()
+	changed.Spec.Image = "marklogic:12.0.2"
+	if _, err := validator.ValidateUpdate(asUser("bob"), oldCluster, changed); !apierrors.IsForbidden(err) {
+		t.Fatalf("expected an approver to be forbidden to change the spec, got %v", err)
+	}
+	if _, err := validator.ValidateUpdate(asUser("alice"), oldCluster, changed); err != nil {
+		t.Fatalf("expected a user who can update clusters to change the spec, got %v", err)
+	}
+}
+
+func TestValidateCreateWarnsAboutRiskyConfigs(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	namespaces := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "prod", Labels: map[string]string{"environment": "Production"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "dev"}},
+	).Build()
+	validator := &MarklogicClusterCustomValidator{Namespaces: namespaces}
+	one := int32(1)
+	cluster := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "prod"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			Image:           "marklogic:12.0.1",
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{{Name: "dnode", Replicas: &one, IsBootstrap: true}},
+		},
+	}
+
+	warnings, err := validator.ValidateCreate(context.Background(), cluster)
+	if err != nil || len(warnings) != 3 {
+		t.Fatalf("expected warnings for a single host, ephemeral storage and no log collection, got %v, %v", warnings, err)
+	}
+
+	safe := cluster.DeepCopy()
+	three := int32(3)
+	safe.Spec.MarkLogicGroups[0].Replicas = &three
+	safe.Spec.Persistence = &marklogicv1.Persistence{Enabled: true, Size: "10Gi"}
+	safe.Spec.LogCollection = &marklogicv1.LogCollection{Enabled: true}
+	if warnings, err := validator.ValidateUpdate(context.Background(), cluster, safe); err != nil || len(warnings) != 0 {
+		t.Fatalf("expected no warnings, got %v, %v", warnings, err)
+	}
+
+	vector := safe.DeepCopy()
+	vector.Spec.LogCollection.Engine = marklogicv1.LogCollectionEngineVector
+	vector.Spec.LogCollection.Parsers = "- name: json\n  format: json"
+	if warnings, _ := validator.ValidateUpdate(context.Background(), safe, vector); len(warnings) != 1 {
+		t.Fatalf("expected a warning for parsers of the vector engine, got %v", warnings)
+	}
+
+	dev := safe.DeepCopy()
+	dev.Namespace = "dev"
+	dev.Spec.Persistence = nil
+	if warnings, _ := validator.ValidateCreate(context.Background(), dev); len(warnings) != 0 {
+		t.Fatalf("expected ephemeral storage outside production namespaces to be fine, got %v", warnings)
+	}
+}
+
+func TestDefaultExpandsClusterProfile(t *testing.T) {
+	defaulter := &MarklogicClusterCustomDefaulter{}
+	ctx := context.Background()
+
+	cluster := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+		Spec:       marklogicv1.MarklogicClusterSpec{Profile: marklogicv1.ClusterProfileENodeDNode},
+	}
+	if err := defaulter.Default(ctx, cluster); err != nil {
+		t.Fatalf("failed to default the cluster: %v", err)
+	}
+	groups := cluster.Spec.MarkLogicGroups
+	if len(groups) != 2 || !groups[0].IsBootstrap || *groups[0].Replicas != 3 || groups[0].Profile != marklogicv1.GroupProfileDNode ||
+		groups[1].IsBootstrap || *groups[1].Replicas != 2 || groups[1].Profile != marklogicv1.GroupProfileENode ||
+		groups[0].GroupConfig.Name == groups[1].GroupConfig.Name {
+		t.Fatalf("expected a bootstrap dnode group and an enode group, got %+v %+v", groups[0], groups[1])
+	}
+	for _, group := range groups {
+		if group.HugePages == nil || group.HugePages.Enabled {
+			t.Fatalf("expected huge pages to be disabled in group %s, got %+v", group.Name, group.HugePages)
+		}
+		if group.Resources != nil {
+			for name := range group.Resources.Requests {
+				if strings.HasPrefix(string(name), corev1.ResourceHugePagesPrefix) {
+					t.Fatalf("expected no huge pages request in group %s, got %+v", group.Name, group.Resources)
+				}
+			}
+		}
+	}
+
+	replicas := int32(5)
+	explicit := &marklogicv1.MarklogicCluster{Spec: marklogicv1.MarklogicClusterSpec{
+		Profile:         marklogicv1.ClusterProfileSingleNode,
+		MarkLogicGroups: []*marklogicv1.MarklogicGroups{{Name: "custom", Replicas: &replicas, IsBootstrap: true}},
+	}}
+	if err := defaulter.Default(ctx, explicit); err != nil {
+		t.Fatalf("failed to default the cluster: %v", err)
+	}
+	if groups := explicit.Spec.MarkLogicGroups; len(groups) != 1 || groups[0].Name != "custom" {
+		t.Fatalf("expected the explicit groups to be kept, got %+v", groups)
+	}
+}
+
+func TestValidateRejectsGroupsOfAnotherCluster(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := marklogicv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	groups := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&marklogicv1.MarklogicGroup{ObjectMeta: metav1.ObjectMeta{
+		Name:            "dnode",
+		Namespace:       "ml",
+		OwnerReferences: []metav1.OwnerReference{{Kind: "MarklogicCluster", Name: "first", UID: "first-uid"}},
+	}}).Build()
+	validator := &MarklogicClusterCustomValidator{Groups: groups}
+	cluster := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "second", Namespace: "ml", UID: "second-uid"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{{Name: "dnode", IsBootstrap: true}},
+		},
+	}
+	if _, err := validator.ValidateCreate(context.Background(), cluster); !apierrors.IsInvalid(err) {
+		t.Fatalf("expected a group of another cluster to be rejected, got %v", err)
+	}
+
+	renamed := cluster.DeepCopy()
+	renamed.Spec.MarkLogicGroups[0].Name = "second-dnode"
+	if _, err := validator.ValidateCreate(context.Background(), renamed); err != nil {
+		t.Fatalf("expected a renamed group to be admitted, got %v", err)
+	}
+	if _, err := validator.ValidateUpdate(context.Background(), renamed, cluster); !apierrors.IsInvalid(err) {
+		t.Fatalf("expected adding a group of another cluster to be rejected, got %v", err)
+	}
+
+	first := cluster.DeepCopy()
+	first.Name, first.UID = "first", "first-uid"
+	if _, err := validator.ValidateCreate(context.Background(), first); err != nil {
+		t.Fatalf("expected the owning cluster to keep its group, got %v", err)
+	}
+	changed := cluster.DeepCopy()
+	changed.Labels = map[string]string{"team": "analytics"}
+	if _, err := validator.ValidateUpdate(context.Background(), cluster, changed); err != nil {
+		t.Fatalf("expected a cluster that already lists the group to stay changeable, got %v", err)
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNext(t *testing.T) {
+	// Friday, 2026-10-16 19:30 UTC.
+	now := time.Date(2026, 10, 16, 19, 30, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		spec string
+		want time.Time
+	}{
+		{"0 20 * * 1-5", time.Date(2026, 10, 16, 20, 0, 0, 0, time.UTC)},
+		{"0 7 * * mon-fri", time.Date(2026, 10, 19, 7, 0, 0, 0, time.UTC)},
+		{"*/20 * * * *", time.Date(2026, 10, 16, 19, 40, 0, 0, time.UTC)},
+		{"0 0 1 jan *", time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 9 13 * 5", time.Date(2026, 10, 23, 9, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)},
+		{"30 8 * * 7", time.Date(2026, 10, 18, 8, 30, 0, 0, time.UTC)},
+	} {
+		schedule, err := Parse(tc.spec)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.spec, err)
+		}
+		if got := schedule.Next(now); !got.Equal(tc.want) {
+			t.Errorf("%s: expected %s, got %s", tc.spec, tc.want, got)
+		}
+	}
+}
+
+func TestLatest(t *testing.T) {
+	schedule, err := Parse("0 20 * * 1-5")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Sunday, 2026-10-18 12:00 UTC: the last stop was Friday evening.
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	got, ok := schedule.Latest(now.AddDate(0, 0, -8), now)
+	if !ok || !got.Equal(time.Date(2026, 10, 16, 20, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected Friday 20:00, got %s %t", got, ok)
+	}
+	if _, ok := schedule.Latest(now.Add(-time.Hour), now); ok {
+		t.Fatalf("expected no activation within the last hour")
+	}
+}
+
+func TestParseRejectsInvalidExpressions(t *testing.T) {
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "0 0 0 * *", "0 0 * 13 *", "0 0 * * 8", "5-1 * * * *", "*/0 * * * *", "0 0 * * funday"} {
+		if _, err := Parse(spec); err == nil {
+			t.Errorf("expected %q to be rejected", spec)
+		}
+	}
+}
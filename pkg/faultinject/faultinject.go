@@ -0,0 +1,249 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+// Package faultinject implements the operator's opt-in fault injection mode.
+// It is enabled with the --fault-injection flag and is meant for resilience
+// testing of rolling upgrades: Manage API calls can be delayed or failed and
+// MarkLogic pods can be deleted while a StatefulSet rollout is in progress.
+// It must never be enabled for production clusters.
+package faultinject
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// PodKillScopeUpgrade only deletes pods while the StatefulSet has pods on
+	// an older revision.
+	PodKillScopeUpgrade = "upgrade"
+	// PodKillScopeAlways deletes pods regardless of rollout state.
+	PodKillScopeAlways = "always"
+
+	defaultPodKillInterval = time.Minute
+)
+
+// Config describes which faults to inject. The zero value injects nothing.
+type Config struct {
+	// ManageLatency is added to every Manage API request.
+	ManageLatency time.Duration
+	// ManageErrorRate is the probability (0-1) that a Manage API request
+	// fails with a 503 response without reaching MarkLogic.
+	ManageErrorRate float64
+	// PodKillRate is the probability (0-1) that a reconcile of a MarkLogic
+	// group deletes one of its pods.
+	PodKillRate float64
+	// PodKillInterval is the minimum time between two pod deletions.
+	PodKillInterval time.Duration
+	// PodKillScope is PodKillScopeUpgrade (default) or PodKillScopeAlways.
+	PodKillScope string
+	// Seed seeds the random source so that a test run can be reproduced.
+	// Zero uses the current time.
+	Seed int64
+}
+
+// ParseConfig parses the value of the --fault-injection flag, a comma
+// separated list of key=value pairs, for example
+// "manage-latency=2s,manage-error-rate=0.1,pod-kill-rate=0.2".
+func ParseConfig(spec string) (Config, error) {
+	cfg := Config{PodKillInterval: defaultPodKillInterval, PodKillScope: PodKillScopeUpgrade}
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return Config{}, fmt.Errorf("fault injection entry %q must be key=value", entry)
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		var err error
+		switch key {
+		case "manage-latency":
+			cfg.ManageLatency, err = time.ParseDuration(value)
+		case "manage-error-rate":
+			cfg.ManageErrorRate, err = parseRate(value)
+		case "pod-kill-rate":
+			cfg.PodKillRate, err = parseRate(value)
+		case "pod-kill-interval":
+			cfg.PodKillInterval, err = time.ParseDuration(value)
+		case "pod-kill-scope":
+			if value != PodKillScopeUpgrade && value != PodKillScopeAlways {
+				err = fmt.Errorf("must be %q or %q", PodKillScopeUpgrade, PodKillScopeAlways)
+			}
+			cfg.PodKillScope = value
+		case "seed":
+			cfg.Seed, err = strconv.ParseInt(value, 10, 64)
+		default:
+			return Config{}, fmt.Errorf("unknown fault injection key %q", key)
+		}
+		if err != nil {
+			return Config{}, fmt.Errorf("invalid fault injection value for %s: %w", key, err)
+		}
+	}
+	return cfg, nil
+}
+
+func parseRate(value string) (float64, error) {
+	rate, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, err
+	}
+	if rate < 0 || rate > 1 {
+		return 0, fmt.Errorf("rate %v is not between 0 and 1", rate)
+	}
+	return rate, nil
+}
+
+// Injector injects the faults described by its Config. A nil *Injector is
+// valid and injects nothing.
+type Injector struct {
+	cfg Config
+
+	mu          sync.Mutex
+	rand        *rand.Rand
+	lastPodKill time.Time
+	now         func() time.Time
+}
+
+// NewInjector returns an Injector for cfg.
+func NewInjector(cfg Config) *Injector {
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &Injector{
+		cfg:  cfg,
+		rand: rand.New(rand.NewSource(seed)), // #nosec G404 -- test-only fault injection
+		now:  time.Now,
+	}
+}
+
+// Config returns the configuration of the injector.
+func (i *Injector) Config() Config {
+	if i == nil {
+		return Config{}
+	}
+	return i.cfg
+}
+
+func (i *Injector) roll(rate float64) bool {
+	if rate <= 0 {
+		return false
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.rand.Float64() < rate
+}
+
+// WrapTransport returns a RoundTripper that delays and fails requests
+// according to the Manage API settings of the injector.
+func (i *Injector) WrapTransport(next http.RoundTripper) http.RoundTripper {
+	if i == nil || (i.cfg.ManageLatency <= 0 && i.cfg.ManageErrorRate <= 0) {
+		return next
+	}
+	return &faultyTransport{injector: i, next: next}
+}
+
+type faultyTransport struct {
+	injector *Injector
+	next     http.RoundTripper
+}
+
+func (t *faultyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if latency := t.injector.cfg.ManageLatency; latency > 0 {
+		timer := time.NewTimer(latency)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+	if t.injector.roll(t.injector.cfg.ManageErrorRate) {
+		if req.Body != nil {
+			_ = req.Body.Close()
+		}
+		return &http.Response{
+			Status:     "503 Service Unavailable",
+			StatusCode: http.StatusServiceUnavailable,
+			Proto:      "HTTP/1.1",
+			ProtoMajor: 1,
+			ProtoMinor: 1,
+			Header:     http.Header{"Content-Type": []string{"text/plain"}},
+			Body:       io.NopCloser(strings.NewReader("fault injected")),
+			Request:    req,
+		}, nil
+	}
+	return t.next.RoundTrip(req)
+}
+
+// MaybeKillPod deletes one pod of the named StatefulSet when the pod kill
+// rate and interval allow it. With PodKillScopeUpgrade it only acts while the
+// StatefulSet is rolling out a new revision. It returns the name of the
+// deleted pod, or "" when nothing was deleted.
+func (i *Injector) MaybeKillPod(ctx context.Context, c client.Client, namespace, statefulSetName string) (string, error) {
+	if i == nil || i.cfg.PodKillRate <= 0 {
+		return "", nil
+	}
+	i.mu.Lock()
+	tooSoon := !i.lastPodKill.IsZero() && i.now().Sub(i.lastPodKill) < i.cfg.PodKillInterval
+	i.mu.Unlock()
+	if tooSoon {
+		return "", nil
+	}
+
+	sts := &appsv1.StatefulSet{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: statefulSetName}, sts); err != nil {
+		return "", client.IgnoreNotFound(err)
+	}
+	if i.cfg.PodKillScope != PodKillScopeAlways && !statefulSetRollingOut(sts) {
+		return "", nil
+	}
+	if !i.roll(i.cfg.PodKillRate) {
+		return "", nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(sts.Spec.Selector)
+	if err != nil {
+		return "", err
+	}
+	pods := &corev1.PodList{}
+	if err := c.List(ctx, pods, client.InNamespace(namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return "", err
+	}
+	var candidates []corev1.Pod
+	for _, pod := range pods.Items {
+		if pod.DeletionTimestamp == nil {
+			candidates = append(candidates, pod)
+		}
+	}
+	if len(candidates) == 0 {
+		return "", nil
+	}
+	i.mu.Lock()
+	victim := candidates[i.rand.Intn(len(candidates))]
+	i.lastPodKill = i.now()
+	i.mu.Unlock()
+	if err := c.Delete(ctx, &victim); err != nil {
+		return "", client.IgnoreNotFound(err)
+	}
+	return victim.Name, nil
+}
+
+// statefulSetRollingOut reports whether some pods still run an older revision.
+func statefulSetRollingOut(sts *appsv1.StatefulSet) bool {
+	return sts.Status.UpdateRevision != "" && sts.Status.CurrentRevision != sts.Status.UpdateRevision
+}
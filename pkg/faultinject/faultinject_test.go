@@ -0,0 +1,119 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package faultinject
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestParseConfig(t *testing.T) {
+	cfg, err := ParseConfig("manage-latency=250ms, manage-error-rate=0.5,pod-kill-rate=1,pod-kill-interval=10s,pod-kill-scope=always,seed=7")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := Config{
+		ManageLatency:   250 * time.Millisecond,
+		ManageErrorRate: 0.5,
+		PodKillRate:     1,
+		PodKillInterval: 10 * time.Second,
+		PodKillScope:    PodKillScopeAlways,
+		Seed:            7,
+	}
+	if cfg != want {
+		t.Fatalf("expected %+v, got %+v", want, cfg)
+	}
+
+	defaults, err := ParseConfig("pod-kill-rate=0.1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if defaults.PodKillScope != PodKillScopeUpgrade || defaults.PodKillInterval != time.Minute {
+		t.Fatalf("expected upgrade scope and 1m interval by default, got %+v", defaults)
+	}
+
+	for _, spec := range []string{"manage-error-rate=2", "unknown=1", "pod-kill-scope=never", "manage-latency"} {
+		if _, err := ParseConfig(spec); err == nil {
+			t.Fatalf("expected error for %q", spec)
+		}
+	}
+}
+
+func TestWrapTransportInjectsErrors(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	injector := NewInjector(Config{ManageErrorRate: 1, ManageLatency: time.Millisecond, Seed: 1})
+	httpClient := &http.Client{Transport: injector.WrapTransport(http.DefaultTransport)}
+	resp, err := httpClient.Get(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("expected injected 503, got %d", resp.StatusCode)
+	}
+	if calls != 0 {
+		t.Fatalf("expected the request not to reach the server, got %d calls", calls)
+	}
+
+	var nilInjector *Injector
+	if nilInjector.WrapTransport(http.DefaultTransport) != http.DefaultTransport {
+		t.Fatal("expected a nil injector to leave the transport unchanged")
+	}
+}
+
+func TestMaybeKillPodOnlyDuringRollout(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	labels := map[string]string{"app.kubernetes.io/instance": "dnode"}
+	sts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "dnode", Namespace: "ml"},
+		Spec:       appsv1.StatefulSetSpec{Selector: &metav1.LabelSelector{MatchLabels: labels}},
+		Status:     appsv1.StatefulSetStatus{CurrentRevision: "rev-1", UpdateRevision: "rev-1"},
+	}
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "dnode-0", Namespace: "ml", Labels: labels}}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(sts, pod).WithStatusSubresource(sts).Build()
+	ctx := context.Background()
+
+	injector := NewInjector(Config{PodKillRate: 1, PodKillInterval: time.Hour, PodKillScope: PodKillScopeUpgrade, Seed: 1})
+	killed, err := injector.MaybeKillPod(ctx, c, "ml", "dnode")
+	if err != nil || killed != "" {
+		t.Fatalf("expected no pod deletion outside a rollout, got %q, %v", killed, err)
+	}
+
+	sts.Status.UpdateRevision = "rev-2"
+	if err := c.Status().Update(ctx, sts); err != nil {
+		t.Fatalf("failed to update statefulset status: %v", err)
+	}
+	killed, err = injector.MaybeKillPod(ctx, c, "ml", "dnode")
+	if err != nil || killed != "dnode-0" {
+		t.Fatalf("expected dnode-0 to be deleted, got %q, %v", killed, err)
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(pod), &corev1.Pod{}); err == nil {
+		t.Fatal("expected the pod to be deleted")
+	}
+
+	if err := c.Create(ctx, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "dnode-0", Namespace: "ml", Labels: labels}}); err != nil {
+		t.Fatalf("failed to recreate pod: %v", err)
+	}
+	killed, err = injector.MaybeKillPod(ctx, c, "ml", "dnode")
+	if err != nil || killed != "" {
+		t.Fatalf("expected the pod kill interval to prevent a second deletion, got %q, %v", killed, err)
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+// Package features implements the operator's feature gates. Large subsystems
+// ship behind a gate so they can be disabled, or enabled before they are on
+// by default, per installation with the --feature-gates flag, for example
+// "--feature-gates=InteractiveUpgrade=true,Backups=false".
+package features
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Feature is the name of a feature gate.
+type Feature string
+
+// Stage is the maturity of a feature. Alpha features are disabled by default.
+type Stage string
+
+const (
+	Alpha Stage = "Alpha"
+	Beta  Stage = "Beta"
+	GA    Stage = "GA"
+)
+
+const (
+	// UpgradeWorkflow holds changes of spec.image back until the upgrade
+	// prechecks pass and rolls them out with health gates. When disabled a
+	// new image is applied to the groups right away.
+	UpgradeWorkflow Feature = "UpgradeWorkflow"
+	// InteractiveUpgrade waits for an approval after the prechecks of an
+	// upgrade passed. Requires UpgradeWorkflow.
+	InteractiveUpgrade Feature = "InteractiveUpgrade"
+	// Backups reconciles spec.backup: backup storage, restores and
+	// scheduled backups.
+	Backups Feature = "Backups"
+)
+
+// Spec describes a known feature.
+type Spec struct {
+	Default bool
+	Stage   Stage
+}
+
+var known = map[Feature]Spec{
+	UpgradeWorkflow:    {Default: true, Stage: Beta},
+	InteractiveUpgrade: {Default: false, Stage: Alpha},
+	Backups:            {Default: true, Stage: Beta},
+}
+
+// Gates holds the state of every known feature.
+type Gates struct {
+	enabled map[Feature]bool
+}
+
+// New returns the gates with every feature at its default.
+func New() *Gates {
+	enabled := make(map[Feature]bool, len(known))
+	for feature, spec := range known {
+		enabled[feature] = spec.Default
+	}
+	return &Gates{enabled: enabled}
+}
+
+// Parse parses the value of the --feature-gates flag, a comma separated list
+// of Feature=bool pairs. Features missing from the list keep their default.
+func Parse(value string) (*Gates, error) {
+	gates := New()
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		key, raw, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("feature gate %q must be Feature=true|false", entry)
+		}
+		feature := Feature(strings.TrimSpace(key))
+		if _, ok := known[feature]; !ok {
+			return nil, fmt.Errorf("unknown feature gate %q, known gates are %s", feature, strings.Join(Known(), ", "))
+		}
+		enabled, err := strconv.ParseBool(strings.TrimSpace(raw))
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of feature gate %s: %w", feature, err)
+		}
+		gates.enabled[feature] = enabled
+	}
+	if gates.Enabled(InteractiveUpgrade) && !gates.Enabled(UpgradeWorkflow) {
+		return nil, fmt.Errorf("feature gate %s requires %s", InteractiveUpgrade, UpgradeWorkflow)
+	}
+	return gates, nil
+}
+
+// Enabled reports whether the feature is enabled. Unknown features are not.
+func (g *Gates) Enabled(feature Feature) bool {
+	return g.enabled[feature]
+}
+
+// String lists the state of every feature, sorted by name.
+func (g *Gates) String() string {
+	entries := make([]string, 0, len(g.enabled))
+	for _, name := range Known() {
+		entries = append(entries, fmt.Sprintf("%s=%t", name, g.enabled[Feature(name)]))
+	}
+	return strings.Join(entries, ",")
+}
+
+// Known returns the names of the known features, sorted.
+func Known() []string {
+	names := make([]string, 0, len(known))
+	for feature := range known {
+		names = append(names, string(feature))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Usage describes the known features for the --feature-gates flag help.
+func Usage() string {
+	entries := make([]string, 0, len(known))
+	for _, name := range Known() {
+		spec := known[Feature(name)]
+		entries = append(entries, fmt.Sprintf("%s=true|false (%s - default=%t)", name, spec.Stage, spec.Default))
+	}
+	return strings.Join(entries, ", ")
+}
+
+// Default holds the gates of the running operator. It is replaced at
+// startup from the --feature-gates flag.
+var Default = New()
+
+// Enabled reports whether the feature is enabled in Default.
+func Enabled(feature Feature) bool {
+	return Default.Enabled(feature)
+}
@@ -0,0 +1,34 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package features
+
+import "testing"
+
+func TestParseOverridesDefaults(t *testing.T) {
+	gates, err := Parse("InteractiveUpgrade=true, Backups=false")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !gates.Enabled(InteractiveUpgrade) || gates.Enabled(Backups) || !gates.Enabled(UpgradeWorkflow) {
+		t.Fatalf("unexpected gates %s", gates)
+	}
+	if got := gates.String(); got != "Backups=false,InteractiveUpgrade=true,UpgradeWorkflow=true" {
+		t.Fatalf("unexpected string %q", got)
+	}
+	if New().Enabled(InteractiveUpgrade) {
+		t.Fatalf("expected alpha features to be disabled by default")
+	}
+}
+
+func TestParseRejectsInvalidGates(t *testing.T) {
+	for _, value := range []string{
+		"Unknown=true",
+		"Backups",
+		"Backups=maybe",
+		"InteractiveUpgrade=true,UpgradeWorkflow=false",
+	} {
+		if _, err := Parse(value); err == nil {
+			t.Errorf("expected %q to be rejected", value)
+		}
+	}
+}
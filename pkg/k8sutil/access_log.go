@@ -0,0 +1,50 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"strings"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+)
+
+const (
+	// accessStructuredParser splits the AccessLog entries into fields.
+	accessStructuredParser = "access_structured_parser"
+
+	// structuredAccessLogRegex parses an AccessLog entry in the combined log
+	// format, such as
+	//   10.0.0.1 - admin [10/Mar/2026:10:00:00 +0000] "GET /v1/documents?uri=/a.json HTTP/1.1" 200 1234 - "curl/8.5.0"
+	// The referer, user agent and a trailing response time are optional.
+	structuredAccessLogRegex = `^(?<host>[^ ]*) [^ ]* (?<user>[^ ]*) \[(?<time>[^\]]*)\] "(?<method>[A-Z]+) (?<path>[^ "]*)(?: (?<protocol>[^"]*))?" (?<status>[0-9]{3}) (?<size>[0-9]+|-)(?: (?:"(?<referer>[^"]*)"|-))?(?: "(?<user_agent>[^"]*)")?(?: (?<response_time>[0-9.]+))?`
+	// structuredAccessLogTypes are the fields that are not strings.
+	structuredAccessLogTypes = "status:integer size:integer response_time:float"
+)
+
+// vectorStructuredAccessLogRegex is structuredAccessLogRegex in the syntax of
+// vector.
+var vectorStructuredAccessLogRegex = strings.ReplaceAll(structuredAccessLogRegex, "(?<", "(?P<")
+
+// accessLogParser is the parser of the AccessLog files.
+func accessLogParser(files marklogicv1.LogFilesConfig) string {
+	if files.StructuredAccessLogs {
+		return accessStructuredParser
+	}
+	return "access_parser"
+}
+
+// fluentBitStructuredAccessParser is the fluent-bit parser of the
+// structured AccessLog entries.
+func fluentBitStructuredAccessParser(files marklogicv1.LogFilesConfig) string {
+	if !files.AccessLogs || !files.StructuredAccessLogs {
+		return ""
+	}
+	return `
+
+  - name: ` + accessStructuredParser + `
+    format: regex
+    regex: '` + structuredAccessLogRegex + `'
+    time_key: time
+    time_format: "%d/%b/%Y:%H:%M:%S %z"
+    types: ` + structuredAccessLogTypes
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"sigs.k8s.io/yaml"
+)
+
+func TestStructuredAccessLogRegex(t *testing.T) {
+	re := regexp.MustCompile(structuredAccessLogRegex)
+	for line, want := range map[string]map[string]string{
+		`10.0.0.1 - admin [10/Mar/2026:10:00:00 +0000] "GET /v1/documents?uri=/a.json HTTP/1.1" 200 1234 - "curl/8.5.0"`: {
+			"host": "10.0.0.1", "user": "admin", "method": "GET", "path": "/v1/documents?uri=/a.json",
+			"protocol": "HTTP/1.1", "status": "200", "size": "1234", "referer": "", "user_agent": "curl/8.5.0",
+		},
+		`::1 - - [10/Mar/2026:10:00:01 +0000] "POST /v1/search HTTP/1.1" 401 - "http://ml:8001/" "Mozilla/5.0" 0.042`: {
+			"host": "::1", "user": "-", "method": "POST", "path": "/v1/search", "status": "401", "size": "-",
+			"referer": "http://ml:8001/", "user_agent": "Mozilla/5.0", "response_time": "0.042",
+		},
+	} {
+		match := re.FindStringSubmatch(line)
+		if match == nil {
+			t.Fatalf("expected %q to parse", line)
+		}
+		for field, value := range want {
+			if got := match[re.SubexpIndex(field)]; got != value {
+				t.Fatalf("expected %s %q in %q, got %q", field, value, line, got)
+			}
+		}
+	}
+}
+
+func TestStructuredAccessLogsUseTheStructuredParser(t *testing.T) {
+	files := marklogicv1.LogFilesConfig{AccessLogs: true, StructuredAccessLogs: true}
+	data := fluentBitConfigData(&marklogicv1.LogCollection{Enabled: true, Files: files})
+	var parsers struct {
+		Parsers []map[string]any `json:"parsers"`
+	}
+	if err := yaml.Unmarshal([]byte(data["parsers.yaml"]), &parsers); err != nil {
+		t.Fatalf("invalid parsers: %v", err)
+	}
+	last := parsers.Parsers[len(parsers.Parsers)-1]
+	if last["name"] != accessStructuredParser || last["regex"] != structuredAccessLogRegex {
+		t.Fatalf("expected the structured access parser, got %v", last)
+	}
+	if !strings.Contains(data["fluent-bit.yaml"], "parser: "+accessStructuredParser) {
+		t.Fatalf("expected the AccessLog input to use the structured parser")
+	}
+	for _, engine := range []marklogicv1.LogCollectionEngine{marklogicv1.LogCollectionEngineFluentd, marklogicv1.LogCollectionEngineVector} {
+		logCollection := &marklogicv1.LogCollection{Engine: engine, Files: files}
+		for _, config := range newLogCollector(logCollection).configData(logCollection) {
+			if !strings.Contains(config, "(?<method>") && !strings.Contains(config, "(?P<method>") {
+				t.Fatalf("expected %s to parse the structured fields, got\n%s", engine, config)
+			}
+		}
+	}
+}
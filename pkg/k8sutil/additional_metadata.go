@@ -0,0 +1,51 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// clusterFullname is the base of the names of the resources generated for
+// the whole cluster: spec.fullnameOverride, or the cluster name.
+func clusterFullname(cr *marklogicv1.MarklogicCluster) string {
+	if cr.Spec.FullnameOverride != "" {
+		return cr.Spec.FullnameOverride
+	}
+	return cr.Name
+}
+
+// mergeMetadata returns a new map with the entries of base and then of
+// overrides, so the entries of overrides win.
+func mergeMetadata(base, overrides map[string]string) map[string]string {
+	merged := make(map[string]string, len(base)+len(overrides))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range overrides {
+		merged[k] = v
+	}
+	return merged
+}
+
+// syncMetadata adds the labels and annotations of desired to current and
+// reports whether current changed. Entries set by others are kept.
+func syncMetadata(current, desired metav1.Object) bool {
+	changed := false
+	for k, v := range desired.GetLabels() {
+		if value, ok := current.GetLabels()[k]; !ok || value != v {
+			changed = true
+		}
+	}
+	for k, v := range desired.GetAnnotations() {
+		if value, ok := current.GetAnnotations()[k]; !ok || value != v {
+			changed = true
+		}
+	}
+	if changed {
+		current.SetLabels(mergeMetadata(current.GetLabels(), desired.GetLabels()))
+		current.SetAnnotations(mergeMetadata(current.GetAnnotations(), desired.GetAnnotations()))
+	}
+	return changed
+}
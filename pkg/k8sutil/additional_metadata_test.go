@@ -0,0 +1,71 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"testing"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestAdditionalMetadataAndFullnameOverride(t *testing.T) {
+	t.Parallel()
+
+	replicas := int32(1)
+	cr := newSharedNamespaceCluster("ml")
+	cr.Spec.FullnameOverride = "analytics"
+	cr.Spec.AdditionalLabels = map[string]string{"cost-center": "42"}
+	cr.Spec.AdditionalAnnotations = map[string]string{"example.com/team": "data"}
+	cr.Spec.HAProxy.ReplicaCount = 1
+	cr.Spec.MarkLogicGroups = []*marklogicv1.MarklogicGroups{{Name: "node", Replicas: &replicas, IsBootstrap: true}}
+	base := newUpgradeTestContext(t, cr)
+
+	request := &reconcile.Request{NamespacedName: types.NamespacedName{Name: "ml", Namespace: "default"}}
+	cc, err := CreateClusterContext(base.Ctx, request, base.Client, base.Scheme, base.Recorder)
+	if err != nil {
+		t.Fatalf("failed to create the cluster context: %v", err)
+	}
+	if res := cc.ReconcileHAProxy(); res.Completed() {
+		t.Fatalf("expected the HAProxy reconcile to continue")
+	}
+	deployment := &appsv1.Deployment{}
+	if err := cc.Client.Get(cc.Ctx, types.NamespacedName{Name: "analytics-haproxy", Namespace: "default"}, deployment); err != nil {
+		t.Fatalf("expected the HAProxy Deployment to be named after fullnameOverride: %v", err)
+	}
+	if deployment.Labels["cost-center"] != "42" || deployment.Annotations["example.com/team"] != "data" {
+		t.Fatalf("expected the additional metadata on the Deployment, got %v %v", deployment.Labels, deployment.Annotations)
+	}
+	template := deployment.Spec.Template
+	if template.Labels["cost-center"] != "42" || template.Annotations["example.com/team"] != "data" || template.Annotations["configmap-hash"] == "" {
+		t.Fatalf("expected the additional metadata on the HAProxy pods, got %v %v", template.Labels, template.Annotations)
+	}
+	if deployment.Labels["app.kubernetes.io/instance"] != "ml" {
+		t.Fatalf("expected the selector labels to keep the cluster name, got %v", deployment.Labels)
+	}
+
+	if _, err := cc.ReconsileMarklogicCluster(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	group := &marklogicv1.MarklogicGroup{}
+	if err := cc.Client.Get(cc.Ctx, types.NamespacedName{Name: "node", Namespace: "default"}, group); err != nil {
+		t.Fatalf("expected the group: %v", err)
+	}
+	if group.Labels["cost-center"] != "42" || group.Spec.AdditionalAnnotations["example.com/team"] != "data" {
+		t.Fatalf("expected the additional metadata on the group, got %v %v", group.Labels, group.Spec.AdditionalAnnotations)
+	}
+
+	oc, err := CreateOperatorContext(cc.Ctx, &reconcile.Request{NamespacedName: types.NamespacedName{Name: "node", Namespace: "default"}}, cc.Client, cc.Scheme, cc.Recorder)
+	if err != nil {
+		t.Fatalf("failed to create the group context: %v", err)
+	}
+	service := oc.generateService("node-cluster", oc.MarklogicGroup)
+	if service.Labels["cost-center"] != "42" || service.Annotations["example.com/team"] != "data" {
+		t.Fatalf("expected the additional metadata on the group Service, got %v %v", service.Labels, service.Annotations)
+	}
+	if service.Spec.Selector["cost-center"] != "" {
+		t.Fatalf("expected the additional labels to stay out of the selector, got %v", service.Spec.Selector)
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	appServerSettingsReasonApplied = "AppServerSettingsApplied"
+	appServerSettingsReasonFailed  = "AppServerSettingsFailed"
+
+	// appServerSettingsRetryInterval is how often settings that could not be
+	// applied are tried again, for example until the app server is created.
+	appServerSettingsRetryInterval = time.Minute
+)
+
+// ReconcileAppServerSettings applies the appServerSettings of the groups to
+// their app servers in MarkLogic whenever they change. Settings of an app
+// server that does not exist yet, or that MarkLogic rejects, are tried again
+// every minute and the reason is reported in status.appServerSettings.
+// Settings removed from the spec are left as they are in MarkLogic. Failures
+// never hold up the rest of the reconcile.
+func (cc *ClusterContext) ReconcileAppServerSettings() result.ReconcileResult {
+	cr := cc.MarklogicCluster
+	if clusterStopped(cr) {
+		return result.Continue()
+	}
+	recorded := map[string]*marklogicv1.AppServerSettingsStatus{}
+	for i := range cr.Status.AppServerSettings {
+		status := &cr.Status.AppServerSettings[i]
+		recorded[status.Group+"/"+status.Name] = status
+	}
+
+	var mgmtClient mlmanage.Client
+	statuses := []marklogicv1.AppServerSettingsStatus{}
+	for _, group := range cr.Spec.MarkLogicGroups {
+		if group == nil {
+			continue
+		}
+		groupName := group.Name
+		if group.GroupConfig != nil && strings.TrimSpace(group.GroupConfig.Name) != "" {
+			groupName = group.GroupConfig.Name
+		}
+		for i := range group.AppServerSettings {
+			settings := &group.AppServerSettings[i]
+			hash := appServerSettingsHash(settings)
+			next := marklogicv1.AppServerSettingsStatus{Group: group.Name, Name: settings.Name}
+			if status := recorded[group.Name+"/"+settings.Name]; status != nil {
+				next = *status.DeepCopy()
+			}
+			if next.SpecHash == hash {
+				statuses = append(statuses, next)
+				continue
+			}
+			var err error
+			if mgmtClient == nil {
+				mgmtClient, err = cc.newBootstrapManagementClient()
+			}
+			if err == nil {
+				err = mgmtClient.SetAppServerProperties(cc.Ctx, groupName, settings.Name, appServerProperties(settings))
+			}
+			if err != nil {
+				cc.ReqLogger.Error(err, "Failed to apply the app server settings", "group", group.Name, "appServer", settings.Name)
+				if next.Message != err.Error() {
+					cc.recordClusterEvent(corev1.EventTypeWarning, appServerSettingsReasonFailed,
+						fmt.Sprintf("Failed to apply the settings of app server %s of group %s: %v", settings.Name, group.Name, err))
+				}
+				next.Message = err.Error()
+				statuses = append(statuses, next)
+				continue
+			}
+			now := metav1.Now()
+			next.SpecHash, next.AppliedAt, next.Message = hash, &now, ""
+			cc.ReqLogger.Info("Applied the app server settings", "group", group.Name, "appServer", settings.Name)
+			cc.recordClusterEvent(corev1.EventTypeNormal, appServerSettingsReasonApplied,
+				fmt.Sprintf("Applied the settings of app server %s of group %s", settings.Name, group.Name))
+			statuses = append(statuses, next)
+		}
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].Group != statuses[j].Group {
+			return statuses[i].Group < statuses[j].Group
+		}
+		return statuses[i].Name < statuses[j].Name
+	})
+	if len(statuses) == 0 {
+		statuses = nil
+	}
+	if equality.Semantic.DeepEqual(cr.Status.AppServerSettings, statuses) {
+		return result.Continue()
+	}
+	patchBase := client.MergeFrom(cr.DeepCopy())
+	cr.Status.AppServerSettings = statuses
+	if err := patchStatus(cc.Ctx, cc.Client, cr, patchBase); err != nil {
+		cc.ReqLogger.Error(err, "Failed to update the app server settings in the cluster status")
+	}
+	return result.Continue()
+}
+
+// appServerProperties returns the Manage API properties of the settings that
+// are set.
+func appServerProperties(settings *marklogicv1.AppServerSettings) map[string]any {
+	properties := map[string]any{}
+	for name, value := range map[string]*int32{
+		"request-timeout":          settings.RequestTimeout,
+		"keep-alive-timeout":       settings.KeepAliveTimeout,
+		"session-timeout":          settings.SessionTimeout,
+		"default-time-limit":       settings.DefaultTimeLimit,
+		"max-time-limit":           settings.MaxTimeLimit,
+		"max-inference-size":       settings.MaxInferenceSize,
+		"concurrent-request-limit": settings.ConcurrentRequestLimit,
+		"threads":                  settings.Threads,
+	} {
+		if value != nil {
+			properties[name] = *value
+		}
+	}
+	if settings.URLRewriter != nil {
+		properties["url-rewriter"] = *settings.URLRewriter
+	}
+	if settings.ErrorHandler != nil {
+		properties["error-handler"] = *settings.ErrorHandler
+	}
+	return properties
+}
+
+// appServerSettingsHash identifies the settings of an app server.
+func appServerSettingsHash(settings *marklogicv1.AppServerSettings) string {
+	data, _ := json.Marshal(settings)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:10]
+}
+
+// nextAppServerSettingsCheck is when settings that could not be applied are
+// tried again, or the zero time when all settings are applied.
+func nextAppServerSettingsCheck(cr *marklogicv1.MarklogicCluster) time.Time {
+	applied := map[string]string{}
+	for _, status := range cr.Status.AppServerSettings {
+		applied[status.Group+"/"+status.Name] = status.SpecHash
+	}
+	for _, group := range cr.Spec.MarkLogicGroups {
+		if group == nil {
+			continue
+		}
+		for i := range group.AppServerSettings {
+			settings := &group.AppServerSettings[i]
+			if applied[group.Name+"/"+settings.Name] != appServerSettingsHash(settings) {
+				return time.Now().Add(appServerSettingsRetryInterval)
+			}
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestReconcileAppServerSettingsAppliesChangedSettings(t *testing.T) {
+	timeout, limit := int32(120), int32(8)
+	rewriter := "/rewriter.xml"
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			ClusterDomain: "cluster.local",
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{
+				{Name: "dnode", IsBootstrap: true},
+				{Name: "enode", GroupConfig: &marklogicv1.GroupConfig{Name: "E-Nodes"}, AppServerSettings: []marklogicv1.AppServerSettings{
+					{Name: "App-Services", RequestTimeout: &timeout, ConcurrentRequestLimit: &limit, URLRewriter: &rewriter},
+					{Name: "orders-api", RequestTimeout: &timeout},
+				}},
+			},
+		},
+	}
+	cc := newUpgradeTestContext(t, cr)
+	recorder := cc.Recorder.(*record.FakeRecorder)
+	applied := map[string]map[string]any{}
+	original := NewDynamicManagementClient
+	NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+		return &stubDynamicManagementClient{
+			setServerPropsFn: func(groupName, serverName string, properties map[string]any) error {
+				if serverName == "orders-api" {
+					return errors.New("SERVER-NOEXIST: No such server orders-api")
+				}
+				applied[groupName+"/"+serverName] = properties
+				return nil
+			},
+		}
+	}
+	t.Cleanup(func() { NewDynamicManagementClient = original })
+
+	if res := cc.ReconcileAppServerSettings(); res.Completed() {
+		t.Fatalf("expected the app server settings never to hold up the reconcile")
+	}
+	properties := applied["E-Nodes/App-Services"]
+	if len(properties) != 3 || properties["request-timeout"] != int32(120) || properties["concurrent-request-limit"] != int32(8) ||
+		properties["url-rewriter"] != "/rewriter.xml" {
+		t.Fatalf("unexpected properties %v", properties)
+	}
+	status := cr.Status.AppServerSettings
+	if len(status) != 2 || status[0].Name != "App-Services" || status[0].SpecHash == "" || status[0].AppliedAt == nil ||
+		status[1].SpecHash != "" || !strings.Contains(status[1].Message, "SERVER-NOEXIST") {
+		t.Fatalf("unexpected status %+v", status)
+	}
+	if next := nextAppServerSettingsCheck(cr); next.IsZero() {
+		t.Fatalf("expected the settings that failed to be tried again")
+	}
+	if len(recorder.Events) != 2 {
+		t.Fatalf("expected an applied and a failed event, got %d", len(recorder.Events))
+	}
+
+	// Applied settings are not applied again until they change, and a
+	// failure with the same reason is not recorded again.
+	delete(applied, "E-Nodes/App-Services")
+	cc.ReconcileAppServerSettings()
+	if len(applied) != 0 || len(recorder.Events) != 2 {
+		t.Fatalf("expected nothing to be applied, got %v", applied)
+	}
+	*cr.Spec.MarkLogicGroups[1].AppServerSettings[0].ConcurrentRequestLimit = 16
+	if err := cc.Client.Update(cc.Ctx, cr); err != nil {
+		t.Fatalf("failed to update cluster: %v", err)
+	}
+	cc.ReconcileAppServerSettings()
+	if applied["E-Nodes/App-Services"]["concurrent-request-limit"] != int32(16) {
+		t.Fatalf("expected the changed settings to be applied, got %v", applied)
+	}
+
+	cr.Spec.MarkLogicGroups[1].AppServerSettings = cr.Spec.MarkLogicGroups[1].AppServerSettings[:1]
+	if err := cc.Client.Update(cc.Ctx, cr); err != nil {
+		t.Fatalf("failed to update cluster: %v", err)
+	}
+	cc.ReconcileAppServerSettings()
+	if len(cr.Status.AppServerSettings) != 1 {
+		t.Fatalf("expected the removed settings to leave the status, got %+v", cr.Status.AppServerSettings)
+	}
+	if next := nextAppServerSettingsCheck(cr); !next.IsZero() {
+		t.Fatalf("expected no further check, got %v", next)
+	}
+}
@@ -0,0 +1,191 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/registry"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const PrecheckImageArchitecture = "image-architecture"
+
+// ImagePlatforms reads the platforms of an image from its registry. Tests
+// replace it to avoid network access.
+var ImagePlatforms = registry.ImagePlatforms
+
+func init() {
+	RegisterPrecheck(imageArchitecturePrecheck{})
+}
+
+// architectureAffinity adds a required node affinity on kubernetes.io/arch
+// for arch, unless the affinity or nodeSelector already constrain the
+// architecture. The affinity passed in is not modified.
+func architectureAffinity(affinity *corev1.Affinity, nodeSelector map[string]string, arch string) *corev1.Affinity {
+	if arch == "" || nodeSelector[corev1.LabelArchStable] != "" || affinitySelectsArchitecture(affinity) {
+		return affinity
+	}
+	requirement := corev1.NodeSelectorRequirement{
+		Key:      corev1.LabelArchStable,
+		Operator: corev1.NodeSelectorOpIn,
+		Values:   []string{arch},
+	}
+	result := affinity.DeepCopy()
+	if result == nil {
+		result = &corev1.Affinity{}
+	}
+	if result.NodeAffinity == nil {
+		result.NodeAffinity = &corev1.NodeAffinity{}
+	}
+	required := result.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if required == nil || len(required.NodeSelectorTerms) == 0 {
+		result.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = &corev1.NodeSelector{
+			NodeSelectorTerms: []corev1.NodeSelectorTerm{{MatchExpressions: []corev1.NodeSelectorRequirement{requirement}}},
+		}
+		return result
+	}
+	// Terms are ORed, so every term needs the requirement.
+	for i := range required.NodeSelectorTerms {
+		required.NodeSelectorTerms[i].MatchExpressions = append(required.NodeSelectorTerms[i].MatchExpressions, requirement)
+	}
+	return result
+}
+
+func affinitySelectsArchitecture(affinity *corev1.Affinity) bool {
+	if affinity == nil || affinity.NodeAffinity == nil || affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return false
+	}
+	for _, term := range affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+		for _, expression := range term.MatchExpressions {
+			if expression.Key == corev1.LabelArchStable {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// imageArchitecturePrecheck requires the target image to be available for
+// the architectures the upgraded groups are scheduled on.
+type imageArchitecturePrecheck struct{}
+
+func (imageArchitecturePrecheck) Name() string { return PrecheckImageArchitecture }
+
+func (imageArchitecturePrecheck) Run(in *PrecheckInput) (marklogicv1.PrecheckStatus, string) {
+	archs, err := in.requiredArchitectures()
+	if apierrors.IsForbidden(err) {
+		return marklogicv1.PrecheckWarning, "reading the node architectures requires cluster-scoped list access to nodes; set spec.architecture to check a single architecture"
+	}
+	if err != nil {
+		return marklogicv1.PrecheckFailed, fmt.Sprintf("failed to read the node architectures: %v", err)
+	}
+	if len(archs) == 0 {
+		return marklogicv1.PrecheckPassed, "no group is scheduled on nodes with a known architecture"
+	}
+	if Offline {
+		return marklogicv1.PrecheckSkipped, fmt.Sprintf("the manifest of %s is not read from the registry in offline mode", in.TargetImage)
+	}
+	creds, err := in.registryCredentials()
+	if err != nil {
+		return marklogicv1.PrecheckWarning, fmt.Sprintf("failed to read the image pull secrets: %v", err)
+	}
+	platforms, err := ImagePlatforms(in.Ctx, in.TargetImage, registry.Options{Credentials: creds})
+	if err != nil {
+		// Registries may be unreachable from the operator, for example behind a pull-through mirror.
+		return marklogicv1.PrecheckWarning, fmt.Sprintf("failed to read the manifest of %s: %v", in.TargetImage, err)
+	}
+	available := map[string]bool{}
+	for _, platform := range platforms {
+		if platform.OS == "" || platform.OS == "linux" {
+			available[platform.Architecture] = true
+		}
+	}
+	missing := []string{}
+	for _, arch := range archs {
+		if !available[arch] {
+			missing = append(missing, arch)
+		}
+	}
+	if len(missing) > 0 {
+		names := make([]string, 0, len(available))
+		for arch := range available {
+			names = append(names, arch)
+		}
+		sort.Strings(names)
+		return marklogicv1.PrecheckFailed, fmt.Sprintf("%s is not available for %s (available: %s)", in.TargetImage, strings.Join(missing, ", "), strings.Join(names, ", "))
+	}
+	return marklogicv1.PrecheckPassed, fmt.Sprintf("%s is available for %s", in.TargetImage, strings.Join(archs, ", "))
+}
+
+// requiredArchitectures returns the architectures the groups running the
+// cluster image are scheduled on: the configured architecture, or the
+// architectures of the schedulable nodes matching the group's nodeSelector.
+func (cc *ClusterContext) requiredArchitectures() ([]string, error) {
+	cr := cc.MarklogicCluster
+	archs := map[string]bool{}
+	for _, group := range cr.Spec.MarkLogicGroups {
+		if group.Image != "" {
+			continue
+		}
+		nodeSelector := cr.Spec.NodeSelector
+		if group.NodeSelector != nil {
+			nodeSelector = group.NodeSelector
+		}
+		arch := cr.Spec.Architecture
+		if group.Architecture != "" {
+			arch = group.Architecture
+		}
+		if selected := nodeSelector[corev1.LabelArchStable]; selected != "" {
+			arch = selected
+		}
+		if arch != "" {
+			archs[arch] = true
+			continue
+		}
+		nodes := &corev1.NodeList{}
+		if err := cc.Client.List(cc.Ctx, nodes, client.MatchingLabels(nodeSelector)); err != nil {
+			return nil, err
+		}
+		for _, node := range nodes.Items {
+			if nodeArch := node.Labels[corev1.LabelArchStable]; nodeArch != "" && !node.Spec.Unschedulable {
+				archs[nodeArch] = true
+			}
+		}
+	}
+	result := make([]string, 0, len(archs))
+	for arch := range archs {
+		result = append(result, arch)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// registryCredentials reads the registry credentials of the cluster's
+// image pull secrets.
+func (cc *ClusterContext) registryCredentials() (map[string]registry.Credentials, error) {
+	creds := map[string]registry.Credentials{}
+	for _, ref := range cc.MarklogicCluster.Spec.ImagePullSecrets {
+		secret, err := cc.getSecret(ref.Name)
+		if err != nil {
+			return nil, err
+		}
+		data, ok := secret.Data[corev1.DockerConfigJsonKey]
+		if !ok {
+			continue
+		}
+		parsed, err := registry.CredentialsFromDockerConfig(data)
+		if err != nil {
+			return nil, fmt.Errorf("secret %s: %w", ref.Name, err)
+		}
+		for host, cred := range parsed {
+			creds[host] = cred
+		}
+	}
+	return creds, nil
+}
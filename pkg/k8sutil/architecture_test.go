@@ -0,0 +1,108 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/registry"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestArchitectureAffinity(t *testing.T) {
+	if got := architectureAffinity(nil, nil, ""); got != nil {
+		t.Fatalf("expected no affinity without an architecture, got %+v", got)
+	}
+	if got := architectureAffinity(nil, map[string]string{corev1.LabelArchStable: "amd64"}, "arm64"); got != nil {
+		t.Fatalf("expected the nodeSelector to take precedence, got %+v", got)
+	}
+
+	got := architectureAffinity(nil, nil, "arm64")
+	terms := got.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	if len(terms) != 1 || terms[0].MatchExpressions[0].Key != corev1.LabelArchStable || terms[0].MatchExpressions[0].Values[0] != "arm64" {
+		t.Fatalf("unexpected affinity %+v", terms)
+	}
+
+	zones := &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+		NodeSelectorTerms: []corev1.NodeSelectorTerm{
+			{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: corev1.LabelTopologyZone, Operator: corev1.NodeSelectorOpIn, Values: []string{"a"}}}},
+			{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: corev1.LabelTopologyZone, Operator: corev1.NodeSelectorOpIn, Values: []string{"b"}}}},
+		},
+	}}}
+	got = architectureAffinity(zones, nil, "amd64")
+	for _, term := range got.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+		if len(term.MatchExpressions) != 2 || term.MatchExpressions[1].Key != corev1.LabelArchStable {
+			t.Fatalf("expected every term to require the architecture, got %+v", term)
+		}
+	}
+	if len(zones.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0].MatchExpressions) != 1 {
+		t.Fatalf("expected the spec affinity not to be modified")
+	}
+	if again := architectureAffinity(got, nil, "arm64"); again != got {
+		t.Fatalf("expected an affinity selecting an architecture to be kept")
+	}
+}
+
+func TestImageArchitecturePrecheck(t *testing.T) {
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			Image:            upgradeTestNewImage,
+			ClusterDomain:    "cluster.local",
+			ImagePullSecrets: []corev1.LocalObjectReference{{Name: "pull"}},
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{
+				{Name: "dnode", IsBootstrap: true},
+				{Name: "enode", Architecture: "arm64"},
+				{Name: "pinned", Image: upgradeTestOldImage, Architecture: "s390x"},
+			},
+		},
+	}
+	pullSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "pull", Namespace: "default"},
+		Type:       corev1.SecretTypeDockerConfigJson,
+		Data:       map[string][]byte{corev1.DockerConfigJsonKey: []byte(`{"auths":{"https://index.docker.io/v1/":{"username":"robot","password":"s3cret"}}}`)},
+	}
+	node := func(name, arch string, unschedulable bool) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{corev1.LabelArchStable: arch}},
+			Spec:       corev1.NodeSpec{Unschedulable: unschedulable},
+		}
+	}
+	cc := newUpgradeTestContext(t, cr, pullSecret, node("a", "amd64", false), node("b", "ppc64le", true))
+	platforms := []registry.Platform{{OS: "linux", Architecture: "amd64"}}
+	original := ImagePlatforms
+	t.Cleanup(func() { ImagePlatforms = original })
+	ImagePlatforms = func(ctx context.Context, image string, opts registry.Options) ([]registry.Platform, error) {
+		if image != upgradeTestNewImage || opts.Credentials["docker.io"].Username != "robot" {
+			t.Fatalf("unexpected registry lookup for %s with %+v", image, opts.Credentials)
+		}
+		return platforms, nil
+	}
+
+	in := &PrecheckInput{ClusterContext: cc, TargetImage: upgradeTestNewImage}
+	status, message := imageArchitecturePrecheck{}.Run(in)
+	if status != marklogicv1.PrecheckFailed || !strings.Contains(message, "not available for arm64") {
+		t.Fatalf("expected arm64 to be missing, got %s: %s", status, message)
+	}
+
+	platforms = append(platforms, registry.Platform{OS: "linux", Architecture: "arm64"})
+	status, message = imageArchitecturePrecheck{}.Run(in)
+	if status != marklogicv1.PrecheckPassed || !strings.Contains(message, "amd64, arm64") {
+		t.Fatalf("expected the precheck to pass, got %s: %s", status, message)
+	}
+
+	Offline = true
+	t.Cleanup(func() { Offline = false })
+	ImagePlatforms = func(ctx context.Context, image string, opts registry.Options) ([]registry.Platform, error) {
+		t.Fatalf("expected no registry lookup in offline mode")
+		return nil, nil
+	}
+	status, message = imageArchitecturePrecheck{}.Run(in)
+	if status != marklogicv1.PrecheckSkipped {
+		t.Fatalf("expected the precheck to be skipped in offline mode, got %s: %s", status, message)
+	}
+}
@@ -0,0 +1,548 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	backupSecretKeyAccessKeyID     = "access-key-id"
+	backupSecretKeySecretAccessKey = "secret-access-key"
+	backupSecretKeyStorageAccount  = "storage-account"
+	backupSecretKeyStorageKey      = "storage-key"
+	backupSecretKeySASToken        = "sas-token"
+
+	// GCS is accessed through its S3 interoperability endpoint using HMAC keys.
+	gcsS3InteropDomain = "storage.googleapis.com"
+
+	defaultBackupRetryInitialDelaySeconds int32 = 5
+	defaultBackupRetryMaxDelaySeconds     int32 = 300
+
+	backupStorageReasonConfigured       = "StorageConfigured"
+	backupStorageReasonInvalidSecret    = "InvalidCredentialSecret"
+	backupStorageReasonManagementFailed = "ManagementAPIFailed"
+
+	backupSchedulesReasonApplied = "SchedulesApplied"
+	backupSchedulesReasonFailed  = "SchedulesFailed"
+
+	// backupScheduleStatusRefreshInterval controls how often last-run times are read back from MarkLogic.
+	backupScheduleStatusRefreshInterval = 5 * time.Minute
+)
+
+// Annotations binding the MarkLogic service account to a cloud identity.
+const (
+	eksRoleARNAnnotation            = "eks.amazonaws.com/role-arn"
+	azureWorkloadIdentityAnnotation = "azure.workload.identity/client-id"
+)
+
+// ReconcileBackupStorage validates the backup target configuration and registers
+// the object store credentials with MarkLogic. Failures are retried with
+// exponential backoff and surfaced through status.backup.storage.
+func (cc *ClusterContext) ReconcileBackupStorage() result.ReconcileResult {
+	cr := cc.MarklogicCluster
+	if cr.Spec.Backup == nil || !cr.Spec.Backup.Enabled || cr.Spec.Backup.Storage == nil {
+		return result.Continue()
+	}
+	storage := cr.Spec.Backup.Storage
+	current := cr.Status.Backup
+	if current != nil && current.Storage != nil && !current.Storage.Ready && current.Storage.NextRetryTime != nil {
+		if wait := time.Until(current.Storage.NextRetryTime.Time); wait > 0 {
+			return result.RequeueSoon(int(wait.Seconds()) + 1)
+		}
+	}
+
+	creds, err := cc.backupCloudCredentials(storage)
+	if err != nil {
+		return cc.backupStorageFailed(storage, backupStorageReasonInvalidSecret, err)
+	}
+	domain := backupS3Domain(storage)
+	groupNames := []string{}
+	if domain != "" {
+		groupNames = clusterMarkLogicGroupNames(cr)
+	}
+	hash := backupStorageHash(storage, creds, domain, groupNames)
+	if current != nil && current.Storage != nil && current.Storage.Ready && current.Storage.AppliedHash == hash {
+		return result.Continue()
+	}
+	cc.ReqLogger.Info("Registering backup storage", "provider", storage.Provider)
+
+	mgmtClient, err := cc.newBootstrapManagementClient()
+	if err != nil {
+		return cc.backupStorageFailed(storage, backupStorageReasonManagementFailed, err)
+	}
+	if creds != nil {
+		if err := mgmtClient.SetCloudCredentials(cc.Ctx, *creds); err != nil {
+			return cc.backupStorageFailed(storage, backupStorageReasonManagementFailed, err)
+		}
+	}
+	for _, groupName := range groupNames {
+		if err := mgmtClient.SetGroupS3Domain(cc.Ctx, groupName, domain); err != nil {
+			return cc.backupStorageFailed(storage, backupStorageReasonManagementFailed, err)
+		}
+	}
+
+	if err := cc.setBackupStorageStatus(&marklogicv1.BackupStorageStatus{
+		Provider:    storage.Provider,
+		Directory:   backupDirectory(storage),
+		Ready:       true,
+		Message:     fmt.Sprintf("%s backup storage configured", storage.Provider),
+		AppliedHash: hash,
+	}, backupStorageReasonConfigured); err != nil {
+		return result.Error(err)
+	}
+	return result.Continue()
+}
+
+// backupCloudCredentials reads the provider specific credential Secret. It returns
+// nil credentials when MarkLogic authenticates through workload identity.
+func (cc *ClusterContext) backupCloudCredentials(storage *marklogicv1.BackupStorage) (*mlmanage.CloudCredentials, error) {
+	if storage.AuthMode == marklogicv1.BackupStorageAuthModeWorkloadIdentity {
+		return nil, nil
+	}
+	secret, err := cc.getSecret(storage.CredentialsSecretName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup credential secret %s: %w", storage.CredentialsSecretName, err)
+	}
+	if err := validateBackupCredentialSecret(storage, secret); err != nil {
+		return nil, err
+	}
+	switch storage.Provider {
+	case marklogicv1.BackupStorageProviderAzure:
+		creds := &mlmanage.CloudCredentials{
+			Type:                "azure",
+			AzureStorageAccount: string(secret.Data[backupSecretKeyStorageAccount]),
+		}
+		if storage.AuthMode == marklogicv1.BackupStorageAuthModeSAS {
+			creds.AzureSASToken = string(secret.Data[backupSecretKeySASToken])
+		} else {
+			creds.AzureStorageKey = string(secret.Data[backupSecretKeyStorageKey])
+		}
+		return creds, nil
+	default:
+		return &mlmanage.CloudCredentials{
+			Type:         "aws",
+			AWSAccessKey: string(secret.Data[backupSecretKeyAccessKeyID]),
+			AWSSecretKey: string(secret.Data[backupSecretKeySecretAccessKey]),
+		}, nil
+	}
+}
+
+// backupStorageHash identifies what ReconcileBackupStorage registers with
+// MarkLogic: the credentials, and the s3 domain of each group.
+func backupStorageHash(storage *marklogicv1.BackupStorage, creds *mlmanage.CloudCredentials, domain string, groupNames []string) string {
+	data, _ := json.Marshal(struct {
+		Directory   string
+		Credentials *mlmanage.CloudCredentials
+		Domain      string
+		Groups      []string
+	}{backupDirectory(storage), creds, domain, groupNames})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:10]
+}
+
+// requiredBackupSecretKeys returns the Secret keys expected for the provider and auth mode.
+func requiredBackupSecretKeys(storage *marklogicv1.BackupStorage) []string {
+	switch storage.Provider {
+	case marklogicv1.BackupStorageProviderAzure:
+		if storage.AuthMode == marklogicv1.BackupStorageAuthModeSAS {
+			return []string{backupSecretKeyStorageAccount, backupSecretKeySASToken}
+		}
+		return []string{backupSecretKeyStorageAccount, backupSecretKeyStorageKey}
+	default:
+		return []string{backupSecretKeyAccessKeyID, backupSecretKeySecretAccessKey}
+	}
+}
+
+func validateBackupCredentialSecret(storage *marklogicv1.BackupStorage, secret *corev1.Secret) error {
+	missing := []string{}
+	for _, key := range requiredBackupSecretKeys(storage) {
+		if len(secret.Data[key]) == 0 {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("secret %s missing required keys for %s backup storage: %s", secret.Name, storage.Provider, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// backupS3Domain returns the group s3-domain MarkLogic must use, or an empty
+// string when the default AWS endpoint applies.
+func backupS3Domain(storage *marklogicv1.BackupStorage) string {
+	switch storage.Provider {
+	case marklogicv1.BackupStorageProviderGCS:
+		return gcsS3InteropDomain
+	case marklogicv1.BackupStorageProviderS3:
+		if storage.S3 != nil {
+			return strings.TrimSpace(storage.S3.Endpoint)
+		}
+	}
+	return ""
+}
+
+// backupDirectory returns the MarkLogic backup directory URI for the storage target.
+func backupDirectory(storage *marklogicv1.BackupStorage) string {
+	scheme := "s3"
+	if storage.Provider == marklogicv1.BackupStorageProviderAzure {
+		scheme = "azure"
+	}
+	dir := fmt.Sprintf("%s://%s/", scheme, storage.Bucket)
+	if prefix := strings.Trim(storage.Prefix, "/"); prefix != "" {
+		dir += prefix + "/"
+	}
+	return dir
+}
+
+func clusterMarkLogicGroupNames(cr *marklogicv1.MarklogicCluster) []string {
+	seen := map[string]struct{}{}
+	names := []string{}
+	for _, group := range cr.Spec.MarkLogicGroups {
+		if group == nil {
+			continue
+		}
+		name := group.Name
+		if group.GroupConfig != nil && strings.TrimSpace(group.GroupConfig.Name) != "" {
+			name = group.GroupConfig.Name
+		}
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// backupRetryDelaySeconds doubles the initial delay for every failed attempt, capped at the configured maximum.
+func backupRetryDelaySeconds(retry *marklogicv1.BackupStorageRetry, retryCount int32) int32 {
+	initial := defaultBackupRetryInitialDelaySeconds
+	maxDelay := defaultBackupRetryMaxDelaySeconds
+	if retry != nil {
+		if retry.InitialDelaySeconds > 0 {
+			initial = retry.InitialDelaySeconds
+		}
+		if retry.MaxDelaySeconds > 0 {
+			maxDelay = retry.MaxDelaySeconds
+		}
+	}
+	delay := initial
+	for i := int32(1); i < retryCount; i++ {
+		if delay >= maxDelay/2 {
+			return maxDelay
+		}
+		delay *= 2
+	}
+	if delay > maxDelay {
+		return maxDelay
+	}
+	return delay
+}
+
+func (cc *ClusterContext) backupStorageFailed(storage *marklogicv1.BackupStorage, reason string, cause error) result.ReconcileResult {
+	cc.ReqLogger.Error(cause, "Backup storage reconciliation failed", "reason", reason)
+	retryCount := int32(1)
+	if cc.MarklogicCluster.Status.Backup != nil && cc.MarklogicCluster.Status.Backup.Storage != nil {
+		retryCount = cc.MarklogicCluster.Status.Backup.Storage.RetryCount + 1
+	}
+	delay := backupRetryDelaySeconds(storage.Retry, retryCount)
+	nextRetry := metav1.NewTime(time.Now().Add(time.Duration(delay) * time.Second))
+	if err := cc.setBackupStorageStatus(&marklogicv1.BackupStorageStatus{
+		Provider:      storage.Provider,
+		Directory:     backupDirectory(storage),
+		Ready:         false,
+		Message:       cause.Error(),
+		RetryCount:    retryCount,
+		NextRetryTime: &nextRetry,
+	}, reason); err != nil {
+		return result.Error(err)
+	}
+	if cc.Recorder != nil {
+		cc.Recorder.Event(cc.MarklogicCluster, "Warning", reason, cause.Error())
+	}
+	return result.RequeueSoon(int(delay))
+}
+
+func (cc *ClusterContext) setBackupStorageStatus(next *marklogicv1.BackupStorageStatus, reason string) error {
+	cr := cc.MarklogicCluster
+	patchBase := client.MergeFrom(cr.DeepCopy())
+	now := metav1.Now()
+
+	var previous *marklogicv1.BackupStorageStatus
+	if cr.Status.Backup != nil {
+		previous = cr.Status.Backup.Storage
+	}
+	next.LastTransitionTime = &now
+	if previous != nil && previous.Ready == next.Ready && previous.LastTransitionTime != nil {
+		next.LastTransitionTime = previous.LastTransitionTime
+	}
+	if cr.Status.Backup == nil {
+		cr.Status.Backup = &marklogicv1.BackupStatus{}
+	}
+	cr.Status.Backup.Storage = next
+
+	conditionStatus := metav1.ConditionFalse
+	if next.Ready {
+		conditionStatus = metav1.ConditionTrue
+	}
+	cr.Status.SetCondition(metav1.Condition{
+		Type:               string(marklogicv1.BackupStorageReady),
+		Status:             conditionStatus,
+		Reason:             reason,
+		Message:            next.Message,
+		LastTransitionTime: *next.LastTransitionTime,
+	})
+	return patchStatus(cc.Ctx, cc.Client, cr, patchBase)
+}
+
+// ReconcileBackupSchedules pushes spec.backup.schedules into the database-backup
+// properties of each database and reports the last run times back into
+// status.backup.schedules. Databases dropped from the spec have their
+// scheduled backups cleared. The schedules of a database are only pushed when
+// they changed, and the last run times are read every
+// backupScheduleStatusRefreshInterval.
+func (cc *ClusterContext) ReconcileBackupSchedules() result.ReconcileResult {
+	cr := cc.MarklogicCluster
+	if cr.Spec.Backup == nil || !cr.Spec.Backup.Enabled {
+		return result.Continue()
+	}
+	desired := groupBackupSchedulesByDatabase(cr.Spec.Backup)
+	var previous []marklogicv1.ScheduledBackupStatus
+	if cr.Status.Backup != nil {
+		previous = cr.Status.Backup.Schedules
+	}
+	previousByDatabase := map[string]marklogicv1.ScheduledBackupStatus{}
+	removed := []string{}
+	for _, status := range previous {
+		previousByDatabase[status.Database] = status
+		if _, ok := desired[status.Database]; !ok {
+			removed = append(removed, status.Database)
+		}
+	}
+
+	now := metav1.Now()
+	databases := make([]string, 0, len(desired))
+	changed := len(removed) > 0
+	for database, schedules := range desired {
+		databases = append(databases, database)
+		status, ok := previousByDatabase[database]
+		changed = changed || !ok || !status.Configured || status.SpecHash != backupSchedulesHash(schedules) ||
+			backupScheduleRefreshDue(status, now.Time)
+	}
+	if !changed {
+		return result.Continue()
+	}
+	sort.Strings(databases)
+	logger := cc.ReqLogger
+	logger.Info("Reconciling scheduled backups", "databases", len(desired))
+
+	mgmtClient, err := cc.newBootstrapManagementClient()
+	if err != nil {
+		return cc.backupSchedulesFailed(previous, err)
+	}
+
+	for _, database := range removed {
+		logger.Info("Clearing scheduled backups removed from spec", "database", database)
+		if err := mgmtClient.SetDatabaseBackups(cc.Ctx, database, []mlmanage.DatabaseBackupSchedule{}); err != nil {
+			return cc.backupSchedulesFailed(previous, err)
+		}
+	}
+
+	statuses := make([]marklogicv1.ScheduledBackupStatus, 0, len(databases))
+	for _, database := range databases {
+		hash := backupSchedulesHash(desired[database])
+		status, ok := previousByDatabase[database]
+		if !ok || !status.Configured || status.SpecHash != hash {
+			status = marklogicv1.ScheduledBackupStatus{Database: database, LastSyncTime: &now}
+			if err := mgmtClient.SetDatabaseBackups(cc.Ctx, database, desired[database]); err != nil {
+				status.Message = err.Error()
+				statuses = append(statuses, status)
+				continue
+			}
+			status.Configured = true
+			status.SpecHash = hash
+		}
+		if status.LastRefreshTime == nil || backupScheduleRefreshDue(status, now.Time) {
+			status.LastRefreshTime = &now
+			status.Message = ""
+			lastRun, err := mgmtClient.GetDatabaseBackupStatus(cc.Ctx, database)
+			if err != nil {
+				status.Message = fmt.Sprintf("failed to read backup status: %v", err)
+			}
+			status.LastBackupTime = lastRun.LastBackup
+			status.LastIncrementalBackupTime = lastRun.LastIncrementalBackup
+		}
+		statuses = append(statuses, status)
+	}
+
+	failed := []string{}
+	for _, status := range statuses {
+		if !status.Configured {
+			failed = append(failed, status.Database)
+		}
+	}
+	if len(failed) > 0 {
+		if err := cc.setBackupSchedulesStatus(statuses, metav1.ConditionFalse, backupSchedulesReasonFailed, fmt.Sprintf("failed to apply scheduled backups for: %s", strings.Join(failed, ", "))); err != nil {
+			return result.Error(err)
+		}
+		return result.RequeueSoon(30)
+	}
+	if err := cc.setBackupSchedulesStatus(statuses, metav1.ConditionTrue, backupSchedulesReasonApplied, fmt.Sprintf("scheduled backups applied to %d database(s)", len(statuses))); err != nil {
+		return result.Error(err)
+	}
+	return result.Continue()
+}
+
+// backupSchedulesHash identifies the schedules pushed to a database.
+func backupSchedulesHash(schedules []mlmanage.DatabaseBackupSchedule) string {
+	data, _ := json.Marshal(schedules)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:10]
+}
+
+// backupScheduleRefreshDue reports whether the last run times of an applied
+// schedule are to be read again.
+func backupScheduleRefreshDue(status marklogicv1.ScheduledBackupStatus, now time.Time) bool {
+	return status.Configured && (status.LastRefreshTime == nil || !now.Before(status.LastRefreshTime.Add(backupScheduleStatusRefreshInterval)))
+}
+
+// nextBackupScheduleRefresh is when the last run times of the applied
+// schedules are read again.
+func nextBackupScheduleRefresh(cr *marklogicv1.MarklogicCluster) time.Time {
+	if cr.Spec.Backup == nil || !cr.Spec.Backup.Enabled || cr.Status.Backup == nil {
+		return time.Time{}
+	}
+	next := time.Time{}
+	for _, status := range cr.Status.Backup.Schedules {
+		if !status.Configured || status.LastRefreshTime == nil {
+			continue
+		}
+		if at := status.LastRefreshTime.Add(backupScheduleStatusRefreshInterval); next.IsZero() || at.Before(next) {
+			next = at
+		}
+	}
+	return next
+}
+
+func (cc *ClusterContext) backupSchedulesFailed(previous []marklogicv1.ScheduledBackupStatus, cause error) result.ReconcileResult {
+	cc.ReqLogger.Error(cause, "Scheduled backup reconciliation failed")
+	if err := cc.setBackupSchedulesStatus(previous, metav1.ConditionFalse, backupSchedulesReasonFailed, cause.Error()); err != nil {
+		return result.Error(err)
+	}
+	if isTransientManagementError(cause) {
+		return result.RequeueSoon(10)
+	}
+	return result.RequeueSoon(30)
+}
+
+func (cc *ClusterContext) setBackupSchedulesStatus(statuses []marklogicv1.ScheduledBackupStatus, conditionStatus metav1.ConditionStatus, reason, message string) error {
+	cr := cc.MarklogicCluster
+	patchBase := client.MergeFrom(cr.DeepCopy())
+	if cr.Status.Backup == nil {
+		cr.Status.Backup = &marklogicv1.BackupStatus{}
+	}
+	cr.Status.Backup.Schedules = statuses
+	cc.setClusterCondition(marklogicv1.BackupSchedulesApplied, conditionStatus, reason, message)
+	return patchStatus(cc.Ctx, cc.Client, cr, patchBase)
+}
+
+// setClusterCondition upserts a cluster condition, keeping the transition time when the status is unchanged.
+func (cc *ClusterContext) setClusterCondition(conditionType marklogicv1.MarkLogicConditionType, status metav1.ConditionStatus, reason, message string) {
+	cr := cc.MarklogicCluster
+	transition := metav1.Now()
+	for _, existing := range cr.Status.Conditions {
+		if existing.Type == string(conditionType) && existing.Status == status {
+			transition = existing.LastTransitionTime
+		}
+	}
+	cr.Status.SetCondition(metav1.Condition{
+		Type:               string(conditionType),
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		LastTransitionTime: transition,
+	})
+}
+
+// groupBackupSchedulesByDatabase converts the CR schedules into Manage API
+// database-backup entries keyed by database name.
+func groupBackupSchedulesByDatabase(backup *marklogicv1.Backup) map[string][]mlmanage.DatabaseBackupSchedule {
+	grouped := map[string][]mlmanage.DatabaseBackupSchedule{}
+	for _, schedule := range backup.Schedules {
+		directory := schedule.Directory
+		if directory == "" && backup.Storage != nil {
+			directory = backupDirectory(backup.Storage) + schedule.Database + "/"
+		}
+		frequency := schedule.Frequency
+		if frequency == "" {
+			frequency = "daily"
+		}
+		period := int(schedule.Period)
+		if period < 1 {
+			period = 1
+		}
+		startTime := schedule.StartTime
+		if len(startTime) == len("15:04") {
+			startTime += ":00"
+		}
+		lagLimit := int(schedule.JournalArchiveLagLimitSeconds)
+		if lagLimit < 1 {
+			lagLimit = 15
+		}
+		journalPath := schedule.JournalArchivePath
+		if journalPath == "" {
+			journalPath = directory
+		}
+		grouped[schedule.Database] = append(grouped[schedule.Database], mlmanage.DatabaseBackupSchedule{
+			Type:                   frequency,
+			Period:                 period,
+			StartTime:              startTime,
+			Days:                   schedule.DaysOfWeek,
+			MonthDay:               int(schedule.DayOfMonth),
+			Directory:              directory,
+			MaxBackups:             int(schedule.MaxBackups),
+			Incremental:            schedule.Incremental,
+			JournalArchiving:       schedule.JournalArchiving,
+			JournalArchivePath:     journalPath,
+			JournalArchiveLagLimit: lagLimit,
+		})
+	}
+	return grouped
+}
+
+// backupServiceAccountAnnotations returns the workload identity annotations the
+// MarkLogic service account needs to reach the backup object store.
+func backupServiceAccountAnnotations(cr *marklogicv1.MarklogicCluster) map[string]string {
+	if cr == nil || cr.Spec.Backup == nil || !cr.Spec.Backup.Enabled || cr.Spec.Backup.Storage == nil {
+		return nil
+	}
+	storage := cr.Spec.Backup.Storage
+	if storage.AuthMode != marklogicv1.BackupStorageAuthModeWorkloadIdentity {
+		return nil
+	}
+	switch storage.Provider {
+	case marklogicv1.BackupStorageProviderS3:
+		if storage.S3 != nil && storage.S3.RoleARN != "" {
+			return map[string]string{eksRoleARNAnnotation: storage.S3.RoleARN}
+		}
+	case marklogicv1.BackupStorageProviderAzure:
+		if storage.Azure != nil && storage.Azure.ClientID != "" {
+			return map[string]string{azureWorkloadIdentityAnnotation: storage.Azure.ClientID}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,385 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func newBackupTestCluster(storage *marklogicv1.BackupStorage) *marklogicv1.MarklogicCluster {
+	return &marklogicv1.MarklogicCluster{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "marklogic.progress.com/v1", Kind: "MarklogicCluster"},
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			ClusterDomain: "cluster.local",
+			Backup:        &marklogicv1.Backup{Enabled: true, Storage: storage},
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{
+				{Name: "dnode", IsBootstrap: true, GroupConfig: &marklogicv1.GroupConfig{Name: "dnode"}},
+				{Name: "enode", GroupConfig: &marklogicv1.GroupConfig{Name: "enode"}},
+			},
+		},
+	}
+}
+
+func newBackupTestContext(t *testing.T, cr *marklogicv1.MarklogicCluster, objects ...client.Object) *ClusterContext {
+	t.Helper()
+	adminSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml-admin", Namespace: "default"},
+		Data:       map[string][]byte{"username": []byte("admin"), "password": []byte("admin")},
+	}
+	fakeClient, scheme := newFakeClient(t, append(objects, cr, adminSecret)...)
+	return &ClusterContext{
+		Ctx:              context.Background(),
+		Client:           fakeClient,
+		Scheme:           scheme,
+		MarklogicCluster: cr,
+		Recorder:         record.NewFakeRecorder(10),
+	}
+}
+
+func TestValidateBackupCredentialSecretReportsMissingProviderKeys(t *testing.T) {
+	t.Parallel()
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "azure-creds"},
+		Data:       map[string][]byte{backupSecretKeyStorageAccount: []byte("mlbackups")},
+	}
+	storage := &marklogicv1.BackupStorage{Provider: marklogicv1.BackupStorageProviderAzure, AuthMode: marklogicv1.BackupStorageAuthModeSAS}
+	err := validateBackupCredentialSecret(storage, secret)
+	if err == nil || !strings.Contains(err.Error(), backupSecretKeySASToken) {
+		t.Fatalf("expected missing sas-token error, got %v", err)
+	}
+
+	storage.AuthMode = marklogicv1.BackupStorageAuthModeSecret
+	err = validateBackupCredentialSecret(storage, secret)
+	if err == nil || !strings.Contains(err.Error(), backupSecretKeyStorageKey) {
+		t.Fatalf("expected missing storage-key error, got %v", err)
+	}
+}
+
+func TestBackupRetryDelaySecondsBacksOffExponentiallyUpToMax(t *testing.T) {
+	t.Parallel()
+
+	retry := &marklogicv1.BackupStorageRetry{InitialDelaySeconds: 5, MaxDelaySeconds: 60}
+	expected := []int32{5, 10, 20, 40, 60, 60}
+	for i, want := range expected {
+		if got := backupRetryDelaySeconds(retry, int32(i+1)); got != want {
+			t.Fatalf("retry %d: expected %d, got %d", i+1, want, got)
+		}
+	}
+}
+
+func TestBackupServiceAccountAnnotationsForWorkloadIdentity(t *testing.T) {
+	t.Parallel()
+
+	cr := newBackupTestCluster(&marklogicv1.BackupStorage{
+		Provider: marklogicv1.BackupStorageProviderS3,
+		Bucket:   "ml-backups",
+		AuthMode: marklogicv1.BackupStorageAuthModeWorkloadIdentity,
+		S3:       &marklogicv1.S3BackupStorage{RoleARN: "arn:aws:iam::123456789012:role/ml-backups"},
+	})
+	annotations := backupServiceAccountAnnotations(cr)
+	if annotations[eksRoleARNAnnotation] != "arn:aws:iam::123456789012:role/ml-backups" {
+		t.Fatalf("expected IRSA annotation, got %v", annotations)
+	}
+
+	cr.Spec.Backup.Storage.AuthMode = marklogicv1.BackupStorageAuthModeSecret
+	if annotations := backupServiceAccountAnnotations(cr); annotations != nil {
+		t.Fatalf("expected no annotations for secret auth, got %v", annotations)
+	}
+}
+
+func TestReconcileBackupStorageRegistersAzureSASCredentials(t *testing.T) {
+	cr := newBackupTestCluster(&marklogicv1.BackupStorage{
+		Provider:              marklogicv1.BackupStorageProviderAzure,
+		Bucket:                "backups",
+		Prefix:                "/prod/",
+		AuthMode:              marklogicv1.BackupStorageAuthModeSAS,
+		CredentialsSecretName: "azure-creds",
+	})
+	credSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "azure-creds", Namespace: "default"},
+		Data: map[string][]byte{
+			backupSecretKeyStorageAccount: []byte("mlbackups"),
+			backupSecretKeySASToken:       []byte("sv=2024&sig=abc"),
+		},
+	}
+	cc := newBackupTestContext(t, cr, credSecret)
+
+	var gotCreds mlmanage.CloudCredentials
+	var gotHost string
+	registered := 0
+	original := NewDynamicManagementClient
+	NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+		gotHost = opts.Host
+		return &stubDynamicManagementClient{
+			setCredentialsFn: func(creds mlmanage.CloudCredentials) error {
+				gotCreds = creds
+				registered++
+				return nil
+			},
+			setS3DomainFn: func(groupName, domain string) error {
+				return errors.New("s3 domain must not be set for azure")
+			},
+		}
+	}
+	defer func() { NewDynamicManagementClient = original }()
+
+	res := cc.ReconcileBackupStorage()
+	if res.Completed() {
+		t.Fatalf("expected reconcile to continue, got completed result")
+	}
+	if gotHost != "dnode-0.dnode.default.svc.cluster.local" {
+		t.Fatalf("unexpected bootstrap host %q", gotHost)
+	}
+	if gotCreds.Type != "azure" || gotCreds.AzureSASToken != "sv=2024&sig=abc" || gotCreds.AzureStorageKey != "" {
+		t.Fatalf("unexpected credentials %+v", gotCreds)
+	}
+
+	latest := &marklogicv1.MarklogicCluster{}
+	if err := cc.Client.Get(cc.Ctx, types.NamespacedName{Name: "ml", Namespace: "default"}, latest); err != nil {
+		t.Fatalf("failed to get cluster: %v", err)
+	}
+	status := latest.Status.Backup.Storage
+	if !status.Ready || status.Directory != "azure://backups/prod/" || status.RetryCount != 0 {
+		t.Fatalf("unexpected backup storage status %+v", status)
+	}
+	if latest.Status.GetConditionStatus(string(marklogicv1.BackupStorageReady)) != metav1.ConditionTrue {
+		t.Fatalf("expected %s condition to be true", marklogicv1.BackupStorageReady)
+	}
+
+	if res := cc.ReconcileBackupStorage(); res.Completed() || registered != 1 {
+		t.Fatalf("expected unchanged credentials not to be registered again, got %d registrations", registered)
+	}
+	credSecret.Data[backupSecretKeySASToken] = []byte("sv=2024&sig=def")
+	if err := cc.Client.Update(cc.Ctx, credSecret); err != nil {
+		t.Fatalf("failed to update the credential secret: %v", err)
+	}
+	if res := cc.ReconcileBackupStorage(); res.Completed() || registered != 2 || gotCreds.AzureSASToken != "sv=2024&sig=def" {
+		t.Fatalf("expected the rotated credentials to be registered, got %d registrations of %+v", registered, gotCreds)
+	}
+}
+
+func TestReconcileBackupStorageBacksOffOnManagementFailure(t *testing.T) {
+	cr := newBackupTestCluster(&marklogicv1.BackupStorage{
+		Provider:              marklogicv1.BackupStorageProviderGCS,
+		Bucket:                "ml-backups",
+		AuthMode:              marklogicv1.BackupStorageAuthModeSecret,
+		CredentialsSecretName: "gcs-hmac",
+		Retry:                 &marklogicv1.BackupStorageRetry{InitialDelaySeconds: 7, MaxDelaySeconds: 120},
+	})
+	credSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "gcs-hmac", Namespace: "default"},
+		Data: map[string][]byte{
+			backupSecretKeyAccessKeyID:     []byte("GOOG1E"),
+			backupSecretKeySecretAccessKey: []byte("secret"),
+		},
+	}
+	cc := newBackupTestContext(t, cr, credSecret)
+
+	var domains []string
+	original := NewDynamicManagementClient
+	NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+		return &stubDynamicManagementClient{
+			setS3DomainFn: func(groupName, domain string) error {
+				domains = append(domains, groupName+"="+domain)
+				return errors.New("management api PUT /manage/v2/groups/enode/properties returned status 503: unavailable")
+			},
+		}
+	}
+	defer func() { NewDynamicManagementClient = original }()
+
+	res := cc.ReconcileBackupStorage()
+	if !res.Completed() {
+		t.Fatalf("expected reconcile to requeue after failure")
+	}
+	out, err := res.Output()
+	if err != nil || out.RequeueAfter.Seconds() != 7 {
+		t.Fatalf("expected requeue after 7s, got %+v err=%v", out, err)
+	}
+	if len(domains) != 1 || domains[0] != "dnode="+gcsS3InteropDomain {
+		t.Fatalf("unexpected s3 domain calls %v", domains)
+	}
+	status := cc.MarklogicCluster.Status.Backup.Storage
+	if status.Ready || status.RetryCount != 1 || status.NextRetryTime == nil {
+		t.Fatalf("unexpected backup storage status %+v", status)
+	}
+}
+
+func TestGroupBackupSchedulesByDatabaseDefaultsDirectoryFromStorage(t *testing.T) {
+	t.Parallel()
+
+	backup := &marklogicv1.Backup{
+		Enabled: true,
+		Storage: &marklogicv1.BackupStorage{Provider: marklogicv1.BackupStorageProviderS3, Bucket: "ml", Prefix: "nightly"},
+		Schedules: []marklogicv1.BackupSchedule{
+			{Database: "Documents", Frequency: "daily", StartTime: "01:30", MaxBackups: 3},
+			{Database: "Documents", Frequency: "hourly", Period: 4, Incremental: true},
+			{Database: "Security", Frequency: "weekly", DaysOfWeek: []string{"sunday"}, Directory: "/var/opt/backups/"},
+		},
+	}
+	grouped := groupBackupSchedulesByDatabase(backup)
+	documents := grouped["Documents"]
+	if len(documents) != 2 {
+		t.Fatalf("expected two Documents schedules, got %+v", documents)
+	}
+	if documents[0].Directory != "s3://ml/nightly/Documents/" || documents[0].StartTime != "01:30:00" || documents[0].Period != 1 {
+		t.Fatalf("unexpected daily schedule %+v", documents[0])
+	}
+	if !documents[1].Incremental || documents[1].Period != 4 {
+		t.Fatalf("unexpected incremental schedule %+v", documents[1])
+	}
+	if grouped["Security"][0].Directory != "/var/opt/backups/" {
+		t.Fatalf("expected explicit directory to be kept, got %+v", grouped["Security"][0])
+	}
+}
+
+func TestReconcileBackupSchedulesReportsLastRunAndClearsRemovedDatabases(t *testing.T) {
+	cr := newBackupTestCluster(nil)
+	cr.Spec.Backup.Schedules = []marklogicv1.BackupSchedule{
+		{Database: "Documents", Frequency: "daily", StartTime: "02:00", Directory: "/backups/"},
+	}
+	cr.Status.Backup = &marklogicv1.BackupStatus{
+		Schedules: []marklogicv1.ScheduledBackupStatus{{Database: "Legacy", Configured: true}},
+	}
+	cc := newBackupTestContext(t, cr)
+
+	pushed := map[string]int{}
+	pushes, reads := 0, 0
+	original := NewDynamicManagementClient
+	NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+		return &stubDynamicManagementClient{
+			setBackupsFn: func(database string, schedules []mlmanage.DatabaseBackupSchedule) error {
+				pushed[database] = len(schedules)
+				pushes++
+				return nil
+			},
+			backupStatusFn: func(database string) (mlmanage.DatabaseBackupStatus, error) {
+				reads++
+				return mlmanage.DatabaseBackupStatus{LastBackup: "2026-10-14T02:00:04Z"}, nil
+			},
+		}
+	}
+	defer func() { NewDynamicManagementClient = original }()
+
+	if res := cc.ReconcileBackupSchedules(); res.Completed() {
+		t.Fatalf("expected reconcile to continue, got completed result")
+	}
+	if next := nextBackupScheduleRefresh(cc.MarklogicCluster); time.Until(next) < backupScheduleStatusRefreshInterval-time.Minute {
+		t.Fatalf("expected the last run times to be refreshed later, got %v", next)
+	}
+	if count, ok := pushed["Legacy"]; !ok || count != 0 {
+		t.Fatalf("expected Legacy schedules to be cleared, got %v", pushed)
+	}
+	if pushed["Documents"] != 1 {
+		t.Fatalf("expected one Documents schedule, got %v", pushed)
+	}
+	schedules := cc.MarklogicCluster.Status.Backup.Schedules
+	if len(schedules) != 1 || !schedules[0].Configured || schedules[0].LastBackupTime != "2026-10-14T02:00:04Z" {
+		t.Fatalf("unexpected schedule status %+v", schedules)
+	}
+	if cc.MarklogicCluster.Status.GetConditionStatus(string(marklogicv1.BackupSchedulesApplied)) != metav1.ConditionTrue {
+		t.Fatalf("expected %s condition to be true", marklogicv1.BackupSchedulesApplied)
+	}
+
+	applied := cc.MarklogicCluster.Status.Backup.DeepCopy()
+	if res := cc.ReconcileBackupSchedules(); res.Completed() || pushes != 2 || reads != 1 {
+		t.Fatalf("expected unchanged schedules not to be pushed or read again, got %d pushes and %d reads", pushes, reads)
+	}
+	if !equality.Semantic.DeepEqual(applied, cc.MarklogicCluster.Status.Backup) {
+		t.Fatalf("expected the status to be unchanged, got %+v", cc.MarklogicCluster.Status.Backup)
+	}
+
+	stale := metav1.NewTime(time.Now().Add(-backupScheduleStatusRefreshInterval))
+	cc.MarklogicCluster.Status.Backup.Schedules[0].LastRefreshTime = &stale
+	if res := cc.ReconcileBackupSchedules(); res.Completed() || pushes != 2 || reads != 2 {
+		t.Fatalf("expected only the last run times to be refreshed, got %d pushes and %d reads", pushes, reads)
+	}
+
+	cc.MarklogicCluster.Spec.Backup.Schedules[0].StartTime = "03:00"
+	if res := cc.ReconcileBackupSchedules(); res.Completed() || pushes != 3 {
+		t.Fatalf("expected the changed schedule to be pushed, got %d pushes", pushes)
+	}
+}
+
+func TestReconcileBackupRestoreTracksJobAcrossAllForests(t *testing.T) {
+	restoreTo := metav1.NewTime(time.Date(2026, 10, 14, 9, 30, 0, 0, time.UTC))
+	cr := newBackupTestCluster(&marklogicv1.BackupStorage{Provider: marklogicv1.BackupStorageProviderS3, Bucket: "ml"})
+	cr.Spec.Backup.Schedules = []marklogicv1.BackupSchedule{{Database: "Documents", Frequency: "daily", JournalArchiving: true}}
+	cr.Spec.Backup.Restore = &marklogicv1.BackupRestore{RestoreID: "r1", Database: "Documents", RestoreToTime: &restoreTo, IncludeReplicas: true}
+	cc := newBackupTestContext(t, cr)
+
+	var gotReq mlmanage.DatabaseRestoreRequest
+	jobStatus := "in-progress"
+	original := NewDynamicManagementClient
+	NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+		return &stubDynamicManagementClient{
+			listForestsFn: func(database string) ([]string, error) {
+				return []string{"Documents-2", "Documents-1"}, nil
+			},
+			startRestoreFn: func(database string, req mlmanage.DatabaseRestoreRequest) (string, error) {
+				gotReq = req
+				return "job-42", nil
+			},
+			restoreStatusFn: func(database, jobID string) (mlmanage.DatabaseRestoreStatus, error) {
+				return mlmanage.DatabaseRestoreStatus{Status: jobStatus, Forests: map[string]string{"Documents-1": jobStatus, "Documents-2": jobStatus}}, nil
+			},
+		}
+	}
+	defer func() { NewDynamicManagementClient = original }()
+
+	if res := cc.ReconcileBackupRestore(); !res.Completed() {
+		t.Fatalf("expected restore start to requeue")
+	}
+	if gotReq.Directory != "s3://ml/Documents/" || !gotReq.JournalArchive || gotReq.RestoreToTime != "2026-10-14T09:30:00Z" {
+		t.Fatalf("unexpected restore request %+v", gotReq)
+	}
+	if len(gotReq.Forests) != 2 || gotReq.Forests[0] != "Documents-1" {
+		t.Fatalf("expected all forests in sorted order, got %v", gotReq.Forests)
+	}
+	status := cc.MarklogicCluster.Status.Backup.Restore
+	if status.Phase != marklogicv1.RestorePhaseRunning || status.JobID != "job-42" {
+		t.Fatalf("unexpected restore status %+v", status)
+	}
+
+	jobStatus = "completed"
+	if res := cc.ReconcileBackupRestore(); res.Completed() {
+		t.Fatalf("expected completed restore to continue")
+	}
+	status = cc.MarklogicCluster.Status.Backup.Restore
+	if status.Phase != marklogicv1.RestorePhaseCompleted || status.CompletionTime == nil || status.Forests[1].Status != "completed" {
+		t.Fatalf("unexpected restore status %+v", status)
+	}
+}
+
+func TestReconcileBackupRestoreRequiresJournalArchivingForPointInTime(t *testing.T) {
+	restoreTo := metav1.Now()
+	cr := newBackupTestCluster(&marklogicv1.BackupStorage{Provider: marklogicv1.BackupStorageProviderS3, Bucket: "ml"})
+	cr.Spec.Backup.Restore = &marklogicv1.BackupRestore{RestoreID: "r1", Database: "Documents", RestoreToTime: &restoreTo}
+	cc := newBackupTestContext(t, cr)
+
+	original := NewDynamicManagementClient
+	NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+		return &stubDynamicManagementClient{}
+	}
+	defer func() { NewDynamicManagementClient = original }()
+
+	cc.ReconcileBackupRestore()
+	status := cc.MarklogicCluster.Status.Backup.Restore
+	if status.Phase != marklogicv1.RestorePhaseFailed || !strings.Contains(status.Message, "journal archiving") {
+		t.Fatalf("unexpected restore status %+v", status)
+	}
+}
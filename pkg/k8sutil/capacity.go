@@ -0,0 +1,250 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// capacityReportInterval is how often status.capacity is refreshed.
+const capacityReportInterval = 5 * time.Minute
+
+var podMetricsListGVK = schema.GroupVersionKind{Group: "metrics.k8s.io", Version: "v1beta1", Kind: "PodMetricsList"}
+
+var (
+	capacityMetricLabels = []string{"namespace", "cluster", "group"}
+
+	capacityCPURequests = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "marklogic_cluster_cpu_requests_cores",
+		Help: "CPU requested by the containers of the MarkLogic pods of a group.",
+	}, capacityMetricLabels)
+	capacityCPUUsage = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "marklogic_cluster_cpu_usage_cores",
+		Help: "CPU used by the MarkLogic pods of a group, as reported by the metrics API.",
+	}, capacityMetricLabels)
+	capacityMemoryRequests = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "marklogic_cluster_memory_requests_bytes",
+		Help: "Memory requested by the containers of the MarkLogic pods of a group.",
+	}, capacityMetricLabels)
+	capacityMemoryUsage = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "marklogic_cluster_memory_usage_bytes",
+		Help: "Memory used by the MarkLogic pods of a group, as reported by the metrics API.",
+	}, capacityMetricLabels)
+	capacityStorageRequests = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "marklogic_cluster_storage_requests_bytes",
+		Help: "Storage requested by the persistent volume claims of the MarkLogic pods of a group.",
+	}, capacityMetricLabels)
+	capacityStorageCapacity = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "marklogic_cluster_storage_capacity_bytes",
+		Help: "Storage provisioned for the persistent volume claims of the MarkLogic pods of a group.",
+	}, capacityMetricLabels)
+
+	capacityGauges = []*prometheus.GaugeVec{
+		capacityCPURequests, capacityCPUUsage, capacityMemoryRequests,
+		capacityMemoryUsage, capacityStorageRequests, capacityStorageCapacity,
+	}
+)
+
+func init() {
+	for _, gauge := range capacityGauges {
+		metrics.Registry.MustRegister(gauge)
+	}
+}
+
+// ReconcileCapacity refreshes status.capacity and the capacity metrics every
+// capacityReportInterval. The CPU and memory usage comes from the metrics API
+// and is left out when it is not served. Failures are logged and never hold
+// up the rest of the reconcile.
+func (cc *ClusterContext) ReconcileCapacity() result.ReconcileResult {
+	cr := cc.MarklogicCluster
+	now := time.Now()
+	if status := cr.Status.Capacity; status != nil && status.LastUpdateTime != nil && now.Sub(status.LastUpdateTime.Time) < capacityReportInterval {
+		return result.Continue()
+	}
+	capacity, err := cc.clusterCapacity()
+	if err != nil {
+		cc.ReqLogger.Error(err, "Failed to collect the cluster capacity")
+		return result.Continue()
+	}
+	setCapacityMetrics(cr, capacity)
+	capacity.LastUpdateTime = &metav1.Time{Time: now}
+	patchBase := client.MergeFrom(cr.DeepCopy())
+	cr.Status.Capacity = capacity
+	if err := patchStatus(cc.Ctx, cc.Client, cr, patchBase); err != nil {
+		cc.ReqLogger.Error(err, "Failed to update the cluster capacity")
+	}
+	return result.Continue()
+}
+
+// nextCapacityReport is when status.capacity is refreshed next, or the zero
+// time when it was never reported.
+func nextCapacityReport(cr *marklogicv1.MarklogicCluster) time.Time {
+	if cr.Status.Capacity == nil || cr.Status.Capacity.LastUpdateTime == nil {
+		return time.Time{}
+	}
+	return cr.Status.Capacity.LastUpdateTime.Add(capacityReportInterval)
+}
+
+func (cc *ClusterContext) clusterCapacity() (*marklogicv1.CapacityStatus, error) {
+	cr := cc.MarklogicCluster
+	capacity := &marklogicv1.CapacityStatus{}
+	usage, usageAvailable := cc.podUsage()
+	for _, group := range cr.Spec.MarkLogicGroups {
+		if group == nil {
+			continue
+		}
+		pods := &corev1.PodList{}
+		if err := cc.Client.List(cc.Ctx, pods, client.InNamespace(cr.Namespace), client.MatchingLabels{
+			"app.kubernetes.io/name":     "marklogic",
+			"app.kubernetes.io/instance": group.Name,
+		}); err != nil {
+			return nil, err
+		}
+		groupCapacity := marklogicv1.GroupCapacity{Group: group.Name}
+		if usageAvailable {
+			groupCapacity.CPUUsage = resource.NewMilliQuantity(0, resource.DecimalSI)
+			groupCapacity.MemoryUsage = resource.NewQuantity(0, resource.BinarySI)
+		}
+		for i := range pods.Items {
+			pod := &pods.Items[i]
+			groupCapacity.Pods++
+			for _, container := range pod.Spec.Containers {
+				groupCapacity.CPURequests.Add(container.Resources.Requests[corev1.ResourceCPU])
+				groupCapacity.MemoryRequests.Add(container.Resources.Requests[corev1.ResourceMemory])
+			}
+			if podUsage, ok := usage[pod.Name]; ok && usageAvailable {
+				groupCapacity.CPUUsage.Add(podUsage[corev1.ResourceCPU])
+				groupCapacity.MemoryUsage.Add(podUsage[corev1.ResourceMemory])
+			}
+			if err := cc.addStorageCapacity(pod, &groupCapacity.ResourceCapacity); err != nil {
+				return nil, err
+			}
+		}
+		addCapacity(&capacity.Total, groupCapacity.ResourceCapacity)
+		capacity.Groups = append(capacity.Groups, groupCapacity)
+	}
+	return capacity, nil
+}
+
+func (cc *ClusterContext) addStorageCapacity(pod *corev1.Pod, capacity *marklogicv1.ResourceCapacity) error {
+	for _, volume := range pod.Spec.Volumes {
+		if volume.PersistentVolumeClaim == nil {
+			continue
+		}
+		pvc := &corev1.PersistentVolumeClaim{}
+		if err := cc.Client.Get(cc.Ctx, types.NamespacedName{Namespace: pod.Namespace, Name: volume.PersistentVolumeClaim.ClaimName}, pvc); err != nil {
+			if client.IgnoreNotFound(err) == nil {
+				continue
+			}
+			return err
+		}
+		capacity.StorageRequests.Add(pvc.Spec.Resources.Requests[corev1.ResourceStorage])
+		capacity.StorageCapacity.Add(pvc.Status.Capacity[corev1.ResourceStorage])
+	}
+	return nil
+}
+
+// podUsage returns the CPU and memory usage of the pods of the namespace from
+// the metrics API, and false when the API is not served or has no metrics of
+// the pods yet.
+func (cc *ClusterContext) podUsage() (map[string]corev1.ResourceList, bool) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(podMetricsListGVK)
+	if err := cc.Client.List(cc.Ctx, list, client.InNamespace(cc.MarklogicCluster.Namespace), client.MatchingLabels{"app.kubernetes.io/name": "marklogic"}); err != nil {
+		cc.ReqLogger.V(1).Info("Pod metrics are not available", "error", err.Error())
+		return nil, false
+	}
+	if len(list.Items) == 0 {
+		return nil, false
+	}
+	usage := map[string]corev1.ResourceList{}
+	for _, item := range list.Items {
+		containers, _, _ := unstructured.NestedSlice(item.Object, "containers")
+		podUsage := corev1.ResourceList{}
+		for _, container := range containers {
+			fields, ok := container.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			values, _, _ := unstructured.NestedStringMap(fields, "usage")
+			for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+				quantity, err := resource.ParseQuantity(values[string(name)])
+				if err != nil {
+					continue
+				}
+				total := podUsage[name]
+				total.Add(quantity)
+				podUsage[name] = total
+			}
+		}
+		usage[item.GetName()] = podUsage
+	}
+	return usage, true
+}
+
+func addCapacity(total *marklogicv1.ResourceCapacity, capacity marklogicv1.ResourceCapacity) {
+	total.Pods += capacity.Pods
+	total.CPURequests.Add(capacity.CPURequests)
+	total.MemoryRequests.Add(capacity.MemoryRequests)
+	total.StorageRequests.Add(capacity.StorageRequests)
+	total.StorageCapacity.Add(capacity.StorageCapacity)
+	if capacity.CPUUsage != nil {
+		if total.CPUUsage == nil {
+			total.CPUUsage = resource.NewMilliQuantity(0, resource.DecimalSI)
+		}
+		total.CPUUsage.Add(*capacity.CPUUsage)
+	}
+	if capacity.MemoryUsage != nil {
+		if total.MemoryUsage == nil {
+			total.MemoryUsage = resource.NewQuantity(0, resource.BinarySI)
+		}
+		total.MemoryUsage.Add(*capacity.MemoryUsage)
+	}
+}
+
+func setCapacityMetrics(cr *marklogicv1.MarklogicCluster, capacity *marklogicv1.CapacityStatus) {
+	// Groups removed from the cluster drop out of the metrics.
+	deleteClusterSeries(capacityGauges, cr.Namespace, cr.Name)
+	for _, group := range capacity.Groups {
+		labels := prometheus.Labels{"namespace": cr.Namespace, "cluster": cr.Name, "group": group.Group}
+		capacityCPURequests.With(labels).Set(group.CPURequests.AsApproximateFloat64())
+		capacityMemoryRequests.With(labels).Set(group.MemoryRequests.AsApproximateFloat64())
+		capacityStorageRequests.With(labels).Set(group.StorageRequests.AsApproximateFloat64())
+		capacityStorageCapacity.With(labels).Set(group.StorageCapacity.AsApproximateFloat64())
+		if group.CPUUsage != nil {
+			capacityCPUUsage.With(labels).Set(group.CPUUsage.AsApproximateFloat64())
+		}
+		if group.MemoryUsage != nil {
+			capacityMemoryUsage.With(labels).Set(group.MemoryUsage.AsApproximateFloat64())
+		}
+	}
+}
+
+// DeleteClusterMetrics removes the capacity and load metrics of a cluster,
+// once the cluster is deleted.
+func DeleteClusterMetrics(namespace, name string) {
+	deleteClusterSeries(capacityGauges, namespace, name)
+	deleteClusterSeries(loadGauges, namespace, name)
+	deleteClusterSeries(replicationGauges, namespace, name)
+	deleteClusterSeries(fluentBitGauges, namespace, name)
+	loadMetricsCollected.Delete(types.NamespacedName{Namespace: namespace, Name: name})
+	logCollectionMetricsCollected.Delete(types.NamespacedName{Namespace: namespace, Name: name})
+}
+
+func deleteClusterSeries(gauges []*prometheus.GaugeVec, namespace, name string) {
+	for _, gauge := range gauges {
+		gauge.DeletePartialMatch(prometheus.Labels{"namespace": namespace, "cluster": name})
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"testing"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestCapacityIsReportedInStatusAndMetrics(t *testing.T) {
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "capacity", Namespace: "default"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{{Name: "dnode", IsBootstrap: true}},
+		},
+	}
+	pods := []*corev1.Pod{newStorageTestPod("dnode-0"), newStorageTestPod("dnode-1")}
+	objs := []client.Object{}
+	for _, pod := range pods {
+		pod.Spec.Containers = []corev1.Container{{
+			Name: "marklogic-server",
+			Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("500m"),
+				corev1.ResourceMemory: resource.MustParse("2Gi"),
+			}},
+		}}
+		pod.Spec.Volumes = []corev1.Volume{{Name: "datadir", VolumeSource: corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "datadir-" + pod.Name},
+		}}}
+		objs = append(objs, pod, &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "datadir-" + pod.Name, Namespace: "default"},
+			Spec: corev1.PersistentVolumeClaimSpec{Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")},
+			}},
+			Status: corev1.PersistentVolumeClaimStatus{Capacity: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("20Gi")}},
+		})
+	}
+	cc := newUpgradeTestContext(t, cr, objs...)
+	defer DeleteClusterMetrics("default", "capacity")
+
+	cc.ReconcileCapacity()
+	capacity := cc.MarklogicCluster.Status.Capacity
+	if capacity == nil || len(capacity.Groups) != 1 {
+		t.Fatalf("expected the capacity of one group, got %+v", capacity)
+	}
+	total := capacity.Total
+	if total.Pods != 2 || total.CPURequests.Cmp(resource.MustParse("1")) != 0 || total.MemoryRequests.Cmp(resource.MustParse("4Gi")) != 0 {
+		t.Fatalf("unexpected requests %+v", total)
+	}
+	if total.StorageRequests.Cmp(resource.MustParse("20Gi")) != 0 || total.StorageCapacity.Cmp(resource.MustParse("40Gi")) != 0 {
+		t.Fatalf("unexpected storage %+v", total)
+	}
+	if total.CPUUsage != nil || total.MemoryUsage != nil {
+		t.Fatalf("expected no usage without the metrics API, got %+v", total)
+	}
+	if got := testutil.ToFloat64(capacityCPURequests.WithLabelValues("default", "capacity", "dnode")); got != 1 {
+		t.Fatalf("expected 1 CPU requested in the metrics, got %v", got)
+	}
+	if next := nextCapacityReport(cc.MarklogicCluster); !next.Equal(capacity.LastUpdateTime.Add(capacityReportInterval)) {
+		t.Fatalf("expected the next report one interval later, got %s", next)
+	}
+}
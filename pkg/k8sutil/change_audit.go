@@ -0,0 +1,171 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	changeLogReasonSpecChanged = "SpecChanged"
+
+	// auditedSecretSet stands in for inline passwords, which are never
+	// written to the status: only setting or removing them is recorded.
+	auditedSecretSet = "<set>"
+)
+
+// auditedField is a spec field recorded in the change log, with the path of
+// the managed fields that tells who wrote it.
+type auditedField struct {
+	value   string
+	managed []string
+}
+
+// auditedFields returns the fields of the cluster whose changes are recorded
+// in status.changeLog, keyed by their path.
+func auditedFields(cr *marklogicv1.MarklogicCluster) map[string]auditedField {
+	fields := map[string]auditedField{
+		"spec.image": {value: cr.Spec.Image, managed: []string{"f:spec", "f:image"}},
+	}
+	groups := []string{"f:spec", "f:markLogicGroups"}
+	for _, group := range cr.Spec.MarkLogicGroups {
+		if group == nil {
+			continue
+		}
+		replicas := int32(1)
+		if group.Replicas != nil {
+			replicas = *group.Replicas
+		}
+		path := fmt.Sprintf("spec.markLogicGroups[%s]", group.Name)
+		fields[path+".replicas"] = auditedField{value: strconv.Itoa(int(replicas)), managed: groups}
+		if group.Image != "" {
+			fields[path+".image"] = auditedField{value: group.Image, managed: groups}
+		}
+	}
+	if auth := cr.Spec.Auth; auth != nil {
+		authField := func(name string, value *string, secret bool) {
+			if value == nil || *value == "" {
+				return
+			}
+			v := *value
+			if secret {
+				v = auditedSecretSet
+			}
+			fields["spec.auth."+name] = auditedField{value: v, managed: []string{"f:spec", "f:auth", "f:" + name}}
+		}
+		authField("secretName", auth.SecretName, false)
+		authField("adminUsername", auth.AdminUsername, false)
+		authField("adminPassword", auth.AdminPassword, true)
+		authField("walletPassword", auth.WalletPassword, true)
+		authField("realm", auth.Realm, false)
+		if auth.OperatorUser != nil {
+			fields["spec.auth.operatorUser"] = auditedField{value: strconv.FormatBool(*auth.OperatorUser), managed: []string{"f:spec", "f:auth", "f:operatorUser"}}
+		}
+	}
+	return fields
+}
+
+// ReconcileChangeAudit compares the audited fields of a new generation of
+// the cluster with those last observed and records every change, with the
+// field manager that made it, in status.changeLog and as a SpecChanged
+// event. The first generation seen is only observed.
+func (cc *ClusterContext) ReconcileChangeAudit() result.ReconcileResult {
+	cr := cc.MarklogicCluster
+	previous := cr.Status.ChangeLog
+	if previous != nil && previous.ObservedGeneration == cr.Generation {
+		return result.Continue()
+	}
+
+	fields := auditedFields(cr)
+	observed := make(map[string]string, len(fields))
+	for path, field := range fields {
+		observed[path] = field.value
+	}
+	changeLog := &marklogicv1.ChangeLogStatus{ObservedGeneration: cr.Generation, Observed: observed}
+	if previous != nil {
+		changeLog.Entries = previous.Entries
+		paths := []string{}
+		for path := range observed {
+			paths = append(paths, path)
+		}
+		for path := range previous.Observed {
+			if _, ok := observed[path]; !ok {
+				paths = append(paths, path)
+			}
+		}
+		slices.Sort(paths)
+		now := metav1.Now()
+		for _, path := range paths {
+			old, current := previous.Observed[path], observed[path]
+			if old == current {
+				continue
+			}
+			managed, ok := fields[path]
+			if !ok {
+				managed = auditedField{managed: removedFieldManagedPath(path)}
+			}
+			entry := marklogicv1.SpecChangeEntry{
+				Time:       now,
+				Generation: cr.Generation,
+				Field:      path,
+				Old:        old,
+				New:        current,
+				Manager:    changeManager(cr, managed.managed),
+			}
+			changeLog.Record(entry)
+			cc.ReqLogger.Info("Spec changed", "field", entry.Field, "old", entry.Old, "new", entry.New, "manager", entry.Manager)
+			cc.recordClusterEvent(corev1.EventTypeNormal, changeLogReasonSpecChanged, specChangeMessage(entry))
+		}
+	}
+
+	patchBase := client.MergeFrom(cr.DeepCopy())
+	cr.Status.ChangeLog = changeLog
+	if err := patchStatus(cc.Ctx, cc.Client, cr, patchBase); err != nil {
+		return result.Error(err)
+	}
+	return result.Continue()
+}
+
+// changeManager returns the field manager that last wrote the managed fields
+// path of a change. A removed field has no manager of its own, so whoever
+// last wrote its closest remaining parent removed it.
+func changeManager(obj metav1.Object, path []string) string {
+	for n := len(path); n > 0; n-- {
+		if manager, ok := lastFieldManager(obj, path[:n]...); ok {
+			return manager
+		}
+	}
+	return OperatorActor
+}
+
+// removedFieldManagedPath is the managed fields path of an audited field
+// that is no longer set.
+func removedFieldManagedPath(path string) []string {
+	switch {
+	case strings.HasPrefix(path, "spec.auth."):
+		return []string{"f:spec", "f:auth", "f:" + strings.TrimPrefix(path, "spec.auth.")}
+	case strings.HasPrefix(path, "spec.markLogicGroups"):
+		return []string{"f:spec", "f:markLogicGroups"}
+	}
+	return []string{"f:spec", "f:" + strings.TrimPrefix(path, "spec.")}
+}
+
+func specChangeMessage(entry marklogicv1.SpecChangeEntry) string {
+	switch {
+	case entry.Old == "":
+		return fmt.Sprintf("%s set to %s by %s", entry.Field, entry.New, entry.Manager)
+	case entry.New == "":
+		return fmt.Sprintf("%s removed, it was %s, by %s", entry.Field, entry.Old, entry.Manager)
+	default:
+		return fmt.Sprintf("%s changed from %s to %s by %s", entry.Field, entry.Old, entry.New, entry.Manager)
+	}
+}
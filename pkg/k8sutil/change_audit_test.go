@@ -0,0 +1,80 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestReconcileChangeAuditRecordsWhoChangedWhat(t *testing.T) {
+	replicas := int32(3)
+	secretName := "ml-admin"
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default", Generation: 1},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			Image:           upgradeTestOldImage,
+			Auth:            &marklogicv1.AdminAuth{SecretName: &secretName},
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{{Name: "dnode", Replicas: &replicas}, {Name: "enode"}},
+		},
+	}
+	cc := newUpgradeTestContext(t, cr)
+	if res := cc.ReconcileChangeAudit(); res.Completed() {
+		t.Fatalf("expected the audit to continue, got %+v", res)
+	}
+	changeLog := cc.MarklogicCluster.Status.ChangeLog
+	if changeLog == nil || len(changeLog.Entries) != 0 || changeLog.Observed["spec.markLogicGroups[dnode].replicas"] != "3" {
+		t.Fatalf("expected the first generation to be observed only, got %+v", changeLog)
+	}
+
+	changed := cr.DeepCopy()
+	changed.Generation = 2
+	changed.ResourceVersion = ""
+	changed.Spec.Image = upgradeTestNewImage
+	more := int32(5)
+	changed.Spec.MarkLogicGroups = changed.Spec.MarkLogicGroups[:1]
+	changed.Spec.MarkLogicGroups[0].Replicas = &more
+	password := "hunter2"
+	changed.Spec.Auth.AdminPassword = &password
+	changed.Status.ChangeLog = changeLog
+	changed.ManagedFields = []metav1.ManagedFieldsEntry{
+		{Manager: "helm", APIVersion: "marklogic.progress.com/v1", Operation: metav1.ManagedFieldsOperationUpdate, Time: &metav1.Time{Time: time.Unix(100, 0)},
+			FieldsType: "FieldsV1", FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:auth":{"f:adminPassword":{},"f:secretName":{}},"f:markLogicGroups":{}}}`)}},
+		{Manager: "kubectl-edit", APIVersion: "marklogic.progress.com/v1", Operation: metav1.ManagedFieldsOperationUpdate, Time: &metav1.Time{Time: time.Unix(200, 0)},
+			FieldsType: "FieldsV1", FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:image":{}}}`)}},
+	}
+	cc = newUpgradeTestContext(t, changed)
+	recorder := record.NewFakeRecorder(10)
+	cc.Recorder = recorder
+	if res := cc.ReconcileChangeAudit(); res.Completed() {
+		t.Fatalf("expected the audit to continue, got %+v", res)
+	}
+
+	want := map[string]marklogicv1.SpecChangeEntry{
+		"spec.auth.adminPassword":              {New: auditedSecretSet, Manager: "helm"},
+		"spec.image":                           {Old: upgradeTestOldImage, New: upgradeTestNewImage, Manager: "kubectl-edit"},
+		"spec.markLogicGroups[dnode].replicas": {Old: "3", New: "5", Manager: "helm"},
+		"spec.markLogicGroups[enode].replicas": {Old: "1", Manager: "helm"},
+	}
+	entries := cc.MarklogicCluster.Status.ChangeLog.Entries
+	if len(entries) != len(want) {
+		t.Fatalf("expected %d entries, got %+v", len(want), entries)
+	}
+	for _, entry := range entries {
+		expected, ok := want[entry.Field]
+		if !ok || entry.Old != expected.Old || entry.New != expected.New || entry.Manager != expected.Manager || entry.Generation != 2 {
+			t.Fatalf("unexpected entry %+v", entry)
+		}
+	}
+	for range want {
+		event := <-recorder.Events
+		if !strings.Contains(event, changeLogReasonSpecChanged) || strings.Contains(event, password) {
+			t.Fatalf("unexpected event %q", event)
+		}
+	}
+}
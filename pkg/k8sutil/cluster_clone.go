@@ -0,0 +1,654 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-logr/logr"
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	cloneReasonClusterCreated = "CloneClusterCreated"
+	cloneReasonReady          = "CloneReady"
+	cloneReasonFailed         = "CloneFailed"
+
+	clonePollIntervalSeconds = 15
+)
+
+var volumeSnapshotGVK = schema.GroupVersionKind{Group: "snapshot.storage.k8s.io", Version: "v1", Kind: "VolumeSnapshot"}
+
+type CloneContext struct {
+	Ctx       context.Context
+	Request   *reconcile.Request
+	Client    client.Client
+	Clone     *marklogicv1.MarklogicClusterClone
+	ReqLogger logr.Logger
+	Recorder  record.EventRecorder
+}
+
+func CreateCloneContext(
+	ctx context.Context,
+	request *reconcile.Request,
+	client client.Client,
+	rec record.EventRecorder) (*CloneContext, error) {
+
+	cl := &CloneContext{
+		Ctx:       ctx,
+		Request:   request,
+		Client:    client,
+		ReqLogger: log.FromContext(ctx),
+		Recorder:  rec,
+	}
+	clone := &marklogicv1.MarklogicClusterClone{}
+	if err := client.Get(ctx, request.NamespacedName, clone); err != nil {
+		return nil, err
+	}
+	cl.Clone = clone
+	cl.ReqLogger = cl.ReqLogger.WithValues("clone", clone.Name)
+	return cl, nil
+}
+
+// ReconcileClusterClone creates the MarklogicCluster of a clone. VolumeSnapshot
+// clones snapshot the data volumes of the source cluster first and provision
+// the data volumes of the clone from the snapshots; MarkLogic renames the
+// hosts of the source cluster to those of the clone as it starts. Backup
+// clones start with empty hosts and restore the databases one at a time
+// through spec.backup.restore of the clone, after creating each database
+// and its forests with the names of the source cluster. The clone never
+// changes the source cluster.
+func (cl *CloneContext) ReconcileClusterClone() result.ReconcileResult {
+	clone := cl.Clone
+	if clone.Status.Phase == marklogicv1.ClonePhaseReady || clone.Status.Phase == marklogicv1.ClonePhaseFailed {
+		return result.Continue()
+	}
+	source := &marklogicv1.MarklogicCluster{}
+	if err := cl.Client.Get(cl.Ctx, types.NamespacedName{Name: clone.Spec.SourceCluster, Namespace: clone.Namespace}, source); err != nil {
+		if apierrors.IsNotFound(err) {
+			return cl.failClone(fmt.Sprintf("source cluster %s not found", clone.Spec.SourceCluster))
+		}
+		return result.Error(err)
+	}
+
+	if clone.Status.Phase == "" {
+		now := metav1.Now()
+		next := clone.Status.DeepCopy()
+		next.Phase = marklogicv1.ClonePhaseProvisioning
+		if clone.Spec.Method == marklogicv1.CloneMethodVolumeSnapshot {
+			if source.Spec.TieredStorage != nil {
+				return cl.failClone("VolumeSnapshot clones do not support the storage tiers of the source cluster")
+			}
+			next.Phase = marklogicv1.ClonePhaseSnapshotting
+		}
+		next.ClusterName = cloneClusterName(clone)
+		next.StartTime = &now
+		next.Message = fmt.Sprintf("cloning cluster %s", source.Name)
+		if err := cl.setCloneStatus(next); err != nil {
+			return result.Error(err)
+		}
+	}
+
+	switch clone.Status.Phase {
+	case marklogicv1.ClonePhaseSnapshotting:
+		return cl.snapshotSource(source)
+	case marklogicv1.ClonePhaseProvisioning:
+		return cl.provisionClone(source)
+	case marklogicv1.ClonePhaseRestoring:
+		return cl.restoreDatabases(source)
+	}
+	return result.Continue()
+}
+
+// snapshotSource takes a VolumeSnapshot of the data volume of every pod of
+// the source cluster and waits until they are ready to use.
+func (cl *CloneContext) snapshotSource(source *marklogicv1.MarklogicCluster) result.ReconcileResult {
+	clone := cl.Clone
+	names := []string{}
+	ready := 0
+	for _, volume := range sourceDataVolumes(source) {
+		name := cloneSnapshotName(clone, volume)
+		names = append(names, name)
+		snapshot := &unstructured.Unstructured{}
+		snapshot.SetGroupVersionKind(volumeSnapshotGVK)
+		err := cl.Client.Get(cl.Ctx, types.NamespacedName{Name: name, Namespace: clone.Namespace}, snapshot)
+		if apierrors.IsNotFound(err) {
+			pvc := &corev1.PersistentVolumeClaim{}
+			if err := cl.Client.Get(cl.Ctx, types.NamespacedName{Name: volume, Namespace: clone.Namespace}, pvc); err != nil {
+				if apierrors.IsNotFound(err) {
+					return cl.failClone(fmt.Sprintf("data volume %s of the source cluster not found", volume))
+				}
+				return result.Error(err)
+			}
+			if err := cl.Client.Create(cl.Ctx, cl.generateVolumeSnapshot(name, volume)); err != nil {
+				return result.Error(err)
+			}
+			cl.ReqLogger.Info("Created VolumeSnapshot of the source cluster", "snapshot", name, "volume", volume)
+			continue
+		}
+		if err != nil {
+			return result.Error(err)
+		}
+		if message, found, _ := unstructured.NestedString(snapshot.Object, "status", "error", "message"); found && message != "" {
+			return cl.failClone(fmt.Sprintf("snapshot %s failed: %s", name, message))
+		}
+		if readyToUse, _, _ := unstructured.NestedBool(snapshot.Object, "status", "readyToUse"); readyToUse {
+			ready++
+		}
+	}
+	next := clone.Status.DeepCopy()
+	next.Snapshots = names
+	next.Message = fmt.Sprintf("%d of %d snapshots ready", ready, len(names))
+	if ready == len(names) {
+		next.Phase = marklogicv1.ClonePhaseProvisioning
+	}
+	if err := cl.setCloneStatus(next); err != nil {
+		return result.Error(err)
+	}
+	return result.RequeueSoon(clonePollIntervalSeconds)
+}
+
+// provisionClone creates the cluster of the clone and waits for its pods.
+func (cl *CloneContext) provisionClone(source *marklogicv1.MarklogicCluster) result.ReconcileResult {
+	clone := cl.Clone
+	cluster := &marklogicv1.MarklogicCluster{}
+	err := cl.Client.Get(cl.Ctx, types.NamespacedName{Name: clone.Status.ClusterName, Namespace: clone.Namespace}, cluster)
+	if apierrors.IsNotFound(err) {
+		cluster = generateClonedCluster(source, clone)
+		if clone.Spec.Method == marklogicv1.CloneMethodVolumeSnapshot {
+			if err := cl.createClonedVolumes(source, cluster); err != nil {
+				return result.Error(err)
+			}
+		}
+		if err := cl.createCloneSecrets(source, cluster); err != nil {
+			return result.Error(err)
+		}
+		if err := cl.Client.Create(cl.Ctx, cluster); err != nil {
+			return result.Error(err)
+		}
+		cl.ReqLogger.Info("Created the cluster of the clone", "cluster", cluster.Name)
+		cl.recordCloneEvent(corev1.EventTypeNormal, cloneReasonClusterCreated, fmt.Sprintf("created cluster %s from cluster %s", cluster.Name, source.Name))
+	} else if err != nil {
+		return result.Error(err)
+	} else if !metav1.IsControlledBy(cluster, clone) {
+		return cl.failClone(fmt.Sprintf("cluster %s already exists", cluster.Name))
+	}
+
+	ready, total, err := cl.clonedPodsReady(cluster)
+	if err != nil {
+		return result.Error(err)
+	}
+	next := clone.Status.DeepCopy()
+	if ready < total {
+		next.Message = fmt.Sprintf("%d of %d pods of cluster %s ready", ready, total, cluster.Name)
+		if err := cl.setCloneStatus(next); err != nil {
+			return result.Error(err)
+		}
+		return result.RequeueSoon(clonePollIntervalSeconds)
+	}
+	if clone.Spec.Method == marklogicv1.CloneMethodVolumeSnapshot {
+		return cl.finishClone()
+	}
+	next.Phase = marklogicv1.ClonePhaseRestoring
+	next.Message = fmt.Sprintf("restoring %d database(s)", len(clone.Spec.Databases))
+	if err := cl.setCloneStatus(next); err != nil {
+		return result.Error(err)
+	}
+	return result.RequeueSoon(1)
+}
+
+// restoreDatabases restores the databases of a Backup clone in order. Each
+// restore runs through spec.backup.restore of the cluster of the clone.
+func (cl *CloneContext) restoreDatabases(source *marklogicv1.MarklogicCluster) result.ReconcileResult {
+	clone := cl.Clone
+	restored := len(clone.Status.RestoredDatabases)
+	if restored >= len(clone.Spec.Databases) {
+		return cl.finishClone()
+	}
+	database := clone.Spec.Databases[restored]
+	cluster := &marklogicv1.MarklogicCluster{}
+	if err := cl.Client.Get(cl.Ctx, types.NamespacedName{Name: clone.Status.ClusterName, Namespace: clone.Namespace}, cluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			return cl.failClone(fmt.Sprintf("cluster %s was deleted", clone.Status.ClusterName))
+		}
+		return result.Error(err)
+	}
+
+	restoreID := fmt.Sprintf("clone-%d", restored)
+	if cluster.Spec.Backup == nil || cluster.Spec.Backup.Restore == nil || cluster.Spec.Backup.Restore.RestoreID != restoreID {
+		directory := ""
+		if source.Spec.Backup != nil {
+			directory = restoreDirectory(source.Spec.Backup, &marklogicv1.BackupRestore{Database: database})
+		}
+		if directory == "" {
+			return cl.failClone(fmt.Sprintf("cluster %s has no backup directory for database %s", source.Name, database))
+		}
+		if err := cl.prepareRestore(source, cluster, database); err != nil {
+			cl.ReqLogger.Error(err, "Failed to prepare the restore of the database", "database", database)
+			return cl.cloneWaiting(fmt.Sprintf("preparing the restore of database %s: %v", database, err))
+		}
+		patchBase := client.MergeFrom(cluster.DeepCopy())
+		if cluster.Spec.Backup == nil {
+			cluster.Spec.Backup = &marklogicv1.Backup{}
+		}
+		cluster.Spec.Backup.Restore = &marklogicv1.BackupRestore{
+			RestoreID: restoreID,
+			Database:  database,
+			Directory: directory,
+		}
+		if err := cl.Client.Patch(cl.Ctx, cluster, patchBase); err != nil {
+			return result.Error(err)
+		}
+		return cl.cloneWaiting(fmt.Sprintf("restoring database %s from %s", database, directory))
+	}
+
+	var status *marklogicv1.RestoreStatus
+	if cluster.Status.Backup != nil && cluster.Status.Backup.Restore != nil && cluster.Status.Backup.Restore.RestoreID == restoreID {
+		status = cluster.Status.Backup.Restore
+	}
+	if status == nil {
+		return cl.cloneWaiting(fmt.Sprintf("restoring database %s", database))
+	}
+	switch status.Phase {
+	case marklogicv1.RestorePhaseCompleted:
+		next := clone.Status.DeepCopy()
+		next.RestoredDatabases = append(next.RestoredDatabases, database)
+		next.Message = fmt.Sprintf("restored %d of %d database(s)", len(next.RestoredDatabases), len(clone.Spec.Databases))
+		if err := cl.setCloneStatus(next); err != nil {
+			return result.Error(err)
+		}
+		return result.RequeueSoon(1)
+	case marklogicv1.RestorePhaseFailed:
+		return cl.failClone(fmt.Sprintf("restore of database %s failed: %s", database, status.Message))
+	}
+	return cl.cloneWaiting(fmt.Sprintf("restoring database %s: %s", database, status.Message))
+}
+
+// prepareRestore creates the database on the cluster of the clone with the
+// properties of the source cluster, and its forests with the names of the
+// forests in the backup. Forests of hosts the clone has fewer of are spread
+// over the hosts the group of the clone has.
+func (cl *CloneContext) prepareRestore(source, cluster *marklogicv1.MarklogicCluster, database string) error {
+	sourceClient, err := cl.clusterContext(source).newBootstrapAdminClient()
+	if err != nil {
+		return err
+	}
+	cloneClient, err := cl.clusterContext(cluster).newBootstrapAdminClient()
+	if err != nil {
+		return err
+	}
+	properties, err := sourceClient.GetDatabaseProperties(cl.Ctx, database)
+	if err != nil {
+		return fmt.Errorf("failed to read database %s: %w", database, err)
+	}
+	if _, err := cloneClient.CreateDatabase(cl.Ctx, portableDatabaseProperties(properties)); err != nil {
+		return fmt.Errorf("failed to create database %s: %w", database, err)
+	}
+	forests, err := sourceClient.ListForestsStatus(cl.Ctx)
+	if err != nil {
+		return err
+	}
+	forestHosts := map[string]string{}
+	for _, forest := range forests {
+		forestHosts[forest.Name] = forest.Host
+	}
+	names, err := sourceClient.ListDatabaseForests(cl.Ctx, database)
+	if err != nil {
+		return fmt.Errorf("failed to list the forests of database %s: %w", database, err)
+	}
+	existing, err := cloneClient.ListDatabaseForests(cl.Ctx, database)
+	if err != nil {
+		return fmt.Errorf("failed to list the forests of database %s on the clone: %w", database, err)
+	}
+	created := map[string]bool{}
+	for _, name := range existing {
+		created[name] = true
+	}
+	for _, name := range names {
+		if created[name] {
+			continue
+		}
+		host, err := clonedHostName(cluster, forestHosts[name])
+		if err != nil {
+			return fmt.Errorf("forest %s of database %s: %w", name, database, err)
+		}
+		forest := mlmanage.ForestSpec{Name: name, Host: host, Database: database}
+		if _, err := cloneClient.CreateForest(cl.Ctx, forest); err != nil {
+			return fmt.Errorf("failed to create forest %s on %s: %w", name, host, err)
+		}
+	}
+	return nil
+}
+
+// portableDatabaseProperties drops the properties of a database that stay
+// with its cluster: its forests, which are created separately, and its
+// replication and backups.
+func portableDatabaseProperties(properties map[string]any) map[string]any {
+	for _, key := range []string{"forest", "foreign-replica", "foreign-master", "database-backup"} {
+		delete(properties, key)
+	}
+	return properties
+}
+
+// createCloneSecrets creates the admin Secret of the cluster of the clone.
+// Cloned data volumes keep the admin user of the source cluster, so the
+// credentials MarkLogic accepts are copied and a new password is applied by
+// the admin credential rotation of the clone. Backup clones initialize their
+// hosts with generated credentials unless keepCredentials is set.
+func (cl *CloneContext) createCloneSecrets(source, cluster *marklogicv1.MarklogicCluster) error {
+	clone := cl.Clone
+	if clone.Spec.Method != marklogicv1.CloneMethodVolumeSnapshot && !clone.Spec.KeepCredentials {
+		return nil
+	}
+	sourceContext := cl.clusterContext(source)
+	accepted, err := sourceContext.getSecret(appliedAdminSecretName(source.Name))
+	if apierrors.IsNotFound(err) || clone.Spec.Method != marklogicv1.CloneMethodVolumeSnapshot {
+		accepted, err = sourceContext.getSecret(sourceContext.adminSecretName())
+	}
+	if err != nil {
+		return err
+	}
+	data := accepted.DeepCopy().Data
+	if clone.Spec.Method == marklogicv1.CloneMethodVolumeSnapshot {
+		if err := cl.createCloneSecret(appliedAdminSecretName(cluster.Name), accepted.DeepCopy().Data); err != nil {
+			return err
+		}
+		if !clone.Spec.KeepCredentials {
+			data["password"] = []byte(generateRandomAlphaNumeric(16))
+		}
+	}
+	return cl.createCloneSecret(AdminSecretName(cluster), data)
+}
+
+func (cl *CloneContext) createCloneSecret(name string, data map[string][]byte) error {
+	objectMeta := generateObjectMeta(name, cl.Clone.Namespace, map[string]string{}, map[string]string{})
+	err := cl.Client.Create(cl.Ctx, generateSecretDef(objectMeta, marklogicClusterCloneAsOwner(cl.Clone), data))
+	if apierrors.IsAlreadyExists(err) {
+		return nil
+	}
+	return err
+}
+
+// createClonedVolumes creates the data volumes of the cluster of the clone
+// from the snapshots, under the names the StatefulSets of the clone claim.
+func (cl *CloneContext) createClonedVolumes(source, cluster *marklogicv1.MarklogicCluster) error {
+	clone := cl.Clone
+	names := clonedDataVolumes(source, cluster)
+	for i, volume := range sourceDataVolumes(source) {
+		pvc := &corev1.PersistentVolumeClaim{}
+		if err := cl.Client.Get(cl.Ctx, types.NamespacedName{Name: volume, Namespace: clone.Namespace}, pvc); err != nil {
+			return err
+		}
+		apiGroup := volumeSnapshotGVK.Group
+		cloned := &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      names[i],
+				Namespace: clone.Namespace,
+			},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				AccessModes:      pvc.Spec.AccessModes,
+				StorageClassName: pvc.Spec.StorageClassName,
+				VolumeMode:       pvc.Spec.VolumeMode,
+				Resources:        pvc.Spec.Resources,
+				DataSource: &corev1.TypedLocalObjectReference{
+					APIGroup: &apiGroup,
+					Kind:     volumeSnapshotGVK.Kind,
+					Name:     cloneSnapshotName(clone, volume),
+				},
+			},
+		}
+		AddOwnerRefToObject(cloned, marklogicClusterCloneAsOwner(clone))
+		if err := cl.Client.Create(cl.Ctx, cloned); err != nil && !apierrors.IsAlreadyExists(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// clonedPodsReady returns the ready pods of the groups of the cluster of the
+// clone and their replicas.
+func (cl *CloneContext) clonedPodsReady(cluster *marklogicv1.MarklogicCluster) (int32, int32, error) {
+	var ready, total int32
+	for _, group := range cluster.Spec.MarkLogicGroups {
+		if group == nil {
+			continue
+		}
+		replicas := cloneGroupReplicas(group)
+		total += replicas
+		sts := &appsv1.StatefulSet{}
+		err := cl.Client.Get(cl.Ctx, types.NamespacedName{Name: group.Name, Namespace: cluster.Namespace}, sts)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return 0, 0, err
+		}
+		ready += min(sts.Status.ReadyReplicas, replicas)
+	}
+	return ready, total, nil
+}
+
+func (cl *CloneContext) generateVolumeSnapshot(name, volume string) *unstructured.Unstructured {
+	spec := map[string]interface{}{
+		"source": map[string]interface{}{"persistentVolumeClaimName": volume},
+	}
+	if cl.Clone.Spec.VolumeSnapshotClassName != "" {
+		spec["volumeSnapshotClassName"] = cl.Clone.Spec.VolumeSnapshotClassName
+	}
+	snapshot := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	snapshot.SetGroupVersionKind(volumeSnapshotGVK)
+	snapshot.SetName(name)
+	snapshot.SetNamespace(cl.Clone.Namespace)
+	snapshot.SetOwnerReferences([]metav1.OwnerReference{marklogicClusterCloneAsOwner(cl.Clone)})
+	return snapshot
+}
+
+// clusterContext returns a ClusterContext for the Manage API clients of a
+// cluster the clone reads from or restores into.
+func (cl *CloneContext) clusterContext(cr *marklogicv1.MarklogicCluster) *ClusterContext {
+	return &ClusterContext{
+		Ctx:              cl.Ctx,
+		Client:           cl.Client,
+		MarklogicCluster: cr,
+		ReqLogger:        cl.ReqLogger,
+		Recorder:         cl.Recorder,
+	}
+}
+
+func (cl *CloneContext) cloneWaiting(message string) result.ReconcileResult {
+	next := cl.Clone.Status.DeepCopy()
+	next.Message = message
+	if err := cl.setCloneStatus(next); err != nil {
+		return result.Error(err)
+	}
+	return result.RequeueSoon(clonePollIntervalSeconds)
+}
+
+func (cl *CloneContext) finishClone() result.ReconcileResult {
+	now := metav1.Now()
+	next := cl.Clone.Status.DeepCopy()
+	next.Phase = marklogicv1.ClonePhaseReady
+	next.Message = fmt.Sprintf("cluster %s is ready", next.ClusterName)
+	next.CompletionTime = &now
+	if err := cl.setCloneStatus(next); err != nil {
+		return result.Error(err)
+	}
+	cl.recordCloneEvent(corev1.EventTypeNormal, cloneReasonReady, next.Message)
+	return result.Continue()
+}
+
+func (cl *CloneContext) failClone(message string) result.ReconcileResult {
+	now := metav1.Now()
+	next := cl.Clone.Status.DeepCopy()
+	next.Phase = marklogicv1.ClonePhaseFailed
+	next.Message = message
+	next.CompletionTime = &now
+	if err := cl.setCloneStatus(next); err != nil {
+		return result.Error(err)
+	}
+	cl.recordCloneEvent(corev1.EventTypeWarning, cloneReasonFailed, message)
+	return result.Continue()
+}
+
+func (cl *CloneContext) setCloneStatus(status *marklogicv1.MarklogicClusterCloneStatus) error {
+	patchBase := client.MergeFrom(cl.Clone.DeepCopy())
+	cl.Clone.Status = *status
+	return patchStatus(cl.Ctx, cl.Client, cl.Clone, patchBase)
+}
+
+func (cl *CloneContext) recordCloneEvent(eventType, reason, message string) {
+	if cl.Recorder != nil {
+		cl.Recorder.Event(cl.Clone, eventType, reason, message)
+	}
+}
+
+// generateClonedCluster returns the cluster of the clone: the spec of the
+// source cluster with the groups renamed to <clusterName>-<group>. The
+// clone gets its own admin Secret and takes no scheduled backups, which
+// would write into the backup directories of the source cluster.
+func generateClonedCluster(source *marklogicv1.MarklogicCluster, clone *marklogicv1.MarklogicClusterClone) *marklogicv1.MarklogicCluster {
+	name := cloneClusterName(clone)
+	spec := source.Spec.DeepCopy()
+	spec.Auth = nil
+	spec.FullnameOverride = ""
+	spec.Stopped = false
+	spec.Hibernation = nil
+	spec.HostRenames = nil
+	if spec.Backup != nil {
+		spec.Backup.Schedules = nil
+		spec.Backup.Restore = nil
+	}
+	replicas := map[string]int32{}
+	for _, group := range clone.Spec.Groups {
+		replicas[group.Name] = group.Replicas
+	}
+	for _, group := range spec.MarkLogicGroups {
+		if group == nil {
+			continue
+		}
+		groupName := cloneGroupName(name, group.Name)
+		if clone.Spec.Method == marklogicv1.CloneMethodVolumeSnapshot {
+			spec.HostRenames = append(spec.HostRenames, marklogicv1.HostRename{From: group.Name, To: groupName})
+		} else {
+			// The forests of the restored databases take the names in the backups.
+			group.Forests = nil
+		}
+		if r, ok := replicas[group.Name]; ok {
+			group.Replicas = &r
+		}
+		group.Name = groupName
+	}
+	cluster := &marklogicv1.MarklogicCluster{
+		TypeMeta: generateTypeMeta("MarklogicCluster", marklogicv1.GroupVersion.String()),
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: clone.Namespace,
+		},
+		Spec: *spec,
+	}
+	AddOwnerRefToObject(cluster, marklogicClusterCloneAsOwner(clone))
+	return cluster
+}
+
+// sourceDataVolumes returns the data volumes of the pods of the source
+// cluster, group by group.
+func sourceDataVolumes(source *marklogicv1.MarklogicCluster) []string {
+	volumes := []string{}
+	for _, group := range source.Spec.MarkLogicGroups {
+		if group == nil {
+			continue
+		}
+		for ordinal := int32(0); ordinal < cloneGroupReplicas(group); ordinal++ {
+			volumes = append(volumes, fmt.Sprintf("datadir-%s-%d", group.Name, ordinal))
+		}
+	}
+	return volumes
+}
+
+// clonedDataVolumes returns the data volumes of the pods of the cluster of
+// the clone in the order of sourceDataVolumes.
+func clonedDataVolumes(source, cluster *marklogicv1.MarklogicCluster) []string {
+	volumes := []string{}
+	for _, group := range source.Spec.MarkLogicGroups {
+		if group == nil {
+			continue
+		}
+		for ordinal := int32(0); ordinal < cloneGroupReplicas(group); ordinal++ {
+			volumes = append(volumes, fmt.Sprintf("datadir-%s-%d", cloneGroupName(cluster.Name, group.Name), ordinal))
+		}
+	}
+	return volumes
+}
+
+// clonedHostName returns the host of the cluster of the clone that takes
+// the forests of a host of the source cluster.
+func clonedHostName(cluster *marklogicv1.MarklogicCluster, host string) (string, error) {
+	pod, service, ok := strings.Cut(host, ".")
+	if !ok {
+		return "", fmt.Errorf("unexpected host %q", host)
+	}
+	groupName, _, _ := strings.Cut(service, ".")
+	ordinal, err := strconv.Atoi(strings.TrimPrefix(pod, groupName+"-"))
+	if err != nil {
+		return "", fmt.Errorf("unexpected host %q", host)
+	}
+	name := cloneGroupName(cluster.Name, groupName)
+	for _, group := range cluster.Spec.MarkLogicGroups {
+		if group != nil && group.Name == name {
+			ordinal %= int(cloneGroupReplicas(group))
+			return fmt.Sprintf("%s-%d.%s.%s.svc.%s", name, ordinal, name, cluster.Namespace, cluster.Spec.ClusterDomain), nil
+		}
+	}
+	return "", fmt.Errorf("host %s is not in a group of cluster %s", host, cluster.Name)
+}
+
+func cloneGroupReplicas(group *marklogicv1.MarklogicGroups) int32 {
+	if group.Replicas == nil {
+		return 1
+	}
+	return *group.Replicas
+}
+
+func cloneClusterName(clone *marklogicv1.MarklogicClusterClone) string {
+	if clone.Spec.ClusterName != "" {
+		return clone.Spec.ClusterName
+	}
+	return clone.Name
+}
+
+func cloneGroupName(clusterName, groupName string) string {
+	return clusterName + "-" + groupName
+}
+
+// cloneSnapshotName returns the VolumeSnapshot of a data volume of the source
+// cluster, e.g. <clone>-dnode-0 for datadir-dnode-0.
+func cloneSnapshotName(clone *marklogicv1.MarklogicClusterClone, volume string) string {
+	return clone.Name + "-" + strings.TrimPrefix(volume, "datadir-")
+}
+
+func marklogicClusterCloneAsOwner(clone *marklogicv1.MarklogicClusterClone) metav1.OwnerReference {
+	trueVar := true
+	return metav1.OwnerReference{
+		APIVersion: marklogicv1.GroupVersion.String(),
+		Kind:       "MarklogicClusterClone",
+		Name:       clone.Name,
+		UID:        clone.UID,
+		Controller: &trueVar,
+	}
+}
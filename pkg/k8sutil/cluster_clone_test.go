@@ -0,0 +1,250 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func newCloneTestContext(t *testing.T, clone *marklogicv1.MarklogicClusterClone, objects ...client.Object) *CloneContext {
+	t.Helper()
+	fakeClient, _ := newFakeClient(t, append(objects, clone)...)
+	return &CloneContext{Ctx: context.Background(), Client: fakeClient, Clone: clone, ReqLogger: logr.Discard()}
+}
+
+func newCloneTestSource() *marklogicv1.MarklogicCluster {
+	replicas := int32(3)
+	adminSecretName := "ml-credentials"
+	return &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			Image:         upgradeTestOldImage,
+			ClusterDomain: "cluster.local",
+			Auth:          &marklogicv1.AdminAuth{SecretName: &adminSecretName},
+			Backup: &marklogicv1.Backup{
+				Enabled:   true,
+				Storage:   &marklogicv1.BackupStorage{Provider: marklogicv1.BackupStorageProvider("s3"), Bucket: "backups"},
+				Schedules: []marklogicv1.BackupSchedule{{Database: "Orders", Directory: "/backups/orders/"}},
+			},
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{{
+				Name:        "dnode",
+				Replicas:    &replicas,
+				IsBootstrap: true,
+				Forests:     &marklogicv1.ForestProvisioning{Databases: []string{"Orders"}},
+			}},
+		},
+	}
+}
+
+func TestReconcileBackupCloneRestoresTheDatabases(t *testing.T) {
+	source := newCloneTestSource()
+	clone := &marklogicv1.MarklogicClusterClone{
+		ObjectMeta: metav1.ObjectMeta{Name: "staging", Namespace: "default"},
+		Spec: marklogicv1.MarklogicClusterCloneSpec{
+			SourceCluster: "ml",
+			Method:        marklogicv1.CloneMethodBackup,
+			Databases:     []string{"Orders"},
+			Groups:        []marklogicv1.CloneGroup{{Name: "dnode", Replicas: 1}},
+		},
+	}
+	adminSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml-credentials", Namespace: "default"},
+		Data:       map[string][]byte{"username": []byte("admin"), "password": []byte("production")},
+	}
+	cl := newCloneTestContext(t, clone, source, adminSecret)
+
+	var clonedDatabase map[string]any
+	clonedForests := []mlmanage.ForestSpec{}
+	original := NewDynamicManagementClient
+	NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+		if strings.HasPrefix(opts.Host, "staging-dnode-0.") {
+			return &stubDynamicManagementClient{
+				createDatabaseFn: func(properties map[string]any) (bool, error) {
+					clonedDatabase = properties
+					return true, nil
+				},
+				listForestsFn: func(database string) ([]string, error) { return nil, nil },
+				createForestFn: func(forest mlmanage.ForestSpec) (bool, error) {
+					clonedForests = append(clonedForests, forest)
+					return true, nil
+				},
+			}
+		}
+		return &stubDynamicManagementClient{
+			dbPropertiesFn: func(database string) (map[string]any, error) {
+				return map[string]any{"database-name": database, "forest": []any{"Orders-dnode-2-1"}, "database-backup": map[string]any{}}, nil
+			},
+			forestsStatusFn: func() ([]mlmanage.ForestStatus, error) {
+				return []mlmanage.ForestStatus{{Name: "Orders-dnode-2-1", Host: "dnode-2.dnode.default.svc.cluster.local"}}, nil
+			},
+			listForestsFn: func(database string) ([]string, error) { return []string{"Orders-dnode-2-1"}, nil },
+		}
+	}
+	t.Cleanup(func() { NewDynamicManagementClient = original })
+
+	cl.ReconcileClusterClone()
+	cluster := &marklogicv1.MarklogicCluster{}
+	if err := cl.Client.Get(cl.Ctx, types.NamespacedName{Name: "staging", Namespace: "default"}, cluster); err != nil {
+		t.Fatalf("expected the cluster of the clone to be created: %v", err)
+	}
+	group := cluster.Spec.MarkLogicGroups[0]
+	if group.Name != "staging-dnode" || *group.Replicas != 1 || group.Forests != nil {
+		t.Fatalf("expected a renamed group with reduced replicas, got %+v", group)
+	}
+	if cluster.Spec.Auth != nil || len(cluster.Spec.Backup.Schedules) != 0 || cluster.Spec.Backup.Storage == nil {
+		t.Fatalf("expected masked credentials and no scheduled backups, got %+v", cluster.Spec)
+	}
+	if !metav1.IsControlledBy(cluster, clone) {
+		t.Fatalf("expected the clone to own its cluster")
+	}
+	err := cl.Client.Get(cl.Ctx, types.NamespacedName{Name: "staging-admin", Namespace: "default"}, &corev1.Secret{})
+	if !apierrors.IsNotFound(err) {
+		t.Fatalf("expected the cluster to generate its own admin credentials, got %v", err)
+	}
+	if phase := clone.Status.Phase; phase != marklogicv1.ClonePhaseProvisioning {
+		t.Fatalf("expected to wait for the pods of the clone, got %s", phase)
+	}
+
+	if err := cl.Client.Create(cl.Ctx, newUpgradeTestStatefulSet("staging-dnode", upgradeTestOldImage, 1)); err != nil {
+		t.Fatalf("failed to create statefulset: %v", err)
+	}
+	generated := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "staging-admin", Namespace: "default"},
+		Data:       map[string][]byte{"username": []byte("admin"), "password": []byte("generated")},
+	}
+	if err := cl.Client.Create(cl.Ctx, generated); err != nil {
+		t.Fatalf("failed to create secret: %v", err)
+	}
+	cl.ReconcileClusterClone()
+	cl.ReconcileClusterClone()
+	if phase := clone.Status.Phase; phase != marklogicv1.ClonePhaseRestoring {
+		t.Fatalf("expected the databases to be restored, got %s: %s", phase, clone.Status.Message)
+	}
+	if _, ok := clonedDatabase["forest"]; ok || clonedDatabase["database-name"] != "Orders" {
+		t.Fatalf("expected the database to be created without its forests, got %v: %s", clonedDatabase, clone.Status.Message)
+	}
+	if len(clonedForests) != 1 || clonedForests[0].Name != "Orders-dnode-2-1" || clonedForests[0].Host != "staging-dnode-0.staging-dnode.default.svc.cluster.local" {
+		t.Fatalf("expected the forest of the backup on the remaining host, got %+v", clonedForests)
+	}
+	if err := cl.Client.Get(cl.Ctx, types.NamespacedName{Name: "staging", Namespace: "default"}, cluster); err != nil {
+		t.Fatalf("failed to get cluster: %v", err)
+	}
+	restore := cluster.Spec.Backup.Restore
+	if restore == nil || restore.Database != "Orders" || restore.Directory != "/backups/orders/" {
+		t.Fatalf("expected the restore from the directory of the schedule, got %+v", restore)
+	}
+
+	cluster.Status.Backup = &marklogicv1.BackupStatus{Restore: &marklogicv1.RestoreStatus{
+		RestoreID: restore.RestoreID,
+		Phase:     marklogicv1.RestorePhaseCompleted,
+	}}
+	if err := cl.Client.Status().Update(cl.Ctx, cluster); err != nil {
+		t.Fatalf("failed to update cluster status: %v", err)
+	}
+	cl.ReconcileClusterClone()
+	cl.ReconcileClusterClone()
+	if clone.Status.Phase != marklogicv1.ClonePhaseReady || len(clone.Status.RestoredDatabases) != 1 {
+		t.Fatalf("expected the clone to be ready, got %+v", clone.Status)
+	}
+}
+
+func TestReconcileVolumeSnapshotCloneProvisionsTheDataVolumes(t *testing.T) {
+	source := newCloneTestSource()
+	one := int32(1)
+	source.Spec.MarkLogicGroups[0].Replicas = &one
+	clone := &marklogicv1.MarklogicClusterClone{
+		ObjectMeta: metav1.ObjectMeta{Name: "staging", Namespace: "default"},
+		Spec: marklogicv1.MarklogicClusterCloneSpec{
+			SourceCluster:           "ml",
+			Method:                  marklogicv1.CloneMethodVolumeSnapshot,
+			VolumeSnapshotClassName: "csi-snapclass",
+		},
+	}
+	storageClass := "gp3"
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "datadir-dnode-0", Namespace: "default"},
+		Spec:       corev1.PersistentVolumeClaimSpec{StorageClassName: &storageClass},
+	}
+	applied := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml-admin-applied", Namespace: "default"},
+		Data:       map[string][]byte{"username": []byte("admin"), "password": []byte("production")},
+	}
+	cl := newCloneTestContext(t, clone, source, pvc, applied)
+
+	cl.ReconcileClusterClone()
+	snapshot := &unstructured.Unstructured{}
+	snapshot.SetGroupVersionKind(volumeSnapshotGVK)
+	if err := cl.Client.Get(cl.Ctx, types.NamespacedName{Name: "staging-dnode-0", Namespace: "default"}, snapshot); err != nil {
+		t.Fatalf("expected a snapshot of the data volume: %v", err)
+	}
+	if claim, _, _ := unstructured.NestedString(snapshot.Object, "spec", "source", "persistentVolumeClaimName"); claim != "datadir-dnode-0" {
+		t.Fatalf("expected the snapshot of datadir-dnode-0, got %s", claim)
+	}
+	if phase := clone.Status.Phase; phase != marklogicv1.ClonePhaseSnapshotting {
+		t.Fatalf("expected to wait for the snapshots, got %s", phase)
+	}
+
+	if err := unstructured.SetNestedField(snapshot.Object, true, "status", "readyToUse"); err != nil {
+		t.Fatalf("failed to set snapshot status: %v", err)
+	}
+	if err := cl.Client.Update(cl.Ctx, snapshot); err != nil {
+		t.Fatalf("failed to update snapshot: %v", err)
+	}
+	cl.ReconcileClusterClone()
+	cl.ReconcileClusterClone()
+	cloned := &corev1.PersistentVolumeClaim{}
+	if err := cl.Client.Get(cl.Ctx, types.NamespacedName{Name: "datadir-staging-dnode-0", Namespace: "default"}, cloned); err != nil {
+		t.Fatalf("expected the data volume of the clone: %v", err)
+	}
+	if cloned.Spec.DataSource == nil || cloned.Spec.DataSource.Name != "staging-dnode-0" || *cloned.Spec.StorageClassName != "gp3" {
+		t.Fatalf("expected the data volume to be restored from the snapshot, got %+v", cloned.Spec)
+	}
+	cluster := &marklogicv1.MarklogicCluster{}
+	if err := cl.Client.Get(cl.Ctx, types.NamespacedName{Name: "staging", Namespace: "default"}, cluster); err != nil {
+		t.Fatalf("expected the cluster of the clone to be created: %v", err)
+	}
+	if renames := cluster.Spec.HostRenames; len(renames) != 1 || renames[0] != (marklogicv1.HostRename{From: "dnode", To: "staging-dnode"}) {
+		t.Fatalf("expected the hosts to be renamed, got %+v", renames)
+	}
+	accepted := &corev1.Secret{}
+	if err := cl.Client.Get(cl.Ctx, types.NamespacedName{Name: "staging-admin-applied", Namespace: "default"}, accepted); err != nil {
+		t.Fatalf("expected the credentials of the cloned volumes: %v", err)
+	}
+	admin := &corev1.Secret{}
+	if err := cl.Client.Get(cl.Ctx, types.NamespacedName{Name: "staging-admin", Namespace: "default"}, admin); err != nil {
+		t.Fatalf("expected the admin credentials of the clone: %v", err)
+	}
+	if string(accepted.Data["password"]) != "production" || string(admin.Data["username"]) != "admin" || string(admin.Data["password"]) == "production" {
+		t.Fatalf("expected a new admin password to be rotated in, got %v", admin.Data)
+	}
+}
+
+func TestClonedHostNameSpreadsForestsOverTheRemainingHosts(t *testing.T) {
+	replicas := int32(2)
+	cluster := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "staging", Namespace: "qa"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			ClusterDomain:   "cluster.local",
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{{Name: "staging-dnode", Replicas: &replicas}},
+		},
+	}
+	host, err := clonedHostName(cluster, "dnode-5.dnode.default.svc.cluster.local")
+	if err != nil || host != "staging-dnode-1.staging-dnode.qa.svc.cluster.local" {
+		t.Fatalf("unexpected host %s: %v", host, err)
+	}
+	if _, err := clonedHostName(cluster, "enode-0.enode.default.svc.cluster.local"); err == nil {
+		t.Fatalf("expected a host of another group to be rejected")
+	}
+}
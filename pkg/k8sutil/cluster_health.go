@@ -0,0 +1,204 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"fmt"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// The health of a cluster, named after the health statuses of Argo CD.
+const (
+	clusterHealthy     = "Healthy"
+	clusterProgressing = "Progressing"
+	clusterDegraded    = "Degraded"
+	clusterSuspended   = "Suspended"
+
+	healthReasonReady                     = "Ready"
+	healthReasonReconcileError            = "ReconcileError"
+	healthReasonGroupsNotReady            = "GroupsNotReady"
+	healthReasonPreflightFailed           = "PreflightFailed"
+	healthReasonUpgradeInProgress         = "UpgradeInProgress"
+	healthReasonUpgradeFailed             = "UpgradeFailed"
+	healthReasonResourceRolloutInProgress = "ResourceRolloutInProgress"
+	healthReasonResourceRolloutFailed     = "ResourceRolloutFailed"
+	healthReasonHostRestartInProgress     = "HostRestartInProgress"
+	healthReasonStopping                  = "Stopping"
+	healthReasonStopped                   = "Stopped"
+	healthReasonQuorumLost                = "QuorumLost"
+	healthReasonScaleUpBlocked            = "ScaleUpBlocked"
+)
+
+// clusterHealth is the health of a cluster with the reason for it and the
+// phase it puts the cluster in.
+type clusterHealth struct {
+	status  string
+	reason  string
+	message string
+	phase   marklogicv1.ClusterPhase
+}
+
+// updateClusterHealth sets the Ready, Reconciling and Stalled conditions,
+// status.phase and status.observedGeneration after every reconcile, including the ones that
+// return early, so GitOps tools tell a cluster that is done from one that is
+// still rolling out, waits for an approval or needs intervention. A failure
+// to update them is logged and does not change the result of the reconcile.
+func (cc *ClusterContext) updateClusterHealth(res reconcile.Result, reconcileErr error) (reconcile.Result, error) {
+	health, err := cc.assessClusterHealth(reconcileErr)
+	if err != nil {
+		cc.ReqLogger.Error(err, "Failed to assess the health of the cluster")
+		return res, reconcileErr
+	}
+	cr := cc.MarklogicCluster
+	conditions := healthConditions(health, cr.Generation)
+	changed := cr.Status.ObservedGeneration != cr.Generation || cr.Status.Phase != health.phase
+	for _, condition := range conditions {
+		changed = changed || !conditionUnchanged(cr.Status.Conditions, condition)
+	}
+	if !changed {
+		return res, reconcileErr
+	}
+	patchBase := client.MergeFrom(cr.DeepCopy())
+	for _, condition := range conditions {
+		for _, existing := range cr.Status.Conditions {
+			if existing.Type == condition.Type && existing.Status == condition.Status {
+				condition.LastTransitionTime = existing.LastTransitionTime
+			}
+		}
+		cr.Status.SetCondition(condition)
+	}
+	cr.Status.Phase = health.phase
+	cr.Status.ObservedGeneration = cr.Generation
+	if err := patchStatus(cc.Ctx, cc.Client, cr, patchBase); err != nil {
+		cc.ReqLogger.Error(err, "Failed to update the health conditions of the cluster")
+	}
+	return res, reconcileErr
+}
+
+// assessClusterHealth tells the health of the cluster: Degraded when it
+// needs intervention, Suspended while it is stopped or an upgrade waits for
+// its approval or is paused, Progressing while a workflow or pods are still
+// on their way and Healthy otherwise. A cluster being deleted keeps its
+// health but is in the Terminating phase.
+func (cc *ClusterContext) assessClusterHealth(reconcileErr error) (clusterHealth, error) {
+	health, err := cc.assessClusterConditions(reconcileErr)
+	if err == nil && cc.MarklogicCluster.DeletionTimestamp != nil {
+		health.phase = marklogicv1.ClusterPhaseTerminating
+	}
+	return health, err
+}
+
+func (cc *ClusterContext) assessClusterConditions(reconcileErr error) (clusterHealth, error) {
+	cr := cc.MarklogicCluster
+	upgrade := cr.Status.Upgrade
+	rollout := cr.Status.ResourceRollout
+	for _, condition := range cr.Status.Conditions {
+		if condition.Type == string(marklogicv1.PreflightFailed) && condition.Status == metav1.ConditionTrue {
+			return clusterHealth{clusterDegraded, healthReasonPreflightFailed, condition.Message, marklogicv1.ClusterPhaseDegraded}, nil
+		}
+		if condition.Type == string(marklogicv1.QuorumLost) && condition.Status == metav1.ConditionTrue {
+			return clusterHealth{clusterDegraded, healthReasonQuorumLost, condition.Message, marklogicv1.ClusterPhaseDegraded}, nil
+		}
+	}
+	switch {
+	case upgrade != nil && upgrade.State == marklogicv1.UpgradeStateFailed:
+		return clusterHealth{clusterDegraded, healthReasonUpgradeFailed, upgrade.Message, marklogicv1.ClusterPhaseDegraded}, nil
+	case rollout != nil && rollout.State == marklogicv1.ResourceRolloutFailed:
+		return clusterHealth{clusterDegraded, healthReasonResourceRolloutFailed, rollout.Message, marklogicv1.ClusterPhaseDegraded}, nil
+	case clusterStopped(cr):
+		if run := cr.Status.Run; run != nil && (run.State == marklogicv1.ClusterRunStateStopped || run.State == marklogicv1.ClusterRunStateHibernated) {
+			return clusterHealth{clusterSuspended, healthReasonStopped, run.Message, marklogicv1.ClusterPhaseStopped}, nil
+		}
+		return clusterHealth{clusterProgressing, healthReasonStopping, "stopping the cluster", marklogicv1.ClusterPhaseStopped}, nil
+	case upgrade != nil && upgrade.State == marklogicv1.UpgradeStateWaitingForUserApproval:
+		return clusterHealth{clusterSuspended, upgradeReasonWaitingForApproval, upgrade.Message, marklogicv1.ClusterPhaseUpgrading}, nil
+	case upgrade != nil && upgrade.Paused:
+		return clusterHealth{clusterSuspended, upgradeReasonPaused, upgrade.Message, marklogicv1.ClusterPhaseUpgrading}, nil
+	case reconcileErr != nil:
+		// An error does not tell where the cluster is, it stays in its phase.
+		phase := cr.Status.Phase
+		if phase == "" || phase == marklogicv1.ClusterPhaseTerminating {
+			phase = marklogicv1.ClusterPhaseProvisioning
+		}
+		return clusterHealth{clusterProgressing, healthReasonReconcileError, reconcileErr.Error(), phase}, nil
+	case upgrade.Active():
+		return clusterHealth{clusterProgressing, healthReasonUpgradeInProgress, upgrade.Message, marklogicv1.ClusterPhaseUpgrading}, nil
+	case rollout != nil && rollout.State == marklogicv1.ResourceRolloutInProgress:
+		return clusterHealth{clusterProgressing, healthReasonResourceRolloutInProgress, rollout.Message, marklogicv1.ClusterPhaseUpgrading}, nil
+	case cr.Status.HostRestart != nil && cr.Status.HostRestart.State == marklogicv1.HostRestartInProgress:
+		return clusterHealth{clusterProgressing, healthReasonHostRestartInProgress, cr.Status.HostRestart.Message, marklogicv1.ClusterPhaseUpgrading}, nil
+	}
+	blocked, err := cc.scaleUpBlockedGroups()
+	if err != nil {
+		return clusterHealth{}, err
+	}
+	if blocked != "" {
+		return clusterHealth{clusterDegraded, healthReasonScaleUpBlocked, blocked, marklogicv1.ClusterPhaseDegraded}, nil
+	}
+	waiting, err := cc.groupsNotReady(false)
+	if err != nil {
+		return clusterHealth{}, err
+	}
+	if waiting == "" {
+		return clusterHealth{clusterHealthy, healthReasonReady, "all groups are ready", marklogicv1.ClusterPhaseReady}, nil
+	}
+	// Hosts join the bootstrap host, so a new cluster is provisioned once
+	// its bootstrap group is ready. Later, hosts that start rejoin it.
+	phase := marklogicv1.ClusterPhaseJoining
+	if cr.Status.Phase == "" || cr.Status.Phase == marklogicv1.ClusterPhaseProvisioning {
+		bootstrapWaiting, err := cc.groupsNotReady(true)
+		if err != nil {
+			return clusterHealth{}, err
+		}
+		if bootstrapWaiting != "" {
+			phase = marklogicv1.ClusterPhaseProvisioning
+		}
+	}
+	return clusterHealth{clusterProgressing, healthReasonGroupsNotReady, fmt.Sprintf("waiting for the pods to be ready: %s", waiting), phase}, nil
+}
+
+// healthConditions returns the Ready, Reconciling and Stalled conditions of
+// a health. The three conditions share its reason and message.
+func healthConditions(health clusterHealth, generation int64) []metav1.Condition {
+	status := func(set bool) metav1.ConditionStatus {
+		if set {
+			return metav1.ConditionTrue
+		}
+		return metav1.ConditionFalse
+	}
+	conditions := []metav1.Condition{}
+	for _, condition := range []struct {
+		conditionType marklogicv1.MarkLogicConditionType
+		set           bool
+	}{
+		{marklogicv1.ClusterReady, health.status == clusterHealthy},
+		{marklogicv1.ClusterReconciling, health.status == clusterProgressing},
+		{marklogicv1.ClusterStalled, health.status == clusterDegraded},
+	} {
+		conditions = append(conditions, metav1.Condition{
+			Type:               string(condition.conditionType),
+			Status:             status(condition.set),
+			Reason:             health.reason,
+			Message:            health.message,
+			ObservedGeneration: generation,
+			LastTransitionTime: metav1.Now(),
+		})
+	}
+	return conditions
+}
+
+// conditionUnchanged reports whether conditions hold condition, apart from
+// its transition time.
+func conditionUnchanged(conditions []metav1.Condition, condition metav1.Condition) bool {
+	for _, existing := range conditions {
+		if existing.Type == condition.Type {
+			return existing.Status == condition.Status && existing.Reason == condition.Reason &&
+				existing.Message == condition.Message && existing.ObservedGeneration == condition.ObservedGeneration
+		}
+	}
+	return false
+}
@@ -0,0 +1,139 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestUpdateClusterHealth(t *testing.T) {
+	replicas := int32(1)
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default", Generation: 3},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			Image:           upgradeTestOldImage,
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{{Name: "dnode", Replicas: &replicas, IsBootstrap: true}},
+		},
+	}
+	sts := newUpgradeTestStatefulSet("dnode", upgradeTestOldImage, 0)
+	sts.Spec.Replicas = &replicas
+	cc := newUpgradeTestContext(t, cr, sts)
+	expect := func(ready, reconciling, stalled metav1.ConditionStatus, reason string) {
+		t.Helper()
+		for conditionType, want := range map[marklogicv1.MarkLogicConditionType]metav1.ConditionStatus{
+			marklogicv1.ClusterReady: ready, marklogicv1.ClusterReconciling: reconciling, marklogicv1.ClusterStalled: stalled,
+		} {
+			if got := cr.Status.GetConditionStatus(string(conditionType)); got != want {
+				t.Fatalf("expected %s to be %s, got %s in %+v", conditionType, want, got, cr.Status.Conditions)
+			}
+		}
+		for _, condition := range cr.Status.Conditions {
+			if condition.Type == string(marklogicv1.ClusterReady) && condition.Reason != reason {
+				t.Fatalf("expected reason %s, got %+v", reason, condition)
+			}
+		}
+		if cr.Status.ObservedGeneration != cr.Generation {
+			t.Fatalf("expected generation %d to be observed, got %d", cr.Generation, cr.Status.ObservedGeneration)
+		}
+	}
+
+	res, err := cc.updateClusterHealth(reconcile.Result{RequeueAfter: 5}, nil)
+	if err != nil || res.RequeueAfter != 5 {
+		t.Fatalf("expected the result of the reconcile to be kept, got %+v %v", res, err)
+	}
+	expect(metav1.ConditionFalse, metav1.ConditionTrue, metav1.ConditionFalse, healthReasonGroupsNotReady)
+
+	sts.Status.ReadyReplicas = 1
+	if err := cc.Client.Status().Update(cc.Ctx, sts); err != nil {
+		t.Fatalf("failed to update StatefulSet: %v", err)
+	}
+	cc.updateClusterHealth(reconcile.Result{}, nil)
+	expect(metav1.ConditionTrue, metav1.ConditionFalse, metav1.ConditionFalse, healthReasonReady)
+
+	if _, err := cc.updateClusterHealth(reconcile.Result{}, errors.New("boom")); err == nil || err.Error() != "boom" {
+		t.Fatalf("expected the reconcile error to be returned, got %v", err)
+	}
+	expect(metav1.ConditionFalse, metav1.ConditionTrue, metav1.ConditionFalse, healthReasonReconcileError)
+
+	cr.Status.Upgrade = &marklogicv1.UpgradeStatus{State: marklogicv1.UpgradeStateWaitingForUserApproval, Message: "waiting for approval"}
+	if err := cc.Client.Status().Update(cc.Ctx, cr); err != nil {
+		t.Fatalf("failed to update cluster: %v", err)
+	}
+	cc.updateClusterHealth(reconcile.Result{}, nil)
+	expect(metav1.ConditionFalse, metav1.ConditionFalse, metav1.ConditionFalse, upgradeReasonWaitingForApproval)
+
+	cr.Status.Upgrade.State = marklogicv1.UpgradeStateFailed
+	if err := cc.Client.Status().Update(cc.Ctx, cr); err != nil {
+		t.Fatalf("failed to update cluster: %v", err)
+	}
+	cc.updateClusterHealth(reconcile.Result{}, nil)
+	expect(metav1.ConditionFalse, metav1.ConditionFalse, metav1.ConditionTrue, healthReasonUpgradeFailed)
+}
+
+func TestUpdateClusterPhase(t *testing.T) {
+	one, two := int32(1), int32(2)
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			Image: upgradeTestOldImage,
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{
+				{Name: "dnode", Replicas: &one, IsBootstrap: true},
+				{Name: "enode", Replicas: &two},
+			},
+		},
+	}
+	dnode := newUpgradeTestStatefulSet("dnode", upgradeTestOldImage, 0)
+	dnode.Spec.Replicas = &one
+	enode := newUpgradeTestStatefulSet("enode", upgradeTestOldImage, 0)
+	enode.Spec.Replicas = &two
+	cc := newUpgradeTestContext(t, cr, dnode, enode)
+	setReady := func(sts *appsv1.StatefulSet, ready int32) {
+		t.Helper()
+		sts.Status.ReadyReplicas = ready
+		if err := cc.Client.Status().Update(cc.Ctx, sts); err != nil {
+			t.Fatalf("failed to update StatefulSet: %v", err)
+		}
+	}
+	expect := func(reconcileErr error, want marklogicv1.ClusterPhase) {
+		t.Helper()
+		cc.updateClusterHealth(reconcile.Result{}, reconcileErr)
+		if cr.Status.Phase != want {
+			t.Fatalf("expected phase %s, got %s", want, cr.Status.Phase)
+		}
+	}
+
+	expect(nil, marklogicv1.ClusterPhaseProvisioning)
+	expect(errors.New("boom"), marklogicv1.ClusterPhaseProvisioning)
+	setReady(dnode, 1)
+	expect(nil, marklogicv1.ClusterPhaseJoining)
+	setReady(enode, 2)
+	expect(nil, marklogicv1.ClusterPhaseReady)
+	expect(errors.New("boom"), marklogicv1.ClusterPhaseReady)
+
+	// A bootstrap host that restarts rejoins a provisioned cluster.
+	setReady(dnode, 0)
+	expect(nil, marklogicv1.ClusterPhaseJoining)
+	setReady(dnode, 1)
+
+	cr.Status.Upgrade = &marklogicv1.UpgradeStatus{State: marklogicv1.UpgradeStateInProgress}
+	if err := cc.Client.Status().Update(cc.Ctx, cr); err != nil {
+		t.Fatalf("failed to update cluster: %v", err)
+	}
+	expect(nil, marklogicv1.ClusterPhaseUpgrading)
+
+	cr.Status.Upgrade.State = marklogicv1.UpgradeStateFailed
+	if err := cc.Client.Status().Update(cc.Ctx, cr); err != nil {
+		t.Fatalf("failed to update cluster: %v", err)
+	}
+	expect(nil, marklogicv1.ClusterPhaseDegraded)
+
+	cr.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	expect(nil, marklogicv1.ClusterPhaseTerminating)
+}
@@ -0,0 +1,261 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"fmt"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	clusterReasonStopping = "ClusterStopping"
+	clusterReasonStopped  = "ClusterStopped"
+	clusterReasonStarting = "ClusterStarting"
+	clusterReasonStarted  = "ClusterStarted"
+
+	clusterRunRequeueSeconds = 10
+)
+
+// ReconcileClusterRun stops the cluster when spec.stopped is set or its
+// hibernation schedule says so, and starts it again afterwards. Stopping scales HAProxy to 0, then every group but
+// the bootstrap group and the bootstrap group last; starting runs the other
+// way round and waits for the bootstrap group to be ready before the other
+// groups start. The group replicas follow status.run, see runReplicas. While
+// the cluster is not running the remaining steps of the reconcile, which need
+// the Manage API, are skipped.
+func (cc *ClusterContext) ReconcileClusterRun() result.ReconcileResult {
+	cr := cc.MarklogicCluster
+	run := &marklogicv1.ClusterRunStatus{State: marklogicv1.ClusterRunStateRunning}
+	if cr.Status.Run != nil {
+		run = cr.Status.Run.DeepCopy()
+	}
+	now := metav1.Now()
+	stopped := clusterStopped(cr)
+	switch run.State {
+	case marklogicv1.ClusterRunStateStopped, marklogicv1.ClusterRunStateHibernated:
+		if !stopped {
+			return cc.startCluster(run, now)
+		}
+		// spec.stopped may be set or cleared while the cluster hibernates.
+		if state := stoppedState(cr); run.State != state {
+			setRunStep(run, state, "", run.Message, now)
+			return cc.setRunStatus(run, result.Done())
+		}
+		return result.Done()
+	case marklogicv1.ClusterRunStateStopping, marklogicv1.ClusterRunStateStarting:
+		if stopped {
+			return cc.stopCluster(run, now)
+		}
+		return cc.startCluster(run, now)
+	default:
+		if !stopped {
+			return result.Continue()
+		}
+		return cc.stopCluster(run, now)
+	}
+}
+
+func (cc *ClusterContext) stopCluster(run *marklogicv1.ClusterRunStatus, now metav1.Time) result.ReconcileResult {
+	if run.State != marklogicv1.ClusterRunStateStopping {
+		setRunStep(run, marklogicv1.ClusterRunStateStopping, marklogicv1.ClusterRunStepQuiescing, "stopping HAProxy", now)
+		cc.recordClusterEvent(corev1.EventTypeNormal, clusterReasonStopping, "stopping the cluster")
+		return cc.setRunStatus(run, result.RequeueSoon(1))
+	}
+	cr := cc.MarklogicCluster
+	switch run.Step {
+	case marklogicv1.ClusterRunStepQuiescing:
+		pods, err := cc.countPods(getHAProxySelectorLabels(cr.Name))
+		if err != nil {
+			return result.Error(err)
+		}
+		if pods > 0 {
+			run.Message = fmt.Sprintf("waiting for %d HAProxy pod(s) to stop", pods)
+			return cc.setRunStatus(run, result.RequeueSoon(clusterRunRequeueSeconds))
+		}
+		setRunStep(run, marklogicv1.ClusterRunStateStopping, marklogicv1.ClusterRunStepNonBootstrapGroups, "stopping the groups but the bootstrap group", now)
+		return cc.setRunStatus(run, result.RequeueSoon(1))
+	case marklogicv1.ClusterRunStepNonBootstrapGroups:
+		pods, err := cc.countGroupPods(false)
+		if err != nil {
+			return result.Error(err)
+		}
+		if pods > 0 {
+			run.Message = fmt.Sprintf("waiting for %d pod(s) of the groups but the bootstrap group to stop", pods)
+			return cc.setRunStatus(run, result.RequeueSoon(clusterRunRequeueSeconds))
+		}
+		setRunStep(run, marklogicv1.ClusterRunStateStopping, marklogicv1.ClusterRunStepBootstrapGroup, "stopping the bootstrap group", now)
+		return cc.setRunStatus(run, result.RequeueSoon(1))
+	default:
+		pods, err := cc.countGroupPods(true)
+		if err != nil {
+			return result.Error(err)
+		}
+		if pods > 0 {
+			run.Message = fmt.Sprintf("waiting for %d pod(s) of the bootstrap group to stop", pods)
+			return cc.setRunStatus(run, result.RequeueSoon(clusterRunRequeueSeconds))
+		}
+		setRunStep(run, stoppedState(cr), "", "all pods stopped", now)
+		cc.recordClusterEvent(corev1.EventTypeNormal, clusterReasonStopped, "the cluster is stopped")
+		return cc.setRunStatus(run, result.Done())
+	}
+}
+
+func (cc *ClusterContext) startCluster(run *marklogicv1.ClusterRunStatus, now metav1.Time) result.ReconcileResult {
+	if run.State != marklogicv1.ClusterRunStateStarting {
+		setRunStep(run, marklogicv1.ClusterRunStateStarting, marklogicv1.ClusterRunStepBootstrapGroup, "starting the bootstrap group", now)
+		cc.recordClusterEvent(corev1.EventTypeNormal, clusterReasonStarting, "starting the cluster")
+		return cc.setRunStatus(run, result.RequeueSoon(1))
+	}
+	if run.Step == marklogicv1.ClusterRunStepBootstrapGroup {
+		waiting, err := cc.groupsNotReady(true)
+		if err != nil {
+			return result.Error(err)
+		}
+		if waiting != "" {
+			run.Message = "waiting for the bootstrap group to be ready: " + waiting
+			return cc.setRunStatus(run, result.RequeueSoon(clusterRunRequeueSeconds))
+		}
+		setRunStep(run, marklogicv1.ClusterRunStateStarting, marklogicv1.ClusterRunStepNonBootstrapGroups, "starting the groups but the bootstrap group", now)
+		return cc.setRunStatus(run, result.RequeueSoon(1))
+	}
+	waiting, err := cc.groupsNotReady(false)
+	if err != nil {
+		return result.Error(err)
+	}
+	if waiting != "" {
+		run.Message = "waiting for the groups to be ready: " + waiting
+		return cc.setRunStatus(run, result.RequeueSoon(clusterRunRequeueSeconds))
+	}
+	setRunStep(run, marklogicv1.ClusterRunStateRunning, "", "all groups are ready", now)
+	cc.recordClusterEvent(corev1.EventTypeNormal, clusterReasonStarted, "the cluster is started")
+	// Requeue so HAProxy scales back up.
+	return cc.setRunStatus(run, result.RequeueSoon(1))
+}
+
+// stoppedState is the state of a stopped cluster: Hibernated when only the
+// hibernation schedule keeps it stopped.
+func stoppedState(cr *marklogicv1.MarklogicCluster) marklogicv1.ClusterRunState {
+	if cr.Spec.Stopped {
+		return marklogicv1.ClusterRunStateStopped
+	}
+	return marklogicv1.ClusterRunStateHibernated
+}
+
+func setRunStep(run *marklogicv1.ClusterRunStatus, state marklogicv1.ClusterRunState, step marklogicv1.ClusterRunStep, message string, now metav1.Time) {
+	run.State = state
+	run.Step = step
+	run.Message = message
+	run.LastTransitionTime = &now
+}
+
+// runReplicas returns the replicas of a group while the cluster stops, is
+// stopped or starts, or nil when the group runs with its own replicas.
+func runReplicas(cr *marklogicv1.MarklogicCluster, group *marklogicv1.MarklogicGroups) *int32 {
+	run := cr.Status.Run
+	if run == nil {
+		return nil
+	}
+	zero := int32(0)
+	switch run.State {
+	case marklogicv1.ClusterRunStateStopped, marklogicv1.ClusterRunStateHibernated:
+		return &zero
+	case marklogicv1.ClusterRunStateStopping:
+		if run.Step == marklogicv1.ClusterRunStepBootstrapGroup || (run.Step == marklogicv1.ClusterRunStepNonBootstrapGroups && !group.IsBootstrap) {
+			return &zero
+		}
+	case marklogicv1.ClusterRunStateStarting:
+		if run.Step == marklogicv1.ClusterRunStepBootstrapGroup && !group.IsBootstrap {
+			return &zero
+		}
+	}
+	return nil
+}
+
+// haproxyReplicas returns the replicas of the HAProxy Deployment, 0 unless the
+// cluster is running.
+func haproxyReplicas(cr *marklogicv1.MarklogicCluster) *int32 {
+	if run := cr.Status.Run; run != nil && run.State != "" && run.State != marklogicv1.ClusterRunStateRunning {
+		zero := int32(0)
+		return &zero
+	}
+	return &cr.Spec.HAProxy.ReplicaCount
+}
+
+// countGroupPods counts the pods, including terminating ones, of the bootstrap
+// group or of the other groups.
+func (cc *ClusterContext) countGroupPods(bootstrap bool) (int, error) {
+	total := 0
+	for _, group := range cc.MarklogicCluster.Spec.MarkLogicGroups {
+		if group == nil || group.IsBootstrap != bootstrap {
+			continue
+		}
+		pods, err := cc.countPods(map[string]string{
+			"app.kubernetes.io/name":     "marklogic",
+			"app.kubernetes.io/instance": group.Name,
+		})
+		if err != nil {
+			return 0, err
+		}
+		total += pods
+	}
+	return total, nil
+}
+
+func (cc *ClusterContext) countPods(labels map[string]string) (int, error) {
+	list := &corev1.PodList{}
+	if err := cc.Client.List(cc.Ctx, list, client.InNamespace(cc.MarklogicCluster.Namespace), client.MatchingLabels(labels)); err != nil {
+		return 0, err
+	}
+	return len(list.Items), nil
+}
+
+// groupsNotReady describes the groups, the bootstrap group or all of them,
+// whose StatefulSet does not have all replicas ready, or returns an empty
+// string when they are ready.
+func (cc *ClusterContext) groupsNotReady(bootstrapOnly bool) (string, error) {
+	cr := cc.MarklogicCluster
+	waiting := ""
+	for _, group := range cr.Spec.MarkLogicGroups {
+		if group == nil || (bootstrapOnly && !group.IsBootstrap) {
+			continue
+		}
+		replicas := int32(1)
+		if group.Replicas != nil {
+			replicas = *group.Replicas
+		}
+		sts := &appsv1.StatefulSet{}
+		err := cc.Client.Get(cc.Ctx, types.NamespacedName{Namespace: cr.Namespace, Name: group.Name}, sts)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return "", err
+		}
+		var ready int32
+		if err == nil && sts.Status.ObservedGeneration >= sts.Generation && sts.Spec.Replicas != nil && *sts.Spec.Replicas == replicas {
+			ready = sts.Status.ReadyReplicas
+		}
+		if ready < replicas {
+			if waiting != "" {
+				waiting += ", "
+			}
+			waiting += fmt.Sprintf("%s %d/%d", group.Name, ready, replicas)
+		}
+	}
+	return waiting, nil
+}
+
+func (cc *ClusterContext) setRunStatus(run *marklogicv1.ClusterRunStatus, next result.ReconcileResult) result.ReconcileResult {
+	cr := cc.MarklogicCluster
+	patchBase := client.MergeFrom(cr.DeepCopy())
+	cr.Status.Run = run
+	if err := patchStatus(cc.Ctx, cc.Client, cr, patchBase); err != nil {
+		return result.Error(err)
+	}
+	return next
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"testing"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestClusterStopsBootstrapLastAndStartsItFirst(t *testing.T) {
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			Stopped: true,
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{
+				{Name: "dnode", IsBootstrap: true},
+				{Name: "enode"},
+			},
+		},
+	}
+	bootstrapPod := newStorageTestPod("dnode-0")
+	enodePod := newStorageTestPod("enode-0")
+	enodePod.Labels["app.kubernetes.io/instance"] = "enode"
+	cc := newUpgradeTestContext(t, cr, bootstrapPod, enodePod)
+	dnode, enode := cr.Spec.MarkLogicGroups[0], cr.Spec.MarkLogicGroups[1]
+
+	step := func(state marklogicv1.ClusterRunState, runStep marklogicv1.ClusterRunStep) {
+		t.Helper()
+		cc.ReconcileClusterRun()
+		run := cc.MarklogicCluster.Status.Run
+		if run == nil || run.State != state || run.Step != runStep {
+			t.Fatalf("expected %s/%s, got %+v", state, runStep, run)
+		}
+	}
+
+	step(marklogicv1.ClusterRunStateStopping, marklogicv1.ClusterRunStepQuiescing)
+	if replicas := haproxyReplicas(cc.MarklogicCluster); *replicas != 0 {
+		t.Fatalf("expected HAProxy to stop first, got %d replicas", *replicas)
+	}
+	step(marklogicv1.ClusterRunStateStopping, marklogicv1.ClusterRunStepNonBootstrapGroups)
+	if runReplicas(cc.MarklogicCluster, enode) == nil || runReplicas(cc.MarklogicCluster, dnode) != nil {
+		t.Fatalf("expected only the enode group to stop")
+	}
+	step(marklogicv1.ClusterRunStateStopping, marklogicv1.ClusterRunStepNonBootstrapGroups)
+	if err := cc.Client.Delete(cc.Ctx, enodePod); err != nil {
+		t.Fatalf("failed to delete pod: %v", err)
+	}
+	step(marklogicv1.ClusterRunStateStopping, marklogicv1.ClusterRunStepBootstrapGroup)
+	if runReplicas(cc.MarklogicCluster, dnode) == nil {
+		t.Fatalf("expected the bootstrap group to stop last")
+	}
+	if err := cc.Client.Delete(cc.Ctx, bootstrapPod); err != nil {
+		t.Fatalf("failed to delete pod: %v", err)
+	}
+	step(marklogicv1.ClusterRunStateStopped, "")
+	if res := cc.ReconcileClusterRun(); !res.Completed() {
+		t.Fatalf("expected the rest of the reconcile to be skipped while stopped")
+	}
+
+	cc.MarklogicCluster.Spec.Stopped = false
+	if err := cc.Client.Update(cc.Ctx, cc.MarklogicCluster); err != nil {
+		t.Fatalf("failed to update cluster: %v", err)
+	}
+	step(marklogicv1.ClusterRunStateStarting, marklogicv1.ClusterRunStepBootstrapGroup)
+	if runReplicas(cc.MarklogicCluster, dnode) != nil || runReplicas(cc.MarklogicCluster, enode) == nil {
+		t.Fatalf("expected the bootstrap group to start first")
+	}
+	step(marklogicv1.ClusterRunStateStarting, marklogicv1.ClusterRunStepBootstrapGroup)
+	for _, name := range []string{"dnode", "enode"} {
+		if err := cc.Client.Create(cc.Ctx, newRunTestStatefulSet(name)); err != nil {
+			t.Fatalf("failed to create statefulset: %v", err)
+		}
+	}
+	step(marklogicv1.ClusterRunStateStarting, marklogicv1.ClusterRunStepNonBootstrapGroups)
+	step(marklogicv1.ClusterRunStateRunning, "")
+	if res := cc.ReconcileClusterRun(); res.Completed() {
+		t.Fatalf("expected a running cluster to continue the reconcile")
+	}
+}
+
+func newRunTestStatefulSet(name string) *appsv1.StatefulSet {
+	replicas := int32(1)
+	return &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas: &replicas,
+			Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "marklogic-server"}}}},
+		},
+		Status: appsv1.StatefulSetStatus{ReadyReplicas: 1},
+	}
+}
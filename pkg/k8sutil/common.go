@@ -114,7 +114,7 @@ func setOperatorInternalStatus(oc *OperatorContext, newState marklogicv1.Interna
 	patch := client.MergeFrom(oc.MarklogicGroup.DeepCopy())
 	oc.MarklogicGroup.Status.MarklogicGroupStatus = newState
 
-	if err := oc.Client.Status().Patch(oc.Ctx, oc.MarklogicGroup, patch); err != nil {
+	if err := patchStatus(oc.Ctx, oc.Client, oc.MarklogicGroup, patch); err != nil {
 		oc.ReqLogger.Error(err, "error updating the MarkLogic Operator Internal status")
 		return err
 	}
@@ -4,9 +4,11 @@ package k8sutil
 
 import (
 	"embed"
+	"maps"
 	"strings"
 
 	"github.com/cisco-open/k8s-objectmatcher/patch"
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
 	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -62,9 +64,10 @@ func (oc *OperatorContext) ReconcileFluentBitConfigMap() result.ReconcileResult
 	cr := oc.MarklogicGroup
 
 	logger.Info("Reconciling Fluent Bit ConfigMap")
-	labels := getFluentBitLabels(cr.Spec.Name)
-	annotations := map[string]string{}
-	configMapName := "fluent-bit"
+	clusterName, _ := oc.getOwningClusterName()
+	labels := withClusterNameLabel(mergeMetadata(cr.Spec.AdditionalLabels, getFluentBitLabels(cr.Spec.Name)), clusterName)
+	annotations := mergeMetadata(cr.Spec.AdditionalAnnotations, nil)
+	configMapName := fluentBitConfigMapName(cr.Spec.Name)
 	objectMeta := generateObjectMeta(configMapName, cr.Namespace, labels, annotations)
 	nsName := types.NamespacedName{Name: objectMeta.Name, Namespace: objectMeta.Namespace}
 	configmap := &corev1.ConfigMap{}
@@ -90,6 +93,10 @@ func (oc *OperatorContext) ReconcileFluentBitConfigMap() result.ReconcileResult
 			return result.Error(err)
 		}
 	}
+	if err := oc.deleteLegacyFluentBitConfigMap(); err != nil {
+		logger.Error(err, "Failed to delete legacy Fluent Bit ConfigMap")
+		return result.Error(err)
+	}
 
 	return result.Continue()
 }
@@ -111,6 +118,8 @@ func (oc *OperatorContext) updateConfigMapIfNeeded(current, desired *corev1.Conf
 	if !patchDiff.IsEmpty() {
 		logger.Info(name + " data has changed, updating it")
 		current.Data = desired.Data
+		current.Labels = mergeMetadata(current.Labels, desired.Labels)
+		current.Annotations = mergeMetadata(current.Annotations, desired.Annotations)
 		if err := patch.DefaultAnnotator.SetLastAppliedAnnotation(current); err != nil {
 			logger.Error(err, "Failed to set last applied annotation for "+name)
 		}
@@ -127,7 +136,8 @@ func (oc *OperatorContext) updateConfigMapIfNeeded(current, desired *corev1.Conf
 
 func (oc *OperatorContext) generateFluentBitDef(configMapMeta metav1.ObjectMeta, ownerRef metav1.OwnerReference) *corev1.ConfigMap {
 
-	fluentBitData := oc.getFluentBitData()
+	logCollection := oc.MarklogicGroup.Spec.LogCollection
+	fluentBitData := newLogCollector(logCollection).configData(logCollection)
 	fluentBitConfigmap := &corev1.ConfigMap{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "ConfigMap",
@@ -198,7 +208,8 @@ func (oc *OperatorContext) getScriptsForConfigMap() map[string]string {
 	return configMapData
 }
 
-func (oc *OperatorContext) getFluentBitData() map[string]string {
+// fluentBitConfigData is the configuration of the fluent-bit engine.
+func fluentBitConfigData(logCollection *marklogicv1.LogCollection) map[string]string {
 	fluentBitData := make(map[string]string)
 
 	// Main YAML configuration file
@@ -208,76 +219,81 @@ func (oc *OperatorContext) getFluentBitData() map[string]string {
   daemon: off
   parsers_file: parsers.yaml
   http_server: on
-  http_listen: 127.0.0.1
+  http_listen: 0.0.0.0
   http_port: 2020
   hot_reload: on
   storage.metrics: on
-
+` + fluentBitServiceHealthCheck(logCollection) + fluentBitServiceStorage(logCollection)
+	if logCollection.CredentialsSecretName != "" {
+		fluentBitData["fluent-bit.yaml"] += `
+includes:
+  - ` + fluentBitCredentialsMountPath + fluentBitCredentialsFile + `
+`
+	}
+	fluentBitData["fluent-bit.yaml"] += `
 pipeline:
   inputs:`
-	if strings.TrimSpace(oc.MarklogicGroup.Spec.LogCollection.Inputs) != "" {
-		fluentBitData["fluent-bit.yaml"] += "\n" + normalizeYAMLIndentation(oc.MarklogicGroup.Spec.LogCollection.Inputs, 4, 6)
+	inputs := ""
+	if strings.TrimSpace(logCollection.Inputs) != "" {
+		inputs += "\n" + normalizeYAMLIndentation(logCollection.Inputs, 4, 6)
 	} else {
-		if oc.MarklogicGroup.Spec.LogCollection.Files.ErrorLogs {
-			fluentBitData["fluent-bit.yaml"] += `
+		if logCollection.Files.ErrorLogs {
+			inputs += `
     - name: tail
       path: /var/opt/MarkLogic/Logs/*ErrorLog.txt
       read_from_head: true
       tag: kube.marklogic.logs.error
       path_key: path
-      parser: error_parser
-      mem_buf_limit: 4MB`
+      parser: error_parser`
 		}
 
-		if oc.MarklogicGroup.Spec.LogCollection.Files.AccessLogs {
-			fluentBitData["fluent-bit.yaml"] += `
+		if logCollection.Files.AccessLogs {
+			inputs += `
     - name: tail
       path: /var/opt/MarkLogic/Logs/*AccessLog.txt
       read_from_head: true
       tag: kube.marklogic.logs.access
       path_key: path
-      parser: access_parser
-      mem_buf_limit: 4MB`
+      parser: ` + accessLogParser(logCollection.Files)
 		}
 
-		if oc.MarklogicGroup.Spec.LogCollection.Files.RequestLogs {
-			fluentBitData["fluent-bit.yaml"] += `
+		if logCollection.Files.RequestLogs {
+			inputs += `
     - name: tail
       path: /var/opt/MarkLogic/Logs/*RequestLog.txt
       read_from_head: true
       tag: kube.marklogic.logs.request
       path_key: path
-      parser: json_parser
-      mem_buf_limit: 4MB`
+      parser: json_parser`
 		}
 
-		if oc.MarklogicGroup.Spec.LogCollection.Files.CrashLogs {
-			fluentBitData["fluent-bit.yaml"] += `
+		if logCollection.Files.CrashLogs {
+			inputs += `
     - name: tail
       path: /var/opt/MarkLogic/Logs/CrashLog.txt
       read_from_head: true
       tag: kube.marklogic.logs.crash
-      path_key: path
-      mem_buf_limit: 4MB`
+      path_key: path`
 		}
 
-		if oc.MarklogicGroup.Spec.LogCollection.Files.AuditLogs {
-			fluentBitData["fluent-bit.yaml"] += `
+		if logCollection.Files.AuditLogs {
+			inputs += `
     - name: tail
       path: /var/opt/MarkLogic/Logs/AuditLog.txt
       read_from_head: true
       tag: kube.marklogic.logs.audit
-      path_key: path
-      mem_buf_limit: 4MB`
+      path_key: path`
 		}
 	}
+	fluentBitData["fluent-bit.yaml"] += withItemProperties(inputs, 4, fluentBitInputProperties(logCollection))
 
 	// Add FILTER sections
 	fluentBitData["fluent-bit.yaml"] += `
 
   filters:`
-	if strings.TrimSpace(oc.MarklogicGroup.Spec.LogCollection.Filters) != "" {
-		fluentBitData["fluent-bit.yaml"] += "\n" + normalizeYAMLIndentation(oc.MarklogicGroup.Spec.LogCollection.Filters, 4, 6)
+	if strings.TrimSpace(logCollection.Filters) != "" {
+		fluentBitData["fluent-bit.yaml"] += "\n" + normalizeYAMLIndentation(logCollection.Filters, 4, 6) +
+			fluentBitSeverityFilters(logCollection, 4)
 	} else {
 		fluentBitData["fluent-bit.yaml"] += `
         - name: modify
@@ -305,48 +321,53 @@ pipeline:
           match: kube.marklogic.logs.crash
           add:
             - tag kube.marklogic.logs.crash
-        `
+        ` + fluentBitSeverityFilters(logCollection, 8)
 	}
 
 	// Add OUTPUT sections
 	fluentBitData["fluent-bit.yaml"] += `
 
   outputs:`
+	outputs := ""
 	// Handle user-defined outputs from LogCollection.Outputs
-	if strings.TrimSpace(oc.MarklogicGroup.Spec.LogCollection.Outputs) != "" {
-		fluentBitData["fluent-bit.yaml"] += "\n" + normalizeYAMLIndentation(oc.MarklogicGroup.Spec.LogCollection.Outputs, 4, 6)
+	if strings.TrimSpace(logCollection.Outputs) != "" {
+		outputs += "\n" + normalizeYAMLIndentation(logCollection.Outputs, 4, 6)
 	} else {
 		// Default stdout output if none specified
-		fluentBitData["fluent-bit.yaml"] += `
+		outputs += `
     - name: stdout
       match: "*"
       format: json_lines`
 	}
+	outputs += fluentBitSeverityRouteOutputs(logCollection)
+	fluentBitData["fluent-bit.yaml"] += withItemProperties(outputs, 4, fluentBitOutputProperties(logCollection))
 
 	// Parsers in YAML format
 	fluentBitData["parsers.yaml"] = `parsers:`
-	if strings.TrimSpace(oc.MarklogicGroup.Spec.LogCollection.Parsers) != "" {
-		fluentBitData["parsers.yaml"] += "\n" + normalizeYAMLIndentation(oc.MarklogicGroup.Spec.LogCollection.Parsers, 2, 4)
+	if strings.TrimSpace(logCollection.Parsers) != "" {
+		fluentBitData["parsers.yaml"] += "\n" + normalizeYAMLIndentation(logCollection.Parsers, 2, 4)
 	} else {
 		fluentBitData["parsers.yaml"] += `
   - name: error_parser
     format: regex
-    regex: ^(?<time>(.+?)(?=[a-zA-Z]))(?<log_level>(.+?)(?=:))(.+?)(?=[a-zA-Z])(?<log>.*)
+    regex: ` + markLogicErrorLogRegex + `
     time_key: time
     time_format: "%Y-%m-%d %H:%M:%S.%L"
 
   - name: access_parser
     format: regex
-    regex: ^(?<host>[^ ]*)(.+?)(?<=\- )(?<user>(.+?)(?=\[))(.+?)(?<=\[)(?<time>(.+?)(?=\]))(.+?)(?<=")(?<request>[^\ ]+[^\"]+)(.+?)(?=\d)(?<response_code>[^\ ]*)(.+?)(?=\d|-)(?<response_obj_size>[^\ ]*)(.+?)(?=")(?<request_info>.*)
+    regex: ` + markLogicAccessLogRegex + `
     time_key: time
     time_format: "%d/%b/%Y:%H:%M:%S %z"
 
   - name: json_parser
     format: json
     time_key: time
-    time_format: "%Y-%m-%dT%H:%M:%S%z"`
+    time_format: "%Y-%m-%dT%H:%M:%S%z"` + fluentBitStructuredAccessParser(logCollection.Files)
 	}
 
+	maps.Copy(fluentBitData, fluentBitSamplingData(logCollection))
+
 	return fluentBitData
 }
 
@@ -0,0 +1,413 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"reflect"
+	"strconv"
+
+	"github.com/go-logr/logr"
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	contentJobReasonStarted   = "ContentJobStarted"
+	contentJobReasonRetrying  = "ContentJobRetrying"
+	contentJobReasonSucceeded = "ContentJobSucceeded"
+	contentJobReasonFailed    = "ContentJobFailed"
+
+	defaultContentJobBackoffLimit = 3
+	contentJobPollIntervalSeconds = 30
+	// contentJobDataPath is where spec.volumeClaimName is mounted.
+	contentJobDataPath = "/data"
+)
+
+type ContentJobContext struct {
+	Ctx        context.Context
+	Request    *reconcile.Request
+	Client     client.Client
+	ContentJob *marklogicv1.MarklogicContentJob
+	ReqLogger  logr.Logger
+	Recorder   record.EventRecorder
+}
+
+func CreateContentJobContext(
+	ctx context.Context,
+	request *reconcile.Request,
+	client client.Client,
+	rec record.EventRecorder) (*ContentJobContext, error) {
+
+	jc := &ContentJobContext{
+		Ctx:       ctx,
+		Request:   request,
+		Client:    client,
+		ReqLogger: log.FromContext(ctx),
+		Recorder:  rec,
+	}
+	contentJob := &marklogicv1.MarklogicContentJob{}
+	if err := client.Get(ctx, request.NamespacedName, contentJob); err != nil {
+		return nil, err
+	}
+	jc.ContentJob = contentJob
+	jc.ReqLogger = jc.ReqLogger.WithValues("contentJob", contentJob.Name)
+	return jc, nil
+}
+
+// ReconcileContentJob runs MLCP against the cluster of a content job once
+// all its groups are ready. The run is a Job owned by the content job, with
+// the host, port, TLS and admin credentials of the cluster; the Job retries
+// failed runs up to the backoff limit. The attempts of the Job are reported
+// in the status of the content job, which never runs again once it
+// succeeded or failed.
+func (jc *ContentJobContext) ReconcileContentJob() result.ReconcileResult {
+	contentJob := jc.ContentJob
+	if contentJob.Status.Phase == marklogicv1.ContentJobSucceeded || contentJob.Status.Phase == marklogicv1.ContentJobFailed {
+		return result.Continue()
+	}
+	if contentJob.Status.Phase == marklogicv1.ContentJobRunning {
+		return jc.trackContentJob()
+	}
+
+	cluster, err := jc.getCluster(contentJob.Spec.Cluster)
+	if cluster == nil {
+		return jc.contentJobClusterMissing(contentJob.Spec.Cluster, err)
+	}
+	clusters := []*marklogicv1.MarklogicCluster{cluster}
+	var target *marklogicv1.MarklogicCluster
+	if contentJob.Spec.Operation == marklogicv1.ContentJobCopy {
+		target = cluster
+		if name := contentJobTargetCluster(contentJob); name != cluster.Name {
+			if target, err = jc.getCluster(name); target == nil {
+				return jc.contentJobClusterMissing(name, err)
+			}
+			clusters = append(clusters, target)
+		}
+	}
+	for _, cr := range clusters {
+		waiting, err := jc.clusterContext(cr).groupsNotReady(false)
+		if err != nil {
+			return result.Error(err)
+		}
+		if waiting != "" {
+			return jc.contentJobWaiting(fmt.Sprintf("waiting for the groups of cluster %s to be ready: %s", cr.Name, waiting))
+		}
+	}
+
+	job, err := jc.generateContentJob(cluster, target)
+	if err != nil {
+		return jc.failContentJob(err.Error())
+	}
+	if err := jc.Client.Create(jc.Ctx, job); err != nil && !apierrors.IsAlreadyExists(err) {
+		return result.Error(err)
+	}
+	now := metav1.Now()
+	next := contentJob.Status.DeepCopy()
+	next.Phase = marklogicv1.ContentJobRunning
+	next.JobName = job.Name
+	next.StartTime = &now
+	next.Message = ""
+	if err := jc.setContentJobStatus(next); err != nil {
+		return result.Error(err)
+	}
+	jc.recordContentJobEvent(corev1.EventTypeNormal, contentJobReasonStarted,
+		fmt.Sprintf("Started Job %s running MLCP %s on database %s", job.Name, contentJob.Spec.Operation, contentJob.Spec.Database))
+	return result.Continue()
+}
+
+// trackContentJob reports the attempts of the Job of a running content job
+// and finishes the content job once the Job completed.
+func (jc *ContentJobContext) trackContentJob() result.ReconcileResult {
+	contentJob := jc.ContentJob
+	job := &batchv1.Job{}
+	err := jc.Client.Get(jc.Ctx, types.NamespacedName{Namespace: contentJob.Namespace, Name: contentJob.Status.JobName}, job)
+	if apierrors.IsNotFound(err) {
+		return jc.failContentJob(fmt.Sprintf("Job %s was deleted before it completed", contentJob.Status.JobName))
+	}
+	if err != nil {
+		return result.Error(err)
+	}
+	next := contentJob.Status.DeepCopy()
+	next.Attempts = max(next.Attempts, job.Status.Active+job.Status.Succeeded+job.Status.Failed)
+	if job.Status.Failed > next.FailedAttempts {
+		next.FailedAttempts = job.Status.Failed
+		if !jobFailed(job) {
+			next.Message = fmt.Sprintf("retrying after %d failed attempt(s)", next.FailedAttempts)
+			jc.recordContentJobEvent(corev1.EventTypeWarning, contentJobReasonRetrying,
+				fmt.Sprintf("Job %s failed %d time(s), retrying", job.Name, next.FailedAttempts))
+		}
+	}
+	switch {
+	case job.Status.Succeeded > 0:
+		return jc.finishContentJob(next, marklogicv1.ContentJobSucceeded, fmt.Sprintf("Job %s completed", job.Name))
+	case jobFailed(job):
+		return jc.finishContentJob(next, marklogicv1.ContentJobFailed, fmt.Sprintf("Job %s failed: %s", job.Name, jobFailureMessage(job)))
+	}
+	if !reflect.DeepEqual(*next, contentJob.Status) {
+		if err := jc.setContentJobStatus(next); err != nil {
+			return result.Error(err)
+		}
+	}
+	return result.Continue()
+}
+
+// generateContentJob returns the Job running MLCP. The admin credentials of
+// the clusters are expanded from environment variables by Kubernetes, so
+// they never appear in the Job spec.
+func (jc *ContentJobContext) generateContentJob(cluster, target *marklogicv1.MarklogicCluster) (*batchv1.Job, error) {
+	contentJob := jc.ContentJob
+	spec := contentJob.Spec
+	host, err := jc.clusterContext(cluster).bootstrapHostFQDN()
+	if err != nil {
+		return nil, err
+	}
+	port := strconv.Itoa(int(contentJobPort(contentJob)))
+	env := contentJobCredentials("ML", cluster)
+
+	var command []string
+	switch spec.Operation {
+	case marklogicv1.ContentJobCopy:
+		if spec.Target == nil {
+			return nil, fmt.Errorf("target is required for Copy")
+		}
+		targetHost, err := jc.clusterContext(target).bootstrapHostFQDN()
+		if err != nil {
+			return nil, err
+		}
+		command = []string{"mlcp.sh", "copy",
+			"-input_host", host,
+			"-input_port", port,
+			"-input_username", "$(ML_USERNAME)",
+			"-input_password", "$(ML_PASSWORD)",
+			"-input_database", spec.Database,
+			"-output_host", targetHost,
+			"-output_port", port,
+			"-output_username", "$(ML_OUTPUT_USERNAME)",
+			"-output_password", "$(ML_OUTPUT_PASSWORD)",
+			"-output_database", spec.Target.Database,
+			"-mode", "local",
+		}
+		if defaultAppServersTLS(cluster) {
+			command = append(command, "-input_ssl", "true")
+		}
+		if defaultAppServersTLS(target) {
+			command = append(command, "-output_ssl", "true")
+		}
+		env = append(env, contentJobCredentials("ML_OUTPUT", target)...)
+	default:
+		command = []string{"mlcp.sh", "import",
+			"-host", host,
+			"-port", port,
+			"-username", "$(ML_USERNAME)",
+			"-password", "$(ML_PASSWORD)",
+			"-database", spec.Database,
+		}
+		if spec.Operation == marklogicv1.ContentJobExport {
+			command[1] = "export"
+			command = append(command, "-output_file_path", spec.OutputPath)
+		} else {
+			command = append(command, "-input_file_path", spec.InputPath)
+		}
+		command = append(command, "-mode", "local")
+		if defaultAppServersTLS(cluster) {
+			command = append(command, "-ssl", "true")
+		}
+	}
+	command = append(command, spec.Args...)
+
+	container := corev1.Container{
+		Name:            "mlcp",
+		Image:           spec.Image,
+		ImagePullPolicy: spec.ImagePullPolicy,
+		Command:         command,
+		Env:             env,
+	}
+	if spec.EnvSecretName != "" {
+		container.EnvFrom = []corev1.EnvFromSource{{SecretRef: &corev1.SecretEnvSource{
+			LocalObjectReference: corev1.LocalObjectReference{Name: spec.EnvSecretName},
+		}}}
+	}
+	if spec.Resources != nil {
+		container.Resources = *spec.Resources
+	}
+	podSpec := corev1.PodSpec{
+		RestartPolicy:                corev1.RestartPolicyNever,
+		ImagePullSecrets:             cluster.Spec.ImagePullSecrets,
+		AutomountServiceAccountToken: boolPtr(false),
+	}
+	if spec.VolumeClaimName != "" {
+		container.VolumeMounts = []corev1.VolumeMount{{Name: "data", MountPath: contentJobDataPath}}
+		podSpec.Volumes = []corev1.Volume{{
+			Name: "data",
+			VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+				ClaimName: spec.VolumeClaimName,
+			}},
+		}}
+	}
+	podSpec.Containers = []corev1.Container{container}
+	if cluster.Spec.RestrictedPodSecurity {
+		restrictPodSpec(&podSpec)
+	}
+
+	backoffLimit := int32(defaultContentJobBackoffLimit)
+	var activeDeadlineSeconds *int64
+	if spec.Retry != nil {
+		if spec.Retry.BackoffLimit != nil {
+			backoffLimit = *spec.Retry.BackoffLimit
+		}
+		activeDeadlineSeconds = spec.Retry.ActiveDeadlineSeconds
+	}
+	// The pod does not get the selector labels of the cluster, so no Service
+	// or PodDisruptionBudget of a group selects it.
+	labels := map[string]string{
+		ClusterNameLabel:                    cluster.Name,
+		"marklogic.progress.com/task":       "content-job",
+		"marklogic.progress.com/contentjob": contentJob.Name,
+	}
+	job := &batchv1.Job{
+		ObjectMeta: generateObjectMeta(contentJob.Name, contentJob.Namespace, labels, nil),
+		Spec: batchv1.JobSpec{
+			BackoffLimit:          &backoffLimit,
+			ActiveDeadlineSeconds: activeDeadlineSeconds,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: maps.Clone(labels)},
+				Spec:       podSpec,
+			},
+		},
+	}
+	AddOwnerRefToObject(job, marklogicContentJobAsOwner(contentJob))
+	return job, nil
+}
+
+// contentJobCredentials returns the environment variables <prefix>_USERNAME
+// and <prefix>_PASSWORD from the admin Secret of a cluster.
+func contentJobCredentials(prefix string, cr *marklogicv1.MarklogicCluster) []corev1.EnvVar {
+	secretKey := func(key string) *corev1.EnvVarSource {
+		return &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: AdminSecretName(cr)},
+			Key:                  key,
+		}}
+	}
+	return []corev1.EnvVar{
+		{Name: prefix + "_USERNAME", ValueFrom: secretKey("username")},
+		{Name: prefix + "_PASSWORD", ValueFrom: secretKey("password")},
+	}
+}
+
+// defaultAppServersTLS reports whether the default app servers of a cluster,
+// which MLCP connects to, use TLS.
+func defaultAppServersTLS(cr *marklogicv1.MarklogicCluster) bool {
+	return cr.Spec.Tls != nil && cr.Spec.Tls.EnableOnDefaultAppServers
+}
+
+func contentJobPort(contentJob *marklogicv1.MarklogicContentJob) int32 {
+	if contentJob.Spec.Port == 0 {
+		return 8000
+	}
+	return contentJob.Spec.Port
+}
+
+func contentJobTargetCluster(contentJob *marklogicv1.MarklogicContentJob) string {
+	if contentJob.Spec.Target != nil && contentJob.Spec.Target.Cluster != "" {
+		return contentJob.Spec.Target.Cluster
+	}
+	return contentJob.Spec.Cluster
+}
+
+// getCluster returns the cluster of the given name in the namespace of the
+// content job, or nil with the error of the lookup.
+func (jc *ContentJobContext) getCluster(name string) (*marklogicv1.MarklogicCluster, error) {
+	cr := &marklogicv1.MarklogicCluster{}
+	if err := jc.Client.Get(jc.Ctx, types.NamespacedName{Name: name, Namespace: jc.ContentJob.Namespace}, cr); err != nil {
+		return nil, err
+	}
+	return cr, nil
+}
+
+func (jc *ContentJobContext) contentJobClusterMissing(name string, err error) result.ReconcileResult {
+	if apierrors.IsNotFound(err) {
+		return jc.failContentJob(fmt.Sprintf("cluster %s not found", name))
+	}
+	return result.Error(err)
+}
+
+// clusterContext returns a ClusterContext for the helpers that read the
+// bootstrap host and the readiness of a cluster.
+func (jc *ContentJobContext) clusterContext(cr *marklogicv1.MarklogicCluster) *ClusterContext {
+	return &ClusterContext{
+		Ctx:              jc.Ctx,
+		Client:           jc.Client,
+		MarklogicCluster: cr,
+		ReqLogger:        jc.ReqLogger,
+		Recorder:         jc.Recorder,
+	}
+}
+
+func (jc *ContentJobContext) contentJobWaiting(message string) result.ReconcileResult {
+	next := jc.ContentJob.Status.DeepCopy()
+	next.Phase = marklogicv1.ContentJobPending
+	next.Message = message
+	if !reflect.DeepEqual(*next, jc.ContentJob.Status) {
+		if err := jc.setContentJobStatus(next); err != nil {
+			return result.Error(err)
+		}
+	}
+	return result.RequeueSoon(contentJobPollIntervalSeconds)
+}
+
+func (jc *ContentJobContext) failContentJob(message string) result.ReconcileResult {
+	return jc.finishContentJob(jc.ContentJob.Status.DeepCopy(), marklogicv1.ContentJobFailed, message)
+}
+
+// finishContentJob records the end of the content job in status and as an
+// event.
+func (jc *ContentJobContext) finishContentJob(next *marklogicv1.MarklogicContentJobStatus, phase marklogicv1.ContentJobPhase, message string) result.ReconcileResult {
+	now := metav1.Now()
+	next.Phase, next.Message, next.CompletionTime = phase, message, &now
+	if err := jc.setContentJobStatus(next); err != nil {
+		return result.Error(err)
+	}
+	if phase == marklogicv1.ContentJobSucceeded {
+		jc.ReqLogger.Info("The content job succeeded", "job", next.JobName)
+		jc.recordContentJobEvent(corev1.EventTypeNormal, contentJobReasonSucceeded, message)
+		return result.Continue()
+	}
+	jc.ReqLogger.Info("The content job failed", "message", message)
+	jc.recordContentJobEvent(corev1.EventTypeWarning, contentJobReasonFailed, message)
+	return result.Continue()
+}
+
+func (jc *ContentJobContext) setContentJobStatus(status *marklogicv1.MarklogicContentJobStatus) error {
+	patchBase := client.MergeFrom(jc.ContentJob.DeepCopy())
+	jc.ContentJob.Status = *status
+	return patchStatus(jc.Ctx, jc.Client, jc.ContentJob, patchBase)
+}
+
+func (jc *ContentJobContext) recordContentJobEvent(eventType, reason, message string) {
+	if jc.Recorder != nil {
+		jc.Recorder.Event(jc.ContentJob, eventType, reason, message)
+	}
+}
+
+func marklogicContentJobAsOwner(contentJob *marklogicv1.MarklogicContentJob) metav1.OwnerReference {
+	trueVar := true
+	return metav1.OwnerReference{
+		APIVersion: marklogicv1.GroupVersion.String(),
+		Kind:       "MarklogicContentJob",
+		Name:       contentJob.Name,
+		UID:        contentJob.UID,
+		Controller: &trueVar,
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func newContentJobTestContext(t *testing.T, contentJob *marklogicv1.MarklogicContentJob, objects ...client.Object) *ContentJobContext {
+	t.Helper()
+	fakeClient, _ := newFakeClient(t, append(objects, contentJob)...)
+	return &ContentJobContext{
+		Ctx:        context.Background(),
+		Client:     fakeClient,
+		ContentJob: contentJob,
+		ReqLogger:  logr.Discard(),
+		Recorder:   record.NewFakeRecorder(10),
+	}
+}
+
+func newContentJobTestCluster(name string, replicas int32) *marklogicv1.MarklogicCluster {
+	return &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			ClusterDomain:   "cluster.local",
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{{Name: name + "-dnode", Replicas: &replicas, IsBootstrap: true}},
+		},
+	}
+}
+
+func TestReconcileContentJobCopiesBetweenClusters(t *testing.T) {
+	replicas := int32(1)
+	source := newContentJobTestCluster("ml", replicas)
+	source.Spec.Tls = &marklogicv1.Tls{EnableOnDefaultAppServers: true}
+	target := newContentJobTestCluster("archive", replicas)
+	sourceSts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml-dnode", Namespace: "default"},
+		Spec:       appsv1.StatefulSetSpec{Replicas: &replicas},
+		Status:     appsv1.StatefulSetStatus{ReadyReplicas: 1},
+	}
+	targetSts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "archive-dnode", Namespace: "default"},
+		Spec:       appsv1.StatefulSetSpec{Replicas: &replicas},
+	}
+	backoffLimit := int32(2)
+	contentJob := &marklogicv1.MarklogicContentJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "orders-copy", Namespace: "default", UID: "copy-uid"},
+		Spec: marklogicv1.MarklogicContentJobSpec{
+			Cluster:   "ml",
+			Operation: marklogicv1.ContentJobCopy,
+			Image:     "example.com/mlcp:11",
+			Database:  "Orders",
+			Port:      8000,
+			Target:    &marklogicv1.ContentJobTarget{Cluster: "archive", Database: "Orders"},
+			Args:      []string{"-collection_filter", "2024"},
+			Retry:     &marklogicv1.ContentJobRetry{BackoffLimit: &backoffLimit},
+		},
+	}
+	jc := newContentJobTestContext(t, contentJob, source, target, sourceSts, targetSts)
+	recorder := jc.Recorder.(*record.FakeRecorder)
+
+	// The job waits for the groups of both clusters.
+	res := jc.ReconcileContentJob()
+	if out, _ := res.Output(); out.RequeueAfter == 0 {
+		t.Fatalf("expected the job to be checked again")
+	}
+	if status := contentJob.Status; status.Phase != marklogicv1.ContentJobPending || !strings.Contains(status.Message, "archive-dnode 0/1") {
+		t.Fatalf("expected the job to wait for the target cluster, got %+v", status)
+	}
+
+	targetSts.Status.ReadyReplicas = 1
+	if err := jc.Client.Status().Update(jc.Ctx, targetSts); err != nil {
+		t.Fatalf("failed to update StatefulSet: %v", err)
+	}
+	jc.ReconcileContentJob()
+	if status := contentJob.Status; status.Phase != marklogicv1.ContentJobRunning || status.JobName != "orders-copy" || status.StartTime == nil {
+		t.Fatalf("expected the job to run, got %+v", status)
+	}
+	job := &batchv1.Job{}
+	if err := jc.Client.Get(jc.Ctx, types.NamespacedName{Namespace: "default", Name: "orders-copy"}, job); err != nil {
+		t.Fatalf("expected the MLCP Job: %v", err)
+	}
+	if *job.Spec.BackoffLimit != 2 || job.OwnerReferences[0].Kind != "MarklogicContentJob" {
+		t.Fatalf("unexpected Job %+v", job.ObjectMeta)
+	}
+	container := job.Spec.Template.Spec.Containers[0]
+	command := strings.Join(container.Command, " ")
+	if !strings.HasPrefix(command, "mlcp.sh copy -input_host ml-dnode-0.ml-dnode.default.svc.cluster.local -input_port 8000") ||
+		!strings.Contains(command, "-output_host archive-dnode-0.archive-dnode.default.svc.cluster.local") ||
+		!strings.Contains(command, "-output_password $(ML_OUTPUT_PASSWORD)") || !strings.Contains(command, "-input_ssl true") ||
+		strings.Contains(command, "-output_ssl") || !strings.HasSuffix(command, "-collection_filter 2024") {
+		t.Fatalf("unexpected command %q", command)
+	}
+	if !slices.ContainsFunc(container.Env, func(env corev1.EnvVar) bool {
+		return env.Name == "ML_OUTPUT_PASSWORD" && env.ValueFrom.SecretKeyRef.Name == "archive-admin"
+	}) {
+		t.Fatalf("unexpected environment %+v", container.Env)
+	}
+
+	// A failed attempt is retried by the Job and reported.
+	job.Status.Failed, job.Status.Active = 1, 1
+	if err := jc.Client.Status().Update(jc.Ctx, job); err != nil {
+		t.Fatalf("failed to update Job: %v", err)
+	}
+	jc.ReconcileContentJob()
+	if status := contentJob.Status; status.Phase != marklogicv1.ContentJobRunning || status.Attempts != 2 || status.FailedAttempts != 1 {
+		t.Fatalf("expected the retry to be reported, got %+v", status)
+	}
+
+	job.Status.Active, job.Status.Failed = 0, 3
+	job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Message: "Job has reached the specified backoff limit"}}
+	if err := jc.Client.Status().Update(jc.Ctx, job); err != nil {
+		t.Fatalf("failed to update Job: %v", err)
+	}
+	jc.ReconcileContentJob()
+	if status := contentJob.Status; status.Phase != marklogicv1.ContentJobFailed || status.FailedAttempts != 3 ||
+		status.CompletionTime == nil || !strings.Contains(status.Message, "backoff limit") {
+		t.Fatalf("expected the job to fail, got %+v", status)
+	}
+	reasons := []string{}
+	for len(recorder.Events) > 0 {
+		event := <-recorder.Events
+		for _, reason := range []string{contentJobReasonStarted, contentJobReasonRetrying, contentJobReasonFailed} {
+			if strings.Contains(event, reason) {
+				reasons = append(reasons, reason)
+			}
+		}
+	}
+	if !slices.Equal(reasons, []string{contentJobReasonStarted, contentJobReasonRetrying, contentJobReasonFailed}) {
+		t.Fatalf("unexpected events %v", reasons)
+	}
+}
+
+func TestGenerateContentJobMountsTheVolumeOfAnExport(t *testing.T) {
+	cluster := newContentJobTestCluster("ml", 1)
+	contentJob := &marklogicv1.MarklogicContentJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "orders-export", Namespace: "default"},
+		Spec: marklogicv1.MarklogicContentJobSpec{
+			Cluster:         "ml",
+			Operation:       marklogicv1.ContentJobExport,
+			Image:           "example.com/mlcp:11",
+			Database:        "Orders",
+			Port:            8010,
+			OutputPath:      "/data/orders",
+			VolumeClaimName: "exports",
+		},
+	}
+	jc := newContentJobTestContext(t, contentJob, cluster)
+	job, err := jc.generateContentJob(cluster, nil)
+	if err != nil {
+		t.Fatalf("failed to generate the Job: %v", err)
+	}
+	command := strings.Join(job.Spec.Template.Spec.Containers[0].Command, " ")
+	if !strings.HasPrefix(command, "mlcp.sh export -host ml-dnode-0.ml-dnode.default.svc.cluster.local -port 8010") ||
+		!strings.HasSuffix(command, "-output_file_path /data/orders -mode local") {
+		t.Fatalf("unexpected command %q", command)
+	}
+	volumes := job.Spec.Template.Spec.Volumes
+	if len(volumes) != 1 || volumes[0].PersistentVolumeClaim.ClaimName != "exports" ||
+		job.Spec.Template.Spec.Containers[0].VolumeMounts[0].MountPath != contentJobDataPath {
+		t.Fatalf("expected the exports claim at %s, got %+v", contentJobDataPath, volumes)
+	}
+	if _, ok := job.Spec.Template.Labels["app.kubernetes.io/instance"]; ok {
+		t.Fatalf("expected the MLCP pod not to carry the selector labels, got %v", job.Spec.Template.Labels)
+	}
+}
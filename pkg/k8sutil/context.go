@@ -68,8 +68,8 @@ func CreateOperatorContext(
 		return nil, err
 	}
 	oc.MarklogicGroup = mlg
-	oc.SetOperatorLabels(oc.MarklogicGroup.GetLabels())
-	oc.SetOperatorAnnotations(oc.MarklogicGroup.GetAnnotations())
+	oc.SetOperatorLabels(mergeMetadata(oc.MarklogicGroup.GetLabels(), mlg.Spec.AdditionalLabels))
+	oc.SetOperatorAnnotations(mergeMetadata(oc.MarklogicGroup.GetAnnotations(), mlg.Spec.AdditionalAnnotations))
 
 	oc.ReqLogger.Info("==== CreateOperatorContext")
 
@@ -102,8 +102,8 @@ func CreateClusterContext(
 		return nil, err
 	}
 	cc.MarklogicCluster = mlc
-	cc.SetClusterLabels(cc.MarklogicCluster.GetLabels())
-	cc.SetClusterAnnotations(cc.MarklogicCluster.GetAnnotations())
+	cc.SetClusterLabels(mergeMetadata(cc.MarklogicCluster.GetLabels(), mlc.Spec.AdditionalLabels))
+	cc.SetClusterAnnotations(mergeMetadata(cc.MarklogicCluster.GetAnnotations(), mlc.Spec.AdditionalAnnotations))
 	cc.ReqLogger.Info("==== CreateOperatorContext")
 
 	// cc.ReqLogger = cc.ReqLogger.WithValues("ML server name")
@@ -179,7 +179,7 @@ func (cc *ClusterContext) GetHAProxyLabels(name string) map[string]string {
 }
 
 func (cc *ClusterContext) GetClusterAnnotations() map[string]string {
-	return cc.Annotations
+	return mergeMetadata(cc.Annotations, nil)
 }
 
 func (cc *ClusterContext) SetClusterLabels(labels map[string]string) {
@@ -0,0 +1,251 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	defaultDataImportBackoffLimit = 3
+	// dataImportCheckInterval is how often a pending or running import is
+	// checked, as the cluster does not watch Jobs.
+	dataImportCheckInterval = 30 * time.Second
+
+	dataImportReasonStarted   = "DataImportStarted"
+	dataImportReasonSucceeded = "DataImportSucceeded"
+	dataImportReasonFailed    = "DataImportFailed"
+)
+
+// ReconcileDataImport runs the data import of spec.bootstrap once all groups
+// of the cluster are ready. The import runs as a Job owned by the cluster and
+// its progress is reported in status.bootstrap.dataImport. An import that
+// succeeded or failed is not run again unless its spec changes. Failures to
+// reach the API are logged and never hold up the rest of the reconcile.
+func (cc *ClusterContext) ReconcileDataImport() result.ReconcileResult {
+	cr := cc.MarklogicCluster
+	if cr.Spec.Bootstrap == nil || cr.Spec.Bootstrap.DataImport == nil {
+		return result.Continue()
+	}
+	spec := cr.Spec.Bootstrap.DataImport
+	hash := dataImportHash(spec)
+	var current *marklogicv1.DataImportStatus
+	if cr.Status.Bootstrap != nil && cr.Status.Bootstrap.DataImport != nil && cr.Status.Bootstrap.DataImport.SpecHash == hash {
+		current = cr.Status.Bootstrap.DataImport
+	}
+	if current != nil && (current.Phase == marklogicv1.DataImportSucceeded || current.Phase == marklogicv1.DataImportFailed) {
+		return result.Continue()
+	}
+	if clusterStopped(cr) {
+		return result.Continue()
+	}
+
+	status := &marklogicv1.DataImportStatus{Phase: marklogicv1.DataImportPending, SpecHash: hash}
+	if current != nil {
+		status = current.DeepCopy()
+	}
+	switch status.Phase {
+	case marklogicv1.DataImportPending:
+		waiting, err := cc.groupsNotReady(false)
+		if err != nil {
+			cc.ReqLogger.Error(err, "Failed to check the groups for the data import")
+			return result.Continue()
+		}
+		if waiting != "" {
+			status.Message = "waiting for the groups to be ready: " + waiting
+			break
+		}
+		job, err := cc.dataImportJob(spec, hash)
+		if err != nil {
+			cc.ReqLogger.Error(err, "Failed to build the data import Job")
+			status.Message = err.Error()
+			break
+		}
+		if err := cc.Client.Create(cc.Ctx, job); err != nil && !apierrors.IsAlreadyExists(err) {
+			cc.ReqLogger.Error(err, "Failed to create the data import Job")
+			status.Message = err.Error()
+			break
+		}
+		now := metav1.Now()
+		status.Phase, status.JobName, status.StartTime, status.Message = marklogicv1.DataImportRunning, job.Name, &now, ""
+		cc.recordClusterEvent(corev1.EventTypeNormal, dataImportReasonStarted,
+			fmt.Sprintf("Started Job %s importing %s into database %s", job.Name, spec.SourceURI, spec.Database))
+	case marklogicv1.DataImportRunning:
+		job := &batchv1.Job{}
+		err := cc.Client.Get(cc.Ctx, types.NamespacedName{Namespace: cr.Namespace, Name: status.JobName}, job)
+		switch {
+		case apierrors.IsNotFound(err):
+			cc.finishDataImport(status, marklogicv1.DataImportFailed, fmt.Sprintf("Job %s was deleted before it completed", status.JobName))
+		case err != nil:
+			cc.ReqLogger.Error(err, "Failed to read the data import Job")
+			return result.Continue()
+		case job.Status.Succeeded > 0:
+			cc.finishDataImport(status, marklogicv1.DataImportSucceeded, "")
+		case jobFailed(job):
+			cc.finishDataImport(status, marklogicv1.DataImportFailed, fmt.Sprintf("Job %s failed: %s", status.JobName, jobFailureMessage(job)))
+		}
+	}
+	if cr.Status.Bootstrap == nil || !reflect.DeepEqual(cr.Status.Bootstrap.DataImport, status) {
+		patchBase := client.MergeFrom(cr.DeepCopy())
+		cr.Status.Bootstrap = &marklogicv1.BootstrapStatus{DataImport: status}
+		if err := patchStatus(cc.Ctx, cc.Client, cr, patchBase); err != nil {
+			cc.ReqLogger.Error(err, "Failed to update the data import status")
+		}
+	}
+	return result.Continue()
+}
+
+// finishDataImport records the end of the import in status and as an event.
+func (cc *ClusterContext) finishDataImport(status *marklogicv1.DataImportStatus, phase marklogicv1.DataImportPhase, message string) {
+	now := metav1.Now()
+	status.Phase, status.CompletionTime, status.Message = phase, &now, message
+	if phase == marklogicv1.DataImportSucceeded {
+		cc.ReqLogger.Info("The data import succeeded", "job", status.JobName)
+		cc.recordClusterEvent(corev1.EventTypeNormal, dataImportReasonSucceeded, fmt.Sprintf("Job %s imported the data", status.JobName))
+		return
+	}
+	cc.ReqLogger.Info("The data import failed", "job", status.JobName, "message", message)
+	cc.recordClusterEvent(corev1.EventTypeWarning, dataImportReasonFailed, message)
+}
+
+// dataImportJob returns the Job that runs the import tool against the
+// bootstrap host. The admin credentials are expanded from environment
+// variables by Kubernetes, so they never appear in the Job spec.
+func (cc *ClusterContext) dataImportJob(spec *marklogicv1.DataImport, hash string) (*batchv1.Job, error) {
+	cr := cc.MarklogicCluster
+	host, err := cc.bootstrapHostFQDN()
+	if err != nil {
+		return nil, err
+	}
+	useTLS := defaultAppServersTLS(cr)
+	var command []string
+	switch spec.Tool {
+	case marklogicv1.DataImportMLGradle:
+		command = []string{"gradle", "mlLoadData",
+			"-PmlHost=" + host,
+			"-PmlUsername=$(ML_USERNAME)",
+			"-PmlPassword=$(ML_PASSWORD)",
+			"-PmlDataPaths=" + spec.SourceURI,
+			"-PmlDataDatabaseName=" + spec.Database,
+		}
+		if useTLS {
+			command = append(command, "-PmlSimpleSsl=true")
+		}
+	default:
+		command = []string{"mlcp.sh", "import",
+			"-host", host,
+			"-port", "8000",
+			"-username", "$(ML_USERNAME)",
+			"-password", "$(ML_PASSWORD)",
+			"-database", spec.Database,
+			"-input_file_path", spec.SourceURI,
+			"-mode", "local",
+		}
+		if useTLS {
+			command = append(command, "-ssl", "true")
+		}
+	}
+	command = append(command, spec.Args...)
+
+	secretKey := func(key string) *corev1.EnvVarSource {
+		return &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: cc.adminSecretName()},
+			Key:                  key,
+		}}
+	}
+	container := corev1.Container{
+		Name:            "data-import",
+		Image:           spec.Image,
+		ImagePullPolicy: spec.ImagePullPolicy,
+		Command:         command,
+		Env: []corev1.EnvVar{
+			{Name: "ML_USERNAME", ValueFrom: secretKey("username")},
+			{Name: "ML_PASSWORD", ValueFrom: secretKey("password")},
+		},
+	}
+	if spec.EnvSecretName != "" {
+		container.EnvFrom = []corev1.EnvFromSource{{SecretRef: &corev1.SecretEnvSource{
+			LocalObjectReference: corev1.LocalObjectReference{Name: spec.EnvSecretName},
+		}}}
+	}
+	if spec.Resources != nil {
+		container.Resources = *spec.Resources
+	}
+	backoffLimit := int32(defaultDataImportBackoffLimit)
+	if spec.BackoffLimit != nil {
+		backoffLimit = *spec.BackoffLimit
+	}
+	labels := withClusterNameLabel(cc.GetClusterLabels(cr.Name), cr.Name)
+	labels["marklogic.progress.com/task"] = "data-import"
+	// The pod does not get the selector labels of the cluster, so no Service
+	// or PodDisruptionBudget of a group selects it.
+	podLabels := map[string]string{ClusterNameLabel: cr.Name, "marklogic.progress.com/task": "data-import"}
+	job := &batchv1.Job{
+		ObjectMeta: generateObjectMeta(dataImportJobName(cr, hash), cr.Namespace, labels, cc.GetClusterAnnotations()),
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: podLabels},
+				Spec: corev1.PodSpec{
+					RestartPolicy:                corev1.RestartPolicyNever,
+					ImagePullSecrets:             cr.Spec.ImagePullSecrets,
+					AutomountServiceAccountToken: boolPtr(false),
+					Containers:                   []corev1.Container{container},
+				},
+			},
+		},
+	}
+	if cr.Spec.RestrictedPodSecurity {
+		restrictPodSpec(&job.Spec.Template.Spec)
+	}
+	AddOwnerRefToObject(job, marklogicClusterAsOwner(cr))
+	return job, nil
+}
+
+func dataImportJobName(cr *marklogicv1.MarklogicCluster, hash string) string {
+	return clusterFullname(cr) + "-data-import-" + hash
+}
+
+// dataImportHash identifies a data import spec.
+func dataImportHash(spec *marklogicv1.DataImport) string {
+	data, _ := json.Marshal(spec)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:10]
+}
+
+// jobFailureMessage returns the message of the Failed condition of a Job.
+func jobFailureMessage(job *batchv1.Job) string {
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+			return condition.Message
+		}
+	}
+	return ""
+}
+
+// nextDataImportCheck is when a pending or running data import is checked
+// again, or the zero time when there is none.
+func nextDataImportCheck(cr *marklogicv1.MarklogicCluster) time.Time {
+	if cr.Spec.Bootstrap == nil || cr.Spec.Bootstrap.DataImport == nil {
+		return time.Time{}
+	}
+	if status := cr.Status.Bootstrap; status != nil && status.DataImport != nil && status.DataImport.SpecHash == dataImportHash(cr.Spec.Bootstrap.DataImport) &&
+		(status.DataImport.Phase == marklogicv1.DataImportSucceeded || status.DataImport.Phase == marklogicv1.DataImportFailed) {
+		return time.Time{}
+	}
+	return time.Now().Add(dataImportCheckInterval)
+}
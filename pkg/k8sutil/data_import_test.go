@@ -0,0 +1,114 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestReconcileDataImportRunsOnceTheGroupsAreReady(t *testing.T) {
+	replicas := int32(1)
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default", UID: "ml-uid"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			ClusterDomain:   "cluster.local",
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{{Name: "dnode", Replicas: &replicas, IsBootstrap: true}},
+			Bootstrap: &marklogicv1.Bootstrap{DataImport: &marklogicv1.DataImport{
+				Tool:          marklogicv1.DataImportMLCP,
+				Image:         "example.com/mlcp:11",
+				SourceURI:     "/data/seed",
+				Database:      "Documents",
+				Args:          []string{"-output_collections", "seed"},
+				EnvSecretName: "seed-credentials",
+			}},
+		},
+	}
+	sts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "dnode", Namespace: "default"},
+		Spec:       appsv1.StatefulSetSpec{Replicas: &replicas},
+	}
+	cc := newUpgradeTestContext(t, cr, sts)
+	recorder := cc.Recorder.(*record.FakeRecorder)
+
+	// The import waits for the groups.
+	cc.ReconcileDataImport()
+	status := cr.Status.Bootstrap.DataImport
+	if status.Phase != marklogicv1.DataImportPending || !strings.Contains(status.Message, "dnode 0/1") {
+		t.Fatalf("expected the import to wait for the groups, got %+v", status)
+	}
+	if next := nextDataImportCheck(cr); next.IsZero() {
+		t.Fatalf("expected the pending import to be checked again")
+	}
+
+	sts.Status.ReadyReplicas = 1
+	if err := cc.Client.Status().Update(cc.Ctx, sts); err != nil {
+		t.Fatalf("failed to update StatefulSet: %v", err)
+	}
+	cc.ReconcileDataImport()
+	status = cr.Status.Bootstrap.DataImport
+	if status.Phase != marklogicv1.DataImportRunning || status.StartTime == nil {
+		t.Fatalf("expected the import to run, got %+v", status)
+	}
+	job := &batchv1.Job{}
+	if err := cc.Client.Get(cc.Ctx, types.NamespacedName{Namespace: "default", Name: status.JobName}, job); err != nil {
+		t.Fatalf("expected the import Job: %v", err)
+	}
+	container := job.Spec.Template.Spec.Containers[0]
+	command := strings.Join(container.Command, " ")
+	if !strings.HasPrefix(command, "mlcp.sh import -host dnode-0.dnode.default.svc.cluster.local") ||
+		!strings.Contains(command, "-password $(ML_PASSWORD)") || !strings.Contains(command, "-database Documents") ||
+		!strings.Contains(command, "-input_file_path /data/seed") || !strings.HasSuffix(command, "-output_collections seed") {
+		t.Fatalf("unexpected command %q", command)
+	}
+	if container.EnvFrom[0].SecretRef.Name != "seed-credentials" ||
+		!slices.ContainsFunc(container.Env, func(env corev1.EnvVar) bool {
+			return env.Name == "ML_PASSWORD" && env.ValueFrom.SecretKeyRef.Name == "ml-admin"
+		}) {
+		t.Fatalf("unexpected environment %+v %+v", container.Env, container.EnvFrom)
+	}
+	if _, ok := job.Spec.Template.Labels["app.kubernetes.io/instance"]; ok {
+		t.Fatalf("expected the import pod not to carry the selector labels, got %v", job.Spec.Template.Labels)
+	}
+
+	job.Status.Succeeded = 1
+	if err := cc.Client.Status().Update(cc.Ctx, job); err != nil {
+		t.Fatalf("failed to update Job: %v", err)
+	}
+	cc.ReconcileDataImport()
+	if status := cr.Status.Bootstrap.DataImport; status.Phase != marklogicv1.DataImportSucceeded || status.CompletionTime == nil {
+		t.Fatalf("expected the import to succeed, got %+v", status)
+	}
+	if next := nextDataImportCheck(cr); !next.IsZero() {
+		t.Fatalf("expected no further check, got %v", next)
+	}
+	started, succeeded := 0, 0
+	for len(recorder.Events) > 0 {
+		event := <-recorder.Events
+		if strings.Contains(event, dataImportReasonStarted) {
+			started++
+		}
+		if strings.Contains(event, dataImportReasonSucceeded) {
+			succeeded++
+		}
+	}
+	if started != 1 || succeeded != 1 {
+		t.Fatalf("expected one started and one succeeded event, got %d and %d", started, succeeded)
+	}
+
+	// A changed spec runs a new import.
+	cr.Spec.Bootstrap.DataImport.Database = "Seed"
+	cc.ReconcileDataImport()
+	if status := cr.Status.Bootstrap.DataImport; status.Phase != marklogicv1.DataImportRunning || status.JobName == job.Name {
+		t.Fatalf("expected a new import, got %+v", status)
+	}
+}
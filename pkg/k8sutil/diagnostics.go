@@ -0,0 +1,252 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	diagnosticsReasonApplied = "DiagnosticsApplied"
+	diagnosticsReasonExpired = "DiagnosticsExpired"
+	diagnosticsReasonRemoved = "DiagnosticsRemoved"
+)
+
+// ReconcileDiagnostics applies the trace events and the file log level of the
+// groups with diagnostics in MarkLogic. The settings of the group before are
+// recorded in status.diagnostics and restored when expiresAfter has passed or
+// the diagnostics are removed from the spec. Changing the diagnostics applies
+// them again with a new expiry. Failures are retried on a later reconcile and
+// never hold up the rest of it.
+func (cc *ClusterContext) ReconcileDiagnostics() result.ReconcileResult {
+	return cc.reconcileDiagnostics(time.Now())
+}
+
+func (cc *ClusterContext) reconcileDiagnostics(now time.Time) result.ReconcileResult {
+	cr := cc.MarklogicCluster
+	if clusterStopped(cr) {
+		return result.Continue()
+	}
+	recorded := map[string]*marklogicv1.DiagnosticsStatus{}
+	for i := range cr.Status.Diagnostics {
+		recorded[cr.Status.Diagnostics[i].Group] = &cr.Status.Diagnostics[i]
+	}
+	groupNames := map[string]string{}
+	for _, group := range cr.Spec.MarkLogicGroups {
+		if group == nil {
+			continue
+		}
+		groupNames[group.Name] = group.Name
+		if group.GroupConfig != nil && strings.TrimSpace(group.GroupConfig.Name) != "" {
+			groupNames[group.Name] = group.GroupConfig.Name
+		}
+	}
+
+	var mgmtClient mlmanage.Client
+	managementClient := func() (mlmanage.Client, error) {
+		if mgmtClient != nil {
+			return mgmtClient, nil
+		}
+		var err error
+		mgmtClient, err = cc.newBootstrapManagementClient()
+		return mgmtClient, err
+	}
+
+	statuses := []marklogicv1.DiagnosticsStatus{}
+	for _, group := range cr.Spec.MarkLogicGroups {
+		if group == nil || group.Diagnostics == nil {
+			continue
+		}
+		status := recorded[group.Name]
+		delete(recorded, group.Name)
+		next, err := cc.applyDiagnostics(managementClient, group.Name, groupNames[group.Name], group.Diagnostics, status, now)
+		if err != nil {
+			cc.ReqLogger.Error(err, "Failed to apply the diagnostics of the group", "group", group.Name)
+			if status != nil {
+				statuses = append(statuses, *status)
+			}
+			continue
+		}
+		statuses = append(statuses, *next)
+	}
+	for _, status := range cr.Status.Diagnostics {
+		if recorded[status.Group] == nil {
+			continue
+		}
+		groupName, ok := groupNames[status.Group]
+		if !ok || status.Expired {
+			continue
+		}
+		if err := cc.restoreDiagnostics(managementClient, groupName, status.Previous); err != nil {
+			cc.ReqLogger.Error(err, "Failed to remove the diagnostics of the group", "group", status.Group)
+			statuses = append(statuses, status)
+			continue
+		}
+		cc.ReqLogger.Info("Removed the diagnostics of the group", "group", status.Group)
+		cc.recordClusterEvent(corev1.EventTypeNormal, diagnosticsReasonRemoved, fmt.Sprintf("Restored the trace events and the file log level of group %s", status.Group))
+	}
+
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Group < statuses[j].Group })
+	if len(statuses) == 0 {
+		statuses = nil
+	}
+	if equality.Semantic.DeepEqual(cr.Status.Diagnostics, statuses) {
+		return result.Continue()
+	}
+	patchBase := client.MergeFrom(cr.DeepCopy())
+	cr.Status.Diagnostics = statuses
+	if err := patchStatus(cc.Ctx, cc.Client, cr, patchBase); err != nil {
+		cc.ReqLogger.Error(err, "Failed to update the diagnostics in the cluster status")
+	}
+	return result.Continue()
+}
+
+// applyDiagnostics applies new or changed diagnostics of a group, and
+// restores its previous settings once they expire. It returns the status of
+// the diagnostics of the group.
+func (cc *ClusterContext) applyDiagnostics(managementClient func() (mlmanage.Client, error), name, groupName string, diagnostics *marklogicv1.Diagnostics, status *marklogicv1.DiagnosticsStatus, now time.Time) (*marklogicv1.DiagnosticsStatus, error) {
+	if status != nil && sameDiagnostics(&status.Applied, diagnostics) {
+		next := status.DeepCopy()
+		next.Applied = *diagnostics.DeepCopy()
+		next.ExpiresAt = diagnosticsExpiry(status.AppliedAt.Time, diagnostics)
+		expired := next.ExpiresAt != nil && !now.Before(next.ExpiresAt.Time)
+		switch {
+		case expired && !status.Expired:
+			if err := cc.restoreDiagnostics(managementClient, groupName, status.Previous); err != nil {
+				return nil, err
+			}
+			cc.ReqLogger.Info("The diagnostics of the group expired", "group", name)
+			cc.recordClusterEvent(corev1.EventTypeNormal, diagnosticsReasonExpired, fmt.Sprintf("The diagnostics of group %s expired, its trace events and file log level were restored", name))
+		case !expired && status.Expired:
+			// expiresAfter was extended past the current time.
+			if err := cc.setDiagnostics(managementClient, groupName, diagnosticSettings(diagnostics, status.Previous)); err != nil {
+				return nil, err
+			}
+			cc.recordDiagnosticsApplied(name, next.ExpiresAt)
+		}
+		next.Expired = expired
+		return next, nil
+	}
+
+	mgmt, err := managementClient()
+	if err != nil {
+		return nil, err
+	}
+	var previous marklogicv1.DiagnosticSettings
+	if status != nil && !status.Expired {
+		// The group still runs with the diagnostics applied before.
+		previous = *status.Previous.DeepCopy()
+	} else {
+		current, err := mgmt.GetGroupDiagnostics(cc.Ctx, groupName)
+		if err != nil {
+			return nil, err
+		}
+		previous = marklogicv1.DiagnosticSettings{EventsActivated: current.EventsActivated, TraceEvents: current.Events, FileLogLevel: current.FileLogLevel}
+	}
+	if err := mgmt.SetGroupDiagnostics(cc.Ctx, groupName, diagnosticSettings(diagnostics, previous)); err != nil {
+		return nil, err
+	}
+	next := &marklogicv1.DiagnosticsStatus{
+		Group:     name,
+		Applied:   *diagnostics.DeepCopy(),
+		Previous:  previous,
+		AppliedAt: metav1.NewTime(now),
+		ExpiresAt: diagnosticsExpiry(now, diagnostics),
+	}
+	cc.recordDiagnosticsApplied(name, next.ExpiresAt)
+	return next, nil
+}
+
+func (cc *ClusterContext) setDiagnostics(managementClient func() (mlmanage.Client, error), groupName string, settings mlmanage.GroupDiagnostics) error {
+	mgmt, err := managementClient()
+	if err != nil {
+		return err
+	}
+	return mgmt.SetGroupDiagnostics(cc.Ctx, groupName, settings)
+}
+
+func (cc *ClusterContext) restoreDiagnostics(managementClient func() (mlmanage.Client, error), groupName string, previous marklogicv1.DiagnosticSettings) error {
+	return cc.setDiagnostics(managementClient, groupName, mlmanage.GroupDiagnostics{
+		EventsActivated: previous.EventsActivated,
+		Events:          previous.TraceEvents,
+		FileLogLevel:    previous.FileLogLevel,
+	})
+}
+
+func (cc *ClusterContext) recordDiagnosticsApplied(name string, expiresAt *metav1.Time) {
+	message := fmt.Sprintf("Applied the diagnostics of group %s", name)
+	if expiresAt != nil {
+		message += fmt.Sprintf(" until %s", expiresAt.UTC().Format(time.RFC3339))
+	}
+	cc.ReqLogger.Info("Applied the diagnostics of the group", "group", name)
+	cc.recordClusterEvent(corev1.EventTypeNormal, diagnosticsReasonApplied, message)
+}
+
+// diagnosticSettings are the settings of a group with the diagnostics
+// applied. The trace events of the group are kept, and the file log level is
+// left alone unless the diagnostics set one.
+func diagnosticSettings(diagnostics *marklogicv1.Diagnostics, previous marklogicv1.DiagnosticSettings) mlmanage.GroupDiagnostics {
+	settings := mlmanage.GroupDiagnostics{
+		EventsActivated: previous.EventsActivated,
+		Events:          slices.Clone(previous.TraceEvents),
+		FileLogLevel:    diagnostics.FileLogLevel,
+	}
+	if len(diagnostics.TraceEvents) > 0 {
+		settings.EventsActivated = true
+		for _, event := range diagnostics.TraceEvents {
+			if !slices.Contains(settings.Events, event) {
+				settings.Events = append(settings.Events, event)
+			}
+		}
+	}
+	return settings
+}
+
+// sameDiagnostics reports whether the diagnostics set the same trace events
+// and file log level. A change of expiresAfter alone moves the expiry.
+func sameDiagnostics(applied, diagnostics *marklogicv1.Diagnostics) bool {
+	if applied.FileLogLevel != diagnostics.FileLogLevel || len(applied.TraceEvents) != len(diagnostics.TraceEvents) {
+		return false
+	}
+	for _, event := range diagnostics.TraceEvents {
+		if !slices.Contains(applied.TraceEvents, event) {
+			return false
+		}
+	}
+	return true
+}
+
+func diagnosticsExpiry(appliedAt time.Time, diagnostics *marklogicv1.Diagnostics) *metav1.Time {
+	if diagnostics.ExpiresAfter == nil {
+		return nil
+	}
+	expiresAt := metav1.NewTime(appliedAt.Add(diagnostics.ExpiresAfter.Duration))
+	return &expiresAt
+}
+
+// nextDiagnosticsExpiry returns when the next diagnostics of the cluster
+// expire, or the zero time.
+func nextDiagnosticsExpiry(cr *marklogicv1.MarklogicCluster) time.Time {
+	var next time.Time
+	for _, status := range cr.Status.Diagnostics {
+		if status.Expired || status.ExpiresAt == nil {
+			continue
+		}
+		if next.IsZero() || status.ExpiresAt.Time.Before(next) {
+			next = status.ExpiresAt.Time
+		}
+	}
+	return next
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestReconcileDiagnosticsRestoresTheGroupWhenTheyExpire(t *testing.T) {
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			ClusterDomain: "cluster.local",
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{
+				{Name: "dnode", IsBootstrap: true, Diagnostics: &marklogicv1.Diagnostics{
+					TraceEvents:  []string{"SSL Handshake"},
+					FileLogLevel: "debug",
+					ExpiresAfter: &metav1.Duration{Duration: time.Hour},
+				}},
+				{Name: "enode"},
+			},
+		},
+	}
+	cc := newUpgradeTestContext(t, cr)
+	groups := map[string]mlmanage.GroupDiagnostics{
+		"dnode": {EventsActivated: true, Events: []string{"Merge"}, FileLogLevel: "info"},
+	}
+	original := NewDynamicManagementClient
+	NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+		return &stubDynamicManagementClient{
+			getDiagnosticsFn: func(groupName string) (mlmanage.GroupDiagnostics, error) {
+				return groups[groupName], nil
+			},
+			setDiagnosticsFn: func(groupName string, diagnostics mlmanage.GroupDiagnostics) error {
+				groups[groupName] = diagnostics
+				return nil
+			},
+		}
+	}
+	t.Cleanup(func() { NewDynamicManagementClient = original })
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	if res := cc.reconcileDiagnostics(now); res.Completed() {
+		t.Fatalf("expected the diagnostics never to hold up the reconcile")
+	}
+	dnode := groups["dnode"]
+	if !dnode.EventsActivated || !slices.Equal(dnode.Events, []string{"Merge", "SSL Handshake"}) || dnode.FileLogLevel != "debug" {
+		t.Fatalf("expected the trace events to be added and the log level raised, got %+v", dnode)
+	}
+	if _, ok := groups["enode"]; ok {
+		t.Fatalf("expected groups without diagnostics to be left alone")
+	}
+	status := cr.Status.Diagnostics
+	if len(status) != 1 || status[0].Previous.FileLogLevel != "info" || !status[0].ExpiresAt.Time.Equal(now.Add(time.Hour)) {
+		t.Fatalf("unexpected diagnostics status %+v", status)
+	}
+	if next := nextDiagnosticsExpiry(cr); !next.Equal(now.Add(time.Hour)) {
+		t.Fatalf("expected a reconcile at the expiry, got %s", next)
+	}
+
+	cc.reconcileDiagnostics(now.Add(time.Hour))
+	dnode = groups["dnode"]
+	if !slices.Equal(dnode.Events, []string{"Merge"}) || dnode.FileLogLevel != "info" || !cr.Status.Diagnostics[0].Expired {
+		t.Fatalf("expected the previous settings to be restored, got %+v", dnode)
+	}
+	if next := nextDiagnosticsExpiry(cr); !next.IsZero() {
+		t.Fatalf("expected no more reconciles for expired diagnostics, got %s", next)
+	}
+	groups["dnode"] = mlmanage.GroupDiagnostics{FileLogLevel: "notice"}
+	cc.reconcileDiagnostics(now.Add(2 * time.Hour))
+	if groups["dnode"].FileLogLevel != "notice" {
+		t.Fatalf("expected expired diagnostics not to be applied again")
+	}
+
+	cr.Spec.MarkLogicGroups[0].Diagnostics.FileLogLevel = "fine"
+	cc.reconcileDiagnostics(now.Add(2 * time.Hour))
+	if dnode = groups["dnode"]; dnode.FileLogLevel != "fine" || cr.Status.Diagnostics[0].Previous.FileLogLevel != "notice" {
+		t.Fatalf("expected changed diagnostics to apply again, got %+v", dnode)
+	}
+
+	cr.Spec.MarkLogicGroups[0].Diagnostics = nil
+	cc.reconcileDiagnostics(now.Add(2 * time.Hour))
+	if dnode = groups["dnode"]; dnode.FileLogLevel != "notice" || dnode.EventsActivated || cr.Status.Diagnostics != nil {
+		t.Fatalf("expected removed diagnostics to be restored, got %+v, status %+v", dnode, cr.Status.Diagnostics)
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	// disruptibleTerminationGracePeriod fits the 30 second notice GKE and
+	// AKS give before they reclaim a spot node.
+	disruptibleTerminationGracePeriod int64 = 25
+	disruptibleDrainDelay                   = 2 * time.Second
+	disruptibleDrainTimeout                 = 15 * time.Second
+)
+
+// disruptibleMaxUnavailable is how many pods of a group with
+// allowDisruptibleNodes evictions may take down at once. The pods hold no
+// forests, so a drain only waits for half of the group to keep serving.
+var disruptibleMaxUnavailable = intstr.FromString("50%")
+
+// disruptibleNodeTolerations tolerate the taints the clouds and Karpenter
+// put on spot and preemptible nodes.
+var disruptibleNodeTolerations = []corev1.Toleration{
+	{Key: "cloud.google.com/gke-spot", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+	{Key: "cloud.google.com/gke-preemptible", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+	{Key: "kubernetes.azure.com/scalesetpriority", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+	{Key: "karpenter.sh/capacity-type", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+	{Key: "eks.amazonaws.com/capacityType", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+}
+
+// applyDisruptibleNodes shortens the shutdown of the pods of a group with
+// allowDisruptibleNodes so it fits the notice of a preemption. It runs after
+// the group-level overrides, and a drain the group sets itself is kept.
+func applyDisruptibleNodes(params *MarkLogicGroupParameters, group *marklogicv1.MarklogicGroups) {
+	if !group.AllowDisruptibleNodes {
+		return
+	}
+	params.AllowDisruptibleNodes = true
+	if params.TerminationGracePeriodSeconds == nil || *params.TerminationGracePeriodSeconds > disruptibleTerminationGracePeriod {
+		grace := disruptibleTerminationGracePeriod
+		params.TerminationGracePeriodSeconds = &grace
+	}
+	if group.Drain == nil {
+		params.Drain = &marklogicv1.ShutdownDrain{
+			Enabled: true,
+			Delay:   &metav1.Duration{Duration: disruptibleDrainDelay},
+			Timeout: &metav1.Duration{Duration: disruptibleDrainTimeout},
+		}
+	}
+}
+
+// podTolerations returns the tolerations of the pods of a group.
+func podTolerations(allowDisruptibleNodes bool) []corev1.Toleration {
+	if !allowDisruptibleNodes {
+		return nil
+	}
+	return append([]corev1.Toleration(nil), disruptibleNodeTolerations...)
+}
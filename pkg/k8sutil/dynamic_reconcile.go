@@ -982,7 +982,7 @@ func (oc *OperatorContext) setDynamicStatusDetailed(phase, reason, message strin
 	}
 
 	oc.MarklogicGroup.Status.Dynamic = next
-	if err := oc.Client.Status().Patch(oc.Ctx, oc.MarklogicGroup, patch); err != nil {
+	if err := patchStatus(oc.Ctx, oc.Client, oc.MarklogicGroup, patch); err != nil {
 		return err
 	}
 	oc.emitDynamicLifecycleEvent(current, next)
@@ -2267,6 +2267,9 @@ func defaultDynamicPVCRestartCleanup(oc *OperatorContext, pod *corev1.Pod) (bool
 		},
 	}
 
+	if oc.MarklogicGroup.Spec.RestrictedPodSecurity {
+		restrictPodSpec(&cleanupJob.Spec.Template.Spec)
+	}
 	if err := controllerutil.SetControllerReference(oc.MarklogicGroup, cleanupJob, oc.Scheme); err != nil {
 		return false, err
 	}
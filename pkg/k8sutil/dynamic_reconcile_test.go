@@ -23,10 +23,47 @@ type stubDynamicManagementClient struct {
 	resolveNameFn       func() (string, error)
 	resolveCandidatesFn func() ([]string, error)
 	removeFn            func(clusterName, hostID string) error
+	setCredentialsFn    func(creds mlmanage.CloudCredentials) error
+	setS3DomainFn       func(groupName, domain string) error
+	setBackupsFn        func(database string, schedules []mlmanage.DatabaseBackupSchedule) error
+	backupStatusFn      func(database string) (mlmanage.DatabaseBackupStatus, error)
+	listForestsFn       func(database string) ([]string, error)
+	createForestFn      func(forest mlmanage.ForestSpec) (bool, error)
+	listPartitionsFn    func(database string) ([]mlmanage.Partition, error)
+	migratePartitionFn  func(database, partition, dataDirectory string) error
+	dbPropertiesFn      func(database string) (map[string]any, error)
+	createDatabaseFn    func(properties map[string]any) (bool, error)
+	replicationLagFn    func(database string) (time.Duration, error)
+	documentCountFn     func(forest string) (int64, error)
+	getThrottleFn       func(database string) (int32, error)
+	setThrottleFn       func(database string, throttle int32) error
+	startRestoreFn      func(database string, req mlmanage.DatabaseRestoreRequest) (string, error)
+	restoreStatusFn     func(database, jobID string) (mlmanage.DatabaseRestoreStatus, error)
+	hostsStatusFn       func() ([]mlmanage.HostStatus, error)
+	forestsStatusFn     func() ([]mlmanage.ForestStatus, error)
+	forestReplicasFn    func(forest string) ([]string, error)
+	restartForestFn     func(forest string) error
+	hostLicenseFn       func(hostName string) (mlmanage.HostLicense, error)
+	setHostZoneFn       func(hostName, zone string) error
+	setHostNameFn       func(hostName, newName string) error
+	getDiagnosticsFn    func(groupName string) (mlmanage.GroupDiagnostics, error)
+	setDiagnosticsFn    func(groupName string, diagnostics mlmanage.GroupDiagnostics) error
+	setServerPropsFn    func(groupName, serverName string, properties map[string]any) error
+	groupLoadFn         func(groupName string) (mlmanage.GroupLoad, error)
+	statusViewFn        func(resource string) ([]byte, error)
+	getGroupFn          func(groupName string) (mlmanage.GroupInfo, error)
+	upgradeSecurityFn   func() (bool, error)
+	ensureUserFn        func(username, password string) error
+	setPasswordFn       func(username, password string) error
+	sslFIPSEnabled      *bool
+	foreignClustersFn   func(clusterName string) ([]mlmanage.ForeignClusterStatus, error)
 }
 
 func (s *stubDynamicManagementClient) ListHostsStatus(ctx context.Context) ([]mlmanage.HostStatus, error) {
-	return nil, nil
+	if s.hostsStatusFn == nil {
+		return nil, nil
+	}
+	return s.hostsStatusFn()
 }
 
 func (s *stubDynamicManagementClient) GetHostGroupName(ctx context.Context, hostName string) (string, error) {
@@ -34,6 +71,9 @@ func (s *stubDynamicManagementClient) GetHostGroupName(ctx context.Context, host
 }
 
 func (s *stubDynamicManagementClient) GetGroup(ctx context.Context, groupName string) (mlmanage.GroupInfo, error) {
+	if s.getGroupFn != nil {
+		return s.getGroupFn(groupName)
+	}
 	return mlmanage.GroupInfo{}, nil
 }
 
@@ -50,7 +90,17 @@ func (s *stubDynamicManagementClient) EnableAdminAPITokenAuthentication(ctx cont
 }
 
 func (s *stubDynamicManagementClient) EnsureManageAdminUser(ctx context.Context, username, password string) error {
-	return nil
+	if s.ensureUserFn == nil {
+		return nil
+	}
+	return s.ensureUserFn(username, password)
+}
+
+func (s *stubDynamicManagementClient) SetUserPassword(ctx context.Context, username, password string) error {
+	if s.setPasswordFn == nil {
+		return nil
+	}
+	return s.setPasswordFn(username, password)
 }
 
 func (s *stubDynamicManagementClient) ResolveClusterName(ctx context.Context) (string, error) {
@@ -81,6 +131,224 @@ func (s *stubDynamicManagementClient) JoinDynamicHost(ctx context.Context, hostF
 	return s.joinFn(hostFQDN, token)
 }
 
+func (s *stubDynamicManagementClient) SetCloudCredentials(ctx context.Context, creds mlmanage.CloudCredentials) error {
+	if s.setCredentialsFn == nil {
+		return nil
+	}
+	return s.setCredentialsFn(creds)
+}
+
+func (s *stubDynamicManagementClient) SetGroupS3Domain(ctx context.Context, groupName, domain string) error {
+	if s.setS3DomainFn == nil {
+		return nil
+	}
+	return s.setS3DomainFn(groupName, domain)
+}
+
+func (s *stubDynamicManagementClient) SetDatabaseBackups(ctx context.Context, database string, schedules []mlmanage.DatabaseBackupSchedule) error {
+	if s.setBackupsFn == nil {
+		return nil
+	}
+	return s.setBackupsFn(database, schedules)
+}
+
+func (s *stubDynamicManagementClient) GetDatabaseBackupStatus(ctx context.Context, database string) (mlmanage.DatabaseBackupStatus, error) {
+	if s.backupStatusFn == nil {
+		return mlmanage.DatabaseBackupStatus{}, nil
+	}
+	return s.backupStatusFn(database)
+}
+
+func (s *stubDynamicManagementClient) ListDatabaseForests(ctx context.Context, database string) ([]string, error) {
+	if s.listForestsFn == nil {
+		return nil, errors.New("listForestsFn is not configured")
+	}
+	return s.listForestsFn(database)
+}
+
+func (s *stubDynamicManagementClient) CreateForest(ctx context.Context, forest mlmanage.ForestSpec) (bool, error) {
+	if s.createForestFn == nil {
+		return false, errors.New("createForestFn is not configured")
+	}
+	return s.createForestFn(forest)
+}
+
+func (s *stubDynamicManagementClient) ListDatabasePartitions(ctx context.Context, database string) ([]mlmanage.Partition, error) {
+	if s.listPartitionsFn == nil {
+		return nil, errors.New("listPartitionsFn is not configured")
+	}
+	return s.listPartitionsFn(database)
+}
+
+func (s *stubDynamicManagementClient) MigratePartition(ctx context.Context, database, partition, dataDirectory string) error {
+	if s.migratePartitionFn == nil {
+		return errors.New("migratePartitionFn is not configured")
+	}
+	return s.migratePartitionFn(database, partition, dataDirectory)
+}
+
+func (s *stubDynamicManagementClient) GetDatabaseProperties(ctx context.Context, database string) (map[string]any, error) {
+	if s.dbPropertiesFn == nil {
+		return nil, errors.New("dbPropertiesFn is not configured")
+	}
+	return s.dbPropertiesFn(database)
+}
+
+func (s *stubDynamicManagementClient) CreateDatabase(ctx context.Context, properties map[string]any) (bool, error) {
+	if s.createDatabaseFn == nil {
+		return false, errors.New("createDatabaseFn is not configured")
+	}
+	return s.createDatabaseFn(properties)
+}
+
+func (s *stubDynamicManagementClient) GetDatabaseReplicationLag(ctx context.Context, database string) (time.Duration, error) {
+	if s.replicationLagFn == nil {
+		return 0, errors.New("replicationLagFn is not configured")
+	}
+	return s.replicationLagFn(database)
+}
+
+func (s *stubDynamicManagementClient) GetForestDocumentCount(ctx context.Context, forest string) (int64, error) {
+	if s.documentCountFn == nil {
+		return 0, errors.New("documentCountFn is not configured")
+	}
+	return s.documentCountFn(forest)
+}
+
+func (s *stubDynamicManagementClient) GetDatabaseRebalancerThrottle(ctx context.Context, database string) (int32, error) {
+	if s.getThrottleFn == nil {
+		return 0, errors.New("getThrottleFn is not configured")
+	}
+	return s.getThrottleFn(database)
+}
+
+func (s *stubDynamicManagementClient) SetDatabaseRebalancerThrottle(ctx context.Context, database string, throttle int32) error {
+	if s.setThrottleFn == nil {
+		return errors.New("setThrottleFn is not configured")
+	}
+	return s.setThrottleFn(database, throttle)
+}
+
+func (s *stubDynamicManagementClient) StartDatabaseRestore(ctx context.Context, database string, req mlmanage.DatabaseRestoreRequest) (string, error) {
+	if s.startRestoreFn == nil {
+		return "", errors.New("startRestoreFn is not configured")
+	}
+	return s.startRestoreFn(database, req)
+}
+
+func (s *stubDynamicManagementClient) GetDatabaseRestoreStatus(ctx context.Context, database, jobID string) (mlmanage.DatabaseRestoreStatus, error) {
+	if s.restoreStatusFn == nil {
+		return mlmanage.DatabaseRestoreStatus{}, errors.New("restoreStatusFn is not configured")
+	}
+	return s.restoreStatusFn(database, jobID)
+}
+
+func (s *stubDynamicManagementClient) ListForestsStatus(ctx context.Context) ([]mlmanage.ForestStatus, error) {
+	if s.forestsStatusFn == nil {
+		return nil, errors.New("forestsStatusFn is not configured")
+	}
+	return s.forestsStatusFn()
+}
+
+func (s *stubDynamicManagementClient) ListForestReplicas(ctx context.Context, forest string) ([]string, error) {
+	if s.forestReplicasFn == nil {
+		return nil, errors.New("forestReplicasFn is not configured")
+	}
+	return s.forestReplicasFn(forest)
+}
+
+func (s *stubDynamicManagementClient) RestartForest(ctx context.Context, forest string) error {
+	if s.restartForestFn == nil {
+		return errors.New("restartForestFn is not configured")
+	}
+	return s.restartForestFn(forest)
+}
+
+func (s *stubDynamicManagementClient) GetHostLicense(ctx context.Context, hostName string) (mlmanage.HostLicense, error) {
+	if s.hostLicenseFn == nil {
+		return mlmanage.HostLicense{}, errors.New("hostLicenseFn is not configured")
+	}
+	return s.hostLicenseFn(hostName)
+}
+
+func (s *stubDynamicManagementClient) SetHostZone(ctx context.Context, hostName, zone string) error {
+	if s.setHostZoneFn == nil {
+		return errors.New("setHostZoneFn is not configured")
+	}
+	return s.setHostZoneFn(hostName, zone)
+}
+
+func (s *stubDynamicManagementClient) SetHostName(ctx context.Context, hostName, newName string) error {
+	if s.setHostNameFn == nil {
+		return errors.New("setHostNameFn is not configured")
+	}
+	return s.setHostNameFn(hostName, newName)
+}
+
+func (s *stubDynamicManagementClient) GetStatusView(ctx context.Context, resource string) ([]byte, error) {
+	if s.statusViewFn == nil {
+		return nil, errors.New("statusViewFn is not configured")
+	}
+	return s.statusViewFn(resource)
+}
+
+func (s *stubDynamicManagementClient) GetGroupDiagnostics(ctx context.Context, groupName string) (mlmanage.GroupDiagnostics, error) {
+	if s.getDiagnosticsFn == nil {
+		return mlmanage.GroupDiagnostics{}, errors.New("getDiagnosticsFn is not configured")
+	}
+	return s.getDiagnosticsFn(groupName)
+}
+
+func (s *stubDynamicManagementClient) SetGroupDiagnostics(ctx context.Context, groupName string, diagnostics mlmanage.GroupDiagnostics) error {
+	if s.setDiagnosticsFn == nil {
+		return errors.New("setDiagnosticsFn is not configured")
+	}
+	return s.setDiagnosticsFn(groupName, diagnostics)
+}
+
+func (s *stubDynamicManagementClient) SetAppServerProperties(ctx context.Context, groupName, serverName string, properties map[string]any) error {
+	if s.setServerPropsFn == nil {
+		return errors.New("setServerPropsFn is not configured")
+	}
+	return s.setServerPropsFn(groupName, serverName, properties)
+}
+
+func (s *stubDynamicManagementClient) GetGroupLoad(ctx context.Context, groupName string) (mlmanage.GroupLoad, error) {
+	if s.groupLoadFn == nil {
+		return mlmanage.GroupLoad{}, errors.New("groupLoadFn is not configured")
+	}
+	return s.groupLoadFn(groupName)
+}
+
+func (s *stubDynamicManagementClient) GetSSLFIPSEnabled(ctx context.Context) (bool, error) {
+	if s.sslFIPSEnabled == nil {
+		return false, errors.New("sslFIPSEnabled is not configured")
+	}
+	return *s.sslFIPSEnabled, nil
+}
+
+func (s *stubDynamicManagementClient) SetSSLFIPSEnabled(ctx context.Context, enabled bool) error {
+	if s.sslFIPSEnabled == nil {
+		return errors.New("sslFIPSEnabled is not configured")
+	}
+	*s.sslFIPSEnabled = enabled
+	return nil
+}
+
+func (s *stubDynamicManagementClient) ListForeignClustersStatus(ctx context.Context, clusterName string) ([]mlmanage.ForeignClusterStatus, error) {
+	if s.foreignClustersFn == nil {
+		return nil, errors.New("foreignClustersFn is not configured")
+	}
+	return s.foreignClustersFn(clusterName)
+}
+
+func (s *stubDynamicManagementClient) UpgradeSecurityDatabase(ctx context.Context) (bool, error) {
+	if s.upgradeSecurityFn == nil {
+		return false, errors.New("upgradeSecurityFn is not configured")
+	}
+	return s.upgradeSecurityFn()
+}
+
 func TestBuildDynamicHostStatusesClearsFailedStateWhenPodRecoveredAndOnline(t *testing.T) {
 	podCreation := metav1.NewTime(time.Now())
 	lastUpdated := metav1.NewTime(podCreation.Add(2 * time.Minute))
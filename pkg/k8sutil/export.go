@@ -0,0 +1,334 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// ExportAnnotation requests an export of the cluster into a bundle. The
+	// cluster is exported again whenever the value changes.
+	ExportAnnotation = "marklogic.progress.com/export"
+
+	// The keys of the export ConfigMap.
+	ExportClusterKey   = "marklogiccluster.yaml"
+	ExportSecretsKey   = "secrets.yaml"
+	ExportResourcesKey = "resources.yaml"
+
+	exportReasonExported = "ClusterExported"
+	exportReasonFailed   = "ClusterExportFailed"
+
+	lastAppliedConfigAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+)
+
+// ExportedSecret is a Secret the cluster depends on. Its values are not
+// exported: the Secret has to be copied to the new cluster separately.
+type ExportedSecret struct {
+	Name string   `json:"name"`
+	Keys []string `json:"keys,omitempty"`
+	// Generated is true for the Secrets the operator created, such as the
+	// admin credentials. Copy them to keep the credentials of the hosts.
+	Generated bool `json:"generated"`
+	// References are the fields of the spec that name the Secret.
+	References []string `json:"references,omitempty"`
+	Missing    bool     `json:"missing,omitempty"`
+}
+
+// ExportedResource is a resource the operator generated for the cluster, with
+// the version it was exported at.
+type ExportedResource struct {
+	Kind            string   `json:"kind"`
+	Name            string   `json:"name"`
+	ResourceVersion string   `json:"resourceVersion"`
+	Generation      int64    `json:"generation,omitempty"`
+	Images          []string `json:"images,omitempty"`
+}
+
+// ReconcileExport exports the cluster into the <cluster>-export ConfigMap when
+// the marklogic.progress.com/export annotation is set to a value that was not
+// exported yet. The bundle holds the MarklogicCluster ready to be applied in
+// another Kubernetes cluster, the Secrets it depends on without their values,
+// and the versions of the resources generated for it. A failed export is
+// reported in status.export and never holds up the rest of the reconcile.
+func (cc *ClusterContext) ReconcileExport() result.ReconcileResult {
+	cr := cc.MarklogicCluster
+	requestID := ClusterOperation(cr, ExportAnnotation)
+	if requestID == "" || (cr.Status.Export != nil && cr.Status.Export.RequestID == requestID) {
+		return result.Continue()
+	}
+
+	status := &marklogicv1.ExportStatus{RequestID: requestID}
+	configMapName, err := cc.exportCluster()
+	if err != nil {
+		cc.ReqLogger.Error(err, "Failed to export the cluster")
+		status.Message = err.Error()
+		cc.recordClusterEvent(corev1.EventTypeWarning, exportReasonFailed, fmt.Sprintf("Failed to export the cluster: %v", err))
+	} else {
+		now := metav1.Now()
+		status.ConfigMapName = configMapName
+		status.ExportTime = &now
+		cc.ReqLogger.Info("Exported the cluster", "configMap", configMapName)
+		cc.recordClusterEvent(corev1.EventTypeNormal, exportReasonExported, fmt.Sprintf("Exported the cluster to ConfigMap %s", configMapName))
+	}
+
+	patchBase := client.MergeFrom(cr.DeepCopy())
+	cr.Status.Export = status
+	if err := patchStatus(cc.Ctx, cc.Client, cr, patchBase); err != nil {
+		cc.ReqLogger.Error(err, "Failed to update the export status")
+	}
+	return result.Continue()
+}
+
+// exportCluster writes the bundle of the cluster to its export ConfigMap and
+// returns the name of the ConfigMap.
+func (cc *ClusterContext) exportCluster() (string, error) {
+	cr := cc.MarklogicCluster
+	name := clusterFullname(cr) + "-export"
+
+	manifest := &marklogicv1.MarklogicCluster{
+		TypeMeta: metav1.TypeMeta{APIVersion: marklogicv1.GroupVersion.String(), Kind: "MarklogicCluster"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cr.Name,
+			Namespace: cr.Namespace,
+			Labels:    cr.Labels,
+		},
+		Spec: *cr.Spec.DeepCopy(),
+	}
+	if manifest.Spec.Operations != nil {
+		// The export is not requested again where the bundle is applied.
+		manifest.Spec.Operations.Export = ""
+	}
+	for key, value := range cr.Annotations {
+		if key == ExportAnnotation || key == lastAppliedConfigAnnotation {
+			continue
+		}
+		if manifest.Annotations == nil {
+			manifest.Annotations = map[string]string{}
+		}
+		manifest.Annotations[key] = value
+	}
+	secrets, err := cc.exportedSecrets()
+	if err != nil {
+		return "", err
+	}
+	resources, err := cc.exportedResources(name)
+	if err != nil {
+		return "", err
+	}
+	data := map[string]string{}
+	for key, value := range map[string]any{ExportClusterKey: manifest, ExportSecretsKey: secrets, ExportResourcesKey: resources} {
+		out, err := yaml.Marshal(value)
+		if err != nil {
+			return "", err
+		}
+		data[key] = string(out)
+	}
+
+	labels := withClusterNameLabel(cc.GetClusterLabels(cr.Name), cr.Name)
+	configMap := &corev1.ConfigMap{}
+	err = cc.Client.Get(cc.Ctx, types.NamespacedName{Name: name, Namespace: cr.Namespace}, configMap)
+	if apierrors.IsNotFound(err) {
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: generateObjectMeta(name, cr.Namespace, labels, cc.GetClusterAnnotations()),
+			Data:       data,
+		}
+		AddOwnerRefToObject(configMap, marklogicClusterAsOwner(cr))
+		return name, cc.Client.Create(cc.Ctx, configMap)
+	}
+	if err != nil {
+		return "", err
+	}
+	configMap.Data = data
+	return name, cc.Client.Update(cc.Ctx, configMap)
+}
+
+// exportedSecrets returns the Secrets named in the spec of the cluster and
+// the Secrets the operator generated for it.
+func (cc *ClusterContext) exportedSecrets() ([]ExportedSecret, error) {
+	cr := cc.MarklogicCluster
+	spec, err := json.Marshal(cr.Spec)
+	if err != nil {
+		return nil, err
+	}
+	var fields any
+	if err := json.Unmarshal(spec, &fields); err != nil {
+		return nil, err
+	}
+	references := map[string][]string{}
+	collectSecretReferences("spec", fields, references)
+
+	list := &corev1.SecretList{}
+	if err := cc.Client.List(cc.Ctx, list, client.InNamespace(cr.Namespace)); err != nil {
+		return nil, err
+	}
+	owners := map[types.UID]bool{cr.UID: true}
+	secrets := map[string]*ExportedSecret{}
+	for i := range list.Items {
+		secret := &list.Items[i]
+		generated := ownedByAny(secret, owners)
+		if !generated && references[secret.Name] == nil {
+			continue
+		}
+		exported := &ExportedSecret{Name: secret.Name, Generated: generated, References: references[secret.Name]}
+		for key := range secret.Data {
+			exported.Keys = append(exported.Keys, key)
+		}
+		sort.Strings(exported.Keys)
+		secrets[secret.Name] = exported
+	}
+	for name, paths := range references {
+		if secrets[name] == nil {
+			secrets[name] = &ExportedSecret{Name: name, References: paths, Missing: true}
+		}
+	}
+	exported := []ExportedSecret{}
+	for _, secret := range secrets {
+		exported = append(exported, *secret)
+	}
+	sort.Slice(exported, func(i, j int) bool { return exported[i].Name < exported[j].Name })
+	return exported, nil
+}
+
+// collectSecretReferences walks the fields of a spec and records the fields
+// ending in secretName or secretNames by the Secret they name.
+func collectSecretReferences(path string, value any, references map[string][]string) {
+	switch value := value.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			field := path + "." + key
+			lower := strings.ToLower(key)
+			switch v := value[key].(type) {
+			case string:
+				if strings.HasSuffix(lower, "secretname") && v != "" {
+					references[v] = append(references[v], field)
+				}
+			case []any:
+				if strings.HasSuffix(lower, "secretnames") {
+					for _, item := range v {
+						if name, ok := item.(string); ok && name != "" {
+							references[name] = append(references[name], field)
+						}
+					}
+					continue
+				}
+				collectSecretReferences(field, v, references)
+			default:
+				collectSecretReferences(field, v, references)
+			}
+		}
+	case []any:
+		for i, item := range value {
+			collectSecretReferences(fmt.Sprintf("%s[%d]", path, i), item, references)
+		}
+	}
+}
+
+// exportedResources returns the resources owned by the cluster or by one of
+// its MarklogicGroups, except the export ConfigMap itself.
+func (cc *ClusterContext) exportedResources(exportName string) ([]ExportedResource, error) {
+	cr := cc.MarklogicCluster
+	inNamespace := client.InNamespace(cr.Namespace)
+	owners := map[types.UID]bool{cr.UID: true}
+	resources := []ExportedResource{}
+	add := func(kind string, obj metav1.Object, podSpec *corev1.PodSpec) {
+		if !ownedByAny(obj, owners) || (kind == "ConfigMap" && obj.GetName() == exportName) {
+			return
+		}
+		resource := ExportedResource{Kind: kind, Name: obj.GetName(), ResourceVersion: obj.GetResourceVersion(), Generation: obj.GetGeneration()}
+		if podSpec != nil {
+			for _, container := range podSpec.Containers {
+				resource.Images = append(resource.Images, container.Image)
+			}
+		}
+		resources = append(resources, resource)
+	}
+
+	groups := &marklogicv1.MarklogicGroupList{}
+	if err := cc.Client.List(cc.Ctx, groups, inNamespace); err != nil {
+		return nil, err
+	}
+	for i := range groups.Items {
+		if ownedByAny(&groups.Items[i], owners) {
+			owners[groups.Items[i].UID] = true
+			add("MarklogicGroup", &groups.Items[i], nil)
+		}
+	}
+	statefulSets := &appsv1.StatefulSetList{}
+	if err := cc.Client.List(cc.Ctx, statefulSets, inNamespace); err != nil {
+		return nil, err
+	}
+	for i := range statefulSets.Items {
+		add("StatefulSet", &statefulSets.Items[i], &statefulSets.Items[i].Spec.Template.Spec)
+	}
+	deployments := &appsv1.DeploymentList{}
+	if err := cc.Client.List(cc.Ctx, deployments, inNamespace); err != nil {
+		return nil, err
+	}
+	for i := range deployments.Items {
+		add("Deployment", &deployments.Items[i], &deployments.Items[i].Spec.Template.Spec)
+	}
+	services := &corev1.ServiceList{}
+	if err := cc.Client.List(cc.Ctx, services, inNamespace); err != nil {
+		return nil, err
+	}
+	for i := range services.Items {
+		add("Service", &services.Items[i], nil)
+	}
+	configMaps := &corev1.ConfigMapList{}
+	if err := cc.Client.List(cc.Ctx, configMaps, inNamespace); err != nil {
+		return nil, err
+	}
+	for i := range configMaps.Items {
+		add("ConfigMap", &configMaps.Items[i], nil)
+	}
+	secrets := &corev1.SecretList{}
+	if err := cc.Client.List(cc.Ctx, secrets, inNamespace); err != nil {
+		return nil, err
+	}
+	for i := range secrets.Items {
+		add("Secret", &secrets.Items[i], nil)
+	}
+	ingresses := &networkingv1.IngressList{}
+	if err := cc.Client.List(cc.Ctx, ingresses, inNamespace); err != nil {
+		return nil, err
+	}
+	for i := range ingresses.Items {
+		add("Ingress", &ingresses.Items[i], nil)
+	}
+	sort.SliceStable(resources, func(i, j int) bool {
+		if resources[i].Kind != resources[j].Kind {
+			return resources[i].Kind < resources[j].Kind
+		}
+		return resources[i].Name < resources[j].Name
+	})
+	return resources, nil
+}
+
+func ownedByAny(obj metav1.Object, owners map[types.UID]bool) bool {
+	for _, ref := range obj.GetOwnerReferences() {
+		if owners[ref.UID] {
+			return true
+		}
+	}
+	return false
+}
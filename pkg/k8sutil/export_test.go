@@ -0,0 +1,95 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"strings"
+	"testing"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/yaml"
+)
+
+func TestReconcileExportWritesTheBundle(t *testing.T) {
+	tlsSecret := "ml-tls"
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default", UID: "cluster-uid", Annotations: map[string]string{
+			ExportAnnotation:            "dr-1",
+			lastAppliedConfigAnnotation: "{}",
+			"example.com/team":          "analytics",
+		}},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			Image: "progressofficial/marklogic-db:12.0.0",
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{{Name: "dnode", IsBootstrap: true,
+				Tls: &marklogicv1.Tls{CertSecretNames: []string{tlsSecret}}}},
+		},
+	}
+	owner := func(uid types.UID) []metav1.OwnerReference {
+		return []metav1.OwnerReference{{APIVersion: "v1", Kind: "Owner", Name: "owner", UID: uid}}
+	}
+	group := &marklogicv1.MarklogicGroup{ObjectMeta: metav1.ObjectMeta{Name: "dnode", Namespace: "default", UID: "group-uid", OwnerReferences: owner("cluster-uid")}}
+	sts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "dnode", Namespace: "default", OwnerReferences: owner("group-uid")},
+		Spec: appsv1.StatefulSetSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "marklogic-server", Image: "progressofficial/marklogic-db:12.0.0"}},
+		}}},
+	}
+	generated := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "ml-manage-admin", Namespace: "default", OwnerReferences: owner("cluster-uid")},
+		Data: map[string][]byte{"username": []byte("u"), "password": []byte("p")}}
+	unrelated := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default"}}
+	cc := newUpgradeTestContext(t, cr, group, sts, generated, unrelated)
+
+	if res := cc.ReconcileExport(); res.Completed() {
+		t.Fatalf("expected the export never to hold up the reconcile")
+	}
+	status := cr.Status.Export
+	if status == nil || status.RequestID != "dr-1" || status.ConfigMapName != "ml-export" || status.ExportTime == nil || status.Message != "" {
+		t.Fatalf("expected the export in the status, got %+v", status)
+	}
+	bundle := &corev1.ConfigMap{}
+	if err := cc.Client.Get(cc.Ctx, types.NamespacedName{Name: "ml-export", Namespace: "default"}, bundle); err != nil {
+		t.Fatalf("expected the export ConfigMap, got %v", err)
+	}
+
+	manifest := &marklogicv1.MarklogicCluster{}
+	if err := yaml.Unmarshal([]byte(bundle.Data[ExportClusterKey]), manifest); err != nil {
+		t.Fatalf("failed to read the exported cluster: %v", err)
+	}
+	if manifest.Kind != "MarklogicCluster" || manifest.Spec.Image != cr.Spec.Image || manifest.UID != "" ||
+		len(manifest.Annotations) != 1 || manifest.Annotations["example.com/team"] != "analytics" {
+		t.Fatalf("expected a manifest ready to apply, got %+v", manifest.ObjectMeta)
+	}
+	secrets := []ExportedSecret{}
+	if err := yaml.Unmarshal([]byte(bundle.Data[ExportSecretsKey]), &secrets); err != nil {
+		t.Fatalf("failed to read the exported secrets: %v", err)
+	}
+	if len(secrets) != 2 || secrets[0].Name != "ml-manage-admin" || !secrets[0].Generated || strings.Join(secrets[0].Keys, ",") != "password,username" ||
+		secrets[1].Name != tlsSecret || !secrets[1].Missing || secrets[1].References[0] != "spec.markLogicGroups[0].tls.certSecretNames" {
+		t.Fatalf("unexpected secrets %+v", secrets)
+	}
+	resources := []ExportedResource{}
+	if err := yaml.Unmarshal([]byte(bundle.Data[ExportResourcesKey]), &resources); err != nil {
+		t.Fatalf("failed to read the exported resources: %v", err)
+	}
+	kinds := []string{}
+	for _, resource := range resources {
+		kinds = append(kinds, resource.Kind+"/"+resource.Name)
+	}
+	if strings.Join(kinds, ",") != "MarklogicGroup/dnode,Secret/ml-manage-admin,StatefulSet/dnode" || resources[2].Images[0] != cr.Spec.Image {
+		t.Fatalf("unexpected resources %v", resources)
+	}
+
+	// The same request is not exported again.
+	bundle.Data = nil
+	if err := cc.Client.Update(cc.Ctx, bundle); err != nil {
+		t.Fatalf("failed to clear the bundle: %v", err)
+	}
+	cc.ReconcileExport()
+	if err := cc.Client.Get(cc.Ctx, types.NamespacedName{Name: "ml-export", Namespace: "default"}, bundle); err != nil || bundle.Data != nil {
+		t.Fatalf("expected no new export, got %v", err)
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlversion"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// fipsCheckInterval is how often a cluster with spec.fipsMode is checked
+	// for drift, for example ssl-fips-enabled turned off in the Admin UI.
+	fipsCheckInterval = 10 * time.Minute
+	// fipsPendingInterval is how often the check is retried while MarkLogic
+	// cannot be reached.
+	fipsPendingInterval = 30 * time.Second
+
+	fipsReasonEnabled      = "FIPSEnabled"
+	fipsReasonNonCompliant = "FIPSNonCompliant"
+)
+
+// fipsHAProxyGlobal restricts the TLS of the HAProxy frontends and of the
+// connections to MarkLogic to TLS 1.2 and later with FIPS approved ciphers.
+const fipsHAProxyGlobal = `  ssl-default-bind-options ssl-min-ver TLSv1.2 no-tls-tickets
+  ssl-default-bind-ciphers ECDHE-ECDSA-AES128-GCM-SHA256:ECDHE-RSA-AES128-GCM-SHA256:ECDHE-ECDSA-AES256-GCM-SHA384:ECDHE-RSA-AES256-GCM-SHA384
+  ssl-default-bind-ciphersuites TLS_AES_128_GCM_SHA256:TLS_AES_256_GCM_SHA384
+  ssl-default-bind-curves P-256:P-384
+  ssl-default-server-options ssl-min-ver TLSv1.2 no-tls-tickets
+  ssl-default-server-ciphers ECDHE-ECDSA-AES128-GCM-SHA256:ECDHE-RSA-AES128-GCM-SHA256:ECDHE-ECDSA-AES256-GCM-SHA384:ECDHE-RSA-AES256-GCM-SHA384
+  ssl-default-server-ciphersuites TLS_AES_128_GCM_SHA256:TLS_AES_256_GCM_SHA384
+`
+
+// ReconcileFIPS checks a cluster with spec.fipsMode: every image must support
+// FIPS, and MarkLogic must have ssl-fips-enabled, which is set when it is
+// not. The result is reported in status.fips. The MarkLogic setting is left
+// alone while an image does not support FIPS, as the hosts could not serve
+// TLS with it.
+func (cc *ClusterContext) ReconcileFIPS() result.ReconcileResult {
+	cr := cc.MarklogicCluster
+	previous := cr.Status.FIPS
+	if !cr.Spec.FIPSMode {
+		if previous == nil {
+			return result.Continue()
+		}
+		patchBase := client.MergeFrom(cr.DeepCopy())
+		cr.Status.FIPS = nil
+		if err := patchStatus(cc.Ctx, cc.Client, cr, patchBase); err != nil {
+			return result.Error(err)
+		}
+		return result.Continue()
+	}
+	now := metav1.Now()
+	if previous != nil && previous.ObservedGeneration == cr.Generation && now.Time.Before(nextFIPSCheck(cr)) {
+		return result.Continue()
+	}
+
+	status := &marklogicv1.FIPSStatus{ObservedGeneration: cr.Generation, LastCheckTime: &now}
+	if previous != nil {
+		status.SSLFIPSEnabled = previous.SSLFIPSEnabled
+	}
+	if problems := fipsImageProblems(cr); len(problems) > 0 {
+		status.State = marklogicv1.FIPSStateNonCompliant
+		status.Message = strings.Join(problems, "; ")
+	} else if clusterStopped(cr) {
+		status.State = marklogicv1.FIPSStatePending
+		status.Message = "the cluster is stopped"
+	} else {
+		cc.applySSLFIPS(status)
+	}
+	if status.State == marklogicv1.FIPSStateNonCompliant && (previous == nil || previous.State != status.State || previous.Message != status.Message) {
+		cc.recordClusterEvent(corev1.EventTypeWarning, fipsReasonNonCompliant, status.Message)
+	}
+
+	patchBase := client.MergeFrom(cr.DeepCopy())
+	cr.Status.FIPS = status
+	if err := patchStatus(cc.Ctx, cc.Client, cr, patchBase); err != nil {
+		return result.Error(err)
+	}
+	return result.Continue()
+}
+
+// applySSLFIPS reads ssl-fips-enabled from MarkLogic and sets it when it is
+// off. MarkLogic restarts the hosts to apply it.
+func (cc *ClusterContext) applySSLFIPS(status *marklogicv1.FIPSStatus) {
+	mgmt, err := cc.newBootstrapManagementClient()
+	var enabled bool
+	if err == nil {
+		enabled, err = mgmt.GetSSLFIPSEnabled(cc.Ctx)
+	}
+	if err != nil {
+		status.State = marklogicv1.FIPSStatePending
+		status.Message = fmt.Sprintf("failed to read ssl-fips-enabled from MarkLogic: %v", err)
+		return
+	}
+	if !enabled {
+		if err := mgmt.SetSSLFIPSEnabled(cc.Ctx, true); err != nil {
+			status.State = marklogicv1.FIPSStateNonCompliant
+			status.SSLFIPSEnabled = false
+			status.Message = fmt.Sprintf("failed to set ssl-fips-enabled in MarkLogic: %v", err)
+			return
+		}
+		cc.ReqLogger.Info("Enabled ssl-fips-enabled in MarkLogic")
+		cc.recordClusterEvent(corev1.EventTypeNormal, fipsReasonEnabled, "ssl-fips-enabled is set in MarkLogic, the hosts restart to apply it")
+	}
+	status.State = marklogicv1.FIPSStateCompliant
+	status.SSLFIPSEnabled = true
+	status.Message = "MarkLogic runs with ssl-fips-enabled on images with FIPS support"
+}
+
+// fipsImageProblems lists the images of the cluster that do not support
+// FIPS: the Red Hat UBI based images of MarkLogic 11 and later ship a FIPS
+// validated OpenSSL provider, others do not. Images without a version tag
+// cannot be checked.
+func fipsImageProblems(cr *marklogicv1.MarklogicCluster) []string {
+	images := []string{cr.Spec.Image}
+	for _, group := range cr.Spec.MarkLogicGroups {
+		if group != nil && group.Image != "" {
+			images = append(images, group.Image)
+		}
+	}
+	problems := []string{}
+	seen := map[string]bool{}
+	for _, image := range images {
+		if seen[image] {
+			continue
+		}
+		seen[image] = true
+		if err := fipsImageSupported(image); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+	return problems
+}
+
+func fipsImageSupported(image string) error {
+	version, ok := mlversion.ParseImage(image)
+	if !ok {
+		return fmt.Errorf("the MarkLogic version of %s cannot be told from its tag", image)
+	}
+	if !mlversion.Supports(version, mlversion.FIPS) {
+		minimum, _ := mlversion.MinimumVersion(mlversion.FIPS)
+		return fmt.Errorf("%s does not support FIPS, MarkLogic %d or later is required", image, minimum.Major)
+	}
+	tag, _, _ := strings.Cut(image, "@")
+	if !strings.Contains(tag[strings.LastIndex(tag, ":")+1:], "ubi") {
+		return fmt.Errorf("%s does not support FIPS, a UBI based image is required", image)
+	}
+	return nil
+}
+
+// nextFIPSCheck is when a cluster with spec.fipsMode is checked next.
+func nextFIPSCheck(cr *marklogicv1.MarklogicCluster) time.Time {
+	status := cr.Status.FIPS
+	if !cr.Spec.FIPSMode || status == nil || status.LastCheckTime == nil {
+		return time.Time{}
+	}
+	if status.State == marklogicv1.FIPSStatePending {
+		return status.LastCheckTime.Add(fipsPendingInterval)
+	}
+	return status.LastCheckTime.Add(fipsCheckInterval)
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestReconcileFIPSEnablesSSLFIPS(t *testing.T) {
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default", Generation: 2},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			Image:           upgradeTestNewImage,
+			ClusterDomain:   "cluster.local",
+			FIPSMode:        true,
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{{Name: "dnode", IsBootstrap: true}},
+		},
+	}
+	cc := newUpgradeTestContext(t, cr)
+	enabled := false
+	original := NewDynamicManagementClient
+	NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+		return &stubDynamicManagementClient{sslFIPSEnabled: &enabled}
+	}
+	defer func() { NewDynamicManagementClient = original }()
+
+	if res := cc.ReconcileFIPS(); res.Completed() {
+		t.Fatalf("expected the reconcile to continue")
+	}
+	status := cc.MarklogicCluster.Status.FIPS
+	if !enabled || status == nil || status.State != marklogicv1.FIPSStateCompliant || !status.SSLFIPSEnabled || status.ObservedGeneration != 2 {
+		t.Fatalf("expected ssl-fips-enabled to be set and the cluster to be compliant, got %v %+v", enabled, status)
+	}
+
+	enabled = false
+	if cc.ReconcileFIPS(); enabled {
+		t.Fatalf("expected MarkLogic not to be checked again before the check interval")
+	}
+
+	cc.MarklogicCluster.Spec.FIPSMode = false
+	if cc.ReconcileFIPS(); cc.MarklogicCluster.Status.FIPS != nil {
+		t.Fatalf("expected the FIPS status to be cleared, got %+v", cc.MarklogicCluster.Status.FIPS)
+	}
+}
+
+func TestReconcileFIPSRejectsUnsupportedImages(t *testing.T) {
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			Image:         "progressofficial/marklogic-db:12.0.3-centos-1.1.2",
+			ClusterDomain: "cluster.local",
+			FIPSMode:      true,
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{
+				{Name: "dnode", IsBootstrap: true},
+				{Name: "enode", Image: "progressofficial/marklogic-db:10.0-10-ubi"},
+			},
+		},
+	}
+	cc := newUpgradeTestContext(t, cr)
+	original := NewDynamicManagementClient
+	NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+		t.Fatalf("expected MarkLogic to be left alone")
+		return nil
+	}
+	defer func() { NewDynamicManagementClient = original }()
+
+	cc.ReconcileFIPS()
+	status := cc.MarklogicCluster.Status.FIPS
+	if status == nil || status.State != marklogicv1.FIPSStateNonCompliant ||
+		!strings.Contains(status.Message, "a UBI based image is required") || !strings.Contains(status.Message, "MarkLogic 11 or later is required") {
+		t.Fatalf("expected both images to be rejected, got %+v", status)
+	}
+}
+
+func TestHAProxyConfigRestrictsCiphersInFIPSMode(t *testing.T) {
+	pathBased := false
+	replicas := int32(1)
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			ClusterDomain: "cluster.local",
+			HAProxy: &marklogicv1.HAProxy{
+				Enabled:          true,
+				PathBasedRouting: &pathBased,
+				AppServers:       []marklogicv1.AppServers{{Name: "app", Port: 8000}},
+			},
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{{Name: "dnode", Replicas: &replicas, IsBootstrap: true}},
+		},
+	}
+	if config := generateHAProxyConfigMapData(context.Background(), cr)["haproxy.cfg"]; strings.Contains(config, "ssl-default-bind-ciphers") {
+		t.Fatalf("expected no cipher restriction without fipsMode")
+	}
+	cr.Spec.FIPSMode = true
+	if config := generateHAProxyConfigMapData(context.Background(), cr)["haproxy.cfg"]; !strings.Contains(config, "ssl-default-bind-ciphers ECDHE-ECDSA-AES128-GCM-SHA256") {
+		t.Fatalf("expected the FIPS ciphers in the HAProxy configuration, got %s", config)
+	}
+}
@@ -0,0 +1,266 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	fluentBitHTTPPort     = 2020
+	fluentBitHTTPPortName = "fluentbit-http"
+
+	defaultFluentBitHealthErrorsCount       = 5
+	defaultFluentBitHealthRetryFailureCount = 5
+	defaultFluentBitHealthPeriodSeconds     = 60
+
+	// logCollectionMetricsInterval is how often the metrics of the fluent-bit
+	// sidecars are collected.
+	logCollectionMetricsInterval = time.Minute
+	fluentBitMetricsTimeout      = 5 * time.Second
+)
+
+var (
+	fluentBitMetricLabels = []string{"namespace", "cluster", "pod", "plugin"}
+
+	fluentBitInputRecords = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "marklogic_fluent_bit_input_records",
+		Help: "Records read by an input of the fluent-bit sidecar of a pod since fluent-bit started.",
+	}, fluentBitMetricLabels)
+	fluentBitInputBytes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "marklogic_fluent_bit_input_bytes",
+		Help: "Bytes read by an input of the fluent-bit sidecar of a pod since fluent-bit started.",
+	}, fluentBitMetricLabels)
+	fluentBitOutputRecords = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "marklogic_fluent_bit_output_records",
+		Help: "Records sent by an output of the fluent-bit sidecar of a pod since fluent-bit started.",
+	}, fluentBitMetricLabels)
+	fluentBitOutputErrors = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "marklogic_fluent_bit_output_errors",
+		Help: "Failed flushes of an output of the fluent-bit sidecar of a pod since fluent-bit started.",
+	}, fluentBitMetricLabels)
+	fluentBitOutputRetries = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "marklogic_fluent_bit_output_retries",
+		Help: "Retried flushes of an output of the fluent-bit sidecar of a pod since fluent-bit started.",
+	}, fluentBitMetricLabels)
+	fluentBitOutputRetriesFailed = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "marklogic_fluent_bit_output_retries_failed",
+		Help: "Flushes of an output of the fluent-bit sidecar of a pod that ran out of retries since fluent-bit started.",
+	}, fluentBitMetricLabels)
+	fluentBitOutputDroppedRecords = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "marklogic_fluent_bit_output_dropped_records",
+		Help: "Records dropped by an output of the fluent-bit sidecar of a pod since fluent-bit started.",
+	}, fluentBitMetricLabels)
+
+	fluentBitGauges = []*prometheus.GaugeVec{
+		fluentBitInputRecords, fluentBitInputBytes, fluentBitOutputRecords, fluentBitOutputErrors,
+		fluentBitOutputRetries, fluentBitOutputRetriesFailed, fluentBitOutputDroppedRecords,
+	}
+
+	// logCollectionMetricsCollected holds when the fluent-bit metrics of each
+	// cluster were collected last.
+	logCollectionMetricsCollected sync.Map
+)
+
+func init() {
+	for _, gauge := range fluentBitGauges {
+		metrics.Registry.MustRegister(gauge)
+	}
+}
+
+// fluentBitMetrics is the response of the /api/v1/metrics endpoint of
+// fluent-bit, keyed by the name of the plugin instance, such as tail.0.
+type fluentBitMetrics struct {
+	Input map[string]struct {
+		Records float64 `json:"records"`
+		Bytes   float64 `json:"bytes"`
+	} `json:"input"`
+	Output map[string]struct {
+		ProcRecords    float64 `json:"proc_records"`
+		Errors         float64 `json:"errors"`
+		Retries        float64 `json:"retries"`
+		RetriesFailed  float64 `json:"retries_failed"`
+		DroppedRecords float64 `json:"dropped_records"`
+	} `json:"output"`
+}
+
+// FluentBitMetricsGet reads the metrics of the fluent-bit sidecar of the pod
+// with the IP. It is a variable so tests can replace it.
+var FluentBitMetricsGet = func(ctx context.Context, podIP string) (*fluentBitMetrics, error) {
+	ctx, cancel := context.WithTimeout(ctx, fluentBitMetricsTimeout)
+	defer cancel()
+	url := "http://" + net.JoinHostPort(podIP, strconv.Itoa(fluentBitHTTPPort)) + "/api/v1/metrics"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s returned status %d", url, resp.StatusCode)
+	}
+	parsed := &fluentBitMetrics{}
+	if err := json.NewDecoder(resp.Body).Decode(parsed); err != nil {
+		return nil, err
+	}
+	return parsed, nil
+}
+
+// fluentBitServiceHealthCheck returns the health check properties of the
+// service section of the fluent-bit configuration. The HTTP server listens on
+// all addresses, so the kubelet can probe it and the operator can read its
+// metrics.
+func fluentBitServiceHealthCheck(logCollection *marklogicv1.LogCollection) string {
+	errorsCount, retryFailureCount, period := int32(defaultFluentBitHealthErrorsCount), int32(defaultFluentBitHealthRetryFailureCount), int32(defaultFluentBitHealthPeriodSeconds)
+	if check := logCollection.HealthCheck; check != nil {
+		if check.ErrorsCount > 0 {
+			errorsCount = check.ErrorsCount
+		}
+		if check.RetryFailureCount > 0 {
+			retryFailureCount = check.RetryFailureCount
+		}
+		if check.PeriodSeconds > 0 {
+			period = check.PeriodSeconds
+		}
+	}
+	return fmt.Sprintf(`  health_check: on
+  hc_errors_count: %d
+  hc_retry_failure_count: %d
+  hc_period: %d
+`, errorsCount, retryFailureCount, period)
+}
+
+// fluentBitLivenessProbe restarts fluent-bit once its health check reports
+// its outputs failing, or its HTTP server stops responding.
+func fluentBitLivenessProbe() *corev1.Probe {
+	return &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			HTTPGet: &corev1.HTTPGetAction{Path: "/api/v1/health", Port: intstr.FromString(fluentBitHTTPPortName)},
+		},
+		InitialDelaySeconds: 10,
+		PeriodSeconds:       30,
+		TimeoutSeconds:      5,
+		FailureThreshold:    3,
+	}
+}
+
+// fluentBitReadinessProbe checks that fluent-bit runs. It does not depend on
+// the outputs, so an unavailable log destination does not take the pods out
+// of their Services.
+func fluentBitReadinessProbe() *corev1.Probe {
+	return &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			HTTPGet: &corev1.HTTPGetAction{Path: "/api/v1/uptime", Port: intstr.FromString(fluentBitHTTPPortName)},
+		},
+		PeriodSeconds:    10,
+		TimeoutSeconds:   5,
+		FailureThreshold: 3,
+	}
+}
+
+// ReconcileLogCollectionMetrics collects the metrics of the fluent-bit
+// sidecars every logCollectionMetricsInterval and exports them as metrics of
+// the operator, so a log pipeline that stopped sending records is noticed.
+// Failures are logged and never hold up the rest of the reconcile.
+func (cc *ClusterContext) ReconcileLogCollectionMetrics() result.ReconcileResult {
+	cr := cc.MarklogicCluster
+	key := types.NamespacedName{Namespace: cr.Namespace, Name: cr.Name}
+	groups := fluentBitGroups(cr)
+	if len(groups) == 0 {
+		if _, collected := logCollectionMetricsCollected.LoadAndDelete(key); collected {
+			deleteClusterSeries(fluentBitGauges, cr.Namespace, cr.Name)
+		}
+		return result.Continue()
+	}
+	if last, ok := logCollectionMetricsCollected.Load(key); ok && time.Since(last.(time.Time)) < logCollectionMetricsInterval {
+		return result.Continue()
+	}
+	logCollectionMetricsCollected.Store(key, time.Now())
+
+	// Pods that are gone drop out of the metrics.
+	deleteClusterSeries(fluentBitGauges, cr.Namespace, cr.Name)
+	for _, group := range groups {
+		list := &corev1.PodList{}
+		if err := cc.Client.List(cc.Ctx, list, client.InNamespace(cr.Namespace), client.MatchingLabels{
+			"app.kubernetes.io/name":     "marklogic",
+			"app.kubernetes.io/instance": group.Name,
+		}); err != nil {
+			cc.ReqLogger.Error(err, "Failed to list the pods for the log collection metrics", "group", group.Name)
+			continue
+		}
+		for _, pod := range list.Items {
+			if status := logCollectorStatus(pod); status == nil || status.Name != fluentBitContainerName || status.State.Running == nil || pod.Status.PodIP == "" {
+				continue
+			}
+			collected, err := FluentBitMetricsGet(cc.Ctx, pod.Status.PodIP)
+			if err != nil {
+				cc.ReqLogger.Error(err, "Failed to read the fluent-bit metrics", "pod", pod.Name)
+				continue
+			}
+			setFluentBitMetrics(cr, pod.Name, collected)
+		}
+	}
+	return result.Continue()
+}
+
+func setFluentBitMetrics(cr *marklogicv1.MarklogicCluster, pod string, collected *fluentBitMetrics) {
+	for plugin, input := range collected.Input {
+		labels := prometheus.Labels{"namespace": cr.Namespace, "cluster": cr.Name, "pod": pod, "plugin": plugin}
+		fluentBitInputRecords.With(labels).Set(input.Records)
+		fluentBitInputBytes.With(labels).Set(input.Bytes)
+	}
+	for plugin, output := range collected.Output {
+		labels := prometheus.Labels{"namespace": cr.Namespace, "cluster": cr.Name, "pod": pod, "plugin": plugin}
+		fluentBitOutputRecords.With(labels).Set(output.ProcRecords)
+		fluentBitOutputErrors.With(labels).Set(output.Errors)
+		fluentBitOutputRetries.With(labels).Set(output.Retries)
+		fluentBitOutputRetriesFailed.With(labels).Set(output.RetriesFailed)
+		fluentBitOutputDroppedRecords.With(labels).Set(output.DroppedRecords)
+	}
+}
+
+// fluentBitGroups returns the groups that run the fluent-bit sidecar. The
+// logCollection of a group replaces the one of the cluster.
+func fluentBitGroups(cr *marklogicv1.MarklogicCluster) []*marklogicv1.MarklogicGroups {
+	groups := []*marklogicv1.MarklogicGroups{}
+	for _, group := range cr.Spec.MarkLogicGroups {
+		if group == nil {
+			continue
+		}
+		logCollection := cr.Spec.LogCollection
+		if group.LogCollection != nil {
+			logCollection = group.LogCollection
+		}
+		if logCollection != nil && logCollection.Enabled && newLogCollector(logCollection).name() == fluentBitContainerName {
+			groups = append(groups, group)
+		}
+	}
+	return groups
+}
+
+// nextLogCollectionMetrics is when the fluent-bit metrics are collected next.
+func nextLogCollectionMetrics(cr *marklogicv1.MarklogicCluster) time.Time {
+	if len(fluentBitGroups(cr)) == 0 || clusterStopped(cr) {
+		return time.Time{}
+	}
+	return time.Now().Add(logCollectionMetricsInterval)
+}
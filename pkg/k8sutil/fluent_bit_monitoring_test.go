@@ -0,0 +1,109 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+func TestFluentBitIsProbedThroughItsHealthCheck(t *testing.T) {
+	logCollection := &marklogicv1.LogCollection{
+		Enabled:     true,
+		Files:       marklogicv1.LogFilesConfig{ErrorLogs: true},
+		HealthCheck: &marklogicv1.LogCollectionHealthCheck{ErrorsCount: 10, PeriodSeconds: 120},
+	}
+	var config struct {
+		Service map[string]any `json:"service"`
+	}
+	if err := yaml.Unmarshal([]byte(fluentBitConfigData(logCollection)["fluent-bit.yaml"]), &config); err != nil {
+		t.Fatalf("invalid fluent-bit configuration: %v", err)
+	}
+	if config.Service["http_listen"] != "0.0.0.0" || config.Service["health_check"] != true ||
+		config.Service["hc_errors_count"] != float64(10) || config.Service["hc_retry_failure_count"] != float64(5) ||
+		config.Service["hc_period"] != float64(120) {
+		t.Fatalf("unexpected service %v", config.Service)
+	}
+
+	containers := generateContainerDef("dnode", containerParameters{
+		Name:          "dnode",
+		LogCollection: logCollection,
+		HugePages:     &marklogicv1.HugePages{},
+	})
+	fluentBit := containers[len(containers)-1]
+	if fluentBit.Name != fluentBitContainerName || len(fluentBit.Ports) != 1 || fluentBit.Ports[0].ContainerPort != fluentBitHTTPPort {
+		t.Fatalf("expected the fluent-bit HTTP port, got %+v", fluentBit.Ports)
+	}
+	if fluentBit.LivenessProbe == nil || fluentBit.LivenessProbe.HTTPGet.Path != "/api/v1/health" ||
+		fluentBit.ReadinessProbe == nil || fluentBit.ReadinessProbe.HTTPGet.Path != "/api/v1/uptime" {
+		t.Fatalf("expected the fluent-bit probes, got %+v and %+v", fluentBit.LivenessProbe, fluentBit.ReadinessProbe)
+	}
+}
+
+func TestFluentBitMetricsAreExported(t *testing.T) {
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "logs", Namespace: "default"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			LogCollection: &marklogicv1.LogCollection{Enabled: true},
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{
+				{Name: "dnode", IsBootstrap: true},
+				{Name: "enode", LogCollection: &marklogicv1.LogCollection{}},
+			},
+		},
+	}
+	pod := func(name, group, ip string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{
+				"app.kubernetes.io/name":     "marklogic",
+				"app.kubernetes.io/instance": group,
+			}},
+			Status: corev1.PodStatus{PodIP: ip, ContainerStatuses: []corev1.ContainerStatus{{
+				Name:  fluentBitContainerName,
+				State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+			}}},
+		}
+	}
+	cc := newUpgradeTestContext(t, cr, pod("dnode-0", "dnode", "10.0.0.1"), pod("enode-0", "enode", "10.0.0.2"))
+	t.Cleanup(func() { DeleteClusterMetrics("default", "logs") })
+	original := FluentBitMetricsGet
+	var probed []string
+	FluentBitMetricsGet = func(ctx context.Context, podIP string) (*fluentBitMetrics, error) {
+		probed = append(probed, podIP)
+		collected := &fluentBitMetrics{}
+		err := json.Unmarshal([]byte(`{
+			"input": {"tail.0": {"records": 120, "bytes": 4096}},
+			"output": {"loki.0": {"proc_records": 100, "errors": 2, "retries": 4, "retries_failed": 1, "dropped_records": 20}}
+		}`), collected)
+		return collected, err
+	}
+	t.Cleanup(func() { FluentBitMetricsGet = original })
+
+	if res := cc.ReconcileLogCollectionMetrics(); res.Completed() {
+		t.Fatalf("expected the reconcile to continue")
+	}
+	if len(probed) != 1 || probed[0] != "10.0.0.1" {
+		t.Fatalf("expected only the pod of the dnode group to be probed, got %v", probed)
+	}
+	if got := testutil.ToFloat64(fluentBitInputRecords.WithLabelValues("default", "logs", "dnode-0", "tail.0")); got != 120 {
+		t.Fatalf("expected the input records, got %v", got)
+	}
+	if got := testutil.ToFloat64(fluentBitOutputDroppedRecords.WithLabelValues("default", "logs", "dnode-0", "loki.0")); got != 20 {
+		t.Fatalf("expected the dropped records, got %v", got)
+	}
+	if nextLogCollectionMetrics(cc.MarklogicCluster).IsZero() {
+		t.Fatalf("expected the next collection to be scheduled")
+	}
+
+	cc.MarklogicCluster.Spec.LogCollection.Enabled = false
+	cc.ReconcileLogCollectionMetrics()
+	if got := testutil.CollectAndCount(fluentBitOutputRecords); got != 0 {
+		t.Fatalf("expected the metrics to be deleted once log collection is disabled, got %d series", got)
+	}
+}
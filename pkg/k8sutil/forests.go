@@ -0,0 +1,155 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"fmt"
+	"strings"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	forestsReasonProvisioned = "Provisioned"
+	forestsReasonPending     = "WaitingForHosts"
+	forestsReasonFailed      = "Failed"
+
+	forestProvisioningRetrySeconds = 30
+)
+
+// ReconcileForestProvisioning creates the forests configured in
+// spec.markLogicGroups[].forests on every online host of the group. Forests
+// are only ever created: the forests of a host removed by scaling down stay
+// in place until an administrator retires them.
+func (cc *ClusterContext) ReconcileForestProvisioning() result.ReconcileResult {
+	cr := cc.MarklogicCluster
+	groups := []*marklogicv1.MarklogicGroups{}
+	for _, group := range cr.Spec.MarkLogicGroups {
+		if group != nil && group.Forests != nil && !group.IsDynamic {
+			groups = append(groups, group)
+		}
+	}
+	if len(groups) == 0 {
+		return result.Continue()
+	}
+	mgmtClient, err := cc.newBootstrapManagementClient()
+	if err != nil {
+		return cc.forestProvisioningFailed(err)
+	}
+
+	existing := map[string]map[string]bool{}
+	forestCount := 0
+	pending := []string{}
+	for _, group := range groups {
+		groupName := group.Name
+		if group.GroupConfig != nil && strings.TrimSpace(group.GroupConfig.Name) != "" {
+			groupName = group.GroupConfig.Name
+		}
+		hosts, err := mgmtClient.ListGroupHosts(cc.Ctx, groupName)
+		if err != nil {
+			return cc.forestProvisioningFailed(fmt.Errorf("failed to list the hosts of group %s: %w", groupName, err))
+		}
+		online := int32(0)
+		for _, host := range hosts {
+			if !host.Online {
+				continue
+			}
+			online++
+			for _, forest := range hostForests(group.Forests, host.Name) {
+				forestCount++
+				if existing[forest.Database] == nil {
+					names, err := mgmtClient.ListDatabaseForests(cc.Ctx, forest.Database)
+					if err != nil {
+						return cc.forestProvisioningFailed(fmt.Errorf("failed to list the forests of database %s: %w", forest.Database, err))
+					}
+					existing[forest.Database] = map[string]bool{}
+					for _, name := range names {
+						existing[forest.Database][name] = true
+					}
+				}
+				if existing[forest.Database][forest.Name] {
+					continue
+				}
+				created, err := mgmtClient.CreateForest(cc.Ctx, forest)
+				if err != nil {
+					return cc.forestProvisioningFailed(fmt.Errorf("failed to create forest %s on %s: %w", forest.Name, host.Name, err))
+				}
+				existing[forest.Database][forest.Name] = true
+				if created {
+					cc.ReqLogger.Info("Created forest", "forest", forest.Name, "host", host.Name, "database", forest.Database)
+					cc.recordClusterEvent(corev1.EventTypeNormal, "ForestCreated",
+						fmt.Sprintf("Created forest %s on %s for database %s", forest.Name, host.Name, forest.Database))
+				}
+			}
+		}
+		if group.Replicas != nil && online < *group.Replicas {
+			pending = append(pending, fmt.Sprintf("%s (%d/%d hosts online)", groupName, online, *group.Replicas))
+		}
+	}
+
+	if len(pending) > 0 {
+		message := fmt.Sprintf("waiting for hosts to join: %s", strings.Join(pending, ", "))
+		if err := cc.setForestsCondition(metav1.ConditionFalse, forestsReasonPending, message); err != nil {
+			return result.Error(err)
+		}
+		return result.RequeueSoon(forestProvisioningRetrySeconds)
+	}
+	message := fmt.Sprintf("%d forest(s) provisioned on the hosts of %d group(s)", forestCount, len(groups))
+	if err := cc.setForestsCondition(metav1.ConditionTrue, forestsReasonProvisioned, message); err != nil {
+		return result.Error(err)
+	}
+	return result.Continue()
+}
+
+// hostForests returns the forests the policy creates on host. They are named
+// <database>-<host>-<n> after the first label of the host name, which is the
+// pod name, so a host that rejoins after a restart finds its forests again.
+func hostForests(policy *marklogicv1.ForestProvisioning, hostName string) []mlmanage.ForestSpec {
+	perHost := policy.ForestsPerHost
+	if perHost < 1 {
+		perHost = 1
+	}
+	shortName, _, _ := strings.Cut(hostName, ".")
+	forests := []mlmanage.ForestSpec{}
+	for _, database := range policy.Databases {
+		for n := int32(1); n <= perHost; n++ {
+			forests = append(forests, mlmanage.ForestSpec{
+				Name:               fmt.Sprintf("%s-%s-%d", database, shortName, n),
+				Host:               hostName,
+				Database:           database,
+				DataDirectory:      policy.DataDirectory,
+				FastDataDirectory:  policy.FastDataDirectory,
+				LargeDataDirectory: policy.LargeDataDirectory,
+			})
+		}
+	}
+	return forests
+}
+
+func (cc *ClusterContext) forestProvisioningFailed(cause error) result.ReconcileResult {
+	cc.ReqLogger.Error(cause, "Forest provisioning failed")
+	if err := cc.setForestsCondition(metav1.ConditionFalse, forestsReasonFailed, cause.Error()); err != nil {
+		return result.Error(err)
+	}
+	if isTransientManagementError(cause) {
+		return result.RequeueSoon(10)
+	}
+	return result.RequeueSoon(forestProvisioningRetrySeconds)
+}
+
+func (cc *ClusterContext) setForestsCondition(status metav1.ConditionStatus, reason, message string) error {
+	cr := cc.MarklogicCluster
+	for _, existing := range cr.Status.Conditions {
+		if existing.Type == string(marklogicv1.ForestsProvisioned) && existing.Status == status && existing.Message == message {
+			return nil
+		}
+	}
+	patchBase := client.MergeFrom(cr.DeepCopy())
+	cc.setClusterCondition(marklogicv1.ForestsProvisioned, status, reason, message)
+	return patchStatus(cc.Ctx, cc.Client, cr, patchBase)
+}
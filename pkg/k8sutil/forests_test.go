@@ -0,0 +1,86 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"testing"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestReconcileForestProvisioningCreatesForestsOnNewHosts(t *testing.T) {
+	replicas := int32(3)
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			ClusterDomain: "cluster.local",
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{
+				{Name: "enode", IsBootstrap: true, GroupConfig: &marklogicv1.GroupConfig{Name: "Evaluators"}},
+				{
+					Name:        "dnode",
+					Replicas:    &replicas,
+					GroupConfig: &marklogicv1.GroupConfig{Name: "Data"},
+					Forests: &marklogicv1.ForestProvisioning{
+						ForestsPerHost: 2,
+						DataDirectory:  "/var/opt/MarkLogic/forests",
+						Databases:      []string{"Documents"},
+					},
+				},
+			},
+		},
+	}
+	cc := newUpgradeTestContext(t, cr)
+	hosts := []mlmanage.GroupHost{
+		{Name: "dnode-0.dnode.default.svc.cluster.local", Online: true},
+		{Name: "dnode-1.dnode.default.svc.cluster.local", Online: true},
+	}
+	forests := []string{"Documents-dnode-0-1", "Documents-dnode-0-2"}
+	var created []mlmanage.ForestSpec
+	original := NewDynamicManagementClient
+	NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+		return &stubDynamicManagementClient{
+			listGroupFn: func(groupName string) ([]mlmanage.GroupHost, error) {
+				if groupName != "Data" {
+					t.Fatalf("unexpected group %s", groupName)
+				}
+				return hosts, nil
+			},
+			listForestsFn: func(database string) ([]string, error) {
+				return forests, nil
+			},
+			createForestFn: func(forest mlmanage.ForestSpec) (bool, error) {
+				created = append(created, forest)
+				forests = append(forests, forest.Name)
+				return true, nil
+			},
+		}
+	}
+	t.Cleanup(func() { NewDynamicManagementClient = original })
+
+	if res := cc.ReconcileForestProvisioning(); !res.Completed() {
+		t.Fatalf("expected a requeue while a host has not joined")
+	}
+	if len(created) != 2 || created[0].Name != "Documents-dnode-1-1" || created[1].Name != "Documents-dnode-1-2" {
+		t.Fatalf("expected the forests of the new host only, got %+v", created)
+	}
+	if created[0].Host != hosts[1].Name || created[0].Database != "Documents" || created[0].DataDirectory != "/var/opt/MarkLogic/forests" {
+		t.Fatalf("unexpected forest %+v", created[0])
+	}
+	if cr.Status.GetConditionStatus(string(marklogicv1.ForestsProvisioned)) != metav1.ConditionFalse {
+		t.Fatalf("expected forests to wait for the third host, got %+v", cr.Status.Conditions)
+	}
+
+	hosts = append(hosts, mlmanage.GroupHost{Name: "dnode-2.dnode.default.svc.cluster.local", Online: true})
+	created = nil
+	if res := cc.ReconcileForestProvisioning(); res.Completed() {
+		t.Fatalf("expected provisioning to finish once every host has joined")
+	}
+	if len(created) != 2 || created[0].Name != "Documents-dnode-2-1" {
+		t.Fatalf("expected the forests of the third host, got %+v", created)
+	}
+	if cr.Status.GetConditionStatus(string(marklogicv1.ForestsProvisioned)) != metav1.ConditionTrue {
+		t.Fatalf("expected forests to be provisioned, got %+v", cr.Status.Conditions)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"fmt"
 	"sort"
 
 	"github.com/cisco-open/k8s-objectmatcher/patch"
@@ -27,12 +28,12 @@ func (cc *ClusterContext) ReconcileHAProxy() result.ReconcileResult {
 
 	logger.Info("Reconciling HAProxy Config")
 
-	labels := cc.GetHAProxyLabels(cr.GetObjectMeta().GetName())
+	labels := withClusterNameLabel(cc.GetHAProxyLabels(cr.GetObjectMeta().GetName()), cr.Name)
 	annotations := cc.GetClusterAnnotations()
-	configMapName := "marklogic-haproxy"
+	configMapName := haproxyName(cr)
 	objectMeta := generateObjectMeta(configMapName, cr.Namespace, labels, annotations)
 	nsName := types.NamespacedName{Name: objectMeta.Name, Namespace: objectMeta.Namespace}
-	svcName := types.NamespacedName{Name: "marklogic-haproxy", Namespace: cr.Namespace}
+	svcName := types.NamespacedName{Name: objectMeta.Name, Namespace: cr.Namespace}
 	configmap := &corev1.ConfigMap{}
 	haproxyService := &corev1.Service{}
 	err := client.Get(cc.Ctx, nsName, configmap)
@@ -85,6 +86,8 @@ func (cc *ClusterContext) ReconcileHAProxy() result.ReconcileResult {
 	if !patchDiff.IsEmpty() {
 		logger.Info("MarkLogic HAProxy Config spec is different from previous spec, updating the HAProxy ConfigMap")
 		configmap.Data = configMapDef.Data
+		configmap.Labels = mergeMetadata(configmap.Labels, configMapDef.Labels)
+		configmap.Annotations = mergeMetadata(configmap.Annotations, configMapDef.Annotations)
 		if err := patch.DefaultAnnotator.SetLastAppliedAnnotation(configmap); err != nil {
 			logger.Error(err, "Failed to set last applied annotation for HAProxy ConfigMap")
 		}
@@ -118,9 +121,12 @@ func (cc *ClusterContext) ReconcileHAProxy() result.ReconcileResult {
 			return result.Error(err)
 		}
 	}
+	if err := cc.reconcileLegacyHAProxyService(haproxyServiceDef); err != nil {
+		return result.Error(err)
+	}
 
 	haproxyDeployment := &appsv1.Deployment{}
-	deployName := types.NamespacedName{Name: "marklogic-haproxy", Namespace: cr.Namespace}
+	deployName := types.NamespacedName{Name: objectMeta.Name, Namespace: cr.Namespace}
 	err = client.Get(cc.Ctx, deployName, haproxyDeployment)
 	if err != nil {
 		logger.Error(err, "Failed to get HAProxy Deployment")
@@ -146,6 +152,10 @@ func (cc *ClusterContext) ReconcileHAProxy() result.ReconcileResult {
 			return result.Error(err)
 		}
 	}
+	if err := cc.deleteLegacyHAProxy(haproxyDeployment); err != nil {
+		logger.Error(err, "Failed to delete legacy HAProxy resources")
+		return result.Error(err)
+	}
 	return result.Continue()
 }
 
@@ -159,6 +169,12 @@ global
   log stdout format raw local0
   maxconn 1024
 `
+	if cr.Spec.FIPSMode {
+		haProxyData["haproxy.cfg"] += fipsHAProxyGlobal
+	}
+	if port := haproxyRuntimeAPIPort(cr); port != 0 {
+		haProxyData["haproxy.cfg"] += fmt.Sprintf("  stats socket ipv4@:%d level admin\n", port)
+	}
 	baseConfig := `
 defaults
   log global
@@ -222,32 +238,39 @@ func (cc *ClusterContext) createHAProxyDeploymentDef(meta metav1.ObjectMeta) *ap
 	selectorLabels := getHAProxySelectorLabels(cr.GetObjectMeta().GetName())
 	ownerDef := marklogicClusterAsOwner(cr)
 	defaultMode := int32(420)
+	podSecurityContext := getHAProxyPodSecurityContextOrDefault(cr.Spec.HAProxy.PodSecurityContext)
+	containerSecurityContext := getHAProxyContainerSecurityContextOrDefault(cr.Spec.HAProxy.ContainerSecurityContext)
+	if cr.Spec.OCPCompatible {
+		ranges := cc.openShiftSCCRanges()
+		podSecurityContext = ranges.podSecurityContext(podSecurityContext)
+		containerSecurityContext = ranges.containerSecurityContext(containerSecurityContext)
+	}
 	deploymentDef := &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:        "marklogic-haproxy",
+			Name:        meta.Name,
 			Namespace:   cc.Request.Namespace,
 			Labels:      meta.Labels,
 			Annotations: meta.Annotations,
 		},
 		Spec: appsv1.DeploymentSpec{
-			Replicas: &cr.Spec.HAProxy.ReplicaCount,
+			Replicas: haproxyReplicas(cr),
 			Selector: &metav1.LabelSelector{
 				MatchLabels: selectorLabels,
 			},
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
 					Labels: meta.Labels,
-					Annotations: map[string]string{
+					Annotations: mergeMetadata(cr.Spec.AdditionalAnnotations, map[string]string{
 						"configmap-hash": calculateHash(generateHAProxyConfigMapData(cc.Ctx, cr)),
-					},
+					}),
 				},
 				Spec: corev1.PodSpec{
-					SecurityContext: getHAProxyPodSecurityContextOrDefault(cr.Spec.HAProxy.PodSecurityContext),
+					SecurityContext: podSecurityContext,
 					Containers: []corev1.Container{
 						{
 							Name:            "haproxy",
 							Image:           cr.Spec.HAProxy.Image,
-							SecurityContext: getHAProxyContainerSecurityContextOrDefault(cr.Spec.HAProxy.ContainerSecurityContext),
+							SecurityContext: containerSecurityContext,
 							Resources: corev1.ResourceRequirements{
 								Requests: corev1.ResourceList{
 									"cpu":    resource.MustParse("250m"),
@@ -270,7 +293,7 @@ func (cc *ClusterContext) createHAProxyDeploymentDef(meta metav1.ObjectMeta) *ap
 							VolumeSource: corev1.VolumeSource{
 								ConfigMap: &corev1.ConfigMapVolumeSource{
 									LocalObjectReference: corev1.LocalObjectReference{
-										Name: "marklogic-haproxy",
+										Name: meta.Name,
 									},
 									DefaultMode: &defaultMode,
 								},
@@ -297,6 +320,9 @@ func (cc *ClusterContext) createHAProxyDeploymentDef(meta metav1.ObjectMeta) *ap
 			},
 		})
 	}
+	if cr.Spec.RestrictedPodSecurity {
+		restrictPodSpec(&deploymentDef.Spec.Template.Spec)
+	}
 	AddOwnerRefToObject(deploymentDef, ownerDef)
 	return deploymentDef
 }
@@ -375,7 +401,7 @@ func (cc *ClusterContext) generateHaproxyServiceDef(meta metav1.ObjectMeta) *cor
 	selectorLabels := getHAProxySelectorLabels(cr.GetObjectMeta().GetName())
 	serviceDef := &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:        "marklogic-haproxy",
+			Name:        meta.Name,
 			Namespace:   cc.Request.Namespace,
 			Labels:      meta.Labels,
 			Annotations: meta.Annotations,
@@ -264,6 +264,11 @@ backend {{ .BackendName }}
   stick match req.cook(HostId)
   stick match req.cook(SessionID)
   default-server check`
+	if haproxyRuntimeAPIPort(cr) != 0 {
+		// Clients sticking to a server in maintenance go to another one.
+		backendTemplate += `
+  option redispatch`
+	}
 	for _, backends := range backendConfigs {
 		data := &HAProxyTemplate{
 			BackendName: backends[0].BackendName,
@@ -0,0 +1,192 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	defaultHAProxyRuntimeAPIPort = 9999
+	haproxyRuntimeTimeout        = 5 * time.Second
+	// haproxyForcedMaint is the admin state bit of servers put into
+	// maintenance through the runtime API.
+	haproxyForcedMaint = 0x01
+)
+
+// HAProxyRuntimeCommand sends a command to the HAProxy runtime API at addr and
+// returns the response. It is a variable so tests can replace it.
+var HAProxyRuntimeCommand = func(ctx context.Context, addr, command string) (string, error) {
+	dialer := net.Dialer{Timeout: haproxyRuntimeTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(haproxyRuntimeTimeout)); err != nil {
+		return "", err
+	}
+	if _, err := io.WriteString(conn, command+"\n"); err != nil {
+		return "", err
+	}
+	// HAProxy closes the connection after answering a single command line.
+	response, err := io.ReadAll(conn)
+	return string(response), err
+}
+
+// ReconcileHAProxyServers keeps the HAProxy servers of the pods an upgrade, a
+// resource rollout or a host restart is restarting in maintenance and puts every other server
+// back, also on HAProxy pods that started in the meantime. A failure is
+// logged and retried on the next reconcile instead of holding back the rest
+// of the cluster.
+func (cc *ClusterContext) ReconcileHAProxyServers() result.ReconcileResult {
+	cr := cc.MarklogicCluster
+	if haproxyRuntimeAPIPort(cr) == 0 {
+		return result.Continue()
+	}
+	maintenance := []string{}
+	if upgrade := cr.Status.Upgrade; upgrade != nil && upgrade.PodRestart != nil {
+		maintenance = append(maintenance, upgrade.PodRestart.Pod)
+	}
+	if rollout := cr.Status.ResourceRollout; rollout != nil && rollout.PodRestart != nil {
+		maintenance = append(maintenance, rollout.PodRestart.Pod)
+	}
+	if restart := cr.Status.HostRestart; restart != nil && restart.PodRestart != nil {
+		maintenance = append(maintenance, restart.PodRestart.Pod)
+	}
+	if err := cc.syncHAProxyServers(maintenance...); err != nil {
+		cc.ReqLogger.Error(err, "Failed to update the HAProxy servers")
+	}
+	return result.Continue()
+}
+
+// syncHAProxyServers puts the servers of the given MarkLogic pods into
+// maintenance on every ready HAProxy pod and the servers the operator put
+// into maintenance before back into the backends. It does nothing unless
+// spec.haproxy.rolloutCoordination is enabled.
+func (cc *ClusterContext) syncHAProxyServers(maintenance ...string) error {
+	cr := cc.MarklogicCluster
+	port := haproxyRuntimeAPIPort(cr)
+	if port == 0 {
+		return nil
+	}
+	serversByPod := haproxyServersOfPods(cc.Ctx, cr)
+	servers := []string{}
+	for _, podServers := range serversByPod {
+		servers = append(servers, podServers...)
+	}
+	sort.Strings(servers)
+	wanted := map[string]bool{}
+	for _, pod := range maintenance {
+		for _, server := range serversByPod[pod] {
+			wanted[server] = true
+		}
+	}
+
+	list := &corev1.PodList{}
+	if err := cc.Client.List(cc.Ctx, list, client.InNamespace(cr.Namespace), client.MatchingLabels(getHAProxySelectorLabels(cr.Name))); err != nil {
+		return err
+	}
+	for i := range list.Items {
+		pod := &list.Items[i]
+		if pod.DeletionTimestamp != nil || pod.Status.PodIP == "" || !hasPodReadyCondition(pod) {
+			continue
+		}
+		addr := net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(int(port)))
+		state, err := HAProxyRuntimeCommand(cc.Ctx, addr, "show servers state")
+		if err != nil {
+			return fmt.Errorf("failed to read the servers of HAProxy pod %s: %w", pod.Name, err)
+		}
+		inMaintenance := haproxyForcedMaintServers(state)
+		commands := []string{}
+		for _, server := range servers {
+			if wanted[server] && !inMaintenance[server] {
+				commands = append(commands, fmt.Sprintf("set server %s state maint", server))
+			} else if !wanted[server] && inMaintenance[server] {
+				commands = append(commands, fmt.Sprintf("set server %s state ready", server))
+			}
+		}
+		if len(commands) == 0 {
+			continue
+		}
+		response, err := HAProxyRuntimeCommand(cc.Ctx, addr, strings.Join(commands, "; "))
+		if err != nil {
+			return fmt.Errorf("failed to update the servers of HAProxy pod %s: %w", pod.Name, err)
+		}
+		if response = strings.TrimSpace(response); response != "" {
+			return fmt.Errorf("HAProxy pod %s rejected the server update: %s", pod.Name, response)
+		}
+		cc.ReqLogger.Info("Updated HAProxy servers", "haproxyPod", pod.Name, "commands", commands)
+	}
+	return nil
+}
+
+// haproxyRuntimeAPIPort returns the port of the HAProxy runtime API, or 0
+// when the operator does not coordinate rollouts with HAProxy.
+func haproxyRuntimeAPIPort(cr *marklogicv1.MarklogicCluster) int32 {
+	haproxy := cr.Spec.HAProxy
+	if haproxy == nil || !haproxy.Enabled || haproxy.RolloutCoordination == nil || !haproxy.RolloutCoordination.Enabled {
+		return 0
+	}
+	if haproxy.RolloutCoordination.RuntimeAPIPort == 0 {
+		return defaultHAProxyRuntimeAPIPort
+	}
+	return haproxy.RolloutCoordination.RuntimeAPIPort
+}
+
+// haproxyServersOfPods maps each MarkLogic pod behind HAProxy to its servers,
+// named backend/server as in the generated HAProxy configuration.
+func haproxyServersOfPods(ctx context.Context, cr *marklogicv1.MarklogicCluster) map[string][]string {
+	config := generateHAProxyConfig(ctx, cr)
+	servers := map[string][]string{}
+	for _, backends := range config.BackendConfigMap {
+		for _, backend := range backends {
+			for i := 0; i < backend.Replicas; i++ {
+				pod := fmt.Sprintf("%s-%d", backend.GroupName, i)
+				servers[pod] = append(servers[pod], fmt.Sprintf("%s/%s-%d-%d", backend.BackendName, backend.GroupName, backend.TargetPort, i))
+			}
+		}
+	}
+	for _, tcpConfigs := range config.TCPConfigMap {
+		for _, tcpConfig := range tcpConfigs {
+			for i := 0; i < tcpConfig.Replicas; i++ {
+				pod := fmt.Sprintf("%s-%d", tcpConfig.PodName, i)
+				servers[pod] = append(servers[pod], fmt.Sprintf("marklogic-TCP-%s/ml-%s-%d-%d", tcpConfig.TcpName, tcpConfig.PodName, tcpConfig.TargetPort, i))
+			}
+		}
+	}
+	return servers
+}
+
+// haproxyForcedMaintServers parses the response of "show servers state" and
+// returns the servers put into maintenance through the runtime API.
+func haproxyForcedMaintServers(state string) map[string]bool {
+	servers := map[string]bool{}
+	for _, line := range strings.Split(state, "\n") {
+		// be_id be_name srv_id srv_name srv_addr srv_op_state srv_admin_state ...
+		fields := strings.Fields(line)
+		if len(fields) < 7 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		adminState, err := strconv.Atoi(fields[6])
+		if err != nil {
+			continue
+		}
+		if adminState&haproxyForcedMaint != 0 {
+			servers[fields[1]+"/"+fields[3]] = true
+		}
+	}
+	return servers
+}
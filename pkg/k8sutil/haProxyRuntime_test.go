@@ -0,0 +1,67 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSyncHAProxyServersTakesRestartingPodOut(t *testing.T) {
+	pathBased := false
+	replicas := int32(2)
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			ClusterDomain: "cluster.local",
+			HAProxy: &marklogicv1.HAProxy{
+				Enabled:             true,
+				PathBasedRouting:    &pathBased,
+				AppServers:          []marklogicv1.AppServers{{Name: "app", Port: 8000}},
+				RolloutCoordination: &marklogicv1.HAProxyRolloutCoordination{Enabled: true},
+			},
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{{Name: "dnode", Replicas: &replicas, IsBootstrap: true}},
+		},
+	}
+	if config := generateHAProxyConfigMapData(context.Background(), cr)["haproxy.cfg"]; !strings.Contains(config, "stats socket ipv4@:9999 level admin") ||
+		!strings.Contains(config, "option redispatch") {
+		t.Fatalf("expected the runtime API in the HAProxy configuration, got %s", config)
+	}
+
+	haproxyPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "marklogic-haproxy-abc", Namespace: "default", Labels: getHAProxySelectorLabels("ml")},
+		Status: corev1.PodStatus{
+			PodIP:      "10.0.0.5",
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+		},
+	}
+	cc := newUpgradeTestContext(t, cr, haproxyPod)
+	commands := []string{}
+	original := HAProxyRuntimeCommand
+	t.Cleanup(func() { HAProxyRuntimeCommand = original })
+	HAProxyRuntimeCommand = func(ctx context.Context, addr, command string) (string, error) {
+		if addr != "10.0.0.5:9999" {
+			t.Fatalf("unexpected runtime API address %s", addr)
+		}
+		commands = append(commands, command)
+		if command == "show servers state" {
+			return "1\n# be_id be_name srv_id srv_name srv_addr srv_op_state srv_admin_state\n" +
+				"3 marklogic-8000-backend 1 dnode-8000-0 10.1.0.1 2 1 1 1\n" +
+				"3 marklogic-8000-backend 2 dnode-8000-1 10.1.0.2 2 0 1 1\n", nil
+		}
+		return "\n", nil
+	}
+
+	if err := cc.syncHAProxyServers("dnode-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "set server marklogic-8000-backend/dnode-8000-0 state ready; set server marklogic-8000-backend/dnode-8000-1 state maint"
+	if len(commands) != 2 || commands[1] != want {
+		t.Fatalf("expected dnode-0 back and dnode-1 out of the backend, got %q", commands)
+	}
+}
@@ -3,6 +3,9 @@
 package k8sutil
 
 import (
+	"time"
+
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/features"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
@@ -36,10 +39,30 @@ func (oc *OperatorContext) ReconsileMarklogicGroupHandler() (reconcile.Result, e
 		}
 	}
 
+	if result := oc.ReconcileQuorumProtection(); result.Completed() {
+		return result.Output()
+	}
+
 	if result := oc.ReconcileVolumeResizeValidation(); result.Completed() {
 		return result.Output()
 	}
 
+	if result := oc.ReconcileVolumeTopology(); result.Completed() {
+		return result.Output()
+	}
+
+	if result := oc.ReconcilePVCRetention(); result.Completed() {
+		return result.Output()
+	}
+
+	if result := oc.ReconcileStorageMigration(); result.Completed() {
+		return result.Output()
+	}
+
+	if result := oc.ReconcileSecretRotation(); result.Completed() {
+		return result.Output()
+	}
+
 	result, err := oc.ReconcileStatefulset()
 	if err != nil {
 		return result, err
@@ -55,6 +78,58 @@ func (oc *OperatorContext) ReconsileMarklogicGroupHandler() (reconcile.Result, e
 }
 
 func (cc *ClusterContext) ReconsileMarklogicClusterHandler() (reconcile.Result, error) {
+	res, err := cc.requeueForHibernation(cc.reconcileMarklogicCluster())
+	if err == nil {
+		res = requeueBy(res, nextCapacityReport(cc.MarklogicCluster))
+		res = requeueBy(res, nextLoadMetricsCollection(cc.MarklogicCluster))
+		res = requeueBy(res, nextHostStatusRefresh(cc.MarklogicCluster))
+		res = requeueBy(res, nextQuorumCheck(cc.MarklogicCluster))
+		res = requeueBy(res, nextHostRecovery(cc.MarklogicCluster))
+		res = requeueBy(res, nextStuckPodCheck(cc.MarklogicCluster))
+		res = requeueBy(res, nextNodeDrainCheck(cc.MarklogicCluster))
+		res = requeueBy(res, nextDataImportCheck(cc.MarklogicCluster))
+		res = requeueBy(res, nextModulesCheck(cc.MarklogicCluster))
+		res = requeueBy(res, nextLogCollectionCheck(cc.MarklogicCluster))
+		res = requeueBy(res, nextLogCollectionMetrics(cc.MarklogicCluster))
+		res = requeueBy(res, nextFIPSCheck(cc.MarklogicCluster))
+		res = requeueBy(res, nextTieredStorageCheck(cc.MarklogicCluster))
+		res = requeueBy(res, nextReplicationCheck(cc.MarklogicCluster))
+		res = requeueBy(res, nextHealthReport(cc.MarklogicCluster))
+		res = requeueBy(res, nextDiagnosticsExpiry(cc.MarklogicCluster))
+		res = requeueBy(res, nextAppServerSettingsCheck(cc.MarklogicCluster))
+		res = requeueBy(res, nextSupportBundleCheck(cc.MarklogicCluster))
+		res = requeueBy(res, nextOperatorUserRetry(cc.MarklogicCluster))
+		res = requeueBy(res, nextBackupScheduleRefresh(cc.MarklogicCluster))
+	}
+	return cc.updateClusterHealth(res, err)
+}
+
+// requeueBy makes sure the cluster is reconciled again by the given time,
+// unless it is zero or an immediate requeue is already asked for.
+func requeueBy(res reconcile.Result, next time.Time) reconcile.Result {
+	if next.IsZero() || (res.Requeue && res.RequeueAfter == 0) {
+		return res
+	}
+	after := max(time.Until(next), time.Second)
+	if res.RequeueAfter == 0 || after < res.RequeueAfter {
+		res.RequeueAfter = after
+	}
+	return res
+}
+
+func (cc *ClusterContext) reconcileMarklogicCluster() (reconcile.Result, error) {
+	if result := cc.ReconcileChangeAudit(); result.Completed() {
+		return result.Output()
+	}
+	if result := cc.ReconcileExport(); result.Completed() {
+		return result.Output()
+	}
+	if result := cc.ReconcileSupportBundle(); result.Completed() {
+		return result.Output()
+	}
+	if result := cc.ReconcilePreflight(); result.Completed() {
+		return result.Output()
+	}
 	if result := cc.ReconcileServiceAccount(); result.Completed() {
 		return result.Output()
 	}
@@ -71,11 +146,125 @@ func (cc *ClusterContext) ReconsileMarklogicClusterHandler() (reconcile.Result,
 		if result := cc.ReconcileHAProxy(); result.Completed() {
 			return result.Output()
 		}
-		if cc.MarklogicCluster.Spec.HAProxy.Ingress.Enabled {
+		if result := cc.ReconcileHAProxyServers(); result.Completed() {
+			return result.Output()
+		}
+		if cc.MarklogicCluster.Spec.HAProxy.Ingress.Enabled && !cc.MarklogicCluster.Spec.OCPCompatible {
 			if result := cc.ReconcileIngress(); result.Completed() {
 				return result.Output()
 			}
 		}
 	}
+	if result := cc.ReconcileAdminIngress(); result.Completed() {
+		return result.Output()
+	}
+	if result := cc.ReconcileRoutes(); result.Completed() {
+		return result.Output()
+	}
+	if result := cc.ReconcileVerticalPodAutoscalers(); result.Completed() {
+		return result.Output()
+	}
+	if result := cc.ReconcileScaledObjects(); result.Completed() {
+		return result.Output()
+	}
+	if result := cc.ReconcileCapacity(); result.Completed() {
+		return result.Output()
+	}
+	if result := cc.ReconcileLogCollectionHealth(); result.Completed() {
+		return result.Output()
+	}
+	if result := cc.ReconcileLogCollectionMetrics(); result.Completed() {
+		return result.Output()
+	}
+	if err == nil {
+		if result := cc.ReconcileHibernation(); result.Completed() {
+			return result.Output()
+		}
+		if result := cc.ReconcileClusterRun(); result.Completed() {
+			return result.Output()
+		}
+		if result := cc.ReconcileLoadMetrics(); result.Completed() {
+			return result.Output()
+		}
+		if result := cc.ReconcileHostStatus(); result.Completed() {
+			return result.Output()
+		}
+		if result := cc.ReconcileReplicationHealth(); result.Completed() {
+			return result.Output()
+		}
+		if result := cc.ReconcileHealthReport(); result.Completed() {
+			return result.Output()
+		}
+		// Nothing below restarts or upgrades pods while the quorum is lost.
+		if result := cc.ReconcileQuorum(); result.Completed() {
+			return result.Output()
+		}
+		if result := cc.ReconcileHostRecovery(); result.Completed() {
+			return result.Output()
+		}
+		if result := cc.ReconcileStuckPods(); result.Completed() {
+			return result.Output()
+		}
+		if result := cc.ReconcileNodeDrain(); result.Completed() {
+			return result.Output()
+		}
+		if result := cc.ReconcileHostZones(); result.Completed() {
+			return result.Output()
+		}
+		if result := cc.ReconcileDiagnostics(); result.Completed() {
+			return result.Output()
+		}
+		if result := cc.ReconcileAppServerSettings(); result.Completed() {
+			return result.Output()
+		}
+		if result := cc.ReconcileFIPS(); result.Completed() {
+			return result.Output()
+		}
+		if result := cc.ReconcileAdminCredentialRotation(); result.Completed() {
+			return result.Output()
+		}
+		if result := cc.ReconcileOperatorUser(); result.Completed() {
+			return result.Output()
+		}
+		// Backup configuration is left untouched while an upgrade is rolling out.
+		if features.Enabled(features.UpgradeWorkflow) {
+			if result := cc.ReconcileUpgrade(); result.Completed() {
+				return result.Output()
+			}
+		}
+		if result := cc.ReconcileResourceRollout(); result.Completed() {
+			return result.Output()
+		}
+		if result := cc.ReconcileHostRestart(); result.Completed() {
+			return result.Output()
+		}
+		if result := cc.ReconcileForestProvisioning(); result.Completed() {
+			return result.Output()
+		}
+		if result := cc.ReconcileDataImport(); result.Completed() {
+			return result.Output()
+		}
+		if result := cc.ReconcileModules(); result.Completed() {
+			return result.Output()
+		}
+		if result := cc.ReconcileTieredStorage(); result.Completed() {
+			return result.Output()
+		}
+		// Like upgrades, warm-up windows defer backup configuration changes.
+		if result := cc.ReconcileScaleUpWarmUp(); result.Completed() {
+			return result.Output()
+		}
+	}
+	if err == nil && cc.MarklogicCluster.Spec.Backup != nil && cc.MarklogicCluster.Spec.Backup.Enabled && features.Enabled(features.Backups) {
+		if result := cc.ReconcileBackupStorage(); result.Completed() {
+			return result.Output()
+		}
+		if result := cc.ReconcileBackupRestore(); result.Completed() {
+			return result.Output()
+		}
+		if result := cc.ReconcileBackupSchedules(); result.Completed() {
+			return result.Output()
+		}
+	}
 	return result, err
 }
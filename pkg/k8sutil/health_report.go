@@ -0,0 +1,349 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// HealthReportKey is the key of the report in the health report
+	// ConfigMap.
+	HealthReportKey = "report.json"
+
+	defaultLicenseExpiryWarningDays = 30
+	defaultStorageWarningPercent    = 85
+
+	healthReportReasonGenerated = "HealthReportGenerated"
+	healthReportReasonFailed    = "HealthReportFailed"
+)
+
+// postHealthReport posts a report to a notification webhook. It is a variable
+// so tests can replace it.
+var postHealthReport = func(ctx context.Context, webhookURL string, report []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(report))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("webhook returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// ClusterHealthReport is the health report of a cluster, as written to its
+// ConfigMap and posted to the notification webhook.
+type ClusterHealthReport struct {
+	Cluster   string                         `json:"cluster"`
+	Namespace string                         `json:"namespace"`
+	Time      metav1.Time                    `json:"time"`
+	Result    marklogicv1.HealthReportResult `json:"result"`
+	// Text summarizes the report for the chat webhooks.
+	Text     string                   `json:"text"`
+	Phase    marklogicv1.ClusterPhase `json:"phase,omitempty"`
+	Findings []HealthReportFinding    `json:"findings,omitempty"`
+	Forests  *ForestsHealthReport     `json:"forests,omitempty"`
+	Storage  []HostStorageReport      `json:"storage,omitempty"`
+	License  *LicenseReport           `json:"license,omitempty"`
+	Upgrade  UpgradeReport            `json:"upgrade"`
+}
+
+// HealthReportFinding is a problem a health report found.
+type HealthReportFinding struct {
+	Severity marklogicv1.HealthReportResult `json:"severity"`
+	Message  string                         `json:"message"`
+}
+
+// ForestsHealthReport counts the forests and lists those that are not open or
+// replicating.
+type ForestsHealthReport struct {
+	Total     int      `json:"total"`
+	Unhealthy []string `json:"unhealthy,omitempty"`
+}
+
+// HostStorageReport is the forest storage of a host, taking the forests as the
+// only data on their device.
+type HostStorageReport struct {
+	Host        string `json:"host"`
+	UsedMB      int64  `json:"usedMB"`
+	CapacityMB  int64  `json:"capacityMB"`
+	UsedPercent int32  `json:"usedPercent"`
+}
+
+// LicenseReport is the license of the bootstrap host.
+type LicenseReport struct {
+	Licensee string `json:"licensee,omitempty"`
+	// Expires is empty for licenses that do not expire.
+	Expires  string `json:"expires,omitempty"`
+	DaysLeft *int32 `json:"daysLeft,omitempty"`
+}
+
+// UpgradeReport is the image the cluster runs and the upgrade it has pending.
+type UpgradeReport struct {
+	CurrentImage string                   `json:"currentImage,omitempty"`
+	DesiredImage string                   `json:"desiredImage,omitempty"`
+	State        marklogicv1.UpgradeState `json:"state,omitempty"`
+	// Pending is true while spec.image is not rolled out.
+	Pending bool `json:"pending"`
+}
+
+// ReconcileHealthReport produces the health report of spec.healthReport once
+// a day or a week: the phase of the cluster, the state of its forests, the
+// forest storage of its hosts, the license of the bootstrap host and the
+// upgrade it has pending, with a finding for everything that needs attention.
+// The report is written to the <cluster>-health-report ConfigMap and posted
+// to the notification webhook of spec.healthReport.webhookURLSecret. A report
+// that cannot be written or posted is reported in status.healthReport and
+// with a Warning event, and is not retried before the next one is due. The
+// forests, storage and license are not read while the cluster is stopped.
+func (cc *ClusterContext) ReconcileHealthReport() result.ReconcileResult {
+	cr := cc.MarklogicCluster
+	spec := cr.Spec.HealthReport
+	if spec == nil {
+		return result.Continue()
+	}
+	if next := nextHealthReport(cr); !next.IsZero() && time.Now().Before(next) {
+		return result.Continue()
+	}
+
+	report := cc.collectHealthReport(spec)
+	status := &marklogicv1.HealthReportStatus{ConfigMapName: healthReportName(cr), ReportTime: &report.Time, Result: report.Result}
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err == nil {
+		err = cc.writeHealthReport(status.ConfigMapName, data)
+	}
+	if err == nil && spec.WebhookURLSecret != nil {
+		var webhookURL string
+		if webhookURL, err = cc.secretURL(spec.WebhookURLSecret, "webhook URL"); err == nil {
+			if err = postHealthReport(cc.Ctx, webhookURL, data); err != nil {
+				err = fmt.Errorf("failed to post the report to %s: %w", redactedURL(webhookURL), err)
+			}
+		}
+	}
+	if err != nil {
+		cc.ReqLogger.Error(err, "Failed to deliver the health report")
+		status.Message = err.Error()
+		cc.recordClusterEvent(corev1.EventTypeWarning, healthReportReasonFailed, fmt.Sprintf("Failed to deliver the health report: %v", err))
+	} else {
+		cc.ReqLogger.Info("Produced the health report", "configMap", status.ConfigMapName, "result", report.Result)
+		cc.recordClusterEvent(corev1.EventTypeNormal, healthReportReasonGenerated,
+			fmt.Sprintf("Wrote the health report to ConfigMap %s: %s with %d finding(s)", status.ConfigMapName, report.Result, len(report.Findings)))
+	}
+
+	patchBase := client.MergeFrom(cr.DeepCopy())
+	cr.Status.HealthReport = status
+	if err := patchStatus(cc.Ctx, cc.Client, cr, patchBase); err != nil {
+		cc.ReqLogger.Error(err, "Failed to update the health report status")
+	}
+	return result.Continue()
+}
+
+// collectHealthReport builds the health report of the cluster.
+func (cc *ClusterContext) collectHealthReport(spec *marklogicv1.HealthReport) ClusterHealthReport {
+	cr := cc.MarklogicCluster
+	report := ClusterHealthReport{Cluster: cr.Name, Namespace: cr.Namespace, Time: metav1.Now(), Phase: cr.Status.Phase}
+	find := func(severity marklogicv1.HealthReportResult, format string, args ...any) {
+		report.Findings = append(report.Findings, HealthReportFinding{Severity: severity, Message: fmt.Sprintf(format, args...)})
+	}
+	if cr.Status.Phase == marklogicv1.ClusterPhaseDegraded {
+		find(marklogicv1.HealthReportCritical, "the cluster is degraded")
+	}
+
+	report.Upgrade = UpgradeReport{CurrentImage: rolloutImage(cr), DesiredImage: cr.Spec.Image}
+	if upgrade := cr.Status.Upgrade; upgrade != nil {
+		report.Upgrade.State = upgrade.State
+		switch upgrade.State {
+		case marklogicv1.UpgradeStateFailed:
+			find(marklogicv1.HealthReportCritical, "the upgrade to %s failed: %s", upgrade.TargetImage, upgrade.Message)
+		case marklogicv1.UpgradeStateWaitingForUserApproval:
+			find(marklogicv1.HealthReportWarning, "the upgrade to %s waits for its approval", upgrade.TargetImage)
+		}
+	}
+	report.Upgrade.Pending = report.Upgrade.CurrentImage != report.Upgrade.DesiredImage
+
+	if clusterStopped(cr) {
+		report.finish()
+		return report
+	}
+	mgmt, err := cc.newBootstrapManagementClient()
+	if err != nil {
+		find(marklogicv1.HealthReportWarning, "the Manage API cannot be reached: %v", err)
+		report.finish()
+		return report
+	}
+	if forests, err := mgmt.ListForestsStatus(cc.Ctx); err != nil {
+		find(marklogicv1.HealthReportWarning, "failed to read the forests: %v", err)
+	} else {
+		report.Forests, report.Storage = forestsHealthReport(forests)
+		for _, forest := range report.Forests.Unhealthy {
+			find(marklogicv1.HealthReportCritical, "forest %s is not open", forest)
+		}
+		limit := spec.StorageWarningPercent
+		if limit <= 0 {
+			limit = defaultStorageWarningPercent
+		}
+		for _, host := range report.Storage {
+			if host.UsedPercent >= limit {
+				find(marklogicv1.HealthReportWarning, "the forests of host %s use %d%% of their storage", host.Host, host.UsedPercent)
+			}
+		}
+	}
+	if license, err := cc.licenseHealthReport(mgmt); err != nil {
+		find(marklogicv1.HealthReportWarning, "failed to read the license: %v", err)
+	} else {
+		report.License = license
+		warningDays := spec.LicenseExpiryWarningDays
+		if warningDays <= 0 {
+			warningDays = defaultLicenseExpiryWarningDays
+		}
+		switch {
+		case license.Expires != "" && license.DaysLeft == nil:
+			find(marklogicv1.HealthReportWarning, "cannot parse the license expiry %q", license.Expires)
+		case license.DaysLeft == nil:
+		case *license.DaysLeft < 0:
+			find(marklogicv1.HealthReportCritical, "the license expired on %s", license.Expires)
+		case *license.DaysLeft < warningDays:
+			find(marklogicv1.HealthReportWarning, "the license expires on %s, in %d day(s)", license.Expires, *license.DaysLeft)
+		}
+	}
+	report.finish()
+	return report
+}
+
+// finish sets the result of the report to its most severe finding and
+// summarizes it in its text.
+func (r *ClusterHealthReport) finish() {
+	r.Result = marklogicv1.HealthReportHealthy
+	lines := []string{}
+	for _, finding := range r.Findings {
+		if finding.Severity == marklogicv1.HealthReportCritical || r.Result == marklogicv1.HealthReportHealthy {
+			r.Result = finding.Severity
+		}
+		lines = append(lines, fmt.Sprintf("- %s: %s", finding.Severity, finding.Message))
+	}
+	text := fmt.Sprintf("MarkLogic cluster %s/%s is %s", r.Namespace, r.Cluster, r.Result)
+	if r.Phase != "" {
+		text += fmt.Sprintf(" (phase %s)", r.Phase)
+	}
+	if r.Upgrade.Pending {
+		text += fmt.Sprintf(", upgrade to %s pending", r.Upgrade.DesiredImage)
+	}
+	r.Text = strings.Join(append([]string{text}, lines...), "\n")
+}
+
+// forestsHealthReport returns the forests that are not healthy and the forest
+// storage of every host.
+func forestsHealthReport(forests []mlmanage.ForestStatus) (*ForestsHealthReport, []HostStorageReport) {
+	report := &ForestsHealthReport{Total: len(forests)}
+	byHost := map[string][]mlmanage.ForestStatus{}
+	for _, forest := range forests {
+		if !forestStateHealthy(forest.State) {
+			report.Unhealthy = append(report.Unhealthy, fmt.Sprintf("%s (%s)", forest.Name, forest.State))
+		}
+		byHost[forest.Host] = append(byHost[forest.Host], forest)
+	}
+	sort.Strings(report.Unhealthy)
+	storage := []HostStorageReport{}
+	for host, hostForests := range byHost {
+		usage, ok := forestDeviceUsage(hostForests)
+		if !ok || usage.CapacityMB <= 0 {
+			continue
+		}
+		storage = append(storage, HostStorageReport{
+			Host: host, UsedMB: usage.UsedMB, CapacityMB: usage.CapacityMB, UsedPercent: int32(usage.UsedMB * 100 / usage.CapacityMB),
+		})
+	}
+	sort.Slice(storage, func(i, j int) bool { return storage[i].Host < storage[j].Host })
+	return report, storage
+}
+
+// licenseHealthReport reads the license of the bootstrap host.
+func (cc *ClusterContext) licenseHealthReport(mgmt mlmanage.Client) (*LicenseReport, error) {
+	host, err := cc.bootstrapHostFQDN()
+	if err != nil {
+		return nil, err
+	}
+	license, err := mgmt.GetHostLicense(cc.Ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	report := &LicenseReport{Licensee: license.Licensee, Expires: license.Expires}
+	if expires, err := parseManageTime(license.Expires); err == nil {
+		report.Expires = expires.Format(time.DateOnly)
+		days := int32(time.Until(expires).Hours() / 24)
+		if time.Until(expires) < 0 {
+			days = -1
+		}
+		report.DaysLeft = &days
+	}
+	return report, nil
+}
+
+// writeHealthReport writes a report to the health report ConfigMap of the
+// cluster.
+func (cc *ClusterContext) writeHealthReport(name string, report []byte) error {
+	cr := cc.MarklogicCluster
+	data := map[string]string{HealthReportKey: string(report)}
+	configMap := &corev1.ConfigMap{}
+	err := cc.Client.Get(cc.Ctx, types.NamespacedName{Name: name, Namespace: cr.Namespace}, configMap)
+	if apierrors.IsNotFound(err) {
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: generateObjectMeta(name, cr.Namespace, withClusterNameLabel(cc.GetClusterLabels(cr.Name), cr.Name), cc.GetClusterAnnotations()),
+			Data:       data,
+		}
+		AddOwnerRefToObject(configMap, marklogicClusterAsOwner(cr))
+		return cc.Client.Create(cc.Ctx, configMap)
+	}
+	if err != nil {
+		return err
+	}
+	configMap.Data = data
+	return cc.Client.Update(cc.Ctx, configMap)
+}
+
+func healthReportName(cr *marklogicv1.MarklogicCluster) string {
+	return clusterFullname(cr) + "-health-report"
+}
+
+// healthReportInterval returns how long a report of the schedule is valid.
+func healthReportInterval(schedule marklogicv1.HealthReportSchedule) time.Duration {
+	if schedule == marklogicv1.HealthReportWeekly {
+		return 7 * 24 * time.Hour
+	}
+	return 24 * time.Hour
+}
+
+// nextHealthReport is when the next health report is due, or the zero time
+// when the cluster has no report yet.
+func nextHealthReport(cr *marklogicv1.MarklogicCluster) time.Time {
+	if cr.Spec.HealthReport == nil || cr.Status.HealthReport == nil || cr.Status.HealthReport.ReportTime == nil {
+		return time.Time{}
+	}
+	return cr.Status.HealthReport.ReportTime.Add(healthReportInterval(cr.Spec.HealthReport.Schedule))
+}
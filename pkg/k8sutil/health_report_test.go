@@ -0,0 +1,107 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestReconcileHealthReportWritesAndPostsReport(t *testing.T) {
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			ClusterDomain:   "cluster.local",
+			Image:           "progressofficial/marklogic-db:12.0.1",
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{{Name: "dnode", IsBootstrap: true}},
+			HealthReport: &marklogicv1.HealthReport{
+				Schedule:         marklogicv1.HealthReportWeekly,
+				WebhookURLSecret: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "webhook"}, Key: "url"},
+			},
+		},
+		Status: marklogicv1.MarklogicClusterStatus{Upgrade: &marklogicv1.UpgradeStatus{
+			State:        marklogicv1.UpgradeStateWaitingForUserApproval,
+			CurrentImage: "progressofficial/marklogic-db:11.3.1",
+			TargetImage:  "progressofficial/marklogic-db:12.0.1",
+		}},
+	}
+	webhook := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "webhook", Namespace: "default"},
+		Data:       map[string][]byte{"url": []byte("https://hooks.example.com/services/T0/B0/secret")},
+	}
+	cc := newUpgradeTestContext(t, cr, webhook)
+	host := "dnode-0.dnode.default.svc.cluster.local"
+	original, originalPost := NewDynamicManagementClient, postHealthReport
+	NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+		return &stubDynamicManagementClient{
+			forestsStatusFn: func() ([]mlmanage.ForestStatus, error) {
+				return []mlmanage.ForestStatus{
+					{Name: "Documents", Host: host, State: "open", DataSizeMB: 900, DeviceSpaceMB: 100},
+					{Name: "Meters", Host: host, State: "error", DataSizeMB: 0, DeviceSpaceMB: 100},
+				}, nil
+			},
+			hostLicenseFn: func(hostName string) (mlmanage.HostLicense, error) {
+				return mlmanage.HostLicense{Licensee: "Example", Expires: time.Now().Add(10 * 24 * time.Hour).Format(time.RFC3339)}, nil
+			},
+		}
+	}
+	var posted []byte
+	postHealthReport = func(ctx context.Context, webhookURL string, report []byte) error {
+		posted = report
+		return nil
+	}
+	t.Cleanup(func() { NewDynamicManagementClient, postHealthReport = original, originalPost })
+
+	if res := cc.ReconcileHealthReport(); res.Completed() {
+		t.Fatalf("expected the health report never to hold up the reconcile")
+	}
+	status := cr.Status.HealthReport
+	if status == nil || status.ReportTime == nil || status.Result != marklogicv1.HealthReportCritical || status.Message != "" {
+		t.Fatalf("unexpected health report status %+v", status)
+	}
+	configMap := &corev1.ConfigMap{}
+	if err := cc.Client.Get(cc.Ctx, types.NamespacedName{Namespace: "default", Name: status.ConfigMapName}, configMap); err != nil {
+		t.Fatalf("expected the report ConfigMap: %v", err)
+	}
+	if configMap.Data[HealthReportKey] != string(posted) {
+		t.Fatalf("expected the ConfigMap report to be posted")
+	}
+	report := ClusterHealthReport{}
+	if err := json.Unmarshal(posted, &report); err != nil {
+		t.Fatalf("failed to decode the report: %v", err)
+	}
+	if report.Forests == nil || report.Forests.Total != 2 || len(report.Forests.Unhealthy) != 1 {
+		t.Fatalf("expected the forest in error to be reported, got %+v", report.Forests)
+	}
+	if len(report.Storage) != 1 || report.Storage[0].UsedPercent != 90 {
+		t.Fatalf("expected the host storage to be 90%% used, got %+v", report.Storage)
+	}
+	if report.License == nil || report.License.DaysLeft == nil || *report.License.DaysLeft != 9 {
+		t.Fatalf("expected the license to expire in 9 days, got %+v", report.License)
+	}
+	if !report.Upgrade.Pending || report.Upgrade.CurrentImage != "progressofficial/marklogic-db:11.3.1" {
+		t.Fatalf("expected the upgrade to be pending, got %+v", report.Upgrade)
+	}
+	if len(report.Findings) != 4 || !strings.Contains(report.Text, "is Critical") {
+		t.Fatalf("unexpected report %+v", report)
+	}
+
+	// The next report is due a week later.
+	posted = nil
+	cc.ReconcileHealthReport()
+	if posted != nil {
+		t.Fatalf("expected no report before the next one is due")
+	}
+	if next := nextHealthReport(cr); time.Until(next) < 6*24*time.Hour {
+		t.Fatalf("expected the next report in a week, got %v", next)
+	}
+}
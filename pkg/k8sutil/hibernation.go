@@ -0,0 +1,141 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"fmt"
+	"time"
+	// Time zones of hibernation schedules must resolve in minimal images.
+	_ "time/tzdata"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/cron"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	hibernationReasonHibernating = "ClusterHibernating"
+	hibernationReasonResuming    = "ClusterResuming"
+	hibernationReasonInvalid     = "HibernationScheduleInvalid"
+
+	// hibernationLookback bounds the search for the last stop and start
+	// times. It covers schedules that repeat weekly.
+	hibernationLookback = 8 * 24 * time.Hour
+)
+
+// ReconcileHibernation evaluates spec.hibernation: the cluster is hibernated
+// while the last stop time of the schedule is later than its last start
+// time. The outcome is recorded in status.hibernation, which
+// ReconcileClusterRun follows like spec.stopped. The schedule is evaluated
+// from scratch on every reconcile, so a changed schedule applies right away
+// and a missed start is caught up.
+func (cc *ClusterContext) ReconcileHibernation() result.ReconcileResult {
+	return cc.reconcileHibernation(time.Now())
+}
+
+func (cc *ClusterContext) reconcileHibernation(now time.Time) result.ReconcileResult {
+	cr := cc.MarklogicCluster
+	previous := cr.Status.Hibernation
+	if cr.Spec.Hibernation == nil {
+		if previous == nil {
+			return result.Continue()
+		}
+		return cc.setHibernationStatus(nil)
+	}
+	status := &marklogicv1.HibernationStatus{}
+	stop, start, location, err := parseHibernation(cr.Spec.Hibernation)
+	if err != nil {
+		// Keep the cluster as it is until the schedule is fixed.
+		status.Hibernated = previous != nil && previous.Hibernated
+		status.Message = fmt.Sprintf("invalid hibernation schedule: %v", err)
+		if previous == nil || previous.Message != status.Message {
+			cc.recordClusterEvent(corev1.EventTypeWarning, hibernationReasonInvalid, status.Message)
+		}
+	} else {
+		now = now.In(location)
+		lastStop, stopped := stop.Latest(now.Add(-hibernationLookback), now)
+		lastStart, started := start.Latest(now.Add(-hibernationLookback), now)
+		status.Hibernated = stopped && (!started || lastStop.After(lastStart))
+		status.NextStopTime = scheduleTime(stop.Next(now))
+		status.NextStartTime = scheduleTime(start.Next(now))
+		switch {
+		case status.Hibernated && status.NextStartTime != nil:
+			status.Message = fmt.Sprintf("hibernated until %s", status.NextStartTime.UTC().Format(time.RFC3339))
+		case status.Hibernated:
+			status.Message = "hibernated"
+		case status.NextStopTime != nil:
+			status.Message = fmt.Sprintf("running until %s", status.NextStopTime.UTC().Format(time.RFC3339))
+		}
+		if wasHibernated := previous != nil && previous.Hibernated; wasHibernated != status.Hibernated {
+			if status.Hibernated {
+				cc.recordClusterEvent(corev1.EventTypeNormal, hibernationReasonHibernating, "stopping the cluster for its hibernation schedule")
+			} else {
+				cc.recordClusterEvent(corev1.EventTypeNormal, hibernationReasonResuming, "starting the cluster for its hibernation schedule")
+			}
+		}
+	}
+	if equality.Semantic.DeepEqual(previous, status) {
+		return result.Continue()
+	}
+	return cc.setHibernationStatus(status)
+}
+
+func parseHibernation(hibernation *marklogicv1.Hibernation) (*cron.Schedule, *cron.Schedule, *time.Location, error) {
+	stop, err := cron.Parse(hibernation.Schedule.Stop)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("stop: %w", err)
+	}
+	start, err := cron.Parse(hibernation.Schedule.Start)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("start: %w", err)
+	}
+	location, err := time.LoadLocation(hibernation.TimeZone)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("time zone: %w", err)
+	}
+	return stop, start, location, nil
+}
+
+func scheduleTime(t time.Time) *metav1.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &metav1.Time{Time: t.UTC()}
+}
+
+// clusterStopped reports whether the cluster is to be stopped, by
+// spec.stopped or by its hibernation schedule.
+func clusterStopped(cr *marklogicv1.MarklogicCluster) bool {
+	return cr.Spec.Stopped || (cr.Spec.Hibernation != nil && cr.Status.Hibernation != nil && cr.Status.Hibernation.Hibernated)
+}
+
+// requeueForHibernation makes sure the cluster is reconciled again at the
+// next stop or start time of its hibernation schedule.
+func (cc *ClusterContext) requeueForHibernation(res reconcile.Result, err error) (reconcile.Result, error) {
+	status := cc.MarklogicCluster.Status.Hibernation
+	if err != nil || cc.MarklogicCluster.Spec.Hibernation == nil || status == nil {
+		return res, err
+	}
+	var next time.Time
+	for _, t := range []*metav1.Time{status.NextStopTime, status.NextStartTime} {
+		if t != nil && (next.IsZero() || t.Time.Before(next)) {
+			next = t.Time
+		}
+	}
+	return requeueBy(res, next), err
+}
+
+func (cc *ClusterContext) setHibernationStatus(status *marklogicv1.HibernationStatus) result.ReconcileResult {
+	cr := cc.MarklogicCluster
+	patchBase := client.MergeFrom(cr.DeepCopy())
+	cr.Status.Hibernation = status
+	if err := patchStatus(cc.Ctx, cc.Client, cr, patchBase); err != nil {
+		return result.Error(err)
+	}
+	return result.Continue()
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"testing"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestHibernationScheduleStopsAndStartsTheCluster(t *testing.T) {
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			Hibernation: &marklogicv1.Hibernation{
+				Schedule: marklogicv1.HibernationSchedule{Stop: "0 20 * * mon-fri", Start: "0 7 * * mon-fri"},
+				TimeZone: "Europe/Berlin",
+			},
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{{Name: "dnode", IsBootstrap: true}},
+		},
+	}
+	cc := newUpgradeTestContext(t, cr)
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatalf("failed to load time zone: %v", err)
+	}
+
+	// Saturday: the cluster was stopped on Friday evening.
+	cc.reconcileHibernation(time.Date(2026, 10, 17, 12, 0, 0, 0, berlin))
+	status := cc.MarklogicCluster.Status.Hibernation
+	if status == nil || !status.Hibernated {
+		t.Fatalf("expected the cluster to hibernate over the weekend, got %+v", status)
+	}
+	if want := time.Date(2026, 10, 19, 7, 0, 0, 0, berlin); status.NextStartTime == nil || !status.NextStartTime.Time.Equal(want) {
+		t.Fatalf("expected the next start on Monday morning, got %v", status.NextStartTime)
+	}
+	cc.ReconcileClusterRun()
+	if run := cc.MarklogicCluster.Status.Run; run == nil || run.State != marklogicv1.ClusterRunStateStopping {
+		t.Fatalf("expected the cluster to stop, got %+v", run)
+	}
+	res, _ := cc.requeueForHibernation(reconcile.Result{}, nil)
+	if res.RequeueAfter <= 0 {
+		t.Fatalf("expected a requeue at the next start time")
+	}
+
+	// Monday morning after the start time.
+	cc.reconcileHibernation(time.Date(2026, 10, 19, 9, 0, 0, 0, berlin))
+	if status := cc.MarklogicCluster.Status.Hibernation; status.Hibernated {
+		t.Fatalf("expected the cluster to resume, got %+v", status)
+	}
+	cc.ReconcileClusterRun()
+	if run := cc.MarklogicCluster.Status.Run; run.State != marklogicv1.ClusterRunStateStarting {
+		t.Fatalf("expected the cluster to start, got %+v", run)
+	}
+}
+
+func TestInvalidHibernationScheduleKeepsTheCluster(t *testing.T) {
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			Hibernation: &marklogicv1.Hibernation{Schedule: marklogicv1.HibernationSchedule{Stop: "0 25 * * *", Start: "0 7 * * *"}},
+		},
+	}
+	cc := newUpgradeTestContext(t, cr)
+	cc.ReconcileHibernation()
+	status := cc.MarklogicCluster.Status.Hibernation
+	if status == nil || status.Hibernated || status.Message == "" {
+		t.Fatalf("expected the invalid schedule to be reported, got %+v", status)
+	}
+	if clusterStopped(cc.MarklogicCluster) {
+		t.Fatalf("expected the cluster to keep running")
+	}
+}
@@ -0,0 +1,332 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"fmt"
+	"net"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// hostRecoveryGracePeriod is how long a pod is ready before its host is
+	// expected to be online in the cluster. Attempts back off from it.
+	hostRecoveryGracePeriod = 2 * time.Minute
+	// hostRecoveryMaxAttempts is how many times a host is renamed or its pod
+	// restarted before its recovery fails.
+	hostRecoveryMaxAttempts = 5
+
+	hostRecoveryReasonRejoinFailed = "HostRejoinFailed"
+	hostRecoveryReasonRenamed      = "HostRenamed"
+	hostRecoveryReasonRestarted    = "HostRecoveryRestarted"
+	hostRecoveryReasonRecovered    = "HostRecovered"
+	hostRecoveryReasonFailed       = "HostRecoveryFailed"
+)
+
+// ReconcileHostRecovery recovers the MarkLogic hosts of pods that are ready
+// while the cluster sees their host offline or never saw it join, as happens
+// when a pod is rescheduled from a failed node and its host comes back with a
+// stale configuration. The suspects are read from the hosts in the status of
+// the groups, and only they are looked up in the Manage API. A host the
+// cluster knows by another name, such as the old address of its pod, is
+// renamed to the name of the pod; otherwise the pod is restarted so its host
+// joins again. Attempts back off exponentially and stop after
+// hostRecoveryMaxAttempts, with a HostRecovered event once the host is online.
+// The bootstrap host and dynamic groups are left alone, and nothing is done
+// while the cluster is stopped or a workflow holds the rollout lock. Failures
+// are logged and never hold up the rest of the reconcile.
+func (cc *ClusterContext) ReconcileHostRecovery() result.ReconcileResult {
+	cr := cc.MarklogicCluster
+	if clusterStopped(cr) || rolloutLockHolder(cr) != "" {
+		return result.Continue()
+	}
+	now := metav1.Now()
+	tracked := map[string]marklogicv1.HostRecoveryStatus{}
+	for _, recovery := range cr.Status.HostRecovery {
+		tracked[recovery.Pod] = recovery
+	}
+	var mgmt mlmanage.Client
+	managementClient := func() (mlmanage.Client, error) {
+		if mgmt != nil {
+			return mgmt, nil
+		}
+		var err error
+		mgmt, err = cc.newBootstrapManagementClient()
+		return mgmt, err
+	}
+
+	recoveries := []marklogicv1.HostRecoveryStatus{}
+	for _, group := range cr.Spec.MarkLogicGroups {
+		if group == nil || group.IsDynamic {
+			continue
+		}
+		recoveries = append(recoveries, cc.recoverGroupHosts(group, tracked, managementClient, now)...)
+	}
+	sort.Slice(recoveries, func(i, j int) bool { return recoveries[i].Pod < recoveries[j].Pod })
+	if len(recoveries) == 0 {
+		recoveries = nil
+	}
+	if equality.Semantic.DeepEqual(recoveries, cr.Status.HostRecovery) {
+		return result.Continue()
+	}
+	patchBase := client.MergeFrom(cr.DeepCopy())
+	cr.Status.HostRecovery = recoveries
+	if err := patchStatus(cc.Ctx, cc.Client, cr, patchBase); err != nil {
+		cc.ReqLogger.Error(err, "Failed to update the host recovery in the cluster status")
+	}
+	return result.Continue()
+}
+
+// recoverGroupHosts recovers the hosts of a group and returns the pods still
+// tracked. Pods that are tracked but not ready, or not recreated yet, keep
+// their entry.
+func (cc *ClusterContext) recoverGroupHosts(group *marklogicv1.MarklogicGroups, tracked map[string]marklogicv1.HostRecoveryStatus, managementClient func() (mlmanage.Client, error), now metav1.Time) []marklogicv1.HostRecoveryStatus {
+	cr := cc.MarklogicCluster
+	pods := &corev1.PodList{}
+	if err := cc.Client.List(cc.Ctx, pods, client.InNamespace(cr.Namespace), client.MatchingLabels{
+		"app.kubernetes.io/name":     "marklogic",
+		"app.kubernetes.io/instance": group.Name,
+	}); err != nil {
+		cc.ReqLogger.Error(err, "Failed to list the pods of the group for host recovery", "group", group.Name)
+		return trackedPods(tracked, func(pod string) bool { return groupPod(group, pod) })
+	}
+	statusHosts := map[string]marklogicv1.HostStatus{}
+	mlGroup := &marklogicv1.MarklogicGroup{}
+	if err := cc.Client.Get(cc.Ctx, types.NamespacedName{Namespace: cr.Namespace, Name: group.Name}, mlGroup); err == nil {
+		for _, host := range mlGroup.Status.Hosts {
+			statusHosts[host.Pod] = host
+		}
+	} else if !apierrors.IsNotFound(err) {
+		cc.ReqLogger.Error(err, "Failed to read the hosts of the group for host recovery", "group", group.Name)
+	}
+
+	// Pods restarted last are tracked until they are back.
+	recoveries := trackedPods(tracked, func(pod string) bool {
+		return groupPod(group, pod) && !slices.ContainsFunc(pods.Items, func(existing corev1.Pod) bool { return existing.Name == pod })
+	})
+	suspects := []corev1.Pod{}
+	for _, pod := range pods.Items {
+		recovery, isTracked := tracked[pod.Name]
+		if group.IsBootstrap && parseOrdinalFromName(pod.Name) == 0 {
+			continue
+		}
+		if since, ready := podReadySince(&pod); !ready || now.Sub(since) < hostRecoveryGracePeriod {
+			if isTracked {
+				recoveries = append(recoveries, recovery)
+			}
+			continue
+		}
+		host, reported := statusHosts[pod.Name]
+		if isTracked || (reported && (host.Host == "" || !host.Online)) {
+			suspects = append(suspects, pod)
+		}
+	}
+	if len(suspects) == 0 {
+		return recoveries
+	}
+
+	groupName := group.Name
+	if group.GroupConfig != nil && strings.TrimSpace(group.GroupConfig.Name) != "" {
+		groupName = group.GroupConfig.Name
+	}
+	mgmt, err := managementClient()
+	var hosts []mlmanage.GroupHost
+	if err == nil {
+		hosts, err = mgmt.ListGroupHosts(cc.Ctx, groupName)
+	}
+	if err != nil {
+		cc.ReqLogger.Error(err, "Failed to list the hosts of the group for host recovery", "group", group.Name)
+		return append(recoveries, trackedPods(tracked, func(pod string) bool {
+			return slices.ContainsFunc(suspects, func(suspect corev1.Pod) bool { return suspect.Name == pod })
+		})...)
+	}
+	podHosts := map[string]bool{}
+	for _, pod := range pods.Items {
+		podHosts[strings.ToLower(cc.podHostFQDN(pod))] = true
+	}
+	hostByName := map[string]mlmanage.GroupHost{}
+	stale := []mlmanage.GroupHost{}
+	for _, host := range hosts {
+		hostByName[strings.ToLower(host.Name)] = host
+		if !host.Online && !podHosts[strings.ToLower(host.Name)] {
+			stale = append(stale, host)
+		}
+	}
+	unjoined := 0
+	for _, pod := range suspects {
+		if _, ok := hostByName[strings.ToLower(cc.podHostFQDN(pod))]; !ok {
+			unjoined++
+		}
+	}
+
+	for _, pod := range suspects {
+		fqdn := cc.podHostFQDN(pod)
+		recovery, isTracked := tracked[pod.Name]
+		host, known := hostByName[strings.ToLower(fqdn)]
+		if known && host.Online {
+			if isTracked {
+				message := fmt.Sprintf("host %s of pod %s rejoined the cluster after %d attempt(s)", host.Name, pod.Name, recovery.Attempts)
+				cc.ReqLogger.Info("Recovered the host", "pod", pod.Name, "host", host.Name, "attempts", recovery.Attempts)
+				cc.recordClusterEvent(corev1.EventTypeNormal, hostRecoveryReasonRecovered, message)
+			}
+			continue
+		}
+		// A pod recreated after the recovery failed gets another chance.
+		if isTracked && recovery.Failed && recovery.LastAttemptTime != nil && pod.CreationTimestamp.After(recovery.LastAttemptTime.Time) {
+			isTracked = false
+		}
+		if !isTracked {
+			recovery = marklogicv1.HostRecoveryStatus{Pod: pod.Name}
+			if known {
+				recovery.Message = fmt.Sprintf("pod %s is ready but its host %s is offline in the cluster", pod.Name, host.Name)
+			} else {
+				recovery.Message = fmt.Sprintf("pod %s is ready but its host %s has not joined the cluster", pod.Name, fqdn)
+			}
+			cc.ReqLogger.Info("Detected a host that did not rejoin the cluster", "pod", pod.Name, "host", fqdn)
+			cc.recordClusterEvent(corev1.EventTypeWarning, hostRecoveryReasonRejoinFailed, recovery.Message)
+		}
+		if known {
+			recovery.Host = host.Name
+		}
+		var renameFrom *mlmanage.GroupHost
+		if !known {
+			renameFrom = renamedHost(pod.Name, stale, unjoined)
+		}
+		recoveries = append(recoveries, cc.recoverHost(pod, fqdn, renameFrom, recovery, mgmt, now))
+	}
+	return recoveries
+}
+
+// recoverHost makes the next attempt to recover the host of a pod, once the
+// backoff of the previous attempt passed: it renames the host the cluster
+// knows by another name, or restarts the pod.
+func (cc *ClusterContext) recoverHost(pod corev1.Pod, fqdn string, renameFrom *mlmanage.GroupHost, recovery marklogicv1.HostRecoveryStatus, mgmt mlmanage.Client, now metav1.Time) marklogicv1.HostRecoveryStatus {
+	if recovery.Failed || (recovery.LastAttemptTime != nil && now.Time.Before(recovery.LastAttemptTime.Add(hostRecoveryBackoff(recovery.Attempts)))) {
+		return recovery
+	}
+	if recovery.Attempts >= hostRecoveryMaxAttempts {
+		recovery.Failed = true
+		recovery.LastAttemptTime = &now
+		recovery.Message = fmt.Sprintf("the host of pod %s did not rejoin the cluster after %d attempts, check its logs and delete the pod to try again", pod.Name, recovery.Attempts)
+		cc.recordClusterEvent(corev1.EventTypeWarning, hostRecoveryReasonFailed, recovery.Message)
+		return recovery
+	}
+	if renameFrom != nil {
+		if err := mgmt.SetHostName(cc.Ctx, renameFrom.Name, fqdn); err != nil {
+			cc.ReqLogger.Error(err, "Failed to rename the host", "host", renameFrom.Name, "name", fqdn)
+			recovery.Message = fmt.Sprintf("failed to rename host %s to %s: %v", renameFrom.Name, fqdn, err)
+			return recovery
+		}
+		recovery.Attempts++
+		recovery.LastAttemptTime = &now
+		recovery.Host = fqdn
+		recovery.Message = fmt.Sprintf("renamed host %s to %s, the name of pod %s", renameFrom.Name, fqdn, pod.Name)
+		cc.ReqLogger.Info("Renamed the host", "host", renameFrom.Name, "name", fqdn)
+		cc.recordClusterEvent(corev1.EventTypeNormal, hostRecoveryReasonRenamed, recovery.Message)
+		return recovery
+	}
+	if err := cc.Client.Delete(cc.Ctx, &pod); err != nil && !apierrors.IsNotFound(err) {
+		cc.ReqLogger.Error(err, "Failed to restart the pod for host recovery", "pod", pod.Name)
+		recovery.Message = fmt.Sprintf("failed to restart pod %s: %v", pod.Name, err)
+		return recovery
+	}
+	recovery.Attempts++
+	recovery.LastAttemptTime = &now
+	recovery.Message = fmt.Sprintf("restarted pod %s so its host joins the cluster again, attempt %d of %d", pod.Name, recovery.Attempts, hostRecoveryMaxAttempts)
+	cc.ReqLogger.Info("Restarted the pod for host recovery", "pod", pod.Name, "attempt", recovery.Attempts)
+	cc.recordClusterEvent(corev1.EventTypeNormal, hostRecoveryReasonRestarted, recovery.Message)
+	return recovery
+}
+
+// renamedHost returns the offline host the cluster knows a pod by when the
+// name of the host is no longer the name of the pod: a host named after the
+// pod in another domain, or the only host named by an address while only one
+// pod has not joined.
+func renamedHost(pod string, stale []mlmanage.GroupHost, unjoined int) *mlmanage.GroupHost {
+	addresses := []mlmanage.GroupHost{}
+	for i, host := range stale {
+		if strings.EqualFold(strings.SplitN(host.Name, ".", 2)[0], pod) {
+			return &stale[i]
+		}
+		if net.ParseIP(host.Name) != nil {
+			addresses = append(addresses, host)
+		}
+	}
+	if unjoined == 1 && len(addresses) == 1 {
+		return &addresses[0]
+	}
+	return nil
+}
+
+// hostRecoveryBackoff is how long the recovery waits after the given number
+// of attempts, doubled for every attempt.
+func hostRecoveryBackoff(attempts int32) time.Duration {
+	if attempts < 1 {
+		return 0
+	}
+	return hostRecoveryGracePeriod << (attempts - 1)
+}
+
+// trackedPods returns the tracked entries of the pods keep selects, so a
+// failed lookup does not forget them.
+func trackedPods(tracked map[string]marklogicv1.HostRecoveryStatus, keep func(pod string) bool) []marklogicv1.HostRecoveryStatus {
+	recoveries := []marklogicv1.HostRecoveryStatus{}
+	for pod, recovery := range tracked {
+		if keep(pod) {
+			recoveries = append(recoveries, recovery)
+		}
+	}
+	return recoveries
+}
+
+// groupPod reports whether a pod is one of the pods the StatefulSet of a
+// group runs.
+func groupPod(group *marklogicv1.MarklogicGroups, pod string) bool {
+	replicas := int32(1)
+	if group.Replicas != nil {
+		replicas = *group.Replicas
+	}
+	ordinal := parseOrdinalFromName(pod)
+	return pod == fmt.Sprintf("%s-%d", group.Name, ordinal) && ordinal >= 0 && int32(ordinal) < replicas
+}
+
+// podReadySince returns when the pod became ready, and whether it is.
+func podReadySince(pod *corev1.Pod) (time.Time, bool) {
+	if !isPodLocallyReady(pod) {
+		return time.Time{}, false
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.LastTransitionTime.Time, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// nextHostRecovery is when the next attempt to recover a host is due.
+func nextHostRecovery(cr *marklogicv1.MarklogicCluster) time.Time {
+	next := time.Time{}
+	for _, recovery := range cr.Status.HostRecovery {
+		if recovery.Failed || recovery.LastAttemptTime == nil {
+			continue
+		}
+		due := recovery.LastAttemptTime.Add(hostRecoveryBackoff(recovery.Attempts))
+		if next.IsZero() || due.Before(next) {
+			next = due
+		}
+	}
+	return next
+}
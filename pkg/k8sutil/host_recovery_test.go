@@ -0,0 +1,133 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestReconcileHostRecoveryRejoinsHosts(t *testing.T) {
+	replicas := int32(3)
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			ClusterDomain:   "cluster.local",
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{{Name: "dnode", Replicas: &replicas, IsBootstrap: true}},
+		},
+	}
+	readyPod := func(name string) *corev1.Pod {
+		pod := newStorageTestPod(name)
+		pod.Status.Phase = corev1.PodRunning
+		pod.Status.Conditions = []corev1.PodCondition{{
+			Type: corev1.PodReady, Status: corev1.ConditionTrue, LastTransitionTime: metav1.NewTime(time.Now().Add(-10 * time.Minute)),
+		}}
+		return pod
+	}
+	group := &marklogicv1.MarklogicGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "dnode", Namespace: "default"},
+		Status: marklogicv1.MarklogicGroupStatus{Hosts: []marklogicv1.HostStatus{
+			{Pod: "dnode-0", Host: "dnode-0.dnode.default.svc.cluster.local", Online: true},
+			{Pod: "dnode-1", Host: "dnode-1.dnode.default.svc.cluster.local"},
+			{Pod: "dnode-2"},
+		}},
+	}
+	cc := newUpgradeTestContext(t, cr, group, readyPod("dnode-0"), readyPod("dnode-1"), readyPod("dnode-2"))
+	recorder := cc.Recorder.(*record.FakeRecorder)
+	hosts := []mlmanage.GroupHost{
+		{Name: "dnode-0.dnode.default.svc.cluster.local", Online: true},
+		{Name: "dnode-1.dnode.default.svc.cluster.local"},
+		{Name: "10.0.0.7"},
+	}
+	renames := map[string]string{}
+	original := NewDynamicManagementClient
+	NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+		return &stubDynamicManagementClient{
+			listGroupFn: func(groupName string) ([]mlmanage.GroupHost, error) { return hosts, nil },
+			setHostNameFn: func(hostName, newName string) error {
+				renames[hostName] = newName
+				return nil
+			},
+		}
+	}
+	t.Cleanup(func() { NewDynamicManagementClient = original })
+	recovery := func(pod string) marklogicv1.HostRecoveryStatus {
+		t.Helper()
+		for _, recovery := range cr.Status.HostRecovery {
+			if recovery.Pod == pod {
+				return recovery
+			}
+		}
+		t.Fatalf("expected pod %s to be recovered, got %+v", pod, cr.Status.HostRecovery)
+		return marklogicv1.HostRecoveryStatus{}
+	}
+
+	if res := cc.ReconcileHostRecovery(); res.Completed() {
+		t.Fatalf("expected the host recovery never to hold up the reconcile")
+	}
+	err := cc.Client.Get(cc.Ctx, types.NamespacedName{Namespace: "default", Name: "dnode-1"}, &corev1.Pod{})
+	if !apierrors.IsNotFound(err) {
+		t.Fatalf("expected the pod of the offline host to be restarted, got %v", err)
+	}
+	if len(renames) != 1 || renames["10.0.0.7"] != "dnode-2.dnode.default.svc.cluster.local" {
+		t.Fatalf("expected the host known by its old address to be renamed, got %v", renames)
+	}
+	if len(cr.Status.HostRecovery) != 2 || recovery("dnode-1").Attempts != 1 || recovery("dnode-2").Attempts != 1 {
+		t.Fatalf("expected one attempt for each host, got %+v", cr.Status.HostRecovery)
+	}
+
+	// Nothing is retried before the backoff passed, and the restarted pod is
+	// tracked until it is back.
+	renames = map[string]string{}
+	cc.ReconcileHostRecovery()
+	if len(renames) != 0 || len(cr.Status.HostRecovery) != 2 || recovery("dnode-2").Attempts != 1 {
+		t.Fatalf("expected no attempt before the backoff passed, got %v %+v", renames, cr.Status.HostRecovery)
+	}
+
+	if err := cc.Client.Create(cc.Ctx, readyPod("dnode-1")); err != nil {
+		t.Fatalf("failed to create pod: %v", err)
+	}
+	hosts = []mlmanage.GroupHost{
+		{Name: "dnode-0.dnode.default.svc.cluster.local", Online: true},
+		{Name: "dnode-1.dnode.default.svc.cluster.local", Online: true},
+		{Name: "dnode-2.dnode.default.svc.cluster.local", Online: true},
+	}
+	cc.ReconcileHostRecovery()
+	if cr.Status.HostRecovery != nil {
+		t.Fatalf("expected the recovered hosts to no longer be tracked, got %+v", cr.Status.HostRecovery)
+	}
+	recovered := 0
+	for len(recorder.Events) > 0 {
+		if event := <-recorder.Events; strings.Contains(event, hostRecoveryReasonRecovered) {
+			recovered++
+		}
+	}
+	if recovered != 2 {
+		t.Fatalf("expected a %s event for each host, got %d", hostRecoveryReasonRecovered, recovered)
+	}
+
+	// The recovery fails once the attempts ran out.
+	hosts[2].Online = false
+	cr.Status.HostRecovery = []marklogicv1.HostRecoveryStatus{{
+		Pod: "dnode-2", Attempts: hostRecoveryMaxAttempts, LastAttemptTime: &metav1.Time{Time: time.Now().Add(-time.Hour)},
+	}}
+	if err := cc.Client.Status().Update(cc.Ctx, cr); err != nil {
+		t.Fatalf("failed to update cluster: %v", err)
+	}
+	cc.ReconcileHostRecovery()
+	if failed := recovery("dnode-2"); !failed.Failed || failed.Attempts != hostRecoveryMaxAttempts {
+		t.Fatalf("expected the recovery to fail, got %+v", failed)
+	}
+	if err := cc.Client.Get(cc.Ctx, types.NamespacedName{Namespace: "default", Name: "dnode-2"}, &corev1.Pod{}); err != nil {
+		t.Fatalf("expected the pod not to be restarted once the recovery failed, got %v", err)
+	}
+}
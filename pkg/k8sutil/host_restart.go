@@ -0,0 +1,224 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// RestartHostsAnnotation lists the MarkLogic pods of a cluster, separated
+	// by commas, that the operator restarts one at a time behind the health
+	// gates of spec.upgrade. Changing the value requests a new restart, even
+	// of the same pods.
+	RestartHostsAnnotation = "marklogic.progress.com/restart-hosts"
+
+	rolloutLockHostRestart = "HostRestart"
+
+	hostRestartReasonStarted          = "HostRestartStarted"
+	hostRestartReasonPodRestarted     = "HostRestartPodRestarted"
+	hostRestartReasonCompleted        = "HostRestartCompleted"
+	hostRestartReasonFailed           = "HostRestartFailed"
+	hostRestartReasonCancelled        = "HostRestartCancelled"
+	hostRestartReasonHealthGateFailed = "HostRestartHealthGateFailed"
+)
+
+// ReconcileHostRestart restarts the pods named in the RestartHostsAnnotation
+// the way an upgrade restarts outdated pods: one at a time, bootstrap group
+// and highest ordinal first, each taken out of HAProxy, held back by merges
+// and reindexing on its host, and only once every pod of the cluster is
+// ready. The next pod is restarted after the health gates of the previous
+// one passed. The restart holds the rollout lock, and waits while an upgrade
+// or a resource rollout holds it. Removing the annotation cancels the pods
+// not restarted yet.
+func (cc *ClusterContext) ReconcileHostRestart() result.ReconcileResult {
+	cr := cc.MarklogicCluster
+	request := strings.TrimSpace(ClusterOperation(cr, RestartHostsAnnotation))
+	restart := &marklogicv1.HostRestartStatus{}
+	if cr.Status.HostRestart != nil {
+		restart = cr.Status.HostRestart.DeepCopy()
+	}
+	now := metav1.Now()
+	if restart.Request != request {
+		if restart.State == marklogicv1.HostRestartInProgress && restart.PodRestart != nil {
+			// Let the pod restarted last come back before changing course.
+			return cc.checkHostRestartPod(restart, now)
+		}
+		if request == "" {
+			restart.Request = ""
+			if restart.State != marklogicv1.HostRestartInProgress {
+				// Adding the annotation again restarts the same pods again.
+				return cc.setHostRestartStatus(restart, result.Continue())
+			}
+			restart.State = marklogicv1.HostRestartCancelled
+			restart.CompletionTime = &now
+			restart.PodHold = nil
+			restart.Message = fmt.Sprintf("cancelled, %d pod(s) were not restarted: %s", len(restart.Pending), strings.Join(restart.Pending, ", "))
+			restart.Pending = nil
+			cc.recordClusterEvent(corev1.EventTypeNormal, hostRestartReasonCancelled, restart.Message)
+			return cc.setHostRestartStatus(restart, result.Continue())
+		}
+		return cc.startHostRestart(request, now)
+	}
+	if restart.State != marklogicv1.HostRestartInProgress {
+		return result.Continue()
+	}
+	if holder := rolloutLockHolder(cr); holder != "" && holder != rolloutLockHostRestart {
+		restart.Message = fmt.Sprintf("waiting for the %s to release the rollout lock", holder)
+		return cc.setHostRestartStatus(restart, result.RequeueSoon(upgradePollIntervalSeconds))
+	}
+	if restart.PodRestart != nil {
+		return cc.checkHostRestartPod(restart, now)
+	}
+	if len(restart.Pending) == 0 {
+		restart.State = marklogicv1.HostRestartCompleted
+		restart.CompletionTime = &now
+		restart.PodHold = nil
+		restart.Message = fmt.Sprintf("restarted %s", strings.Join(restart.Restarted, ", "))
+		cc.recordClusterEvent(corev1.EventTypeNormal, hostRestartReasonCompleted, restart.Message)
+		return cc.setHostRestartStatus(restart, result.Continue())
+	}
+
+	waiting, err := cc.groupsNotReady(false)
+	if err != nil {
+		return result.Error(err)
+	}
+	if waiting != "" {
+		restart.Message = fmt.Sprintf("waiting for all pods to be ready before restarting pod %s: %s", restart.Pending[0], waiting)
+		return cc.setHostRestartStatus(restart, result.RequeueSoon(upgradePollIntervalSeconds))
+	}
+	pod := &corev1.Pod{}
+	if err := cc.Client.Get(cc.Ctx, types.NamespacedName{Namespace: cr.Namespace, Name: restart.Pending[0]}, pod); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return result.Error(err)
+		}
+		return cc.failHostRestart(restart, fmt.Sprintf("pod %s no longer exists", restart.Pending[0]), now)
+	}
+	if held, message := cc.holdPodRestart(pod, &restart.PodHold, now); held {
+		restart.Message = message
+		return cc.setHostRestartStatus(restart, result.RequeueSoon(backgroundJobRequeueSeconds))
+	}
+	if err := cc.syncHAProxyServers(pod.Name); err != nil {
+		return result.Error(err)
+	}
+	if err := cc.Client.Delete(cc.Ctx, pod); err != nil && !apierrors.IsNotFound(err) {
+		return result.Error(err)
+	}
+	restart.Pending = restart.Pending[1:]
+	restart.PodRestart = &marklogicv1.UpgradePodRestart{Pod: pod.Name, PodUID: pod.UID, StartTime: now}
+	restart.Message = fmt.Sprintf("restarted pod %s, %d pod(s) left", pod.Name, len(restart.Pending))
+	cc.recordClusterEvent(corev1.EventTypeNormal, hostRestartReasonPodRestarted, restart.Message)
+	return cc.setHostRestartStatus(restart, result.RequeueSoon(healthGateRequeueSeconds))
+}
+
+// startHostRestart validates the pods of a new request and orders them like
+// the restarts of an upgrade. A request naming pods that are not MarkLogic
+// pods of the cluster fails without restarting any of them.
+func (cc *ClusterContext) startHostRestart(request string, now metav1.Time) result.ReconcileResult {
+	cr := cc.MarklogicCluster
+	restart := &marklogicv1.HostRestartStatus{Request: request, StartTime: &now}
+	// Pods restart by the position of their group, bootstrap group first.
+	groups := map[string]int{}
+	for i, group := range cr.Spec.MarkLogicGroups {
+		if group == nil {
+			continue
+		}
+		groups[group.Name] = i + 1
+		if group.IsBootstrap {
+			groups[group.Name] = 0
+		}
+	}
+	pods := []corev1.Pod{}
+	invalid := []string{}
+	for _, name := range strings.Split(request, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" || slices.ContainsFunc(pods, func(pod corev1.Pod) bool { return pod.Name == name }) {
+			continue
+		}
+		pod := corev1.Pod{}
+		err := cc.Client.Get(cc.Ctx, types.NamespacedName{Namespace: cr.Namespace, Name: name}, &pod)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return result.Error(err)
+		}
+		_, ok := groups[pod.Labels["app.kubernetes.io/instance"]]
+		if err != nil || pod.Labels["app.kubernetes.io/name"] != "marklogic" || !ok {
+			invalid = append(invalid, name)
+			continue
+		}
+		pods = append(pods, pod)
+	}
+	if len(invalid) > 0 {
+		return cc.failHostRestart(restart, fmt.Sprintf("not MarkLogic pods of the cluster: %s", strings.Join(invalid, ", ")), now)
+	}
+	if len(pods) == 0 {
+		return cc.failHostRestart(restart, fmt.Sprintf("%s names no pods", RestartHostsAnnotation), now)
+	}
+	sort.SliceStable(pods, func(i, j int) bool {
+		groupI, groupJ := groups[pods[i].Labels["app.kubernetes.io/instance"]], groups[pods[j].Labels["app.kubernetes.io/instance"]]
+		if groupI != groupJ {
+			return groupI < groupJ
+		}
+		return parseOrdinalFromName(pods[i].Name) > parseOrdinalFromName(pods[j].Name)
+	})
+	for _, pod := range pods {
+		restart.Pending = append(restart.Pending, pod.Name)
+	}
+	restart.State = marklogicv1.HostRestartInProgress
+	restart.Message = fmt.Sprintf("restarting %s one at a time", strings.Join(restart.Pending, ", "))
+	cc.ReqLogger.Info("Restarting hosts", "pods", restart.Pending)
+	cc.recordClusterEvent(corev1.EventTypeNormal, hostRestartReasonStarted, restart.Message)
+	return cc.setHostRestartStatus(restart, result.RequeueSoon(1))
+}
+
+// checkHostRestartPod waits for the health gates of the pod restarted last.
+func (cc *ClusterContext) checkHostRestartPod(restart *marklogicv1.HostRestartStatus, now metav1.Time) result.ReconcileResult {
+	check, err := cc.checkRestartedPod(restart.PodRestart, hostRestartReasonHealthGateFailed, now)
+	if err != nil {
+		return result.Error(err)
+	}
+	if check.failed {
+		restart.PodRestart = nil
+		return cc.failHostRestart(restart, check.message, now)
+	}
+	if !check.passed {
+		restart.Message = check.message
+		return cc.setHostRestartStatus(restart, result.RequeueSoon(healthGateRequeueSeconds))
+	}
+	restart.Restarted = append(restart.Restarted, restart.PodRestart.Pod)
+	restart.PodRestart = nil
+	return cc.setHostRestartStatus(restart, result.RequeueSoon(1))
+}
+
+func (cc *ClusterContext) failHostRestart(restart *marklogicv1.HostRestartStatus, message string, now metav1.Time) result.ReconcileResult {
+	restart.State = marklogicv1.HostRestartFailed
+	restart.CompletionTime = &now
+	restart.PodHold = nil
+	restart.Message = message
+	cc.recordClusterEvent(corev1.EventTypeWarning, hostRestartReasonFailed, message)
+	return cc.setHostRestartStatus(restart, result.Continue())
+}
+
+func (cc *ClusterContext) setHostRestartStatus(restart *marklogicv1.HostRestartStatus, next result.ReconcileResult) result.ReconcileResult {
+	cr := cc.MarklogicCluster
+	patchBase := client.MergeFrom(cr.DeepCopy())
+	cr.Status.HostRestart = restart
+	// A restart waiting for another workflow leaves its lock alone.
+	holder := rolloutLockHolder(cr)
+	setRolloutLock(cr, rolloutLockHostRestart, restart.State == marklogicv1.HostRestartInProgress && (holder == "" || holder == rolloutLockHostRestart),
+		fmt.Sprintf("host restart restarts %d pod(s)", len(restart.Pending)+len(restart.Restarted)))
+	if err := patchStatus(cc.Ctx, cc.Client, cr, patchBase); err != nil {
+		return result.Error(err)
+	}
+	return next
+}
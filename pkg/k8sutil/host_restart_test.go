@@ -0,0 +1,193 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func newHostRestartTestCluster(request string) *marklogicv1.MarklogicCluster {
+	replicas := int32(2)
+	return &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default", Annotations: map[string]string{RestartHostsAnnotation: request}},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			Image:           upgradeTestOldImage,
+			ClusterDomain:   "cluster.local",
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{{Name: "dnode", Replicas: &replicas, IsBootstrap: true}},
+			Upgrade: &marklogicv1.UpgradeSpec{HealthGate: []marklogicv1.HealthGate{
+				{Name: "online", Type: marklogicv1.HealthGateHostOnline, Timeout: &metav1.Duration{Duration: time.Minute}},
+			}},
+		},
+	}
+}
+
+func TestReconcileHostRestartRestartsNamedPodsOneAtATime(t *testing.T) {
+	cr := newHostRestartTestCluster("dnode-0, dnode-1")
+	replicas := int32(2)
+	sts := newUpgradeTestStatefulSet("dnode", upgradeTestOldImage, 2)
+	sts.Spec.Replicas = &replicas
+	cc := newUpgradeTestContext(t, cr, sts, newUpgradeTestPod("dnode-0", "dnode-v1", true), newUpgradeTestPod("dnode-1", "dnode-v1", true))
+	online := map[string]bool{}
+	original := NewDynamicManagementClient
+	NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+		return &stubDynamicManagementClient{hostsStatusFn: func() ([]mlmanage.HostStatus, error) {
+			return []mlmanage.HostStatus{
+				{Name: "dnode-0.dnode.default.svc.cluster.local", Online: online["dnode-0"]},
+				{Name: "dnode-1.dnode.default.svc.cluster.local", Online: online["dnode-1"]},
+			}, nil
+		}}
+	}
+	t.Cleanup(func() { NewDynamicManagementClient = original })
+	podExists := func(name string) bool {
+		return cc.Client.Get(cc.Ctx, client.ObjectKey{Namespace: "default", Name: name}, &corev1.Pod{}) == nil
+	}
+
+	cc.ReconcileHostRestart()
+	restart := cr.Status.HostRestart
+	if restart == nil || restart.State != marklogicv1.HostRestartInProgress || !slices.Equal(restart.Pending, []string{"dnode-1", "dnode-0"}) {
+		t.Fatalf("expected the pods to restart highest ordinal first, got %+v", restart)
+	}
+	if holder := rolloutLockHolder(cr); holder != rolloutLockHostRestart {
+		t.Fatalf("expected the host restart to hold the rollout lock, held by %q", holder)
+	}
+
+	cc.ReconcileHostRestart()
+	if restart := cr.Status.HostRestart; restart.PodRestart == nil || restart.PodRestart.Pod != "dnode-1" || podExists("dnode-1") || !podExists("dnode-0") {
+		t.Fatalf("expected only dnode-1 to be restarted, got %+v", restart)
+	}
+
+	if err := cc.Client.Create(cc.Ctx, newUpgradeTestPod("dnode-1", "dnode-v1", true)); err != nil {
+		t.Fatalf("failed to recreate pod: %v", err)
+	}
+	cc.ReconcileHostRestart()
+	if restart := cr.Status.HostRestart; restart.Message != "waiting for health gate online of pod dnode-1: host is offline" || !podExists("dnode-0") {
+		t.Fatalf("expected to wait for the host to come online, got %+v", restart)
+	}
+
+	online["dnode-1"] = true
+	cc.ReconcileHostRestart()
+	cc.ReconcileHostRestart()
+	if restart := cr.Status.HostRestart; !slices.Equal(restart.Restarted, []string{"dnode-1"}) || restart.PodRestart == nil ||
+		restart.PodRestart.Pod != "dnode-0" || podExists("dnode-0") {
+		t.Fatalf("expected dnode-0 to be restarted once dnode-1 passed its health gates, got %+v", restart)
+	}
+
+	if err := cc.Client.Create(cc.Ctx, newUpgradeTestPod("dnode-0", "dnode-v1", true)); err != nil {
+		t.Fatalf("failed to recreate pod: %v", err)
+	}
+	online["dnode-0"] = true
+	cc.ReconcileHostRestart()
+	cc.ReconcileHostRestart()
+	restart = cr.Status.HostRestart
+	if restart.State != marklogicv1.HostRestartCompleted || !slices.Equal(restart.Restarted, []string{"dnode-1", "dnode-0"}) || restart.CompletionTime == nil {
+		t.Fatalf("expected the restart to complete, got %+v", restart)
+	}
+	if holder := rolloutLockHolder(cr); holder != "" {
+		t.Fatalf("expected the completed restart to release the rollout lock, held by %q", holder)
+	}
+	if res := cc.ReconcileHostRestart(); res.Completed() || !podExists("dnode-0") || !podExists("dnode-1") {
+		t.Fatalf("expected the same request not to restart the pods again")
+	}
+}
+
+func TestReconcileHostRestartRejectsUnknownPods(t *testing.T) {
+	cr := newHostRestartTestCluster("dnode-1,other-0")
+	other := newUpgradeTestPod("other-0", "other-v1", true)
+	other.Labels["app.kubernetes.io/instance"] = "other"
+	cc := newUpgradeTestContext(t, cr, newUpgradeTestPod("dnode-1", "dnode-v1", true), other)
+
+	cc.ReconcileHostRestart()
+	restart := cr.Status.HostRestart
+	if restart == nil || restart.State != marklogicv1.HostRestartFailed || restart.Message != "not MarkLogic pods of the cluster: other-0" || len(restart.Pending) != 0 {
+		t.Fatalf("expected the request to fail on the pod of another cluster, got %+v", restart)
+	}
+	if err := cc.Client.Get(cc.Ctx, client.ObjectKey{Namespace: "default", Name: "dnode-1"}, &corev1.Pod{}); err != nil {
+		t.Fatalf("expected no pod to be restarted: %v", err)
+	}
+}
+
+func TestReconcileHostRestartWaitsForTheUpgrade(t *testing.T) {
+	cr := newHostRestartTestCluster("dnode-1")
+	cr.Status.HostRestart = &marklogicv1.HostRestartStatus{
+		Request: "dnode-1",
+		State:   marklogicv1.HostRestartInProgress,
+		Pending: []string{"dnode-1"},
+	}
+	setRolloutLock(cr, rolloutLockUpgrade, true, "upgrade restarts pods")
+	cc := newUpgradeTestContext(t, cr, newUpgradeTestPod("dnode-1", "dnode-v1", true))
+
+	cc.ReconcileHostRestart()
+	if restart := cr.Status.HostRestart; restart.Message != "waiting for the Upgrade to release the rollout lock" {
+		t.Fatalf("expected the restart to wait for the upgrade, got %+v", restart)
+	}
+	if holder := rolloutLockHolder(cr); holder != rolloutLockUpgrade {
+		t.Fatalf("expected the upgrade to keep the rollout lock, held by %q", holder)
+	}
+
+	delete(cr.Annotations, RestartHostsAnnotation)
+	cc.ReconcileHostRestart()
+	if restart := cr.Status.HostRestart; restart.State != marklogicv1.HostRestartCancelled || len(restart.Pending) != 0 {
+		t.Fatalf("expected removing the annotation to cancel the restart, got %+v", restart)
+	}
+	if err := cc.Client.Get(cc.Ctx, client.ObjectKey{Namespace: "default", Name: "dnode-1"}, &corev1.Pod{}); err != nil {
+		t.Fatalf("expected the cancelled restart not to restart the pod: %v", err)
+	}
+}
+
+func TestReconcileHostRestartWaitsForTheTerminatingPod(t *testing.T) {
+	cr := newHostRestartTestCluster("dnode-0")
+	replicas := int32(1)
+	cr.Spec.MarkLogicGroups[0].Replicas = &replicas
+	sts := newUpgradeTestStatefulSet("dnode", upgradeTestOldImage, 1)
+	sts.Spec.Replicas = &replicas
+	// The finalizer keeps the deleted pod terminating, and still ready.
+	terminating := newUpgradeTestPod("dnode-0", "dnode-v1", true)
+	terminating.UID = "dnode-0-v1"
+	terminating.Finalizers = []string{"test/drain"}
+	cc := newUpgradeTestContext(t, cr, sts, terminating)
+	original := NewDynamicManagementClient
+	NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+		return &stubDynamicManagementClient{hostsStatusFn: func() ([]mlmanage.HostStatus, error) {
+			return []mlmanage.HostStatus{{Name: "dnode-0.dnode.default.svc.cluster.local", Online: true}}, nil
+		}}
+	}
+	t.Cleanup(func() { NewDynamicManagementClient = original })
+
+	cc.ReconcileHostRestart()
+	cc.ReconcileHostRestart()
+	if restart := cr.Status.HostRestart; restart.PodRestart == nil || restart.PodRestart.PodUID != "dnode-0-v1" {
+		t.Fatalf("expected the restart of dnode-0 to record its UID, got %+v", restart)
+	}
+	cc.ReconcileHostRestart()
+	if restart := cr.Status.HostRestart; restart.State != marklogicv1.HostRestartInProgress || len(restart.Restarted) != 0 ||
+		restart.Message != "waiting for health gate pod-ready of pod dnode-0: pod has not been replaced yet" {
+		t.Fatalf("expected to wait while the pod terminates, got %+v", restart)
+	}
+
+	pod := &corev1.Pod{}
+	if err := cc.Client.Get(cc.Ctx, client.ObjectKey{Namespace: "default", Name: "dnode-0"}, pod); err != nil {
+		t.Fatal(err)
+	}
+	pod.Finalizers = nil
+	if err := cc.Client.Update(cc.Ctx, pod); err != nil {
+		t.Fatalf("failed to remove the finalizer: %v", err)
+	}
+	replacement := newUpgradeTestPod("dnode-0", "dnode-v1", true)
+	replacement.UID = "dnode-0-v2"
+	if err := cc.Client.Create(cc.Ctx, replacement); err != nil {
+		t.Fatalf("failed to create pod: %v", err)
+	}
+	cc.ReconcileHostRestart()
+	cc.ReconcileHostRestart()
+	if restart := cr.Status.HostRestart; restart.State != marklogicv1.HostRestartCompleted || !slices.Equal(restart.Restarted, []string{"dnode-0"}) {
+		t.Fatalf("expected the replacement pod to complete the restart, got %+v", restart)
+	}
+}
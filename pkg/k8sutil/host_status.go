@@ -0,0 +1,196 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlversion"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// hostStatusInterval is how often the hosts in the status are refreshed.
+const hostStatusInterval = time.Minute
+
+// ReconcileHostStatus reads the MarkLogic hosts and forests from the Manage
+// API every hostStatusInterval and reports them for every pod in the status
+// of its MarklogicGroup, next to the restarts of its MarkLogic container. The
+// groups are rolled up in status.hosts of the cluster, the oldest version the
+// hosts run in status.serverVersion, and hosts running another version than
+// the image tag of their pod in the ServerVersionMismatch condition. Failures
+// are logged and never hold up the rest of the reconcile.
+func (cc *ClusterContext) ReconcileHostStatus() result.ReconcileResult {
+	cr := cc.MarklogicCluster
+	now := metav1.Now()
+	if status := cr.Status.Hosts; status != nil && status.LastUpdateTime != nil && now.Sub(status.LastUpdateTime.Time) < hostStatusInterval {
+		return result.Continue()
+	}
+	// Pods that have not joined the cluster yet are still reported, offline.
+	var hosts []mlmanage.HostStatus
+	var forests []mlmanage.ForestStatus
+	if !clusterStopped(cr) {
+		mgmt, err := cc.newBootstrapManagementClient()
+		if err == nil {
+			hosts, err = mgmt.ListHostsStatus(cc.Ctx)
+		}
+		if err == nil {
+			forests, err = mgmt.ListForestsStatus(cc.Ctx)
+		}
+		if err != nil {
+			cc.ReqLogger.Error(err, "Failed to read the MarkLogic hosts for the status")
+		}
+	}
+	hostByName := map[string]mlmanage.HostStatus{}
+	for _, host := range hosts {
+		hostByName[strings.ToLower(host.Name)] = host
+	}
+	forestsByHost := map[string]int32{}
+	for _, forest := range forests {
+		forestsByHost[strings.ToLower(forest.Host)]++
+	}
+
+	status := &marklogicv1.ClusterHostsStatus{LastUpdateTime: &now}
+	mismatches := []string{}
+	for _, group := range cr.Spec.MarkLogicGroups {
+		if group == nil {
+			continue
+		}
+		groupHosts, groupMismatches, err := cc.groupHostStatus(group, hostByName, forestsByHost)
+		if err != nil {
+			cc.ReqLogger.Error(err, "Failed to report the hosts of the group", "group", group.Name)
+			continue
+		}
+		mismatches = append(mismatches, groupMismatches...)
+		if err := cc.setGroupHostStatus(group.Name, groupHosts, now); err != nil {
+			cc.ReqLogger.Error(err, "Failed to update the hosts in the MarklogicGroup status", "group", group.Name)
+		}
+		status.Groups = append(status.Groups, groupHostsSummary(group.Name, groupHosts))
+	}
+
+	patchBase := client.MergeFrom(cr.DeepCopy())
+	cr.Status.Hosts = status
+	// The last known version is kept while the Manage API cannot be reached.
+	if len(hosts) > 0 {
+		cr.Status.ServerVersion = oldestHostVersion(hosts)
+		if len(mismatches) > 0 {
+			cc.setClusterCondition(marklogicv1.ServerVersionMismatch, metav1.ConditionTrue, "VersionMismatch", strings.Join(mismatches, "; "))
+		} else {
+			cc.setClusterCondition(marklogicv1.ServerVersionMismatch, metav1.ConditionFalse, "VersionsMatch", "the hosts run the versions of their image tags")
+		}
+	}
+	if err := patchStatus(cc.Ctx, cc.Client, cr, patchBase); err != nil {
+		cc.ReqLogger.Error(err, "Failed to update the hosts in the cluster status")
+	}
+	return result.Continue()
+}
+
+// groupHostStatus returns the host of every pod of a group, ordered by pod,
+// and the pods whose host runs another version than their image tag.
+func (cc *ClusterContext) groupHostStatus(group *marklogicv1.MarklogicGroups, hostByName map[string]mlmanage.HostStatus, forestsByHost map[string]int32) ([]marklogicv1.HostStatus, []string, error) {
+	cr := cc.MarklogicCluster
+	pods := &corev1.PodList{}
+	if err := cc.Client.List(cc.Ctx, pods, client.InNamespace(cr.Namespace), client.MatchingLabels{
+		"app.kubernetes.io/name":     "marklogic",
+		"app.kubernetes.io/instance": group.Name,
+	}); err != nil {
+		return nil, nil, err
+	}
+	hosts := []marklogicv1.HostStatus{}
+	mismatches := []string{}
+	for _, pod := range pods.Items {
+		status := marklogicv1.HostStatus{Pod: pod.Name}
+		fqdn := strings.ToLower(cc.podHostFQDN(pod))
+		if host, ok := hostByName[fqdn]; ok {
+			status.Host = host.Name
+			status.Online = host.Online
+			status.Version = host.Version
+			status.Forests = forestsByHost[fqdn]
+		}
+		if container := marklogicServerContainer(pod.Spec.Containers); container != nil && status.Version != "" {
+			if matches, _ := mlversion.MatchesImage(status.Version, container.Image); !matches {
+				mismatches = append(mismatches, fmt.Sprintf("pod %s runs %s, not the version of %s", pod.Name, status.Version, container.Image))
+			}
+		}
+		for _, container := range pod.Status.ContainerStatuses {
+			if container.Name != "marklogic-server" {
+				continue
+			}
+			status.Restarts = container.RestartCount
+			if container.State.Running != nil {
+				startedAt := container.State.Running.StartedAt
+				status.LastRestartTime = &startedAt
+			}
+		}
+		hosts = append(hosts, status)
+	}
+	sort.Slice(hosts, func(i, j int) bool { return hosts[i].Pod < hosts[j].Pod })
+	sort.Strings(mismatches)
+	return hosts, mismatches, nil
+}
+
+// setGroupHostStatus writes the hosts to the status of the MarklogicGroup
+// when they changed.
+func (cc *ClusterContext) setGroupHostStatus(name string, hosts []marklogicv1.HostStatus, now metav1.Time) error {
+	group := &marklogicv1.MarklogicGroup{}
+	err := cc.Client.Get(cc.Ctx, types.NamespacedName{Namespace: cc.MarklogicCluster.Namespace, Name: name}, group)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if equality.Semantic.DeepEqual(group.Status.Hosts, hosts) {
+		return nil
+	}
+	patchBase := client.MergeFrom(group.DeepCopy())
+	group.Status.Hosts = hosts
+	group.Status.HostsUpdateTime = &now
+	return patchStatus(cc.Ctx, cc.Client, group, patchBase)
+}
+
+func groupHostsSummary(name string, hosts []marklogicv1.HostStatus) marklogicv1.GroupHostsStatus {
+	summary := marklogicv1.GroupHostsStatus{Group: name, Hosts: int32(len(hosts))}
+	versions := map[string]bool{}
+	for _, host := range hosts {
+		if host.Online {
+			summary.OnlineHosts++
+		}
+		summary.Forests += host.Forests
+		if host.Version != "" && !versions[host.Version] {
+			versions[host.Version] = true
+			summary.Versions = append(summary.Versions, host.Version)
+		}
+	}
+	sort.Strings(summary.Versions)
+	return summary
+}
+
+// oldestHostVersion returns the oldest version the hosts report.
+func oldestHostVersion(hosts []mlmanage.HostStatus) string {
+	oldest := ""
+	for _, host := range hosts {
+		if host.Version != "" && (oldest == "" || mlversion.Compare(host.Version, oldest) < 0) {
+			oldest = host.Version
+		}
+	}
+	return oldest
+}
+
+// nextHostStatusRefresh is when the hosts in the status are refreshed next.
+func nextHostStatusRefresh(cr *marklogicv1.MarklogicCluster) time.Time {
+	if cr.Status.Hosts == nil || cr.Status.Hosts.LastUpdateTime == nil {
+		return time.Time{}
+	}
+	return cr.Status.Hosts.LastUpdateTime.Add(hostStatusInterval)
+}
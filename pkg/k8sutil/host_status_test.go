@@ -0,0 +1,93 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"testing"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestReconcileHostStatusReportsHostsAndRollsThemUp(t *testing.T) {
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			ClusterDomain:   "cluster.local",
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{{Name: "dnode", IsBootstrap: true}},
+		},
+	}
+	group := &marklogicv1.MarklogicGroup{ObjectMeta: metav1.ObjectMeta{Name: "dnode", Namespace: "default"}}
+	startedAt := metav1.Now()
+	pod := func(name string, restarts int32) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{
+				"app.kubernetes.io/name":     "marklogic",
+				"app.kubernetes.io/instance": "dnode",
+			}},
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "marklogic-server", Image: "progressofficial/marklogic-db:12.0.1-ubi-rootless"}}},
+			Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+				Name:         "marklogic-server",
+				RestartCount: restarts,
+				State:        corev1.ContainerState{Running: &corev1.ContainerStateRunning{StartedAt: startedAt}},
+			}}},
+		}
+	}
+	cc := newUpgradeTestContext(t, cr, group, pod("dnode-0", 2), pod("dnode-1", 0))
+
+	original := NewDynamicManagementClient
+	NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+		return &stubDynamicManagementClient{
+			hostsStatusFn: func() ([]mlmanage.HostStatus, error) {
+				return []mlmanage.HostStatus{
+					{Name: "dnode-0.dnode.default.svc.cluster.local", Online: true, Version: "12.0.1"},
+					{Name: "dnode-1.dnode.default.svc.cluster.local", Online: false, Version: "12.0.0"},
+				}, nil
+			},
+			forestsStatusFn: func() ([]mlmanage.ForestStatus, error) {
+				return []mlmanage.ForestStatus{
+					{Name: "Documents", Host: "dnode-0.dnode.default.svc.cluster.local"},
+					{Name: "Security", Host: "dnode-0.dnode.default.svc.cluster.local"},
+					{Name: "Meters", Host: "dnode-1.dnode.default.svc.cluster.local"},
+				}, nil
+			},
+		}
+	}
+	defer func() { NewDynamicManagementClient = original }()
+
+	if res := cc.ReconcileHostStatus(); res.Completed() {
+		t.Fatalf("expected the reconcile to continue")
+	}
+
+	updated := &marklogicv1.MarklogicGroup{}
+	if err := cc.Client.Get(cc.Ctx, types.NamespacedName{Namespace: "default", Name: "dnode"}, updated); err != nil {
+		t.Fatalf("failed to get the group: %v", err)
+	}
+	hosts := updated.Status.Hosts
+	if len(hosts) != 2 || hosts[0].Pod != "dnode-0" || !hosts[0].Online || hosts[0].Forests != 2 || hosts[0].Restarts != 2 ||
+		hosts[0].LastRestartTime == nil || hosts[1].Online || hosts[1].Forests != 1 {
+		t.Fatalf("unexpected group hosts %+v", hosts)
+	}
+
+	status := cc.MarklogicCluster.Status.Hosts
+	if status == nil || len(status.Groups) != 1 {
+		t.Fatalf("expected the hosts to be rolled up in the cluster status, got %+v", status)
+	}
+	summary := status.Groups[0]
+	if summary.Hosts != 2 || summary.OnlineHosts != 1 || summary.Forests != 3 || len(summary.Versions) != 2 || summary.Versions[0] != "12.0.0" {
+		t.Fatalf("unexpected group summary %+v", summary)
+	}
+	// dnode-1 still runs 12.0.0 on the 12.0.1 image.
+	if version := cc.MarklogicCluster.Status.ServerVersion; version != "12.0.0" {
+		t.Fatalf("expected the oldest host version in the status, got %q", version)
+	}
+	if cc.MarklogicCluster.Status.GetConditionStatus(string(marklogicv1.ServerVersionMismatch)) != metav1.ConditionTrue {
+		t.Fatalf("expected the version mismatch of dnode-1 to be reported, got %+v", cc.MarklogicCluster.Status.Conditions)
+	}
+	if next := nextHostStatusRefresh(cc.MarklogicCluster); next.IsZero() {
+		t.Fatalf("expected the next refresh to be scheduled")
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const hostZoneReasonSet = "HostZoneSet"
+
+// ReconcileHostZones sets the zone of the MarkLogic hosts of the groups with
+// zoneAwareness to the zone label of the node their pod runs on, so forest
+// replicas are placed in another failure domain than their master forests.
+// The zones set are recorded in status.hostZones and only set again when the
+// pod moves to another zone. Hosts that have not joined the cluster yet are
+// retried on a later reconcile, and failures never hold up the rest of it.
+func (cc *ClusterContext) ReconcileHostZones() result.ReconcileResult {
+	cr := cc.MarklogicCluster
+	if clusterStopped(cr) {
+		return result.Continue()
+	}
+	recorded := map[string]string{}
+	for _, hostZone := range cr.Status.HostZones {
+		recorded[hostZone.Host] = hostZone.Zone
+	}
+	zones := []marklogicv1.HostZone{}
+	pending := map[string][]marklogicv1.HostZone{}
+	groupNames := []string{}
+	nodes := map[string]*corev1.Node{}
+	for _, group := range cr.Spec.MarkLogicGroups {
+		if group == nil || group.ZoneAwareness == nil {
+			continue
+		}
+		pods := &corev1.PodList{}
+		if err := cc.Client.List(cc.Ctx, pods, client.InNamespace(cr.Namespace), client.MatchingLabels{
+			"app.kubernetes.io/name":     "marklogic",
+			"app.kubernetes.io/instance": group.Name,
+		}); err != nil {
+			cc.ReqLogger.Error(err, "Failed to list the pods of the group for their zones", "group", group.Name)
+			continue
+		}
+		groupName := group.Name
+		if group.GroupConfig != nil && strings.TrimSpace(group.GroupConfig.Name) != "" {
+			groupName = group.GroupConfig.Name
+		}
+		for _, pod := range pods.Items {
+			if pod.Spec.NodeName == "" {
+				continue
+			}
+			node, ok := nodes[pod.Spec.NodeName]
+			if !ok {
+				node = &corev1.Node{}
+				if err := cc.Client.Get(cc.Ctx, types.NamespacedName{Name: pod.Spec.NodeName}, node); err != nil {
+					cc.ReqLogger.Error(err, "Failed to read the node of the pod for its zone", "pod", pod.Name, "node", pod.Spec.NodeName)
+					continue
+				}
+				nodes[pod.Spec.NodeName] = node
+			}
+			zone := node.Labels[hostZoneTopologyKey(group.ZoneAwareness)]
+			if zone == "" {
+				continue
+			}
+			hostZone := marklogicv1.HostZone{Host: strings.ToLower(cc.podHostFQDN(pod)), Zone: zone}
+			if recorded[hostZone.Host] == zone {
+				zones = append(zones, hostZone)
+				continue
+			}
+			if pending[groupName] == nil {
+				groupNames = append(groupNames, groupName)
+			}
+			pending[groupName] = append(pending[groupName], hostZone)
+		}
+	}
+
+	if len(groupNames) > 0 {
+		mgmtClient, err := cc.newBootstrapManagementClient()
+		if err != nil {
+			cc.ReqLogger.Error(err, "Failed to create the management client for the host zones")
+			groupNames = nil
+		}
+		for _, groupName := range groupNames {
+			hosts, err := mgmtClient.ListGroupHosts(cc.Ctx, groupName)
+			if err != nil {
+				cc.ReqLogger.Error(err, "Failed to list the hosts of the group for their zones", "group", groupName)
+				continue
+			}
+			joined := map[string]string{}
+			for _, host := range hosts {
+				if host.Online {
+					joined[strings.ToLower(host.Name)] = host.Name
+				}
+			}
+			for _, hostZone := range pending[groupName] {
+				hostName, ok := joined[hostZone.Host]
+				if !ok {
+					continue
+				}
+				if err := mgmtClient.SetHostZone(cc.Ctx, hostName, hostZone.Zone); err != nil {
+					cc.ReqLogger.Error(err, "Failed to set the zone of the host", "host", hostName, "zone", hostZone.Zone)
+					continue
+				}
+				cc.ReqLogger.Info("Set the zone of the host", "host", hostName, "zone", hostZone.Zone)
+				cc.recordClusterEvent(corev1.EventTypeNormal, hostZoneReasonSet, fmt.Sprintf("Set the zone of host %s to %s", hostName, hostZone.Zone))
+				zones = append(zones, hostZone)
+			}
+		}
+	}
+
+	sort.Slice(zones, func(i, j int) bool { return zones[i].Host < zones[j].Host })
+	if len(zones) == 0 {
+		zones = nil
+	}
+	if equality.Semantic.DeepEqual(cr.Status.HostZones, zones) {
+		return result.Continue()
+	}
+	patchBase := client.MergeFrom(cr.DeepCopy())
+	cr.Status.HostZones = zones
+	if err := patchStatus(cc.Ctx, cc.Client, cr, patchBase); err != nil {
+		cc.ReqLogger.Error(err, "Failed to update the host zones in the cluster status")
+	}
+	return result.Continue()
+}
+
+func hostZoneTopologyKey(zoneAwareness *marklogicv1.ZoneAwareness) string {
+	if zoneAwareness.TopologyKey == "" {
+		return corev1.LabelTopologyZone
+	}
+	return zoneAwareness.TopologyKey
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"testing"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestReconcileHostZonesSetsTheZoneOfTheNode(t *testing.T) {
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			ClusterDomain: "cluster.local",
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{
+				{Name: "dnode", IsBootstrap: true, ZoneAwareness: &marklogicv1.ZoneAwareness{}},
+				{Name: "enode"},
+			},
+		},
+	}
+	node := func(name, zone string) *corev1.Node {
+		return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{corev1.LabelTopologyZone: zone}}}
+	}
+	pod := func(name, group, nodeName string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{
+				"app.kubernetes.io/name":     "marklogic",
+				"app.kubernetes.io/instance": group,
+			}},
+			Spec: corev1.PodSpec{NodeName: nodeName},
+		}
+	}
+	cc := newUpgradeTestContext(t, cr, node("node-a", "us-east-1a"), node("node-b", "us-east-1b"),
+		pod("dnode-0", "dnode", "node-a"), pod("dnode-1", "dnode", "node-b"), pod("dnode-2", "dnode", ""), pod("enode-0", "enode", "node-a"))
+	zones := map[string]string{}
+	original := NewDynamicManagementClient
+	NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+		return &stubDynamicManagementClient{
+			listGroupFn: func(groupName string) ([]mlmanage.GroupHost, error) {
+				return []mlmanage.GroupHost{
+					{Name: "dnode-0.dnode.default.svc.cluster.local", Online: true},
+					{Name: "dnode-1.dnode.default.svc.cluster.local", Online: false},
+				}, nil
+			},
+			setHostZoneFn: func(hostName, zone string) error {
+				zones[hostName] = zone
+				return nil
+			},
+		}
+	}
+	t.Cleanup(func() { NewDynamicManagementClient = original })
+
+	if res := cc.ReconcileHostZones(); res.Completed() {
+		t.Fatalf("expected the host zones never to hold up the reconcile")
+	}
+	if len(zones) != 1 || zones["dnode-0.dnode.default.svc.cluster.local"] != "us-east-1a" {
+		t.Fatalf("expected the zone of the joined host to be set, got %v", zones)
+	}
+	if status := cr.Status.HostZones; len(status) != 1 || status[0].Zone != "us-east-1a" {
+		t.Fatalf("expected the zone in the status, got %+v", status)
+	}
+
+	// A recorded zone is not set again.
+	zones = map[string]string{}
+	cc.ReconcileHostZones()
+	if len(zones) != 0 {
+		t.Fatalf("expected no zones to be set again, got %v", zones)
+	}
+}
@@ -26,7 +26,7 @@ func generateIngressDef(ingressMeta metav1.ObjectMeta, ownerRef metav1.OwnerRefe
 							PathType: &pathType,
 							Backend: networkingv1.IngressBackend{
 								Service: &networkingv1.IngressServiceBackend{
-									Name: "marklogic-haproxy",
+									Name: haproxyName(cr),
 									Port: networkingv1.ServiceBackendPort{
 										Number: cr.Spec.HAProxy.FrontendPort,
 									},
@@ -74,7 +74,7 @@ func (cc *ClusterContext) getIngress(namespace string, ingressName string) (*net
 
 func (cc *ClusterContext) generateIngress(ingressName string, cr *marklogicv1.MarklogicCluster) *networkingv1.Ingress {
 	labels := cc.GetClusterLabels(cr.GetObjectMeta().GetName())
-	annotations := cr.Spec.HAProxy.Ingress.Annotations
+	annotations := mergeMetadata(cr.Spec.AdditionalAnnotations, cr.Spec.HAProxy.Ingress.Annotations)
 	ingressObjectMeta := generateObjectMeta(ingressName, cr.Namespace, labels, annotations)
 	ingress := generateIngressDef(ingressObjectMeta, marklogicClusterAsOwner(cr), cr)
 	return ingress
@@ -85,7 +85,7 @@ func (cc *ClusterContext) ReconcileIngress() result.ReconcileResult {
 	logger.Info("Ingress::Reconciling MarkLogic Ingress")
 	client := cc.Client
 	cr := cc.MarklogicCluster
-	ingressName := cr.ObjectMeta.Name
+	ingressName := clusterFullname(cr)
 	currentIngress, err := cc.getIngress(cr.Namespace, ingressName)
 	ingressDef := cc.generateIngress(ingressName, cr)
 	if err != nil {
@@ -128,6 +128,121 @@ func (cc *ClusterContext) ReconcileIngress() result.ReconcileResult {
 	return result.Continue()
 }
 
+// generateAdminIngressDef routes each configured admin host to the matching
+// port of the bootstrap group's ClusterIP service, terminating TLS at the Ingress.
+func generateAdminIngressDef(ingressMeta metav1.ObjectMeta, ownerRef metav1.OwnerReference, serviceName string, adminIngress *marklogicv1.AdminIngress) *networkingv1.Ingress {
+	pathType := networkingv1.PathTypePrefix
+	hostPorts := []struct {
+		host string
+		port int32
+	}{
+		{adminIngress.AppServicesHost, 8000},
+		{adminIngress.AdminHost, 8001},
+		{adminIngress.ManageHost, 8002},
+	}
+	var rules []networkingv1.IngressRule
+	var hosts []string
+	for _, hostPort := range hostPorts {
+		if hostPort.host == "" {
+			continue
+		}
+		hosts = append(hosts, hostPort.host)
+		rules = append(rules, networkingv1.IngressRule{
+			Host: hostPort.host,
+			IngressRuleValue: networkingv1.IngressRuleValue{
+				HTTP: &networkingv1.HTTPIngressRuleValue{
+					Paths: []networkingv1.HTTPIngressPath{
+						{
+							Path:     "/",
+							PathType: &pathType,
+							Backend: networkingv1.IngressBackend{
+								Service: &networkingv1.IngressServiceBackend{
+									Name: serviceName,
+									Port: networkingv1.ServiceBackendPort{Number: hostPort.port},
+								},
+							},
+						},
+					},
+				},
+			},
+		})
+	}
+	ingressSpec := networkingv1.IngressSpec{
+		Rules: rules,
+		TLS:   []networkingv1.IngressTLS{{Hosts: hosts, SecretName: adminIngress.TLSSecretName}},
+	}
+	if adminIngress.IngressClassName != "" {
+		ingressSpec.IngressClassName = &adminIngress.IngressClassName
+	}
+	ingressDef := &networkingv1.Ingress{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Ingress",
+			APIVersion: "v1",
+		},
+		ObjectMeta: ingressMeta,
+		Spec:       ingressSpec,
+	}
+	ingressDef.SetOwnerReferences(append(ingressDef.GetOwnerReferences(), ownerRef))
+	return ingressDef
+}
+
+// ReconcileAdminIngress creates the admin Ingress when spec.networkAccess.exposeAdmin
+// is set and removes it again once exposure is switched off. With
+// spec.ocpCompatible, ReconcileRoutes exposes the admin ports instead.
+func (cc *ClusterContext) ReconcileAdminIngress() result.ReconcileResult {
+	logger := cc.ReqLogger
+	cr := cc.MarklogicCluster
+	ingressName := clusterFullname(cr) + "-admin"
+	currentIngress, err := cc.getIngress(cr.Namespace, ingressName)
+	if err != nil && !errors.IsNotFound(err) {
+		return result.Error(err)
+	}
+	exposed := cr.Spec.NetworkAccess != nil && cr.Spec.NetworkAccess.ExposeAdmin && cr.Spec.NetworkAccess.AdminIngress != nil && !cr.Spec.OCPCompatible
+	if !exposed {
+		if currentIngress != nil {
+			logger.Info("Admin ports are no longer exposed, deleting the admin Ingress")
+			if err := cc.Client.Delete(cc.Ctx, currentIngress); err != nil && !errors.IsNotFound(err) {
+				return result.Error(err)
+			}
+			cc.Recorder.Event(cr, "Normal", "AdminIngressDeleted", "Admin Ingress removed because exposeAdmin is false")
+		}
+		return result.Continue()
+	}
+
+	bootstrapGroup := ""
+	for _, group := range cr.Spec.MarkLogicGroups {
+		if group != nil && group.IsBootstrap {
+			bootstrapGroup = group.Name
+		}
+	}
+	adminIngress := cr.Spec.NetworkAccess.AdminIngress
+	labels := cc.GetClusterLabels(cr.GetObjectMeta().GetName())
+	ingressMeta := generateObjectMeta(ingressName, cr.Namespace, labels, mergeMetadata(cr.Spec.AdditionalAnnotations, adminIngress.Annotations))
+	ingressDef := generateAdminIngressDef(ingressMeta, marklogicClusterAsOwner(cr), bootstrapGroup+"-cluster", adminIngress)
+	if currentIngress == nil {
+		logger.Info("Creating the admin Ingress", "name", ingressName)
+		if err := cc.Client.Create(cc.Ctx, ingressDef); err != nil {
+			return result.Error(err)
+		}
+		cc.Recorder.Event(cr, "Normal", "AdminIngressCreated", "Admin ports exposed through Ingress "+ingressName)
+		return result.Continue()
+	}
+	patchDiff, err := patch.DefaultPatchMaker.Calculate(currentIngress, ingressDef,
+		patch.IgnoreStatusFields(),
+		patch.IgnoreField("kind"))
+	if err != nil {
+		return result.Error(err)
+	}
+	if !patchDiff.IsEmpty() {
+		logger.Info("Updating the admin Ingress", "name", ingressName)
+		ingressDef.ResourceVersion = currentIngress.ResourceVersion
+		if err := cc.Client.Update(cc.Ctx, ingressDef); err != nil {
+			return result.Error(err)
+		}
+	}
+	return result.Continue()
+}
+
 // Deprecated: createIngress is currently unused but kept for future use
 // nolint:unused
 func (cc *ClusterContext) createIngress(namespace string) error {
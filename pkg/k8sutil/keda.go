@@ -0,0 +1,204 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"fmt"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	kedaReasonUnavailable = "ScaledObjectUnavailable"
+
+	// kedaPausedAnnotation pauses the autoscaling of a ScaledObject.
+	kedaPausedAnnotation = "autoscaling.keda.sh/paused"
+)
+
+var scaledObjectGVK = schema.GroupVersionKind{Group: "keda.sh", Version: "v1alpha1", Kind: "ScaledObject"}
+
+// kedaLoadMetrics maps the metric of a marklogic trigger to the gauge the
+// operator exports it as, see ReconcileLoadMetrics.
+var kedaLoadMetrics = map[string]string{
+	"requestRate":    "marklogic_group_request_rate",
+	"activeRequests": "marklogic_group_active_requests",
+	"queueDepth":     "marklogic_group_queue_depth",
+}
+
+// ReconcileScaledObjects creates a KEDA ScaledObject, named like the group,
+// for every group with autoscaling.keda and deletes the one of a group
+// without it. The ScaledObject scales the MarklogicGroup rather than the
+// StatefulSet, so the group controller removes the hosts from the MarkLogic
+// cluster before their pods stop. It is paused while the cluster is stopped
+// or upgraded, so scaling never interleaves with the restarts of an upgrade.
+func (cc *ClusterContext) ReconcileScaledObjects() result.ReconcileResult {
+	cr := cc.MarklogicCluster
+	for _, group := range cr.Spec.MarkLogicGroups {
+		if group == nil {
+			continue
+		}
+		keda := groupKedaAutoscaling(group)
+		var err error
+		if keda != nil {
+			err = cc.applyScaledObject(group, keda)
+		} else {
+			err = cc.deleteScaledObject(group.Name)
+		}
+		if meta.IsNoMatchError(err) {
+			if keda != nil {
+				cc.recordClusterEvent(corev1.EventTypeWarning, kedaReasonUnavailable,
+					fmt.Sprintf("group %s has KEDA autoscaling but the ScaledObject CRD is not installed", group.Name))
+			}
+			continue
+		}
+		if err != nil {
+			return result.Error(err)
+		}
+	}
+	return result.Continue()
+}
+
+func groupKedaAutoscaling(group *marklogicv1.MarklogicGroups) *marklogicv1.KedaAutoscaling {
+	if group.Autoscaling == nil || !group.IsDynamic {
+		return nil
+	}
+	return group.Autoscaling.Keda
+}
+
+func kedaUsesLoadMetrics(keda *marklogicv1.KedaAutoscaling) bool {
+	if keda == nil {
+		return false
+	}
+	for _, trigger := range keda.Triggers {
+		if trigger.Type == marklogicv1.KedaTriggerMarkLogic {
+			return true
+		}
+	}
+	return false
+}
+
+// kedaReplicas returns the replicas KEDA set on the MarklogicGroup of a group
+// it scales, which the cluster reconcile keeps instead of the group replicas.
+// Zero replicas are left from a stopped cluster and are not kept.
+func kedaReplicas(group *marklogicv1.MarklogicGroups, current *marklogicv1.MarklogicGroup) *int32 {
+	if groupKedaAutoscaling(group) == nil || current.Spec.Replicas == nil || *current.Spec.Replicas == 0 {
+		return nil
+	}
+	replicas := *current.Spec.Replicas
+	return &replicas
+}
+
+func scaledObjectPaused(cr *marklogicv1.MarklogicCluster) bool {
+	return clusterStopped(cr) || cr.Status.Upgrade.Active()
+}
+
+func (cc *ClusterContext) applyScaledObject(group *marklogicv1.MarklogicGroups, keda *marklogicv1.KedaAutoscaling) error {
+	cr := cc.MarklogicCluster
+	desired := generateScaledObject(cr, group, keda)
+	desired.SetLabels(cc.GetClusterLabels(cr.Name))
+	desired.SetAnnotations(mergeMetadata(cr.Spec.AdditionalAnnotations, desired.GetAnnotations()))
+	current := &unstructured.Unstructured{}
+	current.SetGroupVersionKind(scaledObjectGVK)
+	err := cc.Client.Get(cc.Ctx, types.NamespacedName{Namespace: cr.Namespace, Name: group.Name}, current)
+	if apierrors.IsNotFound(err) {
+		cc.ReqLogger.Info("Creating ScaledObject", "group", group.Name)
+		return cc.Client.Create(cc.Ctx, desired)
+	}
+	if err != nil {
+		return err
+	}
+	metadataChanged := syncMetadata(current, desired)
+	annotations := current.GetAnnotations()
+	paused := annotations[kedaPausedAnnotation] == "true"
+	if !metadataChanged && equality.Semantic.DeepEqual(current.Object["spec"], desired.Object["spec"]) && paused == scaledObjectPaused(cr) {
+		return nil
+	}
+	current.Object["spec"] = desired.Object["spec"]
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	if scaledObjectPaused(cr) {
+		annotations[kedaPausedAnnotation] = "true"
+	} else {
+		delete(annotations, kedaPausedAnnotation)
+	}
+	current.SetAnnotations(annotations)
+	cc.ReqLogger.Info("Updating ScaledObject", "group", group.Name)
+	return cc.Client.Update(cc.Ctx, current)
+}
+
+func (cc *ClusterContext) deleteScaledObject(name string) error {
+	cr := cc.MarklogicCluster
+	current := &unstructured.Unstructured{}
+	current.SetGroupVersionKind(scaledObjectGVK)
+	err := cc.Client.Get(cc.Ctx, types.NamespacedName{Namespace: cr.Namespace, Name: name}, current)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	// Leave ScaledObjects the operator did not create alone.
+	if !metav1.IsControlledBy(current, cr) {
+		return nil
+	}
+	cc.ReqLogger.Info("Deleting ScaledObject", "group", name)
+	return client.IgnoreNotFound(cc.Client.Delete(cc.Ctx, current))
+}
+
+func generateScaledObject(cr *marklogicv1.MarklogicCluster, group *marklogicv1.MarklogicGroups, keda *marklogicv1.KedaAutoscaling) *unstructured.Unstructured {
+	minReplicas := int64(1)
+	if keda.MinReplicas != nil {
+		minReplicas = int64(*keda.MinReplicas)
+	}
+	triggers := []interface{}{}
+	for _, trigger := range keda.Triggers {
+		query := trigger.Query
+		if trigger.Type == marklogicv1.KedaTriggerMarkLogic {
+			query = fmt.Sprintf(`sum(%s{namespace=%q,cluster=%q,group=%q})`, kedaLoadMetrics[trigger.Metric], cr.Namespace, cr.Name, group.Name)
+		}
+		triggers = append(triggers, map[string]interface{}{
+			"type": string(marklogicv1.KedaTriggerPrometheus),
+			"metadata": map[string]interface{}{
+				"serverAddress": trigger.ServerAddress,
+				"query":         query,
+				"threshold":     trigger.Threshold,
+			},
+		})
+	}
+	spec := map[string]interface{}{
+		"scaleTargetRef": map[string]interface{}{
+			"apiVersion": marklogicv1.GroupVersion.String(),
+			"kind":       "MarklogicGroup",
+			"name":       group.Name,
+		},
+		"minReplicaCount": minReplicas,
+		"maxReplicaCount": int64(keda.MaxReplicas),
+		"triggers":        triggers,
+	}
+	if keda.PollingInterval != nil {
+		spec["pollingInterval"] = int64(*keda.PollingInterval)
+	}
+	if keda.CooldownPeriod != nil {
+		spec["cooldownPeriod"] = int64(*keda.CooldownPeriod)
+	}
+	scaledObject := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	scaledObject.SetGroupVersionKind(scaledObjectGVK)
+	scaledObject.SetName(group.Name)
+	scaledObject.SetNamespace(cr.Namespace)
+	scaledObject.SetOwnerReferences([]metav1.OwnerReference{marklogicClusterAsOwner(cr)})
+	if scaledObjectPaused(cr) {
+		scaledObject.SetAnnotations(map[string]string{kedaPausedAnnotation: "true"})
+	}
+	return scaledObject
+}
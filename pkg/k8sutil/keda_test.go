@@ -0,0 +1,83 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"testing"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestScaledObjectScalesTheMarklogicGroup(t *testing.T) {
+	maxReplicas := int32(6)
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default", UID: "cluster-uid"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{{
+				Name:      "enode",
+				IsDynamic: true,
+				Autoscaling: &marklogicv1.GroupAutoscaling{Keda: &marklogicv1.KedaAutoscaling{
+					MaxReplicas: maxReplicas,
+					Triggers: []marklogicv1.KedaTrigger{{
+						Type:          marklogicv1.KedaTriggerMarkLogic,
+						ServerAddress: "http://prometheus:9090",
+						Metric:        "queueDepth",
+						Threshold:     "5",
+					}},
+				}},
+			}},
+		},
+	}
+	cc := newUpgradeTestContext(t, cr)
+	group := cc.MarklogicCluster.Spec.MarkLogicGroups[0]
+
+	if res := cc.ReconcileScaledObjects(); res.Completed() {
+		t.Fatalf("expected the reconcile to continue")
+	}
+	scaledObject := &unstructured.Unstructured{}
+	scaledObject.SetGroupVersionKind(scaledObjectGVK)
+	key := types.NamespacedName{Namespace: "default", Name: "enode"}
+	if err := cc.Client.Get(cc.Ctx, key, scaledObject); err != nil {
+		t.Fatalf("expected a ScaledObject: %v", err)
+	}
+	if kind, _, _ := unstructured.NestedString(scaledObject.Object, "spec", "scaleTargetRef", "kind"); kind != "MarklogicGroup" {
+		t.Fatalf("expected the ScaledObject to scale the MarklogicGroup, got %q", kind)
+	}
+	triggers, _, _ := unstructured.NestedSlice(scaledObject.Object, "spec", "triggers")
+	query, _, _ := unstructured.NestedString(triggers[0].(map[string]interface{}), "metadata", "query")
+	if want := `sum(marklogic_group_queue_depth{namespace="default",cluster="ml",group="enode"})`; query != want {
+		t.Fatalf("expected query %q, got %q", want, query)
+	}
+	if groups := loadMetricsGroups(cc.MarklogicCluster); len(groups) != 1 {
+		t.Fatalf("expected the marklogic trigger to collect the load metrics of the group")
+	}
+
+	// KEDA's replicas are kept, unless the cluster stopped the group.
+	current := &marklogicv1.MarklogicGroup{Spec: marklogicv1.MarklogicGroupSpec{Replicas: &maxReplicas}}
+	if replicas := kedaReplicas(group, current); replicas == nil || *replicas != maxReplicas {
+		t.Fatalf("expected the replicas set by KEDA to be kept, got %v", replicas)
+	}
+	zero := int32(0)
+	if replicas := kedaReplicas(group, &marklogicv1.MarklogicGroup{Spec: marklogicv1.MarklogicGroupSpec{Replicas: &zero}}); replicas != nil {
+		t.Fatalf("expected stopped replicas not to be kept, got %d", *replicas)
+	}
+
+	cc.MarklogicCluster.Spec.Stopped = true
+	cc.ReconcileScaledObjects()
+	if err := cc.Client.Get(cc.Ctx, key, scaledObject); err != nil {
+		t.Fatalf("failed to get ScaledObject: %v", err)
+	}
+	if scaledObject.GetAnnotations()[kedaPausedAnnotation] != "true" {
+		t.Fatalf("expected the ScaledObject to be paused while the cluster is stopped")
+	}
+
+	group.Autoscaling = nil
+	cc.ReconcileScaledObjects()
+	if err := cc.Client.Get(cc.Ctx, key, scaledObject); !apierrors.IsNotFound(err) {
+		t.Fatalf("expected the ScaledObject to be deleted, got %v", err)
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	// loadMetricsInterval is how often the load of the groups is collected,
+	// about as often as an HPA evaluates its metrics.
+	loadMetricsInterval = 30 * time.Second
+
+	loadMetricsReasonRefused = "LoadMetricsRefused"
+)
+
+var (
+	loadRequestRate = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "marklogic_group_request_rate",
+		Help: "Requests per second served by the app servers of a MarkLogic group.",
+	}, capacityMetricLabels)
+	loadActiveRequests = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "marklogic_group_active_requests",
+		Help: "Requests being processed by the app servers of a MarkLogic group.",
+	}, capacityMetricLabels)
+	loadQueueDepth = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "marklogic_group_queue_depth",
+		Help: "Requests waiting for a thread in the app servers of a MarkLogic group.",
+	}, capacityMetricLabels)
+
+	loadGauges = []*prometheus.GaugeVec{loadRequestRate, loadActiveRequests, loadQueueDepth}
+
+	// loadMetricsCollected holds when the load of each cluster was collected
+	// last. The metrics live in the operator process, so the schedule does
+	// too.
+	loadMetricsCollected sync.Map
+)
+
+func init() {
+	for _, gauge := range loadGauges {
+		metrics.Registry.MustRegister(gauge)
+	}
+}
+
+// ReconcileLoadMetrics collects the load of the groups with
+// autoscaling.loadMetrics or a KEDA marklogic trigger from the Manage API
+// every loadMetricsInterval and exports it as metrics, for an HPA through a
+// metrics adapter or for KEDA.
+// Groups holding forests are refused even when the CRD validation is
+// bypassed, so d-node groups never scale on load. Failures are logged and
+// never hold up the rest of the reconcile.
+func (cc *ClusterContext) ReconcileLoadMetrics() result.ReconcileResult {
+	cr := cc.MarklogicCluster
+	key := types.NamespacedName{Namespace: cr.Namespace, Name: cr.Name}
+	groups := loadMetricsGroups(cr)
+	if len(groups) == 0 {
+		if _, collected := loadMetricsCollected.LoadAndDelete(key); collected {
+			deleteClusterSeries(loadGauges, cr.Namespace, cr.Name)
+		}
+		return result.Continue()
+	}
+	if last, ok := loadMetricsCollected.Load(key); ok && time.Since(last.(time.Time)) < loadMetricsInterval {
+		return result.Continue()
+	}
+	loadMetricsCollected.Store(key, time.Now())
+
+	mgmt, err := cc.newBootstrapManagementClient()
+	if err != nil {
+		cc.ReqLogger.Error(err, "Failed to create the Manage API client for the load metrics")
+		return result.Continue()
+	}
+	// Groups removed or refused drop out of the metrics.
+	deleteClusterSeries(loadGauges, cr.Namespace, cr.Name)
+	for _, group := range groups {
+		info, err := mgmt.GetGroup(cc.Ctx, group.Name)
+		if err != nil {
+			cc.ReqLogger.Error(err, "Failed to read the group for the load metrics", "group", group.Name)
+			continue
+		}
+		if info.ForestCount > 0 {
+			cc.recordClusterEvent(corev1.EventTypeWarning, loadMetricsReasonRefused,
+				fmt.Sprintf("group %s holds %d forest(s), its load is not exported so it does not scale on load", group.Name, info.ForestCount))
+			continue
+		}
+		load, err := mgmt.GetGroupLoad(cc.Ctx, group.Name)
+		if err != nil {
+			cc.ReqLogger.Error(err, "Failed to read the load of the group", "group", group.Name)
+			continue
+		}
+		labels := prometheus.Labels{"namespace": cr.Namespace, "cluster": cr.Name, "group": group.Name}
+		loadRequestRate.With(labels).Set(load.RequestRate)
+		loadActiveRequests.With(labels).Set(float64(load.ActiveRequests))
+		loadQueueDepth.With(labels).Set(float64(load.QueueSize))
+	}
+	return result.Continue()
+}
+
+func loadMetricsGroups(cr *marklogicv1.MarklogicCluster) []*marklogicv1.MarklogicGroups {
+	groups := []*marklogicv1.MarklogicGroups{}
+	for _, group := range cr.Spec.MarkLogicGroups {
+		if group == nil || group.Autoscaling == nil {
+			continue
+		}
+		if (group.Autoscaling.LoadMetrics == nil || !group.Autoscaling.LoadMetrics.Enabled) && !kedaUsesLoadMetrics(group.Autoscaling.Keda) {
+			continue
+		}
+		if group.IsBootstrap || group.Forests != nil {
+			continue
+		}
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+// nextLoadMetricsCollection is when the load of the cluster is collected
+// next, or the zero time when it is not collected.
+func nextLoadMetricsCollection(cr *marklogicv1.MarklogicCluster) time.Time {
+	last, ok := loadMetricsCollected.Load(types.NamespacedName{Namespace: cr.Namespace, Name: cr.Name})
+	if !ok {
+		return time.Time{}
+	}
+	return last.(time.Time).Add(loadMetricsInterval)
+}
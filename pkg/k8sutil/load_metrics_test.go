@@ -0,0 +1,60 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"testing"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestLoadMetricsAreExportedForENodeGroupsOnly(t *testing.T) {
+	enabled := &marklogicv1.GroupAutoscaling{LoadMetrics: &marklogicv1.LoadMetrics{Enabled: true}}
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "load", Namespace: "default"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{
+				{Name: "dnode", IsBootstrap: true, Autoscaling: enabled},
+				{Name: "enode", Autoscaling: enabled},
+				{Name: "legacy", Autoscaling: enabled},
+			},
+		},
+	}
+	cc := newUpgradeTestContext(t, cr)
+	t.Cleanup(func() { DeleteClusterMetrics("default", "load") })
+	original := NewDynamicManagementClient
+	NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+		return &stubDynamicManagementClient{
+			getGroupFn: func(groupName string) (mlmanage.GroupInfo, error) {
+				// Forests were created on the legacy group outside the operator.
+				if groupName == "legacy" {
+					return mlmanage.GroupInfo{Exists: true, ForestCount: 2}, nil
+				}
+				return mlmanage.GroupInfo{Exists: true}, nil
+			},
+			groupLoadFn: func(groupName string) (mlmanage.GroupLoad, error) {
+				return mlmanage.GroupLoad{RequestRate: 42.5, ActiveRequests: 7, QueueSize: 3}, nil
+			},
+		}
+	}
+	t.Cleanup(func() { NewDynamicManagementClient = original })
+
+	if res := cc.ReconcileLoadMetrics(); res.Completed() {
+		t.Fatalf("expected the reconcile to continue")
+	}
+	if got := testutil.ToFloat64(loadRequestRate.WithLabelValues("default", "load", "enode")); got != 42.5 {
+		t.Fatalf("expected the request rate of the enode group, got %v", got)
+	}
+	if got := testutil.ToFloat64(loadQueueDepth.WithLabelValues("default", "load", "enode")); got != 3 {
+		t.Fatalf("expected the queue depth of the enode group, got %v", got)
+	}
+	if got := testutil.CollectAndCount(loadRequestRate); got != 1 {
+		t.Fatalf("expected only the enode group to be exported, got %d series", got)
+	}
+	if nextLoadMetricsCollection(cc.MarklogicCluster).IsZero() {
+		t.Fatalf("expected the next collection to be scheduled")
+	}
+}
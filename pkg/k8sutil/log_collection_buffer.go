@@ -0,0 +1,145 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"strconv"
+	"strings"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	fluentBitBufferVolumeName   = "fluent-bit-buffer"
+	fluentBitBufferPath         = "/fluent-bit/buffer/"
+	defaultFluentBitMemBufLimit = "4MB"
+)
+
+var defaultFluentBitStorageLimit = resource.MustParse("1Gi")
+
+// yamlProperty is a property the operator sets on the inputs or outputs of
+// fluent-bit that do not set it themselves.
+type yamlProperty struct {
+	key, value string
+}
+
+// fluentBitFilesystemBuffer reports whether fluent-bit buffers records on the
+// filesystem volume the operator mounts for it.
+func fluentBitFilesystemBuffer(logCollection *marklogicv1.LogCollection) bool {
+	return logCollection != nil && logCollection.Buffer != nil &&
+		logCollection.Buffer.StorageType == marklogicv1.LogCollectionStorageFilesystem
+}
+
+func fluentBitMemBufLimit(logCollection *marklogicv1.LogCollection) string {
+	if logCollection.Buffer != nil && logCollection.Buffer.MemBufLimit != "" {
+		return logCollection.Buffer.MemBufLimit
+	}
+	return defaultFluentBitMemBufLimit
+}
+
+func fluentBitStorageLimit(logCollection *marklogicv1.LogCollection) resource.Quantity {
+	if logCollection.Buffer != nil && logCollection.Buffer.StorageLimit != nil {
+		return *logCollection.Buffer.StorageLimit
+	}
+	return defaultFluentBitStorageLimit
+}
+
+// fluentBitServiceStorage returns the storage properties of the service
+// section of the fluent-bit configuration.
+func fluentBitServiceStorage(logCollection *marklogicv1.LogCollection) string {
+	if !fluentBitFilesystemBuffer(logCollection) {
+		return ""
+	}
+	return `  storage.path: ` + fluentBitBufferPath + `
+  storage.sync: normal
+  storage.backlog.mem_limit: ` + fluentBitMemBufLimit(logCollection) + `
+`
+}
+
+func fluentBitInputProperties(logCollection *marklogicv1.LogCollection) []yamlProperty {
+	properties := []yamlProperty{{"mem_buf_limit", fluentBitMemBufLimit(logCollection)}}
+	if fluentBitFilesystemBuffer(logCollection) {
+		properties = append(properties, yamlProperty{"storage.type", string(marklogicv1.LogCollectionStorageFilesystem)})
+	}
+	return properties
+}
+
+func fluentBitOutputProperties(logCollection *marklogicv1.LogCollection) []yamlProperty {
+	var properties []yamlProperty
+	if logCollection.Buffer != nil && logCollection.Buffer.RetryLimit != nil {
+		retryLimit := "no_limits"
+		if *logCollection.Buffer.RetryLimit > 0 {
+			retryLimit = strconv.Itoa(int(*logCollection.Buffer.RetryLimit))
+		}
+		properties = append(properties, yamlProperty{"retry_limit", retryLimit})
+	}
+	if fluentBitFilesystemBuffer(logCollection) {
+		limit := fluentBitStorageLimit(logCollection)
+		properties = append(properties, yamlProperty{"storage.total_limit_size", strconv.FormatInt(limit.Value(), 10)})
+	}
+	return properties
+}
+
+// withItemProperties adds properties to the items of a YAML list, indented
+// by listItemIndent, that do not set them. The properties are indented by
+// two more spaces than the items.
+func withItemProperties(items string, listItemIndent int, properties []yamlProperty) string {
+	if len(properties) == 0 {
+		return items
+	}
+	itemPrefix := strings.Repeat(" ", listItemIndent) + "- "
+	propertyIndent := strings.Repeat(" ", listItemIndent+2)
+	lines := strings.Split(items, "\n")
+	result := make([]string, 0, len(lines)+len(properties))
+	var item []string
+	flush := func() {
+		if len(item) == 0 {
+			return
+		}
+		last := len(item) - 1
+		for last > 0 && strings.TrimSpace(item[last]) == "" {
+			last--
+		}
+		result = append(result, item[:last+1]...)
+		for _, property := range properties {
+			set := false
+			for i, line := range item {
+				if i == 0 {
+					line = propertyIndent + strings.TrimPrefix(line, itemPrefix)
+				}
+				if strings.HasPrefix(line, propertyIndent+property.key+":") {
+					set = true
+					break
+				}
+			}
+			if !set {
+				result = append(result, propertyIndent+property.key+": "+property.value)
+			}
+		}
+		result = append(result, item[last+1:]...)
+		item = nil
+	}
+	for _, line := range lines {
+		if strings.HasPrefix(line, itemPrefix) {
+			flush()
+		}
+		if len(item) > 0 || strings.HasPrefix(line, itemPrefix) {
+			item = append(item, line)
+			continue
+		}
+		result = append(result, line)
+	}
+	flush()
+	return strings.Join(result, "\n")
+}
+
+// fluentBitBufferVolume is the emptyDir of the filesystem buffer of
+// fluent-bit. Its outputs bound what they buffer on it.
+func fluentBitBufferVolume() corev1.Volume {
+	return corev1.Volume{
+		Name:         fluentBitBufferVolumeName,
+		VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+	}
+}
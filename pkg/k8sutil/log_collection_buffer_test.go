@@ -0,0 +1,63 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"testing"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/yaml"
+)
+
+func TestFluentBitDataConfiguresTheFilesystemBuffer(t *testing.T) {
+	retryLimit := int32(0)
+	storageLimit := resource.MustParse("2Gi")
+	logCollection := &marklogicv1.LogCollection{
+		Enabled: true,
+		Files:   marklogicv1.LogFilesConfig{AccessLogs: true},
+		Outputs: "- name: loki\n  match: \"*\"\n  retry_limit: 5\n- name: stdout\n  match: \"*\"",
+		Buffer: &marklogicv1.LogCollectionBuffer{
+			MemBufLimit:  "16MB",
+			StorageType:  marklogicv1.LogCollectionStorageFilesystem,
+			StorageLimit: &storageLimit,
+			RetryLimit:   &retryLimit,
+		},
+	}
+
+	var config struct {
+		Service  map[string]any `json:"service"`
+		Pipeline struct {
+			Inputs  []map[string]any `json:"inputs"`
+			Outputs []map[string]any `json:"outputs"`
+		} `json:"pipeline"`
+	}
+	if err := yaml.Unmarshal([]byte(fluentBitConfigData(logCollection)["fluent-bit.yaml"]), &config); err != nil {
+		t.Fatalf("invalid fluent-bit configuration: %v", err)
+	}
+	if config.Service["storage.path"] != fluentBitBufferPath || config.Service["storage.backlog.mem_limit"] != "16MB" {
+		t.Fatalf("unexpected service %v", config.Service)
+	}
+	input := config.Pipeline.Inputs[0]
+	if len(config.Pipeline.Inputs) != 1 || input["mem_buf_limit"] != "16MB" || input["storage.type"] != "filesystem" {
+		t.Fatalf("unexpected inputs %v", config.Pipeline.Inputs)
+	}
+	loki, stdout := config.Pipeline.Outputs[0], config.Pipeline.Outputs[1]
+	if loki["retry_limit"] != float64(5) || stdout["retry_limit"] != "no_limits" ||
+		loki["storage.total_limit_size"] != float64(2147483648) || stdout["storage.total_limit_size"] != float64(2147483648) {
+		t.Fatalf("unexpected outputs %v", config.Pipeline.Outputs)
+	}
+
+	containerParams := containerParameters{Name: "dnode", LogCollection: logCollection}
+	mounts := getFluentBitVolumeMount(containerParams)
+	if mounts[len(mounts)-1].Name != fluentBitBufferVolumeName || mounts[len(mounts)-1].MountPath != fluentBitBufferPath {
+		t.Fatalf("expected the buffer to be mounted, got %+v", mounts)
+	}
+	found := false
+	for _, volume := range generateVolumes("dnode", containerParams) {
+		found = found || (volume.Name == fluentBitBufferVolumeName && volume.EmptyDir != nil)
+	}
+	if !found {
+		t.Fatalf("expected the buffer volume")
+	}
+}
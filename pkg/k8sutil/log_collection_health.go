@@ -0,0 +1,198 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	fluentBitContainerName = "fluent-bit"
+
+	// logCollectionRestartWindow is how long a restart of fluent-bit keeps
+	// log collection degraded.
+	logCollectionRestartWindow = 10 * time.Minute
+
+	logCollectionReasonCrashLooping = "CrashLooping"
+	logCollectionReasonRestarted    = "Restarted"
+	logCollectionReasonHealthy      = "Healthy"
+	logCollectionReasonRecovered    = "LogCollectionRecovered"
+
+	// logCollectionCheckInterval is how often a degraded log collection is
+	// checked for recovery.
+	logCollectionCheckInterval = time.Minute
+
+	// logCollectionMessageLimit caps the termination message of a pod, which
+	// holds the last log lines of fluent-bit.
+	logCollectionMessageLimit = 512
+)
+
+// logCollectionRestarts holds the fluent-bit restarts of the pods of each
+// cluster, so every restart is reported in one event.
+var logCollectionRestarts sync.Map
+
+// ReconcileLogCollectionHealth sets the LogCollectionDegraded condition while
+// the fluent-bit sidecar of a pod crash-loops or restarted within
+// logCollectionRestartWindow, typically on a bad outputs configuration, and
+// records a Warning event with the last termination message for every
+// restart. Failures are logged and never hold up the rest of the reconcile.
+func (cc *ClusterContext) ReconcileLogCollectionHealth() result.ReconcileResult {
+	cr := cc.MarklogicCluster
+	pods := []corev1.Pod{}
+	for _, group := range cr.Spec.MarkLogicGroups {
+		if group == nil {
+			continue
+		}
+		list := &corev1.PodList{}
+		if err := cc.Client.List(cc.Ctx, list, client.InNamespace(cr.Namespace), client.MatchingLabels{
+			"app.kubernetes.io/name":     "marklogic",
+			"app.kubernetes.io/instance": group.Name,
+		}); err != nil {
+			cc.ReqLogger.Error(err, "Failed to list the pods for the log collection health", "group", group.Name)
+			return result.Continue()
+		}
+		pods = append(pods, list.Items...)
+	}
+	sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+
+	key := types.NamespacedName{Namespace: cr.Namespace, Name: cr.Name}
+	seen := map[types.UID]int32{}
+	if last, ok := logCollectionRestarts.Load(key); ok {
+		seen = last.(map[types.UID]int32)
+	}
+	restarts := map[types.UID]int32{}
+	now := time.Now()
+	collecting := false
+	crashLooping := false
+	degraded := []string{}
+	for _, pod := range pods {
+		status := logCollectorStatus(pod)
+		if status == nil {
+			continue
+		}
+		collecting = true
+		terminated := status.LastTerminationState.Terminated
+		recent := terminated != nil && now.Sub(terminated.FinishedAt.Time) < logCollectionRestartWindow
+		restarts[pod.UID] = status.RestartCount
+		if recent && status.RestartCount > seen[pod.UID] {
+			cc.recordClusterEvent(corev1.EventTypeWarning, string(marklogicv1.LogCollectionDegraded),
+				fmt.Sprintf("%s in pod %s restarted (%d restarts): %s", status.Name, pod.Name, status.RestartCount, terminationMessage(terminated)))
+		}
+		switch {
+		case status.State.Waiting != nil && status.State.Waiting.Reason == "CrashLoopBackOff":
+			crashLooping = true
+		case recent:
+		default:
+			continue
+		}
+		degraded = append(degraded, fmt.Sprintf("%s: %s", pod.Name, terminationMessage(terminated)))
+	}
+	if len(restarts) > 0 {
+		logCollectionRestarts.Store(key, restarts)
+	} else {
+		logCollectionRestarts.Delete(key)
+	}
+	if !collecting && cr.Status.GetConditionStatus(string(marklogicv1.LogCollectionDegraded)) == metav1.ConditionUnknown {
+		return result.Continue()
+	}
+
+	condition := metav1.Condition{
+		Type:               string(marklogicv1.LogCollectionDegraded),
+		Status:             metav1.ConditionFalse,
+		Reason:             logCollectionReasonHealthy,
+		Message:            "the log collector is running in every pod",
+		ObservedGeneration: cr.Generation,
+		LastTransitionTime: metav1.Now(),
+	}
+	if len(degraded) > 0 {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = logCollectionReasonRestarted
+		if crashLooping {
+			condition.Reason = logCollectionReasonCrashLooping
+		}
+		condition.Message = fmt.Sprintf("the log collector restarted in %d pod(s): %s", len(degraded), strings.Join(degraded, "; "))
+	}
+	cc.setLogCollectionCondition(condition)
+	return result.Continue()
+}
+
+// setLogCollectionCondition patches the LogCollectionDegraded condition when
+// it changed and records an event when log collection recovers.
+func (cc *ClusterContext) setLogCollectionCondition(condition metav1.Condition) {
+	cr := cc.MarklogicCluster
+	var existing *metav1.Condition
+	for i := range cr.Status.Conditions {
+		if cr.Status.Conditions[i].Type == condition.Type {
+			existing = &cr.Status.Conditions[i]
+		}
+	}
+	if existing != nil {
+		if existing.Status == condition.Status && existing.Reason == condition.Reason && existing.Message == condition.Message {
+			return
+		}
+		if existing.Status == condition.Status {
+			condition.LastTransitionTime = existing.LastTransitionTime
+		}
+	}
+	recovered := existing != nil && existing.Status == metav1.ConditionTrue && condition.Status == metav1.ConditionFalse
+
+	patchBase := client.MergeFrom(cr.DeepCopy())
+	cr.Status.SetCondition(condition)
+	if err := patchStatus(cc.Ctx, cc.Client, cr, patchBase); err != nil {
+		cc.ReqLogger.Error(err, "Failed to update the LogCollectionDegraded condition")
+		return
+	}
+	if recovered {
+		cc.recordClusterEvent(corev1.EventTypeNormal, logCollectionReasonRecovered, "fluent-bit is running in every pod again")
+	}
+}
+
+// nextLogCollectionCheck is when a degraded log collection is checked next.
+// Pods are not watched by the cluster, so the restart window is not noticed
+// to pass otherwise.
+func nextLogCollectionCheck(cr *marklogicv1.MarklogicCluster) time.Time {
+	if cr.Status.GetConditionStatus(string(marklogicv1.LogCollectionDegraded)) != metav1.ConditionTrue {
+		return time.Time{}
+	}
+	return time.Now().Add(logCollectionCheckInterval)
+}
+
+func logCollectorStatus(pod corev1.Pod) *corev1.ContainerStatus {
+	for i := range pod.Status.ContainerStatuses {
+		if isLogCollectorContainer(pod.Status.ContainerStatuses[i].Name) {
+			return &pod.Status.ContainerStatuses[i]
+		}
+	}
+	return nil
+}
+
+// terminationMessage describes how a container terminated, with its
+// termination message when it left one.
+func terminationMessage(terminated *corev1.ContainerStateTerminated) string {
+	if terminated == nil {
+		return "waiting to restart"
+	}
+	description := fmt.Sprintf("exit code %d", terminated.ExitCode)
+	if terminated.Reason != "" {
+		description = fmt.Sprintf("%s, %s", terminated.Reason, description)
+	}
+	message := strings.TrimSpace(terminated.Message)
+	if message == "" {
+		return description
+	}
+	if len(message) > logCollectionMessageLimit {
+		message = "..." + message[len(message)-logCollectionMessageLimit:]
+	}
+	return fmt.Sprintf("%s: %s", description, message)
+}
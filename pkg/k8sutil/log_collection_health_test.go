@@ -0,0 +1,82 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"strings"
+	"testing"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestReconcileLogCollectionHealthReportsCrashLoopingSidecar(t *testing.T) {
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+		Spec:       marklogicv1.MarklogicClusterSpec{MarkLogicGroups: []*marklogicv1.MarklogicGroups{{Name: "dnode"}}},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "dnode-0", Namespace: "default", UID: "dnode-0-uid", Labels: map[string]string{
+			"app.kubernetes.io/name":     "marklogic",
+			"app.kubernetes.io/instance": "dnode",
+		}},
+		Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{
+			{Name: "marklogic-server", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+			{
+				Name:         "fluent-bit",
+				RestartCount: 3,
+				State:        corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+				LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+					ExitCode:   1,
+					Reason:     "Error",
+					Message:    "[error] [output:http:http.0] no upstream connections available",
+					FinishedAt: metav1.Now(),
+				}},
+			},
+		}},
+	}
+	cc := newUpgradeTestContext(t, cr, pod)
+	recorder := record.NewFakeRecorder(10)
+	cc.Recorder = recorder
+	defer logCollectionRestarts.Delete(types.NamespacedName{Namespace: "default", Name: "ml"})
+
+	cc.ReconcileLogCollectionHealth()
+	if status := cr.Status.GetConditionStatus(string(marklogicv1.LogCollectionDegraded)); status != metav1.ConditionTrue {
+		t.Fatalf("expected log collection to be degraded, got %s", status)
+	}
+	for _, condition := range cr.Status.Conditions {
+		if condition.Type == string(marklogicv1.LogCollectionDegraded) &&
+			(condition.Reason != logCollectionReasonCrashLooping || !strings.Contains(condition.Message, "no upstream connections")) {
+			t.Fatalf("unexpected condition %+v", condition)
+		}
+	}
+	select {
+	case event := <-recorder.Events:
+		if !strings.Contains(event, "Warning LogCollectionDegraded") || !strings.Contains(event, "no upstream connections") {
+			t.Fatalf("unexpected event %q", event)
+		}
+	default:
+		t.Fatalf("expected a Warning event for the restart")
+	}
+
+	cc.ReconcileLogCollectionHealth()
+	if len(recorder.Events) != 0 {
+		t.Fatalf("expected the restart to be reported once, got %q", <-recorder.Events)
+	}
+
+	pod.Status.ContainerStatuses[1].State = corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}
+	pod.Status.ContainerStatuses[1].LastTerminationState.Terminated.FinishedAt = metav1.NewTime(metav1.Now().Add(-2 * logCollectionRestartWindow))
+	if err := cc.Client.Status().Update(cc.Ctx, pod); err != nil {
+		t.Fatalf("failed to update the pod: %v", err)
+	}
+	cc.ReconcileLogCollectionHealth()
+	if status := cr.Status.GetConditionStatus(string(marklogicv1.LogCollectionDegraded)); status != metav1.ConditionFalse {
+		t.Fatalf("expected log collection to recover, got %s", status)
+	}
+	if event := <-recorder.Events; !strings.Contains(event, logCollectionReasonRecovered) {
+		t.Fatalf("unexpected event %q", event)
+	}
+}
@@ -0,0 +1,384 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"slices"
+	"strings"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	fluentdContainerName = "fluentd"
+	vectorContainerName  = "vector"
+
+	fluentdMonitorPort     = 24220
+	fluentdMonitorPortName = "fluentd-monitor"
+	vectorAPIPort          = 8686
+	vectorAPIPortName      = "vector-api"
+
+	// markLogicErrorLogRegex and markLogicAccessLogRegex parse the ErrorLog
+	// and AccessLog files. fluent-bit and fluentd both use Onigmo regular
+	// expressions.
+	markLogicErrorLogRegex  = `^(?<time>(.+?)(?=[a-zA-Z]))(?<log_level>(.+?)(?=:))(.+?)(?=[a-zA-Z])(?<log>.*)`
+	markLogicAccessLogRegex = `^(?<host>[^ ]*)(.+?)(?<=\- )(?<user>(.+?)(?=\[))(.+?)(?<=\[)(?<time>(.+?)(?=\]))(.+?)(?<=")(?<request>[^\ ]+[^\"]+)(.+?)(?=\d)(?<response_code>[^\ ]*)(.+?)(?=\d|-)(?<response_obj_size>[^\ ]*)(.+?)(?=")(?<request_info>.*)`
+	// vectorErrorLogRegex and vectorAccessLogRegex are the same for vector,
+	// whose regular expressions have no lookaround.
+	vectorErrorLogRegex  = `^(?P<time>\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2}\.\d+) (?P<log_level>[A-Za-z]+): (?P<log>.*)$`
+	vectorAccessLogRegex = `^(?P<host>\S+) \S+ (?P<user>\S+) \[(?P<time>[^\]]+)\] "(?P<request>[^"]*)" (?P<response_code>\d{3}) (?P<response_obj_size>\S+) ?(?P<request_info>.*)$`
+)
+
+// logCollectorContainerNames are the names of the sidecars of the engines.
+var logCollectorContainerNames = []string{fluentBitContainerName, fluentdContainerName, vectorContainerName}
+
+// logCollector is an engine of LogCollection: the sidecar that ships the
+// MarkLogic logs of a pod, and its configuration in the ConfigMap of the
+// group.
+type logCollector interface {
+	// name is the name of the sidecar and of its configuration volume.
+	name() string
+	// configPath is where the configuration is mounted in the sidecar.
+	configPath() string
+	// configData is the data of the ConfigMap.
+	configData(logCollection *marklogicv1.LogCollection) map[string]string
+	// container sets the command, ports and probes of the sidecar.
+	container(container *corev1.Container)
+}
+
+func newLogCollector(logCollection *marklogicv1.LogCollection) logCollector {
+	switch logCollection.Engine {
+	case marklogicv1.LogCollectionEngineFluentd:
+		return fluentdCollector{}
+	case marklogicv1.LogCollectionEngineVector:
+		return vectorCollector{}
+	default:
+		return fluentBitCollector{}
+	}
+}
+
+// markLogicLogFile is a MarkLogic log file collected by default.
+type markLogicLogFile struct {
+	// id names the file in the configuration of vector.
+	id, path, tag string
+	// parser is the fluent-bit parser of the file, if any.
+	parser string
+}
+
+// markLogicLogFiles returns the log files selected by files.
+func markLogicLogFiles(files marklogicv1.LogFilesConfig) []markLogicLogFile {
+	var selected []markLogicLogFile
+	if files.ErrorLogs {
+		selected = append(selected, markLogicLogFile{"error_log", "/var/opt/MarkLogic/Logs/*ErrorLog.txt", "kube.marklogic.logs.error", "error_parser"})
+	}
+	if files.AccessLogs {
+		selected = append(selected, markLogicLogFile{"access_log", "/var/opt/MarkLogic/Logs/*AccessLog.txt", "kube.marklogic.logs.access", accessLogParser(files)})
+	}
+	if files.RequestLogs {
+		selected = append(selected, markLogicLogFile{"request_log", "/var/opt/MarkLogic/Logs/*RequestLog.txt", "kube.marklogic.logs.request", "json_parser"})
+	}
+	if files.CrashLogs {
+		selected = append(selected, markLogicLogFile{"crash_log", "/var/opt/MarkLogic/Logs/CrashLog.txt", "kube.marklogic.logs.crash", ""})
+	}
+	if files.AuditLogs {
+		selected = append(selected, markLogicLogFile{"audit_log", "/var/opt/MarkLogic/Logs/AuditLog.txt", "kube.marklogic.logs.audit", ""})
+	}
+	return selected
+}
+
+// indentYAML indents a user supplied YAML fragment by indent spaces, keeping
+// the nesting of its lines.
+func indentYAML(content string, indent int) string {
+	lines := strings.Split(strings.ReplaceAll(strings.TrimRight(content, " \n"), "\t", "    "), "\n")
+	common := -1
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if leading := len(line) - len(strings.TrimLeft(line, " ")); common < 0 || leading < common {
+			common = leading
+		}
+	}
+	indented := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		indented = append(indented, strings.Repeat(" ", indent)+line[common:])
+	}
+	return strings.Join(indented, "\n")
+}
+
+type fluentBitCollector struct{}
+
+func (fluentBitCollector) name() string { return fluentBitContainerName }
+
+func (fluentBitCollector) configPath() string { return "/fluent-bit/etc/" }
+
+func (fluentBitCollector) configData(logCollection *marklogicv1.LogCollection) map[string]string {
+	return fluentBitConfigData(logCollection)
+}
+
+func (fluentBitCollector) container(container *corev1.Container) {
+	container.Command = []string{"/fluent-bit/bin/fluent-bit"}
+	container.Args = []string{"--config=/fluent-bit/etc/fluent-bit.yaml"}
+	container.Ports = []corev1.ContainerPort{
+		{Name: fluentBitHTTPPortName, ContainerPort: fluentBitHTTPPort, Protocol: corev1.ProtocolTCP},
+	}
+	container.LivenessProbe = fluentBitLivenessProbe()
+	container.ReadinessProbe = fluentBitReadinessProbe()
+}
+
+// fluentdCollector runs fluentd with its YAML configuration. The inputs,
+// filters and outputs are lists of source, filter and match directives.
+type fluentdCollector struct{}
+
+func (fluentdCollector) name() string { return fluentdContainerName }
+
+func (fluentdCollector) configPath() string { return "/fluentd/etc/" }
+
+func (fluentdCollector) configData(logCollection *marklogicv1.LogCollection) map[string]string {
+	config := `system:
+  log_level: info
+config:
+  - source:
+      $type: monitor_agent
+      bind: 0.0.0.0
+      port: 24220`
+	if strings.TrimSpace(logCollection.Inputs) != "" {
+		config += "\n" + indentYAML(logCollection.Inputs, 2)
+	} else {
+		for _, file := range markLogicLogFiles(logCollection.Files) {
+			config += `
+  - source:
+      $type: tail
+      path: ` + file.path + `
+      read_from_head: true
+      tag: ` + file.tag + `
+      path_key: path
+      parse:` + fluentdParse(file.parser)
+		}
+	}
+	if strings.TrimSpace(logCollection.Filters) != "" {
+		config += "\n" + indentYAML(logCollection.Filters, 2)
+	} else {
+		config += `
+  - filter:
+      $type: record_transformer
+      $tag: "**"
+      record:
+        pod: "#{ENV['POD_NAME']}"
+        namespace: "#{ENV['NAMESPACE']}"
+        tag: ${tag}`
+	}
+	if strings.TrimSpace(logCollection.Outputs) != "" {
+		config += "\n" + indentYAML(logCollection.Outputs, 2)
+	} else {
+		config += `
+  - match:
+      $type: stdout
+      $tag: "**"
+      format:
+        $type: json`
+	}
+	return map[string]string{"fluent.yaml": config + "\n"}
+}
+
+// fluentdParse is the parse section of the tail source of a log file, the
+// same as the fluent-bit parser.
+func fluentdParse(parser string) string {
+	switch parser {
+	case "error_parser":
+		return `
+        $type: regexp
+        expression: '/` + markLogicErrorLogRegex + `/'
+        time_key: time
+        time_format: "%Y-%m-%d %H:%M:%S.%L"`
+	case "access_parser":
+		return `
+        $type: regexp
+        expression: '/` + markLogicAccessLogRegex + `/'
+        time_key: time
+        time_format: "%d/%b/%Y:%H:%M:%S %z"`
+	case accessStructuredParser:
+		return `
+        $type: regexp
+        expression: '/` + structuredAccessLogRegex + `/'
+        time_key: time
+        time_format: "%d/%b/%Y:%H:%M:%S %z"
+        types: ` + strings.ReplaceAll(structuredAccessLogTypes, " ", ",")
+	case "json_parser":
+		return `
+        $type: json
+        time_key: time
+        time_format: "%Y-%m-%dT%H:%M:%S%z"`
+	default:
+		return `
+        $type: none
+        message_key: log`
+	}
+}
+
+func (fluentdCollector) container(container *corev1.Container) {
+	container.Command = []string{"fluentd"}
+	container.Args = []string{"-c", "/fluentd/etc/fluent.yaml"}
+	container.Ports = []corev1.ContainerPort{
+		{Name: fluentdMonitorPortName, ContainerPort: fluentdMonitorPort, Protocol: corev1.ProtocolTCP},
+	}
+	container.LivenessProbe = logCollectorProbe("/api/plugins.json", fluentdMonitorPortName, 30)
+	container.ReadinessProbe = logCollectorProbe("/api/plugins.json", fluentdMonitorPortName, 10)
+}
+
+// vectorCollector runs vector. The inputs, filters and outputs are the
+// sources, transforms and sinks of its configuration. The default transforms
+// are named marklogic_<source>, so outputs read all of them with the
+// marklogic_* input.
+type vectorCollector struct{}
+
+func (vectorCollector) name() string { return vectorContainerName }
+
+func (vectorCollector) configPath() string { return "/etc/vector/" }
+
+func (vectorCollector) configData(logCollection *marklogicv1.LogCollection) map[string]string {
+	config := `data_dir: /tmp
+api:
+  enabled: true
+  address: 0.0.0.0:8686
+sources:`
+	var sources []string
+	if strings.TrimSpace(logCollection.Inputs) != "" {
+		config += "\n" + indentYAML(logCollection.Inputs, 2)
+		// The sources are enriched with the pod, whatever they collect.
+		parsed := map[string]any{}
+		_ = yaml.Unmarshal([]byte(logCollection.Inputs), &parsed)
+		for id := range parsed {
+			sources = append(sources, id)
+		}
+		slices.Sort(sources)
+	} else {
+		for _, file := range markLogicLogFiles(logCollection.Files) {
+			config += `
+  ` + file.id + `:
+    type: file
+    include:
+      - ` + file.path + `
+    read_from: beginning`
+		}
+	}
+	config += `
+transforms:`
+	if strings.TrimSpace(logCollection.Filters) != "" {
+		config += "\n" + indentYAML(logCollection.Filters, 2)
+	} else if strings.TrimSpace(logCollection.Inputs) != "" {
+		for _, id := range sources {
+			config += `
+  marklogic_` + id + `:
+    type: remap
+    inputs:
+      - ` + id + `
+    source: |
+      .pod = "${POD_NAME}"
+      .namespace = "${NAMESPACE}"`
+		}
+	} else {
+		for _, file := range markLogicLogFiles(logCollection.Files) {
+			config += `
+  marklogic_` + file.id + `:
+    type: remap
+    inputs:
+      - ` + file.id + `
+    source: |
+      .tag = "` + file.tag + `"
+      .path = del(.file)
+      .pod = "${POD_NAME}"
+      .namespace = "${NAMESPACE}"` + vectorParse(file.parser)
+		}
+	}
+	config += `
+sinks:`
+	if strings.TrimSpace(logCollection.Outputs) != "" {
+		config += "\n" + indentYAML(logCollection.Outputs, 2)
+	} else {
+		config += `
+  stdout:
+    type: console
+    inputs:
+      - marklogic_*
+    encoding:
+      codec: json`
+	}
+	return map[string]string{"vector.yaml": config + "\n"}
+}
+
+// vectorParse is the VRL that parses a log file, the same as the fluent-bit
+// parser. Lines that do not parse are kept in the log field.
+func vectorParse(parser string) string {
+	switch parser {
+	case "error_parser", "access_parser", accessStructuredParser:
+		regex, format, types := vectorErrorLogRegex, "%Y-%m-%d %H:%M:%S%.3f", ""
+		switch parser {
+		case "access_parser":
+			regex, format = vectorAccessLogRegex, "%d/%b/%Y:%H:%M:%S %z"
+		case accessStructuredParser:
+			regex, format = vectorStructuredAccessLogRegex, "%d/%b/%Y:%H:%M:%S %z"
+			types = `
+        .status = to_int(.status) ?? .status
+        .size = to_int(.size) ?? .size
+        .response_time = to_float(.response_time) ?? .response_time`
+		}
+		return `
+      parsed, err = parse_regex(.message, r'` + regex + `')
+      if err == null {
+        . = merge(., parsed)
+        del(.message)
+        .timestamp = parse_timestamp(.time, "` + format + `") ?? .timestamp` + types + `
+      } else {
+        .log = del(.message)
+      }`
+	case "json_parser":
+		return `
+      parsed, err = parse_json(.message)
+      if err == null && is_object(parsed) {
+        . = merge(., object!(parsed))
+        del(.message)
+        .timestamp = parse_timestamp(.time, "%Y-%m-%dT%H:%M:%S%z") ?? .timestamp
+      } else {
+        .log = del(.message)
+      }`
+	default:
+		return `
+      .log = del(.message)`
+	}
+}
+
+func (vectorCollector) container(container *corev1.Container) {
+	container.Command = []string{"vector"}
+	container.Args = []string{"--config", "/etc/vector/vector.yaml"}
+	container.Ports = []corev1.ContainerPort{
+		{Name: vectorAPIPortName, ContainerPort: vectorAPIPort, Protocol: corev1.ProtocolTCP},
+	}
+	container.LivenessProbe = logCollectorProbe("/health", vectorAPIPortName, 30)
+	container.ReadinessProbe = logCollectorProbe("/health", vectorAPIPortName, 10)
+}
+
+// logCollectorProbe checks that the log collector responds on its HTTP API.
+func logCollectorProbe(path, port string, periodSeconds int32) *corev1.Probe {
+	return &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			HTTPGet: &corev1.HTTPGetAction{Path: path, Port: intstr.FromString(port)},
+		},
+		InitialDelaySeconds: 10,
+		PeriodSeconds:       periodSeconds,
+		TimeoutSeconds:      5,
+		FailureThreshold:    3,
+	}
+}
+
+// isLogCollectorContainer reports whether a container is the sidecar of a
+// log collection engine.
+func isLogCollectorContainer(name string) bool {
+	return slices.Contains(logCollectorContainerNames, name)
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"strings"
+	"testing"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"sigs.k8s.io/yaml"
+)
+
+func TestLogCollectorEngines(t *testing.T) {
+	files := marklogicv1.LogFilesConfig{ErrorLogs: true, AccessLogs: true, CrashLogs: true}
+	for _, tc := range []struct {
+		engine     marklogicv1.LogCollectionEngine
+		image      string
+		configFile string
+		mountPath  string
+		port       int32
+	}{
+		{marklogicv1.LogCollectionEngineFluentBit, OperatorConfig.LogCollectionImage, "fluent-bit.yaml", "/fluent-bit/etc/", fluentBitHTTPPort},
+		{marklogicv1.LogCollectionEngineFluentd, OperatorConfig.FluentdImage, "fluent.yaml", "/fluentd/etc/", fluentdMonitorPort},
+		{marklogicv1.LogCollectionEngineVector, OperatorConfig.VectorImage, "vector.yaml", "/etc/vector/", vectorAPIPort},
+	} {
+		t.Run(string(tc.engine), func(t *testing.T) {
+			logCollection := &marklogicv1.LogCollection{Enabled: true, Engine: tc.engine, Files: files}
+			collector := newLogCollector(logCollection)
+			data := collector.configData(logCollection)
+			config := map[string]any{}
+			if err := yaml.Unmarshal([]byte(data[tc.configFile]), &config); err != nil {
+				t.Fatalf("invalid %s configuration: %v", tc.engine, err)
+			}
+			for _, path := range []string{"*ErrorLog.txt", "*AccessLog.txt", "CrashLog.txt"} {
+				if !strings.Contains(data[tc.configFile], "/var/opt/MarkLogic/Logs/"+path) {
+					t.Fatalf("expected %s to collect %s, got\n%s", tc.engine, path, data[tc.configFile])
+				}
+			}
+
+			containerParams := containerParameters{Name: "dnode", LogCollection: logCollection, HugePages: &marklogicv1.HugePages{}}
+			containers := generateContainerDef("dnode", containerParams)
+			sidecar := containers[len(containers)-1]
+			if sidecar.Name != collector.name() || sidecar.Image != tc.image || sidecar.Ports[0].ContainerPort != tc.port ||
+				sidecar.LivenessProbe == nil || sidecar.ReadinessProbe == nil {
+				t.Fatalf("unexpected sidecar %+v", sidecar)
+			}
+			mounted := false
+			for _, mount := range sidecar.VolumeMounts {
+				mounted = mounted || (mount.Name == collector.name() && mount.MountPath == tc.mountPath)
+			}
+			found := false
+			for _, volume := range generateVolumes("dnode", containerParams) {
+				found = found || (volume.Name == collector.name() && volume.ConfigMap != nil && volume.ConfigMap.Name == fluentBitConfigMapName("dnode"))
+			}
+			if !mounted || !found {
+				t.Fatalf("expected the configuration to be mounted at %s, got %+v", tc.mountPath, sidecar.VolumeMounts)
+			}
+		})
+	}
+}
+
+func TestVectorEnrichesCustomSources(t *testing.T) {
+	logCollection := &marklogicv1.LogCollection{
+		Engine:  marklogicv1.LogCollectionEngineVector,
+		Inputs:  "  app:\n    type: file\n    include:\n      - /var/opt/MarkLogic/Logs/8000_ErrorLog.txt",
+		Outputs: "loki:\n  type: loki\n  inputs: [\"marklogic_*\"]\n  endpoint: http://loki:3100",
+	}
+	var config struct {
+		Sources    map[string]map[string]any `json:"sources"`
+		Transforms map[string]map[string]any `json:"transforms"`
+		Sinks      map[string]map[string]any `json:"sinks"`
+	}
+	if err := yaml.Unmarshal([]byte(vectorCollector{}.configData(logCollection)["vector.yaml"]), &config); err != nil {
+		t.Fatalf("invalid vector configuration: %v", err)
+	}
+	if len(config.Sources) != 1 || config.Sources["app"]["type"] != "file" {
+		t.Fatalf("unexpected sources %v", config.Sources)
+	}
+	if transform := config.Transforms["marklogic_app"]; len(config.Transforms) != 1 || !strings.Contains(transform["source"].(string), "${POD_NAME}") {
+		t.Fatalf("expected the app source to be enriched, got %v", config.Transforms)
+	}
+	if len(config.Sinks) != 1 || config.Sinks["loki"]["endpoint"] != "http://loki:3100" {
+		t.Fatalf("unexpected sinks %v", config.Sinks)
+	}
+}
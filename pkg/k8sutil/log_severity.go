@@ -0,0 +1,115 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+)
+
+const (
+	fluentBitSamplingScript = "sampling.lua"
+	// severityRouteTagPrefix tags the records of a severity route. It is
+	// outside kube.*, so routed records are not sampled or routed again
+	// when fluent-bit emits them with their new tag.
+	severityRouteTagPrefix = "marklogic.severity."
+)
+
+// logSeverities are the levels of the MarkLogic ErrorLog, from the lowest.
+var logSeverities = []marklogicv1.LogSeverity{
+	"Finest", "Finer", "Fine", "Debug", "Config", "Info", "Notice", "Warning", "Error", "Critical", "Alert", "Emergency",
+}
+
+// severitiesFrom returns min and the severities above it.
+func severitiesFrom(min marklogicv1.LogSeverity) []string {
+	index := max(slices.Index(logSeverities, min), 0)
+	severities := make([]string, 0, len(logSeverities)-index)
+	for _, severity := range logSeverities[index:] {
+		severities = append(severities, string(severity))
+	}
+	return severities
+}
+
+// fluentBitSamplingData returns the Lua script that drops the sampled
+// records, keyed by its file name, or nothing without sampling.
+func fluentBitSamplingData(logCollection *marklogicv1.LogCollection) map[string]string {
+	sampling := logCollection.Sampling
+	if sampling == nil {
+		return nil
+	}
+	minSeverity := sampling.MinSeverity
+	if minSeverity == "" {
+		minSeverity = "Warning"
+	}
+	return map[string]string{fluentBitSamplingScript: fmt.Sprintf(`-- Ships the records at or above %[1]s and %[2]d%% of the others. Records
+-- without a severity are always shipped.
+local shipped = {}
+for _, severity in ipairs({"%[3]s"}) do
+  shipped[severity] = true
+end
+
+function sample(tag, timestamp, record)
+  local severity = record["log_level"]
+  if severity == nil or shipped[severity] or math.random(100) <= %[2]d then
+    return 0, timestamp, record
+  end
+  return -1, timestamp, record
+end
+`, minSeverity, sampling.Percent, strings.Join(severitiesFrom(minSeverity), `", "`))}
+}
+
+// sortedSeverityRoutes returns the routes by descending minSeverity, the
+// order in which records are matched against them.
+func sortedSeverityRoutes(routes []marklogicv1.LogSeverityRoute) []marklogicv1.LogSeverityRoute {
+	sorted := slices.Clone(routes)
+	slices.SortStableFunc(sorted, func(a, b marklogicv1.LogSeverityRoute) int {
+		return slices.Index(logSeverities, b.MinSeverity) - slices.Index(logSeverities, a.MinSeverity)
+	})
+	return sorted
+}
+
+// fluentBitSeverityFilters returns the filters that sample and route the
+// records by severity. They run after the other filters, on the records
+// tagged kube.*, which includes the MarkLogic logs collected by default.
+// The filters are indented like the list items they are appended to.
+func fluentBitSeverityFilters(logCollection *marklogicv1.LogCollection, listItemIndent int) string {
+	filters := ""
+	if logCollection.Sampling != nil {
+		filters += `
+    - name: lua
+      match: kube.*
+      script: ` + fluentBitCollector{}.configPath() + fluentBitSamplingScript + `
+      call: sample`
+	}
+	if len(logCollection.SeverityRoutes) > 0 {
+		filters += `
+    - name: rewrite_tag
+      match: kube.*
+      rule:`
+		for _, route := range sortedSeverityRoutes(logCollection.SeverityRoutes) {
+			filters += `
+        - $log_level ^(` + strings.Join(severitiesFrom(route.MinSeverity), "|") + `)$ ` + severityRouteTagPrefix + route.Name + ` false`
+		}
+	}
+	if filters == "" {
+		return ""
+	}
+	return "\n" + normalizeYAMLIndentation(filters, listItemIndent, listItemIndent+2)
+}
+
+// fluentBitSeverityRouteOutputs returns the outputs of the severity routes.
+// Outputs without a match receive the records of their route.
+func fluentBitSeverityRouteOutputs(logCollection *marklogicv1.LogCollection) string {
+	outputs := ""
+	for _, route := range logCollection.SeverityRoutes {
+		if strings.TrimSpace(route.Outputs) == "" {
+			continue
+		}
+		routeOutputs := "\n" + normalizeYAMLIndentation(route.Outputs, 4, 6)
+		outputs += withItemProperties(routeOutputs, 4, []yamlProperty{{"match", severityRouteTagPrefix + route.Name}})
+	}
+	return outputs
+}
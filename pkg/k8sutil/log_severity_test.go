@@ -0,0 +1,68 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"strings"
+	"testing"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"sigs.k8s.io/yaml"
+)
+
+func TestFluentBitSamplesAndRoutesBySeverity(t *testing.T) {
+	for _, filters := range []string{"", "- name: grep\n  match: \"*\"\n  exclude: log ^$"} {
+		logCollection := &marklogicv1.LogCollection{
+			Enabled:  true,
+			Files:    marklogicv1.LogFilesConfig{ErrorLogs: true},
+			Filters:  filters,
+			Sampling: &marklogicv1.LogSampling{Percent: 10, MinSeverity: "Warning"},
+			SeverityRoutes: []marklogicv1.LogSeverityRoute{
+				{Name: "notices", MinSeverity: "Notice", Outputs: "- name: stdout\n  format: json_lines"},
+				{Name: "alerts", MinSeverity: "Critical", Outputs: "- name: http\n  host: pager\n  match: ignored"},
+			},
+		}
+		data := fluentBitConfigData(logCollection)
+		var config struct {
+			Pipeline struct {
+				Filters []map[string]any `json:"filters"`
+				Outputs []map[string]any `json:"outputs"`
+			} `json:"pipeline"`
+		}
+		if err := yaml.Unmarshal([]byte(data["fluent-bit.yaml"]), &config); err != nil {
+			t.Fatalf("invalid fluent-bit configuration: %v\n%s", err, data["fluent-bit.yaml"])
+		}
+
+		filtersCount := len(config.Pipeline.Filters)
+		sampling, routing := config.Pipeline.Filters[filtersCount-2], config.Pipeline.Filters[filtersCount-1]
+		if sampling["name"] != "lua" || sampling["script"] != "/fluent-bit/etc/sampling.lua" || sampling["call"] != "sample" {
+			t.Fatalf("expected the sampling filter, got %v", sampling)
+		}
+		rules, _ := routing["rule"].([]any)
+		if routing["name"] != "rewrite_tag" || len(rules) != 2 ||
+			rules[0] != "$log_level ^(Critical|Alert|Emergency)$ marklogic.severity.alerts false" ||
+			rules[1] != "$log_level ^(Notice|Warning|Error|Critical|Alert|Emergency)$ marklogic.severity.notices false" {
+			t.Fatalf("expected the routes from the highest severity, got %v", routing)
+		}
+
+		outputs := config.Pipeline.Outputs
+		if len(outputs) != 3 || outputs[1]["match"] != "marklogic.severity.notices" || outputs[2]["match"] != "ignored" {
+			t.Fatalf("unexpected outputs %v", outputs)
+		}
+
+		script := data["sampling.lua"]
+		if !strings.Contains(script, `{"Warning", "Error", "Critical", "Alert", "Emergency"}`) ||
+			!strings.Contains(script, "math.random(100) <= 10") {
+			t.Fatalf("unexpected sampling script\n%s", script)
+		}
+	}
+}
+
+func TestFluentBitWithoutSamplingIsUnchanged(t *testing.T) {
+	logCollection := &marklogicv1.LogCollection{Enabled: true, Files: marklogicv1.LogFilesConfig{ErrorLogs: true}}
+	data := fluentBitConfigData(logCollection)
+	if _, ok := data["sampling.lua"]; ok || strings.Contains(data["fluent-bit.yaml"], "rewrite_tag") ||
+		strings.Contains(data["fluent-bit.yaml"], "name: lua") {
+		t.Fatalf("expected no sampling or routing, got\n%s", data["fluent-bit.yaml"])
+	}
+}
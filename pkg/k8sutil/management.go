@@ -0,0 +1,98 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"fmt"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// ManagementStatusCache shares the host and forest status of a cluster
+// between its reconcile steps and requeues for a few seconds. It is set up
+// from the operator configuration; nil caches nothing.
+var ManagementStatusCache *mlmanage.StatusCache
+
+// adminSecretName returns the Secret holding the MarkLogic admin credentials for the cluster.
+func (cc *ClusterContext) adminSecretName() string {
+	return AdminSecretName(cc.MarklogicCluster)
+}
+
+// AdminSecretName returns the Secret holding the MarkLogic admin credentials of a cluster.
+func AdminSecretName(cr *marklogicv1.MarklogicCluster) string {
+	if cr.Spec.Auth != nil && cr.Spec.Auth.SecretName != nil && *cr.Spec.Auth.SecretName != "" {
+		return *cr.Spec.Auth.SecretName
+	}
+	return fmt.Sprintf("%s-admin", cr.ObjectMeta.Name)
+}
+
+// bootstrapHostFQDN returns the DNS name of the first pod of the bootstrap group.
+func (cc *ClusterContext) bootstrapHostFQDN() (string, error) {
+	cr := cc.MarklogicCluster
+	for _, group := range cr.Spec.MarkLogicGroups {
+		if group != nil && group.IsBootstrap {
+			return fmt.Sprintf("%s-0.%s.%s.svc.%s", group.Name, group.Name, cr.Namespace, cr.Spec.ClusterDomain), nil
+		}
+	}
+	return "", fmt.Errorf("cluster %s has no bootstrap group", cr.Name)
+}
+
+// newBootstrapManagementClient builds a Manage API client against the bootstrap host.
+// It authenticates as the operator user once that user is provisioned and
+// with the cluster admin credentials otherwise.
+func (cc *ClusterContext) newBootstrapManagementClient() (mlmanage.Client, error) {
+	if secret, ok := cc.provisionedOperatorUserSecret(); ok {
+		return cc.newManagementClientFromSecret(secret)
+	}
+	return cc.newBootstrapAdminClient()
+}
+
+// newBootstrapAdminClient builds a Manage API client against the bootstrap host
+// using the cluster admin credentials, for calls that need the admin role.
+// While a changed admin password is not applied yet, the credentials
+// MarkLogic accepted last are used.
+func (cc *ClusterContext) newBootstrapAdminClient() (mlmanage.Client, error) {
+	secret, err := cc.getSecret(appliedAdminSecretName(cc.MarklogicCluster.Name))
+	if apierrors.IsNotFound(err) {
+		secret, err = cc.getSecret(cc.adminSecretName())
+	}
+	if err != nil {
+		return nil, err
+	}
+	return cc.newManagementClientFromSecret(secret)
+}
+
+func (cc *ClusterContext) newManagementClientFromSecret(secret *corev1.Secret) (mlmanage.Client, error) {
+	host, err := cc.bootstrapHostFQDN()
+	if err != nil {
+		return nil, err
+	}
+	username, hasUser := secret.Data["username"]
+	password, hasPass := secret.Data["password"]
+	if !hasUser || !hasPass {
+		return nil, fmt.Errorf("secret %s missing username/password", secret.Name)
+	}
+	cr := cc.MarklogicCluster
+	useTLS := cr.Spec.Tls != nil && cr.Spec.Tls.EnableOnDefaultAppServers
+	client := NewDynamicManagementClient(mlmanage.ClientOptions{
+		Host:               host,
+		Username:           string(username),
+		Password:           string(password),
+		UseTLS:             useTLS,
+		InsecureSkipVerify: useTLS,
+	})
+	return ManagementStatusCache.Client(cr.Namespace+"/"+cr.Name+"/"+host, client), nil
+}
+
+func (cc *ClusterContext) getSecret(name string) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+	nsName := types.NamespacedName{Name: name, Namespace: cc.MarklogicCluster.Namespace}
+	if err := cc.Client.Get(cc.Ctx, nsName, secret); err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
@@ -4,6 +4,7 @@ package k8sutil
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/cisco-open/k8s-objectmatcher/patch"
 	"github.com/go-logr/logr"
@@ -33,6 +34,9 @@ type MarkLogicGroupParameters struct {
 	Persistence                    *marklogicv1.Persistence
 	Auth                           *marklogicv1.AdminAuth
 	TerminationGracePeriodSeconds  *int64
+	Drain                          *marklogicv1.ShutdownDrain
+	HostJoin                       *marklogicv1.HostJoin
+	StartupProbe                   *marklogicv1.StartupProbe
 	Resources                      *corev1.ResourceRequirements
 	EnableConverters               bool
 	PriorityClassName              string
@@ -40,8 +44,11 @@ type MarkLogicGroupParameters struct {
 	UpdateStrategy                 appsv1.StatefulSetUpdateStrategyType
 	Affinity                       *corev1.Affinity
 	NodeSelector                   map[string]string
+	Architecture                   string
+	PodManagementPolicy            marklogicv1.PodManagementPolicy
 	TopologySpreadConstraints      []corev1.TopologySpreadConstraint
 	HugePages                      *marklogicv1.HugePages
+	ReadOnlyRootFilesystem         *marklogicv1.ReadOnlyRootFilesystem
 	LivenessProbe                  marklogicv1.ContainerProbe
 	ReadinessProbe                 marklogicv1.ContainerProbe
 	PodSecurityContext             *corev1.PodSecurityContext
@@ -56,6 +63,11 @@ type MarkLogicGroupParameters struct {
 	AdditionalVolumeMounts         *[]corev1.VolumeMount
 	SecretName                     string
 	AdditionalVolumeClaimTemplates *[]corev1.PersistentVolumeClaim
+	VeleroHooks                    *marklogicv1.VeleroHooks
+	AutoscalerDisruption           marklogicv1.AutoscalerDisruption
+	AllowDisruptibleNodes          bool
+	ExposeAdmin                    bool
+	RestrictedPodSecurity          bool
 }
 
 type MarkLogicClusterParameters struct {
@@ -72,11 +84,14 @@ type MarkLogicClusterParameters struct {
 	License                        *marklogicv1.License
 	Affinity                       *corev1.Affinity
 	NodeSelector                   map[string]string
+	Architecture                   string
+	PodManagementPolicy            marklogicv1.PodManagementPolicy
 	TopologySpreadConstraints      []corev1.TopologySpreadConstraint
 	PriorityClassName              string
 	EnableConverters               bool
 	Resources                      *corev1.ResourceRequirements
 	HugePages                      *marklogicv1.HugePages
+	ReadOnlyRootFilesystem         *marklogicv1.ReadOnlyRootFilesystem
 	LivenessProbe                  marklogicv1.ContainerProbe
 	ReadinessProbe                 marklogicv1.ContainerProbe
 	LogCollection                  *marklogicv1.LogCollection
@@ -85,9 +100,16 @@ type MarkLogicClusterParameters struct {
 	PathBasedRouting               bool
 	Tls                            *marklogicv1.Tls
 	TerminationGracePeriodSeconds  *int64
+	Drain                          *marklogicv1.ShutdownDrain
+	HostJoin                       *marklogicv1.HostJoin
+	StartupProbe                   *marklogicv1.StartupProbe
 	AdditionalVolumes              *[]corev1.Volume
 	AdditionalVolumeMounts         *[]corev1.VolumeMount
 	AdditionalVolumeClaimTemplates *[]corev1.PersistentVolumeClaim
+	VeleroHooks                    *marklogicv1.VeleroHooks
+	AutoscalerDisruption           marklogicv1.AutoscalerDisruption
+	ExposeAdmin                    bool
+	RestrictedPodSecurity          bool
 }
 
 func MarkLogicGroupLogger(namespace string, name string) logr.Logger {
@@ -139,9 +161,14 @@ func (cc *ClusterContext) GenerateMarkLogicGroupDef(cr *marklogicv1.MarklogicClu
 			Image:                          params.Image,
 			Labels:                         params.Labels,
 			Annotations:                    params.Annotations,
+			AdditionalLabels:               cr.Spec.AdditionalLabels,
+			AdditionalAnnotations:          cr.Spec.AdditionalAnnotations,
 			ImagePullSecrets:               params.ImagePullSecrets,
 			License:                        params.License,
 			TerminationGracePeriodSeconds:  params.TerminationGracePeriodSeconds,
+			Drain:                          params.Drain,
+			HostJoin:                       params.HostJoin,
+			StartupProbe:                   params.StartupProbe,
 			BootstrapHost:                  bootStrapHostName,
 			Resources:                      params.Resources,
 			EnableConverters:               params.EnableConverters,
@@ -152,6 +179,8 @@ func (cc *ClusterContext) GenerateMarkLogicGroupDef(cr *marklogicv1.MarklogicClu
 			UpdateStrategy:                 params.UpdateStrategy,
 			Affinity:                       params.Affinity,
 			NodeSelector:                   params.NodeSelector,
+			Architecture:                   params.Architecture,
+			PodManagementPolicy:            params.PodManagementPolicy,
 			Persistence:                    params.Persistence,
 			Service:                        params.Service,
 			LivenessProbe:                  params.LivenessProbe,
@@ -166,6 +195,13 @@ func (cc *ClusterContext) GenerateMarkLogicGroupDef(cr *marklogicv1.MarklogicClu
 			AdditionalVolumeMounts:         params.AdditionalVolumeMounts,
 			SecretName:                     params.SecretName,
 			AdditionalVolumeClaimTemplates: params.AdditionalVolumeClaimTemplates,
+			VeleroHooks:                    params.VeleroHooks,
+			AutoscalerDisruption:           params.AutoscalerDisruption,
+			AllowDisruptibleNodes:          params.AllowDisruptibleNodes,
+			ExposeAdmin:                    params.ExposeAdmin,
+			RestrictedPodSecurity:          params.RestrictedPodSecurity,
+			ReadOnlyRootFilesystem:         params.ReadOnlyRootFilesystem,
+			HostRenames:                    cr.Spec.HostRenames,
 		},
 	}
 	AddOwnerRefToObject(MarkLogicGroupDef, ownerDef)
@@ -179,7 +215,13 @@ func (cc *ClusterContext) ReconsileMarklogicCluster() (reconcile.Result, error)
 	total := len(operatorCR.Spec.MarkLogicGroups)
 	logger.Info("===== Total Count ==== ", "Count:", total)
 	cr := cc.MarklogicCluster
+	var scc *sccRanges
+	if cr.Spec.OCPCompatible {
+		ranges := cc.openShiftSCCRanges()
+		scc = &ranges
+	}
 
+	var held []string
 	for i := 0; i < total; i++ {
 		logger.Info("ReconcileCluster", "Count", i)
 		currentMlg := &marklogicv1.MarklogicGroup{}
@@ -188,11 +230,24 @@ func (cc *ClusterContext) ReconsileMarklogicCluster() (reconcile.Result, error)
 		namespacedName := types.NamespacedName{Name: name, Namespace: namespace}
 		clusterParams := generateMarkLogicClusterParams(cr)
 		params := generateMarkLogicGroupParams(cr, i, clusterParams)
+		if scc != nil {
+			applyOpenShiftSecurity(params, *scc)
+		}
+		stoppedReplicas := runReplicas(cr, cr.Spec.MarkLogicGroups[i])
+		if stoppedReplicas != nil {
+			params.Replicas = stoppedReplicas
+		}
 		markLogicGroupDef := cc.GenerateMarkLogicGroupDef(operatorCR, i, params)
 		err := cc.Client.Get(cc.Ctx, namespacedName, currentMlg)
 		if err != nil {
 			if apierrors.IsNotFound(err) {
+				if upgradeHoldsChanges(cr) {
+					held = append(held, "new group "+name)
+					continue
+				}
 				logger.Info("MarkLogicGroup resource not found. Creating a new one")
+				hash := specHash(markLogicGroupDef.Spec, markLogicGroupDef.ObjectMeta)
+				setSpecHash(markLogicGroupDef, hash)
 				if err := patch.DefaultAnnotator.SetLastAppliedAnnotation(markLogicGroupDef); err != nil {
 					logger.Error(err, "Failed to set last applied annotation")
 				}
@@ -201,6 +256,10 @@ func (cc *ClusterContext) ReconsileMarklogicCluster() (reconcile.Result, error)
 					logger.Error(err, "Failed to create markLogicCluster")
 					return result.Error(err).Output()
 				}
+				if err := observeGeneration(cc.Ctx, cc.Client, markLogicGroupDef, hash); err != nil {
+					logger.Error(err, "Error recording the observed generation of the MarklogicGroup")
+					return result.Error(err).Output()
+				}
 
 				logger.Info("Created new MarkLogic Server resource")
 			} else {
@@ -208,11 +267,34 @@ func (cc *ClusterContext) ReconsileMarklogicCluster() (reconcile.Result, error)
 				return result.Error(err).Output()
 			}
 		} else {
+			if owner, conflict := GroupOwnedByOtherCluster(cr, currentMlg); conflict {
+				cc.reportGroupNameConflict(name, owner)
+				continue
+			}
+			if stoppedReplicas == nil {
+				if replicas := kedaReplicas(cr.Spec.MarkLogicGroups[i], currentMlg); replicas != nil {
+					markLogicGroupDef.Spec.Replicas = replicas
+				}
+			}
+			unsafe, err := cc.deferGroupChanges(currentMlg, markLogicGroupDef)
+			if err != nil {
+				logger.Error(err, "Error calculating the deferred MarkLogicGroup changes")
+				return result.Error(err).Output()
+			}
+			if len(unsafe) > 0 {
+				held = append(held, fmt.Sprintf("%s of group %s", strings.Join(unsafe, ", "), name))
+			}
 			if err := immutableMarklogicGroupSpecMismatch(currentMlg, markLogicGroupDef); err != nil {
 				logger.Error(err, "Existing MarkLogicGroup cannot be reconciled to desired immutable spec")
 				return result.Error(err).Output()
 			}
 
+			hash := specHash(markLogicGroupDef.Spec, markLogicGroupDef.ObjectMeta)
+			if specUpToDate(currentMlg, hash) {
+				logger.Info("MarkLogicGroup is up to date with the MarkLogicCluster spec, no update required")
+				continue
+			}
+			setSpecHash(markLogicGroupDef, hash)
 			patchDiff, err := patch.DefaultPatchMaker.Calculate(currentMlg, markLogicGroupDef,
 				patch.IgnoreStatusFields(),
 				patch.IgnoreVolumeClaimTemplateTypeMetaAndStatus(),
@@ -236,12 +318,21 @@ func (cc *ClusterContext) ReconsileMarklogicCluster() (reconcile.Result, error)
 					logger.Error(err, "Error updating MarklogicGroup")
 					return result.Error(err).Output()
 				}
+				currentMlg = markLogicGroupDef
 			} else {
 				logger.Info("MarkLogicGroup spec is same as the current spec, no update required")
 			}
+			if err := observeGeneration(cc.Ctx, cc.Client, currentMlg, hash); err != nil {
+				logger.Error(err, "Error recording the observed generation of the MarklogicGroup")
+				return result.Error(err).Output()
+			}
 		}
 
 	}
+	if err := cc.setUpgradeChangesHeld(held); err != nil {
+		logger.Error(err, "Error reporting the changes held back by the upgrade")
+		return result.Error(err).Output()
+	}
 	return result.Done().Output()
 }
 
@@ -261,7 +352,7 @@ func generateMarkLogicClusterParams(cr *marklogicv1.MarklogicCluster) *MarkLogic
 	markLogicClusterParameters := &MarkLogicClusterParameters{
 		Name:                           cr.ObjectMeta.Name,
 		UpdateStrategy:                 cr.Spec.UpdateStrategy,
-		Image:                          cr.Spec.Image,
+		Image:                          rolloutImage(cr),
 		ImagePullPolicy:                cr.Spec.ImagePullPolicy,
 		ImagePullSecrets:               cr.Spec.ImagePullSecrets,
 		ServiceAccountName:             cr.Spec.ServiceAccountName,
@@ -269,23 +360,39 @@ func generateMarkLogicClusterParams(cr *marklogicv1.MarklogicCluster) *MarkLogic
 		Persistence:                    cr.Spec.Persistence,
 		Affinity:                       cr.Spec.Affinity,
 		NodeSelector:                   cr.Spec.NodeSelector,
+		Architecture:                   cr.Spec.Architecture,
+		PodManagementPolicy:            cr.Spec.PodManagementPolicy,
 		TopologySpreadConstraints:      cr.Spec.TopologySpreadConstraints,
 		PriorityClassName:              cr.Spec.PriorityClassName,
 		License:                        cr.Spec.License,
 		EnableConverters:               cr.Spec.EnableConverters,
 		Resources:                      cr.Spec.Resources,
 		HugePages:                      cr.Spec.HugePages,
-		LivenessProbe:                  marklogicv1.ContainerProbe{Enabled: true, InitialDelaySeconds: 30, TimeoutSeconds: 5, PeriodSeconds: 30, SuccessThreshold: 1, FailureThreshold: 3},
-		ReadinessProbe:                 marklogicv1.ContainerProbe{Enabled: true, InitialDelaySeconds: 10, TimeoutSeconds: 5, PeriodSeconds: 30, SuccessThreshold: 1, FailureThreshold: 3},
+		ReadOnlyRootFilesystem:         cr.Spec.ReadOnlyRootFilesystem,
+		LivenessProbe:                  defaultProbe(OperatorConfig.LivenessProbe),
+		ReadinessProbe:                 defaultProbe(OperatorConfig.ReadinessProbe),
 		LogCollection:                  cr.Spec.LogCollection,
 		Auth:                           cr.Spec.Auth,
 		PodSecurityContext:             cr.Spec.PodSecurityContext,
 		ContainerSecurityContext:       cr.Spec.ContainerSecurityContext,
 		Tls:                            cr.Spec.Tls,
 		TerminationGracePeriodSeconds:  cr.Spec.TerminationGracePeriodSeconds,
+		Drain:                          cr.Spec.Drain,
+		HostJoin:                       cr.Spec.HostJoin,
+		StartupProbe:                   cr.Spec.StartupProbe,
 		AdditionalVolumes:              cr.Spec.AdditionalVolumes,
 		AdditionalVolumeMounts:         cr.Spec.AdditionalVolumeMounts,
 		AdditionalVolumeClaimTemplates: cr.Spec.AdditionalVolumeClaimTemplates,
+		AutoscalerDisruption:           cr.Spec.AutoscalerDisruption,
+		RestrictedPodSecurity:          cr.Spec.RestrictedPodSecurity,
+	}
+
+	if cr.Spec.NetworkAccess != nil {
+		markLogicClusterParameters.ExposeAdmin = cr.Spec.NetworkAccess.ExposeAdmin
+	}
+
+	if cr.Spec.Backup != nil && cr.Spec.Backup.Velero != nil && cr.Spec.Backup.Velero.Enabled {
+		markLogicClusterParameters.VeleroHooks = cr.Spec.Backup.Velero
 	}
 
 	if cr.Spec.HAProxy == nil || cr.Spec.HAProxy.PathBasedRouting == nil || !cr.Spec.HAProxy.Enabled || !*cr.Spec.HAProxy.PathBasedRouting {
@@ -308,7 +415,7 @@ func generateMarkLogicGroupParams(cr *marklogicv1.MarklogicCluster, index int, c
 		Annotations:                    cr.Spec.MarkLogicGroups[index].Annotations,
 		GroupConfig:                    cr.Spec.MarkLogicGroups[index].GroupConfig,
 		Service:                        cr.Spec.MarkLogicGroups[index].Service,
-		Image:                          clusterParams.Image,
+		Image:                          groupRolloutImage(cr, cr.Spec.MarkLogicGroups[index].IsBootstrap),
 		ImagePullPolicy:                clusterParams.ImagePullPolicy,
 		ImagePullSecrets:               clusterParams.ImagePullSecrets,
 		Auth:                           clusterParams.Auth,
@@ -317,6 +424,9 @@ func generateMarkLogicGroupParams(cr *marklogicv1.MarklogicCluster, index int, c
 		License:                        clusterParams.License,
 		Persistence:                    clusterParams.Persistence,
 		TerminationGracePeriodSeconds:  clusterParams.TerminationGracePeriodSeconds,
+		Drain:                          clusterParams.Drain,
+		HostJoin:                       clusterParams.HostJoin,
+		StartupProbe:                   clusterParams.StartupProbe,
 		Resources:                      clusterParams.Resources,
 		EnableConverters:               clusterParams.EnableConverters,
 		UpdateStrategy:                 clusterParams.UpdateStrategy,
@@ -324,8 +434,11 @@ func generateMarkLogicGroupParams(cr *marklogicv1.MarklogicCluster, index int, c
 		ClusterDomain:                  clusterParams.ClusterDomain,
 		Affinity:                       clusterParams.Affinity,
 		NodeSelector:                   clusterParams.NodeSelector,
+		Architecture:                   clusterParams.Architecture,
+		PodManagementPolicy:            clusterParams.PodManagementPolicy,
 		TopologySpreadConstraints:      clusterParams.TopologySpreadConstraints,
 		HugePages:                      clusterParams.HugePages,
+		ReadOnlyRootFilesystem:         clusterParams.ReadOnlyRootFilesystem,
 		LivenessProbe:                  clusterParams.LivenessProbe,
 		ReadinessProbe:                 clusterParams.ReadinessProbe,
 		PodSecurityContext:             clusterParams.PodSecurityContext,
@@ -339,6 +452,10 @@ func generateMarkLogicGroupParams(cr *marklogicv1.MarklogicCluster, index int, c
 		AdditionalVolumeMounts:         clusterParams.AdditionalVolumeMounts,
 		AdditionalVolumes:              clusterParams.AdditionalVolumes,
 		AdditionalVolumeClaimTemplates: clusterParams.AdditionalVolumeClaimTemplates,
+		VeleroHooks:                    clusterParams.VeleroHooks,
+		AutoscalerDisruption:           clusterParams.AutoscalerDisruption,
+		ExposeAdmin:                    clusterParams.ExposeAdmin,
+		RestrictedPodSecurity:          clusterParams.RestrictedPodSecurity,
 	}
 	if markLogicGroupParameters.IsDynamic {
 		markLogicGroupParameters.UpdateStrategy = appsv1.RollingUpdateStatefulSetStrategyType
@@ -349,6 +466,8 @@ func generateMarkLogicGroupParams(cr *marklogicv1.MarklogicCluster, index int, c
 		}
 	}
 
+	applyGroupProfile(markLogicGroupParameters, cr.Spec.MarkLogicGroups[index])
+
 	if cr.Spec.MarkLogicGroups[index].AdditionalVolumeClaimTemplates != nil {
 		markLogicGroupParameters.AdditionalVolumeClaimTemplates = cr.Spec.MarkLogicGroups[index].AdditionalVolumeClaimTemplates
 	}
@@ -382,6 +501,12 @@ func generateMarkLogicGroupParams(cr *marklogicv1.MarklogicCluster, index int, c
 	if cr.Spec.MarkLogicGroups[index].NodeSelector != nil {
 		markLogicGroupParameters.NodeSelector = cr.Spec.MarkLogicGroups[index].NodeSelector
 	}
+	if cr.Spec.MarkLogicGroups[index].Architecture != "" {
+		markLogicGroupParameters.Architecture = cr.Spec.MarkLogicGroups[index].Architecture
+	}
+	if cr.Spec.MarkLogicGroups[index].PodManagementPolicy != "" {
+		markLogicGroupParameters.PodManagementPolicy = cr.Spec.MarkLogicGroups[index].PodManagementPolicy
+	}
 	if cr.Spec.MarkLogicGroups[index].TopologySpreadConstraints != nil {
 		markLogicGroupParameters.TopologySpreadConstraints = cr.Spec.MarkLogicGroups[index].TopologySpreadConstraints
 	}
@@ -391,12 +516,27 @@ func generateMarkLogicGroupParams(cr *marklogicv1.MarklogicCluster, index int, c
 	if cr.Spec.MarkLogicGroups[index].HugePages != nil {
 		markLogicGroupParameters.HugePages = cr.Spec.MarkLogicGroups[index].HugePages
 	}
+	if cr.Spec.MarkLogicGroups[index].ReadOnlyRootFilesystem != nil {
+		markLogicGroupParameters.ReadOnlyRootFilesystem = cr.Spec.MarkLogicGroups[index].ReadOnlyRootFilesystem
+	}
 	if cr.Spec.MarkLogicGroups[index].LogCollection != nil {
 		markLogicGroupParameters.LogCollection = cr.Spec.MarkLogicGroups[index].LogCollection
 	}
 	if cr.Spec.MarkLogicGroups[index].Tls != nil {
 		markLogicGroupParameters.Tls = cr.Spec.MarkLogicGroups[index].Tls
 	}
+	if cr.Spec.MarkLogicGroups[index].Drain != nil {
+		markLogicGroupParameters.Drain = cr.Spec.MarkLogicGroups[index].Drain
+	}
+	if cr.Spec.MarkLogicGroups[index].AutoscalerDisruption != "" {
+		markLogicGroupParameters.AutoscalerDisruption = cr.Spec.MarkLogicGroups[index].AutoscalerDisruption
+	}
+	if cr.Spec.MarkLogicGroups[index].HostJoin != nil {
+		markLogicGroupParameters.HostJoin = cr.Spec.MarkLogicGroups[index].HostJoin
+	}
+	if cr.Spec.MarkLogicGroups[index].StartupProbe != nil {
+		markLogicGroupParameters.StartupProbe = cr.Spec.MarkLogicGroups[index].StartupProbe
+	}
 	if cr.Spec.MarkLogicGroups[index].AdditionalVolumes != nil {
 		markLogicGroupParameters.AdditionalVolumes = cr.Spec.MarkLogicGroups[index].AdditionalVolumes
 	}
@@ -409,5 +549,6 @@ func generateMarkLogicGroupParams(cr *marklogicv1.MarklogicCluster, index int, c
 	if cr.Spec.MarkLogicGroups[index].ReadinessProbe.Enabled {
 		markLogicGroupParameters.ReadinessProbe = cr.Spec.MarkLogicGroups[index].ReadinessProbe
 	}
+	applyDisruptibleNodes(markLogicGroupParameters, cr.Spec.MarkLogicGroups[index])
 	return markLogicGroupParameters
 }
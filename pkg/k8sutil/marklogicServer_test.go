@@ -3,8 +3,11 @@ package k8sutil
 import (
 	"strings"
 	"testing"
+	"time"
 
 	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -46,3 +49,314 @@ func TestImmutableMarklogicGroupSpecMismatch(t *testing.T) {
 		}
 	})
 }
+
+func TestVeleroHooksPropagateToPodTemplateAnnotations(t *testing.T) {
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			Backup: &marklogicv1.Backup{
+				Velero: &marklogicv1.VeleroHooks{Enabled: true, TimeoutSeconds: 120, OnError: "Continue"},
+			},
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{{Name: "node", IsBootstrap: true}},
+		},
+	}
+	params := generateMarkLogicGroupParams(cr, 0, generateMarkLogicClusterParams(cr))
+	if params.VeleroHooks == nil || params.VeleroHooks.TimeoutSeconds != 120 {
+		t.Fatalf("expected velero hooks to propagate to group params, got %+v", params.VeleroHooks)
+	}
+
+	groupAnnotations := map[string]string{"team": "data"}
+	annotations := podTemplateAnnotations(groupAnnotations, params.VeleroHooks)
+	if annotations["team"] != "data" {
+		t.Fatalf("expected group annotations to be kept, got %v", annotations)
+	}
+	if !strings.Contains(annotations["pre.hook.backup.velero.io/command"], "freeze") || annotations["pre.hook.backup.velero.io/timeout"] != "120s" {
+		t.Fatalf("unexpected pre hook annotations %v", annotations)
+	}
+	if annotations["pre.hook.backup.velero.io/on-error"] != "Continue" || !strings.Contains(annotations["post.hook.backup.velero.io/command"], "thaw") {
+		t.Fatalf("unexpected hook annotations %v", annotations)
+	}
+	if len(groupAnnotations) != 1 {
+		t.Fatalf("expected group annotations to stay unmodified, got %v", groupAnnotations)
+	}
+
+	cr.Spec.Backup.Velero.Enabled = false
+	params = generateMarkLogicGroupParams(cr, 0, generateMarkLogicClusterParams(cr))
+	if params.VeleroHooks != nil {
+		t.Fatalf("expected disabled velero hooks not to propagate, got %+v", params.VeleroHooks)
+	}
+}
+
+func TestAutoscalerDisruptionAnnotatesGroupPods(t *testing.T) {
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			AutoscalerDisruption: marklogicv1.AutoscalerDisruptionNever,
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{
+				{Name: "enode"},
+				{Name: "dnode", IsBootstrap: true, AutoscalerDisruption: marklogicv1.AutoscalerDisruptionCoordinated},
+			},
+		},
+	}
+	clusterParams := generateMarkLogicClusterParams(cr)
+	enode := generateMarkLogicGroupParams(cr, 0, clusterParams)
+	dnode := generateMarkLogicGroupParams(cr, 1, clusterParams)
+	if enode.AutoscalerDisruption != marklogicv1.AutoscalerDisruptionNever || dnode.AutoscalerDisruption != marklogicv1.AutoscalerDisruptionCoordinated {
+		t.Fatalf("expected the group to override the cluster, got %q and %q", enode.AutoscalerDisruption, dnode.AutoscalerDisruption)
+	}
+
+	annotations := autoscalerAnnotations(map[string]string{"team": "data"}, enode.AutoscalerDisruption)
+	if annotations[karpenterDoNotDisruptAnnotation] != "true" || annotations[clusterAutoscalerSafeToEvict] != "false" || annotations["team"] != "data" {
+		t.Fatalf("unexpected annotations %v", annotations)
+	}
+	// An annotation the group sets itself wins.
+	annotations = autoscalerAnnotations(map[string]string{clusterAutoscalerSafeToEvict: "false"}, dnode.AutoscalerDisruption)
+	if _, ok := annotations[karpenterDoNotDisruptAnnotation]; ok || annotations[clusterAutoscalerSafeToEvict] != "false" {
+		t.Fatalf("unexpected annotations %v", annotations)
+	}
+	if annotations := autoscalerAnnotations(nil, ""); annotations != nil {
+		t.Fatalf("expected no annotations without an autoscaler disruption, got %v", annotations)
+	}
+}
+
+func TestDrainSettingsReachTheMarkLogicContainer(t *testing.T) {
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			Drain: &marklogicv1.ShutdownDrain{Enabled: true, Timeout: &metav1.Duration{Duration: 2 * time.Minute}},
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{
+				{Name: "dnode", IsBootstrap: true},
+				{Name: "enode", Drain: &marklogicv1.ShutdownDrain{Enabled: false}},
+			},
+		},
+	}
+	clusterParams := generateMarkLogicClusterParams(cr)
+	env := map[string]string{}
+	params := generateMarkLogicGroupParams(cr, 0, clusterParams)
+	for _, envVar := range getEnvironmentVariables(containerParameters{Drain: params.Drain}) {
+		env[envVar.Name] = envVar.Value
+	}
+	if env["MARKLOGIC_DRAIN_TIMEOUT_SECONDS"] != "120" || env["MARKLOGIC_DRAIN_DELAY_SECONDS"] != "5" {
+		t.Fatalf("expected the drain settings of the cluster, got %v", env)
+	}
+
+	params = generateMarkLogicGroupParams(cr, 1, clusterParams)
+	for _, envVar := range getEnvironmentVariables(containerParameters{Drain: params.Drain}) {
+		if strings.HasPrefix(envVar.Name, "MARKLOGIC_DRAIN_") {
+			t.Fatalf("expected the group to turn the drain off, got %s", envVar.Name)
+		}
+	}
+}
+
+func TestHostJoinSettingsReachTheMarkLogicContainer(t *testing.T) {
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			HostJoin: &marklogicv1.HostJoin{MaxConcurrent: 4, Backoff: &metav1.Duration{Duration: 10 * time.Second}},
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{
+				{Name: "dnode", IsBootstrap: true},
+				{Name: "enode", HostJoin: &marklogicv1.HostJoin{Retries: 3}},
+			},
+		},
+	}
+	clusterParams := generateMarkLogicClusterParams(cr)
+	env := map[string]string{}
+	params := generateMarkLogicGroupParams(cr, 0, clusterParams)
+	for _, envVar := range getEnvironmentVariables(containerParameters{HostJoin: params.HostJoin}) {
+		env[envVar.Name] = envVar.Value
+	}
+	if env["MARKLOGIC_JOIN_MAX_CONCURRENT"] != "4" || env["MARKLOGIC_JOIN_RETRIES"] != "10" ||
+		env["MARKLOGIC_JOIN_BACKOFF_SECONDS"] != "10" || env["MARKLOGIC_JOIN_MAX_BACKOFF_SECONDS"] != "60" {
+		t.Fatalf("expected the host join settings of the cluster, got %v", env)
+	}
+
+	env = map[string]string{}
+	params = generateMarkLogicGroupParams(cr, 1, clusterParams)
+	for _, envVar := range getEnvironmentVariables(containerParameters{HostJoin: params.HostJoin}) {
+		env[envVar.Name] = envVar.Value
+	}
+	if env["MARKLOGIC_JOIN_MAX_CONCURRENT"] != "0" || env["MARKLOGIC_JOIN_RETRIES"] != "3" || env["MARKLOGIC_JOIN_BACKOFF_SECONDS"] != "5" {
+		t.Fatalf("expected the host join settings of the group, got %v", env)
+	}
+}
+
+func TestStartupProbeCoversTheBootTimeout(t *testing.T) {
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			StartupProbe: &marklogicv1.StartupProbe{Enabled: true},
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{
+				{Name: "dnode", IsBootstrap: true, StartupProbe: &marklogicv1.StartupProbe{
+					Enabled: true, WaitForForests: true, PeriodSeconds: 30, BootTimeout: &metav1.Duration{Duration: 2 * time.Hour},
+				}},
+				{Name: "enode"},
+			},
+		},
+	}
+	clusterParams := generateMarkLogicClusterParams(cr)
+
+	params := generateMarkLogicGroupParams(cr, 0, clusterParams)
+	container := generateContainerDef("marklogic-server", containerParameters{StartupProbe: params.StartupProbe})[0]
+	if probe := container.StartupProbe; probe == nil || probe.PeriodSeconds != 30 || probe.FailureThreshold != 240 {
+		t.Fatalf("expected the startup probe to cover the boot timeout of the group, got %+v", probe)
+	}
+	env := map[string]string{}
+	for _, envVar := range container.Env {
+		env[envVar.Name] = envVar.Value
+	}
+	if env["MARKLOGIC_BOOT_TIMEOUT_SECONDS"] != "7200" || env["MARKLOGIC_STARTUP_WAIT_FOR_FORESTS"] != "true" {
+		t.Fatalf("expected the boot settings of the group, got %v", env)
+	}
+
+	params = generateMarkLogicGroupParams(cr, 1, clusterParams)
+	container = generateContainerDef("marklogic-server", containerParameters{StartupProbe: params.StartupProbe})[0]
+	if probe := container.StartupProbe; probe == nil || probe.PeriodSeconds != 10 || probe.FailureThreshold != 180 {
+		t.Fatalf("expected the default boot timeout of 30m, got %+v", probe)
+	}
+
+	cr.Spec.StartupProbe = nil
+	params = generateMarkLogicGroupParams(cr, 1, generateMarkLogicClusterParams(cr))
+	if container := generateContainerDef("marklogic-server", containerParameters{StartupProbe: params.StartupProbe})[0]; container.StartupProbe != nil {
+		t.Fatalf("expected no startup probe by default, got %+v", container.StartupProbe)
+	}
+}
+
+func TestAdminPortsStayOffExternalServicesByDefault(t *testing.T) {
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			NetworkAccess:   &marklogicv1.NetworkAccess{},
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{{Name: "node", IsBootstrap: true, Service: marklogicv1.Service{Type: corev1.ServiceTypeLoadBalancer}}},
+		},
+	}
+	params := generateMarkLogicGroupParams(cr, 0, generateMarkLogicClusterParams(cr))
+	group := &marklogicv1.MarklogicGroup{Spec: marklogicv1.MarklogicGroupSpec{Name: "node", Service: params.Service, ExposeAdmin: params.ExposeAdmin}}
+	svcParams := generateServiceParams(group)
+
+	hasAdminPort := func(svc *corev1.Service) bool {
+		for _, port := range svc.Spec.Ports {
+			if port.Port >= 8000 && port.Port <= 8002 {
+				return true
+			}
+		}
+		return false
+	}
+	external := generateServiceDef(metav1.ObjectMeta{Name: "node-cluster"}, metav1.OwnerReference{}, svcParams)
+	if hasAdminPort(external) {
+		t.Fatalf("expected admin ports to be dropped from the LoadBalancer service, got %v", external.Spec.Ports)
+	}
+	headless := generateServiceDef(metav1.ObjectMeta{Name: "node"}, metav1.OwnerReference{}, svcParams)
+	if !hasAdminPort(headless) {
+		t.Fatal("expected admin ports to stay on the headless service")
+	}
+
+	cr.Spec.NetworkAccess.ExposeAdmin = true
+	params = generateMarkLogicGroupParams(cr, 0, generateMarkLogicClusterParams(cr))
+	svcParams.ExposeAdmin = params.ExposeAdmin
+	external = generateServiceDef(metav1.ObjectMeta{Name: "node-cluster"}, metav1.OwnerReference{}, svcParams)
+	if !hasAdminPort(external) {
+		t.Fatal("expected admin ports on the LoadBalancer service when exposeAdmin is true")
+	}
+}
+
+func TestGenerateAdminIngressDefRequiresTLS(t *testing.T) {
+	adminIngress := &marklogicv1.AdminIngress{AdminHost: "admin.example.com", ManageHost: "manage.example.com", TLSSecretName: "admin-tls"}
+	ingress := generateAdminIngressDef(metav1.ObjectMeta{Name: "ml-admin"}, metav1.OwnerReference{}, "node-cluster", adminIngress)
+	if len(ingress.Spec.Rules) != 2 {
+		t.Fatalf("expected one rule per configured host, got %d", len(ingress.Spec.Rules))
+	}
+	backend := ingress.Spec.Rules[1].HTTP.Paths[0].Backend.Service
+	if backend.Name != "node-cluster" || backend.Port.Number != 8002 {
+		t.Fatalf("expected manage host to route to node-cluster:8002, got %+v", backend)
+	}
+	if len(ingress.Spec.TLS) != 1 || ingress.Spec.TLS[0].SecretName != "admin-tls" || len(ingress.Spec.TLS[0].Hosts) != 2 {
+		t.Fatalf("expected TLS for every host, got %+v", ingress.Spec.TLS)
+	}
+	if ingress.Spec.IngressClassName != nil {
+		t.Fatalf("expected default ingress class, got %v", *ingress.Spec.IngressClassName)
+	}
+}
+
+func TestGroupProfileDefaults(t *testing.T) {
+	clusterMemory := corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")}}
+	groupResources := corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("16Gi")}}
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			Resources: &clusterMemory,
+			HugePages: &marklogicv1.HugePages{MountPath: "/dev/hugepages"},
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{
+				{Name: "dnode", IsBootstrap: true, Profile: marklogicv1.GroupProfileDNode, HugePages: &marklogicv1.HugePages{Enabled: true, MountPath: "/dev/hugepages"}},
+				{Name: "enode", Profile: marklogicv1.GroupProfileENode, Affinity: &corev1.Affinity{}},
+				{Name: "custom", Profile: marklogicv1.GroupProfileDNode, Resources: &groupResources},
+				{Name: "plain"},
+			},
+		},
+	}
+	clusterParams := generateMarkLogicClusterParams(cr)
+
+	dnode := generateMarkLogicGroupParams(cr, 0, clusterParams)
+	if !dnode.HugePages.Enabled || dnode.Resources.Limits.Name("hugepages-2Mi", resource.BinarySI).String() != "3Gi" {
+		t.Fatalf("expected huge pages on data hosts, got %+v %+v", dnode.HugePages, dnode.Resources)
+	}
+	if dnode.Affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution[0].Preference.MatchExpressions[0].Values[0] != "dnode" {
+		t.Fatalf("expected the dnode node pool to be preferred, got %+v", dnode.Affinity)
+	}
+
+	enode := generateMarkLogicGroupParams(cr, 1, clusterParams)
+	if enode.HugePages.Enabled || enode.Resources.Requests.Cpu().String() != "4" {
+		t.Fatalf("expected evaluator defaults, got %+v %+v", enode.HugePages, enode.Resources)
+	}
+	if _, ok := enode.Resources.Limits["hugepages-2Mi"]; ok {
+		t.Fatalf("expected no huge pages unless the group enables them, got %+v", enode.Resources)
+	}
+	if enode.Affinity.NodeAffinity != nil {
+		t.Fatalf("expected the group affinity to win over the profile, got %+v", enode.Affinity)
+	}
+
+	custom := generateMarkLogicGroupParams(cr, 2, clusterParams)
+	if custom.HugePages.Enabled || custom.Resources.Requests.Memory().String() != "16Gi" {
+		t.Fatalf("expected group resources without profile huge pages, got %+v %+v", custom.HugePages, custom.Resources)
+	}
+
+	plain := generateMarkLogicGroupParams(cr, 3, clusterParams)
+	if plain.Resources != &clusterMemory || plain.Affinity != nil {
+		t.Fatalf("expected the cluster values without a profile, got %+v", plain)
+	}
+}
+
+func TestDisruptibleGroupsShutDownWithinThePreemptionNotice(t *testing.T) {
+	grace := int64(120)
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			TerminationGracePeriodSeconds: &grace,
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{
+				{Name: "dnode", IsBootstrap: true},
+				{Name: "enode", AllowDisruptibleNodes: true},
+			},
+		},
+	}
+	clusterParams := generateMarkLogicClusterParams(cr)
+	dnode := generateMarkLogicGroupParams(cr, 0, clusterParams)
+	if dnode.AllowDisruptibleNodes || *dnode.TerminationGracePeriodSeconds != 120 || dnode.Drain != nil {
+		t.Fatalf("expected the dnode group to keep the cluster settings, got %+v", dnode)
+	}
+	enode := generateMarkLogicGroupParams(cr, 1, clusterParams)
+	if !enode.AllowDisruptibleNodes || *enode.TerminationGracePeriodSeconds != disruptibleTerminationGracePeriod {
+		t.Fatalf("expected the grace period to fit the preemption notice, got %+v", enode)
+	}
+	env := map[string]string{}
+	for _, envVar := range getEnvironmentVariables(containerParameters{Drain: enode.Drain}) {
+		env[envVar.Name] = envVar.Value
+	}
+	if env["MARKLOGIC_DRAIN_TIMEOUT_SECONDS"] != "15" || env["MARKLOGIC_DRAIN_DELAY_SECONDS"] != "2" {
+		t.Fatalf("expected a fast drain, got %v", env)
+	}
+	if tolerations := podTolerations(enode.AllowDisruptibleNodes); len(tolerations) != len(disruptibleNodeTolerations) {
+		t.Fatalf("expected the spot tolerations, got %v", tolerations)
+	}
+	if tolerations := podTolerations(dnode.AllowDisruptibleNodes); tolerations != nil {
+		t.Fatalf("expected no tolerations, got %v", tolerations)
+	}
+}
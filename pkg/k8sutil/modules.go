@@ -0,0 +1,337 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	defaultModulesBackoffLimit = 3
+	// modulesCheckInterval is how often modules that are pending or loading
+	// are checked, as the cluster does not watch Jobs.
+	modulesCheckInterval = 30 * time.Second
+	// modulesPath is where the Job that loads the modules finds them.
+	modulesPath = "/modules"
+
+	modulesReasonLoading = "ModulesLoading"
+	modulesReasonLoaded  = "ModulesLoaded"
+	modulesReasonFailed  = "ModulesFailed"
+)
+
+// ReconcileModules keeps the modules of spec.modules loaded. Once all groups
+// are ready it creates the modules database with a forest on the bootstrap
+// host when missing, points the app servers at it and runs a Job that loads
+// the modules of the ConfigMap or OCI artifact through the REST API. The
+// modules are loaded again whenever the content hash of the spec and the
+// ConfigMap changes; modules removed from the source are left in the
+// database. Progress is reported in status.modules and failures never hold
+// up the rest of the reconcile.
+func (cc *ClusterContext) ReconcileModules() result.ReconcileResult {
+	cr := cc.MarklogicCluster
+	if cr.Spec.Modules == nil || clusterStopped(cr) {
+		return result.Continue()
+	}
+	spec := cr.Spec.Modules
+	hash, err := cc.modulesContentHash(spec)
+	if err != nil {
+		cc.ReqLogger.Error(err, "Failed to read the modules")
+		status := &marklogicv1.ModulesStatus{Phase: marklogicv1.ModulesPending, Message: err.Error()}
+		if current := cr.Status.Modules; current != nil {
+			status.LoadedAt = current.LoadedAt
+		}
+		cc.updateModulesStatus(status)
+		return result.Continue()
+	}
+	var status *marklogicv1.ModulesStatus
+	if cr.Status.Modules != nil && cr.Status.Modules.ContentHash == hash {
+		status = cr.Status.Modules.DeepCopy()
+	} else {
+		status = &marklogicv1.ModulesStatus{Phase: marklogicv1.ModulesPending, ContentHash: hash}
+		if cr.Status.Modules != nil {
+			status.LoadedAt = cr.Status.Modules.LoadedAt
+		}
+	}
+
+	switch status.Phase {
+	case marklogicv1.ModulesPending:
+		waiting, err := cc.groupsNotReady(false)
+		if err != nil {
+			cc.ReqLogger.Error(err, "Failed to check the groups for the modules")
+			return result.Continue()
+		}
+		if waiting != "" {
+			status.Message = "waiting for the groups to be ready: " + waiting
+			break
+		}
+		if err := cc.ensureModulesDatabase(spec); err != nil {
+			cc.ReqLogger.Error(err, "Failed to set up the modules database", "database", spec.Database)
+			if status.Message != err.Error() {
+				cc.recordClusterEvent(corev1.EventTypeWarning, modulesReasonFailed,
+					fmt.Sprintf("Failed to set up modules database %s: %v", spec.Database, err))
+			}
+			status.Message = err.Error()
+			break
+		}
+		job, err := cc.modulesJob(spec, hash)
+		if err != nil {
+			cc.ReqLogger.Error(err, "Failed to build the modules Job")
+			status.Message = err.Error()
+			break
+		}
+		if err := cc.Client.Create(cc.Ctx, job); err != nil && !apierrors.IsAlreadyExists(err) {
+			cc.ReqLogger.Error(err, "Failed to create the modules Job")
+			status.Message = err.Error()
+			break
+		}
+		status.Phase, status.JobName, status.Message = marklogicv1.ModulesLoading, job.Name, ""
+		cc.recordClusterEvent(corev1.EventTypeNormal, modulesReasonLoading,
+			fmt.Sprintf("Started Job %s loading the modules into database %s", job.Name, spec.Database))
+	case marklogicv1.ModulesLoading:
+		job := &batchv1.Job{}
+		err := cc.Client.Get(cc.Ctx, types.NamespacedName{Namespace: cr.Namespace, Name: status.JobName}, job)
+		switch {
+		case apierrors.IsNotFound(err):
+			cc.finishModules(status, marklogicv1.ModulesFailed, fmt.Sprintf("Job %s was deleted before it completed", status.JobName))
+		case err != nil:
+			cc.ReqLogger.Error(err, "Failed to read the modules Job")
+			return result.Continue()
+		case job.Status.Succeeded > 0:
+			cc.finishModules(status, marklogicv1.ModulesLoaded, "")
+		case jobFailed(job):
+			cc.finishModules(status, marklogicv1.ModulesFailed, fmt.Sprintf("Job %s failed: %s", status.JobName, jobFailureMessage(job)))
+		}
+	}
+	cc.updateModulesStatus(status)
+	return result.Continue()
+}
+
+func (cc *ClusterContext) updateModulesStatus(status *marklogicv1.ModulesStatus) {
+	cr := cc.MarklogicCluster
+	if reflect.DeepEqual(cr.Status.Modules, status) {
+		return
+	}
+	patchBase := client.MergeFrom(cr.DeepCopy())
+	cr.Status.Modules = status
+	if err := patchStatus(cc.Ctx, cc.Client, cr, patchBase); err != nil {
+		cc.ReqLogger.Error(err, "Failed to update the modules status")
+	}
+}
+
+// finishModules records the end of a load in status and as an event.
+func (cc *ClusterContext) finishModules(status *marklogicv1.ModulesStatus, phase marklogicv1.ModulesPhase, message string) {
+	status.Phase, status.Message = phase, message
+	if phase == marklogicv1.ModulesLoaded {
+		now := metav1.Now()
+		status.LoadedAt = &now
+		cc.ReqLogger.Info("Loaded the modules", "job", status.JobName)
+		cc.recordClusterEvent(corev1.EventTypeNormal, modulesReasonLoaded, fmt.Sprintf("Job %s loaded the modules", status.JobName))
+		return
+	}
+	cc.ReqLogger.Info("Loading the modules failed", "job", status.JobName, "message", message)
+	cc.recordClusterEvent(corev1.EventTypeWarning, modulesReasonFailed, message)
+}
+
+// ensureModulesDatabase creates the modules database with a forest on the
+// bootstrap host unless it has forests, and sets it as the modules database
+// of the app servers.
+func (cc *ClusterContext) ensureModulesDatabase(spec *marklogicv1.Modules) error {
+	mgmtClient, err := cc.newBootstrapManagementClient()
+	if err != nil {
+		return err
+	}
+	created, err := mgmtClient.CreateDatabase(cc.Ctx, map[string]any{"database-name": spec.Database})
+	if err != nil {
+		return fmt.Errorf("failed to create database %s: %w", spec.Database, err)
+	}
+	if created {
+		cc.recordClusterEvent(corev1.EventTypeNormal, "DatabaseCreated", fmt.Sprintf("Created modules database %s", spec.Database))
+	}
+	forests, err := mgmtClient.ListDatabaseForests(cc.Ctx, spec.Database)
+	if err != nil {
+		return fmt.Errorf("failed to list the forests of database %s: %w", spec.Database, err)
+	}
+	if len(forests) == 0 {
+		host, err := cc.bootstrapHostFQDN()
+		if err != nil {
+			return err
+		}
+		forest := mlmanage.ForestSpec{Name: spec.Database + "-1", Host: host, Database: spec.Database}
+		if _, err := mgmtClient.CreateForest(cc.Ctx, forest); err != nil {
+			return fmt.Errorf("failed to create forest %s: %w", forest.Name, err)
+		}
+	}
+	for _, server := range spec.AppServers {
+		group := server.Group
+		if group == "" {
+			group = "Default"
+		}
+		if err := mgmtClient.SetAppServerProperties(cc.Ctx, group, server.Name, map[string]any{"modules-database": spec.Database}); err != nil {
+			return fmt.Errorf("failed to set the modules database of app server %s: %w", server.Name, err)
+		}
+	}
+	return nil
+}
+
+// modulesContentHash identifies the spec of the modules and the content of
+// their ConfigMap. The content of an OCI artifact is identified by its
+// reference.
+func (cc *ClusterContext) modulesContentHash(spec *marklogicv1.Modules) (string, error) {
+	content := struct {
+		Spec       *marklogicv1.Modules `json:"spec"`
+		Data       map[string]string    `json:"data,omitempty"`
+		BinaryData map[string][]byte    `json:"binaryData,omitempty"`
+	}{Spec: spec}
+	if spec.ConfigMap != "" {
+		configMap := &corev1.ConfigMap{}
+		err := cc.Client.Get(cc.Ctx, types.NamespacedName{Namespace: cc.MarklogicCluster.Namespace, Name: spec.ConfigMap}, configMap)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return "", fmt.Errorf("ConfigMap %s of the modules not found", spec.ConfigMap)
+			}
+			return "", err
+		}
+		content.Data, content.BinaryData = configMap.Data, configMap.BinaryData
+	}
+	data, _ := json.Marshal(content)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:10], nil
+}
+
+// modulesJob returns the Job that loads the modules into the modules
+// database through the REST API on port 8000 of the bootstrap host. An OCI
+// artifact is pulled by an init container first. The admin credentials are
+// read from environment variables by the shell, so they never appear in the
+// Job spec.
+func (cc *ClusterContext) modulesJob(spec *marklogicv1.Modules, hash string) (*batchv1.Job, error) {
+	cr := cc.MarklogicCluster
+	host, err := cc.bootstrapHostFQDN()
+	if err != nil {
+		return nil, err
+	}
+	scheme, insecure := "http", ""
+	if defaultAppServersTLS(cr) {
+		// The Job does not have the CA of the certificates of the app
+		// servers, as with the MLCP Jobs.
+		scheme, insecure = "https", " --insecure"
+	}
+	root := spec.Root
+	if root == "" {
+		root = "/"
+	}
+	script := fmt.Sprintf(`set -e
+cd %s
+for file in $(find -L . -type f ! -path './..*' | sed 's|^\./||'); do
+  echo "Loading %s$file"
+  curl --silent --show-error --fail --anyauth%s --user "$ML_USERNAME:$ML_PASSWORD" -X PUT -T "$file" \
+    "%s://%s:8000/v1/documents?database=%s&uri=%s$file"
+done`, modulesPath, root, insecure, scheme, host, spec.Database, root)
+
+	secretKey := func(key string) *corev1.EnvVarSource {
+		return &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: cc.adminSecretName()},
+			Key:                  key,
+		}}
+	}
+	image, pullPolicy := spec.Image, spec.ImagePullPolicy
+	if image == "" {
+		image = cr.Spec.Image
+		if pullPolicy == "" {
+			pullPolicy = corev1.PullPolicy(cr.Spec.ImagePullPolicy)
+		}
+	}
+	mount := corev1.VolumeMount{Name: "modules", MountPath: modulesPath}
+	podSpec := corev1.PodSpec{
+		RestartPolicy:                corev1.RestartPolicyNever,
+		ImagePullSecrets:             cr.Spec.ImagePullSecrets,
+		AutomountServiceAccountToken: boolPtr(false),
+		Containers: []corev1.Container{{
+			Name:            "load-modules",
+			Image:           image,
+			ImagePullPolicy: pullPolicy,
+			Command:         []string{"sh", "-c", script},
+			Env: []corev1.EnvVar{
+				{Name: "ML_USERNAME", ValueFrom: secretKey("username")},
+				{Name: "ML_PASSWORD", ValueFrom: secretKey("password")},
+			},
+			VolumeMounts: []corev1.VolumeMount{mount},
+		}},
+	}
+	if spec.ConfigMap != "" {
+		podSpec.Volumes = []corev1.Volume{{Name: "modules", VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: spec.ConfigMap}},
+		}}}
+	} else {
+		podSpec.Volumes = []corev1.Volume{{Name: "modules", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}}}
+		pull := corev1.Container{
+			Name:         "pull-modules",
+			Image:        spec.OCI.Image,
+			Command:      []string{"oras", "pull", spec.OCI.Reference, "--output", modulesPath},
+			VolumeMounts: []corev1.VolumeMount{mount},
+		}
+		if spec.OCI.PullSecretName != "" {
+			pull.Command = append(pull.Command, "--registry-config", "/registry/config.json")
+			pull.VolumeMounts = append(pull.VolumeMounts, corev1.VolumeMount{Name: "registry-auth", MountPath: "/registry", ReadOnly: true})
+			podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{Name: "registry-auth", VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: spec.OCI.PullSecretName,
+					Items:      []corev1.KeyToPath{{Key: corev1.DockerConfigJsonKey, Path: "config.json"}},
+				},
+			}})
+		}
+		podSpec.InitContainers = []corev1.Container{pull}
+	}
+	if cr.Spec.RestrictedPodSecurity {
+		restrictPodSpec(&podSpec)
+	}
+
+	backoffLimit := int32(defaultModulesBackoffLimit)
+	if spec.BackoffLimit != nil {
+		backoffLimit = *spec.BackoffLimit
+	}
+	labels := withClusterNameLabel(cc.GetClusterLabels(cr.Name), cr.Name)
+	labels["marklogic.progress.com/task"] = "modules"
+	// The pod does not get the selector labels of the cluster, so no Service
+	// or PodDisruptionBudget of a group selects it.
+	podLabels := map[string]string{ClusterNameLabel: cr.Name, "marklogic.progress.com/task": "modules"}
+	job := &batchv1.Job{
+		ObjectMeta: generateObjectMeta(clusterFullname(cr)+"-modules-"+hash, cr.Namespace, labels, cc.GetClusterAnnotations()),
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: podLabels},
+				Spec:       podSpec,
+			},
+		},
+	}
+	AddOwnerRefToObject(job, marklogicClusterAsOwner(cr))
+	return job, nil
+}
+
+// nextModulesCheck is when modules that are pending or loading are checked
+// again, or the zero time when they are loaded or failed. A changed ConfigMap
+// is picked up by its watch.
+func nextModulesCheck(cr *marklogicv1.MarklogicCluster) time.Time {
+	if cr.Spec.Modules == nil {
+		return time.Time{}
+	}
+	if status := cr.Status.Modules; status != nil && (status.Phase == marklogicv1.ModulesLoaded || status.Phase == marklogicv1.ModulesFailed) {
+		return time.Time{}
+	}
+	return time.Now().Add(modulesCheckInterval)
+}
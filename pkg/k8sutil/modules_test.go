@@ -0,0 +1,149 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"strings"
+	"testing"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestReconcileModulesLoadsChangedConfigMap(t *testing.T) {
+	replicas := int32(1)
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default", UID: "ml-uid"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			ClusterDomain:   "cluster.local",
+			Image:           "progressofficial/marklogic-db:11",
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{{Name: "dnode", Replicas: &replicas, IsBootstrap: true}},
+			Modules: &marklogicv1.Modules{
+				Database:   "orders-modules",
+				AppServers: []marklogicv1.ModulesAppServer{{Name: "orders-api"}},
+				ConfigMap:  "orders-modules",
+				Root:       "/orders/",
+			},
+		},
+	}
+	sts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "dnode", Namespace: "default"},
+		Spec:       appsv1.StatefulSetSpec{Replicas: &replicas},
+		Status:     appsv1.StatefulSetStatus{ReadyReplicas: 1},
+	}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "orders-modules", Namespace: "default"},
+		Data:       map[string]string{"rewriter.xml": "<rewriter/>"},
+	}
+	cc := newUpgradeTestContext(t, cr, sts, configMap)
+	forests := []mlmanage.ForestSpec{}
+	servers := map[string]map[string]any{}
+	original := NewDynamicManagementClient
+	NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+		return &stubDynamicManagementClient{
+			createDatabaseFn: func(properties map[string]any) (bool, error) { return true, nil },
+			listForestsFn:    func(database string) ([]string, error) { return nil, nil },
+			createForestFn: func(forest mlmanage.ForestSpec) (bool, error) {
+				forests = append(forests, forest)
+				return true, nil
+			},
+			setServerPropsFn: func(groupName, serverName string, properties map[string]any) error {
+				servers[groupName+"/"+serverName] = properties
+				return nil
+			},
+		}
+	}
+	t.Cleanup(func() { NewDynamicManagementClient = original })
+
+	cc.ReconcileModules()
+	status := cr.Status.Modules
+	if status.Phase != marklogicv1.ModulesLoading || status.ContentHash == "" {
+		t.Fatalf("expected the modules to load, got %+v", status)
+	}
+	if len(forests) != 1 || forests[0].Name != "orders-modules-1" || forests[0].Host != "dnode-0.dnode.default.svc.cluster.local" ||
+		servers["Default/orders-api"]["modules-database"] != "orders-modules" {
+		t.Fatalf("expected the modules database to be set up, got %+v %v", forests, servers)
+	}
+	job := &batchv1.Job{}
+	if err := cc.Client.Get(cc.Ctx, types.NamespacedName{Namespace: "default", Name: status.JobName}, job); err != nil {
+		t.Fatalf("expected the modules Job: %v", err)
+	}
+	podSpec := job.Spec.Template.Spec
+	script := podSpec.Containers[0].Command[2]
+	if podSpec.Containers[0].Image != "progressofficial/marklogic-db:11" || len(podSpec.InitContainers) != 0 ||
+		podSpec.Volumes[0].ConfigMap.Name != "orders-modules" ||
+		!strings.Contains(script, "http://dnode-0.dnode.default.svc.cluster.local:8000/v1/documents?database=orders-modules&uri=/orders/$file") {
+		t.Fatalf("unexpected Job %+v", podSpec)
+	}
+
+	job.Status.Succeeded = 1
+	if err := cc.Client.Status().Update(cc.Ctx, job); err != nil {
+		t.Fatalf("failed to update Job: %v", err)
+	}
+	cc.ReconcileModules()
+	if status := cr.Status.Modules; status.Phase != marklogicv1.ModulesLoaded || status.LoadedAt == nil {
+		t.Fatalf("expected the modules to be loaded, got %+v", status)
+	}
+	if next := nextModulesCheck(cr); !next.IsZero() {
+		t.Fatalf("expected no further check, got %v", next)
+	}
+
+	// Loaded modules are not loaded again until the ConfigMap changes.
+	loaded := cr.Status.Modules.ContentHash
+	cc.ReconcileModules()
+	if cr.Status.Modules.Phase != marklogicv1.ModulesLoaded {
+		t.Fatalf("expected the modules not to be loaded again, got %+v", cr.Status.Modules)
+	}
+	configMap.Data["rewriter.xml"] = "<rewriter><match-path/></rewriter>"
+	if err := cc.Client.Update(cc.Ctx, configMap); err != nil {
+		t.Fatalf("failed to update ConfigMap: %v", err)
+	}
+	cc.ReconcileModules()
+	if status := cr.Status.Modules; status.Phase != marklogicv1.ModulesLoading || status.ContentHash == loaded ||
+		status.JobName == job.Name || status.LoadedAt == nil {
+		t.Fatalf("expected the changed modules to load, got %+v", status)
+	}
+}
+
+func TestModulesJobPullsTheOCIArtifact(t *testing.T) {
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			ClusterDomain:   "cluster.local",
+			Tls:             &marklogicv1.Tls{EnableOnDefaultAppServers: true},
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{{Name: "dnode", IsBootstrap: true}},
+		},
+	}
+	spec := &marklogicv1.Modules{
+		Database: "orders-modules",
+		OCI: &marklogicv1.ModulesOCIArtifact{
+			Reference:      "registry.example.com/orders/modules:1.4.0",
+			Image:          "ghcr.io/oras-project/oras:v1.2.0",
+			PullSecretName: "registry",
+		},
+		Image: "example.com/curl:8",
+	}
+	cc := newUpgradeTestContext(t, cr)
+	job, err := cc.modulesJob(spec, "abc")
+	if err != nil {
+		t.Fatalf("failed to generate the Job: %v", err)
+	}
+	podSpec := job.Spec.Template.Spec
+	pull := strings.Join(podSpec.InitContainers[0].Command, " ")
+	if pull != "oras pull registry.example.com/orders/modules:1.4.0 --output /modules --registry-config /registry/config.json" {
+		t.Fatalf("unexpected pull command %q", pull)
+	}
+	if len(podSpec.Volumes) != 2 || podSpec.Volumes[0].EmptyDir == nil || podSpec.Volumes[1].Secret.SecretName != "registry" {
+		t.Fatalf("unexpected volumes %+v", podSpec.Volumes)
+	}
+	script := podSpec.Containers[0].Command[2]
+	if podSpec.Containers[0].Image != "example.com/curl:8" || !strings.Contains(script, "--insecure") ||
+		!strings.Contains(script, "https://dnode-0.dnode.default.svc.cluster.local:8000/v1/documents?database=orders-modules&uri=/$file") {
+		t.Fatalf("unexpected script %q", script)
+	}
+}
@@ -58,7 +58,7 @@ func (cc *ClusterContext) ReconcileNetworkPolicy() result.ReconcileResult {
 	logger.Info("NetworkPolicy::Reconciling MarkLogic NetworkPolicy")
 	client := cc.Client
 	cr := cc.MarklogicCluster
-	networkPolicyName := cr.ObjectMeta.Name
+	networkPolicyName := clusterFullname(cr)
 	currentNetworkPolicy, err := cc.getNetworkPolicy(cr.Namespace, networkPolicyName)
 	networkPolicyDef := cc.generateNetworkPolicy(networkPolicyName, cr)
 	if err != nil {
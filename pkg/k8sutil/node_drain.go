@@ -0,0 +1,389 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	defaultFailoverTimeout = 10 * time.Minute
+	// nodeDrainCheckInterval is how often the forests of pods that fail over
+	// are checked, and nodeDrainWatchInterval how often the pods released
+	// for eviction are, until they left their node.
+	nodeDrainCheckInterval = 10 * time.Second
+	nodeDrainWatchInterval = 30 * time.Second
+
+	nodeDrainReasonFailover    = "NodeDrainFailover"
+	nodeDrainReasonReady       = "NodeDrainReady"
+	nodeDrainReasonTimedOut    = "NodeDrainTimedOut"
+	nodeDrainReasonUnprotected = "NodeDrainUnprotectedForests"
+	nodeDrainReasonPDBConflict = "PodDisruptionBudgetConflict"
+
+	// forestStateOpen is the state of a forest that is the acting master.
+	forestStateOpen = "open"
+)
+
+// autoscalerDisruptionTaints are the taints Karpenter and the cluster
+// autoscaler put on a node before they drain it to remove it.
+var autoscalerDisruptionTaints = []string{
+	"karpenter.sh/disrupted",
+	"karpenter.sh/disruption",
+	"ToBeDeletedByClusterAutoscaler",
+}
+
+// nodeDrainChecks holds when the pods on drained nodes of each cluster are
+// checked again, see nextNodeDrainCheck.
+var nodeDrainChecks sync.Map
+
+// ReconcileNodeDrain coordinates the drain of the nodes the pods of the
+// cluster run on with the failover of MarkLogic. With spec.nodeDrain or an
+// autoscaler disruption of Coordinated, every group has a PodDisruptionBudget
+// that lets evictions take down one of its pods at a time. Once the node of a
+// pod is cordoned or tainted by an autoscaler, the forests the pod holds as
+// acting master are restarted, so their replicas take over, and the budget of
+// the group allows no eviction until they did or the failover timeout passed. Forests without a replica cannot fail over; they are
+// reported and do not hold the drain. Groups with allowDisruptibleNodes hold
+// no forests; they always have a budget, which lets half of their pods be
+// evicted at once. Failures are logged and never hold up the rest of the
+// reconcile.
+func (cc *ClusterContext) ReconcileNodeDrain() result.ReconcileResult {
+	cr := cc.MarklogicCluster
+	key := types.NamespacedName{Namespace: cr.Namespace, Name: cr.Name}
+	spec := cr.Spec.NodeDrain
+	if !NodeDrainEnabled(cr) {
+		nodeDrainChecks.Delete(key)
+		holds := map[string]bool{}
+		for _, group := range cr.Spec.MarkLogicGroups {
+			if group != nil && group.AllowDisruptibleNodes {
+				holds[group.Name] = false
+			}
+		}
+		cc.reconcileDrainBudgets(holds)
+		if cr.Status.NodeDrain != nil {
+			cc.setNodeDrainStatus(nil)
+		}
+		return result.Continue()
+	}
+	timeout := defaultFailoverTimeout
+	if spec != nil && spec.FailoverTimeout != nil && spec.FailoverTimeout.Duration > 0 {
+		timeout = spec.FailoverTimeout.Duration
+	}
+
+	tracked := map[string]marklogicv1.DrainingPodStatus{}
+	if cr.Status.NodeDrain != nil {
+		for _, pod := range cr.Status.NodeDrain.Pods {
+			tracked[pod.Pod] = pod
+		}
+	}
+	now := metav1.Now()
+	drains := []marklogicv1.DrainingPodStatus{}
+	holds := map[string]bool{}
+	forests := cc.drainForests()
+	nodes := map[string]bool{}
+	for _, group := range cr.Spec.MarkLogicGroups {
+		if group == nil {
+			continue
+		}
+		holds[group.Name] = false
+		if group.AllowDisruptibleNodes {
+			continue
+		}
+		pods := &corev1.PodList{}
+		if err := cc.Client.List(cc.Ctx, pods, client.InNamespace(cr.Namespace), client.MatchingLabels{
+			"app.kubernetes.io/name":     "marklogic",
+			"app.kubernetes.io/instance": group.Name,
+		}); err != nil {
+			cc.ReqLogger.Error(err, "Failed to list the pods of the group for the node drain", "group", group.Name)
+			// The pods of the group stay as they were.
+			for _, drain := range tracked {
+				if drain.Group == group.Name {
+					holds[group.Name] = holds[group.Name] || drain.State == marklogicv1.NodeDrainFailingOver
+					drains = append(drains, drain)
+				}
+			}
+			continue
+		}
+		for _, pod := range pods.Items {
+			if pod.DeletionTimestamp != nil || pod.Spec.NodeName == "" || !cc.nodeDraining(pod.Spec.NodeName, nodes) {
+				continue
+			}
+			drain, ok := tracked[pod.Name]
+			if !ok || drain.Node != pod.Spec.NodeName {
+				drain = marklogicv1.DrainingPodStatus{
+					Pod: pod.Name, Group: group.Name, Node: pod.Spec.NodeName,
+					State: marklogicv1.NodeDrainFailingOver, StartTime: &now,
+				}
+				cc.ReqLogger.Info("The node of a pod is drained, failing over its forests", "pod", pod.Name, "node", pod.Spec.NodeName)
+			}
+			if drain.State == marklogicv1.NodeDrainFailingOver {
+				cc.failOverPodForests(&drain, cc.podHostFQDN(pod), forests, timeout, now)
+			}
+			holds[group.Name] = holds[group.Name] || drain.State == marklogicv1.NodeDrainFailingOver
+			drains = append(drains, drain)
+		}
+	}
+	cc.reconcileDrainBudgets(holds)
+
+	sort.Slice(drains, func(i, j int) bool { return drains[i].Pod < drains[j].Pod })
+	var status *marklogicv1.NodeDrainStatus
+	if len(drains) > 0 {
+		status = &marklogicv1.NodeDrainStatus{Pods: drains}
+		interval := nodeDrainWatchInterval
+		if slices.ContainsFunc(drains, func(drain marklogicv1.DrainingPodStatus) bool { return drain.State == marklogicv1.NodeDrainFailingOver }) {
+			interval = nodeDrainCheckInterval
+		}
+		nodeDrainChecks.Store(key, now.Add(interval))
+	} else {
+		nodeDrainChecks.Delete(key)
+	}
+	if !reflect.DeepEqual(cr.Status.NodeDrain, status) {
+		cc.setNodeDrainStatus(status)
+	}
+	return result.Continue()
+}
+
+// drainForests returns a function that lists the forests of the cluster the
+// first time it is called, so clusters without drained nodes do not call
+// the Manage API.
+func (cc *ClusterContext) drainForests() func() (mlmanage.Client, []mlmanage.ForestStatus, error) {
+	var mgmt mlmanage.Client
+	var forests []mlmanage.ForestStatus
+	var err error
+	listed := false
+	return func() (mlmanage.Client, []mlmanage.ForestStatus, error) {
+		if !listed {
+			listed = true
+			if mgmt, err = cc.newBootstrapManagementClient(); err == nil {
+				forests, err = mgmt.ListForestsStatus(cc.Ctx)
+			}
+		}
+		return mgmt, forests, err
+	}
+}
+
+// failOverPodForests restarts the forests the pod of drain holds as acting
+// master and have replicas, so the replicas take over, and moves drain to
+// Ready once none is left or to TimedOut once the failover timeout passed.
+func (cc *ClusterContext) failOverPodForests(drain *marklogicv1.DrainingPodStatus, host string, forests func() (mlmanage.Client, []mlmanage.ForestStatus, error), timeout time.Duration, now metav1.Time) {
+	mgmt, statuses, err := forests()
+	if err != nil {
+		cc.ReqLogger.Error(err, "Failed to read the forests for the node drain", "pod", drain.Pod)
+	} else {
+		pending, unprotected := []string{}, []string{}
+		// failed is set when a forest could not be checked or restarted, it
+		// is retried on the next check.
+		failed := false
+		for _, forest := range statuses {
+			if forest.Host != host || forest.State != forestStateOpen {
+				continue
+			}
+			replicas, err := mgmt.ListForestReplicas(cc.Ctx, forest.Name)
+			if err != nil {
+				cc.ReqLogger.Error(err, "Failed to read the replicas of the forest", "forest", forest.Name)
+				failed = true
+				continue
+			}
+			if len(replicas) == 0 {
+				unprotected = append(unprotected, forest.Name)
+				continue
+			}
+			// A forest is restarted once; one that is still open afterwards
+			// has no replica in sync to fail over to.
+			if slices.Contains(drain.Forests, forest.Name) {
+				pending = append(pending, forest.Name)
+				continue
+			}
+			if err := mgmt.RestartForest(cc.Ctx, forest.Name); err != nil {
+				cc.ReqLogger.Error(err, "Failed to restart the forest to fail it over", "forest", forest.Name)
+				failed = true
+				continue
+			}
+			pending = append(pending, forest.Name)
+			cc.recordClusterEvent(corev1.EventTypeNormal, nodeDrainReasonFailover,
+				fmt.Sprintf("restarted forest %s of pod %s so its replica takes over, node %s is drained", forest.Name, drain.Pod, drain.Node))
+		}
+		sort.Strings(pending)
+		sort.Strings(unprotected)
+		if len(unprotected) > 0 && !slices.Equal(unprotected, drain.UnprotectedForests) {
+			cc.recordClusterEvent(corev1.EventTypeWarning, nodeDrainReasonUnprotected,
+				fmt.Sprintf("forests %s of pod %s have no replica and are unavailable while the pod is evicted from node %s",
+					strings.Join(unprotected, ", "), drain.Pod, drain.Node))
+		}
+		drain.Forests, drain.UnprotectedForests = pending, unprotected
+		if len(drain.Forests) == 0 && !failed {
+			drain.Forests = nil
+			drain.State = marklogicv1.NodeDrainReady
+			cc.recordClusterEvent(corev1.EventTypeNormal, nodeDrainReasonReady,
+				fmt.Sprintf("the forests of pod %s failed over, it can be evicted from node %s", drain.Pod, drain.Node))
+			return
+		}
+		if len(drain.Forests) == 0 {
+			drain.Forests = nil
+		}
+		if len(drain.UnprotectedForests) == 0 {
+			drain.UnprotectedForests = nil
+		}
+	}
+	if drain.StartTime != nil && now.Sub(drain.StartTime.Time) >= timeout {
+		drain.State = marklogicv1.NodeDrainTimedOut
+		cc.recordClusterEvent(corev1.EventTypeWarning, nodeDrainReasonTimedOut,
+			fmt.Sprintf("the forests of pod %s did not fail over within %s, it can be evicted from node %s anyway: %s",
+				drain.Pod, timeout, drain.Node, strings.Join(drain.Forests, ", ")))
+	}
+}
+
+// NodeDrainEnabled reports whether the operator coordinates the drain of the
+// nodes of the cluster, with spec.nodeDrain or because the cluster or one of
+// its groups lets autoscalers disrupt the pods once they failed over.
+func NodeDrainEnabled(cr *marklogicv1.MarklogicCluster) bool {
+	if cr.Spec.NodeDrain != nil && cr.Spec.NodeDrain.Enabled {
+		return true
+	}
+	for _, group := range cr.Spec.MarkLogicGroups {
+		if group == nil {
+			continue
+		}
+		disruption := cr.Spec.AutoscalerDisruption
+		if group.AutoscalerDisruption != "" {
+			disruption = group.AutoscalerDisruption
+		}
+		if disruption == marklogicv1.AutoscalerDisruptionCoordinated {
+			return true
+		}
+	}
+	return false
+}
+
+// NodeDraining reports whether a node is cordoned or tainted by an autoscaler
+// that is about to drain it.
+func NodeDraining(node *corev1.Node) bool {
+	if node.Spec.Unschedulable {
+		return true
+	}
+	return slices.ContainsFunc(node.Spec.Taints, func(taint corev1.Taint) bool {
+		return slices.Contains(autoscalerDisruptionTaints, taint.Key)
+	})
+}
+
+// nodeDraining reports whether a node is drained, see NodeDraining,
+// remembering the nodes it read in draining.
+func (cc *ClusterContext) nodeDraining(name string, draining map[string]bool) bool {
+	if value, ok := draining[name]; ok {
+		return value
+	}
+	node := &corev1.Node{}
+	if err := cc.Client.Get(cc.Ctx, types.NamespacedName{Name: name}, node); err != nil {
+		if !apierrors.IsNotFound(err) {
+			cc.ReqLogger.Error(err, "Failed to read the node for the node drain", "node", name)
+		}
+		draining[name] = false
+		return false
+	}
+	draining[name] = NodeDraining(node)
+	return draining[name]
+}
+
+// reconcileDrainBudgets keeps a PodDisruptionBudget for every group of holds,
+// allowing no eviction for the groups held, and deletes the budgets of the
+// cluster for other groups. A budget of the same name the cluster does not own
+// is left alone.
+func (cc *ClusterContext) reconcileDrainBudgets(holds map[string]bool) {
+	cr := cc.MarklogicCluster
+	disruptible := map[string]bool{}
+	for _, group := range cr.Spec.MarkLogicGroups {
+		if group != nil && group.AllowDisruptibleNodes {
+			disruptible[group.Name] = true
+		}
+	}
+	budgets := &policyv1.PodDisruptionBudgetList{}
+	if err := cc.Client.List(cc.Ctx, budgets, client.InNamespace(cr.Namespace), client.MatchingLabels{ClusterNameLabel: cr.Name}); err != nil {
+		cc.ReqLogger.Error(err, "Failed to list the PodDisruptionBudgets of the cluster")
+		return
+	}
+	for i := range budgets.Items {
+		budget := &budgets.Items[i]
+		if _, ok := holds[budget.Name]; ok || !ownedBy(budget, cr.UID) {
+			continue
+		}
+		if err := cc.Client.Delete(cc.Ctx, budget); err != nil && !apierrors.IsNotFound(err) {
+			cc.ReqLogger.Error(err, "Failed to delete the PodDisruptionBudget", "name", budget.Name)
+		}
+	}
+	for group, hold := range holds {
+		maxUnavailable := intstr.FromInt32(1)
+		switch {
+		case disruptible[group]:
+			maxUnavailable = disruptibleMaxUnavailable
+		case hold:
+			maxUnavailable = intstr.FromInt32(0)
+		}
+		alwaysAllow := policyv1.AlwaysAllow
+		desired := policyv1.PodDisruptionBudgetSpec{
+			MaxUnavailable: &maxUnavailable,
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{
+				"app.kubernetes.io/name":     "marklogic",
+				"app.kubernetes.io/instance": group,
+			}},
+			// Pods that are not ready hold no forests and must not block a drain.
+			UnhealthyPodEvictionPolicy: &alwaysAllow,
+		}
+		budget := &policyv1.PodDisruptionBudget{}
+		err := cc.Client.Get(cc.Ctx, types.NamespacedName{Name: group, Namespace: cr.Namespace}, budget)
+		switch {
+		case apierrors.IsNotFound(err):
+			budget = &policyv1.PodDisruptionBudget{
+				ObjectMeta: generateObjectMeta(group, cr.Namespace, withClusterNameLabel(cc.GetClusterLabels(cr.Name), cr.Name), cc.GetClusterAnnotations()),
+				Spec:       desired,
+			}
+			AddOwnerRefToObject(budget, marklogicClusterAsOwner(cr))
+			err = cc.Client.Create(cc.Ctx, budget)
+		case err != nil:
+		case !ownedBy(budget, cr.UID):
+			cc.recordClusterEvent(corev1.EventTypeWarning, nodeDrainReasonPDBConflict,
+				fmt.Sprintf("PodDisruptionBudget %s is not managed by the cluster, the drain of the nodes of group %s is not coordinated", group, group))
+			continue
+		case !reflect.DeepEqual(budget.Spec, desired):
+			budget.Spec = desired
+			err = cc.Client.Update(cc.Ctx, budget)
+		}
+		if err != nil {
+			cc.ReqLogger.Error(err, "Failed to reconcile the PodDisruptionBudget", "group", group)
+		}
+	}
+}
+
+func (cc *ClusterContext) setNodeDrainStatus(status *marklogicv1.NodeDrainStatus) {
+	cr := cc.MarklogicCluster
+	patchBase := client.MergeFrom(cr.DeepCopy())
+	cr.Status.NodeDrain = status
+	if err := patchStatus(cc.Ctx, cc.Client, cr, patchBase); err != nil {
+		cc.ReqLogger.Error(err, "Failed to update the node drain in the cluster status")
+	}
+}
+
+// nextNodeDrainCheck is when the pods on drained nodes are checked again.
+// Forests are not watched, so their failover would not be noticed otherwise.
+func nextNodeDrainCheck(cr *marklogicv1.MarklogicCluster) time.Time {
+	if next, ok := nodeDrainChecks.Load(types.NamespacedName{Namespace: cr.Namespace, Name: cr.Name}); ok {
+		return next.(time.Time)
+	}
+	return time.Time{}
+}
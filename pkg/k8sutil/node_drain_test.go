@@ -0,0 +1,259 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestReconcileNodeDrainFailsOverForestsBeforeEviction(t *testing.T) {
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default", UID: "ml-uid"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			ClusterDomain:   "cluster.local",
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{{Name: "dnode", IsBootstrap: true}},
+			NodeDrain:       &marklogicv1.NodeDrain{Enabled: true},
+		},
+	}
+	pod0, pod1 := newStorageTestPod("dnode-0"), newStorageTestPod("dnode-1")
+	pod0.Spec.NodeName, pod1.Spec.NodeName = "worker-a", "worker-b"
+	nodeA := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-a"}}
+	nodeB := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-b"}, Spec: corev1.NodeSpec{Unschedulable: true}}
+	cc := newUpgradeTestContext(t, cr, pod0, pod1, nodeA, nodeB)
+	recorder := cc.Recorder.(*record.FakeRecorder)
+	host0, host1 := "dnode-0.dnode.default.svc.cluster.local", "dnode-1.dnode.default.svc.cluster.local"
+	forests := []mlmanage.ForestStatus{
+		{Name: "Documents-0", Host: host0, State: "open"},
+		{Name: "Documents-1", Host: host1, State: "open"},
+		{Name: "Meters", Host: host1, State: "open"},
+	}
+	restarted := []string{}
+	original := NewDynamicManagementClient
+	NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+		return &stubDynamicManagementClient{
+			forestsStatusFn: func() ([]mlmanage.ForestStatus, error) {
+				return append([]mlmanage.ForestStatus(nil), forests...), nil
+			},
+			forestReplicasFn: func(forest string) ([]string, error) {
+				if forest == "Meters" {
+					return nil, nil
+				}
+				return []string{forest + "-replica"}, nil
+			},
+			restartForestFn: func(forest string) error {
+				restarted = append(restarted, forest)
+				return nil
+			},
+		}
+	}
+	t.Cleanup(func() {
+		NewDynamicManagementClient = original
+		nodeDrainChecks.Delete(types.NamespacedName{Namespace: "default", Name: "ml"})
+	})
+	budget := func() *policyv1.PodDisruptionBudget {
+		t.Helper()
+		pdb := &policyv1.PodDisruptionBudget{}
+		if err := cc.Client.Get(cc.Ctx, types.NamespacedName{Namespace: "default", Name: "dnode"}, pdb); err != nil {
+			t.Fatalf("expected the PodDisruptionBudget of the group: %v", err)
+		}
+		return pdb
+	}
+	events := func(reason string) int {
+		count := 0
+		for len(recorder.Events) > 0 {
+			if strings.Contains(<-recorder.Events, reason) {
+				count++
+			}
+		}
+		return count
+	}
+
+	// The forest of the pod on the cordoned node is restarted to fail over,
+	// and the budget allows no eviction meanwhile.
+	if res := cc.ReconcileNodeDrain(); res.Completed() {
+		t.Fatalf("expected the node drain never to hold up the reconcile")
+	}
+	if len(restarted) != 1 || restarted[0] != "Documents-1" {
+		t.Fatalf("expected only Documents-1 to be restarted, got %v", restarted)
+	}
+	status := cr.Status.NodeDrain
+	if status == nil || len(status.Pods) != 1 || status.Pods[0].Pod != "dnode-1" || status.Pods[0].State != marklogicv1.NodeDrainFailingOver ||
+		len(status.Pods[0].Forests) != 1 || len(status.Pods[0].UnprotectedForests) != 1 || status.Pods[0].UnprotectedForests[0] != "Meters" {
+		t.Fatalf("unexpected node drain status %+v", status)
+	}
+	if pdb := budget(); pdb.Spec.MaxUnavailable.IntValue() != 0 {
+		t.Fatalf("expected the budget to allow no eviction, got %v", pdb.Spec.MaxUnavailable)
+	}
+	if events(nodeDrainReasonUnprotected) != 1 {
+		t.Fatalf("expected a %s event", nodeDrainReasonUnprotected)
+	}
+	if next := nextNodeDrainCheck(cr); time.Until(next) > nodeDrainCheckInterval {
+		t.Fatalf("expected the failover to be checked again soon, got %v", next)
+	}
+
+	// The forest is not restarted again while its replica takes over.
+	cc.ReconcileNodeDrain()
+	if len(restarted) != 1 || cr.Status.NodeDrain.Pods[0].State != marklogicv1.NodeDrainFailingOver {
+		t.Fatalf("expected the failover to be awaited, restarted %v", restarted)
+	}
+
+	// Once the replica is the acting master, the pod can be evicted.
+	forests[1].State = "sync replicating"
+	cc.ReconcileNodeDrain()
+	if drain := cr.Status.NodeDrain.Pods[0]; drain.State != marklogicv1.NodeDrainReady || len(drain.Forests) != 0 {
+		t.Fatalf("expected the pod to be released, got %+v", drain)
+	}
+	if pdb := budget(); pdb.Spec.MaxUnavailable.IntValue() != 1 {
+		t.Fatalf("expected the budget to allow one eviction, got %v", pdb.Spec.MaxUnavailable)
+	}
+	if events(nodeDrainReasonReady) != 1 {
+		t.Fatalf("expected a %s event", nodeDrainReasonReady)
+	}
+
+	// Uncordoning the node ends the drain, and disabling the drain removes
+	// the budget.
+	nodeB.Spec.Unschedulable = false
+	if err := cc.Client.Update(cc.Ctx, nodeB); err != nil {
+		t.Fatalf("failed to update node: %v", err)
+	}
+	cc.ReconcileNodeDrain()
+	if cr.Status.NodeDrain != nil {
+		t.Fatalf("expected no pod on a cordoned node, got %+v", cr.Status.NodeDrain)
+	}
+	cr.Spec.NodeDrain = nil
+	cc.ReconcileNodeDrain()
+	if err := cc.Client.Get(cc.Ctx, types.NamespacedName{Namespace: "default", Name: "dnode"}, &policyv1.PodDisruptionBudget{}); !apierrors.IsNotFound(err) {
+		t.Fatalf("expected the budget to be deleted, got %v", err)
+	}
+}
+
+func TestReconcileNodeDrainTimesOut(t *testing.T) {
+	past := metav1.NewTime(time.Now().Add(-time.Hour))
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default", UID: "ml-uid"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			ClusterDomain:   "cluster.local",
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{{Name: "dnode", IsBootstrap: true}},
+			NodeDrain:       &marklogicv1.NodeDrain{Enabled: true, FailoverTimeout: &metav1.Duration{Duration: 5 * time.Minute}},
+		},
+		Status: marklogicv1.MarklogicClusterStatus{NodeDrain: &marklogicv1.NodeDrainStatus{Pods: []marklogicv1.DrainingPodStatus{{
+			Pod: "dnode-0", Group: "dnode", Node: "worker-a", State: marklogicv1.NodeDrainFailingOver, StartTime: &past, Forests: []string{"Documents"},
+		}}}},
+	}
+	pod := newStorageTestPod("dnode-0")
+	pod.Spec.NodeName = "worker-a"
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-a"}, Spec: corev1.NodeSpec{Unschedulable: true}}
+	cc := newUpgradeTestContext(t, cr, pod, node)
+	original := NewDynamicManagementClient
+	NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+		return &stubDynamicManagementClient{
+			forestsStatusFn: func() ([]mlmanage.ForestStatus, error) {
+				return []mlmanage.ForestStatus{{Name: "Documents", Host: "dnode-0.dnode.default.svc.cluster.local", State: "open"}}, nil
+			},
+			forestReplicasFn: func(forest string) ([]string, error) { return []string{"Documents-replica"}, nil },
+		}
+	}
+	t.Cleanup(func() {
+		NewDynamicManagementClient = original
+		nodeDrainChecks.Delete(types.NamespacedName{Namespace: "default", Name: "ml"})
+	})
+
+	cc.ReconcileNodeDrain()
+	if drain := cr.Status.NodeDrain.Pods[0]; drain.State != marklogicv1.NodeDrainTimedOut || len(drain.Forests) != 1 {
+		t.Fatalf("expected the failover to time out, got %+v", drain)
+	}
+	pdb := &policyv1.PodDisruptionBudget{}
+	if err := cc.Client.Get(cc.Ctx, types.NamespacedName{Namespace: "default", Name: "dnode"}, pdb); err != nil || pdb.Spec.MaxUnavailable.IntValue() != 1 {
+		t.Fatalf("expected the budget to allow the eviction, got %+v, %v", pdb.Spec, err)
+	}
+}
+
+func TestReconcileNodeDrainWaitsForAutoscalerDisruption(t *testing.T) {
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default", UID: "ml-uid"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			ClusterDomain:   "cluster.local",
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{{Name: "dnode", IsBootstrap: true, AutoscalerDisruption: marklogicv1.AutoscalerDisruptionCoordinated}},
+		},
+	}
+	if !NodeDrainEnabled(cr) {
+		t.Fatalf("expected a Coordinated group to coordinate the node drain")
+	}
+	pod := newStorageTestPod("dnode-0")
+	pod.Spec.NodeName = "worker-a"
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-a"}, Spec: corev1.NodeSpec{Taints: []corev1.Taint{
+		{Key: "karpenter.sh/disrupted", Effect: corev1.TaintEffectNoSchedule},
+	}}}
+	if !NodeDraining(node) {
+		t.Fatalf("expected a node tainted by Karpenter to be drained")
+	}
+	cc := newUpgradeTestContext(t, cr, pod, node)
+	original := NewDynamicManagementClient
+	NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+		return &stubDynamicManagementClient{
+			forestsStatusFn: func() ([]mlmanage.ForestStatus, error) {
+				return []mlmanage.ForestStatus{{Name: "Documents", Host: "dnode-0.dnode.default.svc.cluster.local", State: "open"}}, nil
+			},
+			forestReplicasFn: func(forest string) ([]string, error) { return []string{"Documents-replica"}, nil },
+		}
+	}
+	t.Cleanup(func() {
+		NewDynamicManagementClient = original
+		nodeDrainChecks.Delete(types.NamespacedName{Namespace: "default", Name: "ml"})
+	})
+
+	cc.ReconcileNodeDrain()
+	if status := cr.Status.NodeDrain; status == nil || len(status.Pods) != 1 || status.Pods[0].State != marklogicv1.NodeDrainFailingOver {
+		t.Fatalf("expected the forests of the pod to fail over, got %+v", status)
+	}
+	pdb := &policyv1.PodDisruptionBudget{}
+	if err := cc.Client.Get(cc.Ctx, types.NamespacedName{Namespace: "default", Name: "dnode"}, pdb); err != nil || pdb.Spec.MaxUnavailable.IntValue() != 0 {
+		t.Fatalf("expected the budget to hold the eviction, got %+v, %v", pdb.Spec, err)
+	}
+}
+
+func TestReconcileNodeDrainKeepsBudgetsOfDisruptibleGroups(t *testing.T) {
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default", UID: "ml-uid"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			ClusterDomain: "cluster.local",
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{
+				{Name: "dnode", IsBootstrap: true},
+				{Name: "enode", AllowDisruptibleNodes: true},
+			},
+		},
+	}
+	cc := newUpgradeTestContext(t, cr)
+	t.Cleanup(func() { nodeDrainChecks.Delete(types.NamespacedName{Namespace: "default", Name: "ml"}) })
+
+	// Without spec.nodeDrain, only the disruptible group has a budget.
+	cc.ReconcileNodeDrain()
+	pdb := &policyv1.PodDisruptionBudget{}
+	if err := cc.Client.Get(cc.Ctx, types.NamespacedName{Namespace: "default", Name: "enode"}, pdb); err != nil || pdb.Spec.MaxUnavailable.String() != "50%" {
+		t.Fatalf("expected the budget to allow half of the group to be evicted, got %+v, %v", pdb.Spec, err)
+	}
+	if err := cc.Client.Get(cc.Ctx, types.NamespacedName{Namespace: "default", Name: "dnode"}, &policyv1.PodDisruptionBudget{}); !apierrors.IsNotFound(err) {
+		t.Fatalf("expected no budget for the dnode group, got %v", err)
+	}
+
+	// With it, the disruptible group keeps its budget.
+	cr.Spec.NodeDrain = &marklogicv1.NodeDrain{Enabled: true}
+	cc.ReconcileNodeDrain()
+	if err := cc.Client.Get(cc.Ctx, types.NamespacedName{Namespace: "default", Name: "enode"}, pdb); err != nil || pdb.Spec.MaxUnavailable.String() != "50%" {
+		t.Fatalf("expected the disruptible budget to be kept, got %+v, %v", pdb.Spec, err)
+	}
+	if err := cc.Client.Get(cc.Ctx, types.NamespacedName{Namespace: "default", Name: "dnode"}, pdb); err != nil || pdb.Spec.MaxUnavailable.IntValue() != 1 {
+		t.Fatalf("expected the dnode budget, got %+v, %v", pdb.Spec, err)
+	}
+}
@@ -0,0 +1,379 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// The annotations OpenShift sets on every namespace with the IDs and the
+	// SELinux level the restricted SCCs assign to its pods.
+	sccUIDRangeAnnotation           = "openshift.io/sa.scc.uid-range"
+	sccSupplementalGroupsAnnotation = "openshift.io/sa.scc.supplemental-groups"
+	sccMCSAnnotation                = "openshift.io/sa.scc.mcs"
+
+	routeReasonUnavailable = "RouteUnavailable"
+)
+
+var (
+	routeGVK     = schema.GroupVersionKind{Group: "route.openshift.io", Version: "v1", Kind: "Route"}
+	routeListGVK = schema.GroupVersionKind{Group: "route.openshift.io", Version: "v1", Kind: "RouteList"}
+)
+
+// idRange is a range of user or group IDs, end excluded.
+type idRange struct {
+	start, end int64
+}
+
+// sccRanges are the user and group IDs and the SELinux level the SCCs allow
+// in a namespace. Without them no fixed ID is allowed and the SCC assigns
+// every ID.
+type sccRanges struct {
+	uids   []idRange
+	groups []idRange
+	mcs    string
+}
+
+// openShiftSCCRanges reads the SCC ranges from the annotations of the
+// namespace of the cluster. A namespace that cannot be read has no ranges.
+func (cc *ClusterContext) openShiftSCCRanges() sccRanges {
+	namespace := &corev1.Namespace{}
+	if err := cc.Client.Get(cc.Ctx, types.NamespacedName{Name: cc.MarklogicCluster.Namespace}, namespace); err != nil {
+		cc.ReqLogger.Error(err, "Failed to read the SCC ranges of the namespace, fixed user and group IDs are left to the SCC")
+		return sccRanges{}
+	}
+	annotations := namespace.GetAnnotations()
+	return sccRanges{
+		uids:   parseIDRanges(annotations[sccUIDRangeAnnotation]),
+		groups: parseIDRanges(annotations[sccSupplementalGroupsAnnotation]),
+		mcs:    annotations[sccMCSAnnotation],
+	}
+}
+
+// parseIDRanges parses the comma separated ranges of an SCC annotation, each
+// either start/size or start-end. Invalid ranges are skipped.
+func parseIDRanges(value string) []idRange {
+	ranges := []idRange{}
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if start, size, ok := strings.Cut(part, "/"); ok {
+			s, errStart := strconv.ParseInt(start, 10, 64)
+			n, errSize := strconv.ParseInt(size, 10, 64)
+			if errStart == nil && errSize == nil && n > 0 {
+				ranges = append(ranges, idRange{start: s, end: s + n})
+			}
+			continue
+		}
+		if start, end, ok := strings.Cut(part, "-"); ok {
+			s, errStart := strconv.ParseInt(start, 10, 64)
+			e, errEnd := strconv.ParseInt(end, 10, 64)
+			if errStart == nil && errEnd == nil && e >= s {
+				ranges = append(ranges, idRange{start: s, end: e + 1})
+			}
+		}
+	}
+	return ranges
+}
+
+func allowedID(id *int64, ranges []idRange) bool {
+	if id == nil {
+		return true
+	}
+	for _, r := range ranges {
+		if *id >= r.start && *id < r.end {
+			return true
+		}
+	}
+	return false
+}
+
+// podSecurityContext returns a copy of a pod security context the SCC
+// admits: IDs outside the ranges are dropped for the SCC to assign, and the
+// pod runs as non-root with the RuntimeDefault seccomp profile and the
+// SELinux level of the namespace unless they are set.
+func (r sccRanges) podSecurityContext(base *corev1.PodSecurityContext) *corev1.PodSecurityContext {
+	sc := &corev1.PodSecurityContext{}
+	if base != nil {
+		sc = base.DeepCopy()
+	}
+	if !allowedID(sc.RunAsUser, r.uids) {
+		sc.RunAsUser = nil
+	}
+	if !allowedID(sc.RunAsGroup, r.groups) {
+		sc.RunAsGroup = nil
+	}
+	if !allowedID(sc.FSGroup, r.groups) {
+		sc.FSGroup = nil
+	}
+	sc.SupplementalGroups = slices.DeleteFunc(sc.SupplementalGroups, func(id int64) bool { return !allowedID(&id, r.groups) })
+	if len(sc.SupplementalGroups) == 0 {
+		sc.SupplementalGroups = nil
+	}
+	sc.RunAsNonRoot = boolPtr(true)
+	if sc.SeccompProfile == nil {
+		sc.SeccompProfile = &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault}
+	}
+	if sc.SELinuxOptions == nil && r.mcs != "" {
+		sc.SELinuxOptions = &corev1.SELinuxOptions{Level: r.mcs}
+	}
+	return sc
+}
+
+// containerSecurityContext returns a copy of a container security context the
+// restricted SCC admits.
+func (r sccRanges) containerSecurityContext(base *corev1.SecurityContext) *corev1.SecurityContext {
+	sc := &corev1.SecurityContext{}
+	if base != nil {
+		sc = base.DeepCopy()
+	}
+	if !allowedID(sc.RunAsUser, r.uids) {
+		sc.RunAsUser = nil
+	}
+	if !allowedID(sc.RunAsGroup, r.groups) {
+		sc.RunAsGroup = nil
+	}
+	sc.Privileged = nil
+	sc.AllowPrivilegeEscalation = boolPtr(false)
+	sc.RunAsNonRoot = boolPtr(true)
+	if sc.Capabilities == nil {
+		sc.Capabilities = &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}}
+	}
+	return sc
+}
+
+// applyOpenShiftSecurity adapts the security contexts of a group to the SCC.
+// The contexts are always set, so the defaults of the MarklogicGroup CRD,
+// which run MarkLogic as user 1000 with fsGroup 2, do not apply.
+func applyOpenShiftSecurity(params *MarkLogicGroupParameters, ranges sccRanges) {
+	params.PodSecurityContext = ranges.podSecurityContext(params.PodSecurityContext)
+	params.ContainerSecurityContext = ranges.containerSecurityContext(getMarkLogicContainerSecurityContextOrDefault(params.ContainerSecurityContext))
+	if params.LogCollection != nil && params.LogCollection.SecurityContext != nil {
+		logCollection := params.LogCollection.DeepCopy()
+		logCollection.SecurityContext = ranges.containerSecurityContext(logCollection.SecurityContext)
+		params.LogCollection = logCollection
+	}
+}
+
+// ReconcileRoutes creates the Routes of a cluster with spec.ocpCompatible in
+// place of its Ingresses: one per app server for the HAProxy ingress and one
+// per admin host for networkAccess.adminIngress. TLS is terminated at the
+// router with the certificate of the Ingress TLS secret, or the default
+// certificate of the router when there is none. Routes the cluster no longer
+// needs are deleted.
+func (cc *ClusterContext) ReconcileRoutes() result.ReconcileResult {
+	cr := cc.MarklogicCluster
+	desired := []*unstructured.Unstructured{}
+	if cr.Spec.OCPCompatible {
+		var err error
+		if desired, err = cc.generateRoutes(); err != nil {
+			return result.Error(err)
+		}
+	}
+	current := &unstructured.UnstructuredList{}
+	current.SetGroupVersionKind(routeListGVK)
+	err := cc.Client.List(cc.Ctx, current, client.InNamespace(cr.Namespace), client.MatchingLabels(getSelectorLabels(cr.Name)))
+	if meta.IsNoMatchError(err) {
+		if len(desired) > 0 {
+			cc.recordClusterEvent(corev1.EventTypeWarning, routeReasonUnavailable, "ocpCompatible is set but the Route API of OpenShift is not available")
+		}
+		return result.Continue()
+	}
+	if err != nil {
+		return result.Error(err)
+	}
+	existing := map[string]*unstructured.Unstructured{}
+	for i := range current.Items {
+		existing[current.Items[i].GetName()] = &current.Items[i]
+	}
+	for _, route := range desired {
+		if err := cc.applyRoute(route, existing[route.GetName()]); err != nil {
+			return result.Error(err)
+		}
+		delete(existing, route.GetName())
+	}
+	for name, route := range existing {
+		if !metav1.IsControlledBy(route, cr) {
+			continue
+		}
+		cc.ReqLogger.Info("Deleting Route", "name", name)
+		if err := client.IgnoreNotFound(cc.Client.Delete(cc.Ctx, route)); err != nil {
+			return result.Error(err)
+		}
+	}
+	if cr.Spec.OCPCompatible {
+		return cc.deleteHAProxyIngress()
+	}
+	return result.Continue()
+}
+
+func (cc *ClusterContext) applyRoute(desired, current *unstructured.Unstructured) error {
+	if current == nil {
+		cc.ReqLogger.Info("Creating Route", "name", desired.GetName())
+		return cc.Client.Create(cc.Ctx, desired)
+	}
+	spec, _ := desired.Object["spec"].(map[string]interface{})
+	if host, _, _ := unstructured.NestedString(current.Object, "spec", "host"); spec["host"] == nil && host != "" {
+		// The router generates a host when none is set.
+		spec["host"] = host
+	}
+	metadataChanged := syncMetadata(current, desired)
+	if !metadataChanged && equality.Semantic.DeepEqual(current.Object["spec"], desired.Object["spec"]) {
+		return nil
+	}
+	current.Object["spec"] = desired.Object["spec"]
+	cc.ReqLogger.Info("Updating Route", "name", desired.GetName())
+	return cc.Client.Update(cc.Ctx, current)
+}
+
+// deleteHAProxyIngress deletes the HAProxy Ingress left from before the
+// cluster was ocpCompatible. The admin Ingress is deleted by
+// ReconcileAdminIngress.
+func (cc *ClusterContext) deleteHAProxyIngress() result.ReconcileResult {
+	cr := cc.MarklogicCluster
+	ingress := &networkingv1.Ingress{}
+	err := cc.Client.Get(cc.Ctx, types.NamespacedName{Namespace: cr.Namespace, Name: clusterFullname(cr)}, ingress)
+	if apierrors.IsNotFound(err) {
+		return result.Continue()
+	}
+	if err != nil {
+		return result.Error(err)
+	}
+	if !metav1.IsControlledBy(ingress, cr) {
+		return result.Continue()
+	}
+	cc.ReqLogger.Info("Deleting the Ingress replaced by Routes", "name", ingress.Name)
+	if err := client.IgnoreNotFound(cc.Client.Delete(cc.Ctx, ingress)); err != nil {
+		return result.Error(err)
+	}
+	return result.Continue()
+}
+
+func (cc *ClusterContext) generateRoutes() ([]*unstructured.Unstructured, error) {
+	cr := cc.MarklogicCluster
+	routes := []*unstructured.Unstructured{}
+	if haproxy := cr.Spec.HAProxy; haproxy != nil && haproxy.Enabled && haproxy.Ingress.Enabled {
+		tls, err := cc.routeTLS(ingressTLSSecret(haproxy.Ingress.TLS, haproxy.Ingress.Host), haproxy.Ingress.TLS != nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, appServer := range haproxy.AppServers {
+			name := fmt.Sprintf("%s-%d", clusterFullname(cr), appServer.Port)
+			routes = append(routes, cc.generateRoute(name, haproxy.Ingress.Host, appServer.Path, haproxyName(cr),
+				haproxy.FrontendPort, haproxy.Ingress.Annotations, tls))
+		}
+	}
+	if access := cr.Spec.NetworkAccess; access != nil && access.ExposeAdmin && access.AdminIngress != nil {
+		tls, err := cc.routeTLS(access.AdminIngress.TLSSecretName, true)
+		if err != nil {
+			return nil, err
+		}
+		bootstrapGroup := ""
+		for _, group := range cr.Spec.MarkLogicGroups {
+			if group != nil && group.IsBootstrap {
+				bootstrapGroup = group.Name
+			}
+		}
+		hostPorts := []struct {
+			host string
+			port int32
+		}{
+			{access.AdminIngress.AppServicesHost, 8000},
+			{access.AdminIngress.AdminHost, 8001},
+			{access.AdminIngress.ManageHost, 8002},
+		}
+		for _, hostPort := range hostPorts {
+			if hostPort.host == "" {
+				continue
+			}
+			name := fmt.Sprintf("%s-admin-%d", clusterFullname(cr), hostPort.port)
+			routes = append(routes, cc.generateRoute(name, hostPort.host, "", bootstrapGroup+"-cluster",
+				hostPort.port, access.AdminIngress.Annotations, tls))
+		}
+	}
+	return routes, nil
+}
+
+func (cc *ClusterContext) generateRoute(name, host, path, service string, port int32, annotations map[string]string, tls map[string]interface{}) *unstructured.Unstructured {
+	cr := cc.MarklogicCluster
+	spec := map[string]interface{}{
+		"to": map[string]interface{}{
+			"kind":   "Service",
+			"name":   service,
+			"weight": int64(100),
+		},
+		"port":           map[string]interface{}{"targetPort": int64(port)},
+		"wildcardPolicy": "None",
+	}
+	if host != "" {
+		spec["host"] = host
+	}
+	if path != "" {
+		spec["path"] = path
+	}
+	if tls != nil {
+		spec["tls"] = tls
+	}
+	route := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	route.SetGroupVersionKind(routeGVK)
+	route.SetName(name)
+	route.SetNamespace(cr.Namespace)
+	route.SetLabels(cc.GetClusterLabels(cr.Name))
+	route.SetAnnotations(mergeMetadata(cr.Spec.AdditionalAnnotations, annotations))
+	route.SetOwnerReferences([]metav1.OwnerReference{marklogicClusterAsOwner(cr)})
+	return route
+}
+
+// routeTLS returns the edge TLS of a Route with the certificate and key of a
+// kubernetes.io/tls secret, as Routes cannot reference secrets. Without a
+// secret the router serves its default certificate.
+func (cc *ClusterContext) routeTLS(secretName string, enabled bool) (map[string]interface{}, error) {
+	if !enabled {
+		return nil, nil
+	}
+	tls := map[string]interface{}{
+		"termination":                   "edge",
+		"insecureEdgeTerminationPolicy": "Redirect",
+	}
+	if secretName == "" {
+		return tls, nil
+	}
+	secret := &corev1.Secret{}
+	if err := cc.Client.Get(cc.Ctx, types.NamespacedName{Namespace: cc.MarklogicCluster.Namespace, Name: secretName}, secret); err != nil {
+		return nil, fmt.Errorf("failed to read the TLS secret %s of the Route: %w", secretName, err)
+	}
+	tls["certificate"] = string(secret.Data[corev1.TLSCertKey])
+	tls["key"] = string(secret.Data[corev1.TLSPrivateKeyKey])
+	if ca := secret.Data["ca.crt"]; len(ca) > 0 {
+		tls["caCertificate"] = string(ca)
+	}
+	return tls, nil
+}
+
+// ingressTLSSecret returns the secret of the Ingress TLS entry covering host.
+func ingressTLSSecret(entries []networkingv1.IngressTLS, host string) string {
+	for _, entry := range entries {
+		if len(entry.Hosts) == 0 || slices.Contains(entry.Hosts, host) {
+			return entry.SecretName
+		}
+	}
+	return ""
+}
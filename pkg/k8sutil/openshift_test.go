@@ -0,0 +1,123 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"testing"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestOpenShiftSecurityContextsUseSCCRanges(t *testing.T) {
+	ranges := sccRanges{
+		uids:   parseIDRanges("1000680000/10000"),
+		groups: parseIDRanges("1000680000/10000, 5-6"),
+		mcs:    "s0:c26,c5",
+	}
+	inRange := int64(1000680001)
+	params := &MarkLogicGroupParameters{
+		PodSecurityContext: &corev1.PodSecurityContext{
+			FSGroup:            int64Ptr(2),
+			RunAsUser:          &inRange,
+			SupplementalGroups: []int64{5, 7},
+		},
+		ContainerSecurityContext: &corev1.SecurityContext{RunAsUser: int64Ptr(1000), Privileged: boolPtr(true)},
+	}
+	applyOpenShiftSecurity(params, ranges)
+
+	pod := params.PodSecurityContext
+	if pod.FSGroup != nil || pod.RunAsUser == nil || *pod.RunAsUser != inRange || len(pod.SupplementalGroups) != 1 || pod.SupplementalGroups[0] != 5 {
+		t.Fatalf("expected only the IDs in the SCC ranges to be kept, got %+v", pod)
+	}
+	if pod.SeccompProfile == nil || pod.SeccompProfile.Type != corev1.SeccompProfileTypeRuntimeDefault ||
+		pod.SELinuxOptions == nil || pod.SELinuxOptions.Level != "s0:c26,c5" || !*pod.RunAsNonRoot {
+		t.Fatalf("expected seccomp and the SELinux level of the namespace, got %+v", pod)
+	}
+	container := params.ContainerSecurityContext
+	if container.RunAsUser != nil || container.Privileged != nil || *container.AllowPrivilegeEscalation || container.Capabilities == nil {
+		t.Fatalf("expected a restricted container security context, got %+v", container)
+	}
+
+	// Without a security context the CRD defaults of user 1000 and fsGroup 2
+	// must not apply, so the contexts are always set.
+	params = &MarkLogicGroupParameters{}
+	applyOpenShiftSecurity(params, sccRanges{})
+	if params.PodSecurityContext == nil || params.ContainerSecurityContext == nil || params.PodSecurityContext.SELinuxOptions != nil {
+		t.Fatalf("expected security contexts without a SELinux level, got %+v %+v", params.PodSecurityContext, params.ContainerSecurityContext)
+	}
+}
+
+func TestReconcileRoutesReplacesIngresses(t *testing.T) {
+	pathBased := false
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default", UID: "cluster-uid"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			OCPCompatible: true,
+			HAProxy: &marklogicv1.HAProxy{
+				Enabled:          true,
+				PathBasedRouting: &pathBased,
+				FrontendPort:     8080,
+				AppServers:       []marklogicv1.AppServers{{Name: "app", Port: 8000, Path: "/console"}},
+				Ingress: marklogicv1.Ingress{
+					Enabled: true,
+					Host:    "ml.apps.example.com",
+					TLS:     []networkingv1.IngressTLS{{Hosts: []string{"ml.apps.example.com"}, SecretName: "ml-tls"}},
+				},
+			},
+			NetworkAccess: &marklogicv1.NetworkAccess{
+				ExposeAdmin:  true,
+				AdminIngress: &marklogicv1.AdminIngress{ManageHost: "manage.apps.example.com", TLSSecretName: "ml-tls"},
+			},
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{{Name: "dnode", IsBootstrap: true}},
+		},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml-tls", Namespace: "default"},
+		Type:       corev1.SecretTypeTLS,
+		Data:       map[string][]byte{corev1.TLSCertKey: []byte("cert"), corev1.TLSPrivateKeyKey: []byte("key")},
+	}
+	ingress := &networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{
+		Name: "ml", Namespace: "default", OwnerReferences: []metav1.OwnerReference{marklogicClusterAsOwner(cr)},
+	}}
+	cc := newUpgradeTestContext(t, cr, secret, ingress)
+
+	if res := cc.ReconcileRoutes(); res.Completed() {
+		t.Fatalf("expected the reconcile to continue")
+	}
+	route := &unstructured.Unstructured{}
+	route.SetGroupVersionKind(routeGVK)
+	if err := cc.Client.Get(cc.Ctx, types.NamespacedName{Namespace: "default", Name: "ml-8000"}, route); err != nil {
+		t.Fatalf("expected a Route for the app server: %v", err)
+	}
+	path, _, _ := unstructured.NestedString(route.Object, "spec", "path")
+	service, _, _ := unstructured.NestedString(route.Object, "spec", "to", "name")
+	certificate, _, _ := unstructured.NestedString(route.Object, "spec", "tls", "certificate")
+	if path != "/console" || service != "ml-haproxy" || certificate != "cert" {
+		t.Fatalf("unexpected Route spec %+v", route.Object["spec"])
+	}
+	admin := &unstructured.Unstructured{}
+	admin.SetGroupVersionKind(routeGVK)
+	if err := cc.Client.Get(cc.Ctx, types.NamespacedName{Namespace: "default", Name: "ml-admin-8002"}, admin); err != nil {
+		t.Fatalf("expected a Route for the Manage port: %v", err)
+	}
+	if service, _, _ := unstructured.NestedString(admin.Object, "spec", "to", "name"); service != "dnode-cluster" {
+		t.Fatalf("expected the admin Route to target the bootstrap group, got %s", service)
+	}
+	if err := cc.Client.Get(cc.Ctx, types.NamespacedName{Namespace: "default", Name: "ml"}, &networkingv1.Ingress{}); !apierrors.IsNotFound(err) {
+		t.Fatalf("expected the Ingress to be replaced by Routes, got %v", err)
+	}
+
+	cc.MarklogicCluster.Spec.OCPCompatible = false
+	if res := cc.ReconcileRoutes(); res.Completed() {
+		t.Fatalf("expected the reconcile to continue")
+	}
+	if err := cc.Client.Get(cc.Ctx, types.NamespacedName{Namespace: "default", Name: "ml-8000"}, route); !apierrors.IsNotFound(err) {
+		t.Fatalf("expected the Routes to be deleted, got %v", err)
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+)
+
+// ClusterOperation returns the value of a control annotation of the cluster,
+// or of the field of spec.operations that stands for it when the annotation
+// is not set. Boolean fields read as "true" when set.
+func ClusterOperation(cr *marklogicv1.MarklogicCluster, annotation string) string {
+	if value := cr.Annotations[annotation]; value != "" {
+		return value
+	}
+	_, value := operationField(cr.Spec.Operations, annotation)
+	return value
+}
+
+// operationFieldManager returns the field manager that most recently wrote
+// the control annotation, or the field of spec.operations it was read from.
+func operationFieldManager(cr *marklogicv1.MarklogicCluster, annotation string) string {
+	if cr.Annotations[annotation] != "" {
+		return annotationFieldManager(cr, annotation)
+	}
+	name, _ := operationField(cr.Spec.Operations, annotation)
+	return fieldManager(cr, "f:spec", "f:operations", "f:"+name)
+}
+
+// operationField returns the name and value of the field of spec.operations
+// for a control annotation.
+func operationField(ops *marklogicv1.Operations, annotation string) (string, string) {
+	if ops == nil {
+		ops = &marklogicv1.Operations{}
+	}
+	flag := func(set bool) string {
+		if set {
+			return "true"
+		}
+		return ""
+	}
+	switch annotation {
+	case UpgradeApprovalAnnotation:
+		return "approveUpgrade", ops.ApproveUpgrade
+	case PauseUpgradeAnnotation:
+		return "pauseUpgrade", flag(ops.PauseUpgrade)
+	case RetryUpgradeAnnotation:
+		return "retryUpgrade", ops.RetryUpgrade
+	case RestartHostsAnnotation:
+		return "restartHosts", ops.RestartHosts
+	case SupportBundleAnnotation:
+		return "supportBundle", ops.SupportBundle
+	case ExportAnnotation:
+		return "export", ops.Export
+	case ForceChangeDuringUpgradeAnnotation:
+		return "forceChangeDuringUpgrade", flag(ops.ForceChangeDuringUpgrade)
+	case SkipPreflightAnnotation:
+		return "skipPreflight", flag(ops.SkipPreflight)
+	case AcknowledgeQuorumLossAnnotation:
+		return "acknowledgeQuorumLoss", ops.AcknowledgeQuorumLoss
+	}
+	return "", ""
+}
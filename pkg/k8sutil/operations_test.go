@@ -0,0 +1,53 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"testing"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestClusterOperation(t *testing.T) {
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{RestartHostsAnnotation: "dnode-1"},
+			ManagedFields: []metav1.ManagedFieldsEntry{{
+				Manager:  "argocd-controller",
+				Time:     &metav1.Time{Time: time.Now()},
+				FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:operations":{"f:approveUpgrade":{}}}}`)},
+			}},
+		},
+		Spec: marklogicv1.MarklogicClusterSpec{Operations: &marklogicv1.Operations{
+			ApproveUpgrade:           "marklogic:12.0.1",
+			RestartHosts:             "dnode-0",
+			ForceChangeDuringUpgrade: true,
+			PauseUpgrade:             true,
+		}},
+	}
+	tests := []struct {
+		annotation string
+		want       string
+	}{
+		{UpgradeApprovalAnnotation, "marklogic:12.0.1"},
+		{RestartHostsAnnotation, "dnode-1"},
+		{ForceChangeDuringUpgradeAnnotation, "true"},
+		{PauseUpgradeAnnotation, "true"},
+		{RetryUpgradeAnnotation, ""},
+		{SkipPreflightAnnotation, ""},
+		{ExportAnnotation, ""},
+	}
+	for _, tt := range tests {
+		if got := ClusterOperation(cr, tt.annotation); got != tt.want {
+			t.Errorf("ClusterOperation(%s) = %q, want %q", tt.annotation, got, tt.want)
+		}
+	}
+	if actor := operationFieldManager(cr, UpgradeApprovalAnnotation); actor != "argocd-controller" {
+		t.Errorf("expected the manager of spec.operations.approveUpgrade as the actor, got %q", actor)
+	}
+	if got := ClusterOperation(&marklogicv1.MarklogicCluster{}, UpgradeApprovalAnnotation); got != "" {
+		t.Errorf("expected no operation without spec.operations, got %q", got)
+	}
+}
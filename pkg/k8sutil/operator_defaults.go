@@ -0,0 +1,85 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/operatorconfig"
+	appsv1 "k8s.io/api/apps/v1"
+)
+
+// OperatorConfig holds the operator-wide defaults. It is replaced at startup
+// when the operator runs with --config.
+var OperatorConfig = operatorconfig.Default()
+
+// Offline is set at startup with --offline for disconnected environments. The
+// operator then only talks to the Kubernetes API and the MarkLogic hosts:
+// image registries are not read and no telemetry is sent.
+var Offline bool
+
+// StatefulSetPVCRetention is cleared at startup on Kubernetes versions whose
+// StatefulSets ignore persistentVolumeClaimRetentionPolicy. The operator then
+// applies the retention policy of the data volumes itself.
+var StatefulSetPVCRetention = true
+
+// defaultProbe returns an enabled probe with the given timings.
+func defaultProbe(defaults operatorconfig.ProbeDefaults) marklogicv1.ContainerProbe {
+	return withProbeDefaults(marklogicv1.ContainerProbe{Enabled: true}, defaults)
+}
+
+// withProbeDefaults fills the timings the probe leaves unset.
+func withProbeDefaults(probe marklogicv1.ContainerProbe, defaults operatorconfig.ProbeDefaults) marklogicv1.ContainerProbe {
+	if probe.InitialDelaySeconds == 0 {
+		probe.InitialDelaySeconds = defaults.InitialDelaySeconds
+	}
+	if probe.TimeoutSeconds == 0 {
+		probe.TimeoutSeconds = defaults.TimeoutSeconds
+	}
+	if probe.PeriodSeconds == 0 {
+		probe.PeriodSeconds = defaults.PeriodSeconds
+	}
+	if probe.SuccessThreshold == 0 {
+		probe.SuccessThreshold = defaults.SuccessThreshold
+	}
+	if probe.FailureThreshold == 0 {
+		probe.FailureThreshold = defaults.FailureThreshold
+	}
+	return probe
+}
+
+func logCollectionImage(lc *marklogicv1.LogCollection) string {
+	if lc.Image != "" {
+		return lc.Image
+	}
+	switch lc.Engine {
+	case marklogicv1.LogCollectionEngineFluentd:
+		return OperatorConfig.FluentdImage
+	case marklogicv1.LogCollectionEngineVector:
+		return OperatorConfig.VectorImage
+	}
+	return OperatorConfig.LogCollectionImage
+}
+
+// applyDefaultStorageClass sets the default storage class on the data volume
+// template of a new StatefulSet. The templates of an existing StatefulSet are
+// immutable, so it keeps the storage class it was created with instead.
+func applyDefaultStorageClass(desired, current *appsv1.StatefulSet) {
+	for i := range desired.Spec.VolumeClaimTemplates {
+		template := &desired.Spec.VolumeClaimTemplates[i]
+		if template.Name != "datadir" || template.Spec.StorageClassName != nil {
+			continue
+		}
+		if current == nil {
+			if OperatorConfig.DefaultStorageClass != "" {
+				storageClass := OperatorConfig.DefaultStorageClass
+				template.Spec.StorageClassName = &storageClass
+			}
+			continue
+		}
+		for _, existing := range current.Spec.VolumeClaimTemplates {
+			if existing.Name == template.Name {
+				template.Spec.StorageClassName = existing.Spec.StorageClassName
+			}
+		}
+	}
+}
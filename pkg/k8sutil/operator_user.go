@@ -0,0 +1,126 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"fmt"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// operatorUserProvisionedAnnotation marks the manage-admin Secret once its
+	// user exists in MarkLogic with the password of the Secret. A recreated
+	// Secret lacks it, so the user is updated with the new password.
+	operatorUserProvisionedAnnotation = "marklogic.progress.com/operator-user-provisioned"
+
+	operatorUserReasonProvisioned = "Provisioned"
+	operatorUserReasonPending     = "Pending"
+
+	operatorUserRetryInterval = 10 * time.Second
+)
+
+// operatorUserEnabled reports whether the operator uses its own user, which
+// is the default unless spec.auth.operatorUser is false.
+func operatorUserEnabled(cr *marklogicv1.MarklogicCluster) bool {
+	return cr.Spec.Auth == nil || cr.Spec.Auth.OperatorUser == nil || *cr.Spec.Auth.OperatorUser
+}
+
+// ReconcileOperatorUser creates the operator's manage-admin user with the
+// admin credentials once the bootstrap host is up, so that later Manage API
+// calls no longer need the admin role. Until it succeeds the operator keeps
+// using the admin credentials and the rest of the reconcile goes on, with a
+// retry scheduled by nextOperatorUserRetry.
+func (cc *ClusterContext) ReconcileOperatorUser() result.ReconcileResult {
+	cr := cc.MarklogicCluster
+	if !operatorUserEnabled(cr) {
+		return result.Continue()
+	}
+	if _, ok := cc.provisionedOperatorUserSecret(); ok {
+		return result.Continue()
+	}
+	secret, err := cc.getSecret(dynamicCredentialSecretName(cr.Name))
+	if err != nil {
+		return cc.operatorUserPending(err)
+	}
+	if err := cc.provisionOperatorUser(secret); err != nil {
+		return cc.operatorUserPending(err)
+	}
+	username := string(secret.Data["username"])
+	if err := cc.setOperatorUserCondition(metav1.ConditionTrue, operatorUserReasonProvisioned,
+		fmt.Sprintf("Manage API calls use the %s user", username)); err != nil {
+		return result.Error(err)
+	}
+	return result.Continue()
+}
+
+// operatorUserPending records why the operator user is not provisioned yet
+// without holding back the steps after it.
+func (cc *ClusterContext) operatorUserPending(err error) result.ReconcileResult {
+	cc.ReqLogger.Info("Operator user is not provisioned yet, using the admin credentials", "error", err.Error())
+	if err := cc.setOperatorUserCondition(metav1.ConditionFalse, operatorUserReasonPending, err.Error()); err != nil {
+		return result.Error(err)
+	}
+	return result.Continue()
+}
+
+// nextOperatorUserRetry returns when to retry provisioning a pending
+// operator user, or zero when there is nothing to retry.
+func nextOperatorUserRetry(cr *marklogicv1.MarklogicCluster) time.Time {
+	if !operatorUserEnabled(cr) {
+		return time.Time{}
+	}
+	condition := meta.FindStatusCondition(cr.Status.Conditions, string(marklogicv1.OperatorUserReady))
+	if condition == nil || condition.Reason != operatorUserReasonPending {
+		return time.Time{}
+	}
+	return time.Now().Add(operatorUserRetryInterval)
+}
+
+func (cc *ClusterContext) provisionOperatorUser(secret *corev1.Secret) error {
+	adminClient, err := cc.newBootstrapAdminClient()
+	if err != nil {
+		return err
+	}
+	if err := adminClient.EnsureManageAdminUser(cc.Ctx, string(secret.Data["username"]), string(secret.Data["password"])); err != nil {
+		return err
+	}
+	patchBase := client.MergeFrom(secret.DeepCopy())
+	if secret.Annotations == nil {
+		secret.Annotations = map[string]string{}
+	}
+	secret.Annotations[operatorUserProvisionedAnnotation] = "true"
+	return cc.Client.Patch(cc.Ctx, secret, patchBase)
+}
+
+// provisionedOperatorUserSecret returns the operator user Secret when the
+// operator user is enabled and exists in MarkLogic.
+func (cc *ClusterContext) provisionedOperatorUserSecret() (*corev1.Secret, bool) {
+	cr := cc.MarklogicCluster
+	if !operatorUserEnabled(cr) {
+		return nil, false
+	}
+	secret, err := cc.getSecret(dynamicCredentialSecretName(cr.Name))
+	if err != nil || secret.Annotations[operatorUserProvisionedAnnotation] != "true" {
+		return nil, false
+	}
+	return secret, true
+}
+
+func (cc *ClusterContext) setOperatorUserCondition(status metav1.ConditionStatus, reason, message string) error {
+	cr := cc.MarklogicCluster
+	for _, existing := range cr.Status.Conditions {
+		if existing.Type == string(marklogicv1.OperatorUserReady) && existing.Status == status && existing.Message == message {
+			return nil
+		}
+	}
+	patchBase := client.MergeFrom(cr.DeepCopy())
+	cc.setClusterCondition(marklogicv1.OperatorUserReady, status, reason, message)
+	return patchStatus(cc.Ctx, cc.Client, cr, patchBase)
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"errors"
+	"testing"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestReconcileOperatorUserIsProvisionedByDefault(t *testing.T) {
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			ClusterDomain:   "cluster.local",
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{{Name: "dnode", IsBootstrap: true}},
+		},
+	}
+	operatorSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml-manage-admin", Namespace: "default"},
+		Data:       map[string][]byte{"username": []byte("ml-manage-admin"), "password": []byte("s3cret")},
+	}
+	cc := newUpgradeTestContext(t, cr, operatorSecret)
+	var users []string
+	ensureErr := errors.New("connection refused")
+	original := NewDynamicManagementClient
+	NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+		users = append(users, opts.Username)
+		return &stubDynamicManagementClient{ensureUserFn: func(username, password string) error {
+			if username != "ml-manage-admin" || password != "s3cret" {
+				t.Fatalf("unexpected operator user %s", username)
+			}
+			return ensureErr
+		}}
+	}
+	t.Cleanup(func() { NewDynamicManagementClient = original })
+
+	if res := cc.ReconcileOperatorUser(); res.Completed() {
+		t.Fatalf("expected the reconcile to go on while the bootstrap host is unavailable")
+	}
+	if cr.Status.GetConditionStatus(string(marklogicv1.OperatorUserReady)) != metav1.ConditionFalse {
+		t.Fatalf("expected the operator user to be pending, got %+v", cr.Status.Conditions)
+	}
+	if nextOperatorUserRetry(cr).IsZero() {
+		t.Fatalf("expected a retry of the pending operator user")
+	}
+	if _, err := cc.newBootstrapManagementClient(); err != nil || users[len(users)-1] != "admin" {
+		t.Fatalf("expected the admin credentials until the user is provisioned, got %v %v", users, err)
+	}
+
+	ensureErr = nil
+	if res := cc.ReconcileOperatorUser(); res.Completed() {
+		t.Fatalf("expected the provisioned operator user to continue")
+	}
+	if cr.Status.GetConditionStatus(string(marklogicv1.OperatorUserReady)) != metav1.ConditionTrue {
+		t.Fatalf("expected the operator user to be ready, got %+v", cr.Status.Conditions)
+	}
+	if !nextOperatorUserRetry(cr).IsZero() {
+		t.Fatalf("expected no retry once the operator user is provisioned")
+	}
+	if _, err := cc.newBootstrapManagementClient(); err != nil || users[len(users)-1] != "ml-manage-admin" {
+		t.Fatalf("expected the operator user for Manage API calls, got %v %v", users, err)
+	}
+	if _, err := cc.newBootstrapAdminClient(); err != nil || users[len(users)-1] != "admin" {
+		t.Fatalf("expected the admin credentials for admin calls, got %v %v", users, err)
+	}
+
+	calls := len(users)
+	if res := cc.ReconcileOperatorUser(); res.Completed() || len(users) != calls {
+		t.Fatalf("expected a provisioned user not to be reconciled again")
+	}
+
+	disabled := false
+	cr.Spec.Auth = &marklogicv1.AdminAuth{OperatorUser: &disabled}
+	if _, err := cc.newBootstrapManagementClient(); err != nil || users[len(users)-1] != "admin" {
+		t.Fatalf("expected the admin credentials when the operator user is disabled, got %v %v", users, err)
+	}
+}
+
+func TestReconcileOperatorUserWaitsForItsSecret(t *testing.T) {
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{{Name: "dnode", IsBootstrap: true}},
+		},
+	}
+	cc := newUpgradeTestContext(t, cr)
+
+	if res := cc.ReconcileOperatorUser(); res.Completed() {
+		t.Fatalf("expected the reconcile to go on without the operator user Secret")
+	}
+	if cr.Status.GetConditionStatus(string(marklogicv1.OperatorUserReady)) != metav1.ConditionFalse || nextOperatorUserRetry(cr).IsZero() {
+		t.Fatalf("expected the operator user to be pending, got %+v", cr.Status.Conditions)
+	}
+}
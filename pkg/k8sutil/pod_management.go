@@ -0,0 +1,71 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"fmt"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const statefulSetReasonRecreated = "StatefulSetRecreated"
+
+// statefulSetPodManagementPolicy maps the pod management policy of a group to
+// the policy of its StatefulSet. BootstrapFirst runs a Parallel StatefulSet
+// whose replicas are held at one until pod 0 is ready.
+func statefulSetPodManagementPolicy(policy marklogicv1.PodManagementPolicy) appsv1.PodManagementPolicyType {
+	if policy == marklogicv1.PodManagementOrderedReady {
+		return appsv1.OrderedReadyPodManagement
+	}
+	return appsv1.ParallelPodManagement
+}
+
+// firstPodPending reports whether a group with the BootstrapFirst policy
+// still waits for pod 0: the StatefulSet runs at most one pod, no other pod
+// of the group exists and pod 0 is not ready. Groups that already run more
+// pods are never held back, so a restart of pod 0 does not scale them down.
+func (oc *OperatorContext) firstPodPending(currentSts *appsv1.StatefulSet) (bool, error) {
+	cr := oc.MarklogicGroup
+	if cr.Spec.PodManagementPolicy != marklogicv1.PodManagementBootstrapFirst || cr.Spec.Replicas == nil || *cr.Spec.Replicas <= 1 {
+		return false, nil
+	}
+	if currentSts != nil && currentSts.Spec.Replicas != nil && *currentSts.Spec.Replicas > 1 {
+		return false, nil
+	}
+	pods := &corev1.PodList{}
+	if err := oc.Client.List(oc.Ctx, pods, client.InNamespace(cr.Namespace), client.MatchingLabels(getSelectorLabelsByComponent(cr.Spec.Name, cr.Spec.IsDynamic))); err != nil {
+		return false, err
+	}
+	firstPod := fmt.Sprintf("%s-0", cr.Spec.Name)
+	ready := false
+	for i := range pods.Items {
+		if pods.Items[i].Name != firstPod {
+			return false, nil
+		}
+		ready = isPodLocallyReady(&pods.Items[i])
+	}
+	return !ready, nil
+}
+
+// recreateStatefulSet deletes a StatefulSet whose pod management policy
+// changed, which Kubernetes does not allow to update, keeping its pods and
+// volumes. The next reconcile creates it again and it adopts the pods.
+func (oc *OperatorContext) recreateStatefulSet(currentSts *appsv1.StatefulSet, policy appsv1.PodManagementPolicyType) result.ReconcileResult {
+	if currentSts.DeletionTimestamp != nil {
+		return result.RequeueSoon(2)
+	}
+	orphan := metav1.DeletePropagationOrphan
+	if err := oc.Client.Delete(oc.Ctx, currentSts, &client.DeleteOptions{PropagationPolicy: &orphan}); err != nil && !apierrors.IsNotFound(err) {
+		return result.Error(err)
+	}
+	oc.ReqLogger.Info("Recreating the statefulSet for its new pod management policy", "from", currentSts.Spec.PodManagementPolicy, "to", policy)
+	oc.Recorder.Event(oc.MarklogicGroup, corev1.EventTypeNormal, statefulSetReasonRecreated,
+		fmt.Sprintf("statefulSet %s is recreated with pod management policy %s, its pods keep running", currentSts.Name, policy))
+	return result.RequeueSoon(2)
+}
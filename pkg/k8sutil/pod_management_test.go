@@ -0,0 +1,95 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"testing"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func newGroupTestPod(name string, ready bool) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: getSelectorLabelsByComponent("dnode", false)},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	if ready {
+		pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+	}
+	return pod
+}
+
+func TestStatefulSetPodManagementPolicy(t *testing.T) {
+	for policy, expected := range map[marklogicv1.PodManagementPolicy]appsv1.PodManagementPolicyType{
+		"":                                      appsv1.ParallelPodManagement,
+		marklogicv1.PodManagementParallel:       appsv1.ParallelPodManagement,
+		marklogicv1.PodManagementOrderedReady:   appsv1.OrderedReadyPodManagement,
+		marklogicv1.PodManagementBootstrapFirst: appsv1.ParallelPodManagement,
+	} {
+		if got := statefulSetPodManagementPolicy(policy); got != expected {
+			t.Fatalf("expected %q for policy %q, got %q", expected, policy, got)
+		}
+	}
+}
+
+func TestFirstPodPendingHoldsBootstrapFirstGroups(t *testing.T) {
+	group := newSecretRotationTestGroup()
+	group.Spec.PodManagementPolicy = marklogicv1.PodManagementBootstrapFirst
+
+	oc := newSecretRotationTestContext(t, group)
+	if pending, err := oc.firstPodPending(nil); err != nil || !pending {
+		t.Fatalf("expected a new group to wait for pod 0, got %v, %v", pending, err)
+	}
+
+	oc = newSecretRotationTestContext(t, group, newGroupTestPod("dnode-0", false))
+	if pending, err := oc.firstPodPending(nil); err != nil || !pending {
+		t.Fatalf("expected the group to wait while pod 0 is not ready, got %v, %v", pending, err)
+	}
+
+	oc = newSecretRotationTestContext(t, group, newGroupTestPod("dnode-0", true))
+	if pending, err := oc.firstPodPending(nil); err != nil || pending {
+		t.Fatalf("expected the group to start once pod 0 is ready, got %v, %v", pending, err)
+	}
+
+	// A restart of pod 0 must not scale down a group that already runs more pods.
+	oc = newSecretRotationTestContext(t, group, newGroupTestPod("dnode-0", false), newGroupTestPod("dnode-1", true))
+	if pending, err := oc.firstPodPending(nil); err != nil || pending {
+		t.Fatalf("expected a running group not to be held, got %v, %v", pending, err)
+	}
+	replicas := int32(2)
+	sts := &appsv1.StatefulSet{Spec: appsv1.StatefulSetSpec{Replicas: &replicas}}
+	oc = newSecretRotationTestContext(t, group, newGroupTestPod("dnode-0", false))
+	if pending, err := oc.firstPodPending(sts); err != nil || pending {
+		t.Fatalf("expected a scaled statefulSet not to be held, got %v, %v", pending, err)
+	}
+
+	group.Spec.PodManagementPolicy = marklogicv1.PodManagementParallel
+	oc = newSecretRotationTestContext(t, group)
+	if pending, err := oc.firstPodPending(nil); err != nil || pending {
+		t.Fatalf("expected a Parallel group not to be held, got %v, %v", pending, err)
+	}
+}
+
+func TestRecreateStatefulSetKeepsPods(t *testing.T) {
+	sts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "dnode", Namespace: "default"},
+		Spec:       appsv1.StatefulSetSpec{PodManagementPolicy: appsv1.ParallelPodManagement},
+	}
+	pod := newGroupTestPod("dnode-0", true)
+	oc := newSecretRotationTestContext(t, newSecretRotationTestGroup(), sts, pod)
+
+	if res := oc.recreateStatefulSet(sts, appsv1.OrderedReadyPodManagement); !res.Completed() {
+		t.Fatalf("expected the reconcile to requeue after deleting the statefulSet")
+	}
+	if err := oc.Client.Get(oc.Ctx, client.ObjectKeyFromObject(sts), &appsv1.StatefulSet{}); !apierrors.IsNotFound(err) {
+		t.Fatalf("expected the statefulSet to be deleted, got %v", err)
+	}
+	if err := oc.Client.Get(oc.Ctx, client.ObjectKeyFromObject(pod), pod); err != nil {
+		t.Fatalf("expected the pod to keep running, got %v", err)
+	}
+}
@@ -0,0 +1,264 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// defaultStuckPodThreshold is how long a pod crash-loops or is not ready
+	// before it is stuck, unless spec.podRemediation.threshold says otherwise.
+	defaultStuckPodThreshold = 10 * time.Minute
+
+	// stuckPodLogLimit caps the end of the ErrorLog recorded in the event of
+	// a stuck pod.
+	stuckPodLogLimit = 768
+
+	stuckPodReasonCrashLoopBackOff = "CrashLoopBackOff"
+	stuckPodReasonNotReady         = "NotReady"
+	stuckPodReasonNone             = "NoPodsStuck"
+
+	stuckPodReasonDetected  = "StuckPodDetected"
+	stuckPodReasonRestarted = "StuckPodRestarted"
+	stuckPodReasonRecreated = "StuckPodRecreated"
+)
+
+// stuckPodsReported holds the UIDs of the stuck pods of each cluster that
+// were reported, so every pod is reported once.
+var stuckPodsReported sync.Map
+
+// stuckPodChecks holds when the pods of each cluster that are not ready
+// become stuck, see nextStuckPodCheck.
+var stuckPodChecks sync.Map
+
+// stuckPod is a pod stuck for longer than the threshold of its group.
+type stuckPod struct {
+	pod         corev1.Pod
+	reason      string
+	since       time.Time
+	remediation marklogicv1.PodRemediation
+}
+
+// ReconcileStuckPods finds the pods of groups with a pod remediation that
+// crash-loop or are not ready for longer than its threshold. Every stuck pod
+// is reported once with a Warning event holding the end of its ErrorLog, then
+// the policy of its group is applied: none leaves it alone, restart deletes it
+// so its StatefulSet starts it again and recreate-with-alert force deletes it
+// with another Warning event. The PodsStuck condition lists the stuck pods.
+// Pods are left alone while the cluster is stopped or a workflow holds the
+// rollout lock. Failures are logged and never hold up the rest of the
+// reconcile.
+func (cc *ClusterContext) ReconcileStuckPods() result.ReconcileResult {
+	cr := cc.MarklogicCluster
+	key := types.NamespacedName{Namespace: cr.Namespace, Name: cr.Name}
+	now := time.Now()
+	next := time.Time{}
+	stuck := []stuckPod{}
+	for _, group := range cr.Spec.MarkLogicGroups {
+		if group == nil {
+			continue
+		}
+		remediation, ok := groupPodRemediation(cr, group)
+		if !ok {
+			continue
+		}
+		pods := &corev1.PodList{}
+		if err := cc.Client.List(cc.Ctx, pods, client.InNamespace(cr.Namespace), client.MatchingLabels{
+			"app.kubernetes.io/name":     "marklogic",
+			"app.kubernetes.io/instance": group.Name,
+		}); err != nil {
+			cc.ReqLogger.Error(err, "Failed to list the pods for stuck pod detection", "group", group.Name)
+			return result.Continue()
+		}
+		for _, pod := range pods.Items {
+			reason, since, ok := podNotReady(&pod)
+			if !ok {
+				continue
+			}
+			if due := since.Add(remediation.Threshold.Duration); due.After(now) {
+				if next.IsZero() || due.Before(next) {
+					next = due
+				}
+				continue
+			}
+			stuck = append(stuck, stuckPod{pod: pod, reason: reason, since: since, remediation: remediation})
+		}
+	}
+	sort.Slice(stuck, func(i, j int) bool { return stuck[i].pod.Name < stuck[j].pod.Name })
+	if next.IsZero() {
+		stuckPodChecks.Delete(key)
+	} else {
+		stuckPodChecks.Store(key, next)
+	}
+
+	reported := map[types.UID]bool{}
+	if last, ok := stuckPodsReported.Load(key); ok {
+		reported = last.(map[types.UID]bool)
+	}
+	current := map[types.UID]bool{}
+	act := !clusterStopped(cr) && rolloutLockHolder(cr) == ""
+	descriptions := []string{}
+	for _, s := range stuck {
+		description := fmt.Sprintf("%s: %s since %s", s.pod.Name, s.reason, s.since.UTC().Format(time.RFC3339))
+		descriptions = append(descriptions, description)
+		current[s.pod.UID] = true
+		if !reported[s.pod.UID] {
+			cc.ReqLogger.Info("Detected a stuck pod", "pod", s.pod.Name, "reason", s.reason, "policy", s.remediation.Policy)
+			cc.recordClusterEvent(corev1.EventTypeWarning, stuckPodReasonDetected,
+				fmt.Sprintf("pod %s is stuck in %s since %s, policy %s. ErrorLog: %s", s.pod.Name, s.reason,
+					s.since.UTC().Format(time.RFC3339), s.remediation.Policy, cc.stuckPodErrorLog(s.pod.Name)))
+		}
+		if act {
+			cc.remediateStuckPod(s)
+		}
+	}
+	if len(current) > 0 {
+		stuckPodsReported.Store(key, current)
+	} else {
+		stuckPodsReported.Delete(key)
+	}
+
+	if len(stuck) == 0 && cr.Status.GetConditionStatus(string(marklogicv1.PodsStuck)) == metav1.ConditionUnknown {
+		return result.Continue()
+	}
+	condition := metav1.Condition{
+		Type:               string(marklogicv1.PodsStuck),
+		Status:             metav1.ConditionFalse,
+		Reason:             stuckPodReasonNone,
+		Message:            "no pod is stuck",
+		ObservedGeneration: cr.Generation,
+		LastTransitionTime: metav1.Now(),
+	}
+	if len(stuck) > 0 {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = stuck[0].reason
+		condition.Message = fmt.Sprintf("%d pod(s) stuck: %s", len(stuck), strings.Join(descriptions, "; "))
+	}
+	if conditionUnchanged(cr.Status.Conditions, condition) {
+		return result.Continue()
+	}
+	for _, existing := range cr.Status.Conditions {
+		if existing.Type == condition.Type && existing.Status == condition.Status {
+			condition.LastTransitionTime = existing.LastTransitionTime
+		}
+	}
+	patchBase := client.MergeFrom(cr.DeepCopy())
+	cr.Status.SetCondition(condition)
+	if err := patchStatus(cc.Ctx, cc.Client, cr, patchBase); err != nil {
+		cc.ReqLogger.Error(err, "Failed to update the PodsStuck condition")
+	}
+	return result.Continue()
+}
+
+// remediateStuckPod applies the policy of the group of a stuck pod.
+func (cc *ClusterContext) remediateStuckPod(s stuckPod) {
+	var opts []client.DeleteOption
+	eventType, reason, action := corev1.EventTypeNormal, stuckPodReasonRestarted, "restarted"
+	switch s.remediation.Policy {
+	case marklogicv1.PodRemediationRestart:
+	case marklogicv1.PodRemediationRecreateWithAlert:
+		opts = append(opts, client.GracePeriodSeconds(0))
+		eventType, reason, action = corev1.EventTypeWarning, stuckPodReasonRecreated, "force deleted"
+	default:
+		return
+	}
+	pod := s.pod
+	if err := cc.Client.Delete(cc.Ctx, &pod, opts...); err != nil {
+		if !apierrors.IsNotFound(err) {
+			cc.ReqLogger.Error(err, "Failed to remediate the stuck pod", "pod", pod.Name, "policy", s.remediation.Policy)
+		}
+		return
+	}
+	cc.ReqLogger.Info("Remediated the stuck pod", "pod", pod.Name, "policy", s.remediation.Policy)
+	cc.recordClusterEvent(eventType, reason, fmt.Sprintf("%s pod %s, stuck in %s since %s, so its StatefulSet starts it again",
+		action, pod.Name, s.reason, s.since.UTC().Format(time.RFC3339)))
+}
+
+// stuckPodErrorLog returns the end of the ErrorLog of a pod, read from the
+// log directory of MarkLogic or, when the server does not run long enough to
+// exec into it, from the log of its container.
+func (cc *ClusterContext) stuckPodErrorLog(podName string) string {
+	var tail string
+	if logs, err := cc.marklogicErrorLogs(podName, stuckPodLogLimit); err == nil && logs["ErrorLog.txt"] != "" {
+		tail = logs["ErrorLog.txt"]
+	} else if output, err := PodLogs(cc.Ctx, cc.MarklogicCluster.Namespace, podName, "marklogic-server", stuckPodLogLimit); err == nil {
+		tail = output
+	} else {
+		cc.ReqLogger.Error(err, "Failed to read the ErrorLog of the stuck pod", "pod", podName)
+		return "unavailable"
+	}
+	if len(tail) > stuckPodLogLimit {
+		tail = tail[len(tail)-stuckPodLogLimit:]
+	}
+	tail = strings.TrimSpace(tail)
+	if tail == "" {
+		return "empty"
+	}
+	return tail
+}
+
+// groupPodRemediation returns the pod remediation of a group with its
+// defaults, and whether the group has one.
+func groupPodRemediation(cr *marklogicv1.MarklogicCluster, group *marklogicv1.MarklogicGroups) (marklogicv1.PodRemediation, bool) {
+	remediation := cr.Spec.PodRemediation
+	if group.PodRemediation != nil {
+		remediation = group.PodRemediation
+	}
+	if remediation == nil {
+		return marklogicv1.PodRemediation{}, false
+	}
+	merged := *remediation
+	if merged.Policy == "" {
+		merged.Policy = marklogicv1.PodRemediationNone
+	}
+	if merged.Threshold == nil || merged.Threshold.Duration <= 0 {
+		merged.Threshold = &metav1.Duration{Duration: defaultStuckPodThreshold}
+	}
+	return merged, true
+}
+
+// podNotReady returns why a scheduled pod that is not being deleted is not
+// ready and since when, and whether it is not ready.
+func podNotReady(pod *corev1.Pod) (string, time.Time, bool) {
+	if pod.DeletionTimestamp != nil || pod.Spec.NodeName == "" {
+		return "", time.Time{}, false
+	}
+	var ready *corev1.PodCondition
+	for i := range pod.Status.Conditions {
+		if pod.Status.Conditions[i].Type == corev1.PodReady {
+			ready = &pod.Status.Conditions[i]
+		}
+	}
+	if ready == nil || ready.Status == corev1.ConditionTrue {
+		return "", time.Time{}, false
+	}
+	reason := stuckPodReasonNotReady
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == "marklogic-server" && status.State.Waiting != nil && status.State.Waiting.Reason == stuckPodReasonCrashLoopBackOff {
+			reason = stuckPodReasonCrashLoopBackOff
+		}
+	}
+	return reason, ready.LastTransitionTime.Time, true
+}
+
+// nextStuckPodCheck is when the next pod that is not ready becomes stuck.
+// Pods are not watched by the cluster, so it would not be noticed otherwise.
+func nextStuckPodCheck(cr *marklogicv1.MarklogicCluster) time.Time {
+	if next, ok := stuckPodChecks.Load(types.NamespacedName{Namespace: cr.Namespace, Name: cr.Name}); ok {
+		return next.(time.Time)
+	}
+	return time.Time{}
+}
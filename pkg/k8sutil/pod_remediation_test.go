@@ -0,0 +1,105 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestReconcileStuckPodsRemediatesStuckPods(t *testing.T) {
+	replicas := int32(3)
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			PodRemediation:  &marklogicv1.PodRemediation{Policy: marklogicv1.PodRemediationNone},
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{{Name: "dnode", Replicas: &replicas}},
+		},
+	}
+	notReadyPod := func(name string, since time.Duration, crashLooping bool) *corev1.Pod {
+		pod := newStorageTestPod(name)
+		pod.UID = types.UID(name + "-uid")
+		pod.Spec.NodeName = "node-a"
+		pod.Status.Conditions = []corev1.PodCondition{{
+			Type: corev1.PodReady, Status: corev1.ConditionFalse, LastTransitionTime: metav1.NewTime(time.Now().Add(-since)),
+		}}
+		if crashLooping {
+			pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+				Name: "marklogic-server", State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+			}}
+		}
+		return pod
+	}
+	cc := newUpgradeTestContext(t, cr, notReadyPod("dnode-0", 20*time.Minute, true), notReadyPod("dnode-1", time.Minute, false))
+	recorder := cc.Recorder.(*record.FakeRecorder)
+	originalExec, originalLogs := ExecInPod, PodLogs
+	ExecInPod = func(ctx context.Context, namespace, podName, container string, command []string) (string, error) {
+		return "", errors.New("container not running")
+	}
+	PodLogs = func(ctx context.Context, namespace, podName, container string, limitBytes int64) (string, error) {
+		return "Alert: XDMP-BADLICENSE\n", nil
+	}
+	t.Cleanup(func() {
+		ExecInPod, PodLogs = originalExec, originalLogs
+		stuckPodsReported.Delete(types.NamespacedName{Namespace: "default", Name: "ml"})
+		stuckPodChecks.Delete(types.NamespacedName{Namespace: "default", Name: "ml"})
+	})
+	events := func() []string {
+		drained := []string{}
+		for len(recorder.Events) > 0 {
+			drained = append(drained, <-recorder.Events)
+		}
+		return drained
+	}
+
+	// The policy none only reports the stuck pod, once.
+	if res := cc.ReconcileStuckPods(); res.Completed() {
+		t.Fatalf("expected the stuck pod detection never to hold up the reconcile")
+	}
+	condition := meta.FindStatusCondition(cr.Status.Conditions, string(marklogicv1.PodsStuck))
+	if condition == nil || condition.Status != metav1.ConditionTrue || condition.Reason != stuckPodReasonCrashLoopBackOff ||
+		!strings.Contains(condition.Message, "dnode-0") || strings.Contains(condition.Message, "dnode-1") {
+		t.Fatalf("expected only dnode-0 to be stuck, got %+v", condition)
+	}
+	if got := events(); len(got) != 1 || !strings.Contains(got[0], stuckPodReasonDetected) || !strings.Contains(got[0], "XDMP-BADLICENSE") {
+		t.Fatalf("expected a %s event with the ErrorLog, got %v", stuckPodReasonDetected, got)
+	}
+	if next := nextStuckPodCheck(cr); next.IsZero() || time.Until(next) > 10*time.Minute {
+		t.Fatalf("expected a check when dnode-1 becomes stuck, got %v", next)
+	}
+	cc.ReconcileStuckPods()
+	if got := events(); len(got) != 0 {
+		t.Fatalf("expected the stuck pod to be reported once, got %v", got)
+	}
+	if err := cc.Client.Get(cc.Ctx, types.NamespacedName{Namespace: "default", Name: "dnode-0"}, &corev1.Pod{}); err != nil {
+		t.Fatalf("expected the policy none to leave the pod alone, got %v", err)
+	}
+
+	// The restart policy deletes the stuck pod.
+	cr.Spec.PodRemediation.Policy = marklogicv1.PodRemediationRestart
+	cc.ReconcileStuckPods()
+	err := cc.Client.Get(cc.Ctx, types.NamespacedName{Namespace: "default", Name: "dnode-0"}, &corev1.Pod{})
+	if !apierrors.IsNotFound(err) {
+		t.Fatalf("expected the stuck pod to be restarted, got %v", err)
+	}
+	if got := events(); len(got) != 1 || !strings.Contains(got[0], stuckPodReasonRestarted) {
+		t.Fatalf("expected a %s event, got %v", stuckPodReasonRestarted, got)
+	}
+
+	// The condition clears once no pod is stuck.
+	cc.ReconcileStuckPods()
+	if status := cr.Status.GetConditionStatus(string(marklogicv1.PodsStuck)); status != metav1.ConditionFalse {
+		t.Fatalf("expected no pod to be stuck, got %s", status)
+	}
+}
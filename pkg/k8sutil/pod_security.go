@@ -0,0 +1,160 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const podSecurityReasonViolation = "PodSecurityViolation"
+
+// restrictedVolumeType reports whether a volume is of a type the restricted
+// Pod Security Standard allows.
+func restrictedVolumeType(volume corev1.Volume) bool {
+	source := volume.VolumeSource
+	return source.ConfigMap != nil || source.CSI != nil || source.DownwardAPI != nil || source.EmptyDir != nil ||
+		source.Ephemeral != nil || source.PersistentVolumeClaim != nil || source.Projected != nil || source.Secret != nil
+}
+
+// restrictPodSpec adapts a generated pod to the restricted Pod Security
+// Standard: the pod runs as non-root with the RuntimeDefault seccomp profile
+// and every container runs unprivileged, without privilege escalation and
+// with all capabilities but NET_BIND_SERVICE dropped. Root user IDs are
+// dropped so the non-root user of the image applies. The fluent-bit sidecar
+// gets a read-only root filesystem; MarkLogic, HAProxy and the copy-certs
+// init container write to theirs unless readOnlyRootFilesystem is set.
+func restrictPodSpec(spec *corev1.PodSpec) {
+	pod := &corev1.PodSecurityContext{}
+	if spec.SecurityContext != nil {
+		pod = spec.SecurityContext.DeepCopy()
+	}
+	if pod.RunAsUser != nil && *pod.RunAsUser == 0 {
+		pod.RunAsUser = nil
+	}
+	pod.RunAsNonRoot = boolPtr(true)
+	if pod.SeccompProfile == nil || pod.SeccompProfile.Type == corev1.SeccompProfileTypeUnconfined {
+		pod.SeccompProfile = &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault}
+	}
+	spec.SecurityContext = pod
+	for i := range spec.InitContainers {
+		spec.InitContainers[i].SecurityContext = restrictedSecurityContext(spec.InitContainers[i].SecurityContext, false)
+	}
+	for i := range spec.Containers {
+		readOnly := isLogCollectorContainer(spec.Containers[i].Name)
+		spec.Containers[i].SecurityContext = restrictedSecurityContext(spec.Containers[i].SecurityContext, readOnly)
+	}
+}
+
+func restrictedSecurityContext(base *corev1.SecurityContext, readOnlyRootFilesystem bool) *corev1.SecurityContext {
+	sc := &corev1.SecurityContext{}
+	if base != nil {
+		sc = base.DeepCopy()
+	}
+	if sc.RunAsUser != nil && *sc.RunAsUser == 0 {
+		sc.RunAsUser = nil
+	}
+	sc.RunAsNonRoot = boolPtr(true)
+	sc.Privileged = nil
+	sc.AllowPrivilegeEscalation = boolPtr(false)
+	capabilities := &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}}
+	if sc.Capabilities != nil && slices.Contains(sc.Capabilities.Add, "NET_BIND_SERVICE") {
+		capabilities.Add = []corev1.Capability{"NET_BIND_SERVICE"}
+	}
+	sc.Capabilities = capabilities
+	if sc.SeccompProfile != nil && sc.SeccompProfile.Type == corev1.SeccompProfileTypeUnconfined {
+		sc.SeccompProfile = nil
+	}
+	if readOnlyRootFilesystem {
+		sc.ReadOnlyRootFilesystem = boolPtr(true)
+	}
+	return sc
+}
+
+// restrictedPodSecurityViolations audits a pod against the restricted Pod
+// Security Standard and returns what it violates, such as hostPath volumes
+// added through additionalVolumes, which restrictPodSpec cannot fix.
+func restrictedPodSecurityViolations(spec *corev1.PodSpec) []string {
+	violations := []string{}
+	if spec.HostNetwork || spec.HostPID || spec.HostIPC {
+		violations = append(violations, "host namespaces are used")
+	}
+	for _, volume := range spec.Volumes {
+		if !restrictedVolumeType(volume) {
+			violations = append(violations, fmt.Sprintf("volume %s is of a type that is not allowed", volume.Name))
+		}
+	}
+	pod := spec.SecurityContext
+	if pod == nil {
+		pod = &corev1.PodSecurityContext{}
+	}
+	containers := append(append([]corev1.Container{}, spec.InitContainers...), spec.Containers...)
+	for _, container := range containers {
+		sc := container.SecurityContext
+		if sc == nil {
+			sc = &corev1.SecurityContext{}
+		}
+		problems := []string{}
+		if sc.Privileged != nil && *sc.Privileged {
+			problems = append(problems, "runs privileged")
+		}
+		if sc.AllowPrivilegeEscalation == nil || *sc.AllowPrivilegeEscalation {
+			problems = append(problems, "allows privilege escalation")
+		}
+		if sc.Capabilities == nil || !slices.Contains(sc.Capabilities.Drop, "ALL") {
+			problems = append(problems, "does not drop all capabilities")
+		} else if slices.ContainsFunc(sc.Capabilities.Add, func(c corev1.Capability) bool { return c != "NET_BIND_SERVICE" }) {
+			problems = append(problems, "adds capabilities other than NET_BIND_SERVICE")
+		}
+		runAsNonRoot := sc.RunAsNonRoot
+		if runAsNonRoot == nil {
+			runAsNonRoot = pod.RunAsNonRoot
+		}
+		if runAsNonRoot == nil || !*runAsNonRoot {
+			problems = append(problems, "may run as root")
+		}
+		runAsUser := sc.RunAsUser
+		if runAsUser == nil {
+			runAsUser = pod.RunAsUser
+		}
+		if runAsUser != nil && *runAsUser == 0 {
+			problems = append(problems, "runs as user 0")
+		}
+		seccomp := sc.SeccompProfile
+		if seccomp == nil {
+			seccomp = pod.SeccompProfile
+		}
+		if seccomp == nil || (seccomp.Type != corev1.SeccompProfileTypeRuntimeDefault && seccomp.Type != corev1.SeccompProfileTypeLocalhost) {
+			problems = append(problems, "has no RuntimeDefault or Localhost seccomp profile")
+		}
+		for _, port := range container.Ports {
+			if port.HostPort != 0 {
+				problems = append(problems, "uses a host port")
+				break
+			}
+		}
+		for _, problem := range problems {
+			violations = append(violations, fmt.Sprintf("container %s %s", container.Name, problem))
+		}
+	}
+	return violations
+}
+
+// recordPodSecurityViolations records a Warning event when the pods of a
+// group with restrictedPodSecurity still violate the restricted Pod Security
+// Standard, so they are not rejected by the namespace without notice.
+func (oc *OperatorContext) recordPodSecurityViolations(statefulSet *appsv1.StatefulSet) {
+	if !oc.MarklogicGroup.Spec.RestrictedPodSecurity {
+		return
+	}
+	violations := restrictedPodSecurityViolations(&statefulSet.Spec.Template.Spec)
+	if len(violations) == 0 {
+		return
+	}
+	oc.Recorder.Event(oc.MarklogicGroup, corev1.EventTypeWarning, podSecurityReasonViolation,
+		fmt.Sprintf("the pods do not meet the restricted Pod Security Standard: %s", strings.Join(violations, "; ")))
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"strings"
+	"testing"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRestrictedPodSecurityStatefulSet(t *testing.T) {
+	root := int64(0)
+	group := &marklogicv1.MarklogicGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "dnode", Namespace: "default"},
+		Spec: marklogicv1.MarklogicGroupSpec{
+			Name:  "dnode",
+			Image: "progressofficial/marklogic-db:12.0.3-ubi9-rootless-2.2.6",
+			// The defaults of the MarklogicGroup CRD.
+			PodSecurityContext:       &corev1.PodSecurityContext{FSGroup: int64Ptr(2), RunAsUser: &root},
+			ContainerSecurityContext: &corev1.SecurityContext{RunAsUser: int64Ptr(1000), RunAsNonRoot: boolPtr(true), AllowPrivilegeEscalation: boolPtr(false)},
+			HugePages:                &marklogicv1.HugePages{},
+			LogCollection:            &marklogicv1.LogCollection{Enabled: true, SecurityContext: &corev1.SecurityContext{ReadOnlyRootFilesystem: boolPtr(false)}},
+			Tls:                      &marklogicv1.Tls{EnableOnDefaultAppServers: true},
+		},
+	}
+	generate := func() *corev1.PodSpec {
+		sts := generateStatefulSetsDef(metav1.ObjectMeta{Name: "dnode", Namespace: "default"}, generateStatefulSetsParams(group),
+			metav1.OwnerReference{}, generateContainerParams(group))
+		return &sts.Spec.Template.Spec
+	}
+
+	if violations := restrictedPodSecurityViolations(generate()); len(violations) == 0 {
+		t.Fatalf("expected the default pods to violate the restricted standard")
+	}
+
+	group.Spec.RestrictedPodSecurity = true
+	spec := generate()
+	if len(spec.InitContainers) != 1 || len(spec.Containers) != 2 {
+		t.Fatalf("expected the copy-certs init container and the fluent-bit sidecar, got %d and %d", len(spec.InitContainers), len(spec.Containers))
+	}
+	if violations := restrictedPodSecurityViolations(spec); len(violations) != 0 {
+		t.Fatalf("expected the pods to meet the restricted standard, got %v", violations)
+	}
+	if spec.SecurityContext.RunAsUser != nil || *spec.SecurityContext.FSGroup != 2 {
+		t.Fatalf("expected only the root user to be dropped, got %+v", spec.SecurityContext)
+	}
+	if fluentBit := spec.Containers[1].SecurityContext; !*fluentBit.ReadOnlyRootFilesystem {
+		t.Fatalf("expected fluent-bit to run with a read-only root filesystem")
+	}
+
+	group.Spec.AdditionalVolumes = &[]corev1.Volume{{Name: "host", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/var/log"}}}}
+	if violations := restrictedPodSecurityViolations(generate()); len(violations) != 1 || !strings.Contains(violations[0], "volume host") {
+		t.Fatalf("expected the hostPath volume to be reported, got %v", violations)
+	}
+}
@@ -0,0 +1,194 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+)
+
+// podTemplateChanged reports whether the desired pod template of a
+// StatefulSet differs from its current one in a way that changes the pods.
+// Both templates are compared in a canonical form: the fields the API server
+// defaults are filled in, lists whose order does not matter are sorted, and
+// empty and unset values are the same. A template that only differs in those
+// is kept, so the StatefulSet does not roll its pods.
+func podTemplateChanged(current, desired *corev1.PodTemplateSpec) bool {
+	return !equality.Semantic.DeepEqual(canonicalPodTemplate(current), canonicalPodTemplate(desired))
+}
+
+func canonicalPodTemplate(template *corev1.PodTemplateSpec) *corev1.PodTemplateSpec {
+	canonical := &corev1.PodTemplateSpec{}
+	canonical.Labels = template.Labels
+	canonical.Annotations = template.Annotations
+	spec := template.Spec.DeepCopy()
+	if spec.RestartPolicy == "" {
+		spec.RestartPolicy = corev1.RestartPolicyAlways
+	}
+	if spec.DNSPolicy == "" {
+		spec.DNSPolicy = corev1.DNSClusterFirst
+	}
+	if spec.SchedulerName == "" {
+		spec.SchedulerName = corev1.DefaultSchedulerName
+	}
+	if spec.TerminationGracePeriodSeconds == nil {
+		spec.TerminationGracePeriodSeconds = int64Ptr(corev1.DefaultTerminationGracePeriodSeconds)
+	}
+	if spec.SecurityContext == nil {
+		spec.SecurityContext = &corev1.PodSecurityContext{}
+	}
+	if spec.EnableServiceLinks == nil {
+		spec.EnableServiceLinks = boolPtr(corev1.DefaultEnableServiceLinks)
+	}
+	if spec.DeprecatedServiceAccount == "" {
+		spec.DeprecatedServiceAccount = spec.ServiceAccountName
+	}
+	for i := range spec.InitContainers {
+		canonicalContainer(&spec.InitContainers[i])
+	}
+	for i := range spec.Containers {
+		canonicalContainer(&spec.Containers[i])
+	}
+	for i := range spec.Volumes {
+		canonicalVolume(&spec.Volumes[i])
+	}
+	slices.SortFunc(spec.Volumes, func(a, b corev1.Volume) int { return strings.Compare(a.Name, b.Name) })
+	slices.SortFunc(spec.ImagePullSecrets, func(a, b corev1.LocalObjectReference) int { return strings.Compare(a.Name, b.Name) })
+	slices.SortFunc(spec.Tolerations, compareTolerations)
+	canonical.Spec = *spec
+	return canonical
+}
+
+// compareTolerations orders tolerations by their fields, with an unset
+// TolerationSeconds before any value.
+func compareTolerations(a, b corev1.Toleration) int {
+	seconds := func(t corev1.Toleration) int64 {
+		if t.TolerationSeconds == nil {
+			return math.MinInt64
+		}
+		return *t.TolerationSeconds
+	}
+	return cmp.Or(
+		strings.Compare(a.Key, b.Key),
+		strings.Compare(string(a.Operator), string(b.Operator)),
+		strings.Compare(a.Value, b.Value),
+		strings.Compare(string(a.Effect), string(b.Effect)),
+		cmp.Compare(seconds(a), seconds(b)),
+	)
+}
+
+// canonicalContainer fills in the defaults of a container. The order of its
+// environment variables is kept, as later variables can refer to earlier ones.
+func canonicalContainer(container *corev1.Container) {
+	if container.TerminationMessagePath == "" {
+		container.TerminationMessagePath = corev1.TerminationMessagePathDefault
+	}
+	if container.TerminationMessagePolicy == "" {
+		container.TerminationMessagePolicy = corev1.TerminationMessageReadFile
+	}
+	if container.ImagePullPolicy == "" {
+		container.ImagePullPolicy = defaultImagePullPolicy(container.Image)
+	}
+	for i := range container.Ports {
+		if container.Ports[i].Protocol == "" {
+			container.Ports[i].Protocol = corev1.ProtocolTCP
+		}
+	}
+	slices.SortFunc(container.Ports, func(a, b corev1.ContainerPort) int {
+		return cmp.Or(cmp.Compare(a.ContainerPort, b.ContainerPort), strings.Compare(string(a.Protocol), string(b.Protocol)))
+	})
+	for i := range container.Env {
+		if ref := container.Env[i].ValueFrom; ref != nil && ref.FieldRef != nil && ref.FieldRef.APIVersion == "" {
+			ref.FieldRef.APIVersion = "v1"
+		}
+	}
+	slices.SortFunc(container.VolumeMounts, func(a, b corev1.VolumeMount) int {
+		return cmp.Or(strings.Compare(a.MountPath, b.MountPath), strings.Compare(a.Name, b.Name))
+	})
+	canonicalProbe(container.LivenessProbe)
+	canonicalProbe(container.ReadinessProbe)
+	canonicalProbe(container.StartupProbe)
+	if container.Lifecycle != nil {
+		for _, handler := range []*corev1.LifecycleHandler{container.Lifecycle.PostStart, container.Lifecycle.PreStop} {
+			if handler != nil {
+				canonicalHTTPGet(handler.HTTPGet)
+			}
+		}
+	}
+}
+
+func canonicalProbe(probe *corev1.Probe) {
+	if probe == nil {
+		return
+	}
+	if probe.TimeoutSeconds == 0 {
+		probe.TimeoutSeconds = 1
+	}
+	if probe.PeriodSeconds == 0 {
+		probe.PeriodSeconds = 10
+	}
+	if probe.SuccessThreshold == 0 {
+		probe.SuccessThreshold = 1
+	}
+	if probe.FailureThreshold == 0 {
+		probe.FailureThreshold = 3
+	}
+	canonicalHTTPGet(probe.HTTPGet)
+}
+
+func canonicalHTTPGet(action *corev1.HTTPGetAction) {
+	if action == nil {
+		return
+	}
+	if action.Path == "" {
+		action.Path = "/"
+	}
+	if action.Scheme == "" {
+		action.Scheme = corev1.URISchemeHTTP
+	}
+}
+
+func canonicalVolume(volume *corev1.Volume) {
+	source := &volume.VolumeSource
+	switch {
+	case source.Secret != nil && source.Secret.DefaultMode == nil:
+		mode := corev1.SecretVolumeSourceDefaultMode
+		source.Secret.DefaultMode = &mode
+	case source.ConfigMap != nil && source.ConfigMap.DefaultMode == nil:
+		mode := corev1.ConfigMapVolumeSourceDefaultMode
+		source.ConfigMap.DefaultMode = &mode
+	case source.DownwardAPI != nil && source.DownwardAPI.DefaultMode == nil:
+		mode := corev1.DownwardAPIVolumeSourceDefaultMode
+		source.DownwardAPI.DefaultMode = &mode
+	case source.Projected != nil && source.Projected.DefaultMode == nil:
+		mode := corev1.ProjectedVolumeSourceDefaultMode
+		source.Projected.DefaultMode = &mode
+	case source.HostPath != nil && source.HostPath.Type == nil:
+		hostPathType := corev1.HostPathUnset
+		source.HostPath.Type = &hostPathType
+	case equality.Semantic.DeepEqual(*source, corev1.VolumeSource{}):
+		source.EmptyDir = &corev1.EmptyDirVolumeSource{}
+	}
+}
+
+// defaultImagePullPolicy is the pull policy the API server sets: Always for
+// an image without a tag or with the latest tag, IfNotPresent otherwise.
+func defaultImagePullPolicy(image string) corev1.PullPolicy {
+	if strings.Contains(image, "@") {
+		return corev1.PullIfNotPresent
+	}
+	name := image[strings.LastIndex(image, "/")+1:]
+	tag, tagged := "", false
+	if i := strings.LastIndex(name, ":"); i >= 0 {
+		tag, tagged = name[i+1:], true
+	}
+	if !tagged || tag == "latest" {
+		return corev1.PullAlways
+	}
+	return corev1.PullIfNotPresent
+}
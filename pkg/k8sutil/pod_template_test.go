@@ -0,0 +1,115 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"slices"
+	"testing"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPodTemplateChangedIgnoresDefaultsAndOrdering(t *testing.T) {
+	replicas := int32(3)
+	cr := &marklogicv1.MarklogicGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "dnode", Namespace: "default"},
+		Spec: marklogicv1.MarklogicGroupSpec{
+			Name:          "dnode",
+			Replicas:      &replicas,
+			Image:         "progressofficial/marklogic-db:11",
+			ClusterDomain: "cluster.local",
+			Resources: &corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+			},
+			NodeSelector:  map[string]string{"disk": "ssd"},
+			HugePages:     &marklogicv1.HugePages{},
+			LogCollection: &marklogicv1.LogCollection{},
+		},
+	}
+	meta := generateObjectMeta("dnode", "default", map[string]string{"app.kubernetes.io/name": "marklogic"}, nil)
+	desired := generateStatefulSetsDef(meta, generateStatefulSetsParams(cr), marklogicServerAsOwner(cr), generateContainerParams(cr)).Spec.Template
+
+	// The template as the API server stores it: defaulted and reordered.
+	current := desired.DeepCopy()
+	current.CreationTimestamp = metav1.Now()
+	current.Spec.DNSPolicy = corev1.DNSClusterFirst
+	current.Spec.RestartPolicy = corev1.RestartPolicyAlways
+	current.Spec.SchedulerName = corev1.DefaultSchedulerName
+	current.Spec.EnableServiceLinks = boolPtr(true)
+	current.Spec.SecurityContext = &corev1.PodSecurityContext{}
+	slices.Reverse(current.Spec.Volumes)
+	for i := range current.Spec.Containers {
+		container := &current.Spec.Containers[i]
+		container.TerminationMessagePath = corev1.TerminationMessagePathDefault
+		container.TerminationMessagePolicy = corev1.TerminationMessageReadFile
+		if container.ImagePullPolicy == "" {
+			container.ImagePullPolicy = corev1.PullIfNotPresent
+		}
+		slices.Reverse(container.VolumeMounts)
+		if container.LivenessProbe != nil {
+			container.LivenessProbe.SuccessThreshold = 1
+		}
+		if memory, ok := container.Resources.Limits[corev1.ResourceMemory]; ok {
+			container.Resources.Limits[corev1.ResourceMemory] = resource.MustParse("1024Mi")
+			if memory.Cmp(container.Resources.Limits[corev1.ResourceMemory]) != 0 {
+				t.Fatalf("expected the same memory limit")
+			}
+		}
+	}
+	if podTemplateChanged(current, &desired) {
+		t.Fatalf("expected the defaulted template to be unchanged")
+	}
+
+	changed := current.DeepCopy()
+	changed.Spec.Containers[0].Image = "progressofficial/marklogic-db:12"
+	if !podTemplateChanged(changed, &desired) {
+		t.Fatalf("expected a different image to change the template")
+	}
+	changed = current.DeepCopy()
+	changed.Spec.NodeSelector = nil
+	if !podTemplateChanged(changed, &desired) {
+		t.Fatalf("expected a removed node selector to change the template")
+	}
+	changed = current.DeepCopy()
+	changed.Spec.Containers[0].Resources.Limits[corev1.ResourceMemory] = resource.MustParse("2Gi")
+	if !podTemplateChanged(changed, &desired) {
+		t.Fatalf("expected a different memory limit to change the template")
+	}
+}
+
+func TestPodTemplateChangedIgnoresTolerationOrder(t *testing.T) {
+	seconds := func(value int64) *int64 { return &value }
+	tolerations := []corev1.Toleration{
+		{Key: "node.kubernetes.io/not-ready", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoExecute, TolerationSeconds: seconds(300)},
+		{Key: "node.kubernetes.io/unreachable", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoExecute, TolerationSeconds: seconds(300)},
+		{Key: "node.kubernetes.io/unreachable", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoExecute, TolerationSeconds: seconds(60)},
+		{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "marklogic", Effect: corev1.TaintEffectNoSchedule},
+	}
+	current := &corev1.PodTemplateSpec{Spec: corev1.PodSpec{Tolerations: tolerations}}
+	desired := current.DeepCopy()
+	slices.Reverse(desired.Spec.Tolerations)
+	if podTemplateChanged(current, desired) {
+		t.Fatalf("expected reordered tolerations not to change the template")
+	}
+	*desired.Spec.Tolerations[1].TolerationSeconds = 120
+	if !podTemplateChanged(current, desired) {
+		t.Fatalf("expected a changed tolerationSeconds to change the template")
+	}
+}
+
+func TestDefaultImagePullPolicy(t *testing.T) {
+	for image, want := range map[string]corev1.PullPolicy{
+		"progressofficial/marklogic-db":              corev1.PullAlways,
+		"progressofficial/marklogic-db:latest":       corev1.PullAlways,
+		"progressofficial/marklogic-db:11":           corev1.PullIfNotPresent,
+		"registry.example.com:5000/marklogic-db":     corev1.PullAlways,
+		"registry.example.com/marklogic-db@sha256:0": corev1.PullIfNotPresent,
+	} {
+		if got := defaultImagePullPolicy(image); got != want {
+			t.Errorf("defaultImagePullPolicy(%q) = %s, want %s", image, got, want)
+		}
+	}
+}
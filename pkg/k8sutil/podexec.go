@@ -0,0 +1,151 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// minLogLineBytes is the length of the shortest log lines expected, to
+// request enough lines of a log.
+const minLogLineBytes = 64
+
+// dataDirMountPath is where the datadir volume holding the forests is mounted.
+const dataDirMountPath = "/var/opt/MarkLogic"
+
+// VolumeUsage is the size and usage of a mounted volume in megabytes.
+type VolumeUsage struct {
+	CapacityMB int64
+	UsedMB     int64
+}
+
+// ExecInPod runs command in a container and returns its standard output. It is
+// a variable so tests can replace it.
+var ExecInPod = func(ctx context.Context, namespace, podName, container string, command []string) (string, error) {
+	config, err := GenerateK8sConfig()
+	if err != nil {
+		return "", err
+	}
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return "", err
+	}
+	req := clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(podName).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: container,
+			Command:   command,
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+	executor, err := remotecommand.NewSPDYExecutor(config, "POST", req.URL())
+	if err != nil {
+		return "", err
+	}
+	var stdout, stderr bytes.Buffer
+	if err := executor.StreamWithContext(ctx, remotecommand.StreamOptions{Stdout: &stdout, Stderr: &stderr}); err != nil {
+		return "", fmt.Errorf("exec %q in %s/%s failed: %w: %s", strings.Join(command, " "), namespace, podName, err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+// StreamToPod runs command in a container with stdin as its standard input.
+// It is a variable so tests can replace it.
+var StreamToPod = func(ctx context.Context, namespace, podName, container string, command []string, stdin io.Reader) error {
+	config, err := GenerateK8sConfig()
+	if err != nil {
+		return err
+	}
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+	req := clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(podName).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: container,
+			Command:   command,
+			Stdin:     true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+	executor, err := remotecommand.NewSPDYExecutor(config, "POST", req.URL())
+	if err != nil {
+		return err
+	}
+	var stderr bytes.Buffer
+	if err := executor.StreamWithContext(ctx, remotecommand.StreamOptions{Stdin: stdin, Stderr: &stderr}); err != nil {
+		return fmt.Errorf("exec %q in %s/%s failed: %w: %s", strings.Join(command, " "), namespace, podName, err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// PodLogs returns the last limitBytes of the log of a container. The API
+// server limits the bytes of a log from its start, so the lines that could
+// fit are requested and cut to size. It is a variable so tests can replace
+// it.
+var PodLogs = func(ctx context.Context, namespace, podName, container string, limitBytes int64) (string, error) {
+	config, err := GenerateK8sConfig()
+	if err != nil {
+		return "", err
+	}
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return "", err
+	}
+	tailLines := max(limitBytes/minLogLineBytes, 1)
+	options := &corev1.PodLogOptions{Container: container, TailLines: &tailLines}
+	data, err := clientset.CoreV1().Pods(namespace).GetLogs(podName, options).DoRaw(ctx)
+	if err != nil {
+		return "", err
+	}
+	if int64(len(data)) > limitBytes {
+		data = data[int64(len(data))-limitBytes:]
+	}
+	return string(data), nil
+}
+
+// dataVolumeUsage returns the usage of the datadir volume of a MarkLogic pod as reported by df.
+func dataVolumeUsage(ctx context.Context, namespace, podName string) (VolumeUsage, error) {
+	output, err := ExecInPod(ctx, namespace, podName, "marklogic-server", []string{"df", "-Pk", dataDirMountPath})
+	if err != nil {
+		return VolumeUsage{}, err
+	}
+	return parseDfOutput(output)
+}
+
+// parseDfOutput parses the POSIX output of df -Pk for a single filesystem.
+func parseDfOutput(output string) (VolumeUsage, error) {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) < 2 {
+		return VolumeUsage{}, fmt.Errorf("unexpected df output %q", output)
+	}
+	fields := strings.Fields(lines[len(lines)-1])
+	if len(fields) < 4 {
+		return VolumeUsage{}, fmt.Errorf("unexpected df output %q", output)
+	}
+	capacityKB, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return VolumeUsage{}, fmt.Errorf("unexpected df size %q", fields[1])
+	}
+	usedKB, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil {
+		return VolumeUsage{}, fmt.Errorf("unexpected df usage %q", fields[2])
+	}
+	return VolumeUsage{CapacityMB: capacityKB / 1024, UsedMB: usedKB / 1024}, nil
+}
@@ -0,0 +1,144 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+)
+
+// PrecheckRunner is a check that must pass before an image upgrade starts
+// rolling out. Runners are registered once with RegisterPrecheck and run for
+// every cluster unless disabled in spec.upgrade.prechecks.
+type PrecheckRunner interface {
+	// Name identifies the precheck in spec.upgrade.prechecks and in status.
+	Name() string
+	// Run returns PrecheckFailed to block the upgrade. PrecheckWarning is
+	// reported but does not block it.
+	Run(input *PrecheckInput) (marklogicv1.PrecheckStatus, string)
+}
+
+// PrecheckInput is passed to every PrecheckRunner.
+type PrecheckInput struct {
+	*ClusterContext
+	// Manage is a client for the bootstrap host, or nil when it could not be built.
+	Manage      mlmanage.Client
+	ManageErr   error
+	TargetImage string
+	// Parameters are the parameters configured for this precheck in spec.upgrade.prechecks.
+	Parameters map[string]string
+}
+
+// manageClient returns the Manage client or the error that prevented building it.
+func (in *PrecheckInput) manageClient() (mlmanage.Client, error) {
+	if in.Manage == nil {
+		if in.ManageErr != nil {
+			return nil, fmt.Errorf("management API unavailable: %w", in.ManageErr)
+		}
+		return nil, fmt.Errorf("management API unavailable")
+	}
+	return in.Manage, nil
+}
+
+var (
+	prechecksMu sync.RWMutex
+	prechecks   = map[string]PrecheckRunner{}
+)
+
+// RegisterPrecheck adds a precheck to the set run before every upgrade.
+// Builds of the operator can register their own checks from an init function.
+// It panics when a precheck with the same name is already registered.
+func RegisterPrecheck(runner PrecheckRunner) {
+	prechecksMu.Lock()
+	defer prechecksMu.Unlock()
+	name := runner.Name()
+	if _, exists := prechecks[name]; exists {
+		panic(fmt.Sprintf("precheck %q is already registered", name))
+	}
+	prechecks[name] = runner
+}
+
+// RegisteredPrechecks returns the registered prechecks sorted by name.
+func RegisteredPrechecks() []PrecheckRunner {
+	prechecksMu.RLock()
+	defer prechecksMu.RUnlock()
+	runners := make([]PrecheckRunner, 0, len(prechecks))
+	for _, runner := range prechecks {
+		runners = append(runners, runner)
+	}
+	sort.Slice(runners, func(i, j int) bool { return runners[i].Name() < runners[j].Name() })
+	return runners
+}
+
+// runPrechecks runs every registered precheck for targetImage. Disabled
+// prechecks are reported as skipped. Prechecks configured in the spec but not
+// registered fail, so a typo cannot silently drop a check.
+func (cc *ClusterContext) runPrechecks(targetImage string) []marklogicv1.PrecheckResult {
+	configured := map[string]marklogicv1.PrecheckSpec{}
+	if cc.MarklogicCluster.Spec.Upgrade != nil {
+		for _, spec := range cc.MarklogicCluster.Spec.Upgrade.Prechecks {
+			configured[spec.Name] = spec
+		}
+	}
+	manage, manageErr := cc.newBootstrapManagementClient()
+
+	runners := RegisteredPrechecks()
+	results := make([]marklogicv1.PrecheckResult, 0, len(runners))
+	for _, runner := range runners {
+		spec, hasSpec := configured[runner.Name()]
+		delete(configured, runner.Name())
+		if hasSpec && spec.Enabled != nil && !*spec.Enabled {
+			results = append(results, marklogicv1.PrecheckResult{Name: runner.Name(), Status: marklogicv1.PrecheckSkipped, Message: "disabled in spec.upgrade.prechecks"})
+			continue
+		}
+		status, message := runner.Run(&PrecheckInput{
+			ClusterContext: cc,
+			Manage:         manage,
+			ManageErr:      manageErr,
+			TargetImage:    targetImage,
+			Parameters:     spec.Parameters,
+		})
+		results = append(results, marklogicv1.PrecheckResult{Name: runner.Name(), Status: status, Message: message})
+	}
+	unknown := make([]string, 0, len(configured))
+	for name, spec := range configured {
+		if spec.Enabled == nil || *spec.Enabled {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+	for _, name := range unknown {
+		results = append(results, marklogicv1.PrecheckResult{Name: name, Status: marklogicv1.PrecheckFailed, Message: "precheck is not registered in this operator"})
+	}
+	return results
+}
+
+// failedPrechecks returns the names of the prechecks that block the upgrade.
+func failedPrechecks(results []marklogicv1.PrecheckResult) []string {
+	failed := []string{}
+	for _, res := range results {
+		if res.Status == marklogicv1.PrecheckFailed {
+			failed = append(failed, res.Name)
+		}
+	}
+	return failed
+}
+
+func precheckSummary(results []marklogicv1.PrecheckResult) string {
+	failed := failedPrechecks(results)
+	if len(failed) == 0 {
+		return fmt.Sprintf("%d prechecks passed", len(results))
+	}
+	messages := make([]string, 0, len(failed))
+	for _, res := range results {
+		if res.Status == marklogicv1.PrecheckFailed {
+			messages = append(messages, fmt.Sprintf("%s: %s", res.Name, res.Message))
+		}
+	}
+	return "prechecks failed: " + strings.Join(messages, "; ")
+}
@@ -0,0 +1,210 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+)
+
+const (
+	PrecheckClusterHealth   = "cluster-health"
+	PrecheckForestHealth    = "forest-health"
+	PrecheckStorageHeadroom = "storage-headroom"
+	PrecheckLicense         = "license"
+	PrecheckBackupFreshness = "backup-freshness"
+
+	defaultLicenseMinValidDays = 30
+	defaultBackupMaxAge        = 24 * time.Hour
+)
+
+func init() {
+	RegisterPrecheck(clusterHealthPrecheck{})
+	RegisterPrecheck(forestHealthPrecheck{})
+	RegisterPrecheck(storageHeadroomPrecheck{})
+	RegisterPrecheck(licensePrecheck{})
+	RegisterPrecheck(backupFreshnessPrecheck{})
+}
+
+// clusterHealthPrecheck requires every host of the cluster to be online.
+type clusterHealthPrecheck struct{}
+
+func (clusterHealthPrecheck) Name() string { return PrecheckClusterHealth }
+
+func (clusterHealthPrecheck) Run(in *PrecheckInput) (marklogicv1.PrecheckStatus, string) {
+	manage, err := in.manageClient()
+	if err != nil {
+		return marklogicv1.PrecheckFailed, err.Error()
+	}
+	hosts, err := manage.ListHostsStatus(in.Ctx)
+	if err != nil {
+		return marklogicv1.PrecheckFailed, fmt.Sprintf("failed to read host status: %v", err)
+	}
+	offline := []string{}
+	for _, host := range hosts {
+		if !host.Online {
+			offline = append(offline, host.Name)
+		}
+	}
+	if len(offline) > 0 {
+		return marklogicv1.PrecheckFailed, fmt.Sprintf("hosts offline: %s", strings.Join(offline, ", "))
+	}
+	return marklogicv1.PrecheckPassed, fmt.Sprintf("%d hosts online", len(hosts))
+}
+
+// forestHealthPrecheck requires every forest to be open or replicating.
+type forestHealthPrecheck struct{}
+
+func (forestHealthPrecheck) Name() string { return PrecheckForestHealth }
+
+func (forestHealthPrecheck) Run(in *PrecheckInput) (marklogicv1.PrecheckStatus, string) {
+	manage, err := in.manageClient()
+	if err != nil {
+		return marklogicv1.PrecheckFailed, err.Error()
+	}
+	forests, err := manage.ListForestsStatus(in.Ctx)
+	if err != nil {
+		return marklogicv1.PrecheckFailed, fmt.Sprintf("failed to read forest status: %v", err)
+	}
+	unhealthy := []string{}
+	for _, forest := range forests {
+		if !forestStateHealthy(forest.State) {
+			unhealthy = append(unhealthy, fmt.Sprintf("%s (%s)", forest.Name, forest.State))
+		}
+	}
+	if len(unhealthy) > 0 {
+		sort.Strings(unhealthy)
+		return marklogicv1.PrecheckFailed, fmt.Sprintf("forests not open: %s", strings.Join(unhealthy, ", "))
+	}
+	return marklogicv1.PrecheckPassed, fmt.Sprintf("%d forests open", len(forests))
+}
+
+// forestStateHealthy reports whether a forest state means the forest is mounted and serving.
+func forestStateHealthy(state string) bool {
+	switch state {
+	case "open", "open replica", "sync replicating", "async replicating":
+		return true
+	}
+	return false
+}
+
+// licensePrecheck fails when the bootstrap host's license has expired and
+// warns when it expires within minValidDays (default 30).
+type licensePrecheck struct{}
+
+func (licensePrecheck) Name() string { return PrecheckLicense }
+
+func (licensePrecheck) Run(in *PrecheckInput) (marklogicv1.PrecheckStatus, string) {
+	minValidDays, err := intPrecheckParameter(in.Parameters, "minValidDays", defaultLicenseMinValidDays)
+	if err != nil {
+		return marklogicv1.PrecheckFailed, err.Error()
+	}
+	manage, err := in.manageClient()
+	if err != nil {
+		return marklogicv1.PrecheckFailed, err.Error()
+	}
+	host, err := in.bootstrapHostFQDN()
+	if err != nil {
+		return marklogicv1.PrecheckFailed, err.Error()
+	}
+	license, err := manage.GetHostLicense(in.Ctx, host)
+	if err != nil {
+		return marklogicv1.PrecheckFailed, fmt.Sprintf("failed to read license: %v", err)
+	}
+	if license.Expires == "" {
+		return marklogicv1.PrecheckPassed, "license does not expire"
+	}
+	expires, err := parseManageTime(license.Expires)
+	if err != nil {
+		return marklogicv1.PrecheckWarning, fmt.Sprintf("cannot parse license expiry %q", license.Expires)
+	}
+	remaining := time.Until(expires)
+	switch {
+	case remaining <= 0:
+		return marklogicv1.PrecheckFailed, fmt.Sprintf("license expired on %s", expires.Format(time.DateOnly))
+	case remaining < time.Duration(minValidDays)*24*time.Hour:
+		return marklogicv1.PrecheckWarning, fmt.Sprintf("license expires on %s", expires.Format(time.DateOnly))
+	}
+	return marklogicv1.PrecheckPassed, fmt.Sprintf("license valid until %s", expires.Format(time.DateOnly))
+}
+
+// backupFreshnessPrecheck requires every database with a backup schedule to
+// have a full backup younger than maxAge (default 24h). It is skipped when
+// backups are not managed by the operator.
+type backupFreshnessPrecheck struct{}
+
+func (backupFreshnessPrecheck) Name() string { return PrecheckBackupFreshness }
+
+func (backupFreshnessPrecheck) Run(in *PrecheckInput) (marklogicv1.PrecheckStatus, string) {
+	backup := in.MarklogicCluster.Spec.Backup
+	if backup == nil || !backup.Enabled {
+		return marklogicv1.PrecheckSkipped, "backups are not enabled"
+	}
+	databases := make([]string, 0)
+	for database := range groupBackupSchedulesByDatabase(backup) {
+		databases = append(databases, database)
+	}
+	if len(databases) == 0 {
+		return marklogicv1.PrecheckSkipped, "no scheduled backups configured"
+	}
+	sort.Strings(databases)
+	maxAge := defaultBackupMaxAge
+	if raw := in.Parameters["maxAge"]; raw != "" {
+		parsed, err := time.ParseDuration(raw)
+		if err != nil {
+			return marklogicv1.PrecheckFailed, fmt.Sprintf("invalid maxAge parameter %q", raw)
+		}
+		maxAge = parsed
+	}
+	manage, err := in.manageClient()
+	if err != nil {
+		return marklogicv1.PrecheckFailed, err.Error()
+	}
+	stale := []string{}
+	for _, database := range databases {
+		status, err := manage.GetDatabaseBackupStatus(in.Ctx, database)
+		if err != nil {
+			return marklogicv1.PrecheckFailed, fmt.Sprintf("failed to read backup status of %s: %v", database, err)
+		}
+		if status.LastBackup == "" {
+			stale = append(stale, fmt.Sprintf("%s (never)", database))
+			continue
+		}
+		last, err := parseManageTime(status.LastBackup)
+		if err != nil || time.Since(last) > maxAge {
+			stale = append(stale, fmt.Sprintf("%s (%s)", database, status.LastBackup))
+		}
+	}
+	if len(stale) > 0 {
+		return marklogicv1.PrecheckFailed, fmt.Sprintf("no backup within %s: %s", maxAge, strings.Join(stale, ", "))
+	}
+	return marklogicv1.PrecheckPassed, fmt.Sprintf("%d databases backed up within %s", len(databases), maxAge)
+}
+
+func intPrecheckParameter(params map[string]string, key string, fallback int) (int, error) {
+	raw, ok := params[key]
+	if !ok || raw == "" {
+		return fallback, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid %s parameter %q", key, raw)
+	}
+	return value, nil
+}
+
+// parseManageTime parses the timestamps returned by the Manage API, which
+// carry an offset and optional fractional seconds.
+func parseManageTime(value string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999Z0700", time.DateOnly} {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unsupported time format %q", value)
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"fmt"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/registry"
+)
+
+const (
+	PrecheckImageSignature = "image-signature"
+
+	cosignPublicKeyKey      = "cosign.pub"
+	cosignFulcioRootsKey    = "fulcio.pem"
+	cosignRekorPublicKeyKey = "rekor.pub"
+)
+
+// ImageSignatures reads the cosign signatures of an image from its registry.
+// Tests replace it to avoid network access.
+var ImageSignatures = registry.ImageSignatures
+
+func init() {
+	RegisterPrecheck(imageSignaturePrecheck{})
+}
+
+// imageSignaturePrecheck requires a cosign signature on the target image,
+// made with the key in the publicKeySecret parameter or, keyless, by the
+// identity parameter with a certificate trusted by trustedRootSecret. It is
+// skipped until one of them is configured.
+type imageSignaturePrecheck struct{}
+
+func (imageSignaturePrecheck) Name() string { return PrecheckImageSignature }
+
+func (imageSignaturePrecheck) Run(in *PrecheckInput) (marklogicv1.PrecheckStatus, string) {
+	keySecret := in.Parameters["publicKeySecret"]
+	identity := in.Parameters["identity"]
+	if keySecret == "" && identity == "" {
+		return marklogicv1.PrecheckSkipped, "no publicKeySecret or identity parameter configured"
+	}
+	if Offline {
+		// The signatures live in the registry, and an unverified image must
+		// not roll out.
+		return marklogicv1.PrecheckFailed, fmt.Sprintf("the signatures of %s cannot be read from the registry in offline mode", in.TargetImage)
+	}
+	policy, err := in.cosignPolicy(in.Parameters)
+	if err != nil {
+		return marklogicv1.PrecheckFailed, err.Error()
+	}
+	creds, err := in.registryCredentials()
+	if err != nil {
+		return marklogicv1.PrecheckFailed, fmt.Sprintf("failed to read the image pull secrets: %v", err)
+	}
+	// Unlike the architecture check, an unreachable registry blocks the
+	// upgrade: an unverified image must not roll out.
+	digest, signatures, err := ImageSignatures(in.Ctx, in.TargetImage, registry.Options{Credentials: creds})
+	if err != nil {
+		return marklogicv1.PrecheckFailed, fmt.Sprintf("failed to read the signatures of %s: %v", in.TargetImage, err)
+	}
+	if err := registry.VerifyCosignSignatures(digest, signatures, policy); err != nil {
+		return marklogicv1.PrecheckFailed, fmt.Sprintf("%s (%s): %v", in.TargetImage, digest, err)
+	}
+	if identity != "" && keySecret == "" {
+		return marklogicv1.PrecheckPassed, fmt.Sprintf("%s is signed by %s", digest, identity)
+	}
+	return marklogicv1.PrecheckPassed, fmt.Sprintf("%s is signed with the key in Secret %s", digest, keySecret)
+}
+
+// cosignPolicy reads the key or the keyless trust material named by the
+// precheck parameters.
+func (cc *ClusterContext) cosignPolicy(parameters map[string]string) (registry.CosignPolicy, error) {
+	if keySecret := parameters["publicKeySecret"]; keySecret != "" {
+		key, err := cc.secretKey(keySecret, cosignPublicKeyKey)
+		return registry.CosignPolicy{PublicKey: key}, err
+	}
+	rootSecret := parameters["trustedRootSecret"]
+	if rootSecret == "" {
+		return registry.CosignPolicy{}, fmt.Errorf("keyless verification requires the trustedRootSecret parameter")
+	}
+	roots, err := cc.secretKey(rootSecret, cosignFulcioRootsKey)
+	if err != nil {
+		return registry.CosignPolicy{}, err
+	}
+	rekorKey, err := cc.secretKey(rootSecret, cosignRekorPublicKeyKey)
+	if err != nil {
+		return registry.CosignPolicy{}, err
+	}
+	return registry.CosignPolicy{
+		Identity:       parameters["identity"],
+		Issuer:         parameters["issuer"],
+		Roots:          roots,
+		RekorPublicKey: rekorKey,
+	}, nil
+}
+
+func (cc *ClusterContext) secretKey(name, key string) ([]byte, error) {
+	secret, err := cc.getSecret(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Secret %s: %w", name, err)
+	}
+	value, ok := secret.Data[key]
+	if !ok {
+		return nil, fmt.Errorf("secret %s has no %s key", name, key)
+	}
+	return value, nil
+}
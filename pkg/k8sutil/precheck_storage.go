@@ -0,0 +1,188 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlversion"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	defaultStorageMaxUsagePercent = 85
+	// After a restart on a new minor release MarkLogic merges the forests,
+	// which can temporarily need about half the forest size again. A new major
+	// release may reindex, which can need the full forest size again.
+	defaultStorageMergeFactor   = 0.5
+	defaultStorageReindexFactor = 1.0
+)
+
+// storageHeadroomPrecheck projects the datadir usage of every pod that is
+// restarted by the upgrade: the current volume usage (df in the pod) plus the
+// size of the host's forests times the merge or reindex factor of the target
+// version. It fails when the projection exceeds maxUsagePercent of the volume.
+// When the volume cannot be inspected, the free device space reported by the
+// Manage API is used instead.
+//
+// Parameters: maxUsagePercent (default 85), mergeFactor (default 0.5) and
+// reindexFactor (default 1.0).
+type storageHeadroomPrecheck struct{}
+
+func (storageHeadroomPrecheck) Name() string { return PrecheckStorageHeadroom }
+
+func (storageHeadroomPrecheck) Run(in *PrecheckInput) (marklogicv1.PrecheckStatus, string) {
+	maxUsage, err := intPrecheckParameter(in.Parameters, "maxUsagePercent", defaultStorageMaxUsagePercent)
+	if err != nil {
+		return marklogicv1.PrecheckFailed, err.Error()
+	}
+	factor, factorName, err := storageGrowthFactor(in)
+	if err != nil {
+		return marklogicv1.PrecheckFailed, err.Error()
+	}
+	manage, err := in.manageClient()
+	if err != nil {
+		return marklogicv1.PrecheckFailed, err.Error()
+	}
+	forests, err := manage.ListForestsStatus(in.Ctx)
+	if err != nil {
+		return marklogicv1.PrecheckFailed, fmt.Sprintf("failed to read forest status: %v", err)
+	}
+	forestsByHost := map[string][]mlmanage.ForestStatus{}
+	for _, forest := range forests {
+		forestsByHost[forest.Host] = append(forestsByHost[forest.Host], forest)
+	}
+	pods, err := in.upgradedPods()
+	if err != nil {
+		return marklogicv1.PrecheckFailed, fmt.Sprintf("failed to list pods: %v", err)
+	}
+
+	overLimit := []string{}
+	estimated := []string{}
+	for _, pod := range pods {
+		host := in.podHostFQDN(pod)
+		hostForests := forestsByHost[host]
+		var forestMB int64
+		for _, forest := range hostForests {
+			forestMB += forest.DataSizeMB
+		}
+		usage, err := dataVolumeUsage(in.Ctx, pod.Namespace, pod.Name)
+		if err != nil {
+			in.ReqLogger.Info("Falling back to forest device space for storage headroom", "pod", pod.Name, "error", err.Error())
+			var ok bool
+			if usage, ok = forestDeviceUsage(hostForests); !ok {
+				continue
+			}
+			estimated = append(estimated, pod.Name)
+		}
+		if usage.CapacityMB <= 0 {
+			continue
+		}
+		projected := usage.UsedMB + int64(float64(forestMB)*factor)
+		if percent := projected * 100 / usage.CapacityMB; percent > int64(maxUsage) {
+			overLimit = append(overLimit, fmt.Sprintf("%s (%d%% of %dMi)", pod.Name, percent, usage.CapacityMB))
+		}
+	}
+	sort.Strings(overLimit)
+	note := ""
+	if len(estimated) > 0 {
+		sort.Strings(estimated)
+		note = fmt.Sprintf("; usage estimated from forest data for %s", strings.Join(estimated, ", "))
+	}
+	if len(overLimit) > 0 {
+		return marklogicv1.PrecheckFailed, fmt.Sprintf("projected %s usage exceeds %d%%: %s%s", factorName, maxUsage, strings.Join(overLimit, ", "), note)
+	}
+	return marklogicv1.PrecheckPassed, fmt.Sprintf("projected %s usage of %d pods within %d%%%s", factorName, len(pods), maxUsage, note)
+}
+
+// storageGrowthFactor returns the forest growth factor for the upgrade: the
+// reindex factor when the major version changes or cannot be determined from
+// the image tags, the merge factor otherwise.
+func storageGrowthFactor(in *PrecheckInput) (float64, string, error) {
+	merge, err := floatPrecheckParameter(in.Parameters, "mergeFactor", defaultStorageMergeFactor)
+	if err != nil {
+		return 0, "", err
+	}
+	reindex, err := floatPrecheckParameter(in.Parameters, "reindexFactor", defaultStorageReindexFactor)
+	if err != nil {
+		return 0, "", err
+	}
+	currentImage := in.MarklogicCluster.Spec.Image
+	if in.MarklogicCluster.Status.Upgrade != nil && in.MarklogicCluster.Status.Upgrade.CurrentImage != "" {
+		currentImage = in.MarklogicCluster.Status.Upgrade.CurrentImage
+	}
+	current, currentOK := imageMajorVersion(currentImage)
+	target, targetOK := imageMajorVersion(in.TargetImage)
+	if currentOK && targetOK && current == target {
+		return merge, "merge", nil
+	}
+	return reindex, "reindex", nil
+}
+
+// upgradedPods returns the pods of the groups that follow spec.image.
+func (cc *ClusterContext) upgradedPods() ([]corev1.Pod, error) {
+	cr := cc.MarklogicCluster
+	pods := []corev1.Pod{}
+	for _, group := range cr.Spec.MarkLogicGroups {
+		if group == nil || group.Image != "" {
+			continue
+		}
+		list := &corev1.PodList{}
+		if err := cc.Client.List(cc.Ctx, list, client.InNamespace(cr.Namespace), client.MatchingLabels{
+			"app.kubernetes.io/name":     "marklogic",
+			"app.kubernetes.io/instance": group.Name,
+		}); err != nil {
+			return nil, err
+		}
+		pods = append(pods, list.Items...)
+	}
+	return pods, nil
+}
+
+// podHostFQDN returns the MarkLogic host name of a pod of the cluster.
+func (cc *ClusterContext) podHostFQDN(pod corev1.Pod) string {
+	cr := cc.MarklogicCluster
+	group := pod.Labels["app.kubernetes.io/instance"]
+	return fmt.Sprintf("%s.%s.%s.svc.%s", pod.Name, group, cr.Namespace, cr.Spec.ClusterDomain)
+}
+
+// forestDeviceUsage approximates the volume usage of a host from its forests:
+// the forests are taken as the only data on the device.
+func forestDeviceUsage(forests []mlmanage.ForestStatus) (VolumeUsage, bool) {
+	if len(forests) == 0 {
+		return VolumeUsage{}, false
+	}
+	usage := VolumeUsage{}
+	var freeMB int64
+	for _, forest := range forests {
+		usage.UsedMB += forest.DataSizeMB
+		freeMB = max(freeMB, forest.DeviceSpaceMB)
+	}
+	usage.CapacityMB = usage.UsedMB + freeMB
+	return usage, true
+}
+
+// imageMajorVersion returns the MarkLogic major version from an image tag
+// such as 11.3.1-ubi-rootless.
+func imageMajorVersion(image string) (int, bool) {
+	version, ok := mlversion.ParseImage(image)
+	return version.Major, ok
+}
+
+func floatPrecheckParameter(params map[string]string, key string, fallback float64) (float64, error) {
+	raw, ok := params[key]
+	if !ok || raw == "" {
+		return fallback, nil
+	}
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid %s parameter %q", key, raw)
+	}
+	return value, nil
+}
@@ -0,0 +1,280 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/registry"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type customPrecheck struct {
+	calls *int
+}
+
+func (customPrecheck) Name() string { return "custom" }
+
+func (c customPrecheck) Run(in *PrecheckInput) (marklogicv1.PrecheckStatus, string) {
+	*c.calls++
+	return marklogicv1.PrecheckWarning, "custom parameter " + in.Parameters["mode"]
+}
+
+func newPrecheckTestCluster(prechecks ...marklogicv1.PrecheckSpec) *marklogicv1.MarklogicCluster {
+	return &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			Image:           upgradeTestNewImage,
+			ClusterDomain:   "cluster.local",
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{{Name: "dnode", IsBootstrap: true}},
+			Upgrade:         &marklogicv1.UpgradeSpec{Prechecks: prechecks},
+		},
+	}
+}
+
+func precheckResultByName(results []marklogicv1.PrecheckResult, name string) marklogicv1.PrecheckResult {
+	for _, res := range results {
+		if res.Name == name {
+			return res
+		}
+	}
+	return marklogicv1.PrecheckResult{}
+}
+
+func TestRunPrechecksHonoursSpecAndCustomRunners(t *testing.T) {
+	calls := 0
+	RegisterPrecheck(customPrecheck{calls: &calls})
+	t.Cleanup(func() {
+		prechecksMu.Lock()
+		delete(prechecks, "custom")
+		prechecksMu.Unlock()
+	})
+	disabled := false
+	cr := newPrecheckTestCluster(
+		marklogicv1.PrecheckSpec{Name: PrecheckLicense, Enabled: &disabled},
+		marklogicv1.PrecheckSpec{Name: "custom", Parameters: map[string]string{"mode": "strict"}},
+		marklogicv1.PrecheckSpec{Name: "typo"},
+	)
+	cc := newUpgradeTestContext(t, cr)
+	forests := []mlmanage.ForestStatus{{Name: "Documents", State: "open", DataSizeMB: 100, DeviceSpaceMB: 900}}
+	stubHealthyManagementClient(t, &forests)
+
+	results := cc.runPrechecks(upgradeTestNewImage)
+	if calls != 1 || precheckResultByName(results, "custom").Message != "custom parameter strict" {
+		t.Fatalf("expected the custom precheck to run with its parameters, got %+v", results)
+	}
+	if precheckResultByName(results, PrecheckLicense).Status != marklogicv1.PrecheckSkipped {
+		t.Fatalf("expected the disabled license precheck to be skipped, got %+v", results)
+	}
+	if precheckResultByName(results, PrecheckBackupFreshness).Status != marklogicv1.PrecheckSkipped {
+		t.Fatalf("expected backup freshness to be skipped without backups, got %+v", results)
+	}
+	if failed := failedPrechecks(results); len(failed) != 1 || failed[0] != "typo" {
+		t.Fatalf("expected only the unregistered precheck to fail, got %v", failed)
+	}
+}
+
+func TestRegisterPrecheckRejectsDuplicates(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a duplicate registration to panic")
+		}
+	}()
+	RegisterPrecheck(clusterHealthPrecheck{})
+}
+
+func TestBuiltinPrechecks(t *testing.T) {
+	cc := newUpgradeTestContext(t, newPrecheckTestCluster())
+	manage := &stubDynamicManagementClient{
+		hostsStatusFn: func() ([]mlmanage.HostStatus, error) {
+			return []mlmanage.HostStatus{{Name: "dnode-0", Online: true}, {Name: "dnode-1"}}, nil
+		},
+		forestsStatusFn: func() ([]mlmanage.ForestStatus, error) {
+			return []mlmanage.ForestStatus{
+				{Name: "Documents", State: "open", DataSizeMB: 850, DeviceSpaceMB: 150},
+				{Name: "Meters", State: "open replica", DataSizeMB: 100, DeviceSpaceMB: 900},
+			}, nil
+		},
+		hostLicenseFn: func(hostName string) (mlmanage.HostLicense, error) {
+			if hostName != "dnode-0.dnode.default.svc.cluster.local" {
+				t.Fatalf("unexpected license host %q", hostName)
+			}
+			return mlmanage.HostLicense{Expires: time.Now().Add(10 * 24 * time.Hour).Format(time.RFC3339)}, nil
+		},
+		backupStatusFn: func(database string) (mlmanage.DatabaseBackupStatus, error) {
+			return mlmanage.DatabaseBackupStatus{LastBackup: time.Now().Add(-48 * time.Hour).Format("2006-01-02T15:04:05.000-07:00")}, nil
+		},
+	}
+	in := &PrecheckInput{ClusterContext: cc, Manage: manage, TargetImage: upgradeTestNewImage}
+
+	if status, message := (clusterHealthPrecheck{}).Run(in); status != marklogicv1.PrecheckFailed || message != "hosts offline: dnode-1" {
+		t.Fatalf("unexpected cluster health result %s: %s", status, message)
+	}
+	if status, message := (forestHealthPrecheck{}).Run(in); status != marklogicv1.PrecheckPassed {
+		t.Fatalf("unexpected forest health result %s: %s", status, message)
+	}
+	if status, message := (licensePrecheck{}).Run(in); status != marklogicv1.PrecheckWarning {
+		t.Fatalf("expected a warning for a license expiring soon, got %s: %s", status, message)
+	}
+
+	cc.MarklogicCluster.Spec.Backup = &marklogicv1.Backup{
+		Enabled:   true,
+		Schedules: []marklogicv1.BackupSchedule{{Database: "Documents", Frequency: "daily", Period: 1}},
+	}
+	if status, message := (backupFreshnessPrecheck{}).Run(in); status != marklogicv1.PrecheckFailed {
+		t.Fatalf("expected a stale backup to fail, got %s: %s", status, message)
+	}
+	in.Parameters = map[string]string{"maxAge": "72h"}
+	if status, message := (backupFreshnessPrecheck{}).Run(in); status != marklogicv1.PrecheckPassed {
+		t.Fatalf("expected the backup to be fresh enough, got %s: %s", status, message)
+	}
+
+	in.Manage = nil
+	if status, _ := (clusterHealthPrecheck{}).Run(in); status != marklogicv1.PrecheckFailed {
+		t.Fatalf("expected a missing management client to fail the precheck")
+	}
+}
+
+func newStorageTestPod(name string) *corev1.Pod {
+	return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:      name,
+		Namespace: "default",
+		Labels:    map[string]string{"app.kubernetes.io/name": "marklogic", "app.kubernetes.io/instance": "dnode"},
+	}}
+}
+
+func TestStorageHeadroomPrecheckProjectsVolumeUsage(t *testing.T) {
+	cr := newPrecheckTestCluster()
+	cr.Spec.Image = upgradeTestOldImage
+	cc := newUpgradeTestContext(t, cr, newStorageTestPod("dnode-0"), newStorageTestPod("dnode-1"))
+	manage := &stubDynamicManagementClient{
+		forestsStatusFn: func() ([]mlmanage.ForestStatus, error) {
+			return []mlmanage.ForestStatus{
+				{Name: "Documents", Host: "dnode-0.dnode.default.svc.cluster.local", DataSizeMB: 3000, DeviceSpaceMB: 6000},
+				{Name: "Security", Host: "dnode-1.dnode.default.svc.cluster.local", DataSizeMB: 2000, DeviceSpaceMB: 7000},
+			}, nil
+		},
+	}
+	original := ExecInPod
+	t.Cleanup(func() { ExecInPod = original })
+	ExecInPod = func(ctx context.Context, namespace, podName, container string, command []string) (string, error) {
+		if container != "marklogic-server" || strings.Join(command, " ") != "df -Pk /var/opt/MarkLogic" {
+			t.Fatalf("unexpected exec %s %v", container, command)
+		}
+		if podName == "dnode-1" {
+			return "", errors.New("exec not permitted")
+		}
+		// 10 GiB volume with 4 GiB used.
+		return "Filesystem 1024-blocks Used Available Capacity Mounted on\n/dev/sdb 10485760 4194304 6291456 40% /var/opt/MarkLogic\n", nil
+	}
+
+	// 11.3.1 -> 11.3.2 merges: dnode-0 projects 4096+1500 of 10240 MB (54%), dnode-1
+	// falls back to forest data and projects 2000+1000 of 9000 MB (33%).
+	in := &PrecheckInput{ClusterContext: cc, Manage: manage, TargetImage: "progressofficial/marklogic-db:11.3.2-ubi-rootless"}
+	status, message := (storageHeadroomPrecheck{}).Run(in)
+	if status != marklogicv1.PrecheckPassed || !strings.Contains(message, "merge") || !strings.Contains(message, "estimated from forest data for dnode-1") {
+		t.Fatalf("unexpected merge projection %s: %s", status, message)
+	}
+
+	// 11 -> 12 reindexes: dnode-0 projects 4096+3000 of 10240 MB (69%).
+	in.TargetImage = upgradeTestNewImage
+	in.Parameters = map[string]string{"maxUsagePercent": "60"}
+	status, message = (storageHeadroomPrecheck{}).Run(in)
+	if status != marklogicv1.PrecheckFailed || !strings.HasPrefix(message, "projected reindex usage exceeds 60%: dnode-0 (69% of 10240Mi)") {
+		t.Fatalf("unexpected reindex projection %s: %s", status, message)
+	}
+
+	in.Parameters = map[string]string{"reindexFactor": "oops"}
+	if status, _ := (storageHeadroomPrecheck{}).Run(in); status != marklogicv1.PrecheckFailed {
+		t.Fatalf("expected an invalid parameter to fail the precheck")
+	}
+}
+
+func TestImageMajorVersion(t *testing.T) {
+	cases := map[string]int{
+		"progressofficial/marklogic-db:11.3.1-ubi-rootless":              11,
+		"registry.local:5000/marklogic-db:12.0.3-ubi9-rootless-2.2.6":    12,
+		"progressofficial/marklogic-db:10.0-9.5@sha256:0123456789abcdef": 10,
+	}
+	for image, want := range cases {
+		if got, ok := imageMajorVersion(image); !ok || got != want {
+			t.Errorf("imageMajorVersion(%q) = %d, %t; want %d", image, got, ok, want)
+		}
+	}
+	for _, image := range []string{"progressofficial/marklogic-db:latest", "registry.local:5000/marklogic-db"} {
+		if _, ok := imageMajorVersion(image); ok {
+			t.Errorf("expected no major version for %q", image)
+		}
+	}
+}
+
+func TestVersionCompatibilityPrecheck(t *testing.T) {
+	cr := newPrecheckTestCluster()
+	cr.Spec.Image = upgradeTestOldImage
+	cc := newUpgradeTestContext(t, cr)
+	hostVersion := "10.0-9.5"
+	manage := &stubDynamicManagementClient{hostsStatusFn: func() ([]mlmanage.HostStatus, error) {
+		return []mlmanage.HostStatus{{Name: "dnode-0", Version: "11.3-1"}, {Name: "dnode-1", Version: hostVersion}}, nil
+	}}
+	in := &PrecheckInput{ClusterContext: cc, Manage: manage, TargetImage: upgradeTestNewImage}
+
+	// The oldest host decides, so a half finished upgrade is not skipped.
+	if status, message := (versionCompatibilityPrecheck{}).Run(in); status != marklogicv1.PrecheckPassed || message != "MarkLogic 10.0 upgrades to 12.0, running version from the hosts" {
+		t.Fatalf("unexpected result %s: %s", status, message)
+	}
+	hostVersion = "9.0-13"
+	if status, message := (versionCompatibilityPrecheck{}).Run(in); status != marklogicv1.PrecheckFailed || message != "MarkLogic 9 cannot be upgraded to 12 directly, upgrade to 10, 11 first" {
+		t.Fatalf("expected an upgrade skipping major versions to fail, got %s: %s", status, message)
+	}
+
+	// Without the Manage API the current image is used.
+	in.Manage = nil
+	cc.MarklogicCluster.Spec.Image = "progressofficial/marklogic-db:12.0.3-ubi-rootless"
+	in.TargetImage = upgradeTestOldImage
+	if status, message := (versionCompatibilityPrecheck{}).Run(in); status != marklogicv1.PrecheckFailed || message != "MarkLogic 12 cannot be downgraded to 11" {
+		t.Fatalf("expected a downgrade to fail, got %s: %s", status, message)
+	}
+	in.TargetImage = "progressofficial/marklogic-db:13.0.0-ubi-rootless"
+	if status, _ := (versionCompatibilityPrecheck{}).Run(in); status != marklogicv1.PrecheckWarning {
+		t.Fatalf("expected a warning for an unknown version, got %s", status)
+	}
+	in.TargetImage = "progressofficial/marklogic-db:latest"
+	if status, _ := (versionCompatibilityPrecheck{}).Run(in); status != marklogicv1.PrecheckWarning {
+		t.Fatalf("expected a warning for a tag without a version, got %s", status)
+	}
+}
+
+func TestImageSignaturePrecheck(t *testing.T) {
+	keySecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "cosign", Namespace: "default"},
+		Data:       map[string][]byte{cosignPublicKeyKey: []byte("not a key")},
+	}
+	cc := newUpgradeTestContext(t, newPrecheckTestCluster(), keySecret)
+	lookups := 0
+	original := ImageSignatures
+	t.Cleanup(func() { ImageSignatures = original })
+	ImageSignatures = func(ctx context.Context, image string, opts registry.Options) (string, []registry.Signature, error) {
+		lookups++
+		return "sha256:abc", nil, nil
+	}
+
+	in := &PrecheckInput{ClusterContext: cc, TargetImage: upgradeTestNewImage}
+	if status, _ := (imageSignaturePrecheck{}).Run(in); status != marklogicv1.PrecheckSkipped || lookups != 0 {
+		t.Fatalf("expected the precheck to be skipped without a key or identity, got %s", status)
+	}
+	in.Parameters = map[string]string{"identity": "release@example.com"}
+	if status, message := (imageSignaturePrecheck{}).Run(in); status != marklogicv1.PrecheckFailed || !strings.Contains(message, "trustedRootSecret") {
+		t.Fatalf("expected keyless verification to require a trusted root, got %s: %s", status, message)
+	}
+	in.Parameters = map[string]string{"publicKeySecret": "cosign"}
+	if status, message := (imageSignaturePrecheck{}).Run(in); status != marklogicv1.PrecheckFailed || !strings.Contains(message, "no cosign signatures") {
+		t.Fatalf("expected an unsigned image to fail, got %s: %s", status, message)
+	}
+}
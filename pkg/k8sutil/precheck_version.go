@@ -0,0 +1,73 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"errors"
+	"fmt"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlversion"
+)
+
+const PrecheckVersionCompatibility = "version-compatibility"
+
+func init() {
+	RegisterPrecheck(versionCompatibilityPrecheck{})
+}
+
+// versionCompatibilityPrecheck requires the target image to be an upgrade the
+// running MarkLogic version supports, following the version table of
+// pkg/mlversion. The running version is read from the hosts, or from the
+// current image when the Manage API cannot be reached.
+type versionCompatibilityPrecheck struct{}
+
+func (versionCompatibilityPrecheck) Name() string { return PrecheckVersionCompatibility }
+
+func (versionCompatibilityPrecheck) Run(in *PrecheckInput) (marklogicv1.PrecheckStatus, string) {
+	target, ok := mlversion.ParseImage(in.TargetImage)
+	if !ok {
+		return marklogicv1.PrecheckWarning, fmt.Sprintf("the MarkLogic version of %s cannot be told from its tag", in.TargetImage)
+	}
+	current, source, ok := in.runningVersion()
+	if !ok {
+		return marklogicv1.PrecheckWarning, "the running MarkLogic version cannot be told from the hosts or the current image"
+	}
+	if err := mlversion.CheckUpgrade(current, target); err != nil {
+		if errors.Is(err, mlversion.ErrUnknownVersion) {
+			return marklogicv1.PrecheckWarning, err.Error()
+		}
+		return marklogicv1.PrecheckFailed, err.Error()
+	}
+	return marklogicv1.PrecheckPassed, fmt.Sprintf("MarkLogic %s upgrades to %s, running version from %s", current, target, source)
+}
+
+// runningVersion returns the oldest MarkLogic version the hosts of the
+// cluster run, or the version of the current image, and where it was read.
+func (in *PrecheckInput) runningVersion() (mlversion.Version, string, bool) {
+	if manage, err := in.manageClient(); err == nil {
+		if hosts, err := manage.ListHostsStatus(in.Ctx); err == nil {
+			var oldest mlversion.Version
+			found := false
+			for _, host := range hosts {
+				version, ok := mlversion.ParseHost(host.Version)
+				if !ok {
+					continue
+				}
+				if !found || version.Major < oldest.Major || (version.Major == oldest.Major && version.Minor < oldest.Minor) {
+					oldest = version
+					found = true
+				}
+			}
+			if found {
+				return oldest, "the hosts", true
+			}
+		}
+	}
+	currentImage := in.MarklogicCluster.Spec.Image
+	if upgrade := in.MarklogicCluster.Status.Upgrade; upgrade != nil && upgrade.CurrentImage != "" {
+		currentImage = upgrade.CurrentImage
+	}
+	version, ok := mlversion.ParseImage(currentImage)
+	return version, "the current image", ok
+}
@@ -0,0 +1,340 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/registry"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// SkipPreflightAnnotation set to "true" on a MarklogicCluster creates its
+	// resources without the pre-flight checks, for environments the checks
+	// misjudge, such as node pools a cluster autoscaler has yet to create.
+	SkipPreflightAnnotation = "marklogic.progress.com/skip-preflight"
+
+	// preflightRetrySeconds is how often failed pre-flight checks are run
+	// again.
+	preflightRetrySeconds = 30
+
+	preflightReasonPassed  = "PreflightPassed"
+	preflightReasonInvalid = "EnvironmentInvalid"
+
+	defaultStorageClassAnnotation     = "storageclass.kubernetes.io/is-default-class"
+	betaDefaultStorageClassAnnotation = "storageclass.beta.kubernetes.io/is-default-class"
+	localVolumeProvisioner            = "kubernetes.io/no-provisioner"
+
+	// defaultMarkLogicFSGroup is the fsGroup of the MarklogicGroup CRD
+	// default pod security context.
+	defaultMarkLogicFSGroup = 2
+)
+
+// ReconcilePreflight validates the environment of a new cluster before any
+// of its resources are created: the storage classes of the volumes exist,
+// the resource requests of every group fit a schedulable node, the images
+// exist in their registry and the OpenShift SCC of the namespace allows the
+// fsGroup and user of the pods. Problems are reported in the
+// PreflightFailed condition and checked again until they are fixed, instead
+// of leaving pods Pending or in ErrImagePull. Checks that cannot be run, for
+// lack of access or in offline mode, are skipped. Clusters that passed, or
+// whose groups already exist, are not checked again.
+func (cc *ClusterContext) ReconcilePreflight() result.ReconcileResult {
+	cr := cc.MarklogicCluster
+	if ClusterOperation(cr, SkipPreflightAnnotation) == "true" {
+		return result.Continue()
+	}
+	if cr.Status.GetConditionStatus(string(marklogicv1.PreflightFailed)) == metav1.ConditionFalse {
+		return result.Continue()
+	}
+	if cr.Status.GetConditionStatus(string(marklogicv1.PreflightFailed)) == metav1.ConditionUnknown {
+		created, err := cc.groupsCreated()
+		if err != nil {
+			return result.Error(err)
+		}
+		if created {
+			return result.Continue()
+		}
+	}
+
+	problems := cc.preflightProblems()
+	condition := metav1.Condition{
+		Type:               string(marklogicv1.PreflightFailed),
+		Status:             metav1.ConditionFalse,
+		Reason:             preflightReasonPassed,
+		Message:            "the environment can run the cluster",
+		ObservedGeneration: cr.Generation,
+		LastTransitionTime: metav1.Now(),
+	}
+	if len(problems) > 0 {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = preflightReasonInvalid
+		condition.Message = strings.Join(problems, "; ")
+	}
+	changed := true
+	for _, existing := range cr.Status.Conditions {
+		if existing.Type == condition.Type && existing.Status == condition.Status {
+			changed = existing.Message != condition.Message
+			condition.LastTransitionTime = existing.LastTransitionTime
+		}
+	}
+	if changed {
+		patchBase := client.MergeFrom(cr.DeepCopy())
+		cr.Status.SetCondition(condition)
+		if err := patchStatus(cc.Ctx, cc.Client, cr, patchBase); err != nil {
+			return result.Error(err)
+		}
+	}
+	if len(problems) == 0 {
+		return result.Continue()
+	}
+	if changed {
+		cc.recordClusterEvent(corev1.EventTypeWarning, string(marklogicv1.PreflightFailed), condition.Message)
+	}
+	cc.ReqLogger.Info("Pre-flight checks failed, the cluster resources are not created", "problems", condition.Message)
+	return result.RequeueSoon(preflightRetrySeconds)
+}
+
+// groupsCreated reports whether a MarklogicGroup of the cluster exists, as for
+// clusters created before the pre-flight checks.
+func (cc *ClusterContext) groupsCreated() (bool, error) {
+	cr := cc.MarklogicCluster
+	for _, group := range cr.Spec.MarkLogicGroups {
+		if group == nil {
+			continue
+		}
+		mlg := &marklogicv1.MarklogicGroup{}
+		err := cc.Client.Get(cc.Ctx, types.NamespacedName{Namespace: cr.Namespace, Name: group.Name}, mlg)
+		if err == nil {
+			return true, nil
+		}
+		if !apierrors.IsNotFound(err) {
+			return false, err
+		}
+	}
+	return false, nil
+}
+
+// preflightProblems runs the pre-flight checks on the groups as they would
+// be generated.
+func (cc *ClusterContext) preflightProblems() []string {
+	cr := cc.MarklogicCluster
+	clusterParams := generateMarkLogicClusterParams(cr)
+	groups := []*MarkLogicGroupParameters{}
+	for i, group := range cr.Spec.MarkLogicGroups {
+		if group != nil {
+			groups = append(groups, generateMarkLogicGroupParams(cr, i, clusterParams))
+		}
+	}
+	problems := cc.storageClassProblems(groups)
+	problems = append(problems, cc.nodeFitProblems(groups)...)
+	problems = append(problems, cc.imageProblems(groups)...)
+	if !cr.Spec.OCPCompatible {
+		problems = append(problems, cc.sccProblems(groups)...)
+	}
+	return problems
+}
+
+// storageClassProblems checks that the storage classes of the persistent
+// volumes exist, or that there is a default storage class for volumes
+// without one, and that local volumes are bound once their pod is scheduled.
+func (cc *ClusterContext) storageClassProblems(groups []*MarkLogicGroupParameters) []string {
+	classes := map[string][]string{}
+	for _, group := range groups {
+		if group.Persistence != nil && group.Persistence.Enabled {
+			name := group.Persistence.StorageClassName
+			if name == "" {
+				name = OperatorConfig.DefaultStorageClass
+			}
+			classes[name] = append(classes[name], group.Name)
+		}
+		if group.AdditionalVolumeClaimTemplates != nil {
+			for _, pvc := range *group.AdditionalVolumeClaimTemplates {
+				if pvc.Spec.StorageClassName != nil && *pvc.Spec.StorageClassName != "" {
+					classes[*pvc.Spec.StorageClassName] = append(classes[*pvc.Spec.StorageClassName], group.Name)
+				}
+			}
+		}
+	}
+	problems := []string{}
+	for _, name := range slices.Sorted(maps.Keys(classes)) {
+		users := strings.Join(slices.Compact(classes[name]), ", ")
+		if name == "" {
+			list := &storagev1.StorageClassList{}
+			if err := cc.Client.List(cc.Ctx, list); err != nil {
+				cc.ReqLogger.Error(err, "Failed to list the storage classes, the default storage class is not checked")
+				continue
+			}
+			if !hasDefaultStorageClass(list.Items) {
+				problems = append(problems, fmt.Sprintf("groups %s use the default storage class but none is marked as default", users))
+			}
+			continue
+		}
+		class := &storagev1.StorageClass{}
+		err := cc.Client.Get(cc.Ctx, types.NamespacedName{Name: name}, class)
+		if apierrors.IsNotFound(err) {
+			problems = append(problems, fmt.Sprintf("storage class %s of groups %s does not exist", name, users))
+		} else if err == nil && class.Provisioner == localVolumeProvisioner &&
+			(class.VolumeBindingMode == nil || *class.VolumeBindingMode != storagev1.VolumeBindingWaitForFirstConsumer) {
+			// Binding a local volume before the pod is scheduled may pick a
+			// node the pod cannot run on.
+			problems = append(problems, fmt.Sprintf("storage class %s of groups %s provides local volumes and needs volumeBindingMode WaitForFirstConsumer", name, users))
+		} else if err != nil {
+			cc.ReqLogger.Error(err, "Failed to read a storage class, it is not checked", "storageClass", name)
+		}
+	}
+	return problems
+}
+
+func hasDefaultStorageClass(classes []storagev1.StorageClass) bool {
+	for _, class := range classes {
+		if class.Annotations[defaultStorageClassAnnotation] == "true" || class.Annotations[betaDefaultStorageClassAnnotation] == "true" {
+			return true
+		}
+	}
+	return false
+}
+
+// nodeFitProblems checks that the resource requests of every group fit the
+// allocatable resources of a schedulable node matching its nodeSelector.
+// Taints, affinities and the requests of pods already on the nodes are not
+// taken into account.
+func (cc *ClusterContext) nodeFitProblems(groups []*MarkLogicGroupParameters) []string {
+	problems := []string{}
+	for _, group := range groups {
+		if group.Resources == nil || len(group.Resources.Requests) == 0 {
+			continue
+		}
+		nodes := &corev1.NodeList{}
+		if err := cc.Client.List(cc.Ctx, nodes, client.MatchingLabels(group.NodeSelector)); err != nil {
+			cc.ReqLogger.Error(err, "Failed to list the nodes, the resource requests are not checked", "group", group.Name)
+			return problems
+		}
+		schedulable := 0
+		fits := false
+		for _, node := range nodes.Items {
+			if node.Spec.Unschedulable {
+				continue
+			}
+			schedulable++
+			if resourcesFit(group.Resources.Requests, node.Status.Allocatable) {
+				fits = true
+				break
+			}
+		}
+		switch {
+		case fits:
+		case schedulable == 0 && len(group.NodeSelector) == 0:
+			// Without nodes, a cluster autoscaler is left to provide them.
+		case schedulable == 0:
+			problems = append(problems, fmt.Sprintf("no schedulable node matches the nodeSelector of group %s", group.Name))
+		default:
+			problems = append(problems, fmt.Sprintf("the requests %s of group %s do not fit the allocatable resources of any of the %d schedulable nodes",
+				formatResourceList(group.Resources.Requests), group.Name, schedulable))
+		}
+	}
+	return problems
+}
+
+func resourcesFit(requests, allocatable corev1.ResourceList) bool {
+	for name, request := range requests {
+		available, ok := allocatable[name]
+		if !ok || available.Cmp(request) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func formatResourceList(list corev1.ResourceList) string {
+	names := make([]string, 0, len(list))
+	for name := range list {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		quantity := list[corev1.ResourceName(name)]
+		parts = append(parts, fmt.Sprintf("%s=%s", name, quantity.String()))
+	}
+	return strings.Join(parts, ",")
+}
+
+// imageProblems checks that the images of the groups exist in their
+// registry. Registries the operator cannot reach or is not allowed to read
+// are not checked, as the nodes may pull through a mirror or with their own
+// credentials.
+func (cc *ClusterContext) imageProblems(groups []*MarkLogicGroupParameters) []string {
+	if Offline {
+		return nil
+	}
+	creds, err := cc.registryCredentials()
+	if err != nil {
+		cc.ReqLogger.Error(err, "Failed to read the image pull secrets, the images are not checked")
+		return nil
+	}
+	problems := []string{}
+	seen := map[string]bool{}
+	for _, group := range groups {
+		if group.Image == "" || seen[group.Image] {
+			continue
+		}
+		seen[group.Image] = true
+		_, err := ImagePlatforms(cc.Ctx, group.Image, registry.Options{Credentials: creds})
+		if registry.IsNotFound(err) {
+			problems = append(problems, fmt.Sprintf("image %s of group %s does not exist in its registry", group.Image, group.Name))
+		} else if err != nil {
+			cc.ReqLogger.Info("The image could not be read from its registry, it is not checked", "image", group.Image, "error", err.Error())
+		}
+	}
+	return problems
+}
+
+// sccProblems checks the fsGroup and user of the pods against the ranges the
+// OpenShift SCC of the namespace allows. Namespaces without SCC ranges are
+// not checked; with spec.ocpCompatible the operator adapts the pods instead.
+func (cc *ClusterContext) sccProblems(groups []*MarkLogicGroupParameters) []string {
+	namespace := &corev1.Namespace{}
+	if err := cc.Client.Get(cc.Ctx, types.NamespacedName{Name: cc.MarklogicCluster.Namespace}, namespace); err != nil {
+		cc.ReqLogger.Error(err, "Failed to read the namespace, the SCC ranges are not checked")
+		return nil
+	}
+	annotations := namespace.GetAnnotations()
+	if annotations[sccUIDRangeAnnotation] == "" && annotations[sccSupplementalGroupsAnnotation] == "" {
+		return nil
+	}
+	ranges := sccRanges{
+		uids:   parseIDRanges(annotations[sccUIDRangeAnnotation]),
+		groups: parseIDRanges(annotations[sccSupplementalGroupsAnnotation]),
+	}
+	problems := []string{}
+	for _, group := range groups {
+		fsGroup := int64Ptr(defaultMarkLogicFSGroup)
+		var runAsUser *int64
+		if group.PodSecurityContext != nil {
+			fsGroup = group.PodSecurityContext.FSGroup
+			runAsUser = group.PodSecurityContext.RunAsUser
+		}
+		if !allowedID(fsGroup, ranges.groups) {
+			problems = append(problems, fmt.Sprintf("fsGroup %d of group %s is outside the range %s the OpenShift SCC of namespace %s allows; set ocpCompatible",
+				*fsGroup, group.Name, annotations[sccSupplementalGroupsAnnotation], namespace.Name))
+		}
+		if !allowedID(runAsUser, ranges.uids) {
+			problems = append(problems, fmt.Sprintf("runAsUser %d of group %s is outside the range %s the OpenShift SCC of namespace %s allows; set ocpCompatible",
+				*runAsUser, group.Name, annotations[sccUIDRangeAnnotation], namespace.Name))
+		}
+	}
+	return problems
+}
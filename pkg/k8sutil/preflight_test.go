@@ -0,0 +1,196 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/registry"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func preflightCondition(cr *marklogicv1.MarklogicCluster) *metav1.Condition {
+	for i := range cr.Status.Conditions {
+		if cr.Status.Conditions[i].Type == string(marklogicv1.PreflightFailed) {
+			return &cr.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+func TestReconcilePreflightReportsEnvironmentProblems(t *testing.T) {
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default", Generation: 1},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			Image:       upgradeTestNewImage,
+			Persistence: &marklogicv1.Persistence{Enabled: true, Size: "10Gi", StorageClassName: "fast"},
+			Resources: &corev1.ResourceRequirements{Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("8"),
+				corev1.ResourceMemory: resource.MustParse("64Gi"),
+			}},
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{
+				{Name: "dnode", IsBootstrap: true},
+				{Name: "enode", Image: "progressofficial/marklogic-db:12.0.3-missing"},
+			},
+		},
+	}
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "small"},
+		Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("4"),
+			corev1.ResourceMemory: resource.MustParse("16Gi"),
+		}},
+	}
+	cc := newUpgradeTestContext(t, cr, node)
+
+	// The missing image is looked up in a registry that does not have it.
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+	original := ImagePlatforms
+	t.Cleanup(func() { ImagePlatforms = original })
+	ImagePlatforms = func(ctx context.Context, image string, opts registry.Options) ([]registry.Platform, error) {
+		if strings.HasSuffix(image, "-missing") {
+			host := strings.TrimPrefix(server.URL, "https://")
+			return registry.ImagePlatforms(ctx, host+"/marklogic-db:missing", registry.Options{HTTPClient: server.Client()})
+		}
+		return []registry.Platform{{OS: "linux", Architecture: "amd64"}}, nil
+	}
+
+	res := cc.ReconcilePreflight()
+	if !res.Completed() {
+		t.Fatalf("expected the reconcile to stop while the pre-flight checks fail")
+	}
+	condition := preflightCondition(cr)
+	if condition == nil || condition.Status != metav1.ConditionTrue {
+		t.Fatalf("expected the PreflightFailed condition to be True, got %+v", condition)
+	}
+	for _, expected := range []string{
+		"storage class fast of groups dnode, enode does not exist",
+		"the requests cpu=8,memory=64Gi of group dnode do not fit",
+		"image progressofficial/marklogic-db:12.0.3-missing of group enode does not exist",
+	} {
+		if !strings.Contains(condition.Message, expected) {
+			t.Fatalf("expected %q in the condition message, got %q", expected, condition.Message)
+		}
+	}
+
+	storageClass := &storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "fast"}, Provisioner: "ebs.csi.aws.com"}
+	if err := cc.Client.Create(cc.Ctx, storageClass); err != nil {
+		t.Fatalf("failed to create the storage class: %v", err)
+	}
+	if err := cc.Client.Get(cc.Ctx, client.ObjectKeyFromObject(node), node); err != nil {
+		t.Fatalf("failed to read the node: %v", err)
+	}
+	node.Status.Allocatable[corev1.ResourceCPU] = resource.MustParse("16")
+	node.Status.Allocatable[corev1.ResourceMemory] = resource.MustParse("128Gi")
+	if err := cc.Client.Status().Update(cc.Ctx, node); err != nil {
+		t.Fatalf("failed to update the node: %v", err)
+	}
+	cr.Spec.MarkLogicGroups[1].Image = ""
+	if res := cc.ReconcilePreflight(); res.Completed() {
+		t.Fatalf("expected the reconcile to continue once the checks pass, got %+v", preflightCondition(cr))
+	}
+	if condition := preflightCondition(cr); condition == nil || condition.Status != metav1.ConditionFalse {
+		t.Fatalf("expected the PreflightFailed condition to be False, got %+v", condition)
+	}
+
+	ImagePlatforms = func(ctx context.Context, image string, opts registry.Options) ([]registry.Platform, error) {
+		t.Fatalf("expected no pre-flight checks once they passed")
+		return nil, nil
+	}
+	cc.ReconcilePreflight()
+}
+
+func TestReconcilePreflightChecksOpenShiftSCCRanges(t *testing.T) {
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "apps"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			Image:           upgradeTestNewImage,
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{{Name: "dnode", IsBootstrap: true}},
+		},
+	}
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "apps", Annotations: map[string]string{
+		sccUIDRangeAnnotation:           "1000650000/10000",
+		sccSupplementalGroupsAnnotation: "1000650000/10000",
+	}}}
+	cc := newUpgradeTestContext(t, cr, namespace)
+	Offline = true
+	t.Cleanup(func() { Offline = false })
+
+	cc.ReconcilePreflight()
+	condition := preflightCondition(cr)
+	if condition == nil || condition.Status != metav1.ConditionTrue || !strings.Contains(condition.Message, "fsGroup 2 of group dnode is outside the range 1000650000/10000") {
+		t.Fatalf("expected the default fsGroup to be rejected, got %+v", condition)
+	}
+
+	cr.Spec.OCPCompatible = true
+	cc.ReconcilePreflight()
+	if condition := preflightCondition(cr); condition == nil || condition.Status != metav1.ConditionFalse {
+		t.Fatalf("expected ocpCompatible to pass the checks, got %+v", condition)
+	}
+}
+
+func TestReconcilePreflightSkipsExistingClusters(t *testing.T) {
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			Image:           upgradeTestNewImage,
+			Persistence:     &marklogicv1.Persistence{Enabled: true, Size: "10Gi", StorageClassName: "gone"},
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{{Name: "dnode", IsBootstrap: true}},
+		},
+	}
+	group := &marklogicv1.MarklogicGroup{ObjectMeta: metav1.ObjectMeta{Name: "dnode", Namespace: "default"}}
+	cc := newUpgradeTestContext(t, cr, group)
+
+	if res := cc.ReconcilePreflight(); res.Completed() || preflightCondition(cr) != nil {
+		t.Fatalf("expected a cluster with existing groups not to be checked")
+	}
+}
+
+func TestReconcilePreflightRequiresLateBindingForLocalVolumes(t *testing.T) {
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			Image:           upgradeTestNewImage,
+			Persistence:     &marklogicv1.Persistence{Enabled: true, Size: "10Gi", StorageClassName: "local"},
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{{Name: "dnode", IsBootstrap: true}},
+			OCPCompatible:   true,
+		},
+	}
+	immediate := storagev1.VolumeBindingImmediate
+	storageClass := &storagev1.StorageClass{
+		ObjectMeta:        metav1.ObjectMeta{Name: "local"},
+		Provisioner:       localVolumeProvisioner,
+		VolumeBindingMode: &immediate,
+	}
+	cc := newUpgradeTestContext(t, cr, storageClass)
+	Offline = true
+	t.Cleanup(func() { Offline = false })
+
+	cc.ReconcilePreflight()
+	condition := preflightCondition(cr)
+	if condition == nil || condition.Status != metav1.ConditionTrue || !strings.Contains(condition.Message, "storage class local of groups dnode provides local volumes") {
+		t.Fatalf("expected the Immediate binding mode to be rejected, got %+v", condition)
+	}
+
+	waitForFirstConsumer := storagev1.VolumeBindingWaitForFirstConsumer
+	storageClass.VolumeBindingMode = &waitForFirstConsumer
+	if err := cc.Client.Update(cc.Ctx, storageClass); err != nil {
+		t.Fatalf("failed to update the storage class: %v", err)
+	}
+	cc.ReconcilePreflight()
+	if condition := preflightCondition(cr); condition == nil || condition.Status != metav1.ConditionFalse {
+		t.Fatalf("expected WaitForFirstConsumer to pass the checks, got %+v", condition)
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NodePoolLabel is the node label the group profiles prefer to schedule on,
+// for example marklogic.progress.com/node-pool=dnode.
+const NodePoolLabel = "marklogic.progress.com/node-pool"
+
+// applyGroupProfile sets the defaults of the group's profile. It runs before
+// the group-level overrides are applied, so only cluster-wide values are
+// replaced.
+func applyGroupProfile(params *MarkLogicGroupParameters, group *marklogicv1.MarklogicGroups) {
+	var cpu string
+	switch group.Profile {
+	case marklogicv1.GroupProfileDNode:
+		cpu = "2"
+	case marklogicv1.GroupProfileENode:
+		// Evaluators spend their time on query evaluation rather than I/O.
+		cpu = "4"
+	default:
+		return
+	}
+	// MarkLogic recommends huge pages of about 3/8 of the memory.
+	memory, hugePages := "8Gi", "3Gi"
+
+	resources := &corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(cpu),
+			corev1.ResourceMemory: resource.MustParse(memory),
+		},
+		Limits: corev1.ResourceList{
+			corev1.ResourceMemory: resource.MustParse(memory),
+		},
+	}
+	params.HugePages = &marklogicv1.HugePages{Enabled: false, MountPath: "/dev/hugepages"}
+	// Huge pages must be pre-allocated on the nodes, which default node pools
+	// are not, so the profile only requests them when the group enables them.
+	// They need a matching resource limit, so they only come with the profile
+	// resources.
+	if group.Resources == nil && group.HugePages != nil && group.HugePages.Enabled {
+		hugePagesResource := corev1.ResourceName(corev1.ResourceHugePagesPrefix + "2Mi")
+		resources.Requests[hugePagesResource] = resource.MustParse(hugePages)
+		resources.Limits[hugePagesResource] = resource.MustParse(hugePages)
+		params.HugePages.Enabled = true
+	}
+	params.Resources = resources
+	params.Affinity = profileAffinity(group.Name, group.Profile)
+}
+
+// profileAffinity prefers nodes of the profile's node pool and spreads the
+// hosts of the group across nodes. Both are preferences, so the node pool
+// labels are not required to schedule the pods.
+func profileAffinity(groupName string, profile marklogicv1.GroupProfile) *corev1.Affinity {
+	return &corev1.Affinity{
+		NodeAffinity: &corev1.NodeAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []corev1.PreferredSchedulingTerm{{
+				Weight: 100,
+				Preference: corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{{
+					Key:      NodePoolLabel,
+					Operator: corev1.NodeSelectorOpIn,
+					Values:   []string{string(profile)},
+				}}},
+			}},
+		},
+		PodAntiAffinity: &corev1.PodAntiAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{{
+				Weight: 100,
+				PodAffinityTerm: corev1.PodAffinityTerm{
+					TopologyKey: corev1.LabelHostname,
+					LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{
+						"app.kubernetes.io/name":     "marklogic",
+						"app.kubernetes.io/instance": groupName,
+					}},
+				},
+			}},
+		},
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/version"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const pvcReasonDeleted = "PersistentVolumeClaimDeleted"
+
+// SupportsStatefulSetPVCRetention reports whether the StatefulSets of a
+// Kubernetes version honor persistentVolumeClaimRetentionPolicy without a
+// feature gate, which they do from 1.27.
+func SupportsStatefulSetPVCRetention(info *version.Info) bool {
+	major, err := strconv.Atoi(info.Major)
+	if err != nil {
+		return true
+	}
+	// Managed offerings report versions such as "27+".
+	minor, err := strconv.Atoi(strings.TrimRight(info.Minor, "+"))
+	if err != nil {
+		return true
+	}
+	return major > 1 || minor >= 27
+}
+
+// pvcRetentionPolicy returns the retention policy of the data volumes of a
+// group, nil when it has none or no data volumes.
+func pvcRetentionPolicy(persistence *marklogicv1.Persistence) *marklogicv1.PVCRetentionPolicy {
+	if persistence == nil || !persistence.Enabled {
+		return nil
+	}
+	return persistence.RetentionPolicy
+}
+
+func deletesPVCs(policy marklogicv1.PVCRetentionPolicyType) bool {
+	return policy == marklogicv1.PVCRetentionPolicyDelete
+}
+
+// statefulSetPVCRetentionPolicy returns the persistentVolumeClaimRetentionPolicy
+// of the StatefulSet of a group, nil when the group has no retention policy
+// or the operator applies it. A group scaled to zero, as a stopped cluster
+// is, keeps its volumes.
+func statefulSetPVCRetentionPolicy(cr *marklogicv1.MarklogicGroup) *appsv1.StatefulSetPersistentVolumeClaimRetentionPolicy {
+	policy := pvcRetentionPolicy(cr.Spec.Persistence)
+	if policy == nil || !StatefulSetPVCRetention {
+		return nil
+	}
+	retention := &appsv1.StatefulSetPersistentVolumeClaimRetentionPolicy{
+		WhenDeleted: appsv1.RetainPersistentVolumeClaimRetentionPolicyType,
+		WhenScaled:  appsv1.RetainPersistentVolumeClaimRetentionPolicyType,
+	}
+	if deletesPVCs(policy.WhenDeleted) {
+		retention.WhenDeleted = appsv1.DeletePersistentVolumeClaimRetentionPolicyType
+	}
+	if deletesPVCs(policy.WhenScaled) && (cr.Spec.Replicas == nil || *cr.Spec.Replicas > 0) {
+		retention.WhenScaled = appsv1.DeletePersistentVolumeClaimRetentionPolicyType
+	}
+	return retention
+}
+
+// ReconcilePVCRetention applies the retention policy of the data volumes on
+// Kubernetes versions whose StatefulSets do not: the volumes to delete with
+// the group are owned by the MarklogicGroup, so the garbage collector deletes
+// them with it, and the volumes of the pods removed by a scale-down are
+// deleted once the pods are gone.
+func (oc *OperatorContext) ReconcilePVCRetention() result.ReconcileResult {
+	cr := oc.MarklogicGroup
+	if cr.Spec.Persistence == nil || !cr.Spec.Persistence.Enabled {
+		return result.Continue()
+	}
+	policy := pvcRetentionPolicy(cr.Spec.Persistence)
+	pvcs, err := oc.groupDataPVCs()
+	if err != nil {
+		return result.Error(err)
+	}
+	ownedByGroup := !StatefulSetPVCRetention && policy != nil && deletesPVCs(policy.WhenDeleted)
+	for i := range pvcs {
+		if err := oc.setPVCGroupOwner(&pvcs[i], ownedByGroup); err != nil {
+			return result.Error(err)
+		}
+	}
+	if StatefulSetPVCRetention || policy == nil || !deletesPVCs(policy.WhenScaled) || cr.Spec.Replicas == nil || *cr.Spec.Replicas == 0 {
+		return result.Continue()
+	}
+	for i := range pvcs {
+		pvc := &pvcs[i]
+		if parseOrdinalFromName(pvc.Name) < int(*cr.Spec.Replicas) || pvc.DeletionTimestamp != nil {
+			continue
+		}
+		podName := derivePodNameFromPVC(cr.Spec.Name, pvc.Name)
+		err := oc.Client.Get(oc.Ctx, types.NamespacedName{Name: podName, Namespace: cr.Namespace}, &corev1.Pod{})
+		if err == nil {
+			// The pod is still being removed.
+			continue
+		}
+		if !apierrors.IsNotFound(err) {
+			return result.Error(err)
+		}
+		if err := oc.Client.Delete(oc.Ctx, pvc); err != nil && !apierrors.IsNotFound(err) {
+			return result.Error(err)
+		}
+		oc.ReqLogger.Info("Deleted the PersistentVolumeClaim of a removed pod", "pvc", pvc.Name, "pod", podName)
+		oc.recordGroupEvent(corev1.EventTypeNormal, pvcReasonDeleted,
+			fmt.Sprintf("PersistentVolumeClaim %s of the removed pod %s is deleted by the whenScaled retention policy", pvc.Name, podName))
+	}
+	return result.Continue()
+}
+
+// groupDataPVCs returns the data volume claims of the pods of the group.
+func (oc *OperatorContext) groupDataPVCs() ([]corev1.PersistentVolumeClaim, error) {
+	cr := oc.MarklogicGroup
+	list := &corev1.PersistentVolumeClaimList{}
+	if err := oc.Client.List(oc.Ctx, list, client.InNamespace(cr.Namespace)); err != nil {
+		return nil, err
+	}
+	prefix := fmt.Sprintf("%s-%s-", dataDirPVCName, cr.Spec.Name)
+	pvcs := []corev1.PersistentVolumeClaim{}
+	for _, pvc := range list.Items {
+		if strings.HasPrefix(pvc.Name, prefix) && derivePodNameFromPVC(cr.Spec.Name, pvc.Name) != "" {
+			pvcs = append(pvcs, pvc)
+		}
+	}
+	return pvcs, nil
+}
+
+// setPVCGroupOwner adds or removes the MarklogicGroup from the owners of a
+// volume claim.
+func (oc *OperatorContext) setPVCGroupOwner(pvc *corev1.PersistentVolumeClaim, owned bool) error {
+	cr := oc.MarklogicGroup
+	index := -1
+	for i, ref := range pvc.OwnerReferences {
+		if ref.UID == cr.UID {
+			index = i
+		}
+	}
+	if (index >= 0) == owned {
+		return nil
+	}
+	patchBase := client.MergeFrom(pvc.DeepCopy())
+	if owned {
+		pvc.OwnerReferences = append(pvc.OwnerReferences, metav1.OwnerReference{
+			APIVersion: marklogicv1.GroupVersion.String(),
+			Kind:       "MarklogicGroup",
+			Name:       cr.Name,
+			UID:        cr.UID,
+		})
+	} else {
+		pvc.OwnerReferences = append(pvc.OwnerReferences[:index], pvc.OwnerReferences[index+1:]...)
+	}
+	return oc.Client.Patch(oc.Ctx, pvc, patchBase)
+}
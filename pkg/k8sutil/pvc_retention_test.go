@@ -0,0 +1,108 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"testing"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/version"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func newRetentionTestPVC(name string) *corev1.PersistentVolumeClaim {
+	return &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+}
+
+func TestSupportsStatefulSetPVCRetention(t *testing.T) {
+	for _, v := range []struct {
+		major, minor string
+		expected     bool
+	}{
+		{"1", "26", false},
+		{"1", "27", true},
+		{"1", "25+", false},
+		{"1", "30+", true},
+		{"", "", true},
+	} {
+		if got := SupportsStatefulSetPVCRetention(&version.Info{Major: v.major, Minor: v.minor}); got != v.expected {
+			t.Fatalf("expected %v for %s.%s, got %v", v.expected, v.major, v.minor, got)
+		}
+	}
+}
+
+func TestStatefulSetPVCRetentionPolicy(t *testing.T) {
+	group := newSecretRotationTestGroup()
+	group.Spec.Persistence = &marklogicv1.Persistence{Enabled: true, Size: "10Gi"}
+	if policy := generateStatefulSetsParams(group).PVCRetentionPolicy; policy != nil {
+		t.Fatalf("expected no retention policy by default, got %+v", policy)
+	}
+
+	group.Spec.Persistence.RetentionPolicy = &marklogicv1.PVCRetentionPolicy{
+		WhenDeleted: marklogicv1.PVCRetentionPolicyDelete,
+		WhenScaled:  marklogicv1.PVCRetentionPolicyDelete,
+	}
+	policy := generateStatefulSetsParams(group).PVCRetentionPolicy
+	if policy == nil || policy.WhenDeleted != appsv1.DeletePersistentVolumeClaimRetentionPolicyType ||
+		policy.WhenScaled != appsv1.DeletePersistentVolumeClaimRetentionPolicyType {
+		t.Fatalf("expected the volumes to be deleted, got %+v", policy)
+	}
+
+	// A stopped cluster scales its groups to zero and keeps their data.
+	zero := int32(0)
+	group.Spec.Replicas = &zero
+	if policy := generateStatefulSetsParams(group).PVCRetentionPolicy; policy.WhenScaled != appsv1.RetainPersistentVolumeClaimRetentionPolicyType {
+		t.Fatalf("expected a group scaled to zero to keep its volumes, got %+v", policy)
+	}
+
+	StatefulSetPVCRetention = false
+	t.Cleanup(func() { StatefulSetPVCRetention = true })
+	if policy := generateStatefulSetsParams(group).PVCRetentionPolicy; policy != nil {
+		t.Fatalf("expected no StatefulSet policy when the operator applies it, got %+v", policy)
+	}
+}
+
+func TestReconcilePVCRetentionWithoutStatefulSetSupport(t *testing.T) {
+	StatefulSetPVCRetention = false
+	t.Cleanup(func() { StatefulSetPVCRetention = true })
+	group := newSecretRotationTestGroup()
+	group.UID = "group-uid"
+	group.Spec.Persistence = &marklogicv1.Persistence{Enabled: true, Size: "10Gi", RetentionPolicy: &marklogicv1.PVCRetentionPolicy{
+		WhenDeleted: marklogicv1.PVCRetentionPolicyDelete,
+		WhenScaled:  marklogicv1.PVCRetentionPolicyDelete,
+	}}
+	oc := newSecretRotationTestContext(t, group,
+		newRetentionTestPVC("datadir-dnode-0"), newRetentionTestPVC("datadir-dnode-1"),
+		newRetentionTestPVC("datadir-dnode-2"), newRetentionTestPVC("datadir-dnode-3"),
+		newRetentionTestPVC("datadir-enode-5"), newGroupTestPod("dnode-3", true))
+
+	if res := oc.ReconcilePVCRetention(); res.Completed() {
+		t.Fatalf("expected the reconcile to continue")
+	}
+	pvc := &corev1.PersistentVolumeClaim{}
+	if err := oc.Client.Get(oc.Ctx, client.ObjectKey{Name: "datadir-dnode-0", Namespace: "default"}, pvc); err != nil {
+		t.Fatalf("failed to read the pvc: %v", err)
+	}
+	if len(pvc.OwnerReferences) != 1 || pvc.OwnerReferences[0].UID != group.UID || pvc.OwnerReferences[0].Kind != "MarklogicGroup" {
+		t.Fatalf("expected the group to own the pvc, got %+v", pvc.OwnerReferences)
+	}
+	// The volume of a removed pod is deleted, that of a pod still shutting down is not.
+	if err := oc.Client.Get(oc.Ctx, client.ObjectKey{Name: "datadir-dnode-2", Namespace: "default"}, pvc); !apierrors.IsNotFound(err) {
+		t.Fatalf("expected the pvc of the removed pod to be deleted, got %v", err)
+	}
+	for _, name := range []string{"datadir-dnode-1", "datadir-dnode-3", "datadir-enode-5"} {
+		if err := oc.Client.Get(oc.Ctx, client.ObjectKey{Name: name, Namespace: "default"}, pvc); err != nil {
+			t.Fatalf("expected pvc %s to be kept, got %v", name, err)
+		}
+	}
+
+	group.Spec.Persistence.RetentionPolicy.WhenDeleted = marklogicv1.PVCRetentionPolicyRetain
+	oc.ReconcilePVCRetention()
+	if err := oc.Client.Get(oc.Ctx, client.ObjectKey{Name: "datadir-dnode-0", Namespace: "default"}, pvc); err != nil || len(pvc.OwnerReferences) != 0 {
+		t.Fatalf("expected the group to no longer own the pvc, got %+v, %v", pvc.OwnerReferences, err)
+	}
+}
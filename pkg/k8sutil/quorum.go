@@ -0,0 +1,206 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// AcknowledgeQuorumLossAnnotation acknowledges a quorum loss once the
+	// cluster has its quorum again, so the operator resumes restarting and
+	// upgrading pods. Changing the value makes a new acknowledgment.
+	AcknowledgeQuorumLossAnnotation = "marklogic.progress.com/acknowledge-quorum-loss"
+
+	// quorumCheckInterval is how often the hosts are checked for quorum.
+	quorumCheckInterval = time.Minute
+	// quorumLossGracePeriod is how long the cluster is without quorum before
+	// the loss is reported, so a host that restarts does not trip it.
+	quorumLossGracePeriod = 2 * time.Minute
+
+	quorumReasonLost         = "QuorumLost"
+	quorumReasonRestored     = "QuorumRestored"
+	quorumReasonAcknowledged = "QuorumLossAcknowledged"
+)
+
+// ReconcileQuorum checks every quorumCheckInterval that more than half of the
+// MarkLogic hosts are online, as the cluster needs them to keep its quorum.
+// Once the quorum is lost for longer than quorumLossGracePeriod, the
+// QuorumLost condition is set and a Warning event is recorded at every check,
+// even after the quorum is restored, until the loss is acknowledged with the
+// AcknowledgeQuorumLossAnnotation. Until then the rest of the reconcile is
+// held, so no pod is restarted, recovered or upgraded while the cluster is
+// split. The check is skipped while the cluster is stopped, and a Manage API
+// that cannot be reached leaves the condition as it is.
+func (cc *ClusterContext) ReconcileQuorum() result.ReconcileResult {
+	cr := cc.MarklogicCluster
+	if clusterStopped(cr) {
+		return quorumHold(cr)
+	}
+	now := metav1.Now()
+	status := &marklogicv1.QuorumStatus{}
+	if cr.Status.Quorum != nil {
+		status = cr.Status.Quorum.DeepCopy()
+	}
+	lost := quorumLost(cr)
+	var condition *metav1.Condition
+
+	if status.LastCheckTime == nil || now.Sub(status.LastCheckTime.Time) >= quorumCheckInterval {
+		online, total, offline, err := cc.onlineHosts()
+		if err != nil {
+			cc.ReqLogger.Error(err, "Failed to check the quorum of the cluster")
+			if lost {
+				cc.recordClusterEvent(corev1.EventTypeWarning, quorumReasonLost,
+					fmt.Sprintf("the cluster lost its quorum at %s and the Manage API cannot be reached: %v", quorumLostSince(cr), err))
+			}
+			return quorumHold(cr)
+		}
+		status.LastCheckTime = &now
+		if total > 0 {
+			status.OnlineHosts, status.TotalHosts = online, total
+			if online*2 > total {
+				status.LostSince = nil
+			} else if status.LostSince == nil {
+				status.LostSince = &now
+			}
+		}
+		message := fmt.Sprintf("%d of %d hosts are online, more than half must be for quorum", status.OnlineHosts, status.TotalHosts)
+		if len(offline) > 0 {
+			message += fmt.Sprintf("; offline: %s", strings.Join(offline, ", "))
+		}
+		switch {
+		case status.LostSince != nil && (lost || now.Sub(status.LostSince.Time) >= quorumLossGracePeriod):
+			condition = &metav1.Condition{Status: metav1.ConditionTrue, Reason: quorumReasonLost, Message: message}
+			cc.ReqLogger.Info("The cluster lost its quorum, holding restarts and upgrades", "online", status.OnlineHosts, "hosts", status.TotalHosts)
+			cc.recordClusterEvent(corev1.EventTypeWarning, quorumReasonLost, fmt.Sprintf(
+				"the cluster lost its quorum: %s. No pod is restarted or upgraded until the loss is acknowledged with %s",
+				message, AcknowledgeQuorumLossAnnotation))
+		case lost:
+			condition = &metav1.Condition{Status: metav1.ConditionTrue, Reason: quorumReasonRestored, Message: fmt.Sprintf(
+				"the quorum was restored, %d of %d hosts are online; acknowledge the loss with %s to resume restarts and upgrades",
+				status.OnlineHosts, status.TotalHosts, AcknowledgeQuorumLossAnnotation)}
+			cc.recordClusterEvent(corev1.EventTypeWarning, quorumReasonLost, fmt.Sprintf(
+				"the cluster lost its quorum at %s and is held until the loss is acknowledged with %s", quorumLostSince(cr), AcknowledgeQuorumLossAnnotation))
+		}
+	}
+
+	if request := ClusterOperation(cr, AcknowledgeQuorumLossAnnotation); request != "" && request != status.AcknowledgedRequestID {
+		status.AcknowledgedRequestID = request
+		switch {
+		case !lost:
+		case status.LostSince != nil:
+			cc.recordClusterEvent(corev1.EventTypeWarning, quorumReasonLost, fmt.Sprintf(
+				"ignored the acknowledgment %s, the cluster has no quorum yet; acknowledge again once it is restored", request))
+		default:
+			condition = &metav1.Condition{Status: metav1.ConditionFalse, Reason: quorumReasonAcknowledged, Message: fmt.Sprintf(
+				"the quorum loss was acknowledged with request %s by %s", request, operationFieldManager(cr, AcknowledgeQuorumLossAnnotation))}
+			cc.ReqLogger.Info("The quorum loss was acknowledged, resuming restarts and upgrades", "request", request)
+			cc.recordClusterEvent(corev1.EventTypeNormal, quorumReasonAcknowledged, condition.Message)
+		}
+	}
+
+	if equality.Semantic.DeepEqual(status, cr.Status.Quorum) && condition == nil {
+		return quorumHold(cr)
+	}
+	patchBase := client.MergeFrom(cr.DeepCopy())
+	cr.Status.Quorum = status
+	if condition != nil {
+		cc.setClusterCondition(marklogicv1.QuorumLost, condition.Status, condition.Reason, condition.Message)
+	}
+	if err := patchStatus(cc.Ctx, cc.Client, cr, patchBase); err != nil {
+		cc.ReqLogger.Error(err, "Failed to update the quorum in the cluster status")
+	}
+	return quorumHold(cr)
+}
+
+// onlineHosts returns how many hosts of the cluster are online, how many it
+// has and the names of the offline hosts.
+func (cc *ClusterContext) onlineHosts() (int32, int32, []string, error) {
+	mgmt, err := cc.newBootstrapManagementClient()
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	hosts, err := mgmt.ListHostsStatus(cc.Ctx)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	online := int32(0)
+	offline := []string{}
+	for _, host := range hosts {
+		if host.Online {
+			online++
+		} else {
+			offline = append(offline, host.Name)
+		}
+	}
+	sort.Strings(offline)
+	return online, int32(len(hosts)), offline, nil
+}
+
+// quorumHold holds the rest of the reconcile while the cluster is without
+// quorum or the loss was not acknowledged.
+func quorumHold(cr *marklogicv1.MarklogicCluster) result.ReconcileResult {
+	if quorumLost(cr) {
+		return result.RequeueSoon(int(quorumCheckInterval.Seconds()))
+	}
+	return result.Continue()
+}
+
+// quorumLost reports whether the cluster lost its quorum and the loss was
+// not acknowledged yet.
+func quorumLost(cr *marklogicv1.MarklogicCluster) bool {
+	return cr.Status.GetConditionStatus(string(marklogicv1.QuorumLost)) == metav1.ConditionTrue
+}
+
+// quorumLostSince returns when the QuorumLost condition was set.
+func quorumLostSince(cr *marklogicv1.MarklogicCluster) string {
+	for _, condition := range cr.Status.Conditions {
+		if condition.Type == string(marklogicv1.QuorumLost) {
+			return condition.LastTransitionTime.UTC().Format(time.RFC3339)
+		}
+	}
+	return "unknown"
+}
+
+// ReconcileQuorumProtection holds the changes of a group that restart its
+// pods, such as volume resizes, storage migrations and secret rotations,
+// while its cluster lost its quorum.
+func (oc *OperatorContext) ReconcileQuorumProtection() result.ReconcileResult {
+	for _, ownerRef := range oc.MarklogicGroup.OwnerReferences {
+		if ownerRef.Kind != "MarklogicCluster" {
+			continue
+		}
+		cluster := &marklogicv1.MarklogicCluster{}
+		if err := oc.Client.Get(oc.Ctx, types.NamespacedName{Name: ownerRef.Name, Namespace: oc.MarklogicGroup.Namespace}, cluster); err != nil {
+			if !apierrors.IsNotFound(err) {
+				oc.ReqLogger.Error(err, "Failed to read the cluster for its quorum", "cluster", ownerRef.Name)
+			}
+			return result.Continue()
+		}
+		if quorumLost(cluster) {
+			oc.ReqLogger.Info("Holding the changes of the group while its cluster lost its quorum", "cluster", cluster.Name)
+			return result.RequeueSoon(int(quorumCheckInterval.Seconds()))
+		}
+	}
+	return result.Continue()
+}
+
+// nextQuorumCheck is when the hosts are checked for quorum next.
+func nextQuorumCheck(cr *marklogicv1.MarklogicCluster) time.Time {
+	if cr.Status.Quorum == nil || cr.Status.Quorum.LastCheckTime == nil {
+		return time.Time{}
+	}
+	return cr.Status.Quorum.LastCheckTime.Add(quorumCheckInterval)
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestReconcileQuorumHoldsUntilAcknowledged(t *testing.T) {
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			ClusterDomain:   "cluster.local",
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{{Name: "dnode", IsBootstrap: true}},
+		},
+	}
+	cc := newUpgradeTestContext(t, cr)
+	recorder := cc.Recorder.(*record.FakeRecorder)
+	hosts := []mlmanage.HostStatus{{Name: "dnode-0", Online: true}, {Name: "dnode-1"}, {Name: "dnode-2"}}
+	original := NewDynamicManagementClient
+	NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+		return &stubDynamicManagementClient{hostsStatusFn: func() ([]mlmanage.HostStatus, error) { return hosts, nil }}
+	}
+	t.Cleanup(func() { NewDynamicManagementClient = original })
+	events := func(reason string) int {
+		count := 0
+		for len(recorder.Events) > 0 {
+			if strings.Contains(<-recorder.Events, reason) {
+				count++
+			}
+		}
+		return count
+	}
+	// checkAgain makes the next check due, the loss being older than the
+	// grace period.
+	checkAgain := func() {
+		t.Helper()
+		past := metav1.NewTime(time.Now().Add(-quorumLossGracePeriod))
+		cr.Status.Quorum.LastCheckTime = &past
+		if cr.Status.Quorum.LostSince != nil {
+			cr.Status.Quorum.LostSince = &past
+		}
+		if err := cc.Client.Status().Update(cc.Ctx, cr); err != nil {
+			t.Fatalf("failed to update cluster: %v", err)
+		}
+	}
+
+	// A loss is only reported once it outlasted the grace period.
+	if res := cc.ReconcileQuorum(); res.Completed() {
+		t.Fatalf("expected a new loss not to hold the reconcile")
+	}
+	if quorum := cr.Status.Quorum; quorum == nil || quorum.OnlineHosts != 1 || quorum.TotalHosts != 3 || quorum.LostSince == nil {
+		t.Fatalf("expected the loss to be tracked, got %+v", quorum)
+	}
+	checkAgain()
+	if res := cc.ReconcileQuorum(); !res.Completed() {
+		t.Fatalf("expected the lost quorum to hold the reconcile")
+	}
+	if !quorumLost(cr) || events(quorumReasonLost) != 1 {
+		t.Fatalf("expected the QuorumLost condition and event, got %+v", cr.Status.Conditions)
+	}
+	if health, _ := cc.assessClusterHealth(nil); health.reason != healthReasonQuorumLost || health.phase != marklogicv1.ClusterPhaseDegraded {
+		t.Fatalf("expected the cluster to be degraded, got %+v", health)
+	}
+
+	// An acknowledgment is ignored while the quorum is lost.
+	cr.Annotations = map[string]string{AcknowledgeQuorumLossAnnotation: "first"}
+	if res := cc.ReconcileQuorum(); !res.Completed() || !quorumLost(cr) || cr.Status.Quorum.AcknowledgedRequestID != "first" {
+		t.Fatalf("expected the acknowledgment to be ignored, got %+v", cr.Status.Quorum)
+	}
+
+	// A restored quorum stays held until the loss is acknowledged.
+	hosts[1].Online, hosts[2].Online = true, true
+	checkAgain()
+	cr.Annotations = nil
+	if res := cc.ReconcileQuorum(); !res.Completed() || !quorumLost(cr) || cr.Status.Quorum.LostSince != nil {
+		t.Fatalf("expected the restored quorum to stay held, got %+v", cr.Status.Quorum)
+	}
+	if events(quorumReasonLost) != 2 {
+		t.Fatalf("expected a %s event at every check", quorumReasonLost)
+	}
+	cr.Annotations = map[string]string{AcknowledgeQuorumLossAnnotation: "second"}
+	if res := cc.ReconcileQuorum(); res.Completed() || quorumLost(cr) {
+		t.Fatalf("expected the acknowledgment to resume the reconcile, got %+v", cr.Status.Conditions)
+	}
+	if events(quorumReasonAcknowledged) != 1 {
+		t.Fatalf("expected a %s event", quorumReasonAcknowledged)
+	}
+}
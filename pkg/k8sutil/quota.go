@@ -0,0 +1,409 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// quotaRequeueSeconds is how often a blocked scale-up is checked again,
+	// as changes to the quotas of the namespace are not watched.
+	quotaRequeueSeconds = 60
+
+	quotaReasonExceeded   = "QuotaExceeded"
+	quotaReasonLimitRange = "LimitRangeViolated"
+	quotaReasonHeadroom   = "ScaleUpAllowed"
+
+	storageClassQuotaSuffix = ".storageclass.storage.k8s.io/"
+)
+
+// scaleUpNeeds are the resources the pods and claims added by a scale-up
+// take from the quotas of the namespace.
+type scaleUpNeeds struct {
+	pods      int64
+	requests  corev1.ResourceList
+	limits    corev1.ResourceList
+	claims    int64
+	storage   resource.Quantity
+	byClass   map[string]int64
+	storageBy map[string]resource.Quantity
+}
+
+// checkScaleUpQuota holds the scale-up of the StatefulSet of the group while
+// the pods it adds would be rejected: they exceed the headroom left by a
+// ResourceQuota of the namespace, or their containers, pods or claims are out
+// of the bounds of a LimitRange. Otherwise the pods would never be created
+// and the StatefulSet would only report FailedCreate events. The replicas
+// of sts are reset to current and the ScaleUpBlocked condition of the group
+// explains why, until the quota allows the scale-up. It reports whether the
+// scale-up is held. Quotas with scopes are not checked.
+func (oc *OperatorContext) checkScaleUpQuota(sts *appsv1.StatefulSet, current int32) bool {
+	cr := oc.MarklogicGroup
+	desired := int32(1)
+	if sts.Spec.Replicas != nil {
+		desired = *sts.Spec.Replicas
+	}
+	reason, problems := "", []string{}
+	if desired > current {
+		var err error
+		reason, problems, err = oc.scaleUpQuotaProblems(sts, current, desired)
+		if err != nil {
+			oc.ReqLogger.Error(err, "Failed to check the quotas of the namespace, scaling up anyway")
+			return false
+		}
+	}
+	held := len(problems) > 0
+	condition := metav1.Condition{
+		Type:    string(marklogicv1.ScaleUpBlocked),
+		Status:  metav1.ConditionFalse,
+		Reason:  quotaReasonHeadroom,
+		Message: "the quotas of the namespace allow the pods of the group",
+	}
+	if held {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = reason
+		condition.Message = fmt.Sprintf("scaling up from %d to %d replicas is held: %s", current, desired, strings.Join(problems, "; "))
+		sts.Spec.Replicas = &current
+	}
+	if current := meta.FindStatusCondition(cr.Status.Conditions, condition.Type); current == nil || current.Status != condition.Status ||
+		current.Reason != condition.Reason || current.Message != condition.Message {
+		if held {
+			oc.ReqLogger.Info("Holding the scale-up of the group", "reason", reason, "problems", problems)
+			oc.recordGroupEvent(corev1.EventTypeWarning, reason, condition.Message)
+		}
+		// A group that was never held does not report the condition.
+		if current != nil || held {
+			patchBase := client.MergeFrom(cr.DeepCopy())
+			condition.LastTransitionTime = metav1.Now()
+			if current != nil && current.Status == condition.Status {
+				condition.LastTransitionTime = current.LastTransitionTime
+			}
+			cr.SetCondition(condition)
+			if err := patchStatus(oc.Ctx, oc.Client, cr, patchBase); err != nil {
+				oc.ReqLogger.Error(err, "Failed to update the ScaleUpBlocked condition")
+			}
+		}
+	}
+	return held
+}
+
+// scaleUpBlockedGroups describes the groups of the cluster whose scale-up the
+// quotas of the namespace hold, or returns an empty string when none is held.
+func (cc *ClusterContext) scaleUpBlockedGroups() (string, error) {
+	cr := cc.MarklogicCluster
+	blocked := []string{}
+	for _, group := range cr.Spec.MarkLogicGroups {
+		if group == nil {
+			continue
+		}
+		mlGroup := &marklogicv1.MarklogicGroup{}
+		err := cc.Client.Get(cc.Ctx, types.NamespacedName{Namespace: cr.Namespace, Name: group.Name}, mlGroup)
+		if apierrors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return "", err
+		}
+		if condition := meta.FindStatusCondition(mlGroup.Status.Conditions, string(marklogicv1.ScaleUpBlocked)); condition != nil && condition.Status == metav1.ConditionTrue {
+			blocked = append(blocked, fmt.Sprintf("group %s: %s", group.Name, condition.Message))
+		}
+	}
+	return strings.Join(blocked, "; "), nil
+}
+
+// scaleUpQuotaProblems returns why the pods from current to desired of the
+// StatefulSet would be rejected, with the reason of the first problem.
+func (oc *OperatorContext) scaleUpQuotaProblems(sts *appsv1.StatefulSet, current, desired int32) (string, []string, error) {
+	namespace := oc.MarklogicGroup.Namespace
+	limitRanges := &corev1.LimitRangeList{}
+	if err := oc.Client.List(oc.Ctx, limitRanges, client.InNamespace(namespace)); err != nil {
+		return "", nil, err
+	}
+	quotas := &corev1.ResourceQuotaList{}
+	if err := oc.Client.List(oc.Ctx, quotas, client.InNamespace(namespace)); err != nil {
+		return "", nil, err
+	}
+	if len(limitRanges.Items) == 0 && len(quotas.Items) == 0 {
+		return "", nil, nil
+	}
+
+	reason, problems := "", []string{}
+	add := func(r string, problem string) {
+		if reason == "" {
+			reason = r
+		}
+		problems = append(problems, problem)
+	}
+	requests, limits, violations := podResources(&sts.Spec.Template.Spec, limitRanges.Items)
+	for _, violation := range violations {
+		add(quotaReasonLimitRange, violation)
+	}
+	needs, err := oc.scaleUpNeeds(sts, current, desired, requests, limits)
+	if err != nil {
+		return "", nil, err
+	}
+	for _, limitRange := range limitRanges.Items {
+		for _, item := range limitRange.Spec.Limits {
+			if item.Type != corev1.LimitTypePersistentVolumeClaim {
+				continue
+			}
+			for _, template := range sts.Spec.VolumeClaimTemplates {
+				size := template.Spec.Resources.Requests[corev1.ResourceStorage]
+				if max, ok := item.Max[corev1.ResourceStorage]; ok && size.Cmp(max) > 0 {
+					add(quotaReasonLimitRange, fmt.Sprintf("LimitRange %s allows claims of at most %s, %s requests %s", limitRange.Name, max.String(), template.Name, size.String()))
+				}
+				if min, ok := item.Min[corev1.ResourceStorage]; ok && size.Cmp(min) < 0 {
+					add(quotaReasonLimitRange, fmt.Sprintf("LimitRange %s requires claims of at least %s, %s requests %s", limitRange.Name, min.String(), template.Name, size.String()))
+				}
+			}
+		}
+	}
+	sort.Slice(quotas.Items, func(i, j int) bool { return quotas.Items[i].Name < quotas.Items[j].Name })
+	for _, quota := range quotas.Items {
+		if len(quota.Spec.Scopes) > 0 || quota.Spec.ScopeSelector != nil {
+			continue
+		}
+		names := make([]string, 0, len(quota.Status.Hard))
+		for name := range quota.Status.Hard {
+			names = append(names, string(name))
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			need, ok := needs.quantity(corev1.ResourceName(name))
+			if !ok || need.IsZero() {
+				continue
+			}
+			left := quota.Status.Hard[corev1.ResourceName(name)].DeepCopy()
+			left.Sub(quota.Status.Used[corev1.ResourceName(name)])
+			if need.Cmp(left) > 0 {
+				add(quotaReasonExceeded, fmt.Sprintf("ResourceQuota %s has %s of %s left, %d more pod(s) need %s", quota.Name, left.String(), name, needs.pods, need.String()))
+			}
+		}
+	}
+	return reason, problems, nil
+}
+
+// scaleUpNeeds sums what the pods from current to desired and their new
+// claims need. Claims that exist from an earlier scale-down are reused and
+// need nothing.
+func (oc *OperatorContext) scaleUpNeeds(sts *appsv1.StatefulSet, current, desired int32, requests, limits corev1.ResourceList) (scaleUpNeeds, error) {
+	added := int64(desired - current)
+	needs := scaleUpNeeds{
+		pods: added, requests: corev1.ResourceList{}, limits: corev1.ResourceList{},
+		byClass: map[string]int64{}, storageBy: map[string]resource.Quantity{},
+	}
+	for name, quantity := range requests {
+		quantity.Mul(added)
+		needs.requests[name] = quantity
+	}
+	for name, quantity := range limits {
+		quantity.Mul(added)
+		needs.limits[name] = quantity
+	}
+	defaultClass, defaultResolved := "", false
+	for _, template := range sts.Spec.VolumeClaimTemplates {
+		class := ""
+		if template.Spec.StorageClassName != nil {
+			class = *template.Spec.StorageClassName
+		} else {
+			if !defaultResolved {
+				defaultClass, defaultResolved = oc.defaultStorageClassName(), true
+			}
+			class = defaultClass
+		}
+		size := template.Spec.Resources.Requests[corev1.ResourceStorage]
+		for ordinal := current; ordinal < desired; ordinal++ {
+			pvc := &corev1.PersistentVolumeClaim{}
+			err := oc.Client.Get(oc.Ctx, types.NamespacedName{Namespace: sts.Namespace, Name: fmt.Sprintf("%s-%s-%d", template.Name, sts.Name, ordinal)}, pvc)
+			if err == nil {
+				continue
+			}
+			if !apierrors.IsNotFound(err) {
+				return needs, err
+			}
+			needs.claims++
+			needs.storage.Add(size)
+			if class != "" {
+				needs.byClass[class]++
+				classStorage := needs.storageBy[class]
+				classStorage.Add(size)
+				needs.storageBy[class] = classStorage
+			}
+		}
+	}
+	return needs, nil
+}
+
+// defaultStorageClassName returns the default StorageClass of the cluster,
+// or "" if it has none or the StorageClasses cannot be listed.
+func (oc *OperatorContext) defaultStorageClassName() string {
+	classes := &storagev1.StorageClassList{}
+	if err := oc.Client.List(oc.Ctx, classes); err != nil {
+		return ""
+	}
+	for _, class := range classes.Items {
+		if hasDefaultStorageClass([]storagev1.StorageClass{class}) {
+			return class.Name
+		}
+	}
+	return ""
+}
+
+// quantity returns what the scale-up needs of a resource of a quota, and
+// whether the resource is one it takes from.
+func (n scaleUpNeeds) quantity(name corev1.ResourceName) (resource.Quantity, bool) {
+	switch {
+	case name == corev1.ResourcePods || name == "count/pods":
+		return *resource.NewQuantity(n.pods, resource.DecimalSI), true
+	case name == corev1.ResourcePersistentVolumeClaims || name == "count/persistentvolumeclaims":
+		return *resource.NewQuantity(n.claims, resource.DecimalSI), true
+	case name == corev1.ResourceRequestsStorage:
+		return n.storage, true
+	case strings.Contains(string(name), storageClassQuotaSuffix):
+		class, resourceName, _ := strings.Cut(string(name), storageClassQuotaSuffix)
+		switch corev1.ResourceName(resourceName) {
+		case corev1.ResourcePersistentVolumeClaims:
+			return *resource.NewQuantity(n.byClass[class], resource.DecimalSI), true
+		case corev1.ResourceRequestsStorage:
+			return n.storageBy[class], true
+		}
+	case strings.HasPrefix(string(name), "requests."):
+		quantity, ok := n.requests[corev1.ResourceName(strings.TrimPrefix(string(name), "requests."))]
+		return quantity, ok
+	case strings.HasPrefix(string(name), "limits."):
+		quantity, ok := n.limits[corev1.ResourceName(strings.TrimPrefix(string(name), "limits."))]
+		return quantity, ok
+	case name == corev1.ResourceCPU || name == corev1.ResourceMemory || name == corev1.ResourceEphemeralStorage:
+		quantity, ok := n.requests[name]
+		return quantity, ok
+	}
+	return resource.Quantity{}, false
+}
+
+// podResources returns the requests and limits of a pod once the defaults of
+// the LimitRanges are applied to its containers, as admission does, and the
+// bounds of the LimitRanges the pod is out of. Like the scheduler, the pod
+// needs the sum of its containers or the most any of its init containers
+// needs, whichever is higher.
+func podResources(spec *corev1.PodSpec, limitRanges []corev1.LimitRange) (corev1.ResourceList, corev1.ResourceList, []string) {
+	violations := []string{}
+	requests, limits := corev1.ResourceList{}, corev1.ResourceList{}
+	initRequests, initLimits := corev1.ResourceList{}, corev1.ResourceList{}
+	containers := append([]corev1.Container{}, spec.InitContainers...)
+	containers = append(containers, spec.Containers...)
+	for i, container := range containers {
+		containerRequests, containerLimits := container.Resources.Requests.DeepCopy(), container.Resources.Limits.DeepCopy()
+		if containerRequests == nil {
+			containerRequests = corev1.ResourceList{}
+		}
+		if containerLimits == nil {
+			containerLimits = corev1.ResourceList{}
+		}
+		for _, limitRange := range limitRanges {
+			for _, item := range limitRange.Spec.Limits {
+				if item.Type != corev1.LimitTypeContainer {
+					continue
+				}
+				for name, quantity := range item.Default {
+					if _, ok := containerLimits[name]; !ok {
+						containerLimits[name] = quantity.DeepCopy()
+					}
+				}
+				for name, quantity := range item.DefaultRequest {
+					if _, ok := containerRequests[name]; !ok {
+						containerRequests[name] = quantity.DeepCopy()
+					}
+				}
+			}
+		}
+		// A container with a limit and no request requests its limit.
+		for name, quantity := range containerLimits {
+			if _, ok := containerRequests[name]; !ok {
+				containerRequests[name] = quantity.DeepCopy()
+			}
+		}
+		for _, limitRange := range limitRanges {
+			for _, item := range limitRange.Spec.Limits {
+				if item.Type == corev1.LimitTypeContainer {
+					violations = append(violations, limitRangeViolations(limitRange.Name, "container "+container.Name, item, containerRequests, containerLimits)...)
+				}
+			}
+		}
+		if i < len(spec.InitContainers) {
+			maxResources(initRequests, containerRequests)
+			maxResources(initLimits, containerLimits)
+			continue
+		}
+		addResources(requests, containerRequests)
+		addResources(limits, containerLimits)
+	}
+	maxResources(requests, initRequests)
+	maxResources(limits, initLimits)
+	for _, limitRange := range limitRanges {
+		for _, item := range limitRange.Spec.Limits {
+			if item.Type == corev1.LimitTypePod {
+				violations = append(violations, limitRangeViolations(limitRange.Name, "the pod", item, requests, limits)...)
+			}
+		}
+	}
+	return requests, limits, violations
+}
+
+// limitRangeViolations returns the bounds of a LimitRange item the requests
+// and limits of a container or pod are out of.
+func limitRangeViolations(limitRange, subject string, item corev1.LimitRangeItem, requests, limits corev1.ResourceList) []string {
+	violations := []string{}
+	for _, name := range sortedResourceNames(item.Max) {
+		max := item.Max[name]
+		if limit, ok := limits[name]; !ok {
+			violations = append(violations, fmt.Sprintf("LimitRange %s requires a %s limit for %s", limitRange, name, subject))
+		} else if limit.Cmp(max) > 0 {
+			violations = append(violations, fmt.Sprintf("LimitRange %s allows a %s limit of at most %s, %s has %s", limitRange, name, max.String(), subject, limit.String()))
+		}
+	}
+	for _, name := range sortedResourceNames(item.Min) {
+		min := item.Min[name]
+		if request, ok := requests[name]; !ok || request.Cmp(min) < 0 {
+			violations = append(violations, fmt.Sprintf("LimitRange %s requires a %s request of at least %s for %s", limitRange, name, min.String(), subject))
+		}
+	}
+	return violations
+}
+
+func addResources(total, add corev1.ResourceList) {
+	for name, quantity := range add {
+		sum := total[name]
+		sum.Add(quantity)
+		total[name] = sum
+	}
+}
+
+func maxResources(total, other corev1.ResourceList) {
+	for name, quantity := range other {
+		if current, ok := total[name]; !ok || quantity.Cmp(current) > 0 {
+			total[name] = quantity.DeepCopy()
+		}
+	}
+}
+
+func sortedResourceNames(list corev1.ResourceList) []corev1.ResourceName {
+	names := make([]corev1.ResourceName, 0, len(list))
+	for name := range list {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
+}
@@ -0,0 +1,136 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"strings"
+	"testing"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func newQuotaTestStatefulSet(replicas int32) *appsv1.StatefulSet {
+	class := "fast"
+	return &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "dnode", Namespace: "default"},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas: &replicas,
+			Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+				InitContainers: []corev1.Container{{Name: "init", Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("3Gi")},
+				}}},
+				Containers: []corev1.Container{
+					{Name: "marklogic-server", Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1"), corev1.ResourceMemory: resource.MustParse("2Gi")},
+					}},
+					{Name: "fluent-bit"},
+				},
+			}},
+			VolumeClaimTemplates: []corev1.PersistentVolumeClaim{{
+				ObjectMeta: metav1.ObjectMeta{Name: "datadir"},
+				Spec: corev1.PersistentVolumeClaimSpec{
+					StorageClassName: &class,
+					Resources: corev1.VolumeResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")},
+					},
+				},
+			}},
+		},
+	}
+}
+
+func TestCheckScaleUpQuotaHoldsScaleUpBeyondHeadroom(t *testing.T) {
+	quota := &corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: "compute", Namespace: "default"},
+		Status: corev1.ResourceQuotaStatus{
+			Hard: corev1.ResourceList{
+				corev1.ResourceRequestsMemory:                       resource.MustParse("12Gi"),
+				corev1.ResourcePersistentVolumeClaims:               resource.MustParse("3"),
+				"fast.storageclass.storage.k8s.io/requests.storage": resource.MustParse("30Gi"),
+			},
+			Used: corev1.ResourceList{
+				corev1.ResourceRequestsMemory:                       resource.MustParse("3Gi"),
+				corev1.ResourcePersistentVolumeClaims:               resource.MustParse("2"),
+				"fast.storageclass.storage.k8s.io/requests.storage": resource.MustParse("20Gi"),
+			},
+		},
+	}
+	// The claim of dnode-1 is left from an earlier scale-down.
+	claim := &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "datadir-dnode-1", Namespace: "default"}}
+	oc := newSecretRotationTestContext(t, newSecretRotationTestGroup(), quota, claim)
+	recorder := oc.Recorder.(*record.FakeRecorder)
+
+	// Two more pods need 6Gi of memory, as the init container needs more
+	// than the containers, and a single new claim.
+	sts := newQuotaTestStatefulSet(3)
+	if oc.checkScaleUpQuota(sts, 1) {
+		t.Fatalf("expected the scale-up to fit the quota")
+	}
+	if condition := meta.FindStatusCondition(oc.MarklogicGroup.Status.Conditions, string(marklogicv1.ScaleUpBlocked)); condition != nil {
+		t.Fatalf("expected no condition for a group that was never held, got %+v", condition)
+	}
+
+	sts = newQuotaTestStatefulSet(5)
+	if !oc.checkScaleUpQuota(sts, 1) {
+		t.Fatalf("expected the scale-up to be held")
+	}
+	if *sts.Spec.Replicas != 1 {
+		t.Fatalf("expected the replicas to be held at 1, got %d", *sts.Spec.Replicas)
+	}
+	condition := meta.FindStatusCondition(oc.MarklogicGroup.Status.Conditions, string(marklogicv1.ScaleUpBlocked))
+	if condition == nil || condition.Status != metav1.ConditionTrue || condition.Reason != quotaReasonExceeded ||
+		!strings.Contains(condition.Message, "9Gi of requests.memory left, 4 more pod(s) need 12Gi") ||
+		!strings.Contains(condition.Message, "1 of persistentvolumeclaims left") ||
+		!strings.Contains(condition.Message, "10Gi of fast.storageclass.storage.k8s.io/requests.storage left") {
+		t.Fatalf("unexpected condition %+v", condition)
+	}
+	if len(recorder.Events) != 1 || !strings.Contains(<-recorder.Events, quotaReasonExceeded) {
+		t.Fatalf("expected a %s event", quotaReasonExceeded)
+	}
+
+	// Once the quota is raised, the scale-up goes ahead.
+	quota.Status.Hard = nil
+	if err := oc.Client.Update(oc.Ctx, quota); err != nil {
+		t.Fatalf("failed to update quota: %v", err)
+	}
+	sts = newQuotaTestStatefulSet(5)
+	if oc.checkScaleUpQuota(sts, 1) || *sts.Spec.Replicas != 5 {
+		t.Fatalf("expected the scale-up to go ahead")
+	}
+	if condition := meta.FindStatusCondition(oc.MarklogicGroup.Status.Conditions, string(marklogicv1.ScaleUpBlocked)); condition == nil || condition.Status != metav1.ConditionFalse {
+		t.Fatalf("expected the condition to be cleared, got %+v", condition)
+	}
+}
+
+func TestCheckScaleUpQuotaAppliesLimitRanges(t *testing.T) {
+	limitRange := &corev1.LimitRange{
+		ObjectMeta: metav1.ObjectMeta{Name: "limits", Namespace: "default"},
+		Spec: corev1.LimitRangeSpec{Limits: []corev1.LimitRangeItem{
+			{
+				Type:    corev1.LimitTypeContainer,
+				Default: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("4Gi")},
+				Max:     corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("3Gi")},
+			},
+			{Type: corev1.LimitTypePersistentVolumeClaim, Max: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("5Gi")}},
+		}},
+	}
+	oc := newSecretRotationTestContext(t, newSecretRotationTestGroup(), limitRange)
+
+	// The containers without a memory limit get the default, above the max.
+	sts := newQuotaTestStatefulSet(1)
+	if !oc.checkScaleUpQuota(sts, 0) || *sts.Spec.Replicas != 0 {
+		t.Fatalf("expected the new group to be held")
+	}
+	condition := meta.FindStatusCondition(oc.MarklogicGroup.Status.Conditions, string(marklogicv1.ScaleUpBlocked))
+	if condition == nil || condition.Reason != quotaReasonLimitRange ||
+		!strings.Contains(condition.Message, "memory limit of at most 3Gi, container fluent-bit has 4Gi") ||
+		!strings.Contains(condition.Message, "claims of at most 5Gi, datadir requests 10Gi") {
+		t.Fatalf("unexpected condition %+v", condition)
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"fmt"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// readOnlyPIDFile is where MarkLogic writes its PID file when the root
+	// filesystem is read-only, /tmp must be one of the writable paths.
+	readOnlyPIDFile = "/tmp/MarkLogic.pid"
+
+	fluentBitTmpVolumeName = "fluent-bit-tmp"
+)
+
+func readOnlyRootFilesystemEnabled(cfg *marklogicv1.ReadOnlyRootFilesystem) bool {
+	return cfg != nil && cfg.Enabled
+}
+
+// writablePathVolumes returns an emptyDir volume and its mount for each
+// writable path of the MarkLogic container. Paths already mounted through
+// additionalVolumeMounts are left to those volumes.
+func writablePathVolumes(containerParams containerParameters) ([]corev1.Volume, []corev1.VolumeMount) {
+	cfg := containerParams.ReadOnlyRootFilesystem
+	if !readOnlyRootFilesystemEnabled(cfg) {
+		return nil, nil
+	}
+	mounted := map[string]bool{}
+	if containerParams.AdditionalVolumeMounts != nil {
+		for _, mount := range *containerParams.AdditionalVolumeMounts {
+			mounted[mount.MountPath] = true
+		}
+	}
+	volumes := []corev1.Volume{}
+	mounts := []corev1.VolumeMount{}
+	for i, path := range cfg.WritablePaths {
+		if mounted[path] {
+			continue
+		}
+		mounted[path] = true
+		name := fmt.Sprintf("writable-%d", i)
+		volumes = append(volumes, corev1.Volume{
+			Name:         name,
+			VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{SizeLimit: cfg.SizeLimit}},
+		})
+		mounts = append(mounts, corev1.VolumeMount{Name: name, MountPath: path})
+	}
+	return volumes, mounts
+}
+
+// readOnlySecurityContext returns a copy of the security context of a
+// container with a read-only root filesystem.
+func readOnlySecurityContext(base *corev1.SecurityContext) *corev1.SecurityContext {
+	sc := &corev1.SecurityContext{}
+	if base != nil {
+		sc = base.DeepCopy()
+	}
+	sc.ReadOnlyRootFilesystem = boolPtr(true)
+	return sc
+}
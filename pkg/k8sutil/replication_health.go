@@ -0,0 +1,199 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	defaultReplicationCheckInterval = time.Minute
+	defaultReplicationMaxLag        = 60 * time.Second
+
+	replicationReasonHealthy      = "ReplicationHealthy"
+	replicationReasonNotChecked   = "ReplicationNotChecked"
+	replicationReasonCheckFailed  = "ReplicationCheckFailed"
+	replicationReasonNotConnected = "ForeignClusterNotConnected"
+	replicationReasonLagging      = "ReplicationLagging"
+	replicationReasonLagUnknown   = "ReplicationLagUnknown"
+	replicationReasonRecovered    = "ReplicationRecovered"
+)
+
+var (
+	replicationLag = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "marklogic_cluster_replication_lag_seconds",
+		Help: "How far the foreign replicas of a database of spec.replication are behind it.",
+	}, []string{"namespace", "cluster", "database"})
+	replicationConnectedHosts = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "marklogic_cluster_foreign_cluster_connected_hosts",
+		Help: "Hosts of a foreign cluster the cluster is connected to.",
+	}, []string{"namespace", "cluster", "foreign_cluster"})
+	replicationHosts = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "marklogic_cluster_foreign_cluster_hosts",
+		Help: "Hosts of a foreign cluster the cluster knows.",
+	}, []string{"namespace", "cluster", "foreign_cluster"})
+
+	replicationGauges = []*prometheus.GaugeVec{replicationLag, replicationConnectedHosts, replicationHosts}
+)
+
+func init() {
+	for _, gauge := range replicationGauges {
+		metrics.Registry.MustRegister(gauge)
+	}
+}
+
+// ReconcileReplicationHealth checks the replication of spec.replication
+// every interval: the foreign clusters the cluster is coupled with must be
+// connected on all their hosts, and the databases must not lag behind their
+// foreign replicas by more than maxLag. The results are reported in
+// status.replication, as metrics and in the ReplicationDegraded condition,
+// with a Warning event when the replication degrades. Failures never hold up
+// the rest of the reconcile.
+func (cc *ClusterContext) ReconcileReplicationHealth() result.ReconcileResult {
+	cr := cc.MarklogicCluster
+	spec := cr.Spec.Replication
+	if spec == nil || len(spec.Databases) == 0 {
+		if cr.Status.Replication != nil {
+			deleteClusterSeries(replicationGauges, cr.Namespace, cr.Name)
+			patchBase := client.MergeFrom(cr.DeepCopy())
+			cr.Status.Replication = nil
+			if cr.Status.GetConditionStatus(string(marklogicv1.ReplicationDegraded)) != metav1.ConditionUnknown {
+				cc.setClusterCondition(marklogicv1.ReplicationDegraded, metav1.ConditionFalse, replicationReasonNotChecked, "spec.replication is not set")
+			}
+			if err := patchStatus(cc.Ctx, cc.Client, cr, patchBase); err != nil {
+				cc.ReqLogger.Error(err, "Failed to clear the replication in the cluster status")
+			}
+		}
+		return result.Continue()
+	}
+	if clusterStopped(cr) {
+		return result.Continue()
+	}
+	now := metav1.Now()
+	if status := cr.Status.Replication; status != nil && status.LastCheckTime != nil && now.Sub(status.LastCheckTime.Time) < replicationCheckInterval(spec) {
+		return result.Continue()
+	}
+	maxLag := defaultReplicationMaxLag
+	if spec.MaxLag != nil && spec.MaxLag.Duration > 0 {
+		maxLag = spec.MaxLag.Duration
+	}
+
+	status := &marklogicv1.ReplicationStatus{LastCheckTime: &now}
+	reason, problems := "", []string{}
+	degrade := func(r, problem string) {
+		if reason == "" {
+			reason = r
+		}
+		problems = append(problems, problem)
+	}
+	mgmt, err := cc.newBootstrapManagementClient()
+	clusterName := ""
+	if err == nil {
+		clusterName, err = mgmt.ResolveClusterName(cc.Ctx)
+	}
+	if err != nil {
+		degrade(replicationReasonCheckFailed, fmt.Sprintf("failed to reach the Manage API: %v", err))
+	} else {
+		foreign, err := mgmt.ListForeignClustersStatus(cc.Ctx, clusterName)
+		switch {
+		case err != nil:
+			degrade(replicationReasonCheckFailed, fmt.Sprintf("failed to read the foreign clusters: %v", err))
+		case len(foreign) == 0:
+			degrade(replicationReasonNotConnected, fmt.Sprintf("cluster %s is not coupled with a foreign cluster", clusterName))
+		}
+		sort.Slice(foreign, func(i, j int) bool { return foreign[i].Name < foreign[j].Name })
+		for _, cluster := range foreign {
+			status.ForeignClusters = append(status.ForeignClusters, marklogicv1.ForeignClusterReplicationStatus{
+				Name: cluster.Name, ConnectedHosts: int32(cluster.ConnectedHosts), Hosts: int32(cluster.Hosts),
+			})
+			if cluster.Hosts == 0 || cluster.ConnectedHosts < cluster.Hosts {
+				degrade(replicationReasonNotConnected, fmt.Sprintf("foreign cluster %s is connected on %d of %d hosts", cluster.Name, cluster.ConnectedHosts, cluster.Hosts))
+			}
+		}
+		lagging, unknown := []string{}, []string{}
+		for _, database := range spec.Databases {
+			entry := marklogicv1.DatabaseReplicationStatus{Database: database}
+			lag, err := mgmt.GetDatabaseReplicationLag(cc.Ctx, database)
+			if err != nil {
+				entry.Message = err.Error()
+				unknown = append(unknown, fmt.Sprintf("the lag of database %s is unknown: %v", database, err))
+				replicationLag.DeletePartialMatch(prometheus.Labels{"namespace": cr.Namespace, "cluster": cr.Name, "database": database})
+			} else {
+				entry.Lag = &metav1.Duration{Duration: lag}
+				replicationLag.WithLabelValues(cr.Namespace, cr.Name, database).Set(lag.Seconds())
+				if lag > maxLag {
+					lagging = append(lagging, fmt.Sprintf("database %s lags %s behind, more than %s", database, lag, maxLag))
+				}
+			}
+			status.Databases = append(status.Databases, entry)
+		}
+		for _, problem := range lagging {
+			degrade(replicationReasonLagging, problem)
+		}
+		for _, problem := range unknown {
+			degrade(replicationReasonLagUnknown, problem)
+		}
+	}
+	cc.setReplicationMetrics(status)
+
+	degraded := reason != ""
+	previous := cr.Status.GetConditionStatus(string(marklogicv1.ReplicationDegraded))
+	patchBase := client.MergeFrom(cr.DeepCopy())
+	cr.Status.Replication = status
+	if degraded {
+		message := strings.Join(problems, "; ")
+		if previous != metav1.ConditionTrue {
+			cc.recordClusterEvent(corev1.EventTypeWarning, string(marklogicv1.ReplicationDegraded), message)
+		}
+		cc.setClusterCondition(marklogicv1.ReplicationDegraded, metav1.ConditionTrue, reason, message)
+	} else {
+		if previous == metav1.ConditionTrue {
+			cc.recordClusterEvent(corev1.EventTypeNormal, replicationReasonRecovered, "the foreign clusters are connected and the databases are within their replication lag")
+		}
+		cc.setClusterCondition(marklogicv1.ReplicationDegraded, metav1.ConditionFalse, replicationReasonHealthy,
+			fmt.Sprintf("%d foreign cluster(s) connected, %d database(s) within %s", len(status.ForeignClusters), len(status.Databases), maxLag))
+	}
+	if err := patchStatus(cc.Ctx, cc.Client, cr, patchBase); err != nil {
+		cc.ReqLogger.Error(err, "Failed to update the replication in the cluster status")
+	}
+	return result.Continue()
+}
+
+// setReplicationMetrics exports the foreign clusters of a check and drops
+// the series of the foreign clusters the cluster is no longer coupled with.
+func (cc *ClusterContext) setReplicationMetrics(status *marklogicv1.ReplicationStatus) {
+	cr := cc.MarklogicCluster
+	for _, gauge := range []*prometheus.GaugeVec{replicationConnectedHosts, replicationHosts} {
+		gauge.DeletePartialMatch(prometheus.Labels{"namespace": cr.Namespace, "cluster": cr.Name})
+	}
+	for _, cluster := range status.ForeignClusters {
+		replicationConnectedHosts.WithLabelValues(cr.Namespace, cr.Name, cluster.Name).Set(float64(cluster.ConnectedHosts))
+		replicationHosts.WithLabelValues(cr.Namespace, cr.Name, cluster.Name).Set(float64(cluster.Hosts))
+	}
+}
+
+func replicationCheckInterval(spec *marklogicv1.ReplicationCheck) time.Duration {
+	if spec.Interval != nil && spec.Interval.Duration > 0 {
+		return spec.Interval.Duration
+	}
+	return defaultReplicationCheckInterval
+}
+
+// nextReplicationCheck is when the replication is checked next.
+func nextReplicationCheck(cr *marklogicv1.MarklogicCluster) time.Time {
+	if cr.Spec.Replication == nil || cr.Status.Replication == nil || cr.Status.Replication.LastCheckTime == nil {
+		return time.Time{}
+	}
+	return cr.Status.Replication.LastCheckTime.Add(replicationCheckInterval(cr.Spec.Replication))
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestReconcileReplicationHealthReportsDegradation(t *testing.T) {
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			ClusterDomain:   "cluster.local",
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{{Name: "dnode", IsBootstrap: true}},
+			Replication: &marklogicv1.ReplicationCheck{
+				Databases: []string{"Documents"},
+				MaxLag:    &metav1.Duration{Duration: 30 * time.Second},
+			},
+		},
+	}
+	cc := newUpgradeTestContext(t, cr)
+	recorder := cc.Recorder.(*record.FakeRecorder)
+	foreign := []mlmanage.ForeignClusterStatus{{Name: "dr", Hosts: 3, ConnectedHosts: 3}}
+	lag := 5 * time.Second
+	original := NewDynamicManagementClient
+	NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+		return &stubDynamicManagementClient{
+			resolveNameFn: func() (string, error) { return "primary", nil },
+			foreignClustersFn: func(clusterName string) ([]mlmanage.ForeignClusterStatus, error) {
+				return append([]mlmanage.ForeignClusterStatus(nil), foreign...), nil
+			},
+			replicationLagFn: func(database string) (time.Duration, error) { return lag, nil },
+		}
+	}
+	t.Cleanup(func() {
+		NewDynamicManagementClient = original
+		deleteClusterSeries(replicationGauges, "default", "ml")
+	})
+	events := func(reason string) int {
+		count := 0
+		for len(recorder.Events) > 0 {
+			if strings.Contains(<-recorder.Events, reason) {
+				count++
+			}
+		}
+		return count
+	}
+	// checkAgain makes the next check due.
+	checkAgain := func() {
+		t.Helper()
+		past := metav1.NewTime(time.Now().Add(-time.Hour))
+		cr.Status.Replication.LastCheckTime = &past
+		if err := cc.Client.Status().Update(cc.Ctx, cr); err != nil {
+			t.Fatalf("failed to update cluster: %v", err)
+		}
+	}
+	degraded := func() *metav1.Condition {
+		return meta.FindStatusCondition(cr.Status.Conditions, string(marklogicv1.ReplicationDegraded))
+	}
+
+	if res := cc.ReconcileReplicationHealth(); res.Completed() {
+		t.Fatalf("expected the replication check never to hold up the reconcile")
+	}
+	if condition := degraded(); condition == nil || condition.Status != metav1.ConditionFalse || condition.Reason != replicationReasonHealthy {
+		t.Fatalf("expected a healthy replication, got %+v", condition)
+	}
+	status := cr.Status.Replication
+	if status == nil || len(status.ForeignClusters) != 1 || status.ForeignClusters[0].ConnectedHosts != 3 ||
+		len(status.Databases) != 1 || status.Databases[0].Lag == nil || status.Databases[0].Lag.Duration != lag {
+		t.Fatalf("unexpected replication status %+v", status)
+	}
+	if next := nextReplicationCheck(cr); time.Until(next) > defaultReplicationCheckInterval || time.Until(next) <= 0 {
+		t.Fatalf("expected the next check in an interval, got %v", next)
+	}
+
+	// A lag above maxLag degrades the replication.
+	lag = 2 * time.Minute
+	checkAgain()
+	cc.ReconcileReplicationHealth()
+	if condition := degraded(); condition == nil || condition.Status != metav1.ConditionTrue || condition.Reason != replicationReasonLagging ||
+		!strings.Contains(condition.Message, "Documents") {
+		t.Fatalf("expected the lagging database to degrade the replication, got %+v", condition)
+	}
+	if events(string(marklogicv1.ReplicationDegraded)) != 1 {
+		t.Fatalf("expected a %s event", marklogicv1.ReplicationDegraded)
+	}
+
+	// A foreign cluster that is not connected on all its hosts comes first.
+	foreign[0].ConnectedHosts = 1
+	checkAgain()
+	cc.ReconcileReplicationHealth()
+	if condition := degraded(); condition == nil || condition.Reason != replicationReasonNotConnected || !strings.Contains(condition.Message, "1 of 3 hosts") {
+		t.Fatalf("expected the disconnected foreign cluster to be reported, got %+v", condition)
+	}
+	if events(string(marklogicv1.ReplicationDegraded)) != 0 {
+		t.Fatalf("expected the degradation to be reported once")
+	}
+
+	// The recovery is reported as well.
+	foreign[0].ConnectedHosts, lag = 3, time.Second
+	checkAgain()
+	cc.ReconcileReplicationHealth()
+	if condition := degraded(); condition == nil || condition.Status != metav1.ConditionFalse {
+		t.Fatalf("expected the replication to recover, got %+v", condition)
+	}
+	if events(replicationReasonRecovered) != 1 {
+		t.Fatalf("expected a %s event", replicationReasonRecovered)
+	}
+}
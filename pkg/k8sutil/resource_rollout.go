@@ -0,0 +1,301 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	resourceRolloutReasonStarted          = "ResourceRolloutStarted"
+	resourceRolloutReasonPodRestarted     = "ResourceRolloutPodRestarted"
+	resourceRolloutReasonCompleted        = "ResourceRolloutCompleted"
+	resourceRolloutReasonHealthGateFailed = "ResourceRolloutHealthGateFailed"
+
+	defaultRestartSettleWindow = time.Minute
+)
+
+// ReconcileResourceRollout applies changed CPU and memory to the groups with
+// the OnDelete update strategy, whose StatefulSets do not restart pods on
+// their own. Like an upgrade, it restarts the pods with outdated resources one
+// at a time, bootstrap group and highest ordinal first, and waits for the
+// health gates of spec.upgrade before the next restart. The rollout holds the
+// rollout lock while it restarts pods and waits while an upgrade holds it, as
+// the upgrade restarts every outdated pod itself. With
+// spec.upgrade.restartBatching it applies the other changes of the pod
+// templates too, once they settled, so a batch of changes restarts each pod
+// once.
+func (cc *ClusterContext) ReconcileResourceRollout() result.ReconcileResult {
+	cr := cc.MarklogicCluster
+	if holder := rolloutLockHolder(cr); holder != "" && holder != rolloutLockResourceRollout {
+		return result.Continue()
+	}
+	rollout := &marklogicv1.ResourceRolloutStatus{}
+	if cr.Status.ResourceRollout != nil {
+		rollout = cr.Status.ResourceRollout.DeepCopy()
+	}
+	if rollout.State == marklogicv1.ResourceRolloutFailed && rollout.ObservedGeneration == cr.Generation {
+		return result.Continue()
+	}
+	now := metav1.Now()
+
+	if rollout.PodRestart != nil {
+		check, err := cc.checkRestartedPod(rollout.PodRestart, resourceRolloutReasonHealthGateFailed, now)
+		if err != nil {
+			return result.Error(err)
+		}
+		if check.failed {
+			rollout.PodRestart = nil
+			rollout.State = marklogicv1.ResourceRolloutFailed
+			rollout.ObservedGeneration = cr.Generation
+			rollout.Message = check.message
+			cc.recordClusterEvent(corev1.EventTypeWarning, resourceRolloutReasonHealthGateFailed, check.message)
+			return cc.setResourceRolloutStatus(rollout, result.Continue())
+		}
+		if !check.passed {
+			rollout.Message = check.message
+			return cc.setResourceRolloutStatus(rollout, result.RequeueSoon(healthGateRequeueSeconds))
+		}
+		rollout.PodRestart = nil
+	}
+
+	batching := restartBatching(cr)
+	pod, outdated, revisions, err := cc.nextResourceOutdatedPod(batching != nil)
+	if err != nil {
+		return result.Error(err)
+	}
+	rollout.OutdatedPods = outdated
+	if batching == nil {
+		rollout.Revisions = nil
+		rollout.SettlingUntil = nil
+	} else if !slices.Equal(rollout.Revisions, revisions) {
+		// Another change arrived, wait for the next one before restarting.
+		settlingUntil := metav1.NewTime(now.Add(restartSettleWindow(batching)))
+		rollout.Revisions = revisions
+		rollout.SettlingUntil = &settlingUntil
+	}
+	if outdated == 0 {
+		rollout.PodHold = nil
+		if rollout.State != marklogicv1.ResourceRolloutInProgress {
+			return result.Continue()
+		}
+		rollout.State = marklogicv1.ResourceRolloutCompleted
+		rollout.CompletionTime = &now
+		rollout.Message = fmt.Sprintf("all pods run with the %s of their group", rolloutChanges(batching))
+		cc.recordClusterEvent(corev1.EventTypeNormal, resourceRolloutReasonCompleted, rollout.Message)
+		return cc.setResourceRolloutStatus(rollout, result.Continue())
+	}
+	if settlingUntil := rollout.SettlingUntil; settlingUntil != nil && now.Before(settlingUntil) {
+		rollout.Message = fmt.Sprintf("%d pod(s) to restart, waiting until %s for further changes", outdated, settlingUntil.UTC().Format(time.RFC3339))
+		return cc.setResourceRolloutStatus(rollout, result.RequeueSoon(int(math.Ceil(settlingUntil.Sub(now.Time).Seconds()))))
+	}
+	if rollout.State != marklogicv1.ResourceRolloutInProgress {
+		rollout.State = marklogicv1.ResourceRolloutInProgress
+		rollout.StartTime = &now
+		rollout.CompletionTime = nil
+		rollout.ObservedGeneration = 0
+		cc.recordClusterEvent(corev1.EventTypeNormal, resourceRolloutReasonStarted,
+			fmt.Sprintf("%d pod(s) run with outdated %s, restarting them one at a time", outdated, rolloutChanges(batching)))
+	}
+	if pod == nil {
+		rollout.Message = fmt.Sprintf("%d pod(s) with outdated %s, waiting for all pods to be ready", outdated, rolloutChanges(batching))
+		return cc.setResourceRolloutStatus(rollout, result.RequeueSoon(upgradePollIntervalSeconds))
+	}
+	if held, message := cc.holdPodRestart(pod, &rollout.PodHold, now); held {
+		rollout.Message = message
+		return cc.setResourceRolloutStatus(rollout, result.RequeueSoon(backgroundJobRequeueSeconds))
+	}
+	// A running upgrade holds the rollout back, so only this pod stays out of HAProxy.
+	if err := cc.syncHAProxyServers(pod.Name); err != nil {
+		return result.Error(err)
+	}
+	if err := cc.Client.Delete(cc.Ctx, pod); err != nil && !apierrors.IsNotFound(err) {
+		return result.Error(err)
+	}
+	rollout.PodRestart = &marklogicv1.UpgradePodRestart{Pod: pod.Name, PodUID: pod.UID, StartTime: now}
+	rollout.Message = fmt.Sprintf("restarted pod %s to apply the %s of its group", pod.Name, rolloutChanges(batching))
+	cc.recordClusterEvent(corev1.EventTypeNormal, resourceRolloutReasonPodRestarted, rollout.Message)
+	return cc.setResourceRolloutStatus(rollout, result.RequeueSoon(healthGateRequeueSeconds))
+}
+
+// nextResourceOutdatedPod returns the pod to restart next and the number of
+// pods of OnDelete groups whose resources differ from their StatefulSet, or
+// from the recommendation of their VPA, see podResourcesMatcher. With
+// templates, pods not on the update revision of their StatefulSet are
+// outdated too, unless the revision changes their image, which is rolled out
+// by the upgrade; the update revisions are returned as group=revision. No
+// pod is returned while any pod is missing or not ready, or a StatefulSet
+// has not observed its template, so only one pod is down at a time.
+func (cc *ClusterContext) nextResourceOutdatedPod(templates bool) (*corev1.Pod, int32, []string, error) {
+	cr := cc.MarklogicCluster
+	groups := make([]*marklogicv1.MarklogicGroups, 0, len(cr.Spec.MarkLogicGroups))
+	for _, group := range cr.Spec.MarkLogicGroups {
+		if group != nil {
+			groups = append(groups, group)
+		}
+	}
+	sort.SliceStable(groups, func(i, j int) bool { return groups[i].IsBootstrap && !groups[j].IsBootstrap })
+
+	var next *corev1.Pod
+	var outdated int32
+	var revisions []string
+	settled := true
+	for _, group := range groups {
+		sts := &appsv1.StatefulSet{}
+		err := cc.Client.Get(cc.Ctx, types.NamespacedName{Namespace: cr.Namespace, Name: group.Name}, sts)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, 0, nil, err
+		}
+		template := marklogicServerContainer(sts.Spec.Template.Spec.Containers)
+		updateRevision := ""
+		if templates && sts.Spec.UpdateStrategy.Type == appsv1.OnDeleteStatefulSetStrategyType {
+			updateRevision = sts.Status.UpdateRevision
+			revisions = append(revisions, group.Name+"="+updateRevision)
+		}
+		// The operator also restarts the pods of groups whose VPA runs in
+		// Auto mode, whatever their update strategy.
+		vertical := groupVerticalAutoscaling(group)
+		vpaAuto := vertical != nil && vertical.Mode == marklogicv1.VerticalAutoscalingAuto
+		if (sts.Spec.UpdateStrategy.Type != appsv1.OnDeleteStatefulSetStrategyType && !vpaAuto) || template == nil {
+			continue
+		}
+		matches, err := cc.podResourcesMatcher(group, template)
+		if err != nil {
+			return nil, 0, nil, err
+		}
+		if sts.Status.ObservedGeneration < sts.Generation {
+			settled = false
+		}
+		list := &corev1.PodList{}
+		if err := cc.Client.List(cc.Ctx, list, client.InNamespace(cr.Namespace), client.MatchingLabels{
+			"app.kubernetes.io/name":     "marklogic",
+			"app.kubernetes.io/instance": group.Name,
+		}); err != nil {
+			return nil, 0, nil, err
+		}
+		if sts.Spec.Replicas != nil && int32(len(list.Items)) < *sts.Spec.Replicas {
+			settled = false
+		}
+		var candidate *corev1.Pod
+		for i := range list.Items {
+			pod := &list.Items[i]
+			if pod.DeletionTimestamp != nil || !hasPodReadyCondition(pod) {
+				settled = false
+			}
+			container := marklogicServerContainer(pod.Spec.Containers)
+			if container == nil {
+				continue
+			}
+			templateChanged := updateRevision != "" && pod.Labels[appsv1.StatefulSetRevisionLabel] != updateRevision &&
+				container.Image == template.Image
+			if matches(container.Resources) && !templateChanged {
+				continue
+			}
+			outdated++
+			if candidate == nil || parseOrdinalFromName(pod.Name) > parseOrdinalFromName(candidate.Name) {
+				candidate = pod
+			}
+		}
+		if next == nil {
+			next = candidate
+		}
+	}
+	if !settled {
+		return nil, outdated, revisions, nil
+	}
+	return next, outdated, revisions, nil
+}
+
+// restartBatching returns spec.upgrade.restartBatching, or nil.
+func restartBatching(cr *marklogicv1.MarklogicCluster) *marklogicv1.RestartBatching {
+	if cr.Spec.Upgrade == nil {
+		return nil
+	}
+	return cr.Spec.Upgrade.RestartBatching
+}
+
+func restartSettleWindow(batching *marklogicv1.RestartBatching) time.Duration {
+	if batching.SettleWindow != nil {
+		return batching.SettleWindow.Duration
+	}
+	return defaultRestartSettleWindow
+}
+
+// rolloutChanges describes what the rollout applies in its messages.
+func rolloutChanges(batching *marklogicv1.RestartBatching) string {
+	if batching == nil {
+		return "resources"
+	}
+	return "configuration"
+}
+
+func marklogicServerContainer(containers []corev1.Container) *corev1.Container {
+	for i := range containers {
+		if containers[i].Name == "marklogic-server" {
+			return &containers[i]
+		}
+	}
+	return nil
+}
+
+// resourcesMatch compares the resources of a pod with its template. The API
+// server defaults missing requests to the limits on pods only, so the same
+// defaulting is applied to both sides, and quantities compare by value.
+func resourcesMatch(pod, template corev1.ResourceRequirements) bool {
+	return resourceListsEqual(pod.Limits, template.Limits) &&
+		resourceListsEqual(defaultedRequests(pod), defaultedRequests(template))
+}
+
+func defaultedRequests(resources corev1.ResourceRequirements) corev1.ResourceList {
+	requests := corev1.ResourceList{}
+	for name, quantity := range resources.Requests {
+		requests[name] = quantity
+	}
+	for name, limit := range resources.Limits {
+		if _, ok := requests[name]; !ok {
+			requests[name] = limit
+		}
+	}
+	return requests
+}
+
+func resourceListsEqual(a, b corev1.ResourceList) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name, quantity := range a {
+		other, ok := b[name]
+		if !ok || quantity.Cmp(other) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (cc *ClusterContext) setResourceRolloutStatus(rollout *marklogicv1.ResourceRolloutStatus, next result.ReconcileResult) result.ReconcileResult {
+	cr := cc.MarklogicCluster
+	patchBase := client.MergeFrom(cr.DeepCopy())
+	cr.Status.ResourceRollout = rollout
+	setRolloutLock(cr, rolloutLockResourceRollout, rollout.State == marklogicv1.ResourceRolloutInProgress,
+		fmt.Sprintf("resource rollout restarts %d pod(s) with outdated resources", rollout.OutdatedPods))
+	if err := patchStatus(cc.Ctx, cc.Client, cr, patchBase); err != nil {
+		return result.Error(err)
+	}
+	return next
+}
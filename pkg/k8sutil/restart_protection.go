@@ -0,0 +1,132 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ForceRestartAnnotation lets the operator restart a pod although its
+	// host still merges or reindexes.
+	ForceRestartAnnotation = "marklogic.progress.com/force-restart"
+
+	restartReasonHeld        = "PodRestartHeld"
+	restartReasonHoldExpired = "PodRestartHoldExpired"
+	restartReasonForced      = "PodRestartForced"
+
+	defaultBackgroundJobTimeout   = time.Hour
+	backgroundJobRequeueSeconds   = 60
+	backgroundJobMaxListedForests = 5
+)
+
+// holdPodRestart reports whether the restart of pod has to wait for merges
+// or reindexing on its host, following spec.upgrade.backgroundJobs. hold is
+// the hold recorded in the status of the caller and is updated in place. A
+// failed check does not block the restart: health gates still protect the
+// rollout, and a Manage API outage would otherwise stall it for good.
+func (cc *ClusterContext) holdPodRestart(pod *corev1.Pod, hold **marklogicv1.UpgradePodHold, now metav1.Time) (bool, string) {
+	protection := cc.backgroundJobProtection()
+	if protection.Policy == marklogicv1.BackgroundJobIgnore {
+		*hold = nil
+		return false, ""
+	}
+	jobs, err := cc.hostBackgroundJobs(*pod)
+	if err != nil {
+		cc.ReqLogger.Error(err, "Failed to check background jobs before restarting pod, restarting anyway", "pod", pod.Name)
+		*hold = nil
+		return false, ""
+	}
+	if jobs == "" {
+		*hold = nil
+		return false, ""
+	}
+	if pod.Annotations[ForceRestartAnnotation] == "true" {
+		*hold = nil
+		cc.recordClusterEvent(corev1.EventTypeWarning, restartReasonForced,
+			fmt.Sprintf("restarting pod %s by %s although its host is %s", pod.Name, ForceRestartAnnotation, jobs))
+		return false, ""
+	}
+	if *hold == nil || (*hold).Pod != pod.Name {
+		*hold = &marklogicv1.UpgradePodHold{Pod: pod.Name, Since: now}
+		cc.recordClusterEvent(corev1.EventTypeNormal, restartReasonHeld,
+			fmt.Sprintf("holding back the restart of pod %s, its host is %s", pod.Name, jobs))
+	}
+	(*hold).Jobs = jobs
+	waited := now.Sub((*hold).Since.Time)
+	if protection.Policy == marklogicv1.BackgroundJobWait {
+		timeout := defaultBackgroundJobTimeout
+		if protection.Timeout != nil && protection.Timeout.Duration > 0 {
+			timeout = protection.Timeout.Duration
+		}
+		if waited >= timeout {
+			*hold = nil
+			cc.recordClusterEvent(corev1.EventTypeWarning, restartReasonHoldExpired,
+				fmt.Sprintf("restarting pod %s after waiting %s, its host is still %s", pod.Name, timeout, jobs))
+			return false, ""
+		}
+		return true, fmt.Sprintf("restart of pod %s waits up to %s for its host, which is %s", pod.Name, timeout, jobs)
+	}
+	return true, fmt.Sprintf("restart of pod %s waits for its host, which is %s; annotate the pod with %s=true to restart it now",
+		pod.Name, jobs, ForceRestartAnnotation)
+}
+
+// hostBackgroundJobs describes the merges and reindexing in progress on the
+// host of the pod, or returns an empty string when there are none.
+func (cc *ClusterContext) hostBackgroundJobs(pod corev1.Pod) (string, error) {
+	manage, err := cc.newBootstrapManagementClient()
+	if err != nil {
+		return "", err
+	}
+	forests, err := manage.ListForestsStatus(cc.Ctx)
+	if err != nil {
+		return "", err
+	}
+	host := cc.podHostFQDN(pod)
+	merging, reindexing := []string{}, []string{}
+	for _, forest := range forests {
+		if forest.Host != host {
+			continue
+		}
+		if forest.Merging {
+			merging = append(merging, forest.Name)
+		}
+		if forest.Reindexing {
+			reindexing = append(reindexing, forest.Name)
+		}
+	}
+	jobs := []string{}
+	if len(merging) > 0 {
+		jobs = append(jobs, "merging "+forestList(merging))
+	}
+	if len(reindexing) > 0 {
+		jobs = append(jobs, "reindexing "+forestList(reindexing))
+	}
+	return strings.Join(jobs, " and "), nil
+}
+
+func forestList(forests []string) string {
+	sort.Strings(forests)
+	if len(forests) > backgroundJobMaxListedForests {
+		return fmt.Sprintf("%s and %d more", strings.Join(forests[:backgroundJobMaxListedForests], ", "), len(forests)-backgroundJobMaxListedForests)
+	}
+	return strings.Join(forests, ", ")
+}
+
+func (cc *ClusterContext) backgroundJobProtection() marklogicv1.BackgroundJobProtection {
+	protection := marklogicv1.BackgroundJobProtection{Policy: marklogicv1.BackgroundJobWait}
+	if upgrade := cc.MarklogicCluster.Spec.Upgrade; upgrade != nil && upgrade.BackgroundJobs != nil {
+		protection = *upgrade.BackgroundJobs
+		if protection.Policy == "" {
+			protection.Policy = marklogicv1.BackgroundJobWait
+		}
+	}
+	return protection
+}
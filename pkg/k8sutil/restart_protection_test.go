@@ -0,0 +1,60 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestHoldPodRestartWhileHostMerges(t *testing.T) {
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			ClusterDomain:   "cluster.local",
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{{Name: "dnode", IsBootstrap: true}},
+			Upgrade: &marklogicv1.UpgradeSpec{BackgroundJobs: &marklogicv1.BackgroundJobProtection{
+				Policy:  marklogicv1.BackgroundJobWait,
+				Timeout: &metav1.Duration{Duration: 30 * time.Minute},
+			}},
+		},
+	}
+	pod := newStorageTestPod("dnode-1")
+	cc := newUpgradeTestContext(t, cr, pod)
+	forests := []mlmanage.ForestStatus{
+		{Name: "Documents-2", Host: "dnode-1.dnode.default.svc.cluster.local", Merging: true},
+		{Name: "Documents-1", Host: "dnode-0.dnode.default.svc.cluster.local", Reindexing: true},
+	}
+	stubHealthyManagementClient(t, &forests)
+
+	var hold *marklogicv1.UpgradePodHold
+	now := metav1.Now()
+	held, message := cc.holdPodRestart(pod, &hold, now)
+	if !held || hold == nil || hold.Jobs != "merging Documents-2" || !strings.Contains(message, "waits up to 30m0s") {
+		t.Fatalf("expected the restart to wait for the merge, got %t %q %+v", held, message, hold)
+	}
+
+	if held, _ := cc.holdPodRestart(pod, &hold, metav1.NewTime(now.Add(31*time.Minute))); held || hold != nil {
+		t.Fatalf("expected the restart to go ahead after the timeout, got %+v", hold)
+	}
+
+	cr.Spec.Upgrade.BackgroundJobs.Policy = marklogicv1.BackgroundJobRequireForce
+	if held, message := cc.holdPodRestart(pod, &hold, metav1.NewTime(now.Add(2*time.Hour))); !held || !strings.Contains(message, ForceRestartAnnotation) {
+		t.Fatalf("expected the restart to require the force annotation, got %t %q", held, message)
+	}
+	pod.Annotations = map[string]string{ForceRestartAnnotation: "true"}
+	if held, _ := cc.holdPodRestart(pod, &hold, metav1.NewTime(now.Add(2*time.Hour))); held || hold != nil {
+		t.Fatalf("expected a forced restart, got %+v", hold)
+	}
+
+	pod.Annotations = nil
+	forests[0].Merging = false
+	if held, _ := cc.holdPodRestart(pod, &hold, now); held {
+		t.Fatalf("expected the restart to go ahead once the merge finished")
+	}
+}
@@ -0,0 +1,195 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	restoreReasonStarted   = "RestoreStarted"
+	restoreReasonCompleted = "RestoreCompleted"
+	restoreReasonFailed    = "RestoreFailed"
+
+	restorePollIntervalSeconds = 15
+)
+
+// ReconcileBackupRestore drives spec.backup.restore. The restore covers every
+// forest of the database and, when restoreToTime is set, replays archived
+// journals up to that timestamp. Progress is tracked per forest in
+// status.backup.restore until MarkLogic reports the job finished.
+func (cc *ClusterContext) ReconcileBackupRestore() result.ReconcileResult {
+	cr := cc.MarklogicCluster
+	if cr.Spec.Backup == nil || cr.Spec.Backup.Restore == nil {
+		return result.Continue()
+	}
+	restore := cr.Spec.Backup.Restore
+	logger := cc.ReqLogger.WithValues("restoreId", restore.RestoreID, "database", restore.Database)
+
+	var current *marklogicv1.RestoreStatus
+	if cr.Status.Backup != nil && cr.Status.Backup.Restore != nil && cr.Status.Backup.Restore.RestoreID == restore.RestoreID {
+		current = cr.Status.Backup.Restore
+	}
+	if current != nil && (current.Phase == marklogicv1.RestorePhaseCompleted || current.Phase == marklogicv1.RestorePhaseFailed) {
+		return result.Continue()
+	}
+
+	mgmtClient, err := cc.newBootstrapManagementClient()
+	if err != nil {
+		logger.Error(err, "Failed to build management client for restore")
+		return result.RequeueSoon(restorePollIntervalSeconds)
+	}
+
+	if current == nil || current.JobID == "" {
+		return cc.startBackupRestore(mgmtClient, restore)
+	}
+
+	jobStatus, err := mgmtClient.GetDatabaseRestoreStatus(cc.Ctx, restore.Database, current.JobID)
+	if err != nil {
+		if isTransientManagementError(err) {
+			return result.RequeueSoon(restorePollIntervalSeconds)
+		}
+		return cc.finishBackupRestore(current, marklogicv1.RestorePhaseFailed, fmt.Sprintf("failed to read restore status: %v", err))
+	}
+	next := current.DeepCopy()
+	next.Forests = forestRestoreStatuses(next.Forests, jobStatus.Forests)
+	switch jobStatus.Status {
+	case "completed":
+		return cc.finishBackupRestore(next, marklogicv1.RestorePhaseCompleted, "restore completed")
+	case "failed", "cancelled":
+		return cc.finishBackupRestore(next, marklogicv1.RestorePhaseFailed, fmt.Sprintf("restore job %s %s", next.JobID, jobStatus.Status))
+	}
+	next.Message = fmt.Sprintf("restore job %s is %s", next.JobID, jobStatus.Status)
+	if err := cc.setRestoreStatus(next); err != nil {
+		return result.Error(err)
+	}
+	return result.RequeueSoon(restorePollIntervalSeconds)
+}
+
+func (cc *ClusterContext) startBackupRestore(mgmtClient mlmanage.Client, restore *marklogicv1.BackupRestore) result.ReconcileResult {
+	status := &marklogicv1.RestoreStatus{
+		RestoreID:     restore.RestoreID,
+		Database:      restore.Database,
+		Directory:     restoreDirectory(cc.MarklogicCluster.Spec.Backup, restore),
+		RestoreToTime: restore.RestoreToTime,
+	}
+	if status.Directory == "" {
+		return cc.finishBackupRestore(status, marklogicv1.RestorePhaseFailed, "restore directory is not set and no backup storage is configured")
+	}
+	journalArchive := databaseHasJournalArchiving(cc.MarklogicCluster.Spec.Backup, restore.Database)
+	if restore.RestoreToTime != nil && !journalArchive {
+		return cc.finishBackupRestore(status, marklogicv1.RestorePhaseFailed, fmt.Sprintf("restoreToTime requires journal archiving on a schedule for database %s", restore.Database))
+	}
+
+	forests, err := mgmtClient.ListDatabaseForests(cc.Ctx, restore.Database)
+	if err != nil {
+		cc.ReqLogger.Error(err, "Failed to list database forests for restore")
+		return result.RequeueSoon(restorePollIntervalSeconds)
+	}
+	if len(forests) == 0 {
+		return cc.finishBackupRestore(status, marklogicv1.RestorePhaseFailed, fmt.Sprintf("database %s has no forests", restore.Database))
+	}
+	sort.Strings(forests)
+
+	req := mlmanage.DatabaseRestoreRequest{
+		Directory:       status.Directory,
+		Forests:         forests,
+		JournalArchive:  journalArchive,
+		IncludeReplicas: restore.IncludeReplicas,
+	}
+	if restore.RestoreToTime != nil {
+		req.RestoreToTime = restore.RestoreToTime.UTC().Format(time.RFC3339)
+	}
+	jobID, err := mgmtClient.StartDatabaseRestore(cc.Ctx, restore.Database, req)
+	if err != nil {
+		if isTransientManagementError(err) {
+			return result.RequeueSoon(restorePollIntervalSeconds)
+		}
+		return cc.finishBackupRestore(status, marklogicv1.RestorePhaseFailed, fmt.Sprintf("failed to start restore: %v", err))
+	}
+
+	now := metav1.Now()
+	status.JobID = jobID
+	status.Phase = marklogicv1.RestorePhaseRunning
+	status.StartTime = &now
+	status.Message = fmt.Sprintf("restore job %s started for %d forest(s)", jobID, len(forests))
+	for _, forest := range forests {
+		status.Forests = append(status.Forests, marklogicv1.ForestRestoreStatus{Name: forest, Status: "pending"})
+	}
+	if err := cc.setRestoreStatus(status); err != nil {
+		return result.Error(err)
+	}
+	if cc.Recorder != nil {
+		cc.Recorder.Event(cc.MarklogicCluster, "Normal", restoreReasonStarted, status.Message)
+	}
+	return result.RequeueSoon(restorePollIntervalSeconds)
+}
+
+func (cc *ClusterContext) finishBackupRestore(status *marklogicv1.RestoreStatus, phase marklogicv1.RestorePhase, message string) result.ReconcileResult {
+	now := metav1.Now()
+	status.Phase = phase
+	status.Message = message
+	status.CompletionTime = &now
+	if err := cc.setRestoreStatus(status); err != nil {
+		return result.Error(err)
+	}
+	if cc.Recorder != nil {
+		if phase == marklogicv1.RestorePhaseCompleted {
+			cc.Recorder.Event(cc.MarklogicCluster, "Normal", restoreReasonCompleted, message)
+		} else {
+			cc.Recorder.Event(cc.MarklogicCluster, "Warning", restoreReasonFailed, message)
+		}
+	}
+	return result.Continue()
+}
+
+func (cc *ClusterContext) setRestoreStatus(status *marklogicv1.RestoreStatus) error {
+	cr := cc.MarklogicCluster
+	patchBase := client.MergeFrom(cr.DeepCopy())
+	if cr.Status.Backup == nil {
+		cr.Status.Backup = &marklogicv1.BackupStatus{}
+	}
+	cr.Status.Backup.Restore = status
+	return patchStatus(cc.Ctx, cc.Client, cr, patchBase)
+}
+
+// restoreDirectory resolves the backup directory to restore from.
+func restoreDirectory(backup *marklogicv1.Backup, restore *marklogicv1.BackupRestore) string {
+	if restore.Directory != "" {
+		return restore.Directory
+	}
+	if schedules := groupBackupSchedulesByDatabase(backup)[restore.Database]; len(schedules) > 0 {
+		return schedules[0].Directory
+	}
+	if backup.Storage != nil {
+		return backupDirectory(backup.Storage) + restore.Database + "/"
+	}
+	return ""
+}
+
+func databaseHasJournalArchiving(backup *marklogicv1.Backup, database string) bool {
+	for _, schedule := range backup.Schedules {
+		if schedule.Database == database && schedule.JournalArchiving {
+			return true
+		}
+	}
+	return false
+}
+
+// forestRestoreStatuses merges the per forest job status into the tracked forest list.
+func forestRestoreStatuses(tracked []marklogicv1.ForestRestoreStatus, reported map[string]string) []marklogicv1.ForestRestoreStatus {
+	for i := range tracked {
+		if status, ok := reported[tracked[i].Name]; ok && status != "" {
+			tracked[i].Status = status
+		}
+	}
+	return tracked
+}
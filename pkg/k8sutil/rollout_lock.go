@@ -0,0 +1,198 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cisco-open/k8s-objectmatcher/patch"
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/features"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// ForceChangeDuringUpgradeAnnotation lets a MarklogicCluster change
+	// replicas, storage, images and the rest of its groups while an upgrade
+	// is active when set to "true".
+	ForceChangeDuringUpgradeAnnotation = "marklogic.progress.com/force-change-during-upgrade"
+
+	// The workflows that restart pods hold the rollout lock, see
+	// rolloutLockHolder.
+	rolloutLockUpgrade         = "Upgrade"
+	rolloutLockResourceRollout = "ResourceRollout"
+	rolloutLockReasonReleased  = "Released"
+
+	rolloutReasonGroupChangesDeferred = "GroupChangesDeferred"
+	upgradeReasonChangesHeld          = "ChangesHeld"
+	upgradeReasonNoChangesHeld        = "NoChangesHeld"
+)
+
+// rolloutLockHolder returns the workflow holding the rollout lock of the
+// cluster, or "" when no workflow restarts pods. The lock is the RolloutLocked
+// condition, so the upgrade, the resource rollout and the cluster reconcile
+// all read the same state. A lock of the upgrade is ignored while the
+// UpgradeWorkflow feature gate is disabled, as nothing would release it.
+func rolloutLockHolder(cr *marklogicv1.MarklogicCluster) string {
+	for _, condition := range cr.Status.Conditions {
+		if condition.Type != string(marklogicv1.RolloutLocked) || condition.Status != metav1.ConditionTrue {
+			continue
+		}
+		if condition.Reason == rolloutLockUpgrade && !features.Enabled(features.UpgradeWorkflow) {
+			return ""
+		}
+		return condition.Reason
+	}
+	return ""
+}
+
+// setRolloutLock makes holder hold the rollout lock, or releases the lock of
+// holder when held is false. The status is patched by the caller. The
+// generation the lock was acquired at is kept while the same workflow holds
+// it.
+func setRolloutLock(cr *marklogicv1.MarklogicCluster, holder string, held bool, message string) {
+	current := rolloutLockHolder(cr)
+	if !held {
+		if current != holder {
+			return
+		}
+		cr.Status.SetCondition(metav1.Condition{
+			Type:               string(marklogicv1.RolloutLocked),
+			Status:             metav1.ConditionFalse,
+			Reason:             rolloutLockReasonReleased,
+			Message:            fmt.Sprintf("released by %s", holder),
+			ObservedGeneration: cr.Generation,
+			LastTransitionTime: metav1.Now(),
+		})
+		return
+	}
+	condition := metav1.Condition{
+		Type:               string(marklogicv1.RolloutLocked),
+		Status:             metav1.ConditionTrue,
+		Reason:             holder,
+		Message:            message,
+		ObservedGeneration: cr.Generation,
+		LastTransitionTime: metav1.Now(),
+	}
+	if current == holder {
+		for _, existing := range cr.Status.Conditions {
+			if existing.Type == condition.Type {
+				condition.ObservedGeneration = existing.ObservedGeneration
+				condition.LastTransitionTime = existing.LastTransitionTime
+			}
+		}
+	}
+	cr.Status.SetCondition(condition)
+}
+
+// upgradeHoldsRolloutLock reports whether the upgrade restarts pods: from the
+// start of its rollout until it completes or is cancelled. A failed rollout
+// keeps the lock, as its pods run on two images.
+func upgradeHoldsRolloutLock(upgrade *marklogicv1.UpgradeStatus) bool {
+	return upgrade != nil && upgrade.RolloutStarted &&
+		(upgrade.State == marklogicv1.UpgradeStateInProgress || upgrade.State == marklogicv1.UpgradeStateFailed)
+}
+
+// upgradeHoldsChanges reports whether the upgrade holds back changes of the
+// groups: while it is active or holds the rollout lock, unless the
+// ForceChangeDuringUpgradeAnnotation is set.
+func upgradeHoldsChanges(cr *marklogicv1.MarklogicCluster) bool {
+	if !cr.Status.Upgrade.Active() && rolloutLockHolder(cr) != rolloutLockUpgrade {
+		return false
+	}
+	return ClusterOperation(cr, ForceChangeDuringUpgradeAnnotation) != "true"
+}
+
+// deferGroupChanges holds back the changes of a MarklogicGroup an upgrade
+// must not interleave with, and returns the unsafe ones among them: changed
+// replicas, persistence and images other than those of the upgrade, which
+// the admission webhook rejects when it runs. From the precheck on only the
+// unsafe changes are held. While the upgrade holds the rollout lock the
+// current spec is kept apart from the image the upgrade rolls out, so no
+// other change of the groups lands on the pods the upgrade restarts. The
+// changes are applied once the upgrade ends and releases the lock, or right
+// away with the ForceChangeDuringUpgradeAnnotation.
+func (cc *ClusterContext) deferGroupChanges(current, desired *marklogicv1.MarklogicGroup) ([]string, error) {
+	cr := cc.MarklogicCluster
+	if !upgradeHoldsChanges(cr) {
+		return nil, nil
+	}
+	unsafe := unsafeGroupChanges(cr, current, desired)
+	if rolloutLockHolder(cr) != rolloutLockUpgrade {
+		for _, change := range unsafe {
+			switch change {
+			case "replicas":
+				desired.Spec.Replicas = current.Spec.Replicas
+			case "persistence":
+				desired.Spec.Persistence = current.Spec.Persistence
+			case "image":
+				desired.Spec.Image = current.Spec.Image
+			}
+		}
+		return unsafe, nil
+	}
+	imageOnly := current.DeepCopy()
+	imageOnly.Spec.Image = desired.Spec.Image
+	patchDiff, err := patch.DefaultPatchMaker.Calculate(imageOnly, desired,
+		patch.IgnoreStatusFields(),
+		patch.IgnoreVolumeClaimTemplateTypeMetaAndStatus(),
+		patch.IgnoreField("kind"))
+	if err != nil {
+		return nil, err
+	}
+	if patchDiff.IsEmpty() {
+		return unsafe, nil
+	}
+	desired.Spec = imageOnly.Spec
+	cc.ReqLogger.Info("Deferring MarkLogicGroup changes until the upgrade completes", "group", current.Name)
+	cc.recordClusterEvent(corev1.EventTypeNormal, rolloutReasonGroupChangesDeferred,
+		fmt.Sprintf("changes to group %s are deferred until the upgrade completes", current.Name))
+	return unsafe, nil
+}
+
+// unsafeGroupChanges returns the fields of desired that change the replicas,
+// persistence or image of the group. Replicas changed by stopping or starting
+// the cluster and the images of the upgrade are not counted.
+func unsafeGroupChanges(cr *marklogicv1.MarklogicCluster, current, desired *marklogicv1.MarklogicGroup) []string {
+	var changes []string
+	run := cr.Status.Run
+	if (run == nil || run.State == marklogicv1.ClusterRunStateRunning) &&
+		!equality.Semantic.DeepEqual(current.Spec.Replicas, desired.Spec.Replicas) {
+		changes = append(changes, "replicas")
+	}
+	if !equality.Semantic.DeepEqual(current.Spec.Persistence, desired.Spec.Persistence) {
+		changes = append(changes, "persistence")
+	}
+	if upgrade := cr.Status.Upgrade; desired.Spec.Image != current.Spec.Image &&
+		(upgrade == nil || (desired.Spec.Image != upgrade.TargetImage && desired.Spec.Image != upgrade.CurrentImage)) {
+		changes = append(changes, "image")
+	}
+	return changes
+}
+
+// setUpgradeChangesHeld reports the changes the upgrade holds back in the
+// UpgradeChangesHeld condition of the cluster.
+func (cc *ClusterContext) setUpgradeChangesHeld(held []string) error {
+	cr := cc.MarklogicCluster
+	patchBase := client.MergeFrom(cr.DeepCopy())
+	if len(held) == 0 {
+		if cr.Status.GetConditionStatus(string(marklogicv1.UpgradeChangesHeld)) != metav1.ConditionTrue {
+			return nil
+		}
+		cc.setClusterCondition(marklogicv1.UpgradeChangesHeld, metav1.ConditionFalse, upgradeReasonNoChangesHeld,
+			"no changes are held back")
+	} else {
+		state := marklogicv1.UpgradeStateInProgress
+		if cr.Status.Upgrade != nil {
+			state = cr.Status.Upgrade.State
+		}
+		cc.setClusterCondition(marklogicv1.UpgradeChangesHeld, metav1.ConditionTrue, upgradeReasonChangesHeld,
+			fmt.Sprintf("the upgrade in state %s holds back %s, set the %s annotation to \"true\" to apply them",
+				state, strings.Join(held, "; "), ForceChangeDuringUpgradeAnnotation))
+	}
+	return patchStatus(cc.Ctx, cc.Client, cr, patchBase)
+}
@@ -0,0 +1,145 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"testing"
+
+	"github.com/cisco-open/k8s-objectmatcher/patch"
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestUpgradeRolloutLockDefersGroupChanges(t *testing.T) {
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+		Spec:       marklogicv1.MarklogicClusterSpec{Image: upgradeTestPatchImage},
+		Status: marklogicv1.MarklogicClusterStatus{Upgrade: &marklogicv1.UpgradeStatus{
+			State:          marklogicv1.UpgradeStateInProgress,
+			CurrentImage:   upgradeTestOldImage,
+			TargetImage:    upgradeTestPatchImage,
+			RolloutStarted: true,
+		}},
+	}
+	setRolloutLock(cr, rolloutLockUpgrade, upgradeHoldsRolloutLock(cr.Status.Upgrade), "upgrade restarts pods")
+	cc := newUpgradeTestContext(t, cr)
+
+	replicas, scaled := int32(3), int32(5)
+	current := &marklogicv1.MarklogicGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "dnode", Namespace: "default"},
+		Spec:       marklogicv1.MarklogicGroupSpec{Replicas: &replicas, Image: upgradeTestOldImage},
+	}
+	if err := patch.DefaultAnnotator.SetLastAppliedAnnotation(current); err != nil {
+		t.Fatalf("failed to set last applied annotation: %v", err)
+	}
+	desired := func() *marklogicv1.MarklogicGroup {
+		return &marklogicv1.MarklogicGroup{
+			ObjectMeta: metav1.ObjectMeta{Name: "dnode", Namespace: "default"},
+			Spec:       marklogicv1.MarklogicGroupSpec{Replicas: &scaled, Image: upgradeTestPatchImage},
+		}
+	}
+
+	group := desired()
+	unsafe, err := cc.deferGroupChanges(current, group)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(unsafe) != 1 || unsafe[0] != "replicas" {
+		t.Fatalf("expected the replicas to be reported as held, got %v", unsafe)
+	}
+	if *group.Spec.Replicas != replicas || group.Spec.Image != upgradeTestPatchImage {
+		t.Fatalf("expected only the image to apply while the upgrade holds the lock, got %d replicas and image %s", *group.Spec.Replicas, group.Spec.Image)
+	}
+
+	cr.Annotations = map[string]string{ForceChangeDuringUpgradeAnnotation: "true"}
+	group = desired()
+	if _, err := cc.deferGroupChanges(current, group); err != nil || *group.Spec.Replicas != scaled {
+		t.Fatalf("expected the force annotation to apply the change, got %d replicas, %v", *group.Spec.Replicas, err)
+	}
+
+	cr.Annotations = nil
+	cr.Status.Upgrade.State = marklogicv1.UpgradeStateCompleted
+	setRolloutLock(cr, rolloutLockUpgrade, upgradeHoldsRolloutLock(cr.Status.Upgrade), "")
+	group = desired()
+	if _, err := cc.deferGroupChanges(current, group); err != nil || *group.Spec.Replicas != scaled {
+		t.Fatalf("expected the change to apply once the lock is released, got %d replicas, %v", *group.Spec.Replicas, err)
+	}
+}
+
+func TestActiveUpgradeHoldsUnsafeGroupChanges(t *testing.T) {
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+		Spec:       marklogicv1.MarklogicClusterSpec{Image: upgradeTestNewImage},
+		Status: marklogicv1.MarklogicClusterStatus{Upgrade: &marklogicv1.UpgradeStatus{
+			State:        marklogicv1.UpgradeStateWaitingForUserApproval,
+			CurrentImage: upgradeTestOldImage,
+			TargetImage:  upgradeTestNewImage,
+		}},
+	}
+	cc := newUpgradeTestContext(t, cr)
+
+	replicas, scaled := int32(3), int32(5)
+	current := &marklogicv1.MarklogicGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "dnode", Namespace: "default"},
+		Spec: marklogicv1.MarklogicGroupSpec{
+			Replicas:    &replicas,
+			Image:       upgradeTestOldImage,
+			Persistence: &marklogicv1.Persistence{Enabled: true, Size: "10Gi"},
+		},
+	}
+	desired := func() *marklogicv1.MarklogicGroup {
+		return &marklogicv1.MarklogicGroup{
+			ObjectMeta: metav1.ObjectMeta{Name: "dnode", Namespace: "default"},
+			Spec: marklogicv1.MarklogicGroupSpec{
+				Replicas:          &scaled,
+				Image:             upgradeTestOldImage,
+				Persistence:       &marklogicv1.Persistence{Enabled: true, Size: "20Gi"},
+				PriorityClassName: "marklogic",
+			},
+		}
+	}
+
+	group := desired()
+	unsafe, err := cc.deferGroupChanges(current, group)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(unsafe) != 2 || unsafe[0] != "replicas" || unsafe[1] != "persistence" {
+		t.Fatalf("expected the replicas and persistence to be held, got %v", unsafe)
+	}
+	if *group.Spec.Replicas != replicas || group.Spec.Persistence.Size != "10Gi" || group.Spec.PriorityClassName != "marklogic" {
+		t.Fatalf("expected only the safe changes to apply before the rollout, got %+v", group.Spec)
+	}
+
+	if err := cc.setUpgradeChangesHeld([]string{"replicas, persistence of group dnode"}); err != nil {
+		t.Fatalf("failed to set the condition: %v", err)
+	}
+	stored := &marklogicv1.MarklogicCluster{}
+	if err := cc.Client.Get(cc.Ctx, client.ObjectKeyFromObject(cr), stored); err != nil {
+		t.Fatalf("failed to read the cluster: %v", err)
+	}
+	if stored.Status.GetConditionStatus(string(marklogicv1.UpgradeChangesHeld)) != metav1.ConditionTrue {
+		t.Fatalf("expected the UpgradeChangesHeld condition, got %+v", stored.Status.Conditions)
+	}
+
+	cr.Status.Run = &marklogicv1.ClusterRunStatus{State: marklogicv1.ClusterRunStateStopping}
+	group = desired()
+	group.Spec.Persistence = current.Spec.Persistence
+	if unsafe, err := cc.deferGroupChanges(current, group); err != nil || len(unsafe) != 0 || *group.Spec.Replicas != scaled {
+		t.Fatalf("expected the replicas of a stopping cluster to apply, got %v %d, %v", unsafe, *group.Spec.Replicas, err)
+	}
+
+	cr.Status.Run = nil
+	cr.Annotations = map[string]string{ForceChangeDuringUpgradeAnnotation: "true"}
+	group = desired()
+	if unsafe, err := cc.deferGroupChanges(current, group); err != nil || len(unsafe) != 0 || group.Spec.Persistence.Size != "20Gi" {
+		t.Fatalf("expected the force annotation to apply the changes, got %v %+v, %v", unsafe, group.Spec.Persistence, err)
+	}
+	if err := cc.setUpgradeChangesHeld(nil); err != nil {
+		t.Fatalf("failed to clear the condition: %v", err)
+	}
+	if status := cr.Status.GetConditionStatus(string(marklogicv1.UpgradeChangesHeld)); status != metav1.ConditionFalse {
+		t.Fatalf("expected the condition to be cleared, got %s", status)
+	}
+}
@@ -7,6 +7,13 @@
 
 N_RETRY=10
 RETRY_INTERVAL=5
+# Hosts join in parallel, at most JOIN_MAX_CONCURRENT at a time (0 for no
+# limit), and retry a failed join JOIN_RETRIES times with an exponential backoff
+JOIN_MAX_CONCURRENT=${MARKLOGIC_JOIN_MAX_CONCURRENT:-0}
+JOIN_RETRIES=${MARKLOGIC_JOIN_RETRIES:-10}
+JOIN_BACKOFF=${MARKLOGIC_JOIN_BACKOFF_SECONDS:-5}
+JOIN_MAX_BACKOFF=${MARKLOGIC_JOIN_MAX_BACKOFF_SECONDS:-60}
+JOIN_QUEUE_TIMEOUT=600
 HOSTNAME=$(cat /etc/hostname)
 HOST_FQDN="${HOSTNAME}.${MARKLOGIC_FQDN_SUFFIX}"
 ML_KUBERNETES_FILE_PATH="/var/opt/MarkLogic/Kubernetes"
@@ -109,6 +116,14 @@ else
     LICENSE_PAYLOAD="{\"license-key\" : \"${LICENSE_KEY}\",\"licensee\" : \"${LICENSEE}\"}"
 fi
 
+# the realm and wallet password can be set in the admin secret
+if [[ -z "${REALM}" ]] && [[ -s /run/secrets/ml-secrets/realm ]]; then
+    REALM="$(< /run/secrets/ml-secrets/realm)"
+fi
+if [[ -z "${MARKLOGIC_WALLET_PASSWORD}" ]] && [[ -s /run/secrets/ml-secrets/wallet-password ]]; then
+    MARKLOGIC_WALLET_PASSWORD="$(< /run/secrets/ml-secrets/wallet-password)"
+fi
+
 # sets realm conditionally based on user input
 if [[ -z "${REALM}" ]]; then
     ML_REALM="public"
@@ -312,6 +327,100 @@ function init_security_db {
     fi
 }
 
+################################################################
+# Function to wait before the next join attempt
+# $1: The number of the failed attempt, starting at 1
+# The delay doubles with every attempt up to JOIN_MAX_BACKOFF, plus a
+# random jitter of up to half the delay so hosts that failed together
+# do not retry together.
+################################################################
+function join_backoff {
+    local attempt=$1 i
+    local delay=$JOIN_BACKOFF
+    for ((i = 1; i < attempt && delay < JOIN_MAX_BACKOFF; i++)); do
+        delay=$((delay * 2))
+    done
+    if [[ $delay -gt $JOIN_MAX_BACKOFF ]]; then
+        delay=$JOIN_MAX_BACKOFF
+    fi
+    delay=$((delay + RANDOM % (delay / 2 + 1)))
+    info "retrying in ${delay}s"
+    sleep ${delay}
+}
+
+################################################################
+# join_retry(description, expected_response_code, curl_options...)
+# Run a curl command of the join until it returns the expected
+# response code, retrying JOIN_RETRIES times with join_backoff.
+# Exit with an error when every attempt failed.
+################################################################
+function join_retry {
+    local description=$1; shift
+    local expected_response_code=$1; shift
+    local attempt response_code
+
+    for ((attempt = 1; ; attempt++)); do
+        response_code=$(curl -s -m 30 -w '%{http_code}' "$@")
+        if [[ "${response_code}" == "${expected_response_code}" ]]; then
+            return 0
+        fi
+        if [[ $attempt -gt $JOIN_RETRIES ]]; then
+            error "Failed to ${description} after ${JOIN_RETRIES} retries, last response code: ${response_code}. Exit." exit
+        fi
+        info "Failed to ${description}, response code: ${response_code}, attempt ${attempt}"
+        join_backoff $attempt
+    done
+}
+
+################################################################
+# Function to check if a host has joined the cluster
+# $1: The host name
+# return values: 0 - the host is in the cluster
+################################################################
+function host_joined {
+    local response_code
+    response_code=$(curl -s --anyauth -m 20 -o /dev/null -w '%{http_code}' \
+        --user "${MARKLOGIC_ADMIN_USERNAME}":"${MARKLOGIC_ADMIN_PASSWORD}" $HTTPS_OPTION \
+        $HTTP_PROTOCOL://${MARKLOGIC_BOOTSTRAP_HOST}:8002/manage/v2/hosts/$1/properties?format=xml \
+    )
+    [[ "${response_code}" == "200" ]]
+}
+
+################################################################
+# Function to wait for the turn of this host to join
+# With JOIN_MAX_CONCURRENT set, a host waits for the host
+# JOIN_MAX_CONCURRENT ordinals before it to join, so at most
+# JOIN_MAX_CONCURRENT hosts of the group join at the same time.
+# A host that did not join in JOIN_QUEUE_TIMEOUT no longer holds
+# the hosts after it.
+################################################################
+function wait_join_turn {
+    local ordinal=${HOSTNAME##*-}
+    local first_ordinal=0
+    local waited=0
+    if [[ $JOIN_MAX_CONCURRENT -le 0 ]]; then
+        return 0
+    fi
+    # Pod 0 of the bootstrap group is the bootstrap host and does not join
+    if [[ "$MARKLOGIC_CLUSTER_TYPE" == "bootstrap" ]]; then
+        first_ordinal=1
+    fi
+    local previous=$((ordinal - JOIN_MAX_CONCURRENT))
+    if [[ $previous -lt $first_ordinal ]]; then
+        return 0
+    fi
+    local previous_host="${HOSTNAME%-*}-${previous}.${MARKLOGIC_FQDN_SUFFIX}"
+    info "waiting for ${previous_host} to join, at most ${JOIN_MAX_CONCURRENT} hosts join at a time"
+    until host_joined "${previous_host}"; do
+        if [[ $waited -ge $JOIN_QUEUE_TIMEOUT ]]; then
+            info "${previous_host} did not join in ${JOIN_QUEUE_TIMEOUT}s, joining anyway"
+            return 0
+        fi
+        sleep 5
+        waited=$((waited + 5))
+    done
+}
+
 ################################################################
 # Function to join marklogic host to cluster
 # 
@@ -376,13 +485,18 @@ function join_cluster {
         fi
     done
 
+    wait_join_turn
+
     info "joining cluster of group ${MARKLOGIC_GROUP}"
     MARKLOGIC_GROUP_PAYLOAD="group=${MARKLOGIC_GROUP}"
     curl_retry_validate false "http://localhost:8001/admin/v1/server-config" 200 \
         "-o" "/tmp/host.xml" "-X" "GET" "-H" "Accept: application/xml"
     
+    # Concurrent joins change the configuration of the bootstrap host at the
+    # same time, so a join that fails is retried with a backoff
     info "getting cluster-config from bootstrap host"
-    curl_retry_validate false "$HTTP_PROTOCOL://${MARKLOGIC_BOOTSTRAP_HOST}:8001/admin/v1/cluster-config" 200 \
+    join_retry "get the cluster-config from the bootstrap host" 200 \
+        "$HTTP_PROTOCOL://${MARKLOGIC_BOOTSTRAP_HOST}:8001/admin/v1/cluster-config" \
         "--anyauth" "--user" "${MARKLOGIC_ADMIN_USERNAME}:${MARKLOGIC_ADMIN_PASSWORD}" \
         "-X" "POST" "-d" "${MARKLOGIC_GROUP_PAYLOAD}" \
         "--data-urlencode" "server-config@/tmp/host.xml" \
@@ -392,7 +506,8 @@ function join_cluster {
     timestamp=$(curl -s --anyauth --user "${MARKLOGIC_ADMIN_USERNAME}:${MARKLOGIC_ADMIN_PASSWORD}" "http://localhost:8001/admin/v1/timestamp" )
 
     info "joining cluster of group ${MARKLOGIC_GROUP}"
-    curl_retry_validate false "http://localhost:8001/admin/v1/cluster-config" 202 \
+    join_retry "apply the cluster-config" 202 \
+            "http://localhost:8001/admin/v1/cluster-config" \
             "-o" "/dev/null" \
             "-X" "POST" "-H" "Content-type: application/zip" \
             "--data-binary" "@/tmp/cluster.zip"
@@ -45,6 +45,21 @@ shutdown_handler() {
 
 trap 'shutdown_handler' SIGTERM SIGINT
 
+# --- Cloned Data Volumes: Rename Hosts ---
+# MARKLOGIC_HOST_RENAMES lists "from=to" group pairs of a cluster cloned from the
+# data volumes of another one. The hosts of the source groups are renamed to the
+# pods of this cluster in the configuration before MarkLogic reads it. Renamed
+# hosts no longer match, so restarts leave the configuration alone.
+HOSTS_XML="/var/opt/MarkLogic/hosts.xml"
+if [[ -n "$MARKLOGIC_HOST_RENAMES" && -f "$HOSTS_XML" ]]; then
+    for rename in $MARKLOGIC_HOST_RENAMES; do
+        from="${rename%%=*}"
+        to="${rename#*=}"
+        echo "[Wrapper] Renaming the hosts of group ${from} to ${to}..."
+        sed -i "s#<host-name>${from}-\([0-9][0-9]*\)\.${from}\.#<host-name>${to}-\1.${to}.#g" "$HOSTS_XML"
+    done
+fi
+
 # --- Phase 1: Background Application Startup ---
 # Detect the correct startup script for backward compatibility with older QA images.
 # QA images use starter.sh (user switching). Official images use start-marklogic.sh.
@@ -91,7 +106,8 @@ echo "[Wrapper] MarkLogic is running with PID: $REAL_ML_PID"
 
 # --- Phase 3: Local Readiness Gate ---
 echo "[Wrapper] Waiting for local socket (localhost:8001)..."
-MAX_STARTUP_WAIT=60
+# Checks run every 2s, MARKLOGIC_BOOT_TIMEOUT_SECONDS is set with the startup probe
+MAX_STARTUP_WAIT=$(( ${MARKLOGIC_BOOT_TIMEOUT_SECONDS:-120} / 2 ))
 startup_count=0
 until ml_port_open; do
     # Note: kill -0 is not used here - EPERM is indistinguishable from ESRCH in
@@ -20,6 +20,46 @@ if [[ "$MARKLOGIC_JOIN_TLS_ENABLED" == "true" ]]; then
     HTTPS_OPTION="-k"
 fi
 log "Info: [prestop] MarkLogic Pod Hostname: "$my_host
+
+# Counts the requests running on this host, leaving out the request that
+# lists them.
+active_requests () {
+    local count
+    count=$(curl --anyauth --user $MARKLOGIC_ADMIN_USERNAME:$MARKLOGIC_ADMIN_PASSWORD \
+        -m 10 -s -f ${HTTPS_OPTION} \
+        "${HTTP_PROTOCOL}://localhost:8002/manage/v2/requests?format=json&host-id=${my_host}" \
+        | grep -o '"list-count":{[^}]*}' | grep -o '"value":[0-9]*' | cut -d: -f2)
+    if [[ -z "$count" ]]; then
+        return 1
+    fi
+    echo $((count > 0 ? count - 1 : 0))
+}
+
+if [[ -n "$MARKLOGIC_DRAIN_TIMEOUT_SECONDS" ]]; then
+    # Fail the readiness probe so Services and HAProxy stop sending new
+    # requests, then give the running ones time to finish.
+    rm -f /tmp/marklogic_ready
+    log "Info: [prestop] Draining, waiting ${MARKLOGIC_DRAIN_DELAY_SECONDS:-0}s for endpoints to drop the pod"
+    sleep ${MARKLOGIC_DRAIN_DELAY_SECONDS:-0}
+    deadline=$((SECONDS + MARKLOGIC_DRAIN_TIMEOUT_SECONDS))
+    while true; do
+        if ! requests=$(active_requests); then
+            log "ERROR: [prestop] Failed to list the active requests, stopping MarkLogic"
+            break
+        fi
+        if [[ $requests -eq 0 ]]; then
+            log "Info: [prestop] No active requests left"
+            break
+        fi
+        if [[ $SECONDS -ge $deadline ]]; then
+            log "Info: [prestop] Drain timed out with $requests active requests, stopping MarkLogic"
+            break
+        fi
+        log "Info: [prestop] Waiting for $requests active requests"
+        sleep 2s
+    done
+fi
+
 for ((i = 0; i < 5; i = i + 1)); do
     res_code=$(curl --anyauth --user $MARKLOGIC_ADMIN_USERNAME:$MARKLOGIC_ADMIN_PASSWORD \
         -o /dev/null -m 10 -s -w %{http_code} \
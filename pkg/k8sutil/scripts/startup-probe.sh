@@ -0,0 +1,36 @@
+#!/bin/bash
+# Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+# Startup probe of the MarkLogic container. Succeeds once MarkLogic answers on
+# port 8001 and, with MARKLOGIC_STARTUP_WAIT_FOR_FORESTS, once every forest of
+# a host that is already in the cluster is open. Until then the liveness probe
+# does not run, so hosts that take long to mount large forests keep running.
+
+if ! curl -s -k -m 5 -o /dev/null http://localhost:8001 && ! curl -s -k -m 5 -o /dev/null https://localhost:8001; then
+    exit 1
+fi
+
+# A new host has no forests to open yet and joins the cluster after the probe
+if [[ "$MARKLOGIC_STARTUP_WAIT_FOR_FORESTS" != "true" ]] || [[ ! -f /var/opt/MarkLogic/Kubernetes/status.txt ]]; then
+    exit 0
+fi
+
+MARKLOGIC_ADMIN_USERNAME="$(< /run/secrets/ml-secrets/username)"
+MARKLOGIC_ADMIN_PASSWORD="$(< /run/secrets/ml-secrets/password)"
+
+HTTP_PROTOCOL="http"
+HTTPS_OPTION=""
+if [[ "$MARKLOGIC_JOIN_TLS_ENABLED" == "true" ]]; then
+    HTTP_PROTOCOL="https"
+    HTTPS_OPTION="-k"
+fi
+
+# The status summary of the forests of this host counts those not open yet
+not_open=$(curl --anyauth --user $MARKLOGIC_ADMIN_USERNAME:$MARKLOGIC_ADMIN_PASSWORD \
+    -m 5 -s -f ${HTTPS_OPTION} \
+    "${HTTP_PROTOCOL}://localhost:8002/manage/v2/forests?view=status&host-id=$(hostname -f)&format=json" \
+    | grep -o '"state-not-open":{[^}]*}' | grep -o '"value":[0-9]*' | cut -d: -f2)
+if [[ -z "$not_open" ]] || [[ $not_open -gt 0 ]]; then
+    echo "${not_open:-unknown} forests are not open yet"
+    exit 1
+fi
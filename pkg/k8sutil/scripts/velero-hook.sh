@@ -0,0 +1,91 @@
+#!/bin/bash
+# Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+# Velero backup hook. "freeze" puts every forest hosted on this pod into
+# flash-backup mode so volume snapshots are consistent, "thaw" restores the
+# updates-allowed setting each forest had before the freeze.
+
+MARKLOGIC_ADMIN_USERNAME="$(< /run/secrets/ml-secrets/username)"
+MARKLOGIC_ADMIN_PASSWORD="$(< /run/secrets/ml-secrets/password)"
+STATE_FILE="/tmp/velero-hook-forests"
+
+log () {
+    local TIMESTAMP=$(date +"%Y-%m-%d %T.%3N")
+    echo "${TIMESTAMP} $@" > /proc/1/fd/1
+}
+
+my_host=$(hostname -f)
+
+HTTP_PROTOCOL="http"
+HTTPS_OPTION=""
+if [[ "$MARKLOGIC_JOIN_TLS_ENABLED" == "true" ]]; then
+    HTTP_PROTOCOL="https"
+    HTTPS_OPTION="-k"
+fi
+MANAGE_URL="${HTTP_PROTOCOL}://localhost:8002/manage/v2"
+
+manage () {
+    curl --anyauth --user $MARKLOGIC_ADMIN_USERNAME:$MARKLOGIC_ADMIN_PASSWORD \
+        -m 30 -s -f ${HTTPS_OPTION} "$@"
+}
+
+set_updates_allowed () {
+    local forest=$1
+    local value=$2
+    manage -o /dev/null -X PUT -H "Content-type: application/json" \
+        -d "{\"updates-allowed\":\"${value}\"}" \
+        "${MANAGE_URL}/forests/${forest}/properties"
+}
+
+freeze () {
+    local response forests
+    if ! response=$(manage "${MANAGE_URL}/forests?host-id=${my_host}&format=json"); then
+        log "ERROR: [velero-hook] Failed to list forests for host ${my_host}"
+        exit 1
+    fi
+    forests=$(echo "${response}" | grep -o '"nameref":"[^"]*"' | cut -d'"' -f4)
+    : > "${STATE_FILE}"
+    for forest in ${forests}; do
+        current=$(manage "${MANAGE_URL}/forests/${forest}/properties?format=json" | grep -o '"updates-allowed":"[^"]*"' | cut -d'"' -f4)
+        echo "${forest} ${current:-all}" >> "${STATE_FILE}"
+        if ! set_updates_allowed "${forest}" "flash-backup"; then
+            log "ERROR: [velero-hook] Failed to set flash-backup on forest ${forest}"
+            thaw
+            exit 1
+        fi
+        log "Info: [velero-hook] Forest ${forest} set to flash-backup"
+    done
+}
+
+thaw () {
+    if [[ ! -f "${STATE_FILE}" ]]; then
+        log "Info: [velero-hook] No frozen forests recorded"
+        return 0
+    fi
+    local failed=0
+    while read -r forest previous; do
+        if set_updates_allowed "${forest}" "${previous}"; then
+            log "Info: [velero-hook] Forest ${forest} restored to ${previous}"
+        else
+            log "ERROR: [velero-hook] Failed to restore forest ${forest} to ${previous}"
+            failed=1
+        fi
+    done < "${STATE_FILE}"
+    if [[ ${failed} -eq 0 ]]; then
+        rm -f "${STATE_FILE}"
+    fi
+    return ${failed}
+}
+
+case "$1" in
+    freeze)
+        freeze
+        ;;
+    thaw)
+        thaw
+        ;;
+    *)
+        echo "usage: $0 freeze|thaw"
+        exit 2
+        ;;
+esac
@@ -52,7 +52,7 @@ func (cc *ClusterContext) ReconcileSecret() result.ReconcileResult {
 		}
 	}
 
-	if hasDynamicGroups(mlc.Spec.MarkLogicGroups) {
+	if hasDynamicGroups(mlc.Spec.MarkLogicGroups) || operatorUserEnabled(mlc) {
 		if dynamicSecretResult := cc.reconcileDynamicCredentialSecret(mlc.ObjectMeta.Name); dynamicSecretResult.Completed() {
 			return dynamicSecretResult
 		}
@@ -139,6 +139,10 @@ func (cc *ClusterContext) generateSecretData() map[string][]byte {
 		secretData["wallet-password"] = []byte(generateRandomAlphaNumeric(10))
 	}
 
+	if spec.Auth != nil && spec.Auth.Realm != nil {
+		secretData["realm"] = []byte(*spec.Auth.Realm)
+	}
+
 	return secretData
 }
 
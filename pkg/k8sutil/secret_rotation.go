@@ -0,0 +1,365 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	fluentBitCredentialsMountPath = "/fluent-bit/credentials/"
+	fluentBitCredentialsFile      = "env.yaml"
+
+	// secretVolumeSyncDelay covers the kubelet sync period and cache TTL
+	// after which an updated Secret is visible in the mounted volumes.
+	secretVolumeSyncDelay        = 90 * time.Second
+	secretRotationRequeueSeconds = 10
+
+	secretReasonTLSRotated            = "TLSSecretRotated"
+	secretReasonLogCredentialsRotated = "LogCredentialsRotated"
+	secretReasonAdminPasswordRotated  = "AdminPasswordRotated"
+	secretReasonAdminRotationFailed   = "AdminPasswordRotationFailed"
+)
+
+// fluentBitReloadCommand triggers a fluent-bit hot reload. It runs in the
+// MarkLogic container, which shares the pod network with fluent-bit and
+// ships curl, unlike the distroless fluent-bit image.
+var fluentBitReloadCommand = []string{"curl", "-s", "-f", "-X", "POST", "http://127.0.0.1:2020/api/v2/reload"}
+
+func fluentBitCredentialsSecretName(groupName string) string {
+	return groupName + "-fluent-bit-credentials"
+}
+
+// appliedAdminSecretName returns the operator-owned copy of the admin
+// credentials that MarkLogic currently accepts.
+func appliedAdminSecretName(clusterName string) string {
+	return clusterName + "-admin-applied"
+}
+
+// ReferencedSecretNames returns the Secrets a group mounts or renders into
+// its pods, so a change to any of them can trigger a reconcile.
+func ReferencedSecretNames(group *marklogicv1.MarklogicGroup) []string {
+	names := []string{}
+	if group.Spec.SecretName != "" {
+		names = append(names, group.Spec.SecretName)
+	}
+	names = append(names, tlsSecretNames(group)...)
+	if lc := group.Spec.LogCollection; lc != nil && lc.Enabled && lc.CredentialsSecretName != "" {
+		names = append(names, lc.CredentialsSecretName)
+	}
+	return names
+}
+
+func tlsSecretNames(group *marklogicv1.MarklogicGroup) []string {
+	tls := group.Spec.Tls
+	if tls == nil || !tls.EnableOnDefaultAppServers {
+		return nil
+	}
+	names := []string{}
+	if tls.CaSecretName != "" {
+		names = append(names, tls.CaSecretName)
+	}
+	return append(names, tls.CertSecretNames...)
+}
+
+// ReconcileSecretRotation applies changes of the Secrets referenced by the
+// group without waiting for a manual restart: the pods are restarted one at a
+// time when a TLS Secret changes, since the certificates are copied into the
+// pod at startup, and fluent-bit reloads its configuration when the log
+// output credentials change.
+func (oc *OperatorContext) ReconcileSecretRotation() result.ReconcileResult {
+	cr := oc.MarklogicGroup
+	rotation := &marklogicv1.SecretRotationStatus{}
+	if cr.Status.SecretRotation != nil {
+		rotation = cr.Status.SecretRotation.DeepCopy()
+	}
+	requeue, err := oc.rotateTLSSecrets(rotation)
+	if err != nil {
+		return result.Error(err)
+	}
+	logRequeue, err := oc.rotateLogCredentials(rotation)
+	if err != nil {
+		return result.Error(err)
+	}
+	if reflect.DeepEqual(*rotation, marklogicv1.SecretRotationStatus{}) {
+		rotation = nil
+	}
+	if !reflect.DeepEqual(cr.Status.SecretRotation, rotation) {
+		patchBase := client.MergeFrom(cr.DeepCopy())
+		cr.Status.SecretRotation = rotation
+		if err := patchStatus(oc.Ctx, oc.Client, cr, patchBase); err != nil {
+			return result.Error(err)
+		}
+	}
+	if requeue > 0 || logRequeue > 0 {
+		if requeue == 0 || (logRequeue > 0 && logRequeue < requeue) {
+			requeue = logRequeue
+		}
+		return result.RequeueSoon(requeue)
+	}
+	return result.Continue()
+}
+
+// rotateTLSSecrets restarts the pods one at a time after the TLS Secrets
+// changed. It returns the seconds after which to check again, if any.
+func (oc *OperatorContext) rotateTLSSecrets(rotation *marklogicv1.SecretRotationStatus) (int, error) {
+	cr := oc.MarklogicGroup
+	names := tlsSecretNames(cr)
+	if len(names) == 0 {
+		rotation.TLSChecksum = ""
+		rotation.TLSRestartPending = nil
+		return 0, nil
+	}
+	checksum, err := secretsChecksum(oc.Ctx, oc.Client, cr.Namespace, names...)
+	if err != nil {
+		return 0, err
+	}
+	if rotation.TLSChecksum == "" {
+		rotation.TLSChecksum = checksum
+		return 0, nil
+	}
+	pods, err := oc.groupPods()
+	if err != nil {
+		return 0, err
+	}
+	if rotation.TLSChecksum != checksum {
+		rotation.TLSChecksum = checksum
+		rotation.TLSRestartPending = podNamesByOrdinalDesc(pods)
+		oc.recordGroupEvent("Normal", secretReasonTLSRotated,
+			fmt.Sprintf("TLS Secrets changed, restarting %d pods to load the new certificates", len(rotation.TLSRestartPending)))
+	}
+	if len(rotation.TLSRestartPending) == 0 {
+		return 0, nil
+	}
+	// Only one pod is restarted at a time.
+	if cr.Spec.Replicas != nil && len(pods) < int(*cr.Spec.Replicas) {
+		return secretRotationRequeueSeconds, nil
+	}
+	for _, pod := range pods {
+		if pod.DeletionTimestamp != nil || !hasPodReadyCondition(&pod) {
+			return secretRotationRequeueSeconds, nil
+		}
+	}
+	next := rotation.TLSRestartPending[0]
+	rotation.TLSRestartPending = rotation.TLSRestartPending[1:]
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: next, Namespace: cr.Namespace}}
+	if err := oc.Client.Delete(oc.Ctx, pod); err != nil && !apierrors.IsNotFound(err) {
+		return 0, err
+	}
+	oc.ReqLogger.Info("Restarted pod for rotated TLS Secrets", "pod", next)
+	if len(rotation.TLSRestartPending) == 0 {
+		rotation.TLSRestartPending = nil
+	}
+	return secretRotationRequeueSeconds, nil
+}
+
+// rotateLogCredentials renders the fluent-bit credentials Secret and reloads
+// fluent-bit in every pod once the kubelet had time to update the mounted
+// Secret. It returns the seconds after which to check again, if any.
+func (oc *OperatorContext) rotateLogCredentials(rotation *marklogicv1.SecretRotationStatus) (int, error) {
+	cr := oc.MarklogicGroup
+	lc := cr.Spec.LogCollection
+	if lc == nil || !lc.Enabled || lc.CredentialsSecretName == "" {
+		rotation.LogCredentialsChecksum = ""
+		rotation.LogCredentialsUpdateTime = nil
+		rotation.LogReloadPending = nil
+		return 0, nil
+	}
+	checksum, err := secretsChecksum(oc.Ctx, oc.Client, cr.Namespace, lc.CredentialsSecretName)
+	if err != nil {
+		return 0, err
+	}
+	if err := oc.reconcileFluentBitCredentialsSecret(lc.CredentialsSecretName); err != nil {
+		return 0, err
+	}
+	now := metav1.Now()
+	if rotation.LogCredentialsChecksum == "" {
+		// The pods started with the current credentials.
+		rotation.LogCredentialsChecksum = checksum
+		rotation.LogCredentialsUpdateTime = &now
+		return 0, nil
+	}
+	if rotation.LogCredentialsChecksum != checksum {
+		pods, err := oc.groupPods()
+		if err != nil {
+			return 0, err
+		}
+		rotation.LogCredentialsChecksum = checksum
+		rotation.LogCredentialsUpdateTime = &now
+		rotation.LogReloadPending = podNamesByOrdinalDesc(pods)
+		oc.recordGroupEvent("Normal", secretReasonLogCredentialsRotated,
+			fmt.Sprintf("log output credentials changed, reloading fluent-bit in %d pods", len(rotation.LogReloadPending)))
+	}
+	if len(rotation.LogReloadPending) == 0 {
+		return 0, nil
+	}
+	if wait := secretVolumeSyncDelay - now.Sub(rotation.LogCredentialsUpdateTime.Time); wait > 0 {
+		return int(wait.Seconds()) + 1, nil
+	}
+	pending := []string{}
+	for _, podName := range rotation.LogReloadPending {
+		if _, err := ExecInPod(oc.Ctx, cr.Namespace, podName, "marklogic-server", fluentBitReloadCommand); err != nil {
+			pod := &corev1.Pod{}
+			if getErr := oc.Client.Get(oc.Ctx, types.NamespacedName{Namespace: cr.Namespace, Name: podName}, pod); apierrors.IsNotFound(getErr) {
+				// A recreated pod mounts the new credentials at startup.
+				continue
+			}
+			oc.ReqLogger.Info("Failed to reload fluent-bit", "pod", podName, "error", err.Error())
+			pending = append(pending, podName)
+		}
+	}
+	if len(pending) == 0 {
+		rotation.LogReloadPending = nil
+		return 0, nil
+	}
+	rotation.LogReloadPending = pending
+	return upgradePollIntervalSeconds, nil
+}
+
+// reconcileFluentBitCredentialsSecret renders the user Secret as a fluent-bit
+// env include, which fluent-bit re-reads on hot reload.
+func (oc *OperatorContext) reconcileFluentBitCredentialsSecret(sourceName string) error {
+	cr := oc.MarklogicGroup
+	source := &corev1.Secret{}
+	if err := oc.Client.Get(oc.Ctx, types.NamespacedName{Namespace: cr.Namespace, Name: sourceName}, source); err != nil {
+		return err
+	}
+	env := map[string]string{}
+	for key, value := range source.Data {
+		env[key] = string(value)
+	}
+	rendered, err := yaml.Marshal(map[string]any{"env": env})
+	if err != nil {
+		return err
+	}
+	data := map[string][]byte{fluentBitCredentialsFile: rendered}
+
+	name := fluentBitCredentialsSecretName(cr.Spec.Name)
+	secret := &corev1.Secret{}
+	err = oc.Client.Get(oc.Ctx, types.NamespacedName{Namespace: cr.Namespace, Name: name}, secret)
+	if apierrors.IsNotFound(err) {
+		objectMeta := generateObjectMeta(name, cr.Namespace, getFluentBitLabels(cr.Spec.Name), map[string]string{})
+		return oc.Client.Create(oc.Ctx, generateSecretDef(objectMeta, marklogicServerAsOwner(cr), data))
+	}
+	if err != nil {
+		return err
+	}
+	if reflect.DeepEqual(secret.Data, data) {
+		return nil
+	}
+	secret.Data = data
+	return oc.Client.Update(oc.Ctx, secret)
+}
+
+func (oc *OperatorContext) groupPods() ([]corev1.Pod, error) {
+	cr := oc.MarklogicGroup
+	list := &corev1.PodList{}
+	if err := oc.Client.List(oc.Ctx, list, client.InNamespace(cr.Namespace), client.MatchingLabels{
+		"app.kubernetes.io/name":     "marklogic",
+		"app.kubernetes.io/instance": cr.Spec.Name,
+	}); err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+func (oc *OperatorContext) recordGroupEvent(eventType, reason, message string) {
+	if oc.Recorder != nil {
+		oc.Recorder.Event(oc.MarklogicGroup, eventType, reason, message)
+	}
+}
+
+// ReconcileAdminCredentialRotation applies a changed password in the admin
+// Secret to the MarkLogic admin user, authenticating with the credentials
+// MarkLogic accepted last. Those are kept in an operator-owned copy of the
+// Secret, which the pods never read.
+func (cc *ClusterContext) ReconcileAdminCredentialRotation() result.ReconcileResult {
+	cr := cc.MarklogicCluster
+	admin, err := cc.getSecret(cc.adminSecretName())
+	if err != nil {
+		return result.Error(err)
+	}
+	applied, err := cc.getSecret(appliedAdminSecretName(cr.Name))
+	if apierrors.IsNotFound(err) {
+		objectMeta := generateObjectMeta(appliedAdminSecretName(cr.Name), cr.Namespace, cc.GetClusterLabels(cr.Name), cc.GetClusterAnnotations())
+		if err := cc.Client.Create(cc.Ctx, generateSecretDef(objectMeta, marklogicClusterAsOwner(cr), admin.DeepCopy().Data)); err != nil {
+			return result.Error(err)
+		}
+		return result.Continue()
+	}
+	if err != nil {
+		return result.Error(err)
+	}
+	if reflect.DeepEqual(admin.Data, applied.Data) {
+		return result.Continue()
+	}
+	username := string(admin.Data["username"])
+	if username != string(applied.Data["username"]) {
+		message := fmt.Sprintf("admin username changed from %s to %s in Secret %s, only password changes are applied", applied.Data["username"], username, admin.Name)
+		cc.recordClusterEvent("Warning", secretReasonAdminRotationFailed, message)
+		return result.Continue()
+	}
+	if string(admin.Data["password"]) != string(applied.Data["password"]) {
+		adminClient, err := cc.newManagementClientFromSecret(applied)
+		if err != nil {
+			return result.Error(err)
+		}
+		if err := adminClient.SetUserPassword(cc.Ctx, username, string(admin.Data["password"])); err != nil {
+			cc.recordClusterEvent("Warning", secretReasonAdminRotationFailed, fmt.Sprintf("failed to apply the admin password: %v", err))
+			return result.RequeueSoon(upgradePollIntervalSeconds)
+		}
+		cc.recordClusterEvent("Normal", secretReasonAdminPasswordRotated, fmt.Sprintf("admin password from Secret %s applied", admin.Name))
+	}
+	applied.Data = admin.DeepCopy().Data
+	if err := cc.Client.Update(cc.Ctx, applied); err != nil {
+		return result.Error(err)
+	}
+	return result.Continue()
+}
+
+// secretsChecksum returns a checksum over the data of the named Secrets.
+// Missing Secrets contribute their name only.
+func secretsChecksum(ctx context.Context, c client.Client, namespace string, names ...string) (string, error) {
+	hash := sha256.New()
+	for _, name := range names {
+		secret := &corev1.Secret{}
+		err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, secret)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return "", err
+		}
+		fmt.Fprintf(hash, "%s\x00", name)
+		keys := make([]string, 0, len(secret.Data))
+		for key := range secret.Data {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fmt.Fprintf(hash, "%s\x00%s\x00", key, secret.Data[key])
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func podNamesByOrdinalDesc(pods []corev1.Pod) []string {
+	names := make([]string, 0, len(pods))
+	for _, pod := range pods {
+		names = append(names, pod.Name)
+	}
+	sort.Slice(names, func(i, j int) bool { return parseOrdinalFromName(names[i]) > parseOrdinalFromName(names[j]) })
+	return names
+}
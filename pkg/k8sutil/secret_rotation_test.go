@@ -0,0 +1,180 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func newSecretRotationTestContext(t *testing.T, group *marklogicv1.MarklogicGroup, objects ...client.Object) *OperatorContext {
+	t.Helper()
+	fakeClient, scheme := newFakeClient(t, append(objects, group)...)
+	return &OperatorContext{
+		Ctx:            context.Background(),
+		Client:         fakeClient,
+		Scheme:         scheme,
+		MarklogicGroup: group,
+		Recorder:       record.NewFakeRecorder(10),
+	}
+}
+
+func newSecretRotationTestGroup() *marklogicv1.MarklogicGroup {
+	replicas := int32(2)
+	return &marklogicv1.MarklogicGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "dnode", Namespace: "default"},
+		Spec: marklogicv1.MarklogicGroupSpec{
+			Name:     "dnode",
+			Replicas: &replicas,
+			Tls:      &marklogicv1.Tls{EnableOnDefaultAppServers: true, CertSecretNames: []string{"dnode-cert"}},
+		},
+	}
+}
+
+func TestReconcileSecretRotationRestartsPodsForTLSSecrets(t *testing.T) {
+	cert := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "dnode-cert", Namespace: "default"},
+		Data:       map[string][]byte{"tls.crt": []byte("old")},
+	}
+	oc := newSecretRotationTestContext(t, newSecretRotationTestGroup(), cert,
+		newUpgradeTestPod("dnode-0", "", true), newUpgradeTestPod("dnode-1", "", true))
+
+	if res := oc.ReconcileSecretRotation(); res.Completed() {
+		t.Fatalf("expected the first reconcile to only record the checksum")
+	}
+	if rotation := oc.MarklogicGroup.Status.SecretRotation; rotation == nil || rotation.TLSChecksum == "" {
+		t.Fatalf("expected the TLS checksum to be recorded, got %+v", rotation)
+	}
+
+	cert.Data["tls.crt"] = []byte("new")
+	if err := oc.Client.Update(oc.Ctx, cert); err != nil {
+		t.Fatalf("failed to update the certificate: %v", err)
+	}
+	if res := oc.ReconcileSecretRotation(); !res.Completed() {
+		t.Fatalf("expected a requeue while pods restart")
+	}
+	if pending := oc.MarklogicGroup.Status.SecretRotation.TLSRestartPending; len(pending) != 1 || pending[0] != "dnode-0" {
+		t.Fatalf("expected dnode-0 to be restarted after dnode-1, got %v", pending)
+	}
+	err := oc.Client.Get(oc.Ctx, types.NamespacedName{Namespace: "default", Name: "dnode-1"}, &corev1.Pod{})
+	if !apierrors.IsNotFound(err) {
+		t.Fatalf("expected the highest ordinal to be restarted first, got %v", err)
+	}
+
+	// dnode-1 has not come back yet, so dnode-0 must wait.
+	if res := oc.ReconcileSecretRotation(); !res.Completed() {
+		t.Fatalf("expected a requeue while dnode-1 is missing")
+	}
+	if err := oc.Client.Get(oc.Ctx, types.NamespacedName{Namespace: "default", Name: "dnode-0"}, &corev1.Pod{}); err != nil {
+		t.Fatalf("expected dnode-0 to keep running, got %v", err)
+	}
+}
+
+func TestReconcileSecretRotationReloadsFluentBitCredentials(t *testing.T) {
+	group := newSecretRotationTestGroup()
+	group.Spec.Tls = nil
+	group.Spec.LogCollection = &marklogicv1.LogCollection{Enabled: true, CredentialsSecretName: "log-output"}
+	source := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "log-output", Namespace: "default"},
+		Data:       map[string][]byte{"ES_PASSWORD": []byte("old")},
+	}
+	oc := newSecretRotationTestContext(t, group, source, newUpgradeTestPod("dnode-0", "", true))
+	var reloaded []string
+	original := ExecInPod
+	t.Cleanup(func() { ExecInPod = original })
+	ExecInPod = func(ctx context.Context, namespace, podName, container string, command []string) (string, error) {
+		reloaded = append(reloaded, podName)
+		return "", nil
+	}
+
+	if res := oc.ReconcileSecretRotation(); res.Completed() {
+		t.Fatalf("expected the first reconcile to continue")
+	}
+	rendered := &corev1.Secret{}
+	if err := oc.Client.Get(oc.Ctx, types.NamespacedName{Namespace: "default", Name: "dnode-fluent-bit-credentials"}, rendered); err != nil {
+		t.Fatalf("expected the fluent-bit credentials Secret, got %v", err)
+	}
+	if !strings.Contains(string(rendered.Data[fluentBitCredentialsFile]), "ES_PASSWORD: old") {
+		t.Fatalf("unexpected rendered credentials %q", rendered.Data[fluentBitCredentialsFile])
+	}
+
+	source.Data["ES_PASSWORD"] = []byte("new")
+	if err := oc.Client.Update(oc.Ctx, source); err != nil {
+		t.Fatalf("failed to update the credentials: %v", err)
+	}
+	if res := oc.ReconcileSecretRotation(); !res.Completed() || len(reloaded) != 0 {
+		t.Fatalf("expected the reload to wait for the kubelet sync, reloaded %v", reloaded)
+	}
+
+	rotation := oc.MarklogicGroup.Status.SecretRotation
+	past := metav1.NewTime(time.Now().Add(-2 * secretVolumeSyncDelay))
+	rotation.LogCredentialsUpdateTime = &past
+	if res := oc.ReconcileSecretRotation(); res.Completed() {
+		t.Fatalf("expected the reload to complete")
+	}
+	if len(reloaded) != 1 || reloaded[0] != "dnode-0" {
+		t.Fatalf("expected fluent-bit to be reloaded in dnode-0, got %v", reloaded)
+	}
+	if oc.MarklogicGroup.Status.SecretRotation.LogReloadPending != nil {
+		t.Fatalf("expected no pending reloads, got %v", oc.MarklogicGroup.Status.SecretRotation.LogReloadPending)
+	}
+}
+
+func TestReconcileAdminCredentialRotationAppliesPassword(t *testing.T) {
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			ClusterDomain:   "cluster.local",
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{{Name: "dnode", IsBootstrap: true}},
+		},
+	}
+	cc := newUpgradeTestContext(t, cr)
+	var authPasswords, newPasswords []string
+	original := NewDynamicManagementClient
+	NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+		authPasswords = append(authPasswords, opts.Password)
+		return &stubDynamicManagementClient{setPasswordFn: func(username, password string) error {
+			newPasswords = append(newPasswords, password)
+			return nil
+		}}
+	}
+	t.Cleanup(func() { NewDynamicManagementClient = original })
+
+	if res := cc.ReconcileAdminCredentialRotation(); res.Completed() {
+		t.Fatalf("expected the applied copy to be created")
+	}
+	applied, err := cc.getSecret(appliedAdminSecretName(cr.Name))
+	if err != nil || string(applied.Data["password"]) != "admin" {
+		t.Fatalf("expected the applied admin Secret, got %v", err)
+	}
+
+	admin, err := cc.getSecret(cc.adminSecretName())
+	if err != nil {
+		t.Fatalf("failed to get the admin Secret: %v", err)
+	}
+	admin.Data["password"] = []byte("rotated")
+	if err := cc.Client.Update(cc.Ctx, admin); err != nil {
+		t.Fatalf("failed to update the admin Secret: %v", err)
+	}
+	if res := cc.ReconcileAdminCredentialRotation(); res.Completed() {
+		t.Fatalf("expected the rotation to complete")
+	}
+	if len(authPasswords) != 1 || authPasswords[0] != "admin" || len(newPasswords) != 1 || newPasswords[0] != "rotated" {
+		t.Fatalf("expected the old password to set the new one, got %v %v", authPasswords, newPasswords)
+	}
+	applied, err = cc.getSecret(appliedAdminSecretName(cr.Name))
+	if err != nil || string(applied.Data["password"]) != "rotated" {
+		t.Fatalf("expected the applied copy to hold the new password, got %v", err)
+	}
+}
@@ -0,0 +1,29 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"testing"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGenerateSecretDataStoresRealmAndWalletPassword(t *testing.T) {
+	realm, wallet := "corp", "wallet-secret"
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+		Spec:       marklogicv1.MarklogicClusterSpec{Auth: &marklogicv1.AdminAuth{Realm: &realm, WalletPassword: &wallet}},
+	}
+	cc := &ClusterContext{MarklogicCluster: cr}
+	data := cc.generateSecretData()
+	if string(data["realm"]) != "corp" || string(data["wallet-password"]) != "wallet-secret" {
+		t.Fatalf("unexpected secret data %v", data)
+	}
+
+	cr.Spec.Auth = nil
+	data = cc.generateSecretData()
+	if _, ok := data["realm"]; ok || len(data["wallet-password"]) == 0 {
+		t.Fatalf("expected a generated wallet password and the default realm, got %v", data)
+	}
+}
@@ -77,7 +77,7 @@ func TestCreateHAProxyDeploymentDefAppliesSecurityContexts(t *testing.T) {
 		},
 	}
 
-	deployment := cc.createHAProxyDeploymentDef(metav1.ObjectMeta{Name: "marklogic-haproxy", Namespace: "default"})
+	deployment := cc.createHAProxyDeploymentDef(metav1.ObjectMeta{Name: "ml-haproxy", Namespace: "default"})
 	if deployment.Spec.Template.Spec.SecurityContext == nil {
 		t.Fatal("expected HAProxy pod securityContext to be set")
 	}
@@ -94,3 +94,59 @@ func TestCreateHAProxyDeploymentDefAppliesSecurityContexts(t *testing.T) {
 		t.Fatalf("expected HAProxy runAsUser %d, got %+v", runAsUser, deployment.Spec.Template.Spec.Containers[0].SecurityContext.RunAsUser)
 	}
 }
+
+func TestReadOnlyRootFilesystemStatefulSet(t *testing.T) {
+	t.Parallel()
+
+	group := &marklogicv1.MarklogicGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "dnode", Namespace: "default"},
+		Spec: marklogicv1.MarklogicGroupSpec{
+			Name:                     "dnode",
+			Image:                    "progressofficial/marklogic-db:12.0.3-ubi9-rootless-2.2.6",
+			ContainerSecurityContext: &corev1.SecurityContext{RunAsUser: int64Ptr(1000), RunAsNonRoot: boolPtr(true), AllowPrivilegeEscalation: boolPtr(false)},
+			HugePages:                &marklogicv1.HugePages{},
+			LogCollection:            &marklogicv1.LogCollection{Enabled: true, SecurityContext: &corev1.SecurityContext{ReadOnlyRootFilesystem: boolPtr(false)}},
+			AdditionalVolumeMounts:   &[]corev1.VolumeMount{{Name: "scratch", MountPath: "/scratch"}},
+			ReadOnlyRootFilesystem: &marklogicv1.ReadOnlyRootFilesystem{
+				Enabled:       true,
+				WritablePaths: []string{"/tmp", "/var/tmp", "/scratch"},
+			},
+		},
+	}
+	sts := generateStatefulSetsDef(metav1.ObjectMeta{Name: "dnode", Namespace: "default"}, generateStatefulSetsParams(group),
+		metav1.OwnerReference{}, generateContainerParams(group))
+	spec := sts.Spec.Template.Spec
+
+	volumes := map[string]corev1.Volume{}
+	for _, volume := range spec.Volumes {
+		volumes[volume.Name] = volume
+	}
+	for _, container := range spec.Containers {
+		if sc := container.SecurityContext; sc == nil || sc.ReadOnlyRootFilesystem == nil || !*sc.ReadOnlyRootFilesystem {
+			t.Fatalf("expected %s to run with a read-only root filesystem, got %+v", container.Name, sc)
+		}
+		mounts := map[string]string{}
+		for _, mount := range container.VolumeMounts {
+			mounts[mount.MountPath] = mount.Name
+		}
+		if volume, ok := volumes[mounts["/tmp"]]; !ok || volume.EmptyDir == nil {
+			t.Fatalf("expected an emptyDir volume on /tmp of %s, got %v", container.Name, mounts)
+		}
+	}
+	mounts := map[string]string{}
+	for _, mount := range spec.Containers[0].VolumeMounts {
+		mounts[mount.MountPath] = mount.Name
+	}
+	if mounts["/var/tmp"] == "" || mounts["/scratch"] != "scratch" {
+		t.Fatalf("expected /var/tmp on an emptyDir and /scratch left to its volume, got %v", mounts)
+	}
+	var pidFile string
+	for _, env := range spec.Containers[0].Env {
+		if env.Name == "MARKLOGIC_PID_FILE" {
+			pidFile = env.Value
+		}
+	}
+	if pidFile != readOnlyPIDFile {
+		t.Fatalf("expected the PID file in %s, got %q", readOnlyPIDFile, pidFile)
+	}
+}
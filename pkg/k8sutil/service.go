@@ -22,6 +22,7 @@ type serviceParameters struct {
 	Ports       []corev1.ServicePort
 	Type        corev1.ServiceType
 	Annotations map[string]string
+	ExposeAdmin bool
 }
 
 func generateServiceParams(cr *marklogicv1.MarklogicGroup) serviceParameters {
@@ -31,6 +32,7 @@ func generateServiceParams(cr *marklogicv1.MarklogicGroup) serviceParameters {
 		Type:        cr.Spec.Service.Type,
 		Ports:       cr.Spec.Service.AdditionalPorts,
 		Annotations: cr.Spec.Service.Annotations,
+		ExposeAdmin: cr.Spec.ExposeAdmin,
 	}
 }
 
@@ -82,6 +84,9 @@ func generateServiceDef(serviceMeta metav1.ObjectMeta, ownerRef metav1.OwnerRefe
 	}
 	if strings.HasSuffix(serviceMeta.Name, "-cluster") {
 		svcSpec.Type = params.Type
+		if !params.ExposeAdmin && params.Type != "" && params.Type != corev1.ServiceTypeClusterIP {
+			svcSpec.Ports = withoutAdminPorts(svcSpec.Ports)
+		}
 	} else {
 		svcSpec.ClusterIP = "None"
 		svcSpec.PublishNotReadyAddresses = true
@@ -98,6 +103,19 @@ func generateServiceDef(serviceMeta metav1.ObjectMeta, ownerRef metav1.OwnerRefe
 	return service
 }
 
+// withoutAdminPorts drops the App-Services, Admin and Manage ports so that they
+// are not published on a NodePort or LoadBalancer service.
+func withoutAdminPorts(ports []corev1.ServicePort) []corev1.ServicePort {
+	filtered := make([]corev1.ServicePort, 0, len(ports))
+	for _, port := range ports {
+		if port.Port >= 8000 && port.Port <= 8002 {
+			continue
+		}
+		filtered = append(filtered, port)
+	}
+	return filtered
+}
+
 func (oc *OperatorContext) generateService(svcName string, cr *marklogicv1.MarklogicGroup) *corev1.Service {
 	labels := oc.GetOperatorLabels(cr.Spec.Name)
 	groupLabels := cr.Spec.Labels
@@ -106,7 +124,7 @@ func (oc *OperatorContext) generateService(svcName string, cr *marklogicv1.Markl
 	}
 	labels["app.kubernetes.io/component"] = getMarkLogicComponentLabel(cr.Spec.IsDynamic)
 	svcParams := generateServiceParams(cr)
-	svcObjectMeta := generateObjectMeta(svcName, cr.Namespace, labels, svcParams.Annotations)
+	svcObjectMeta := generateObjectMeta(svcName, cr.Namespace, labels, mergeMetadata(cr.Spec.AdditionalAnnotations, svcParams.Annotations))
 	service := generateServiceDef(svcObjectMeta, marklogicServerAsOwner(cr), svcParams)
 	return service
 }
@@ -9,6 +9,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 func (cc *ClusterContext) ReconcileServiceAccount() result.ReconcileResult {
@@ -43,21 +44,53 @@ func (cc *ClusterContext) ReconcileServiceAccount() result.ReconcileResult {
 		}
 	} else {
 		logger.Info("ServiceAccount already exists")
+		if result := cc.reconcileServiceAccountAnnotations(sa); result.Completed() {
+			return result
+		}
 	}
 
 	return result.Continue()
 }
 
+// reconcileServiceAccountAnnotations adds workload identity annotations required by
+// the backup storage configuration to an existing service account.
+func (cc *ClusterContext) reconcileServiceAccountAnnotations(sa *corev1.ServiceAccount) result.ReconcileResult {
+	desired := backupServiceAccountAnnotations(cc.MarklogicCluster)
+	patchBase := client.MergeFrom(sa.DeepCopy())
+	changed := false
+	for key, value := range desired {
+		if sa.Annotations[key] == value {
+			continue
+		}
+		if sa.Annotations == nil {
+			sa.Annotations = map[string]string{}
+		}
+		sa.Annotations[key] = value
+		changed = true
+	}
+	if !changed {
+		return result.Continue()
+	}
+	if err := cc.Client.Patch(cc.Ctx, sa, patchBase); err != nil {
+		cc.ReqLogger.Error(err, "Failed to update ServiceAccount annotations", "name", sa.Name)
+		return result.Error(err)
+	}
+	return result.Continue()
+}
+
 func generateServiceAccountDef(namespacedName types.NamespacedName, cr *marklogicv1.MarklogicCluster) *corev1.ServiceAccount {
 	serviceAccount := &corev1.ServiceAccount{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      namespacedName.Name,
-			Namespace: namespacedName.Namespace,
+			Name:        namespacedName.Name,
+			Namespace:   namespacedName.Namespace,
+			Annotations: backupServiceAccountAnnotations(cr),
 		},
 	}
 
 	// Add owner reference for garbage collection
 	if cr != nil {
+		serviceAccount.Labels = cr.Spec.AdditionalLabels
+		serviceAccount.Annotations = mergeMetadata(cr.Spec.AdditionalAnnotations, serviceAccount.Annotations)
 		ownerRef := marklogicClusterAsOwner(cr)
 		AddOwnerRefToObject(serviceAccount, ownerRef)
 	}
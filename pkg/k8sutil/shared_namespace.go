@@ -0,0 +1,171 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"fmt"
+	"reflect"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// ClusterNameLabel names the MarklogicCluster that owns a generated
+	// resource, so resources of clusters sharing a namespace can be told apart.
+	ClusterNameLabel = "marklogic.progress.com/cluster"
+
+	// legacyHAProxyName and legacyFluentBitConfigMapName are the fixed names
+	// used before resources were prefixed, which collide when more than one
+	// cluster shares a namespace.
+	legacyHAProxyName            = "marklogic-haproxy"
+	legacyFluentBitConfigMapName = "fluent-bit"
+
+	groupNameConflictReason = "GroupNameConflict"
+)
+
+// haproxyName is the name of the HAProxy ConfigMap, Deployment and Service
+// of a cluster.
+func haproxyName(cr *marklogicv1.MarklogicCluster) string {
+	return clusterFullname(cr) + "-haproxy"
+}
+
+// fluentBitConfigMapName is the name of the fluent-bit ConfigMap of a group.
+func fluentBitConfigMapName(groupName string) string {
+	return groupName + "-fluent-bit"
+}
+
+// withClusterNameLabel returns a copy of labels with the ClusterNameLabel.
+func withClusterNameLabel(labels map[string]string, clusterName string) map[string]string {
+	merged := make(map[string]string, len(labels)+1)
+	for k, v := range labels {
+		merged[k] = v
+	}
+	if clusterName != "" {
+		merged[ClusterNameLabel] = clusterName
+	}
+	return merged
+}
+
+// ownedBy reports whether obj has an owner reference to uid.
+func ownedBy(obj metav1.Object, uid types.UID) bool {
+	for _, ref := range obj.GetOwnerReferences() {
+		if ref.UID == uid {
+			return true
+		}
+	}
+	return false
+}
+
+// GroupOwnedByOtherCluster returns the name of the MarklogicCluster that owns
+// an existing MarklogicGroup, when it is not the cluster being reconciled.
+func GroupOwnedByOtherCluster(cr *marklogicv1.MarklogicCluster, group *marklogicv1.MarklogicGroup) (string, bool) {
+	for _, ref := range group.GetOwnerReferences() {
+		if ref.Kind == "MarklogicCluster" && ref.UID != cr.UID {
+			return ref.Name, true
+		}
+	}
+	return "", false
+}
+
+// reportGroupNameConflict records that a group of the cluster is named like a
+// MarklogicGroup of another cluster in the namespace, which is left alone.
+func (cc *ClusterContext) reportGroupNameConflict(name, owner string) {
+	msg := fmt.Sprintf("MarkLogicGroup %s already belongs to MarklogicCluster %s in this namespace, rename the group", name, owner)
+	cc.ReqLogger.Info(msg)
+	cc.recordClusterEvent(corev1.EventTypeWarning, groupNameConflictReason, msg)
+}
+
+// reconcileLegacyHAProxyService keeps the marklogic-haproxy Service of a
+// cluster created before HAProxy resources were prefixed in sync with the
+// prefixed Service, so clients using the old DNS name keep working. It is
+// only removed by deleting it, or with the cluster.
+func (cc *ClusterContext) reconcileLegacyHAProxyService(serviceDef *corev1.Service) error {
+	cr := cc.MarklogicCluster
+	legacy := &corev1.Service{}
+	if err := cc.Client.Get(cc.Ctx, types.NamespacedName{Name: legacyHAProxyName, Namespace: cr.Namespace}, legacy); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if !ownedBy(legacy, cr.UID) {
+		return nil
+	}
+	if reflect.DeepEqual(legacy.Spec.Selector, serviceDef.Spec.Selector) &&
+		reflect.DeepEqual(legacy.Spec.Ports, serviceDef.Spec.Ports) &&
+		reflect.DeepEqual(legacy.Labels, serviceDef.Labels) {
+		return nil
+	}
+	legacy.Spec.Selector = serviceDef.Spec.Selector
+	legacy.Spec.Ports = serviceDef.Spec.Ports
+	legacy.Labels = serviceDef.Labels
+	if err := cc.Client.Update(cc.Ctx, legacy); err != nil {
+		cc.ReqLogger.Error(err, "Error updating legacy HAProxy service")
+		return err
+	}
+	return nil
+}
+
+// deleteLegacyHAProxy removes the marklogic-haproxy Deployment and ConfigMap
+// of the cluster once the prefixed Deployment is ready to take over.
+func (cc *ClusterContext) deleteLegacyHAProxy(deployment *appsv1.Deployment) error {
+	cr := cc.MarklogicCluster
+	key := types.NamespacedName{Name: legacyHAProxyName, Namespace: cr.Namespace}
+	legacyDeployment := &appsv1.Deployment{}
+	if err := cc.Client.Get(cc.Ctx, key, legacyDeployment); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+	} else if ownedBy(legacyDeployment, cr.UID) {
+		replicas := int32(1)
+		if deployment.Spec.Replicas != nil {
+			replicas = *deployment.Spec.Replicas
+		}
+		if deployment.Status.ReadyReplicas < replicas {
+			return nil
+		}
+		cc.ReqLogger.Info("Deleting legacy HAProxy Deployment", "deployment", legacyHAProxyName)
+		if err := cc.Client.Delete(cc.Ctx, legacyDeployment); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+	legacyConfigMap := &corev1.ConfigMap{}
+	if err := cc.Client.Get(cc.Ctx, key, legacyConfigMap); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if !ownedBy(legacyConfigMap, cr.UID) {
+		return nil
+	}
+	cc.ReqLogger.Info("Deleting legacy HAProxy ConfigMap", "configmap", legacyHAProxyName)
+	return client.IgnoreNotFound(cc.Client.Delete(cc.Ctx, legacyConfigMap))
+}
+
+// deleteLegacyFluentBitConfigMap removes the fluent-bit ConfigMap created by
+// the group before it was prefixed, once no pod in the namespace mounts it.
+// Pods of other groups may still mount it until they are restarted.
+func (oc *OperatorContext) deleteLegacyFluentBitConfigMap() error {
+	cr := oc.MarklogicGroup
+	legacy := &corev1.ConfigMap{}
+	if err := oc.Client.Get(oc.Ctx, types.NamespacedName{Name: legacyFluentBitConfigMapName, Namespace: cr.Namespace}, legacy); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if !ownedBy(legacy, cr.UID) {
+		return nil
+	}
+	pods := &corev1.PodList{}
+	if err := oc.Client.List(oc.Ctx, pods, client.InNamespace(cr.Namespace)); err != nil {
+		return err
+	}
+	for _, pod := range pods.Items {
+		for _, volume := range pod.Spec.Volumes {
+			if volume.ConfigMap != nil && volume.ConfigMap.Name == legacyFluentBitConfigMapName {
+				return nil
+			}
+		}
+	}
+	oc.ReqLogger.Info("Deleting legacy Fluent Bit ConfigMap", "configmap", legacyFluentBitConfigMapName)
+	return client.IgnoreNotFound(oc.Client.Delete(oc.Ctx, legacy))
+}
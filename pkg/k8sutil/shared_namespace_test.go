@@ -0,0 +1,178 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"strings"
+	"testing"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func newSharedNamespaceCluster(name string) *marklogicv1.MarklogicCluster {
+	return &marklogicv1.MarklogicCluster{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "marklogic.progress.com/v1", Kind: "MarklogicCluster"},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", UID: types.UID("uid-" + name)},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			Image: upgradeTestNewImage,
+			HAProxy: &marklogicv1.HAProxy{
+				Enabled:          true,
+				FrontendPort:     8080,
+				ReplicaCount:     2,
+				PathBasedRouting: boolPtr(true),
+				AppServers:       []marklogicv1.AppServers{{Name: "app-service", Port: 8000, Path: "/console"}},
+			},
+		},
+	}
+}
+
+func sharedNamespaceContext(cc *ClusterContext, cr *marklogicv1.MarklogicCluster) *ClusterContext {
+	other := *cc
+	other.MarklogicCluster = cr
+	other.Request = &reconcile.Request{NamespacedName: types.NamespacedName{Name: cr.Name, Namespace: cr.Namespace}}
+	return &other
+}
+
+func TestHAProxyOfClustersSharingANamespace(t *testing.T) {
+	t.Parallel()
+
+	first, second := newSharedNamespaceCluster("ml-a"), newSharedNamespaceCluster("ml-b")
+	cc := newUpgradeTestContext(t, first, second)
+	for _, cr := range []*marklogicv1.MarklogicCluster{first, second} {
+		if res := sharedNamespaceContext(cc, cr).ReconcileHAProxy(); res.Completed() {
+			t.Fatalf("expected the HAProxy reconcile of %s to continue", cr.Name)
+		}
+	}
+	for _, cr := range []*marklogicv1.MarklogicCluster{first, second} {
+		key := types.NamespacedName{Name: cr.Name + "-haproxy", Namespace: "default"}
+		for _, obj := range []client.Object{&corev1.ConfigMap{}, &corev1.Service{}, &appsv1.Deployment{}} {
+			if err := cc.Client.Get(cc.Ctx, key, obj); err != nil {
+				t.Fatalf("expected %T %s: %v", obj, key.Name, err)
+			}
+			if obj.GetLabels()[ClusterNameLabel] != cr.Name || !ownedBy(obj, cr.UID) {
+				t.Fatalf("expected %T %s to belong to %s, got labels %v", obj, key.Name, cr.Name, obj.GetLabels())
+			}
+		}
+	}
+	if err := cc.Client.Get(cc.Ctx, types.NamespacedName{Name: legacyHAProxyName, Namespace: "default"}, &corev1.Service{}); !apierrors.IsNotFound(err) {
+		t.Fatalf("expected no %s Service for new clusters, got %v", legacyHAProxyName, err)
+	}
+}
+
+func TestLegacyHAProxyResourcesAreMigrated(t *testing.T) {
+	t.Parallel()
+
+	cr := newSharedNamespaceCluster("ml")
+	legacyMeta := metav1.ObjectMeta{
+		Name:            legacyHAProxyName,
+		Namespace:       "default",
+		Labels:          getHAProxySelectorLabels("ml"),
+		OwnerReferences: []metav1.OwnerReference{marklogicClusterAsOwner(cr)},
+	}
+	legacyService := &corev1.Service{ObjectMeta: *legacyMeta.DeepCopy(), Spec: corev1.ServiceSpec{Selector: map[string]string{"stale": "selector"}}}
+	cc := newUpgradeTestContext(t, cr,
+		&corev1.ConfigMap{ObjectMeta: *legacyMeta.DeepCopy()},
+		&appsv1.Deployment{ObjectMeta: *legacyMeta.DeepCopy()},
+		legacyService,
+	)
+	cc = sharedNamespaceContext(cc, cr)
+	legacyKey := types.NamespacedName{Name: legacyHAProxyName, Namespace: "default"}
+
+	for i := 0; i < 2; i++ {
+		if res := cc.ReconcileHAProxy(); res.Completed() {
+			t.Fatalf("expected the HAProxy reconcile to continue")
+		}
+	}
+	if err := cc.Client.Get(cc.Ctx, legacyKey, &appsv1.Deployment{}); err != nil {
+		t.Fatalf("expected the legacy Deployment to stay until the new one is ready: %v", err)
+	}
+
+	deployment := &appsv1.Deployment{}
+	if err := cc.Client.Get(cc.Ctx, types.NamespacedName{Name: "ml-haproxy", Namespace: "default"}, deployment); err != nil {
+		t.Fatalf("expected the prefixed Deployment: %v", err)
+	}
+	deployment.Status.ReadyReplicas = *deployment.Spec.Replicas
+	if err := cc.Client.Status().Update(cc.Ctx, deployment); err != nil {
+		t.Fatalf("failed to mark the Deployment ready: %v", err)
+	}
+	if res := cc.ReconcileHAProxy(); res.Completed() {
+		t.Fatalf("expected the HAProxy reconcile to continue")
+	}
+	for _, obj := range []client.Object{&appsv1.Deployment{}, &corev1.ConfigMap{}} {
+		if err := cc.Client.Get(cc.Ctx, legacyKey, obj); !apierrors.IsNotFound(err) {
+			t.Fatalf("expected the legacy %T to be deleted, got %v", obj, err)
+		}
+	}
+	service := &corev1.Service{}
+	if err := cc.Client.Get(cc.Ctx, legacyKey, service); err != nil {
+		t.Fatalf("expected the legacy Service to be kept for its DNS name: %v", err)
+	}
+	if service.Spec.Selector["app.kubernetes.io/component"] != "haproxy" || len(service.Spec.Ports) == 0 {
+		t.Fatalf("expected the legacy Service to follow the prefixed one, got %+v", service.Spec)
+	}
+}
+
+func TestLegacyHAProxyOfAnotherClusterIsLeftAlone(t *testing.T) {
+	t.Parallel()
+
+	cr := newSharedNamespaceCluster("ml")
+	owner := marklogicClusterAsOwner(newSharedNamespaceCluster("other"))
+	legacyMeta := metav1.ObjectMeta{Name: legacyHAProxyName, Namespace: "default", OwnerReferences: []metav1.OwnerReference{owner}}
+	cc := sharedNamespaceContext(newUpgradeTestContext(t, cr,
+		&corev1.ConfigMap{ObjectMeta: *legacyMeta.DeepCopy()},
+		&appsv1.Deployment{ObjectMeta: *legacyMeta.DeepCopy()},
+	), cr)
+
+	deployment := &appsv1.Deployment{Status: appsv1.DeploymentStatus{ReadyReplicas: 1}}
+	if err := cc.deleteLegacyHAProxy(deployment); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	legacyKey := types.NamespacedName{Name: legacyHAProxyName, Namespace: "default"}
+	for _, obj := range []client.Object{&appsv1.Deployment{}, &corev1.ConfigMap{}} {
+		if err := cc.Client.Get(cc.Ctx, legacyKey, obj); err != nil {
+			t.Fatalf("expected the %T of the other cluster to be kept: %v", obj, err)
+		}
+	}
+}
+
+func TestGroupOfAnotherClusterIsNotTakenOver(t *testing.T) {
+	t.Parallel()
+
+	replicas := int32(1)
+	cr := newSharedNamespaceCluster("ml")
+	cr.Spec.HAProxy = nil
+	cr.Spec.MarkLogicGroups = []*marklogicv1.MarklogicGroups{{Name: "node", Replicas: &replicas, IsBootstrap: true}}
+	otherOwner := marklogicClusterAsOwner(newSharedNamespaceCluster("other"))
+	group := &marklogicv1.MarklogicGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "node", Namespace: "default", OwnerReferences: []metav1.OwnerReference{otherOwner}},
+		Spec:       marklogicv1.MarklogicGroupSpec{Image: upgradeTestOldImage},
+	}
+	cc := sharedNamespaceContext(newUpgradeTestContext(t, cr, group), cr)
+
+	if _, err := cc.ReconsileMarklogicCluster(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	current := &marklogicv1.MarklogicGroup{}
+	if err := cc.Client.Get(cc.Ctx, types.NamespacedName{Name: "node", Namespace: "default"}, current); err != nil {
+		t.Fatalf("failed to get the group: %v", err)
+	}
+	if current.Spec.Image != upgradeTestOldImage || ownedBy(current, cr.UID) {
+		t.Fatalf("expected the group of the other cluster to be left alone, got %+v", current)
+	}
+	select {
+	case event := <-cc.Recorder.(*record.FakeRecorder).Events:
+		if !strings.Contains(event, groupNameConflictReason) || !strings.Contains(event, "other") {
+			t.Fatalf("unexpected event %q", event)
+		}
+	default:
+		t.Fatalf("expected a %s event", groupNameConflictReason)
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"maps"
+	"strconv"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// SpecHashAnnotation identifies what the operator generated for a child
+	// resource, such as the StatefulSet of a group, when it last wrote it.
+	SpecHashAnnotation = "marklogic.progress.com/spec-hash"
+	// ObservedGenerationAnnotation is the generation of a child resource
+	// once the operator wrote it. A different generation means the spec of
+	// the child was changed since.
+	ObservedGenerationAnnotation = "marklogic.progress.com/observed-generation"
+)
+
+// specHash identifies the spec and metadata the operator generates for a
+// child resource.
+func specHash(spec any, meta metav1.ObjectMeta) string {
+	data, _ := json.Marshal(struct {
+		Spec        any               `json:"spec"`
+		Labels      map[string]string `json:"labels,omitempty"`
+		Annotations map[string]string `json:"annotations,omitempty"`
+	}{spec, meta.Labels, withoutSpecTracking(meta.Annotations)})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// specUpToDate reports whether current was written by the operator from the
+// spec of hash and was not changed since, so comparing it with what the
+// operator generates and updating it can be skipped.
+func specUpToDate(current metav1.Object, hash string) bool {
+	annotations := current.GetAnnotations()
+	return annotations[SpecHashAnnotation] == hash &&
+		annotations[ObservedGenerationAnnotation] == strconv.FormatInt(current.GetGeneration(), 10)
+}
+
+// setSpecHash sets the spec hash of desired, replacing its annotations
+// with a copy, as they can be shared with other generated resources, such as
+// the pod template of a StatefulSet. The observed generation is only known
+// once the resource is written, see observeGeneration.
+func setSpecHash(desired metav1.Object, hash string) {
+	annotations := maps.Clone(desired.GetAnnotations())
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[SpecHashAnnotation] = hash
+	delete(annotations, ObservedGenerationAnnotation)
+	desired.SetAnnotations(annotations)
+}
+
+// observeGeneration records the generation of current, which is up to date
+// with the spec of hash, so later reconciles skip it until either changes.
+func observeGeneration(ctx context.Context, c client.Client, current client.Object, hash string) error {
+	if specUpToDate(current, hash) {
+		return nil
+	}
+	patchBase := client.MergeFrom(current.DeepCopyObject().(client.Object))
+	annotations := maps.Clone(current.GetAnnotations())
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[SpecHashAnnotation] = hash
+	annotations[ObservedGenerationAnnotation] = strconv.FormatInt(current.GetGeneration(), 10)
+	current.SetAnnotations(annotations)
+	return c.Patch(ctx, current, patchBase)
+}
+
+// withoutSpecTracking returns annotations without the spec hash and the
+// observed generation, which describe the resource they are on and are not
+// passed on to the resources generated from it.
+func withoutSpecTracking(annotations map[string]string) map[string]string {
+	if _, ok := annotations[SpecHashAnnotation]; !ok {
+		if _, ok := annotations[ObservedGenerationAnnotation]; !ok {
+			return annotations
+		}
+	}
+	filtered := maps.Clone(annotations)
+	delete(filtered, SpecHashAnnotation)
+	delete(filtered, ObservedGenerationAnnotation)
+	return filtered
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"testing"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestSpecHashTracksTheGenerationOfChildren(t *testing.T) {
+	cr := &marklogicv1.MarklogicCluster{ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"}}
+	annotations := map[string]string{"example.com/team": "orders"}
+	sts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "dnode", Namespace: "default", Annotations: annotations},
+		Spec: appsv1.StatefulSetSpec{
+			Template: corev1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{Annotations: annotations}},
+		},
+	}
+	hash := specHash(sts.Spec, sts.ObjectMeta)
+	setSpecHash(sts, hash)
+	if _, ok := sts.Spec.Template.Annotations[SpecHashAnnotation]; ok {
+		t.Fatalf("expected the pod template annotations to be left alone")
+	}
+	if specHash(sts.Spec, sts.ObjectMeta) != hash {
+		t.Fatalf("expected the spec hash to ignore its own annotation")
+	}
+
+	cc := newUpgradeTestContext(t, cr, sts)
+	current := &appsv1.StatefulSet{}
+	if err := cc.Client.Get(cc.Ctx, client.ObjectKeyFromObject(sts), current); err != nil {
+		t.Fatalf("failed to get StatefulSet: %v", err)
+	}
+	if specUpToDate(current, hash) {
+		t.Fatalf("expected a StatefulSet without an observed generation to be compared")
+	}
+	if err := observeGeneration(cc.Ctx, cc.Client, current, hash); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := cc.Client.Get(cc.Ctx, client.ObjectKeyFromObject(sts), current); err != nil {
+		t.Fatalf("failed to get StatefulSet: %v", err)
+	}
+	if !specUpToDate(current, hash) {
+		t.Fatalf("expected the StatefulSet to be up to date, got %v", current.Annotations)
+	}
+	if specUpToDate(current, specHash(appsv1.StatefulSetSpec{ServiceName: "dnode"}, sts.ObjectMeta)) {
+		t.Fatalf("expected a different spec not to be up to date")
+	}
+	current.Generation++
+	if specUpToDate(current, hash) {
+		t.Fatalf("expected a changed StatefulSet not to be up to date")
+	}
+}
@@ -5,7 +5,10 @@ package k8sutil
 import (
 	"context"
 	"fmt"
+	"math"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/cisco-open/k8s-objectmatcher/patch"
 	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
@@ -20,14 +23,26 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
+const (
+	defaultDrainDelay     = 5 * time.Second
+	defaultDrainTimeout   = 30 * time.Second
+	defaultJoinRetries    = 10
+	defaultJoinBackoff    = 5 * time.Second
+	defaultJoinMaxBackoff = time.Minute
+	defaultBootTimeout    = 30 * time.Minute
+	defaultStartupPeriod  = 10
+)
+
 type statefulSetParameters struct {
 	Replicas                       *int32
 	Name                           string
 	IsDynamic                      bool
 	PersistentVolumeClaim          corev1.PersistentVolumeClaim
+	PVCRetentionPolicy             *appsv1.StatefulSetPersistentVolumeClaimRetentionPolicy
 	ServiceName                    string
 	TerminationGracePeriodSeconds  *int64
 	UpdateStrategy                 appsv1.StatefulSetUpdateStrategyType
+	PodManagementPolicy            marklogicv1.PodManagementPolicy
 	NodeSelector                   map[string]string
 	Affinity                       *corev1.Affinity
 	TopologySpreadConstraints      []corev1.TopologySpreadConstraint
@@ -36,6 +51,9 @@ type statefulSetParameters struct {
 	AdditionalVolumeClaimTemplates *[]corev1.PersistentVolumeClaim
 	ServiceAccountName             string
 	AutomountServiceAccountToken   *bool
+	VeleroHooks                    *marklogicv1.VeleroHooks
+	AutoscalerDisruption           marklogicv1.AutoscalerDisruption
+	AllowDisruptibleNodes          bool
 }
 
 type containerParameters struct {
@@ -65,6 +83,12 @@ type containerParameters struct {
 	AdditionalVolumeMounts *[]corev1.VolumeMount
 	SecretName             string
 	IsDynamic              bool
+	Drain                  *marklogicv1.ShutdownDrain
+	HostJoin               *marklogicv1.HostJoin
+	StartupProbe           *marklogicv1.StartupProbe
+	RestrictedPodSecurity  bool
+	ReadOnlyRootFilesystem *marklogicv1.ReadOnlyRootFilesystem
+	HostRenames            []marklogicv1.HostRename
 }
 
 func (oc *OperatorContext) ReconcileStatefulset() (reconcile.Result, error) {
@@ -76,20 +100,44 @@ func (oc *OperatorContext) ReconcileStatefulset() (reconcile.Result, error) {
 	}
 	groupLabels["app.kubernetes.io/instance"] = cr.Spec.Name
 	groupLabels["app.kubernetes.io/component"] = getMarkLogicComponentLabel(cr.Spec.IsDynamic)
-	groupAnnotations := cr.GetAnnotations()
+	groupAnnotations := withoutSpecTracking(cr.GetAnnotations())
 	delete(groupAnnotations, "banzaicloud.com/last-applied")
 	objectMeta := generateObjectMeta(cr.Spec.Name, cr.Namespace, groupLabels, groupAnnotations)
 	currentSts, err := oc.GetStatefulSet(cr.Namespace, objectMeta.Name)
 	containerParams := generateContainerParams(cr)
 	statefulSetParams := generateStatefulSetsParams(cr)
 	statefulSetDef := generateStatefulSetsDef(objectMeta, statefulSetParams, marklogicServerAsOwner(cr), containerParams)
+	applyDefaultStorageClass(statefulSetDef, currentSts)
+	if pending, pendingErr := oc.firstPodPending(currentSts); pendingErr != nil {
+		logger.Error(pendingErr, "Failed to check the first pod of the group")
+		return result.Error(pendingErr).Output()
+	} else if pending {
+		// BootstrapFirst: the other pods start once pod 0 is ready.
+		firstPodOnly := int32(1)
+		statefulSetDef.Spec.Replicas = &firstPodOnly
+	}
+	currentReplicas := int32(0)
+	if err == nil && currentSts.Spec.Replicas != nil {
+		currentReplicas = *currentSts.Spec.Replicas
+	}
+	quotaHeld := oc.checkScaleUpQuota(statefulSetDef, currentReplicas)
 	if err != nil {
 		if apierrors.IsNotFound(err) {
+			if quotaHeld {
+				return result.RequeueSoon(quotaRequeueSeconds).Output()
+			}
+			oc.recordPodSecurityViolations(statefulSetDef)
+			hash := specHash(statefulSetDef.Spec, statefulSetDef.ObjectMeta)
+			setSpecHash(statefulSetDef, hash)
 			err := oc.createStatefulSet(statefulSetDef, cr)
 			if err != nil {
 				logger.Error(err, "Failed to create statefulSet")
 				return result.Error(err).Output()
 			}
+			if err := observeGeneration(oc.Ctx, oc.Client, statefulSetDef, hash); err != nil {
+				logger.Error(err, "Error recording the observed generation of the statefulSet")
+				return result.Error(err).Output()
+			}
 			oc.Recorder.Event(oc.MarklogicGroup, "Normal", "StatefulSetCreated", "MarkLogic statefulSet created successfully")
 			return result.Done().Output()
 		}
@@ -97,36 +145,21 @@ func (oc *OperatorContext) ReconcileStatefulset() (reconcile.Result, error) {
 		return result.Error(err).Output()
 	}
 
-	patchDiff, err := patch.DefaultPatchMaker.Calculate(currentSts, statefulSetDef,
-		patch.IgnoreStatusFields(),
-		patch.IgnoreVolumeClaimTemplateTypeMetaAndStatus(),
-		patch.IgnoreField("kind"))
+	if currentSts.Spec.PodManagementPolicy != statefulSetDef.Spec.PodManagementPolicy {
+		return oc.recreateStatefulSet(currentSts, statefulSetDef.Spec.PodManagementPolicy).Output()
+	}
+
 	if shouldDelayDynamicEmptyDirScaleDown(cr, currentSts) {
 		statefulSetDef.Spec.Replicas = currentSts.Spec.Replicas
-		patchDiff, err = patch.DefaultPatchMaker.Calculate(currentSts, statefulSetDef,
-			patch.IgnoreStatusFields(),
-			patch.IgnoreVolumeClaimTemplateTypeMetaAndStatus(),
-			patch.IgnoreField("kind"))
 	}
-	logger.Info("Patch Diff:", "Diff", patchDiff.String())
-	logger.Info("statefulSetDef Spec:", "Spec", statefulSetDef.Spec.Replicas)
-	if err != nil {
-		logger.Error(err, "Error calculating patch")
+	hash := specHash(statefulSetDef.Spec, statefulSetDef.ObjectMeta)
+	if specUpToDate(currentSts, hash) {
+		logger.Info("MarkLogic statefulSet is up to date with the MarkLogicGroup spec, no update needed")
+	} else if err := oc.updateStatefulSet(currentSts, statefulSetDef, hash); err != nil {
 		return result.Error(err).Output()
 	}
-
-	if !patchDiff.IsEmpty() {
-		logger.Info("MarkLogic statefulSet spec is different from the MarkLogicGroup spec, updating the statefulSet")
-		currentSts.Spec = statefulSetDef.Spec
-		currentSts.ObjectMeta.Annotations = statefulSetDef.ObjectMeta.Annotations
-		currentSts.ObjectMeta.Labels = statefulSetDef.ObjectMeta.Labels
-		err := oc.Client.Update(oc.Ctx, currentSts)
-		if err != nil {
-			logger.Error(err, "Error updating statefulSet")
-			return result.Error(err).Output()
-		}
-	} else {
-		logger.Info("MarkLogic statefulSet spec is the same as the current spec, no update needed")
+	if quotaHeld {
+		return result.RequeueSoon(quotaRequeueSeconds).Output()
 	}
 	logger.Info("Operator Status:", "Stage", cr.Status.Stage)
 	if cr.Status.Stage == "STS_CREATED" {
@@ -139,6 +172,7 @@ func (oc *OperatorContext) ReconcileStatefulset() (reconcile.Result, error) {
 	}
 
 	patchClient := client.MergeFrom(oc.MarklogicGroup.DeepCopy())
+	scaleUpdated := oc.setScaleStatus(currentSts)
 	updated := false
 	if currentSts.Status.ReadyReplicas == 0 || currentSts.Status.ReadyReplicas != currentSts.Status.Replicas {
 		logger.Info("MarkLogic statefulSet is not ready, setting condition and requeue")
@@ -148,9 +182,9 @@ func (oc *OperatorContext) ReconcileStatefulset() (reconcile.Result, error) {
 			Reason:  "MarkLogicGroupStatefulSetNotReady",
 			Message: "MarkLogicGroup statefulSet is not ready",
 		}
-		updated = oc.setCondition(&condition)
+		updated = oc.setCondition(&condition) || scaleUpdated
 		if updated {
-			err := oc.Client.Status().Patch(oc.Ctx, oc.MarklogicGroup, patchClient)
+			err := patchStatus(oc.Ctx, oc.Client, oc.MarklogicGroup, patchClient)
 			if err != nil {
 				oc.ReqLogger.Error(err, "error updating the MarkLogic Operator Internal status")
 			}
@@ -164,10 +198,10 @@ func (oc *OperatorContext) ReconcileStatefulset() (reconcile.Result, error) {
 			Reason:  "MarkLogicGroupStatefulSetReady",
 			Message: "MarkLogicGroup statefulSet is ready",
 		}
-		updated = oc.setCondition(&condition)
+		updated = oc.setCondition(&condition) || scaleUpdated
 	}
 	if updated {
-		err := oc.Client.Status().Patch(oc.Ctx, oc.MarklogicGroup, patchClient)
+		err := patchStatus(oc.Ctx, oc.Client, oc.MarklogicGroup, patchClient)
 		if err != nil {
 			oc.ReqLogger.Error(err, "error updating the MarkLogic Operator Internal status")
 		}
@@ -176,6 +210,63 @@ func (oc *OperatorContext) ReconcileStatefulset() (reconcile.Result, error) {
 	return result.Done().Output()
 }
 
+// updateStatefulSet updates the StatefulSet of the group when it differs from
+// what the operator generates, and records that it is up to date with hash.
+func (oc *OperatorContext) updateStatefulSet(currentSts, statefulSetDef *appsv1.StatefulSet, hash string) error {
+	logger := oc.ReqLogger
+	setSpecHash(statefulSetDef, hash)
+	if !podTemplateChanged(&currentSts.Spec.Template, &statefulSetDef.Spec.Template) {
+		// Keep the current pod template, so an update of other fields of the
+		// StatefulSet does not roll the pods.
+		statefulSetDef.Spec.Template = currentSts.Spec.Template
+	}
+	patchDiff, err := patch.DefaultPatchMaker.Calculate(currentSts, statefulSetDef,
+		patch.IgnoreStatusFields(),
+		patch.IgnoreVolumeClaimTemplateTypeMetaAndStatus(),
+		patch.IgnoreField("kind"))
+	if err != nil {
+		logger.Error(err, "Error calculating patch")
+		return err
+	}
+	logger.Info("Patch Diff:", "Diff", patchDiff.String())
+	logger.Info("statefulSetDef Spec:", "Spec", statefulSetDef.Spec.Replicas)
+
+	if !patchDiff.IsEmpty() {
+		logger.Info("MarkLogic statefulSet spec is different from the MarkLogicGroup spec, updating the statefulSet")
+		oc.recordPodSecurityViolations(statefulSetDef)
+		currentSts.Spec = statefulSetDef.Spec
+		currentSts.ObjectMeta.Annotations = statefulSetDef.ObjectMeta.Annotations
+		currentSts.ObjectMeta.Labels = statefulSetDef.ObjectMeta.Labels
+		if err := oc.Client.Update(oc.Ctx, currentSts); err != nil {
+			logger.Error(err, "Error updating statefulSet")
+			return err
+		}
+	} else {
+		logger.Info("MarkLogic statefulSet spec is the same as the current spec, no update needed")
+	}
+	if err := observeGeneration(oc.Ctx, oc.Client, currentSts, hash); err != nil {
+		logger.Error(err, "Error recording the observed generation of the statefulSet")
+		return err
+	}
+	return nil
+}
+
+// setScaleStatus sets the status the scale subresource of the MarklogicGroup
+// reads from the StatefulSet, and reports whether it changed.
+func (oc *OperatorContext) setScaleStatus(sts *appsv1.StatefulSet) bool {
+	selector := ""
+	if sts.Spec.Selector != nil {
+		selector = metav1.FormatLabelSelector(sts.Spec.Selector)
+	}
+	status := &oc.MarklogicGroup.Status
+	if status.Replicas == sts.Status.Replicas && status.Selector == selector {
+		return false
+	}
+	status.Replicas = sts.Status.Replicas
+	status.Selector = selector
+	return true
+}
+
 func shouldDelayDynamicEmptyDirScaleDown(cr *marklogicv1.MarklogicGroup, currentSts *appsv1.StatefulSet) bool {
 	if cr == nil || currentSts == nil || !cr.Spec.IsDynamic {
 		return false
@@ -237,15 +328,16 @@ func generateStatefulSetsDef(stsMeta metav1.ObjectMeta, params statefulSetParame
 		TypeMeta:   generateTypeMeta("StatefulSet", "apps/v1"),
 		ObjectMeta: stsMeta,
 		Spec: appsv1.StatefulSetSpec{
-			Selector:            LabelSelectors(getSelectorLabelsByComponent(params.Name, params.IsDynamic)),
-			ServiceName:         stsMeta.Name,
-			Replicas:            params.Replicas,
-			PodManagementPolicy: appsv1.ParallelPodManagement,
-			UpdateStrategy:      appsv1.StatefulSetUpdateStrategy{Type: params.UpdateStrategy},
+			Selector:                             LabelSelectors(getSelectorLabelsByComponent(params.Name, params.IsDynamic)),
+			ServiceName:                          stsMeta.Name,
+			Replicas:                             params.Replicas,
+			PodManagementPolicy:                  statefulSetPodManagementPolicy(params.PodManagementPolicy),
+			UpdateStrategy:                       appsv1.StatefulSetUpdateStrategy{Type: params.UpdateStrategy},
+			PersistentVolumeClaimRetentionPolicy: params.PVCRetentionPolicy,
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
 					Labels:      stsMeta.GetLabels(),
-					Annotations: stsMeta.GetAnnotations(),
+					Annotations: autoscalerAnnotations(podTemplateAnnotations(stsMeta.GetAnnotations(), params.VeleroHooks), params.AutoscalerDisruption),
 				},
 				Spec: corev1.PodSpec{
 					Containers:                    generateContainerDef("marklogic-server", containerParams),
@@ -254,6 +346,7 @@ func generateStatefulSetsDef(stsMeta metav1.ObjectMeta, params statefulSetParame
 					Volumes:                       generateVolumes(stsMeta.Name, containerParams),
 					NodeSelector:                  params.NodeSelector,
 					Affinity:                      params.Affinity,
+					Tolerations:                   podTolerations(params.AllowDisruptibleNodes),
 					TopologySpreadConstraints:     params.TopologySpreadConstraints,
 					PriorityClassName:             params.PriorityClassName,
 					ImagePullSecrets:              params.ImagePullSecrets,
@@ -270,6 +363,7 @@ func generateStatefulSetsDef(stsMeta metav1.ObjectMeta, params statefulSetParame
 		statefulSet.Spec.Template.Spec.Volumes = append(statefulSet.Spec.Template.Spec.Volumes, emptyDir)
 	} else {
 		statefulSet.Spec.VolumeClaimTemplates = append(statefulSet.Spec.VolumeClaimTemplates, params.PersistentVolumeClaim)
+		statefulSet.Spec.VolumeClaimTemplates = append(statefulSet.Spec.VolumeClaimTemplates, generateTierPVCTemplates(containerParams.Persistence)...)
 	}
 	if params.AdditionalVolumeClaimTemplates != nil {
 		statefulSet.Spec.VolumeClaimTemplates = append(statefulSet.Spec.VolumeClaimTemplates, *params.AdditionalVolumeClaimTemplates...)
@@ -332,6 +426,9 @@ func generateStatefulSetsDef(stsMeta metav1.ObjectMeta, params statefulSetParame
 			},
 		}
 	}
+	if containerParams.RestrictedPodSecurity {
+		restrictPodSpec(&statefulSet.Spec.Template.Spec)
+	}
 
 	AddOwnerRefToObject(statefulSet, ownerDef)
 	return statefulSet
@@ -366,33 +463,46 @@ func generateContainerDef(name string, containerParams containerParameters) []co
 		containerDef[0].Resources = *containerParams.Resources
 	}
 
+	if containerParams.StartupProbe != nil && containerParams.StartupProbe.Enabled {
+		containerDef[0].StartupProbe = getStartupProbe(containerParams.StartupProbe)
+	}
+
 	if containerParams.LivenessProbe.Enabled {
-		containerDef[0].LivenessProbe = getLivenessProbe(containerParams.LivenessProbe)
+		containerDef[0].LivenessProbe = getLivenessProbe(withProbeDefaults(containerParams.LivenessProbe, OperatorConfig.LivenessProbe))
 	}
 
 	if containerParams.ReadinessProbe.Enabled {
+		readinessProbe := withProbeDefaults(containerParams.ReadinessProbe, OperatorConfig.ReadinessProbe)
 		if containerParams.IsDynamic {
-			containerDef[0].ReadinessProbe = getReadinessTCPProbe(containerParams.ReadinessProbe)
+			containerDef[0].ReadinessProbe = getReadinessTCPProbe(readinessProbe)
 		} else {
-			containerDef[0].ReadinessProbe = getReadinessProbe(containerParams.ReadinessProbe)
+			containerDef[0].ReadinessProbe = getReadinessProbe(readinessProbe)
 		}
 	}
 
 	if containerParams.LogCollection != nil && containerParams.LogCollection.Enabled {
-		fulentBitContainerDef := corev1.Container{
-			Name:            "fluent-bit",
-			Image:           containerParams.LogCollection.Image,
+		collector := newLogCollector(containerParams.LogCollection)
+		logCollectorDef := corev1.Container{
+			Name:            collector.name(),
+			Image:           logCollectionImage(containerParams.LogCollection),
 			ImagePullPolicy: "IfNotPresent",
-			Command:         []string{"/fluent-bit/bin/fluent-bit"},
-			Args:            []string{"--config=/fluent-bit/etc/fluent-bit.yaml"},
 			Env:             getFluentBitEnvironmentVariables(),
 			SecurityContext: getFluentBitSecurityContextOrDefault(containerParams.LogCollection.SecurityContext),
 			VolumeMounts:    getFluentBitVolumeMount(containerParams),
+			// The last log lines of a crashed log collector are reported in
+			// the LogCollectionDegraded condition.
+			TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
 		}
+		collector.container(&logCollectorDef)
 		if containerParams.LogCollection.Resources != nil {
-			fulentBitContainerDef.Resources = *containerParams.LogCollection.Resources
+			logCollectorDef.Resources = *containerParams.LogCollection.Resources
+		}
+		containerDef = append(containerDef, logCollectorDef)
+	}
+	if readOnlyRootFilesystemEnabled(containerParams.ReadOnlyRootFilesystem) {
+		for i := range containerDef {
+			containerDef[i].SecurityContext = readOnlySecurityContext(containerDef[i].SecurityContext)
 		}
-		containerDef = append(containerDef, fulentBitContainerDef)
 	}
 
 	return containerDef
@@ -410,15 +520,20 @@ func generateStatefulSetsParams(cr *marklogicv1.MarklogicGroup) statefulSetParam
 		AutomountServiceAccountToken:   &falseValue, // Always false for security
 		TerminationGracePeriodSeconds:  cr.Spec.TerminationGracePeriodSeconds,
 		UpdateStrategy:                 cr.Spec.UpdateStrategy,
+		PodManagementPolicy:            cr.Spec.PodManagementPolicy,
 		NodeSelector:                   cr.Spec.NodeSelector,
-		Affinity:                       cr.Spec.Affinity,
+		Affinity:                       architectureAffinity(cr.Spec.Affinity, cr.Spec.NodeSelector, cr.Spec.Architecture),
 		TopologySpreadConstraints:      cr.Spec.TopologySpreadConstraints,
 		PriorityClassName:              cr.Spec.PriorityClassName,
 		ImagePullSecrets:               cr.Spec.ImagePullSecrets,
 		AdditionalVolumeClaimTemplates: cr.Spec.AdditionalVolumeClaimTemplates,
+		VeleroHooks:                    cr.Spec.VeleroHooks,
+		AutoscalerDisruption:           cr.Spec.AutoscalerDisruption,
+		AllowDisruptibleNodes:          cr.Spec.AllowDisruptibleNodes,
 	}
 	if cr.Spec.Persistence != nil && cr.Spec.Persistence.Enabled {
 		params.PersistentVolumeClaim = generatePVCTemplate(cr.Spec.Persistence)
+		params.PVCRetentionPolicy = statefulSetPVCRetentionPolicy(cr)
 	}
 	return params
 }
@@ -444,6 +559,12 @@ func generateContainerParams(cr *marklogicv1.MarklogicGroup) containerParameters
 		AdditionalVolumeMounts: cr.Spec.AdditionalVolumeMounts,
 		Persistence:            cr.Spec.Persistence,
 		IsDynamic:              cr.Spec.IsDynamic,
+		Drain:                  cr.Spec.Drain,
+		HostJoin:               cr.Spec.HostJoin,
+		StartupProbe:           cr.Spec.StartupProbe,
+		RestrictedPodSecurity:  cr.Spec.RestrictedPodSecurity,
+		ReadOnlyRootFilesystem: cr.Spec.ReadOnlyRootFilesystem,
+		HostRenames:            cr.Spec.HostRenames,
 	}
 
 	// Set SecretName with fallback to default if not specified
@@ -526,19 +647,40 @@ func generateVolumes(stsName string, containerParams containerParameters) []core
 	}
 	if containerParams.LogCollection != nil && containerParams.LogCollection.Enabled {
 		volumes = append(volumes, corev1.Volume{
-			Name: "fluent-bit",
+			Name: newLogCollector(containerParams.LogCollection).name(),
 			VolumeSource: corev1.VolumeSource{
 				ConfigMap: &corev1.ConfigMapVolumeSource{
 					LocalObjectReference: corev1.LocalObjectReference{
-						Name: "fluent-bit",
+						Name: fluentBitConfigMapName(stsName),
 					},
 				},
 			},
 		})
+		if readOnlyRootFilesystemEnabled(containerParams.ReadOnlyRootFilesystem) {
+			volumes = append(volumes, corev1.Volume{
+				Name:         fluentBitTmpVolumeName,
+				VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{SizeLimit: containerParams.ReadOnlyRootFilesystem.SizeLimit}},
+			})
+		}
+		if fluentBitFilesystemBuffer(containerParams.LogCollection) {
+			volumes = append(volumes, fluentBitBufferVolume())
+		}
+		if containerParams.LogCollection.CredentialsSecretName != "" {
+			volumes = append(volumes, corev1.Volume{
+				Name: "fluent-bit-credentials",
+				VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{
+						SecretName: fluentBitCredentialsSecretName(containerParams.Name),
+					},
+				},
+			})
+		}
 	}
 	if containerParams.AdditionalVolumes != nil {
 		volumes = append(volumes, *containerParams.AdditionalVolumes...)
 	}
+	writableVolumes, _ := writablePathVolumes(containerParams)
+	volumes = append(volumes, writableVolumes...)
 	if containerParams.Tls != nil && containerParams.Tls.EnableOnDefaultAppServers {
 		volumes = append(volumes, corev1.Volume{
 			Name:         "certs",
@@ -610,6 +752,28 @@ func generatePVCTemplate(persistence *marklogicv1.Persistence) corev1.Persistent
 	return pvcTemplate
 }
 
+// generateTierPVCTemplates returns a volume claim template for every storage
+// tier of the group.
+func generateTierPVCTemplates(persistence *marklogicv1.Persistence) []corev1.PersistentVolumeClaim {
+	templates := []corev1.PersistentVolumeClaim{}
+	for _, tier := range persistence.Tiers {
+		template := corev1.PersistentVolumeClaim{}
+		template.Name = tierVolumeName(tier.Name)
+		if tier.StorageClassName != "" {
+			template.Spec.StorageClassName = &tier.StorageClassName
+		}
+		template.Spec.AccessModes = persistence.AccessModes
+		template.Annotations = persistence.Annotations
+		template.Spec.Resources.Requests = corev1.ResourceList{corev1.ResourceStorage: tier.Size}
+		templates = append(templates, template)
+	}
+	return templates
+}
+
+func tierVolumeName(tier string) string {
+	return "tier-" + tier
+}
+
 func getEnvironmentVariables(containerParams containerParameters) []corev1.EnvVar {
 	envVars := []corev1.EnvVar{}
 	groupName := "Default"
@@ -701,9 +865,76 @@ func getEnvironmentVariables(containerParams containerParameters) []corev1.EnvVa
 		})
 	}
 
+	if readOnlyRootFilesystemEnabled(containerParams.ReadOnlyRootFilesystem) {
+		envVars = append(envVars, corev1.EnvVar{
+			Name:  "MARKLOGIC_PID_FILE",
+			Value: readOnlyPIDFile,
+		})
+	}
+
+	if containerParams.Drain != nil && containerParams.Drain.Enabled {
+		envVars = append(envVars, corev1.EnvVar{
+			Name:  "MARKLOGIC_DRAIN_DELAY_SECONDS",
+			Value: durationSeconds(containerParams.Drain.Delay, defaultDrainDelay),
+		}, corev1.EnvVar{
+			Name:  "MARKLOGIC_DRAIN_TIMEOUT_SECONDS",
+			Value: durationSeconds(containerParams.Drain.Timeout, defaultDrainTimeout),
+		})
+	}
+
+	if containerParams.StartupProbe != nil && containerParams.StartupProbe.Enabled {
+		envVars = append(envVars, corev1.EnvVar{
+			Name:  "MARKLOGIC_BOOT_TIMEOUT_SECONDS",
+			Value: durationSeconds(containerParams.StartupProbe.BootTimeout, defaultBootTimeout),
+		}, corev1.EnvVar{
+			Name:  "MARKLOGIC_STARTUP_WAIT_FOR_FORESTS",
+			Value: strconv.FormatBool(containerParams.StartupProbe.WaitForForests),
+		})
+	}
+
+	if len(containerParams.HostRenames) > 0 {
+		renames := []string{}
+		for _, rename := range containerParams.HostRenames {
+			renames = append(renames, rename.From+"="+rename.To)
+		}
+		envVars = append(envVars, corev1.EnvVar{
+			Name:  "MARKLOGIC_HOST_RENAMES",
+			Value: strings.Join(renames, " "),
+		})
+	}
+
+	if containerParams.HostJoin != nil {
+		retries := containerParams.HostJoin.Retries
+		if retries <= 0 {
+			retries = defaultJoinRetries
+		}
+		envVars = append(envVars, corev1.EnvVar{
+			Name:  "MARKLOGIC_JOIN_MAX_CONCURRENT",
+			Value: strconv.Itoa(int(containerParams.HostJoin.MaxConcurrent)),
+		}, corev1.EnvVar{
+			Name:  "MARKLOGIC_JOIN_RETRIES",
+			Value: strconv.Itoa(int(retries)),
+		}, corev1.EnvVar{
+			Name:  "MARKLOGIC_JOIN_BACKOFF_SECONDS",
+			Value: durationSeconds(containerParams.HostJoin.Backoff, defaultJoinBackoff),
+		}, corev1.EnvVar{
+			Name:  "MARKLOGIC_JOIN_MAX_BACKOFF_SECONDS",
+			Value: durationSeconds(containerParams.HostJoin.MaxBackoff, defaultJoinMaxBackoff),
+		})
+	}
+
 	return envVars
 }
 
+// durationSeconds formats d, or def when d is unset, as whole seconds for
+// the scripts of the MarkLogic container.
+func durationSeconds(d *metav1.Duration, def time.Duration) string {
+	if d != nil && d.Duration > 0 {
+		def = d.Duration
+	}
+	return strconv.Itoa(int(def.Seconds()))
+}
+
 func getFluentBitEnvironmentVariables() []corev1.EnvVar {
 
 	envVars := []corev1.EnvVar{}
@@ -738,6 +969,14 @@ func getVolumeMount(containerParams containerParameters) []corev1.VolumeMount {
 			ReadOnly:  true,
 		},
 	)
+	if containerParams.Persistence != nil && containerParams.Persistence.Enabled {
+		for _, tier := range containerParams.Persistence.Tiers {
+			VolumeMounts = append(VolumeMounts, corev1.VolumeMount{
+				Name:      tierVolumeName(tier.Name),
+				MountPath: storageTierMountPath + tier.Name,
+			})
+		}
+	}
 	if containerParams.HugePages != nil && containerParams.HugePages.Enabled {
 		VolumeMounts = append(VolumeMounts,
 			corev1.VolumeMount{
@@ -756,6 +995,8 @@ func getVolumeMount(containerParams containerParameters) []corev1.VolumeMount {
 	if containerParams.AdditionalVolumeMounts != nil {
 		VolumeMounts = append(VolumeMounts, *containerParams.AdditionalVolumeMounts...)
 	}
+	_, writableMounts := writablePathVolumes(containerParams)
+	VolumeMounts = append(VolumeMounts, writableMounts...)
 	return VolumeMounts
 }
 
@@ -777,13 +1018,33 @@ func getFluentBitVolumeMount(containerParams containerParameters) []corev1.Volum
 		}
 	}
 
+	collector := newLogCollector(containerParams.LogCollection)
 	VolumeMountsFluentBit = append(VolumeMountsFluentBit,
 		logsMount,
 		corev1.VolumeMount{
-			Name:      "fluent-bit",
-			MountPath: "/fluent-bit/etc/",
+			Name:      collector.name(),
+			MountPath: collector.configPath(),
 		},
 	)
+	if fluentBitFilesystemBuffer(containerParams.LogCollection) {
+		VolumeMountsFluentBit = append(VolumeMountsFluentBit, corev1.VolumeMount{
+			Name:      fluentBitBufferVolumeName,
+			MountPath: fluentBitBufferPath,
+		})
+	}
+	if containerParams.LogCollection.CredentialsSecretName != "" {
+		VolumeMountsFluentBit = append(VolumeMountsFluentBit, corev1.VolumeMount{
+			Name:      "fluent-bit-credentials",
+			MountPath: fluentBitCredentialsMountPath,
+			ReadOnly:  true,
+		})
+	}
+	if readOnlyRootFilesystemEnabled(containerParams.ReadOnlyRootFilesystem) {
+		VolumeMountsFluentBit = append(VolumeMountsFluentBit, corev1.VolumeMount{
+			Name:      fluentBitTmpVolumeName,
+			MountPath: "/tmp",
+		})
+	}
 	return VolumeMountsFluentBit
 }
 
@@ -805,6 +1066,31 @@ func getLivenessProbe(probe marklogicv1.ContainerProbe) *corev1.Probe {
 	}
 }
 
+// getStartupProbe runs startup-probe.sh until MarkLogic is up, and with
+// waitForForests until the forests of the host are open. The pod is only
+// restarted once the boot timeout has passed.
+func getStartupProbe(probe *marklogicv1.StartupProbe) *corev1.Probe {
+	period := probe.PeriodSeconds
+	if period <= 0 {
+		period = defaultStartupPeriod
+	}
+	bootTimeout := defaultBootTimeout
+	if probe.BootTimeout != nil && probe.BootTimeout.Duration > 0 {
+		bootTimeout = probe.BootTimeout.Duration
+	}
+	return &corev1.Probe{
+		PeriodSeconds:    period,
+		TimeoutSeconds:   period,
+		SuccessThreshold: 1,
+		FailureThreshold: int32(math.Ceil(bootTimeout.Seconds() / float64(period))),
+		ProbeHandler: corev1.ProbeHandler{
+			Exec: &corev1.ExecAction{
+				Command: []string{"/bin/bash", "/tmp/helm-scripts/startup-probe.sh"},
+			},
+		},
+	}
+}
+
 func getReadinessProbe(probe marklogicv1.ContainerProbe) *corev1.Probe {
 	return &corev1.Probe{
 		InitialDelaySeconds: probe.InitialDelaySeconds,
@@ -843,3 +1129,60 @@ func getReadinessTCPProbe(probe marklogicv1.ContainerProbe) *corev1.Probe {
 		},
 	}
 }
+
+// podTemplateAnnotations returns the group annotations plus the Velero backup
+// hook annotations when Velero hooks are enabled. The input map is not modified.
+func podTemplateAnnotations(annotations map[string]string, hooks *marklogicv1.VeleroHooks) map[string]string {
+	if hooks == nil || !hooks.Enabled {
+		return annotations
+	}
+	merged := make(map[string]string, len(annotations)+8)
+	for key, value := range annotations {
+		merged[key] = value
+	}
+	timeout := hooks.TimeoutSeconds
+	if timeout <= 0 {
+		timeout = 300
+	}
+	onError := hooks.OnError
+	if onError == "" {
+		onError = "Fail"
+	}
+	merged["pre.hook.backup.velero.io/container"] = "marklogic-server"
+	merged["pre.hook.backup.velero.io/command"] = `["/bin/bash", "/tmp/helm-scripts/velero-hook.sh", "freeze"]`
+	merged["pre.hook.backup.velero.io/on-error"] = onError
+	merged["pre.hook.backup.velero.io/timeout"] = fmt.Sprintf("%ds", timeout)
+	merged["post.hook.backup.velero.io/container"] = "marklogic-server"
+	merged["post.hook.backup.velero.io/command"] = `["/bin/bash", "/tmp/helm-scripts/velero-hook.sh", "thaw"]`
+	merged["post.hook.backup.velero.io/on-error"] = "Continue"
+	merged["post.hook.backup.velero.io/timeout"] = fmt.Sprintf("%ds", timeout)
+	return merged
+}
+
+const (
+	karpenterDoNotDisruptAnnotation = "karpenter.sh/do-not-disrupt"
+	clusterAutoscalerSafeToEvict    = "cluster-autoscaler.kubernetes.io/safe-to-evict"
+)
+
+// autoscalerAnnotations adds the annotations that tell Karpenter and the
+// cluster autoscaler whether they may evict the pods. Annotations the group
+// sets itself are kept.
+func autoscalerAnnotations(annotations map[string]string, disruption marklogicv1.AutoscalerDisruption) map[string]string {
+	var policy map[string]string
+	switch disruption {
+	case marklogicv1.AutoscalerDisruptionNever:
+		policy = map[string]string{karpenterDoNotDisruptAnnotation: "true", clusterAutoscalerSafeToEvict: "false"}
+	case marklogicv1.AutoscalerDisruptionCoordinated:
+		policy = map[string]string{clusterAutoscalerSafeToEvict: "true"}
+	default:
+		return annotations
+	}
+	merged := make(map[string]string, len(annotations)+len(policy))
+	for key, value := range policy {
+		merged[key] = value
+	}
+	for key, value := range annotations {
+		merged[key] = value
+	}
+	return merged
+}
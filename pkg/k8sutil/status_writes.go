@@ -0,0 +1,45 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	statusWriteWritten = "written"
+	statusWriteSkipped = "skipped"
+	statusWriteFailed  = "failed"
+)
+
+var statusWrites = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "marklogic_operator_status_writes_total",
+	Help: "Status writes of the operator by kind of resource and result: written, skipped as they changed nothing, or failed.",
+}, []string{"kind", "result"})
+
+func init() {
+	metrics.Registry.MustRegister(statusWrites)
+}
+
+// patchStatus writes the status of obj with a merge patch. A patch that
+// changes nothing, such as the status a step reports on every requeue while
+// it waits, is not sent, so steady reconciles do not write to the API
+// server.
+func patchStatus(ctx context.Context, c client.Client, obj client.Object, patch client.Patch) error {
+	kind := reflect.TypeOf(obj).Elem().Name()
+	if data, err := patch.Data(obj); err == nil && string(data) == "{}" {
+		statusWrites.WithLabelValues(kind, statusWriteSkipped).Inc()
+		return nil
+	}
+	if err := c.Status().Patch(ctx, obj, patch); err != nil {
+		statusWrites.WithLabelValues(kind, statusWriteFailed).Inc()
+		return err
+	}
+	statusWrites.WithLabelValues(kind, statusWriteWritten).Inc()
+	return nil
+}
@@ -0,0 +1,44 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"testing"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestPatchStatusSkipsUnchangedStatus(t *testing.T) {
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "status-writes", Namespace: "default"},
+		Spec:       marklogicv1.MarklogicClusterSpec{Image: "progressofficial/marklogic-db:11"},
+	}
+	cc := newUpgradeTestContext(t, cr)
+	written := statusWrites.WithLabelValues("MarklogicCluster", statusWriteWritten)
+	skipped := statusWrites.WithLabelValues("MarklogicCluster", statusWriteSkipped)
+	writes, skips := testutil.ToFloat64(written), testutil.ToFloat64(skipped)
+
+	patchBase := client.MergeFrom(cr.DeepCopy())
+	if err := patchStatus(cc.Ctx, cc.Client, cr, patchBase); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if testutil.ToFloat64(written) != writes || testutil.ToFloat64(skipped) != skips+1 {
+		t.Fatalf("expected the unchanged status to be skipped")
+	}
+
+	patchBase = client.MergeFrom(cr.DeepCopy())
+	cr.Status.Upgrade = &marklogicv1.UpgradeStatus{State: marklogicv1.UpgradeStateFailed}
+	if err := patchStatus(cc.Ctx, cc.Client, cr, patchBase); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if testutil.ToFloat64(written) != writes+1 {
+		t.Fatalf("expected the changed status to be written")
+	}
+	stored := &marklogicv1.MarklogicCluster{}
+	if err := cc.Client.Get(cc.Ctx, client.ObjectKeyFromObject(cr), stored); err != nil || stored.Status.Upgrade == nil {
+		t.Fatalf("expected the status to be stored, got %+v, %v", stored.Status, err)
+	}
+}
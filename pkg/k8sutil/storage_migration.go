@@ -0,0 +1,498 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// StorageMigrationAnnotation confirms the migration of the data volumes
+	// of a group to the storage class it names, which must be the storage
+	// class of the group spec.
+	StorageMigrationAnnotation = "marklogic.progress.com/migrate-storage-class"
+
+	storageMigrationClaimSuffix    = "-migration"
+	storageMigrationJobSuffix      = "-storage-migration"
+	storageMigrationRequeueSeconds = 5
+	storageMigrationWaitSeconds    = 30
+	storageMigrationBackoffLimit   = 2
+
+	storageReasonMigrationPending   = "StorageMigrationPending"
+	storageReasonMigrationStarted   = "StorageMigrationStarted"
+	storageReasonPodMigrated        = "StorageMigrationPodMigrated"
+	storageReasonMigrationCompleted = "StorageMigrationCompleted"
+	storageReasonMigrationFailed    = "StorageMigrationFailed"
+)
+
+// storageMigrationCopyCommand copies the data volume, keeping the owners and
+// permissions of the files.
+const storageMigrationCopyCommand = "set -euo pipefail; cp -a /source/. /target/; sync"
+
+// ReconcileStorageMigration moves the data volumes of a group to the storage
+// class of its spec once the group is annotated with
+// marklogic.progress.com/migrate-storage-class naming that class. The pods
+// are migrated one at a time, from the highest ordinal: a volume of the new
+// class is created, the pod is stopped, a Job copies its data and the new
+// volume takes the name of the old one before the pod starts again. The
+// volumes of the previous class are kept.
+//
+// Without the annotation, a changed storage class is not applied to the
+// StatefulSet, whose volume claim templates cannot be updated.
+func (oc *OperatorContext) ReconcileStorageMigration() result.ReconcileResult {
+	cr := oc.MarklogicGroup
+	if cr.Spec.Persistence == nil || !cr.Spec.Persistence.Enabled || cr.Spec.Persistence.StorageClassName == "" {
+		return result.Continue()
+	}
+	target := cr.Spec.Persistence.StorageClassName
+	currentSts, err := oc.GetStatefulSet(cr.Namespace, cr.Spec.Name)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return result.Error(err)
+		}
+		currentSts = nil
+	}
+	pending, err := oc.pvcsToMigrate(target)
+	if err != nil {
+		return result.Error(err)
+	}
+	templateChanged := currentSts != nil && dataStorageClass(currentSts) != target
+
+	migration := cr.Status.StorageMigration.DeepCopy()
+	// A migration to another storage class starts over once no pod is in
+	// the middle of one.
+	if migration != nil && migration.TargetStorageClass != target && migration.Pod == "" {
+		migration = nil
+	}
+	var res result.ReconcileResult
+	switch {
+	case migration != nil && migration.Phase == marklogicv1.StorageMigrationPhaseRunning:
+		res = oc.migrateStorage(migration, currentSts, pending)
+	case (templateChanged || len(pending) > 0) && isResizeOperationActive(cr.Status.VolumeResizeStatus):
+		// The volumes are migrated once they are resized.
+		res = result.RequeueSoon(storageMigrationWaitSeconds)
+	case !templateChanged && len(pending) == 0:
+		res = result.Continue()
+	case migration != nil && migration.Phase == marklogicv1.StorageMigrationPhaseFailed && cr.Annotations[StorageMigrationAnnotation] == target:
+		// A failed migration is retried by annotating the group again.
+		res = result.Continue()
+	case cr.Annotations[StorageMigrationAnnotation] != target:
+		message := fmt.Sprintf("the storage class of the data volumes changed to %s, annotate the group with %s=%s to migrate them",
+			target, StorageMigrationAnnotation, target)
+		if migration == nil || migration.Phase != marklogicv1.StorageMigrationPhasePending || migration.Message != message {
+			oc.recordGroupEvent(corev1.EventTypeWarning, storageReasonMigrationPending, message)
+		}
+		migration = &marklogicv1.StorageMigrationStatus{
+			Phase:              marklogicv1.StorageMigrationPhasePending,
+			SourceStorageClass: dataStorageClass(currentSts),
+			TargetStorageClass: target,
+			Message:            message,
+		}
+		res = result.Continue()
+		if templateChanged {
+			res = result.RequeueSoon(storageMigrationWaitSeconds)
+		}
+	default:
+		now := metav1.Now()
+		migration = &marklogicv1.StorageMigrationStatus{
+			Phase:              marklogicv1.StorageMigrationPhaseRunning,
+			SourceStorageClass: dataStorageClass(currentSts),
+			TargetStorageClass: target,
+			StartTime:          &now,
+		}
+		oc.recordGroupEvent(corev1.EventTypeNormal, storageReasonMigrationStarted,
+			fmt.Sprintf("migrating the data volumes of %d pods to storage class %s", len(pending), target))
+		res = oc.migrateStorage(migration, currentSts, pending)
+	}
+	if !reflect.DeepEqual(cr.Status.StorageMigration, migration) {
+		patchBase := client.MergeFrom(cr.DeepCopy())
+		cr.Status.StorageMigration = migration
+		if err := patchStatus(oc.Ctx, oc.Client, cr, patchBase); err != nil {
+			return result.Error(err)
+		}
+	}
+	return res
+}
+
+// migrateStorage runs the next step of a migration.
+func (oc *OperatorContext) migrateStorage(migration *marklogicv1.StorageMigrationStatus, currentSts *appsv1.StatefulSet, pending []corev1.PersistentVolumeClaim) result.ReconcileResult {
+	cr := oc.MarklogicGroup
+	// The pods keep running while the StatefulSet is created again with the
+	// new volume claim template, and stay down while their data is copied.
+	if currentSts != nil && (migration.Pod != "" || dataStorageClass(currentSts) != migration.TargetStorageClass) {
+		return oc.deleteStatefulSetForMigration(currentSts)
+	}
+	if migration.Pod == "" {
+		if len(pending) == 0 {
+			now := metav1.Now()
+			migration.Phase = marklogicv1.StorageMigrationPhaseCompleted
+			migration.CompletionTime = &now
+			migration.Message = fmt.Sprintf("the data volumes use storage class %s", migration.TargetStorageClass)
+			oc.recordGroupEvent(corev1.EventTypeNormal, storageReasonMigrationCompleted, migration.Message)
+			return result.Continue()
+		}
+		if currentSts == nil {
+			return result.Continue()
+		}
+		ready, err := oc.groupPodsReady()
+		if err != nil {
+			return result.Error(err)
+		}
+		if !ready {
+			return result.RequeueSoon(storageMigrationWaitSeconds)
+		}
+		pvc := &pending[0]
+		if err := oc.createMigrationClaim(pvc, migration.TargetStorageClass); err != nil {
+			return result.Error(err)
+		}
+		migration.Pod = derivePodNameFromPVC(cr.Spec.Name, pvc.Name)
+		migration.Step = marklogicv1.StorageMigrationStepStopping
+		migration.Message = fmt.Sprintf("stopping pod %s to copy its data", migration.Pod)
+		return oc.deleteStatefulSetForMigration(currentSts)
+	}
+
+	claimName := fmt.Sprintf("%s-%s", dataDirPVCName, migration.Pod)
+	switch migration.Step {
+	case marklogicv1.StorageMigrationStepStopping:
+		pod := &corev1.Pod{}
+		err := oc.Client.Get(oc.Ctx, types.NamespacedName{Name: migration.Pod, Namespace: cr.Namespace}, pod)
+		if err == nil {
+			if pod.DeletionTimestamp == nil {
+				if err := oc.Client.Delete(oc.Ctx, pod); err != nil && !apierrors.IsNotFound(err) {
+					return result.Error(err)
+				}
+			}
+			return result.RequeueSoon(storageMigrationRequeueSeconds)
+		}
+		if !apierrors.IsNotFound(err) {
+			return result.Error(err)
+		}
+		migration.Step = marklogicv1.StorageMigrationStepCopying
+		migration.Message = fmt.Sprintf("copying the data of pod %s", migration.Pod)
+		return result.RequeueSoon(1)
+	case marklogicv1.StorageMigrationStepCopying:
+		job := &batchv1.Job{}
+		err := oc.Client.Get(oc.Ctx, types.NamespacedName{Name: migration.Pod + storageMigrationJobSuffix, Namespace: cr.Namespace}, job)
+		if apierrors.IsNotFound(err) {
+			job = oc.storageMigrationJob(migration.Pod, claimName)
+			if err := controllerutil.SetControllerReference(cr, job, oc.Scheme); err != nil {
+				return result.Error(err)
+			}
+			if err := oc.Client.Create(oc.Ctx, job); err != nil && !apierrors.IsAlreadyExists(err) {
+				return result.Error(err)
+			}
+			return result.RequeueSoon(storageMigrationWaitSeconds)
+		}
+		if err != nil {
+			return result.Error(err)
+		}
+		switch {
+		case job.Status.Succeeded > 0:
+			migration.Step = marklogicv1.StorageMigrationStepSwapping
+			migration.Message = fmt.Sprintf("moving pod %s to its new volume", migration.Pod)
+			return result.RequeueSoon(1)
+		case jobFailed(job):
+			return oc.abortStorageMigration(migration, job, claimName)
+		}
+		return result.RequeueSoon(storageMigrationWaitSeconds)
+	case marklogicv1.StorageMigrationStepSwapping:
+		return oc.swapMigratedVolume(migration, claimName)
+	}
+	return result.Continue()
+}
+
+// swapMigratedVolume gives the volume the data was copied to the name of the
+// data volume of the pod. The volumes are kept with the Retain reclaim
+// policy while their claims are deleted, and the new one is bound to a claim
+// with the name of the old one.
+func (oc *OperatorContext) swapMigratedVolume(migration *marklogicv1.StorageMigrationStatus, claimName string) result.ReconcileResult {
+	cr := oc.MarklogicGroup
+	copyClaim, err := oc.getClaim(claimName + storageMigrationClaimSuffix)
+	if err != nil {
+		return result.Error(err)
+	}
+	if copyClaim != nil && migration.NewVolume == "" {
+		if copyClaim.Spec.VolumeName == "" {
+			return result.RequeueSoon(storageMigrationRequeueSeconds)
+		}
+		if err := oc.retainVolume(copyClaim.Spec.VolumeName); err != nil {
+			return result.Error(err)
+		}
+		migration.NewVolume = copyClaim.Spec.VolumeName
+	}
+	oldClaim, err := oc.getClaim(claimName)
+	if err != nil {
+		return result.Error(err)
+	}
+	if oldClaim != nil && oldClaim.Spec.VolumeName != migration.NewVolume {
+		if oldClaim.Spec.VolumeName != "" && !containsName(migration.RetainedVolumes, oldClaim.Spec.VolumeName) {
+			if err := oc.retainVolume(oldClaim.Spec.VolumeName); err != nil {
+				return result.Error(err)
+			}
+			migration.RetainedVolumes = append(migration.RetainedVolumes, oldClaim.Spec.VolumeName)
+		}
+		if err := oc.deleteStorageMigrationJob(migration.Pod); err != nil {
+			return result.Error(err)
+		}
+		if oldClaim.DeletionTimestamp == nil {
+			if err := oc.Client.Delete(oc.Ctx, oldClaim); err != nil && !apierrors.IsNotFound(err) {
+				return result.Error(err)
+			}
+		}
+		return result.RequeueSoon(storageMigrationRequeueSeconds)
+	}
+	if copyClaim != nil {
+		if copyClaim.DeletionTimestamp == nil {
+			if err := oc.Client.Delete(oc.Ctx, copyClaim); err != nil && !apierrors.IsNotFound(err) {
+				return result.Error(err)
+			}
+		}
+		return result.RequeueSoon(storageMigrationRequeueSeconds)
+	}
+	if oldClaim == nil {
+		pv := &corev1.PersistentVolume{}
+		if err := oc.Client.Get(oc.Ctx, types.NamespacedName{Name: migration.NewVolume}, pv); err != nil {
+			return result.Error(err)
+		}
+		// Reserve the volume for the new claim, the claim of the copy is gone.
+		patchBase := client.MergeFrom(pv.DeepCopy())
+		pv.Spec.ClaimRef = &corev1.ObjectReference{Kind: "PersistentVolumeClaim", APIVersion: "v1", Namespace: cr.Namespace, Name: claimName}
+		if err := oc.Client.Patch(oc.Ctx, pv, patchBase); err != nil {
+			return result.Error(err)
+		}
+		claim := oc.migrationClaim(claimName, migration.TargetStorageClass, pv.Spec.AccessModes, pv.Spec.Capacity[corev1.ResourceStorage])
+		claim.Spec.VolumeName = pv.Name
+		claim.Spec.VolumeMode = pv.Spec.VolumeMode
+		if err := oc.Client.Create(oc.Ctx, claim); err != nil && !apierrors.IsAlreadyExists(err) {
+			return result.Error(err)
+		}
+	}
+	oc.recordGroupEvent(corev1.EventTypeNormal, storageReasonPodMigrated,
+		fmt.Sprintf("pod %s runs on volume %s of storage class %s", migration.Pod, migration.NewVolume, migration.TargetStorageClass))
+	migration.MigratedPods = append(migration.MigratedPods, migration.Pod)
+	migration.Pod = ""
+	migration.Step = ""
+	migration.NewVolume = ""
+	migration.Message = ""
+	return result.Continue()
+}
+
+// abortStorageMigration removes the copy of a failed Job. The pod starts
+// again on its old volume.
+func (oc *OperatorContext) abortStorageMigration(migration *marklogicv1.StorageMigrationStatus, job *batchv1.Job, claimName string) result.ReconcileResult {
+	if err := oc.deleteStorageMigrationJob(migration.Pod); err != nil {
+		return result.Error(err)
+	}
+	copyClaim := &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: claimName + storageMigrationClaimSuffix, Namespace: job.Namespace}}
+	if err := oc.Client.Delete(oc.Ctx, copyClaim); err != nil && !apierrors.IsNotFound(err) {
+		return result.Error(err)
+	}
+	migration.Phase = marklogicv1.StorageMigrationPhaseFailed
+	migration.Message = fmt.Sprintf("copying the data of pod %s failed, see Job %s, the pod keeps its volume", migration.Pod, job.Name)
+	migration.Pod = ""
+	migration.Step = ""
+	oc.recordGroupEvent(corev1.EventTypeWarning, storageReasonMigrationFailed, migration.Message)
+	return result.Continue()
+}
+
+// pvcsToMigrate returns the data volume claims of the pods of the group
+// that are not of the target storage class, highest ordinal first.
+func (oc *OperatorContext) pvcsToMigrate(target string) ([]corev1.PersistentVolumeClaim, error) {
+	pvcs, err := oc.groupDataPVCs()
+	if err != nil {
+		return nil, err
+	}
+	replicas := 0
+	if oc.MarklogicGroup.Spec.Replicas != nil {
+		replicas = int(*oc.MarklogicGroup.Spec.Replicas)
+	}
+	pending := []corev1.PersistentVolumeClaim{}
+	for _, pvc := range pvcs {
+		if parseOrdinalFromName(pvc.Name) < replicas && (pvc.Spec.StorageClassName == nil || *pvc.Spec.StorageClassName != target) {
+			pending = append(pending, pvc)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool {
+		return parseOrdinalFromName(pending[i].Name) > parseOrdinalFromName(pending[j].Name)
+	})
+	return pending, nil
+}
+
+// dataStorageClass returns the storage class of the data volume claim
+// template of a StatefulSet.
+func dataStorageClass(sts *appsv1.StatefulSet) string {
+	if sts == nil {
+		return ""
+	}
+	for _, template := range sts.Spec.VolumeClaimTemplates {
+		if template.Name == dataDirPVCName && template.Spec.StorageClassName != nil {
+			return *template.Spec.StorageClassName
+		}
+	}
+	return ""
+}
+
+func (oc *OperatorContext) groupPodsReady() (bool, error) {
+	pods, err := oc.groupPods()
+	if err != nil {
+		return false, err
+	}
+	ready := 0
+	for i := range pods {
+		if pods[i].DeletionTimestamp == nil && hasPodReadyCondition(&pods[i]) {
+			ready++
+		}
+	}
+	return oc.MarklogicGroup.Spec.Replicas != nil && ready >= int(*oc.MarklogicGroup.Spec.Replicas), nil
+}
+
+func (oc *OperatorContext) deleteStatefulSetForMigration(sts *appsv1.StatefulSet) result.ReconcileResult {
+	if sts.DeletionTimestamp == nil {
+		orphan := metav1.DeletePropagationOrphan
+		if err := oc.Client.Delete(oc.Ctx, sts, &client.DeleteOptions{PropagationPolicy: &orphan}); err != nil && !apierrors.IsNotFound(err) {
+			return result.Error(err)
+		}
+	}
+	return result.RequeueSoon(storageMigrationRequeueSeconds)
+}
+
+func (oc *OperatorContext) getClaim(name string) (*corev1.PersistentVolumeClaim, error) {
+	pvc := &corev1.PersistentVolumeClaim{}
+	err := oc.Client.Get(oc.Ctx, types.NamespacedName{Name: name, Namespace: oc.MarklogicGroup.Namespace}, pvc)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return pvc, nil
+}
+
+// migrationClaim returns a data volume claim of the group like those the
+// StatefulSet creates.
+func (oc *OperatorContext) migrationClaim(name, storageClass string, accessModes []corev1.PersistentVolumeAccessMode, size resource.Quantity) *corev1.PersistentVolumeClaim {
+	cr := oc.MarklogicGroup
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   cr.Namespace,
+			Labels:      getSelectorLabelsByComponent(cr.Spec.Name, cr.Spec.IsDynamic),
+			Annotations: cr.Spec.Persistence.Annotations,
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes:      accessModes,
+			StorageClassName: &storageClass,
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: size},
+			},
+		},
+	}
+}
+
+// createMigrationClaim creates the claim the data of a volume is copied to.
+// It is owned by the group until it replaces the old claim.
+func (oc *OperatorContext) createMigrationClaim(pvc *corev1.PersistentVolumeClaim, storageClass string) error {
+	size := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	if capacity, ok := pvc.Status.Capacity[corev1.ResourceStorage]; ok && capacity.Cmp(size) > 0 {
+		size = capacity
+	}
+	claim := oc.migrationClaim(pvc.Name+storageMigrationClaimSuffix, storageClass, pvc.Spec.AccessModes, size)
+	claim.Spec.VolumeMode = pvc.Spec.VolumeMode
+	if err := controllerutil.SetControllerReference(oc.MarklogicGroup, claim, oc.Scheme); err != nil {
+		return err
+	}
+	if err := oc.Client.Create(oc.Ctx, claim); err != nil && !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+// storageMigrationJob returns the Job that copies the data volume of a pod
+// to the volume of the new storage class.
+func (oc *OperatorContext) storageMigrationJob(podName, claimName string) *batchv1.Job {
+	cr := oc.MarklogicGroup
+	backoffLimit := int32(storageMigrationBackoffLimit)
+	labels := map[string]string{
+		"app.kubernetes.io/instance":  cr.Spec.Name,
+		"marklogic.progress.com/task": "storage-migration",
+	}
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: podName + storageMigrationJobSuffix, Namespace: cr.Namespace, Labels: labels},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					RestartPolicy:    corev1.RestartPolicyNever,
+					SecurityContext:  cr.Spec.PodSecurityContext,
+					ImagePullSecrets: cr.Spec.ImagePullSecrets,
+					Containers: []corev1.Container{{
+						Name:            "copy",
+						Image:           cr.Spec.Image,
+						ImagePullPolicy: corev1.PullPolicy(cr.Spec.ImagePullPolicy),
+						Command:         []string{"/bin/bash", "-c", storageMigrationCopyCommand},
+						SecurityContext: getMarkLogicContainerSecurityContextOrDefault(cr.Spec.ContainerSecurityContext),
+						VolumeMounts: []corev1.VolumeMount{
+							{Name: "source", MountPath: "/source", ReadOnly: true},
+							{Name: "target", MountPath: "/target"},
+						},
+					}},
+					Volumes: []corev1.Volume{
+						{Name: "source", VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: claimName, ReadOnly: true}}},
+						{Name: "target", VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: claimName + storageMigrationClaimSuffix}}},
+					},
+				},
+			},
+		},
+	}
+	if cr.Spec.RestrictedPodSecurity {
+		restrictPodSpec(&job.Spec.Template.Spec)
+	}
+	return job
+}
+
+func (oc *OperatorContext) deleteStorageMigrationJob(podName string) error {
+	job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: podName + storageMigrationJobSuffix, Namespace: oc.MarklogicGroup.Namespace}}
+	background := metav1.DeletePropagationBackground
+	if err := oc.Client.Delete(oc.Ctx, job, &client.DeleteOptions{PropagationPolicy: &background}); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// retainVolume keeps a PersistentVolume when its claim is deleted.
+func (oc *OperatorContext) retainVolume(name string) error {
+	pv := &corev1.PersistentVolume{}
+	if err := oc.Client.Get(oc.Ctx, types.NamespacedName{Name: name}, pv); err != nil {
+		return err
+	}
+	if pv.Spec.PersistentVolumeReclaimPolicy == corev1.PersistentVolumeReclaimRetain {
+		return nil
+	}
+	patchBase := client.MergeFrom(pv.DeepCopy())
+	pv.Spec.PersistentVolumeReclaimPolicy = corev1.PersistentVolumeReclaimRetain
+	return oc.Client.Patch(oc.Ctx, pv, patchBase)
+}
+
+func jobFailed(job *batchv1.Job) bool {
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
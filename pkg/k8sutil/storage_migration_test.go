@@ -0,0 +1,160 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"testing"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func newStorageMigrationTestStatefulSet(storageClass string) *appsv1.StatefulSet {
+	return &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "dnode", Namespace: "default"},
+		Spec: appsv1.StatefulSetSpec{VolumeClaimTemplates: []corev1.PersistentVolumeClaim{{
+			ObjectMeta: metav1.ObjectMeta{Name: dataDirPVCName},
+			Spec:       corev1.PersistentVolumeClaimSpec{StorageClassName: &storageClass},
+		}}},
+	}
+}
+
+func TestReconcileStorageMigrationMovesPodsToTheNewClass(t *testing.T) {
+	group := newSecretRotationTestGroup()
+	replicas := int32(1)
+	group.Spec.Replicas = &replicas
+	group.Spec.Persistence = &marklogicv1.Persistence{Enabled: true, Size: "10Gi", StorageClassName: "fast"}
+	standard := "standard"
+	oldClaim := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "datadir-dnode-0", Namespace: "default"},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			StorageClassName: &standard,
+			VolumeName:       "pv-old",
+			AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			Resources:        corev1.VolumeResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")}},
+		},
+	}
+	oldVolume := &corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: "pv-old"},
+		Spec: corev1.PersistentVolumeSpec{PersistentVolumeReclaimPolicy: corev1.PersistentVolumeReclaimDelete}}
+	newVolume := &corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: "pv-new"},
+		Spec: corev1.PersistentVolumeSpec{
+			PersistentVolumeReclaimPolicy: corev1.PersistentVolumeReclaimDelete,
+			AccessModes:                   []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			Capacity:                      corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")},
+		}}
+	oc := newSecretRotationTestContext(t, group, newStorageMigrationTestStatefulSet(standard),
+		oldClaim, oldVolume, newVolume, newGroupTestPod("dnode-0", true))
+	key := func(name string) types.NamespacedName { return types.NamespacedName{Name: name, Namespace: "default"} }
+
+	// Without the annotation the statefulSet is left alone.
+	if res := oc.ReconcileStorageMigration(); !res.Completed() {
+		t.Fatalf("expected the statefulSet update to be held back")
+	}
+	if migration := group.Status.StorageMigration; migration == nil || migration.Phase != marklogicv1.StorageMigrationPhasePending ||
+		migration.SourceStorageClass != "standard" {
+		t.Fatalf("expected a pending migration, got %+v", migration)
+	}
+
+	// The statefulSet is created again with the new template, the pods keep
+	// their volumes.
+	group.Annotations = map[string]string{StorageMigrationAnnotation: "fast"}
+	oc.ReconcileStorageMigration()
+	if err := oc.Client.Get(oc.Ctx, key("dnode"), &appsv1.StatefulSet{}); !apierrors.IsNotFound(err) {
+		t.Fatalf("expected the statefulSet to be deleted, got %v", err)
+	}
+	if res := oc.ReconcileStorageMigration(); res.Completed() {
+		t.Fatalf("expected the statefulSet to be created again")
+	}
+	if err := oc.Client.Create(oc.Ctx, newStorageMigrationTestStatefulSet("fast")); err != nil {
+		t.Fatalf("failed to create the statefulSet: %v", err)
+	}
+
+	// The pod is stopped and its data copied to a volume of the new class.
+	oc.ReconcileStorageMigration()
+	copyClaim := &corev1.PersistentVolumeClaim{}
+	if err := oc.Client.Get(oc.Ctx, key("datadir-dnode-0-migration"), copyClaim); err != nil || *copyClaim.Spec.StorageClassName != "fast" {
+		t.Fatalf("expected the claim of the copy to be created, got %v", err)
+	}
+	if migration := group.Status.StorageMigration; migration.Pod != "dnode-0" || migration.Step != marklogicv1.StorageMigrationStepStopping {
+		t.Fatalf("expected pod dnode-0 to be stopped, got %+v", migration)
+	}
+	oc.ReconcileStorageMigration()
+	if err := oc.Client.Get(oc.Ctx, key("dnode-0"), &corev1.Pod{}); !apierrors.IsNotFound(err) {
+		t.Fatalf("expected the pod to be deleted, got %v", err)
+	}
+	oc.ReconcileStorageMigration()
+	oc.ReconcileStorageMigration()
+	job := &batchv1.Job{}
+	if err := oc.Client.Get(oc.Ctx, key("dnode-0-storage-migration"), job); err != nil {
+		t.Fatalf("expected the copy Job to be created, got %v", err)
+	}
+	job.Status.Succeeded = 1
+	if err := oc.Client.Status().Update(oc.Ctx, job); err != nil {
+		t.Fatalf("failed to update the Job: %v", err)
+	}
+	copyClaim.Spec.VolumeName = "pv-new"
+	if err := oc.Client.Update(oc.Ctx, copyClaim); err != nil {
+		t.Fatalf("failed to bind the claim of the copy: %v", err)
+	}
+
+	// The new volume takes the name of the old claim.
+	for i := 0; i < 3; i++ {
+		oc.ReconcileStorageMigration()
+	}
+	if res := oc.ReconcileStorageMigration(); res.Completed() {
+		t.Fatalf("expected the pod to be started on its new volume")
+	}
+	claim := &corev1.PersistentVolumeClaim{}
+	if err := oc.Client.Get(oc.Ctx, key("datadir-dnode-0"), claim); err != nil || claim.Spec.VolumeName != "pv-new" || *claim.Spec.StorageClassName != "fast" {
+		t.Fatalf("expected the data claim to use the new volume, got %+v, %v", claim.Spec, err)
+	}
+	for _, name := range []string{"pv-old", "pv-new"} {
+		pv := &corev1.PersistentVolume{}
+		if err := oc.Client.Get(oc.Ctx, types.NamespacedName{Name: name}, pv); err != nil || pv.Spec.PersistentVolumeReclaimPolicy != corev1.PersistentVolumeReclaimRetain {
+			t.Fatalf("expected volume %s to be retained, got %+v, %v", name, pv.Spec, err)
+		}
+	}
+	migration := group.Status.StorageMigration
+	if len(migration.MigratedPods) != 1 || len(migration.RetainedVolumes) != 1 || migration.RetainedVolumes[0] != "pv-old" || migration.Pod != "" {
+		t.Fatalf("expected pod dnode-0 to be migrated, got %+v", migration)
+	}
+
+	oc.ReconcileStorageMigration()
+	if migration := group.Status.StorageMigration; migration.Phase != marklogicv1.StorageMigrationPhaseCompleted || migration.CompletionTime == nil {
+		t.Fatalf("expected the migration to be completed, got %+v", migration)
+	}
+}
+
+func TestReconcileStorageMigrationFailedCopyKeepsTheVolume(t *testing.T) {
+	group := newSecretRotationTestGroup()
+	group.Spec.Persistence = &marklogicv1.Persistence{Enabled: true, Size: "10Gi", StorageClassName: "fast"}
+	group.Annotations = map[string]string{StorageMigrationAnnotation: "fast"}
+	group.Status.StorageMigration = &marklogicv1.StorageMigrationStatus{
+		Phase:              marklogicv1.StorageMigrationPhaseRunning,
+		TargetStorageClass: "fast",
+		Pod:                "dnode-1",
+		Step:               marklogicv1.StorageMigrationStepCopying,
+	}
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "dnode-1-storage-migration", Namespace: "default"},
+		Status:     batchv1.JobStatus{Conditions: []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue}}},
+	}
+	copyClaim := &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "datadir-dnode-1-migration", Namespace: "default"}}
+	oc := newSecretRotationTestContext(t, group, job, copyClaim)
+
+	if res := oc.ReconcileStorageMigration(); res.Completed() {
+		t.Fatalf("expected the pod to be started on its old volume")
+	}
+	if migration := group.Status.StorageMigration; migration.Phase != marklogicv1.StorageMigrationPhaseFailed || migration.Pod != "" {
+		t.Fatalf("expected the migration to fail, got %+v", migration)
+	}
+	if err := oc.Client.Get(oc.Ctx, types.NamespacedName{Name: copyClaim.Name, Namespace: "default"}, copyClaim); !apierrors.IsNotFound(err) {
+		t.Fatalf("expected the claim of the copy to be deleted, got %v", err)
+	}
+}
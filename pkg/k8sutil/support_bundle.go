@@ -0,0 +1,520 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// SupportBundleAnnotation requests a support bundle of the cluster. A
+	// bundle is collected whenever the value changes.
+	SupportBundleAnnotation = "marklogic.progress.com/support-bundle"
+
+	supportBundleReasonCollected = "SupportBundleCollected"
+	supportBundleReasonFailed    = "SupportBundleFailed"
+
+	// supportBundleMountPath is where the receiver pod mounts the claim of
+	// the bundles.
+	supportBundleMountPath     = "/support-bundles"
+	supportBundleContainerName = "receiver"
+	// supportBundlePollInterval is how often a bundle waiting for its
+	// receiver pod is checked.
+	supportBundlePollInterval = 5 * time.Second
+	// defaultSupportBundleLogLimit is how much of the end of every log is
+	// collected when logLimitBytes is not set.
+	defaultSupportBundleLogLimit = 10 << 20
+
+	marklogicLogsPath = dataDirMountPath + "/Logs"
+)
+
+// supportBundleStatusViews are the Manage API resources whose status views
+// are collected.
+var supportBundleStatusViews = []string{"hosts", "servers", "databases", "forests"}
+
+// uploadSupportBundle uploads a bundle to a pre-signed URL. It is a variable
+// so tests can replace it.
+var uploadSupportBundle = func(ctx context.Context, uploadURL string, bundle []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, uploadURL, bytes.NewReader(bundle))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/zip")
+	// Azure only accepts block blobs uploaded with a SAS URL with this header.
+	req.Header.Set("x-ms-blob-type", "BlockBlob")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("upload failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// ReconcileSupportBundle collects a support bundle of the cluster when the
+// marklogic.progress.com/support-bundle annotation is set to a value that was
+// not collected yet. The bundle is a zip of the MarkLogic error logs, the
+// status views of the Manage API, the operator log and the YAML of the
+// cluster and its resources. It is stored in spec.supportBundle, through a
+// receiver pod mounting the claim or by an upload to a pre-signed URL. Parts
+// that cannot be collected are listed in the bundle and in status.supportBundle,
+// and a bundle never holds up the rest of the reconcile.
+func (cc *ClusterContext) ReconcileSupportBundle() result.ReconcileResult {
+	cr := cc.MarklogicCluster
+	requestID := ClusterOperation(cr, SupportBundleAnnotation)
+	previous := cr.Status.SupportBundle
+	if requestID == "" || (previous != nil && previous.RequestID == requestID && previous.Phase != marklogicv1.SupportBundlePending) {
+		return result.Continue()
+	}
+
+	status := &marklogicv1.SupportBundleStatus{RequestID: requestID, Phase: marklogicv1.SupportBundlePending}
+	spec := cr.Spec.SupportBundle
+	var err error
+	switch {
+	case spec == nil:
+		err = errors.New("spec.supportBundle is not set")
+	case spec.PersistentVolumeClaim != "":
+		var running bool
+		running, err = cc.supportBundleReceiver(spec.PersistentVolumeClaim)
+		if err == nil && !running {
+			status.Message = fmt.Sprintf("waiting for pod %s to mount claim %s", supportBundleReceiverName(cr), spec.PersistentVolumeClaim)
+			break
+		}
+		if err == nil {
+			err = cc.storeSupportBundle(status, func(name string, bundle []byte) (string, error) {
+				file := path.Join(supportBundleMountPath, name)
+				command := []string{"sh", "-c", fmt.Sprintf("cat > '%[1]s.part' && mv '%[1]s.part' '%[1]s'", file)}
+				if err := StreamToPod(cc.Ctx, cr.Namespace, supportBundleReceiverName(cr), supportBundleContainerName, command, bytes.NewReader(bundle)); err != nil {
+					return "", err
+				}
+				return fmt.Sprintf("%s:%s", spec.PersistentVolumeClaim, name), nil
+			})
+		}
+		if deleteErr := cc.deleteSupportBundleReceiver(); deleteErr != nil {
+			cc.ReqLogger.Error(deleteErr, "Failed to delete the support bundle receiver pod")
+		}
+	default:
+		var uploadURL string
+		uploadURL, err = cc.secretURL(spec.UploadURLSecret, "upload URL")
+		if err == nil {
+			err = cc.storeSupportBundle(status, func(name string, bundle []byte) (string, error) {
+				if err := uploadSupportBundle(cc.Ctx, uploadURL, bundle); err != nil {
+					return "", err
+				}
+				return redactedURL(uploadURL), nil
+			})
+		}
+	}
+	if err != nil {
+		status.Phase = marklogicv1.SupportBundleFailed
+		status.Message = err.Error()
+		cc.ReqLogger.Error(err, "Failed to collect the support bundle")
+		cc.recordClusterEvent(corev1.EventTypeWarning, supportBundleReasonFailed, fmt.Sprintf("Failed to collect the support bundle: %v", err))
+	}
+
+	patchBase := client.MergeFrom(cr.DeepCopy())
+	cr.Status.SupportBundle = status
+	if err := patchStatus(cc.Ctx, cc.Client, cr, patchBase); err != nil {
+		cc.ReqLogger.Error(err, "Failed to update the support bundle status")
+	}
+	return result.Continue()
+}
+
+// storeSupportBundle collects the bundle and stores it with store, which
+// returns its location.
+func (cc *ClusterContext) storeSupportBundle(status *marklogicv1.SupportBundleStatus, store func(name string, bundle []byte) (string, error)) error {
+	cr := cc.MarklogicCluster
+	now := metav1.Now()
+	bundle, problems, err := cc.collectSupportBundle(now.Time)
+	if err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%s-%s.zip", clusterFullname(cr), now.UTC().Format("20060102-150405"))
+	location, err := store(name, bundle)
+	if err != nil {
+		return fmt.Errorf("failed to store the support bundle: %w", err)
+	}
+	status.Phase = marklogicv1.SupportBundleCompleted
+	status.Location = location
+	status.SizeBytes = int64(len(bundle))
+	status.CollectionTime = &now
+	if len(problems) > 0 {
+		status.Message = fmt.Sprintf("%d part(s) could not be collected, see errors.txt", len(problems))
+	}
+	cc.ReqLogger.Info("Collected the support bundle", "location", location, "size", len(bundle))
+	cc.recordClusterEvent(corev1.EventTypeNormal, supportBundleReasonCollected, fmt.Sprintf("Stored the support bundle at %s", location))
+	return nil
+}
+
+// collectSupportBundle zips the parts of the bundle. Parts that cannot be
+// collected are listed in errors.txt and returned.
+func (cc *ClusterContext) collectSupportBundle(now time.Time) ([]byte, []string, error) {
+	cr := cc.MarklogicCluster
+	limit := int64(defaultSupportBundleLogLimit)
+	if cr.Spec.SupportBundle != nil && cr.Spec.SupportBundle.LogLimitBytes > 0 {
+		limit = cr.Spec.SupportBundle.LogLimitBytes
+	}
+	var buffer bytes.Buffer
+	archive := zip.NewWriter(&buffer)
+	problems := []string{}
+	add := func(name string, data []byte) error {
+		w, err := archive.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: now})
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	}
+	addYAML := func(name string, obj any) error {
+		data, err := yaml.Marshal(obj)
+		if err != nil {
+			return err
+		}
+		return add(name, data)
+	}
+
+	objects, pods, err := cc.supportBundleObjects()
+	if err != nil {
+		problems = append(problems, fmt.Sprintf("resources: %v", err))
+	}
+	for _, obj := range objects {
+		if err := addYAML(path.Join("resources", obj.kind, obj.object.GetName()+".yaml"), obj.object); err != nil {
+			return nil, nil, err
+		}
+	}
+	events := &corev1.EventList{}
+	if err := cc.Client.List(cc.Ctx, events, client.InNamespace(cr.Namespace)); err != nil {
+		problems = append(problems, fmt.Sprintf("events: %v", err))
+	} else if err := addYAML("resources/events.yaml", events.Items); err != nil {
+		return nil, nil, err
+	}
+
+	for _, pod := range pods {
+		if pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		logs, err := cc.marklogicErrorLogs(pod.Name, limit)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("error logs of pod %s: %v", pod.Name, err))
+		}
+		for _, name := range sortedKeys(logs) {
+			if err := add(path.Join("marklogic", "logs", pod.Name, name), []byte(logs[name])); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	if clusterStopped(cr) {
+		problems = append(problems, "status views: the cluster is stopped")
+	} else if mgmtClient, err := cc.newBootstrapManagementClient(); err != nil {
+		problems = append(problems, fmt.Sprintf("status views: %v", err))
+	} else {
+		for _, view := range supportBundleStatusViews {
+			data, err := mgmtClient.GetStatusView(cc.Ctx, view)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("status view of %s: %v", view, err))
+				continue
+			}
+			if err := add(path.Join("marklogic", "status", view+".json"), data); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	operatorPod, operatorNamespace := os.Getenv("HOSTNAME"), supportBundleOperatorNamespace()
+	if operatorPod == "" || operatorNamespace == "" {
+		problems = append(problems, "operator log: the pod of the operator is not known")
+	} else if logs, err := PodLogs(cc.Ctx, operatorNamespace, operatorPod, "", limit); err != nil {
+		problems = append(problems, fmt.Sprintf("operator log: %v", err))
+	} else if err := add(path.Join("operator", operatorPod+".log"), []byte(logs)); err != nil {
+		return nil, nil, err
+	}
+
+	if len(problems) > 0 {
+		if err := add("errors.txt", []byte(strings.Join(problems, "\n")+"\n")); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, nil, err
+	}
+	return buffer.Bytes(), problems, nil
+}
+
+type supportBundleObject struct {
+	kind   string
+	object client.Object
+}
+
+// supportBundleObjects returns the cluster, the resources owned by it or by
+// one of its MarklogicGroups, the pods of its groups and their claims.
+// Secrets are collected without their values, and server-set fields that do
+// not help support are dropped.
+func (cc *ClusterContext) supportBundleObjects() ([]supportBundleObject, []corev1.Pod, error) {
+	cr := cc.MarklogicCluster
+	inNamespace := client.InNamespace(cr.Namespace)
+	objects := []supportBundleObject{{kind: "MarklogicCluster", object: cr.DeepCopy()}}
+	owners := map[types.UID]bool{cr.UID: true}
+
+	groups := &marklogicv1.MarklogicGroupList{}
+	if err := cc.Client.List(cc.Ctx, groups, inNamespace); err != nil {
+		return objects, nil, err
+	}
+	for i := range groups.Items {
+		if ownedByAny(&groups.Items[i], owners) {
+			owners[groups.Items[i].UID] = true
+			objects = append(objects, supportBundleObject{kind: "MarklogicGroup", object: &groups.Items[i]})
+		}
+	}
+	for kind, list := range map[string]client.ObjectList{
+		"StatefulSet": &appsv1.StatefulSetList{},
+		"Deployment":  &appsv1.DeploymentList{},
+		"Service":     &corev1.ServiceList{},
+		"ConfigMap":   &corev1.ConfigMapList{},
+		"Secret":      &corev1.SecretList{},
+		"Ingress":     &networkingv1.IngressList{},
+	} {
+		if err := cc.Client.List(cc.Ctx, list, inNamespace); err != nil {
+			return objects, nil, err
+		}
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			return objects, nil, err
+		}
+		for _, item := range items {
+			obj, ok := item.(client.Object)
+			if !ok || !ownedByAny(obj, owners) {
+				continue
+			}
+			if secret, ok := obj.(*corev1.Secret); ok {
+				obj = redactedSecret(secret)
+			}
+			objects = append(objects, supportBundleObject{kind: kind, object: obj})
+		}
+	}
+
+	pods := []corev1.Pod{}
+	for _, group := range cr.Spec.MarkLogicGroups {
+		if group == nil {
+			continue
+		}
+		groupPods := &corev1.PodList{}
+		if err := cc.Client.List(cc.Ctx, groupPods, inNamespace, client.MatchingLabels{
+			"app.kubernetes.io/name":     "marklogic",
+			"app.kubernetes.io/instance": group.Name,
+		}); err != nil {
+			return objects, pods, err
+		}
+		pods = append(pods, groupPods.Items...)
+	}
+	for i := range pods {
+		objects = append(objects, supportBundleObject{kind: "Pod", object: &pods[i]})
+		for _, volume := range pods[i].Spec.Volumes {
+			if volume.PersistentVolumeClaim == nil {
+				continue
+			}
+			claim := &corev1.PersistentVolumeClaim{}
+			err := cc.Client.Get(cc.Ctx, types.NamespacedName{Name: volume.PersistentVolumeClaim.ClaimName, Namespace: cr.Namespace}, claim)
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return objects, pods, err
+			}
+			objects = append(objects, supportBundleObject{kind: "PersistentVolumeClaim", object: claim})
+		}
+	}
+	for _, obj := range objects {
+		obj.object.SetManagedFields(nil)
+	}
+	sort.SliceStable(objects, func(i, j int) bool {
+		if objects[i].kind != objects[j].kind {
+			return objects[i].kind < objects[j].kind
+		}
+		return objects[i].object.GetName() < objects[j].object.GetName()
+	})
+	return objects, pods, nil
+}
+
+// marklogicErrorLogs returns the end of the current error logs of a
+// MarkLogic pod, by file name. Rotated logs are left out.
+func (cc *ClusterContext) marklogicErrorLogs(podName string, limit int64) (map[string]string, error) {
+	namespace := cc.MarklogicCluster.Namespace
+	output, err := ExecInPod(cc.Ctx, namespace, podName, "marklogic-server", []string{"ls", marklogicLogsPath})
+	if err != nil {
+		return nil, err
+	}
+	logs := map[string]string{}
+	var errs []error
+	for _, name := range strings.Fields(output) {
+		if !strings.HasSuffix(name, "ErrorLog.txt") {
+			continue
+		}
+		content, err := ExecInPod(cc.Ctx, namespace, podName, "marklogic-server", []string{"tail", "-c", fmt.Sprint(limit), path.Join(marklogicLogsPath, name)})
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		logs[name] = content
+	}
+	return logs, errors.Join(errs...)
+}
+
+// supportBundleReceiver makes sure the pod mounting the claim of the bundles
+// exists, and reports whether it runs.
+func (cc *ClusterContext) supportBundleReceiver(claimName string) (bool, error) {
+	cr := cc.MarklogicCluster
+	pod := &corev1.Pod{}
+	err := cc.Client.Get(cc.Ctx, types.NamespacedName{Name: supportBundleReceiverName(cr), Namespace: cr.Namespace}, pod)
+	if apierrors.IsNotFound(err) {
+		return false, cc.Client.Create(cc.Ctx, cc.generateSupportBundleReceiver(claimName))
+	}
+	if err != nil {
+		return false, err
+	}
+	if pod.Status.Phase == corev1.PodFailed || pod.Status.Phase == corev1.PodSucceeded {
+		return false, fmt.Errorf("pod %s stopped before the bundle was stored", pod.Name)
+	}
+	return pod.Status.Phase == corev1.PodRunning, nil
+}
+
+func (cc *ClusterContext) generateSupportBundleReceiver(claimName string) *corev1.Pod {
+	cr := cc.MarklogicCluster
+	labels := withClusterNameLabel(cc.GetClusterLabels(cr.Name), cr.Name)
+	labels["app.kubernetes.io/component"] = "support-bundle"
+	pod := &corev1.Pod{
+		ObjectMeta: generateObjectMeta(supportBundleReceiverName(cr), cr.Namespace, labels, cc.GetClusterAnnotations()),
+		Spec: corev1.PodSpec{
+			RestartPolicy:    corev1.RestartPolicyNever,
+			ImagePullSecrets: cr.Spec.ImagePullSecrets,
+			SecurityContext:  cr.Spec.PodSecurityContext,
+			// The pod stops by itself should the operator not come back to it.
+			ActiveDeadlineSeconds: int64Ptr(int64(time.Hour / time.Second)),
+			Containers: []corev1.Container{{
+				Name:            supportBundleContainerName,
+				Image:           cr.Spec.Image,
+				ImagePullPolicy: corev1.PullPolicy(cr.Spec.ImagePullPolicy),
+				Command:         []string{"sleep", "3600"},
+				SecurityContext: cr.Spec.ContainerSecurityContext,
+				VolumeMounts:    []corev1.VolumeMount{{Name: "support-bundles", MountPath: supportBundleMountPath}},
+			}},
+			Volumes: []corev1.Volume{{
+				Name: "support-bundles",
+				VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: claimName,
+				}},
+			}},
+		},
+	}
+	AddOwnerRefToObject(pod, marklogicClusterAsOwner(cr))
+	return pod
+}
+
+func (cc *ClusterContext) deleteSupportBundleReceiver() error {
+	cr := cc.MarklogicCluster
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: supportBundleReceiverName(cr), Namespace: cr.Namespace}}
+	return client.IgnoreNotFound(cc.Client.Delete(cc.Ctx, pod))
+}
+
+// secretURL reads a URL kept in a Secret, such as the upload URL of the
+// support bundles. what names the URL in the errors.
+func (cc *ClusterContext) secretURL(selector *corev1.SecretKeySelector, what string) (string, error) {
+	secret, err := cc.getSecret(selector.Name)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the %s: %w", what, err)
+	}
+	value := strings.TrimSpace(string(secret.Data[selector.Key]))
+	if value == "" {
+		return "", fmt.Errorf("secret %s has no %s in key %s", selector.Name, what, selector.Key)
+	}
+	return value, nil
+}
+
+// supportBundleOperatorNamespace returns the namespace the operator runs in,
+// from POD_NAMESPACE or the service account namespace file. The pod name is
+// the HOSTNAME Kubernetes sets.
+func supportBundleOperatorNamespace() string {
+	if namespace := strings.TrimSpace(os.Getenv("POD_NAMESPACE")); namespace != "" {
+		return namespace
+	}
+	data, err := os.ReadFile("/var/run/secrets/kubernetes.io/serviceaccount/namespace")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+func supportBundleReceiverName(cr *marklogicv1.MarklogicCluster) string {
+	return clusterFullname(cr) + "-support-bundle"
+}
+
+// nextSupportBundleCheck returns when a bundle waiting for its receiver pod
+// is checked again, or the zero time.
+func nextSupportBundleCheck(cr *marklogicv1.MarklogicCluster) time.Time {
+	status := cr.Status.SupportBundle
+	if status == nil || status.Phase != marklogicv1.SupportBundlePending {
+		return time.Time{}
+	}
+	return time.Now().Add(supportBundlePollInterval)
+}
+
+// redactedSecret returns the Secret with the names of its keys only.
+func redactedSecret(secret *corev1.Secret) *corev1.Secret {
+	redacted := secret.DeepCopy()
+	redacted.Data = map[string][]byte{}
+	for key := range secret.Data {
+		redacted.Data[key] = nil
+	}
+	redacted.StringData = nil
+	delete(redacted.Annotations, lastAppliedConfigAnnotation)
+	return redacted
+}
+
+// redactedURL drops the query of a pre-signed URL, which holds its signature.
+func redactedURL(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	parsed.RawQuery = ""
+	parsed.User = nil
+	return parsed.String()
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,222 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func newSupportBundleTestCluster(supportBundle *marklogicv1.SupportBundle) (*marklogicv1.MarklogicCluster, []client.Object) {
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "ml",
+			Namespace:   "default",
+			UID:         "ml-uid",
+			Annotations: map[string]string{SupportBundleAnnotation: "case-1234"},
+		},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			Image:           upgradeTestOldImage,
+			ClusterDomain:   "cluster.local",
+			SupportBundle:   supportBundle,
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{{Name: "dnode", IsBootstrap: true}},
+		},
+	}
+	owner := []metav1.OwnerReference{{APIVersion: marklogicv1.GroupVersion.String(), Kind: "MarklogicCluster", Name: "ml", UID: "ml-uid"}}
+	objects := []client.Object{
+		&appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "dnode", Namespace: "default", OwnerReferences: owner}},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "ml-tls", Namespace: "default", OwnerReferences: owner},
+			Data:       map[string][]byte{"tls.key": []byte("private key")},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "dnode-0", Namespace: "default", Labels: map[string]string{
+				"app.kubernetes.io/name":     "marklogic",
+				"app.kubernetes.io/instance": "dnode",
+			}},
+			Spec: corev1.PodSpec{Volumes: []corev1.Volume{{Name: "datadir", VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "datadir-dnode-0"},
+			}}}},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		},
+		&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "datadir-dnode-0", Namespace: "default"}},
+	}
+	return cr, objects
+}
+
+func stubSupportBundleSources(t *testing.T) {
+	t.Helper()
+	t.Setenv("HOSTNAME", "marklogic-operator-abc")
+	t.Setenv("POD_NAMESPACE", "marklogic-operator-system")
+	originalExec, originalLogs, originalClient := ExecInPod, PodLogs, NewDynamicManagementClient
+	ExecInPod = func(ctx context.Context, namespace, podName, container string, command []string) (string, error) {
+		if command[0] == "ls" {
+			return "8001_ErrorLog.txt\nAccessLog.txt\nErrorLog.txt\nErrorLog_1.txt\n", nil
+		}
+		return "error log of " + command[len(command)-1], nil
+	}
+	PodLogs = func(ctx context.Context, namespace, podName, container string, limitBytes int64) (string, error) {
+		return "operator log", nil
+	}
+	NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+		return &stubDynamicManagementClient{statusViewFn: func(resource string) ([]byte, error) {
+			return []byte(`{"` + resource + `-status":{}}`), nil
+		}}
+	}
+	t.Cleanup(func() {
+		ExecInPod, PodLogs, NewDynamicManagementClient = originalExec, originalLogs, originalClient
+	})
+}
+
+func readSupportBundle(t *testing.T, bundle []byte) map[string]string {
+	t.Helper()
+	archive, err := zip.NewReader(bytes.NewReader(bundle), int64(len(bundle)))
+	if err != nil {
+		t.Fatalf("failed to read the bundle: %v", err)
+	}
+	files := map[string]string{}
+	for _, file := range archive.File {
+		r, err := file.Open()
+		if err != nil {
+			t.Fatalf("failed to open %s: %v", file.Name, err)
+		}
+		data, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", file.Name, err)
+		}
+		files[file.Name] = string(data)
+	}
+	return files
+}
+
+func TestReconcileSupportBundleUploadsToThePresignedURL(t *testing.T) {
+	cr, objects := newSupportBundleTestCluster(&marklogicv1.SupportBundle{
+		UploadURLSecret: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "support-upload"}, Key: "url"},
+	})
+	uploadSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "support-upload", Namespace: "default"},
+		Data:       map[string][]byte{"url": []byte("https://bucket.s3.amazonaws.com/case-1234.zip?X-Amz-Signature=secret")},
+	}
+	cc := newUpgradeTestContext(t, cr, append(objects, uploadSecret)...)
+	stubSupportBundleSources(t)
+	var uploaded []byte
+	originalUpload := uploadSupportBundle
+	uploadSupportBundle = func(ctx context.Context, uploadURL string, bundle []byte) error {
+		if !strings.HasSuffix(uploadURL, "X-Amz-Signature=secret") {
+			t.Fatalf("expected the pre-signed URL, got %s", uploadURL)
+		}
+		uploaded = bundle
+		return nil
+	}
+	t.Cleanup(func() { uploadSupportBundle = originalUpload })
+
+	if res := cc.ReconcileSupportBundle(); res.Completed() {
+		t.Fatalf("expected the support bundle never to hold up the reconcile")
+	}
+	status := cr.Status.SupportBundle
+	if status == nil || status.Phase != marklogicv1.SupportBundleCompleted || status.RequestID != "case-1234" {
+		t.Fatalf("expected the bundle to be collected, got %+v", status)
+	}
+	if status.Location != "https://bucket.s3.amazonaws.com/case-1234.zip" || status.Message != "" {
+		t.Fatalf("expected the location without the signature, got %+v", status)
+	}
+	files := readSupportBundle(t, uploaded)
+	for _, name := range []string{
+		"resources/MarklogicCluster/ml.yaml",
+		"resources/StatefulSet/dnode.yaml",
+		"resources/Pod/dnode-0.yaml",
+		"resources/PersistentVolumeClaim/datadir-dnode-0.yaml",
+		"resources/events.yaml",
+		"marklogic/logs/dnode-0/ErrorLog.txt",
+		"marklogic/logs/dnode-0/8001_ErrorLog.txt",
+		"marklogic/status/hosts.json",
+		"marklogic/status/forests.json",
+		"operator/marklogic-operator-abc.log",
+	} {
+		if _, ok := files[name]; !ok {
+			t.Fatalf("expected %s in the bundle, got %v", name, sortedKeys(files))
+		}
+	}
+	if _, ok := files["marklogic/logs/dnode-0/ErrorLog_1.txt"]; ok {
+		t.Fatalf("expected rotated logs to be left out")
+	}
+	if secret := files["resources/Secret/ml-tls.yaml"]; secret == "" || strings.Contains(secret, "cHJpdmF0ZSBrZXk=") {
+		t.Fatalf("expected the Secret without its values, got %q", secret)
+	}
+
+	uploaded = nil
+	cc.ReconcileSupportBundle()
+	if uploaded != nil {
+		t.Fatalf("expected a bundle to be collected once per request")
+	}
+}
+
+func TestReconcileSupportBundleStoresInTheClaim(t *testing.T) {
+	cr, objects := newSupportBundleTestCluster(&marklogicv1.SupportBundle{PersistentVolumeClaim: "support-bundles"})
+	cc := newUpgradeTestContext(t, cr, objects...)
+	stubSupportBundleSources(t)
+	var written []byte
+	var writeCommand []string
+	originalStream := StreamToPod
+	StreamToPod = func(ctx context.Context, namespace, podName, container string, command []string, stdin io.Reader) error {
+		writeCommand = command
+		data, err := io.ReadAll(stdin)
+		written = data
+		return err
+	}
+	t.Cleanup(func() { StreamToPod = originalStream })
+
+	cc.ReconcileSupportBundle()
+	receiver := &corev1.Pod{}
+	if err := cc.Client.Get(cc.Ctx, types.NamespacedName{Name: "ml-support-bundle", Namespace: "default"}, receiver); err != nil {
+		t.Fatalf("expected the receiver pod to be created: %v", err)
+	}
+	if claim := receiver.Spec.Volumes[0].PersistentVolumeClaim; claim == nil || claim.ClaimName != "support-bundles" {
+		t.Fatalf("expected the receiver to mount the claim, got %+v", receiver.Spec.Volumes)
+	}
+	if status := cr.Status.SupportBundle; status.Phase != marklogicv1.SupportBundlePending || nextSupportBundleCheck(cr).IsZero() {
+		t.Fatalf("expected to wait for the receiver pod, got %+v", status)
+	}
+
+	receiver.Status.Phase = corev1.PodRunning
+	if err := cc.Client.Status().Update(cc.Ctx, receiver); err != nil {
+		t.Fatalf("failed to update pod status: %v", err)
+	}
+	cc.ReconcileSupportBundle()
+	status := cr.Status.SupportBundle
+	if status.Phase != marklogicv1.SupportBundleCompleted || !strings.HasPrefix(status.Location, "support-bundles:ml-") || status.SizeBytes != int64(len(written)) {
+		t.Fatalf("expected the bundle to be stored in the claim, got %+v", status)
+	}
+	if !strings.Contains(writeCommand[len(writeCommand)-1], "/support-bundles/ml-") {
+		t.Fatalf("expected the bundle to be written to the mount of the claim, got %v", writeCommand)
+	}
+	if files := readSupportBundle(t, written); files["operator/marklogic-operator-abc.log"] != "operator log" {
+		t.Fatalf("expected the operator log in the bundle, got %v", sortedKeys(files))
+	}
+	err := cc.Client.Get(cc.Ctx, types.NamespacedName{Name: "ml-support-bundle", Namespace: "default"}, receiver)
+	if !apierrors.IsNotFound(err) {
+		t.Fatalf("expected the receiver pod to be deleted, got %v", err)
+	}
+}
+
+func TestReconcileSupportBundleFailsWithoutStorage(t *testing.T) {
+	cr, _ := newSupportBundleTestCluster(nil)
+	cc := newUpgradeTestContext(t, cr)
+	cc.ReconcileSupportBundle()
+	if status := cr.Status.SupportBundle; status.Phase != marklogicv1.SupportBundleFailed || !strings.Contains(status.Message, "spec.supportBundle") {
+		t.Fatalf("expected the bundle to fail, got %+v", status)
+	}
+}
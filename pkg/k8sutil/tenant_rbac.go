@@ -0,0 +1,164 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// TenantGroupsAnnotation on a Namespace lists the comma separated RBAC
+	// groups of the app teams that may view its MarkLogic clusters and
+	// approve their upgrades.
+	TenantGroupsAnnotation = "marklogic.progress.com/tenant-groups"
+
+	// TenantRBACName is the name of the Role and RoleBinding generated for
+	// the tenant groups of a namespace.
+	TenantRBACName = "marklogic-tenant"
+)
+
+// tenantRBACLabels mark the Roles and RoleBindings the operator generates,
+// so objects of the same name created by others are never changed.
+func tenantRBACLabels() map[string]string {
+	return map[string]string{
+		"app.kubernetes.io/name":       "marklogic",
+		"app.kubernetes.io/component":  "tenant-rbac",
+		"app.kubernetes.io/managed-by": "marklogic-operator",
+	}
+}
+
+// tenantGroups returns the sorted groups of the TenantGroupsAnnotation.
+func tenantGroups(ns *corev1.Namespace) []string {
+	groups := []string{}
+	for _, group := range strings.Split(ns.Annotations[TenantGroupsAnnotation], ",") {
+		if group = strings.TrimSpace(group); group != "" && !slices.Contains(groups, group) {
+			groups = append(groups, group)
+		}
+	}
+	slices.Sort(groups)
+	return groups
+}
+
+// tenantRoleRules grant what app teams need on their clusters: reading the
+// clusters and groups, and patching a cluster to set the
+// UpgradeApprovalAnnotation. With webhooks enabled, users who cannot update
+// MarklogicClusters are limited to that annotation.
+func tenantRoleRules() []rbacv1.PolicyRule {
+	return []rbacv1.PolicyRule{
+		{
+			APIGroups: []string{"marklogic.progress.com"},
+			Resources: []string{"marklogicclusters", "marklogicgroups"},
+			Verbs:     []string{"get", "list", "watch"},
+		},
+		{
+			APIGroups: []string{"marklogic.progress.com"},
+			Resources: []string{"marklogicclusters/status", "marklogicgroups/status"},
+			Verbs:     []string{"get"},
+		},
+		{
+			APIGroups: []string{"marklogic.progress.com"},
+			Resources: []string{"marklogicclusters"},
+			Verbs:     []string{"patch"},
+		},
+	}
+}
+
+// ReconcileTenantRBAC generates the marklogic-tenant Role and RoleBinding in
+// a namespace with the TenantGroupsAnnotation and removes them once the
+// annotation is gone.
+func ReconcileTenantRBAC(ctx context.Context, c client.Client, ns *corev1.Namespace) error {
+	if ns.DeletionTimestamp != nil {
+		return nil
+	}
+	groups := tenantGroups(ns)
+	if len(groups) == 0 {
+		return deleteTenantRBAC(ctx, c, ns.Name)
+	}
+
+	role := &rbacv1.Role{ObjectMeta: metav1.ObjectMeta{Name: TenantRBACName, Namespace: ns.Name}}
+	if _, err := controllerutil.CreateOrUpdate(ctx, c, role, func() error {
+		if err := claimTenantRBAC(role); err != nil {
+			return err
+		}
+		role.Rules = tenantRoleRules()
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to apply Role %s/%s: %w", ns.Name, TenantRBACName, err)
+	}
+
+	binding := &rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: TenantRBACName, Namespace: ns.Name}}
+	if _, err := controllerutil.CreateOrUpdate(ctx, c, binding, func() error {
+		if err := claimTenantRBAC(binding); err != nil {
+			return err
+		}
+		binding.RoleRef = rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: TenantRBACName}
+		binding.Subjects = make([]rbacv1.Subject, 0, len(groups))
+		for _, group := range groups {
+			binding.Subjects = append(binding.Subjects, rbacv1.Subject{APIGroup: rbacv1.GroupName, Kind: rbacv1.GroupKind, Name: group})
+		}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to apply RoleBinding %s/%s: %w", ns.Name, TenantRBACName, err)
+	}
+	return nil
+}
+
+// claimTenantRBAC labels a new object as generated and refuses to change an
+// existing one the operator did not generate.
+func claimTenantRBAC(obj client.Object) error {
+	labels := obj.GetLabels()
+	if obj.GetResourceVersion() == "" {
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		for k, v := range tenantRBACLabels() {
+			labels[k] = v
+		}
+		obj.SetLabels(labels)
+		return nil
+	}
+	if !generatedTenantRBAC(obj) {
+		return fmt.Errorf("%s/%s exists and was not generated by the operator", obj.GetNamespace(), obj.GetName())
+	}
+	return nil
+}
+
+func generatedTenantRBAC(obj client.Object) bool {
+	labels := obj.GetLabels()
+	for k, v := range tenantRBACLabels() {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+func deleteTenantRBAC(ctx context.Context, c client.Client, namespace string) error {
+	key := types.NamespacedName{Name: TenantRBACName, Namespace: namespace}
+	for _, obj := range []client.Object{&rbacv1.RoleBinding{}, &rbacv1.Role{}} {
+		if err := c.Get(ctx, key, obj); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		if !generatedTenantRBAC(obj) {
+			continue
+		}
+		if err := c.Delete(ctx, obj); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconcileTenantRBAC(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:        "team-a",
+		Annotations: map[string]string{TenantGroupsAnnotation: "team-a-devs, team-a-ops,team-a-devs"},
+	}}
+	other := &rbacv1.Role{ObjectMeta: metav1.ObjectMeta{Name: TenantRBACName, Namespace: "team-b"}}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(ns, other).Build()
+	ctx := context.Background()
+	key := types.NamespacedName{Name: TenantRBACName, Namespace: "team-a"}
+
+	if err := ReconcileTenantRBAC(ctx, c, ns); err != nil {
+		t.Fatalf("ReconcileTenantRBAC: %v", err)
+	}
+	role := &rbacv1.Role{}
+	if err := c.Get(ctx, key, role); err != nil {
+		t.Fatalf("expected the tenant Role: %v", err)
+	}
+	for _, rule := range role.Rules {
+		for _, verb := range rule.Verbs {
+			if verb != "get" && verb != "list" && verb != "watch" && verb != "patch" {
+				t.Fatalf("expected read and patch verbs only, got %v", rule)
+			}
+		}
+	}
+	binding := &rbacv1.RoleBinding{}
+	if err := c.Get(ctx, key, binding); err != nil {
+		t.Fatalf("expected the tenant RoleBinding: %v", err)
+	}
+	if len(binding.Subjects) != 2 || binding.Subjects[0].Name != "team-a-devs" || binding.Subjects[1].Name != "team-a-ops" ||
+		binding.Subjects[0].Kind != rbacv1.GroupKind || binding.RoleRef.Name != TenantRBACName {
+		t.Fatalf("expected the two groups bound to the tenant Role, got %+v", binding)
+	}
+
+	delete(ns.Annotations, TenantGroupsAnnotation)
+	if err := ReconcileTenantRBAC(ctx, c, ns); err != nil {
+		t.Fatalf("ReconcileTenantRBAC: %v", err)
+	}
+	if err := c.Get(ctx, key, &rbacv1.Role{}); !apierrors.IsNotFound(err) {
+		t.Fatalf("expected the Role to be removed with the annotation, got %v", err)
+	}
+	if err := c.Get(ctx, key, &rbacv1.RoleBinding{}); !apierrors.IsNotFound(err) {
+		t.Fatalf("expected the RoleBinding to be removed with the annotation, got %v", err)
+	}
+
+	teamB := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:        "team-b",
+		Annotations: map[string]string{TenantGroupsAnnotation: "team-b-devs"},
+	}}
+	if err := ReconcileTenantRBAC(ctx, c, teamB); err == nil {
+		t.Fatalf("expected a Role the operator did not generate to be left alone")
+	}
+	delete(teamB.Annotations, TenantGroupsAnnotation)
+	if err := ReconcileTenantRBAC(ctx, c, teamB); err != nil {
+		t.Fatalf("ReconcileTenantRBAC: %v", err)
+	}
+	if err := c.Get(ctx, types.NamespacedName{Name: TenantRBACName, Namespace: "team-b"}, &rbacv1.Role{}); err != nil {
+		t.Fatalf("expected a Role the operator did not generate to be kept, got %v", err)
+	}
+}
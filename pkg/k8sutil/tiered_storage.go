@@ -0,0 +1,172 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// storageTierMountPath is where the volumes of the storage tiers of a
+	// group are mounted, one directory per tier.
+	storageTierMountPath = "/var/opt/MarkLogic/tiers/"
+
+	// tieredStorageCheckInterval is how often the partitions are checked
+	// against spec.tieredStorage.
+	tieredStorageCheckInterval = 15 * time.Minute
+
+	tieringReasonPartitionMigrated = "PartitionMigrated"
+	tieringReasonMigrationFailed   = "PartitionMigrationFailed"
+)
+
+// partitionBoundLayouts are the lexical forms of the xs:dateTime and xs:date
+// range bounds of a partition.
+var partitionBoundLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02Z07:00", "2006-01-02"}
+
+// ReconcileTieredStorage migrates every tieredStorageCheckInterval the range
+// partitions whose range ended longer ago than a policy of
+// spec.tieredStorage to the data directory of the policy. MarkLogic moves
+// the forests in the background, and a partition already in its directory
+// is left alone. Failures are reported in status.tieredStorage and never
+// hold up the rest of the reconcile.
+func (cc *ClusterContext) ReconcileTieredStorage() result.ReconcileResult {
+	cr := cc.MarklogicCluster
+	if cr.Spec.TieredStorage == nil || len(cr.Spec.TieredStorage.Policies) == 0 || clusterStopped(cr) {
+		return result.Continue()
+	}
+	now := metav1.Now()
+	if status := cr.Status.TieredStorage; status != nil && status.LastCheckTime != nil && now.Sub(status.LastCheckTime.Time) < tieredStorageCheckInterval {
+		return result.Continue()
+	}
+
+	status := &marklogicv1.TieredStorageStatus{LastCheckTime: &now}
+	if cr.Status.TieredStorage != nil {
+		status.Partitions = append(status.Partitions, cr.Status.TieredStorage.Partitions...)
+	}
+	policies := map[string][]marklogicv1.PartitionMigrationPolicy{}
+	databases := []string{}
+	for _, policy := range cr.Spec.TieredStorage.Policies {
+		if policies[policy.Database] == nil {
+			databases = append(databases, policy.Database)
+		}
+		policies[policy.Database] = append(policies[policy.Database], policy)
+	}
+	problems := []string{}
+	mgmtClient, err := cc.newBootstrapManagementClient()
+	if err != nil {
+		problems = append(problems, err.Error())
+		databases = nil
+	}
+	for _, database := range databases {
+		partitions, err := mgmtClient.ListDatabasePartitions(cc.Ctx, database)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("failed to list the partitions of database %s: %v", database, err))
+			continue
+		}
+		for _, partition := range partitions {
+			policy := partitionPolicy(policies[database], partition, now.Time)
+			if policy == nil || sameDirectory(partition.DataDirectory, policy.DataDirectory) {
+				continue
+			}
+			// A migration already started is not started again while
+			// MarkLogic moves the forests.
+			if migrated := partitionTierStatus(status.Partitions, database, partition.Name); migrated != nil && sameDirectory(migrated.DataDirectory, policy.DataDirectory) {
+				continue
+			}
+			if err := mgmtClient.MigratePartition(cc.Ctx, database, partition.Name, policy.DataDirectory); err != nil {
+				message := fmt.Sprintf("failed to migrate partition %s of database %s to %s: %v", partition.Name, database, policy.DataDirectory, err)
+				problems = append(problems, message)
+				cc.recordClusterEvent(corev1.EventTypeWarning, tieringReasonMigrationFailed, message)
+				continue
+			}
+			cc.ReqLogger.Info("Migrating partition", "database", database, "partition", partition.Name, "dataDirectory", policy.DataDirectory)
+			cc.recordClusterEvent(corev1.EventTypeNormal, tieringReasonPartitionMigrated,
+				fmt.Sprintf("Migrating partition %s of database %s, which ended %s, to %s", partition.Name, database, partition.UpperBound, policy.DataDirectory))
+			status.Partitions = setPartitionTierStatus(status.Partitions, marklogicv1.PartitionTierStatus{
+				Database:      database,
+				Partition:     partition.Name,
+				DataDirectory: policy.DataDirectory,
+				MigrationTime: &now,
+			})
+		}
+	}
+	status.Message = strings.Join(problems, "; ")
+	if len(problems) > 0 {
+		cc.ReqLogger.Info("Tiered storage check incomplete", "problems", status.Message)
+	}
+
+	patchBase := client.MergeFrom(cr.DeepCopy())
+	cr.Status.TieredStorage = status
+	if err := patchStatus(cc.Ctx, cc.Client, cr, patchBase); err != nil {
+		cc.ReqLogger.Error(err, "Failed to update the tiered storage status")
+	}
+	return result.Continue()
+}
+
+// nextTieredStorageCheck is when the partitions are checked next, or the zero
+// time when spec.tieredStorage is not set or was never checked.
+func nextTieredStorageCheck(cr *marklogicv1.MarklogicCluster) time.Time {
+	if cr.Spec.TieredStorage == nil || cr.Status.TieredStorage == nil || cr.Status.TieredStorage.LastCheckTime == nil {
+		return time.Time{}
+	}
+	return cr.Status.TieredStorage.LastCheckTime.Add(tieredStorageCheckInterval)
+}
+
+// partitionPolicy returns the policy with the largest olderThan whose age the
+// range of the partition exceeds, or nil. A partition without an upper bound
+// holds new data and is never migrated.
+func partitionPolicy(policies []marklogicv1.PartitionMigrationPolicy, partition mlmanage.Partition, now time.Time) *marklogicv1.PartitionMigrationPolicy {
+	upperBound, ok := parsePartitionBound(partition.UpperBound)
+	if !ok {
+		return nil
+	}
+	var match *marklogicv1.PartitionMigrationPolicy
+	for i := range policies {
+		if now.Sub(upperBound) < policies[i].OlderThan.Duration {
+			continue
+		}
+		if match == nil || policies[i].OlderThan.Duration > match.OlderThan.Duration {
+			match = &policies[i]
+		}
+	}
+	return match
+}
+
+func parsePartitionBound(value string) (time.Time, bool) {
+	for _, layout := range partitionBoundLayouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func sameDirectory(a, b string) bool {
+	return strings.TrimSuffix(a, "/") == strings.TrimSuffix(b, "/")
+}
+
+func partitionTierStatus(partitions []marklogicv1.PartitionTierStatus, database, partition string) *marklogicv1.PartitionTierStatus {
+	for i := range partitions {
+		if partitions[i].Database == database && partitions[i].Partition == partition {
+			return &partitions[i]
+		}
+	}
+	return nil
+}
+
+func setPartitionTierStatus(partitions []marklogicv1.PartitionTierStatus, partition marklogicv1.PartitionTierStatus) []marklogicv1.PartitionTierStatus {
+	if existing := partitionTierStatus(partitions, partition.Database, partition.Partition); existing != nil {
+		*existing = partition
+		return partitions
+	}
+	return append(partitions, partition)
+}
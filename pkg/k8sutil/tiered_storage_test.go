@@ -0,0 +1,111 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestReconcileTieredStorageMigratesOldPartitions(t *testing.T) {
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			ClusterDomain:   "cluster.local",
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{{Name: "dnode", IsBootstrap: true}},
+			TieredStorage: &marklogicv1.TieredStorage{Policies: []marklogicv1.PartitionMigrationPolicy{
+				{Database: "Sales", OlderThan: metav1.Duration{Duration: 30 * 24 * time.Hour}, DataDirectory: "/var/opt/MarkLogic/tiers/warm"},
+				{Database: "Sales", OlderThan: metav1.Duration{Duration: 365 * 24 * time.Hour}, DataDirectory: "/var/opt/MarkLogic/tiers/archive"},
+				{Database: "Logs", OlderThan: metav1.Duration{Duration: 24 * time.Hour}, DataDirectory: "/var/opt/MarkLogic/tiers/archive"},
+			}},
+		},
+	}
+	cc := newUpgradeTestContext(t, cr)
+	now := time.Now().UTC()
+	migrated := map[string]string{}
+	original := NewDynamicManagementClient
+	NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+		return &stubDynamicManagementClient{
+			listPartitionsFn: func(database string) ([]mlmanage.Partition, error) {
+				if database == "Logs" {
+					return nil, errors.New("XDMP-NOPARTITION")
+				}
+				return []mlmanage.Partition{
+					{Name: "2019", UpperBound: "2020-01-01T00:00:00Z"},
+					{Name: "recent", UpperBound: now.AddDate(0, -2, 0).Format("2006-01-02")},
+					{Name: "moved", UpperBound: now.AddDate(0, -2, 0).Format(time.RFC3339), DataDirectory: "/var/opt/MarkLogic/tiers/warm/"},
+					{Name: "current", UpperBound: now.AddDate(0, 0, -1).Format(time.RFC3339)},
+					{Name: "open"},
+				}, nil
+			},
+			migratePartitionFn: func(database, partition, dataDirectory string) error {
+				migrated[database+"/"+partition] = dataDirectory
+				return nil
+			},
+		}
+	}
+	t.Cleanup(func() { NewDynamicManagementClient = original })
+
+	if res := cc.ReconcileTieredStorage(); res.Completed() {
+		t.Fatalf("expected tiered storage never to hold up the reconcile")
+	}
+	if len(migrated) != 2 || migrated["Sales/2019"] != "/var/opt/MarkLogic/tiers/archive" || migrated["Sales/recent"] != "/var/opt/MarkLogic/tiers/warm" {
+		t.Fatalf("expected the old partitions to move to the tier of the oldest matching policy, got %v", migrated)
+	}
+	status := cr.Status.TieredStorage
+	if status == nil || len(status.Partitions) != 2 || status.LastCheckTime == nil || !strings.Contains(status.Message, "database Logs") {
+		t.Fatalf("expected the migrations and the failed database in the status, got %+v", status)
+	}
+	if next := nextTieredStorageCheck(cr); !next.Equal(status.LastCheckTime.Add(tieredStorageCheckInterval)) {
+		t.Fatalf("expected the next check after the interval, got %v", next)
+	}
+
+	// A started migration is not started again while MarkLogic moves the forests.
+	migrated = map[string]string{}
+	status.LastCheckTime = &metav1.Time{Time: now.Add(-time.Hour)}
+	cc.ReconcileTieredStorage()
+	if len(migrated) != 0 {
+		t.Fatalf("expected no new migrations, got %v", migrated)
+	}
+}
+
+func TestStorageTiersAreMountedInThePods(t *testing.T) {
+	group := newSecretRotationTestGroup()
+	group.Spec.HugePages = &marklogicv1.HugePages{}
+	group.Spec.LogCollection = &marklogicv1.LogCollection{}
+	group.Spec.Persistence = &marklogicv1.Persistence{
+		Enabled:     true,
+		Size:        "10Gi",
+		AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+		Tiers: []marklogicv1.StorageTier{
+			{Name: "fast", StorageClassName: "local-nvme", Size: resource.MustParse("50Gi")},
+			{Name: "archive", Size: resource.MustParse("1Ti")},
+		},
+	}
+	sts := generateStatefulSetsDef(metav1.ObjectMeta{Name: "dnode", Namespace: "default"}, generateStatefulSetsParams(group),
+		metav1.OwnerReference{}, generateContainerParams(group))
+
+	templates := sts.Spec.VolumeClaimTemplates
+	if len(templates) != 3 || templates[1].Name != "tier-fast" || *templates[1].Spec.StorageClassName != "local-nvme" ||
+		templates[2].Name != "tier-archive" || templates[2].Spec.StorageClassName != nil {
+		t.Fatalf("expected a volume claim template per tier, got %+v", templates)
+	}
+	if size := templates[2].Spec.Resources.Requests.Storage(); size.String() != "1Ti" {
+		t.Fatalf("expected the size of the tier, got %v", size)
+	}
+	mounts := map[string]string{}
+	for _, mount := range sts.Spec.Template.Spec.Containers[0].VolumeMounts {
+		mounts[mount.Name] = mount.MountPath
+	}
+	if mounts["tier-fast"] != "/var/opt/MarkLogic/tiers/fast" || mounts["tier-archive"] != "/var/opt/MarkLogic/tiers/archive" {
+		t.Fatalf("expected the tiers to be mounted, got %v", mounts)
+	}
+}
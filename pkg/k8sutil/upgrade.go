@@ -0,0 +1,506 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/features"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlversion"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// OperatorActor is recorded in the upgrade timeline for transitions the operator makes itself.
+	OperatorActor = "marklogic-operator"
+
+	// UpgradeApprovalAnnotation approves the upgrade to the image it is set
+	// to when the InteractiveUpgrade feature gate is enabled.
+	UpgradeApprovalAnnotation = "marklogic.progress.com/approve-upgrade"
+
+	// PauseUpgradeAnnotation holds the rollout of an upgrade before it
+	// restarts the next pod while it is set to "true".
+	PauseUpgradeAnnotation = "marklogic.progress.com/pause-upgrade"
+
+	// RetryUpgradeAnnotation retries a failed upgrade right away, once for
+	// every request ID it is set to.
+	RetryUpgradeAnnotation = "marklogic.progress.com/retry-upgrade"
+
+	// ImagePolicyAnnotation names the image policy, such as the ImagePolicy
+	// of Flux as <namespace>/<name>, whose image automation changes
+	// spec.image. It is reported with the approval the upgrade waits for.
+	ImagePolicyAnnotation = "marklogic.progress.com/image-policy"
+
+	upgradeReasonStarted            = "UpgradeStarted"
+	upgradeReasonCompleted          = "UpgradeCompleted"
+	upgradeReasonCancelled          = "UpgradeCancelled"
+	upgradeReasonWaitingForApproval = "UpgradeWaitingForApproval"
+	upgradeReasonNoApprovalPending  = "NoApprovalPending"
+	upgradeReasonPaused             = "UpgradePaused"
+	upgradeReasonResumed            = "UpgradeResumed"
+	upgradeReasonRetried            = "UpgradeRetried"
+
+	upgradeReasonPrecheckFailed           = "UpgradePrecheckFailed"
+	upgradeReasonSecurityDatabaseUpgraded = "SecurityDatabaseUpgraded"
+
+	upgradePollIntervalSeconds  = 30
+	upgradePrecheckRetrySeconds = 60
+)
+
+// ReconcileUpgrade tracks the rollout of spec.image in status.upgrade. A new
+// image is held back from the groups until the registered prechecks pass.
+// Every state transition is appended to status.upgrade.timeline together
+// with the actor that caused it and emitted as an event, so a stuck upgrade
+// can be located with kubectl get/describe.
+func (cc *ClusterContext) ReconcileUpgrade() result.ReconcileResult {
+	cr := cc.MarklogicCluster
+	current := cr.Status.Upgrade
+	if current == nil {
+		// First reconcile: the cluster is created with spec.image, nothing to upgrade.
+		return cc.setUpgradeStatus(&marklogicv1.UpgradeStatus{State: marklogicv1.UpgradeStateIdle, CurrentImage: cr.Spec.Image}, result.Continue())
+	}
+	upgrade := current.DeepCopy()
+	now := metav1.Now()
+	if upgrade.Paused && ClusterOperation(cr, PauseUpgradeAnnotation) != "true" {
+		upgrade.Paused = false
+		upgrade.RecordTransition(upgrade.State, OperatorActor, fmt.Sprintf("upgrade to %s resumed", upgrade.TargetImage), now)
+		cc.recordClusterEvent("Normal", upgradeReasonResumed, upgrade.Message)
+	}
+
+	switch upgrade.State {
+	case marklogicv1.UpgradeStateInProgress:
+		if cr.Spec.Image == upgrade.CurrentImage {
+			return cc.cancelUpgrade(upgrade, now)
+		}
+		if cr.Spec.Image != upgrade.TargetImage {
+			upgrade.TargetImage = cr.Spec.Image
+			upgrade.RecordTransition(marklogicv1.UpgradeStateInProgress, specFieldManager(cr, "image"),
+				fmt.Sprintf("upgrade target changed to %s", cr.Spec.Image), now)
+			cc.recordClusterEvent("Normal", upgradeReasonStarted, upgrade.Message)
+		}
+		return cc.progressUpgrade(upgrade, now)
+	case marklogicv1.UpgradeStateFailed:
+		if cr.Spec.Image == upgrade.CurrentImage {
+			return cc.cancelUpgrade(upgrade, now)
+		}
+		if cr.Spec.Image == upgrade.TargetImage {
+			if retry := ClusterOperation(cr, RetryUpgradeAnnotation); retry != "" && retry != upgrade.RetryRequestID {
+				upgrade.RetryRequestID = retry
+				upgrade.RecordTransition(marklogicv1.UpgradeStateFailed, operationFieldManager(cr, RetryUpgradeAnnotation),
+					fmt.Sprintf("retry %s requested, running prechecks for upgrade to %s", retry, upgrade.TargetImage), now)
+				cc.recordClusterEvent("Normal", upgradeReasonRetried, upgrade.Message)
+			}
+			// Re-run the prechecks until they pass or the image is changed. A
+			// rollout stopped by a health gate resumes with the next outdated pod.
+			return cc.precheckUpgrade(upgrade, now)
+		}
+	case marklogicv1.UpgradeStateWaitingForUserApproval:
+		if cr.Spec.Image == upgrade.CurrentImage {
+			return cc.cancelUpgrade(upgrade, now)
+		}
+		if cr.Spec.Image == upgrade.TargetImage {
+			return cc.precheckUpgrade(upgrade, now)
+		}
+	default:
+		if cr.Spec.Image == upgrade.CurrentImage {
+			return result.Continue()
+		}
+	}
+	upgrade.TargetImage = cr.Spec.Image
+	upgrade.StartTime = &now
+	upgrade.CompletionTime = nil
+	upgrade.UpdatedPods = 0
+	upgrade.TotalPods = 0
+	upgrade.RolloutStarted = false
+	upgrade.PodRestart = nil
+	upgrade.RecordTransition(marklogicv1.UpgradeStatePrecheck, specFieldManager(cr, "image"),
+		fmt.Sprintf("running prechecks for upgrade from %s to %s", upgrade.CurrentImage, upgrade.TargetImage), now)
+	return cc.precheckUpgrade(upgrade, now)
+}
+
+// precheckUpgrade runs the prechecks for upgrade.TargetImage and starts the
+// rollout when none of them failed.
+func (cc *ClusterContext) precheckUpgrade(upgrade *marklogicv1.UpgradeStatus, now metav1.Time) result.ReconcileResult {
+	upgrade.Prechecks = cc.runPrechecks(upgrade.TargetImage)
+	impact, err := cc.estimateUpgradeImpact(upgrade)
+	if err != nil {
+		return result.Error(err)
+	}
+	upgrade.Impact = impact
+	summary := precheckSummary(upgrade.Prechecks)
+	if len(failedPrechecks(upgrade.Prechecks)) > 0 {
+		if upgrade.State != marklogicv1.UpgradeStateFailed || upgrade.Message != summary {
+			upgrade.RecordTransition(marklogicv1.UpgradeStateFailed, OperatorActor, summary, now)
+			cc.recordClusterEvent("Warning", upgradeReasonPrecheckFailed, summary)
+		}
+		return cc.setUpgradeStatus(upgrade, result.RequeueSoon(upgradePrecheckRetrySeconds))
+	}
+	actor := OperatorActor
+	if features.Enabled(features.InteractiveUpgrade) {
+		if ClusterOperation(cc.MarklogicCluster, UpgradeApprovalAnnotation) == upgrade.TargetImage {
+			actor = operationFieldManager(cc.MarklogicCluster, UpgradeApprovalAnnotation)
+			summary += ", approved"
+		} else if approved, reason, next := cc.autoApproveUpgrade(upgrade.Prechecks, now.Time); approved {
+			summary += ", approved automatically"
+		} else {
+			message := fmt.Sprintf("%s, waiting for approval: annotate the cluster with %s=%s", summary, UpgradeApprovalAnnotation, upgrade.TargetImage)
+			if reason != "" {
+				message += fmt.Sprintf(", not approved automatically: %s", reason)
+			}
+			if upgrade.State != marklogicv1.UpgradeStateWaitingForUserApproval {
+				upgrade.RecordTransition(marklogicv1.UpgradeStateWaitingForUserApproval, OperatorActor, message, now)
+				cc.recordClusterEvent("Normal", upgradeReasonWaitingForApproval, upgrade.Message)
+			} else {
+				upgrade.Message = message
+			}
+			upgrade.PendingApproval = cc.pendingApproval(upgrade, reason, now)
+			if next.IsZero() {
+				return cc.setUpgradeStatus(upgrade, result.Continue())
+			}
+			// Come back when the maintenance window opens.
+			return cc.setUpgradeStatus(upgrade, result.RequeueSoon(int(next.Sub(now.Time).Seconds())+1))
+		}
+	}
+	if res, held := cc.holdPausedUpgrade(upgrade, summary, now); held {
+		return res
+	}
+	if rollout := cc.MarklogicCluster.Status.ResourceRollout; rolloutLockHolder(cc.MarklogicCluster) == rolloutLockResourceRollout &&
+		rollout != nil && rollout.PodRestart != nil {
+		// The upgrade takes the rollout lock over once the pod the resource
+		// rollout restarted passed its health gates.
+		upgrade.Message = fmt.Sprintf("%s, waiting for the resource rollout to finish restarting pod %s", summary, rollout.PodRestart.Pod)
+		return cc.setUpgradeStatus(upgrade, result.RequeueSoon(healthGateRequeueSeconds))
+	}
+	if restart := cc.MarklogicCluster.Status.HostRestart; rolloutLockHolder(cc.MarklogicCluster) == rolloutLockHostRestart &&
+		restart != nil && restart.PodRestart != nil {
+		upgrade.Message = fmt.Sprintf("%s, waiting for the host restart to finish restarting pod %s", summary, restart.PodRestart.Pod)
+		return cc.setUpgradeStatus(upgrade, result.RequeueSoon(healthGateRequeueSeconds))
+	}
+	upgrade.Step = ""
+	upgrade.RolloutStarted = true
+	if cc.upgradesBootstrapFirst(upgrade) {
+		upgrade.Step = marklogicv1.UpgradeStepBootstrapGroup
+		summary += ", upgrading the bootstrap group first"
+	}
+	upgrade.RecordTransition(marklogicv1.UpgradeStateInProgress, actor,
+		fmt.Sprintf("%s, upgrading from %s to %s", summary, upgrade.CurrentImage, upgrade.TargetImage), now)
+	cc.recordClusterEvent("Normal", upgradeReasonStarted, upgrade.Message)
+	// Requeue right away so the groups pick up the target image.
+	return cc.setUpgradeStatus(upgrade, result.RequeueSoon(1))
+}
+
+func (cc *ClusterContext) cancelUpgrade(upgrade *marklogicv1.UpgradeStatus, now metav1.Time) result.ReconcileResult {
+	upgrade.RecordTransition(marklogicv1.UpgradeStateIdle, specFieldManager(cc.MarklogicCluster, "image"),
+		fmt.Sprintf("upgrade to %s cancelled, image reverted to %s", upgrade.TargetImage, upgrade.CurrentImage), now)
+	upgrade.TargetImage = ""
+	upgrade.RolloutStarted = false
+	upgrade.PodRestart = nil
+	upgrade.Paused = false
+	cc.recordClusterEvent("Warning", upgradeReasonCancelled, upgrade.Message)
+	return cc.setUpgradeStatus(upgrade, result.Continue())
+}
+
+// holdPausedUpgrade holds the upgrade while spec.operations.pauseUpgrade or
+// the pause-upgrade annotation is set, before the rollout starts or the next
+// pod restarts, and reports whether it did. Pods already restarting finish
+// their health gates first.
+func (cc *ClusterContext) holdPausedUpgrade(upgrade *marklogicv1.UpgradeStatus, progress string, now metav1.Time) (result.ReconcileResult, bool) {
+	cr := cc.MarklogicCluster
+	if ClusterOperation(cr, PauseUpgradeAnnotation) != "true" {
+		return nil, false
+	}
+	message := fmt.Sprintf("%s, paused: remove %s or spec.operations.pauseUpgrade to resume", progress, PauseUpgradeAnnotation)
+	if !upgrade.Paused {
+		upgrade.Paused = true
+		upgrade.RecordTransition(upgrade.State, operationFieldManager(cr, PauseUpgradeAnnotation), message, now)
+		cc.recordClusterEvent("Normal", upgradeReasonPaused, message)
+	} else {
+		upgrade.Message = message
+	}
+	return cc.setUpgradeStatus(upgrade, result.Continue()), true
+}
+
+// rolloutImage returns the image the groups that follow spec.image should
+// run. A changed spec.image is only handed to the groups once its prechecks
+// passed. A rollout that failed on a health gate keeps the target image so
+// the pods already restarted are not rolled back.
+func rolloutImage(cr *marklogicv1.MarklogicCluster) string {
+	upgrade := cr.Status.Upgrade
+	if upgrade == nil || upgrade.CurrentImage == "" || !features.Enabled(features.UpgradeWorkflow) {
+		return cr.Spec.Image
+	}
+	if upgrade.RolloutStarted && upgrade.TargetImage != "" &&
+		(upgrade.State == marklogicv1.UpgradeStateInProgress || upgrade.State == marklogicv1.UpgradeStateFailed) {
+		return upgrade.TargetImage
+	}
+	return upgrade.CurrentImage
+}
+
+// groupRolloutImage returns the image of a group that follows spec.image.
+// While a major version upgrade rolls out the bootstrap group and upgrades
+// the Security database, the other groups stay on the current image.
+func groupRolloutImage(cr *marklogicv1.MarklogicCluster, isBootstrap bool) string {
+	upgrade := cr.Status.Upgrade
+	if !isBootstrap && upgrade != nil && upgrade.RolloutStarted && features.Enabled(features.UpgradeWorkflow) &&
+		(upgrade.Step == marklogicv1.UpgradeStepBootstrapGroup || upgrade.Step == marklogicv1.UpgradeStepSecurityDatabase) {
+		return upgrade.CurrentImage
+	}
+	return rolloutImage(cr)
+}
+
+// upgradesBootstrapFirst reports whether the upgrade changes the MarkLogic
+// major version, which needs the Security database upgraded on the bootstrap
+// host before the other hosts restart on the new release. Tags without a
+// version are treated as a major version change.
+func (cc *ClusterContext) upgradesBootstrapFirst(upgrade *marklogicv1.UpgradeStatus) bool {
+	bootstrapFollowsImage := false
+	for _, group := range cc.MarklogicCluster.Spec.MarkLogicGroups {
+		if group != nil && group.IsBootstrap && group.Image == "" {
+			bootstrapFollowsImage = true
+		}
+	}
+	return bootstrapFollowsImage && changesMajorVersion(upgrade.CurrentImage, upgrade.TargetImage)
+}
+
+// changesMajorVersion reports whether target runs another MarkLogic major
+// version than current. Tags without a version count as a change.
+func changesMajorVersion(current, target string) bool {
+	currentMajor, currentOK := imageMajorVersion(current)
+	targetMajor, targetOK := imageMajorVersion(target)
+	return !currentOK || !targetOK || currentMajor != targetMajor
+}
+
+// progressUpgrade restarts the outdated pods of OnDelete groups one at a time
+// behind the health gates, counts the pods running the target image and
+// completes the upgrade once every group that follows spec.image has rolled
+// out. Major
+// version upgrades first roll out the bootstrap group and wait for the
+// Security database upgrade before the remaining groups get the new image.
+func (cc *ClusterContext) progressUpgrade(upgrade *marklogicv1.UpgradeStatus, now metav1.Time) result.ReconcileResult {
+	switch upgrade.Step {
+	case marklogicv1.UpgradeStepBootstrapGroup:
+		if res, handled := cc.restartUpgradePods(upgrade, true, now); handled {
+			return res
+		}
+		updated, total, err := cc.upgradeRolloutProgress(upgrade.TargetImage, true)
+		if err != nil {
+			return result.Error(err)
+		}
+		if updated < total {
+			upgrade.Message = fmt.Sprintf("bootstrap group: %d/%d pods running %s", updated, total, upgrade.TargetImage)
+			return cc.setUpgradeStatus(upgrade, result.RequeueSoon(upgradePollIntervalSeconds))
+		}
+		upgrade.Step = marklogicv1.UpgradeStepSecurityDatabase
+		upgrade.RecordTransition(marklogicv1.UpgradeStateInProgress, OperatorActor,
+			fmt.Sprintf("bootstrap group running %s, upgrading the Security database", upgrade.TargetImage), now)
+		fallthrough
+	case marklogicv1.UpgradeStepSecurityDatabase:
+		upgraded, err := cc.upgradeSecurityDatabase(upgrade.TargetImage)
+		if err != nil || !upgraded {
+			upgrade.Message = "waiting for the Security database upgrade on the bootstrap host"
+			if err != nil {
+				upgrade.Message = fmt.Sprintf("%s: %v", upgrade.Message, err)
+			}
+			return cc.setUpgradeStatus(upgrade, result.RequeueSoon(upgradePollIntervalSeconds))
+		}
+		upgrade.Step = marklogicv1.UpgradeStepRemainingGroups
+		upgrade.RecordTransition(marklogicv1.UpgradeStateInProgress, OperatorActor,
+			"Security database upgraded, upgrading the remaining groups", now)
+		cc.recordClusterEvent("Normal", upgradeReasonSecurityDatabaseUpgraded, upgrade.Message)
+		// Requeue right away so the remaining groups pick up the target image.
+		return cc.setUpgradeStatus(upgrade, result.RequeueSoon(1))
+	}
+
+	if res, handled := cc.restartUpgradePods(upgrade, false, now); handled {
+		return res
+	}
+	updated, total, err := cc.upgradeRolloutProgress(upgrade.TargetImage, false)
+	if err != nil {
+		return result.Error(err)
+	}
+	upgrade.UpdatedPods = updated
+	upgrade.TotalPods = total
+	if updated < total {
+		upgrade.Message = fmt.Sprintf("%d/%d pods running %s", updated, total, upgrade.TargetImage)
+		return cc.setUpgradeStatus(upgrade, result.RequeueSoon(upgradePollIntervalSeconds))
+	}
+	upgrade.CurrentImage = upgrade.TargetImage
+	upgrade.CompletionTime = &now
+	upgrade.RecordTransition(marklogicv1.UpgradeStateCompleted, OperatorActor,
+		fmt.Sprintf("all %d pods running %s", total, upgrade.TargetImage), now)
+	cc.recordClusterEvent("Normal", upgradeReasonCompleted, upgrade.Message)
+	return cc.setUpgradeStatus(upgrade, result.Continue())
+}
+
+// upgradeRolloutProgress returns how many pods of the groups that use the
+// cluster image are updated to image and ready, and the total pod count.
+// Groups with their own image override are not part of the cluster upgrade.
+func (cc *ClusterContext) upgradeRolloutProgress(image string, bootstrapOnly bool) (int32, int32, error) {
+	cr := cc.MarklogicCluster
+	var updated, total int32
+	for _, group := range cr.Spec.MarkLogicGroups {
+		if group == nil || group.Image != "" || (bootstrapOnly && !group.IsBootstrap) {
+			continue
+		}
+		replicas := int32(1)
+		if group.Replicas != nil {
+			replicas = *group.Replicas
+		}
+		total += replicas
+		sts := &appsv1.StatefulSet{}
+		err := cc.Client.Get(cc.Ctx, types.NamespacedName{Namespace: cr.Namespace, Name: group.Name}, sts)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return 0, 0, err
+		}
+		updated += statefulSetPodsOnImage(sts, image)
+	}
+	return updated, total, nil
+}
+
+// statefulSetPodsOnImage returns the number of ready pods on the current
+// template revision when that template runs image.
+func statefulSetPodsOnImage(sts *appsv1.StatefulSet, image string) int32 {
+	if sts.Status.ObservedGeneration < sts.Generation {
+		return 0
+	}
+	for _, container := range sts.Spec.Template.Spec.Containers {
+		if container.Name == "marklogic-server" && container.Image != image {
+			return 0
+		}
+	}
+	return min(sts.Status.UpdatedReplicas, sts.Status.ReadyReplicas)
+}
+
+// upgradeSecurityDatabase upgrades the Security database once the bootstrap
+// host is back online on the target release. It returns true when MarkLogic
+// reports nothing left to upgrade.
+func (cc *ClusterContext) upgradeSecurityDatabase(targetImage string) (bool, error) {
+	mgmtClient, err := cc.newBootstrapAdminClient()
+	if err != nil {
+		return false, err
+	}
+	bootstrapHost, err := cc.bootstrapHostFQDN()
+	if err != nil {
+		return false, err
+	}
+	hosts, err := mgmtClient.ListHostsStatus(cc.Ctx)
+	if err != nil {
+		return false, err
+	}
+	targetMajor, targetKnown := imageMajorVersion(targetImage)
+	for _, host := range hosts {
+		if host.Name != bootstrapHost {
+			continue
+		}
+		if !host.Online {
+			return false, nil
+		}
+		if major, ok := hostMajorVersion(host.Version); targetKnown && ok && major != targetMajor {
+			return false, nil
+		}
+		return mgmtClient.UpgradeSecurityDatabase(cc.Ctx)
+	}
+	return false, fmt.Errorf("bootstrap host %s is not part of the cluster", bootstrapHost)
+}
+
+// hostMajorVersion returns the major version of a MarkLogic version string such as 12.0-1.
+func hostMajorVersion(version string) (int, bool) {
+	parsed, ok := mlversion.ParseHost(version)
+	return parsed.Major, ok
+}
+
+func (cc *ClusterContext) setUpgradeStatus(upgrade *marklogicv1.UpgradeStatus, next result.ReconcileResult) result.ReconcileResult {
+	cr := cc.MarklogicCluster
+	patchBase := client.MergeFrom(cr.DeepCopy())
+	if upgrade.State != marklogicv1.UpgradeStateWaitingForUserApproval {
+		upgrade.PendingApproval = nil
+	}
+	if !upgrade.Active() && upgrade.State != marklogicv1.UpgradeStateFailed {
+		upgrade.Paused = false
+	}
+	cr.Status.Upgrade = upgrade
+	setRolloutLock(cr, rolloutLockUpgrade, upgradeHoldsRolloutLock(upgrade),
+		fmt.Sprintf("upgrade to %s restarts pods, other changes of the groups are deferred", upgrade.TargetImage))
+	cc.setPendingApprovalCondition(upgrade.PendingApproval)
+	if err := patchStatus(cc.Ctx, cc.Client, cr, patchBase); err != nil {
+		return result.Error(err)
+	}
+	return next
+}
+
+// recordClusterEvent emits an event on the MarklogicCluster.
+func (cc *ClusterContext) recordClusterEvent(eventType, reason, message string) {
+	if cc.Recorder != nil {
+		cc.Recorder.Event(cc.MarklogicCluster, eventType, reason, message)
+	}
+}
+
+// specFieldManager returns the field manager that most recently wrote
+// spec.<field>, falling back to OperatorActor when it cannot be determined.
+func specFieldManager(obj metav1.Object, field string) string {
+	return fieldManager(obj, "f:spec", "f:"+field)
+}
+
+// annotationFieldManager returns the field manager that most recently wrote
+// the annotation, falling back to OperatorActor.
+func annotationFieldManager(obj metav1.Object, key string) string {
+	return fieldManager(obj, "f:metadata", "f:annotations", "f:"+key)
+}
+
+func fieldManager(obj metav1.Object, path ...string) string {
+	if manager, ok := lastFieldManager(obj, path...); ok {
+		return manager
+	}
+	return OperatorActor
+}
+
+// lastFieldManager returns the field manager that most recently wrote the
+// managed fields path, if any manager owns it.
+func lastFieldManager(obj metav1.Object, path ...string) (string, bool) {
+	actor, found := "", false
+	var latest time.Time
+	for _, entry := range obj.GetManagedFields() {
+		if entry.FieldsV1 == nil || entry.Subresource != "" {
+			continue
+		}
+		if !hasManagedField(entry.FieldsV1.Raw, path) {
+			continue
+		}
+		var written time.Time
+		if entry.Time != nil {
+			written = entry.Time.Time
+		}
+		if !found || !written.Before(latest) {
+			latest = written
+			actor, found = entry.Manager, true
+		}
+	}
+	return actor, found
+}
+
+// hasManagedField reports whether the managed fields set contains the path.
+func hasManagedField(raw []byte, path []string) bool {
+	for _, key := range path {
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &fields); err != nil {
+			return false
+		}
+		next, ok := fields[key]
+		if !ok {
+			return false
+		}
+		raw = next
+	}
+	return true
+}
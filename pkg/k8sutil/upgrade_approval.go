@@ -0,0 +1,105 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"fmt"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/cron"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// autoApproveUpgrade reports whether spec.upgrade.autoApprove approves an
+// upgrade whose prechecks did not fail. Otherwise it returns why, empty
+// without autoApprove, and when the next maintenance window opens, zero when
+// waiting for it does not help.
+func (cc *ClusterContext) autoApproveUpgrade(prechecks []marklogicv1.PrecheckResult, now time.Time) (bool, string, time.Time) {
+	spec := cc.MarklogicCluster.Spec.Upgrade
+	if spec == nil || spec.AutoApprove == nil {
+		return false, "", time.Time{}
+	}
+	auto := spec.AutoApprove
+	maxWarnings := int32(0)
+	if auto.MaxWarnings != nil {
+		maxWarnings = *auto.MaxWarnings
+	}
+	warnings := int32(0)
+	for _, res := range prechecks {
+		if res.Status == marklogicv1.PrecheckWarning {
+			warnings++
+		}
+	}
+	if warnings > maxWarnings {
+		return false, fmt.Sprintf("%d precheck(s) warned, %d allowed", warnings, maxWarnings), time.Time{}
+	}
+	if auto.MaintenanceWindow == nil {
+		return true, "", time.Time{}
+	}
+	open, next, err := maintenanceWindowOpen(auto.MaintenanceWindow, now)
+	if err != nil {
+		return false, fmt.Sprintf("invalid maintenance window: %v", err), time.Time{}
+	}
+	if open {
+		return true, "", time.Time{}
+	}
+	if next.IsZero() {
+		return false, "outside the maintenance window", time.Time{}
+	}
+	return false, fmt.Sprintf("outside the maintenance window, next window opens at %s", next.UTC().Format(time.RFC3339)), next
+}
+
+// maintenanceWindowOpen reports whether a maintenance window is open at now,
+// and when it opens next.
+func maintenanceWindowOpen(window *marklogicv1.MaintenanceWindow, now time.Time) (bool, time.Time, error) {
+	start, err := cron.Parse(window.Start)
+	if err != nil {
+		return false, time.Time{}, fmt.Errorf("start: %w", err)
+	}
+	location, err := time.LoadLocation(window.TimeZone)
+	if err != nil {
+		return false, time.Time{}, fmt.Errorf("time zone: %w", err)
+	}
+	now = now.In(location)
+	_, open := start.Latest(now.Add(-window.Duration.Duration), now)
+	return open, start.Next(now), nil
+}
+
+// pendingApproval describes the approval the upgrade waits for, keeping the
+// time it started to wait.
+func (cc *ClusterContext) pendingApproval(upgrade *marklogicv1.UpgradeStatus, autoApproval string, now metav1.Time) *marklogicv1.UpgradeApproval {
+	cr := cc.MarklogicCluster
+	approval := &marklogicv1.UpgradeApproval{
+		Image:        upgrade.TargetImage,
+		Since:        now,
+		RequestedBy:  specFieldManager(cr, "image"),
+		ImagePolicy:  cr.Annotations[ImagePolicyAnnotation],
+		Annotation:   UpgradeApprovalAnnotation,
+		Field:        "spec.operations.approveUpgrade",
+		AutoApproval: autoApproval,
+	}
+	for _, res := range upgrade.Prechecks {
+		if res.Status == marklogicv1.PrecheckWarning {
+			approval.Warnings++
+		}
+	}
+	if previous := upgrade.PendingApproval; previous != nil && previous.Image == approval.Image {
+		approval.Since = previous.Since
+	}
+	return approval
+}
+
+// setPendingApprovalCondition sets the UpgradePendingApproval condition of
+// the cluster. The status is patched by the caller.
+func (cc *ClusterContext) setPendingApprovalCondition(approval *marklogicv1.UpgradeApproval) {
+	cr := cc.MarklogicCluster
+	if approval == nil {
+		if cr.Status.GetConditionStatus(string(marklogicv1.UpgradePendingApproval)) == metav1.ConditionTrue {
+			cc.setClusterCondition(marklogicv1.UpgradePendingApproval, metav1.ConditionFalse, upgradeReasonNoApprovalPending, "no upgrade waits for approval")
+		}
+		return
+	}
+	cc.setClusterCondition(marklogicv1.UpgradePendingApproval, metav1.ConditionTrue, upgradeReasonWaitingForApproval,
+		fmt.Sprintf("the upgrade to %s waits for approval: set %s or %s to the image", approval.Image, approval.Annotation, approval.Field))
+}
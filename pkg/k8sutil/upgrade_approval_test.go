@@ -0,0 +1,116 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/features"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestAutoApproveUpgrade(t *testing.T) {
+	oneWarning := int32(1)
+	window := &marklogicv1.MaintenanceWindow{Start: "0 22 * * *", Duration: metav1.Duration{Duration: 4 * time.Hour}, TimeZone: "Europe/Berlin"}
+	warned := []marklogicv1.PrecheckResult{
+		{Name: "storage-headroom", Status: marklogicv1.PrecheckPassed},
+		{Name: "image-signature", Status: marklogicv1.PrecheckWarning},
+	}
+	// 21:00 UTC is 23:00 in Berlin in October.
+	inWindow := time.Date(2026, 10, 16, 21, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		auto     *marklogicv1.AutoApprove
+		now      time.Time
+		approved bool
+		reason   string
+		next     time.Time
+	}{
+		{name: "not configured", now: inWindow},
+		{name: "no window", auto: &marklogicv1.AutoApprove{MaxWarnings: &oneWarning}, now: inWindow, approved: true},
+		{name: "too many warnings", auto: &marklogicv1.AutoApprove{}, now: inWindow, reason: "1 precheck(s) warned, 0 allowed"},
+		{name: "in window", auto: &marklogicv1.AutoApprove{MaxWarnings: &oneWarning, MaintenanceWindow: window}, now: inWindow, approved: true},
+		{
+			name:   "outside window",
+			auto:   &marklogicv1.AutoApprove{MaxWarnings: &oneWarning, MaintenanceWindow: window},
+			now:    time.Date(2026, 10, 16, 10, 0, 0, 0, time.UTC),
+			reason: "outside the maintenance window, next window opens at 2026-10-16T20:00:00Z",
+			next:   time.Date(2026, 10, 16, 20, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "invalid window",
+			auto:   &marklogicv1.AutoApprove{MaxWarnings: &oneWarning, MaintenanceWindow: &marklogicv1.MaintenanceWindow{Start: "0 25 * * *"}},
+			now:    inWindow,
+			reason: "invalid maintenance window: start:",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cc := &ClusterContext{MarklogicCluster: &marklogicv1.MarklogicCluster{
+				Spec: marklogicv1.MarklogicClusterSpec{Upgrade: &marklogicv1.UpgradeSpec{AutoApprove: tt.auto}},
+			}}
+			approved, reason, next := cc.autoApproveUpgrade(warned, tt.now)
+			if approved != tt.approved || !strings.HasPrefix(reason, tt.reason) || (tt.reason == "" && reason != "") || !next.Equal(tt.next) {
+				t.Fatalf("expected %v %q %v, got %v %q %v", tt.approved, tt.reason, tt.next, approved, reason, next)
+			}
+		})
+	}
+}
+
+func TestReconcileUpgradeApprovesAutomatically(t *testing.T) {
+	gates, err := features.Parse("InteractiveUpgrade=true")
+	if err != nil {
+		t.Fatalf("failed to parse feature gates: %v", err)
+	}
+	original := features.Default
+	features.Default = gates
+	t.Cleanup(func() { features.Default = original })
+
+	maxWarnings := int32(10)
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			Image:           upgradeTestPatchImage,
+			ClusterDomain:   "cluster.local",
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{{Name: "dnode", IsBootstrap: true}},
+			Upgrade: &marklogicv1.UpgradeSpec{AutoApprove: &marklogicv1.AutoApprove{
+				MaxWarnings: &maxWarnings,
+				// Opens on February 29 only.
+				MaintenanceWindow: &marklogicv1.MaintenanceWindow{Start: "0 0 29 2 *", Duration: metav1.Duration{Duration: time.Hour}},
+			}},
+		},
+		Status: marklogicv1.MarklogicClusterStatus{Upgrade: &marklogicv1.UpgradeStatus{
+			State:        marklogicv1.UpgradeStateCompleted,
+			CurrentImage: upgradeTestOldImage,
+		}},
+	}
+	cc := newUpgradeTestContext(t, cr)
+	forests := []mlmanage.ForestStatus{{Name: "Documents", State: "open", DataSizeMB: 100, DeviceSpaceMB: 900}}
+	stubHealthyManagementClient(t, &forests)
+
+	if res := cc.ReconcileUpgrade(); !res.Completed() {
+		t.Fatalf("expected a requeue for the next maintenance window")
+	}
+	upgrade := cr.Status.Upgrade
+	if upgrade.State != marklogicv1.UpgradeStateWaitingForUserApproval ||
+		!strings.Contains(upgrade.Message, "not approved automatically: outside the maintenance window, next window opens at ") {
+		t.Fatalf("expected the upgrade to wait for the maintenance window, got %+v", upgrade)
+	}
+
+	cr.Spec.Upgrade.AutoApprove.MaintenanceWindow = nil
+	if res := cc.ReconcileUpgrade(); !res.Completed() {
+		t.Fatalf("expected a requeue once the upgrade was approved")
+	}
+	upgrade = cr.Status.Upgrade
+	if upgrade.State != marklogicv1.UpgradeStateInProgress || rolloutImage(cr) != upgradeTestPatchImage ||
+		!strings.Contains(upgrade.Message, "approved automatically") {
+		t.Fatalf("expected the upgrade to be approved automatically, got %+v", upgrade)
+	}
+	if actor := upgrade.Timeline[len(upgrade.Timeline)-1].Actor; actor != OperatorActor {
+		t.Fatalf("expected the operator to approve the upgrade, got %q", actor)
+	}
+}
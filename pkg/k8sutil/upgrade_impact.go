@@ -0,0 +1,96 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// estimateUpgradeImpact estimates how many pods an upgrade to TargetImage
+// restarts, how long each of them is down, from the restarts recorded in
+// status.upgrade.podStartHistory, and which databases have forests on their
+// hosts. Databases are left out when the Manage API cannot be reached.
+func (cc *ClusterContext) estimateUpgradeImpact(upgrade *marklogicv1.UpgradeStatus) (*marklogicv1.UpgradeImpact, error) {
+	cr := cc.MarklogicCluster
+	impact := &marklogicv1.UpgradeImpact{}
+	hosts := map[string]bool{}
+	for _, group := range cr.Spec.MarkLogicGroups {
+		// Groups with their own image are not part of the cluster upgrade.
+		if group == nil || group.Image != "" {
+			continue
+		}
+		pods := &corev1.PodList{}
+		if err := cc.Client.List(cc.Ctx, pods, client.InNamespace(cr.Namespace), client.MatchingLabels{
+			"app.kubernetes.io/name":     "marklogic",
+			"app.kubernetes.io/instance": group.Name,
+		}); err != nil {
+			return nil, err
+		}
+		for i := range pods.Items {
+			container := marklogicServerContainer(pods.Items[i].Spec.Containers)
+			if container == nil || container.Image == upgrade.TargetImage {
+				continue
+			}
+			impact.Restarts++
+			hosts[strings.ToLower(cc.podHostFQDN(pods.Items[i]))] = true
+		}
+	}
+
+	parts := []string{fmt.Sprintf("restarts %d pod(s) one at a time", impact.Restarts)}
+	if downtime, ok := medianDuration(upgrade.PodStartHistory); ok {
+		impact.PodDowntime = &metav1.Duration{Duration: downtime}
+		impact.Duration = &metav1.Duration{Duration: downtime * time.Duration(impact.Restarts)}
+		parts = append(parts, fmt.Sprintf("each down for about %s (median of %d restarts), about %s in total",
+			downtime, len(upgrade.PodStartHistory), impact.Duration.Duration))
+	} else {
+		parts = append(parts, "no restarts recorded yet to estimate the downtime")
+	}
+
+	if impact.Restarts > 0 {
+		var forests []mlmanage.ForestStatus
+		manage, err := cc.newBootstrapManagementClient()
+		if err == nil {
+			forests, err = manage.ListForestsStatus(cc.Ctx)
+		}
+		for _, forest := range forests {
+			if forest.Database != "" && hosts[strings.ToLower(forest.Host)] && !slices.Contains(impact.Databases, forest.Database) {
+				impact.Databases = append(impact.Databases, forest.Database)
+			}
+		}
+		slices.Sort(impact.Databases)
+		switch {
+		case err != nil:
+			parts = append(parts, fmt.Sprintf("affected databases unknown: %v", err))
+		case len(impact.Databases) > 0:
+			parts = append(parts, "affecting "+strings.Join(impact.Databases, ", "))
+		}
+	}
+	impact.Message = strings.Join(parts, ", ")
+	return impact, nil
+}
+
+// medianDuration returns the median of durations.
+func medianDuration(durations []metav1.Duration) (time.Duration, bool) {
+	if len(durations) == 0 {
+		return 0, false
+	}
+	sorted := make([]time.Duration, 0, len(durations))
+	for _, d := range durations {
+		sorted = append(sorted, d.Duration)
+	}
+	slices.Sort(sorted)
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2, true
+	}
+	return sorted[middle], true
+}
@@ -0,0 +1,367 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlversion"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	upgradeReasonPodRestarted     = "UpgradePodRestarted"
+	upgradeReasonHealthGateFailed = "UpgradeHealthGateFailed"
+
+	defaultHealthGateTimeout = 10 * time.Minute
+	// defaultAppServerPort is used for pods whose MarkLogic version cannot be
+	// told from their image.
+	defaultAppServerPort     = 8000
+	healthGateRequeueSeconds = 10
+	healthGateHTTPTimeout    = 5 * time.Second
+)
+
+// defaultHealthGates are evaluated when spec.upgrade.healthGate is empty.
+var defaultHealthGates = []marklogicv1.HealthGate{
+	{Name: "host-online", Type: marklogicv1.HealthGateHostOnline},
+	{Name: "forests-mounted", Type: marklogicv1.HealthGateForestsMounted},
+}
+
+// HealthGateHTTPGet performs the GET requests of AppServer and HTTP health
+// gates and returns the response status code. It is a variable so tests can
+// replace it.
+var HealthGateHTTPGet = func(ctx context.Context, url string) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, healthGateHTTPTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	httpClient := &http.Client{Transport: &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, // #nosec G402 -- pods serve the cluster's self-signed certificates
+	}}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+// restartUpgradePods drives the rollout of groups with the OnDelete update
+// strategy: it restarts one outdated pod at a time, highest ordinal first,
+// and only moves on once the restarted pod is ready and its health gates
+// passed. It returns false when there is nothing to restart or wait for.
+func (cc *ClusterContext) restartUpgradePods(upgrade *marklogicv1.UpgradeStatus, bootstrapOnly bool, now metav1.Time) (result.ReconcileResult, bool) {
+	if upgrade.PodRestart != nil {
+		check, err := cc.checkRestartedPod(upgrade.PodRestart, upgradeReasonHealthGateFailed, now)
+		if err != nil {
+			return result.Error(err), true
+		}
+		if check.failed {
+			upgrade.PodRestart = nil
+			upgrade.RecordTransition(marklogicv1.UpgradeStateFailed, OperatorActor, check.message, now)
+			cc.recordClusterEvent("Warning", upgradeReasonHealthGateFailed, check.message)
+			return cc.setUpgradeStatus(upgrade, result.RequeueSoon(upgradePrecheckRetrySeconds)), true
+		}
+		if !check.passed {
+			upgrade.Message = check.message
+			return cc.setUpgradeStatus(upgrade, result.RequeueSoon(healthGateRequeueSeconds)), true
+		}
+		upgrade.RecordPodStart(now.Sub(upgrade.PodRestart.StartTime.Time))
+		upgrade.PodRestart = nil
+	}
+	pod, err := cc.nextOutdatedPod(upgrade.TargetImage, bootstrapOnly)
+	if err != nil {
+		return result.Error(err), true
+	}
+	if pod == nil {
+		upgrade.PodHold = nil
+		return nil, false
+	}
+	if res, held := cc.holdPausedUpgrade(upgrade, fmt.Sprintf("pod %s is next to restart on %s", pod.Name, upgrade.TargetImage), now); held {
+		return res, true
+	}
+	if held, message := cc.holdPodRestart(pod, &upgrade.PodHold, now); held {
+		upgrade.Message = message
+		return cc.setUpgradeStatus(upgrade, result.RequeueSoon(backgroundJobRequeueSeconds)), true
+	}
+	// Take the pod out of HAProxy, and put the pod restarted before back.
+	maintenance := []string{pod.Name}
+	if rollout := cc.MarklogicCluster.Status.ResourceRollout; rollout != nil && rollout.PodRestart != nil {
+		maintenance = append(maintenance, rollout.PodRestart.Pod)
+	}
+	if err := cc.syncHAProxyServers(maintenance...); err != nil {
+		return result.Error(err), true
+	}
+	if err := cc.Client.Delete(cc.Ctx, pod); err != nil && !apierrors.IsNotFound(err) {
+		return result.Error(err), true
+	}
+	upgrade.PodRestart = &marklogicv1.UpgradePodRestart{Pod: pod.Name, PodUID: pod.UID, StartTime: now}
+	upgrade.Message = fmt.Sprintf("restarted pod %s on %s", pod.Name, upgrade.TargetImage)
+	cc.recordClusterEvent("Normal", upgradeReasonPodRestarted, upgrade.Message)
+	return cc.setUpgradeStatus(upgrade, result.RequeueSoon(healthGateRequeueSeconds)), true
+}
+
+// restartCheck is the outcome of the health gates of a restarted pod.
+type restartCheck struct {
+	// passed is set once every gate passed or was ignored.
+	passed bool
+	// failed is set when a gate with the Fail policy did not pass in time.
+	failed  bool
+	message string
+}
+
+// checkRestartedPod evaluates the health gates of a pod the operator
+// restarted. A gate that has not passed is waited for until its timeout;
+// gates with the Ignore policy are then skipped with a Warning event of
+// reason.
+func (cc *ClusterContext) checkRestartedPod(restart *marklogicv1.UpgradePodRestart, reason string, now metav1.Time) (restartCheck, error) {
+	elapsed := now.Sub(restart.StartTime.Time)
+	pod := &corev1.Pod{}
+	err := cc.Client.Get(cc.Ctx, types.NamespacedName{Namespace: cc.MarklogicCluster.Namespace, Name: restart.Pod}, pod)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return restartCheck{}, err
+	}
+	gates := cc.healthGates()
+	message := ""
+	switch {
+	case err != nil:
+		message = "pod is not ready"
+	case pod.DeletionTimestamp != nil || (restart.PodUID != "" && pod.UID == restart.PodUID):
+		// The deleted pod stays ready and its host online while it drains,
+		// so the gates would pass against it.
+		message = "pod has not been replaced yet"
+	case !hasPodReadyCondition(pod):
+		message = "pod is not ready"
+	}
+	if message != "" {
+		// The pod has to come back before any gate can pass, so it is
+		// bounded by the longest gate timeout.
+		var timeout time.Duration
+		for _, gate := range gates {
+			timeout = max(timeout, healthGateTimeout(gate))
+		}
+		ready := marklogicv1.HealthGate{Name: "pod-ready", Timeout: &metav1.Duration{Duration: timeout}, FailurePolicy: marklogicv1.HealthGateFail}
+		if check, stop := cc.healthGateFailed(restart.Pod, ready, message, reason, elapsed); stop {
+			return check, nil
+		}
+	}
+
+	var manage mlmanage.Client
+	var manageErr error
+	for _, gate := range gates {
+		if manage == nil && manageErr == nil && (gate.Type == marklogicv1.HealthGateHostOnline || gate.Type == marklogicv1.HealthGateForestsMounted) {
+			manage, manageErr = cc.newBootstrapManagementClient()
+		}
+		passed, message := cc.evaluateHealthGate(gate, *pod, manage, manageErr)
+		if passed {
+			continue
+		}
+		if check, stop := cc.healthGateFailed(restart.Pod, gate, message, reason, elapsed); stop {
+			return check, nil
+		}
+	}
+	return restartCheck{passed: true}, nil
+}
+
+// healthGateFailed handles a gate that has not passed yet: it waits until the
+// gate timeout and then applies the gate's failure policy. It returns false
+// when the policy lets the rollout continue.
+func (cc *ClusterContext) healthGateFailed(pod string, gate marklogicv1.HealthGate, message, reason string, elapsed time.Duration) (restartCheck, bool) {
+	timeout := healthGateTimeout(gate)
+	if elapsed < timeout {
+		return restartCheck{message: fmt.Sprintf("waiting for health gate %s of pod %s: %s", gate.Name, pod, message)}, true
+	}
+	summary := fmt.Sprintf("health gate %s of pod %s did not pass within %s: %s", gate.Name, pod, timeout, message)
+	if gate.FailurePolicy == marklogicv1.HealthGateIgnore {
+		cc.ReqLogger.Info("Ignoring failed health gate", "gate", gate.Name, "pod", pod, "message", message)
+		cc.recordClusterEvent("Warning", reason, summary+", ignored")
+		return restartCheck{}, false
+	}
+	return restartCheck{failed: true, message: summary}, true
+}
+
+// evaluateHealthGate runs a single gate against a restarted pod.
+func (cc *ClusterContext) evaluateHealthGate(gate marklogicv1.HealthGate, pod corev1.Pod, manage mlmanage.Client, manageErr error) (bool, string) {
+	host := cc.podHostFQDN(pod)
+	switch gate.Type {
+	case marklogicv1.HealthGateHostOnline:
+		if manageErr != nil {
+			return false, manageErr.Error()
+		}
+		hosts, err := manage.ListHostsStatus(cc.Ctx)
+		if err != nil {
+			return false, fmt.Sprintf("failed to read host status: %v", err)
+		}
+		for _, status := range hosts {
+			if status.Name == host {
+				if status.Online {
+					return true, ""
+				}
+				return false, "host is offline"
+			}
+		}
+		return false, "host is not part of the cluster"
+	case marklogicv1.HealthGateForestsMounted:
+		if manageErr != nil {
+			return false, manageErr.Error()
+		}
+		forests, err := manage.ListForestsStatus(cc.Ctx)
+		if err != nil {
+			return false, fmt.Sprintf("failed to read forest status: %v", err)
+		}
+		unmounted := []string{}
+		for _, forest := range forests {
+			if forest.Host == host && !forestStateHealthy(forest.State) {
+				unmounted = append(unmounted, fmt.Sprintf("%s (%s)", forest.Name, forest.State))
+			}
+		}
+		if len(unmounted) > 0 {
+			sort.Strings(unmounted)
+			return false, fmt.Sprintf("forests not open: %s", strings.Join(unmounted, ", "))
+		}
+		return true, ""
+	case marklogicv1.HealthGateAppServer:
+		port := gate.Port
+		if port == 0 {
+			port = defaultAppServerPort
+			if container := marklogicServerContainer(pod.Spec.Containers); container != nil {
+				if version, ok := mlversion.ParseImage(container.Image); ok {
+					caps, _ := mlversion.Lookup(version.Major)
+					port = caps.Ports.AppServices
+				}
+			}
+		}
+		scheme := "http"
+		if tlsSpec := cc.MarklogicCluster.Spec.Tls; tlsSpec != nil && tlsSpec.EnableOnDefaultAppServers {
+			scheme = "https"
+		}
+		// Any response below 500, including 401 for unauthenticated requests, shows the app server is serving.
+		return healthGateGet(cc.Ctx, fmt.Sprintf("%s://%s:%d/", scheme, host, port), 500)
+	case marklogicv1.HealthGateHTTP:
+		if gate.HTTP == nil {
+			return false, "no http endpoint configured"
+		}
+		scheme := strings.ToLower(gate.HTTP.Scheme)
+		if scheme == "" {
+			scheme = "http"
+		}
+		path := gate.HTTP.Path
+		if !strings.HasPrefix(path, "/") {
+			path = "/" + path
+		}
+		return healthGateGet(cc.Ctx, fmt.Sprintf("%s://%s:%d%s", scheme, host, gate.HTTP.Port, path), 400)
+	}
+	return false, fmt.Sprintf("unknown health gate type %q", gate.Type)
+}
+
+func healthGateGet(ctx context.Context, url string, maxStatus int) (bool, string) {
+	status, err := HealthGateHTTPGet(ctx, url)
+	if err != nil {
+		return false, fmt.Sprintf("GET %s failed: %v", url, err)
+	}
+	if status < 200 || status >= maxStatus {
+		return false, fmt.Sprintf("GET %s returned %d", url, status)
+	}
+	return true, ""
+}
+
+// nextOutdatedPod returns the pod the operator restarts next: the highest
+// ordinal pod not on the update revision of the first OnDelete group, with
+// the bootstrap group first. It returns nil while a pod of the group is not
+// ready, so only one pod is down at a time.
+func (cc *ClusterContext) nextOutdatedPod(targetImage string, bootstrapOnly bool) (*corev1.Pod, error) {
+	cr := cc.MarklogicCluster
+	groups := make([]*marklogicv1.MarklogicGroups, 0, len(cr.Spec.MarkLogicGroups))
+	for _, group := range cr.Spec.MarkLogicGroups {
+		if group == nil || group.Image != "" || (bootstrapOnly && !group.IsBootstrap) {
+			continue
+		}
+		groups = append(groups, group)
+	}
+	sort.SliceStable(groups, func(i, j int) bool { return groups[i].IsBootstrap && !groups[j].IsBootstrap })
+
+	for _, group := range groups {
+		sts := &appsv1.StatefulSet{}
+		err := cc.Client.Get(cc.Ctx, types.NamespacedName{Namespace: cr.Namespace, Name: group.Name}, sts)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if sts.Spec.UpdateStrategy.Type != appsv1.OnDeleteStatefulSetStrategyType {
+			continue
+		}
+		if !statefulSetTemplateOnImage(sts, targetImage) || sts.Status.UpdateRevision == "" {
+			// The group has not picked up the target image yet.
+			return nil, nil
+		}
+		list := &corev1.PodList{}
+		if err := cc.Client.List(cc.Ctx, list, client.InNamespace(cr.Namespace), client.MatchingLabels{
+			"app.kubernetes.io/name":     "marklogic",
+			"app.kubernetes.io/instance": group.Name,
+		}); err != nil {
+			return nil, err
+		}
+		var next *corev1.Pod
+		for i := range list.Items {
+			pod := &list.Items[i]
+			if pod.DeletionTimestamp != nil || !hasPodReadyCondition(pod) {
+				return nil, nil
+			}
+			if pod.Labels[appsv1.StatefulSetRevisionLabel] == sts.Status.UpdateRevision {
+				continue
+			}
+			if next == nil || parseOrdinalFromName(pod.Name) > parseOrdinalFromName(next.Name) {
+				next = pod
+			}
+		}
+		if next != nil {
+			return next, nil
+		}
+	}
+	return nil, nil
+}
+
+// statefulSetTemplateOnImage reports whether the observed template of a StatefulSet runs image.
+func statefulSetTemplateOnImage(sts *appsv1.StatefulSet, image string) bool {
+	if sts.Status.ObservedGeneration < sts.Generation {
+		return false
+	}
+	for _, container := range sts.Spec.Template.Spec.Containers {
+		if container.Name == "marklogic-server" {
+			return container.Image == image
+		}
+	}
+	return false
+}
+
+func (cc *ClusterContext) healthGates() []marklogicv1.HealthGate {
+	if upgrade := cc.MarklogicCluster.Spec.Upgrade; upgrade != nil && len(upgrade.HealthGate) > 0 {
+		return upgrade.HealthGate
+	}
+	return defaultHealthGates
+}
+
+func healthGateTimeout(gate marklogicv1.HealthGate) time.Duration {
+	if gate.Timeout != nil && gate.Timeout.Duration > 0 {
+		return gate.Timeout.Duration
+	}
+	return defaultHealthGateTimeout
+}
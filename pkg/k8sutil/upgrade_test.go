@@ -0,0 +1,898 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/features"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	upgradeTestOldImage = "progressofficial/marklogic-db:11.3.1-ubi-rootless"
+	upgradeTestNewImage = "progressofficial/marklogic-db:12.0.3-ubi9-rootless-2.2.6"
+	// upgradeTestPatchImage is a release of the same major version as upgradeTestOldImage.
+	upgradeTestPatchImage = "progressofficial/marklogic-db:11.3.2-ubi-rootless"
+)
+
+func newUpgradeTestContext(t *testing.T, cr *marklogicv1.MarklogicCluster, objects ...client.Object) *ClusterContext {
+	t.Helper()
+	adminSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: cr.Name + "-admin", Namespace: cr.Namespace},
+		Data:       map[string][]byte{"username": []byte("admin"), "password": []byte("admin")},
+	}
+	fakeClient, scheme := newFakeClient(t, append(objects, cr, adminSecret)...)
+	return &ClusterContext{
+		Ctx:              context.Background(),
+		Client:           fakeClient,
+		Scheme:           scheme,
+		MarklogicCluster: cr,
+		Recorder:         record.NewFakeRecorder(10),
+	}
+}
+
+// stubHealthyManagementClient replaces the Manage client with one that passes
+// every built-in precheck. forests may be changed by the caller.
+func stubHealthyManagementClient(t *testing.T, forests *[]mlmanage.ForestStatus) {
+	t.Helper()
+	original := NewDynamicManagementClient
+	NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+		return &stubDynamicManagementClient{
+			hostsStatusFn: func() ([]mlmanage.HostStatus, error) {
+				return []mlmanage.HostStatus{{Name: "dnode-0", Online: true}, {Name: "dnode-1", Online: true}}, nil
+			},
+			forestsStatusFn: func() ([]mlmanage.ForestStatus, error) {
+				return *forests, nil
+			},
+			hostLicenseFn: func(hostName string) (mlmanage.HostLicense, error) {
+				return mlmanage.HostLicense{}, nil
+			},
+		}
+	}
+	t.Cleanup(func() { NewDynamicManagementClient = original })
+}
+
+func newUpgradeTestStatefulSet(name, image string, updatedReady int32) *appsv1.StatefulSet {
+	return &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Generation: 2},
+		Spec: appsv1.StatefulSetSpec{
+			Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "marklogic-server", Image: image}},
+			}},
+		},
+		Status: appsv1.StatefulSetStatus{ObservedGeneration: 2, UpdatedReplicas: updatedReady, ReadyReplicas: updatedReady},
+	}
+}
+
+func TestReconcileUpgradeRecordsTimeline(t *testing.T) {
+	replicas := int32(2)
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			Image: upgradeTestOldImage,
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{
+				{Name: "dnode", Replicas: &replicas, IsBootstrap: true},
+				{Name: "pinned", Image: upgradeTestOldImage},
+			},
+		},
+	}
+	sts := newUpgradeTestStatefulSet("dnode", upgradeTestPatchImage, 1)
+	cc := newUpgradeTestContext(t, cr, sts)
+	forests := []mlmanage.ForestStatus{{Name: "Documents", State: "open", DataSizeMB: 100, DeviceSpaceMB: 900}}
+	stubHealthyManagementClient(t, &forests)
+
+	if res := cc.ReconcileUpgrade(); res.Completed() {
+		t.Fatalf("expected first reconcile to continue")
+	}
+	if cr.Status.Upgrade.State != marklogicv1.UpgradeStateIdle || cr.Status.Upgrade.CurrentImage != upgradeTestOldImage {
+		t.Fatalf("expected idle upgrade status on %s, got %+v", upgradeTestOldImage, cr.Status.Upgrade)
+	}
+
+	cr.Spec.Image = upgradeTestPatchImage
+	if err := cc.Client.Update(cc.Ctx, cr); err != nil {
+		t.Fatalf("failed to update cluster: %v", err)
+	}
+	if rolloutImage(cr) != upgradeTestOldImage {
+		t.Fatalf("expected the new image to be held back before the prechecks ran")
+	}
+	if res := cc.ReconcileUpgrade(); !res.Completed() {
+		t.Fatalf("expected a requeue once the prechecks passed")
+	}
+	upgrade := cr.Status.Upgrade
+	if upgrade.State != marklogicv1.UpgradeStateInProgress || upgrade.StartTime == nil || rolloutImage(cr) != upgradeTestPatchImage {
+		t.Fatalf("expected the upgrade to be in progress, got %+v", upgrade)
+	}
+	if len(upgrade.Prechecks) != len(RegisteredPrechecks()) {
+		t.Fatalf("expected a result per registered precheck, got %+v", upgrade.Prechecks)
+	}
+	if len(upgrade.Timeline) != 2 || upgrade.Timeline[0].State != marklogicv1.UpgradeStatePrecheck || upgrade.Timeline[1].Actor != OperatorActor {
+		t.Fatalf("expected precheck and start entries, got %+v", upgrade.Timeline)
+	}
+
+	if res := cc.ReconcileUpgrade(); !res.Completed() {
+		t.Fatalf("expected a requeue while pods are rolling out")
+	}
+	upgrade = cr.Status.Upgrade
+	if upgrade.UpdatedPods != 1 || upgrade.TotalPods != 2 {
+		t.Fatalf("expected 1/2 pods updated, got %+v", upgrade)
+	}
+
+	sts.Status.UpdatedReplicas, sts.Status.ReadyReplicas = 2, 2
+	if err := cc.Client.Status().Update(cc.Ctx, sts); err != nil {
+		t.Fatalf("failed to update statefulset: %v", err)
+	}
+	if res := cc.ReconcileUpgrade(); res.Completed() {
+		t.Fatalf("expected the completed upgrade to continue")
+	}
+	upgrade = cr.Status.Upgrade
+	if upgrade.State != marklogicv1.UpgradeStateCompleted || upgrade.CurrentImage != upgradeTestPatchImage || upgrade.CompletionTime == nil {
+		t.Fatalf("expected completed upgrade, got %+v", upgrade)
+	}
+	if len(upgrade.Timeline) != 3 || upgrade.Timeline[2].Actor != OperatorActor {
+		t.Fatalf("expected a completion entry by the operator, got %+v", upgrade.Timeline)
+	}
+}
+
+func TestReconcileUpgradeHeldByFailedPrecheck(t *testing.T) {
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			Image:           upgradeTestNewImage,
+			ClusterDomain:   "cluster.local",
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{{Name: "dnode", IsBootstrap: true}},
+		},
+		Status: marklogicv1.MarklogicClusterStatus{Upgrade: &marklogicv1.UpgradeStatus{
+			State:        marklogicv1.UpgradeStateCompleted,
+			CurrentImage: upgradeTestOldImage,
+		}},
+	}
+	cc := newUpgradeTestContext(t, cr)
+	forests := []mlmanage.ForestStatus{{Name: "Security", State: "error", DataSizeMB: 100, DeviceSpaceMB: 900}}
+	stubHealthyManagementClient(t, &forests)
+
+	for range 2 {
+		if res := cc.ReconcileUpgrade(); !res.Completed() {
+			t.Fatalf("expected a requeue while a precheck fails")
+		}
+	}
+	upgrade := cr.Status.Upgrade
+	if upgrade.State != marklogicv1.UpgradeStateFailed || rolloutImage(cr) != upgradeTestOldImage {
+		t.Fatalf("expected the upgrade to be held back, got %+v", upgrade)
+	}
+	if len(upgrade.Timeline) != 2 {
+		t.Fatalf("expected an unchanged failure not to be recorded twice, got %+v", upgrade.Timeline)
+	}
+
+	forests[0].State = "open"
+	cc.ReconcileUpgrade()
+	if cr.Status.Upgrade.State != marklogicv1.UpgradeStateInProgress {
+		t.Fatalf("expected the upgrade to start once the prechecks pass, got %+v", cr.Status.Upgrade)
+	}
+}
+
+func TestReconcileUpgradeWaitsForApproval(t *testing.T) {
+	gates, err := features.Parse("InteractiveUpgrade=true")
+	if err != nil {
+		t.Fatalf("failed to parse feature gates: %v", err)
+	}
+	original := features.Default
+	features.Default = gates
+	t.Cleanup(func() { features.Default = original })
+
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			Image:           upgradeTestPatchImage,
+			ClusterDomain:   "cluster.local",
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{{Name: "dnode", IsBootstrap: true}},
+		},
+		Status: marklogicv1.MarklogicClusterStatus{Upgrade: &marklogicv1.UpgradeStatus{
+			State:        marklogicv1.UpgradeStateCompleted,
+			CurrentImage: upgradeTestOldImage,
+		}},
+	}
+	cc := newUpgradeTestContext(t, cr)
+	forests := []mlmanage.ForestStatus{{Name: "Documents", State: "open", DataSizeMB: 100, DeviceSpaceMB: 900}}
+	stubHealthyManagementClient(t, &forests)
+
+	for range 2 {
+		if res := cc.ReconcileUpgrade(); res.Completed() {
+			t.Fatalf("expected the upgrade to wait without a requeue")
+		}
+	}
+	upgrade := cr.Status.Upgrade
+	if upgrade.State != marklogicv1.UpgradeStateWaitingForUserApproval || rolloutImage(cr) != upgradeTestOldImage {
+		t.Fatalf("expected the upgrade to wait for approval, got %+v", upgrade)
+	}
+	if len(upgrade.Timeline) != 2 {
+		t.Fatalf("expected the wait to be recorded once, got %+v", upgrade.Timeline)
+	}
+	if approval := upgrade.PendingApproval; approval == nil || approval.Image != upgradeTestPatchImage ||
+		approval.Annotation != UpgradeApprovalAnnotation || approval.Field != "spec.operations.approveUpgrade" {
+		t.Fatalf("expected the pending approval to be reported, got %+v", approval)
+	}
+	if status := cr.Status.GetConditionStatus(string(marklogicv1.UpgradePendingApproval)); status != metav1.ConditionTrue {
+		t.Fatalf("expected the UpgradePendingApproval condition to be True, got %s", status)
+	}
+
+	cr.Annotations = map[string]string{UpgradeApprovalAnnotation: upgradeTestNewImage}
+	if err := cc.Client.Update(cc.Ctx, cr); err != nil {
+		t.Fatalf("failed to annotate cluster: %v", err)
+	}
+	cc.ReconcileUpgrade()
+	if cr.Status.Upgrade.State != marklogicv1.UpgradeStateWaitingForUserApproval {
+		t.Fatalf("expected an approval of another image to be ignored, got %+v", cr.Status.Upgrade)
+	}
+
+	cr.Annotations[UpgradeApprovalAnnotation] = upgradeTestPatchImage
+	if err := cc.Client.Update(cc.Ctx, cr); err != nil {
+		t.Fatalf("failed to annotate cluster: %v", err)
+	}
+	if res := cc.ReconcileUpgrade(); !res.Completed() {
+		t.Fatalf("expected a requeue once the upgrade was approved")
+	}
+	if cr.Status.Upgrade.State != marklogicv1.UpgradeStateInProgress || rolloutImage(cr) != upgradeTestPatchImage {
+		t.Fatalf("expected the approved upgrade to roll out, got %+v", cr.Status.Upgrade)
+	}
+	if cr.Status.Upgrade.PendingApproval != nil || cr.Status.GetConditionStatus(string(marklogicv1.UpgradePendingApproval)) != metav1.ConditionFalse {
+		t.Fatalf("expected the approved upgrade not to wait for approval, got %+v", cr.Status)
+	}
+}
+
+func TestReconcileMajorUpgradeUpgradesBootstrapFirst(t *testing.T) {
+	replicas := int32(1)
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			Image:         upgradeTestNewImage,
+			ClusterDomain: "cluster.local",
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{
+				{Name: "dnode", Replicas: &replicas, IsBootstrap: true},
+				{Name: "enode", Replicas: &replicas},
+			},
+		},
+		Status: marklogicv1.MarklogicClusterStatus{Upgrade: &marklogicv1.UpgradeStatus{
+			State:        marklogicv1.UpgradeStateIdle,
+			CurrentImage: upgradeTestOldImage,
+		}},
+	}
+	dnode := newUpgradeTestStatefulSet("dnode", upgradeTestNewImage, 0)
+	enode := newUpgradeTestStatefulSet("enode", upgradeTestOldImage, 1)
+	cc := newUpgradeTestContext(t, cr, dnode, enode)
+	forests := []mlmanage.ForestStatus{{Name: "Security", State: "open", DataSizeMB: 100, DeviceSpaceMB: 900}}
+	stubHealthyManagementClient(t, &forests)
+	bootstrapVersion := "11.3-1"
+	securityUpgraded := false
+	precheckClient := NewDynamicManagementClient
+	NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+		stub := precheckClient(opts).(*stubDynamicManagementClient)
+		stub.hostsStatusFn = func() ([]mlmanage.HostStatus, error) {
+			return []mlmanage.HostStatus{{Name: "dnode-0.dnode.default.svc.cluster.local", Online: true, Version: bootstrapVersion}}, nil
+		}
+		stub.upgradeSecurityFn = func() (bool, error) {
+			if securityUpgraded {
+				return true, nil
+			}
+			securityUpgraded = true
+			return false, nil
+		}
+		return stub
+	}
+
+	cc.ReconcileUpgrade()
+	upgrade := cr.Status.Upgrade
+	if upgrade.State != marklogicv1.UpgradeStateInProgress || upgrade.Step != marklogicv1.UpgradeStepBootstrapGroup {
+		t.Fatalf("expected the bootstrap group to be upgraded first, got %+v", upgrade)
+	}
+	if groupRolloutImage(cr, true) != upgradeTestNewImage || groupRolloutImage(cr, false) != upgradeTestOldImage {
+		t.Fatalf("expected only the bootstrap group to get the new image")
+	}
+
+	cc.ReconcileUpgrade()
+	if cr.Status.Upgrade.Step != marklogicv1.UpgradeStepBootstrapGroup {
+		t.Fatalf("expected to wait for the bootstrap pods, got %+v", cr.Status.Upgrade)
+	}
+
+	dnode.Status.UpdatedReplicas, dnode.Status.ReadyReplicas = 1, 1
+	if err := cc.Client.Status().Update(cc.Ctx, dnode); err != nil {
+		t.Fatalf("failed to update statefulset: %v", err)
+	}
+	cc.ReconcileUpgrade()
+	if cr.Status.Upgrade.Step != marklogicv1.UpgradeStepSecurityDatabase || securityUpgraded {
+		t.Fatalf("expected to wait for the bootstrap host to run the new release, got %+v", cr.Status.Upgrade)
+	}
+
+	bootstrapVersion = "12.0-3"
+	cc.ReconcileUpgrade()
+	if cr.Status.Upgrade.Step != marklogicv1.UpgradeStepSecurityDatabase || !securityUpgraded {
+		t.Fatalf("expected the Security database upgrade to run, got %+v", cr.Status.Upgrade)
+	}
+	cc.ReconcileUpgrade()
+	if cr.Status.Upgrade.Step != marklogicv1.UpgradeStepRemainingGroups || groupRolloutImage(cr, false) != upgradeTestNewImage {
+		t.Fatalf("expected the remaining groups to be upgraded, got %+v", cr.Status.Upgrade)
+	}
+
+	enode.Spec.Template.Spec.Containers[0].Image = upgradeTestNewImage
+	if err := cc.Client.Update(cc.Ctx, enode); err != nil {
+		t.Fatalf("failed to update statefulset: %v", err)
+	}
+	cc.ReconcileUpgrade()
+	if cr.Status.Upgrade.State != marklogicv1.UpgradeStateCompleted {
+		t.Fatalf("expected the upgrade to complete, got %+v", cr.Status.Upgrade)
+	}
+}
+
+func TestReconcileUpgradeCancelledWhenImageReverted(t *testing.T) {
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			Image:           upgradeTestOldImage,
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{{Name: "dnode", IsBootstrap: true}},
+		},
+		Status: marklogicv1.MarklogicClusterStatus{Upgrade: &marklogicv1.UpgradeStatus{
+			State:        marklogicv1.UpgradeStateInProgress,
+			CurrentImage: upgradeTestOldImage,
+			TargetImage:  upgradeTestNewImage,
+		}},
+	}
+	cc := newUpgradeTestContext(t, cr)
+
+	if res := cc.ReconcileUpgrade(); res.Completed() {
+		t.Fatalf("expected the cancelled upgrade to continue")
+	}
+	if cr.Status.Upgrade.State != marklogicv1.UpgradeStateIdle || cr.Status.Upgrade.TargetImage != "" {
+		t.Fatalf("expected the upgrade to return to idle, got %+v", cr.Status.Upgrade)
+	}
+}
+
+func TestSpecFieldManager(t *testing.T) {
+	cr := &marklogicv1.MarklogicCluster{ObjectMeta: metav1.ObjectMeta{ManagedFields: []metav1.ManagedFieldsEntry{
+		{Manager: "kubectl-client-side-apply", Operation: metav1.ManagedFieldsOperationUpdate, Time: &metav1.Time{Time: time.Unix(100, 0)},
+			FieldsType: "FieldsV1", FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:image":{},"f:markLogicGroups":{}}}`)}},
+		{Manager: "kubectl-edit", Operation: metav1.ManagedFieldsOperationUpdate, Time: &metav1.Time{Time: time.Unix(200, 0)},
+			FieldsType: "FieldsV1", FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:image":{}}}`)}},
+		{Manager: "marklogic-operator", Operation: metav1.ManagedFieldsOperationUpdate, Time: &metav1.Time{Time: time.Unix(300, 0)},
+			Subresource: "status", FieldsType: "FieldsV1", FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:status":{}}`)}},
+	}}}
+
+	if actor := specFieldManager(cr, "image"); actor != "kubectl-edit" {
+		t.Fatalf("expected kubectl-edit to own spec.image, got %s", actor)
+	}
+	if actor := specFieldManager(cr, "markLogicGroups"); actor != "kubectl-client-side-apply" {
+		t.Fatalf("expected kubectl-client-side-apply to own spec.markLogicGroups, got %s", actor)
+	}
+	if actor := specFieldManager(cr, "storage"); actor != OperatorActor {
+		t.Fatalf("expected fallback to %s, got %s", OperatorActor, actor)
+	}
+}
+
+func TestEstimateUpgradeImpact(t *testing.T) {
+	cr := newPrecheckTestCluster()
+	cr.Spec.MarkLogicGroups = append(cr.Spec.MarkLogicGroups, &marklogicv1.MarklogicGroups{Name: "custom", Image: upgradeTestOldImage})
+	pod := func(name, group, image string) *corev1.Pod {
+		pod := newStorageTestPod(name)
+		pod.Labels["app.kubernetes.io/instance"] = group
+		pod.Spec.Containers = []corev1.Container{{Name: "marklogic-server", Image: image}}
+		return pod
+	}
+	cc := newUpgradeTestContext(t, cr,
+		pod("dnode-0", "dnode", upgradeTestOldImage), pod("dnode-1", "dnode", upgradeTestOldImage),
+		pod("dnode-2", "dnode", upgradeTestNewImage), pod("custom-0", "custom", upgradeTestOldImage))
+	original := NewDynamicManagementClient
+	NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+		return &stubDynamicManagementClient{forestsStatusFn: func() ([]mlmanage.ForestStatus, error) {
+			return []mlmanage.ForestStatus{
+				{Name: "Security", Host: "dnode-0.dnode.default.svc.cluster.local", Database: "Security"},
+				{Name: "Documents-1", Host: "dnode-1.dnode.default.svc.cluster.local", Database: "Documents"},
+				{Name: "Documents-2", Host: "dnode-2.dnode.default.svc.cluster.local", Database: "Documents"},
+				{Name: "Meters", Host: "custom-0.custom.default.svc.cluster.local", Database: "Meters"},
+			}, nil
+		}}
+	}
+	t.Cleanup(func() { NewDynamicManagementClient = original })
+	upgrade := &marklogicv1.UpgradeStatus{TargetImage: upgradeTestNewImage}
+
+	impact, err := cc.estimateUpgradeImpact(upgrade)
+	if err != nil {
+		t.Fatalf("failed to estimate the impact: %v", err)
+	}
+	// dnode-2 already runs the new image and custom-0 does not follow spec.image.
+	if impact.Restarts != 2 || impact.PodDowntime != nil || !slices.Equal(impact.Databases, []string{"Documents", "Security"}) {
+		t.Fatalf("unexpected impact without history %+v", impact)
+	}
+
+	for _, d := range []time.Duration{time.Minute, 3 * time.Minute, 90 * time.Second} {
+		upgrade.RecordPodStart(d)
+	}
+	impact, _ = cc.estimateUpgradeImpact(upgrade)
+	if impact.PodDowntime == nil || impact.PodDowntime.Duration != 90*time.Second || impact.Duration.Duration != 3*time.Minute ||
+		impact.Message != "restarts 2 pod(s) one at a time, each down for about 1m30s (median of 3 restarts), about 3m0s in total, affecting Documents, Security" {
+		t.Fatalf("unexpected impact with history %+v", impact)
+	}
+	for i := 0; i < marklogicv1.MaxPodStartHistory; i++ {
+		upgrade.RecordPodStart(time.Minute)
+	}
+	if len(upgrade.PodStartHistory) != marklogicv1.MaxPodStartHistory {
+		t.Fatalf("expected the history to be bounded, got %d entries", len(upgrade.PodStartHistory))
+	}
+}
+
+func TestUpgradeTimelineIsBounded(t *testing.T) {
+	upgrade := &marklogicv1.UpgradeStatus{}
+	for i := 0; i < marklogicv1.MaxUpgradeTimelineEntries+5; i++ {
+		upgrade.RecordTransition(marklogicv1.UpgradeStateInProgress, OperatorActor, "step", metav1.Now())
+	}
+	if len(upgrade.Timeline) != marklogicv1.MaxUpgradeTimelineEntries {
+		t.Fatalf("expected %d timeline entries, got %d", marklogicv1.MaxUpgradeTimelineEntries, len(upgrade.Timeline))
+	}
+}
+
+func newUpgradeTestPod(name, revision string, ready bool) *corev1.Pod {
+	pod := newStorageTestPod(name)
+	pod.Labels[appsv1.StatefulSetRevisionLabel] = revision
+	if ready {
+		pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+	}
+	return pod
+}
+
+func TestReconcileUpgradeRestartsPodsBehindHealthGates(t *testing.T) {
+	replicas := int32(2)
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			Image:           upgradeTestPatchImage,
+			ClusterDomain:   "cluster.local",
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{{Name: "dnode", Replicas: &replicas, IsBootstrap: true}},
+			Upgrade: &marklogicv1.UpgradeSpec{HealthGate: []marklogicv1.HealthGate{
+				{Name: "online", Type: marklogicv1.HealthGateHostOnline, Timeout: &metav1.Duration{Duration: time.Minute}},
+				{Name: "ready-endpoint", Type: marklogicv1.HealthGateHTTP, FailurePolicy: marklogicv1.HealthGateIgnore,
+					Timeout: &metav1.Duration{Duration: time.Minute}, HTTP: &marklogicv1.HTTPHealthGate{Path: "ready", Port: 8080}},
+			}},
+		},
+		Status: marklogicv1.MarklogicClusterStatus{Upgrade: &marklogicv1.UpgradeStatus{
+			State:          marklogicv1.UpgradeStateInProgress,
+			CurrentImage:   upgradeTestOldImage,
+			TargetImage:    upgradeTestPatchImage,
+			RolloutStarted: true,
+		}},
+	}
+	sts := newUpgradeTestStatefulSet("dnode", upgradeTestPatchImage, 0)
+	sts.Spec.UpdateStrategy.Type = appsv1.OnDeleteStatefulSetStrategyType
+	sts.Status.UpdateRevision = "dnode-v2"
+	cc := newUpgradeTestContext(t, cr, sts, newUpgradeTestPod("dnode-0", "dnode-v1", true), newUpgradeTestPod("dnode-1", "dnode-v1", true))
+	online := false
+	original := NewDynamicManagementClient
+	NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+		return &stubDynamicManagementClient{hostsStatusFn: func() ([]mlmanage.HostStatus, error) {
+			return []mlmanage.HostStatus{{Name: "dnode-1.dnode.default.svc.cluster.local", Online: online}}, nil
+		}}
+	}
+	t.Cleanup(func() { NewDynamicManagementClient = original })
+	originalGet := HealthGateHTTPGet
+	t.Cleanup(func() { HealthGateHTTPGet = originalGet })
+	HealthGateHTTPGet = func(ctx context.Context, url string) (int, error) {
+		if url != "http://dnode-1.dnode.default.svc.cluster.local:8080/ready" {
+			t.Fatalf("unexpected health gate url %s", url)
+		}
+		return 503, nil
+	}
+	podExists := func(name string) bool {
+		return cc.Client.Get(cc.Ctx, client.ObjectKey{Namespace: "default", Name: name}, &corev1.Pod{}) == nil
+	}
+
+	cc.ReconcileUpgrade()
+	if restart := cr.Status.Upgrade.PodRestart; restart == nil || restart.Pod != "dnode-1" || podExists("dnode-1") || !podExists("dnode-0") {
+		t.Fatalf("expected only the highest ordinal pod to be restarted, got %+v", cr.Status.Upgrade)
+	}
+
+	if err := cc.Client.Create(cc.Ctx, newUpgradeTestPod("dnode-1", "dnode-v2", true)); err != nil {
+		t.Fatalf("failed to recreate pod: %v", err)
+	}
+	cc.ReconcileUpgrade()
+	if cr.Status.Upgrade.Message != "waiting for health gate online of pod dnode-1: host is offline" || !podExists("dnode-0") {
+		t.Fatalf("expected to wait for the host to come online, got %+v", cr.Status.Upgrade)
+	}
+
+	online = true
+	cr.Status.Upgrade.PodRestart.StartTime = metav1.NewTime(time.Now().Add(-2 * time.Minute))
+	cc.ReconcileUpgrade()
+	if restart := cr.Status.Upgrade.PodRestart; restart == nil || restart.Pod != "dnode-0" || podExists("dnode-0") {
+		t.Fatalf("expected the ignored gate to let the rollout continue with dnode-0, got %+v", cr.Status.Upgrade)
+	}
+	if history := cr.Status.Upgrade.PodStartHistory; len(history) != 1 || history[0].Duration < 2*time.Minute {
+		t.Fatalf("expected the start time of dnode-1 to be recorded, got %v", history)
+	}
+
+	if err := cc.Client.Create(cc.Ctx, newUpgradeTestPod("dnode-0", "dnode-v2", false)); err != nil {
+		t.Fatalf("failed to recreate pod: %v", err)
+	}
+	cr.Status.Upgrade.PodRestart.StartTime = metav1.NewTime(time.Now().Add(-2 * time.Minute))
+	cc.ReconcileUpgrade()
+	upgrade := cr.Status.Upgrade
+	if upgrade.State != marklogicv1.UpgradeStateFailed || upgrade.Message != "health gate pod-ready of pod dnode-0 did not pass within 1m0s: pod is not ready" {
+		t.Fatalf("expected the upgrade to fail on the unready pod, got %+v", upgrade)
+	}
+	if rolloutImage(cr) != upgradeTestPatchImage {
+		t.Fatalf("expected a failed rollout to keep the target image, got %s", rolloutImage(cr))
+	}
+}
+
+func TestReconcileUpgradeChecksTheReplacementPod(t *testing.T) {
+	replicas := int32(2)
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			Image:           upgradeTestPatchImage,
+			ClusterDomain:   "cluster.local",
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{{Name: "dnode", Replicas: &replicas, IsBootstrap: true}},
+			Upgrade: &marklogicv1.UpgradeSpec{HealthGate: []marklogicv1.HealthGate{
+				{Name: "online", Type: marklogicv1.HealthGateHostOnline, Timeout: &metav1.Duration{Duration: time.Minute}},
+			}},
+		},
+		Status: marklogicv1.MarklogicClusterStatus{Upgrade: &marklogicv1.UpgradeStatus{
+			State:          marklogicv1.UpgradeStateInProgress,
+			CurrentImage:   upgradeTestOldImage,
+			TargetImage:    upgradeTestPatchImage,
+			RolloutStarted: true,
+		}},
+	}
+	sts := newUpgradeTestStatefulSet("dnode", upgradeTestPatchImage, 0)
+	sts.Spec.UpdateStrategy.Type = appsv1.OnDeleteStatefulSetStrategyType
+	sts.Status.UpdateRevision = "dnode-v2"
+	// The finalizer keeps the deleted pod terminating, and still ready.
+	terminating := newUpgradeTestPod("dnode-1", "dnode-v1", true)
+	terminating.UID = "dnode-1-v1"
+	terminating.Finalizers = []string{"test/drain"}
+	cc := newUpgradeTestContext(t, cr, sts, newUpgradeTestPod("dnode-0", "dnode-v1", true), terminating)
+	original := NewDynamicManagementClient
+	NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+		return &stubDynamicManagementClient{hostsStatusFn: func() ([]mlmanage.HostStatus, error) {
+			return []mlmanage.HostStatus{{Name: "dnode-1.dnode.default.svc.cluster.local", Online: true}}, nil
+		}}
+	}
+	t.Cleanup(func() { NewDynamicManagementClient = original })
+	key := client.ObjectKey{Namespace: "default", Name: "dnode-1"}
+	waiting := "waiting for health gate pod-ready of pod dnode-1: pod has not been replaced yet"
+
+	cc.ReconcileUpgrade()
+	if restart := cr.Status.Upgrade.PodRestart; restart == nil || restart.Pod != "dnode-1" || restart.PodUID != "dnode-1-v1" {
+		t.Fatalf("expected the restart of dnode-1 to record its UID, got %+v", cr.Status.Upgrade.PodRestart)
+	}
+	cc.ReconcileUpgrade()
+	if cr.Status.Upgrade.PodRestart == nil || cr.Status.Upgrade.Message != waiting {
+		t.Fatalf("expected to wait for the terminating pod to be replaced, got %+v", cr.Status.Upgrade)
+	}
+
+	pod := &corev1.Pod{}
+	if err := cc.Client.Get(cc.Ctx, key, pod); err != nil {
+		t.Fatal(err)
+	}
+	pod.Finalizers = nil
+	if err := cc.Client.Update(cc.Ctx, pod); err != nil {
+		t.Fatalf("failed to remove the finalizer: %v", err)
+	}
+	// A stale read can return the deleted pod without its deletion timestamp.
+	stale := newUpgradeTestPod("dnode-1", "dnode-v1", true)
+	stale.UID = "dnode-1-v1"
+	if err := cc.Client.Create(cc.Ctx, stale); err != nil {
+		t.Fatalf("failed to create pod: %v", err)
+	}
+	cc.ReconcileUpgrade()
+	if cr.Status.Upgrade.PodRestart == nil || cr.Status.Upgrade.Message != waiting {
+		t.Fatalf("expected to wait for a pod with a new UID, got %+v", cr.Status.Upgrade)
+	}
+
+	if err := cc.Client.Delete(cc.Ctx, stale); err != nil {
+		t.Fatal(err)
+	}
+	replacement := newUpgradeTestPod("dnode-1", "dnode-v2", true)
+	replacement.UID = "dnode-1-v2"
+	if err := cc.Client.Create(cc.Ctx, replacement); err != nil {
+		t.Fatalf("failed to create pod: %v", err)
+	}
+	cc.ReconcileUpgrade()
+	if restart := cr.Status.Upgrade.PodRestart; restart == nil || restart.Pod != "dnode-0" {
+		t.Fatalf("expected the replacement pod to pass the gates, got %+v", cr.Status.Upgrade)
+	}
+}
+
+func TestReconcileUpgradePausedBeforeNextPod(t *testing.T) {
+	replicas := int32(2)
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "ml",
+			Namespace:   "default",
+			Annotations: map[string]string{PauseUpgradeAnnotation: "true"},
+		},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			Image:           upgradeTestPatchImage,
+			ClusterDomain:   "cluster.local",
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{{Name: "dnode", Replicas: &replicas, IsBootstrap: true}},
+		},
+		Status: marklogicv1.MarklogicClusterStatus{Upgrade: &marklogicv1.UpgradeStatus{
+			State:          marklogicv1.UpgradeStateInProgress,
+			CurrentImage:   upgradeTestOldImage,
+			TargetImage:    upgradeTestPatchImage,
+			RolloutStarted: true,
+		}},
+	}
+	sts := newUpgradeTestStatefulSet("dnode", upgradeTestPatchImage, 0)
+	sts.Spec.UpdateStrategy.Type = appsv1.OnDeleteStatefulSetStrategyType
+	sts.Status.UpdateRevision = "dnode-v2"
+	cc := newUpgradeTestContext(t, cr, sts, newUpgradeTestPod("dnode-0", "dnode-v1", true), newUpgradeTestPod("dnode-1", "dnode-v1", true))
+	podExists := func(name string) bool {
+		return cc.Client.Get(cc.Ctx, client.ObjectKey{Namespace: "default", Name: name}, &corev1.Pod{}) == nil
+	}
+
+	for range 2 {
+		if res := cc.ReconcileUpgrade(); res.Completed() {
+			t.Fatalf("expected the paused upgrade to wait for the pause to be lifted")
+		}
+	}
+	upgrade := cr.Status.Upgrade
+	if !upgrade.Paused || upgrade.PodRestart != nil || !podExists("dnode-1") || len(upgrade.Timeline) != 1 {
+		t.Fatalf("expected the upgrade to pause once before restarting dnode-1, got %+v", upgrade)
+	}
+	health, err := cc.assessClusterHealth(nil)
+	if err != nil || health.status != clusterSuspended || health.reason != upgradeReasonPaused {
+		t.Fatalf("expected a paused upgrade to suspend the cluster, got %+v %v", health, err)
+	}
+
+	delete(cr.Annotations, PauseUpgradeAnnotation)
+	if err := cc.Client.Update(cc.Ctx, cr); err != nil {
+		t.Fatalf("failed to lift the pause: %v", err)
+	}
+	cc.ReconcileUpgrade()
+	upgrade = cr.Status.Upgrade
+	if upgrade.Paused || upgrade.PodRestart == nil || upgrade.PodRestart.Pod != "dnode-1" || podExists("dnode-1") {
+		t.Fatalf("expected the resumed upgrade to restart dnode-1, got %+v", upgrade)
+	}
+	if resumed := upgrade.Timeline[1]; resumed.Message != "upgrade to "+upgradeTestPatchImage+" resumed" {
+		t.Fatalf("expected the resume to be recorded, got %+v", upgrade.Timeline)
+	}
+}
+
+func TestReconcileUpgradeRetriedOnRequest(t *testing.T) {
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			Image:           upgradeTestNewImage,
+			ClusterDomain:   "cluster.local",
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{{Name: "dnode", IsBootstrap: true}},
+		},
+		Status: marklogicv1.MarklogicClusterStatus{Upgrade: &marklogicv1.UpgradeStatus{
+			State:        marklogicv1.UpgradeStateCompleted,
+			CurrentImage: upgradeTestOldImage,
+		}},
+	}
+	cc := newUpgradeTestContext(t, cr)
+	forests := []mlmanage.ForestStatus{{Name: "Security", State: "error", DataSizeMB: 100, DeviceSpaceMB: 900}}
+	stubHealthyManagementClient(t, &forests)
+
+	cc.ReconcileUpgrade()
+	cr.Spec.Operations = &marklogicv1.Operations{RetryUpgrade: "1"}
+	if err := cc.Client.Update(cc.Ctx, cr); err != nil {
+		t.Fatalf("failed to request a retry: %v", err)
+	}
+	for range 2 {
+		cc.ReconcileUpgrade()
+	}
+	upgrade := cr.Status.Upgrade
+	if upgrade.State != marklogicv1.UpgradeStateFailed || upgrade.RetryRequestID != "1" || len(upgrade.Timeline) != 4 {
+		t.Fatalf("expected one retry that failed its prechecks again, got %+v", upgrade)
+	}
+	if retry := upgrade.Timeline[2]; retry.Message != "retry 1 requested, running prechecks for upgrade to "+upgradeTestNewImage {
+		t.Fatalf("expected the retry to be recorded, got %+v", upgrade.Timeline)
+	}
+
+	forests[0].State = "open"
+	cr.Spec.Operations.RetryUpgrade = "2"
+	if err := cc.Client.Update(cc.Ctx, cr); err != nil {
+		t.Fatalf("failed to request a retry: %v", err)
+	}
+	cc.ReconcileUpgrade()
+	if upgrade := cr.Status.Upgrade; upgrade.State != marklogicv1.UpgradeStateInProgress || upgrade.RetryRequestID != "2" {
+		t.Fatalf("expected the retried upgrade to start, got %+v", upgrade)
+	}
+}
+
+func TestReconcileResourceRolloutRestartsPodsWithOutdatedResources(t *testing.T) {
+	replicas := int32(2)
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			Image:           upgradeTestOldImage,
+			ClusterDomain:   "cluster.local",
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{{Name: "dnode", Replicas: &replicas, IsBootstrap: true}},
+			Upgrade: &marklogicv1.UpgradeSpec{HealthGate: []marklogicv1.HealthGate{
+				{Name: "online", Type: marklogicv1.HealthGateHostOnline, Timeout: &metav1.Duration{Duration: time.Minute}},
+			}},
+		},
+		Status: marklogicv1.MarklogicClusterStatus{Upgrade: &marklogicv1.UpgradeStatus{
+			State:          marklogicv1.UpgradeStateInProgress,
+			CurrentImage:   upgradeTestOldImage,
+			TargetImage:    upgradeTestPatchImage,
+			RolloutStarted: true,
+		}},
+	}
+	setRolloutLock(cr, rolloutLockUpgrade, true, "upgrade restarts pods")
+	sts := newUpgradeTestStatefulSet("dnode", upgradeTestOldImage, 2)
+	sts.Spec.Replicas = &replicas
+	sts.Spec.UpdateStrategy.Type = appsv1.OnDeleteStatefulSetStrategyType
+	sts.Spec.Template.Spec.Containers[0].Resources = corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")},
+		Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("8Gi")},
+	}
+	resourcePod := func(name, cpu string) *corev1.Pod {
+		pod := newUpgradeTestPod(name, "dnode-v1", true)
+		pod.UID = types.UID(name + "-" + cpu)
+		pod.Spec.Containers = []corev1.Container{{Name: "marklogic-server", Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu), corev1.ResourceMemory: resource.MustParse("8Gi")},
+			Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("8Gi")},
+		}}}
+		return pod
+	}
+	cc := newUpgradeTestContext(t, cr, sts, resourcePod("dnode-0", "4000m"), resourcePod("dnode-1", "2"))
+	original := NewDynamicManagementClient
+	NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+		return &stubDynamicManagementClient{hostsStatusFn: func() ([]mlmanage.HostStatus, error) {
+			return []mlmanage.HostStatus{{Name: "dnode-1.dnode.default.svc.cluster.local", Online: true}}, nil
+		}}
+	}
+	t.Cleanup(func() { NewDynamicManagementClient = original })
+	podExists := func(name string) bool {
+		return cc.Client.Get(cc.Ctx, client.ObjectKey{Namespace: "default", Name: name}, &corev1.Pod{}) == nil
+	}
+
+	if res := cc.ReconcileResourceRollout(); res.Completed() || !podExists("dnode-1") {
+		t.Fatalf("expected the rollout to wait for the running upgrade")
+	}
+
+	if res := cc.setUpgradeStatus(&marklogicv1.UpgradeStatus{State: marklogicv1.UpgradeStateCompleted, CurrentImage: upgradeTestOldImage}, result.Continue()); res.Completed() {
+		t.Fatalf("failed to complete the upgrade")
+	}
+	if holder := rolloutLockHolder(cr); holder != "" {
+		t.Fatalf("expected the completed upgrade to release the rollout lock, held by %q", holder)
+	}
+	cc.ReconcileResourceRollout()
+	if holder := rolloutLockHolder(cr); holder != rolloutLockResourceRollout {
+		t.Fatalf("expected the resource rollout to hold the rollout lock, held by %q", holder)
+	}
+	rollout := cr.Status.ResourceRollout
+	if rollout == nil || rollout.State != marklogicv1.ResourceRolloutInProgress || rollout.OutdatedPods != 1 ||
+		rollout.PodRestart == nil || rollout.PodRestart.Pod != "dnode-1" || rollout.PodRestart.PodUID != "dnode-1-2" ||
+		podExists("dnode-1") || !podExists("dnode-0") {
+		t.Fatalf("expected only the pod with outdated resources to be restarted, got %+v", rollout)
+	}
+
+	cc.ReconcileResourceRollout()
+	if rollout := cr.Status.ResourceRollout; rollout.State != marklogicv1.ResourceRolloutInProgress ||
+		rollout.Message != "waiting for health gate pod-ready of pod dnode-1: pod is not ready" {
+		t.Fatalf("expected to wait for the restarted pod, got %+v", rollout)
+	}
+
+	// A stale read of the deleted pod is not checked against the gates.
+	stale := resourcePod("dnode-1", "2")
+	if err := cc.Client.Create(cc.Ctx, stale); err != nil {
+		t.Fatalf("failed to create pod: %v", err)
+	}
+	cc.ReconcileResourceRollout()
+	if rollout := cr.Status.ResourceRollout; rollout.PodRestart == nil ||
+		rollout.Message != "waiting for health gate pod-ready of pod dnode-1: pod has not been replaced yet" {
+		t.Fatalf("expected to wait for the replacement pod, got %+v", rollout)
+	}
+	if err := cc.Client.Delete(cc.Ctx, stale); err != nil {
+		t.Fatal(err)
+	}
+	if err := cc.Client.Create(cc.Ctx, resourcePod("dnode-1", "4")); err != nil {
+		t.Fatalf("failed to recreate pod: %v", err)
+	}
+	cc.ReconcileResourceRollout()
+	rollout = cr.Status.ResourceRollout
+	if rollout.State != marklogicv1.ResourceRolloutCompleted || rollout.OutdatedPods != 0 || rollout.PodRestart != nil || !podExists("dnode-0") {
+		t.Fatalf("expected the rollout to complete, got %+v", rollout)
+	}
+}
+
+func TestReconcileResourceRolloutBatchesPodTemplateChanges(t *testing.T) {
+	replicas := int32(2)
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			Image:           upgradeTestOldImage,
+			ClusterDomain:   "cluster.local",
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{{Name: "dnode", Replicas: &replicas, IsBootstrap: true}},
+			Upgrade: &marklogicv1.UpgradeSpec{
+				HealthGate: []marklogicv1.HealthGate{
+					{Name: "online", Type: marklogicv1.HealthGateHostOnline, Timeout: &metav1.Duration{Duration: time.Minute}},
+				},
+				RestartBatching: &marklogicv1.RestartBatching{SettleWindow: &metav1.Duration{Duration: 2 * time.Minute}},
+			},
+		},
+	}
+	sts := newUpgradeTestStatefulSet("dnode", upgradeTestOldImage, 2)
+	sts.Spec.Replicas = &replicas
+	sts.Spec.UpdateStrategy.Type = appsv1.OnDeleteStatefulSetStrategyType
+	sts.Status.UpdateRevision = "dnode-v2"
+	templatePod := func(name, revision string) *corev1.Pod {
+		pod := newUpgradeTestPod(name, revision, true)
+		pod.Spec.Containers = []corev1.Container{{Name: "marklogic-server", Image: upgradeTestOldImage}}
+		return pod
+	}
+	cc := newUpgradeTestContext(t, cr, sts, templatePod("dnode-0", "dnode-v1"), templatePod("dnode-1", "dnode-v1"))
+	original := NewDynamicManagementClient
+	NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+		return &stubDynamicManagementClient{hostsStatusFn: func() ([]mlmanage.HostStatus, error) {
+			return []mlmanage.HostStatus{
+				{Name: "dnode-0.dnode.default.svc.cluster.local", Online: true},
+				{Name: "dnode-1.dnode.default.svc.cluster.local", Online: true},
+			}, nil
+		}}
+	}
+	t.Cleanup(func() { NewDynamicManagementClient = original })
+	podExists := func(name string) bool {
+		return cc.Client.Get(cc.Ctx, client.ObjectKey{Namespace: "default", Name: name}, &corev1.Pod{}) == nil
+	}
+
+	cc.ReconcileResourceRollout()
+	rollout := cr.Status.ResourceRollout
+	if rollout == nil || rollout.State != "" || rollout.OutdatedPods != 2 || rollout.SettlingUntil == nil || !podExists("dnode-1") {
+		t.Fatalf("expected the rollout to wait for further changes, got %+v", rollout)
+	}
+	if holder := rolloutLockHolder(cr); holder != "" {
+		t.Fatalf("expected the settling rollout not to hold the rollout lock, held by %q", holder)
+	}
+
+	// A second change arriving after the first settled starts the window again.
+	rollout.SettlingUntil = &metav1.Time{Time: time.Now().Add(-time.Second)}
+	sts.Status.UpdateRevision = "dnode-v3"
+	if err := cc.Client.Status().Update(cc.Ctx, sts); err != nil {
+		t.Fatalf("failed to update the StatefulSet: %v", err)
+	}
+	cc.ReconcileResourceRollout()
+	rollout = cr.Status.ResourceRollout
+	if !rollout.SettlingUntil.After(time.Now()) || rollout.Revisions[0] != "dnode=dnode-v3" || !podExists("dnode-1") {
+		t.Fatalf("expected the second change to restart the settle window, got %+v", rollout)
+	}
+
+	patchBase := client.MergeFrom(cr.DeepCopy())
+	rollout.SettlingUntil = &metav1.Time{Time: time.Now().Add(-time.Second)}
+	if err := cc.Client.Status().Patch(cc.Ctx, cr, patchBase); err != nil {
+		t.Fatalf("failed to end the settle window: %v", err)
+	}
+	cc.ReconcileResourceRollout()
+	rollout = cr.Status.ResourceRollout
+	if rollout.State != marklogicv1.ResourceRolloutInProgress || rollout.PodRestart == nil || rollout.PodRestart.Pod != "dnode-1" || podExists("dnode-1") || !podExists("dnode-0") {
+		t.Fatalf("expected one restart of dnode-1 for both changes, got %+v", rollout)
+	}
+
+	if err := cc.Client.Create(cc.Ctx, templatePod("dnode-1", "dnode-v3")); err != nil {
+		t.Fatalf("failed to recreate pod: %v", err)
+	}
+	cc.ReconcileResourceRollout()
+	if rollout := cr.Status.ResourceRollout; rollout.PodRestart == nil || rollout.PodRestart.Pod != "dnode-0" || podExists("dnode-0") {
+		t.Fatalf("expected dnode-0 to be restarted next, got %+v", rollout)
+	}
+	if err := cc.Client.Create(cc.Ctx, templatePod("dnode-0", "dnode-v3")); err != nil {
+		t.Fatalf("failed to recreate pod: %v", err)
+	}
+	cc.ReconcileResourceRollout()
+	rollout = cr.Status.ResourceRollout
+	if rollout.State != marklogicv1.ResourceRolloutCompleted || rollout.OutdatedPods != 0 || rollout.Message != "all pods run with the configuration of their group" {
+		t.Fatalf("expected the rollout to complete, got %+v", rollout)
+	}
+}
@@ -902,7 +902,7 @@ func (oc *OperatorContext) patchResizeStatus(status *marklogicv1.VolumeResizeSta
 
 	patchClient := client.MergeFrom(latest.DeepCopy())
 	latest.Status.VolumeResizeStatus = status
-	if err := oc.Client.Status().Patch(oc.Ctx, latest, patchClient); err != nil {
+	if err := patchStatus(oc.Ctx, oc.Client, latest, patchClient); err != nil {
 		return err
 	}
 
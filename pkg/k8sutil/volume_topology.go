@@ -0,0 +1,228 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	volumeTopologyRequeueSeconds = 30
+
+	volumeReasonNodeMissing  = "VolumeNodeMissing"
+	volumeReasonNodeExcluded = "VolumeNodeExcluded"
+	volumeReasonNoConflict   = "VolumeNodesSchedulable"
+)
+
+var nodeSelectorOperators = map[corev1.NodeSelectorOperator]selection.Operator{
+	corev1.NodeSelectorOpIn:           selection.In,
+	corev1.NodeSelectorOpNotIn:        selection.NotIn,
+	corev1.NodeSelectorOpExists:       selection.Exists,
+	corev1.NodeSelectorOpDoesNotExist: selection.DoesNotExist,
+	corev1.NodeSelectorOpGt:           selection.GreaterThan,
+	corev1.NodeSelectorOpLt:           selection.LessThan,
+}
+
+// ReconcileVolumeTopology records the node every local volume of the group is
+// bound to and checks that its pod can still run there. A pod whose volume is
+// on a node that no longer exists stays Pending, and the operator does not
+// move it: its data only lives on that node. A nodeSelector or node affinity
+// that excludes the node of a volume is not applied to the StatefulSet, since
+// the pod could neither stay nor be rescheduled.
+func (oc *OperatorContext) ReconcileVolumeTopology() result.ReconcileResult {
+	cr := oc.MarklogicGroup
+	var bindings []marklogicv1.VolumeNodeBinding
+	if cr.Spec.Persistence != nil && cr.Spec.Persistence.Enabled {
+		var err error
+		if bindings, err = oc.volumeNodeBindings(); err != nil {
+			return result.Error(err)
+		}
+	}
+	missing, excluded, err := oc.volumeTopologyConflicts(bindings)
+	if err != nil {
+		return result.Error(err)
+	}
+
+	condition := metav1.Condition{
+		Type:    string(marklogicv1.VolumeTopologyConflict),
+		Status:  metav1.ConditionFalse,
+		Reason:  volumeReasonNoConflict,
+		Message: "the pods can run on the nodes of their volumes",
+	}
+	problems := []string{}
+	if len(missing) > 0 {
+		condition.Reason = volumeReasonNodeMissing
+		problems = append(problems, fmt.Sprintf("the volumes of pods %s are on nodes that no longer exist, the pods stay Pending until the nodes return or the PersistentVolumeClaims are deleted",
+			strings.Join(missing, ", ")))
+	}
+	if len(excluded) > 0 {
+		condition.Reason = volumeReasonNodeExcluded
+		problems = append(problems, fmt.Sprintf("the nodeSelector or node affinity of the group excludes the nodes of the volumes of pods %s, the statefulSet is not updated",
+			strings.Join(excluded, ", ")))
+	}
+	if len(problems) > 0 {
+		condition.Status = metav1.ConditionTrue
+		condition.Message = strings.Join(problems, "; ")
+	}
+
+	patchBase := client.MergeFrom(cr.DeepCopy())
+	updated := !reflect.DeepEqual(cr.Status.VolumeBindings, bindings)
+	cr.Status.VolumeBindings = bindings
+	if current := meta.FindStatusCondition(cr.Status.Conditions, condition.Type); current == nil || current.Status != condition.Status ||
+		current.Reason != condition.Reason || current.Message != condition.Message {
+		if condition.Status == metav1.ConditionTrue {
+			oc.recordGroupEvent(corev1.EventTypeWarning, condition.Reason, condition.Message)
+		}
+		// A group without local volumes does not report the condition.
+		if current != nil || condition.Status == metav1.ConditionTrue {
+			condition.LastTransitionTime = metav1.Now()
+			if current != nil && current.Status == condition.Status {
+				condition.LastTransitionTime = current.LastTransitionTime
+			}
+			cr.SetCondition(condition)
+			updated = true
+		}
+	}
+	if updated {
+		if err := patchStatus(oc.Ctx, oc.Client, cr, patchBase); err != nil {
+			return result.Error(err)
+		}
+	}
+	if len(excluded) > 0 {
+		return result.RequeueSoon(volumeTopologyRequeueSeconds)
+	}
+	return result.Continue()
+}
+
+// volumeNodeBindings returns the data volumes of the group that are bound to
+// a PersistentVolume pinned to one node.
+func (oc *OperatorContext) volumeNodeBindings() ([]marklogicv1.VolumeNodeBinding, error) {
+	pvcs, err := oc.groupDataPVCs()
+	if err != nil {
+		return nil, err
+	}
+	bindings := []marklogicv1.VolumeNodeBinding{}
+	for _, pvc := range pvcs {
+		if pvc.Status.Phase != corev1.ClaimBound || pvc.Spec.VolumeName == "" {
+			continue
+		}
+		pv := &corev1.PersistentVolume{}
+		if err := oc.Client.Get(oc.Ctx, types.NamespacedName{Name: pvc.Spec.VolumeName}, pv); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		if node := volumeNode(pv); node != "" {
+			bindings = append(bindings, marklogicv1.VolumeNodeBinding{
+				PersistentVolumeClaim: pvc.Name,
+				Pod:                   derivePodNameFromPVC(oc.MarklogicGroup.Spec.Name, pvc.Name),
+				PersistentVolume:      pv.Name,
+				Node:                  node,
+			})
+		}
+	}
+	if len(bindings) == 0 {
+		return nil, nil
+	}
+	sort.Slice(bindings, func(i, j int) bool {
+		return parseOrdinalFromName(bindings[i].Pod) < parseOrdinalFromName(bindings[j].Pod)
+	})
+	return bindings, nil
+}
+
+// volumeNode returns the node a PersistentVolume is pinned to by a required
+// node affinity on the kubernetes.io/hostname label, as local volumes are.
+func volumeNode(pv *corev1.PersistentVolume) string {
+	if pv.Spec.NodeAffinity == nil || pv.Spec.NodeAffinity.Required == nil || len(pv.Spec.NodeAffinity.Required.NodeSelectorTerms) != 1 {
+		return ""
+	}
+	for _, expr := range pv.Spec.NodeAffinity.Required.NodeSelectorTerms[0].MatchExpressions {
+		if expr.Key == corev1.LabelHostname && expr.Operator == corev1.NodeSelectorOpIn && len(expr.Values) == 1 {
+			return expr.Values[0]
+		}
+	}
+	return ""
+}
+
+// volumeTopologyConflicts returns the pods whose volume is on a node that no
+// longer exists and those whose volume is on a node the scheduling
+// constraints of the group exclude.
+func (oc *OperatorContext) volumeTopologyConflicts(bindings []marklogicv1.VolumeNodeBinding) ([]string, []string, error) {
+	params := generateStatefulSetsParams(oc.MarklogicGroup)
+	missing, excluded := []string{}, []string{}
+	for _, binding := range bindings {
+		nodes := &corev1.NodeList{}
+		if err := oc.Client.List(oc.Ctx, nodes, client.MatchingLabels{corev1.LabelHostname: binding.Node}); err != nil {
+			return nil, nil, err
+		}
+		switch {
+		case len(nodes.Items) == 0:
+			missing = append(missing, binding.Pod)
+		case !nodeMatchesScheduling(&nodes.Items[0], params.NodeSelector, params.Affinity):
+			excluded = append(excluded, binding.Pod)
+		}
+	}
+	return missing, excluded, nil
+}
+
+// nodeMatchesScheduling reports whether a pod with the given nodeSelector and
+// affinity can be scheduled on the node. Only the required node affinity is
+// taken into account.
+func nodeMatchesScheduling(node *corev1.Node, nodeSelector map[string]string, affinity *corev1.Affinity) bool {
+	if !labels.SelectorFromSet(nodeSelector).Matches(labels.Set(node.Labels)) {
+		return false
+	}
+	if affinity == nil || affinity.NodeAffinity == nil || affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return true
+	}
+	// Terms are ORed.
+	for _, term := range affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+		if nodeSelectorTermMatches(node, term) {
+			return true
+		}
+	}
+	return false
+}
+
+func nodeSelectorTermMatches(node *corev1.Node, term corev1.NodeSelectorTerm) bool {
+	if len(term.MatchExpressions) == 0 && len(term.MatchFields) == 0 {
+		return false
+	}
+	selector := labels.NewSelector()
+	for _, expr := range term.MatchExpressions {
+		requirement, err := labels.NewRequirement(expr.Key, nodeSelectorOperators[expr.Operator], expr.Values)
+		if err != nil {
+			return false
+		}
+		selector = selector.Add(*requirement)
+	}
+	if !selector.Matches(labels.Set(node.Labels)) {
+		return false
+	}
+	for _, field := range term.MatchFields {
+		if field.Key != metav1.ObjectNameField {
+			continue
+		}
+		named := slices.Contains(field.Values, node.Name)
+		if (field.Operator == corev1.NodeSelectorOpIn && !named) || (field.Operator == corev1.NodeSelectorOpNotIn && named) {
+			return false
+		}
+	}
+	return true
+}
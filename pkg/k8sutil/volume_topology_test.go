@@ -0,0 +1,110 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"strings"
+	"testing"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newLocalVolumeTestObjects(pod, node string) (*corev1.PersistentVolumeClaim, *corev1.PersistentVolume) {
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "datadir-" + pod, Namespace: "default"},
+		Spec:       corev1.PersistentVolumeClaimSpec{VolumeName: "local-" + node},
+		Status:     corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimBound},
+	}
+	pv := &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "local-" + node},
+		Spec: corev1.PersistentVolumeSpec{
+			PersistentVolumeSource: corev1.PersistentVolumeSource{Local: &corev1.LocalVolumeSource{Path: "/mnt/disks/ssd1"}},
+			NodeAffinity: &corev1.VolumeNodeAffinity{Required: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+				MatchExpressions: []corev1.NodeSelectorRequirement{{Key: corev1.LabelHostname, Operator: corev1.NodeSelectorOpIn, Values: []string{node}}},
+			}}}},
+		},
+	}
+	return pvc, pv
+}
+
+func TestReconcileVolumeTopologyReportsConflicts(t *testing.T) {
+	group := newSecretRotationTestGroup()
+	group.Spec.Persistence = &marklogicv1.Persistence{Enabled: true, Size: "10Gi"}
+	pvc0, pv0 := newLocalVolumeTestObjects("dnode-0", "worker-a")
+	pvc1, pv1 := newLocalVolumeTestObjects("dnode-1", "worker-b")
+	nodeA := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-a", Labels: map[string]string{
+		corev1.LabelHostname: "worker-a",
+		"disktype":           "ssd",
+	}}}
+	oc := newSecretRotationTestContext(t, group, pvc0, pv0, pvc1, pv1, nodeA)
+
+	if res := oc.ReconcileVolumeTopology(); res.Completed() {
+		t.Fatalf("expected a missing node not to stop the reconcile")
+	}
+	bindings := group.Status.VolumeBindings
+	if len(bindings) != 2 || bindings[0].Pod != "dnode-0" || bindings[0].Node != "worker-a" || bindings[1].PersistentVolume != "local-worker-b" {
+		t.Fatalf("expected both volumes to be recorded, got %+v", bindings)
+	}
+	condition := meta.FindStatusCondition(group.Status.Conditions, string(marklogicv1.VolumeTopologyConflict))
+	if condition == nil || condition.Status != metav1.ConditionTrue || condition.Reason != volumeReasonNodeMissing ||
+		!strings.Contains(condition.Message, "pods dnode-1 are on nodes that no longer exist") {
+		t.Fatalf("expected the missing node to be reported, got %+v", condition)
+	}
+
+	// A nodeSelector that moves the pods off their nodes is not applied.
+	nodeB := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-b", Labels: map[string]string{corev1.LabelHostname: "worker-b"}}}
+	if err := oc.Client.Create(oc.Ctx, nodeB); err != nil {
+		t.Fatalf("failed to create the node: %v", err)
+	}
+	group.Spec.NodeSelector = map[string]string{"disktype": "ssd"}
+	if res := oc.ReconcileVolumeTopology(); !res.Completed() {
+		t.Fatalf("expected the statefulSet update to be held back")
+	}
+	condition = meta.FindStatusCondition(group.Status.Conditions, string(marklogicv1.VolumeTopologyConflict))
+	if condition == nil || condition.Reason != volumeReasonNodeExcluded || !strings.Contains(condition.Message, "volumes of pods dnode-1") {
+		t.Fatalf("expected the excluded node to be reported, got %+v", condition)
+	}
+
+	group.Spec.NodeSelector = nil
+	if res := oc.ReconcileVolumeTopology(); res.Completed() {
+		t.Fatalf("expected the reconcile to continue once the pods fit their nodes")
+	}
+	condition = meta.FindStatusCondition(group.Status.Conditions, string(marklogicv1.VolumeTopologyConflict))
+	if condition == nil || condition.Status != metav1.ConditionFalse {
+		t.Fatalf("expected the conflict to be cleared, got %+v", condition)
+	}
+}
+
+func TestNodeMatchesScheduling(t *testing.T) {
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-a", Labels: map[string]string{
+		corev1.LabelHostname:   "worker-a",
+		corev1.LabelArchStable: "amd64",
+	}}}
+	affinity := func(terms ...corev1.NodeSelectorTerm) *corev1.Affinity {
+		return &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: terms},
+		}}
+	}
+	arm := corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{
+		{Key: corev1.LabelArchStable, Operator: corev1.NodeSelectorOpIn, Values: []string{"arm64"}},
+	}}
+	named := corev1.NodeSelectorTerm{MatchFields: []corev1.NodeSelectorRequirement{
+		{Key: metav1.ObjectNameField, Operator: corev1.NodeSelectorOpIn, Values: []string{"worker-a"}},
+	}}
+
+	if !nodeMatchesScheduling(node, nil, nil) {
+		t.Fatalf("expected a pod without constraints to fit any node")
+	}
+	if nodeMatchesScheduling(node, map[string]string{corev1.LabelArchStable: "arm64"}, nil) {
+		t.Fatalf("expected the nodeSelector to exclude the node")
+	}
+	if nodeMatchesScheduling(node, nil, affinity(arm)) {
+		t.Fatalf("expected the node affinity to exclude the node")
+	}
+	if !nodeMatchesScheduling(node, nil, affinity(arm, named)) {
+		t.Fatalf("expected any matching term to admit the node")
+	}
+}
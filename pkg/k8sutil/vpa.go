@@ -0,0 +1,235 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"fmt"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const vpaReasonUnavailable = "VerticalPodAutoscalerUnavailable"
+
+var verticalPodAutoscalerGVK = schema.GroupVersionKind{Group: "autoscaling.k8s.io", Version: "v1", Kind: "VerticalPodAutoscaler"}
+
+// ReconcileVerticalPodAutoscalers creates a VerticalPodAutoscaler, named
+// like the group, for every group with autoscaling.vertical and deletes the
+// one of a group without it. The VPA updater never evicts MarkLogic pods: the
+// Auto mode is created with the Initial update mode, and the resource rollout
+// restarts the pods whose requests are outside the recommendation instead,
+// see podResourcesMatcher.
+func (cc *ClusterContext) ReconcileVerticalPodAutoscalers() result.ReconcileResult {
+	cr := cc.MarklogicCluster
+	for _, group := range cr.Spec.MarkLogicGroups {
+		if group == nil {
+			continue
+		}
+		vertical := groupVerticalAutoscaling(group)
+		var err error
+		if vertical != nil {
+			err = cc.applyVerticalPodAutoscaler(group, vertical)
+		} else {
+			err = cc.deleteVerticalPodAutoscaler(group.Name)
+		}
+		if meta.IsNoMatchError(err) {
+			if vertical != nil {
+				cc.recordClusterEvent(corev1.EventTypeWarning, vpaReasonUnavailable,
+					fmt.Sprintf("group %s has vertical autoscaling but the VerticalPodAutoscaler CRD is not installed", group.Name))
+			}
+			continue
+		}
+		if err != nil {
+			return result.Error(err)
+		}
+	}
+	return result.Continue()
+}
+
+func groupVerticalAutoscaling(group *marklogicv1.MarklogicGroups) *marklogicv1.VerticalAutoscaling {
+	if group.Autoscaling == nil {
+		return nil
+	}
+	return group.Autoscaling.Vertical
+}
+
+func (cc *ClusterContext) applyVerticalPodAutoscaler(group *marklogicv1.MarklogicGroups, vertical *marklogicv1.VerticalAutoscaling) error {
+	cr := cc.MarklogicCluster
+	desired := generateVerticalPodAutoscaler(cr, group, vertical)
+	desired.SetLabels(cc.GetClusterLabels(cr.Name))
+	desired.SetAnnotations(cr.Spec.AdditionalAnnotations)
+	current := &unstructured.Unstructured{}
+	current.SetGroupVersionKind(verticalPodAutoscalerGVK)
+	err := cc.Client.Get(cc.Ctx, types.NamespacedName{Namespace: cr.Namespace, Name: group.Name}, current)
+	if apierrors.IsNotFound(err) {
+		cc.ReqLogger.Info("Creating VerticalPodAutoscaler", "group", group.Name)
+		return cc.Client.Create(cc.Ctx, desired)
+	}
+	if err != nil {
+		return err
+	}
+	metadataChanged := syncMetadata(current, desired)
+	if !metadataChanged && equality.Semantic.DeepEqual(current.Object["spec"], desired.Object["spec"]) {
+		return nil
+	}
+	current.Object["spec"] = desired.Object["spec"]
+	cc.ReqLogger.Info("Updating VerticalPodAutoscaler", "group", group.Name)
+	return cc.Client.Update(cc.Ctx, current)
+}
+
+func (cc *ClusterContext) deleteVerticalPodAutoscaler(name string) error {
+	cr := cc.MarklogicCluster
+	current := &unstructured.Unstructured{}
+	current.SetGroupVersionKind(verticalPodAutoscalerGVK)
+	err := cc.Client.Get(cc.Ctx, types.NamespacedName{Namespace: cr.Namespace, Name: name}, current)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	// Leave VerticalPodAutoscalers the operator did not create alone.
+	if !metav1.IsControlledBy(current, cr) {
+		return nil
+	}
+	cc.ReqLogger.Info("Deleting VerticalPodAutoscaler", "group", name)
+	return client.IgnoreNotFound(cc.Client.Delete(cc.Ctx, current))
+}
+
+func generateVerticalPodAutoscaler(cr *marklogicv1.MarklogicCluster, group *marklogicv1.MarklogicGroups, vertical *marklogicv1.VerticalAutoscaling) *unstructured.Unstructured {
+	updateMode := string(marklogicv1.VerticalAutoscalingOff)
+	if vertical.Mode == marklogicv1.VerticalAutoscalingInitial || vertical.Mode == marklogicv1.VerticalAutoscalingAuto {
+		updateMode = string(marklogicv1.VerticalAutoscalingInitial)
+	}
+	policy := map[string]interface{}{
+		"containerName": "marklogic-server",
+		"mode":          "Auto",
+	}
+	if len(vertical.MinAllowed) > 0 {
+		policy["minAllowed"] = resourceListObject(vertical.MinAllowed)
+	}
+	if len(vertical.MaxAllowed) > 0 {
+		policy["maxAllowed"] = resourceListObject(vertical.MaxAllowed)
+	}
+	if vertical.ControlledValues != "" {
+		policy["controlledValues"] = vertical.ControlledValues
+	}
+	vpa := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"targetRef": map[string]interface{}{
+				"apiVersion": "apps/v1",
+				"kind":       "StatefulSet",
+				"name":       group.Name,
+			},
+			"updatePolicy": map[string]interface{}{
+				"updateMode": updateMode,
+			},
+			"resourcePolicy": map[string]interface{}{
+				// Sidecars such as fluent-bit keep their own resources.
+				"containerPolicies": []interface{}{
+					policy,
+					map[string]interface{}{"containerName": "*", "mode": "Off"},
+				},
+			},
+		},
+	}}
+	vpa.SetGroupVersionKind(verticalPodAutoscalerGVK)
+	vpa.SetName(group.Name)
+	vpa.SetNamespace(cr.Namespace)
+	vpa.SetOwnerReferences([]metav1.OwnerReference{marklogicClusterAsOwner(cr)})
+	return vpa
+}
+
+func resourceListObject(list corev1.ResourceList) map[string]interface{} {
+	object := map[string]interface{}{}
+	for name, quantity := range list {
+		object[string(name)] = quantity.String()
+	}
+	return object
+}
+
+// podResourcesMatcher returns the check of the resources of the pods of a
+// group for the resource rollout: against the StatefulSet template, or, when
+// a VPA sets the requests of new pods, against its recommendation. Pods of
+// the Initial mode always match, so they are never restarted for their
+// resources; pods of the Auto mode match while their requests are within the
+// recommended bounds.
+func (cc *ClusterContext) podResourcesMatcher(group *marklogicv1.MarklogicGroups, template *corev1.Container) (func(corev1.ResourceRequirements) bool, error) {
+	vertical := groupVerticalAutoscaling(group)
+	if vertical == nil || vertical.Mode == "" || vertical.Mode == marklogicv1.VerticalAutoscalingOff {
+		return func(resources corev1.ResourceRequirements) bool {
+			return resourcesMatch(resources, template.Resources)
+		}, nil
+	}
+	if vertical.Mode == marklogicv1.VerticalAutoscalingInitial {
+		return func(corev1.ResourceRequirements) bool { return true }, nil
+	}
+	lower, upper, err := cc.vpaRecommendationBounds(group.Name)
+	if err != nil {
+		return nil, err
+	}
+	return func(resources corev1.ResourceRequirements) bool {
+		return requestsWithin(resources.Requests, lower, upper)
+	}, nil
+}
+
+// vpaRecommendationBounds returns the lower and upper bounds the VPA of a
+// group recommends for the MarkLogic container, or nil bounds while there is
+// no recommendation.
+func (cc *ClusterContext) vpaRecommendationBounds(name string) (corev1.ResourceList, corev1.ResourceList, error) {
+	vpa := &unstructured.Unstructured{}
+	vpa.SetGroupVersionKind(verticalPodAutoscalerGVK)
+	err := cc.Client.Get(cc.Ctx, types.NamespacedName{Namespace: cc.MarklogicCluster.Namespace, Name: name}, vpa)
+	if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	recommendations, _, _ := unstructured.NestedSlice(vpa.Object, "status", "recommendation", "containerRecommendations")
+	for _, item := range recommendations {
+		recommendation, ok := item.(map[string]interface{})
+		if !ok || recommendation["containerName"] != "marklogic-server" {
+			continue
+		}
+		lower, _, _ := unstructured.NestedStringMap(recommendation, "lowerBound")
+		upper, _, _ := unstructured.NestedStringMap(recommendation, "upperBound")
+		return parseResourceList(lower), parseResourceList(upper), nil
+	}
+	return nil, nil, nil
+}
+
+func parseResourceList(values map[string]string) corev1.ResourceList {
+	list := corev1.ResourceList{}
+	for name, value := range values {
+		if quantity, err := resource.ParseQuantity(value); err == nil {
+			list[corev1.ResourceName(name)] = quantity
+		}
+	}
+	return list
+}
+
+// requestsWithin reports whether the CPU and memory requests are within the
+// bounds. A request the bounds cover but the pod lacks is out of bounds.
+func requestsWithin(requests, lower, upper corev1.ResourceList) bool {
+	for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		request, ok := requests[name]
+		if bound, bounded := lower[name]; bounded && (!ok || request.Cmp(bound) < 0) {
+			return false
+		}
+		if bound, bounded := upper[name]; bounded && (!ok || request.Cmp(bound) > 0) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"testing"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestVerticalPodAutoscalerLeavesRestartsToTheOperator(t *testing.T) {
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default", UID: "cluster-uid"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{{
+				Name: "enode",
+				Autoscaling: &marklogicv1.GroupAutoscaling{Vertical: &marklogicv1.VerticalAutoscaling{
+					Mode:       marklogicv1.VerticalAutoscalingAuto,
+					MaxAllowed: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")},
+				}},
+			}},
+		},
+	}
+	cc := newUpgradeTestContext(t, cr)
+	group := cc.MarklogicCluster.Spec.MarkLogicGroups[0]
+
+	if res := cc.ReconcileVerticalPodAutoscalers(); res.Completed() {
+		t.Fatalf("expected the reconcile to continue")
+	}
+	vpa := &unstructured.Unstructured{}
+	vpa.SetGroupVersionKind(verticalPodAutoscalerGVK)
+	if err := cc.Client.Get(cc.Ctx, types.NamespacedName{Namespace: "default", Name: "enode"}, vpa); err != nil {
+		t.Fatalf("expected a VerticalPodAutoscaler: %v", err)
+	}
+	if mode, _, _ := unstructured.NestedString(vpa.Object, "spec", "updatePolicy", "updateMode"); mode != "Initial" {
+		t.Fatalf("expected the VPA updater to never evict pods, got update mode %q", mode)
+	}
+
+	// Requests below the recommended lower bound make the pod outdated.
+	if err := unstructured.SetNestedSlice(vpa.Object, []interface{}{map[string]interface{}{
+		"containerName": "marklogic-server",
+		"lowerBound":    map[string]interface{}{"cpu": "1", "memory": "4Gi"},
+		"upperBound":    map[string]interface{}{"cpu": "4", "memory": "16Gi"},
+	}}, "status", "recommendation", "containerRecommendations"); err != nil {
+		t.Fatalf("failed to set recommendation: %v", err)
+	}
+	if err := cc.Client.Update(cc.Ctx, vpa); err != nil {
+		t.Fatalf("failed to update VerticalPodAutoscaler: %v", err)
+	}
+	matches, err := cc.podResourcesMatcher(group, &corev1.Container{Name: "marklogic-server"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	requests := func(cpu, memory string) corev1.ResourceRequirements {
+		return corev1.ResourceRequirements{Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(cpu),
+			corev1.ResourceMemory: resource.MustParse(memory),
+		}}
+	}
+	if matches(requests("500m", "8Gi")) {
+		t.Fatalf("expected requests below the recommendation to be outdated")
+	}
+	if !matches(requests("2", "8Gi")) {
+		t.Fatalf("expected requests within the recommendation to match")
+	}
+
+	group.Autoscaling = nil
+	cc.ReconcileVerticalPodAutoscalers()
+	if err := cc.Client.Get(cc.Ctx, types.NamespacedName{Namespace: "default", Name: "enode"}, vpa); !apierrors.IsNotFound(err) {
+		t.Fatalf("expected the VerticalPodAutoscaler to be deleted, got %v", err)
+	}
+}
@@ -0,0 +1,226 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/result"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	defaultWarmUpWindow  = 30 * time.Minute
+	warmUpMonitorSeconds = 30
+)
+
+// ReconcileScaleUpWarmUp starts a warm-up window when a group with a scaleUp
+// policy gains replicas. For the length of the window the rebalancer throttle
+// of the policy is applied to the databases and their rebalancing progress is
+// reported in status.warmUp. The previous throttle is restored afterwards.
+func (cc *ClusterContext) ReconcileScaleUpWarmUp() result.ReconcileResult {
+	cr := cc.MarklogicCluster
+	previous := map[string]marklogicv1.GroupWarmUpStatus{}
+	for _, status := range cr.Status.WarmUp {
+		previous[status.Group] = status
+	}
+
+	var mgmtClient mlmanage.Client
+	now := metav1.Now()
+	warming := false
+	statuses := []marklogicv1.GroupWarmUpStatus{}
+	for _, group := range cr.Spec.MarkLogicGroups {
+		if group == nil || group.ScaleUp == nil {
+			continue
+		}
+		replicas := int32(1)
+		if group.Replicas != nil {
+			replicas = *group.Replicas
+		}
+		status, found := previous[group.Name]
+		status.Group = group.Name
+		// The first observation of a group is its baseline, not a scale-up.
+		scaledUp := found && replicas > status.Replicas
+		if !found || (!scaledUp && status.Phase != marklogicv1.WarmUpPhaseWarmingUp) {
+			status.Replicas = replicas
+			statuses = append(statuses, status)
+			continue
+		}
+		if mgmtClient == nil {
+			var err error
+			if mgmtClient, err = cc.newBootstrapManagementClient(); err != nil {
+				cc.ReqLogger.Error(err, "Failed to create a Manage API client for the scale-up warm-up")
+				return result.RequeueSoon(warmUpMonitorSeconds)
+			}
+		}
+		if scaledUp {
+			if err := cc.startWarmUp(mgmtClient, group, replicas, &status, now); err != nil {
+				status.Message = err.Error()
+				statuses = append(statuses, status)
+				warming = true
+				continue
+			}
+			status.Replicas = replicas
+		}
+		cc.monitorWarmUp(mgmtClient, group, &status, now)
+		if status.Phase == marklogicv1.WarmUpPhaseWarmingUp {
+			warming = true
+		}
+		statuses = append(statuses, status)
+	}
+
+	if !equality.Semantic.DeepEqual(statuses, cr.Status.WarmUp) && (len(statuses) > 0 || len(cr.Status.WarmUp) > 0) {
+		patchBase := client.MergeFrom(cr.DeepCopy())
+		cr.Status.WarmUp = statuses
+		if err := patchStatus(cc.Ctx, cc.Client, cr, patchBase); err != nil {
+			return result.Error(err)
+		}
+	}
+	if warming {
+		return result.RequeueSoon(warmUpMonitorSeconds)
+	}
+	return result.Continue()
+}
+
+// startWarmUp opens the warm-up window of a group and applies the rebalancer
+// throttle. A scale-up during an open window restarts the window but keeps
+// the throttle recorded before the first one.
+func (cc *ClusterContext) startWarmUp(mgmtClient mlmanage.Client, group *marklogicv1.MarklogicGroups, replicas int32, status *marklogicv1.GroupWarmUpStatus, now metav1.Time) error {
+	if status.Phase != marklogicv1.WarmUpPhaseWarmingUp {
+		status.Databases = nil
+	}
+	status.Phase = marklogicv1.WarmUpPhaseWarmingUp
+	status.StartTime = &now
+	status.CompletionTime = nil
+
+	throttle := group.ScaleUp.RebalancerThrottle
+	for _, database := range warmUpDatabases(group) {
+		entry := warmUpDatabase(status, database)
+		if throttle == nil || entry.PreviousThrottle != 0 {
+			continue
+		}
+		current, err := mgmtClient.GetDatabaseRebalancerThrottle(cc.Ctx, database)
+		if err != nil {
+			return fmt.Errorf("failed to read the rebalancer throttle of %s: %w", database, err)
+		}
+		if err := mgmtClient.SetDatabaseRebalancerThrottle(cc.Ctx, database, *throttle); err != nil {
+			return fmt.Errorf("failed to set the rebalancer throttle of %s: %w", database, err)
+		}
+		entry.PreviousThrottle = current
+	}
+	message := fmt.Sprintf("Group %s scaled up to %d replicas, monitoring the rebalancing of %s for %s",
+		group.Name, replicas, strings.Join(warmUpDatabases(group), ", "), warmUpWindow(group.ScaleUp))
+	cc.ReqLogger.Info(message)
+	cc.recordClusterEvent(corev1.EventTypeNormal, "ScaleUpWarmUpStarted", message)
+	return nil
+}
+
+// monitorWarmUp refreshes the rebalancing progress and closes the window once
+// it has elapsed, restoring the rebalancer throttle.
+func (cc *ClusterContext) monitorWarmUp(mgmtClient mlmanage.Client, group *marklogicv1.MarklogicGroups, status *marklogicv1.GroupWarmUpStatus, now metav1.Time) {
+	progress := []string{}
+	for i := range status.Databases {
+		entry := &status.Databases[i]
+		if err := cc.measureRebalance(mgmtClient, entry); err != nil {
+			progress = append(progress, fmt.Sprintf("%s: %v", entry.Database, err))
+			continue
+		}
+		progress = append(progress, fmt.Sprintf("%s %d%% balanced across %d forests", entry.Database, entry.BalancePercent, entry.Forests))
+	}
+	status.Message = strings.Join(progress, "; ")
+
+	window := warmUpWindow(group.ScaleUp)
+	if status.StartTime == nil || now.Sub(status.StartTime.Time) < window {
+		return
+	}
+	for i := range status.Databases {
+		entry := &status.Databases[i]
+		if entry.PreviousThrottle == 0 {
+			continue
+		}
+		if err := mgmtClient.SetDatabaseRebalancerThrottle(cc.Ctx, entry.Database, entry.PreviousThrottle); err != nil {
+			status.Message = fmt.Sprintf("failed to restore the rebalancer throttle of %s: %v", entry.Database, err)
+			return
+		}
+		entry.PreviousThrottle = 0
+	}
+	status.Phase = marklogicv1.WarmUpPhaseCompleted
+	status.CompletionTime = &now
+	cc.recordClusterEvent(corev1.EventTypeNormal, "ScaleUpWarmUpCompleted",
+		fmt.Sprintf("Warm-up of group %s ended: %s", group.Name, status.Message))
+}
+
+// measureRebalance counts the documents of every forest of the database.
+func (cc *ClusterContext) measureRebalance(mgmtClient mlmanage.Client, entry *marklogicv1.DatabaseRebalanceStatus) error {
+	forests, err := mgmtClient.ListDatabaseForests(cc.Ctx, entry.Database)
+	if err != nil {
+		return err
+	}
+	counts := make([]int64, 0, len(forests))
+	for _, forest := range forests {
+		count, err := mgmtClient.GetForestDocumentCount(cc.Ctx, forest)
+		if err != nil {
+			return err
+		}
+		counts = append(counts, count)
+	}
+	entry.Forests = int32(len(counts))
+	entry.Documents = 0
+	for _, count := range counts {
+		entry.Documents += count
+	}
+	entry.BalancePercent = rebalancePercent(counts)
+	return nil
+}
+
+// rebalancePercent compares the emptiest forest with the average forest. New
+// forests start empty, so it rises from 0 to 100 as the rebalancer moves
+// documents onto them.
+func rebalancePercent(counts []int64) int32 {
+	var total int64
+	lowest := int64(-1)
+	for _, count := range counts {
+		total += count
+		if lowest < 0 || count < lowest {
+			lowest = count
+		}
+	}
+	if total == 0 {
+		return 100
+	}
+	percent := lowest * 100 * int64(len(counts)) / total
+	return int32(min(percent, 100))
+}
+
+func warmUpWindow(policy *marklogicv1.ScaleUpPolicy) time.Duration {
+	if policy.WarmUpWindow == nil || policy.WarmUpWindow.Duration <= 0 {
+		return defaultWarmUpWindow
+	}
+	return policy.WarmUpWindow.Duration
+}
+
+func warmUpDatabases(group *marklogicv1.MarklogicGroups) []string {
+	if len(group.ScaleUp.Databases) > 0 || group.Forests == nil {
+		return group.ScaleUp.Databases
+	}
+	return group.Forests.Databases
+}
+
+// warmUpDatabase returns the status entry of the database, adding it when
+// missing.
+func warmUpDatabase(status *marklogicv1.GroupWarmUpStatus, database string) *marklogicv1.DatabaseRebalanceStatus {
+	for i := range status.Databases {
+		if status.Databases[i].Database == database {
+			return &status.Databases[i]
+		}
+	}
+	status.Databases = append(status.Databases, marklogicv1.DatabaseRebalanceStatus{Database: database})
+	return &status.Databases[len(status.Databases)-1]
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package k8sutil
+
+import (
+	"testing"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestReconcileScaleUpWarmUpThrottlesRebalancing(t *testing.T) {
+	replicas := int32(2)
+	throttle := int32(2)
+	cr := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml", Namespace: "default"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			ClusterDomain: "cluster.local",
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{{
+				Name:        "dnode",
+				IsBootstrap: true,
+				Replicas:    &replicas,
+				Forests:     &marklogicv1.ForestProvisioning{ForestsPerHost: 1, Databases: []string{"Documents"}},
+				ScaleUp: &marklogicv1.ScaleUpPolicy{
+					WarmUpWindow:       &metav1.Duration{Duration: 10 * time.Minute},
+					RebalancerThrottle: &throttle,
+				},
+			}},
+		},
+	}
+	cc := newUpgradeTestContext(t, cr)
+	databaseThrottle := int32(5)
+	counts := map[string]int64{"Documents-dnode-0-1": 600, "Documents-dnode-1-1": 600}
+	original := NewDynamicManagementClient
+	NewDynamicManagementClient = func(opts mlmanage.ClientOptions) mlmanage.Client {
+		return &stubDynamicManagementClient{
+			listForestsFn: func(database string) ([]string, error) {
+				names := []string{}
+				for name := range counts {
+					names = append(names, name)
+				}
+				return names, nil
+			},
+			documentCountFn: func(forest string) (int64, error) { return counts[forest], nil },
+			getThrottleFn:   func(database string) (int32, error) { return databaseThrottle, nil },
+			setThrottleFn: func(database string, value int32) error {
+				databaseThrottle = value
+				return nil
+			},
+		}
+	}
+	t.Cleanup(func() { NewDynamicManagementClient = original })
+
+	if res := cc.ReconcileScaleUpWarmUp(); res.Completed() {
+		t.Fatalf("expected the initial replica count to be recorded without a warm-up")
+	}
+	if len(cr.Status.WarmUp) != 1 || cr.Status.WarmUp[0].Replicas != 2 || cr.Status.WarmUp[0].Phase != "" {
+		t.Fatalf("unexpected baseline %+v", cr.Status.WarmUp)
+	}
+
+	scaled := int32(3)
+	cr.Spec.MarkLogicGroups[0].Replicas = &scaled
+	if err := cc.Client.Update(cc.Ctx, cr); err != nil {
+		t.Fatalf("failed to scale up: %v", err)
+	}
+	counts["Documents-dnode-2-1"] = 0
+	if res := cc.ReconcileScaleUpWarmUp(); !res.Completed() {
+		t.Fatalf("expected the warm-up to be monitored")
+	}
+	status := cr.Status.WarmUp[0]
+	if status.Phase != marklogicv1.WarmUpPhaseWarmingUp || status.Replicas != 3 || databaseThrottle != 2 {
+		t.Fatalf("expected a throttled warm-up, got %+v with throttle %d", status, databaseThrottle)
+	}
+	if len(status.Databases) != 1 || status.Databases[0].PreviousThrottle != 5 || status.Databases[0].BalancePercent != 0 || status.Databases[0].Forests != 3 {
+		t.Fatalf("unexpected rebalance status %+v", status.Databases)
+	}
+
+	counts = map[string]int64{"Documents-dnode-0-1": 400, "Documents-dnode-1-1": 400, "Documents-dnode-2-1": 360}
+	started := metav1.NewTime(time.Now().Add(-11 * time.Minute))
+	cr.Status.WarmUp[0].StartTime = &started
+	if res := cc.ReconcileScaleUpWarmUp(); res.Completed() {
+		t.Fatalf("expected the warm-up to end after its window")
+	}
+	status = cr.Status.WarmUp[0]
+	if status.Phase != marklogicv1.WarmUpPhaseCompleted || status.CompletionTime == nil || databaseThrottle != 5 {
+		t.Fatalf("expected the throttle to be restored, got %+v with throttle %d", status, databaseThrottle)
+	}
+	if status.Databases[0].BalancePercent != 93 || status.Databases[0].Documents != 1160 {
+		t.Fatalf("unexpected final rebalance status %+v", status.Databases[0])
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package mlmanage
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// StatusCache keeps the host and forest status of clusters for a short time,
+// so the reconcile steps and frequent requeues of a cluster, for example
+// while hosts restart during an upgrade, share one Manage API call instead of
+// making one each. A nil StatusCache or a zero TTL caches nothing.
+type StatusCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]statusCacheEntry
+}
+
+type statusCacheEntry struct {
+	hosts   []HostStatus
+	forests []ForestStatus
+	expires time.Time
+}
+
+// NewStatusCache returns a cache that keeps the status for ttl.
+func NewStatusCache(ttl time.Duration) *StatusCache {
+	return &StatusCache{ttl: ttl, now: time.Now, entries: map[string]statusCacheEntry{}}
+}
+
+// Client returns client with its ListHostsStatus and ListForestsStatus
+// served from the cache entry of key, usually the cluster and the host the
+// client connects to. Calls that change hosts or forests through the client
+// drop the entry. Errors are never cached.
+func (c *StatusCache) Client(key string, client Client) Client {
+	if c == nil || c.ttl <= 0 {
+		return client
+	}
+	return &cachedClient{Client: client, cache: c, key: key}
+}
+
+// Invalidate drops the cached status of key.
+func (c *StatusCache) Invalidate(key string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, key)
+}
+
+// entry returns the unexpired entry of key, and drops expired entries.
+func (c *StatusCache) entry(key string) statusCacheEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	for k, entry := range c.entries {
+		if !now.Before(entry.expires) {
+			delete(c.entries, k)
+		}
+	}
+	return c.entries[key]
+}
+
+func (c *StatusCache) update(key string, set func(*statusCacheEntry)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry := c.entries[key]
+	if !c.now().Before(entry.expires) {
+		entry = statusCacheEntry{expires: c.now().Add(c.ttl)}
+	}
+	set(&entry)
+	c.entries[key] = entry
+}
+
+type cachedClient struct {
+	Client
+	cache *StatusCache
+	key   string
+}
+
+func (c *cachedClient) ListHostsStatus(ctx context.Context) ([]HostStatus, error) {
+	if hosts := c.cache.entry(c.key).hosts; hosts != nil {
+		return slices.Clone(hosts), nil
+	}
+	hosts, err := c.Client.ListHostsStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.cache.update(c.key, func(entry *statusCacheEntry) { entry.hosts = slices.Clone(hosts) })
+	return hosts, nil
+}
+
+func (c *cachedClient) ListForestsStatus(ctx context.Context) ([]ForestStatus, error) {
+	if forests := c.cache.entry(c.key).forests; forests != nil {
+		return slices.Clone(forests), nil
+	}
+	forests, err := c.Client.ListForestsStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.cache.update(c.key, func(entry *statusCacheEntry) { entry.forests = slices.Clone(forests) })
+	return forests, nil
+}
+
+func (c *cachedClient) JoinDynamicHost(ctx context.Context, hostFQDN, token string) error {
+	defer c.cache.Invalidate(c.key)
+	return c.Client.JoinDynamicHost(ctx, hostFQDN, token)
+}
+
+func (c *cachedClient) RemoveDynamicHost(ctx context.Context, clusterName, hostID string) error {
+	defer c.cache.Invalidate(c.key)
+	return c.Client.RemoveDynamicHost(ctx, clusterName, hostID)
+}
+
+func (c *cachedClient) SetHostName(ctx context.Context, hostName, newName string) error {
+	defer c.cache.Invalidate(c.key)
+	return c.Client.SetHostName(ctx, hostName, newName)
+}
+
+func (c *cachedClient) CreateForest(ctx context.Context, forest ForestSpec) (bool, error) {
+	defer c.cache.Invalidate(c.key)
+	return c.Client.CreateForest(ctx, forest)
+}
+
+func (c *cachedClient) MigratePartition(ctx context.Context, database, partition, dataDirectory string) error {
+	defer c.cache.Invalidate(c.key)
+	return c.Client.MigratePartition(ctx, database, partition, dataDirectory)
+}
+
+func (c *cachedClient) RestartForest(ctx context.Context, forest string) error {
+	defer c.cache.Invalidate(c.key)
+	return c.Client.RestartForest(ctx, forest)
+}
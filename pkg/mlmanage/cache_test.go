@@ -0,0 +1,64 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package mlmanage
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestStatusCacheSharesTheHostStatusUntilItExpires(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/manage/v2/hosts":
+			calls.Add(1)
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"host-status-list":{"status-list-items":{"status-list-item":[{"nameref":"ml-0","status":"online","version":"12.0-1"}]}}}`))
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	now := time.Now()
+	cache := NewStatusCache(5 * time.Second)
+	cache.now = func() time.Time { return now }
+	newClient := func() Client {
+		return cache.Client("default/ml/ml-0", NewClient(ClientOptions{Host: server.Listener.Addr().String()}))
+	}
+	ctx := context.Background()
+
+	hosts, err := newClient().ListHostsStatus(ctx)
+	if err != nil || len(hosts) != 1 || hosts[0].Name != "ml-0" {
+		t.Fatalf("unexpected hosts %+v, %v", hosts, err)
+	}
+	hosts[0].Online = false
+	hosts, err = newClient().ListHostsStatus(ctx)
+	if err != nil || calls.Load() != 1 || !hosts[0].Online {
+		t.Fatalf("expected the cached status of the first call, got %+v after %d calls", hosts, calls.Load())
+	}
+
+	now = now.Add(5 * time.Second)
+	if _, err := newClient().ListHostsStatus(ctx); err != nil || calls.Load() != 2 {
+		t.Fatalf("expected the expired status to be read again, got %d calls, %v", calls.Load(), err)
+	}
+	client := newClient()
+	if err := client.RestartForest(ctx, "Documents"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.ListHostsStatus(ctx); err != nil || calls.Load() != 3 {
+		t.Fatalf("expected a restart to drop the cached status, got %d calls, %v", calls.Load(), err)
+	}
+
+	var uncached *StatusCache
+	if _, ok := uncached.Client("default/ml/ml-0", NewClient(ClientOptions{Host: "ml-0"})).(*cachedClient); ok {
+		t.Fatalf("expected a nil cache to return the client unchanged")
+	}
+}
@@ -16,10 +16,13 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"mime"
+	"mime/multipart"
 	"net"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -31,11 +34,43 @@ type Client interface {
 	EnableDynamicHosts(ctx context.Context, groupName string) error
 	EnableAdminAPITokenAuthentication(ctx context.Context, groupName string) error
 	EnsureManageAdminUser(ctx context.Context, username, password string) error
+	SetUserPassword(ctx context.Context, username, password string) error
 	ResolveClusterName(ctx context.Context) (string, error)
 	RequestDynamicHostToken(ctx context.Context, clusterName, groupName, hostFQDN, duration string) (string, error)
 	JoinDynamicHost(ctx context.Context, hostFQDN, token string) error
 	ListGroupHosts(ctx context.Context, groupName string) ([]GroupHost, error)
 	RemoveDynamicHost(ctx context.Context, clusterName, hostID string) error
+	SetCloudCredentials(ctx context.Context, creds CloudCredentials) error
+	SetGroupS3Domain(ctx context.Context, groupName, domain string) error
+	GetGroupDiagnostics(ctx context.Context, groupName string) (GroupDiagnostics, error)
+	SetGroupDiagnostics(ctx context.Context, groupName string, diagnostics GroupDiagnostics) error
+	SetAppServerProperties(ctx context.Context, groupName, serverName string, properties map[string]any) error
+	SetDatabaseBackups(ctx context.Context, database string, schedules []DatabaseBackupSchedule) error
+	GetDatabaseBackupStatus(ctx context.Context, database string) (DatabaseBackupStatus, error)
+	ListDatabaseForests(ctx context.Context, database string) ([]string, error)
+	CreateForest(ctx context.Context, forest ForestSpec) (bool, error)
+	ListDatabasePartitions(ctx context.Context, database string) ([]Partition, error)
+	MigratePartition(ctx context.Context, database, partition, dataDirectory string) error
+	GetDatabaseProperties(ctx context.Context, database string) (map[string]any, error)
+	CreateDatabase(ctx context.Context, properties map[string]any) (bool, error)
+	GetDatabaseReplicationLag(ctx context.Context, database string) (time.Duration, error)
+	GetForestDocumentCount(ctx context.Context, forest string) (int64, error)
+	GetDatabaseRebalancerThrottle(ctx context.Context, database string) (int32, error)
+	SetDatabaseRebalancerThrottle(ctx context.Context, database string, throttle int32) error
+	StartDatabaseRestore(ctx context.Context, database string, req DatabaseRestoreRequest) (string, error)
+	GetDatabaseRestoreStatus(ctx context.Context, database, jobID string) (DatabaseRestoreStatus, error)
+	ListForestsStatus(ctx context.Context) ([]ForestStatus, error)
+	ListForestReplicas(ctx context.Context, forest string) ([]string, error)
+	RestartForest(ctx context.Context, forest string) error
+	GetHostLicense(ctx context.Context, hostName string) (HostLicense, error)
+	SetHostZone(ctx context.Context, hostName, zone string) error
+	SetHostName(ctx context.Context, hostName, newName string) error
+	GetGroupLoad(ctx context.Context, groupName string) (GroupLoad, error)
+	GetStatusView(ctx context.Context, resource string) ([]byte, error)
+	UpgradeSecurityDatabase(ctx context.Context) (bool, error)
+	GetSSLFIPSEnabled(ctx context.Context) (bool, error)
+	SetSSLFIPSEnabled(ctx context.Context, enabled bool) error
+	ListForeignClustersStatus(ctx context.Context, clusterName string) ([]ForeignClusterStatus, error)
 }
 
 type ClientOptions struct {
@@ -45,6 +80,9 @@ type ClientOptions struct {
 	UseTLS             bool
 	InsecureSkipVerify bool
 	HTTPClient         *http.Client
+	// WrapTransport, when set, wraps the transport built for this client. It
+	// is used by the fault injection mode to delay or fail Manage API calls.
+	WrapTransport func(http.RoundTripper) http.RoundTripper
 }
 
 type HostStatus struct {
@@ -64,6 +102,120 @@ type GroupHost struct {
 	Online bool
 }
 
+// CloudCredentials are the cluster wide object store credentials MarkLogic uses
+// for backup and restore. Only the fields matching Type are sent.
+type CloudCredentials struct {
+	Type                string
+	AWSAccessKey        string
+	AWSSecretKey        string
+	AzureStorageAccount string
+	AzureStorageKey     string
+	AzureSASToken       string
+}
+
+// DatabaseBackupSchedule mirrors a database-backup entry of the database properties.
+type DatabaseBackupSchedule struct {
+	Type        string
+	Period      int
+	StartTime   string
+	Days        []string
+	MonthDay    int
+	Directory   string
+	MaxBackups  int
+	Incremental bool
+
+	JournalArchiving       bool
+	JournalArchivePath     string
+	JournalArchiveLagLimit int
+}
+
+type DatabaseBackupStatus struct {
+	LastBackup            string
+	LastIncrementalBackup string
+}
+
+type DatabaseRestoreRequest struct {
+	Directory       string
+	Forests         []string
+	RestoreToTime   string
+	JournalArchive  bool
+	IncludeReplicas bool
+}
+
+// DatabaseRestoreStatus is the result of the restore-status operation. Status is
+// the overall job status and Forests maps each forest to its own status.
+type DatabaseRestoreStatus struct {
+	Status  string
+	Forests map[string]string
+}
+
+// ForestStatus is the state and disk usage of a forest. Sizes are in megabytes;
+// DeviceSpaceMB is the free space left on the forest's device. Merging and
+// Reindexing report background jobs that a restart of the host interrupts.
+// Database is empty for forests not attached to a database.
+type ForestStatus struct {
+	Name          string
+	Host          string
+	Database      string
+	State         string
+	DataSizeMB    int64
+	DeviceSpaceMB int64
+	Merging       bool
+	Reindexing    bool
+}
+
+// ForestSpec describes a forest to create on Host and attach to Database. An
+// empty DataDirectory uses the default data directory of the host, and the
+// forest has no fast or large data directory when those are empty.
+type ForestSpec struct {
+	Name               string
+	Host               string
+	Database           string
+	DataDirectory      string
+	FastDataDirectory  string
+	LargeDataDirectory string
+}
+
+// Partition is a range partition of a database, the forests that share a
+// range. UpperBound is the range-upper-bound, empty when the range has no
+// upper bound, and DataDirectory the data directory of its forests.
+type Partition struct {
+	Name          string
+	UpperBound    string
+	DataDirectory string
+}
+
+// ForeignClusterStatus is a foreign cluster the cluster is coupled with and
+// how many of its hosts the cluster is connected to.
+type ForeignClusterStatus struct {
+	Name           string
+	Hosts          int
+	ConnectedHosts int
+}
+
+// HostLicense is the license installed on a host. Expires is empty when the
+// license does not expire.
+type HostLicense struct {
+	Licensee string
+	Expires  string
+}
+
+// GroupLoad is the load of the app servers of a group. RequestRate is in
+// requests per second, ActiveRequests counts the requests being processed and
+// QueueSize the requests waiting for a thread.
+type GroupLoad struct {
+	RequestRate    float64
+	ActiveRequests int64
+	QueueSize      int64
+}
+
+// GroupDiagnostics are the trace events and the file log level of a group.
+type GroupDiagnostics struct {
+	EventsActivated bool
+	Events          []string
+	FileLogLevel    string
+}
+
 type managementClient struct {
 	baseURL    string
 	username   string
@@ -80,15 +232,111 @@ func NewClient(opts ClientOptions) Client {
 	}
 }
 
+// Eval runs an ad hoc XQuery ("xquery") or JavaScript ("javascript") request
+// through the /v1/eval endpoint of the App-Services server and returns the
+// result parts joined by newlines. Host defaults to port 8000.
+func Eval(ctx context.Context, opts ClientOptions, language, source string) (result string, err error) {
+	if language != "xquery" && language != "javascript" {
+		return "", fmt.Errorf("unsupported eval language %q", language)
+	}
+	host := opts.Host
+	if _, _, splitErr := net.SplitHostPort(host); splitErr != nil {
+		host = net.JoinHostPort(host, "8000")
+	}
+	c := &managementClient{
+		baseURL:    buildBaseURL(host, opts.UseTLS),
+		username:   opts.Username,
+		password:   opts.Password,
+		httpClient: buildHTTPClient(opts),
+	}
+	form := url.Values{}
+	form.Set(language, source)
+	headers := map[string]string{
+		"Content-Type": "application/x-www-form-urlencoded",
+		"Accept":       "multipart/mixed",
+	}
+	resp, err := c.doRequestWithAuth(ctx, http.MethodPost, c.baseURL+"/v1/eval", headers, []byte(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		err = errors.Join(err, resp.Body.Close())
+	}()
+	if resp.StatusCode != http.StatusOK {
+		data, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("eval returned status %d: %s", resp.StatusCode, string(data))
+	}
+	mediaType, params, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil || !strings.HasPrefix(mediaType, "multipart/") {
+		data, readErr := io.ReadAll(resp.Body)
+		return strings.TrimSpace(string(data)), readErr
+	}
+	reader := multipart.NewReader(resp.Body, params["boundary"])
+	parts := []string{}
+	for {
+		part, partErr := reader.NextPart()
+		if partErr == io.EOF {
+			break
+		}
+		if partErr != nil {
+			return "", partErr
+		}
+		data, readErr := io.ReadAll(part)
+		if readErr != nil {
+			return "", readErr
+		}
+		parts = append(parts, string(data))
+	}
+	return strings.Join(parts, "\n"), nil
+}
+
+// transports are shared by the clients with the same TLS settings, so the
+// clients built for every reconcile reuse the connections to the hosts.
+var (
+	transportsMu sync.Mutex
+	transports   = map[[2]bool]*http.Transport{}
+)
+
+func sharedTransport(opts ClientOptions) *http.Transport {
+	key := [2]bool{opts.UseTLS, opts.InsecureSkipVerify}
+	transportsMu.Lock()
+	defer transportsMu.Unlock()
+	if transport := transports[key]; transport != nil {
+		return transport
+	}
+	transport := &http.Transport{
+		MaxIdleConnsPerHost: 4,
+		IdleConnTimeout:     90 * time.Second,
+	}
+	if opts.UseTLS {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: opts.InsecureSkipVerify}
+	}
+	transports[key] = transport
+	return transport
+}
+
 func buildHTTPClient(opts ClientOptions) *http.Client {
 	if opts.HTTPClient != nil {
 		return opts.HTTPClient
 	}
-	transport := &http.Transport{}
-	if opts.UseTLS {
-		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: opts.InsecureSkipVerify}
+	transport := sharedTransport(opts)
+	var roundTripper http.RoundTripper = transport
+	if opts.WrapTransport != nil {
+		roundTripper = opts.WrapTransport(transport)
 	}
-	return &http.Client{Timeout: 15 * time.Second, Transport: transport}
+	return &http.Client{Timeout: 15 * time.Second, Transport: roundTripper}
+}
+
+// adminBaseURL returns the Admin API (port 8001) URL of the host behind a Manage API base URL.
+func adminBaseURL(baseURL string) string {
+	parsed, err := url.Parse(baseURL)
+	if err != nil {
+		return baseURL
+	}
+	if parsed.Port() == "8002" {
+		parsed.Host = net.JoinHostPort(parsed.Hostname(), "8001")
+	}
+	return parsed.String()
 }
 
 func buildBaseURL(host string, useTLS bool) string {
@@ -254,6 +502,13 @@ func (c *managementClient) EnsureManageAdminUser(ctx context.Context, username,
 	return err
 }
 
+// SetUserPassword changes the password of an existing MarkLogic user.
+func (c *managementClient) SetUserPassword(ctx context.Context, username, password string) error {
+	payload := map[string]any{"password": password}
+	_, _, err := c.doJSON(ctx, http.MethodPut, "/manage/v2/users/"+url.PathEscape(username)+"/properties", nil, payload, http.StatusAccepted, http.StatusNoContent)
+	return err
+}
+
 func (c *managementClient) ResolveClusterName(ctx context.Context) (string, error) {
 	if clusterName, err := c.resolveClusterNameFromClusterList(ctx); err == nil && strings.TrimSpace(clusterName) != "" {
 		return clusterName, nil
@@ -452,6 +707,705 @@ func (c *managementClient) RemoveDynamicHost(ctx context.Context, clusterName, h
 	return err
 }
 
+func (c *managementClient) SetCloudCredentials(ctx context.Context, creds CloudCredentials) error {
+	var payload map[string]any
+	switch creds.Type {
+	case "aws":
+		payload = map[string]any{
+			"aws-access-key": creds.AWSAccessKey,
+			"aws-secret-key": creds.AWSSecretKey,
+		}
+	case "azure":
+		payload = map[string]any{"azure-storage-account": creds.AzureStorageAccount}
+		if creds.AzureSASToken != "" {
+			payload["azure-sas-token"] = creds.AzureSASToken
+		} else {
+			payload["azure-storage-key"] = creds.AzureStorageKey
+		}
+	default:
+		return fmt.Errorf("unsupported cloud credential type %q", creds.Type)
+	}
+	_, _, err := c.doJSON(ctx, http.MethodPut, "/manage/v2/credentials/properties", nil, payload, http.StatusAccepted, http.StatusNoContent, http.StatusOK)
+	return err
+}
+
+func (c *managementClient) SetGroupS3Domain(ctx context.Context, groupName, domain string) error {
+	payload := map[string]any{"s3-domain": domain}
+	_, _, err := c.doJSON(ctx, http.MethodPut, "/manage/v2/groups/"+url.PathEscape(groupName)+"/properties", nil, payload, http.StatusAccepted, http.StatusNoContent)
+	return err
+}
+
+// GetGroupDiagnostics reads the events-activated, event and file-log-level
+// properties of the group.
+func (c *managementClient) GetGroupDiagnostics(ctx context.Context, groupName string) (GroupDiagnostics, error) {
+	query := url.Values{}
+	query.Set("format", "json")
+	data, _, err := c.doJSON(ctx, http.MethodGet, "/manage/v2/groups/"+url.PathEscape(groupName)+"/properties", query, nil, http.StatusOK)
+	if err != nil {
+		return GroupDiagnostics{}, err
+	}
+	var payload struct {
+		EventsActivated bool   `json:"events-activated"`
+		FileLogLevel    string `json:"file-log-level"`
+		Event           []struct {
+			EventID string `json:"event-id"`
+		} `json:"event"`
+	}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return GroupDiagnostics{}, err
+	}
+	diagnostics := GroupDiagnostics{EventsActivated: payload.EventsActivated, FileLogLevel: payload.FileLogLevel}
+	for _, event := range payload.Event {
+		if event.EventID != "" {
+			diagnostics.Events = append(diagnostics.Events, event.EventID)
+		}
+	}
+	return diagnostics, nil
+}
+
+// SetGroupDiagnostics replaces the trace events of the group and sets
+// events-activated and, when not empty, file-log-level.
+func (c *managementClient) SetGroupDiagnostics(ctx context.Context, groupName string, diagnostics GroupDiagnostics) error {
+	events := make([]map[string]any, 0, len(diagnostics.Events))
+	for _, event := range diagnostics.Events {
+		events = append(events, map[string]any{"event-id": event})
+	}
+	payload := map[string]any{
+		"events-activated": diagnostics.EventsActivated,
+		"event":            events,
+	}
+	if diagnostics.FileLogLevel != "" {
+		payload["file-log-level"] = diagnostics.FileLogLevel
+	}
+	_, _, err := c.doJSON(ctx, http.MethodPut, "/manage/v2/groups/"+url.PathEscape(groupName)+"/properties", nil, payload, http.StatusAccepted, http.StatusNoContent)
+	return err
+}
+
+// SetAppServerProperties sets the given properties of an app server of a
+// group, for example request-timeout or url-rewriter.
+func (c *managementClient) SetAppServerProperties(ctx context.Context, groupName, serverName string, properties map[string]any) error {
+	query := url.Values{}
+	query.Set("group-id", groupName)
+	_, _, err := c.doJSON(ctx, http.MethodPut, "/manage/v2/servers/"+url.PathEscape(serverName)+"/properties", query, properties, http.StatusAccepted, http.StatusNoContent)
+	return err
+}
+
+// GetSSLFIPSEnabled reads the ssl-fips-enabled property of the cluster, which
+// restricts the TLS of MarkLogic to the FIPS 140 validated OpenSSL module.
+func (c *managementClient) GetSSLFIPSEnabled(ctx context.Context) (bool, error) {
+	query := url.Values{}
+	query.Set("format", "json")
+	data, _, err := c.doJSON(ctx, http.MethodGet, "/manage/v2/properties", query, nil, http.StatusOK)
+	if err != nil {
+		return false, err
+	}
+	var payload struct {
+		SSLFIPSEnabled bool `json:"ssl-fips-enabled"`
+	}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return false, err
+	}
+	return payload.SSLFIPSEnabled, nil
+}
+
+// SetSSLFIPSEnabled sets the ssl-fips-enabled property of the cluster. MarkLogic
+// restarts the hosts to apply a change.
+func (c *managementClient) SetSSLFIPSEnabled(ctx context.Context, enabled bool) error {
+	payload := map[string]any{"ssl-fips-enabled": enabled}
+	_, _, err := c.doJSON(ctx, http.MethodPut, "/manage/v2/properties", nil, payload, http.StatusAccepted, http.StatusNoContent)
+	return err
+}
+
+// ListForeignClustersStatus returns the foreign clusters the cluster is
+// coupled with, each with the hosts its status view reports and how many of
+// them are connected.
+func (c *managementClient) ListForeignClustersStatus(ctx context.Context, clusterName string) ([]ForeignClusterStatus, error) {
+	query := url.Values{}
+	query.Set("format", "json")
+	base := "/manage/v2/clusters/" + url.PathEscape(clusterName) + "/foreign-clusters"
+	data, _, err := c.doJSON(ctx, http.MethodGet, base, query, nil, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	var payload any
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, err
+	}
+	root, ok := payload.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("unexpected foreign cluster list payload for cluster %s", clusterName)
+	}
+	clusters := []ForeignClusterStatus{}
+	for _, item := range extractListItems(root, "foreign-cluster-default-list", "list-items", "list-item") {
+		name := firstString(item, "nameref", "name")
+		if name == "" {
+			continue
+		}
+		query.Set("view", "status")
+		data, _, err := c.doJSON(ctx, http.MethodGet, base+"/"+url.PathEscape(name), query, nil, http.StatusOK)
+		if err != nil {
+			return nil, err
+		}
+		var status any
+		if err := json.Unmarshal(data, &status); err != nil {
+			return nil, err
+		}
+		cluster := ForeignClusterStatus{Name: name}
+		walkAny(status, func(node map[string]any) {
+			if _, ok := node["foreign-host-name"]; !ok {
+				return
+			}
+			cluster.Hosts++
+			connection := strings.ToLower(firstString(node, "connection-status", "connected"))
+			if connection == "true" || connection == "connected" || connection == "online" {
+				cluster.ConnectedHosts++
+			}
+		})
+		clusters = append(clusters, cluster)
+	}
+	return clusters, nil
+}
+
+func (c *managementClient) SetDatabaseBackups(ctx context.Context, database string, schedules []DatabaseBackupSchedule) error {
+	backups := make([]map[string]any, 0, len(schedules))
+	for _, schedule := range schedules {
+		entry := map[string]any{
+			"backup-enabled":     true,
+			"backup-directory":   schedule.Directory,
+			"backup-type":        schedule.Type,
+			"max-backups":        schedule.MaxBackups,
+			"incremental-backup": schedule.Incremental,
+		}
+		switch schedule.Type {
+		case "minutely", "hourly", "daily", "weekly", "monthly":
+			entry["backup-period"] = schedule.Period
+		default:
+			return fmt.Errorf("unsupported backup type %q for database %s", schedule.Type, database)
+		}
+		if schedule.Type != "minutely" && schedule.StartTime != "" {
+			entry["backup-start-time"] = schedule.StartTime
+		}
+		if schedule.Type == "weekly" {
+			entry["backup-day"] = schedule.Days
+		}
+		if schedule.Type == "monthly" {
+			entry["backup-month-day"] = schedule.MonthDay
+		}
+		if schedule.JournalArchiving {
+			entry["journal-archiving"] = true
+			entry["journal-archive-path"] = schedule.JournalArchivePath
+			entry["journal-archive-lag-limit"] = schedule.JournalArchiveLagLimit
+		}
+		backups = append(backups, entry)
+	}
+	payload := map[string]any{"database-backup": backups}
+	_, _, err := c.doJSON(ctx, http.MethodPut, "/manage/v2/databases/"+url.PathEscape(database)+"/properties", nil, payload, http.StatusAccepted, http.StatusNoContent)
+	return err
+}
+
+func (c *managementClient) GetDatabaseBackupStatus(ctx context.Context, database string) (DatabaseBackupStatus, error) {
+	query := url.Values{}
+	query.Set("view", "status")
+	query.Set("format", "json")
+	data, _, err := c.doJSON(ctx, http.MethodGet, "/manage/v2/databases/"+url.PathEscape(database), query, nil, http.StatusOK)
+	if err != nil {
+		return DatabaseBackupStatus{}, err
+	}
+	var payload any
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return DatabaseBackupStatus{}, err
+	}
+	return DatabaseBackupStatus{
+		LastBackup:            findFirstStringByKeys(payload, "last-backup"),
+		LastIncrementalBackup: findFirstStringByKeys(payload, "last-incremental-backup"),
+	}, nil
+}
+
+func (c *managementClient) ListDatabaseForests(ctx context.Context, database string) ([]string, error) {
+	query := url.Values{}
+	query.Set("database-id", database)
+	query.Set("format", "json")
+	data, _, err := c.doJSON(ctx, http.MethodGet, "/manage/v2/forests", query, nil, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	var payload any
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, err
+	}
+	root, ok := payload.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("unexpected forest list payload for database %s", database)
+	}
+	forests := []string{}
+	for _, item := range extractListItems(root, "forest-default-list", "list-items", "list-item") {
+		if name := firstString(item, "nameref", "name"); name != "" {
+			forests = append(forests, name)
+		}
+	}
+	return forests, nil
+}
+
+// CreateForest creates the forest unless a forest of that name exists. It
+// reports whether the forest was created.
+func (c *managementClient) CreateForest(ctx context.Context, forest ForestSpec) (bool, error) {
+	query := url.Values{}
+	query.Set("format", "json")
+	_, statusCode, err := c.doJSON(ctx, http.MethodGet, "/manage/v2/forests/"+url.PathEscape(forest.Name), query, nil, http.StatusOK, http.StatusNotFound)
+	if err != nil || statusCode == http.StatusOK {
+		return false, err
+	}
+	payload := map[string]any{
+		"forest-name": forest.Name,
+		"host":        forest.Host,
+		"database":    forest.Database,
+	}
+	if forest.DataDirectory != "" {
+		payload["data-directory"] = forest.DataDirectory
+	}
+	if forest.FastDataDirectory != "" {
+		payload["fast-data-directory"] = forest.FastDataDirectory
+	}
+	if forest.LargeDataDirectory != "" {
+		payload["large-data-directory"] = forest.LargeDataDirectory
+	}
+	_, _, err = c.doJSON(ctx, http.MethodPost, "/manage/v2/forests", nil, payload, http.StatusCreated, http.StatusAccepted, http.StatusNoContent)
+	return err == nil, err
+}
+
+// ListDatabasePartitions returns the range partitions of the database with
+// their upper bound and data directory.
+func (c *managementClient) ListDatabasePartitions(ctx context.Context, database string) ([]Partition, error) {
+	query := url.Values{}
+	query.Set("format", "json")
+	base := "/manage/v2/databases/" + url.PathEscape(database) + "/partitions"
+	data, _, err := c.doJSON(ctx, http.MethodGet, base, query, nil, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	var payload any
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, err
+	}
+	root, ok := payload.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("unexpected partition list payload for database %s", database)
+	}
+	partitions := []Partition{}
+	for _, item := range extractListItems(root, "partition-default-list", "list-items", "list-item") {
+		name := firstString(item, "nameref", "name")
+		if name == "" {
+			continue
+		}
+		query := url.Values{}
+		query.Set("view", "properties")
+		query.Set("format", "json")
+		data, _, err := c.doJSON(ctx, http.MethodGet, base+"/"+url.PathEscape(name), query, nil, http.StatusOK)
+		if err != nil {
+			return nil, err
+		}
+		var properties any
+		if err := json.Unmarshal(data, &properties); err != nil {
+			return nil, err
+		}
+		partitions = append(partitions, Partition{
+			Name:          name,
+			UpperBound:    findFirstStringByKeys(properties, "upper-bound", "range-upper-bound"),
+			DataDirectory: findFirstStringByKeys(properties, "data-directory"),
+		})
+	}
+	return partitions, nil
+}
+
+// MigratePartition starts moving the forests of the partition to the data
+// directory. MarkLogic migrates them in the background.
+func (c *managementClient) MigratePartition(ctx context.Context, database, partition, dataDirectory string) error {
+	payload := map[string]any{
+		"operation":      "migrate-partition",
+		"data-directory": dataDirectory,
+	}
+	path := "/manage/v2/databases/" + url.PathEscape(database) + "/partitions/" + url.PathEscape(partition)
+	_, _, err := c.doJSON(ctx, http.MethodPost, path, nil, payload, http.StatusOK, http.StatusAccepted, http.StatusNoContent)
+	return err
+}
+
+// GetDatabaseProperties returns the properties of the database as the Manage
+// API reports them.
+func (c *managementClient) GetDatabaseProperties(ctx context.Context, database string) (map[string]any, error) {
+	query := url.Values{}
+	query.Set("format", "json")
+	data, _, err := c.doJSON(ctx, http.MethodGet, "/manage/v2/databases/"+url.PathEscape(database)+"/properties", query, nil, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	properties := map[string]any{}
+	if err := json.Unmarshal(data, &properties); err != nil {
+		return nil, err
+	}
+	return properties, nil
+}
+
+// CreateDatabase creates a database with the properties unless a database of
+// that database-name exists. It reports whether the database was created.
+func (c *managementClient) CreateDatabase(ctx context.Context, properties map[string]any) (bool, error) {
+	name := toString(properties["database-name"])
+	if name == "" {
+		return false, errors.New("database properties without a database-name")
+	}
+	query := url.Values{}
+	query.Set("format", "json")
+	_, statusCode, err := c.doJSON(ctx, http.MethodGet, "/manage/v2/databases/"+url.PathEscape(name), query, nil, http.StatusOK, http.StatusNotFound)
+	if err != nil || statusCode == http.StatusOK {
+		return false, err
+	}
+	_, _, err = c.doJSON(ctx, http.MethodPost, "/manage/v2/databases", nil, properties, http.StatusCreated, http.StatusAccepted, http.StatusNoContent)
+	return err == nil, err
+}
+
+// GetDatabaseReplicationLag returns how far the foreign replicas of the
+// database are behind it. It fails while the replication has not reported a
+// lag yet.
+func (c *managementClient) GetDatabaseReplicationLag(ctx context.Context, database string) (time.Duration, error) {
+	query := url.Values{}
+	query.Set("view", "status")
+	query.Set("format", "json")
+	data, _, err := c.doJSON(ctx, http.MethodGet, "/manage/v2/databases/"+url.PathEscape(database), query, nil, http.StatusOK)
+	if err != nil {
+		return 0, err
+	}
+	var payload any
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return 0, err
+	}
+	lag, ok := findFirstQuantityByKey(payload, "replication-lag")
+	if !ok {
+		return 0, fmt.Errorf("database %s reports no replication lag", database)
+	}
+	return time.Duration(lag) * time.Second, nil
+}
+
+// GetForestDocumentCount returns the number of documents stored in the forest.
+func (c *managementClient) GetForestDocumentCount(ctx context.Context, forest string) (int64, error) {
+	query := url.Values{}
+	query.Set("view", "counts")
+	query.Set("format", "json")
+	data, _, err := c.doJSON(ctx, http.MethodGet, "/manage/v2/forests/"+url.PathEscape(forest), query, nil, http.StatusOK)
+	if err != nil {
+		return 0, err
+	}
+	var payload any
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return 0, err
+	}
+	count, ok := findFirstQuantityByKey(payload, "document-count")
+	if !ok {
+		return 0, fmt.Errorf("forest %s counts have no document-count", forest)
+	}
+	return int64(count), nil
+}
+
+// GetDatabaseRebalancerThrottle returns the rebalancer-throttle property of
+// the database, from 1 to 10.
+func (c *managementClient) GetDatabaseRebalancerThrottle(ctx context.Context, database string) (int32, error) {
+	query := url.Values{}
+	query.Set("format", "json")
+	data, _, err := c.doJSON(ctx, http.MethodGet, "/manage/v2/databases/"+url.PathEscape(database)+"/properties", query, nil, http.StatusOK)
+	if err != nil {
+		return 0, err
+	}
+	var payload any
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return 0, err
+	}
+	throttle, ok := findFirstQuantityByKey(payload, "rebalancer-throttle")
+	if !ok {
+		return 0, fmt.Errorf("database %s properties have no rebalancer-throttle", database)
+	}
+	return int32(throttle), nil
+}
+
+func (c *managementClient) SetDatabaseRebalancerThrottle(ctx context.Context, database string, throttle int32) error {
+	payload := map[string]any{"rebalancer-throttle": throttle}
+	_, _, err := c.doJSON(ctx, http.MethodPut, "/manage/v2/databases/"+url.PathEscape(database)+"/properties", nil, payload, http.StatusAccepted, http.StatusNoContent)
+	return err
+}
+
+// ListForestsStatus returns the status of every forest in the cluster.
+func (c *managementClient) ListForestsStatus(ctx context.Context) ([]ForestStatus, error) {
+	query := url.Values{}
+	query.Set("format", "json")
+	data, _, err := c.doJSON(ctx, http.MethodGet, "/manage/v2/forests", query, nil, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	var payload any
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, err
+	}
+	root, ok := payload.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("unexpected forest list payload")
+	}
+	forests := []ForestStatus{}
+	for _, item := range extractListItems(root, "forest-default-list", "list-items", "list-item") {
+		name := firstString(item, "nameref", "name")
+		if name == "" {
+			continue
+		}
+		status, err := c.getForestStatus(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		forests = append(forests, status)
+	}
+	return forests, nil
+}
+
+func (c *managementClient) getForestStatus(ctx context.Context, forest string) (ForestStatus, error) {
+	query := url.Values{}
+	query.Set("view", "status")
+	query.Set("format", "json")
+	data, _, err := c.doJSON(ctx, http.MethodGet, "/manage/v2/forests/"+url.PathEscape(forest), query, nil, http.StatusOK)
+	if err != nil {
+		return ForestStatus{}, err
+	}
+	var payload any
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return ForestStatus{}, err
+	}
+	status := ForestStatus{Name: forest}
+	status.State = strings.ToLower(findFirstValueStringByKey(payload, "state"))
+	walkAny(payload, func(node map[string]any) {
+		if status.Host == "" && firstString(node, "typeref") == "hosts" {
+			status.Host = findFirstStringByKeys(node["relation"], "nameref")
+		}
+		if status.Database == "" && firstString(node, "typeref") == "databases" {
+			status.Database = findFirstStringByKeys(node["relation"], "nameref")
+		}
+	})
+	if size, ok := findFirstQuantityByKey(payload, "data-size"); ok {
+		status.DataSizeMB = int64(size)
+	}
+	if space, ok := findFirstQuantityByKey(payload, "device-space"); ok {
+		status.DeviceSpaceMB = int64(space)
+	}
+	if merges, ok := findFirstQuantityByKey(payload, "merge-count"); ok && merges > 0 {
+		status.Merging = true
+	}
+	walkAny(payload, func(node map[string]any) {
+		if merges, ok := node["merges"]; ok && countEntries(merges) > 0 {
+			status.Merging = true
+		}
+	})
+	status.Reindexing = findFirstValueStringByKey(payload, "reindexing") == "true"
+	return status, nil
+}
+
+// ListForestReplicas returns the names of the replica forests of a forest,
+// empty when it has none.
+func (c *managementClient) ListForestReplicas(ctx context.Context, forest string) ([]string, error) {
+	query := url.Values{}
+	query.Set("format", "json")
+	data, _, err := c.doJSON(ctx, http.MethodGet, "/manage/v2/forests/"+url.PathEscape(forest)+"/properties", query, nil, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	var payload any
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, err
+	}
+	replicas := []string{}
+	walkAny(payload, func(node map[string]any) {
+		if name := firstString(node, "replica-name"); name != "" {
+			replicas = append(replicas, name)
+		}
+	})
+	return replicas, nil
+}
+
+// RestartForest restarts a forest. A master forest with replicas fails over
+// to one of them, which becomes the acting master.
+func (c *managementClient) RestartForest(ctx context.Context, forest string) error {
+	payload := map[string]any{"state": "restart"}
+	_, _, err := c.doJSON(ctx, http.MethodPost, "/manage/v2/forests/"+url.PathEscape(forest), nil, payload, http.StatusOK, http.StatusAccepted, http.StatusNoContent)
+	return err
+}
+
+// GetStatusView returns the status view of a resource list of the Manage API,
+// such as hosts, servers, forests or databases, as JSON.
+func (c *managementClient) GetStatusView(ctx context.Context, resource string) ([]byte, error) {
+	query := url.Values{}
+	query.Set("view", "status")
+	query.Set("format", "json")
+	data, _, err := c.doJSON(ctx, http.MethodGet, "/manage/v2/"+url.PathEscape(resource), query, nil, http.StatusOK)
+	return data, err
+}
+
+// GetGroupLoad returns the load of the app servers of groupName from their
+// status list, using the summary of the list when MarkLogic reports one.
+func (c *managementClient) GetGroupLoad(ctx context.Context, groupName string) (GroupLoad, error) {
+	query := url.Values{}
+	query.Set("view", "status")
+	query.Set("group-id", groupName)
+	query.Set("format", "json")
+	data, _, err := c.doJSON(ctx, http.MethodGet, "/manage/v2/servers", query, nil, http.StatusOK)
+	if err != nil {
+		return GroupLoad{}, err
+	}
+	var payload any
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return GroupLoad{}, err
+	}
+	root, ok := payload.(map[string]any)
+	if !ok {
+		return GroupLoad{}, fmt.Errorf("unexpected server status list payload")
+	}
+	list, _ := root["server-status-list"].(map[string]any)
+	if summary, ok := list["status-list-summary"].(map[string]any); ok {
+		load, found := groupLoadOf(summary)
+		if found {
+			return load, nil
+		}
+	}
+	total := GroupLoad{}
+	for _, item := range extractListItems(root, "server-status-list", "list-items", "list-item") {
+		load, _ := groupLoadOf(item)
+		total.RequestRate += load.RequestRate
+		total.ActiveRequests += load.ActiveRequests
+		total.QueueSize += load.QueueSize
+	}
+	return total, nil
+}
+
+func groupLoadOf(node map[string]any) (GroupLoad, bool) {
+	load := GroupLoad{}
+	rate, found := quantityValueAsFloat(node["request-rate"])
+	load.RequestRate = rate
+	if count, ok := quantityValueAsInt(node["request-count"]); ok {
+		load.ActiveRequests = int64(count)
+		found = true
+	}
+	if size, ok := quantityValueAsInt(node["queue-size"]); ok {
+		load.QueueSize = int64(size)
+		found = true
+	}
+	return load, found
+}
+
+// GetHostLicense returns the license details reported in the status of hostName.
+func (c *managementClient) GetHostLicense(ctx context.Context, hostName string) (HostLicense, error) {
+	query := url.Values{}
+	query.Set("view", "status")
+	query.Set("format", "json")
+	data, _, err := c.doJSON(ctx, http.MethodGet, "/manage/v2/hosts/"+url.PathEscape(hostName), query, nil, http.StatusOK)
+	if err != nil {
+		return HostLicense{}, err
+	}
+	var payload any
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return HostLicense{}, err
+	}
+	return HostLicense{
+		Licensee: findFirstValueStringByKey(payload, "licensee"),
+		Expires:  findFirstValueStringByKey(payload, "license-key-expires"),
+	}, nil
+}
+
+// SetHostZone sets the zone of the host, which MarkLogic takes into account
+// when it places forest replicas.
+func (c *managementClient) SetHostZone(ctx context.Context, hostName, zone string) error {
+	payload := map[string]any{"zone": zone}
+	_, _, err := c.doJSON(ctx, http.MethodPut, "/manage/v2/hosts/"+url.PathEscape(hostName)+"/properties", nil, payload, http.StatusAccepted, http.StatusNoContent)
+	return err
+}
+
+// SetHostName renames a host of the cluster, for example after the address
+// of the pod running it changed.
+func (c *managementClient) SetHostName(ctx context.Context, hostName, newName string) error {
+	payload := map[string]any{"host-name": newName}
+	_, _, err := c.doJSON(ctx, http.MethodPut, "/manage/v2/hosts/"+url.PathEscape(hostName)+"/properties", nil, payload, http.StatusAccepted, http.StatusNoContent)
+	return err
+}
+
+// UpgradeSecurityDatabase calls the Admin API init endpoint of the host,
+// which upgrades its configuration files and the Security database after the
+// host was restarted on a new MarkLogic release. It returns true when there
+// was nothing left to upgrade. A 202 response means the upgrade ran and the
+// host restarts, so the caller should call it again once the host is back.
+func (c *managementClient) UpgradeSecurityDatabase(ctx context.Context) (upgraded bool, err error) {
+	endpoint := adminBaseURL(c.baseURL) + "/admin/v1/init"
+	resp, err := c.doRequestWithAuth(ctx, http.MethodPost, endpoint, map[string]string{"Content-Type": "application/json"}, []byte("{}"))
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		err = errors.Join(err, resp.Body.Close())
+	}()
+	switch resp.StatusCode {
+	case http.StatusNoContent, http.StatusOK:
+		return true, nil
+	case http.StatusAccepted:
+		return false, nil
+	}
+	data, _ := io.ReadAll(resp.Body)
+	return false, fmt.Errorf("POST /admin/v1/init returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+}
+
+func (c *managementClient) StartDatabaseRestore(ctx context.Context, database string, req DatabaseRestoreRequest) (string, error) {
+	payload := map[string]any{
+		"operation":        "restore-database",
+		"backup-path":      req.Directory,
+		"forest":           req.Forests,
+		"include-replicas": req.IncludeReplicas,
+	}
+	if req.JournalArchive {
+		payload["journal-archiving"] = true
+	}
+	if req.RestoreToTime != "" {
+		payload["restore-to-time"] = req.RestoreToTime
+	}
+	data, _, err := c.doJSON(ctx, http.MethodPost, "/manage/v2/databases/"+url.PathEscape(database), nil, payload, http.StatusOK, http.StatusAccepted)
+	if err != nil {
+		return "", err
+	}
+	var response any
+	if err := json.Unmarshal(data, &response); err != nil {
+		return "", err
+	}
+	jobID := findFirstStringByKeys(response, "job-id")
+	if jobID == "" {
+		return "", fmt.Errorf("restore of database %s did not return a job id", database)
+	}
+	return jobID, nil
+}
+
+func (c *managementClient) GetDatabaseRestoreStatus(ctx context.Context, database, jobID string) (DatabaseRestoreStatus, error) {
+	payload := map[string]any{"operation": "restore-status", "job-id": jobID}
+	data, _, err := c.doJSON(ctx, http.MethodPost, "/manage/v2/databases/"+url.PathEscape(database), nil, payload, http.StatusOK)
+	if err != nil {
+		return DatabaseRestoreStatus{}, err
+	}
+	var response any
+	if err := json.Unmarshal(data, &response); err != nil {
+		return DatabaseRestoreStatus{}, err
+	}
+	status := DatabaseRestoreStatus{Forests: map[string]string{}}
+	if root, ok := response.(map[string]any); ok {
+		if restoreStatus, ok := root["restore-status"].(map[string]any); ok {
+			status.Status = strings.ToLower(firstString(restoreStatus, "status"))
+		}
+	}
+	walkAny(response, func(node map[string]any) {
+		if name := firstString(node, "forest-name"); name != "" {
+			status.Forests[name] = strings.ToLower(firstString(node, "status"))
+		}
+	})
+	if status.Status == "" {
+		status.Status = strings.ToLower(findFirstStringByKeys(response, "status"))
+	}
+	return status, nil
+}
+
 func (c *managementClient) fetchClusterVersion(ctx context.Context) (string, error) {
 	query := url.Values{}
 	query.Set("format", "json")
@@ -845,6 +1799,19 @@ func quantityValueAsInt(value any) (int, bool) {
 	return 0, false
 }
 
+func quantityValueAsFloat(value any) (float64, bool) {
+	if valueMap, ok := value.(map[string]any); ok {
+		value = valueMap["value"]
+	}
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	}
+	return 0, false
+}
+
 func countForests(payload any) int {
 	forestNodes := 0
 	walkAny(payload, func(m map[string]any) {
@@ -1141,6 +2108,51 @@ func findClusterNameByVersionEnvelope(node map[string]any) string {
 	return ""
 }
 
+// findFirstValueStringByKey is findFirstStringByKeys for a single key that also
+// unwraps the {"units": ..., "value": ...} objects of the status views.
+func findFirstValueStringByKey(payload any, key string) string {
+	found := ""
+	walkAny(payload, func(node map[string]any) {
+		if found != "" {
+			return
+		}
+		switch value := node[key].(type) {
+		case map[string]any:
+			found = toString(value["value"])
+		default:
+			found = toString(value)
+		}
+	})
+	return found
+}
+
+// countEntries returns the number of entries of a list or object value.
+func countEntries(value any) int {
+	switch current := value.(type) {
+	case []any:
+		return len(current)
+	case map[string]any:
+		return len(current)
+	}
+	return 0
+}
+
+func findFirstQuantityByKey(payload any, key string) (int, bool) {
+	var (
+		found int
+		ok    bool
+	)
+	walkAny(payload, func(node map[string]any) {
+		if ok {
+			return
+		}
+		if value, exists := node[key]; exists {
+			found, ok = quantityValueAsInt(value)
+		}
+	})
+	return found, ok
+}
+
 func findFirstStringByKeys(payload any, keys ...string) string {
 	switch current := payload.(type) {
 	case map[string]any:
@@ -11,6 +11,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestRemoveDynamicHostUsesXMLBodyContract(t *testing.T) {
@@ -631,3 +632,557 @@ func TestListHostsStatusParsesHostDefaultListEnvelope(t *testing.T) {
 		t.Fatalf("expected host name node-0, got %s", hosts[0].Name)
 	}
 }
+
+func TestSetCloudCredentialsSendsAzureSASToken(t *testing.T) {
+	t.Parallel()
+
+	var gotMethod string
+	var gotPath string
+	var gotPayload map[string]any
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer r.Body.Close()
+		gotMethod = r.Method
+		gotPath = r.URL.Path
+		if err := json.NewDecoder(r.Body).Decode(&gotPayload); err != nil {
+			t.Fatalf("decode body: %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := &managementClient{
+		baseURL:    server.URL,
+		username:   "user",
+		password:   "password",
+		httpClient: server.Client(),
+	}
+
+	err := client.SetCloudCredentials(context.Background(), CloudCredentials{
+		Type:                "azure",
+		AzureStorageAccount: "mlbackups",
+		AzureStorageKey:     "ignored",
+		AzureSASToken:       "sv=2024&sig=abc",
+	})
+	if err != nil {
+		t.Fatalf("SetCloudCredentials returned error: %v", err)
+	}
+	if gotMethod != http.MethodPut || gotPath != "/manage/v2/credentials/properties" {
+		t.Fatalf("unexpected request %s %s", gotMethod, gotPath)
+	}
+	if gotPayload["azure-storage-account"] != "mlbackups" || gotPayload["azure-sas-token"] != "sv=2024&sig=abc" {
+		t.Fatalf("unexpected payload: %v", gotPayload)
+	}
+	if _, ok := gotPayload["azure-storage-key"]; ok {
+		t.Fatalf("expected storage key to be omitted when a SAS token is set, got %v", gotPayload)
+	}
+}
+
+func TestSetCloudCredentialsRejectsUnknownType(t *testing.T) {
+	t.Parallel()
+
+	client := &managementClient{baseURL: "http://127.0.0.1:0", httpClient: http.DefaultClient}
+	if err := client.SetCloudCredentials(context.Background(), CloudCredentials{Type: "gcs"}); err == nil {
+		t.Fatal("expected error for unsupported credential type")
+	}
+}
+
+func TestSetDatabaseBackupsBuildsWeeklySchedule(t *testing.T) {
+	t.Parallel()
+
+	var gotPath string
+	var gotPayload map[string][]map[string]any
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer r.Body.Close()
+		gotPath = r.URL.Path
+		if err := json.NewDecoder(r.Body).Decode(&gotPayload); err != nil {
+			t.Fatalf("decode body: %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := &managementClient{baseURL: server.URL, username: "user", password: "password", httpClient: server.Client()}
+	err := client.SetDatabaseBackups(context.Background(), "Documents", []DatabaseBackupSchedule{{
+		Type:       "weekly",
+		Period:     1,
+		StartTime:  "01:00:00",
+		Days:       []string{"sunday"},
+		Directory:  "s3://ml/Documents/",
+		MaxBackups: 2,
+	}})
+	if err != nil {
+		t.Fatalf("SetDatabaseBackups returned error: %v", err)
+	}
+	if gotPath != "/manage/v2/databases/Documents/properties" {
+		t.Fatalf("unexpected path %s", gotPath)
+	}
+	entries := gotPayload["database-backup"]
+	if len(entries) != 1 {
+		t.Fatalf("expected one database-backup entry, got %v", gotPayload)
+	}
+	entry := entries[0]
+	if entry["backup-type"] != "weekly" || entry["backup-start-time"] != "01:00:00" || entry["backup-directory"] != "s3://ml/Documents/" {
+		t.Fatalf("unexpected entry %v", entry)
+	}
+	if days, ok := entry["backup-day"].([]any); !ok || len(days) != 1 || days[0] != "sunday" {
+		t.Fatalf("unexpected backup-day %v", entry["backup-day"])
+	}
+}
+
+func TestEvalJoinsMultipartResults(t *testing.T) {
+	t.Parallel()
+
+	var gotForm string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("parse form: %v", err)
+		}
+		gotForm = r.PostForm.Get("xquery")
+		w.Header().Set("Content-Type", "multipart/mixed; boundary=ML_BOUNDARY")
+		_, _ = io.WriteString(w, "--ML_BOUNDARY\r\nContent-Type: text/plain\r\n\r\n1\r\n--ML_BOUNDARY\r\nContent-Type: text/plain\r\n\r\n2\r\n--ML_BOUNDARY--\r\n")
+	}))
+	defer server.Close()
+
+	host := strings.TrimPrefix(server.URL, "http://")
+	out, err := Eval(context.Background(), ClientOptions{Host: host, HTTPClient: server.Client()}, "xquery", "(1, 2)")
+	if err != nil {
+		t.Fatalf("Eval returned error: %v", err)
+	}
+	if gotForm != "(1, 2)" {
+		t.Fatalf("unexpected query %q", gotForm)
+	}
+	if out != "1\n2" {
+		t.Fatalf("unexpected result %q", out)
+	}
+}
+
+func TestListForestsStatusReadsStateAndSpace(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		switch r.URL.Path {
+		case "/manage/v2/forests":
+			_, _ = w.Write([]byte(`{"forest-default-list":{"list-items":{"list-item":[{"nameref":"Documents"}]}}}`))
+		case "/manage/v2/forests/Documents":
+			if r.URL.Query().Get("view") != "status" {
+				t.Fatalf("expected view=status, got %s", r.URL.Query().Get("view"))
+			}
+			_, _ = w.Write([]byte(`{"forest-status":{"id":"1","name":"Documents","relations":{"relation-group":[{"typeref":"hosts","relation":[{"nameref":"node-0.node.default.svc.cluster.local"}]},{"typeref":"databases","relation":[{"nameref":"Documents"}]}]},"status-properties":{"state":{"units":"enum","value":"open"},"data-size":{"units":"MB","value":120},"device-space":{"units":"MB","value":4096},"merge-count":{"units":"quantity","value":1},"reindexing":{"units":"bool","value":false}}}}`))
+		default:
+			t.Fatalf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := &managementClient{
+		baseURL:    server.URL,
+		username:   "user",
+		password:   "password",
+		httpClient: server.Client(),
+	}
+
+	forests, err := client.ListForestsStatus(context.Background())
+	if err != nil {
+		t.Fatalf("ListForestsStatus returned error: %v", err)
+	}
+	if len(forests) != 1 {
+		t.Fatalf("expected 1 forest, got %d", len(forests))
+	}
+	if forests[0].State != "open" || forests[0].Host != "node-0.node.default.svc.cluster.local" || forests[0].Database != "Documents" || forests[0].DataSizeMB != 120 || forests[0].DeviceSpaceMB != 4096 {
+		t.Fatalf("unexpected forest status %+v", forests[0])
+	}
+	if !forests[0].Merging || forests[0].Reindexing {
+		t.Fatalf("expected a merging forest that is not reindexing, got %+v", forests[0])
+	}
+}
+
+func TestUpgradeSecurityDatabaseUsesAdminPort(t *testing.T) {
+	t.Parallel()
+
+	status := http.StatusAccepted
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/admin/v1/init" {
+			t.Fatalf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	if got := adminBaseURL("https://node-0.node.default.svc.cluster.local:8002"); got != "https://node-0.node.default.svc.cluster.local:8001" {
+		t.Fatalf("unexpected admin URL %s", got)
+	}
+	client := &managementClient{
+		baseURL:    server.URL,
+		username:   "user",
+		password:   "password",
+		httpClient: server.Client(),
+	}
+
+	upgraded, err := client.UpgradeSecurityDatabase(context.Background())
+	if err != nil || upgraded {
+		t.Fatalf("expected a pending upgrade while the host restarts, got %t, %v", upgraded, err)
+	}
+	status = http.StatusNoContent
+	upgraded, err = client.UpgradeSecurityDatabase(context.Background())
+	if err != nil || !upgraded {
+		t.Fatalf("expected the upgrade to be complete, got %t, %v", upgraded, err)
+	}
+}
+
+func TestCreateForestSkipsExistingForest(t *testing.T) {
+	t.Parallel()
+
+	var posted map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer r.Body.Close()
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/manage/v2/forests/Documents-dnode-0-1":
+			w.WriteHeader(http.StatusOK)
+		case r.Method == http.MethodGet:
+			w.WriteHeader(http.StatusNotFound)
+		case r.Method == http.MethodPost && r.URL.Path == "/manage/v2/forests":
+			if err := json.NewDecoder(r.Body).Decode(&posted); err != nil {
+				t.Errorf("decode body: %v", err)
+			}
+			w.WriteHeader(http.StatusCreated)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	client := &managementClient{baseURL: server.URL, httpClient: server.Client()}
+	created, err := client.CreateForest(context.Background(), ForestSpec{Name: "Documents-dnode-0-1", Host: "dnode-0", Database: "Documents"})
+	if err != nil || created || posted != nil {
+		t.Fatalf("expected the existing forest to be kept, got %v %v %v", created, posted, err)
+	}
+	created, err = client.CreateForest(context.Background(), ForestSpec{Name: "Documents-dnode-1-1", Host: "dnode-1", Database: "Documents", DataDirectory: "/data"})
+	if err != nil || !created {
+		t.Fatalf("expected the forest to be created, got %v %v", created, err)
+	}
+	if posted["forest-name"] != "Documents-dnode-1-1" || posted["host"] != "dnode-1" || posted["database"] != "Documents" || posted["data-directory"] != "/data" {
+		t.Fatalf("unexpected payload: %v", posted)
+	}
+}
+
+func TestListAndMigrateDatabasePartitions(t *testing.T) {
+	t.Parallel()
+
+	var posted map[string]any
+	var postedPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer r.Body.Close()
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/manage/v2/databases/Sales/partitions":
+			_, _ = w.Write([]byte(`{"partition-default-list":{"list-items":{"list-item":[{"nameref":"2019"}]}}}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/manage/v2/databases/Sales/partitions/2019":
+			_, _ = w.Write([]byte(`{"partition-properties":{"upper-bound":"2020-01-01T00:00:00Z","data-directory":"/var/opt/MarkLogic/tiers/warm"}}`))
+		case r.Method == http.MethodPost:
+			postedPath = r.URL.Path
+			if err := json.NewDecoder(r.Body).Decode(&posted); err != nil {
+				t.Errorf("decode body: %v", err)
+			}
+			w.WriteHeader(http.StatusAccepted)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := &managementClient{baseURL: server.URL, httpClient: server.Client()}
+	partitions, err := client.ListDatabasePartitions(context.Background(), "Sales")
+	if err != nil || len(partitions) != 1 {
+		t.Fatalf("expected one partition, got %v %v", partitions, err)
+	}
+	if partitions[0] != (Partition{Name: "2019", UpperBound: "2020-01-01T00:00:00Z", DataDirectory: "/var/opt/MarkLogic/tiers/warm"}) {
+		t.Fatalf("unexpected partition: %+v", partitions[0])
+	}
+	if err := client.MigratePartition(context.Background(), "Sales", "2019", "/var/opt/MarkLogic/tiers/archive"); err != nil {
+		t.Fatalf("migrate partition: %v", err)
+	}
+	if postedPath != "/manage/v2/databases/Sales/partitions/2019" || posted["operation"] != "migrate-partition" ||
+		posted["data-directory"] != "/var/opt/MarkLogic/tiers/archive" {
+		t.Fatalf("unexpected migration request %s: %v", postedPath, posted)
+	}
+}
+
+func TestGetDatabaseReplicationLag(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/manage/v2/databases/Documents" || r.URL.Query().Get("view") != "status" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = io.WriteString(w, `{"database-status":{"status-properties":{"replication-lag":{"units":"sec","value":4}}}}`)
+	}))
+	defer server.Close()
+
+	client := &managementClient{baseURL: server.URL, httpClient: server.Client()}
+	lag, err := client.GetDatabaseReplicationLag(context.Background(), "Documents")
+	if err != nil || lag != 4*time.Second {
+		t.Fatalf("expected a lag of 4s, got %v %v", lag, err)
+	}
+}
+
+func TestListForeignClustersStatusCountsConnectedHosts(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/manage/v2/clusters/blue/foreign-clusters":
+			_, _ = io.WriteString(w, `{"foreign-cluster-default-list":{"list-items":{"list-item":[{"nameref":"green"}]}}}`)
+		case "/manage/v2/clusters/blue/foreign-clusters/green":
+			if r.URL.Query().Get("view") != "status" {
+				t.Errorf("expected the status view, got %q", r.URL.RawQuery)
+			}
+			_, _ = io.WriteString(w, `{"foreign-cluster-status":{"foreign-hosts":{"foreign-host":[`+
+				`{"foreign-host-name":"green-0","connection-status":"connected"},`+
+				`{"foreign-host-name":"green-1","connected":true},`+
+				`{"foreign-host-name":"green-2","connection-status":"disconnected"}]}}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := &managementClient{baseURL: server.URL, httpClient: server.Client()}
+	clusters, err := client.ListForeignClustersStatus(context.Background(), "blue")
+	if err != nil {
+		t.Fatalf("list foreign clusters: %v", err)
+	}
+	if len(clusters) != 1 || clusters[0] != (ForeignClusterStatus{Name: "green", Hosts: 3, ConnectedHosts: 2}) {
+		t.Fatalf("unexpected foreign clusters: %+v", clusters)
+	}
+}
+
+func TestListForestReplicasAndRestartForest(t *testing.T) {
+	t.Parallel()
+
+	var restartPayload map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer r.Body.Close()
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/manage/v2/forests/Documents-1/properties":
+			_, _ = io.WriteString(w, `{"forest-name":"Documents-1","forest-replica":[`+
+				`{"replica-name":"Documents-1-r1","host":"dnode-1"},{"replica-name":"Documents-1-r2","host":"dnode-2"}]}`)
+		case r.Method == http.MethodGet && r.URL.Path == "/manage/v2/forests/Meters/properties":
+			_, _ = io.WriteString(w, `{"forest-name":"Meters"}`)
+		case r.Method == http.MethodPost && r.URL.Path == "/manage/v2/forests/Documents-1":
+			if err := json.NewDecoder(r.Body).Decode(&restartPayload); err != nil {
+				t.Errorf("decode body: %v", err)
+			}
+			w.WriteHeader(http.StatusAccepted)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := &managementClient{baseURL: server.URL, httpClient: server.Client()}
+	replicas, err := client.ListForestReplicas(context.Background(), "Documents-1")
+	if err != nil {
+		t.Fatalf("list forest replicas: %v", err)
+	}
+	if len(replicas) != 2 || replicas[0] != "Documents-1-r1" || replicas[1] != "Documents-1-r2" {
+		t.Fatalf("unexpected replicas: %v", replicas)
+	}
+	if replicas, err := client.ListForestReplicas(context.Background(), "Meters"); err != nil || len(replicas) != 0 {
+		t.Fatalf("expected no replicas, got %v, %v", replicas, err)
+	}
+	if err := client.RestartForest(context.Background(), "Documents-1"); err != nil {
+		t.Fatalf("restart forest: %v", err)
+	}
+	if restartPayload["state"] != "restart" {
+		t.Fatalf("unexpected restart payload: %v", restartPayload)
+	}
+}
+
+func TestDatabaseRebalancerThrottleUsesProperties(t *testing.T) {
+	t.Parallel()
+
+	var putPayload map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer r.Body.Close()
+		if r.URL.Path != "/manage/v2/databases/Documents/properties" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Method == http.MethodPut {
+			if err := json.NewDecoder(r.Body).Decode(&putPayload); err != nil {
+				t.Errorf("decode body: %v", err)
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		_, _ = io.WriteString(w, `{"database-name":"Documents","rebalancer-enable":true,"rebalancer-throttle":5}`)
+	}))
+	defer server.Close()
+
+	client := &managementClient{baseURL: server.URL, httpClient: server.Client()}
+	throttle, err := client.GetDatabaseRebalancerThrottle(context.Background(), "Documents")
+	if err != nil || throttle != 5 {
+		t.Fatalf("expected throttle 5, got %d %v", throttle, err)
+	}
+	if err := client.SetDatabaseRebalancerThrottle(context.Background(), "Documents", 2); err != nil {
+		t.Fatalf("SetDatabaseRebalancerThrottle returned error: %v", err)
+	}
+	if putPayload["rebalancer-throttle"] != float64(2) {
+		t.Fatalf("unexpected payload: %v", putPayload)
+	}
+}
+
+func TestGetGroupLoadReadsServerStatusSummary(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/manage/v2/servers" || r.URL.Query().Get("group-id") != "enode" || r.URL.Query().Get("view") != "status" {
+			t.Fatalf("unexpected request %s", r.URL.String())
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"server-status-list":{"status-list-summary":{"request-rate":{"units":"requests/sec","value":12.5},"request-count":{"units":"quantity","value":4},"queue-size":{"units":"quantity","value":2}},"list-items":{"list-item":[{"nameref":"App-Services","request-rate":{"value":12.5}}]}}}`))
+	}))
+	defer server.Close()
+
+	client := &managementClient{
+		baseURL:    server.URL,
+		username:   "user",
+		password:   "password",
+		httpClient: server.Client(),
+	}
+
+	load, err := client.GetGroupLoad(context.Background(), "enode")
+	if err != nil {
+		t.Fatalf("GetGroupLoad returned error: %v", err)
+	}
+	if load.RequestRate != 12.5 || load.ActiveRequests != 4 || load.QueueSize != 2 {
+		t.Fatalf("unexpected group load %+v", load)
+	}
+}
+
+func TestSSLFIPSEnabledReadsAndSetsClusterProperty(t *testing.T) {
+	t.Parallel()
+
+	var gotBody map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/manage/v2/properties" {
+			t.Fatalf("unexpected path %s", r.URL.Path)
+		}
+		switch r.Method {
+		case http.MethodGet:
+			if r.URL.Query().Get("format") != "json" {
+				t.Fatalf("expected format=json, got %s", r.URL.RawQuery)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"cluster-name":"ml","ssl-fips-enabled":true}`))
+		case http.MethodPut:
+			if err := json.NewDecoder(r.Body).Decode(&gotBody); err != nil {
+				t.Fatalf("decode body: %v", err)
+			}
+			w.WriteHeader(http.StatusAccepted)
+		default:
+			t.Fatalf("unexpected method %s", r.Method)
+		}
+	}))
+	defer server.Close()
+
+	client := &managementClient{
+		baseURL:    server.URL,
+		username:   "user",
+		password:   "password",
+		httpClient: server.Client(),
+	}
+
+	enabled, err := client.GetSSLFIPSEnabled(context.Background())
+	if err != nil || !enabled {
+		t.Fatalf("expected ssl-fips-enabled to be true, got %v, %v", enabled, err)
+	}
+	if err := client.SetSSLFIPSEnabled(context.Background(), true); err != nil {
+		t.Fatalf("SetSSLFIPSEnabled returned error: %v", err)
+	}
+	if gotBody["ssl-fips-enabled"] != true {
+		t.Fatalf("expected ssl-fips-enabled in the request body, got %v", gotBody)
+	}
+}
+
+func TestGroupDiagnosticsReadsAndSetsGroupProperties(t *testing.T) {
+	t.Parallel()
+
+	var gotBody map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/manage/v2/groups/Default/properties" {
+			t.Fatalf("unexpected path %s", r.URL.Path)
+		}
+		switch r.Method {
+		case http.MethodGet:
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"group-name":"Default","events-activated":true,"event":[{"event-id":"Merge"}],"file-log-level":"info"}`))
+		case http.MethodPut:
+			if err := json.NewDecoder(r.Body).Decode(&gotBody); err != nil {
+				t.Fatalf("decode body: %v", err)
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Fatalf("unexpected method %s", r.Method)
+		}
+	}))
+	defer server.Close()
+
+	client := &managementClient{
+		baseURL:    server.URL,
+		username:   "user",
+		password:   "password",
+		httpClient: server.Client(),
+	}
+
+	diagnostics, err := client.GetGroupDiagnostics(context.Background(), "Default")
+	if err != nil {
+		t.Fatalf("GetGroupDiagnostics returned error: %v", err)
+	}
+	if !diagnostics.EventsActivated || len(diagnostics.Events) != 1 || diagnostics.Events[0] != "Merge" || diagnostics.FileLogLevel != "info" {
+		t.Fatalf("unexpected diagnostics %+v", diagnostics)
+	}
+	if err := client.SetGroupDiagnostics(context.Background(), "Default", GroupDiagnostics{}); err != nil {
+		t.Fatalf("SetGroupDiagnostics returned error: %v", err)
+	}
+	if gotBody["events-activated"] != false || len(gotBody["event"].([]any)) != 0 {
+		t.Fatalf("expected the events to be removed, got %v", gotBody)
+	}
+	if _, ok := gotBody["file-log-level"]; ok {
+		t.Fatalf("expected the file log level to be left alone, got %v", gotBody)
+	}
+}
+
+func TestSetAppServerPropertiesPutsTheServerPropertiesOfTheGroup(t *testing.T) {
+	t.Parallel()
+
+	var gotBody map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/manage/v2/servers/App-Services/properties" || r.URL.Query().Get("group-id") != "E-Nodes" {
+			t.Fatalf("unexpected request %s %s", r.Method, r.URL)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&gotBody); err != nil {
+			t.Fatalf("decode body: %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := &managementClient{
+		baseURL:    server.URL,
+		username:   "user",
+		password:   "password",
+		httpClient: server.Client(),
+	}
+
+	properties := map[string]any{"request-timeout": 120, "url-rewriter": "/rewriter.xml"}
+	if err := client.SetAppServerProperties(context.Background(), "E-Nodes", "App-Services", properties); err != nil {
+		t.Fatalf("SetAppServerProperties returned error: %v", err)
+	}
+	if gotBody["request-timeout"] != float64(120) || gotBody["url-rewriter"] != "/rewriter.xml" {
+		t.Fatalf("unexpected body %v", gotBody)
+	}
+}
@@ -0,0 +1,192 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+// Package mltest provides helpers for writing integration tests against
+// MarkLogic clusters managed by the operator: building and creating a
+// MarklogicCluster, waiting until it is ready both at the Kubernetes and at the
+// MarkLogic API level, and running queries against it.
+package mltest
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const DefaultPollInterval = 5 * time.Second
+
+// TestingT is the subset of testing.TB used by the helpers, so they work with
+// the standard library as well as Ginkgo and e2e-framework.
+type TestingT interface {
+	Helper()
+	Logf(format string, args ...any)
+}
+
+// ClusterOption customizes a MarklogicCluster built by NewCluster.
+type ClusterOption func(*marklogicv1.MarklogicCluster)
+
+// NewCluster returns a single group MarklogicCluster with one bootstrap replica.
+func NewCluster(name, namespace string, opts ...ClusterOption) *marklogicv1.MarklogicCluster {
+	replicas := int32(1)
+	cr := &marklogicv1.MarklogicCluster{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "marklogic.progress.com/v1", Kind: "MarklogicCluster"},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{{
+				Name:        "node",
+				Replicas:    &replicas,
+				IsBootstrap: true,
+			}},
+		},
+	}
+	for _, opt := range opts {
+		opt(cr)
+	}
+	return cr
+}
+
+// WithImage sets the MarkLogic image of the cluster.
+func WithImage(image string) ClusterOption {
+	return func(cr *marklogicv1.MarklogicCluster) {
+		cr.Spec.Image = image
+	}
+}
+
+// WithGroup appends a non bootstrap group with the given replica count.
+func WithGroup(name string, replicas int32) ClusterOption {
+	return func(cr *marklogicv1.MarklogicCluster) {
+		cr.Spec.MarkLogicGroups = append(cr.Spec.MarkLogicGroups, &marklogicv1.MarklogicGroups{
+			Name:     name,
+			Replicas: &replicas,
+		})
+	}
+}
+
+// WithReplicas sets the replica count of the bootstrap group.
+func WithReplicas(replicas int32) ClusterOption {
+	return func(cr *marklogicv1.MarklogicCluster) {
+		for _, group := range cr.Spec.MarkLogicGroups {
+			if group.IsBootstrap {
+				group.Replicas = &replicas
+			}
+		}
+	}
+}
+
+// WithAdminSecret points the cluster at an existing admin credential Secret.
+func WithAdminSecret(secretName string) ClusterOption {
+	return func(cr *marklogicv1.MarklogicCluster) {
+		if cr.Spec.Auth == nil {
+			cr.Spec.Auth = &marklogicv1.AdminAuth{}
+		}
+		cr.Spec.Auth.SecretName = &secretName
+	}
+}
+
+// CreateCluster creates the cluster. Callers own the namespace and its cleanup.
+func CreateCluster(ctx context.Context, t TestingT, c client.Client, cr *marklogicv1.MarklogicCluster) error {
+	t.Helper()
+	t.Logf("Creating MarklogicCluster %s/%s", cr.Namespace, cr.Name)
+	return c.Create(ctx, cr)
+}
+
+// WaitForClusterPodsReady waits until the StatefulSet of every group of the
+// cluster reports all replicas ready.
+func WaitForClusterPodsReady(ctx context.Context, t TestingT, c client.Reader, namespace, name string, timeout time.Duration) error {
+	t.Helper()
+	return poll(ctx, timeout, func() (bool, error) {
+		cr := &marklogicv1.MarklogicCluster{}
+		if err := c.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, cr); err != nil {
+			if apierrors.IsNotFound(err) {
+				t.Logf("MarklogicCluster %s/%s not found yet", namespace, name)
+				return false, nil
+			}
+			return false, err
+		}
+		for _, group := range cr.Spec.MarkLogicGroups {
+			desired := int32(1)
+			if group.Replicas != nil {
+				desired = *group.Replicas
+			}
+			sts := &appsv1.StatefulSet{}
+			if err := c.Get(ctx, types.NamespacedName{Name: group.Name, Namespace: namespace}, sts); err != nil {
+				if apierrors.IsNotFound(err) {
+					t.Logf("StatefulSet %s/%s not created yet", namespace, group.Name)
+					return false, nil
+				}
+				return false, err
+			}
+			if sts.Status.ReadyReplicas < desired {
+				t.Logf("StatefulSet %s/%s has %d/%d ready replicas", namespace, group.Name, sts.Status.ReadyReplicas, desired)
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+}
+
+// AdminCredentials returns the username and password from the admin Secret of the cluster.
+func AdminCredentials(ctx context.Context, c client.Reader, cr *marklogicv1.MarklogicCluster) (string, string, error) {
+	secretName := cr.Name + "-admin"
+	if cr.Spec.Auth != nil && cr.Spec.Auth.SecretName != nil && *cr.Spec.Auth.SecretName != "" {
+		secretName = *cr.Spec.Auth.SecretName
+	}
+	return SecretCredentials(ctx, c, cr.Namespace, secretName, "username", "password")
+}
+
+// SecretCredentials returns the username and password stored under the given
+// keys of a Secret, such as the admin Secret of a cluster or of a monitoring
+// stack the test queries.
+func SecretCredentials(ctx context.Context, c client.Reader, namespace, secretName, usernameKey, passwordKey string) (string, string, error) {
+	secret := &corev1.Secret{}
+	if err := c.Get(ctx, types.NamespacedName{Name: secretName, Namespace: namespace}, secret); err != nil {
+		return "", "", err
+	}
+	username, hasUser := secret.Data[usernameKey]
+	password, hasPass := secret.Data[passwordKey]
+	if !hasUser || !hasPass {
+		return "", "", fmt.Errorf("secret %s missing %s/%s", secretName, usernameKey, passwordKey)
+	}
+	return string(username), string(password), nil
+}
+
+// ExpectedHosts returns the total number of MarkLogic hosts the cluster should have.
+func ExpectedHosts(cr *marklogicv1.MarklogicCluster) int {
+	total := 0
+	for _, group := range cr.Spec.MarkLogicGroups {
+		if group.Replicas != nil {
+			total += int(*group.Replicas)
+		} else {
+			total++
+		}
+	}
+	return total
+}
+
+func poll(ctx context.Context, timeout time.Duration, condition func() (bool, error)) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		done, err := condition()
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out after %v", timeout)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(DefaultPollInterval):
+		}
+	}
+}
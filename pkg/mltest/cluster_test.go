@@ -0,0 +1,94 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package mltest
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newTestScheme(t *testing.T) *runtime.Scheme {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	for _, add := range []func(*runtime.Scheme) error{marklogicv1.AddToScheme, appsv1.AddToScheme, corev1.AddToScheme} {
+		if err := add(scheme); err != nil {
+			t.Fatalf("failed to build scheme: %v", err)
+		}
+	}
+	return scheme
+}
+
+func TestNewClusterAppliesOptions(t *testing.T) {
+	t.Parallel()
+
+	cr := NewCluster("ml", "test", WithImage("progressofficial/marklogic-db:12.0.3"), WithReplicas(3), WithGroup("enode", 2), WithAdminSecret("creds"))
+	if cr.Spec.Image != "progressofficial/marklogic-db:12.0.3" {
+		t.Fatalf("unexpected image %q", cr.Spec.Image)
+	}
+	if len(cr.Spec.MarkLogicGroups) != 2 || !cr.Spec.MarkLogicGroups[0].IsBootstrap {
+		t.Fatalf("unexpected groups %+v", cr.Spec.MarkLogicGroups)
+	}
+	if ExpectedHosts(cr) != 5 {
+		t.Fatalf("expected 5 hosts, got %d", ExpectedHosts(cr))
+	}
+	if *cr.Spec.Auth.SecretName != "creds" {
+		t.Fatalf("unexpected admin secret %v", cr.Spec.Auth.SecretName)
+	}
+}
+
+func TestWaitForClusterPodsReadyAndAdminCredentials(t *testing.T) {
+	t.Parallel()
+
+	cr := NewCluster("ml", "test", WithReplicas(2))
+	sts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "node", Namespace: "test"},
+		Status:     appsv1.StatefulSetStatus{ReadyReplicas: 2},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "ml-admin", Namespace: "test"},
+		Data:       map[string][]byte{"username": []byte("admin"), "password": []byte("secret")},
+	}
+	c := fake.NewClientBuilder().WithScheme(newTestScheme(t)).WithObjects(cr, sts, secret).Build()
+
+	if err := WaitForClusterPodsReady(context.Background(), t, c, "test", "ml", time.Second); err != nil {
+		t.Fatalf("expected cluster pods to be ready, got %v", err)
+	}
+	username, password, err := AdminCredentials(context.Background(), c, cr)
+	if err != nil || username != "admin" || password != "secret" {
+		t.Fatalf("unexpected credentials %q/%q err=%v", username, password, err)
+	}
+}
+
+func TestWaitForClusterPodsReadyTimesOut(t *testing.T) {
+	t.Parallel()
+
+	cr := NewCluster("ml", "test")
+	c := fake.NewClientBuilder().WithScheme(newTestScheme(t)).WithObjects(cr).Build()
+
+	err := WaitForClusterPodsReady(context.Background(), t, c, "test", "ml", 0)
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("expected timeout error, got %v", err)
+	}
+}
+
+func TestQueryOptionsDefaultToAppServicesPort(t *testing.T) {
+	t.Parallel()
+
+	endpoint := Endpoint{Host: "localhost:18002"}
+	if got := endpoint.queryOptions().Host; got != "localhost" {
+		t.Fatalf("expected port to be stripped from query host, got %q", got)
+	}
+	endpoint.QueryHost = "localhost:18000"
+	if got := endpoint.queryOptions().Host; got != "localhost:18000" {
+		t.Fatalf("expected explicit query host, got %q", got)
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package mltest
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mlmanage"
+)
+
+// Endpoint describes how the test process reaches MarkLogic, for example a
+// port-forward to localhost or the service DNS name when running in-cluster.
+type Endpoint struct {
+	// Host of the Manage server, with or without port. Port 8002 is used when omitted.
+	Host string
+	// QueryHost of the App-Services server used by Eval. Defaults to Host on port 8000.
+	QueryHost string
+	Username  string
+	Password  string
+	UseTLS    bool
+}
+
+func (e Endpoint) manageOptions() mlmanage.ClientOptions {
+	return mlmanage.ClientOptions{
+		Host:               e.Host,
+		Username:           e.Username,
+		Password:           e.Password,
+		UseTLS:             e.UseTLS,
+		InsecureSkipVerify: e.UseTLS,
+	}
+}
+
+// WaitForMarkLogicReady waits until the Manage API answers and reports at least
+// expectedHosts hosts, all of them online. Unlike pod readiness this confirms
+// the hosts joined the cluster.
+func WaitForMarkLogicReady(ctx context.Context, t TestingT, endpoint Endpoint, expectedHosts int, timeout time.Duration) error {
+	t.Helper()
+	mgmtClient := mlmanage.NewClient(endpoint.manageOptions())
+	return poll(ctx, timeout, func() (bool, error) {
+		hosts, err := mgmtClient.ListHostsStatus(ctx)
+		if err != nil {
+			t.Logf("Manage API not ready: %v", err)
+			return false, nil
+		}
+		online := 0
+		for _, host := range hosts {
+			if host.Online {
+				online++
+			}
+		}
+		if online < expectedHosts {
+			t.Logf("MarkLogic reports %d/%d hosts online", online, expectedHosts)
+			return false, nil
+		}
+		return true, nil
+	})
+}
+
+// EvalXQuery runs an XQuery expression and returns the serialized result.
+func EvalXQuery(ctx context.Context, endpoint Endpoint, query string) (string, error) {
+	return mlmanage.Eval(ctx, endpoint.queryOptions(), "xquery", query)
+}
+
+// EvalJavaScript runs a server-side JavaScript expression and returns the serialized result.
+func EvalJavaScript(ctx context.Context, endpoint Endpoint, script string) (string, error) {
+	return mlmanage.Eval(ctx, endpoint.queryOptions(), "javascript", script)
+}
+
+func (e Endpoint) queryOptions() mlmanage.ClientOptions {
+	opts := e.manageOptions()
+	opts.Host = e.QueryHost
+	if opts.Host == "" {
+		opts.Host = hostWithoutPort(e.Host)
+	}
+	return opts
+}
+
+func hostWithoutPort(host string) string {
+	if name, _, err := net.SplitHostPort(host); err == nil {
+		return name
+	}
+	return host
+}
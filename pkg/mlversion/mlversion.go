@@ -0,0 +1,273 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+// Package mlversion describes how the MarkLogic major versions differ, from a
+// table embedded in the operator, so the reconcilers and prechecks adapt to
+// the version a cluster runs instead of assuming the behavior of one release.
+// Add a major version to versions.yaml when the operator starts supporting it.
+package mlversion
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Feature is a capability that only some MarkLogic versions have.
+type Feature string
+
+const (
+	// FIPS is FIPS 140 mode on UBI based images.
+	FIPS Feature = "fips"
+	// PathBasedRouting is serving the app servers of a host below paths of
+	// a single port through HAProxy.
+	PathBasedRouting Feature = "pathBasedRouting"
+	// DynamicHosts is hosts joining and leaving a cluster without changing
+	// its configuration.
+	DynamicHosts Feature = "dynamicHosts"
+)
+
+// ErrUnknownVersion is returned for major versions missing from the table.
+var ErrUnknownVersion = errors.New("unknown MarkLogic version")
+
+// Version is a MarkLogic release, down to the minor version.
+type Version struct {
+	Major int
+	Minor int
+}
+
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d", v.Major, v.Minor)
+}
+
+// Ports are the ports of the built-in app servers and the health check.
+type Ports struct {
+	AppServices int32 `json:"appServices"`
+	Admin       int32 `json:"admin"`
+	Manage      int32 `json:"manage"`
+	HealthCheck int32 `json:"healthCheck"`
+}
+
+// Capabilities describe a MarkLogic major version.
+type Capabilities struct {
+	Major int   `json:"major"`
+	Ports Ports `json:"ports"`
+	// ManageAPI is the version of the Manage REST API.
+	ManageAPI string `json:"manageAPI"`
+	// UpgradeFrom are the major versions that upgrade to this one directly.
+	UpgradeFrom []int `json:"upgradeFrom"`
+	// Features maps the supported features to the first minor release that
+	// has them.
+	Features map[Feature]int `json:"features"`
+}
+
+//go:embed versions.yaml
+var versionsYAML []byte
+
+var table = mustLoad(versionsYAML)
+
+func mustLoad(data []byte) []Capabilities {
+	versions, err := load(data)
+	if err != nil {
+		panic(fmt.Sprintf("invalid MarkLogic version table: %v", err))
+	}
+	return versions
+}
+
+func load(data []byte) ([]Capabilities, error) {
+	versions := []Capabilities{}
+	if err := yaml.UnmarshalStrict(data, &versions); err != nil {
+		return nil, err
+	}
+	if len(versions) == 0 {
+		return nil, errors.New("no versions")
+	}
+	for i, caps := range versions {
+		if i > 0 && caps.Major <= versions[i-1].Major {
+			return nil, fmt.Errorf("major version %d is not in ascending order", caps.Major)
+		}
+		if caps.Ports.AppServices == 0 || caps.Ports.Admin == 0 || caps.Ports.Manage == 0 || caps.Ports.HealthCheck == 0 {
+			return nil, fmt.Errorf("major version %d misses a port", caps.Major)
+		}
+		if caps.ManageAPI == "" {
+			return nil, fmt.Errorf("major version %d misses the Manage API version", caps.Major)
+		}
+	}
+	return versions, nil
+}
+
+// Known returns the major versions in the table, oldest first.
+func Known() []int {
+	majors := make([]int, 0, len(table))
+	for _, caps := range table {
+		majors = append(majors, caps.Major)
+	}
+	return majors
+}
+
+// Lookup returns the capabilities of a major version and whether it is in the
+// table. Versions newer than the table are taken to behave like the newest
+// version in it, older versions like the oldest.
+func Lookup(major int) (Capabilities, bool) {
+	for _, caps := range table {
+		if caps.Major == major {
+			return caps, true
+		}
+	}
+	if major > table[len(table)-1].Major {
+		return table[len(table)-1], false
+	}
+	return table[0], false
+}
+
+// Supports reports whether a MarkLogic version supports a feature.
+func Supports(v Version, feature Feature) bool {
+	caps, _ := Lookup(v.Major)
+	since, ok := caps.Features[feature]
+	if !ok {
+		return false
+	}
+	// A major version newer than the table has every minor release.
+	return v.Major > caps.Major || v.Minor >= since
+}
+
+// MinimumVersion returns the first version in the table that supports a
+// feature.
+func MinimumVersion(feature Feature) (Version, bool) {
+	for _, caps := range table {
+		if since, ok := caps.Features[feature]; ok {
+			return Version{Major: caps.Major, Minor: since}, true
+		}
+	}
+	return Version{}, false
+}
+
+// CheckUpgrade returns an error when a cluster on from cannot be upgraded to
+// to directly. Targets missing from the table return an error wrapping
+// ErrUnknownVersion.
+func CheckUpgrade(from, to Version) error {
+	if to.Major < from.Major {
+		return fmt.Errorf("MarkLogic %d cannot be downgraded to %d", from.Major, to.Major)
+	}
+	caps, known := Lookup(to.Major)
+	if !known {
+		return fmt.Errorf("%w %d, known versions are %s", ErrUnknownVersion, to.Major, joinMajors(Known()))
+	}
+	if slices.Contains(caps.UpgradeFrom, from.Major) {
+		return nil
+	}
+	via := []int{}
+	for _, major := range caps.UpgradeFrom {
+		if intermediate, ok := Lookup(major); ok && major != to.Major && slices.Contains(intermediate.UpgradeFrom, from.Major) {
+			via = append(via, major)
+		}
+	}
+	if len(via) == 0 {
+		return fmt.Errorf("MarkLogic %d cannot be upgraded to %d, it upgrades from %s", from.Major, to.Major, joinMajors(caps.UpgradeFrom))
+	}
+	return fmt.Errorf("MarkLogic %d cannot be upgraded to %d directly, upgrade to %s first", from.Major, to.Major, joinMajors(via))
+}
+
+func joinMajors(majors []int) string {
+	names := make([]string, 0, len(majors))
+	for _, major := range majors {
+		names = append(names, strconv.Itoa(major))
+	}
+	return strings.Join(names, ", ")
+}
+
+// ParseImage returns the MarkLogic version of an image from its tag, such as
+// 11.3.1-ubi-rootless. Tags like latest have no version.
+func ParseImage(image string) (Version, bool) {
+	tag, ok := imageTag(image)
+	if !ok {
+		return Version{}, false
+	}
+	return parse(tag)
+}
+
+// MatchesImage reports whether a version reported by the Manage API, such as
+// 11.3.1 or 10.0-9.5, is the release in the tag of an image. The second
+// result is false for tags without a version, which match any version.
+func MatchesImage(version, image string) (bool, bool) {
+	tag, ok := imageTag(image)
+	if !ok {
+		return true, false
+	}
+	return tag == version || strings.HasPrefix(tag, version+"-"), true
+}
+
+// imageTag returns the tag of an image when it starts with a version.
+func imageTag(image string) (string, bool) {
+	tag := image
+	if at := strings.Index(tag, "@"); at >= 0 {
+		tag = tag[:at]
+	}
+	colon := strings.LastIndex(tag, ":")
+	if colon < 0 || strings.Contains(tag[colon:], "/") {
+		return "", false
+	}
+	tag = tag[colon+1:]
+	if !strings.Contains(tag, ".") {
+		return "", false
+	}
+	if _, ok := parse(tag); !ok {
+		return "", false
+	}
+	return tag, true
+}
+
+// Compare orders two versions reported by the Manage API, comparing their
+// numbers one by one, so that 11.3.10 is newer than 11.3.9 and 10.0-9.5 is
+// newer than 10.0-9.
+func Compare(a, b string) int {
+	numbersA, numbersB := numbers(a), numbers(b)
+	for i := 0; i < len(numbersA) && i < len(numbersB); i++ {
+		if numbersA[i] != numbersB[i] {
+			if numbersA[i] < numbersB[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case len(numbersA) < len(numbersB):
+		return -1
+	case len(numbersA) > len(numbersB):
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+func numbers(version string) []int {
+	result := []int{}
+	for _, field := range strings.FieldsFunc(version, func(r rune) bool { return r < '0' || r > '9' }) {
+		n, _ := strconv.Atoi(field)
+		result = append(result, n)
+	}
+	return result
+}
+
+// ParseHost returns the version of a MarkLogic version string reported by
+// the Manage API, such as 12.0-1 or 11.3.1.
+func ParseHost(version string) (Version, bool) {
+	return parse(version)
+}
+
+func parse(version string) (Version, bool) {
+	majorPart, rest, _ := strings.Cut(version, ".")
+	major, err := strconv.Atoi(majorPart)
+	if err != nil {
+		return Version{}, false
+	}
+	digits := strings.IndexFunc(rest, func(r rune) bool { return r < '0' || r > '9' })
+	if digits < 0 {
+		digits = len(rest)
+	}
+	minor, _ := strconv.Atoi(rest[:digits])
+	return Version{Major: major, Minor: minor}, true
+}
@@ -0,0 +1,130 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package mlversion
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTableDescribesSupportedVersions(t *testing.T) {
+	if got := joinMajors(Known()); got != "10, 11, 12" {
+		t.Fatalf("unexpected major versions %s", got)
+	}
+	caps, known := Lookup(12)
+	if !known || caps.Ports.Manage != 8002 || caps.ManageAPI != "v2" {
+		t.Fatalf("unexpected capabilities of MarkLogic 12: %+v", caps)
+	}
+	if caps, known := Lookup(13); known || caps.Major != 12 {
+		t.Fatalf("expected newer versions to behave like the newest known one, got %+v", caps)
+	}
+	if _, err := load([]byte("- major: 11\n  manageAPI: v2\n")); err == nil {
+		t.Fatalf("expected a version without ports to be rejected")
+	}
+	if _, err := load([]byte("- major: 11\n  unknown: true\n")); err == nil {
+		t.Fatalf("expected unknown fields to be rejected")
+	}
+}
+
+func TestSupports(t *testing.T) {
+	cases := []struct {
+		version Version
+		feature Feature
+		want    bool
+	}{
+		{Version{10, 0}, FIPS, false},
+		{Version{11, 0}, FIPS, true},
+		{Version{11, 0}, PathBasedRouting, false},
+		{Version{11, 1}, PathBasedRouting, true},
+		{Version{11, 3}, DynamicHosts, false},
+		{Version{12, 0}, DynamicHosts, true},
+		{Version{13, 0}, DynamicHosts, true},
+	}
+	for _, c := range cases {
+		if got := Supports(c.version, c.feature); got != c.want {
+			t.Errorf("Supports(%s, %s) = %t, want %t", c.version, c.feature, got, c.want)
+		}
+	}
+	if minimum, ok := MinimumVersion(FIPS); !ok || minimum != (Version{11, 0}) {
+		t.Fatalf("unexpected minimum version for FIPS: %s", minimum)
+	}
+}
+
+func TestCheckUpgrade(t *testing.T) {
+	for _, c := range []struct {
+		from, to Version
+		want     string
+	}{
+		{Version{11, 3}, Version{12, 0}, ""},
+		{Version{11, 2}, Version{11, 3}, ""},
+		{Version{9, 0}, Version{12, 0}, "MarkLogic 9 cannot be upgraded to 12 directly, upgrade to 10, 11 first"},
+		{Version{8, 0}, Version{12, 0}, "MarkLogic 8 cannot be upgraded to 12 directly, upgrade to 10 first"},
+		{Version{7, 0}, Version{12, 0}, "MarkLogic 7 cannot be upgraded to 12, it upgrades from 10, 11, 12"},
+		{Version{12, 0}, Version{11, 3}, "MarkLogic 12 cannot be downgraded to 11"},
+	} {
+		err := CheckUpgrade(c.from, c.to)
+		if (c.want == "" && err != nil) || (c.want != "" && (err == nil || err.Error() != c.want)) {
+			t.Errorf("CheckUpgrade(%s, %s) = %v, want %q", c.from, c.to, err, c.want)
+		}
+	}
+	if err := CheckUpgrade(Version{12, 0}, Version{13, 0}); !errors.Is(err, ErrUnknownVersion) {
+		t.Fatalf("expected an unknown target version, got %v", err)
+	}
+}
+
+func TestParse(t *testing.T) {
+	images := map[string]Version{
+		"progressofficial/marklogic-db:11.3.1-ubi-rootless":              {11, 3},
+		"registry.local:5000/marklogic-db:12.0.3-ubi9-rootless-2.2.6":    {12, 0},
+		"progressofficial/marklogic-db:10.0-9.5@sha256:0123456789abcdef": {10, 0},
+	}
+	for image, want := range images {
+		if got, ok := ParseImage(image); !ok || got != want {
+			t.Errorf("ParseImage(%q) = %s, %t; want %s", image, got, ok, want)
+		}
+	}
+	for _, image := range []string{"progressofficial/marklogic-db:latest", "registry.local:5000/marklogic-db"} {
+		if _, ok := ParseImage(image); ok {
+			t.Errorf("expected no version for %q", image)
+		}
+	}
+	for _, c := range []struct {
+		version, image string
+		matches, known bool
+	}{
+		{"11.3.1", "progressofficial/marklogic-db:11.3.1-ubi-rootless", true, true},
+		{"12.0.3", "registry.local:5000/marklogic-db:12.0.3", true, true},
+		{"10.0-9.5", "progressofficial/marklogic-db:10.0-9.5-centos-1.1.2", true, true},
+		{"11.3.0", "progressofficial/marklogic-db:11.3.1-ubi-rootless", false, true},
+		{"11.3.1", "progressofficial/marklogic-db:11.3.10-ubi-rootless", false, true},
+		{"11.3.1", "progressofficial/marklogic-db:latest", true, false},
+	} {
+		if matches, known := MatchesImage(c.version, c.image); matches != c.matches || known != c.known {
+			t.Errorf("MatchesImage(%q, %q) = %t, %t; want %t, %t", c.version, c.image, matches, known, c.matches, c.known)
+		}
+	}
+	if got, ok := ParseHost("12.0-1"); !ok || got != (Version{12, 0}) {
+		t.Errorf("unexpected host version %s", got)
+	}
+	if _, ok := ParseHost(""); ok {
+		t.Errorf("expected no version for an empty string")
+	}
+}
+
+func TestCompare(t *testing.T) {
+	ordered := []string{"10.0-9", "10.0-9.5", "11.3.1", "11.3.9", "11.3.10", "12.0-1", "12.0.1"}
+	for i := range ordered {
+		for j := range ordered {
+			got, want := Compare(ordered[i], ordered[j]), 0
+			switch {
+			case i < j:
+				want = -1
+			case i > j:
+				want = 1
+			}
+			if got != want {
+				t.Errorf("Compare(%q, %q) = %d, want %d", ordered[i], ordered[j], got, want)
+			}
+		}
+	}
+}
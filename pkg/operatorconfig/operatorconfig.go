@@ -0,0 +1,269 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+// Package operatorconfig loads the operator-wide defaults from the file passed
+// with the --config flag, usually mounted from a ConfigMap. The defaults apply
+// to every MarklogicCluster that does not set the corresponding field, so
+// fleet-wide policies do not have to be repeated in each cluster.
+package operatorconfig
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// EventVerbosityAll records every event.
+	EventVerbosityAll = "All"
+	// EventVerbosityWarnings only records Warning events.
+	EventVerbosityWarnings = "Warnings"
+
+	// DefaultLogCollectionImage is the fluent-bit image used when neither the
+	// cluster nor the operator configuration set one.
+	DefaultLogCollectionImage = "fluent/fluent-bit:4.1.1"
+	// DefaultFluentdImage and DefaultVectorImage are the images of the fluentd
+	// and vector log collection engines.
+	DefaultFluentdImage = "fluent/fluentd:v1.18-1"
+	DefaultVectorImage  = "timberio/vector:0.49.0-debian"
+
+	// DefaultTelemetryInterval is how often usage is reported when telemetry
+	// is enabled without an interval.
+	DefaultTelemetryInterval = 24 * time.Hour
+	// DefaultStatusCacheTTL is how long the host and forest status of a
+	// cluster is reused unless the configuration sets another time.
+	DefaultStatusCacheTTL = 5 * time.Second
+	// minTelemetryInterval keeps a misconfigured operator from flooding the
+	// telemetry endpoint.
+	minTelemetryInterval = time.Hour
+)
+
+// ProbeDefaults are the timings of a MarkLogic container probe. They apply to
+// the probes of a cluster that leave them unset.
+type ProbeDefaults struct {
+	InitialDelaySeconds int32 `json:"initialDelaySeconds,omitempty"`
+	TimeoutSeconds      int32 `json:"timeoutSeconds,omitempty"`
+	PeriodSeconds       int32 `json:"periodSeconds,omitempty"`
+	SuccessThreshold    int32 `json:"successThreshold,omitempty"`
+	FailureThreshold    int32 `json:"failureThreshold,omitempty"`
+}
+
+// RequeueIntervals bound the delays the controllers wait before checking on
+// a cluster again, for example while pods restart or a backup runs.
+type RequeueIntervals struct {
+	// Minimum raises shorter delays, to reduce the API load of large fleets.
+	Minimum metav1.Duration `json:"minimum,omitempty"`
+	// Maximum lowers longer delays. Zero leaves them unchanged.
+	Maximum metav1.Duration `json:"maximum,omitempty"`
+}
+
+// Telemetry configures the anonymous usage report of the operator. It is
+// disabled unless Enabled is set, so air-gapped installations never connect
+// to the endpoint.
+type Telemetry struct {
+	Enabled bool `json:"enabled,omitempty"`
+	// Endpoint is the http or https URL the report is posted to.
+	Endpoint string `json:"endpoint,omitempty"`
+	// Interval between two reports, at least an hour. Defaults to 24h.
+	Interval metav1.Duration `json:"interval,omitempty"`
+}
+
+// ManageAPI configures the calls of the operator to the Manage API.
+type ManageAPI struct {
+	// StatusCacheTTL is how long the host and forest status of a cluster is
+	// reused by its reconciles. Defaults to 5s; 0s turns the cache off.
+	StatusCacheTTL *metav1.Duration `json:"statusCacheTTL,omitempty"`
+}
+
+// Config is the operator configuration file.
+type Config struct {
+	// LogCollectionImage is the fluent-bit image of clusters with log
+	// collection enabled and no image of their own.
+	LogCollectionImage string `json:"logCollectionImage,omitempty"`
+	// FluentdImage and VectorImage are the images of clusters whose log
+	// collection uses the fluentd or vector engine and no image of their own.
+	FluentdImage string `json:"fluentdImage,omitempty"`
+	VectorImage  string `json:"vectorImage,omitempty"`
+	// LivenessProbe and ReadinessProbe are the default probe timings.
+	LivenessProbe  ProbeDefaults `json:"livenessProbe,omitempty"`
+	ReadinessProbe ProbeDefaults `json:"readinessProbe,omitempty"`
+	// RequeueIntervals bound the requeue delays of the controllers.
+	RequeueIntervals RequeueIntervals `json:"requeueIntervals,omitempty"`
+	// DefaultStorageClass is the storage class of the data volumes of new
+	// StatefulSets whose persistence does not name one. Empty uses the
+	// default storage class of the Kubernetes cluster.
+	DefaultStorageClass string `json:"defaultStorageClass,omitempty"`
+	// EventVerbosity is All (default) or Warnings, which drops Normal events.
+	EventVerbosity string `json:"eventVerbosity,omitempty"`
+	// Telemetry is the opt-in usage report of the operator.
+	Telemetry Telemetry `json:"telemetry,omitempty"`
+	// ManageAPI configures the calls to the Manage API.
+	ManageAPI ManageAPI `json:"manageAPI,omitempty"`
+}
+
+// Default returns the configuration used without a configuration file.
+func Default() Config {
+	return Config{
+		LogCollectionImage: DefaultLogCollectionImage,
+		FluentdImage:       DefaultFluentdImage,
+		VectorImage:        DefaultVectorImage,
+		LivenessProbe:      ProbeDefaults{InitialDelaySeconds: 30, TimeoutSeconds: 5, PeriodSeconds: 30, SuccessThreshold: 1, FailureThreshold: 3},
+		ReadinessProbe:     ProbeDefaults{InitialDelaySeconds: 10, TimeoutSeconds: 5, PeriodSeconds: 30, SuccessThreshold: 1, FailureThreshold: 3},
+		EventVerbosity:     EventVerbosityAll,
+		ManageAPI:          ManageAPI{StatusCacheTTL: &metav1.Duration{Duration: DefaultStatusCacheTTL}},
+	}
+}
+
+// Load reads the configuration file at path. Fields missing from the file
+// keep their Default value.
+func Load(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, err
+	}
+	return Parse(data)
+}
+
+// Parse parses a YAML or JSON configuration.
+func Parse(data []byte) (Config, error) {
+	var file Config
+	if err := yaml.UnmarshalStrict(data, &file); err != nil {
+		return Config{}, fmt.Errorf("invalid operator configuration: %w", err)
+	}
+	cfg := Default()
+	if file.LogCollectionImage != "" {
+		cfg.LogCollectionImage = file.LogCollectionImage
+	}
+	if file.FluentdImage != "" {
+		cfg.FluentdImage = file.FluentdImage
+	}
+	if file.VectorImage != "" {
+		cfg.VectorImage = file.VectorImage
+	}
+	mergeProbe(&cfg.LivenessProbe, file.LivenessProbe)
+	mergeProbe(&cfg.ReadinessProbe, file.ReadinessProbe)
+	cfg.RequeueIntervals = file.RequeueIntervals
+	cfg.DefaultStorageClass = file.DefaultStorageClass
+	if file.EventVerbosity != "" {
+		cfg.EventVerbosity = file.EventVerbosity
+	}
+	cfg.Telemetry = file.Telemetry
+	if file.ManageAPI.StatusCacheTTL != nil {
+		cfg.ManageAPI.StatusCacheTTL = file.ManageAPI.StatusCacheTTL
+	}
+	if cfg.Telemetry.Enabled && cfg.Telemetry.Interval.Duration == 0 {
+		cfg.Telemetry.Interval.Duration = DefaultTelemetryInterval
+	}
+	if err := cfg.validate(); err != nil {
+		return Config{}, fmt.Errorf("invalid operator configuration: %w", err)
+	}
+	return cfg, nil
+}
+
+func mergeProbe(probe *ProbeDefaults, file ProbeDefaults) {
+	if file.InitialDelaySeconds > 0 {
+		probe.InitialDelaySeconds = file.InitialDelaySeconds
+	}
+	if file.TimeoutSeconds > 0 {
+		probe.TimeoutSeconds = file.TimeoutSeconds
+	}
+	if file.PeriodSeconds > 0 {
+		probe.PeriodSeconds = file.PeriodSeconds
+	}
+	if file.SuccessThreshold > 0 {
+		probe.SuccessThreshold = file.SuccessThreshold
+	}
+	if file.FailureThreshold > 0 {
+		probe.FailureThreshold = file.FailureThreshold
+	}
+}
+
+func (c Config) validate() error {
+	if c.EventVerbosity != EventVerbosityAll && c.EventVerbosity != EventVerbosityWarnings {
+		return fmt.Errorf("eventVerbosity must be %s or %s, got %q", EventVerbosityAll, EventVerbosityWarnings, c.EventVerbosity)
+	}
+	minimum, maximum := c.RequeueIntervals.Minimum.Duration, c.RequeueIntervals.Maximum.Duration
+	if minimum < 0 || maximum < 0 {
+		return fmt.Errorf("requeueIntervals must not be negative")
+	}
+	if maximum > 0 && minimum > maximum {
+		return fmt.Errorf("requeueIntervals.minimum %s is larger than maximum %s", minimum, maximum)
+	}
+	if ttl := c.ManageAPI.StatusCacheTTL; ttl != nil && ttl.Duration < 0 {
+		return fmt.Errorf("manageAPI.statusCacheTTL must not be negative")
+	}
+	return c.Telemetry.validate()
+}
+
+func (t Telemetry) validate() error {
+	if !t.Enabled {
+		return nil
+	}
+	endpoint, err := url.Parse(t.Endpoint)
+	if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+		return fmt.Errorf("telemetry.endpoint must be an http or https URL, got %q", t.Endpoint)
+	}
+	if t.Interval.Duration < minTelemetryInterval {
+		return fmt.Errorf("telemetry.interval must be at least %s, got %s", minTelemetryInterval, t.Interval.Duration)
+	}
+	return nil
+}
+
+// RequeueDuration converts a requeue delay in seconds to a duration within
+// the configured bounds. It replaces result.DurationFunc.
+func (c Config) RequeueDuration(secs int) time.Duration {
+	d := time.Duration(secs) * time.Second
+	if minimum := c.RequeueIntervals.Minimum.Duration; minimum > 0 && d < minimum {
+		d = minimum
+	}
+	if maximum := c.RequeueIntervals.Maximum.Duration; maximum > 0 && d > maximum {
+		d = maximum
+	}
+	return d
+}
+
+// StatusCacheTTL returns how long the host and forest status of a cluster is
+// reused, 0 when the cache is off.
+func (c Config) StatusCacheTTL() time.Duration {
+	if c.ManageAPI.StatusCacheTTL == nil {
+		return 0
+	}
+	return c.ManageAPI.StatusCacheTTL.Duration
+}
+
+// EventRecorder applies the event verbosity to recorder.
+func (c Config) EventRecorder(recorder record.EventRecorder) record.EventRecorder {
+	if c.EventVerbosity != EventVerbosityWarnings {
+		return recorder
+	}
+	return warningRecorder{recorder: recorder}
+}
+
+// warningRecorder drops Normal events.
+type warningRecorder struct {
+	recorder record.EventRecorder
+}
+
+func (w warningRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	if eventtype == corev1.EventTypeWarning {
+		w.recorder.Event(object, eventtype, reason, message)
+	}
+}
+
+func (w warningRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	if eventtype == corev1.EventTypeWarning {
+		w.recorder.Eventf(object, eventtype, reason, messageFmt, args...)
+	}
+}
+
+func (w warningRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	if eventtype == corev1.EventTypeWarning {
+		w.recorder.AnnotatedEventf(object, annotations, eventtype, reason, messageFmt, args...)
+	}
+}
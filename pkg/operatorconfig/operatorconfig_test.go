@@ -0,0 +1,94 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package operatorconfig
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestParseKeepsDefaultsForMissingFields(t *testing.T) {
+	cfg, err := Parse([]byte(`
+logCollectionImage: registry.example.com/fluent-bit:4.1.1
+livenessProbe:
+  initialDelaySeconds: 60
+requeueIntervals:
+  minimum: 5s
+  maximum: 2m
+defaultStorageClass: gp3
+eventVerbosity: Warnings
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.LogCollectionImage != "registry.example.com/fluent-bit:4.1.1" || cfg.DefaultStorageClass != "gp3" {
+		t.Fatalf("unexpected config %+v", cfg)
+	}
+	if cfg.LivenessProbe.InitialDelaySeconds != 60 || cfg.LivenessProbe.PeriodSeconds != 30 || cfg.ReadinessProbe != Default().ReadinessProbe {
+		t.Fatalf("expected probe timings to be merged with the defaults, got %+v %+v", cfg.LivenessProbe, cfg.ReadinessProbe)
+	}
+	if got := cfg.RequeueDuration(1); got != 5*time.Second {
+		t.Fatalf("expected the minimum requeue interval, got %s", got)
+	}
+	if got := cfg.RequeueDuration(600); got != 2*time.Minute {
+		t.Fatalf("expected the maximum requeue interval, got %s", got)
+	}
+	if got := cfg.RequeueDuration(30); got != 30*time.Second {
+		t.Fatalf("expected an unchanged requeue interval, got %s", got)
+	}
+	if got := cfg.StatusCacheTTL(); got != DefaultStatusCacheTTL {
+		t.Fatalf("expected the default status cache TTL, got %s", got)
+	}
+	cfg, err = Parse([]byte("manageAPI: {statusCacheTTL: 0s}"))
+	if err != nil || cfg.StatusCacheTTL() != 0 {
+		t.Fatalf("expected the status cache to be turned off, got %s, %v", cfg.StatusCacheTTL(), err)
+	}
+}
+
+func TestParseRejectsInvalidConfig(t *testing.T) {
+	for _, data := range []string{
+		"eventVerbosity: Debug",
+		"defaultStorageClas: gp3",
+		"requeueIntervals: {minimum: 5m, maximum: 1m}",
+		"manageAPI: {statusCacheTTL: -1s}",
+		"telemetry: {enabled: true}",
+		"telemetry: {enabled: true, endpoint: telemetry.example.com}",
+		"telemetry: {enabled: true, endpoint: https://telemetry.example.com, interval: 5m}",
+	} {
+		if _, err := Parse([]byte(data)); err == nil {
+			t.Errorf("expected %q to be rejected", data)
+		}
+	}
+}
+
+func TestParseTelemetryIsDisabledByDefault(t *testing.T) {
+	cfg, err := Parse([]byte("eventVerbosity: All"))
+	if err != nil || cfg.Telemetry.Enabled {
+		t.Fatalf("expected telemetry to be disabled by default, got %+v, %v", cfg.Telemetry, err)
+	}
+	cfg, err = Parse([]byte("telemetry: {enabled: true, endpoint: https://telemetry.example.com/v1/reports}"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.Telemetry.Enabled || cfg.Telemetry.Interval.Duration != DefaultTelemetryInterval {
+		t.Fatalf("expected the default telemetry interval, got %+v", cfg.Telemetry)
+	}
+}
+
+func TestEventRecorderDropsNormalEvents(t *testing.T) {
+	fake := record.NewFakeRecorder(2)
+	cfg := Default()
+	cfg.EventVerbosity = EventVerbosityWarnings
+	recorder := cfg.EventRecorder(fake)
+	recorder.Event(&corev1.Pod{}, corev1.EventTypeNormal, "Started", "started")
+	recorder.Event(&corev1.Pod{}, corev1.EventTypeWarning, "Failed", "failed")
+	if len(fake.Events) != 1 {
+		t.Fatalf("expected only the warning to be recorded, got %d events", len(fake.Events))
+	}
+	if event := <-fake.Events; event != "Warning Failed failed" {
+		t.Fatalf("unexpected event %q", event)
+	}
+}
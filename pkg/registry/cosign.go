@@ -0,0 +1,346 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package registry
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	cosignSignatureAnnotation   = "dev.cosignproject.cosign/signature"
+	cosignCertificateAnnotation = "dev.sigstore.cosign/certificate"
+	cosignChainAnnotation       = "dev.sigstore.cosign/chain"
+	cosignBundleAnnotation      = "dev.sigstore.cosign/bundle"
+)
+
+var (
+	// Fulcio certificate extensions holding the OIDC issuer of the signer.
+	oidFulcioIssuer   = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 1}
+	oidFulcioIssuerV2 = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 8}
+)
+
+// Signature is a cosign signature attached to an image.
+type Signature struct {
+	// Payload is the signed simple signing document.
+	Payload   []byte
+	Signature []byte
+	// Certificate and Chain are PEM encoded and set for keyless signatures.
+	Certificate []byte
+	Chain       []byte
+	// Bundle is the transparency log entry of a keyless signature.
+	Bundle []byte
+}
+
+// CosignPolicy describes the signatures an image must carry. Either
+// PublicKey, or Identity with Roots and RekorPublicKey, must be set.
+type CosignPolicy struct {
+	// PublicKey is the PEM encoded key of key-based signatures.
+	PublicKey []byte
+	// Identity is the email or URI subject of a keyless signing certificate.
+	Identity string
+	// Issuer is the OIDC issuer that authenticated Identity, if it must match.
+	Issuer string
+	// Roots are the PEM encoded Fulcio root and intermediate certificates.
+	Roots []byte
+	// RekorPublicKey is the PEM encoded transparency log key.
+	RekorPublicKey []byte
+}
+
+// ImageSignatures returns the digest of the image and the cosign signatures
+// stored for it under the sha256-<digest>.sig tag. An image without
+// signatures returns no signatures and no error.
+func ImageSignatures(ctx context.Context, image string, opts Options) (string, []Signature, error) {
+	c, err := newRegistryClient(image, opts)
+	if err != nil {
+		return "", nil, err
+	}
+	digest := c.ref.Reference
+	if !strings.HasPrefix(digest, "sha256:") {
+		resp, err := c.do(ctx, http.MethodHead, fmt.Sprintf("/v2/%s/manifests/%s", c.ref.Repository, c.ref.Reference), manifestAccept)
+		if err != nil {
+			return "", nil, err
+		}
+		digest = resp.Header.Get("Docker-Content-Digest")
+		_ = resp.Body.Close()
+		if !strings.HasPrefix(digest, "sha256:") {
+			return "", nil, fmt.Errorf("registry %s returned no sha256 digest for %s", c.ref.Registry, image)
+		}
+	}
+
+	var manifest struct {
+		Layers []struct {
+			Digest      string            `json:"digest"`
+			Annotations map[string]string `json:"annotations"`
+		} `json:"layers"`
+	}
+	signatureTag := strings.Replace(digest, ":", "-", 1) + ".sig"
+	err = c.getJSON(ctx, fmt.Sprintf("/v2/%s/manifests/%s", c.ref.Repository, signatureTag), manifestAccept, &manifest)
+	if err != nil {
+		if IsNotFound(err) {
+			return digest, nil, nil
+		}
+		return "", nil, err
+	}
+	signatures := []Signature{}
+	for _, layer := range manifest.Layers {
+		encoded, ok := layer.Annotations[cosignSignatureAnnotation]
+		if !ok {
+			continue
+		}
+		sig, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid signature in layer %s: %w", layer.Digest, err)
+		}
+		payload, err := c.getBlob(ctx, layer.Digest)
+		if err != nil {
+			return "", nil, err
+		}
+		signatures = append(signatures, Signature{
+			Payload:     payload,
+			Signature:   sig,
+			Certificate: []byte(layer.Annotations[cosignCertificateAnnotation]),
+			Chain:       []byte(layer.Annotations[cosignChainAnnotation]),
+			Bundle:      []byte(layer.Annotations[cosignBundleAnnotation]),
+		})
+	}
+	return digest, signatures, nil
+}
+
+func (c *registryClient) getBlob(ctx context.Context, digest string) (blob []byte, err error) {
+	resp, err := c.do(ctx, http.MethodGet, fmt.Sprintf("/v2/%s/blobs/%s", c.ref.Repository, digest), "")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = errors.Join(err, resp.Body.Close())
+	}()
+	blob, err = io.ReadAll(io.LimitReader(resp.Body, maxManifestResponseBytes))
+	if err != nil {
+		return nil, err
+	}
+	if sum := sha256.Sum256(blob); "sha256:"+hex.EncodeToString(sum[:]) != digest {
+		return nil, fmt.Errorf("blob %s does not match its digest", digest)
+	}
+	return blob, nil
+}
+
+// VerifyCosignSignatures returns nil when one of the signatures signs digest
+// and satisfies the policy, and otherwise the reason the last one failed.
+func VerifyCosignSignatures(digest string, signatures []Signature, policy CosignPolicy) error {
+	if len(signatures) == 0 {
+		return errors.New("image has no cosign signatures")
+	}
+	var err error
+	for _, sig := range signatures {
+		if err = verifyCosignSignature(digest, sig, policy); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+func verifyCosignSignature(digest string, sig Signature, policy CosignPolicy) error {
+	var payload struct {
+		Critical struct {
+			Image struct {
+				Digest string `json:"docker-manifest-digest"`
+			} `json:"image"`
+		} `json:"critical"`
+	}
+	if err := json.Unmarshal(sig.Payload, &payload); err != nil {
+		return fmt.Errorf("invalid signature payload: %w", err)
+	}
+	if payload.Critical.Image.Digest != digest {
+		return fmt.Errorf("signature is for %s, not %s", payload.Critical.Image.Digest, digest)
+	}
+	if len(policy.PublicKey) > 0 {
+		key, err := parsePublicKey(policy.PublicKey)
+		if err != nil {
+			return err
+		}
+		return verifyPayload(key, sig.Payload, sig.Signature)
+	}
+	if policy.Identity == "" {
+		return errors.New("a public key or a keyless identity is required")
+	}
+	return verifyKeyless(sig, policy)
+}
+
+// verifyKeyless checks a signature made with a short-lived Fulcio
+// certificate: the certificate must chain to the roots at the time the
+// transparency log recorded the signature, and name the expected signer.
+func verifyKeyless(sig Signature, policy CosignPolicy) error {
+	certBlock, _ := pem.Decode(sig.Certificate)
+	if certBlock == nil {
+		return errors.New("signature has no signing certificate")
+	}
+	cert, err := x509.ParseCertificate(certBlock.Bytes)
+	if err != nil {
+		return fmt.Errorf("invalid signing certificate: %w", err)
+	}
+	if err := verifyPayload(cert.PublicKey, sig.Payload, sig.Signature); err != nil {
+		return err
+	}
+	integratedTime, err := verifyRekorBundle(sig, policy.RekorPublicKey)
+	if err != nil {
+		return err
+	}
+
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(policy.Roots) {
+		return errors.New("no Fulcio root certificates configured")
+	}
+	intermediates := x509.NewCertPool()
+	intermediates.AppendCertsFromPEM(sig.Chain)
+	if _, err := cert.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   integratedTime,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	}); err != nil {
+		return fmt.Errorf("signing certificate is not trusted: %w", err)
+	}
+
+	identities := append([]string{}, cert.EmailAddresses...)
+	for _, uri := range cert.URIs {
+		identities = append(identities, uri.String())
+	}
+	matched := false
+	for _, identity := range identities {
+		matched = matched || identity == policy.Identity
+	}
+	if !matched {
+		return fmt.Errorf("certificate identity %s does not match %s", strings.Join(identities, ", "), policy.Identity)
+	}
+	if policy.Issuer != "" {
+		if issuer := certificateIssuer(cert); issuer != policy.Issuer {
+			return fmt.Errorf("certificate issuer %q does not match %s", issuer, policy.Issuer)
+		}
+	}
+	return nil
+}
+
+// verifyRekorBundle checks the signed entry timestamp of the transparency log
+// entry and that the entry records this signature. It returns the time the
+// entry was integrated into the log.
+func verifyRekorBundle(sig Signature, rekorKeyPEM []byte) (time.Time, error) {
+	if len(sig.Bundle) == 0 {
+		return time.Time{}, errors.New("keyless signature has no transparency log bundle")
+	}
+	var bundle struct {
+		SignedEntryTimestamp []byte `json:"SignedEntryTimestamp"`
+		Payload              struct {
+			Body           string `json:"body"`
+			IntegratedTime int64  `json:"integratedTime"`
+			LogIndex       int64  `json:"logIndex"`
+			LogID          string `json:"logID"`
+		} `json:"Payload"`
+	}
+	if err := json.Unmarshal(sig.Bundle, &bundle); err != nil {
+		return time.Time{}, fmt.Errorf("invalid transparency log bundle: %w", err)
+	}
+	rekorKey, err := parsePublicKey(rekorKeyPEM)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("rekor public key: %w", err)
+	}
+	// The timestamp signs the canonical JSON of the payload, whose keys are sorted.
+	canonical, err := json.Marshal(struct {
+		Body           string `json:"body"`
+		IntegratedTime int64  `json:"integratedTime"`
+		LogID          string `json:"logID"`
+		LogIndex       int64  `json:"logIndex"`
+	}{bundle.Payload.Body, bundle.Payload.IntegratedTime, bundle.Payload.LogID, bundle.Payload.LogIndex})
+	if err != nil {
+		return time.Time{}, err
+	}
+	if err := verifyPayload(rekorKey, canonical, bundle.SignedEntryTimestamp); err != nil {
+		return time.Time{}, fmt.Errorf("transparency log timestamp: %w", err)
+	}
+
+	body, err := base64.StdEncoding.DecodeString(bundle.Payload.Body)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid transparency log entry: %w", err)
+	}
+	var entry struct {
+		Spec struct {
+			Signature struct {
+				Content string `json:"content"`
+			} `json:"signature"`
+			Data struct {
+				Hash struct {
+					Value string `json:"value"`
+				} `json:"hash"`
+			} `json:"data"`
+		} `json:"spec"`
+	}
+	if err := json.Unmarshal(body, &entry); err != nil {
+		return time.Time{}, fmt.Errorf("invalid transparency log entry: %w", err)
+	}
+	payloadHash := sha256.Sum256(sig.Payload)
+	if entry.Spec.Signature.Content != base64.StdEncoding.EncodeToString(sig.Signature) || entry.Spec.Data.Hash.Value != hex.EncodeToString(payloadHash[:]) {
+		return time.Time{}, errors.New("transparency log entry is for a different signature")
+	}
+	return time.Unix(bundle.Payload.IntegratedTime, 0), nil
+}
+
+func certificateIssuer(cert *x509.Certificate) string {
+	for _, ext := range cert.Extensions {
+		switch {
+		case ext.Id.Equal(oidFulcioIssuerV2):
+			var issuer string
+			if _, err := asn1.Unmarshal(ext.Value, &issuer); err == nil {
+				return issuer
+			}
+		case ext.Id.Equal(oidFulcioIssuer):
+			return string(ext.Value)
+		}
+	}
+	return ""
+}
+
+func parsePublicKey(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(bytes.TrimSpace(data))
+	if block == nil {
+		return nil, errors.New("public key is not PEM encoded")
+	}
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
+
+func verifyPayload(key crypto.PublicKey, payload, signature []byte) error {
+	digest := sha256.Sum256(payload)
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		if ecdsa.VerifyASN1(k, digest[:], signature) {
+			return nil
+		}
+	case *rsa.PublicKey:
+		if rsa.VerifyPKCS1v15(k, crypto.SHA256, digest[:], signature) == nil {
+			return nil
+		}
+	case ed25519.PublicKey:
+		if ed25519.Verify(k, payload, signature) {
+			return nil
+		}
+	default:
+		return fmt.Errorf("unsupported public key type %T", key)
+	}
+	return errors.New("signature does not match")
+}
@@ -0,0 +1,184 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package registry
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+const cosignTestDigest = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+func cosignTestPayload(digest string) []byte {
+	return []byte(fmt.Sprintf(`{"critical":{"identity":{"docker-reference":"ml/marklogic-db"},"image":{"docker-manifest-digest":%q},"type":"cosign container image signature"},"optional":null}`, digest))
+}
+
+func newTestKey(t *testing.T) (*ecdsa.PrivateKey, []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("failed to marshal key: %v", err)
+	}
+	return key, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
+
+func signTest(t *testing.T, key *ecdsa.PrivateKey, data []byte) []byte {
+	t.Helper()
+	digest := sha256.Sum256(data)
+	sig, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	if err != nil {
+		t.Fatalf("failed to sign: %v", err)
+	}
+	return sig
+}
+
+func TestImageSignaturesReadsSignatureManifest(t *testing.T) {
+	key, publicKey := newTestKey(t)
+	payload := cosignTestPayload(cosignTestDigest)
+	payloadSum := sha256.Sum256(payload)
+	payloadDigest := "sha256:" + hex.EncodeToString(payloadSum[:])
+	signature := base64.StdEncoding.EncodeToString(signTest(t, key, payload))
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/ml/marklogic-db/manifests/12.0.3":
+			w.Header().Set("Content-Type", mediaTypeOCIIndex)
+			w.Header().Set("Docker-Content-Digest", cosignTestDigest)
+		case "/v2/ml/marklogic-db/manifests/" + strings.Replace(cosignTestDigest, ":", "-", 1) + ".sig":
+			w.Header().Set("Content-Type", mediaTypeOCIManifest)
+			_, _ = fmt.Fprintf(w, `{"layers":[{"digest":%q,"annotations":{%q:%q}}]}`, payloadDigest, cosignSignatureAnnotation, signature)
+		case "/v2/ml/marklogic-db/blobs/" + payloadDigest:
+			_, _ = w.Write(payload)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "https://")
+	opts := Options{HTTPClient: server.Client()}
+
+	digest, signatures, err := ImageSignatures(context.Background(), host+"/ml/marklogic-db:12.0.3", opts)
+	if err != nil || digest != cosignTestDigest || len(signatures) != 1 {
+		t.Fatalf("unexpected signatures %s %+v: %v", digest, signatures, err)
+	}
+	if err := VerifyCosignSignatures(digest, signatures, CosignPolicy{PublicKey: publicKey}); err != nil {
+		t.Fatalf("expected the signature to verify, got %v", err)
+	}
+	_, otherKey := newTestKey(t)
+	if err := VerifyCosignSignatures(digest, signatures, CosignPolicy{PublicKey: otherKey}); err == nil {
+		t.Fatalf("expected a signature of another key to be rejected")
+	}
+
+	otherDigest := "sha256:" + strings.Repeat("f", 64)
+	_, signatures, err = ImageSignatures(context.Background(), host+"/ml/marklogic-db@"+otherDigest, opts)
+	if err != nil || len(signatures) != 0 {
+		t.Fatalf("expected an unsigned image to have no signatures, got %+v %v", signatures, err)
+	}
+	if err := VerifyCosignSignatures(otherDigest, signatures, CosignPolicy{PublicKey: publicKey}); err == nil {
+		t.Fatalf("expected an unsigned image to be rejected")
+	}
+}
+
+func TestVerifyCosignSignaturesKeyless(t *testing.T) {
+	now := time.Now()
+	rootKey, _ := newTestKey(t)
+	rootTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "sigstore"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	rootDER, err := x509.CreateCertificate(rand.Reader, rootTemplate, rootTemplate, &rootKey.PublicKey, rootKey)
+	if err != nil {
+		t.Fatalf("failed to create root: %v", err)
+	}
+	root, _ := x509.ParseCertificate(rootDER)
+
+	// The signing certificate lived for ten minutes and has expired since.
+	signedAt := now.Add(-30 * time.Minute)
+	signerKey, _ := newTestKey(t)
+	issuer, _ := asn1.Marshal("https://token.actions.githubusercontent.com")
+	identity, _ := url.Parse("https://github.com/marklogic/release/.github/workflows/release.yaml@refs/heads/main")
+	leafTemplate := &x509.Certificate{
+		SerialNumber:    big.NewInt(2),
+		NotBefore:       signedAt.Add(-time.Minute),
+		NotAfter:        signedAt.Add(9 * time.Minute),
+		KeyUsage:        x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+		URIs:            []*url.URL{identity},
+		ExtraExtensions: []pkix.Extension{{Id: oidFulcioIssuerV2, Value: issuer}},
+	}
+	leafDER, err := x509.CreateCertificate(rand.Reader, leafTemplate, root, &signerKey.PublicKey, rootKey)
+	if err != nil {
+		t.Fatalf("failed to create leaf: %v", err)
+	}
+
+	payload := cosignTestPayload(cosignTestDigest)
+	sig := signTest(t, signerKey, payload)
+	payloadSum := sha256.Sum256(payload)
+	body, _ := json.Marshal(map[string]any{"spec": map[string]any{
+		"signature": map[string]any{"content": base64.StdEncoding.EncodeToString(sig)},
+		"data":      map[string]any{"hash": map[string]any{"algorithm": "sha256", "value": hex.EncodeToString(payloadSum[:])}},
+	}})
+	rekorKey, rekorPublicKey := newTestKey(t)
+	entry := map[string]any{"body": base64.StdEncoding.EncodeToString(body), "integratedTime": signedAt.Unix(), "logID": "c0d23d6a", "logIndex": 42}
+	canonical, _ := json.Marshal(entry)
+	bundle, _ := json.Marshal(map[string]any{"SignedEntryTimestamp": signTest(t, rekorKey, canonical), "Payload": entry})
+
+	signature := Signature{
+		Payload:     payload,
+		Signature:   sig,
+		Certificate: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leafDER}),
+		Bundle:      bundle,
+	}
+	policy := CosignPolicy{
+		Identity:       identity.String(),
+		Issuer:         "https://token.actions.githubusercontent.com",
+		Roots:          pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: rootDER}),
+		RekorPublicKey: rekorPublicKey,
+	}
+	if err := VerifyCosignSignatures(cosignTestDigest, []Signature{signature}, policy); err != nil {
+		t.Fatalf("expected the keyless signature to verify, got %v", err)
+	}
+
+	wrongIdentity := policy
+	wrongIdentity.Identity = "https://github.com/someone/else"
+	if err := VerifyCosignSignatures(cosignTestDigest, []Signature{signature}, wrongIdentity); err == nil || !strings.Contains(err.Error(), "does not match") {
+		t.Fatalf("expected another identity to be rejected, got %v", err)
+	}
+	wrongIssuer := policy
+	wrongIssuer.Issuer = "https://accounts.google.com"
+	if err := VerifyCosignSignatures(cosignTestDigest, []Signature{signature}, wrongIssuer); err == nil {
+		t.Fatalf("expected another issuer to be rejected")
+	}
+	tampered := signature
+	tampered.Bundle = []byte(strings.Replace(string(bundle), "42", "43", 1))
+	if err := VerifyCosignSignatures(cosignTestDigest, []Signature{tampered}, policy); err == nil {
+		t.Fatalf("expected a tampered log entry to be rejected")
+	}
+}
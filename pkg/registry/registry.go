@@ -0,0 +1,373 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+// Package registry reads image manifests from OCI and Docker registries.
+package registry
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	mediaTypeOCIIndex        = "application/vnd.oci.image.index.v1+json"
+	mediaTypeOCIManifest     = "application/vnd.oci.image.manifest.v1+json"
+	mediaTypeDockerList      = "application/vnd.docker.distribution.manifest.list.v2+json"
+	mediaTypeDockerManifest  = "application/vnd.docker.distribution.manifest.v2+json"
+	dockerHubRegistry        = "docker.io"
+	dockerHubRegistryHost    = "registry-1.docker.io"
+	defaultRequestTimeout    = 30 * time.Second
+	maxManifestResponseBytes = 4 << 20
+)
+
+var manifestAccept = strings.Join([]string{mediaTypeOCIIndex, mediaTypeDockerList, mediaTypeOCIManifest, mediaTypeDockerManifest}, ", ")
+
+// Platform is an operating system and CPU architecture an image is built for.
+type Platform struct {
+	OS           string `json:"os"`
+	Architecture string `json:"architecture"`
+	Variant      string `json:"variant,omitempty"`
+}
+
+// Credentials authenticate against a registry.
+type Credentials struct {
+	Username string
+	Password string
+}
+
+type Options struct {
+	// Credentials are looked up by registry host, as in a docker config.
+	Credentials map[string]Credentials
+	HTTPClient  *http.Client
+}
+
+// Reference is a parsed image reference.
+type Reference struct {
+	// Registry is the registry host, docker.io for Docker Hub.
+	Registry   string
+	Repository string
+	// Reference is the tag or digest of the image.
+	Reference string
+}
+
+// ParseReference splits an image such as progressofficial/marklogic-db:12.0.3
+// into the registry, repository and tag or digest.
+func ParseReference(image string) (Reference, error) {
+	if image == "" {
+		return Reference{}, errors.New("image is empty")
+	}
+	ref := Reference{Registry: dockerHubRegistry}
+	name := image
+	if slash := strings.Index(name, "/"); slash > 0 {
+		host := name[:slash]
+		if strings.ContainsAny(host, ".:") || host == "localhost" {
+			ref.Registry = host
+			name = name[slash+1:]
+		}
+	}
+	if at := strings.Index(name, "@"); at >= 0 {
+		ref.Reference = name[at+1:]
+		name = name[:at]
+	} else if colon := strings.LastIndex(name, ":"); colon >= 0 {
+		ref.Reference = name[colon+1:]
+		name = name[:colon]
+	}
+	if ref.Reference == "" {
+		ref.Reference = "latest"
+	}
+	if name == "" {
+		return Reference{}, fmt.Errorf("image %q has no repository", image)
+	}
+	if ref.Registry == dockerHubRegistry && !strings.Contains(name, "/") {
+		name = "library/" + name
+	}
+	ref.Repository = name
+	return ref, nil
+}
+
+// ImagePlatforms returns the platforms the image is available for. A HEAD
+// request on the manifest tells an image index from a single-platform
+// manifest; only then is the index, or the config of the single image, read.
+func ImagePlatforms(ctx context.Context, image string, opts Options) ([]Platform, error) {
+	c, err := newRegistryClient(image, opts)
+	if err != nil {
+		return nil, err
+	}
+	ref := c.ref
+	manifestPath := fmt.Sprintf("/v2/%s/manifests/%s", ref.Repository, ref.Reference)
+	resp, err := c.do(ctx, http.MethodHead, manifestPath, manifestAccept)
+	if err != nil {
+		return nil, err
+	}
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	_ = resp.Body.Close()
+
+	switch mediaType {
+	case mediaTypeOCIIndex, mediaTypeDockerList:
+		var index struct {
+			Manifests []struct {
+				Platform *Platform `json:"platform"`
+			} `json:"manifests"`
+		}
+		if err := c.getJSON(ctx, manifestPath, manifestAccept, &index); err != nil {
+			return nil, err
+		}
+		platforms := []Platform{}
+		for _, manifest := range index.Manifests {
+			// Attestation manifests report the platform unknown/unknown.
+			if manifest.Platform != nil && manifest.Platform.Architecture != "unknown" {
+				platforms = append(platforms, *manifest.Platform)
+			}
+		}
+		return platforms, nil
+	case mediaTypeOCIManifest, mediaTypeDockerManifest:
+		var manifest struct {
+			Config struct {
+				Digest string `json:"digest"`
+			} `json:"config"`
+		}
+		if err := c.getJSON(ctx, manifestPath, manifestAccept, &manifest); err != nil {
+			return nil, err
+		}
+		var platform Platform
+		if err := c.getJSON(ctx, fmt.Sprintf("/v2/%s/blobs/%s", ref.Repository, manifest.Config.Digest), "", &platform); err != nil {
+			return nil, err
+		}
+		return []Platform{platform}, nil
+	default:
+		return nil, fmt.Errorf("manifest of %s has unsupported media type %q", image, mediaType)
+	}
+}
+
+type statusError struct {
+	method     string
+	url        string
+	statusCode int
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("%s %s returned status %d", e.method, e.url, e.statusCode)
+}
+
+// IsNotFound reports whether the registry answered that the image or one of
+// its blobs does not exist.
+func IsNotFound(err error) bool {
+	var statusErr *statusError
+	return errors.As(err, &statusErr) && statusErr.statusCode == http.StatusNotFound
+}
+
+func newRegistryClient(image string, opts Options) (*registryClient, error) {
+	ref, err := ParseReference(image)
+	if err != nil {
+		return nil, err
+	}
+	c := &registryClient{ref: ref, httpClient: opts.HTTPClient}
+	if c.httpClient == nil {
+		c.httpClient = &http.Client{Timeout: defaultRequestTimeout}
+	}
+	if creds, ok := lookupCredentials(opts.Credentials, ref.Registry); ok {
+		c.credentials = &creds
+	}
+	return c, nil
+}
+
+type registryClient struct {
+	ref         Reference
+	httpClient  *http.Client
+	credentials *Credentials
+	token       string
+}
+
+func (c *registryClient) baseURL() string {
+	if c.ref.Registry == dockerHubRegistry {
+		return "https://" + dockerHubRegistryHost
+	}
+	return "https://" + c.ref.Registry
+}
+
+func (c *registryClient) getJSON(ctx context.Context, path, accept string, into any) (err error) {
+	resp, err := c.do(ctx, http.MethodGet, path, accept)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, resp.Body.Close())
+	}()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxManifestResponseBytes))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, into)
+}
+
+// do sends the request, answering a registry authentication challenge once.
+// The caller closes the body of the returned response.
+func (c *registryClient) do(ctx context.Context, method, path, accept string) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, method, c.baseURL()+path, nil)
+		if err != nil {
+			return nil, err
+		}
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		if c.token != "" {
+			req.Header.Set("Authorization", "Bearer "+c.token)
+		} else if c.credentials != nil {
+			req.SetBasicAuth(c.credentials.Username, c.credentials.Password)
+		}
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode == http.StatusUnauthorized && attempt == 0 {
+			challenge := resp.Header.Get("WWW-Authenticate")
+			_ = resp.Body.Close()
+			if err := c.authenticate(ctx, challenge); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if resp.StatusCode != http.StatusOK {
+			_ = resp.Body.Close()
+			return nil, &statusError{method: method, url: c.ref.Registry + "/" + strings.TrimPrefix(path, "/v2/"), statusCode: resp.StatusCode}
+		}
+		return resp, nil
+	}
+}
+
+// authenticate fetches a pull token for a Bearer challenge. Basic challenges
+// are answered with the credentials on the next request.
+func (c *registryClient) authenticate(ctx context.Context, challenge string) (err error) {
+	scheme, params := parseChallenge(challenge)
+	switch scheme {
+	case "basic":
+		if c.credentials == nil {
+			return fmt.Errorf("registry %s requires credentials", c.ref.Registry)
+		}
+		return nil
+	case "bearer":
+	default:
+		return fmt.Errorf("registry %s returned unsupported challenge %q", c.ref.Registry, challenge)
+	}
+	realm, err := url.Parse(params["realm"])
+	if err != nil || params["realm"] == "" {
+		return fmt.Errorf("registry %s returned invalid token realm %q", c.ref.Registry, params["realm"])
+	}
+	query := realm.Query()
+	if service := params["service"]; service != "" {
+		query.Set("service", service)
+	}
+	scope := params["scope"]
+	if scope == "" {
+		scope = fmt.Sprintf("repository:%s:pull", c.ref.Repository)
+	}
+	query.Set("scope", scope)
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return err
+	}
+	if c.credentials != nil {
+		req.SetBasicAuth(c.credentials.Username, c.credentials.Password)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, resp.Body.Close())
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("token request to %s returned status %d", realm.Host, resp.StatusCode)
+	}
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxManifestResponseBytes)).Decode(&token); err != nil {
+		return err
+	}
+	c.token = token.Token
+	if c.token == "" {
+		c.token = token.AccessToken
+	}
+	if c.token == "" {
+		return fmt.Errorf("token request to %s returned no token", realm.Host)
+	}
+	return nil
+}
+
+// parseChallenge parses a WWW-Authenticate header such as
+// Bearer realm="https://auth.docker.io/token",service="registry.docker.io".
+func parseChallenge(header string) (string, map[string]string) {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(header), " ")
+	params := map[string]string{}
+	for rest != "" {
+		var key, value string
+		key, rest, _ = strings.Cut(strings.TrimLeft(rest, " ,"), "=")
+		if strings.HasPrefix(rest, `"`) {
+			value, rest, _ = strings.Cut(rest[1:], `"`)
+		} else {
+			value, rest, _ = strings.Cut(rest, ",")
+		}
+		if key != "" {
+			params[strings.ToLower(strings.TrimSpace(key))] = value
+		}
+	}
+	return strings.ToLower(scheme), params
+}
+
+// CredentialsFromDockerConfig reads the registry credentials of a
+// kubernetes.io/dockerconfigjson Secret.
+func CredentialsFromDockerConfig(data []byte) (map[string]Credentials, error) {
+	var config struct {
+		Auths map[string]struct {
+			Username string `json:"username"`
+			Password string `json:"password"`
+			Auth     string `json:"auth"`
+		} `json:"auths"`
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+	creds := map[string]Credentials{}
+	for host, entry := range config.Auths {
+		cred := Credentials{Username: entry.Username, Password: entry.Password}
+		if entry.Auth != "" {
+			decoded, err := base64.StdEncoding.DecodeString(entry.Auth)
+			if err != nil {
+				return nil, fmt.Errorf("invalid auth for registry %s: %w", host, err)
+			}
+			cred.Username, cred.Password, _ = strings.Cut(string(decoded), ":")
+		}
+		creds[normalizeRegistryHost(host)] = cred
+	}
+	return creds, nil
+}
+
+func lookupCredentials(creds map[string]Credentials, registry string) (Credentials, bool) {
+	cred, ok := creds[normalizeRegistryHost(registry)]
+	return cred, ok
+}
+
+// normalizeRegistryHost maps the spellings of a registry in docker configs,
+// such as https://index.docker.io/v1/, to the host of the image reference.
+func normalizeRegistryHost(host string) string {
+	host = strings.TrimPrefix(strings.TrimPrefix(host, "https://"), "http://")
+	host, _, _ = strings.Cut(host, "/")
+	switch host {
+	case "index.docker.io", dockerHubRegistryHost:
+		return dockerHubRegistry
+	}
+	return host
+}
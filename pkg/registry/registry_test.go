@@ -0,0 +1,107 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package registry
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestParseReference(t *testing.T) {
+	tests := []struct {
+		image string
+		want  Reference
+	}{
+		{"progressofficial/marklogic-db:12.0.3", Reference{"docker.io", "progressofficial/marklogic-db", "12.0.3"}},
+		{"haproxy", Reference{"docker.io", "library/haproxy", "latest"}},
+		{"registry.example.com:5000/ml/marklogic-db@sha256:abc", Reference{"registry.example.com:5000", "ml/marklogic-db", "sha256:abc"}},
+		{"localhost/marklogic-db:11", Reference{"localhost", "marklogic-db", "11"}},
+	}
+	for _, tt := range tests {
+		got, err := ParseReference(tt.image)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseReference(%q) = %+v, %v; want %+v", tt.image, got, err, tt.want)
+		}
+	}
+}
+
+func TestImagePlatformsReadsIndexWithBearerToken(t *testing.T) {
+	var heads int
+	server := httptest.NewTLSServer(nil)
+	defer server.Close()
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/token":
+			if user, pass, ok := r.BasicAuth(); !ok || user != "robot" || pass != "s3cret" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			if r.URL.Query().Get("scope") != "repository:ml/marklogic-db:pull" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			_, _ = fmt.Fprint(w, `{"token":"abc"}`)
+		case r.Header.Get("Authorization") != "Bearer abc":
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="test"`, server.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+		case r.URL.Path == "/v2/ml/marklogic-db/manifests/12.0.3":
+			w.Header().Set("Content-Type", mediaTypeOCIIndex)
+			if r.Method == http.MethodHead {
+				heads++
+				return
+			}
+			_, _ = fmt.Fprint(w, `{"manifests":[
+				{"platform":{"os":"linux","architecture":"amd64"}},
+				{"platform":{"os":"linux","architecture":"arm64","variant":"v8"}},
+				{"platform":{"os":"unknown","architecture":"unknown"}}]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	host := strings.TrimPrefix(server.URL, "https://")
+	config := fmt.Sprintf(`{"auths":{"https://%s/v1/":{"auth":"cm9ib3Q6czNjcmV0"}}}`, host)
+	creds, err := CredentialsFromDockerConfig([]byte(config))
+	if err != nil {
+		t.Fatalf("failed to read docker config: %v", err)
+	}
+
+	platforms, err := ImagePlatforms(context.Background(), host+"/ml/marklogic-db:12.0.3", Options{Credentials: creds, HTTPClient: server.Client()})
+	if err != nil {
+		t.Fatalf("ImagePlatforms returned %v", err)
+	}
+	if heads != 1 || len(platforms) != 2 || platforms[0].Architecture != "amd64" || platforms[1].Architecture != "arm64" {
+		t.Fatalf("unexpected platforms %+v after %d HEAD requests", platforms, heads)
+	}
+}
+
+func TestImagePlatformsReadsSingleManifestConfig(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/marklogic-db/manifests/11.3.1":
+			w.Header().Set("Content-Type", mediaTypeDockerManifest)
+			_, _ = fmt.Fprint(w, `{"config":{"digest":"sha256:config"}}`)
+		case "/v2/marklogic-db/blobs/sha256:config":
+			_, _ = fmt.Fprint(w, `{"os":"linux","architecture":"amd64"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	image := strings.TrimPrefix(server.URL, "https://") + "/marklogic-db:11.3.1"
+	platforms, err := ImagePlatforms(context.Background(), image, Options{HTTPClient: server.Client()})
+	if err != nil {
+		t.Fatalf("ImagePlatforms returned %v", err)
+	}
+	if len(platforms) != 1 || platforms[0].Architecture != "amd64" {
+		t.Fatalf("unexpected platforms %+v", platforms)
+	}
+
+	if _, err := ImagePlatforms(context.Background(), strings.TrimSuffix(image, "11.3.1")+"missing", Options{HTTPClient: server.Client()}); err == nil {
+		t.Fatalf("expected an error for a missing tag")
+	}
+}
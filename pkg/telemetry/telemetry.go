@@ -0,0 +1,210 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+// Package telemetry reports how the operator is used to an endpoint chosen by
+// the installation, so the maintainers can prioritize features. It is opt-in:
+// nothing is collected or sent unless telemetry is enabled in the operator
+// configuration. The report is anonymous. It holds the operator version,
+// counts of clusters, groups and hosts and how many clusters use each
+// feature, never names, namespaces, images, hosts or credentials.
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/features"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// initialDelay lets the manager cache sync before the first report.
+	initialDelay  = time.Minute
+	clientTimeout = 30 * time.Second
+)
+
+// Options configures a Reporter.
+type Options struct {
+	// Endpoint is the URL the report is posted to as JSON.
+	Endpoint string
+	// Interval between two reports.
+	Interval        time.Duration
+	OperatorVersion string
+}
+
+// Report is the anonymous usage report.
+type Report struct {
+	OperatorVersion string `json:"operatorVersion"`
+	Clusters        int    `json:"clusters"`
+	Groups          int    `json:"groups"`
+	// Hosts is the sum of the replicas of the groups.
+	Hosts int `json:"hosts"`
+	// Features counts the clusters using each feature.
+	Features map[string]int `json:"features"`
+	// FeatureGates is the state of the feature gates of the operator.
+	FeatureGates map[string]bool `json:"featureGates"`
+}
+
+// Reporter posts a Report every Interval. It implements the controller-runtime
+// Runnable interface.
+type Reporter struct {
+	client     client.Reader
+	httpClient *http.Client
+	opts       Options
+	log        logr.Logger
+}
+
+// NewReporter returns a Reporter reading the clusters with c, usually the
+// manager client.
+func NewReporter(c client.Reader, opts Options, log logr.Logger) *Reporter {
+	return &Reporter{
+		client:     c,
+		httpClient: &http.Client{Timeout: clientTimeout},
+		opts:       opts,
+		log:        log,
+	}
+}
+
+// Start sends a report after initialDelay and then every Interval until ctx
+// is done. Failed reports are logged and dropped.
+func (r *Reporter) Start(ctx context.Context) error {
+	timer := time.NewTimer(initialDelay)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-timer.C:
+			if err := r.Send(ctx); err != nil {
+				r.log.Error(err, "Failed to send the telemetry report", "endpoint", r.opts.Endpoint)
+			}
+			timer.Reset(r.opts.Interval)
+		}
+	}
+}
+
+// NeedLeaderElection is true so an installation reports once, whatever the
+// number of replicas.
+func (r *Reporter) NeedLeaderElection() bool {
+	return true
+}
+
+// Send collects a report and posts it to the endpoint.
+func (r *Reporter) Send(ctx context.Context) error {
+	report, err := r.Collect(ctx)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.opts.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("telemetry endpoint returned %s", resp.Status)
+	}
+	r.log.V(1).Info("Sent the telemetry report", "report", report)
+	return nil
+}
+
+// Collect builds the report from the MarklogicClusters the operator watches.
+func (r *Reporter) Collect(ctx context.Context) (Report, error) {
+	clusters := &marklogicv1.MarklogicClusterList{}
+	if err := r.client.List(ctx, clusters); err != nil {
+		return Report{}, err
+	}
+	report := Report{
+		OperatorVersion: r.opts.OperatorVersion,
+		Clusters:        len(clusters.Items),
+		Features:        map[string]int{},
+		FeatureGates:    map[string]bool{},
+	}
+	for i := range clusters.Items {
+		cr := &clusters.Items[i]
+		for _, group := range cr.Spec.MarkLogicGroups {
+			if group == nil {
+				continue
+			}
+			report.Groups++
+			replicas := 1
+			if group.Replicas != nil {
+				replicas = int(*group.Replicas)
+			}
+			report.Hosts += replicas
+		}
+		for _, feature := range clusterFeatures(cr) {
+			report.Features[feature]++
+		}
+	}
+	for _, name := range features.Known() {
+		report.FeatureGates[name] = features.Enabled(features.Feature(name))
+	}
+	return report, nil
+}
+
+// clusterFeatures returns the optional features a cluster uses, sorted.
+func clusterFeatures(cr *marklogicv1.MarklogicCluster) []string {
+	used := map[string]bool{
+		"haproxy":                cr.Spec.HAProxy != nil && cr.Spec.HAProxy.Enabled,
+		"logCollection":          cr.Spec.LogCollection != nil && cr.Spec.LogCollection.Enabled,
+		"tls":                    cr.Spec.Tls != nil && cr.Spec.Tls.EnableOnDefaultAppServers,
+		"networkPolicy":          cr.Spec.NetworkPolicy.Enabled,
+		"backup":                 cr.Spec.Backup != nil && cr.Spec.Backup.Enabled,
+		"hibernation":            cr.Spec.Hibernation != nil,
+		"stopped":                cr.Spec.Stopped,
+		"drain":                  cr.Spec.Drain != nil,
+		"fipsMode":               cr.Spec.FIPSMode,
+		"ocpCompatible":          cr.Spec.OCPCompatible,
+		"restrictedPodSecurity":  cr.Spec.RestrictedPodSecurity,
+		"readOnlyRootFilesystem": cr.Spec.ReadOnlyRootFilesystem != nil && cr.Spec.ReadOnlyRootFilesystem.Enabled,
+		"clusterProfile":         cr.Spec.Profile != "",
+		"healthReport":           cr.Spec.HealthReport != nil,
+		"nodeDrain":              cr.Spec.NodeDrain != nil && cr.Spec.NodeDrain.Enabled,
+		"autoscalerDisruption":   cr.Spec.AutoscalerDisruption != "",
+		"dataImport":             cr.Spec.Bootstrap != nil && cr.Spec.Bootstrap.DataImport != nil,
+		"modules":                cr.Spec.Modules != nil,
+	}
+	for _, group := range cr.Spec.MarkLogicGroups {
+		if group == nil {
+			continue
+		}
+		used["logCollection"] = used["logCollection"] || (group.LogCollection != nil && group.LogCollection.Enabled)
+		used["drain"] = used["drain"] || group.Drain != nil
+		used["readOnlyRootFilesystem"] = used["readOnlyRootFilesystem"] || (group.ReadOnlyRootFilesystem != nil && group.ReadOnlyRootFilesystem.Enabled)
+		used["dynamicHosts"] = used["dynamicHosts"] || group.IsDynamic
+		used["forestProvisioning"] = used["forestProvisioning"] || group.Forests != nil
+		used["scaleUp"] = used["scaleUp"] || group.ScaleUp != nil
+		used["groupProfiles"] = used["groupProfiles"] || group.Profile != ""
+		used["autoscalerDisruption"] = used["autoscalerDisruption"] || group.AutoscalerDisruption != ""
+		used["disruptibleNodes"] = used["disruptibleNodes"] || group.AllowDisruptibleNodes
+		used["appServerSettings"] = used["appServerSettings"] || len(group.AppServerSettings) > 0
+		if autoscaling := group.Autoscaling; autoscaling != nil {
+			used["verticalAutoscaling"] = used["verticalAutoscaling"] || autoscaling.Vertical != nil
+			used["loadMetrics"] = used["loadMetrics"] || (autoscaling.LoadMetrics != nil && autoscaling.LoadMetrics.Enabled)
+			used["keda"] = used["keda"] || autoscaling.Keda != nil
+		}
+	}
+	names := []string{}
+	for name, ok := range used {
+		if ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
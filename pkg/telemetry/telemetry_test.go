@@ -0,0 +1,85 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestSendPostsAnonymousReport(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := marklogicv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add marklogic scheme: %v", err)
+	}
+	replicas := int32(3)
+	cluster := &marklogicv1.MarklogicCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "customer-prod", Namespace: "payments"},
+		Spec: marklogicv1.MarklogicClusterSpec{
+			Image:   "registry.internal/marklogic:12.0.1",
+			HAProxy: &marklogicv1.HAProxy{Enabled: true},
+			MarkLogicGroups: []*marklogicv1.MarklogicGroups{
+				{Name: "dnode", Replicas: &replicas, IsBootstrap: true},
+				{Name: "enode", IsDynamic: true, Autoscaling: &marklogicv1.GroupAutoscaling{LoadMetrics: &marklogicv1.LoadMetrics{Enabled: true}}},
+			},
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster).Build()
+
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ = io.ReadAll(req.Body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	reporter := NewReporter(fakeClient, Options{Endpoint: server.URL, OperatorVersion: "1.3.0"}, logr.Discard())
+	if err := reporter.Send(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, private := range []string{"customer-prod", "payments", "registry.internal", "dnode", "enode"} {
+		if strings.Contains(string(body), private) {
+			t.Fatalf("expected the report to be anonymous, found %q in %s", private, body)
+		}
+	}
+	report := Report{}
+	if err := json.Unmarshal(body, &report); err != nil {
+		t.Fatalf("invalid report %s: %v", body, err)
+	}
+	if report.OperatorVersion != "1.3.0" || report.Clusters != 1 || report.Groups != 2 || report.Hosts != 4 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	if report.Features["haproxy"] != 1 || report.Features["loadMetrics"] != 1 || report.Features["dynamicHosts"] != 1 || report.Features["backup"] != 0 {
+		t.Fatalf("unexpected features %v", report.Features)
+	}
+	if len(report.FeatureGates) == 0 {
+		t.Fatalf("expected the feature gates to be reported")
+	}
+}
+
+func TestSendFailsOnRejectedReport(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := marklogicv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add marklogic scheme: %v", err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	reporter := NewReporter(fake.NewClientBuilder().WithScheme(scheme).Build(), Options{Endpoint: server.URL}, logr.Discard())
+	if err := reporter.Send(context.Background()); err == nil {
+		t.Fatalf("expected a rejected report to fail")
+	}
+}
@@ -0,0 +1,168 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package webhookcert
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// Certificates is a self-signed CA together with the serving certificate it issued.
+type Certificates struct {
+	CACert []byte
+	Cert   []byte
+	Key    []byte
+}
+
+// ServiceDNSNames returns the names the API server may use to reach the webhook Service.
+func ServiceDNSNames(service, namespace, clusterDomain string) []string {
+	names := []string{
+		service,
+		fmt.Sprintf("%s.%s", service, namespace),
+		fmt.Sprintf("%s.%s.svc", service, namespace),
+	}
+	if clusterDomain != "" {
+		names = append(names, fmt.Sprintf("%s.%s.svc.%s", service, namespace, clusterDomain))
+	}
+	return names
+}
+
+// Generate creates a new CA and a serving certificate for dnsNames, both valid
+// from now for validity. All values are PEM encoded.
+func Generate(dnsNames []string, now time.Time, validity time.Duration) (*Certificates, error) {
+	if len(dnsNames) == 0 {
+		return nil, errors.New("at least one DNS name is required")
+	}
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	caTemplate := &x509.Certificate{
+		SerialNumber:          newSerial(),
+		Subject:               pkix.Name{CommonName: "marklogic-operator-webhook-ca"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(validity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		return nil, err
+	}
+	caCert, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber: newSerial(),
+		Subject:      pkix.Name{CommonName: dnsNames[0]},
+		DNSNames:     dnsNames,
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(validity),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+	if err != nil {
+		return nil, err
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return &Certificates{
+		CACert: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER}),
+		Cert:   pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		Key:    pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}, nil
+}
+
+// NeedsRotation reports whether certs are missing, unparsable, do not cover
+// dnsNames, or expire within renewBefore.
+func NeedsRotation(certs *Certificates, dnsNames []string, now time.Time, renewBefore time.Duration) bool {
+	if certs == nil || len(certs.CACert) == 0 || len(certs.Cert) == 0 || len(certs.Key) == 0 {
+		return true
+	}
+	block, _ := pem.Decode(certs.Cert)
+	if block == nil {
+		return true
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return true
+	}
+	if now.Add(renewBefore).After(cert.NotAfter) {
+		return true
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(certs.CACert) {
+		return true
+	}
+	for _, name := range dnsNames {
+		if _, err := cert.Verify(x509.VerifyOptions{DNSName: name, Roots: pool, CurrentTime: now}); err != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// MergeCABundle returns caCert followed by the certificates of previous that
+// are still valid at now, so that serving certificates issued by a replaced
+// CA stay trusted until they expire. Duplicates are dropped.
+func MergeCABundle(caCert, previous []byte, now time.Time) []byte {
+	bundle := bytes.Clone(caCert)
+	seen := map[string]bool{}
+	for _, block := range certificateBlocks(caCert) {
+		seen[string(block.Bytes)] = true
+	}
+	for _, block := range certificateBlocks(previous) {
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil || seen[string(block.Bytes)] || !now.Before(cert.NotAfter) {
+			continue
+		}
+		seen[string(block.Bytes)] = true
+		bundle = append(bundle, pem.EncodeToMemory(block)...)
+	}
+	return bundle
+}
+
+func certificateBlocks(data []byte) []*pem.Block {
+	var blocks []*pem.Block
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return blocks
+		}
+		if block.Type == "CERTIFICATE" {
+			blocks = append(blocks, block)
+		}
+	}
+}
+
+func (c *Certificates) equal(other *Certificates) bool {
+	return other != nil && bytes.Equal(c.CACert, other.CACert) && bytes.Equal(c.Cert, other.Cert) && bytes.Equal(c.Key, other.Key)
+}
+
+func newSerial() *big.Int {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return big.NewInt(time.Now().UnixNano())
+	}
+	return serial
+}
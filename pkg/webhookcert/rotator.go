@@ -0,0 +1,254 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+// Package webhookcert provides serving certificates for the operator's
+// admission webhooks when cert-manager is not used. A self-signed CA and
+// serving certificate are kept in a Secret shared by all operator replicas,
+// written to the webhook server's certificate directory, injected into the
+// caBundle of the webhook configurations and rotated before they expire.
+// A rotation keeps the replaced CA in the bundle until it expires, so the
+// replicas that still serve the previous certificate until their next check
+// keep being trusted.
+package webhookcert
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-logr/logr"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+//+kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=validatingwebhookconfigurations;mutatingwebhookconfigurations,verbs=get;list;watch;update;patch
+
+const (
+	// ModeSelfSigned lets the operator generate and rotate its own certificates.
+	ModeSelfSigned = "self-signed"
+	// ModeCertManager expects cert-manager to provide the certificate files
+	// and to inject the CA through the cert-manager.io/inject-ca-from annotation.
+	ModeCertManager = "cert-manager"
+
+	caCertKey = "ca.crt"
+	certKey   = corev1.TLSCertKey
+	keyKey    = corev1.TLSPrivateKeyKey
+
+	defaultValidity      = 365 * 24 * time.Hour
+	defaultRenewBefore   = 30 * 24 * time.Hour
+	defaultCheckInterval = time.Hour
+)
+
+// Options configures a Rotator.
+type Options struct {
+	Namespace     string
+	SecretName    string
+	ServiceName   string
+	ClusterDomain string
+	// CertDir is where tls.crt and tls.key are written for the webhook server.
+	CertDir string
+	// ValidatingWebhookConfigurations and MutatingWebhookConfigurations get
+	// the CA injected into every webhook entry. Missing objects are skipped.
+	ValidatingWebhookConfigurations []string
+	MutatingWebhookConfigurations   []string
+	Validity                        time.Duration
+	RenewBefore                     time.Duration
+	CheckInterval                   time.Duration
+}
+
+// Rotator keeps the webhook certificates valid. It implements the
+// controller-runtime Runnable interface.
+type Rotator struct {
+	client client.Client
+	opts   Options
+	log    logr.Logger
+	now    func() time.Time
+}
+
+// NewRotator returns a Rotator. The client should not be backed by the
+// manager cache so that the operator namespace is reachable in
+// namespace-scoped mode and the first rotation can run before the manager starts.
+func NewRotator(c client.Client, opts Options, log logr.Logger) *Rotator {
+	if opts.Validity == 0 {
+		opts.Validity = defaultValidity
+	}
+	if opts.RenewBefore == 0 {
+		opts.RenewBefore = defaultRenewBefore
+	}
+	if opts.CheckInterval == 0 {
+		opts.CheckInterval = defaultCheckInterval
+	}
+	return &Rotator{client: c, opts: opts, log: log, now: time.Now}
+}
+
+// Start re-checks the certificates every CheckInterval until ctx is done.
+func (r *Rotator) Start(ctx context.Context) error {
+	ticker := time.NewTicker(r.opts.CheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := r.Ensure(ctx); err != nil {
+				r.log.Error(err, "Failed to rotate webhook certificates")
+			}
+		}
+	}
+}
+
+// NeedLeaderElection is false because every replica serves webhooks and needs the files.
+func (r *Rotator) NeedLeaderElection() bool {
+	return false
+}
+
+// Ensure makes sure a valid certificate is stored in the Secret, trusted by
+// the webhook configurations and written to CertDir. The CA is injected
+// before the certificate is served so that no request meets an unknown CA.
+func (r *Rotator) Ensure(ctx context.Context) error {
+	certs, err := r.ensureSecret(ctx)
+	if err != nil {
+		return err
+	}
+	if err := r.injectCABundle(ctx, certs.CACert); err != nil {
+		return err
+	}
+	return r.writeCertFiles(certs)
+}
+
+func (r *Rotator) dnsNames() []string {
+	return ServiceDNSNames(r.opts.ServiceName, r.opts.Namespace, r.opts.ClusterDomain)
+}
+
+func (r *Rotator) ensureSecret(ctx context.Context) (*Certificates, error) {
+	secret := &corev1.Secret{}
+	key := types.NamespacedName{Namespace: r.opts.Namespace, Name: r.opts.SecretName}
+	err := r.client.Get(ctx, key, secret)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+	exists := err == nil
+	current := &Certificates{CACert: secret.Data[caCertKey], Cert: secret.Data[certKey], Key: secret.Data[keyKey]}
+	var certs *Certificates
+	if exists && !NeedsRotation(current, r.dnsNames(), r.now(), r.opts.RenewBefore) {
+		// Prune the CAs of previous rotations once they expired.
+		certs = &Certificates{CACert: MergeCABundle(nil, current.CACert, r.now()), Cert: current.Cert, Key: current.Key}
+		if bytes.Equal(certs.CACert, current.CACert) {
+			return current, nil
+		}
+	} else {
+		r.log.Info("Generating webhook serving certificate", "secret", key.String())
+		if certs, err = Generate(r.dnsNames(), r.now(), r.opts.Validity); err != nil {
+			return nil, err
+		}
+		certs.CACert = MergeCABundle(certs.CACert, current.CACert, r.now())
+	}
+	data := map[string][]byte{caCertKey: certs.CACert, certKey: certs.Cert, keyKey: certs.Key}
+	if !exists {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+			Type:       corev1.SecretTypeTLS,
+			Data:       data,
+		}
+		if err := r.client.Create(ctx, secret); err != nil {
+			if apierrors.IsAlreadyExists(err) {
+				// Another replica won the race; use its certificate.
+				return r.ensureSecret(ctx)
+			}
+			return nil, err
+		}
+		return certs, nil
+	}
+	secret.Data = data
+	if err := r.client.Update(ctx, secret); err != nil {
+		if apierrors.IsConflict(err) {
+			return r.ensureSecret(ctx)
+		}
+		return nil, err
+	}
+	return certs, nil
+}
+
+// writeCertFiles updates the files only when they changed so that the webhook
+// server's certificate watcher does not reload needlessly.
+func (r *Rotator) writeCertFiles(certs *Certificates) error {
+	if r.opts.CertDir == "" {
+		return nil
+	}
+	onDisk := &Certificates{CACert: certs.CACert}
+	onDisk.Cert, _ = os.ReadFile(filepath.Join(r.opts.CertDir, certKey))
+	onDisk.Key, _ = os.ReadFile(filepath.Join(r.opts.CertDir, keyKey))
+	if certs.equal(onDisk) {
+		return nil
+	}
+	if err := os.MkdirAll(r.opts.CertDir, 0o700); err != nil {
+		return err
+	}
+	// Write the key first; the watcher reloads on the certificate write.
+	if err := writeFileAtomic(filepath.Join(r.opts.CertDir, keyKey), certs.Key); err != nil {
+		return err
+	}
+	return writeFileAtomic(filepath.Join(r.opts.CertDir, certKey), certs.Cert)
+}
+
+func writeFileAtomic(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func (r *Rotator) injectCABundle(ctx context.Context, caBundle []byte) error {
+	for _, name := range r.opts.ValidatingWebhookConfigurations {
+		config := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+		if err := r.client.Get(ctx, types.NamespacedName{Name: name}, config); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		patchBase := client.MergeFrom(config.DeepCopy())
+		changed := false
+		for i := range config.Webhooks {
+			if !bytes.Equal(config.Webhooks[i].ClientConfig.CABundle, caBundle) {
+				config.Webhooks[i].ClientConfig.CABundle = caBundle
+				changed = true
+			}
+		}
+		if changed {
+			if err := r.client.Patch(ctx, config, patchBase); err != nil {
+				return fmt.Errorf("failed to inject CA into %s: %w", name, err)
+			}
+		}
+	}
+	for _, name := range r.opts.MutatingWebhookConfigurations {
+		config := &admissionregistrationv1.MutatingWebhookConfiguration{}
+		if err := r.client.Get(ctx, types.NamespacedName{Name: name}, config); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		patchBase := client.MergeFrom(config.DeepCopy())
+		changed := false
+		for i := range config.Webhooks {
+			if !bytes.Equal(config.Webhooks[i].ClientConfig.CABundle, caBundle) {
+				config.Webhooks[i].ClientConfig.CABundle = caBundle
+				changed = true
+			}
+		}
+		if changed {
+			if err := r.client.Patch(ctx, config, patchBase); err != nil {
+				return fmt.Errorf("failed to inject CA into %s: %w", name, err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+// Copyright (c) 2024-2026 Progress Software Corporation and/or its subsidiaries or affiliates. All Rights Reserved.
+
+package webhookcert
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestNeedsRotation(t *testing.T) {
+	now := time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC)
+	names := ServiceDNSNames("webhook", "ops", "cluster.local")
+	certs, err := Generate(names, now, 90*24*time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if NeedsRotation(certs, names, now, 30*24*time.Hour) {
+		t.Fatal("expected a fresh certificate not to need rotation")
+	}
+	if !NeedsRotation(certs, names, now.Add(61*24*time.Hour), 30*24*time.Hour) {
+		t.Fatal("expected rotation inside the renew window")
+	}
+	if !NeedsRotation(certs, ServiceDNSNames("other", "ops", ""), now, time.Hour) {
+		t.Fatal("expected rotation when the certificate does not cover the service")
+	}
+	if !NeedsRotation(&Certificates{}, names, now, time.Hour) {
+		t.Fatal("expected rotation for empty certificates")
+	}
+}
+
+func TestEnsureCreatesSecretFilesAndCABundle(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	webhookConfig := &admissionregistrationv1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "marklogic-operator-validating-webhook-configuration"},
+		Webhooks: []admissionregistrationv1.ValidatingWebhook{{
+			Name:                    "vmarklogiccluster.marklogic.progress.com",
+			SideEffects:             ptrTo(admissionregistrationv1.SideEffectClassNone),
+			AdmissionReviewVersions: []string{"v1"},
+		}},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(webhookConfig).Build()
+	certDir := t.TempDir()
+	rotator := NewRotator(c, Options{
+		Namespace:                       "ops",
+		SecretName:                      "webhook-server-cert",
+		ServiceName:                     "webhook-service",
+		CertDir:                         certDir,
+		ValidatingWebhookConfigurations: []string{webhookConfig.Name},
+		MutatingWebhookConfigurations:   []string{"missing"},
+	}, logr.Discard())
+	ctx := context.Background()
+
+	if err := rotator.Ensure(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	secret := &corev1.Secret{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: "ops", Name: "webhook-server-cert"}, secret); err != nil {
+		t.Fatalf("expected the secret to be created: %v", err)
+	}
+	onDisk, err := os.ReadFile(filepath.Join(certDir, corev1.TLSCertKey))
+	if err != nil || !bytes.Equal(onDisk, secret.Data[corev1.TLSCertKey]) {
+		t.Fatalf("expected tls.crt to match the secret, err=%v", err)
+	}
+	updated := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+	if err := c.Get(ctx, types.NamespacedName{Name: webhookConfig.Name}, updated); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(updated.Webhooks[0].ClientConfig.CABundle, secret.Data["ca.crt"]) {
+		t.Fatal("expected the CA bundle to be injected")
+	}
+
+	// A second run keeps the certificate; a run inside the renew window replaces it.
+	if err := rotator.Ensure(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	unchanged := &corev1.Secret{}
+	_ = c.Get(ctx, types.NamespacedName{Namespace: "ops", Name: "webhook-server-cert"}, unchanged)
+	if !bytes.Equal(unchanged.Data[corev1.TLSCertKey], secret.Data[corev1.TLSCertKey]) {
+		t.Fatal("expected a valid certificate to be kept")
+	}
+	rotator.now = func() time.Time { return time.Now().Add(340 * 24 * time.Hour) }
+	if err := rotator.Ensure(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rotated := &corev1.Secret{}
+	_ = c.Get(ctx, types.NamespacedName{Namespace: "ops", Name: "webhook-server-cert"}, rotated)
+	if bytes.Equal(rotated.Data[corev1.TLSCertKey], secret.Data[corev1.TLSCertKey]) {
+		t.Fatal("expected the certificate to be rotated")
+	}
+}
+
+func TestRotationKeepsTrustingReplicasWithThePreviousCertificate(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	webhookConfig := &admissionregistrationv1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "marklogic-operator-validating-webhook-configuration"},
+		Webhooks: []admissionregistrationv1.ValidatingWebhook{{
+			Name:                    "vmarklogiccluster.marklogic.progress.com",
+			SideEffects:             ptrTo(admissionregistrationv1.SideEffectClassNone),
+			AdmissionReviewVersions: []string{"v1"},
+		}},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(webhookConfig).Build()
+	newReplica := func() (*Rotator, string) {
+		certDir := t.TempDir()
+		return NewRotator(c, Options{
+			Namespace:                       "ops",
+			SecretName:                      "webhook-server-cert",
+			ServiceName:                     "webhook-service",
+			CertDir:                         certDir,
+			ValidatingWebhookConfigurations: []string{webhookConfig.Name},
+		}, logr.Discard()), certDir
+	}
+	first, firstDir := newReplica()
+	second, secondDir := newReplica()
+	ctx := context.Background()
+	start := time.Now()
+	for _, replica := range []*Rotator{first, second} {
+		if err := replica.Ensure(ctx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	trusted := func(certDir string, now time.Time) bool {
+		config := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+		if err := c.Get(ctx, types.NamespacedName{Name: webhookConfig.Name}, config); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		served := &Certificates{CACert: config.Webhooks[0].ClientConfig.CABundle}
+		served.Cert, _ = os.ReadFile(filepath.Join(certDir, corev1.TLSCertKey))
+		served.Key, _ = os.ReadFile(filepath.Join(certDir, corev1.TLSPrivateKeyKey))
+		return !NeedsRotation(served, ServiceDNSNames("webhook-service", "ops", ""), now, 0)
+	}
+
+	// The first replica rotates; the second serves the previous certificate
+	// until its next check and must stay trusted meanwhile.
+	renewal := start.Add(340 * 24 * time.Hour)
+	first.now = func() time.Time { return renewal }
+	if err := first.Ensure(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !trusted(firstDir, renewal) || !trusted(secondDir, renewal) {
+		t.Fatal("expected the rotated and the previous certificates to be trusted")
+	}
+	second.now = first.now
+	if err := second.Ensure(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rotated, _ := os.ReadFile(filepath.Join(firstDir, corev1.TLSCertKey))
+	reloaded, _ := os.ReadFile(filepath.Join(secondDir, corev1.TLSCertKey))
+	if !bytes.Equal(rotated, reloaded) {
+		t.Fatal("expected the second replica to serve the rotated certificate")
+	}
+
+	// Once the previous CA expired it is pruned from the bundle.
+	expired := start.Add(366 * 24 * time.Hour)
+	first.now = func() time.Time { return expired }
+	if err := first.Ensure(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	config := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+	_ = c.Get(ctx, types.NamespacedName{Name: webhookConfig.Name}, config)
+	if cas := certificateBlocks(config.Webhooks[0].ClientConfig.CABundle); len(cas) != 1 || !trusted(firstDir, expired) {
+		t.Fatalf("expected only the current CA to be left, got %d", len(cas))
+	}
+}
+
+func ptrTo[T any](v T) *T {
+	return &v
+}
@@ -26,8 +26,8 @@ func cleanupHAProxyNamespaceArtifacts(ns string) {
 	// Remove stale resources from interrupted runs so each test starts from a clean workload state.
 	e2eutils.RunCommand(fmt.Sprintf("kubectl --request-timeout=20s -n %s delete marklogiccluster --all --ignore-not-found=true --wait=false", ns))
 	e2eutils.RunCommand(fmt.Sprintf("kubectl --request-timeout=20s -n %s delete statefulset --all --ignore-not-found=true --wait=false", ns))
-	e2eutils.RunCommand(fmt.Sprintf("kubectl --request-timeout=20s -n %s delete deployment marklogic-haproxy --ignore-not-found=true --wait=false", ns))
-	e2eutils.RunCommand(fmt.Sprintf("kubectl --request-timeout=20s -n %s delete service marklogic-haproxy ml ml-cluster --ignore-not-found=true --wait=false", ns))
+	e2eutils.RunCommand(fmt.Sprintf("kubectl --request-timeout=20s -n %s delete deployment marklogicclusters-haproxy marklogic-haproxy --ignore-not-found=true --wait=false", ns))
+	e2eutils.RunCommand(fmt.Sprintf("kubectl --request-timeout=20s -n %s delete service marklogicclusters-haproxy marklogic-haproxy ml ml-cluster --ignore-not-found=true --wait=false", ns))
 	e2eutils.RunCommand(fmt.Sprintf("kubectl --request-timeout=20s -n %s delete pod --all --ignore-not-found=true --wait=false", ns))
 	e2eutils.RunCommand(fmt.Sprintf("kubectl --request-timeout=20s -n %s delete pvc --all --ignore-not-found=true --wait=false", ns))
 
@@ -146,7 +146,7 @@ func TestHAProxyPathBasedEnabled(t *testing.T) {
 	})
 
 	feature.Assess("HAProxy with path-based routing is working", func(ctx context.Context, t *testing.T, c *envconf.Config) context.Context {
-		fqdn := fmt.Sprintf("marklogic-haproxy.%s.svc.cluster.local", haProxyPathNS)
+		fqdn := fmt.Sprintf("marklogicclusters-haproxy.%s.svc.cluster.local", haProxyPathNS)
 		url := "http://" + fqdn + ":8080/adminUI"
 		cmd := fmt.Sprintf("curl --anyauth -u %s:%s %s", adminUsername, adminPassword, url)
 		time.Sleep(5 * time.Second)
@@ -321,7 +321,7 @@ func TestHAProxyPathBasedDisabled(t *testing.T) {
 	})
 
 	feature.Assess("HAProxy with path-based routing disabled is working", func(ctx context.Context, t *testing.T, c *envconf.Config) context.Context {
-		fqdn := fmt.Sprintf("marklogic-haproxy.%s.svc.cluster.local", haProxyNS)
+		fqdn := fmt.Sprintf("marklogicclusters-haproxy.%s.svc.cluster.local", haProxyNS)
 		url := "http://" + fqdn + ":8001"
 		cmd := fmt.Sprintf("curl --anyauth -u %s:%s %s", adminUsername, adminPassword, url)
 		time.Sleep(5 * time.Second)
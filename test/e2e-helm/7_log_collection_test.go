@@ -158,7 +158,7 @@ func TestLogCollectionDisabled(t *testing.T) {
 
 	feature.Assess("Fluent-bit ConfigMap not created", func(ctx context.Context, t *testing.T, c *envconf.Config) context.Context {
 		var cm corev1.ConfigMap
-		if err := c.Client().Resources().Get(ctx, "fluent-bit", logNS, &cm); err == nil {
+		if err := c.Client().Resources().Get(ctx, logGroupName+"-fluent-bit", logNS, &cm); err == nil {
 			t.Fatal("fluent-bit ConfigMap should not exist when LogCollection is disabled")
 		}
 		t.Log("Verified: fluent-bit ConfigMap is NOT created when LogCollection is disabled")
@@ -230,7 +230,7 @@ func TestLogCollectionPartialLogs(t *testing.T) {
 
 	feature.Assess("Fluent-bit ConfigMap has only error logs", func(ctx context.Context, t *testing.T, c *envconf.Config) context.Context {
 		var cm corev1.ConfigMap
-		if err := c.Client().Resources().Get(ctx, "fluent-bit", logNS, &cm); err != nil {
+		if err := c.Client().Resources().Get(ctx, logGroupName+"-fluent-bit", logNS, &cm); err != nil {
 			t.Fatalf("Failed to get fluent-bit ConfigMap: %v", err)
 		}
 		cfg := cm.Data["fluent-bit.yaml"]
@@ -393,7 +393,7 @@ func TestLogCollectionCustomFilters(t *testing.T) {
 
 	feature.Assess("Custom filters are in fluent-bit ConfigMap", func(ctx context.Context, t *testing.T, c *envconf.Config) context.Context {
 		var cm corev1.ConfigMap
-		if err := c.Client().Resources().Get(ctx, "fluent-bit", logNS, &cm); err != nil {
+		if err := c.Client().Resources().Get(ctx, logGroupName+"-fluent-bit", logNS, &cm); err != nil {
 			t.Fatalf("Failed to get fluent-bit ConfigMap: %v", err)
 		}
 		cfg := cm.Data["fluent-bit.yaml"]
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mltest"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/util/homedir"
@@ -126,13 +127,7 @@ func TestMlClusterWithEdnode(t *testing.T) {
 	feature.Assess("MarklogicCluster Pod created", func(ctx context.Context, t *testing.T, c *envconf.Config) context.Context {
 		client := c.Client()
 
-		podName := "dnode-0"
-		err := utils.WaitForPod(ctx, t, client, mlClusterNs, podName, 120*time.Second, true)
-		if err != nil {
-			t.Fatalf("Failed to wait for pod creation: %v", err)
-		}
-		epodName := "enode-0"
-		err = utils.WaitForPod(ctx, t, client, mlClusterNs, epodName, 180*time.Second, true)
+		err := mltest.WaitForClusterPodsReady(ctx, t, client.Resources(mlClusterNs).GetControllerRuntimeClient(), mlClusterNs, mlcluster.Name, 300*time.Second)
 		if err != nil {
 			t.Fatalf("Failed to wait for pod creation: %v", err)
 		}
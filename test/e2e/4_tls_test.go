@@ -11,6 +11,7 @@ import (
 	"time"
 
 	marklogicv1 "github.com/marklogic/marklogic-operator-kubernetes/api/v1"
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mltest"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -272,16 +273,9 @@ func TestTlsWithNamedCert(t *testing.T) {
 		client := c.Client()
 
 		// Wait for both pods to be ready (first pod can take longer to initialize cluster)
-		t.Log("Waiting for marklogic-0 pod...")
-		err := utils.WaitForPod(ctx, t, client, namespace, "marklogic-0", 180*time.Second, true)
+		err := mltest.WaitForClusterPodsReady(ctx, t, client.Resources(namespace).GetControllerRuntimeClient(), namespace, cr.Name, 360*time.Second)
 		if err != nil {
-			t.Fatalf("Failed to wait for marklogic-0 creation: %v", err)
-		}
-
-		t.Log("Waiting for marklogic-1 pod...")
-		err = utils.WaitForPod(ctx, t, client, namespace, "marklogic-1", 180*time.Second, true)
-		if err != nil {
-			t.Fatalf("Failed to wait for marklogic-1 creation: %v", err)
+			t.Fatalf("Failed to wait for the MarklogicCluster pods: %v", err)
 		}
 		return ctx
 	})
@@ -494,16 +488,9 @@ func TestTlsWithMultiNode(t *testing.T) {
 		client := c.Client()
 
 		// Wait for both pods to be ready
-		t.Log("Waiting for dnode-0 pod...")
-		err := utils.WaitForPod(ctx, t, client, namespace, "dnode-0", 180*time.Second, true)
-		if err != nil {
-			t.Fatalf("Failed to wait for dnode-0 creation: %v", err)
-		}
-
-		t.Log("Waiting for enode-0 pod...")
-		err = utils.WaitForPod(ctx, t, client, namespace, "enode-0", 180*time.Second, true)
+		err := mltest.WaitForClusterPodsReady(ctx, t, client.Resources(namespace).GetControllerRuntimeClient(), namespace, cr.Name, 360*time.Second)
 		if err != nil {
-			t.Fatalf("Failed to wait for enode-0 creation: %v", err)
+			t.Fatalf("Failed to wait for the MarklogicCluster pods: %v", err)
 		}
 
 		// Wait additional time for enode to join cluster and configure TLS
@@ -115,7 +115,7 @@ func TestHAPorxyPathBaseEnabled(t *testing.T) {
 
 	feature.Assess("HAProxy with PathBased Route is working", func(ctx context.Context, t *testing.T, c *envconf.Config) context.Context {
 		podName := "ml-0"
-		fqdn := fmt.Sprintf("marklogic-haproxy.%s.svc.cluster.local", namespace)
+		fqdn := fmt.Sprintf("marklogicclusters-haproxy.%s.svc.cluster.local", namespace)
 		url := "http://" + fqdn + ":8080/adminUI"
 		t.Log("URL for testing haproxy service: ", url)
 		// curl command to check if haproxy is working for path based routing
@@ -253,7 +253,7 @@ func TestHAPorxWithNoPathBasedDisabled(t *testing.T) {
 
 	feature.Assess("HAProxy with PathBased disabled is working", func(ctx context.Context, t *testing.T, c *envconf.Config) context.Context {
 		podName := "ml-0"
-		fqdn := fmt.Sprintf("marklogic-haproxy.%s.svc.cluster.local", namespace)
+		fqdn := fmt.Sprintf("marklogicclusters-haproxy.%s.svc.cluster.local", namespace)
 		url := "http://" + fqdn + ":8001"
 		t.Log("URL for haproxy: ", url)
 		command := fmt.Sprintf("curl --anyauth -u %s:%s %s", adminUsername, adminPassword, url)
@@ -144,7 +144,7 @@ func TestLogCollectionDisabled(t *testing.T) {
 		client := c.Client()
 
 		var configMap corev1.ConfigMap
-		err := client.Resources().Get(ctx, "fluent-bit", logCollectionNamespace, &configMap)
+		err := client.Resources().Get(ctx, logGroupName+"-fluent-bit", logCollectionNamespace, &configMap)
 		if err == nil {
 			t.Fatal("Fluent-bit ConfigMap should not exist when LogCollection is disabled")
 		}
@@ -291,7 +291,7 @@ func TestLogCollectionPartialLogs(t *testing.T) {
 		client := c.Client()
 
 		var configMap corev1.ConfigMap
-		if err := client.Resources().Get(ctx, "fluent-bit", logCollectionNamespace, &configMap); err != nil {
+		if err := client.Resources().Get(ctx, logGroupName+"-fluent-bit", logCollectionNamespace, &configMap); err != nil {
 			t.Fatalf("Failed to get fluent-bit ConfigMap: %v", err)
 		}
 
@@ -638,7 +638,7 @@ func TestLogCollectionCustomFilters(t *testing.T) {
 		client := c.Client()
 
 		var configMap corev1.ConfigMap
-		if err := client.Resources().Get(ctx, "fluent-bit", logCollectionNamespace, &configMap); err != nil {
+		if err := client.Resources().Get(ctx, logGroupName+"-fluent-bit", logCollectionNamespace, &configMap); err != nil {
 			t.Fatalf("Failed to get fluent-bit ConfigMap: %v", err)
 		}
 
@@ -28,6 +28,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/marklogic/marklogic-operator-kubernetes/pkg/mltest"
 	. "github.com/onsi/ginkgo/v2" //nolint:golint,revive
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -360,22 +361,13 @@ func WaitForPod(ctx context.Context, t *testing.T, client klient.Client, namespa
 	}
 }
 
-// util function to get secret data
+// GetSecretData returns the username and password stored under the given keys of a Secret.
 func GetSecretData(ctx context.Context, client klient.Client, namespace, secretName, username, password string) (string, string, error) {
-	secret := &corev1.Secret{}
-	err := client.Resources(namespace).Get(ctx, secretName, namespace, secret)
+	usernameSecret, passwordSecret, err := mltest.SecretCredentials(ctx, client.Resources(namespace).GetControllerRuntimeClient(), namespace, secretName, username, password)
 	if err != nil {
 		return "", "", fmt.Errorf("Failed to get secret: %s", err)
 	}
-	usernameSecret, ok := secret.Data[username]
-	if !ok {
-		return "", "", fmt.Errorf("username not found in secret data")
-	}
-	passwordSecret, ok := secret.Data[password]
-	if !ok {
-		return "", "", fmt.Errorf("password not found in secret data")
-	}
-	return string(usernameSecret), string(passwordSecret), nil
+	return usernameSecret, passwordSecret, nil
 }
 
 func ExecCmdInPod(podName, namespace, containerName, command string) (string, error) {